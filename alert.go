@@ -0,0 +1,82 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for alert errors and op logs.
+const (
+	OpCreateAlertAcknowledgement  = "CreateAlertAcknowledgement"
+	OpFindAlertAcknowledgements   = "FindAlertAcknowledgements"
+	OpDeleteAlertAcknowledgements = "DeleteAlertAcknowledgements"
+	OpAcknowledgeAlert            = "AcknowledgeAlert"
+	OpFindAlerts                  = "FindAlerts"
+)
+
+// AlertCriticalLevel is the level of the statuses that raise alerts.
+const AlertCriticalLevel = "crit"
+
+// AlertAcknowledgement records that a user acknowledged the alert of a check.
+// The statuses written by the check up to the time of the acknowledgement are
+// acknowledged.
+type AlertAcknowledgement struct {
+	ID      ID        `json:"id"`
+	OrgID   ID        `json:"orgID"`
+	CheckID ID        `json:"checkID"`
+	UserID  ID        `json:"userID"`
+	Time    time.Time `json:"time"`
+}
+
+// AlertAcknowledgementFilter represents a set of filters that restrict the
+// returned acknowledgements.
+type AlertAcknowledgementFilter struct {
+	OrgID   *ID
+	CheckID *ID
+}
+
+// AlertAcknowledgementService stores the acknowledgements of alerts.
+type AlertAcknowledgementService interface {
+	// CreateAlertAcknowledgement creates an acknowledgement and sets a.ID
+	// with the new identifier.
+	CreateAlertAcknowledgement(ctx context.Context, a *AlertAcknowledgement) error
+
+	// FindAlertAcknowledgements returns the acknowledgements matching the
+	// filter, the latest first.
+	FindAlertAcknowledgements(ctx context.Context, filter AlertAcknowledgementFilter) ([]*AlertAcknowledgement, int, error)
+
+	// DeleteAlertAcknowledgements deletes the acknowledgements matching the
+	// filter that are older than before.
+	DeleteAlertAcknowledgements(ctx context.Context, filter AlertAcknowledgementFilter, before time.Time) error
+}
+
+// Alert is raised by a check when it writes a critical status. Its ID is the
+// ID of the check.
+type Alert struct {
+	CheckID   ID     `json:"checkID"`
+	CheckName string `json:"checkName,omitempty"`
+	// Time and Message are of the last critical status of the check.
+	Time    time.Time `json:"time"`
+	Message string    `json:"message,omitempty"`
+	// Acknowledged is whether the last critical status was acknowledged.
+	Acknowledged bool `json:"acknowledged"`
+	// Acknowledgement is the last acknowledgement of the alert, if any.
+	Acknowledgement *AlertAcknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// AlertSummary is the summary of the alerts of an organization.
+type AlertSummary struct {
+	OrgID                  ID       `json:"orgID"`
+	UnacknowledgedCritical int      `json:"unacknowledgedCritical"`
+	Alerts                 []*Alert `json:"alerts"`
+}
+
+// AlertService acknowledges the alerts of checks.
+type AlertService interface {
+	// AcknowledgeAlert acknowledges the alert of a check on behalf of a user.
+	AcknowledgeAlert(ctx context.Context, checkID, userID ID) (*AlertAcknowledgement, error)
+
+	// FindAlerts returns the alerts of the checks of an organization that
+	// wrote critical statuses within the retention of its history.
+	FindAlerts(ctx context.Context, orgID ID) (*AlertSummary, error)
+}
@@ -0,0 +1,94 @@
+package influxdb
+
+import (
+	"fmt"
+)
+
+// APIRequestClass is the class of an API request, whose requests share a
+// rate limit of an organization.
+type APIRequestClass string
+
+// The classes of API requests. Writes are the writes and deletes of points,
+// reads are the GET requests and queries, and admin requests are all other
+// requests, which change resources.
+const (
+	APIRequestRead  APIRequestClass = "read"
+	APIRequestWrite APIRequestClass = "write"
+	APIRequestAdmin APIRequestClass = "admin"
+)
+
+// APIRateLimit is a token bucket limit of the API requests of an
+// organization. The bucket holds Burst requests and is refilled with
+// RequestsPerSecond requests every second. A zero rate is unlimited.
+type APIRateLimit struct {
+	RequestsPerSecond int `json:"requestsPerSecond"`
+	// Burst defaults to RequestsPerSecond when zero.
+	Burst int `json:"burst,omitempty"`
+}
+
+// Valid returns an error if the rate or the burst is negative.
+func (l APIRateLimit) Valid() error {
+	if l.RequestsPerSecond < 0 || l.Burst < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "api rate limit requests per second and burst must not be negative",
+		}
+	}
+	return nil
+}
+
+// Unlimited reports whether the limit allows any rate of requests.
+func (l APIRateLimit) Unlimited() bool {
+	return l.RequestsPerSecond == 0
+}
+
+// BurstSize returns the number of requests the bucket of the limit holds.
+func (l APIRateLimit) BurstSize() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.RequestsPerSecond
+}
+
+// APIRateLimits are the rate limits of the classes of API requests of an
+// organization. A class without a limit has the limit of the server.
+type APIRateLimits struct {
+	Read  *APIRateLimit `json:"read,omitempty"`
+	Write *APIRateLimit `json:"write,omitempty"`
+	Admin *APIRateLimit `json:"admin,omitempty"`
+}
+
+// Valid returns an error if a limit is invalid.
+func (ls *APIRateLimits) Valid() error {
+	for _, class := range []APIRequestClass{APIRequestRead, APIRequestWrite, APIRequestAdmin} {
+		l := ls.Of(class)
+		if l == nil {
+			continue
+		}
+		if err := l.Valid(); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid %s api rate limit", class),
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+// Of returns the limit of the class, or nil if the class has none.
+func (ls *APIRateLimits) Of(class APIRequestClass) *APIRateLimit {
+	if ls == nil {
+		return nil
+	}
+	switch class {
+	case APIRequestRead:
+		return ls.Read
+	case APIRequestWrite:
+		return ls.Write
+	case APIRequestAdmin:
+		return ls.Admin
+	default:
+		return nil
+	}
+}
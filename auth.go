@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -23,6 +24,15 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// TagConstraints restrict the points written with the authorization to
+	// the points having all of the tags, e.g. so that the token of a device
+	// cannot write the series of another device.
+	TagConstraints []Tag `json:"tagConstraints,omitempty"`
+	// LastUsedAt and LastUsedIP are the time and the client address of the
+	// latest request authenticated with the authorization. They are recorded
+	// asynchronously, so they may lag behind the latest requests.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIP,omitempty"`
 	CRUDLog
 }
 
@@ -43,6 +53,16 @@ func (a *Authorization) Valid() error {
 		}
 	}
 
+	for _, t := range a.TagConstraints {
+		if err := t.Valid(); err != nil {
+			return &Error{
+				Msg:  "invalid tag constraint",
+				Code: EInvalid,
+				Err:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -109,6 +129,21 @@ type AuthorizationService interface {
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationUsage is a use of an authorization by a request.
+type AuthorizationUsage struct {
+	ID   ID
+	Time time.Time
+	IP   string
+}
+
+// AuthorizationUsageService records the use of authorizations.
+type AuthorizationUsageService interface {
+	// RecordAuthorizationUsage sets the last use of the authorizations to
+	// their usage, unless they were used later. Deleted authorizations are
+	// skipped.
+	RecordAuthorizationUsage(ctx context.Context, usage []AuthorizationUsage) error
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
@@ -0,0 +1,48 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService wraps a influxdb.AlertService and authorizes actions
+// against it appropriately.
+type AlertService struct {
+	s      influxdb.AlertService
+	checks influxdb.CheckService
+}
+
+// NewAlertService constructs an instance of an authorizing alert service.
+// The check service is used to look up the organization of the check.
+func NewAlertService(s influxdb.AlertService, cs influxdb.CheckService) *AlertService {
+	return &AlertService{
+		s:      s,
+		checks: cs,
+	}
+}
+
+// AcknowledgeAlert checks to see if the authorizer on context has write access to the organization of the check.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, checkID, userID influxdb.ID) (*influxdb.AlertAcknowledgement, error) {
+	chk, err := s.checks.FindCheckByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteOrg(ctx, chk.GetOrgID()); err != nil {
+		return nil, err
+	}
+
+	return s.s.AcknowledgeAlert(ctx, checkID, userID)
+}
+
+// FindAlerts checks to see if the authorizer on context has read access to the organization.
+func (s *AlertService) FindAlerts(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error) {
+	if err := authorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindAlerts(ctx, orgID)
+}
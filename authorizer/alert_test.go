@@ -0,0 +1,75 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestAlertService(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	cs := mock.NewCheckService()
+	cs.FindCheckByIDFn = func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: orgID}}, nil
+	}
+
+	tests := []struct {
+		name            string
+		permissions     []influxdb.Permission
+		wantFindAllowed bool
+		wantAckAllowed  bool
+	}{
+		{
+			name: "write access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantFindAllowed: true,
+			wantAckAllowed:  true,
+		},
+		{
+			name: "read access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantFindAllowed: true,
+		},
+		{
+			name: "access to another org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewAlertService(mock.NewAlertService(), cs)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindAlerts(ctx, orgID)
+			if got := err == nil; got != tt.wantFindAllowed {
+				t.Errorf("FindAlerts() error = %v, want allowed %v", err, tt.wantFindAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindAlerts() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.AcknowledgeAlert(ctx, influxdb.ID(10), influxdb.ID(100))
+			if got := err == nil; got != tt.wantAckAllowed {
+				t.Errorf("AcknowledgeAlert() error = %v, want allowed %v", err, tt.wantAckAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("AcknowledgeAlert() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
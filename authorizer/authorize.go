@@ -16,7 +16,7 @@ func IsAllowed(ctx context.Context, p influxdb.Permission) error {
 		return err
 	}
 
-	if !a.Allowed(p) {
+	if !Allowed(ctx, a, p) {
 		return &influxdb.Error{
 			Code: influxdb.EUnauthorized,
 			Msg:  fmt.Sprintf("%s is unauthorized", p),
@@ -55,6 +55,19 @@ func authorizeWriteBucket(ctx context.Context, orgID, id influxdb.ID) error {
 	return nil
 }
 
+func authorizeDeleteBucket(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newBucketPermission(influxdb.DeleteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // FindBucketByID checks to see if the authorizer on context has read access to the id provided.
 func (s *BucketService) FindBucketByID(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -160,7 +173,8 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 	return s.s.CreateBucket(ctx, b)
 }
 
-// UpdateBucket checks to see if the authorizer on context has write access to the bucket provided.
+// UpdateBucket checks to see if the authorizer on context has write access to the bucket provided,
+// and delete access if the update reduces the retention of the bucket.
 func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
 	b, err := s.s.FindBucketByID(ctx, id)
 	if err != nil {
@@ -171,6 +185,12 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 		return nil, err
 	}
 
+	if upd.ReducesRetention(b) {
+		if err := authorizeDeleteBucket(ctx, b.OrgID, id); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.s.UpdateBucket(ctx, id, upd)
 }
 
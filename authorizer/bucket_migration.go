@@ -0,0 +1,106 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketMigrationService = (*BucketMigrationService)(nil)
+
+// BucketMigrationService wraps a influxdb.BucketMigrationService and
+// authorizes actions against it appropriately.
+type BucketMigrationService struct {
+	s influxdb.BucketMigrationService
+}
+
+// NewBucketMigrationService constructs an instance of an authorizing bucket migration service.
+func NewBucketMigrationService(s influxdb.BucketMigrationService) *BucketMigrationService {
+	return &BucketMigrationService{
+		s: s,
+	}
+}
+
+// CreateBucketMigration checks to see if the authorizer on context has read access to the
+// source bucket and write access to the destination bucket of the migration.
+func (s *BucketMigrationService) CreateBucketMigration(ctx context.Context, m *influxdb.BucketMigration) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, m.OrgID, m.SourceBucketID); err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.DestinationBucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateBucketMigration(ctx, m)
+}
+
+// FindBucketMigrationByID checks to see if the authorizer on context has read access to the
+// source bucket of the migration.
+func (s *BucketMigrationService) FindBucketMigrationByID(ctx context.Context, id influxdb.ID) (*influxdb.BucketMigration, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindBucketMigrationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrgID, m.SourceBucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindBucketMigrations retrieves all migrations that match the provided filter and then filters
+// the list down to the migrations whose source bucket is readable.
+func (s *BucketMigrationService) FindBucketMigrations(ctx context.Context, filter influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ms, err := s.s.FindBucketMigrations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	migrations := ms[:0]
+	for _, m := range ms {
+		err := authorizeReadBucket(ctx, m.OrgID, m.SourceBucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		migrations = append(migrations, m)
+	}
+
+	return migrations, nil
+}
+
+// CancelBucketMigration checks to see if the authorizer on context has write access to the
+// destination bucket of the migration.
+func (s *BucketMigrationService) CancelBucketMigration(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindBucketMigrationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.DestinationBucketID); err != nil {
+		return err
+	}
+
+	return s.s.CancelBucketMigration(ctx, id)
+}
@@ -0,0 +1,110 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBucketMigrationService_CreateBucketMigration(t *testing.T) {
+	orgID, sourceID, destinationID := influxdb.ID(10), influxdb.ID(1), influxdb.ID(2)
+	readSource := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &sourceID},
+	}
+	writeDestination := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &destinationID},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name:        "authorized to read the source and write the destination",
+			permissions: []influxdb.Permission{readSource, writeDestination},
+		},
+		{
+			name: "authorized to read and write the buckets of the organization",
+			permissions: []influxdb.Permission{
+				{
+					Action:   influxdb.ReadAction,
+					Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+				},
+				{
+					Action:   influxdb.WriteAction,
+					Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+				},
+			},
+		},
+		{
+			name:        "unauthorized to write the destination",
+			permissions: []influxdb.Permission{readSource},
+			wantErr:     true,
+		},
+		{
+			name:        "unauthorized to read the source",
+			permissions: []influxdb.Permission{writeDestination},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketMigrationService(mock.NewBucketMigrationService())
+
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+			now := time.Now()
+			err := s.CreateBucketMigration(ctx, &influxdb.BucketMigration{
+				OrgID:               orgID,
+				SourceBucketID:      sourceID,
+				DestinationBucketID: destinationID,
+				Start:               now.Add(-time.Hour),
+				Stop:                now,
+			})
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+					t.Fatalf("expected unauthorized error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestBucketMigrationService_FindBucketMigrations(t *testing.T) {
+	orgID := influxdb.ID(10)
+	svc := mock.NewBucketMigrationService()
+	svc.FindBucketMigrationsFn = func(context.Context, influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+		return []*influxdb.BucketMigration{
+			{ID: 1, OrgID: orgID, SourceBucketID: 1, DestinationBucketID: 3},
+			{ID: 2, OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 3},
+		}, nil
+	}
+	s := authorizer.NewBucketMigrationService(svc)
+
+	sourceID := influxdb.ID(2)
+	ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: []influxdb.Permission{
+		{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &sourceID},
+		},
+	}})
+	ms, err := s.FindBucketMigrations(ctx, influxdb.BucketMigrationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ID != 2 {
+		t.Fatalf("expected the migration of the readable source bucket, got %+v", ms)
+	}
+}
@@ -0,0 +1,77 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketOptimizationService = (*BucketOptimizationService)(nil)
+
+// BucketOptimizationService wraps a influxdb.BucketOptimizationService and
+// authorizes actions against it appropriately.
+type BucketOptimizationService struct {
+	s          influxdb.BucketOptimizationService
+	orgService OrganizationService
+}
+
+// NewBucketOptimizationService constructs an instance of an authorizing bucket optimization service.
+func NewBucketOptimizationService(orgSvc OrganizationService, s influxdb.BucketOptimizationService) *BucketOptimizationService {
+	return &BucketOptimizationService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// OptimizeBucket checks to see if the authorizer on context has write access to the bucket.
+func (s *BucketOptimizationService) OptimizeBucket(ctx context.Context, bucketID influxdb.ID, windowStart, windowStop time.Time) (*influxdb.BucketOptimization, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.OptimizeBucket(ctx, bucketID, windowStart, windowStop)
+}
+
+// FindBucketOptimization checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketOptimizationService) FindBucketOptimization(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketOptimization, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindBucketOptimization(ctx, bucketID)
+}
+
+// CancelBucketOptimization checks to see if the authorizer on context has write access to the bucket.
+func (s *BucketOptimizationService) CancelBucketOptimization(ctx context.Context, bucketID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, orgID, bucketID); err != nil {
+		return err
+	}
+
+	return s.s.CancelBucketOptimization(ctx, bucketID)
+}
@@ -0,0 +1,64 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBucketOptimizationService_OptimizeBucket(t *testing.T) {
+	orgID, bucketID := influxdb.ID(10), influxdb.ID(1)
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to write the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &bucketID},
+			},
+		},
+		{
+			name: "authorized to write the buckets of the organization",
+			permission: influxdb.Permission{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+			},
+		},
+		{
+			name: "unauthorized to write the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &bucketID},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketOptimizationService(&OrgService{OrgID: orgID}, mock.NewBucketOptimizationService())
+
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: []influxdb.Permission{tt.permission}})
+			now := time.Now()
+			_, err := s.OptimizeBucket(ctx, bucketID, now, now.Add(time.Hour))
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+					t.Fatalf("expected unauthorized error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
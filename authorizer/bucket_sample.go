@@ -0,0 +1,42 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketSampleService = (*BucketSampleService)(nil)
+
+// BucketSampleService wraps a influxdb.BucketSampleService and authorizes actions
+// against it appropriately.
+type BucketSampleService struct {
+	s          influxdb.BucketSampleService
+	orgService OrganizationService
+}
+
+// NewBucketSampleService constructs an instance of an authorizing bucket sample service.
+func NewBucketSampleService(orgSvc OrganizationService, s influxdb.BucketSampleService) *BucketSampleService {
+	return &BucketSampleService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// SampleBucket checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSampleService) SampleBucket(ctx context.Context, bucketID influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.SampleBucket(ctx, bucketID, opts)
+}
@@ -0,0 +1,32 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBucketSampleService(t *testing.T) {
+	orgID, bucketID, otherBucketID := influxdb.ID(10), influxdb.ID(1), influxdb.ID(2)
+
+	svc := mock.NewBucketSampleService()
+	svc.SampleBucketFn = func(ctx context.Context, id influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error) {
+		return []*influxdb.BucketSampleRow{{Measurement: "cpu"}}, nil
+	}
+	s := authorizer.NewBucketSampleService(&OrgService{OrgID: orgID}, svc)
+
+	ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: []influxdb.Permission{{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &bucketID},
+	}}})
+	if rows, err := s.SampleBucket(ctx, bucketID, influxdb.BucketSampleOptions{}); err != nil || len(rows) != 1 {
+		t.Fatalf("expected the sample of the bucket, got %v %v", rows, err)
+	}
+	if _, err := s.SampleBucket(ctx, otherBucketID, influxdb.BucketSampleOptions{}); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService wraps a influxdb.BucketSchemaService and authorizes actions
+// against it appropriately.
+type BucketSchemaService struct {
+	s          influxdb.BucketSchemaService
+	orgService OrganizationService
+}
+
+// NewBucketSchemaService constructs an instance of an authorizing bucket schema service.
+func NewBucketSchemaService(orgSvc OrganizationService, s influxdb.BucketSchemaService) *BucketSchemaService {
+	return &BucketSchemaService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// FindMeasurements checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindMeasurements(ctx context.Context, bucketID influxdb.ID) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeReadBucket(ctx, bucketID); err != nil {
+		return nil, err
+	}
+	return s.s.FindMeasurements(ctx, bucketID)
+}
+
+// FindFields checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindFields(ctx context.Context, bucketID influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeReadBucket(ctx, bucketID); err != nil {
+		return nil, err
+	}
+	return s.s.FindFields(ctx, bucketID, filter)
+}
+
+// FindTagValues checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindTagValues(ctx context.Context, bucketID influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeReadBucket(ctx, bucketID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTagValues(ctx, bucketID, key, filter)
+}
+
+func (s *BucketSchemaService) authorizeReadBucket(ctx context.Context, bucketID influxdb.ID) error {
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return err
+	}
+	return authorizeReadBucket(ctx, orgID, bucketID)
+}
@@ -0,0 +1,76 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBucketSchemaService(t *testing.T) {
+	orgID, bucketID, otherBucketID := influxdb.ID(10), influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to read the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &bucketID},
+			},
+		},
+		{
+			name: "authorized to read the buckets of the organization",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+			},
+		},
+		{
+			name: "unauthorized to read the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &otherBucketID},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewBucketSchemaService()
+			svc.FindMeasurementsFn = func(ctx context.Context, id influxdb.ID) ([]string, error) {
+				return []string{"cpu"}, nil
+			}
+			svc.FindFieldsFn = func(ctx context.Context, id influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error) {
+				return []string{"usage"}, nil
+			}
+			svc.FindTagValuesFn = func(ctx context.Context, id influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error) {
+				return []string{"a"}, nil
+			}
+			s := authorizer.NewBucketSchemaService(&OrgService{OrgID: orgID}, svc)
+
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: []influxdb.Permission{tt.permission}})
+			ms, err := s.FindMeasurements(ctx, bucketID)
+			fs, fieldsErr := s.FindFields(ctx, bucketID, influxdb.BucketSchemaFilter{})
+			vs, tagValuesErr := s.FindTagValues(ctx, bucketID, "host", influxdb.BucketSchemaFilter{})
+			for _, err := range []error{err, fieldsErr, tagValuesErr} {
+				if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+					t.Fatalf("expected unauthorized error, got %v", err)
+				}
+				if !tt.wantErr && err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !tt.wantErr && (len(ms) != 1 || len(fs) != 1 || len(vs) != 1) {
+				t.Errorf("expected the schema of the bucket, got %v %v %v", ms, fs, vs)
+			}
+		})
+	}
+}
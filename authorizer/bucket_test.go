@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
@@ -335,6 +336,7 @@ func TestBucketService_UpdateBucket(t *testing.T) {
 	}
 	type args struct {
 		id          influxdb.ID
+		upd         influxdb.BucketUpdate
 		permissions []influxdb.Permission
 	}
 	type wants struct {
@@ -425,6 +427,133 @@ func TestBucketService_UpdateBucket(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "unauthorized to reduce the retention of bucket",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					FindBucketByIDFn: func(ctc context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:              1,
+							OrgID:           10,
+							RetentionPeriod: 72 * time.Hour,
+							RetentionRules:  []influxdb.BucketRetentionRule{{Measurement: "debug_*", RetentionPeriod: 24 * time.Hour}},
+						}, nil
+					},
+					UpdateBucketFn: func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:    1,
+							OrgID: 10,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				upd: influxdb.BucketUpdate{
+					RetentionRules: &[]influxdb.BucketRetentionRule{{Measurement: "debug_*", RetentionPeriod: time.Hour}},
+				},
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.BucketsResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "delete:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+		{
+			name: "authorized to reduce the retention of bucket",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					FindBucketByIDFn: func(ctc context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:              1,
+							OrgID:           10,
+							RetentionPeriod: 72 * time.Hour,
+						}, nil
+					},
+					UpdateBucketFn: func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:    1,
+							OrgID: 10,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				upd: influxdb.BucketUpdate{
+					RetentionRules: &[]influxdb.BucketRetentionRule{{Measurement: "debug_*", RetentionPeriod: time.Hour}},
+				},
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.BucketsResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+					{
+						Action: "delete",
+						Resource: influxdb.Resource{
+							Type: influxdb.BucketsResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "authorized to extend the retention of bucket",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					FindBucketByIDFn: func(ctc context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:              1,
+							OrgID:           10,
+							RetentionPeriod: 72 * time.Hour,
+							RetentionRules:  []influxdb.BucketRetentionRule{{Measurement: "debug_*", RetentionPeriod: 24 * time.Hour}},
+						}, nil
+					},
+					UpdateBucketFn: func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:    1,
+							OrgID: 10,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				upd: influxdb.BucketUpdate{
+					RetentionPeriod: new(time.Duration),
+					RetentionRules:  &[]influxdb.BucketRetentionRule{{Measurement: "debug_*", RetentionPeriod: 48 * time.Hour}},
+				},
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type: influxdb.BucketsResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -434,7 +563,7 @@ func TestBucketService_UpdateBucket(t *testing.T) {
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
 
-			_, err := s.UpdateBucket(ctx, tt.args.id, influxdb.BucketUpdate{})
+			_, err := s.UpdateBucket(ctx, tt.args.id, tt.args.upd)
 			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
 		})
 	}
@@ -0,0 +1,28 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.CheckStatusStream = (*CheckStatusStream)(nil)
+
+// CheckStatusStream wraps a influxdb.CheckStatusStream and authorizes subscriptions
+// against it appropriately.
+type CheckStatusStream struct {
+	s influxdb.CheckStatusStream
+}
+
+// NewCheckStatusStream constructs an instance of an authorizing check status stream.
+func NewCheckStatusStream(s influxdb.CheckStatusStream) *CheckStatusStream {
+	return &CheckStatusStream{s: s}
+}
+
+// SubscribeCheckStatuses checks to see if the authorizer on context has read access to the organization of the filter.
+func (s *CheckStatusStream) SubscribeCheckStatuses(ctx context.Context, filter influxdb.CheckStatusEventFilter) (<-chan *influxdb.CheckStatusEvent, error) {
+	if err := authorizeReadOrg(ctx, filter.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.SubscribeCheckStatuses(ctx, filter)
+}
@@ -11,13 +11,17 @@ var _ influxdb.DashboardService = (*DashboardService)(nil)
 // DashboardService wraps a influxdb.DashboardService and authorizes actions
 // against it appropriately.
 type DashboardService struct {
-	s influxdb.DashboardService
+	s    influxdb.DashboardService
+	acls influxdb.ResourceACLService
 }
 
 // NewDashboardService constructs an instance of an authorizing dashboard serivce.
-func NewDashboardService(s influxdb.DashboardService) *DashboardService {
+// Dashboards that are restricted with an acl of the acl service are only
+// accessible to the users they are shared with.
+func NewDashboardService(s influxdb.DashboardService, acls influxdb.ResourceACLService) *DashboardService {
 	return &DashboardService{
-		s: s,
+		s:    s,
+		acls: acls,
 	}
 }
 
@@ -25,8 +29,8 @@ func newDashboardPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb
 	return influxdb.NewPermissionAtID(id, a, influxdb.DashboardsResourceType, orgID)
 }
 
-func authorizeReadDashboard(ctx context.Context, orgID, id influxdb.ID) error {
-	p, err := newDashboardPermission(influxdb.ReadAction, orgID, id)
+func (s *DashboardService) authorizeDashboard(ctx context.Context, a influxdb.Action, orgID, id influxdb.ID) error {
+	p, err := newDashboardPermission(a, orgID, id)
 	if err != nil {
 		return err
 	}
@@ -35,20 +39,15 @@ func authorizeReadDashboard(ctx context.Context, orgID, id influxdb.ID) error {
 		return err
 	}
 
-	return nil
+	return authorizeResourceACL(ctx, s.acls, a, influxdb.DashboardsResourceType, orgID, id)
 }
 
-func authorizeWriteDashboard(ctx context.Context, orgID, id influxdb.ID) error {
-	p, err := newDashboardPermission(influxdb.WriteAction, orgID, id)
-	if err != nil {
-		return err
-	}
-
-	if err := IsAllowed(ctx, *p); err != nil {
-		return err
-	}
+func (s *DashboardService) authorizeReadDashboard(ctx context.Context, orgID, id influxdb.ID) error {
+	return s.authorizeDashboard(ctx, influxdb.ReadAction, orgID, id)
+}
 
-	return nil
+func (s *DashboardService) authorizeWriteDashboard(ctx context.Context, orgID, id influxdb.ID) error {
+	return s.authorizeDashboard(ctx, influxdb.WriteAction, orgID, id)
 }
 
 // FindDashboardByID checks to see if the authorizer on context has read access to the id provided.
@@ -58,7 +57,7 @@ func (s *DashboardService) FindDashboardByID(ctx context.Context, id influxdb.ID
 		return nil, err
 	}
 
-	if err := authorizeReadDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeReadDashboard(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -78,7 +77,7 @@ func (s *DashboardService) FindDashboards(ctx context.Context, filter influxdb.D
 	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
 	dashboards := bs[:0]
 	for _, b := range bs {
-		err := authorizeReadDashboard(ctx, b.OrganizationID, b.ID)
+		err := s.authorizeReadDashboard(ctx, b.OrganizationID, b.ID)
 		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
 			return nil, 0, err
 		}
@@ -114,7 +113,7 @@ func (s *DashboardService) UpdateDashboard(ctx context.Context, id influxdb.ID,
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -128,7 +127,7 @@ func (s *DashboardService) DeleteDashboard(ctx context.Context, id influxdb.ID)
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
 		return err
 	}
 
@@ -141,7 +140,7 @@ func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID,
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
 		return err
 	}
 
@@ -154,7 +153,7 @@ func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
 		return err
 	}
 
@@ -167,7 +166,7 @@ func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -180,7 +179,7 @@ func (s *DashboardService) GetDashboardCellView(ctx context.Context, dashboardID
 		return nil, err
 	}
 
-	if err := authorizeReadDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeReadDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -193,7 +192,7 @@ func (s *DashboardService) UpdateDashboardCellView(ctx context.Context, dashboar
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -206,7 +205,7 @@ func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxd
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
 		return err
 	}
 
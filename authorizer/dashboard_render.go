@@ -0,0 +1,36 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DashboardRenderService = (*DashboardRenderService)(nil)
+
+// DashboardRenderService wraps a influxdb.DashboardRenderService and authorizes
+// actions against it appropriately. The queries of the cells run with the
+// authorization of the caller, so they are authorized by the query service.
+type DashboardRenderService struct {
+	s          influxdb.DashboardRenderService
+	dashboards *DashboardService
+}
+
+// NewDashboardRenderService constructs an instance of an authorizing dashboard
+// render service. The dashboard service is used to look up the organization
+// of the dashboard.
+func NewDashboardRenderService(s influxdb.DashboardRenderService, ds influxdb.DashboardService, acls influxdb.ResourceACLService) *DashboardRenderService {
+	return &DashboardRenderService{
+		s:          s,
+		dashboards: NewDashboardService(ds, acls),
+	}
+}
+
+// RenderDashboard checks to see if the authorizer on context has read access to the dashboard.
+func (s *DashboardRenderService) RenderDashboard(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+	if _, err := s.dashboards.FindDashboardByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.RenderDashboard(ctx, id, opts)
+}
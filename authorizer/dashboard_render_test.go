@@ -0,0 +1,61 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestDashboardRenderService(t *testing.T) {
+	orgID, dashboardID, otherDashboardID := influxdb.ID(1), influxdb.ID(10), influxdb.ID(11)
+
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+		return &influxdb.Dashboard{ID: id, OrganizationID: orgID}, nil
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantAllowed bool
+	}{
+		{
+			name: "read access to the dashboard",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID, ID: &dashboardID}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "read access to another dashboard",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID, ID: &otherDashboardID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := false
+			rs := mock.NewDashboardRenderService()
+			rs.RenderDashboardFn = func(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+				rendered = true
+				return nil, nil
+			}
+			s := authorizer.NewDashboardRenderService(rs, ds, mock.NewResourceACLService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.RenderDashboard(ctx, dashboardID, influxdb.DashboardRenderOptions{})
+			if got := err == nil; got != tt.wantAllowed || rendered != tt.wantAllowed {
+				t.Errorf("RenderDashboard() error = %v, rendered %v, want allowed %v", err, rendered, tt.wantAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("RenderDashboard() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
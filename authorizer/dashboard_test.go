@@ -104,7 +104,7 @@ func TestDashboardService_FindDashboardByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
@@ -229,7 +229,7 @@ func TestDashboardService_FindDashboards(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
@@ -344,7 +344,7 @@ func TestDashboardService_UpdateDashboard(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
@@ -449,7 +449,7 @@ func TestDashboardService_DeleteDashboard(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
@@ -531,7 +531,7 @@ func TestDashboardService_CreateDashboard(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
@@ -649,7 +649,7 @@ func TestDashboardService_WriteDashboardCell(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
@@ -755,7 +755,7 @@ func TestDashboardService_FindDashboardCellView(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := authorizer.NewDashboardService(tt.fields.DashboardService)
+			s := authorizer.NewDashboardService(tt.fields.DashboardService, mock.NewResourceACLService())
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
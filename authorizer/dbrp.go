@@ -0,0 +1,122 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.DBRPMappingServiceV2 = (*DBRPMappingService)(nil)
+
+// DBRPMappingService wraps a influxdb.DBRPMappingServiceV2 and authorizes actions
+// against it appropriately. A mapping is authorized by the permissions on its bucket.
+type DBRPMappingService struct {
+	s influxdb.DBRPMappingServiceV2
+}
+
+// NewDBRPMappingService constructs an instance of an authorizing dbrp mapping service.
+func NewDBRPMappingService(s influxdb.DBRPMappingServiceV2) *DBRPMappingService {
+	return &DBRPMappingService{
+		s: s,
+	}
+}
+
+// FindDBRPMappingByID checks to see if the authorizer on context has read access to the bucket of the mapping.
+func (s *DBRPMappingService) FindDBRPMappingByID(ctx context.Context, id influxdb.ID) (*influxdb.DBRPMappingV2, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.s.FindDBRPMappingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindDBRPMappings retrieves all mappings that match the provided filter and then filters the list down to only the mappings of buckets that are authorized.
+func (s *DBRPMappingService) FindDBRPMappings(ctx context.Context, filter influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ms, _, err := s.s.FindDBRPMappings(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	mappings := ms[:0]
+	for _, m := range ms {
+		err := authorizeReadBucket(ctx, m.OrganizationID, m.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		mappings = append(mappings, m)
+	}
+
+	return mappings, len(mappings), nil
+}
+
+// CreateDBRPMapping checks to see if the authorizer on context has write access to the bucket of the mapping.
+func (s *DBRPMappingService) CreateDBRPMapping(ctx context.Context, m *influxdb.DBRPMappingV2) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateDBRPMapping(ctx, m)
+}
+
+// UpdateDBRPMapping checks to see if the authorizer on context has write access to the bucket of the mapping,
+// and to the bucket the mapping is moved to.
+func (s *DBRPMappingService) UpdateDBRPMapping(ctx context.Context, id influxdb.ID, upd influxdb.DBRPMappingUpdate) (*influxdb.DBRPMappingV2, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.FindDBRPMappingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	if upd.BucketID != nil {
+		if err := authorizeWriteBucket(ctx, m.OrganizationID, *upd.BucketID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.UpdateDBRPMapping(ctx, id, upd)
+}
+
+// DeleteDBRPMapping checks to see if the authorizer on context has write access to the bucket of the mapping.
+func (s *DBRPMappingService) DeleteDBRPMapping(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m, err := s.FindDBRPMappingByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteDBRPMapping(ctx, id)
+}
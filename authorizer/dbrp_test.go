@@ -0,0 +1,158 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newDBRPMappingService() *mock.DBRPMappingServiceV2 {
+	s := mock.NewDBRPMappingServiceV2()
+	s.FindDBRPMappingByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.DBRPMappingV2, error) {
+		return &influxdb.DBRPMappingV2{ID: id, OrganizationID: 10, BucketID: 1}, nil
+	}
+	s.FindDBRPMappingsFn = func(ctx context.Context, filter influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+		return []*influxdb.DBRPMappingV2{
+			{ID: 1, OrganizationID: 10, BucketID: 1},
+			{ID: 2, OrganizationID: 10, BucketID: 2},
+			{ID: 3, OrganizationID: 11, BucketID: 3},
+		}, 3, nil
+	}
+	return s
+}
+
+func bucketPermission(a influxdb.Action, id influxdb.ID) influxdb.Permission {
+	return influxdb.Permission{
+		Action: a,
+		Resource: influxdb.Resource{
+			Type: influxdb.BucketsResourceType,
+			ID:   influxdbtesting.IDPtr(id),
+		},
+	}
+}
+
+func TestDBRPMappingService_FindDBRPMappingByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name:       "authorized to read bucket",
+			permission: bucketPermission(influxdb.ReadAction, 1),
+		},
+		{
+			name:       "unauthorized to read bucket",
+			permission: bucketPermission(influxdb.ReadAction, 2),
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(newDBRPMappingService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.FindDBRPMappingByID(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestDBRPMappingService_FindDBRPMappings(t *testing.T) {
+	s := authorizer.NewDBRPMappingService(newDBRPMappingService())
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		bucketPermission(influxdb.ReadAction, 1),
+		bucketPermission(influxdb.ReadAction, 3),
+	}})
+
+	ms, n, err := s.FindDBRPMappings(ctx, influxdb.DBRPMappingFilterV2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*influxdb.DBRPMappingV2{
+		{ID: 1, OrganizationID: 10, BucketID: 1},
+		{ID: 3, OrganizationID: 11, BucketID: 3},
+	}
+	if n != len(want) {
+		t.Errorf("expected %d mappings, got %d", len(want), n)
+	}
+	if diff := cmp.Diff(ms, want); diff != "" {
+		t.Errorf("mappings are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestDBRPMappingService_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		fn          func(ctx context.Context, s *authorizer.DBRPMappingService) error
+		err         error
+	}{
+		{
+			name:        "authorized to create mapping",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.WriteAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.DBRPMappingService) error {
+				return s.CreateDBRPMapping(ctx, &influxdb.DBRPMappingV2{OrganizationID: 10, BucketID: 1})
+			},
+		},
+		{
+			name:        "unauthorized to create mapping",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.ReadAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.DBRPMappingService) error {
+				return s.CreateDBRPMapping(ctx, &influxdb.DBRPMappingV2{OrganizationID: 10, BucketID: 1})
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name: "unauthorized to move mapping to bucket",
+			permissions: []influxdb.Permission{
+				bucketPermission(influxdb.ReadAction, 1),
+				bucketPermission(influxdb.WriteAction, 1),
+			},
+			fn: func(ctx context.Context, s *authorizer.DBRPMappingService) error {
+				_, err := s.UpdateDBRPMapping(ctx, 1, influxdb.DBRPMappingUpdate{BucketID: influxdbtesting.IDPtr(2)})
+				return err
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "unauthorized to delete mapping",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.ReadAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.DBRPMappingService) error {
+				return s.DeleteDBRPMapping(ctx, 1)
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(newDBRPMappingService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			influxdbtesting.ErrorsEqual(t, tt.fn(ctx, s), tt.err)
+		})
+	}
+}
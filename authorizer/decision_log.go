@@ -0,0 +1,75 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/jsonweb"
+	"go.uber.org/zap"
+)
+
+type decisionLogKey struct{}
+
+// WithDecisionLog returns a context in which the authorization decisions
+// made by Allowed are logged with the logger, including the required
+// permission and the permissions of the authorizer that match it and those
+// that do not. It is used to troubleshoot unexpected denials.
+func WithDecisionLog(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, decisionLogKey{}, logger)
+}
+
+func decisionLogger(ctx context.Context) *zap.Logger {
+	logger, _ := ctx.Value(decisionLogKey{}).(*zap.Logger)
+	return logger
+}
+
+// Allowed returns whether the authorizer allows the permission, and logs the
+// decision if the context has a decision log.
+func Allowed(ctx context.Context, a influxdb.Authorizer, p influxdb.Permission) bool {
+	allowed := a.Allowed(p)
+
+	logger := decisionLogger(ctx)
+	if logger == nil {
+		return allowed
+	}
+
+	fields := []zap.Field{
+		zap.String("auth_kind", a.Kind()),
+		zap.String("auth_id", a.Identifier().String()),
+		zap.String("user_id", a.GetUserID().String()),
+		zap.String("required_permission", p.String()),
+		zap.Bool("allowed", allowed),
+	}
+
+	var ps []influxdb.Permission
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		if !a.IsActive() {
+			fields = append(fields, zap.String("status", string(a.Status)))
+		}
+		ps = a.Permissions
+	case *influxdb.Session:
+		if err := a.Expired(); err != nil {
+			fields = append(fields, zap.Time("expired_at", a.ExpiresAt))
+		}
+		ps = a.Permissions
+	case *jsonweb.Token:
+		ps = a.Permissions
+	}
+
+	var matched, unmatched []string
+	for _, perm := range ps {
+		if perm.Matches(p) {
+			matched = append(matched, perm.String())
+		} else {
+			unmatched = append(unmatched, perm.String())
+		}
+	}
+	fields = append(fields,
+		zap.Strings("matched_permissions", matched),
+		zap.Strings("unmatched_permissions", unmatched),
+	)
+
+	logger.Info("Authorization decision", fields...)
+	return allowed
+}
@@ -0,0 +1,129 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAllowed_DecisionLog(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	readBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	writeBuckets := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	otherOrgBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &otherOrgID},
+	}
+
+	tests := []struct {
+		name          string
+		auth          influxdb.Authorizer
+		wantAllowed   bool
+		wantMatched   []interface{}
+		wantUnmatched []interface{}
+		wantField     string
+	}{
+		{
+			name: "allowed",
+			auth: &influxdb.Authorization{
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{writeBuckets, readBuckets},
+			},
+			wantAllowed:   true,
+			wantMatched:   []interface{}{readBuckets.String()},
+			wantUnmatched: []interface{}{writeBuckets.String()},
+		},
+		{
+			name: "unmatched",
+			auth: &influxdb.Authorization{
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{otherOrgBuckets},
+			},
+			wantUnmatched: []interface{}{otherOrgBuckets.String()},
+		},
+		{
+			name: "inactive",
+			auth: &influxdb.Authorization{
+				Status:      influxdb.Inactive,
+				Permissions: []influxdb.Permission{readBuckets},
+			},
+			wantMatched: []interface{}{readBuckets.String()},
+			wantField:   "status",
+		},
+		{
+			name: "expired session",
+			auth: &influxdb.Session{
+				ExpiresAt:   time.Now().Add(-time.Hour),
+				Permissions: []influxdb.Permission{readBuckets},
+			},
+			wantMatched: []interface{}{readBuckets.String()},
+			wantField:   "expired_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			ctx := authorizer.WithDecisionLog(context.Background(), zap.New(core))
+
+			if got := authorizer.Allowed(ctx, tt.auth, readBuckets); got != tt.wantAllowed {
+				t.Fatalf("expected allowed to be %v, got %v", tt.wantAllowed, got)
+			}
+			if logs.Len() != 1 {
+				t.Fatalf("expected one logged decision, got %d", logs.Len())
+			}
+			fields := logs.All()[0].ContextMap()
+			if got := fields["required_permission"]; got != readBuckets.String() {
+				t.Errorf("unexpected required permission %v", got)
+			}
+			if got := fields["allowed"]; got != tt.wantAllowed {
+				t.Errorf("unexpected allowed field %v", got)
+			}
+			if !equalFields(fields["matched_permissions"], tt.wantMatched) {
+				t.Errorf("unexpected matched permissions %v", fields["matched_permissions"])
+			}
+			if !equalFields(fields["unmatched_permissions"], tt.wantUnmatched) {
+				t.Errorf("unexpected unmatched permissions %v", fields["unmatched_permissions"])
+			}
+			if _, ok := fields[tt.wantField]; tt.wantField != "" && !ok {
+				t.Errorf("expected the %s field to be logged", tt.wantField)
+			}
+		})
+	}
+}
+
+func TestAllowed_NoDecisionLog(t *testing.T) {
+	orgID := influxdb.ID(1)
+	p := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	auth := &influxdb.Authorization{Status: influxdb.Active, Permissions: []influxdb.Permission{p}}
+	if !authorizer.Allowed(context.Background(), auth, p) {
+		t.Fatal("expected the permission to be allowed")
+	}
+}
+
+func equalFields(got interface{}, want []interface{}) bool {
+	values, _ := got.([]interface{})
+	if len(values) != len(want) {
+		return false
+	}
+	for i := range values {
+		if values[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.IngestRuleService = (*IngestRuleService)(nil)
+
+// IngestRuleService wraps a influxdb.IngestRuleService and authorizes actions
+// against it appropriately. A rule is authorized by the permissions on its bucket.
+type IngestRuleService struct {
+	s          influxdb.IngestRuleService
+	orgService OrganizationService
+}
+
+// NewIngestRuleService constructs an instance of an authorizing ingest rule service.
+func NewIngestRuleService(orgSvc OrganizationService, s influxdb.IngestRuleService) *IngestRuleService {
+	return &IngestRuleService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// FindIngestRuleByID checks to see if the authorizer on context has read access to the bucket of the rule.
+func (s *IngestRuleService) FindIngestRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindIngestRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, r.OrgID, r.BucketID); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindIngestRules retrieves all rules that match the provided filter and then filters the list down to only the rules of buckets that are authorized.
+func (s *IngestRuleService) FindIngestRules(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rs, _, err := s.s.FindIngestRules(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rules := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadBucket(ctx, r.OrgID, r.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, len(rules), nil
+}
+
+// CreateIngestRule checks to see if the authorizer on context has write access to the bucket of the rule.
+func (s *IngestRuleService) CreateIngestRule(ctx context.Context, r *influxdb.IngestRule) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, r.BucketID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, orgID, r.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateIngestRule(ctx, r)
+}
+
+// UpdateIngestRule checks to see if the authorizer on context has write access to the bucket of the rule.
+func (s *IngestRuleService) UpdateIngestRule(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.FindIngestRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, r.OrgID, r.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateIngestRule(ctx, id, upd)
+}
+
+// DeleteIngestRule checks to see if the authorizer on context has write access to the bucket of the rule.
+func (s *IngestRuleService) DeleteIngestRule(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.FindIngestRuleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, r.OrgID, r.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteIngestRule(ctx, id)
+}
@@ -0,0 +1,112 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newIngestRuleService() *mock.IngestRuleService {
+	s := mock.NewIngestRuleService()
+	s.FindIngestRuleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+		return &influxdb.IngestRule{ID: id, OrgID: 10, BucketID: 1}, nil
+	}
+	s.FindIngestRulesFn = func(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+		return []*influxdb.IngestRule{
+			{ID: 1, OrgID: 10, BucketID: 1},
+			{ID: 2, OrgID: 10, BucketID: 2},
+			{ID: 3, OrgID: 11, BucketID: 3},
+		}, 3, nil
+	}
+	return s
+}
+
+func TestIngestRuleService_FindIngestRules(t *testing.T) {
+	s := authorizer.NewIngestRuleService(&OrgService{OrgID: 10}, newIngestRuleService())
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		bucketPermission(influxdb.ReadAction, 1),
+		bucketPermission(influxdb.ReadAction, 3),
+	}})
+
+	rs, n, err := s.FindIngestRules(ctx, influxdb.IngestRuleFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*influxdb.IngestRule{
+		{ID: 1, OrgID: 10, BucketID: 1},
+		{ID: 3, OrgID: 11, BucketID: 3},
+	}
+	if n != len(want) {
+		t.Errorf("expected %d rules, got %d", len(want), n)
+	}
+	if diff := cmp.Diff(rs, want); diff != "" {
+		t.Errorf("rules are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestIngestRuleService_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		fn          func(ctx context.Context, s *authorizer.IngestRuleService) error
+		err         error
+	}{
+		{
+			name:        "authorized to create rule",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.WriteAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.IngestRuleService) error {
+				return s.CreateIngestRule(ctx, &influxdb.IngestRule{BucketID: 1})
+			},
+		},
+		{
+			name:        "unauthorized to create rule",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.ReadAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.IngestRuleService) error {
+				return s.CreateIngestRule(ctx, &influxdb.IngestRule{BucketID: 1})
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name: "authorized to update rule",
+			permissions: []influxdb.Permission{
+				bucketPermission(influxdb.ReadAction, 1),
+				bucketPermission(influxdb.WriteAction, 1),
+			},
+			fn: func(ctx context.Context, s *authorizer.IngestRuleService) error {
+				_, err := s.UpdateIngestRule(ctx, 1, influxdb.IngestRuleUpdate{})
+				return err
+			},
+		},
+		{
+			name:        "unauthorized to delete rule",
+			permissions: []influxdb.Permission{bucketPermission(influxdb.ReadAction, 1)},
+			fn: func(ctx context.Context, s *authorizer.IngestRuleService) error {
+				return s.DeleteIngestRule(ctx, 1)
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewIngestRuleService(&OrgService{OrgID: 10}, newIngestRuleService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			influxdbtesting.ErrorsEqual(t, tt.fn(ctx, s), tt.err)
+		})
+	}
+}
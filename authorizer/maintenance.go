@@ -0,0 +1,78 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+)
+
+var _ influxdb.MaintenanceService = (*MaintenanceService)(nil)
+
+// MaintenanceService wraps a influxdb.MaintenanceService and authorizes actions
+// against it appropriately. The maintenance of the instance may only be
+// changed by authorizers with access to all organizations, while everyone
+// may see it.
+type MaintenanceService struct {
+	s influxdb.MaintenanceService
+}
+
+// NewMaintenanceService constructs an instance of an authorizing maintenance service.
+func NewMaintenanceService(s influxdb.MaintenanceService) *MaintenanceService {
+	return &MaintenanceService{
+		s: s,
+	}
+}
+
+func authorizeWriteMaintenance(ctx context.Context, orgID *influxdb.ID) error {
+	if orgID == nil {
+		return authorizeRuntimeConfig(ctx, influxdb.WriteAction)
+	}
+	return authorizeWriteOrg(ctx, *orgID)
+}
+
+// FindMaintenances returns the maintenance of the instance and the
+// maintenances of the organizations the authorizer on context can read.
+func (s *MaintenanceService) FindMaintenances(ctx context.Context) ([]*influxdb.Maintenance, error) {
+	if _, err := influxdbcontext.GetAuthorizer(ctx); err != nil {
+		return nil, err
+	}
+
+	ms, err := s.s.FindMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenances := ms[:0]
+	for _, m := range ms {
+		if m.OrganizationID != nil {
+			err := authorizeReadOrg(ctx, *m.OrganizationID)
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				return nil, err
+			}
+			if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+				continue
+			}
+		}
+		maintenances = append(maintenances, m)
+	}
+	return maintenances, nil
+}
+
+// StartMaintenance checks to see if the authorizer on context has write access
+// to the organization of the maintenance, or to all orgs for the instance.
+func (s *MaintenanceService) StartMaintenance(ctx context.Context, m *influxdb.Maintenance) error {
+	if err := authorizeWriteMaintenance(ctx, m.OrganizationID); err != nil {
+		return err
+	}
+	return s.s.StartMaintenance(ctx, m)
+}
+
+// StopMaintenance checks to see if the authorizer on context has write access
+// to the organization, or to all orgs for the instance.
+func (s *MaintenanceService) StopMaintenance(ctx context.Context, orgID *influxdb.ID) error {
+	if err := authorizeWriteMaintenance(ctx, orgID); err != nil {
+		return err
+	}
+	return s.s.StopMaintenance(ctx, orgID)
+}
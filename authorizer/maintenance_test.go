@@ -0,0 +1,90 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestMaintenanceService(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	readOrg := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}}
+	writeOrg := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}}
+	readOrgs := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}}
+	writeOrgs := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}}
+
+	tests := []struct {
+		name         string
+		permissions  []influxdb.Permission
+		wantFound    int
+		wantOrg      bool
+		wantInstance bool
+	}{
+		{
+			name:         "operator can change the maintenance of the instance",
+			permissions:  []influxdb.Permission{readOrgs, writeOrgs},
+			wantFound:    3,
+			wantOrg:      true,
+			wantInstance: true,
+		},
+		{
+			name:        "org owner can change the maintenance of the org",
+			permissions: []influxdb.Permission{readOrg, writeOrg},
+			wantFound:   2,
+			wantOrg:     true,
+		},
+		{
+			name:      "others only see the maintenance of the instance",
+			wantFound: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewMaintenanceService()
+			svc.FindMaintenancesFn = func(context.Context) ([]*influxdb.Maintenance, error) {
+				return []*influxdb.Maintenance{
+					{},
+					{OrganizationID: &orgID},
+					{OrganizationID: &otherOrgID},
+				}, nil
+			}
+			s := authorizer.NewMaintenanceService(svc)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			ms, err := s.FindMaintenances(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ms) != tt.wantFound {
+				t.Errorf("FindMaintenances() found %d maintenances, want %d", len(ms), tt.wantFound)
+			}
+
+			err = s.StartMaintenance(ctx, &influxdb.Maintenance{OrganizationID: &orgID})
+			if got := err == nil; got != tt.wantOrg {
+				t.Errorf("StartMaintenance() of the org error = %v, want allowed %v", err, tt.wantOrg)
+			}
+			err = s.StopMaintenance(ctx, &orgID)
+			if got := err == nil; got != tt.wantOrg {
+				t.Errorf("StopMaintenance() of the org error = %v, want allowed %v", err, tt.wantOrg)
+			}
+			err = s.StartMaintenance(ctx, &influxdb.Maintenance{})
+			if got := err == nil; got != tt.wantInstance {
+				t.Errorf("StartMaintenance() of the instance error = %v, want allowed %v", err, tt.wantInstance)
+			}
+			err = s.StopMaintenance(ctx, nil)
+			if got := err == nil; got != tt.wantInstance {
+				t.Errorf("StopMaintenance() of the instance error = %v, want allowed %v", err, tt.wantInstance)
+			}
+		})
+	}
+
+	if _, err := authorizer.NewMaintenanceService(mock.NewMaintenanceService()).FindMaintenances(context.Background()); err == nil {
+		t.Error("expected FindMaintenances() without an authorizer to fail")
+	}
+}
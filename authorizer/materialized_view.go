@@ -0,0 +1,144 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MaterializedViewService = (*MaterializedViewService)(nil)
+
+// MaterializedViewService wraps a influxdb.MaterializedViewService and authorizes actions
+// against it appropriately.
+type MaterializedViewService struct {
+	s influxdb.MaterializedViewService
+}
+
+// NewMaterializedViewService constructs an instance of an authorizing materialized view service.
+func NewMaterializedViewService(s influxdb.MaterializedViewService) *MaterializedViewService {
+	return &MaterializedViewService{
+		s: s,
+	}
+}
+
+func newMaterializedViewPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.MaterializedViewsResourceType, orgID)
+}
+
+func authorizeReadMaterializedView(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newMaterializedViewPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteMaterializedView(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newMaterializedViewPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindMaterializedViewByID checks to see if the authorizer on context has read access to the id provided.
+func (s *MaterializedViewService) FindMaterializedViewByID(ctx context.Context, id influxdb.ID) (*influxdb.MaterializedView, error) {
+	mv, err := s.s.FindMaterializedViewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadMaterializedView(ctx, mv.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return mv, nil
+}
+
+// FindMaterializedViews retrieves all materialized views that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *MaterializedViewService) FindMaterializedViews(ctx context.Context, filter influxdb.MaterializedViewFilter, opt ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+	ms, _, err := s.s.FindMaterializedViews(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	mvs := ms[:0]
+	for _, mv := range ms {
+		err := authorizeReadMaterializedView(ctx, mv.OrganizationID, mv.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		mvs = append(mvs, mv)
+	}
+
+	return mvs, len(mvs), nil
+}
+
+// CreateMaterializedView checks to see if the authorizer on context has write access to the
+// materialized views of the organization, read access to the source bucket and write access
+// to the destination bucket of the view.
+func (s *MaterializedViewService) CreateMaterializedView(ctx context.Context, mv *influxdb.MaterializedView) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.MaterializedViewsResourceType, mv.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := authorizeReadBucket(ctx, mv.OrganizationID, mv.SourceBucketID); err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, mv.OrganizationID, mv.DestinationBucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateMaterializedView(ctx, mv)
+}
+
+// UpdateMaterializedView checks to see if the authorizer on context has write access to the materialized view provided.
+func (s *MaterializedViewService) UpdateMaterializedView(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+	mv, err := s.FindMaterializedViewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteMaterializedView(ctx, mv.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMaterializedView(ctx, id, upd)
+}
+
+// DeleteMaterializedView checks to see if the authorizer on context has write access to the materialized view provided.
+func (s *MaterializedViewService) DeleteMaterializedView(ctx context.Context, id influxdb.ID) error {
+	mv, err := s.FindMaterializedViewByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteMaterializedView(ctx, mv.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteMaterializedView(ctx, id)
+}
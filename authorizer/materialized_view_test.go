@@ -0,0 +1,113 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestMaterializedViewService(t *testing.T) {
+	orgID, viewID := influxdb.ID(1), influxdb.ID(10)
+	sourceID, destID := influxdb.ID(100), influxdb.ID(200)
+
+	view := &influxdb.MaterializedView{
+		ID:                  viewID,
+		OrganizationID:      orgID,
+		SourceBucketID:      sourceID,
+		DestinationBucketID: destID,
+	}
+	readViews := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.MaterializedViewsResourceType, OrgID: &orgID}}
+	writeViews := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.MaterializedViewsResourceType, OrgID: &orgID}}
+	readSource := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &sourceID}}
+	writeDest := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &destID}}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantCreate  bool
+		wantUpdate  bool
+	}{
+		{
+			name:        "write access to views and buckets",
+			permissions: []influxdb.Permission{readViews, writeViews, readSource, writeDest},
+			wantFind:    true,
+			wantCreate:  true,
+			wantUpdate:  true,
+		},
+		{
+			name:        "write access to views without the buckets",
+			permissions: []influxdb.Permission{readViews, writeViews},
+			wantFind:    true,
+			wantUpdate:  true,
+		},
+		{
+			name:        "write access to views without the destination bucket",
+			permissions: []influxdb.Permission{readViews, writeViews, readSource},
+			wantFind:    true,
+			wantUpdate:  true,
+		},
+		{
+			name:        "read access to views",
+			permissions: []influxdb.Permission{readViews, readSource, writeDest},
+			wantFind:    true,
+		},
+		{
+			name:        "access to the buckets only",
+			permissions: []influxdb.Permission{readSource, writeDest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewMaterializedViewService()
+			m.FindMaterializedViewByIDFn = func(context.Context, influxdb.ID) (*influxdb.MaterializedView, error) {
+				return view, nil
+			}
+			m.FindMaterializedViewsFn = func(context.Context, influxdb.MaterializedViewFilter, ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+				return []*influxdb.MaterializedView{view}, 1, nil
+			}
+			s := authorizer.NewMaterializedViewService(m)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindMaterializedViewByID(ctx, viewID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindMaterializedViewByID() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			mvs, _, err := s.FindMaterializedViews(ctx, influxdb.MaterializedViewFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(mvs) == 1; got != tt.wantFind {
+				t.Errorf("FindMaterializedViews() returned %d views, want allowed %v", len(mvs), tt.wantFind)
+			}
+
+			err = s.CreateMaterializedView(ctx, &influxdb.MaterializedView{
+				OrganizationID:      orgID,
+				SourceBucketID:      sourceID,
+				DestinationBucketID: destID,
+			})
+			if got := err == nil; got != tt.wantCreate {
+				t.Errorf("CreateMaterializedView() error = %v, want allowed %v", err, tt.wantCreate)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("CreateMaterializedView() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.UpdateMaterializedView(ctx, viewID, influxdb.MaterializedViewUpdate{})
+			if got := err == nil; got != tt.wantUpdate {
+				t.Errorf("UpdateMaterializedView() error = %v, want allowed %v", err, tt.wantUpdate)
+			}
+
+			err = s.DeleteMaterializedView(ctx, viewID)
+			if got := err == nil; got != tt.wantUpdate {
+				t.Errorf("DeleteMaterializedView() error = %v, want allowed %v", err, tt.wantUpdate)
+			}
+		})
+	}
+}
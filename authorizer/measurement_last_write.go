@@ -0,0 +1,58 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.MeasurementLastWriteService = (*MeasurementLastWriteService)(nil)
+
+// MeasurementLastWriteService wraps a influxdb.MeasurementLastWriteService and
+// authorizes actions against it appropriately.
+type MeasurementLastWriteService struct {
+	s          influxdb.MeasurementLastWriteService
+	orgService OrganizationService
+}
+
+// NewMeasurementLastWriteService constructs an instance of an authorizing
+// measurement last write service.
+func NewMeasurementLastWriteService(orgSvc OrganizationService, s influxdb.MeasurementLastWriteService) *MeasurementLastWriteService {
+	return &MeasurementLastWriteService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// FindMeasurementLastWrites checks to see if the authorizer on context has read access to the bucket.
+func (s *MeasurementLastWriteService) FindMeasurementLastWrites(ctx context.Context, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+	return s.s.FindMeasurementLastWrites(ctx, bucketID)
+}
+
+// RecordMeasurementLastWrites checks to see if the authorizer on context has write access to the buckets.
+func (s *MeasurementLastWriteService) RecordMeasurementLastWrites(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	for bucketID := range writes {
+		orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+		if err != nil {
+			return err
+		}
+		if err := authorizeWriteBucket(ctx, orgID, bucketID); err != nil {
+			return err
+		}
+	}
+	return s.s.RecordMeasurementLastWrites(ctx, writes)
+}
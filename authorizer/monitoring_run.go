@@ -0,0 +1,56 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MonitoringRunService = (*MonitoringRunService)(nil)
+
+// MonitoringRunService wraps a influxdb.MonitoringRunService and authorizes actions
+// against it appropriately.
+type MonitoringRunService struct {
+	s      influxdb.MonitoringRunService
+	checks influxdb.CheckService
+	rules  influxdb.NotificationRuleStore
+}
+
+// NewMonitoringRunService constructs an instance of an authorizing monitoring run service.
+// The check service and notification rule store are used to look up the organization
+// of the check or notification rule.
+func NewMonitoringRunService(s influxdb.MonitoringRunService, cs influxdb.CheckService, rs influxdb.NotificationRuleStore) *MonitoringRunService {
+	return &MonitoringRunService{
+		s:      s,
+		checks: cs,
+		rules:  rs,
+	}
+}
+
+// FindCheckRuns checks to see if the authorizer on context has read access to the organization of the check.
+func (s *MonitoringRunService) FindCheckRuns(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	chk, err := s.checks.FindCheckByID(ctx, checkID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := authorizeReadOrg(ctx, chk.GetOrgID()); err != nil {
+		return nil, 0, err
+	}
+
+	return s.s.FindCheckRuns(ctx, checkID, filter)
+}
+
+// FindNotificationRuleRuns checks to see if the authorizer on context has read access to the organization of the notification rule.
+func (s *MonitoringRunService) FindNotificationRuleRuns(ctx context.Context, ruleID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	nr, err := s.rules.FindNotificationRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := authorizeReadOrg(ctx, nr.GetOrgID()); err != nil {
+		return nil, 0, err
+	}
+
+	return s.s.FindNotificationRuleRuns(ctx, ruleID, filter)
+}
@@ -0,0 +1,70 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestMonitoringRunService(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	cs := mock.NewCheckService()
+	cs.FindCheckByIDFn = func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: orgID}}, nil
+	}
+	rs := &mock.NotificationRuleStore{
+		FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+			return &rule.Slack{Base: rule.Base{ID: id, OrgID: orgID}}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantAllowed bool
+	}{
+		{
+			name: "read access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "read access to another org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewMonitoringRunService(mock.NewMonitoringRunService(), cs, rs)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, _, err := s.FindCheckRuns(ctx, influxdb.ID(10), influxdb.RunFilter{})
+			if got := err == nil; got != tt.wantAllowed {
+				t.Errorf("FindCheckRuns() error = %v, want allowed %v", err, tt.wantAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindCheckRuns() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, _, err = s.FindNotificationRuleRuns(ctx, influxdb.ID(20), influxdb.RunFilter{})
+			if got := err == nil; got != tt.wantAllowed {
+				t.Errorf("FindNotificationRuleRuns() error = %v, want allowed %v", err, tt.wantAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindNotificationRuleRuns() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
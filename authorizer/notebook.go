@@ -0,0 +1,151 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotebookService = (*NotebookService)(nil)
+
+// NotebookService wraps a influxdb.NotebookService and authorizes actions
+// against it appropriately.
+type NotebookService struct {
+	s    influxdb.NotebookService
+	acls influxdb.ResourceACLService
+}
+
+// NewNotebookService constructs an instance of an authorizing notebook service.
+// Notebooks that are restricted with an acl of the acl service are only
+// accessible to the users they are shared with.
+func NewNotebookService(s influxdb.NotebookService, acls influxdb.ResourceACLService) *NotebookService {
+	return &NotebookService{
+		s:    s,
+		acls: acls,
+	}
+}
+
+func newNotebookPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.NotebooksResourceType, orgID)
+}
+
+func (s *NotebookService) authorizeNotebook(ctx context.Context, a influxdb.Action, orgID, id influxdb.ID) error {
+	p, err := newNotebookPermission(a, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return authorizeResourceACL(ctx, s.acls, a, influxdb.NotebooksResourceType, orgID, id)
+}
+
+func (s *NotebookService) authorizeReadNotebook(ctx context.Context, id influxdb.ID) error {
+	n, err := s.s.FindNotebookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.authorizeNotebook(ctx, influxdb.ReadAction, n.OrganizationID, id)
+}
+
+func (s *NotebookService) authorizeWriteNotebook(ctx context.Context, id influxdb.ID) error {
+	n, err := s.s.FindNotebookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.authorizeNotebook(ctx, influxdb.WriteAction, n.OrganizationID, id)
+}
+
+// FindNotebookByID checks to see if the authorizer on context has read access to the id provided.
+func (s *NotebookService) FindNotebookByID(ctx context.Context, id influxdb.ID) (*influxdb.Notebook, error) {
+	n, err := s.s.FindNotebookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeNotebook(ctx, influxdb.ReadAction, n.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// FindNotebooks retrieves all notebooks that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *NotebookService) FindNotebooks(ctx context.Context, filter influxdb.NotebookFilter, opt ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error) {
+	ns, _, err := s.s.FindNotebooks(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	notebooks := ns[:0]
+	for _, n := range ns {
+		err := s.authorizeNotebook(ctx, influxdb.ReadAction, n.OrganizationID, n.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		notebooks = append(notebooks, n)
+	}
+
+	return notebooks, len(notebooks), nil
+}
+
+// CreateNotebook checks to see if the authorizer on context has write access to the global notebooks resource.
+func (s *NotebookService) CreateNotebook(ctx context.Context, n *influxdb.Notebook) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.NotebooksResourceType, n.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateNotebook(ctx, n)
+}
+
+// UpdateNotebook checks to see if the authorizer on context has write access to the notebook provided.
+func (s *NotebookService) UpdateNotebook(ctx context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error) {
+	if err := s.authorizeWriteNotebook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateNotebook(ctx, id, upd)
+}
+
+// DeleteNotebook checks to see if the authorizer on context has write access to the notebook provided.
+func (s *NotebookService) DeleteNotebook(ctx context.Context, id influxdb.ID) error {
+	if err := s.authorizeWriteNotebook(ctx, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteNotebook(ctx, id)
+}
+
+// FindNotebookVersions checks to see if the authorizer on context has read access to the notebook provided.
+func (s *NotebookService) FindNotebookVersions(ctx context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error) {
+	if err := s.authorizeReadNotebook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindNotebookVersions(ctx, id)
+}
+
+// FindNotebookVersion checks to see if the authorizer on context has read access to the notebook provided.
+func (s *NotebookService) FindNotebookVersion(ctx context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error) {
+	if err := s.authorizeReadNotebook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindNotebookVersion(ctx, id, version)
+}
@@ -0,0 +1,116 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestNotebookService(t *testing.T) {
+	orgID, notebookID, otherUserID := influxdb.ID(1), influxdb.ID(10), influxdb.ID(3)
+
+	n := &influxdb.Notebook{
+		ID:             notebookID,
+		OrganizationID: orgID,
+	}
+	readNotebooks := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.NotebooksResourceType, OrgID: &orgID}}
+	writeNotebooks := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.NotebooksResourceType, OrgID: &orgID}}
+
+	name := "cpu"
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		grants      []influxdb.ResourceGrant
+		wantRead    bool
+		wantCreate  bool
+		wantWrite   bool
+	}{
+		{
+			name:        "write access to notebooks",
+			permissions: []influxdb.Permission{readNotebooks, writeNotebooks},
+			wantRead:    true,
+			wantCreate:  true,
+			wantWrite:   true,
+		},
+		{
+			name:        "read access to notebooks",
+			permissions: []influxdb.Permission{readNotebooks},
+			wantRead:    true,
+		},
+		{
+			name:        "notebook shared with another user",
+			permissions: []influxdb.Permission{readNotebooks, writeNotebooks},
+			grants:      []influxdb.ResourceGrant{{UserID: &otherUserID, Access: influxdb.ResourceAccessEdit}},
+			wantCreate:  true,
+		},
+		{
+			name:        "notebook shared with the user to view",
+			permissions: []influxdb.Permission{readNotebooks, writeNotebooks},
+			grants:      []influxdb.ResourceGrant{{UserID: &otherUserID, Access: influxdb.ResourceAccessEdit}, {Access: influxdb.ResourceAccessView}},
+			wantRead:    true,
+			wantCreate:  true,
+		},
+		{
+			name: "no access to notebooks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewNotebookService()
+			m.FindNotebookByIDFn = func(context.Context, influxdb.ID) (*influxdb.Notebook, error) {
+				return n, nil
+			}
+			m.FindNotebooksFn = func(context.Context, influxdb.NotebookFilter, ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error) {
+				return []*influxdb.Notebook{n}, 1, nil
+			}
+			acls := mock.NewResourceACLService()
+			acls.FindResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+				return &influxdb.ResourceACL{ResourceType: rt, ResourceID: id, Grants: tt.grants}, nil
+			}
+			s := authorizer.NewNotebookService(m, acls)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindNotebookByID(ctx, notebookID)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindNotebookByID() error = %v, want allowed %v", err, tt.wantRead)
+			}
+
+			ns, _, err := s.FindNotebooks(ctx, influxdb.NotebookFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(ns) == 1; got != tt.wantRead {
+				t.Errorf("FindNotebooks() returned %d notebooks, want allowed %v", len(ns), tt.wantRead)
+			}
+
+			_, err = s.FindNotebookVersions(ctx, notebookID)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindNotebookVersions() error = %v, want allowed %v", err, tt.wantRead)
+			}
+
+			err = s.CreateNotebook(ctx, &influxdb.Notebook{OrganizationID: orgID})
+			if got := err == nil; got != tt.wantCreate {
+				t.Errorf("CreateNotebook() error = %v, want allowed %v", err, tt.wantCreate)
+			}
+
+			_, err = s.UpdateNotebook(ctx, notebookID, influxdb.NotebookUpdate{Name: &name})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("UpdateNotebook() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("UpdateNotebook() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			err = s.DeleteNotebook(ctx, notebookID)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("DeleteNotebook() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.OrganizationSettingsService = (*OrgSettingsService)(nil)
+
+// OrgSettingsService wraps a influxdb.OrganizationSettingsService and authorizes actions
+// against it appropriately.
+type OrgSettingsService struct {
+	s influxdb.OrganizationSettingsService
+}
+
+// NewOrgSettingsService constructs an instance of an authorizing org settings service.
+func NewOrgSettingsService(s influxdb.OrganizationSettingsService) *OrgSettingsService {
+	return &OrgSettingsService{
+		s: s,
+	}
+}
+
+// FindOrganizationSettings checks to see if the authorizer on context has read access to the organization.
+func (s *OrgSettingsService) FindOrganizationSettings(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+	if err := authorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindOrganizationSettings(ctx, orgID)
+}
+
+// UpdateOrganizationSettings checks to see if the authorizer on context has write access to the organization.
+func (s *OrgSettingsService) UpdateOrganizationSettings(ctx context.Context, orgID influxdb.ID, upd influxdb.OrganizationSettingsUpdate) (*influxdb.OrganizationSettings, error) {
+	if err := authorizeWriteOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateOrganizationSettings(ctx, orgID, upd)
+}
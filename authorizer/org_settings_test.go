@@ -0,0 +1,69 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestOrgSettingsService(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantUpdate  bool
+	}{
+		{
+			name: "write access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantFind:   true,
+			wantUpdate: true,
+		},
+		{
+			name: "read access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantFind: true,
+		},
+		{
+			name: "write access to another org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewOrgSettingsService(mock.NewOrganizationSettingsService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindOrganizationSettings(ctx, orgID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindOrganizationSettings() error = %v, want allowed %v", err, tt.wantFind)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindOrganizationSettings() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.UpdateOrganizationSettings(ctx, orgID, influxdb.OrganizationSettingsUpdate{})
+			if got := err == nil; got != tt.wantUpdate {
+				t.Errorf("UpdateOrganizationSettings() error = %v, want allowed %v", err, tt.wantUpdate)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("UpdateOrganizationSettings() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.QueryTemplateService = (*QueryTemplateService)(nil)
+
+// QueryTemplateService wraps a influxdb.QueryTemplateService and authorizes actions
+// against it appropriately.
+type QueryTemplateService struct {
+	s influxdb.QueryTemplateService
+}
+
+// NewQueryTemplateService constructs an instance of an authorizing query template service.
+func NewQueryTemplateService(s influxdb.QueryTemplateService) *QueryTemplateService {
+	return &QueryTemplateService{
+		s: s,
+	}
+}
+
+func newQueryTemplatePermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.QueryTemplatesResourceType, orgID)
+}
+
+func authorizeReadQueryTemplate(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newQueryTemplatePermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteQueryTemplate(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newQueryTemplatePermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindQueryTemplateByID checks to see if the authorizer on context has read access to the id provided.
+func (s *QueryTemplateService) FindQueryTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryTemplate, error) {
+	t, err := s.s.FindQueryTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadQueryTemplate(ctx, t.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// FindQueryTemplates retrieves all query templates that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *QueryTemplateService) FindQueryTemplates(ctx context.Context, filter influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error) {
+	ts, err := s.s.FindQueryTemplates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	qts := ts[:0]
+	for _, t := range ts {
+		err := authorizeReadQueryTemplate(ctx, t.OrganizationID, t.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		qts = append(qts, t)
+	}
+
+	return qts, nil
+}
+
+// CreateQueryTemplate checks to see if the authorizer on context has write access to the query templates of the organization.
+func (s *QueryTemplateService) CreateQueryTemplate(ctx context.Context, t *influxdb.QueryTemplate) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.QueryTemplatesResourceType, t.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateQueryTemplate(ctx, t)
+}
+
+// UpdateQueryTemplate checks to see if the authorizer on context has write access to the query template provided.
+func (s *QueryTemplateService) UpdateQueryTemplate(ctx context.Context, id influxdb.ID, upd influxdb.QueryTemplateUpdate) (*influxdb.QueryTemplate, error) {
+	t, err := s.FindQueryTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteQueryTemplate(ctx, t.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateQueryTemplate(ctx, id, upd)
+}
+
+// DeleteQueryTemplate checks to see if the authorizer on context has write access to the query template provided.
+func (s *QueryTemplateService) DeleteQueryTemplate(ctx context.Context, id influxdb.ID) error {
+	t, err := s.FindQueryTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteQueryTemplate(ctx, t.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteQueryTemplate(ctx, id)
+}
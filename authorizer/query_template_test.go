@@ -0,0 +1,91 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestQueryTemplateService(t *testing.T) {
+	orgID, otherOrgID, qtID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(10)
+
+	qts := []*influxdb.QueryTemplate{
+		{ID: qtID, OrganizationID: orgID, Name: "error-rate"},
+		{ID: qtID + 1, OrganizationID: otherOrgID, Name: "error-rate"},
+	}
+	readTemplates := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.QueryTemplatesResourceType, OrgID: &orgID}}
+	writeTemplates := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.QueryTemplatesResourceType, OrgID: &orgID}}
+
+	name := "errors"
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantWrite   bool
+	}{
+		{
+			name:        "write access to query templates",
+			permissions: []influxdb.Permission{readTemplates, writeTemplates},
+			wantFind:    true,
+			wantWrite:   true,
+		},
+		{
+			name:        "read access to query templates",
+			permissions: []influxdb.Permission{readTemplates},
+			wantFind:    true,
+		},
+		{
+			name: "no access to query templates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewQueryTemplateService()
+			m.FindQueryTemplateByIDFn = func(context.Context, influxdb.ID) (*influxdb.QueryTemplate, error) {
+				return qts[0], nil
+			}
+			m.FindQueryTemplatesFn = func(context.Context, influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error) {
+				return append([]*influxdb.QueryTemplate(nil), qts...), nil
+			}
+			s := authorizer.NewQueryTemplateService(m)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindQueryTemplateByID(ctx, qtID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindQueryTemplateByID() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			ts, err := s.FindQueryTemplates(ctx, influxdb.QueryTemplateFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(ts) == 1 && ts[0].ID == qtID; got != tt.wantFind {
+				t.Errorf("FindQueryTemplates() returned %d templates, want allowed %v", len(ts), tt.wantFind)
+			}
+
+			err = s.CreateQueryTemplate(ctx, &influxdb.QueryTemplate{OrganizationID: orgID})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("CreateQueryTemplate() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("CreateQueryTemplate() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.UpdateQueryTemplate(ctx, qtID, influxdb.QueryTemplateUpdate{Name: &name})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("UpdateQueryTemplate() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+
+			err = s.DeleteQueryTemplate(ctx, qtID)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("DeleteQueryTemplate() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
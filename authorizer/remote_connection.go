@@ -0,0 +1,148 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RemoteConnectionService = (*RemoteConnectionService)(nil)
+
+// RemoteConnectionService wraps a influxdb.RemoteConnectionService and authorizes actions
+// against it appropriately.
+type RemoteConnectionService struct {
+	s influxdb.RemoteConnectionService
+}
+
+// NewRemoteConnectionService constructs an instance of an authorizing remote connection service.
+func NewRemoteConnectionService(s influxdb.RemoteConnectionService) *RemoteConnectionService {
+	return &RemoteConnectionService{
+		s: s,
+	}
+}
+
+func newRemoteConnectionPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.RemoteConnectionsResourceType, orgID)
+}
+
+func authorizeReadRemoteConnection(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRemoteConnectionPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteRemoteConnection(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRemoteConnectionPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRemoteConnectionByID checks to see if the authorizer on context has read access to the id provided.
+func (s *RemoteConnectionService) FindRemoteConnectionByID(ctx context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+	rc, err := s.s.FindRemoteConnectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadRemoteConnection(ctx, rc.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+// FindRemoteConnections retrieves all remote connections that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *RemoteConnectionService) FindRemoteConnections(ctx context.Context, filter influxdb.RemoteConnectionFilter, opt ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+	rs, _, err := s.s.FindRemoteConnections(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rcs := rs[:0]
+	for _, rc := range rs {
+		err := authorizeReadRemoteConnection(ctx, rc.OrganizationID, rc.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		rcs = append(rcs, rc)
+	}
+
+	return rcs, len(rcs), nil
+}
+
+// CreateRemoteConnection checks to see if the authorizer on context has write access to the
+// remote connections of the organization. Since the data of the connection is sent with the
+// token of one of the secrets of the organization, it also requires read access to the secrets.
+func (s *RemoteConnectionService) CreateRemoteConnection(ctx context.Context, rc *influxdb.RemoteConnection) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.RemoteConnectionsResourceType, rc.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := authorizeReadSecret(ctx, rc.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateRemoteConnection(ctx, rc)
+}
+
+// UpdateRemoteConnection checks to see if the authorizer on context has write access to the
+// remote connection provided. Updates changing where or with which token the data is sent
+// also require read access to the secrets of the organization.
+func (s *RemoteConnectionService) UpdateRemoteConnection(ctx context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error) {
+	rc, err := s.FindRemoteConnectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteRemoteConnection(ctx, rc.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	if upd.Redirects() {
+		if err := authorizeReadSecret(ctx, rc.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.UpdateRemoteConnection(ctx, id, upd)
+}
+
+// DeleteRemoteConnection checks to see if the authorizer on context has write access to the remote connection provided.
+func (s *RemoteConnectionService) DeleteRemoteConnection(ctx context.Context, id influxdb.ID) error {
+	rc, err := s.FindRemoteConnectionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRemoteConnection(ctx, rc.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRemoteConnection(ctx, id)
+}
@@ -0,0 +1,109 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestRemoteConnectionService(t *testing.T) {
+	orgID, rcID := influxdb.ID(1), influxdb.ID(10)
+
+	rc := &influxdb.RemoteConnection{
+		ID:             rcID,
+		OrganizationID: orgID,
+	}
+	readRemotes := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.RemoteConnectionsResourceType, OrgID: &orgID}}
+	writeRemotes := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.RemoteConnectionsResourceType, OrgID: &orgID}}
+	readSecrets := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.SecretsResourceType, OrgID: &orgID}}
+
+	url := "https://edge-2:9999"
+	name := "edge-2"
+
+	tests := []struct {
+		name         string
+		permissions  []influxdb.Permission
+		wantFind     bool
+		wantCreate   bool
+		wantRename   bool
+		wantRedirect bool
+	}{
+		{
+			name:         "write access to remotes and secrets",
+			permissions:  []influxdb.Permission{readRemotes, writeRemotes, readSecrets},
+			wantFind:     true,
+			wantCreate:   true,
+			wantRename:   true,
+			wantRedirect: true,
+		},
+		{
+			name:        "write access to remotes without the secrets",
+			permissions: []influxdb.Permission{readRemotes, writeRemotes},
+			wantFind:    true,
+			wantRename:  true,
+		},
+		{
+			name:        "read access to remotes",
+			permissions: []influxdb.Permission{readRemotes, readSecrets},
+			wantFind:    true,
+		},
+		{
+			name:        "access to the secrets only",
+			permissions: []influxdb.Permission{readSecrets},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRemoteConnectionService()
+			m.FindRemoteConnectionByIDFn = func(context.Context, influxdb.ID) (*influxdb.RemoteConnection, error) {
+				return rc, nil
+			}
+			m.FindRemoteConnectionsFn = func(context.Context, influxdb.RemoteConnectionFilter, ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+				return []*influxdb.RemoteConnection{rc}, 1, nil
+			}
+			s := authorizer.NewRemoteConnectionService(m)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindRemoteConnectionByID(ctx, rcID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindRemoteConnectionByID() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			rcs, _, err := s.FindRemoteConnections(ctx, influxdb.RemoteConnectionFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(rcs) == 1; got != tt.wantFind {
+				t.Errorf("FindRemoteConnections() returned %d connections, want allowed %v", len(rcs), tt.wantFind)
+			}
+
+			err = s.CreateRemoteConnection(ctx, &influxdb.RemoteConnection{OrganizationID: orgID})
+			if got := err == nil; got != tt.wantCreate {
+				t.Errorf("CreateRemoteConnection() error = %v, want allowed %v", err, tt.wantCreate)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("CreateRemoteConnection() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.UpdateRemoteConnection(ctx, rcID, influxdb.RemoteConnectionUpdate{Name: &name})
+			if got := err == nil; got != tt.wantRename {
+				t.Errorf("UpdateRemoteConnection() of the name error = %v, want allowed %v", err, tt.wantRename)
+			}
+
+			_, err = s.UpdateRemoteConnection(ctx, rcID, influxdb.RemoteConnectionUpdate{URL: &url})
+			if got := err == nil; got != tt.wantRedirect {
+				t.Errorf("UpdateRemoteConnection() of the url error = %v, want allowed %v", err, tt.wantRedirect)
+			}
+
+			err = s.DeleteRemoteConnection(ctx, rcID)
+			if got := err == nil; got != tt.wantRename {
+				t.Errorf("DeleteRemoteConnection() error = %v, want allowed %v", err, tt.wantRename)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ReportService = (*ReportService)(nil)
+
+// ReportService wraps a influxdb.ReportService and authorizes actions
+// against it appropriately.
+//
+// Reports are rendered with read access to all the buckets of their
+// organization, so creating or updating a report also requires read access
+// to those buckets, to its dashboard and to its notification endpoint.
+type ReportService struct {
+	s          influxdb.ReportService
+	dashboards *DashboardService
+}
+
+// NewReportService constructs an instance of an authorizing report service.
+// The dashboard service is used to check the access to the dashboards of
+// reports.
+func NewReportService(s influxdb.ReportService, ds influxdb.DashboardService, acls influxdb.ResourceACLService) *ReportService {
+	return &ReportService{
+		s:          s,
+		dashboards: NewDashboardService(ds, acls),
+	}
+}
+
+func newReportPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.ReportsResourceType, orgID)
+}
+
+func authorizeReadReport(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newReportPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteReport(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newReportPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// authorizeReportTargets checks to see if the authorizer on context has read
+// access to the buckets of the organization and to the dashboard and the
+// notification endpoint of the report.
+func (s *ReportService) authorizeReportTargets(ctx context.Context, r *influxdb.Report) error {
+	p, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.BucketsResourceType, r.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if _, err := s.dashboards.FindDashboardByID(ctx, r.DashboardID); err != nil {
+		return err
+	}
+
+	if !r.EndpointID.Valid() {
+		return nil
+	}
+	p, err = influxdb.NewPermissionAtID(r.EndpointID, influxdb.ReadAction, influxdb.NotificationEndpointResourceType, r.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// FindReportByID checks to see if the authorizer on context has read access to the id provided.
+func (s *ReportService) FindReportByID(ctx context.Context, id influxdb.ID) (*influxdb.Report, error) {
+	r, err := s.s.FindReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadReport(ctx, r.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindReports retrieves all reports that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *ReportService) FindReports(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
+	// will likely be expensive.
+	rs, _, err := s.s.FindReports(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	reports := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadReport(ctx, r.OrganizationID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		reports = append(reports, r)
+	}
+
+	return reports, len(reports), nil
+}
+
+// CreateReport checks to see if the authorizer on context has write access to the reports of the organization.
+func (s *ReportService) CreateReport(ctx context.Context, r *influxdb.Report) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.ReportsResourceType, r.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := s.authorizeReportTargets(ctx, r); err != nil {
+		return err
+	}
+
+	return s.s.CreateReport(ctx, r)
+}
+
+// UpdateReport checks to see if the authorizer on context has write access to the report provided.
+func (s *ReportService) UpdateReport(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error) {
+	r, err := s.FindReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteReport(ctx, r.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	upd.Apply(r)
+	if err := s.authorizeReportTargets(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateReport(ctx, id, upd)
+}
+
+// DeleteReport checks to see if the authorizer on context has write access to the report provided.
+func (s *ReportService) DeleteReport(ctx context.Context, id influxdb.ID) error {
+	r, err := s.FindReportByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteReport(ctx, r.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteReport(ctx, id)
+}
+
+// FindReportRuns checks to see if the authorizer on context has read access to the report provided.
+func (s *ReportService) FindReportRuns(ctx context.Context, id influxdb.ID) ([]*influxdb.ReportRun, error) {
+	if _, err := s.FindReportByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindReportRuns(ctx, id)
+}
+
+// AddReportRun checks to see if the authorizer on context has write access to the report of the run.
+func (s *ReportService) AddReportRun(ctx context.Context, run *influxdb.ReportRun) error {
+	r, err := s.FindReportByID(ctx, run.ReportID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteReport(ctx, r.OrganizationID, r.ID); err != nil {
+		return err
+	}
+
+	return s.s.AddReportRun(ctx, run)
+}
+
+var _ influxdb.ReportDeliveryService = (*ReportDeliveryService)(nil)
+
+// ReportDeliveryService wraps a influxdb.ReportDeliveryService and authorizes
+// actions against it appropriately.
+type ReportDeliveryService struct {
+	s       influxdb.ReportDeliveryService
+	reports influxdb.ReportService
+}
+
+// NewReportDeliveryService constructs an instance of an authorizing report
+// delivery service. The report service is used to look up the organization
+// of the report.
+func NewReportDeliveryService(s influxdb.ReportDeliveryService, rs influxdb.ReportService) *ReportDeliveryService {
+	return &ReportDeliveryService{
+		s:       s,
+		reports: rs,
+	}
+}
+
+// DeliverReport checks to see if the authorizer on context has write access to the report provided.
+func (s *ReportDeliveryService) DeliverReport(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error) {
+	r, err := s.reports.FindReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteReport(ctx, r.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.DeliverReport(ctx, id)
+}
@@ -0,0 +1,124 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestReportService(t *testing.T) {
+	orgID, otherOrgID, reportID, dashboardID, endpointID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(10), influxdb.ID(20), influxdb.ID(30)
+
+	reports := []*influxdb.Report{
+		{ID: reportID, OrganizationID: orgID, Name: "daily", DashboardID: dashboardID, EndpointID: endpointID},
+		{ID: reportID + 1, OrganizationID: otherOrgID, Name: "daily", DashboardID: dashboardID + 1},
+	}
+	readReports := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.ReportsResourceType, OrgID: &orgID}}
+	writeReports := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.ReportsResourceType, OrgID: &orgID}}
+	readBuckets := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID}}
+	readDashboard := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID, ID: &dashboardID}}
+	readEndpoint := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.NotificationEndpointResourceType, OrgID: &orgID, ID: &endpointID}}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantWrite   bool
+		wantDelete  bool
+	}{
+		{
+			name:        "write access to reports and read access to their targets",
+			permissions: []influxdb.Permission{readReports, writeReports, readBuckets, readDashboard, readEndpoint},
+			wantFind:    true,
+			wantWrite:   true,
+			wantDelete:  true,
+		},
+		{
+			name:        "write access to reports without read access to buckets",
+			permissions: []influxdb.Permission{readReports, writeReports, readDashboard, readEndpoint},
+			wantFind:    true,
+			wantDelete:  true,
+		},
+		{
+			name:        "write access to reports without read access to the endpoint",
+			permissions: []influxdb.Permission{readReports, writeReports, readBuckets, readDashboard},
+			wantFind:    true,
+			wantDelete:  true,
+		},
+		{
+			name:        "read access to reports",
+			permissions: []influxdb.Permission{readReports, readBuckets, readDashboard, readEndpoint},
+			wantFind:    true,
+		},
+		{
+			name: "no access to reports",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewReportService()
+			m.FindReportByIDFn = func(context.Context, influxdb.ID) (*influxdb.Report, error) {
+				r := *reports[0]
+				return &r, nil
+			}
+			m.FindReportsFn = func(context.Context, influxdb.ReportFilter, ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+				return append([]*influxdb.Report(nil), reports...), len(reports), nil
+			}
+			ds := mock.NewDashboardService()
+			ds.FindDashboardByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+				return &influxdb.Dashboard{ID: id, OrganizationID: orgID}, nil
+			}
+			s := authorizer.NewReportService(m, ds, mock.NewResourceACLService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindReportByID(ctx, reportID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindReportByID() error = %v, want allowed %v", err, tt.wantFind)
+			}
+			if _, err := s.FindReportRuns(ctx, reportID); (err == nil) != tt.wantFind {
+				t.Errorf("FindReportRuns() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			rs, n, err := s.FindReports(ctx, influxdb.ReportFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantFind && (n != 1 || rs[0].ID != reportID) {
+				t.Errorf("FindReports() = %v, want the report of the organization", rs)
+			}
+			if !tt.wantFind && n != 0 {
+				t.Errorf("FindReports() = %v, want none", rs)
+			}
+
+			r := *reports[0]
+			r.ID = 0
+			if err := s.CreateReport(ctx, &r); (err == nil) != tt.wantWrite {
+				t.Errorf("CreateReport() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			every := influxdb.Duration{Duration: time.Hour}
+			if _, err := s.UpdateReport(ctx, reportID, influxdb.ReportUpdate{Every: &every}); (err == nil) != tt.wantWrite {
+				t.Errorf("UpdateReport() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err := s.DeleteReport(ctx, reportID); (err == nil) != tt.wantDelete {
+				t.Errorf("DeleteReport() error = %v, want allowed %v", err, tt.wantDelete)
+			}
+
+			delivered := false
+			rds := mock.NewReportDeliveryService()
+			rds.DeliverReportFn = func(context.Context, influxdb.ID) (*influxdb.ReportRun, error) {
+				delivered = true
+				return &influxdb.ReportRun{}, nil
+			}
+			_, err = authorizer.NewReportDeliveryService(rds, s).DeliverReport(ctx, reportID)
+			if got := err == nil; got != tt.wantDelete || delivered != tt.wantDelete {
+				t.Errorf("DeliverReport() error = %v, delivered %v, want allowed %v", err, delivered, tt.wantDelete)
+			}
+		})
+	}
+}
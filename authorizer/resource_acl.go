@@ -0,0 +1,108 @@
+package authorizer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var _ influxdb.ResourceACLService = (*ResourceACLService)(nil)
+
+// authorizeResourceACL checks that the acl of the resource allows the user of
+// the authorizer on context the action. Users with write access to the
+// organization of the resource are always allowed, so that a restricted
+// resource cannot become inaccessible.
+func authorizeResourceACL(ctx context.Context, acls influxdb.ResourceACLService, a influxdb.Action, rt influxdb.ResourceType, orgID, id influxdb.ID) error {
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := newOrgPermission(influxdb.WriteAction, orgID)
+	if err != nil {
+		return err
+	}
+	if Allowed(ctx, auth, *p) {
+		return nil
+	}
+
+	acl, err := acls.FindResourceACL(ctx, rt, id)
+	if err != nil {
+		return err
+	}
+	if !acl.Allowed(auth.GetUserID(), a) {
+		return &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("%s:%s/%s is not shared with the user", a, rt, id),
+		}
+	}
+	return nil
+}
+
+// ResourceACLService wraps a influxdb.ResourceACLService and authorizes actions
+// against it appropriately.
+type ResourceACLService struct {
+	s          influxdb.ResourceACLService
+	orgService OrganizationService
+}
+
+// NewResourceACLService constructs an instance of an authorizing resource acl service.
+func NewResourceACLService(orgSvc OrganizationService, s influxdb.ResourceACLService) *ResourceACLService {
+	return &ResourceACLService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// authorize checks to see if the authorizer on context may perform the
+// action on the resource and, if the resource is restricted, that it is
+// shared with the user.
+func (s *ResourceACLService) authorize(ctx context.Context, a influxdb.Action, rt influxdb.ResourceType, id influxdb.ID) error {
+	if err := influxdb.ValidResourceACLResourceType(rt); err != nil {
+		return err
+	}
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, rt, id)
+	if err != nil {
+		return err
+	}
+
+	p, err := influxdb.NewPermissionAtID(id, a, rt, orgID)
+	if err != nil {
+		return err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return authorizeResourceACL(ctx, s.s, a, rt, orgID, id)
+}
+
+// FindResourceACL checks to see if the authorizer on context has read access to the resource.
+func (s *ResourceACLService) FindResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+	if err := s.authorize(ctx, influxdb.ReadAction, rt, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindResourceACL(ctx, rt, id)
+}
+
+// PutResourceACL checks to see if the authorizer on context has write access to the resource.
+func (s *ResourceACLService) PutResourceACL(ctx context.Context, acl *influxdb.ResourceACL) error {
+	if err := s.authorize(ctx, influxdb.WriteAction, acl.ResourceType, acl.ResourceID); err != nil {
+		return err
+	}
+
+	return s.s.PutResourceACL(ctx, acl)
+}
+
+// DeleteResourceACL checks to see if the authorizer on context has write access to the resource.
+func (s *ResourceACLService) DeleteResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error {
+	if err := s.authorize(ctx, influxdb.WriteAction, rt, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteResourceACL(ctx, rt, id)
+}
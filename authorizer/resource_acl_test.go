@@ -0,0 +1,179 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestDashboardService_ResourceACL(t *testing.T) {
+	orgID, dashboardID := influxdb.ID(10), influxdb.ID(1)
+	// the user of the mock Authorizer.
+	userID, otherUserID := influxdb.ID(2), influxdb.ID(3)
+
+	dashboardPermissions := []influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+	}
+	ownerPermissions := append([]influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+	}, dashboardPermissions...)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		grants      []influxdb.ResourceGrant
+		wantRead    bool
+		wantWrite   bool
+	}{
+		{
+			name:        "not restricted",
+			permissions: dashboardPermissions,
+			wantRead:    true,
+			wantWrite:   true,
+		},
+		{
+			name:        "shared for viewing",
+			permissions: dashboardPermissions,
+			grants:      []influxdb.ResourceGrant{{UserID: &userID, Access: influxdb.ResourceAccessView}},
+			wantRead:    true,
+		},
+		{
+			name:        "shared with the organization for editing",
+			permissions: dashboardPermissions,
+			grants:      []influxdb.ResourceGrant{{Access: influxdb.ResourceAccessEdit}},
+			wantRead:    true,
+			wantWrite:   true,
+		},
+		{
+			name:        "shared with another user",
+			permissions: dashboardPermissions,
+			grants:      []influxdb.ResourceGrant{{UserID: &otherUserID, Access: influxdb.ResourceAccessEdit}},
+		},
+		{
+			name:        "owner of the organization",
+			permissions: ownerPermissions,
+			grants:      []influxdb.ResourceGrant{{UserID: &otherUserID, Access: influxdb.ResourceAccessEdit}},
+			wantRead:    true,
+			wantWrite:   true,
+		},
+		{
+			name:   "shared without permission on the dashboard",
+			grants: []influxdb.ResourceGrant{{UserID: &userID, Access: influxdb.ResourceAccessEdit}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acls := mock.NewResourceACLService()
+			acls.FindResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+				return &influxdb.ResourceACL{ResourceType: rt, ResourceID: id, Grants: tt.grants}, nil
+			}
+			dashboards := &mock.DashboardService{
+				FindDashboardByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+					return &influxdb.Dashboard{ID: id, OrganizationID: orgID}, nil
+				},
+				FindDashboardsF: func(ctx context.Context, filter influxdb.DashboardFilter, opt influxdb.FindOptions) ([]*influxdb.Dashboard, int, error) {
+					return []*influxdb.Dashboard{{ID: dashboardID, OrganizationID: orgID}}, 1, nil
+				},
+				UpdateDashboardF: func(ctx context.Context, id influxdb.ID, upd influxdb.DashboardUpdate) (*influxdb.Dashboard, error) {
+					return &influxdb.Dashboard{ID: id, OrganizationID: orgID}, nil
+				},
+			}
+			s := authorizer.NewDashboardService(dashboards, acls)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindDashboardByID(ctx, dashboardID)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindDashboardByID() error = %v, want allowed %v", err, tt.wantRead)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindDashboardByID() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			ds, _, err := s.FindDashboards(ctx, influxdb.DashboardFilter{}, influxdb.FindOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(ds) == 1; got != tt.wantRead {
+				t.Errorf("FindDashboards() = %d dashboards, want listed %v", len(ds), tt.wantRead)
+			}
+
+			_, err = s.UpdateDashboard(ctx, dashboardID, influxdb.DashboardUpdate{})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("UpdateDashboard() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("UpdateDashboard() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
+
+func TestResourceACLService(t *testing.T) {
+	orgID, dashboardID := influxdb.ID(10), influxdb.ID(1)
+	userID := influxdb.ID(2)
+
+	readPermissions := []influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+	}
+	writePermissions := append([]influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+	}, readPermissions...)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		grants      []influxdb.ResourceGrant
+		wantFind    bool
+		wantWrite   bool
+	}{
+		{
+			name:        "write access to dashboards",
+			permissions: writePermissions,
+			wantFind:    true,
+			wantWrite:   true,
+		},
+		{
+			name:        "read access to dashboards",
+			permissions: readPermissions,
+			wantFind:    true,
+		},
+		{
+			name:        "write access to a dashboard shared for viewing",
+			permissions: writePermissions,
+			grants:      []influxdb.ResourceGrant{{UserID: &userID, Access: influxdb.ResourceAccessView}},
+			wantFind:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acls := mock.NewResourceACLService()
+			acls.FindResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+				return &influxdb.ResourceACL{ResourceType: rt, ResourceID: id, Grants: tt.grants}, nil
+			}
+			s := authorizer.NewResourceACLService(&OrgService{OrgID: orgID}, acls)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindResourceACL(ctx, influxdb.DashboardsResourceType, dashboardID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindResourceACL() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			err = s.PutResourceACL(ctx, &influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: dashboardID})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("PutResourceACL() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+
+			err = s.DeleteResourceACL(ctx, influxdb.DashboardsResourceType, dashboardID)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("DeleteResourceACL() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
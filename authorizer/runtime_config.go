@@ -0,0 +1,59 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RuntimeConfigService = (*RuntimeConfigService)(nil)
+
+// RuntimeConfigService wraps a influxdb.RuntimeConfigService and authorizes actions
+// against it appropriately. The runtime config affects the whole server, so
+// only authorizers with access to all organizations may read or change it.
+type RuntimeConfigService struct {
+	s influxdb.RuntimeConfigService
+}
+
+// NewRuntimeConfigService constructs an instance of an authorizing runtime config service.
+func NewRuntimeConfigService(s influxdb.RuntimeConfigService) *RuntimeConfigService {
+	return &RuntimeConfigService{
+		s: s,
+	}
+}
+
+func authorizeRuntimeConfig(ctx context.Context, a influxdb.Action) error {
+	p, err := influxdb.NewGlobalPermission(a, influxdb.OrgsResourceType)
+	if err != nil {
+		return err
+	}
+
+	return IsAllowed(ctx, *p)
+}
+
+// FindRuntimeConfig checks to see if the authorizer on context has read access to all orgs.
+func (s *RuntimeConfigService) FindRuntimeConfig(ctx context.Context) (*influxdb.RuntimeConfig, error) {
+	if err := authorizeRuntimeConfig(ctx, influxdb.ReadAction); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindRuntimeConfig(ctx)
+}
+
+// UpdateRuntimeConfig checks to see if the authorizer on context has write access to all orgs.
+func (s *RuntimeConfigService) UpdateRuntimeConfig(ctx context.Context, upd influxdb.RuntimeConfigUpdate) (*influxdb.RuntimeConfig, error) {
+	if err := authorizeRuntimeConfig(ctx, influxdb.WriteAction); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateRuntimeConfig(ctx, upd)
+}
+
+// ResetRuntimeConfig checks to see if the authorizer on context has write access to all orgs.
+func (s *RuntimeConfigService) ResetRuntimeConfig(ctx context.Context) (*influxdb.RuntimeConfig, error) {
+	if err := authorizeRuntimeConfig(ctx, influxdb.WriteAction); err != nil {
+		return nil, err
+	}
+
+	return s.s.ResetRuntimeConfig(ctx)
+}
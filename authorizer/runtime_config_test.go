@@ -0,0 +1,66 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestRuntimeConfigService(t *testing.T) {
+	orgID := influxdb.ID(1)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantRead    bool
+		wantWrite   bool
+	}{
+		{
+			name: "operator can read and write",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantRead:  true,
+			wantWrite: true,
+		},
+		{
+			name: "global read access can only read",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantRead: true,
+		},
+		{
+			name: "org scoped access is denied",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRuntimeConfigService(mock.NewRuntimeConfigService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindRuntimeConfig(ctx)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindRuntimeConfig() error = %v, want allowed %v", err, tt.wantRead)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindRuntimeConfig() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.UpdateRuntimeConfig(ctx, influxdb.RuntimeConfigUpdate{})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("UpdateRuntimeConfig() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
@@ -25,6 +25,32 @@ func newSecretPermission(a influxdb.Action, orgID influxdb.ID) (*influxdb.Permis
 	return influxdb.NewPermission(a, influxdb.SecretsResourceType, orgID)
 }
 
+func authorizeReadSecretKeys(ctx context.Context, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.SecretKeysResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeDeleteSecret(ctx context.Context, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.SecretDeletionsResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func authorizeReadSecret(ctx context.Context, orgID influxdb.ID) error {
 	p, err := newSecretPermission(influxdb.ReadAction, orgID)
 	if err != nil {
@@ -65,9 +91,9 @@ func (s *SecretService) LoadSecret(ctx context.Context, orgID influxdb.ID, key s
 	return secret, nil
 }
 
-// GetSecretKeys checks to see if the authorizer on context has read access to all the secrets belonging to orgID.
+// GetSecretKeys checks to see if the authorizer on context has access to list the keys of the secrets belonging to orgID.
 func (s *SecretService) GetSecretKeys(ctx context.Context, orgID influxdb.ID) ([]string, error) {
-	if err := authorizeReadSecret(ctx, orgID); err != nil {
+	if err := authorizeReadSecretKeys(ctx, orgID); err != nil {
 		return []string{}, err
 	}
 
@@ -93,7 +119,7 @@ func (s *SecretService) PutSecret(ctx context.Context, orgID influxdb.ID, key st
 	return nil
 }
 
-// PutSecrets checks to see if the authorizer on context has read and write access to the secret keys provided.
+// PutSecrets checks to see if the authorizer on context has read, write and delete access to the secret keys provided.
 func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
 	// PutSecrets operates on intersection between m and keys beloging to orgID.
 	// We need to have read access to those secrets since it deletes the secrets (within the intersection) that have not be overridden.
@@ -105,6 +131,10 @@ func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map
 		return err
 	}
 
+	if err := authorizeDeleteSecret(ctx, orgID); err != nil {
+		return err
+	}
+
 	err := s.s.PutSecrets(ctx, orgID, m)
 	if err != nil {
 		return err
@@ -127,9 +157,9 @@ func (s *SecretService) PatchSecrets(ctx context.Context, orgID influxdb.ID, m m
 	return nil
 }
 
-// DeleteSecret checks to see if the authorizer on context has write access to the secret keys provided.
+// DeleteSecret checks to see if the authorizer on context has delete access to the secret keys provided.
 func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, keys ...string) error {
-	if err := authorizeWriteSecret(ctx, orgID); err != nil {
+	if err := authorizeDeleteSecret(ctx, orgID); err != nil {
 		return err
 	}
 
@@ -185,7 +185,7 @@ func TestSecretService_GetSecretKeys(t *testing.T) {
 				permission: influxdb.Permission{
 					Action: "read",
 					Resource: influxdb.Resource{
-						Type:  influxdb.SecretsResourceType,
+						Type:  influxdb.SecretKeysResourceType,
 						OrgID: influxdbtesting.IDPtr(1),
 					},
 				},
@@ -216,7 +216,7 @@ func TestSecretService_GetSecretKeys(t *testing.T) {
 				permission: influxdb.Permission{
 					Action: "read",
 					Resource: influxdb.Resource{
-						Type:  influxdb.SecretsResourceType,
+						Type:  influxdb.SecretKeysResourceType,
 						OrgID: influxdbtesting.IDPtr(1),
 					},
 				},
@@ -225,7 +225,7 @@ func TestSecretService_GetSecretKeys(t *testing.T) {
 			wants: wants{
 				err: &influxdb.Error{
 					Code: influxdb.EUnauthorized,
-					Msg:  "read:orgs/0000000000000002/secrets is unauthorized",
+					Msg:  "read:orgs/0000000000000002/secretKeys is unauthorized",
 				},
 				secrets: []string{},
 			},
@@ -246,7 +246,7 @@ func TestSecretService_GetSecretKeys(t *testing.T) {
 				permission: influxdb.Permission{
 					Action: "read",
 					Resource: influxdb.Resource{
-						Type:  influxdb.SecretsResourceType,
+						Type:  influxdb.SecretKeysResourceType,
 						OrgID: influxdbtesting.IDPtr(10),
 					},
 				},
@@ -399,7 +399,7 @@ func TestSecretService_DeleteSecret(t *testing.T) {
 					{
 						Action: "write",
 						Resource: influxdb.Resource{
-							Type:  influxdb.SecretsResourceType,
+							Type:  influxdb.SecretDeletionsResourceType,
 							OrgID: influxdbtesting.IDPtr(1),
 						},
 					},
@@ -432,7 +432,7 @@ func TestSecretService_DeleteSecret(t *testing.T) {
 			},
 			wants: wants{
 				err: &influxdb.Error{
-					Msg:  "write:orgs/000000000000000a/secrets is unauthorized",
+					Msg:  "write:orgs/000000000000000a/secretDeletions is unauthorized",
 					Code: influxdb.EUnauthorized,
 				},
 			},
@@ -578,6 +578,13 @@ func TestSecretService_PutSecrets(t *testing.T) {
 							OrgID: influxdbtesting.IDPtr(10),
 						},
 					},
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type:  influxdb.SecretDeletionsResourceType,
+							OrgID: influxdbtesting.IDPtr(10),
+						},
+					},
 				},
 			},
 			wants: wants{
@@ -681,6 +688,41 @@ func TestSecretService_PutSecrets(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "unauthorized to put secrets without delete access to their org",
+			fields: fields{
+				SecretService: &mock.SecretService{
+					PutSecretsFn: func(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				orgID: 10,
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type:  influxdb.SecretsResourceType,
+							OrgID: influxdbtesting.IDPtr(10),
+						},
+					},
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type:  influxdb.SecretsResourceType,
+							OrgID: influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/secretDeletions is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
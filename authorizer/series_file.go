@@ -0,0 +1,56 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SeriesFileService = (*SeriesFileService)(nil)
+
+// SeriesFileService wraps a influxdb.SeriesFileService and authorizes actions
+// against it appropriately. The series file holds the series of all
+// organizations, so only authorizers with access to all organizations may
+// inspect or maintain it.
+type SeriesFileService struct {
+	s influxdb.SeriesFileService
+}
+
+// NewSeriesFileService constructs an instance of an authorizing series file service.
+func NewSeriesFileService(s influxdb.SeriesFileService) *SeriesFileService {
+	return &SeriesFileService{
+		s: s,
+	}
+}
+
+// FindSeriesFileStats checks to see if the authorizer on context has read access to all orgs.
+func (s *SeriesFileService) FindSeriesFileStats(ctx context.Context) (*influxdb.SeriesFileStats, error) {
+	if err := authorizeRuntimeConfig(ctx, influxdb.ReadAction); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindSeriesFileStats(ctx)
+}
+
+// CompactSeriesFile checks to see if the authorizer on context has write access to all orgs.
+func (s *SeriesFileService) CompactSeriesFile(ctx context.Context) error {
+	if err := authorizeRuntimeConfig(ctx, influxdb.WriteAction); err != nil {
+		return err
+	}
+
+	return s.s.CompactSeriesFile(ctx)
+}
+
+// VerifySeriesFile checks to see if the authorizer on context has read access
+// to all orgs, and write access to all orgs to repair the series file.
+func (s *SeriesFileService) VerifySeriesFile(ctx context.Context, repair bool) (*influxdb.SeriesFileVerification, error) {
+	a := influxdb.ReadAction
+	if repair {
+		a = influxdb.WriteAction
+	}
+	if err := authorizeRuntimeConfig(ctx, a); err != nil {
+		return nil, err
+	}
+
+	return s.s.VerifySeriesFile(ctx, repair)
+}
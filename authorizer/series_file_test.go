@@ -0,0 +1,76 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestSeriesFileService(t *testing.T) {
+	orgID := influxdb.ID(1)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantRead    bool
+		wantWrite   bool
+	}{
+		{
+			name: "operator can inspect and maintain",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantRead:  true,
+			wantWrite: true,
+		},
+		{
+			name: "global read access can only inspect",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantRead: true,
+		},
+		{
+			name: "org scoped access is denied",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSeriesFileService(mock.NewSeriesFileService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindSeriesFileStats(ctx)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindSeriesFileStats() error = %v, want allowed %v", err, tt.wantRead)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindSeriesFileStats() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.VerifySeriesFile(ctx, false)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("VerifySeriesFile(false) error = %v, want allowed %v", err, tt.wantRead)
+			}
+
+			_, err = s.VerifySeriesFile(ctx, true)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("VerifySeriesFile(true) error = %v, want allowed %v", err, tt.wantWrite)
+			}
+
+			err = s.CompactSeriesFile(ctx)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("CompactSeriesFile() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SessionAlertService = (*SessionAlertService)(nil)
+
+// SessionAlertService wraps a influxdb.SessionAlertService and authorizes actions
+// against it appropriately.
+type SessionAlertService struct {
+	s influxdb.SessionAlertService
+}
+
+// NewSessionAlertService constructs an instance of an authorizing session alert service.
+func NewSessionAlertService(s influxdb.SessionAlertService) *SessionAlertService {
+	return &SessionAlertService{
+		s: s,
+	}
+}
+
+// CreateSessionAlert checks to see if the authorizer on context has write access to the user of the alert.
+func (s *SessionAlertService) CreateSessionAlert(ctx context.Context, a *influxdb.SessionAlert) error {
+	if err := authorizeWriteUser(ctx, a.UserID); err != nil {
+		return err
+	}
+
+	return s.s.CreateSessionAlert(ctx, a)
+}
+
+// FindSessionAlerts checks to see if the authorizer on context has read access to the user.
+func (s *SessionAlertService) FindSessionAlerts(ctx context.Context, userID influxdb.ID) ([]*influxdb.SessionAlert, error) {
+	if err := authorizeReadUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindSessionAlerts(ctx, userID)
+}
+
+// DeleteSessionAlerts checks to see if the authorizer on context has write access to the user.
+func (s *SessionAlertService) DeleteSessionAlerts(ctx context.Context, userID influxdb.ID) error {
+	if err := authorizeWriteUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteSessionAlerts(ctx, userID)
+}
@@ -0,0 +1,71 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestSessionAlertService(t *testing.T) {
+	userID, otherUserID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantWrite   bool
+	}{
+		{
+			name: "write access to the user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+			},
+			wantFind:  true,
+			wantWrite: true,
+		},
+		{
+			name: "read access to the user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+			},
+			wantFind: true,
+		},
+		{
+			name: "write access to another user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &otherUserID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &otherUserID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSessionAlertService(mock.NewSessionAlertService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindSessionAlerts(ctx, userID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindSessionAlerts() error = %v, want allowed %v", err, tt.wantFind)
+			}
+
+			err = s.CreateSessionAlert(ctx, &influxdb.SessionAlert{UserID: userID})
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("CreateSessionAlert() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+
+			err = s.DeleteSessionAlerts(ctx, userID)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("DeleteSessionAlerts() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("DeleteSessionAlerts() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
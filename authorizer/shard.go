@@ -0,0 +1,59 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.ShardService = (*ShardService)(nil)
+
+// ShardService wraps a influxdb.ShardService and authorizes actions
+// against it appropriately.
+type ShardService struct {
+	s          influxdb.ShardService
+	orgService OrganizationService
+}
+
+// NewShardService constructs an instance of an authorizing shard service.
+func NewShardService(orgSvc OrganizationService, s influxdb.ShardService) *ShardService {
+	return &ShardService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// FindShardGroupStats checks to see if the authorizer on context has read access to the bucket.
+func (s *ShardService) FindShardGroupStats(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.ShardGroupStats, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindShardGroupStats(ctx, bucketID)
+}
+
+// FindCompactionPlan checks to see if the authorizer on context has read access to the bucket.
+func (s *ShardService) FindCompactionPlan(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.CompactionPlanGroup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindCompactionPlan(ctx, bucketID)
+}
@@ -0,0 +1,82 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestShardService_FindShardGroupStats(t *testing.T) {
+	orgID, bucketID, otherBucketID := influxdb.ID(10), influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to read the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &bucketID},
+			},
+		},
+		{
+			name: "authorized to read the buckets of the organization",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+			},
+		},
+		{
+			name: "unauthorized to read the bucket",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: &otherBucketID},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewShardService()
+			svc.FindShardGroupStatsFn = func(ctx context.Context, id influxdb.ID) ([]*influxdb.ShardGroupStats, error) {
+				return []*influxdb.ShardGroupStats{{BucketID: id}}, nil
+			}
+			svc.FindCompactionPlanFn = func(ctx context.Context, id influxdb.ID) ([]*influxdb.CompactionPlanGroup, error) {
+				return []*influxdb.CompactionPlanGroup{{Level: 1}}, nil
+			}
+			s := authorizer.NewShardService(&OrgService{OrgID: orgID}, svc)
+
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: []influxdb.Permission{tt.permission}})
+			stats, err := s.FindShardGroupStats(ctx, bucketID)
+			plan, planErr := s.FindCompactionPlan(ctx, bucketID)
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+					t.Fatalf("expected unauthorized error, got %v", err)
+				}
+				if influxdb.ErrorCode(planErr) != influxdb.EUnauthorized {
+					t.Fatalf("expected unauthorized error of the compaction plan, got %v", planErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if planErr != nil {
+				t.Fatal(planErr)
+			}
+			if len(stats) != 1 {
+				t.Errorf("expected stats of the bucket, got %+v", stats)
+			}
+			if len(plan) != 1 {
+				t.Errorf("expected the compaction plan of the bucket, got %+v", plan)
+			}
+		})
+	}
+}
@@ -98,7 +98,7 @@ func (ts *taskServiceValidator) FindTasks(ctx context.Context, filter influxdb.T
 		}
 
 		// We don't want to log authorization errors on this one.
-		if !auth.Allowed(*perm) {
+		if !Allowed(ctx, auth, *perm) {
 			continue
 		}
 
@@ -326,7 +326,7 @@ func (ts *taskServiceValidator) validatePermission(ctx context.Context, perm inf
 		return err
 	}
 
-	if !auth.Allowed(perm) {
+	if !Allowed(ctx, auth, perm) {
 		ts.logger.With(loggerFields...).Info("Authorization failed",
 			zap.String("user_id", auth.GetUserID().String()),
 			zap.String("auth_kind", auth.Kind()),
@@ -0,0 +1,75 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskBackfillService(t *testing.T) {
+	orgID, taskID := influxdb.ID(1), influxdb.ID(10)
+
+	ts := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+			return &influxdb.Task{ID: id, OrganizationID: orgID}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantRead    bool
+		wantWrite   bool
+	}{
+		{
+			name: "write access to the task",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+			},
+			wantRead:  true,
+			wantWrite: true,
+		},
+		{
+			name: "read access to the task",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID}},
+			},
+			wantRead: true,
+		},
+		{
+			name: "no access to the task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTaskBackfillService(mock.NewTaskBackfillService(), ts)
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindTaskBackfill(ctx, taskID)
+			if got := err == nil; got != tt.wantRead {
+				t.Errorf("FindTaskBackfill() error = %v, want allowed %v", err, tt.wantRead)
+			}
+
+			now := time.Now()
+			_, err = s.BackfillTask(ctx, taskID, now.Add(-time.Hour), now)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("BackfillTask() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("BackfillTask() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			err = s.CancelTaskBackfill(ctx, taskID)
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("CancelTaskBackfill() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
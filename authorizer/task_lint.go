@@ -0,0 +1,31 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskLintService = (*TaskLintService)(nil)
+
+// TaskLintService wraps a influxdb.TaskLintService and authorizes actions
+// against it appropriately.
+type TaskLintService struct {
+	s influxdb.TaskLintService
+}
+
+// NewTaskLintService constructs an instance of an authorizing task lint service.
+func NewTaskLintService(s influxdb.TaskLintService) *TaskLintService {
+	return &TaskLintService{
+		s: s,
+	}
+}
+
+// LintTask checks to see if the authorizer on context has read access to the organization.
+func (s *TaskLintService) LintTask(ctx context.Context, orgID influxdb.ID, script string) (*influxdb.TaskLintResult, error) {
+	if err := authorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.LintTask(ctx, orgID, script)
+}
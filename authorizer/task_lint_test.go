@@ -0,0 +1,53 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskLintService(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantAllowed bool
+	}{
+		{
+			name: "read access to the organization",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "read access to another organization",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+			},
+		},
+		{
+			name: "no access to the organization",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTaskLintService(mock.NewTaskLintService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.LintTask(ctx, orgID, `option task = {name: "a", every: 1h}`)
+			if got := err == nil; got != tt.wantAllowed {
+				t.Errorf("LintTask() error = %v, want allowed %v", err, tt.wantAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("LintTask() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
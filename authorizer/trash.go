@@ -0,0 +1,109 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TrashService = (*TrashService)(nil)
+
+// TrashService wraps a influxdb.TrashService and authorizes actions
+// against it appropriately. Trashed resources are authorized with the
+// permissions and the acl of the resource they were before deletion.
+type TrashService struct {
+	s    influxdb.TrashService
+	acls influxdb.ResourceACLService
+}
+
+// NewTrashService constructs an instance of an authorizing trash service.
+func NewTrashService(s influxdb.TrashService, acls influxdb.ResourceACLService) *TrashService {
+	return &TrashService{
+		s:    s,
+		acls: acls,
+	}
+}
+
+func (s *TrashService) authorizeTrashedResource(ctx context.Context, a influxdb.Action, r *influxdb.TrashedResource) error {
+	p, err := influxdb.NewPermissionAtID(r.ID, a, r.Type, r.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if influxdb.ValidResourceACLResourceType(r.Type) != nil {
+		return nil
+	}
+	return authorizeResourceACL(ctx, s.acls, a, r.Type, r.OrgID, r.ID)
+}
+
+// FindTrashedResourceByID checks to see if the authorizer on context has read access to the trashed resource.
+func (s *TrashService) FindTrashedResourceByID(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	r, err := s.s.FindTrashedResourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeTrashedResource(ctx, influxdb.ReadAction, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindTrashedResources retrieves all trashed resources that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *TrashService) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	rs, _, err := s.s.FindTrashedResources(ctx, filter, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	resources := rs[:0]
+	for _, r := range rs {
+		err := s.authorizeTrashedResource(ctx, influxdb.ReadAction, r)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, len(resources), nil
+}
+
+// RestoreDashboard checks to see if the authorizer on context has write access to the trashed dashboard.
+func (s *TrashService) RestoreDashboard(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+	r, err := s.s.FindTrashedResourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeTrashedResource(ctx, influxdb.WriteAction, r); err != nil {
+		return nil, err
+	}
+
+	return s.s.RestoreDashboard(ctx, id)
+}
+
+// RestoreTask checks to see if the authorizer on context has write access to the trashed task.
+func (s *TrashService) RestoreTask(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	r, err := s.s.FindTrashedResourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeTrashedResource(ctx, influxdb.WriteAction, r); err != nil {
+		return nil, err
+	}
+
+	return s.s.RestoreTask(ctx, id)
+}
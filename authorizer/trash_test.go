@@ -0,0 +1,228 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newTrashService() *mock.TrashService {
+	resources := []*influxdb.TrashedResource{
+		{ID: 1, OrgID: 10, Type: influxdb.DashboardsResourceType, Name: "dash"},
+		{ID: 2, OrgID: 10, Type: influxdb.TasksResourceType, Name: "task"},
+	}
+
+	s := mock.NewTrashService()
+	s.FindTrashedResourceByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error) {
+		for _, r := range resources {
+			if r.ID == id {
+				return r, nil
+			}
+		}
+		return nil, influxdb.ErrTrashedResourceNotFound
+	}
+	s.FindTrashedResourcesFn = func(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+		rs := append([]*influxdb.TrashedResource(nil), resources...)
+		return rs, len(rs), nil
+	}
+	s.RestoreDashboardFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+		return &influxdb.Dashboard{ID: id, OrganizationID: 10}, nil
+	}
+	s.RestoreTaskFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+	}
+	return s
+}
+
+func TestTrashService_FindTrashedResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wants      []influxdb.ID
+	}{
+		{
+			name: "authorized to see all trashed dashboards",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type: influxdb.DashboardsResourceType,
+				},
+			},
+			wants: []influxdb.ID{1},
+		},
+		{
+			name: "authorized to see trashed tasks of the org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.TasksResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			wants: []influxdb.ID{2},
+		},
+		{
+			name: "not authorized to see trashed resources of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.DashboardsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTrashService(newTrashService(), mock.NewResourceACLService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			rs, _, err := s.FindTrashedResources(ctx, influxdb.TrashFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []influxdb.ID
+			for _, r := range rs {
+				ids = append(ids, r.ID)
+			}
+			if diff := cmp.Diff(ids, tt.wants); diff != "" {
+				t.Errorf("trashed resources are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		restore    func(context.Context, *authorizer.TrashService) error
+		wants      error
+	}{
+		{
+			name: "authorized to restore dashboard",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type: influxdb.DashboardsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			restore: func(ctx context.Context, s *authorizer.TrashService) error {
+				_, err := s.RestoreDashboard(ctx, 1)
+				return err
+			},
+		},
+		{
+			name: "unauthorized to restore dashboard with read permission",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type: influxdb.DashboardsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			restore: func(ctx context.Context, s *authorizer.TrashService) error {
+				_, err := s.RestoreDashboard(ctx, 1)
+				return err
+			},
+			wants: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/dashboards/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name: "authorized to restore task",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.TasksResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			restore: func(ctx context.Context, s *authorizer.TrashService) error {
+				_, err := s.RestoreTask(ctx, 2)
+				return err
+			},
+		},
+		{
+			name: "unauthorized to restore task of another org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.TasksResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			restore: func(ctx context.Context, s *authorizer.TrashService) error {
+				_, err := s.RestoreTask(ctx, 2)
+				return err
+			},
+			wants: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTrashService(newTrashService(), mock.NewResourceACLService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			influxdbtesting.ErrorsEqual(t, tt.restore(ctx, s), tt.wants)
+		})
+	}
+}
+
+func TestTrashService_ResourceACL(t *testing.T) {
+	orgID := influxdb.ID(10)
+	// the user of the mock Authorizer is 2.
+	otherUserID := influxdb.ID(3)
+
+	acls := mock.NewResourceACLService()
+	acls.FindResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+		if rt != influxdb.DashboardsResourceType {
+			t.Errorf("unexpected acl lookup of %s/%s", rt, id)
+		}
+		return &influxdb.ResourceACL{
+			ResourceType: rt,
+			ResourceID:   id,
+			Grants:       []influxdb.ResourceGrant{{UserID: &otherUserID, Access: influxdb.ResourceAccessEdit}},
+		}, nil
+	}
+	s := authorizer.NewTrashService(newTrashService(), acls)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID}},
+	}})
+
+	rs, _, err := s.FindTrashedResources(ctx, influxdb.TrashFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].ID != 2 {
+		t.Errorf("expected only the trashed task to be listed, got %+v", rs)
+	}
+
+	if _, err := s.FindTrashedResourceByID(ctx, 1); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected finding a dashboard not shared with the user to be unauthorized, got %v", err)
+	}
+	if _, err := s.RestoreDashboard(ctx, 1); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected restoring a dashboard not shared with the user to be unauthorized, got %v", err)
+	}
+}
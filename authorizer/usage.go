@@ -0,0 +1,43 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.UsageService = (*UsageService)(nil)
+
+// UsageService wraps a influxdb.UsageService and authorizes actions
+// against it appropriately.
+type UsageService struct {
+	s influxdb.UsageService
+}
+
+// NewUsageService constructs an instance of an authorizing usage service.
+func NewUsageService(s influxdb.UsageService) *UsageService {
+	return &UsageService{
+		s: s,
+	}
+}
+
+// GetUsage checks to see if the authorizer on context has read access to the
+// organization in the filter. Usage across all organizations requires read
+// access to all organizations.
+func (s *UsageService) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.OrgID != nil {
+		if err := authorizeReadOrg(ctx, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	} else {
+		p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, influxdb.OrgsResourceType)
+		if err != nil {
+			return nil, err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.GetUsage(ctx, filter)
+}
@@ -0,0 +1,66 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestUsageService_GetUsage(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		orgID       *influxdb.ID
+		wantAllowed bool
+	}{
+		{
+			name: "read access to the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+			orgID:       &orgID,
+			wantAllowed: true,
+		},
+		{
+			name: "read access to another org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &otherOrgID}},
+			},
+			orgID: &orgID,
+		},
+		{
+			name: "all orgs with global read access",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "all orgs with org scoped access",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewUsageService(mock.NewUsageService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.GetUsage(ctx, influxdb.UsageFilter{OrgID: tt.orgID})
+			if got := err == nil; got != tt.wantAllowed {
+				t.Errorf("GetUsage() error = %v, want allowed %v", err, tt.wantAllowed)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("GetUsage() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.UserSettingsService = (*UserSettingsService)(nil)
+
+// UserSettingsService wraps a influxdb.UserSettingsService and authorizes actions
+// against it appropriately.
+type UserSettingsService struct {
+	s influxdb.UserSettingsService
+}
+
+// NewUserSettingsService constructs an instance of an authorizing user settings service.
+func NewUserSettingsService(s influxdb.UserSettingsService) *UserSettingsService {
+	return &UserSettingsService{
+		s: s,
+	}
+}
+
+// FindUserSettings checks to see if the authorizer on context has read access to the user.
+func (s *UserSettingsService) FindUserSettings(ctx context.Context, userID influxdb.ID) (*influxdb.UserSettings, error) {
+	if err := authorizeReadUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindUserSettings(ctx, userID)
+}
+
+// PutUserSetting checks to see if the authorizer on context has write access to the user.
+func (s *UserSettingsService) PutUserSetting(ctx context.Context, userID influxdb.ID, namespace string, value json.RawMessage) (*influxdb.UserSettings, error) {
+	if err := authorizeWriteUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.s.PutUserSetting(ctx, userID, namespace, value)
+}
+
+// DeleteUserSetting checks to see if the authorizer on context has write access to the user.
+func (s *UserSettingsService) DeleteUserSetting(ctx context.Context, userID influxdb.ID, namespace string) error {
+	if err := authorizeWriteUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteUserSetting(ctx, userID, namespace)
+}
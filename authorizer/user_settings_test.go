@@ -0,0 +1,78 @@
+package authorizer_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestUserSettingsService(t *testing.T) {
+	userID, otherUserID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantFind    bool
+		wantWrite   bool
+	}{
+		{
+			name: "write access to the user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+			},
+			wantFind:  true,
+			wantWrite: true,
+		},
+		{
+			name: "read access to the user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &userID}},
+			},
+			wantFind: true,
+		},
+		{
+			name: "write access to another user",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &otherUserID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: &otherUserID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewUserSettingsService(mock.NewUserSettingsService())
+			ctx := icontext.SetAuthorizer(context.Background(), &Authorizer{Permissions: tt.permissions})
+
+			_, err := s.FindUserSettings(ctx, userID)
+			if got := err == nil; got != tt.wantFind {
+				t.Errorf("FindUserSettings() error = %v, want allowed %v", err, tt.wantFind)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("FindUserSettings() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			_, err = s.PutUserSetting(ctx, userID, "theme", json.RawMessage(`"dark"`))
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("PutUserSetting() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("PutUserSetting() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+
+			err = s.DeleteUserSetting(ctx, userID, "theme")
+			if got := err == nil; got != tt.wantWrite {
+				t.Errorf("DeleteUserSetting() error = %v, want allowed %v", err, tt.wantWrite)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("DeleteUserSetting() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EUnauthorized)
+			}
+		})
+	}
+}
@@ -48,6 +48,9 @@ const (
 	ReadAction Action = "read" // 1
 	// WriteAction is the action for writing.
 	WriteAction Action = "write" // 2
+	// DeleteAction is the action for deleting the data of a bucket, which
+	// writing to the bucket does not permit.
+	DeleteAction Action = "delete" // 3
 )
 
 var actions = []Action{
@@ -60,6 +63,7 @@ func (a Action) Valid() (err error) {
 	switch a {
 	case ReadAction: // 1
 	case WriteAction: // 2
+	case DeleteAction: // 3
 	default:
 		err = ErrInvalidAction
 	}
@@ -129,6 +133,20 @@ const (
 	NotificationEndpointResourceType = ResourceType("notificationEndpoints") // 15
 	// ChecksResourceType gives permission to one or more Checks.
 	ChecksResourceType = ResourceType("checks") // 16
+	// SecretKeysResourceType gives permission to list the keys of the secrets of an org.
+	SecretKeysResourceType = ResourceType("secretKeys") // 17
+	// SecretDeletionsResourceType gives permission to delete the secrets of an org.
+	SecretDeletionsResourceType = ResourceType("secretDeletions") // 18
+	// MaterializedViewsResourceType gives permission to one or more materialized views.
+	MaterializedViewsResourceType = ResourceType("materializedViews") // 19
+	// RemoteConnectionsResourceType gives permission to one or more remote connections.
+	RemoteConnectionsResourceType = ResourceType("remotes") // 20
+	// NotebooksResourceType gives permission to one or more notebooks.
+	NotebooksResourceType = ResourceType("notebooks") // 21
+	// QueryTemplatesResourceType gives permission to one or more query templates.
+	QueryTemplatesResourceType = ResourceType("queryTemplates") // 22
+	// ReportsResourceType gives permission to one or more scheduled reports.
+	ReportsResourceType = ResourceType("reports") // 23
 )
 
 // AllResourceTypes is the list of all known resource types.
@@ -150,6 +168,13 @@ var AllResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	SecretKeysResourceType,           // 17
+	SecretDeletionsResourceType,      // 18
+	MaterializedViewsResourceType,    // 19
+	RemoteConnectionsResourceType,    // 20
+	NotebooksResourceType,            // 21
+	QueryTemplatesResourceType,       // 22
+	ReportsResourceType,              // 23
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
@@ -167,6 +192,13 @@ var OrgResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	SecretKeysResourceType,           // 17
+	SecretDeletionsResourceType,      // 18
+	MaterializedViewsResourceType,    // 19
+	RemoteConnectionsResourceType,    // 20
+	NotebooksResourceType,            // 21
+	QueryTemplatesResourceType,       // 22
+	ReportsResourceType,              // 23
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -194,6 +226,13 @@ func (t ResourceType) Valid() (err error) {
 	case NotificationRuleResourceType: // 14
 	case NotificationEndpointResourceType: // 15
 	case ChecksResourceType: // 16
+	case SecretKeysResourceType: // 17
+	case SecretDeletionsResourceType: // 18
+	case MaterializedViewsResourceType: // 19
+	case RemoteConnectionsResourceType: // 20
+	case NotebooksResourceType: // 21
+	case QueryTemplatesResourceType: // 22
+	case ReportsResourceType: // 23
 	default:
 		err = ErrInvalidResourceType
 	}
@@ -266,6 +305,14 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if p.Action == DeleteAction && p.Resource.Type != BucketsResourceType {
+		return &Error{
+			Code: EInvalid,
+			Err:  ErrInvalidAction,
+			Msg:  "delete action is only valid for buckets",
+		}
+	}
+
 	if p.Resource.OrgID != nil && !(*p.Resource.OrgID).Valid() {
 		return &Error{
 			Code: EInvalid,
@@ -331,6 +378,7 @@ func OperPermissions() []Permission {
 			ps = append(ps, Permission{Action: a, Resource: Resource{Type: r}})
 		}
 	}
+	ps = append(ps, Permission{Action: DeleteAction, Resource: Resource{Type: BucketsResourceType}})
 
 	return ps
 }
@@ -347,6 +395,7 @@ func OwnerPermissions(orgID ID) []Permission {
 			ps = append(ps, Permission{Action: a, Resource: Resource{Type: r, OrgID: &orgID}})
 		}
 	}
+	ps = append(ps, Permission{Action: DeleteAction, Resource: Resource{Type: BucketsResourceType, OrgID: &orgID}})
 	return ps
 }
 
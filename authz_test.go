@@ -285,6 +285,27 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid bucket delete permission",
+			fields: fields{
+				Action: platform.DeleteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "invalid delete permission of a resource other than buckets",
+			fields: fields{
+				Action: platform.DeleteAction,
+				Resource: platform.Resource{
+					Type:  platform.DashboardsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid permission without an action",
 			fields: fields{
@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -40,9 +41,96 @@ type Bucket struct {
 	Description         string        `json:"description"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	// ShardGroupDuration is the duration of the windows of time the data of
+	// the bucket is reported and optimized in. The TSM files hold the data
+	// of all buckets ordered by series key, not one set of files per shard
+	// group, so the windows are computed when the files are read. Changing
+	// the duration regroups existing data without rewriting it, and there
+	// is no re-sharding to run.
+	ShardGroupDuration time.Duration `json:"shardGroupDuration,omitempty"`
+	SchemaType         SchemaType    `json:"schemaType,omitempty"`
+	// FieldTypeConflictPolicy determines how written values that conflict
+	// with the type of their field are handled.
+	FieldTypeConflictPolicy FieldTypeConflictPolicy `json:"fieldTypeConflictPolicy,omitempty"`
+	// RetentionRules override the retention period of the bucket for the
+	// series they match. The first matching rule applies.
+	RetentionRules []BucketRetentionRule `json:"retentionRules,omitempty"`
 	CRUDLog
 }
 
+// BucketRetentionRule keeps the series of a bucket matching its measurement
+// and tags for its own retention period instead of the one of the bucket.
+// Patterns match literally except for *, which matches any characters, so
+// that a rule for debug_* with a period of 3 days and a bucket retention
+// period of 90 days expires debug data after 3 days and the rest after 90.
+type BucketRetentionRule struct {
+	// Measurement is the pattern of the measurements matched by the rule.
+	Measurement string `json:"measurement,omitempty"`
+	// Tags are the patterns of the tag values matched by the rule. _field
+	// matches the field keys.
+	Tags []Tag `json:"tags,omitempty"`
+	// RetentionPeriod is how long the matched series are kept. Zero keeps
+	// them forever.
+	RetentionPeriod time.Duration `json:"retentionPeriod"`
+}
+
+// Valid returns an error if the rule is invalid.
+func (r BucketRetentionRule) Valid() error {
+	if r.Measurement == "" && len(r.Tags) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "retention rule requires a measurement or tags",
+		}
+	}
+	for _, t := range r.Tags {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+		if t.Key == "_measurement" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "retention rule matches measurements with its measurement rather than a tag",
+			}
+		}
+	}
+	if r.RetentionPeriod < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "retention rule period must not be negative",
+		}
+	}
+	return nil
+}
+
+// FieldTypeConflictPolicy determines how values written with a different type
+// than the type of their field are handled.
+type FieldTypeConflictPolicy string
+
+const (
+	// FieldTypeConflictReject fails the write of conflicting values. It is
+	// the default policy.
+	FieldTypeConflictReject FieldTypeConflictPolicy = "reject"
+	// FieldTypeConflictCoerce converts integer values written to float fields
+	// into floats and drops the other conflicting values.
+	FieldTypeConflictCoerce FieldTypeConflictPolicy = "coerce"
+	// FieldTypeConflictDrop drops conflicting values and writes the rest of
+	// the batch.
+	FieldTypeConflictDrop FieldTypeConflictPolicy = "drop"
+)
+
+// Valid returns an error if the policy is unknown. The empty policy is the
+// default policy.
+func (p FieldTypeConflictPolicy) Valid() error {
+	switch p {
+	case "", FieldTypeConflictReject, FieldTypeConflictCoerce, FieldTypeConflictDrop:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid field type conflict policy %q; supported policies are reject, coerce and drop", p),
+	}
+}
+
 // BucketType differentiates system buckets from user buckets.
 type BucketType int
 
@@ -102,6 +190,72 @@ type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	Description     *string        `json:"description,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	// ShardGroupDuration changes the duration of the shard groups of the
+	// bucket. Shard groups are windows of time over the storage shared by
+	// all buckets, so existing data is regrouped without being rewritten.
+	ShardGroupDuration *time.Duration `json:"shardGroupDuration,omitempty"`
+
+	FieldTypeConflictPolicy *FieldTypeConflictPolicy `json:"fieldTypeConflictPolicy,omitempty"`
+
+	// RetentionRules replaces the retention rules of the bucket.
+	RetentionRules *[]BucketRetentionRule `json:"retentionRules,omitempty"`
+}
+
+// ReducesRetention returns true if the update may expire data of the bucket
+// earlier than it would be. Retention rules are compared by their patterns
+// only, so replaced rules that are not kept with a period at least as long,
+// and removed rules keeping data longer than the bucket, count as reducing.
+func (u BucketUpdate) ReducesRetention(b *Bucket) bool {
+	period := b.RetentionPeriod
+	if u.RetentionPeriod != nil {
+		if !keepsAsLong(*u.RetentionPeriod, b.RetentionPeriod) {
+			return true
+		}
+		period = *u.RetentionPeriod
+	}
+	if u.RetentionRules == nil {
+		return false
+	}
+
+	rules := *u.RetentionRules
+	for _, r := range rules {
+		old, ok := findRetentionRule(b.RetentionRules, r)
+		if !ok || !keepsAsLong(r.RetentionPeriod, old.RetentionPeriod) {
+			return true
+		}
+	}
+	for _, old := range b.RetentionRules {
+		if _, ok := findRetentionRule(rules, old); !ok && !keepsAsLong(period, old.RetentionPeriod) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepsAsLong returns true if data kept for the period a is kept at least as
+// long as data kept for the period b. Zero periods keep data forever.
+func keepsAsLong(a, b time.Duration) bool {
+	return a == 0 || (b != 0 && a >= b)
+}
+
+// findRetentionRule returns the rule of the rules with the patterns of r.
+func findRetentionRule(rules []BucketRetentionRule, r BucketRetentionRule) (BucketRetentionRule, bool) {
+	for _, rule := range rules {
+		if rule.Measurement != r.Measurement || len(rule.Tags) != len(r.Tags) {
+			continue
+		}
+		same := true
+		for i, t := range rule.Tags {
+			if t != r.Tags[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return rule, true
+		}
+	}
+	return BucketRetentionRule{}, false
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
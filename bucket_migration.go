@@ -0,0 +1,152 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for bucket migration errors.
+const (
+	OpCreateBucketMigration   = "CreateBucketMigration"
+	OpFindBucketMigrationByID = "FindBucketMigrationByID"
+	OpFindBucketMigrations    = "FindBucketMigrations"
+	OpCancelBucketMigration   = "CancelBucketMigration"
+)
+
+var (
+	// ErrBucketMigrationNotFound is used when the bucket migration is not found.
+	ErrBucketMigrationNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "bucket migration not found",
+	}
+
+	// ErrBucketMigrationFinished is used when a migration that has finished
+	// is canceled.
+	ErrBucketMigrationFinished = &Error{
+		Code: EConflict,
+		Msg:  "bucket migration has finished",
+	}
+)
+
+// Bucket migration statuses.
+const (
+	BucketMigrationRunning   = "running"
+	BucketMigrationCompleted = "completed"
+	BucketMigrationCanceled  = "canceled"
+	BucketMigrationFailed    = "failed"
+)
+
+// BucketMigrationService copies the data of a bucket to another bucket on
+// the server, e.g. to move data into a bucket with a longer retention period
+// or to downsample it, without reading the data through a client.
+type BucketMigrationService interface {
+	// CreateBucketMigration starts the migration and sets m.ID with the new
+	// identifier.
+	CreateBucketMigration(ctx context.Context, m *BucketMigration) error
+
+	// FindBucketMigrationByID returns the progress of a single migration.
+	FindBucketMigrationByID(ctx context.Context, id ID) (*BucketMigration, error)
+
+	// FindBucketMigrations returns the migrations that match the filter.
+	FindBucketMigrations(ctx context.Context, filter BucketMigrationFilter) ([]*BucketMigration, error)
+
+	// CancelBucketMigration stops a running migration. The data already
+	// written to the destination bucket is kept.
+	CancelBucketMigration(ctx context.Context, id ID) error
+}
+
+// BucketMigration copies the points of a bucket in the time range
+// [Start, Stop) to a destination bucket of the same organization. When Every
+// is set, the series are aggregated by windows of Every with Aggregate, and
+// each window is written as a single point at the start of the window.
+type BucketMigration struct {
+	ID                  ID        `json:"id,omitempty"`
+	OrgID               ID        `json:"orgID"`
+	SourceBucketID      ID        `json:"sourceBucketID"`
+	DestinationBucketID ID        `json:"destinationBucketID"`
+	Start               time.Time `json:"start"`
+	Stop                time.Time `json:"stop"`
+	// Measurement restricts the migration to a measurement when set.
+	Measurement string `json:"measurement,omitempty"`
+
+	Every     time.Duration             `json:"every,omitempty"`
+	Aggregate MaterializedViewAggregate `json:"aggregate,omitempty"`
+
+	// MaxPointsPerSecond limits the rate of the points written to the
+	// destination bucket. Zero is unlimited.
+	MaxPointsPerSecond int `json:"maxPointsPerSecond,omitempty"`
+
+	Status string `json:"status"`
+	// MigratedUntil is the time below which the data of the range has been
+	// migrated.
+	MigratedUntil time.Time `json:"migratedUntil"`
+	// PointsWritten is the number of points written to the destination bucket.
+	PointsWritten int64 `json:"pointsWritten"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Valid returns an error if the definition of the migration is invalid.
+func (m *BucketMigration) Valid() error {
+	switch {
+	case !m.OrgID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration requires an organization",
+		}
+	case !m.SourceBucketID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration requires a source bucket",
+		}
+	case !m.DestinationBucketID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration requires a destination bucket",
+		}
+	case m.SourceBucketID == m.DestinationBucketID:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration destination bucket must differ from its source bucket",
+		}
+	case !m.Start.Before(m.Stop):
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration start must be before stop",
+		}
+	case m.Every < 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration window must not be negative",
+		}
+	case m.Every == 0 && m.Aggregate != "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration aggregate requires a window",
+		}
+	case m.MaxPointsPerSecond < 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket migration max points per second must not be negative",
+		}
+	}
+	if m.Every > 0 {
+		return m.Aggregate.Valid()
+	}
+	return nil
+}
+
+// Finished reports whether the migration has completed, failed or been canceled.
+func (m *BucketMigration) Finished() bool {
+	return m.FinishedAt != nil
+}
+
+// BucketMigrationFilter represents a set of filters that restrict the
+// returned bucket migrations.
+type BucketMigrationFilter struct {
+	OrgID          *ID
+	SourceBucketID *ID
+}
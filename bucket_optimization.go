@@ -0,0 +1,79 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for bucket optimization errors.
+const (
+	OpOptimizeBucket           = "OptimizeBucket"
+	OpFindBucketOptimization   = "FindBucketOptimization"
+	OpCancelBucketOptimization = "CancelBucketOptimization"
+)
+
+var (
+	// ErrBucketOptimizationNotFound is used when the bucket has no optimization.
+	ErrBucketOptimizationNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "bucket optimization not found",
+	}
+
+	// ErrBucketOptimizationInProgress is used when an optimization is
+	// requested for a bucket that has an optimization scheduled or running.
+	ErrBucketOptimizationInProgress = &Error{
+		Code: EConflict,
+		Msg:  "bucket has an optimization in progress",
+	}
+)
+
+// Bucket optimization statuses.
+const (
+	BucketOptimizationScheduled = "scheduled"
+	BucketOptimizationRunning   = "running"
+	BucketOptimizationCompleted = "completed"
+	BucketOptimizationCanceled  = "canceled"
+	BucketOptimizationFailed    = "failed"
+)
+
+// BucketOptimizationService fully compacts the TSM files and the index
+// holding the data of a bucket, e.g. after a large backfill left many
+// level 1 files.
+type BucketOptimizationService interface {
+	// OptimizeBucket schedules an optimization of the bucket that runs in
+	// the maintenance window [windowStart, windowStop). A bucket has at most
+	// one optimization in progress.
+	OptimizeBucket(ctx context.Context, bucketID ID, windowStart, windowStop time.Time) (*BucketOptimization, error)
+
+	// FindBucketOptimization returns the progress of the latest optimization
+	// of the bucket.
+	FindBucketOptimization(ctx context.Context, bucketID ID) (*BucketOptimization, error)
+
+	// CancelBucketOptimization stops the optimization in progress of the
+	// bucket. A compaction that is already running is not interrupted.
+	CancelBucketOptimization(ctx context.Context, bucketID ID) error
+}
+
+// BucketOptimization is the progress of the optimization of the shard groups
+// of a bucket.
+type BucketOptimization struct {
+	BucketID    ID        `json:"bucketID"`
+	OrgID       ID        `json:"orgID"`
+	WindowStart time.Time `json:"windowStart"`
+	WindowStop  time.Time `json:"windowStop"`
+	Status      string    `json:"status"`
+
+	// ShardGroups is the number of shard groups of the bucket holding data
+	// when the optimization started.
+	ShardGroups int `json:"shardGroups"`
+	// OptimizedShardGroups is the number of those shard groups whose blocks
+	// are compacted into a single generation of TSM files.
+	OptimizedShardGroups int `json:"optimizedShardGroups"`
+	// IndexOptimized is true once the index and the series file are compacted.
+	IndexOptimized bool `json:"indexOptimized"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
@@ -0,0 +1,55 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OpSampleBucket is the op of bucket sampling errors.
+const OpSampleBucket = "SampleBucket"
+
+// Sizes of bucket samples.
+const (
+	// DefaultBucketSampleSize is the number of rows of samples that do not
+	// set one.
+	DefaultBucketSampleSize = 100
+	// MaxBucketSampleSize is the maximum number of rows of a sample.
+	MaxBucketSampleSize = 1000
+)
+
+// BucketSampleService samples the data of buckets, so that users can preview
+// its shape without writing queries.
+type BucketSampleService interface {
+	// SampleBucket returns the latest rows of the bucket, the latest first.
+	SampleBucket(ctx context.Context, bucketID ID, opts BucketSampleOptions) ([]*BucketSampleRow, error)
+}
+
+// BucketSampleOptions are the options of a sample of a bucket.
+type BucketSampleOptions struct {
+	// Measurement restricts the sample to the rows of a measurement.
+	Measurement *string
+	// N is the number of rows of the sample, DefaultBucketSampleSize if 0.
+	N int
+}
+
+// Valid returns an error if the options are invalid.
+func (o BucketSampleOptions) Valid() error {
+	if o.N < 0 || o.N > MaxBucketSampleSize {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpSampleBucket,
+			Msg:  fmt.Sprintf("sample size must be between 1 and %d", MaxBucketSampleSize),
+		}
+	}
+	return nil
+}
+
+// BucketSampleRow is a row of a sample of a bucket: the values of the fields
+// of a series at a time.
+type BucketSampleRow struct {
+	Time        time.Time              `json:"time"`
+	Measurement string                 `json:"measurement"`
+	Tags        map[string]string      `json:"tags"`
+	Fields      map[string]interface{} `json:"fields"`
+}
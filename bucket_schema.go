@@ -0,0 +1,55 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for bucket schema errors.
+const (
+	OpFindMeasurements            = "FindMeasurements"
+	OpFindFields                  = "FindFields"
+	OpFindTagValues               = "FindTagValues"
+	OpFindMeasurementLastWrites   = "FindMeasurementLastWrites"
+	OpRecordMeasurementLastWrites = "RecordMeasurementLastWrites"
+)
+
+// BucketSchemaFilter restricts the schema of a bucket to the series of a
+// measurement.
+type BucketSchemaFilter struct {
+	Measurement *string
+}
+
+// BucketSchemaService reports the schema of the data of buckets, e.g. for the
+// data explorer to list what can be queried without running schema queries.
+// Names and values are sorted.
+type BucketSchemaService interface {
+	// FindMeasurements returns the measurements of the bucket.
+	FindMeasurements(ctx context.Context, bucketID ID) ([]string, error)
+
+	// FindFields returns the field keys of the bucket matching the filter.
+	FindFields(ctx context.Context, bucketID ID, filter BucketSchemaFilter) ([]string, error)
+
+	// FindTagValues returns the values of the tag key in the bucket matching
+	// the filter.
+	FindTagValues(ctx context.Context, bucketID ID, key string, filter BucketSchemaFilter) ([]string, error)
+}
+
+// MeasurementLastWrites are the times of the last writes to the measurements
+// of a bucket, by measurement.
+type MeasurementLastWrites map[string]time.Time
+
+// MeasurementLastWriteService tracks when the measurements of buckets were
+// last written to, e.g. for users to find data sources that stopped writing
+// without querying the last point of every series.
+type MeasurementLastWriteService interface {
+	// FindMeasurementLastWrites returns the times of the last writes to the
+	// measurements of the bucket. Measurements that were not written to
+	// since writes are tracked are missing.
+	FindMeasurementLastWrites(ctx context.Context, bucketID ID) (MeasurementLastWrites, error)
+
+	// RecordMeasurementLastWrites records the times of writes to the
+	// measurements of buckets, by bucket. The latest time of a measurement
+	// is kept.
+	RecordMeasurementLastWrites(ctx context.Context, writes map[ID]MeasurementLastWrites) error
+}
@@ -0,0 +1,47 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// MeasurementStatistics summarize the data of a measurement of a bucket on
+// disk, for the query planner to estimate the cost of reading it.
+type MeasurementStatistics struct {
+	// Series is the number of series of the measurement with data on disk.
+	// Every field of a series key is a separate series.
+	Series int64 `json:"series"`
+	// Blocks is the number of TSM blocks of the series.
+	Blocks int64 `json:"blocks"`
+	// MinTime and MaxTime are the bounds of the data in nanoseconds.
+	MinTime int64 `json:"minTime"`
+	MaxTime int64 `json:"maxTime"`
+}
+
+// BlocksPerSeries returns the average number of blocks of a series, which is
+// the density of the data of the measurement.
+func (s MeasurementStatistics) BlocksPerSeries() float64 {
+	if s.Series == 0 {
+		return 0
+	}
+	return float64(s.Blocks) / float64(s.Series)
+}
+
+// BucketStatistics are the statistics of the measurements of a bucket at the
+// time they were collected.
+type BucketStatistics struct {
+	BucketID     ID                               `json:"bucketID"`
+	CollectedAt  time.Time                        `json:"collectedAt"`
+	Measurements map[string]MeasurementStatistics `json:"measurements"`
+}
+
+// BucketStatisticsStore persists the statistics of buckets collected for
+// query planning.
+type BucketStatisticsStore interface {
+	// FindBucketStatistics returns the statistics of the bucket, or an error
+	// with code ENotFound if none have been collected.
+	FindBucketStatistics(ctx context.Context, bucketID ID) (*BucketStatistics, error)
+
+	// PutBucketStatistics replaces the statistics of the bucket.
+	PutBucketStatistics(ctx context.Context, s *BucketStatistics) error
+}
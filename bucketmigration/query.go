@@ -0,0 +1,47 @@
+package bucketmigration
+
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// migrationQuery returns the query that copies the data of the migration in
+// [start, stop) to its destination bucket.
+func migrationQuery(m *influxdb.BucketMigration, start, stop time.Time) *ast.Package {
+	calls := []*ast.CallExpression{
+		flux.Call(flux.Identifier("range"), flux.Object(
+			flux.Property("start", &ast.DateTimeLiteral{Value: start.UTC()}),
+			flux.Property("stop", &ast.DateTimeLiteral{Value: stop.UTC()}),
+		)),
+	}
+	if m.Measurement != "" {
+		calls = append(calls, flux.Call(flux.Identifier("filter"), flux.Object(
+			// (r) => r._measurement == m
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.Equal(flux.Member("r", "_measurement"), flux.String(m.Measurement)))),
+		)))
+	}
+	if m.Every > 0 {
+		calls = append(calls, flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+			flux.Property("every", flux.TimeDuration(m.Every)),
+			flux.Property("fn", flux.Identifier(string(m.Aggregate))),
+			flux.Property("createEmpty", flux.Bool(false)),
+			flux.Property("timeSrc", flux.String("_start")),
+		)))
+	}
+	calls = append(calls, flux.Call(flux.Identifier("to"), flux.Object(
+		flux.Property("bucketID", flux.String(m.DestinationBucketID.String())),
+		flux.Property("orgID", flux.String(m.OrgID.String())),
+	)))
+
+	from := flux.Call(flux.Identifier("from"), flux.Object(flux.Property("bucketID", flux.String(m.SourceBucketID.String()))))
+	return &ast.Package{
+		Package: "main",
+		Files: []*ast.File{{
+			Body: []ast.Statement{flux.ExpressionStatement(flux.Pipe(from, calls...))},
+		}},
+	}
+}
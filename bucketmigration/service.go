@@ -0,0 +1,354 @@
+// Package bucketmigration copies the data of buckets to other buckets on the
+// server with Flux, so that data can be moved or downsampled without being
+// read through a client.
+package bucketmigration
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/snowflake"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// DefaultChunkDuration is the default time range of the data copied by a
+// single query of a migration.
+const DefaultChunkDuration = time.Hour
+
+var _ influxdb.BucketMigrationService = (*Service)(nil)
+
+// ServiceOption is a option you can use to modify the Service.
+type ServiceOption func(*Service)
+
+// WithChunkDuration sets the time range of the data copied by a single query
+// of a migration.
+func WithChunkDuration(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		if d > 0 {
+			s.chunk = d
+		}
+	}
+}
+
+// Service runs bucket migrations. The range of a migration is copied in
+// chunks by queries writing to the destination bucket with to(), so that the
+// progress of the migration is known and a rate of points can be kept. The
+// progress of migrations is kept in memory; a migration interrupted by a
+// restart can be resumed by migrating the range from its last progress.
+type Service struct {
+	logger       *zap.Logger
+	buckets      influxdb.BucketService
+	queryService query.QueryService
+	idGenerator  influxdb.IDGenerator
+
+	chunk time.Duration
+
+	mu         sync.Mutex
+	migrations map[influxdb.ID]*migration
+}
+
+type migration struct {
+	// progress is guarded by the mutex of the Service.
+	progress influxdb.BucketMigration
+	cancel   context.CancelFunc
+}
+
+// NewService returns a Service migrating the buckets of bs with the queries
+// of qs. The services must not be authorized, as the migrations run after
+// the requests that created them.
+func NewService(logger *zap.Logger, bs influxdb.BucketService, qs query.QueryService, opts ...ServiceOption) *Service {
+	s := &Service{
+		logger:       logger,
+		buckets:      bs,
+		queryService: qs,
+		idGenerator:  snowflake.NewIDGenerator(),
+		chunk:        DefaultChunkDuration,
+		migrations:   make(map[influxdb.ID]*migration),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateBucketMigration validates the migration and starts it.
+func (s *Service) CreateBucketMigration(ctx context.Context, m *influxdb.BucketMigration) error {
+	if err := m.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateBucketMigration,
+			Err: err,
+		}
+	}
+
+	for _, id := range []influxdb.ID{m.SourceBucketID, m.DestinationBucketID} {
+		b, err := s.buckets.FindBucketByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if b.OrgID != m.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpCreateBucketMigration,
+				Msg:  "bucket migration buckets must belong to its organization",
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	m.ID = s.idGenerator.ID()
+	m.Start, m.Stop = m.Start.UTC(), m.Stop.UTC()
+	m.Status = influxdb.BucketMigrationRunning
+	m.MigratedUntil = m.Start
+	m.PointsWritten = 0
+	m.CreatedAt = now
+	m.StartedAt = &now
+	m.FinishedAt = nil
+	m.Error = ""
+
+	mctx, cancel := context.WithCancel(context.Background())
+	mg := &migration{
+		progress: *m,
+		cancel:   cancel,
+	}
+
+	s.mu.Lock()
+	s.migrations[m.ID] = mg
+	s.mu.Unlock()
+
+	go s.run(mctx, mg, *m)
+	return nil
+}
+
+// FindBucketMigrationByID returns the progress of the migration.
+func (s *Service) FindBucketMigrationByID(ctx context.Context, id influxdb.ID) (*influxdb.BucketMigration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mg, ok := s.migrations[id]
+	if !ok {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindBucketMigrationByID,
+			Err: influxdb.ErrBucketMigrationNotFound,
+		}
+	}
+	progress := mg.progress
+	return &progress, nil
+}
+
+// FindBucketMigrations returns the progress of the migrations matching the
+// filter, latest first.
+func (s *Service) FindBucketMigrations(ctx context.Context, filter influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := make([]*influxdb.BucketMigration, 0, len(s.migrations))
+	for _, mg := range s.migrations {
+		if filter.OrgID != nil && mg.progress.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.SourceBucketID != nil && mg.progress.SourceBucketID != *filter.SourceBucketID {
+			continue
+		}
+		progress := mg.progress
+		ms = append(ms, &progress)
+	}
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].CreatedAt.After(ms[j].CreatedAt)
+	})
+	return ms, nil
+}
+
+// CancelBucketMigration stops the migration after its current chunk.
+func (s *Service) CancelBucketMigration(ctx context.Context, id influxdb.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mg, ok := s.migrations[id]
+	if !ok {
+		return &influxdb.Error{
+			Op:  influxdb.OpCancelBucketMigration,
+			Err: influxdb.ErrBucketMigrationNotFound,
+		}
+	}
+	if mg.progress.Finished() {
+		return &influxdb.Error{
+			Op:  influxdb.OpCancelBucketMigration,
+			Err: influxdb.ErrBucketMigrationFinished,
+		}
+	}
+	s.finish(mg, influxdb.BucketMigrationCanceled, "")
+	return nil
+}
+
+// run copies the chunks of the range of the migration in order, waiting
+// after each chunk as long as the rate of points requires.
+func (s *Service) run(ctx context.Context, mg *migration, m influxdb.BucketMigration) {
+	logger := s.logger.With(zap.Stringer("bucket_migration_id", m.ID))
+
+	var limiter *rate.Limiter
+	if m.MaxPointsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(m.MaxPointsPerSecond), m.MaxPointsPerSecond)
+	}
+
+	for _, c := range chunks(m.Start, m.Stop, s.chunkDuration(m.Every)) {
+		n, err := s.migrate(ctx, &m, c.start, c.stop)
+		if ctx.Err() != nil {
+			// canceled; the status is set by CancelBucketMigration.
+			return
+		}
+		if err != nil {
+			logger.Info("Failed to migrate bucket", zap.Error(err))
+			s.mu.Lock()
+			s.finish(mg, influxdb.BucketMigrationFailed, err.Error())
+			s.mu.Unlock()
+			return
+		}
+
+		s.mu.Lock()
+		mg.progress.MigratedUntil = c.stop
+		mg.progress.PointsWritten += n
+		s.mu.Unlock()
+
+		if limiter != nil {
+			if err := waitN(ctx, limiter, n); err != nil {
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.finish(mg, influxdb.BucketMigrationCompleted, "")
+	s.mu.Unlock()
+}
+
+// chunkDuration returns the duration of the chunks of a migration, which is
+// a multiple of its window so that no window spans two chunks.
+func (s *Service) chunkDuration(every time.Duration) time.Duration {
+	if every <= 0 {
+		return s.chunk
+	}
+	if n := (s.chunk + every - 1) / every; n > 1 {
+		return n * every
+	}
+	return every
+}
+
+type chunk struct {
+	start, stop time.Time
+}
+
+// chunks splits [start, stop) at the multiples of d since the Unix epoch,
+// which are the boundaries of the windows of Flux.
+func chunks(start, stop time.Time, d time.Duration) []chunk {
+	var cs []chunk
+	for t := start; t.Before(stop); {
+		next := query.WindowStart(t, d).Add(d)
+		if next.After(stop) {
+			next = stop
+		}
+		cs = append(cs, chunk{start: t, stop: next})
+		t = next
+	}
+	return cs
+}
+
+// migrate copies the data of [start, stop) and returns the number of points
+// written to the destination bucket.
+func (s *Service) migrate(ctx context.Context, m *influxdb.BucketMigration, start, stop time.Time) (int64, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// The migration runs after the request that created it, so it acts with
+	// a permission to read the source bucket and write the destination bucket.
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     m.ID,
+		OrgID:  m.OrgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &m.OrgID,
+					ID:    &m.SourceBucketID,
+				},
+			},
+			{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &m.OrgID,
+					ID:    &m.DestinationBucketID,
+				},
+			},
+		},
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	req := &query.Request{
+		Authorization:  auth,
+		OrganizationID: m.OrgID,
+		Compiler: lang.ASTCompiler{
+			AST: migrationQuery(m, start, stop),
+			Now: time.Now(),
+		},
+	}
+	it, err := s.queryService.Query(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Release()
+
+	// to() passes the rows it writes through, so they are the points written.
+	var n int64
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				n += int64(cr.Len())
+				return nil
+			})
+		})
+		if err != nil {
+			return n, err
+		}
+	}
+	it.Release()
+	return n, it.Err()
+}
+
+// waitN waits until n points may be written at the rate of the limiter.
+func waitN(ctx context.Context, l *rate.Limiter, n int64) error {
+	for n > 0 {
+		k := n
+		if b := int64(l.Burst()); k > b {
+			k = b
+		}
+		if err := l.WaitN(ctx, int(k)); err != nil {
+			return err
+		}
+		n -= k
+	}
+	return nil
+}
+
+// finish must be called with the mutex of the Service held. Only the first
+// status a migration finishes with is kept.
+func (s *Service) finish(mg *migration, status, msg string) {
+	if mg.progress.Finished() {
+		return
+	}
+	now := time.Now().UTC()
+	mg.progress.Status = status
+	mg.progress.FinishedAt = &now
+	mg.progress.Error = msg
+	mg.cancel()
+}
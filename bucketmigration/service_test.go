@@ -0,0 +1,227 @@
+package bucketmigration_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bucketmigration"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap"
+)
+
+func bucketService(orgID influxdb.ID) *mock.BucketService {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id == 99 {
+			return &influxdb.Bucket{ID: id, OrgID: orgID + 1}, nil
+		}
+		return &influxdb.Bucket{ID: id, OrgID: orgID}, nil
+	}
+	return bs
+}
+
+// waitFinished polls the migration until it has finished.
+func waitFinished(t *testing.T, s influxdb.BucketMigrationService, id influxdb.ID) *influxdb.BucketMigration {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m, err := s.FindBucketMigrationByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Finished() {
+			return m
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("migration did not finish: %+v", m)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestService_CreateBucketMigration(t *testing.T) {
+	orgID := influxdb.ID(1)
+	start := time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+	}
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID || req.Authorization == nil {
+				t.Errorf("expected query in org %s with an authorization, got %s %v", orgID, req.OrganizationID, req.Authorization)
+			}
+			mu.Lock()
+			queries = append(queries, ast.Format(req.Compiler.(lang.ASTCompiler).AST.Files[0]))
+			mu.Unlock()
+
+			r := executetest.NewResult([]*executetest.Table{{
+				ColMeta: cols,
+				Data: [][]interface{}{
+					{values.ConvertTime(start), 1.0},
+					{values.ConvertTime(start.Add(time.Minute)), 2.0},
+				},
+			}})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+	s := bucketmigration.NewService(zap.NewNop(), bucketService(orgID), qs)
+
+	m := &influxdb.BucketMigration{
+		OrgID:               orgID,
+		SourceBucketID:      2,
+		DestinationBucketID: 3,
+		Start:               start,
+		Stop:                start.Add(2 * time.Hour),
+		Measurement:         "cpu",
+		Every:               time.Hour,
+		Aggregate:           influxdb.MaterializedViewMean,
+	}
+	if err := s.CreateBucketMigration(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if !m.ID.Valid() || m.Status != influxdb.BucketMigrationRunning {
+		t.Fatalf("expected a running migration, got %+v", m)
+	}
+
+	m = waitFinished(t, s, m.ID)
+	if m.Status != influxdb.BucketMigrationCompleted || m.Error != "" {
+		t.Fatalf("expected migration to complete, got %+v", m)
+	}
+	// the range is split at the hours, so the partial hours are copied first and last.
+	if len(queries) != 3 || m.PointsWritten != 6 || !m.MigratedUntil.Equal(m.Stop) {
+		t.Fatalf("expected 3 chunks of 2 points, got %d queries and %+v", len(queries), m)
+	}
+	want := `from(bucketID: "0000000000000002")
+	|> range(start: 2020-01-01T00:30:00Z, stop: 2020-01-01T01:00:00Z)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+	|> aggregateWindow(
+		every: 3600000000000ns,
+		fn: mean,
+		createEmpty: false,
+		timeSrc: "_start",
+	)
+	|> to(bucketID: "0000000000000003", orgID: "0000000000000001")`
+	if queries[0] != want {
+		t.Fatalf("unexpected query of the first chunk:\n%s\nwant:\n%s", queries[0], want)
+	}
+
+	ms, err := s.FindBucketMigrations(context.Background(), influxdb.BucketMigrationFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ID != m.ID {
+		t.Fatalf("expected the migration of the org, got %+v", ms)
+	}
+
+	if err := s.CancelBucketMigration(context.Background(), m.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected finished migration to not be canceled, got %v", err)
+	}
+}
+
+func TestService_CreateBucketMigration_Invalid(t *testing.T) {
+	orgID := influxdb.ID(1)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := bucketmigration.NewService(zap.NewNop(), bucketService(orgID), &qmock.QueryService{})
+
+	for _, m := range []*influxdb.BucketMigration{
+		{OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 2, Start: start, Stop: start.Add(time.Hour)},
+		{OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 3, Start: start, Stop: start},
+		{OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 3, Start: start, Stop: start.Add(time.Hour), Every: time.Minute},
+		{OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 3, Start: start, Stop: start.Add(time.Hour), Aggregate: influxdb.MaterializedViewMean},
+		{OrgID: orgID, SourceBucketID: 2, DestinationBucketID: 99, Start: start, Stop: start.Add(time.Hour)},
+	} {
+		if err := s.CreateBucketMigration(context.Background(), m); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected %+v to be invalid, got %v", m, err)
+		}
+	}
+}
+
+func TestService_CancelBucketMigration(t *testing.T) {
+	orgID := influxdb.ID(1)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	started := make(chan struct{})
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	s := bucketmigration.NewService(zap.NewNop(), bucketService(orgID), qs)
+
+	m := &influxdb.BucketMigration{
+		OrgID:               orgID,
+		SourceBucketID:      2,
+		DestinationBucketID: 3,
+		Start:               start,
+		Stop:                start.Add(24 * time.Hour),
+	}
+	if err := s.CreateBucketMigration(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := s.CancelBucketMigration(context.Background(), m.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	m = waitFinished(t, s, m.ID)
+	if m.Status != influxdb.BucketMigrationCanceled || !m.MigratedUntil.Equal(start) {
+		t.Fatalf("expected migration to be canceled before its first chunk, got %+v", m)
+	}
+}
+
+func TestService_RateLimit(t *testing.T) {
+	orgID := influxdb.ID(1)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cols := []flux.ColMeta{{Label: "_value", Type: flux.TFloat}}
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			r := executetest.NewResult([]*executetest.Table{{
+				ColMeta: cols,
+				Data:    [][]interface{}{{1.0}, {2.0}, {3.0}, {4.0}, {5.0}},
+			}})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+	s := bucketmigration.NewService(zap.NewNop(), bucketService(orgID), qs)
+
+	// 3 chunks of 5 points at 5 points per second take 2s after the initial burst.
+	m := &influxdb.BucketMigration{
+		OrgID:               orgID,
+		SourceBucketID:      2,
+		DestinationBucketID: 3,
+		Start:               start,
+		Stop:                start.Add(3 * time.Hour),
+		MaxPointsPerSecond:  5,
+	}
+	now := time.Now()
+	if err := s.CreateBucketMigration(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	m = waitFinished(t, s, m.ID)
+	if m.Status != influxdb.BucketMigrationCompleted || m.PointsWritten != 15 {
+		t.Fatalf("expected migration to complete, got %+v", m)
+	}
+	if d := time.Since(now); d < 2*time.Second {
+		t.Fatalf("expected migration to be limited to 5 points per second, took %s", d)
+	}
+}
@@ -0,0 +1,36 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// CheckStatusEvent is a change of the level of a status of a check, written
+// to the monitoring bucket by the check.
+type CheckStatusEvent struct {
+	Time      time.Time `json:"time"`
+	OrgID     ID        `json:"orgID"`
+	CheckID   ID        `json:"checkID"`
+	CheckName string    `json:"checkName"`
+	Level     string    `json:"level"`
+	// PreviousLevel is empty when no previous status of the series of the
+	// status has been seen.
+	PreviousLevel string            `json:"previousLevel,omitempty"`
+	Message       string            `json:"message"`
+	Tags          map[string]string `json:"tags"`
+}
+
+// CheckStatusEventFilter selects the status events of the checks of an
+// organization, or of a single check.
+type CheckStatusEventFilter struct {
+	OrgID   ID
+	CheckID *ID
+}
+
+// CheckStatusStream streams the status events of checks as their statuses
+// are written.
+type CheckStatusStream interface {
+	// SubscribeCheckStatuses returns a channel receiving the events matching
+	// the filter. The channel is closed when the context is done.
+	SubscribeCheckStatuses(ctx context.Context, filter CheckStatusEventFilter) (<-chan *CheckStatusEvent, error)
+}
@@ -26,6 +26,7 @@ type Client struct {
 	SourcesStore            *SourcesStore
 	ServersStore            *ServersStore
 	LayoutsStore            *LayoutsStore
+	LayoutPacksStore        *LayoutPacksStore
 	DashboardsStore         *DashboardsStore
 	UsersStore              *UsersStore
 	OrganizationsStore      *OrganizationsStore
@@ -44,6 +45,10 @@ func NewClient() *Client {
 		client: c,
 		IDs:    &id.UUID{},
 	}
+	c.LayoutPacksStore = &LayoutPacksStore{
+		client: c,
+		IDs:    &id.UUID{},
+	}
 	c.DashboardsStore = &DashboardsStore{
 		client: c,
 		IDs:    &id.UUID{},
@@ -164,6 +169,10 @@ func (c *Client) initialize(ctx context.Context) error {
 		if _, err := tx.CreateBucketIfNotExists(OrganizationConfigBucket); err != nil {
 			return err
 		}
+		// Always create LayoutPacks bucket.
+		if _, err := tx.CreateBucketIfNotExists(LayoutPacksBucket); err != nil {
+			return err
+		}
 		return nil
 	}); err != nil {
 		return err
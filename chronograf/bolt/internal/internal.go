@@ -128,6 +128,11 @@ func UnmarshalServer(data []byte, s *chronograf.Server) error {
 
 // MarshalLayout encodes a layout to binary protobuf format.
 func MarshalLayout(l chronograf.Layout) ([]byte, error) {
+	return proto.Marshal(layoutToPB(l))
+}
+
+// layoutToPB converts a layout to its protobuf message.
+func layoutToPB(l chronograf.Layout) *Layout {
 	cells := make([]*Cell, len(l.Cells))
 	for i, c := range l.Cells {
 		queries := make([]*Query, len(c.Queries))
@@ -167,13 +172,13 @@ func MarshalLayout(l chronograf.Layout) ([]byte, error) {
 			Axes:    axes,
 		}
 	}
-	return proto.Marshal(&Layout{
+	return &Layout{
 		ID:          l.ID,
 		Measurement: l.Measurement,
 		Application: l.Application,
 		Autoflow:    l.Autoflow,
 		Cells:       cells,
-	})
+	}
 }
 
 // UnmarshalLayout decodes a layout from binary protobuf data.
@@ -182,7 +187,12 @@ func UnmarshalLayout(data []byte, l *chronograf.Layout) error {
 	if err := proto.Unmarshal(data, &pb); err != nil {
 		return err
 	}
+	layoutFromPB(&pb, l)
+	return nil
+}
 
+// layoutFromPB converts a protobuf message to a layout.
+func layoutFromPB(pb *Layout, l *chronograf.Layout) {
 	l.ID = pb.ID
 	l.Measurement = pb.Measurement
 	l.Application = pb.Application
@@ -227,6 +237,36 @@ func UnmarshalLayout(data []byte, l *chronograf.Layout) error {
 		}
 	}
 	l.Cells = cells
+}
+
+// MarshalLayoutPack encodes a layout pack to binary protobuf format.
+func MarshalLayoutPack(p chronograf.LayoutPack) ([]byte, error) {
+	layouts := make([]*Layout, len(p.Layouts))
+	for i, l := range p.Layouts {
+		layouts[i] = layoutToPB(l)
+	}
+	return proto.Marshal(&LayoutPack{
+		ID:           p.ID,
+		Name:         p.Name,
+		Organization: p.Organization,
+		Layouts:      layouts,
+	})
+}
+
+// UnmarshalLayoutPack decodes a layout pack from binary protobuf data.
+func UnmarshalLayoutPack(data []byte, p *chronograf.LayoutPack) error {
+	var pb LayoutPack
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	p.ID = pb.ID
+	p.Name = pb.Name
+	p.Organization = pb.Organization
+	p.Layouts = make([]chronograf.Layout, len(pb.Layouts))
+	for i, l := range pb.Layouts {
+		layoutFromPB(l, &p.Layouts[i])
+	}
 	return nil
 }
 
@@ -364,12 +404,20 @@ func MarshalDashboard(d chronograf.Dashboard) ([]byte, error) {
 		}
 		templates[i] = template
 	}
+	roles := make([]*DashboardRole, len(d.Roles))
+	for i, r := range d.Roles {
+		roles[i] = &DashboardRole{
+			UserID: r.UserID,
+			Role:   r.Role,
+		}
+	}
 	return proto.Marshal(&Dashboard{
 		ID:           int64(d.ID),
 		Cells:        cells,
 		Templates:    templates,
 		Name:         d.Name,
 		Organization: d.Organization,
+		Roles:        roles,
 	})
 }
 
@@ -550,11 +598,20 @@ func UnmarshalDashboard(data []byte, d *chronograf.Dashboard) error {
 		templates[i] = template
 	}
 
+	var roles []chronograf.DashboardRole
+	for _, r := range pb.Roles {
+		roles = append(roles, chronograf.DashboardRole{
+			UserID: r.UserID,
+			Role:   r.Role,
+		})
+	}
+
 	d.ID = chronograf.DashboardID(pb.ID)
 	d.Cells = cells
 	d.Templates = templates
 	d.Name = pb.Name
 	d.Organization = pb.Organization
+	d.Roles = roles
 	return nil
 }
 
@@ -166,6 +166,7 @@ type Dashboard struct {
 	Cells                []*DashboardCell `protobuf:"bytes,3,rep,name=cells" json:"cells,omitempty"`
 	Templates            []*Template      `protobuf:"bytes,4,rep,name=templates" json:"templates,omitempty"`
 	Organization         string           `protobuf:"bytes,5,opt,name=Organization,proto3" json:"Organization,omitempty"`
+	Roles                []*DashboardRole `protobuf:"bytes,6,rep,name=roles" json:"roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -230,6 +231,59 @@ func (m *Dashboard) GetOrganization() string {
 	return ""
 }
 
+func (m *Dashboard) GetRoles() []*DashboardRole {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+type DashboardRole struct {
+	UserID               uint64   `protobuf:"varint,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DashboardRole) Reset()         { *m = DashboardRole{} }
+func (m *DashboardRole) String() string { return proto.CompactTextString(m) }
+func (*DashboardRole) ProtoMessage()    {}
+func (*DashboardRole) Descriptor() ([]byte, []int) {
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{2}
+}
+func (m *DashboardRole) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DashboardRole.Unmarshal(m, b)
+}
+func (m *DashboardRole) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DashboardRole.Marshal(b, m, deterministic)
+}
+func (dst *DashboardRole) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DashboardRole.Merge(dst, src)
+}
+func (m *DashboardRole) XXX_Size() int {
+	return xxx_messageInfo_DashboardRole.Size(m)
+}
+func (m *DashboardRole) XXX_DiscardUnknown() {
+	xxx_messageInfo_DashboardRole.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DashboardRole proto.InternalMessageInfo
+
+func (m *DashboardRole) GetUserID() uint64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *DashboardRole) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type DashboardCell struct {
 	X                    int32             `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
 	Y                    int32             `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
@@ -255,7 +309,7 @@ func (m *DashboardCell) Reset()         { *m = DashboardCell{} }
 func (m *DashboardCell) String() string { return proto.CompactTextString(m) }
 func (*DashboardCell) ProtoMessage()    {}
 func (*DashboardCell) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{2}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{3}
 }
 func (m *DashboardCell) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DashboardCell.Unmarshal(m, b)
@@ -392,7 +446,7 @@ func (m *DecimalPlaces) Reset()         { *m = DecimalPlaces{} }
 func (m *DecimalPlaces) String() string { return proto.CompactTextString(m) }
 func (*DecimalPlaces) ProtoMessage()    {}
 func (*DecimalPlaces) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{3}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{4}
 }
 func (m *DecimalPlaces) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DecimalPlaces.Unmarshal(m, b)
@@ -440,7 +494,7 @@ func (m *TableOptions) Reset()         { *m = TableOptions{} }
 func (m *TableOptions) String() string { return proto.CompactTextString(m) }
 func (*TableOptions) ProtoMessage()    {}
 func (*TableOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{4}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{5}
 }
 func (m *TableOptions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TableOptions.Unmarshal(m, b)
@@ -501,7 +555,7 @@ func (m *RenamableField) Reset()         { *m = RenamableField{} }
 func (m *RenamableField) String() string { return proto.CompactTextString(m) }
 func (*RenamableField) ProtoMessage()    {}
 func (*RenamableField) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{5}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{6}
 }
 func (m *RenamableField) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenamableField.Unmarshal(m, b)
@@ -557,7 +611,7 @@ func (m *Color) Reset()         { *m = Color{} }
 func (m *Color) String() string { return proto.CompactTextString(m) }
 func (*Color) ProtoMessage()    {}
 func (*Color) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{6}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{7}
 }
 func (m *Color) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Color.Unmarshal(m, b)
@@ -624,7 +678,7 @@ func (m *Legend) Reset()         { *m = Legend{} }
 func (m *Legend) String() string { return proto.CompactTextString(m) }
 func (*Legend) ProtoMessage()    {}
 func (*Legend) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{7}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{8}
 }
 func (m *Legend) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Legend.Unmarshal(m, b)
@@ -675,7 +729,7 @@ func (m *Axis) Reset()         { *m = Axis{} }
 func (m *Axis) String() string { return proto.CompactTextString(m) }
 func (*Axis) ProtoMessage()    {}
 func (*Axis) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{8}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{9}
 }
 func (m *Axis) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Axis.Unmarshal(m, b)
@@ -760,7 +814,7 @@ func (m *Template) Reset()         { *m = Template{} }
 func (m *Template) String() string { return proto.CompactTextString(m) }
 func (*Template) ProtoMessage()    {}
 func (*Template) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{9}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{10}
 }
 func (m *Template) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Template.Unmarshal(m, b)
@@ -836,7 +890,7 @@ func (m *TemplateValue) Reset()         { *m = TemplateValue{} }
 func (m *TemplateValue) String() string { return proto.CompactTextString(m) }
 func (*TemplateValue) ProtoMessage()    {}
 func (*TemplateValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{10}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{11}
 }
 func (m *TemplateValue) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TemplateValue.Unmarshal(m, b)
@@ -900,7 +954,7 @@ func (m *TemplateQuery) Reset()         { *m = TemplateQuery{} }
 func (m *TemplateQuery) String() string { return proto.CompactTextString(m) }
 func (*TemplateQuery) ProtoMessage()    {}
 func (*TemplateQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{11}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{12}
 }
 func (m *TemplateQuery) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TemplateQuery.Unmarshal(m, b)
@@ -983,7 +1037,7 @@ func (m *Server) Reset()         { *m = Server{} }
 func (m *Server) String() string { return proto.CompactTextString(m) }
 func (*Server) ProtoMessage()    {}
 func (*Server) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{12}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{13}
 }
 func (m *Server) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Server.Unmarshal(m, b)
@@ -1095,7 +1149,7 @@ func (m *Layout) Reset()         { *m = Layout{} }
 func (m *Layout) String() string { return proto.CompactTextString(m) }
 func (*Layout) ProtoMessage()    {}
 func (*Layout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{13}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{14}
 }
 func (m *Layout) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Layout.Unmarshal(m, b)
@@ -1150,6 +1204,68 @@ func (m *Layout) GetAutoflow() bool {
 	return false
 }
 
+type LayoutPack struct {
+	ID                   string    `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string    `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Organization         string    `protobuf:"bytes,3,opt,name=Organization,proto3" json:"Organization,omitempty"`
+	Layouts              []*Layout `protobuf:"bytes,4,rep,name=Layouts" json:"Layouts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LayoutPack) Reset()         { *m = LayoutPack{} }
+func (m *LayoutPack) String() string { return proto.CompactTextString(m) }
+func (*LayoutPack) ProtoMessage()    {}
+func (*LayoutPack) Descriptor() ([]byte, []int) {
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{15}
+}
+func (m *LayoutPack) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LayoutPack.Unmarshal(m, b)
+}
+func (m *LayoutPack) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LayoutPack.Marshal(b, m, deterministic)
+}
+func (dst *LayoutPack) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LayoutPack.Merge(dst, src)
+}
+func (m *LayoutPack) XXX_Size() int {
+	return xxx_messageInfo_LayoutPack.Size(m)
+}
+func (m *LayoutPack) XXX_DiscardUnknown() {
+	xxx_messageInfo_LayoutPack.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LayoutPack proto.InternalMessageInfo
+
+func (m *LayoutPack) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *LayoutPack) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LayoutPack) GetOrganization() string {
+	if m != nil {
+		return m.Organization
+	}
+	return ""
+}
+
+func (m *LayoutPack) GetLayouts() []*Layout {
+	if m != nil {
+		return m.Layouts
+	}
+	return nil
+}
+
 type Cell struct {
 	X                    int32            `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
 	Y                    int32            `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
@@ -1171,7 +1287,7 @@ func (m *Cell) Reset()         { *m = Cell{} }
 func (m *Cell) String() string { return proto.CompactTextString(m) }
 func (*Cell) ProtoMessage()    {}
 func (*Cell) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{14}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{16}
 }
 func (m *Cell) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Cell.Unmarshal(m, b)
@@ -1287,7 +1403,7 @@ func (m *Query) Reset()         { *m = Query{} }
 func (m *Query) String() string { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()    {}
 func (*Query) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{15}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{17}
 }
 func (m *Query) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Query.Unmarshal(m, b)
@@ -1383,7 +1499,7 @@ func (m *TimeShift) Reset()         { *m = TimeShift{} }
 func (m *TimeShift) String() string { return proto.CompactTextString(m) }
 func (*TimeShift) ProtoMessage()    {}
 func (*TimeShift) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{16}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{18}
 }
 func (m *TimeShift) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TimeShift.Unmarshal(m, b)
@@ -1436,7 +1552,7 @@ func (m *Range) Reset()         { *m = Range{} }
 func (m *Range) String() string { return proto.CompactTextString(m) }
 func (*Range) ProtoMessage()    {}
 func (*Range) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{17}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{19}
 }
 func (m *Range) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Range.Unmarshal(m, b)
@@ -1484,7 +1600,7 @@ func (m *AlertRule) Reset()         { *m = AlertRule{} }
 func (m *AlertRule) String() string { return proto.CompactTextString(m) }
 func (*AlertRule) ProtoMessage()    {}
 func (*AlertRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{18}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{20}
 }
 func (m *AlertRule) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AlertRule.Unmarshal(m, b)
@@ -1548,7 +1664,7 @@ func (m *User) Reset()         { *m = User{} }
 func (m *User) String() string { return proto.CompactTextString(m) }
 func (*User) ProtoMessage()    {}
 func (*User) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{19}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{21}
 }
 func (m *User) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_User.Unmarshal(m, b)
@@ -1622,7 +1738,7 @@ func (m *Role) Reset()         { *m = Role{} }
 func (m *Role) String() string { return proto.CompactTextString(m) }
 func (*Role) ProtoMessage()    {}
 func (*Role) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{20}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{22}
 }
 func (m *Role) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Role.Unmarshal(m, b)
@@ -1671,7 +1787,7 @@ func (m *Mapping) Reset()         { *m = Mapping{} }
 func (m *Mapping) String() string { return proto.CompactTextString(m) }
 func (*Mapping) ProtoMessage()    {}
 func (*Mapping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{21}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{23}
 }
 func (m *Mapping) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Mapping.Unmarshal(m, b)
@@ -1739,7 +1855,7 @@ func (m *Organization) Reset()         { *m = Organization{} }
 func (m *Organization) String() string { return proto.CompactTextString(m) }
 func (*Organization) ProtoMessage()    {}
 func (*Organization) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{22}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{24}
 }
 func (m *Organization) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Organization.Unmarshal(m, b)
@@ -1791,7 +1907,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{23}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{25}
 }
 func (m *Config) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Config.Unmarshal(m, b)
@@ -1829,7 +1945,7 @@ func (m *AuthConfig) Reset()         { *m = AuthConfig{} }
 func (m *AuthConfig) String() string { return proto.CompactTextString(m) }
 func (*AuthConfig) ProtoMessage()    {}
 func (*AuthConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{24}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{26}
 }
 func (m *AuthConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthConfig.Unmarshal(m, b)
@@ -1868,7 +1984,7 @@ func (m *OrganizationConfig) Reset()         { *m = OrganizationConfig{} }
 func (m *OrganizationConfig) String() string { return proto.CompactTextString(m) }
 func (*OrganizationConfig) ProtoMessage()    {}
 func (*OrganizationConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{25}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{27}
 }
 func (m *OrganizationConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OrganizationConfig.Unmarshal(m, b)
@@ -1913,7 +2029,7 @@ func (m *LogViewerConfig) Reset()         { *m = LogViewerConfig{} }
 func (m *LogViewerConfig) String() string { return proto.CompactTextString(m) }
 func (*LogViewerConfig) ProtoMessage()    {}
 func (*LogViewerConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{26}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{28}
 }
 func (m *LogViewerConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogViewerConfig.Unmarshal(m, b)
@@ -1953,7 +2069,7 @@ func (m *LogViewerColumn) Reset()         { *m = LogViewerColumn{} }
 func (m *LogViewerColumn) String() string { return proto.CompactTextString(m) }
 func (*LogViewerColumn) ProtoMessage()    {}
 func (*LogViewerColumn) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{27}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{29}
 }
 func (m *LogViewerColumn) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogViewerColumn.Unmarshal(m, b)
@@ -2007,7 +2123,7 @@ func (m *ColumnEncoding) Reset()         { *m = ColumnEncoding{} }
 func (m *ColumnEncoding) String() string { return proto.CompactTextString(m) }
 func (*ColumnEncoding) ProtoMessage()    {}
 func (*ColumnEncoding) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{28}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{30}
 }
 func (m *ColumnEncoding) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ColumnEncoding.Unmarshal(m, b)
@@ -2060,7 +2176,7 @@ func (m *BuildInfo) Reset()         { *m = BuildInfo{} }
 func (m *BuildInfo) String() string { return proto.CompactTextString(m) }
 func (*BuildInfo) ProtoMessage()    {}
 func (*BuildInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{29}
+	return fileDescriptor_internal_6148f2d00a5dfb1b, []int{31}
 }
 func (m *BuildInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BuildInfo.Unmarshal(m, b)
@@ -2097,6 +2213,7 @@ func (m *BuildInfo) GetCommit() string {
 func init() {
 	proto.RegisterType((*Source)(nil), "internal.Source")
 	proto.RegisterType((*Dashboard)(nil), "internal.Dashboard")
+	proto.RegisterType((*DashboardRole)(nil), "internal.DashboardRole")
 	proto.RegisterType((*DashboardCell)(nil), "internal.DashboardCell")
 	proto.RegisterMapType((map[string]*Axis)(nil), "internal.DashboardCell.AxesEntry")
 	proto.RegisterType((*DecimalPlaces)(nil), "internal.DecimalPlaces")
@@ -2110,6 +2227,7 @@ func init() {
 	proto.RegisterType((*TemplateQuery)(nil), "internal.TemplateQuery")
 	proto.RegisterType((*Server)(nil), "internal.Server")
 	proto.RegisterType((*Layout)(nil), "internal.Layout")
+	proto.RegisterType((*LayoutPack)(nil), "internal.LayoutPack")
 	proto.RegisterType((*Cell)(nil), "internal.Cell")
 	proto.RegisterMapType((map[string]*Axis)(nil), "internal.Cell.AxesEntry")
 	proto.RegisterType((*Query)(nil), "internal.Query")
@@ -2132,118 +2250,123 @@ func init() {
 func init() { proto.RegisterFile("internal.proto", fileDescriptor_internal_6148f2d00a5dfb1b) }
 
 var fileDescriptor_internal_6148f2d00a5dfb1b = []byte{
-	// 1807 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x6f, 0xdc, 0xc8,
-	0x11, 0x06, 0x67, 0x86, 0xa3, 0x61, 0x8d, 0x24, 0x0b, 0x1d, 0x63, 0x97, 0xbb, 0x09, 0x82, 0x09,
-	0x91, 0x6c, 0x94, 0xc7, 0x3a, 0x0b, 0x19, 0x79, 0x60, 0xb1, 0xbb, 0x80, 0x1e, 0xb6, 0x23, 0x5b,
-	0xb6, 0xe5, 0x96, 0xac, 0x9c, 0x82, 0x45, 0x8b, 0xec, 0x99, 0x69, 0x98, 0x43, 0x32, 0x4d, 0x52,
-	0x12, 0x73, 0xce, 0xef, 0x08, 0x10, 0x20, 0xb9, 0x07, 0x41, 0x8e, 0x01, 0x72, 0xcf, 0x0f, 0xc8,
-	0x5f, 0xc9, 0x35, 0xa8, 0x7e, 0x90, 0x4d, 0x69, 0x6c, 0x38, 0x40, 0xb0, 0xb7, 0xfe, 0xaa, 0x6a,
-	0xaa, 0xab, 0xab, 0xab, 0xbe, 0x2e, 0x0e, 0x6c, 0x8b, 0xac, 0xe2, 0x32, 0x63, 0xe9, 0x83, 0x42,
-	0xe6, 0x55, 0x4e, 0x26, 0x16, 0x47, 0x7f, 0x18, 0xc2, 0xf8, 0x2c, 0xaf, 0x65, 0xcc, 0xc9, 0x36,
-	0x0c, 0x8e, 0x8f, 0x42, 0x6f, 0xe6, 0xed, 0x0e, 0xe9, 0xe0, 0xf8, 0x88, 0x10, 0x18, 0xbd, 0x60,
-	0x2b, 0x1e, 0x0e, 0x66, 0xde, 0x6e, 0x40, 0xd5, 0x1a, 0x65, 0xe7, 0x4d, 0xc1, 0xc3, 0xa1, 0x96,
-	0xe1, 0x9a, 0x7c, 0x0c, 0x93, 0xd7, 0x25, 0x7a, 0x5b, 0xf1, 0x70, 0xa4, 0xe4, 0x2d, 0x46, 0xdd,
-	0x29, 0x2b, 0xcb, 0xeb, 0x5c, 0x26, 0xa1, 0xaf, 0x75, 0x16, 0x93, 0x1d, 0x18, 0xbe, 0xa6, 0x27,
-	0xe1, 0x58, 0x89, 0x71, 0x49, 0x42, 0xd8, 0x38, 0xe2, 0x73, 0x56, 0xa7, 0x55, 0xb8, 0x31, 0xf3,
-	0x76, 0x27, 0xd4, 0x42, 0xf4, 0x73, 0xce, 0x53, 0xbe, 0x90, 0x6c, 0x1e, 0x4e, 0xb4, 0x1f, 0x8b,
-	0xc9, 0x03, 0x20, 0xc7, 0x59, 0xc9, 0xe3, 0x5a, 0xf2, 0xb3, 0x37, 0xa2, 0xb8, 0xe0, 0x52, 0xcc,
-	0x9b, 0x30, 0x50, 0x0e, 0xd6, 0x68, 0x70, 0x97, 0xe7, 0xbc, 0x62, 0xb8, 0x37, 0x28, 0x57, 0x16,
-	0x92, 0x08, 0x36, 0xcf, 0x96, 0x4c, 0xf2, 0xe4, 0x8c, 0xc7, 0x92, 0x57, 0xe1, 0x54, 0xa9, 0x7b,
-	0x32, 0xb4, 0x79, 0x29, 0x17, 0x2c, 0x13, 0xbf, 0x67, 0x95, 0xc8, 0xb3, 0x70, 0x53, 0xdb, 0xb8,
-	0x32, 0xcc, 0x12, 0xcd, 0x53, 0x1e, 0x6e, 0xe9, 0x2c, 0xe1, 0x9a, 0x7c, 0x07, 0x02, 0x73, 0x18,
-	0x7a, 0x1a, 0x6e, 0x2b, 0x45, 0x27, 0x88, 0xfe, 0xee, 0x41, 0x70, 0xc4, 0xca, 0xe5, 0x65, 0xce,
-	0x64, 0xf2, 0x5e, 0x37, 0xf1, 0x29, 0xf8, 0x31, 0x4f, 0xd3, 0x32, 0x1c, 0xce, 0x86, 0xbb, 0xd3,
-	0xbd, 0x0f, 0x1f, 0xb4, 0x57, 0xdc, 0xfa, 0x39, 0xe4, 0x69, 0x4a, 0xb5, 0x15, 0xf9, 0x0c, 0x82,
-	0x8a, 0xaf, 0x8a, 0x94, 0x55, 0xbc, 0x0c, 0x47, 0xea, 0x27, 0xa4, 0xfb, 0xc9, 0xb9, 0x51, 0xd1,
-	0xce, 0xe8, 0xce, 0x41, 0xfd, 0xbb, 0x07, 0x8d, 0xfe, 0x3d, 0x82, 0xad, 0xde, 0x76, 0x64, 0x13,
-	0xbc, 0x1b, 0x15, 0xb9, 0x4f, 0xbd, 0x1b, 0x44, 0x8d, 0x8a, 0xda, 0xa7, 0x5e, 0x83, 0xe8, 0x5a,
-	0x55, 0x8e, 0x4f, 0xbd, 0x6b, 0x44, 0x4b, 0x55, 0x2f, 0x3e, 0xf5, 0x96, 0xe4, 0x47, 0xb0, 0xf1,
-	0xbb, 0x9a, 0x4b, 0xc1, 0xcb, 0xd0, 0x57, 0xd1, 0xdd, 0xeb, 0xa2, 0x7b, 0x55, 0x73, 0xd9, 0x50,
-	0xab, 0xc7, 0x6c, 0xa8, 0x5a, 0xd3, 0x85, 0xa3, 0xd6, 0x28, 0xab, 0xb0, 0x2e, 0x37, 0xb4, 0x0c,
-	0xd7, 0x26, 0x8b, 0xba, 0x5a, 0x30, 0x8b, 0x3f, 0x87, 0x11, 0xbb, 0xe1, 0x65, 0x18, 0x28, 0xff,
-	0xdf, 0x7b, 0x4b, 0xc2, 0x1e, 0xec, 0xdf, 0xf0, 0xf2, 0x51, 0x56, 0xc9, 0x86, 0x2a, 0x73, 0xf2,
-	0x43, 0x18, 0xc7, 0x79, 0x9a, 0xcb, 0x32, 0x84, 0xdb, 0x81, 0x1d, 0xa2, 0x9c, 0x1a, 0x35, 0xd9,
-	0x85, 0x71, 0xca, 0x17, 0x3c, 0x4b, 0x54, 0xdd, 0x4c, 0xf7, 0x76, 0x3a, 0xc3, 0x13, 0x25, 0xa7,
-	0x46, 0x4f, 0x3e, 0x87, 0xcd, 0x8a, 0x5d, 0xa6, 0xfc, 0x65, 0x81, 0x59, 0x2c, 0x55, 0x0d, 0x4d,
-	0xf7, 0x3e, 0x70, 0xee, 0xc3, 0xd1, 0xd2, 0x9e, 0x2d, 0xf9, 0x02, 0x36, 0xe7, 0x82, 0xa7, 0x89,
-	0xfd, 0xed, 0x96, 0x0a, 0x2a, 0xec, 0x7e, 0x4b, 0x79, 0xc6, 0x56, 0xf8, 0x8b, 0xc7, 0x68, 0x46,
-	0x7b, 0xd6, 0xe4, 0xbb, 0x00, 0x95, 0x58, 0xf1, 0xc7, 0xb9, 0x5c, 0xb1, 0xca, 0x94, 0xa1, 0x23,
-	0x21, 0x5f, 0xc2, 0x56, 0xc2, 0x63, 0xb1, 0x62, 0xe9, 0x69, 0xca, 0x62, 0x5e, 0x86, 0xf7, 0x54,
-	0x68, 0x6e, 0x75, 0xb9, 0x6a, 0xda, 0xb7, 0xfe, 0xf8, 0x09, 0x04, 0x6d, 0xfa, 0xb0, 0xbf, 0xdf,
-	0xf0, 0x46, 0x15, 0x43, 0x40, 0x71, 0x49, 0xbe, 0x0f, 0xfe, 0x15, 0x4b, 0x6b, 0x5d, 0xc8, 0xd3,
-	0xbd, 0xed, 0xce, 0xeb, 0xfe, 0x8d, 0x28, 0xa9, 0x56, 0x7e, 0x3e, 0xf8, 0x95, 0x17, 0x3d, 0x81,
-	0xad, 0xde, 0x46, 0x18, 0xb8, 0x28, 0x1f, 0x65, 0xf3, 0x5c, 0xc6, 0x3c, 0x51, 0x3e, 0x27, 0xd4,
-	0x91, 0x90, 0x0f, 0x60, 0x9c, 0x88, 0x85, 0xa8, 0x4a, 0x53, 0x6e, 0x06, 0x45, 0xff, 0xf0, 0x60,
-	0xd3, 0xcd, 0x26, 0xf9, 0x31, 0xec, 0x5c, 0x71, 0x59, 0x89, 0x98, 0xa5, 0xe7, 0x62, 0xc5, 0x71,
-	0x63, 0xf5, 0x93, 0x09, 0xbd, 0x23, 0x27, 0x9f, 0xc1, 0xb8, 0xcc, 0x65, 0x75, 0xd0, 0xa8, 0xaa,
-	0x7d, 0x57, 0x96, 0x8d, 0x1d, 0xf2, 0xd4, 0xb5, 0x64, 0x45, 0x21, 0xb2, 0x85, 0xe5, 0x42, 0x8b,
-	0xc9, 0x27, 0xb0, 0x3d, 0x17, 0x37, 0x8f, 0x85, 0x2c, 0xab, 0xc3, 0x3c, 0xad, 0x57, 0x99, 0xaa,
-	0xe0, 0x09, 0xbd, 0x25, 0x7d, 0x3a, 0x9a, 0x78, 0x3b, 0x83, 0xa7, 0xa3, 0x89, 0xbf, 0x33, 0x8e,
-	0x0a, 0xd8, 0xee, 0xef, 0x84, 0x6d, 0x69, 0x83, 0x50, 0x9c, 0xa0, 0xd3, 0xdb, 0x93, 0x91, 0x19,
-	0x4c, 0x13, 0x51, 0x16, 0x29, 0x6b, 0x1c, 0xda, 0x70, 0x45, 0xc8, 0x81, 0x57, 0xa2, 0x14, 0x97,
-	0xa9, 0xa6, 0xf2, 0x09, 0xb5, 0x30, 0x5a, 0x80, 0xaf, 0xca, 0xda, 0x21, 0xa1, 0xc0, 0x92, 0x90,
-	0xa2, 0xfe, 0x81, 0x43, 0xfd, 0x3b, 0x30, 0xfc, 0x35, 0xbf, 0x31, 0xaf, 0x01, 0x2e, 0x5b, 0xaa,
-	0x1a, 0x39, 0x54, 0x75, 0x1f, 0xfc, 0x0b, 0x75, 0xed, 0x9a, 0x42, 0x34, 0x88, 0xbe, 0x82, 0xb1,
-	0x6e, 0x8b, 0xd6, 0xb3, 0xe7, 0x78, 0x9e, 0xc1, 0xf4, 0xa5, 0x14, 0x3c, 0xab, 0x34, 0xf9, 0x98,
-	0x23, 0x38, 0xa2, 0xe8, 0x6f, 0x1e, 0x8c, 0xd4, 0x2d, 0x45, 0xb0, 0x99, 0xf2, 0x05, 0x8b, 0x9b,
-	0x83, 0xbc, 0xce, 0x92, 0x32, 0xf4, 0x66, 0xc3, 0xdd, 0x21, 0xed, 0xc9, 0xb0, 0x3c, 0x2e, 0xb5,
-	0x76, 0x30, 0x1b, 0xee, 0x06, 0xd4, 0x20, 0x0c, 0x2d, 0x65, 0x97, 0x3c, 0x35, 0x47, 0xd0, 0x00,
-	0xad, 0x0b, 0xc9, 0xe7, 0xe2, 0xc6, 0x1c, 0xc3, 0x20, 0x94, 0x97, 0xf5, 0x1c, 0xe5, 0xfa, 0x24,
-	0x06, 0xe1, 0x01, 0x2e, 0x59, 0xd9, 0x32, 0x12, 0xae, 0xd1, 0x73, 0x19, 0xb3, 0xd4, 0x52, 0x92,
-	0x06, 0xd1, 0x3f, 0x3d, 0x7c, 0xc8, 0x34, 0xc5, 0xde, 0xc9, 0xf0, 0x47, 0x30, 0x41, 0xfa, 0xfd,
-	0xfa, 0x8a, 0x49, 0x73, 0xe0, 0x0d, 0xc4, 0x17, 0x4c, 0x92, 0x9f, 0xc1, 0x58, 0x35, 0xc7, 0x1a,
-	0xba, 0xb7, 0xee, 0x54, 0x56, 0xa9, 0x31, 0x6b, 0x09, 0x71, 0xe4, 0x10, 0x62, 0x7b, 0x58, 0xdf,
-	0x3d, 0xec, 0xa7, 0xe0, 0x23, 0xb3, 0x36, 0x2a, 0xfa, 0xb5, 0x9e, 0x35, 0xff, 0x6a, 0xab, 0x68,
-	0x01, 0x5b, 0xbd, 0x1d, 0xdb, 0x9d, 0xbc, 0xfe, 0x4e, 0x5d, 0xa3, 0x07, 0xa6, 0xb1, 0xb1, 0x39,
-	0x4a, 0x9e, 0xf2, 0xb8, 0xe2, 0x89, 0xa9, 0xba, 0x16, 0x5b, 0xb2, 0x18, 0xb5, 0x64, 0x11, 0xfd,
-	0xc9, 0xeb, 0x76, 0x52, 0x11, 0x60, 0xd1, 0xc6, 0xf9, 0x6a, 0xc5, 0xb2, 0xc4, 0x6c, 0x66, 0x21,
-	0x66, 0x32, 0xb9, 0x34, 0x9b, 0x0d, 0x92, 0x4b, 0xc4, 0xb2, 0x30, 0x77, 0x3a, 0x90, 0x05, 0x56,
-	0xd3, 0x8a, 0xb3, 0xb2, 0x96, 0x7c, 0xc5, 0xb3, 0xca, 0xec, 0xe2, 0x8a, 0xc8, 0x87, 0xb0, 0x51,
-	0xb1, 0xc5, 0xd7, 0x18, 0x83, 0xb9, 0xdb, 0x8a, 0x2d, 0x9e, 0xf1, 0x86, 0x7c, 0x1b, 0x02, 0xc5,
-	0xa0, 0x4a, 0xa5, 0x2f, 0x78, 0xa2, 0x04, 0xcf, 0x78, 0x13, 0xfd, 0x75, 0x00, 0xe3, 0x33, 0x2e,
-	0xaf, 0xb8, 0x7c, 0xaf, 0x37, 0xdb, 0x9d, 0x94, 0x86, 0xef, 0x98, 0x94, 0x46, 0xeb, 0x27, 0x25,
-	0xbf, 0x9b, 0x94, 0xee, 0x83, 0x7f, 0x26, 0xe3, 0xe3, 0x23, 0x15, 0xd1, 0x90, 0x6a, 0x80, 0xf5,
-	0xb9, 0x1f, 0x57, 0xe2, 0x8a, 0x9b, 0xf1, 0xc9, 0xa0, 0x3b, 0x4f, 0xf9, 0x64, 0xcd, 0xcc, 0xf2,
-	0xbf, 0x4e, 0x51, 0xb6, 0x69, 0xc1, 0x69, 0xda, 0x08, 0x36, 0x71, 0x94, 0x4a, 0x58, 0xc5, 0x9e,
-	0x9e, 0xbd, 0x7c, 0x61, 0xe7, 0x27, 0x57, 0x16, 0xfd, 0xd1, 0x83, 0xf1, 0x09, 0x6b, 0xf2, 0xba,
-	0xba, 0x53, 0xff, 0x33, 0x98, 0xee, 0x17, 0x45, 0x2a, 0xe2, 0x5e, 0xcf, 0x3b, 0x22, 0xb4, 0x78,
-	0xee, 0xdc, 0xa3, 0xce, 0xa1, 0x2b, 0xc2, 0x27, 0xe6, 0x50, 0x8d, 0x45, 0x7a, 0xc6, 0x71, 0x9e,
-	0x18, 0x3d, 0x0d, 0x29, 0x25, 0x26, 0x7b, 0xbf, 0xae, 0xf2, 0x79, 0x9a, 0x5f, 0xab, 0xac, 0x4e,
-	0x68, 0x8b, 0xa3, 0x7f, 0x0d, 0x60, 0xf4, 0x4d, 0x8d, 0x32, 0x9b, 0xe0, 0x09, 0x53, 0x54, 0x9e,
-	0x68, 0x07, 0x9b, 0x0d, 0x67, 0xb0, 0x09, 0x61, 0xa3, 0x91, 0x2c, 0x5b, 0xf0, 0x32, 0x9c, 0x28,
-	0x5e, 0xb3, 0x50, 0x69, 0x54, 0x07, 0xeb, 0x89, 0x26, 0xa0, 0x16, 0xb6, 0x1d, 0x09, 0x4e, 0x47,
-	0xfe, 0xd4, 0x0c, 0x3f, 0xd3, 0xdb, 0xe3, 0xc2, 0xba, 0x99, 0xe7, 0xff, 0xf7, 0x8e, 0xff, 0xc7,
-	0x03, 0xbf, 0x6d, 0xde, 0xc3, 0x7e, 0xf3, 0x1e, 0x76, 0xcd, 0x7b, 0x74, 0x60, 0x9b, 0xf7, 0xe8,
-	0x00, 0x31, 0x3d, 0xb5, 0xcd, 0x4b, 0x4f, 0xf1, 0xb2, 0x9e, 0xc8, 0xbc, 0x2e, 0x0e, 0x1a, 0x7d,
-	0xab, 0x01, 0x6d, 0x31, 0x56, 0xfc, 0x6f, 0x96, 0x5c, 0x9a, 0x54, 0x07, 0xd4, 0x20, 0xec, 0x8f,
-	0x13, 0x45, 0x75, 0x3a, 0xb9, 0x1a, 0x90, 0x1f, 0x80, 0x4f, 0x31, 0x79, 0x2a, 0xc3, 0xbd, 0x7b,
-	0x51, 0x62, 0xaa, 0xb5, 0xe8, 0x54, 0x7f, 0x12, 0x99, 0x46, 0xb1, 0x1f, 0x48, 0x3f, 0x81, 0xf1,
-	0xd9, 0x52, 0xcc, 0x2b, 0x3b, 0x42, 0x7e, 0xcb, 0xa1, 0x4a, 0xb1, 0xe2, 0x4a, 0x47, 0x8d, 0x49,
-	0xf4, 0x0a, 0x82, 0x56, 0xd8, 0x85, 0xe3, 0xb9, 0xe1, 0x10, 0x18, 0xbd, 0xce, 0x44, 0x65, 0x29,
-	0x02, 0xd7, 0x78, 0xd8, 0x57, 0x35, 0xcb, 0x2a, 0x51, 0x35, 0x96, 0x22, 0x2c, 0x8e, 0x1e, 0x9a,
-	0xf0, 0xd1, 0xdd, 0xeb, 0xa2, 0xe0, 0xd2, 0xd0, 0x8d, 0x06, 0x6a, 0x93, 0xfc, 0x9a, 0xeb, 0xb7,
-	0x63, 0x48, 0x35, 0x88, 0x7e, 0x0b, 0xc1, 0x7e, 0xca, 0x65, 0x45, 0xeb, 0x94, 0xaf, 0x7b, 0xd3,
-	0x55, 0xa3, 0x9a, 0x08, 0x70, 0xdd, 0x51, 0xcb, 0xf0, 0x16, 0xb5, 0x3c, 0x63, 0x05, 0x3b, 0x3e,
-	0x52, 0x75, 0x3e, 0xa4, 0x06, 0x45, 0x7f, 0xf6, 0x60, 0x84, 0x1c, 0xe6, 0xb8, 0x1e, 0xbd, 0x8b,
-	0xff, 0x4e, 0x65, 0x7e, 0x25, 0x12, 0x2e, 0xed, 0xe1, 0x2c, 0x56, 0x49, 0x8f, 0x97, 0xbc, 0x1d,
-	0x1d, 0x0c, 0xc2, 0x5a, 0xc3, 0xef, 0x27, 0xdb, 0x4b, 0x4e, 0xad, 0xa1, 0x98, 0x6a, 0x25, 0x8e,
-	0x87, 0x67, 0x75, 0xc1, 0xe5, 0x7e, 0xb2, 0x12, 0x76, 0xae, 0x72, 0x24, 0xd1, 0x57, 0xfa, 0x8b,
-	0xec, 0x0e, 0x13, 0x7a, 0xeb, 0xbf, 0xde, 0x6e, 0x47, 0x1e, 0xfd, 0xc5, 0x83, 0x8d, 0xe7, 0x66,
-	0x8e, 0x73, 0x4f, 0xe1, 0xbd, 0xf5, 0x14, 0x83, 0xde, 0x29, 0xf6, 0xe0, 0xbe, 0xb5, 0xe9, 0xed,
-	0xaf, 0xb3, 0xb0, 0x56, 0x67, 0x32, 0x3a, 0x6a, 0x2f, 0xeb, 0x7d, 0x3e, 0xc8, 0xce, 0xfb, 0x36,
-	0xeb, 0x2e, 0xfc, 0xce, 0xad, 0xcc, 0x60, 0x6a, 0x3f, 0x44, 0xf3, 0xd4, 0x3e, 0x4c, 0xae, 0x28,
-	0xda, 0x83, 0xf1, 0x61, 0x9e, 0xcd, 0xc5, 0x82, 0xec, 0xc2, 0x68, 0xbf, 0xae, 0x96, 0xca, 0xe3,
-	0x74, 0xef, 0xbe, 0xd3, 0xf8, 0x75, 0xb5, 0xd4, 0x36, 0x54, 0x59, 0x44, 0x5f, 0x00, 0x74, 0x32,
-	0x7c, 0x5d, 0xba, 0xdb, 0x78, 0xc1, 0xaf, 0xb1, 0x64, 0x4a, 0x33, 0xc6, 0xaf, 0xd1, 0x44, 0x35,
-	0x10, 0xf7, 0x1c, 0xc6, 0xcb, 0x27, 0xb0, 0xed, 0x4a, 0xdb, 0x93, 0xdd, 0x92, 0x92, 0x5f, 0x42,
-	0x70, 0x92, 0x2f, 0x2e, 0x04, 0xb7, 0xdd, 0x30, 0xdd, 0xfb, 0xc8, 0xf9, 0x18, 0xb3, 0x2a, 0x13,
-	0x6f, 0x67, 0x1b, 0x3d, 0x86, 0x7b, 0xb7, 0xb4, 0xe4, 0x21, 0xf2, 0x16, 0xce, 0xe5, 0x7a, 0xb0,
-	0x7c, 0x9b, 0x27, 0xb4, 0xa0, 0xd6, 0x32, 0x6a, 0x7a, 0x7e, 0x50, 0xd6, 0x66, 0xde, 0xbb, 0xd5,
-	0x0f, 0x79, 0x29, 0xda, 0xd7, 0xce, 0xa7, 0x2d, 0x26, 0xbf, 0x80, 0xe0, 0x51, 0x16, 0xe7, 0x89,
-	0xc8, 0x16, 0x76, 0xe8, 0x0b, 0x7b, 0x5f, 0x9e, 0xf5, 0x2a, 0xb3, 0x06, 0xb4, 0x33, 0x8d, 0x5e,
-	0xc0, 0x76, 0x5f, 0xb9, 0x76, 0xbc, 0x6e, 0x47, 0xf2, 0x81, 0x33, 0x92, 0xb7, 0x31, 0x0e, 0x9d,
-	0xca, 0xff, 0x12, 0x82, 0x83, 0x5a, 0xa4, 0xc9, 0x71, 0x36, 0xcf, 0x91, 0xc4, 0x2f, 0xb8, 0x2c,
-	0xbb, 0xce, 0xb1, 0x10, 0x0b, 0x1f, 0xf9, 0xbc, 0x65, 0x33, 0x83, 0x2e, 0xc7, 0xea, 0x0f, 0xa7,
-	0x87, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x58, 0xd6, 0x93, 0x3e, 0x82, 0x12, 0x00, 0x00,
+	// 1874 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x8e, 0xe4, 0x48,
+	0x11, 0x96, 0xab, 0xec, 0xaa, 0x72, 0x54, 0x75, 0x6f, 0x2b, 0x19, 0xcd, 0x7a, 0x17, 0x84, 0x0a,
+	0x0b, 0x96, 0x66, 0x61, 0x86, 0x55, 0x8f, 0xf8, 0xd1, 0xb2, 0xbb, 0x52, 0xff, 0xcc, 0x0c, 0x3d,
+	0xbf, 0x3d, 0xd9, 0x3d, 0xcd, 0x09, 0xad, 0xb2, 0xed, 0xac, 0xea, 0xd4, 0xb8, 0x6c, 0x93, 0xb6,
+	0xbb, 0xbb, 0x38, 0x71, 0xe0, 0x39, 0x90, 0x90, 0xe0, 0x8e, 0x38, 0x23, 0x71, 0xe7, 0x01, 0x78,
+	0x01, 0x1e, 0x82, 0x2b, 0x8a, 0xfc, 0x71, 0xa5, 0xab, 0x6b, 0x46, 0x83, 0x84, 0xb8, 0xe5, 0x17,
+	0x11, 0x15, 0x19, 0x11, 0x19, 0xf9, 0x65, 0xb8, 0x60, 0x5b, 0xe4, 0x35, 0x97, 0x39, 0xcb, 0xee,
+	0x97, 0xb2, 0xa8, 0x0b, 0x32, 0xb2, 0x38, 0xfe, 0x7d, 0x1f, 0x06, 0xa7, 0x45, 0x23, 0x13, 0x4e,
+	0xb6, 0xa1, 0x77, 0x7c, 0x14, 0x79, 0x53, 0x6f, 0xb7, 0x4f, 0x7b, 0xc7, 0x47, 0x84, 0x80, 0xff,
+	0x82, 0x2d, 0x78, 0xd4, 0x9b, 0x7a, 0xbb, 0x21, 0x55, 0x6b, 0x94, 0x9d, 0x2d, 0x4b, 0x1e, 0xf5,
+	0xb5, 0x0c, 0xd7, 0xe4, 0x63, 0x18, 0xbd, 0xae, 0xd0, 0xdb, 0x82, 0x47, 0xbe, 0x92, 0xb7, 0x18,
+	0x75, 0x27, 0xac, 0xaa, 0xae, 0x0b, 0x99, 0x46, 0x81, 0xd6, 0x59, 0x4c, 0x76, 0xa0, 0xff, 0x9a,
+	0x3e, 0x8b, 0x06, 0x4a, 0x8c, 0x4b, 0x12, 0xc1, 0xf0, 0x88, 0xcf, 0x58, 0x93, 0xd5, 0xd1, 0x70,
+	0xea, 0xed, 0x8e, 0xa8, 0x85, 0xe8, 0xe7, 0x8c, 0x67, 0x7c, 0x2e, 0xd9, 0x2c, 0x1a, 0x69, 0x3f,
+	0x16, 0x93, 0xfb, 0x40, 0x8e, 0xf3, 0x8a, 0x27, 0x8d, 0xe4, 0xa7, 0x6f, 0x44, 0x79, 0xce, 0xa5,
+	0x98, 0x2d, 0xa3, 0x50, 0x39, 0xd8, 0xa0, 0xc1, 0x5d, 0x9e, 0xf3, 0x9a, 0xe1, 0xde, 0xa0, 0x5c,
+	0x59, 0x48, 0x62, 0x98, 0x9c, 0x5e, 0x32, 0xc9, 0xd3, 0x53, 0x9e, 0x48, 0x5e, 0x47, 0x63, 0xa5,
+	0xee, 0xc8, 0xd0, 0xe6, 0xa5, 0x9c, 0xb3, 0x5c, 0xfc, 0x96, 0xd5, 0xa2, 0xc8, 0xa3, 0x89, 0xb6,
+	0x71, 0x65, 0x58, 0x25, 0x5a, 0x64, 0x3c, 0xda, 0xd2, 0x55, 0xc2, 0x35, 0xf9, 0x16, 0x84, 0x26,
+	0x19, 0x7a, 0x12, 0x6d, 0x2b, 0xc5, 0x4a, 0x10, 0xff, 0xcb, 0x83, 0xf0, 0x88, 0x55, 0x97, 0x17,
+	0x05, 0x93, 0xe9, 0x7b, 0x9d, 0xc4, 0x3d, 0x08, 0x12, 0x9e, 0x65, 0x55, 0xd4, 0x9f, 0xf6, 0x77,
+	0xc7, 0x7b, 0x1f, 0xde, 0x6f, 0x8f, 0xb8, 0xf5, 0x73, 0xc8, 0xb3, 0x8c, 0x6a, 0x2b, 0xf2, 0x19,
+	0x84, 0x35, 0x5f, 0x94, 0x19, 0xab, 0x79, 0x15, 0xf9, 0xea, 0x27, 0x64, 0xf5, 0x93, 0x33, 0xa3,
+	0xa2, 0x2b, 0xa3, 0x5b, 0x89, 0x06, 0x1b, 0x12, 0xbd, 0x07, 0x81, 0x2c, 0x32, 0x5e, 0x45, 0x83,
+	0xb7, 0x06, 0x81, 0xc9, 0x53, 0x6d, 0x15, 0xff, 0x02, 0xb6, 0x3a, 0x72, 0x72, 0x17, 0x06, 0x4d,
+	0xc5, 0xa5, 0x49, 0xd6, 0xa7, 0x06, 0x61, 0xc2, 0xf8, 0x0b, 0x9b, 0x30, 0xae, 0xe3, 0x7f, 0xfa,
+	0xb0, 0xd5, 0x49, 0x8d, 0x4c, 0xc0, 0xbb, 0x51, 0x3f, 0x0c, 0xa8, 0x77, 0x83, 0x68, 0xa9, 0x7e,
+	0x10, 0x50, 0x6f, 0x89, 0xe8, 0x5a, 0x75, 0x69, 0x40, 0xbd, 0x6b, 0x44, 0x97, 0xaa, 0x37, 0x03,
+	0xea, 0x5d, 0x92, 0x1f, 0xc0, 0xf0, 0x37, 0x0d, 0x97, 0x82, 0x57, 0x51, 0xa0, 0xe2, 0xfe, 0x60,
+	0x15, 0xf7, 0xab, 0x86, 0xcb, 0x25, 0xb5, 0x7a, 0x0c, 0x44, 0xf5, 0xb5, 0x6e, 0x52, 0xb5, 0x46,
+	0x59, 0x8d, 0x77, 0x60, 0xa8, 0x65, 0xb8, 0x36, 0x27, 0xa6, 0x3b, 0x13, 0x4f, 0xec, 0x27, 0xe0,
+	0xb3, 0x1b, 0x5e, 0x45, 0xa1, 0xf2, 0xff, 0x9d, 0xb7, 0x1c, 0xce, 0xfd, 0xfd, 0x1b, 0x5e, 0x3d,
+	0xcc, 0x6b, 0xb9, 0xa4, 0xca, 0x9c, 0x7c, 0x1f, 0x06, 0x49, 0x91, 0x15, 0xb2, 0x8a, 0x60, 0x3d,
+	0xb0, 0x43, 0x94, 0x53, 0xa3, 0x26, 0xbb, 0x30, 0xc8, 0xf8, 0x9c, 0xe7, 0xa9, 0xea, 0xd1, 0xf1,
+	0xde, 0xce, 0xca, 0xf0, 0x99, 0x92, 0x53, 0xa3, 0x27, 0x9f, 0xc3, 0xa4, 0x66, 0x17, 0x19, 0x7f,
+	0x59, 0xe2, 0x89, 0x55, 0xaa, 0x5f, 0xc7, 0x7b, 0x77, 0x9d, 0xb3, 0x77, 0xb4, 0xb4, 0x63, 0x4b,
+	0xbe, 0x80, 0xc9, 0x4c, 0xf0, 0x2c, 0xb5, 0xbf, 0xdd, 0x52, 0x41, 0x45, 0xab, 0xdf, 0x52, 0x9e,
+	0xb3, 0x05, 0xfe, 0xe2, 0x11, 0x9a, 0xd1, 0x8e, 0x35, 0xf9, 0x36, 0x40, 0x2d, 0x16, 0xfc, 0x51,
+	0x21, 0x17, 0xac, 0x36, 0x2d, 0xef, 0x48, 0xc8, 0x97, 0xb0, 0x95, 0xf2, 0x44, 0x2c, 0x58, 0x76,
+	0x92, 0xb1, 0x84, 0x57, 0xd1, 0x07, 0x53, 0x6f, 0xad, 0x89, 0x5c, 0x35, 0xed, 0x5a, 0x7f, 0xfc,
+	0x18, 0xc2, 0xb6, 0x7c, 0xc8, 0x25, 0x6f, 0xf8, 0x52, 0x35, 0x43, 0x48, 0x71, 0x49, 0xbe, 0x0b,
+	0xc1, 0x15, 0xcb, 0x1a, 0xdd, 0x43, 0xe3, 0xbd, 0xed, 0x95, 0xd7, 0xfd, 0x1b, 0x51, 0x51, 0xad,
+	0xfc, 0xbc, 0xf7, 0x73, 0x2f, 0x7e, 0x0c, 0x5b, 0x9d, 0x8d, 0x30, 0x70, 0x51, 0x3d, 0xcc, 0x67,
+	0x85, 0x4c, 0x78, 0xaa, 0x7c, 0x8e, 0xa8, 0x23, 0xc1, 0xae, 0x4d, 0xc5, 0x5c, 0xd4, 0x95, 0x69,
+	0x37, 0x83, 0xe2, 0xbf, 0x79, 0x30, 0x71, 0xab, 0x49, 0x3e, 0x85, 0x9d, 0x2b, 0x2e, 0x6b, 0x91,
+	0xb0, 0xec, 0x4c, 0x2c, 0x38, 0x6e, 0xac, 0x7e, 0x32, 0xa2, 0xb7, 0xe4, 0xe4, 0x33, 0x18, 0x54,
+	0x85, 0xac, 0x0f, 0x96, 0xaa, 0x6b, 0xdf, 0x55, 0x65, 0x63, 0x87, 0x9c, 0x78, 0x2d, 0x59, 0x59,
+	0x8a, 0x7c, 0x6e, 0x79, 0xd7, 0x62, 0xf2, 0x09, 0x6c, 0xcf, 0xc4, 0xcd, 0x23, 0x21, 0xab, 0xfa,
+	0xb0, 0xc8, 0x9a, 0x45, 0xae, 0x3a, 0x78, 0x44, 0xd7, 0xa4, 0x4f, 0xfc, 0x91, 0xb7, 0xd3, 0x7b,
+	0xe2, 0x8f, 0x82, 0x9d, 0x41, 0x5c, 0xc2, 0x76, 0x77, 0x27, 0xa4, 0x00, 0x1b, 0x84, 0xe2, 0x1f,
+	0x5d, 0xde, 0x8e, 0x8c, 0x4c, 0x61, 0x9c, 0x8a, 0xaa, 0xcc, 0xd8, 0xd2, 0xa1, 0x28, 0x57, 0x84,
+	0x7c, 0x7b, 0x25, 0x2a, 0x71, 0x91, 0xe9, 0x67, 0x63, 0x44, 0x2d, 0x8c, 0xe7, 0x10, 0xa8, 0xb6,
+	0x76, 0x08, 0x2f, 0xb4, 0x84, 0xa7, 0x9e, 0x99, 0x9e, 0xf3, 0xcc, 0xec, 0x40, 0xff, 0x97, 0xfc,
+	0xc6, 0xbc, 0x3c, 0xb8, 0x6c, 0x69, 0xd1, 0x77, 0x68, 0xf1, 0x0e, 0x04, 0xe7, 0xea, 0xd8, 0x35,
+	0x5d, 0x69, 0x10, 0x7f, 0x05, 0x03, 0x7d, 0x2d, 0x5a, 0xcf, 0x9e, 0xe3, 0x79, 0x0a, 0xe3, 0x97,
+	0x52, 0xf0, 0xbc, 0xd6, 0x44, 0x67, 0x52, 0x70, 0x44, 0xf1, 0x5f, 0x3d, 0xf0, 0xd5, 0x29, 0xc5,
+	0x30, 0xc9, 0xf8, 0x9c, 0x25, 0xcb, 0x83, 0xa2, 0xc9, 0xd3, 0x2a, 0xf2, 0xa6, 0xfd, 0xdd, 0x3e,
+	0xed, 0xc8, 0xb0, 0x3d, 0x2e, 0xb4, 0xb6, 0x37, 0xed, 0xef, 0x86, 0xd4, 0x20, 0x0c, 0x2d, 0x63,
+	0x17, 0x3c, 0x33, 0x29, 0x68, 0x80, 0xd6, 0xa5, 0xe4, 0x33, 0x71, 0x63, 0xd2, 0x30, 0x08, 0xe5,
+	0x55, 0x33, 0x43, 0xb9, 0xce, 0xc4, 0x20, 0x4c, 0xe0, 0x82, 0x55, 0x2d, 0x23, 0xe1, 0x1a, 0x3d,
+	0x57, 0x09, 0xcb, 0x2c, 0x25, 0x69, 0x10, 0xff, 0xdd, 0xc3, 0x47, 0x53, 0xd3, 0xf9, 0xad, 0x0a,
+	0x7f, 0x04, 0x23, 0xa4, 0xfa, 0xaf, 0xaf, 0x98, 0x34, 0x09, 0x0f, 0x11, 0x9f, 0x33, 0x49, 0x7e,
+	0x0c, 0x03, 0x75, 0x39, 0x36, 0x3c, 0x2d, 0xd6, 0x9d, 0xaa, 0x2a, 0x35, 0x66, 0x2d, 0x21, 0xfa,
+	0x0e, 0x21, 0xb6, 0xc9, 0x06, 0x6e, 0xb2, 0xf7, 0x20, 0x40, 0x66, 0x5d, 0xaa, 0xe8, 0x37, 0x7a,
+	0xd6, 0xfc, 0xab, 0xad, 0xe2, 0x39, 0x6c, 0x75, 0x76, 0x6c, 0x77, 0xf2, 0xba, 0x3b, 0xad, 0x2e,
+	0x7a, 0x68, 0x2e, 0x36, 0x5e, 0x8e, 0x8a, 0x67, 0x3c, 0xa9, 0x79, 0x6a, 0xba, 0xae, 0xc5, 0x96,
+	0x2c, 0xfc, 0x96, 0x2c, 0xe2, 0x3f, 0x7a, 0xb0, 0xd5, 0x89, 0x00, 0x9b, 0x36, 0x29, 0x16, 0x0b,
+	0x96, 0xa7, 0x66, 0x33, 0x0b, 0xb1, 0x92, 0xe9, 0x85, 0xd9, 0xac, 0x97, 0x5e, 0x20, 0x96, 0xa5,
+	0x39, 0xd3, 0x9e, 0x2c, 0xb1, 0x9b, 0x16, 0x9c, 0x55, 0x8d, 0xe4, 0x0b, 0x9e, 0xd7, 0x66, 0x17,
+	0x57, 0x44, 0x3e, 0x84, 0x61, 0xcd, 0xe6, 0x5f, 0x63, 0x0c, 0xe6, 0x6c, 0x6b, 0x36, 0x7f, 0xca,
+	0x97, 0xe4, 0x9b, 0x10, 0x2a, 0x06, 0x55, 0x2a, 0x7d, 0xc0, 0x23, 0x25, 0x78, 0xca, 0x97, 0xf1,
+	0x5f, 0x7a, 0x30, 0x38, 0xe5, 0xf2, 0x8a, 0xcb, 0xf7, 0x9a, 0x0f, 0xdc, 0xa9, 0xac, 0xff, 0x8e,
+	0xa9, 0xcc, 0xdf, 0x3c, 0x95, 0x05, 0xab, 0xa9, 0xec, 0x0e, 0x04, 0xa7, 0x32, 0x39, 0x3e, 0x52,
+	0x11, 0xf5, 0xa9, 0x06, 0xd8, 0x9f, 0xfb, 0x49, 0x2d, 0xae, 0xb8, 0x19, 0xd5, 0x0c, 0xba, 0x35,
+	0x36, 0x8c, 0x36, 0x8c, 0x0d, 0xff, 0xed, 0xc4, 0x66, 0x2f, 0x2d, 0x38, 0x97, 0x36, 0x86, 0x09,
+	0x8e, 0x6d, 0x29, 0xab, 0xd9, 0x93, 0xd3, 0x97, 0x2f, 0xec, 0xac, 0xe6, 0xca, 0xe2, 0x3f, 0x78,
+	0x30, 0x78, 0xc6, 0x96, 0x45, 0x53, 0xdf, 0xea, 0xff, 0x29, 0x8c, 0xf7, 0xcb, 0x32, 0x13, 0x49,
+	0xe7, 0xce, 0x3b, 0x22, 0xb4, 0x78, 0xee, 0x9c, 0xa3, 0xae, 0xa1, 0x2b, 0xc2, 0x27, 0xe6, 0x50,
+	0x8d, 0x60, 0x7a, 0x9e, 0x72, 0x9e, 0x18, 0x3d, 0x79, 0x29, 0x25, 0x16, 0x7b, 0xbf, 0xa9, 0x8b,
+	0x59, 0x56, 0x5c, 0xab, 0xaa, 0x8e, 0x68, 0x8b, 0xe3, 0xdf, 0x79, 0x00, 0x3a, 0xc0, 0x13, 0x96,
+	0xbc, 0xd9, 0x44, 0x83, 0xb7, 0xce, 0x75, 0xbd, 0xbe, 0xfd, 0x0d, 0xf5, 0xfd, 0x14, 0x86, 0xda,
+	0xab, 0x0d, 0xcd, 0x1d, 0x0f, 0x94, 0x82, 0x5a, 0x83, 0xf8, 0x1f, 0x3d, 0xf0, 0xff, 0x5f, 0xd3,
+	0xd4, 0x04, 0x3c, 0x61, 0xfa, 0xda, 0x13, 0xed, 0x6c, 0x35, 0x74, 0x66, 0xab, 0x08, 0x86, 0x4b,
+	0xc9, 0xf2, 0x39, 0xaf, 0xa2, 0x91, 0xa2, 0x56, 0x0b, 0x95, 0x46, 0x91, 0x88, 0x1e, 0xaa, 0x42,
+	0x6a, 0x61, 0x4b, 0x0a, 0xe0, 0x90, 0xc2, 0x8f, 0xcc, 0xfc, 0x35, 0x5e, 0x9f, 0x58, 0x36, 0x8d,
+	0x5d, 0xff, 0xbb, 0x51, 0xe2, 0xdf, 0x1e, 0x04, 0x2d, 0x7f, 0x1c, 0x76, 0xf9, 0xe3, 0x70, 0xc5,
+	0x1f, 0x47, 0x07, 0x96, 0x3f, 0x8e, 0x0e, 0x10, 0xd3, 0x13, 0xcb, 0x1f, 0xf4, 0x04, 0xfb, 0xe5,
+	0xb1, 0x2c, 0x9a, 0xf2, 0x60, 0xa9, 0x4f, 0x2f, 0xa4, 0x2d, 0xc6, 0x4b, 0xf7, 0xab, 0x4b, 0x2e,
+	0x4d, 0xa9, 0x43, 0x6a, 0x10, 0x5e, 0xd1, 0x67, 0x8a, 0x6d, 0x75, 0x71, 0x35, 0x20, 0xdf, 0x83,
+	0x80, 0x62, 0xf1, 0x54, 0x85, 0x3b, 0xe7, 0xa2, 0xc4, 0x54, 0x6b, 0xc9, 0x5d, 0xfb, 0x05, 0x68,
+	0xee, 0xaa, 0x41, 0xe4, 0x87, 0x30, 0x38, 0xbd, 0x14, 0xb3, 0xda, 0x4e, 0xb1, 0xdf, 0x70, 0xd8,
+	0x5a, 0x2c, 0xb8, 0xd2, 0x51, 0x63, 0x12, 0xbf, 0x82, 0xb0, 0x15, 0xae, 0xc2, 0xf1, 0xdc, 0x70,
+	0x08, 0xf8, 0xaf, 0x73, 0x51, 0xdb, 0x6e, 0xc6, 0x35, 0x26, 0xfb, 0xaa, 0x61, 0x79, 0x2d, 0xea,
+	0xa5, 0x65, 0x29, 0x8b, 0xe3, 0x07, 0x26, 0x7c, 0x74, 0xf7, 0xba, 0x2c, 0xb9, 0x34, 0x8c, 0xa7,
+	0x81, 0xda, 0xa4, 0xb8, 0xe6, 0xfa, 0xf9, 0xea, 0x53, 0x0d, 0xe2, 0x5f, 0x43, 0xb8, 0x9f, 0x71,
+	0x59, 0xd3, 0x26, 0xe3, 0x9b, 0xee, 0x93, 0xe2, 0x0a, 0x13, 0x01, 0xae, 0x57, 0xec, 0xd6, 0x5f,
+	0x63, 0xb7, 0xa7, 0xac, 0x64, 0xc7, 0x47, 0xaa, 0xcf, 0xfb, 0xd4, 0xa0, 0xf8, 0x4f, 0x1e, 0xf8,
+	0x48, 0xa3, 0x8e, 0x6b, 0xff, 0x5d, 0x14, 0x7c, 0x22, 0x8b, 0x2b, 0x91, 0x72, 0x69, 0x93, 0xb3,
+	0x58, 0x15, 0x3d, 0xb9, 0xe4, 0xed, 0xf4, 0x62, 0x10, 0xf6, 0x1a, 0x2d, 0x32, 0x73, 0xc0, 0x9d,
+	0x5e, 0xd3, 0x1f, 0x52, 0x4a, 0x89, 0x13, 0xea, 0x69, 0x53, 0x72, 0xb9, 0x9f, 0x2e, 0x84, 0x1d,
+	0xed, 0x1c, 0x49, 0xfc, 0x95, 0xfe, 0x00, 0xbd, 0x45, 0x16, 0xde, 0xe6, 0x8f, 0xd5, 0xf5, 0xc8,
+	0xe3, 0x3f, 0x7b, 0x30, 0x7c, 0x6e, 0x46, 0x49, 0x37, 0x0b, 0xef, 0xad, 0x59, 0xf4, 0x3a, 0x59,
+	0xec, 0xc1, 0x1d, 0x6b, 0xb3, 0x81, 0xac, 0x36, 0xea, 0x4c, 0x45, 0xfd, 0xf6, 0xb0, 0xde, 0xe3,
+	0xfb, 0x33, 0x3e, 0x83, 0xc9, 0x06, 0x1f, 0xef, 0x26, 0xd0, 0x29, 0x8c, 0xed, 0x77, 0x77, 0x91,
+	0xd9, 0xb7, 0xd1, 0x15, 0xc5, 0x7b, 0x30, 0x38, 0x2c, 0xf2, 0x99, 0x98, 0x93, 0x5d, 0xf0, 0xf7,
+	0x9b, 0xfa, 0x52, 0x79, 0x1c, 0xef, 0xdd, 0x71, 0x2e, 0x7e, 0x53, 0x5f, 0x6a, 0x1b, 0xaa, 0x2c,
+	0xe2, 0x2f, 0x00, 0x56, 0x32, 0x7c, 0xe0, 0x56, 0xa7, 0xf1, 0x82, 0x5f, 0x63, 0xcb, 0x54, 0xe6,
+	0x4b, 0x62, 0x83, 0x26, 0x6e, 0x80, 0xb8, 0x79, 0x18, 0x2f, 0x9f, 0xc0, 0xb6, 0x2b, 0x6d, 0x33,
+	0x5b, 0x93, 0x92, 0x9f, 0x41, 0xf8, 0xac, 0x98, 0x9f, 0x0b, 0x6e, 0x6f, 0xc3, 0x78, 0xef, 0x23,
+	0x87, 0xf0, 0xad, 0xca, 0xc4, 0xbb, 0xb2, 0x8d, 0x1f, 0xc1, 0x07, 0x6b, 0x5a, 0xf2, 0x00, 0x86,
+	0xfa, 0xd3, 0x40, 0xcf, 0xb6, 0x6f, 0xf3, 0x84, 0x16, 0xd4, 0x5a, 0xc6, 0xcb, 0x8e, 0x1f, 0x94,
+	0xb5, 0x95, 0xf7, 0xd6, 0xee, 0x43, 0x51, 0x89, 0xf6, 0xc1, 0x0d, 0x68, 0x8b, 0xc9, 0x4f, 0x21,
+	0x7c, 0x98, 0x27, 0x45, 0x2a, 0xf2, 0xb9, 0x9d, 0x3b, 0xa3, 0xce, 0xc7, 0x6f, 0xb3, 0xc8, 0xad,
+	0x01, 0x5d, 0x99, 0xc6, 0x2f, 0x60, 0xbb, 0xab, 0xdc, 0x38, 0xe1, 0xb7, 0x5f, 0x05, 0x3d, 0xe7,
+	0xab, 0xa0, 0x8d, 0xb1, 0xef, 0x74, 0xfe, 0x97, 0x10, 0x1e, 0x34, 0x22, 0x4b, 0x8f, 0xf3, 0x59,
+	0x81, 0x24, 0x7e, 0xce, 0x65, 0xb5, 0xba, 0x39, 0x16, 0x62, 0xe3, 0x23, 0x9f, 0xb7, 0x6c, 0x66,
+	0xd0, 0xc5, 0x40, 0xfd, 0xbf, 0xf6, 0xe0, 0x3f, 0x03, 0x00, 0x2b, 0x37, 0x0c, 0x3e, 0x71, 0x13,
+	0x00, 0x00,
 }
@@ -201,6 +201,10 @@ func Test_MarshalDashboard(t *testing.T) {
 		},
 		Templates: []chronograf.Template{},
 		Name:      "Dashboard",
+		Roles: []chronograf.DashboardRole{
+			{UserID: 1, Role: "editor"},
+			{UserID: 2, Role: "viewer"},
+		},
 	}
 
 	var actual chronograf.Dashboard
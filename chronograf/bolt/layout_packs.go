@@ -0,0 +1,123 @@
+package bolt
+
+import (
+	"context"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/bolt/internal"
+)
+
+// Ensure LayoutPacksStore implements chronograf.LayoutPacksStore.
+var _ chronograf.LayoutPacksStore = &LayoutPacksStore{}
+
+// LayoutPacksBucket is the bolt bucket layout packs are stored in
+var LayoutPacksBucket = []byte("LayoutPacksV1")
+
+// LayoutPacksStore is the bolt implementation to store layout packs
+type LayoutPacksStore struct {
+	client *Client
+	IDs    chronograf.ID
+}
+
+// All returns all known layout packs
+func (s *LayoutPacksStore) All(ctx context.Context) ([]chronograf.LayoutPack, error) {
+	var packs []chronograf.LayoutPack
+	if err := s.client.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(LayoutPacksBucket).ForEach(func(k, v []byte) error {
+			var pack chronograf.LayoutPack
+			if err := internal.UnmarshalLayoutPack(v, &pack); err != nil {
+				return err
+			}
+			packs = append(packs, pack)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return packs, nil
+}
+
+// Add creates a new LayoutPack in the LayoutPacksStore. Layouts of the
+// pack without an ID are given a new one.
+func (s *LayoutPacksStore) Add(ctx context.Context, pack chronograf.LayoutPack) (chronograf.LayoutPack, error) {
+	if err := s.client.db.Update(func(tx *bolt.Tx) error {
+		id, err := s.IDs.Generate()
+		if err != nil {
+			return err
+		}
+		pack.ID = id
+		if err := s.identifyLayouts(&pack); err != nil {
+			return err
+		}
+		return s.put(tx, pack)
+	}); err != nil {
+		return chronograf.LayoutPack{}, err
+	}
+
+	return pack, nil
+}
+
+// Delete removes the LayoutPack from the LayoutPacksStore
+func (s *LayoutPacksStore) Delete(ctx context.Context, pack chronograf.LayoutPack) error {
+	return s.client.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(LayoutPacksBucket)
+		if v := b.Get([]byte(pack.ID)); v == nil {
+			return chronograf.ErrLayoutPackNotFound
+		}
+		return b.Delete([]byte(pack.ID))
+	})
+}
+
+// Get returns a LayoutPack if the id exists.
+func (s *LayoutPacksStore) Get(ctx context.Context, id string) (chronograf.LayoutPack, error) {
+	var pack chronograf.LayoutPack
+	if err := s.client.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(LayoutPacksBucket).Get([]byte(id))
+		if v == nil {
+			return chronograf.ErrLayoutPackNotFound
+		}
+		return internal.UnmarshalLayoutPack(v, &pack)
+	}); err != nil {
+		return chronograf.LayoutPack{}, err
+	}
+
+	return pack, nil
+}
+
+// Update replaces a LayoutPack. Layouts of the pack without an ID are
+// given a new one.
+func (s *LayoutPacksStore) Update(ctx context.Context, pack chronograf.LayoutPack) error {
+	return s.client.db.Update(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(LayoutPacksBucket).Get([]byte(pack.ID)); v == nil {
+			return chronograf.ErrLayoutPackNotFound
+		}
+		if err := s.identifyLayouts(&pack); err != nil {
+			return err
+		}
+		return s.put(tx, pack)
+	})
+}
+
+func (s *LayoutPacksStore) identifyLayouts(pack *chronograf.LayoutPack) error {
+	for i := range pack.Layouts {
+		if pack.Layouts[i].ID != "" {
+			continue
+		}
+		id, err := s.IDs.Generate()
+		if err != nil {
+			return err
+		}
+		pack.Layouts[i].ID = id
+	}
+	return nil
+}
+
+func (s *LayoutPacksStore) put(tx *bolt.Tx, pack chronograf.LayoutPack) error {
+	v, err := internal.MarshalLayoutPack(pack)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(LayoutPacksBucket).Put([]byte(pack.ID), v)
+}
@@ -0,0 +1,88 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+func TestLayoutPacksStore(t *testing.T) {
+	client, err := NewTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	s := client.LayoutPacksStore
+	ctx := context.Background()
+
+	pack, err := s.Add(ctx, chronograf.LayoutPack{
+		Name:         "team",
+		Organization: "default",
+		Layouts: []chronograf.Layout{
+			{
+				ID:          "fixed",
+				Application: "app",
+				Measurement: "cpu",
+				Cells: []chronograf.Cell{
+					{
+						W:    4,
+						H:    4,
+						I:    "cell",
+						Name: "usage",
+						Queries: []chronograf.Query{
+							{Command: "SELECT mean(usage) FROM cpu", Label: "%"},
+						},
+						Axes: map[string]chronograf.Axis{
+							"y": {Bounds: []string{"0", "100"}},
+						},
+					},
+				},
+			},
+			{Application: "app", Measurement: "mem"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.ID == "" {
+		t.Fatal("expected pack to be given an ID")
+	}
+	if pack.Layouts[0].ID != "fixed" || pack.Layouts[1].ID == "" {
+		t.Fatalf("unexpected layout IDs %q and %q", pack.Layouts[0].ID, pack.Layouts[1].ID)
+	}
+
+	got, err := s.Get(ctx, pack.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, pack, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Get() diff: %s", diff)
+	}
+
+	pack.Name = "renamed"
+	pack.Layouts = pack.Layouts[1:]
+	if err := s.Update(ctx, pack); err != nil {
+		t.Fatal(err)
+	}
+	packs, err := s.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(packs, []chronograf.LayoutPack{pack}, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("All() diff: %s", diff)
+	}
+
+	if err := s.Delete(ctx, pack); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, pack.ID); err != chronograf.ErrLayoutPackNotFound {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, chronograf.ErrLayoutPackNotFound)
+	}
+	if err := s.Update(ctx, pack); err != chronograf.ErrLayoutPackNotFound {
+		t.Errorf("Update() after Delete() error = %v, want %v", err, chronograf.ErrLayoutPackNotFound)
+	}
+}
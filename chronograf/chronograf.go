@@ -39,6 +39,7 @@ const (
 	ErrInvalidCellOptionsSort          = Error("cell options sortby cannot be empty'")
 	ErrInvalidCellOptionsColumns       = Error("cell options columns cannot be empty'")
 	ErrOrganizationConfigNotFound      = Error("could not find organization config")
+	ErrLayoutPackNotFound              = Error("layout pack not found")
 )
 
 // Error is a domain error encountered while processing chronograf requests
@@ -109,6 +110,9 @@ const (
 	InfluxEnterprise = "influx-enterprise"
 	// InfluxRelay is the basic HA layer over InfluxDB
 	InfluxRelay = "influx-relay"
+	// InfluxDBv2 is the 2.x time-series database, whose Username is the
+	// organization and Password the token of the source
+	InfluxDBv2 = "influx-v2"
 )
 
 // TSDBStatus represents the current status of a time series database
@@ -529,20 +533,53 @@ type Databases interface {
 
 // Annotation represents a time-based metadata associated with a source
 type Annotation struct {
-	ID        string    // ID is the unique annotation identifier
-	StartTime time.Time // StartTime starts the annotation
-	EndTime   time.Time // EndTime ends the annotation
-	Text      string    // Text is the associated user-facing text describing the annotation
-	Type      string    // Type describes the kind of annotation
+	ID        string         // ID is the unique annotation identifier
+	StartTime time.Time      // StartTime starts the annotation
+	EndTime   time.Time      // EndTime ends the annotation
+	Text      string         // Text is the associated user-facing text describing the annotation
+	Type      string         // Type describes the kind of annotation
+	Tags      AnnotationTags // Tags are the key/value pairs the annotation is filtered by, e.g. the service of a deploy
+}
+
+// AnnotationTags are the tags of an annotation by their keys
+type AnnotationTags map[string]string
+
+// Matches specifies if the tags have all of the tags of other
+func (t AnnotationTags) Matches(other AnnotationTags) bool {
+	for k, v := range other {
+		if t[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// AnnotationFilter selects the annotations of AnnotationStore.All
+type AnnotationFilter struct {
+	StartTime time.Time      // StartTime excludes the annotations that end before it
+	EndTime   time.Time      // EndTime excludes the annotations that start after it
+	Tags      AnnotationTags // Tags are the tags the annotations must all have
+	Text      string         // Text is case-insensitively contained by the text of the annotations
+	Limit     int            // Limit is the maximum number of annotations; zero is no limit
+	Offset    int            // Offset is the number of matching annotations to skip
+}
+
+// AnnotationVersion is an annotation as it was after one of its changes
+type AnnotationVersion struct {
+	Annotation
+	ModifiedTime time.Time // ModifiedTime is when the annotation was changed to this version
+	Deleted      bool      // Deleted is true if the change removed the annotation
 }
 
 // AnnotationStore represents storage and retrieval of annotations
 type AnnotationStore interface {
-	All(ctx context.Context, start, stop time.Time) ([]Annotation, error) // All lists all Annotations between start and stop
-	Add(context.Context, *Annotation) (*Annotation, error)                // Add creates a new annotation in the store
-	Delete(ctx context.Context, id string) error                          // Delete removes the annotation from the store
-	Get(ctx context.Context, id string) (*Annotation, error)              // Get retrieves an annotation
-	Update(context.Context, *Annotation) error                            // Update replaces annotation
+	All(ctx context.Context, filter AnnotationFilter) ([]Annotation, error) // All lists the Annotations that match the filter
+	Add(context.Context, *Annotation) (*Annotation, error)                  // Add creates a new annotation in the store
+	AddMany(context.Context, []*Annotation) ([]*Annotation, error)          // AddMany creates all the annotations in the store at once
+	Delete(ctx context.Context, id string) error                            // Delete removes the annotation from the store
+	Get(ctx context.Context, id string) (*Annotation, error)                // Get retrieves an annotation
+	Update(context.Context, *Annotation) error                              // Update replaces annotation
+	Versions(ctx context.Context, id string) ([]AnnotationVersion, error)   // Versions lists every version of an annotation, oldest first
 }
 
 // DashboardID is the dashboard ID
@@ -554,7 +591,16 @@ type Dashboard struct {
 	Cells        []DashboardCell `json:"cells"`
 	Templates    []Template      `json:"templates"`
 	Name         string          `json:"name"`
-	Organization string          `json:"organization"` // Organization is the organization ID that resource belongs to
+	Organization string          `json:"organization"`    // Organization is the organization ID that resource belongs to
+	Roles        []DashboardRole `json:"roles,omitempty"` // Roles restrict the users who may edit the dashboard
+}
+
+// DashboardRole is the role of a user on a dashboard. Once a dashboard has
+// roles, only its editors and the admins of its organization may change it;
+// a role does not grant more than the role of the user in the organization.
+type DashboardRole struct {
+	UserID uint64 `json:"userID,string"`
+	Role   string `json:"role"` // Role is either viewer or editor
 }
 
 // Axis represents the visible extents of a visualization
@@ -674,6 +720,29 @@ type LayoutsStore interface {
 	Update(context.Context, Layout) error
 }
 
+// LayoutPack is a named collection of Layouts uploaded by the users of an
+// organization, in addition to the canned layouts shipped with chronograf.
+type LayoutPack struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Organization string   `json:"organization"`
+	Layouts      []Layout `json:"layouts"`
+}
+
+// LayoutPacksStore stores layout packs
+type LayoutPacksStore interface {
+	// All returns all layout packs in the store
+	All(context.Context) ([]LayoutPack, error)
+	// Add creates a new layout pack in the LayoutPacksStore
+	Add(context.Context, LayoutPack) (LayoutPack, error)
+	// Delete the layout pack from the store
+	Delete(context.Context, LayoutPack) error
+	// Get retrieves LayoutPack if `ID` exists
+	Get(ctx context.Context, ID string) (LayoutPack, error)
+	// Update the layout pack in the store.
+	Update(context.Context, LayoutPack) error
+}
+
 // MappingWildcard is the wildcard value for mappings
 const MappingWildcard string = "*"
 
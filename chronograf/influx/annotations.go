@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/chronograf"
@@ -14,17 +15,34 @@ import (
 
 const (
 	// AllAnnotations returns all annotations from the chronograf database
-	AllAnnotations = `SELECT "start_time", "modified_time_ns", "text", "type", "id" FROM "annotations" WHERE "deleted"=false AND time >= %dns and "start_time" <= %d ORDER BY time DESC`
+	AllAnnotations = `SELECT "start_time", "modified_time_ns", "text", "type", "id" FROM "annotations" WHERE "deleted"=false AND time >= %dns and "start_time" <= %d GROUP BY * ORDER BY time DESC`
 	// GetAnnotationID returns all annotations from the chronograf database where id is %s
-	GetAnnotationID = `SELECT "start_time", "modified_time_ns", "text", "type", "id" FROM "annotations" WHERE "id"='%s' AND "deleted"=false ORDER BY time DESC`
+	GetAnnotationID = `SELECT "start_time", "modified_time_ns", "text", "type", "id" FROM "annotations" WHERE "id"='%s' AND "deleted"=false GROUP BY * ORDER BY time DESC`
+	// AnnotationVersionsID returns every version of the annotation where id is %s
+	AnnotationVersionsID = `SELECT "start_time", "end_time", "text", "type", "tags", "deleted" FROM "annotation_versions" WHERE "id"='%s' ORDER BY time ASC`
 	// AnnotationsDB is chronograf.  Perhaps later we allow this to be changed
 	AnnotationsDB = "chronograf"
 	// DefaultRP is autogen. Perhaps later we allow this to be changed
 	DefaultRP = "autogen"
 	// DefaultMeasurement is annotations.
 	DefaultMeasurement = "annotations"
+	// VersionsMeasurement is annotation_versions; it has a point for each
+	// change of each annotation, at the time of the change.
+	VersionsMeasurement = "annotation_versions"
 )
 
+// reservedTagKeys are the keys of the fields and tags of the points of
+// annotations, which the tags of annotations cannot have
+var reservedTagKeys = map[string]bool{
+	"time":             true,
+	"id":               true,
+	"deleted":          true,
+	"start_time":       true,
+	"modified_time_ns": true,
+	"text":             true,
+	"type":             true,
+}
+
 var _ chronograf.AnnotationStore = &AnnotationStore{}
 
 // AnnotationStore stores annotations within InfluxDB
@@ -43,9 +61,36 @@ func NewAnnotationStore(client chronograf.TimeSeries) *AnnotationStore {
 	}
 }
 
-// All lists all Annotations
-func (a *AnnotationStore) All(ctx context.Context, start, stop time.Time) ([]chronograf.Annotation, error) {
-	return a.queryAnnotations(ctx, fmt.Sprintf(AllAnnotations, start.UnixNano(), stop.UnixNano()))
+// All lists the Annotations of the time range of the filter that have its
+// tags and text. The annotations are filtered after the latest version of
+// each is found, so that an annotation is not matched by the tags or text of
+// a previous version.
+func (a *AnnotationStore) All(ctx context.Context, filter chronograf.AnnotationFilter) ([]chronograf.Annotation, error) {
+	annos, err := a.queryAnnotations(ctx, fmt.Sprintf(AllAnnotations, filter.StartTime.UnixNano(), filter.EndTime.UnixNano()))
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ToLower(filter.Text)
+	res := []chronograf.Annotation{}
+	for _, anno := range annos {
+		if !anno.Tags.Matches(filter.Tags) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(anno.Text), text) {
+			continue
+		}
+		res = append(res, anno)
+	}
+
+	if filter.Offset >= len(res) {
+		return []chronograf.Annotation{}, nil
+	}
+	res = res[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(res) {
+		res = res[:filter.Limit]
+	}
+	return res, nil
 }
 
 // Get retrieves an annotation
@@ -62,51 +107,113 @@ func (a *AnnotationStore) Get(ctx context.Context, id string) (*chronograf.Annot
 
 // Add creates a new annotation in the store
 func (a *AnnotationStore) Add(ctx context.Context, anno *chronograf.Annotation) (*chronograf.Annotation, error) {
+	if err := validTags(anno.Tags); err != nil {
+		return nil, err
+	}
 	var err error
 	anno.ID, err = a.id.Generate()
 	if err != nil {
 		return nil, err
 	}
+	now := a.now()
 	return anno, a.client.Write(ctx, []chronograf.Point{
-		toPoint(anno, a.now()),
+		toPoint(anno, now),
+		toVersionPoint(anno, now, false),
 	})
 }
 
+// AddMany creates the annotations in the store with a single write; none of
+// them are created if any has invalid tags
+func (a *AnnotationStore) AddMany(ctx context.Context, annos []*chronograf.Annotation) ([]*chronograf.Annotation, error) {
+	for _, anno := range annos {
+		if err := validTags(anno.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	now := a.now()
+	points := make([]chronograf.Point, 0, 2*len(annos))
+	for _, anno := range annos {
+		var err error
+		anno.ID, err = a.id.Generate()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, toPoint(anno, now), toVersionPoint(anno, now, false))
+	}
+	return annos, a.client.Write(ctx, points)
+}
+
 // Delete removes the annotation from the store
 func (a *AnnotationStore) Delete(ctx context.Context, id string) error {
 	cur, err := a.Get(ctx, id)
 	if err != nil {
 		return err
 	}
+	now := a.now()
 	return a.client.Write(ctx, []chronograf.Point{
-		toDeletedPoint(cur, a.now()),
+		toDeletedPoint(cur, now),
+		toVersionPoint(cur, now, true),
 	})
 }
 
-// Update replaces annotation; if the annotation's time is different, it
-// also removes the previous annotation
+// Update replaces annotation; if the annotation's time or tags are
+// different, it also removes the previous annotation
 func (a *AnnotationStore) Update(ctx context.Context, anno *chronograf.Annotation) error {
+	if err := validTags(anno.Tags); err != nil {
+		return err
+	}
 	cur, err := a.Get(ctx, anno.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := a.client.Write(ctx, []chronograf.Point{toPoint(anno, a.now())}); err != nil {
+	now := a.now()
+	if err := a.client.Write(ctx, []chronograf.Point{
+		toPoint(anno, now),
+		toVersionPoint(anno, now, false),
+	}); err != nil {
 		return err
 	}
 
-	// If the updated annotation has a different time, then, we must
-	// delete the previous annotation
-	if !cur.EndTime.Equal(anno.EndTime) {
+	// If the updated annotation has a different time or tags, then, it is
+	// written to another point and we must delete the previous annotation
+	if !cur.EndTime.Equal(anno.EndTime) || !sameTags(cur.Tags, anno.Tags) {
 		return a.client.Write(ctx, []chronograf.Point{
-			toDeletedPoint(cur, a.now()),
+			toDeletedPoint(cur, now),
 		})
 	}
 	return nil
 }
 
+// Versions lists every version of an annotation, oldest first, including
+// the removal of the annotation if it was deleted
+func (a *AnnotationStore) Versions(ctx context.Context, id string) ([]chronograf.AnnotationVersion, error) {
+	results, err := a.query(ctx, fmt.Sprintf(AnnotationVersionsID, id))
+	if err != nil {
+		return nil, err
+	}
+	versions, err := results.Versions(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, chronograf.ErrAnnotationNotFound
+	}
+	return versions, nil
+}
+
 // queryAnnotations queries the chronograf db and produces all annotations
 func (a *AnnotationStore) queryAnnotations(ctx context.Context, query string) ([]chronograf.Annotation, error) {
+	results, err := a.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return results.Annotations()
+}
+
+// query queries the chronograf db
+func (a *AnnotationStore) query(ctx context.Context, query string) (influxResults, error) {
 	res, err := a.client.Query(ctx, chronograf.Query{
 		Command: query,
 		DB:      AnnotationsDB,
@@ -126,7 +233,35 @@ func (a *AnnotationStore) queryAnnotations(ctx context.Context, query string) ([
 	if err := d.Decode(&results); err != nil {
 		return nil, err
 	}
-	return results.Annotations()
+	return results, nil
+}
+
+// validTags returns an error if a tag is empty or has a reserved key
+func validTags(tags chronograf.AnnotationTags) error {
+	for k, v := range tags {
+		if k == "" || v == "" {
+			return fmt.Errorf("annotation tags must have a key and a value")
+		}
+		if reservedTagKeys[k] {
+			return fmt.Errorf("annotation tag key %q is reserved", k)
+		}
+	}
+	return nil
+}
+
+func sameTags(a, b chronograf.AnnotationTags) bool {
+	return len(a) == len(b) && a.Matches(b)
+}
+
+// pointTags returns the tags of the point of the annotation
+func pointTags(anno *chronograf.Annotation) map[string]string {
+	tags := map[string]string{
+		"id": anno.ID,
+	}
+	for k, v := range anno.Tags {
+		tags[k] = v
+	}
+	return tags
 }
 
 func toPoint(anno *chronograf.Annotation, now time.Time) chronograf.Point {
@@ -135,9 +270,7 @@ func toPoint(anno *chronograf.Annotation, now time.Time) chronograf.Point {
 		RetentionPolicy: DefaultRP,
 		Measurement:     DefaultMeasurement,
 		Time:            anno.EndTime.UnixNano(),
-		Tags: map[string]string{
-			"id": anno.ID,
-		},
+		Tags:            pointTags(anno),
 		Fields: map[string]interface{}{
 			"deleted":          false,
 			"start_time":       anno.StartTime.UnixNano(),
@@ -148,15 +281,15 @@ func toPoint(anno *chronograf.Annotation, now time.Time) chronograf.Point {
 	}
 }
 
+// toDeletedPoint returns a point that overwrites the point of the annotation,
+// which has the same time and tags
 func toDeletedPoint(anno *chronograf.Annotation, now time.Time) chronograf.Point {
 	return chronograf.Point{
 		Database:        AnnotationsDB,
 		RetentionPolicy: DefaultRP,
 		Measurement:     DefaultMeasurement,
 		Time:            anno.EndTime.UnixNano(),
-		Tags: map[string]string{
-			"id": anno.ID,
-		},
+		Tags:            pointTags(anno),
 		Fields: map[string]interface{}{
 			"deleted":          true,
 			"start_time":       int64(0),
@@ -167,6 +300,31 @@ func toDeletedPoint(anno *chronograf.Annotation, now time.Time) chronograf.Point
 	}
 }
 
+// toVersionPoint returns the point recording that the annotation was changed
+// to its current state, or removed, at now
+func toVersionPoint(anno *chronograf.Annotation, now time.Time, deleted bool) chronograf.Point {
+	// The tags of the annotation are a field, as a series per set of tags
+	// would split the versions of an annotation whose tags were changed.
+	tags, _ := json.Marshal(anno.Tags)
+	return chronograf.Point{
+		Database:        AnnotationsDB,
+		RetentionPolicy: DefaultRP,
+		Measurement:     VersionsMeasurement,
+		Time:            now.UnixNano(),
+		Tags: map[string]string{
+			"id": anno.ID,
+		},
+		Fields: map[string]interface{}{
+			"deleted":    deleted,
+			"start_time": anno.StartTime.UnixNano(),
+			"end_time":   anno.EndTime.UnixNano(),
+			"text":       anno.Text,
+			"type":       anno.Type,
+			"tags":       string(tags),
+		},
+	}
+}
+
 type value []interface{}
 
 func (v value) Int64(idx int) (int64, error) {
@@ -199,12 +357,40 @@ func (v value) String(idx int) (string, error) {
 	return str, nil
 }
 
+func (v value) Bool(idx int) (bool, error) {
+	if idx >= len(v) {
+		return false, fmt.Errorf("index %d does not exist in values", idx)
+	}
+	b, ok := v[idx].(bool)
+	if !ok {
+		return false, fmt.Errorf("value at index %d is not bool, but, %T", idx, v[idx])
+	}
+	return b, nil
+}
+
 type influxResults []struct {
 	Series []struct {
-		Values []value `json:"values"`
+		Tags   map[string]string `json:"tags"`
+		Values []value           `json:"values"`
 	} `json:"series"`
 }
 
+// annotationTags returns the tags of the annotations of a series grouped by
+// all tags; series have empty values for the keys their points do not have.
+func annotationTags(tags map[string]string) chronograf.AnnotationTags {
+	var res chronograf.AnnotationTags
+	for k, v := range tags {
+		if k == "id" || v == "" {
+			continue
+		}
+		if res == nil {
+			res = chronograf.AnnotationTags{}
+		}
+		res[k] = v
+	}
+	return res
+}
+
 // annotationResult is an intermediate struct to track the latest modified
 // time of an annotation
 type annotationResult struct {
@@ -221,6 +407,7 @@ func (r *influxResults) Annotations() (res []chronograf.Annotation, err error) {
 		for _, s := range u.Series {
 			for _, v := range s.Values {
 				anno := annotationResult{}
+				anno.Tags = annotationTags(s.Tags)
 
 				if anno.EndTime, err = v.Time(0); err != nil {
 					return
@@ -268,3 +455,51 @@ func (r *influxResults) Annotations() (res []chronograf.Annotation, err error) {
 
 	return res, err
 }
+
+// Versions converts AnnotationVersionsID query to the versions of the
+// annotation with the id
+func (r *influxResults) Versions(id string) ([]chronograf.AnnotationVersion, error) {
+	res := []chronograf.AnnotationVersion{}
+	for _, u := range *r {
+		for _, s := range u.Series {
+			for _, v := range s.Values {
+				version := chronograf.AnnotationVersion{}
+				version.ID = id
+
+				var err error
+				if version.ModifiedTime, err = v.Time(0); err != nil {
+					return nil, err
+				}
+				if version.StartTime, err = v.Time(1); err != nil {
+					return nil, err
+				}
+				if version.EndTime, err = v.Time(2); err != nil {
+					return nil, err
+				}
+				if version.Text, err = v.String(3); err != nil {
+					return nil, err
+				}
+				if version.Type, err = v.String(4); err != nil {
+					return nil, err
+				}
+				tags, err := v.String(5)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal([]byte(tags), &version.Tags); err != nil {
+					return nil, fmt.Errorf("invalid tags of annotation version: %v", err)
+				}
+				if version.Deleted, err = v.Bool(6); err != nil {
+					return nil, err
+				}
+
+				res = append(res, version)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].ModifiedTime.Before(res[j].ModifiedTime)
+	})
+	return res, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/id"
 	"github.com/influxdata/influxdb/chronograf/mocks"
 )
 
@@ -72,6 +73,35 @@ func Test_toPoint(t *testing.T) {
 				},
 			},
 		},
+		2: {
+			name: "convert annotation with tags to point",
+			anno: &chronograf.Annotation{
+				ID:   "1",
+				Text: "mytext",
+				Type: "mytype",
+				Tags: chronograf.AnnotationTags{
+					"service": "api",
+				},
+			},
+			now: time.Unix(0, 0),
+			want: chronograf.Point{
+				Database:        AnnotationsDB,
+				RetentionPolicy: DefaultRP,
+				Measurement:     DefaultMeasurement,
+				Time:            time.Time{}.UnixNano(),
+				Tags: map[string]string{
+					"id":      "1",
+					"service": "api",
+				},
+				Fields: map[string]interface{}{
+					"deleted":          false,
+					"start_time":       time.Time{}.UnixNano(),
+					"modified_time_ns": int64(time.Unix(0, 0).UnixNano()),
+					"text":             "mytext",
+					"type":             "mytype",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -440,6 +470,88 @@ func TestAnnotationStore_queryAnnotations(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "tags of the series are the tags of the annotations",
+			client: &mocks.TimeSeries{
+				QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+					return mocks.NewResponse(`[
+						{
+							"series": [
+								{
+									"name": "annotations",
+									"tags": {
+										"id": "ea0aa94b-969a-4cd5-912a-5db61d502268",
+										"service": "api"
+									},
+									"columns": [
+										"time",
+										"start_time",
+										"modified_time_ns",
+										"text",
+										"type",
+										"id"
+									],
+									"values": [
+										[
+											1516920177345000000,
+											0,
+											1516989242129417403,
+											"deploy",
+											"mytype",
+											"ea0aa94b-969a-4cd5-912a-5db61d502268"
+										]
+									]
+								},
+								{
+									"name": "annotations",
+									"tags": {
+										"id": "ecf3a75d-f1c0-40e8-9790-902701467e92",
+										"service": ""
+									},
+									"columns": [
+										"time",
+										"start_time",
+										"modified_time_ns",
+										"text",
+										"type",
+										"id"
+									],
+									"values": [
+										[
+											1516920177345000000,
+											0,
+											1516989242129417403,
+											"mytext",
+											"mytype",
+											"ecf3a75d-f1c0-40e8-9790-902701467e92"
+										]
+									]
+								}
+							]
+						}
+					]`, nil), nil
+				},
+			},
+			want: []chronograf.Annotation{
+				{
+					EndTime:   time.Unix(0, 1516920177345000000),
+					StartTime: time.Unix(0, 0),
+					Text:      "deploy",
+					Type:      "mytype",
+					ID:        "ea0aa94b-969a-4cd5-912a-5db61d502268",
+					Tags: chronograf.AnnotationTags{
+						"service": "api",
+					},
+				},
+				{
+					EndTime:   time.Unix(0, 1516920177345000000),
+					StartTime: time.Unix(0, 0),
+					Text:      "mytext",
+					Type:      "mytype",
+					ID:        "ecf3a75d-f1c0-40e8-9790-902701467e92",
+				},
+			},
+		},
 		{
 			name: "no responses returns empty array",
 			client: &mocks.TimeSeries{
@@ -663,3 +775,286 @@ func TestAnnotationStore_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotationStore_All(t *testing.T) {
+	client := &mocks.TimeSeries{
+		QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+			return mocks.NewResponse(`[
+				{
+					"series": [
+						{
+							"name": "annotations",
+							"tags": {
+								"id": "1",
+								"service": "api"
+							},
+							"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+							"values": [
+								[1000, 1000, 1, "Deploy v1.0", "deploy", "1"]
+							]
+						},
+						{
+							"name": "annotations",
+							"tags": {
+								"id": "2",
+								"service": "web"
+							},
+							"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+							"values": [
+								[2000, 2000, 1, "Deploy v1.1", "deploy", "2"]
+							]
+						},
+						{
+							"name": "annotations",
+							"tags": {
+								"id": "3",
+								"service": "api"
+							},
+							"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+							"values": [
+								[3000, 3000, 1, "Outage", "incident", "3"]
+							]
+						},
+						{
+							"name": "annotations",
+							"tags": {
+								"id": "3",
+								"service": "web"
+							},
+							"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+							"values": [
+								[3000, 3000, 2, "Outage", "incident", "3"]
+							]
+						}
+					]
+				}
+			]`, nil), nil
+		},
+	}
+	tests := []struct {
+		name    string
+		filter  chronograf.AnnotationFilter
+		wantIDs []string
+	}{
+		{
+			name:    "no filter returns the latest version of all annotations",
+			wantIDs: []string{"1", "2", "3"},
+		},
+		{
+			name: "tags match the latest version of the annotations",
+			filter: chronograf.AnnotationFilter{
+				Tags: chronograf.AnnotationTags{"service": "api"},
+			},
+			wantIDs: []string{"1"},
+		},
+		{
+			name: "text is matched case-insensitively",
+			filter: chronograf.AnnotationFilter{
+				Text: "DEPLOY",
+			},
+			wantIDs: []string{"1", "2"},
+		},
+		{
+			name: "tags and text",
+			filter: chronograf.AnnotationFilter{
+				Tags: chronograf.AnnotationTags{"service": "web"},
+				Text: "deploy",
+			},
+			wantIDs: []string{"2"},
+		},
+		{
+			name: "limit and offset paginate the annotations",
+			filter: chronograf.AnnotationFilter{
+				Limit:  1,
+				Offset: 1,
+			},
+			wantIDs: []string{"2"},
+		},
+		{
+			name: "offset past the annotations",
+			filter: chronograf.AnnotationFilter{
+				Offset: 3,
+			},
+			wantIDs: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &AnnotationStore{
+				client: client,
+			}
+			got, err := a.All(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("AnnotationStore.All() error = %v", err)
+			}
+			ids := []string{}
+			for _, anno := range got {
+				ids = append(ids, anno.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("AnnotationStore.All() = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestAnnotationStore_UpdateTags(t *testing.T) {
+	var written []chronograf.Point
+	a := &AnnotationStore{
+		client: &mocks.TimeSeries{
+			QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+				return mocks.NewResponse(`[
+					{
+						"series": [
+							{
+								"name": "annotations",
+								"tags": {
+									"id": "1",
+									"service": "api"
+								},
+								"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+								"values": [
+									[1000, 1000, 1, "mytext", "mytype", "1"]
+								]
+							}
+						]
+					}
+				]`, nil), nil
+			},
+			WriteF: func(ctx context.Context, points []chronograf.Point) error {
+				written = append(written, points...)
+				return nil
+			},
+		},
+		now: func() time.Time { return time.Unix(0, 2) },
+	}
+
+	err := a.Update(context.Background(), &chronograf.Annotation{
+		ID:        "1",
+		StartTime: time.Unix(0, 1000),
+		EndTime:   time.Unix(0, 1000),
+		Text:      "mytext",
+		Type:      "mytype",
+		Tags:      chronograf.AnnotationTags{"service": "web"},
+	})
+	if err != nil {
+		t.Fatalf("AnnotationStore.Update() error = %v", err)
+	}
+	if len(written) != 3 {
+		t.Fatalf("AnnotationStore.Update() wrote %d points, want the update, its version and the deletion of the previous version", len(written))
+	}
+	if version := written[1]; version.Measurement != VersionsMeasurement || version.Fields["tags"] != `{"service":"web"}` {
+		t.Errorf("AnnotationStore.Update() did not record the version: %v", version)
+	}
+	if deleted := written[2]; deleted.Fields["deleted"] != true || !reflect.DeepEqual(deleted.Tags, map[string]string{"id": "1", "service": "api"}) {
+		t.Errorf("AnnotationStore.Update() did not delete the previous version: %v", deleted)
+	}
+
+	if err := a.Update(context.Background(), &chronograf.Annotation{
+		ID:   "1",
+		Tags: chronograf.AnnotationTags{"text": "reserved"},
+	}); err == nil {
+		t.Error("AnnotationStore.Update() with a reserved tag key expected an error")
+	}
+}
+
+func TestAnnotationStore_AddMany(t *testing.T) {
+	var written []chronograf.Point
+	a := &AnnotationStore{
+		client: &mocks.TimeSeries{
+			WriteF: func(ctx context.Context, points []chronograf.Point) error {
+				written = append(written, points...)
+				return nil
+			},
+		},
+		id:  &id.UUID{},
+		now: func() time.Time { return time.Unix(0, 0) },
+	}
+
+	annos, err := a.AddMany(context.Background(), []*chronograf.Annotation{
+		{Text: "deploy", Tags: chronograf.AnnotationTags{"service": "api"}},
+		{Text: "rollback"},
+	})
+	if err != nil {
+		t.Fatalf("AnnotationStore.AddMany() error = %v", err)
+	}
+	if len(annos) != 2 || len(written) != 4 {
+		t.Fatalf("AnnotationStore.AddMany() added %d annotations with %d points, want 2 with their versions", len(annos), len(written))
+	}
+
+	written = nil
+	if _, err := a.AddMany(context.Background(), []*chronograf.Annotation{
+		{Text: "deploy"},
+		{Text: "rollback", Tags: chronograf.AnnotationTags{"service": ""}},
+	}); err == nil {
+		t.Error("AnnotationStore.AddMany() with an empty tag expected an error")
+	}
+	if len(written) != 0 {
+		t.Errorf("AnnotationStore.AddMany() wrote %d points of invalid annotations", len(written))
+	}
+}
+
+func TestAnnotationStore_Versions(t *testing.T) {
+	var query string
+	a := &AnnotationStore{
+		client: &mocks.TimeSeries{
+			QueryF: func(ctx context.Context, q chronograf.Query) (chronograf.Response, error) {
+				query = q.Command
+				return mocks.NewResponse(`[
+					{
+						"series": [
+							{
+								"name": "annotation_versions",
+								"columns": ["time", "start_time", "end_time", "text", "type", "tags", "deleted"],
+								"values": [
+									[3, 1000, 2000, "deploy v2", "mytype", "{\"service\":\"web\"}", true],
+									[1, 1000, 2000, "deploy", "mytype", "{\"service\":\"api\"}", false],
+									[2, 1000, 2000, "deploy v2", "mytype", "{\"service\":\"web\"}", false]
+								]
+							}
+						]
+					}
+				]`, nil), nil
+			},
+		},
+	}
+
+	got, err := a.Versions(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("AnnotationStore.Versions() error = %v", err)
+	}
+	if want := fmt.Sprintf(AnnotationVersionsID, "1"); query != want {
+		t.Errorf("AnnotationStore.Versions() query = %q, want %q", query, want)
+	}
+	version := func(modified int64, text, service string, deleted bool) chronograf.AnnotationVersion {
+		return chronograf.AnnotationVersion{
+			Annotation: chronograf.Annotation{
+				ID:        "1",
+				StartTime: time.Unix(0, 1000),
+				EndTime:   time.Unix(0, 2000),
+				Text:      text,
+				Type:      "mytype",
+				Tags:      chronograf.AnnotationTags{"service": service},
+			},
+			ModifiedTime: time.Unix(0, modified),
+			Deleted:      deleted,
+		}
+	}
+	want := []chronograf.AnnotationVersion{
+		version(1, "deploy", "api", false),
+		version(2, "deploy v2", "web", false),
+		version(3, "deploy v2", "web", true),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnnotationStore.Versions() = %v, want %v", got, want)
+	}
+
+	a.client = &mocks.TimeSeries{
+		QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+			return mocks.NewResponse(`[{}]`, nil), nil
+		},
+	}
+	if _, err := a.Versions(context.Background(), "2"); err != chronograf.ErrAnnotationNotFound {
+		t.Errorf("AnnotationStore.Versions() of an unknown annotation error = %v, want %v", err, chronograf.ErrAnnotationNotFound)
+	}
+}
@@ -21,8 +21,13 @@ type NoAuthorization struct{}
 // Set does not add authorization
 func (n *NoAuthorization) Set(req *http.Request) error { return nil }
 
-// DefaultAuthorization creates either a shared JWT builder, basic auth or Noop
+// DefaultAuthorization creates either a token, a shared JWT builder, basic auth or Noop
 func DefaultAuthorization(src *chronograf.Source) Authorizer {
+	if src.Type == chronograf.InfluxDBv2 {
+		return &TokenAuth{
+			Token: src.Password,
+		}
+	}
 	// Optionally, add the shared secret JWT token creation
 	if src.Username != "" && src.SharedSecret != "" {
 		return &BearerJWT{
@@ -50,6 +55,17 @@ func (b *BasicAuth) Set(r *http.Request) error {
 	return nil
 }
 
+// TokenAuth adds Authorization: Token to the request header of InfluxDB 2.x
+type TokenAuth struct {
+	Token string
+}
+
+// Set adds the token to the request
+func (t *TokenAuth) Set(r *http.Request) error {
+	r.Header.Set("Authorization", "Token "+t.Token)
+	return nil
+}
+
 // BearerJWT is the default Bearer for InfluxDB
 type BearerJWT struct {
 	Username     string
@@ -33,6 +33,11 @@ type Client struct {
 	Authorizer         Authorizer
 	InsecureSkipVerify bool
 	Logger             chronograf.Logger
+	// Org is the organization of an InfluxDB 2.x source, whose InfluxQL
+	// queries are transpiled into Flux.
+	Org string
+
+	v2 bool
 }
 
 // Response is a partial JSON decoded InfluxQL response used
@@ -144,6 +149,11 @@ func (c *Client) Query(ctx context.Context, q chronograf.Query) (chronograf.Resp
 
 	resps := make(chan (result))
 	go func() {
+		if c.v2 {
+			resp, err := c.queryV2(ctx, c.URL, q)
+			resps <- result{resp, err}
+			return
+		}
 		resp, err := c.query(ctx, c.URL, q)
 		resps <- result{resp, err}
 	}()
@@ -166,6 +176,10 @@ func (c *Client) Connect(ctx context.Context, src *chronograf.Source) error {
 		return err
 	}
 	c.Authorizer = DefaultAuthorization(src)
+	c.v2 = src.Type == chronograf.InfluxDBv2
+	if c.v2 {
+		c.Org = src.Username
+	}
 	// Only allow acceptance of all certs if the scheme is https AND the user opted into to the setting.
 	if u.Scheme == "https" && src.InsecureSkipVerify {
 		c.InsecureSkipVerify = src.InsecureSkipVerify
@@ -209,6 +223,11 @@ func (c *Client) pingTimeout(ctx context.Context) (string, string, error) {
 
 	resps := make(chan (pingResult))
 	go func() {
+		if c.v2 {
+			err := c.health(ctx, c.URL)
+			resps <- pingResult{"", chronograf.InfluxDBv2, err}
+			return
+		}
 		version, tsdbType, err := c.ping(ctx, c.URL)
 		resps <- pingResult{version, tsdbType, err}
 	}()
@@ -0,0 +1,368 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/kit/tracing"
+	transpiler "github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxql"
+)
+
+// epochUnits are the units of the epoch query parameter of InfluxDB 1.x.
+var epochUnits = map[string]time.Duration{
+	"h":  time.Hour,
+	"m":  time.Minute,
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"u":  time.Microsecond,
+	"µ":  time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+type v2QueryRequest struct {
+	Query   string    `json:"query"`
+	Type    string    `json:"type"`
+	Dialect v2Dialect `json:"dialect"`
+}
+
+type v2Dialect struct {
+	Annotations []string `json:"annotations"`
+}
+
+// queryV2 transpiles the InfluxQL query into Flux, queries the InfluxDB 2.x
+// source with it and returns its results as InfluxDB 1.x would.
+func (c *Client) queryV2(ctx context.Context, u *url.URL, q chronograf.Query) (chronograf.Response, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	logs := c.Logger.
+		WithField("component", "proxy").
+		WithField("host", u.Host).
+		WithField("command", q.Command).
+		WithField("db", q.DB).
+		WithField("rp", q.RP)
+	logs.Debug("query")
+
+	if c.Org == "" {
+		return nil, errors.New("organization of the InfluxDB 2.x source is required")
+	}
+	query, err := c.transpile(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(v2QueryRequest{
+		Query: query,
+		Type:  "flux",
+		Dialect: v2Dialect{
+			Annotations: []string{"datatype", "group", "default"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newV2Request(ctx, "POST", u, "/api/v2/query", url.Values{"org": {c.Org}}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.InjectToHTTPRequest(span, req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkV2Response(resp, http.StatusOK); err != nil {
+		logs.WithField("influx_status", resp.StatusCode).
+			Error("Received non-200 response from influxdb")
+		return nil, err
+	}
+
+	results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var buf bytes.Buffer
+	if _, err := transpiler.NewMultiResultEncoder().Encode(&buf, results); err != nil {
+		return nil, err
+	}
+	var response transpiler.Response
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	if err := dec.Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Err != "" {
+		return nil, errors.New(response.Err)
+	}
+	if response.Results == nil {
+		response.Results = []transpiler.Result{}
+	}
+
+	epoch := q.Epoch
+	if epoch == "" {
+		epoch = "ms"
+	}
+	if err := epochTimes(response.Results, epoch); err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(response.Results)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Results: octets}, nil
+}
+
+// transpile returns the Flux of the InfluxQL query. Only the statements that
+// the chronograf query builder uses to render dashboards are supported.
+func (c *Client) transpile(ctx context.Context, q chronograf.Query) (string, error) {
+	parsed, err := influxql.ParseQuery(q.Command)
+	if err != nil {
+		return "", err
+	}
+	for _, stmt := range parsed.Statements {
+		switch stmt.(type) {
+		case *influxql.SelectStatement,
+			*influxql.ShowDatabasesStatement,
+			*influxql.ShowRetentionPoliciesStatement,
+			*influxql.ShowTagValuesStatement:
+		default:
+			words := strings.Fields(stmt.String())
+			if len(words) > 2 {
+				words = words[:2]
+			}
+			return "", fmt.Errorf("%s statements are not supported by InfluxDB 2.x sources", strings.Join(words, " "))
+		}
+	}
+
+	t := transpiler.NewTranspilerWithConfig(&dbrpMappingService{c: c}, transpiler.Config{
+		DefaultDatabase:        q.DB,
+		DefaultRetentionPolicy: q.RP,
+	})
+	pkg, err := t.Transpile(ctx, q.Command)
+	if err != nil {
+		return "", fmt.Errorf("unable to transpile the query into Flux for the InfluxDB 2.x source: %v", err)
+	}
+	return ast.Format(pkg.Files[0]), nil
+}
+
+// epochTimes replaces the RFC3339 times of the results, which the transpiled
+// queries return, with the number of units of the epoch since the Unix epoch.
+func epochTimes(results []transpiler.Result, epoch string) error {
+	unit, ok := epochUnits[epoch]
+	if !ok {
+		return fmt.Errorf("invalid epoch %q", epoch)
+	}
+	for _, r := range results {
+		for _, row := range r.Series {
+			col := -1
+			for i, c := range row.Columns {
+				if c == "time" {
+					col = i
+					break
+				}
+			}
+			if col < 0 {
+				continue
+			}
+			for _, vs := range row.Values {
+				s, ok := vs[col].(string)
+				if !ok {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339Nano, s)
+				if err != nil {
+					return err
+				}
+				vs[col] = t.UnixNano() / int64(unit)
+			}
+		}
+	}
+	return nil
+}
+
+// health checks that the InfluxDB 2.x source is healthy.
+func (c *Client) health(ctx context.Context, u *url.URL) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	req, err := c.newV2Request(ctx, "GET", u, "/health", nil, nil)
+	if err != nil {
+		return err
+	}
+	tracing.InjectToHTTPRequest(span, req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkV2Response(resp, http.StatusOK)
+}
+
+// newV2Request returns an authorized request of the path of the InfluxDB
+// 2.x source.
+func (c *Client) newV2Request(ctx context.Context, method string, u *url.URL, path string, params url.Values, body io.Reader) (*http.Request, error) {
+	ru := *u
+	ru.Path = path
+	ru.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(method, ru.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Authorizer != nil {
+		if err := c.Authorizer.Set(req); err != nil {
+			return nil, err
+		}
+	}
+	return req.WithContext(ctx), nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	hc := &http.Client{}
+	if c.InsecureSkipVerify {
+		hc.Transport = skipVerifyTransport
+	} else {
+		hc.Transport = defaultTransport
+	}
+	return hc
+}
+
+// checkV2Response returns an error with the message of the InfluxDB 2.x
+// error of the response if it does not have the status code.
+func checkV2Response(resp *http.Response, code int) error {
+	if resp.StatusCode == code {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var e struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Message == "" {
+		e.Message = string(body)
+	}
+	return fmt.Errorf("received status code %d from server: err: %s", resp.StatusCode, e.Message)
+}
+
+// dbrpMappingService finds the buckets of the databases and retention
+// policies of InfluxQL queries in the dbrp mappings of an InfluxDB 2.x
+// source. The mappings of the source cannot be changed through it.
+type dbrpMappingService struct {
+	c *Client
+}
+
+var _ influxdb.DBRPMappingService = (*dbrpMappingService)(nil)
+
+type dbrpMappingsResponse struct {
+	DBRPs []*influxdb.DBRPMappingV2 `json:"dbrps"`
+}
+
+// FindBy returns the mapping of the database and retention policy.
+func (s *dbrpMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	return s.Find(ctx, influxdb.DBRPMappingFilter{
+		Cluster:         &cluster,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+}
+
+// Find returns the first mapping that matches the filter.
+func (s *dbrpMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	ms, _, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		var db, rp string
+		if filter.Database != nil {
+			db = *filter.Database
+		}
+		if filter.RetentionPolicy != nil {
+			rp = *filter.RetentionPolicy
+		}
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("no bucket of the InfluxDB 2.x source is mapped to database %q and retention policy %q", db, rp),
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the mappings that match the filter. Only the default
+// mappings of the database are found when the filter asks for them; the
+// mappings of a named retention policy are found whether they are the
+// default or not.
+func (s *dbrpMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	params := url.Values{"org": {s.c.Org}}
+	if filter.Database != nil {
+		params.Set("db", *filter.Database)
+	}
+	if filter.RetentionPolicy != nil && *filter.RetentionPolicy != "" {
+		params.Set("rp", *filter.RetentionPolicy)
+	}
+	if filter.Default != nil && *filter.Default {
+		params.Set("default", strconv.FormatBool(true))
+	}
+
+	req, err := s.c.newV2Request(ctx, "GET", s.c.URL, "/api/v2/dbrps", params, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := s.c.httpClient().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if err := checkV2Response(resp, http.StatusOK); err != nil {
+		return nil, 0, err
+	}
+
+	var dbrps dbrpMappingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dbrps); err != nil {
+		return nil, 0, err
+	}
+	ms := make([]*influxdb.DBRPMapping, 0, len(dbrps.DBRPs))
+	for _, m := range dbrps.DBRPs {
+		ms = append(ms, &influxdb.DBRPMapping{
+			Database:        m.Database,
+			RetentionPolicy: m.RetentionPolicy,
+			Default:         m.Default,
+			OrganizationID:  m.OrganizationID,
+			BucketID:        m.BucketID,
+		})
+	}
+	return ms, len(ms), nil
+}
+
+// Create is not supported, as the mappings of the source are read-only.
+func (s *dbrpMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	return errors.New("dbrp mappings of InfluxDB 2.x sources cannot be created by chronograf")
+}
+
+// Delete is not supported, as the mappings of the source are read-only.
+func (s *dbrpMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	return errors.New("dbrp mappings of InfluxDB 2.x sources cannot be deleted by chronograf")
+}
@@ -0,0 +1,108 @@
+package influx_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/influx"
+)
+
+func Test_Influx_V2TranspilesQueries(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Token my-token" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		if got := r.URL.Query().Get("org"); got != "my-org" {
+			t.Errorf("unexpected org %q", got)
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/dbrps":
+			if db := r.URL.Query().Get("db"); db != "telegraf" {
+				t.Errorf("unexpected database %q", db)
+			}
+			rw.Write([]byte(`{"dbrps":[{"id":"0000000000000003","orgID":"0000000000000001","bucketID":"0000000000000002","database":"telegraf","retentionPolicy":"autogen","default":true}]}`))
+		case "/api/v2/query":
+			var req struct {
+				Query string `json:"query"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			if !strings.Contains(req.Query, `from(bucketID: "0000000000000002")`) {
+				t.Errorf("expected the query to read the mapped bucket, got:\n%s", req.Query)
+			}
+			rw.Write([]byte("#datatype,string,long,string,dateTime:RFC3339,double\r\n" +
+				"#group,false,false,true,false,false\r\n" +
+				"#default,0,,,,\r\n" +
+				",result,table,_measurement,_time,mean\r\n" +
+				",,0,cpu,2020-01-01T00:00:00Z,1.5\r\n" +
+				",,0,cpu,2020-01-01T00:01:00Z,2.5\r\n" +
+				"\r\n"))
+		default:
+			t.Errorf("unexpected request of %s", r.URL.Path)
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	series := &influx.Client{
+		Logger: &chronograf.NoopLogger{},
+	}
+	if err := series.Connect(context.Background(), &chronograf.Source{
+		Type:     chronograf.InfluxDBv2,
+		URL:      ts.URL,
+		Username: "my-org",
+		Password: "my-token",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := series.Query(context.Background(), chronograf.Query{
+		Command: `SELECT mean("usage_user") FROM "telegraf"."autogen"."cpu" WHERE time > now() - 1h GROUP BY time(1m)`,
+	})
+	if err != nil {
+		t.Fatal("Expected no error but was", err)
+	}
+	got, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"statement_id":0,"series":[{"name":"cpu","columns":["time","mean"],"values":[[1577836800000,1.5],[1577836860000,2.5]]}]}]`
+	if string(got) != want {
+		t.Errorf("unexpected results\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func Test_Influx_V2RejectsUnsupportedStatements(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request of %s", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	series := &influx.Client{
+		Logger: &chronograf.NoopLogger{},
+	}
+	if err := series.Connect(context.Background(), &chronograf.Source{
+		Type:     chronograf.InfluxDBv2,
+		URL:      ts.URL,
+		Username: "my-org",
+		Password: "my-token",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := series.Query(context.Background(), chronograf.Query{
+		Command: `SHOW MEASUREMENTS ON "telegraf"`,
+	})
+	if err == nil || err.Error() != "SHOW MEASUREMENTS statements are not supported by InfluxDB 2.x sources" {
+		t.Errorf("expected an unsupported statement error, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+var _ chronograf.LayoutPacksStore = &LayoutPacksStore{}
+
+type LayoutPacksStore struct {
+	AddF    func(ctx context.Context, pack chronograf.LayoutPack) (chronograf.LayoutPack, error)
+	AllF    func(ctx context.Context) ([]chronograf.LayoutPack, error)
+	DeleteF func(ctx context.Context, pack chronograf.LayoutPack) error
+	GetF    func(ctx context.Context, id string) (chronograf.LayoutPack, error)
+	UpdateF func(ctx context.Context, pack chronograf.LayoutPack) error
+}
+
+func (s *LayoutPacksStore) Add(ctx context.Context, pack chronograf.LayoutPack) (chronograf.LayoutPack, error) {
+	return s.AddF(ctx, pack)
+}
+
+func (s *LayoutPacksStore) All(ctx context.Context) ([]chronograf.LayoutPack, error) {
+	return s.AllF(ctx)
+}
+
+func (s *LayoutPacksStore) Delete(ctx context.Context, pack chronograf.LayoutPack) error {
+	return s.DeleteF(ctx, pack)
+}
+
+func (s *LayoutPacksStore) Get(ctx context.Context, id string) (chronograf.LayoutPack, error) {
+	return s.GetF(ctx, id)
+}
+
+func (s *LayoutPacksStore) Update(ctx context.Context, pack chronograf.LayoutPack) error {
+	return s.UpdateF(ctx, pack)
+}
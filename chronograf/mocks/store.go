@@ -12,6 +12,7 @@ type Store struct {
 	MappingsStore           chronograf.MappingsStore
 	ServersStore            chronograf.ServersStore
 	LayoutsStore            chronograf.LayoutsStore
+	LayoutPacksStore        chronograf.LayoutPacksStore
 	UsersStore              chronograf.UsersStore
 	DashboardsStore         chronograf.DashboardsStore
 	OrganizationsStore      chronograf.OrganizationsStore
@@ -31,6 +32,10 @@ func (s *Store) Layouts(ctx context.Context) chronograf.LayoutsStore {
 	return s.LayoutsStore
 }
 
+func (s *Store) LayoutPacks(ctx context.Context) chronograf.LayoutPacksStore {
+	return s.LayoutPacksStore
+}
+
 func (s *Store) Users(ctx context.Context) chronograf.UsersStore {
 	return s.UsersStore
 }
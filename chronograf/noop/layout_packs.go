@@ -0,0 +1,33 @@
+package noop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+// ensure LayoutPacksStore implements chronograf.LayoutPacksStore
+var _ chronograf.LayoutPacksStore = &LayoutPacksStore{}
+
+type LayoutPacksStore struct{}
+
+func (s *LayoutPacksStore) All(context.Context) ([]chronograf.LayoutPack, error) {
+	return nil, fmt.Errorf("no layout packs found")
+}
+
+func (s *LayoutPacksStore) Add(context.Context, chronograf.LayoutPack) (chronograf.LayoutPack, error) {
+	return chronograf.LayoutPack{}, fmt.Errorf("failed to add layout pack")
+}
+
+func (s *LayoutPacksStore) Delete(context.Context, chronograf.LayoutPack) error {
+	return fmt.Errorf("failed to delete layout pack")
+}
+
+func (s *LayoutPacksStore) Get(ctx context.Context, ID string) (chronograf.LayoutPack, error) {
+	return chronograf.LayoutPack{}, chronograf.ErrLayoutPackNotFound
+}
+
+func (s *LayoutPacksStore) Update(context.Context, chronograf.LayoutPack) error {
+	return fmt.Errorf("failed to update layout pack")
+}
@@ -20,6 +20,7 @@ type cookie struct {
 	Name       string        // Name is the name of the cookie stored on the browser
 	Lifespan   time.Duration // Lifespan is the expiration date of the cookie. 0 means session cookie
 	Inactivity time.Duration // Inactivity is the length of time a token is valid if there is no activity
+	Lifetime   time.Duration // Lifetime is the length of time a token is valid regardless of activity. 0 means Lifespan
 	Now        func() time.Time
 	Tokens     Tokenizer
 }
@@ -46,6 +47,46 @@ func NewCookieJWT(secret string, lifespan time.Duration) Authenticator {
 	}
 }
 
+// NewCookieJWTWithTimeouts creates an Authenticator that uses cookies for auth.
+// Users are logged out after inactivity without any request, and after
+// lifetime since they logged in regardless of their activity. A lifetime of 0
+// means the lifespan of the cookie.
+func NewCookieJWTWithTimeouts(secret string, lifespan, inactivity, lifetime time.Duration) Authenticator {
+	if inactivity <= 0 {
+		inactivity = DefaultInactivityDuration
+	}
+	return &cookie{
+		Name:       DefaultCookieName,
+		Lifespan:   lifespan,
+		Inactivity: inactivity,
+		Lifetime:   lifetime,
+		Now:        DefaultNowTime,
+		Tokens: &JWT{
+			Secret: secret,
+			Now:    DefaultNowTime,
+		},
+	}
+}
+
+// lifetime returns the length of time a token is valid regardless of activity,
+// 0 meaning that it can be extended indefinitely.
+func (c *cookie) lifetime() time.Duration {
+	if c.Lifetime > 0 {
+		return c.Lifetime
+	}
+	return c.Lifespan
+}
+
+// limitLifetime ensures that the principal does not expire after its lifetime.
+func (c *cookie) limitLifetime(p Principal) Principal {
+	if lifetime := c.lifetime(); lifetime > 0 {
+		if end := p.IssuedAt.Add(lifetime); p.ExpiresAt.After(end) {
+			p.ExpiresAt = end
+		}
+	}
+	return p
+}
+
 // Validate returns Principal of the Cookie if the Token is valid.
 func (c *cookie) Validate(ctx context.Context, r *http.Request) (Principal, error) {
 	cookie, err := r.Cookie(c.Name)
@@ -53,17 +94,21 @@ func (c *cookie) Validate(ctx context.Context, r *http.Request) (Principal, erro
 		return Principal{}, ErrAuthentication
 	}
 
-	return c.Tokens.ValidPrincipal(ctx, Token(cookie.Value), c.Lifespan)
+	// Tokens are never refreshed beyond their lifetime, so the ones lasting
+	// longer are invalid.
+	return c.Tokens.ValidPrincipal(ctx, Token(cookie.Value), c.lifetime())
 }
 
 // Extend will extend the lifetime of the Token by the Inactivity time.  Assumes
 // Principal is already valid.
 func (c *cookie) Extend(ctx context.Context, w http.ResponseWriter, p Principal) (Principal, error) {
-	// Refresh the token by extending its life another Inactivity duration
+	// Refresh the token by extending its life another Inactivity duration,
+	// up to its lifetime
 	p, err := c.Tokens.ExtendedPrincipal(ctx, p, c.Inactivity)
 	if err != nil {
 		return Principal{}, ErrAuthentication
 	}
+	p = c.limitLifetime(p)
 
 	// Creating a new token with the extended principal
 	token, err := c.Tokens.Create(ctx, p)
@@ -85,10 +130,11 @@ func (c *cookie) Extend(ctx context.Context, w http.ResponseWriter, p Principal)
 // a token with cookie.Duration of life to be stored as the cookie's value.
 func (c *cookie) Authorize(ctx context.Context, w http.ResponseWriter, p Principal) error {
 	// Principal will be issued at Now() and will expire
-	// c.Inactivity into the future
+	// c.Inactivity into the future, or at the end of its lifetime
 	now := c.Now()
 	p.IssuedAt = now
 	p.ExpiresAt = now.Add(c.Inactivity)
+	p = c.limitLifetime(p)
 
 	token, err := c.Tokens.Create(ctx, p)
 	if err != nil {
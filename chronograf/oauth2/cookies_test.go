@@ -294,3 +294,56 @@ func TestCookieExtend(t *testing.T) {
 		})
 	}
 }
+
+func TestCookieTimeouts(t *testing.T) {
+	now := time.Date(2019, 11, 1, 3, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	auth := NewCookieJWTWithTimeouts("secret", 720*time.Hour, 15*time.Minute, time.Hour).(*cookie)
+	auth.Now = clock
+	auth.Tokens.(*JWT).Now = clock
+
+	// request authenticates with the cookie set by the last response and
+	// refreshes it.
+	var w *httptest.ResponseRecorder
+	request := func() error {
+		r := httptest.NewRequest("GET", "http://example.com", nil)
+		for _, c := range w.Result().Cookies() {
+			r.AddCookie(c)
+		}
+		p, err := auth.Validate(context.Background(), r)
+		if err != nil {
+			return err
+		}
+		w = httptest.NewRecorder()
+		_, err = auth.Extend(context.Background(), w, p)
+		return err
+	}
+
+	w = httptest.NewRecorder()
+	if err := auth.Authorize(context.Background(), w, Principal{Subject: "biff"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Activity keeps the user logged in past the idle timeout.
+	for i := 0; i < 4; i++ {
+		now = now.Add(14 * time.Minute)
+		if err := request(); err != nil {
+			t.Fatalf("request after %d minutes failed: %v", 14*(i+1), err)
+		}
+	}
+	// Tokens are not refreshed beyond the lifetime.
+	now = now.Add(5 * time.Minute)
+	if err := request(); err == nil {
+		t.Fatal("expected authentication to expire after its lifetime")
+	}
+
+	// Users are logged out after the idle timeout.
+	w = httptest.NewRecorder()
+	if err := auth.Authorize(context.Background(), w, Principal{Subject: "biff"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(16 * time.Minute)
+	if err := request(); err == nil {
+		t.Fatal("expected authentication to expire after the idle timeout")
+	}
+}
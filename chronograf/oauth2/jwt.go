@@ -52,6 +52,8 @@ type Claims struct {
 	// `family_name`, and `middle_name` in the iana link provided above). I should add the discalimer
 	// I'm currently sick, so this thought process might be off.
 	Group string `json:"grp,omitempty"`
+	// Scheme is the authentication scheme of the principal when it is not OAuth2.
+	Scheme string `json:"scm,omitempty"`
 }
 
 // Valid adds an empty subject test to the StandardClaims checks.
@@ -200,6 +202,7 @@ func (j *JWT) ValidClaims(jwtToken Token, lifespan time.Duration, alg gojwt.Keyf
 		Issuer:       claims.Issuer,
 		Organization: claims.Organization,
 		Group:        claims.Group,
+		Scheme:       claims.Scheme,
 		ExpiresAt:    exp,
 		IssuedAt:     iat,
 	}, nil
@@ -241,6 +244,7 @@ func (j *JWT) Create(ctx context.Context, user Principal) (Token, error) {
 		},
 		Organization: user.Organization,
 		Group:        user.Group,
+		Scheme:       user.Scheme,
 	}
 	token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims)
 	// Sign and get the complete encoded token as a string using the secret
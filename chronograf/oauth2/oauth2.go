@@ -35,6 +35,7 @@ type Principal struct {
 	Issuer       string
 	Organization string
 	Group        string
+	Scheme       string // Scheme used to authenticate; OAuth2 if empty
 	ExpiresAt    time.Time
 	IssuedAt     time.Time
 }
@@ -0,0 +1,600 @@
+package oauth2
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/influxdata/influxdb/chronograf"
+	"golang.org/x/oauth2"
+)
+
+var (
+	_ Provider    = &SAML{}
+	_ MetadataMux = &SAMLMux{}
+)
+
+// SAML 2.0 identifiers, see http://docs.oasis-open.org/security/saml/v2.0/.
+const (
+	samlAssertionNamespace  = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlProtocolNamespace   = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlSuccess             = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer              = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlHTTPPostBinding     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlNameIDUnspecified   = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	samlMetadataContentType = "application/samlmetadata+xml"
+)
+
+// SAMLScheme is the authentication scheme of principals authenticated by a
+// SAML identity provider.
+const SAMLScheme = "saml"
+
+// SAMLClockSkew is the tolerated difference between the clocks of Chronograf
+// and of the identity provider when validating assertions.
+const SAMLClockSkew = 3 * time.Minute
+
+// MetadataMux is a Mux publishing metadata describing Chronograf to its
+// identity provider.
+type MetadataMux interface {
+	Mux
+	Metadata() http.Handler
+}
+
+// SAML is a SAML 2.0 identity provider. Chronograf acts as the service
+// provider: users are sent to the identity provider with an AuthnRequest
+// (HTTP-Redirect binding) and come back with a signed Response (HTTP-POST
+// binding). SAML implements Provider so that it is configured and mapped like
+// the OAuth2 providers, but it has no OAuth2 configuration.
+type SAML struct {
+	PageName        string              // PageName is the name of the provider; "saml" by default
+	EntityID        string              // EntityID identifies Chronograf to the identity provider
+	ACSURL          string              // ACSURL is the URL of the assertion consumer service (the callback)
+	IDPSSOURL       string              // IDPSSOURL is the single sign-on URL of the identity provider
+	IDPEntityID     string              // IDPEntityID is the required issuer of assertions, if set
+	IDPCertificates []*x509.Certificate // IDPCertificates are the certificates of the identity provider signing responses
+	NameAttribute   string              // NameAttribute identifies users; the NameID of the subject is used if empty
+	GroupAttribute  string              // GroupAttribute holds the groups of users
+	Logger          chronograf.Logger
+}
+
+// ParseSAMLCertificates parses the PEM encoded certificates of an identity
+// provider.
+func ParseSAMLCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// ID returns the entity ID of Chronograf.
+func (s *SAML) ID() string {
+	return s.EntityID
+}
+
+// Secret is empty as responses are authenticated by their signature.
+func (s *SAML) Secret() string {
+	return ""
+}
+
+// Scopes is not used by SAML.
+func (s *SAML) Scopes() []string {
+	return nil
+}
+
+// Config is not used by SAML.
+func (s *SAML) Config() *oauth2.Config {
+	return &oauth2.Config{}
+}
+
+// PrincipalID is not used by SAML as principals are read from assertions.
+func (s *SAML) PrincipalID(provider *http.Client) (string, error) {
+	return "", errors.New("SAML principals are read from assertions")
+}
+
+// Group is not used by SAML as groups are read from assertions.
+func (s *SAML) Group(provider *http.Client) (string, error) {
+	return "", errors.New("SAML groups are read from assertions")
+}
+
+// Name returns the name of the provider.
+func (s *SAML) Name() string {
+	if s.PageName == "" {
+		return "saml"
+	}
+	return s.PageName
+}
+
+type samlIssuer struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	Value   string   `xml:",chardata"`
+}
+
+type samlNameIDPolicy struct {
+	XMLName     xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol NameIDPolicy"`
+	AllowCreate bool     `xml:",attr"`
+}
+
+type samlAuthnRequest struct {
+	XMLName                     xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+	ID                          string   `xml:",attr"`
+	Version                     string   `xml:",attr"`
+	IssueInstant                string   `xml:",attr"`
+	Destination                 string   `xml:",attr"`
+	ProtocolBinding             string   `xml:",attr"`
+	AssertionConsumerServiceURL string   `xml:",attr"`
+	Issuer                      samlIssuer
+	NameIDPolicy                samlNameIDPolicy
+}
+
+// AuthnRequestURL returns the URL sending the user to the identity provider
+// with an AuthnRequest encoded with the HTTP-Redirect binding.
+func (s *SAML) AuthnRequestURL(id, relayState string, now time.Time) (string, error) {
+	req := samlAuthnRequest{
+		ID:                          id,
+		Version:                     "2.0",
+		IssueInstant:                now.UTC().Format(time.RFC3339),
+		Destination:                 s.IDPSSOURL,
+		ProtocolBinding:             samlHTTPPostBinding,
+		AssertionConsumerServiceURL: s.ACSURL,
+		Issuer:                      samlIssuer{Value: s.EntityID},
+		NameIDPolicy:                samlNameIDPolicy{AllowCreate: true},
+	}
+	data, err := xml.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return "", err
+	}
+	if err := fw.Close(); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(s.IDPSSOURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	q.Set("RelayState", relayState)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type samlEndpoint struct {
+	Binding   string `xml:",attr"`
+	Location  string `xml:",attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault bool   `xml:"isDefault,attr"`
+}
+
+type samlSPSSODescriptor struct {
+	AuthnRequestsSigned        bool           `xml:",attr"`
+	WantAssertionsSigned       bool           `xml:",attr"`
+	ProtocolSupportEnumeration string         `xml:"protocolSupportEnumeration,attr"`
+	NameIDFormat               string         `xml:"urn:oasis:names:tc:SAML:2.0:metadata NameIDFormat"`
+	AssertionConsumerService   []samlEndpoint `xml:"urn:oasis:names:tc:SAML:2.0:metadata AssertionConsumerService"`
+}
+
+type samlEntityDescriptor struct {
+	XMLName         xml.Name            `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID        string              `xml:"entityID,attr"`
+	SPSSODescriptor samlSPSSODescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata SPSSODescriptor"`
+}
+
+// Metadata returns the metadata of Chronograf as a service provider, to be
+// registered with the identity provider.
+func (s *SAML) Metadata() ([]byte, error) {
+	md := samlEntityDescriptor{
+		EntityID: s.EntityID,
+		SPSSODescriptor: samlSPSSODescriptor{
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: samlProtocolNamespace,
+			NameIDFormat:               samlNameIDUnspecified,
+			AssertionConsumerService: []samlEndpoint{{
+				Binding:   samlHTTPPostBinding,
+				Location:  s.ACSURL,
+				Index:     0,
+				IsDefault: true,
+			}},
+		},
+	}
+	data, err := xml.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// SAMLAssertion is the identity of a user asserted by the identity provider.
+type SAMLAssertion struct {
+	NameID     string
+	Attributes map[string][]string // Attributes are keyed by name and friendly name
+}
+
+// Principal returns the principal asserted by the identity provider.
+func (s *SAML) Principal(a *SAMLAssertion) (Principal, error) {
+	id := a.NameID
+	if s.NameAttribute != "" {
+		values := a.Attributes[s.NameAttribute]
+		if len(values) == 0 {
+			return Principal{}, fmt.Errorf("assertion has no %s attribute", s.NameAttribute)
+		}
+		id = values[0]
+	}
+	if id == "" {
+		return Principal{}, errors.New("assertion does not identify the user")
+	}
+
+	var group string
+	if s.GroupAttribute != "" {
+		group = strings.Join(a.Attributes[s.GroupAttribute], ",")
+	}
+	return Principal{
+		Subject: id,
+		Issuer:  s.Name(),
+		Group:   group,
+		Scheme:  SAMLScheme,
+	}, nil
+}
+
+// ValidateResponse validates a Response of the identity provider to the
+// AuthnRequest with the ID and returns its assertion. The assertion, or the
+// response enveloping it, must be signed by the identity provider. Values
+// are only read from the signed elements.
+func (s *SAML) ValidateResponse(data []byte, requestID string, now time.Time) (*SAMLAssertion, error) {
+	resp, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	if !xmlIs(resp, samlProtocolNamespace, "Response") {
+		return nil, errors.New("document is not a SAML response")
+	}
+
+	// Signatures reference elements by ID: duplicates could make a signature
+	// cover an element other than the one validated.
+	ids := map[string]bool{}
+	var duplicate bool
+	xmlWalk(resp, func(e *etree.Element) {
+		if id := xmlAttr(e, "ID"); id != "" {
+			duplicate = duplicate || ids[id]
+			ids[id] = true
+		}
+	})
+	if duplicate {
+		return nil, errors.New("response has duplicate IDs")
+	}
+
+	// The assertion is read from the signed response, if it is signed, so a
+	// signature covers it whether it is on the response or on the assertion
+	// itself.
+	signedResp, err := verifySignature(resp, s.IDPCertificates, now)
+	switch err {
+	case nil:
+		resp = signedResp
+	case errNotSigned:
+	default:
+		return nil, err
+	}
+	responseSigned := err == nil
+
+	if d := xmlAttr(resp, "Destination"); d != "" && d != s.ACSURL {
+		return nil, fmt.Errorf("response is destined to %s", d)
+	}
+	if xmlAttr(resp, "InResponseTo") != requestID {
+		return nil, errors.New("response does not answer the authentication request")
+	}
+	var status string
+	if st := xmlChild(resp, samlProtocolNamespace, "Status"); st != nil {
+		if code := xmlChild(st, samlProtocolNamespace, "StatusCode"); code != nil {
+			status = xmlAttr(code, "Value")
+		}
+	}
+	if status != samlSuccess {
+		return nil, fmt.Errorf("authentication failed with status %q", status)
+	}
+	if issuer := xmlChild(resp, samlAssertionNamespace, "Issuer"); issuer != nil && s.IDPEntityID != "" && xmlText(issuer) != s.IDPEntityID {
+		return nil, fmt.Errorf("response is issued by %s", xmlText(issuer))
+	}
+
+	assertions := xmlChildren(resp, samlAssertionNamespace, "Assertion")
+	if len(assertions) != 1 {
+		if xmlChild(resp, samlAssertionNamespace, "EncryptedAssertion") != nil {
+			return nil, errors.New("encrypted assertions are not supported")
+		}
+		return nil, fmt.Errorf("expected one assertion, found %d", len(assertions))
+	}
+	assertion := assertions[0]
+	signedAssertion, err := verifySignature(assertion, s.IDPCertificates, now)
+	switch {
+	case err == nil:
+		assertion = signedAssertion
+	case err != errNotSigned:
+		return nil, err
+	case !responseSigned:
+		return nil, errors.New("assertion is not signed")
+	}
+
+	if s.IDPEntityID != "" {
+		issuer := xmlChild(assertion, samlAssertionNamespace, "Issuer")
+		if issuer == nil || xmlText(issuer) != s.IDPEntityID {
+			return nil, errors.New("assertion is not issued by the identity provider")
+		}
+	}
+	if err := s.validateConditions(assertion, now); err != nil {
+		return nil, err
+	}
+
+	subject := xmlChild(assertion, samlAssertionNamespace, "Subject")
+	if subject == nil {
+		return nil, errors.New("assertion has no subject")
+	}
+	if err := s.validateSubjectConfirmation(subject, requestID, now); err != nil {
+		return nil, err
+	}
+
+	a := &SAMLAssertion{Attributes: map[string][]string{}}
+	if nameID := xmlChild(subject, samlAssertionNamespace, "NameID"); nameID != nil {
+		a.NameID = strings.TrimSpace(xmlText(nameID))
+	}
+	for _, stmt := range xmlChildren(assertion, samlAssertionNamespace, "AttributeStatement") {
+		for _, attr := range xmlChildren(stmt, samlAssertionNamespace, "Attribute") {
+			var values []string
+			for _, v := range xmlChildren(attr, samlAssertionNamespace, "AttributeValue") {
+				values = append(values, strings.TrimSpace(xmlText(v)))
+			}
+			for _, name := range []string{xmlAttr(attr, "Name"), xmlAttr(attr, "FriendlyName")} {
+				if name != "" {
+					a.Attributes[name] = append(a.Attributes[name], values...)
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
+// validateConditions checks the validity period and the audience of the
+// assertion.
+func (s *SAML) validateConditions(assertion *etree.Element, now time.Time) error {
+	conditions := xmlChild(assertion, samlAssertionNamespace, "Conditions")
+	if conditions == nil {
+		return errors.New("assertion has no conditions")
+	}
+	if err := validatePeriod(conditions, now); err != nil {
+		return err
+	}
+	for _, restriction := range xmlChildren(conditions, samlAssertionNamespace, "AudienceRestriction") {
+		found := false
+		for _, audience := range xmlChildren(restriction, samlAssertionNamespace, "Audience") {
+			found = found || strings.TrimSpace(xmlText(audience)) == s.EntityID
+		}
+		if !found {
+			return errors.New("assertion is not intended for this service provider")
+		}
+	}
+	return nil
+}
+
+// validateSubjectConfirmation checks that the subject can be confirmed as
+// the bearer of the assertion.
+func (s *SAML) validateSubjectConfirmation(subject *etree.Element, requestID string, now time.Time) error {
+	for _, sc := range xmlChildren(subject, samlAssertionNamespace, "SubjectConfirmation") {
+		if xmlAttr(sc, "Method") != samlBearer {
+			continue
+		}
+		data := xmlChild(sc, samlAssertionNamespace, "SubjectConfirmationData")
+		if data == nil || xmlAttr(data, "NotOnOrAfter") == "" || xmlAttr(data, "Recipient") != s.ACSURL {
+			continue
+		}
+		if irt := xmlAttr(data, "InResponseTo"); irt != "" && irt != requestID {
+			continue
+		}
+		if validatePeriod(data, now) == nil {
+			return nil
+		}
+	}
+	return errors.New("assertion has no valid bearer subject confirmation")
+}
+
+// validatePeriod checks the NotBefore and NotOnOrAfter attributes of the
+// element.
+func validatePeriod(e *etree.Element, now time.Time) error {
+	if v := xmlAttr(e, "NotBefore"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return err
+		}
+		if now.Add(SAMLClockSkew).Before(t) {
+			return fmt.Errorf("%s is not valid before %s", e.Tag, v)
+		}
+	}
+	if v := xmlAttr(e, "NotOnOrAfter"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return err
+		}
+		if !now.Add(-SAMLClockSkew).Before(t) {
+			return fmt.Errorf("%s expired at %s", e.Tag, v)
+		}
+	}
+	return nil
+}
+
+// NewSAMLMux constructs a Mux handling SAML 2.0 authentication with the
+// provider.
+func NewSAMLMux(p *SAML, a Authenticator, t Tokenizer, basepath string, l chronograf.Logger) *SAMLMux {
+	return &SAMLMux{
+		Provider:   p,
+		Auth:       a,
+		Tokens:     t,
+		SuccessURL: path.Join(basepath, "/"),
+		FailureURL: path.Join(basepath, "/login"),
+		Now:        DefaultNowTime,
+		Logger:     l,
+	}
+}
+
+// SAMLMux services a SAML 2.0 authentication with an identity provider and
+// stores the resultant token in the user's browser as a cookie, like AuthMux.
+// The ID of the AuthnRequest is kept in the RelayState as a token, so that
+// any Chronograf instance can validate the response.
+type SAMLMux struct {
+	Provider   *SAML             // Provider is the identity provider
+	Auth       Authenticator     // Auth is used to Authorize after successful authentication and Expire on Logout
+	Tokens     Tokenizer         // Tokens is used to create and validate the RelayState
+	Logger     chronograf.Logger // Logger is used to give some more information about the SAML process
+	SuccessURL string            // SuccessURL is redirect location after successful authorization
+	FailureURL string            // FailureURL is redirect location after authorization failure
+	Now        func() time.Time  // Now returns the current time (for testing)
+}
+
+// Login returns a handler that redirects to the identity provider with an
+// AuthnRequest.
+func (j *SAMLMux) Login() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := j.Logger.
+			WithField("component", "auth").
+			WithField("remote_addr", r.RemoteAddr).
+			WithField("method", r.Method).
+			WithField("url", r.URL)
+
+		// IDs of SAML requests must not start with a digit.
+		k := make([]byte, 20)
+		if _, err := io.ReadFull(rand.Reader, k); err != nil {
+			log.Error("Internal authentication error: ", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		id := "id-" + hex.EncodeToString(k)
+		now := j.Now()
+
+		// The response must be received within 10 minutes.
+		p := Principal{
+			Subject:   id,
+			IssuedAt:  now,
+			ExpiresAt: now.Add(TenMinutes),
+		}
+		token, err := j.Tokens.Create(r.Context(), p)
+		if err != nil {
+			log.Error("Internal authentication error: ", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		u, err := j.Provider.AuthnRequestURL(id, string(token), now)
+		if err != nil {
+			log.Error("Unable to create SAML authentication request: ", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, u, http.StatusTemporaryRedirect)
+	})
+}
+
+// Callback is the assertion consumer service receiving the response of the
+// identity provider with the HTTP-POST binding.
+func (j *SAMLMux) Callback() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := j.Logger.
+			WithField("component", "auth").
+			WithField("remote_addr", r.RemoteAddr).
+			WithField("method", r.Method).
+			WithField("url", r.URL)
+
+		// The RelayState is the token created on login, whose subject is the
+		// ID of the request.
+		state := r.PostFormValue("RelayState")
+		req, err := j.Tokens.ValidPrincipal(r.Context(), Token(state), TenMinutes)
+		if err != nil {
+			log.Error("Invalid SAML relay state received: ", err.Error())
+			http.Redirect(w, r, j.FailureURL, http.StatusSeeOther)
+			return
+		}
+
+		data, err := decodeBase64(r.PostFormValue("SAMLResponse"))
+		if err != nil {
+			log.Error("Unable to decode SAML response: ", err.Error())
+			http.Redirect(w, r, j.FailureURL, http.StatusSeeOther)
+			return
+		}
+		assertion, err := j.Provider.ValidateResponse(data, req.Subject, j.Now())
+		if err != nil {
+			log.Error("Invalid SAML response: ", err.Error())
+			http.Redirect(w, r, j.FailureURL, http.StatusSeeOther)
+			return
+		}
+		p, err := j.Provider.Principal(assertion)
+		if err != nil {
+			log.Error("Unable to get principal from SAML assertion: ", err.Error())
+			http.Redirect(w, r, j.FailureURL, http.StatusSeeOther)
+			return
+		}
+
+		if err := j.Auth.Authorize(r.Context(), w, p); err != nil {
+			log.Error("Unable to get add session to response ", err.Error())
+			http.Redirect(w, r, j.FailureURL, http.StatusSeeOther)
+			return
+		}
+		log.Info("User ", p.Subject, " is authenticated")
+		http.Redirect(w, r, j.SuccessURL, http.StatusSeeOther)
+	})
+}
+
+// Logout handler will expire our authentication cookie and redirect to the
+// successURL. The session of the identity provider is left untouched.
+func (j *SAMLMux) Logout() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		j.Auth.Expire(w)
+		http.Redirect(w, r, j.SuccessURL, http.StatusTemporaryRedirect)
+	})
+}
+
+// Metadata returns a handler serving the metadata of Chronograf as a service
+// provider.
+func (j *SAMLMux) Metadata() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := j.Provider.Metadata()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", samlMetadataContentType)
+		w.Write(data)
+	})
+}
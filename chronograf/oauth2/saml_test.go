@@ -0,0 +1,557 @@
+package oauth2
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/influxdata/influxdb/chronograf"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+const (
+	samlTestACSURL    = "https://chronograf.example.com/oauth/saml/callback"
+	samlTestEntityID  = "https://chronograf.example.com/oauth/saml/metadata"
+	samlTestIDP       = "https://idp.example.com"
+	samlTestRequestID = "id-1234"
+)
+
+// newSAMLTestCertificate returns a key and a self-signed certificate of an
+// identity provider.
+func newSAMLTestCertificate(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    testTime.Add(-time.Hour),
+		NotAfter:     testTime.Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+// signSAMLTestElement signs the element of the document with the ID. The
+// signature follows the issuer of the element.
+func signSAMLTestElement(t *testing.T, key *rsa.PrivateKey, cert *x509.Certificate, doc, id string) string {
+	t.Helper()
+	d := etree.NewDocument()
+	if err := d.ReadFromString(doc); err != nil {
+		t.Fatal(err)
+	}
+	var el *etree.Element
+	xmlWalk(d.Root(), func(e *etree.Element) {
+		if xmlAttr(e, "ID") == id {
+			el = e
+		}
+	})
+
+	// The element is signed on its own, with the namespaces it inherits
+	// declared on it.
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx, err = ctx.SubContext(el); err != nil {
+		t.Fatal(err)
+	}
+	detached, err := etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore{Certificate: [][]byte{cert.Raw}, PrivateKey: key})
+	sig, err := signer.ConstructSignature(detached, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	el.InsertChildAt(xmlChild(el, samlAssertionNamespace, "Issuer").Index()+1, sig)
+
+	signed, err := d.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// samlTestResponse returns a response to the test request whose assertion is
+// valid at now.
+func samlTestResponse(now time.Time, nameID string) string {
+	return fmt.Sprintf(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="r1" Version="2.0" IssueInstant="%[1]s" Destination="%[4]s" InResponseTo="%[5]s">
+  <saml:Issuer>%[6]s</saml:Issuer>
+  <samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>
+  <saml:Assertion xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ID="a1" Version="2.0" IssueInstant="%[1]s">
+    <saml:Issuer>%[6]s</saml:Issuer>
+    <saml:Subject>
+      <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">%[8]s</saml:NameID>
+      <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+        <saml:SubjectConfirmationData NotOnOrAfter="%[3]s" Recipient="%[4]s" InResponseTo="%[5]s"/>
+      </saml:SubjectConfirmation>
+    </saml:Subject>
+    <saml:Conditions NotBefore="%[2]s" NotOnOrAfter="%[3]s">
+      <saml:AudienceRestriction><saml:Audience>%[7]s</saml:Audience></saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="urn:oid:1.3.6.1.4.1.5923.1.5.1.1" FriendlyName="groups">
+        <saml:AttributeValue xsi:type="xs:string">admins</saml:AttributeValue>
+        <saml:AttributeValue xsi:type="xs:string">ops &amp; dev</saml:AttributeValue>
+      </saml:Attribute>
+      <saml:Attribute Name="uid"><saml:AttributeValue xsi:type="xs:string">biff</saml:AttributeValue></saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>
+</samlp:Response>`,
+		now.UTC().Format(time.RFC3339),
+		now.Add(-time.Minute).UTC().Format(time.RFC3339),
+		now.Add(5*time.Minute).UTC().Format(time.RFC3339),
+		samlTestACSURL, samlTestRequestID, samlTestIDP, samlTestEntityID, nameID)
+}
+
+func newSAMLTestProvider(cert *x509.Certificate) *SAML {
+	return &SAML{
+		EntityID:        samlTestEntityID,
+		ACSURL:          samlTestACSURL,
+		IDPSSOURL:       samlTestIDP + "/sso?tenant=1",
+		IDPEntityID:     samlTestIDP,
+		IDPCertificates: []*x509.Certificate{cert},
+		GroupAttribute:  "groups",
+		Logger:          &chronograf.NoopLogger{},
+	}
+}
+
+func TestSAML_ValidateResponse(t *testing.T) {
+	key, cert := newSAMLTestCertificate(t)
+	otherKey, otherCert := newSAMLTestCertificate(t)
+	s := newSAMLTestProvider(cert)
+
+	doc := samlTestResponse(testTime, "biff@example.com")
+	signedAssertion := signSAMLTestElement(t, key, cert, doc, "a1")
+	signedResponse := signSAMLTestElement(t, key, cert, doc, "r1")
+
+	// A forged assertion of another user, with an ID of its own.
+	forged := doc[strings.Index(doc, "<saml:Assertion"):strings.Index(doc, "</samlp:Response>")]
+	forged = strings.Replace(strings.Replace(forged, `ID="a1"`, `ID="a2"`, 1), "biff@example.com", "admin@example.com", 1)
+
+	// The signature of the response is moved into the assertion, where it
+	// references an element other than the assertion.
+	misplaced := etree.NewDocument()
+	if err := misplaced.ReadFromString(signedResponse); err != nil {
+		t.Fatal(err)
+	}
+	misplacedSig := xmlChild(misplaced.Root(), xmldsigNamespace, "Signature")
+	misplaced.Root().RemoveChild(misplacedSig)
+	misplaced.Root().FindElement("./Assertion").AddChild(misplacedSig)
+	misplacedResponse, err := misplaced.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The identity provider signed the NameID of a user of another domain,
+	// which a comment truncates if it is read up to the comment.
+	commented := strings.Replace(
+		signSAMLTestElement(t, key, cert, samlTestResponse(testTime, "biff@example.com.evil.com"), "a1"),
+		"biff@example.com.evil.com", "biff@example.com<!---->.evil.com", 1)
+
+	tests := []struct {
+		name        string
+		response    string
+		requestID   string
+		now         time.Time
+		wantErr     string
+		wantSubject string
+	}{
+		{
+			name:     "signed assertion",
+			response: signedAssertion,
+		},
+		{
+			name:     "signed response",
+			response: signedResponse,
+		},
+		{
+			name:     "signed response and assertion",
+			response: signSAMLTestElement(t, key, cert, signedAssertion, "r1"),
+		},
+		{
+			name:     "not signed",
+			response: doc,
+			wantErr:  "assertion is not signed",
+		},
+		{
+			name:     "signed by another key",
+			response: signSAMLTestElement(t, otherKey, otherCert, doc, "a1"),
+			wantErr:  "invalid signature",
+		},
+		{
+			name:     "tampered",
+			response: strings.Replace(signedAssertion, "biff@example.com", "admin@example.com", 1),
+			wantErr:  "invalid signature",
+		},
+		{
+			name: "wrapped",
+			// The signed assertion is hidden in the extensions of the response
+			// and replaced by a forged one with the same ID.
+			response: strings.Replace(
+				strings.Replace(signedAssertion, "biff@example.com", "admin@example.com", 1),
+				"<samlp:Status>",
+				"<samlp:Extensions>"+strings.Split(strings.Split(signedAssertion, "</samlp:Status>")[1], "</samlp:Response>")[0]+"</samlp:Extensions><samlp:Status>", 1),
+			wantErr: "duplicate IDs",
+		},
+		{
+			name:     "duplicate assertion",
+			response: strings.Replace(signedAssertion, "</samlp:Response>", forged+"</samlp:Response>", 1),
+			wantErr:  "expected one assertion, found 2",
+		},
+		{
+			name:     "duplicate assertion in signed response",
+			response: strings.Replace(signedResponse, "</samlp:Response>", forged+"</samlp:Response>", 1),
+			wantErr:  "invalid signature",
+		},
+		{
+			name:     "reference to another element",
+			response: misplacedResponse,
+			wantErr:  "invalid signature",
+		},
+		{
+			name:        "comment in NameID",
+			response:    commented,
+			wantSubject: "biff@example.com.evil.com",
+		},
+		{
+			name:      "other request",
+			response:  signedAssertion,
+			requestID: "id-5678",
+			wantErr:   "does not answer the authentication request",
+		},
+		{
+			name:     "expired",
+			response: signedAssertion,
+			now:      testTime.Add(10 * time.Minute),
+			wantErr:  "expired",
+		},
+		{
+			name:     "not yet valid",
+			response: signedAssertion,
+			now:      testTime.Add(-10 * time.Minute),
+			wantErr:  "not valid before",
+		},
+		{
+			name:     "other audience",
+			response: signSAMLTestElement(t, key, cert, strings.Replace(doc, "<saml:Audience>"+samlTestEntityID, "<saml:Audience>https://other.example.com", 1), "a1"),
+			wantErr:  "not intended for this service provider",
+		},
+		{
+			name:     "other issuer",
+			response: signSAMLTestElement(t, key, cert, strings.Replace(doc, "<saml:Issuer>"+samlTestIDP, "<saml:Issuer>https://evil.example.com", -1), "a1"),
+			wantErr:  "issued by",
+		},
+		{
+			name:     "failed",
+			response: strings.Replace(signedAssertion, "status:Success", "status:Requester", 1),
+			wantErr:  "authentication failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestID, now := tt.requestID, tt.now
+			if requestID == "" {
+				requestID = samlTestRequestID
+			}
+			if now.IsZero() {
+				now = testTime
+			}
+
+			a, err := s.ValidateResponse([]byte(tt.response), requestID, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateResponse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := s.Principal(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Principal{Subject: "biff@example.com", Issuer: "saml", Group: "admins,ops & dev", Scheme: SAMLScheme}
+			if tt.wantSubject != "" {
+				want.Subject = tt.wantSubject
+			}
+			if p != want {
+				t.Errorf("Principal() = %+v, want %+v", p, want)
+			}
+		})
+	}
+}
+
+// TestSAML_ValidateResponse_IdentityProviders validates responses signed by
+// identity providers, and attacks on them. The responses and the signature
+// wrapping attacks are those of the tests of github.com/crewjam/saml.
+func TestSAML_ValidateResponse_IdentityProviders(t *testing.T) {
+	oneLogin := &SAML{
+		EntityID:    "https://29ee6d2e.ngrok.io/saml/metadata",
+		ACSURL:      "https://29ee6d2e.ngrok.io/saml/acs",
+		IDPEntityID: "https://app.onelogin.com/saml/metadata/503983",
+	}
+	oneLoginRequestID := "id-d40c15c104b52691eccf0a2a5c8a15595be75423"
+	oneLoginTime := time.Date(2016, 1, 5, 17, 53, 12, 0, time.UTC)
+	google := &SAML{
+		EntityID:    "https://29ee6d2e.ngrok.io/saml/metadata",
+		ACSURL:      "https://29ee6d2e.ngrok.io/saml/acs",
+		IDPEntityID: "https://accounts.google.com/o/saml2?idpid=C02dfl1r1",
+	}
+	for _, p := range []struct {
+		s    *SAML
+		cert string
+	}{{oneLogin, "saml_onelogin_idp.pem"}, {google, "saml_google_idp.pem"}} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", p.cert))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.s.IDPCertificates, err = ParseSAMLCertificates(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	response := func(name string) string {
+		t.Helper()
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := decodeBase64(string(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(doc)
+	}
+	oneLoginResponse := response("saml_onelogin_response")
+
+	tests := []struct {
+		name       string
+		s          *SAML
+		response   string
+		requestID  string
+		now        time.Time
+		wantNameID string
+		wantErr    string
+	}{
+		{
+			name:       "onelogin",
+			s:          oneLogin,
+			response:   oneLoginResponse,
+			requestID:  oneLoginRequestID,
+			now:        oneLoginTime,
+			wantNameID: "ross@kndr.org",
+		},
+		{
+			name:       "google",
+			s:          google,
+			response:   response("saml_google_response"),
+			requestID:  "id-fd419a5ab0472645427f8e07d87a3a5dd0b2e9a6",
+			now:        time.Date(2016, 1, 5, 16, 55, 39, 0, time.UTC),
+			wantNameID: "ross@octolabs.io",
+		},
+		{
+			name:       "onelogin with a comment in the NameID",
+			s:          oneLogin,
+			response:   strings.Replace(oneLoginResponse, "ross@kndr.org</saml:NameID>", "ross@kndr<!---->.org</saml:NameID>", 1),
+			requestID:  oneLoginRequestID,
+			now:        oneLoginTime,
+			wantNameID: "ross@kndr.org",
+		},
+		{
+			name:      "onelogin tampered",
+			s:         oneLogin,
+			response:  strings.Replace(oneLoginResponse, "ross@kndr.org</saml:NameID>", "admin@kndr.org</saml:NameID>", 1),
+			requestID: oneLoginRequestID,
+			now:       oneLoginTime,
+			wantErr:   "invalid signature",
+		},
+		{
+			name:      "onelogin signed by google",
+			s:         google,
+			response:  oneLoginResponse,
+			requestID: oneLoginRequestID,
+			now:       oneLoginTime,
+			wantErr:   "invalid signature",
+		},
+		{
+			name:      "onelogin wrapped in an evil response",
+			s:         oneLogin,
+			response:  response("saml_onelogin_xsw1_response"),
+			requestID: oneLoginRequestID,
+			now:       oneLoginTime,
+			wantErr:   "duplicate IDs",
+		},
+		{
+			name:      "onelogin wrapped in the signature of an evil response",
+			s:         oneLogin,
+			response:  response("saml_onelogin_xsw2_response"),
+			requestID: oneLoginRequestID,
+			now:       oneLoginTime,
+			wantErr:   "duplicate IDs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.s.ValidateResponse([]byte(tt.response), tt.requestID, tt.now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateResponse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.NameID != tt.wantNameID {
+				t.Errorf("NameID = %q, want %q", a.NameID, tt.wantNameID)
+			}
+		})
+	}
+}
+
+func TestSAML_Principal_NameAttribute(t *testing.T) {
+	s := &SAML{PageName: "okta", NameAttribute: "uid"}
+	p, err := s.Principal(&SAMLAssertion{NameID: "biff@example.com", Attributes: map[string][]string{"uid": {"biff"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Subject != "biff" || p.Issuer != "okta" || p.Group != "" {
+		t.Errorf("unexpected principal %+v", p)
+	}
+	if _, err := s.Principal(&SAMLAssertion{NameID: "biff@example.com"}); err == nil {
+		t.Error("expected error for an assertion without the name attribute")
+	}
+}
+
+func TestSAMLMux(t *testing.T) {
+	key, cert := newSAMLTestCertificate(t)
+	s := newSAMLTestProvider(cert)
+	now := testTime
+
+	var authorized Principal
+	auth := &cookie{
+		Name:       DefaultCookieName,
+		Lifespan:   time.Hour,
+		Inactivity: DefaultInactivityDuration,
+		Now:        func() time.Time { return now },
+		Tokens:     &YesManTokenizer{},
+	}
+	jwt := NewJWT("secret", "")
+	jwt.Now = func() time.Time { return now }
+	m := NewSAMLMux(s, &principalRecorder{Authenticator: auth, principal: &authorized}, jwt, "/chronograf", &chronograf.NoopLogger{})
+	m.Now = func() time.Time { return now }
+
+	// Login redirects to the identity provider with the request.
+	w := httptest.NewRecorder()
+	m.Login().ServeHTTP(w, httptest.NewRequest("GET", "/oauth/saml/login", nil))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("Login returned %d", w.Code)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Host != "idp.example.com" || loc.Query().Get("tenant") != "1" {
+		t.Fatalf("unexpected redirect to %s", loc)
+	}
+	deflated, err := base64.StdEncoding.DecodeString(loc.Query().Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := parseXML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestID := xmlAttr(req, "ID")
+	if !xmlIs(req, samlProtocolNamespace, "AuthnRequest") || xmlAttr(req, "AssertionConsumerServiceURL") != samlTestACSURL || requestID == "" {
+		t.Fatalf("unexpected request %s", data)
+	}
+
+	// The identity provider posts its response to the callback.
+	doc := strings.Replace(samlTestResponse(now, "biff@example.com"), samlTestRequestID, requestID, -1)
+	form := url.Values{
+		"SAMLResponse": {base64.StdEncoding.EncodeToString([]byte(signSAMLTestElement(t, key, cert, doc, "a1")))},
+		"RelayState":   {loc.Query().Get("RelayState")},
+	}
+	r := httptest.NewRequest("POST", "/oauth/saml/callback", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	m.Callback().ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/chronograf" {
+		t.Fatalf("Callback returned %d to %s", w.Code, w.Header().Get("Location"))
+	}
+	if authorized.Subject != "biff@example.com" || authorized.Scheme != SAMLScheme || w.Header().Get("Set-Cookie") == "" {
+		t.Fatalf("unexpected authorization of %+v", authorized)
+	}
+
+	// A response to another request is refused.
+	form.Set("RelayState", "invalid")
+	r = httptest.NewRequest("POST", "/oauth/saml/callback", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	m.Callback().ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/chronograf/login" {
+		t.Fatalf("Callback returned %d to %s", w.Code, w.Header().Get("Location"))
+	}
+
+	// The metadata describes the assertion consumer service.
+	w = httptest.NewRecorder()
+	m.Metadata().ServeHTTP(w, httptest.NewRequest("GET", "/oauth/saml/metadata", nil))
+	md, err := parseXML(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := xmlChild(md, "urn:oasis:names:tc:SAML:2.0:metadata", "SPSSODescriptor")
+	if xmlAttr(md, "entityID") != samlTestEntityID || sp == nil {
+		t.Fatalf("unexpected metadata %s", w.Body)
+	}
+	acs := xmlChild(sp, "urn:oasis:names:tc:SAML:2.0:metadata", "AssertionConsumerService")
+	if acs == nil || xmlAttr(acs, "Location") != samlTestACSURL || xmlAttr(acs, "Binding") != samlHTTPPostBinding {
+		t.Fatalf("unexpected metadata %s", w.Body)
+	}
+}
+
+// principalRecorder records the principal authorized by an Authenticator.
+type principalRecorder struct {
+	Authenticator
+	principal *Principal
+}
+
+func (r *principalRecorder) Authorize(ctx context.Context, w http.ResponseWriter, p Principal) error {
+	*r.principal = p
+	return r.Authenticator.Authorize(ctx, w, p)
+}
@@ -0,0 +1,104 @@
+package oauth2
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// parseXML parses the root element of a document. The document is checked
+// to be well-formed first, as etree does not match end elements with start
+// elements; document type declarations are rejected.
+func parseXML(data []byte) (*etree.Element, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tok.(xml.Directive); ok {
+			return nil, errors.New("xml: directives are not allowed")
+		}
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+	if len(doc.ChildElements()) != 1 {
+		return nil, errors.New("xml: expected one root element")
+	}
+	for _, t := range doc.Child {
+		if c, ok := t.(*etree.CharData); ok && strings.TrimSpace(c.Data) != "" {
+			return nil, errors.New("xml: character data outside of the root element")
+		}
+	}
+	return doc.Root(), nil
+}
+
+// xmlIs returns true if the element has the namespace and local name.
+func xmlIs(e *etree.Element, space, local string) bool {
+	return e.Tag == local && e.NamespaceURI() == space
+}
+
+// xmlChildren returns the child elements having the namespace and local name.
+func xmlChildren(e *etree.Element, space, local string) []*etree.Element {
+	var els []*etree.Element
+	for _, el := range e.ChildElements() {
+		if xmlIs(el, space, local) {
+			els = append(els, el)
+		}
+	}
+	return els
+}
+
+// xmlChild returns the first child element having the namespace and local
+// name, or nil.
+func xmlChild(e *etree.Element, space, local string) *etree.Element {
+	if els := xmlChildren(e, space, local); len(els) > 0 {
+		return els[0]
+	}
+	return nil
+}
+
+// xmlAttr returns the value of the unprefixed attribute. Unlike
+// etree.Element.SelectAttrValue, prefixed attributes with the same local
+// name do not match.
+func xmlAttr(e *etree.Element, name string) string {
+	for _, a := range e.Attr {
+		if a.Space == "" && a.Key == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xmlText returns the character data of the element, excluding the one of
+// its descendants. Unlike etree.Element.Text, it does not stop at comments:
+// comments are not signed, so a comment inserted in a signed value must not
+// truncate it.
+func xmlText(e *etree.Element) string {
+	var sb strings.Builder
+	for _, t := range e.Child {
+		if c, ok := t.(*etree.CharData); ok {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+// xmlWalk calls fn for the element and each of its descendants in document
+// order.
+func xmlWalk(e *etree.Element, fn func(*etree.Element)) {
+	fn(e)
+	for _, el := range e.ChildElements() {
+		xmlWalk(el, fn)
+	}
+}
@@ -0,0 +1,71 @@
+package oauth2
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// xmldsigNamespace is the namespace of XML signatures.
+const xmldsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+// errNotSigned is returned when an element has no enveloped signature.
+var errNotSigned = errors.New("xmldsig: element is not signed")
+
+// verifySignature verifies the enveloped signature of the element against
+// the certificates at now, and returns the signed element: a copy of the
+// element without its signature. Only the returned element is covered by
+// the signature, so values must be read from it rather than from the
+// document.
+func verifySignature(e *etree.Element, certs []*x509.Certificate, now time.Time) (*etree.Element, error) {
+	sigs := xmlChildren(e, xmldsigNamespace, "Signature")
+	switch {
+	case len(sigs) == 0:
+		return nil, errNotSigned
+	case len(sigs) > 1:
+		return nil, errors.New("xmldsig: element has several signatures")
+	}
+
+	// The element is validated on its own, with the namespaces it inherits
+	// from the document declared on it.
+	ctx, err := etreeutils.NSBuildParentContext(e)
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = ctx.SubContext(e); err != nil {
+		return nil, err
+	}
+	el, err := etreeutils.NSDetatch(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signatures identifying their key other than by its certificate are
+	// verified with the certificate of the identity provider.
+	if sig := xmlChild(el, xmldsigNamespace, "Signature"); sig != nil {
+		if keyInfo := xmlChild(sig, xmldsigNamespace, "KeyInfo"); keyInfo != nil && keyInfo.FindElement("./X509Data/X509Certificate") == nil {
+			sig.RemoveChild(keyInfo)
+		}
+	}
+
+	v := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	v.IdAttribute = "ID"
+	v.Clock = dsig.NewFakeClockAt(now)
+	signed, err := v.Validate(el)
+	if err != nil {
+		return nil, fmt.Errorf("xmldsig: invalid signature of %s: %v", e.Tag, err)
+	}
+	return signed, nil
+}
+
+// decodeBase64 decodes base64 content that may be wrapped on several lines.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
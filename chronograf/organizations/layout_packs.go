@@ -0,0 +1,165 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+// ensure that LayoutPacksStore implements chronograf.LayoutPacksStore
+var _ chronograf.LayoutPacksStore = &LayoutPacksStore{}
+
+// LayoutPacksStore facade on a LayoutPacksStore that filters layout packs
+// by organization.
+type LayoutPacksStore struct {
+	store        chronograf.LayoutPacksStore
+	organization string
+}
+
+// NewLayoutPacksStore creates a new LayoutPacksStore from an existing
+// chronograf.LayoutPacksStore and an organization string
+func NewLayoutPacksStore(s chronograf.LayoutPacksStore, org string) *LayoutPacksStore {
+	return &LayoutPacksStore{
+		store:        s,
+		organization: org,
+	}
+}
+
+// All retrieves all layout packs from the underlying LayoutPacksStore and filters them
+// by organization.
+func (s *LayoutPacksStore) All(ctx context.Context) ([]chronograf.LayoutPack, error) {
+	err := validOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := s.store.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters layout packs without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	packs := ps[:0]
+	for _, p := range ps {
+		if p.Organization == s.organization {
+			packs = append(packs, p)
+		}
+	}
+
+	return packs, nil
+}
+
+// Add creates a new LayoutPack in the LayoutPacksStore with pack.Organization set to be the
+// organization from the layout pack store.
+func (s *LayoutPacksStore) Add(ctx context.Context, p chronograf.LayoutPack) (chronograf.LayoutPack, error) {
+	err := validOrganization(ctx)
+	if err != nil {
+		return chronograf.LayoutPack{}, err
+	}
+
+	p.Organization = s.organization
+	return s.store.Add(ctx, p)
+}
+
+// Delete the layout pack from LayoutPacksStore
+func (s *LayoutPacksStore) Delete(ctx context.Context, p chronograf.LayoutPack) error {
+	p, err := s.Get(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, p)
+}
+
+// Get returns a LayoutPack if the id exists and belongs to the organization that is set.
+func (s *LayoutPacksStore) Get(ctx context.Context, id string) (chronograf.LayoutPack, error) {
+	err := validOrganization(ctx)
+	if err != nil {
+		return chronograf.LayoutPack{}, err
+	}
+
+	p, err := s.store.Get(ctx, id)
+	if err != nil {
+		return chronograf.LayoutPack{}, err
+	}
+
+	if p.Organization != s.organization {
+		return chronograf.LayoutPack{}, chronograf.ErrLayoutPackNotFound
+	}
+
+	return p, nil
+}
+
+// Update the layout pack in LayoutPacksStore.
+func (s *LayoutPacksStore) Update(ctx context.Context, p chronograf.LayoutPack) error {
+	if _, err := s.Get(ctx, p.ID); err != nil {
+		return err
+	}
+
+	p.Organization = s.organization
+	return s.store.Update(ctx, p)
+}
+
+// ensure that LayoutsStore implements chronograf.LayoutsStore
+var _ chronograf.LayoutsStore = &LayoutsStore{}
+
+// LayoutsStore is a read-only chronograf.LayoutsStore of the layouts shipped
+// by the layout packs of an organization. Layouts of a pack are changed by
+// updating the pack.
+type LayoutsStore struct {
+	packs *LayoutPacksStore
+}
+
+// NewLayoutsStore creates a new LayoutsStore of the layouts of the packs in
+// an existing chronograf.LayoutPacksStore belonging to the organization.
+func NewLayoutsStore(s chronograf.LayoutPacksStore, org string) *LayoutsStore {
+	return &LayoutsStore{
+		packs: NewLayoutPacksStore(s, org),
+	}
+}
+
+// All returns the layouts of all the packs of the organization.
+func (s *LayoutsStore) All(ctx context.Context) ([]chronograf.Layout, error) {
+	packs, err := s.packs.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	layouts := []chronograf.Layout{}
+	for _, p := range packs {
+		layouts = append(layouts, p.Layouts...)
+	}
+	return layouts, nil
+}
+
+// Add is not supported, layouts are added by updating their pack.
+func (s *LayoutsStore) Add(context.Context, chronograf.Layout) (chronograf.Layout, error) {
+	return chronograf.Layout{}, fmt.Errorf("layouts of layout packs are read-only")
+}
+
+// Delete is not supported, layouts are deleted by updating their pack.
+func (s *LayoutsStore) Delete(context.Context, chronograf.Layout) error {
+	return fmt.Errorf("layouts of layout packs are read-only")
+}
+
+// Get returns the layout with the id from the packs of the organization.
+func (s *LayoutsStore) Get(ctx context.Context, id string) (chronograf.Layout, error) {
+	layouts, err := s.All(ctx)
+	if err != nil {
+		return chronograf.Layout{}, err
+	}
+
+	for _, l := range layouts {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return chronograf.Layout{}, chronograf.ErrLayoutNotFound
+}
+
+// Update is not supported, layouts are updated by updating their pack.
+func (s *LayoutsStore) Update(context.Context, chronograf.Layout) error {
+	return fmt.Errorf("layouts of layout packs are read-only")
+}
@@ -0,0 +1,94 @@
+package organizations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/mocks"
+	"github.com/influxdata/influxdb/chronograf/organizations"
+)
+
+func newLayoutPacksMock() *mocks.LayoutPacksStore {
+	packs := []chronograf.LayoutPack{
+		{
+			ID:           "1",
+			Organization: "1337",
+			Layouts: []chronograf.Layout{
+				{ID: "a", Application: "app", Measurement: "cpu"},
+				{ID: "b", Application: "app", Measurement: "mem"},
+			},
+		},
+		{
+			ID:           "2",
+			Organization: "1338",
+			Layouts: []chronograf.Layout{
+				{ID: "c", Application: "app", Measurement: "disk"},
+			},
+		},
+	}
+	return &mocks.LayoutPacksStore{
+		AllF: func(ctx context.Context) ([]chronograf.LayoutPack, error) {
+			return append([]chronograf.LayoutPack{}, packs...), nil
+		},
+		GetF: func(ctx context.Context, id string) (chronograf.LayoutPack, error) {
+			for _, p := range packs {
+				if p.ID == id {
+					return p, nil
+				}
+			}
+			return chronograf.LayoutPack{}, chronograf.ErrLayoutPackNotFound
+		},
+		UpdateF: func(ctx context.Context, p chronograf.LayoutPack) error {
+			return nil
+		},
+	}
+}
+
+func TestLayoutPacks(t *testing.T) {
+	ctx := context.WithValue(context.Background(), organizations.ContextKey, "1337")
+	s := organizations.NewLayoutPacksStore(newLayoutPacksMock(), "1337")
+
+	packs, err := s.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packs) != 1 || packs[0].ID != "1" {
+		t.Errorf("All() = %v, want only pack 1", packs)
+	}
+
+	if _, err := s.Get(ctx, "2"); err != chronograf.ErrLayoutPackNotFound {
+		t.Errorf("Get() of another organization error = %v, want %v", err, chronograf.ErrLayoutPackNotFound)
+	}
+	if err := s.Update(ctx, chronograf.LayoutPack{ID: "2"}); err != chronograf.ErrLayoutPackNotFound {
+		t.Errorf("Update() of another organization error = %v, want %v", err, chronograf.ErrLayoutPackNotFound)
+	}
+}
+
+func TestLayoutPacks_Layouts(t *testing.T) {
+	ctx := context.WithValue(context.Background(), organizations.ContextKey, "1337")
+	s := organizations.NewLayoutsStore(newLayoutPacksMock(), "1337")
+
+	layouts, err := s.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []chronograf.Layout{
+		{ID: "a", Application: "app", Measurement: "cpu"},
+		{ID: "b", Application: "app", Measurement: "mem"},
+	}
+	if diff := cmp.Diff(layouts, want); diff != "" {
+		t.Errorf("All() diff: %s", diff)
+	}
+
+	if l, err := s.Get(ctx, "b"); err != nil || l.Measurement != "mem" {
+		t.Errorf("Get() = %v, %v", l, err)
+	}
+	if _, err := s.Get(ctx, "c"); err != chronograf.ErrLayoutNotFound {
+		t.Errorf("Get() of another organization error = %v, want %v", err, chronograf.ErrLayoutNotFound)
+	}
+	if _, err := s.Add(ctx, chronograf.Layout{}); err == nil {
+		t.Error("expected Add() to fail")
+	}
+}
@@ -1,10 +1,14 @@
 package server
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/chronograf"
@@ -14,20 +18,31 @@ import (
 const (
 	since           = "since"
 	until           = "until"
+	tagQuery        = "tag"
+	textQuery       = "text"
 	timeMilliFormat = "2006-01-02T15:04:05.999Z07:00"
 )
 
+// Columns of annotations imported from CSV; all other columns are tags
+const (
+	startTimeColumn = "startTime"
+	endTimeColumn   = "endTime"
+	textColumn      = "text"
+	typeColumn      = "type"
+)
+
 type annotationLinks struct {
 	Self string `json:"self"` // Self link mapping to this resource
 }
 
 type annotationResponse struct {
-	ID        string          `json:"id"`        // ID is the unique annotation identifier
-	StartTime string          `json:"startTime"` // StartTime in RFC3339 of the start of the annotation
-	EndTime   string          `json:"endTime"`   // EndTime in RFC3339 of the end of the annotation
-	Text      string          `json:"text"`      // Text is the associated user-facing text describing the annotation
-	Type      string          `json:"type"`      // Type describes the kind of annotation
-	Links     annotationLinks `json:"links"`
+	ID        string                    `json:"id"`             // ID is the unique annotation identifier
+	StartTime string                    `json:"startTime"`      // StartTime in RFC3339 of the start of the annotation
+	EndTime   string                    `json:"endTime"`        // EndTime in RFC3339 of the end of the annotation
+	Text      string                    `json:"text"`           // Text is the associated user-facing text describing the annotation
+	Type      string                    `json:"type"`           // Type describes the kind of annotation
+	Tags      chronograf.AnnotationTags `json:"tags,omitempty"` // Tags are the key/value pairs the annotation is filtered by
+	Links     annotationLinks           `json:"links"`
 }
 
 func newAnnotationResponse(src chronograf.Source, a *chronograf.Annotation) annotationResponse {
@@ -38,6 +53,7 @@ func newAnnotationResponse(src chronograf.Source, a *chronograf.Annotation) anno
 		EndTime:   a.EndTime.UTC().Format(timeMilliFormat),
 		Text:      a.Text,
 		Type:      a.Type,
+		Tags:      a.Tags,
 		Links: annotationLinks{
 			Self: fmt.Sprintf("%s/%d/annotations/%s", base, src.ID, a.ID),
 		},
@@ -64,30 +80,57 @@ func newAnnotationsResponse(src chronograf.Source, as []chronograf.Annotation) a
 	}
 }
 
-func validAnnotationQuery(query url.Values) (startTime, stopTime time.Time, err error) {
+func validAnnotationQuery(query url.Values) (chronograf.AnnotationFilter, error) {
+	var filter chronograf.AnnotationFilter
 	start := query.Get(since)
 	if start == "" {
-		return time.Time{}, time.Time{}, fmt.Errorf("since parameter is required")
+		return filter, fmt.Errorf("since parameter is required")
 	}
 
-	startTime, err = time.Parse(timeMilliFormat, start)
+	startTime, err := time.Parse(timeMilliFormat, start)
 	if err != nil {
-		return
+		return filter, err
 	}
 
 	// if until isn't stated, the default time is now
-	stopTime = time.Now()
+	stopTime := time.Now()
 	stop := query.Get(until)
 	if stop != "" {
 		stopTime, err = time.Parse(timeMilliFormat, stop)
 		if err != nil {
-			return time.Time{}, time.Time{}, err
+			return filter, err
 		}
 	}
 	if startTime.After(stopTime) {
 		startTime, stopTime = stopTime, startTime
 	}
-	return startTime, stopTime, nil
+	filter.StartTime, filter.EndTime = startTime, stopTime
+
+	// tags are key:value; the key ends at the first colon
+	for _, tag := range query[tagQuery] {
+		kv := strings.SplitN(tag, ":", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return filter, fmt.Errorf("tag parameter %q must be key:value", tag)
+		}
+		if filter.Tags == nil {
+			filter.Tags = chronograf.AnnotationTags{}
+		}
+		filter.Tags[kv[0]] = kv[1]
+	}
+	filter.Text = query.Get(textQuery)
+
+	// annotations are not paginated unless a limit is stated
+	if limit := query.Get(limitQuery); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("limit parameter must be a non-negative integer")
+		}
+	}
+	if offset := query.Get(offsetQuery); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil || filter.Offset < 0 {
+			return filter, fmt.Errorf("offset parameter must be a non-negative integer")
+		}
+	}
+	return filter, nil
 }
 
 // Annotations returns all annotations within the annotations store
@@ -98,7 +141,7 @@ func (s *Service) Annotations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	start, stop, err := validAnnotationQuery(r.URL.Query())
+	filter, err := validAnnotationQuery(r.URL.Query())
 	if err != nil {
 		Error(w, http.StatusUnprocessableEntity, err.Error(), s.Logger)
 		return
@@ -125,7 +168,7 @@ func (s *Service) Annotations(w http.ResponseWriter, r *http.Request) {
 	}
 
 	store := influx.NewAnnotationStore(ts)
-	annotations, err := store.All(ctx, start, stop)
+	annotations, err := store.All(ctx, filter)
 	if err != nil {
 		msg := fmt.Errorf("error loading annotations: %v", err)
 		unknownErrorWithMessage(w, msg, s.Logger)
@@ -185,11 +228,86 @@ func (s *Service) Annotation(w http.ResponseWriter, r *http.Request) {
 	encodeJSON(w, http.StatusOK, res, s.Logger)
 }
 
+type annotationVersionResponse struct {
+	annotationResponse
+	ModifiedTime string `json:"modifiedTime"` // ModifiedTime in RFC3339 of when the annotation was changed to this version
+	Deleted      bool   `json:"deleted"`      // Deleted is true if the change removed the annotation
+}
+
+type annotationVersionsResponse struct {
+	Versions []annotationVersionResponse `json:"versions"`
+}
+
+func newAnnotationVersionsResponse(src chronograf.Source, vs []chronograf.AnnotationVersion) annotationVersionsResponse {
+	versions := make([]annotationVersionResponse, len(vs))
+	for i, v := range vs {
+		versions[i] = annotationVersionResponse{
+			annotationResponse: newAnnotationResponse(src, &v.Annotation),
+			ModifiedTime:       v.ModifiedTime.UTC().Format(timeMilliFormat),
+			Deleted:            v.Deleted,
+		}
+	}
+	return annotationVersionsResponse{
+		Versions: versions,
+	}
+}
+
+// AnnotationVersions returns the version history of an annotation, oldest
+// first, including its removal if it was deleted
+func (s *Service) AnnotationVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := paramID("id", r)
+	if err != nil {
+		Error(w, http.StatusUnprocessableEntity, err.Error(), s.Logger)
+		return
+	}
+	annoID, err := paramStr("aid", r)
+	if err != nil {
+		Error(w, http.StatusUnprocessableEntity, err.Error(), s.Logger)
+		return
+	}
+
+	ctx := r.Context()
+	src, err := s.Store.Sources(ctx).Get(ctx, id)
+	if err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	ts, err := s.TimeSeries(src)
+	if err != nil {
+		msg := fmt.Sprintf("unable to connect to source %d: %v", id, err)
+		Error(w, http.StatusBadRequest, msg, s.Logger)
+		return
+	}
+
+	if err = ts.Connect(ctx, &src); err != nil {
+		msg := fmt.Sprintf("unable to connect to source %d: %v", id, err)
+		Error(w, http.StatusBadRequest, msg, s.Logger)
+		return
+	}
+
+	store := influx.NewAnnotationStore(ts)
+	versions, err := store.Versions(ctx, annoID)
+	if err != nil {
+		if err != chronograf.ErrAnnotationNotFound {
+			msg := fmt.Errorf("error loading annotation versions: %v", err)
+			unknownErrorWithMessage(w, msg, s.Logger)
+			return
+		}
+		Error(w, http.StatusNotFound, err.Error(), s.Logger)
+		return
+	}
+
+	res := newAnnotationVersionsResponse(src, versions)
+	encodeJSON(w, http.StatusOK, res, s.Logger)
+}
+
 type newAnnotationRequest struct {
 	StartTime time.Time
 	EndTime   time.Time
-	Text      string `json:"text,omitempty"` // Text is the associated user-facing text describing the annotation
-	Type      string `json:"type,omitempty"` // Type describes the kind of annotation
+	Text      string                    `json:"text,omitempty"` // Text is the associated user-facing text describing the annotation
+	Type      string                    `json:"type,omitempty"` // Type describes the kind of annotation
+	Tags      chronograf.AnnotationTags `json:"tags,omitempty"` // Tags are the key/value pairs the annotation is filtered by
 }
 
 func (ar *newAnnotationRequest) UnmarshalJSON(data []byte) error {
@@ -229,6 +347,7 @@ func (ar *newAnnotationRequest) Annotation() *chronograf.Annotation {
 		EndTime:   ar.EndTime,
 		Text:      ar.Text,
 		Type:      ar.Type,
+		Tags:      ar.Tags,
 	}
 }
 
@@ -336,6 +455,8 @@ type updateAnnotationRequest struct {
 	EndTime   *time.Time `json:"endTime,omitempty"`   // EndTime is the time in rfc3339 milliseconds
 	Text      *string    `json:"text,omitempty"`      // Text is the associated user-facing text describing the annotation
 	Type      *string    `json:"type,omitempty"`      // Type describes the kind of annotation
+	// Tags replace the tags of the annotation; empty tags remove them
+	Tags chronograf.AnnotationTags `json:"tags,omitempty"`
 }
 
 // TODO: make sure that endtime is after starttime
@@ -369,7 +490,7 @@ func (u *updateAnnotationRequest) UnmarshalJSON(data []byte) error {
 	}
 
 	// Update must have at least one field set
-	if u.StartTime == nil && u.EndTime == nil && u.Text == nil && u.Type == nil {
+	if u.StartTime == nil && u.EndTime == nil && u.Text == nil && u.Type == nil && u.Tags == nil {
 		return fmt.Errorf("update request must have at least one field")
 	}
 
@@ -435,6 +556,9 @@ func (s *Service) UpdateAnnotation(w http.ResponseWriter, r *http.Request) {
 	if req.Type != nil {
 		cur.Type = *req.Type
 	}
+	if req.Tags != nil {
+		cur.Tags = req.Tags
+	}
 
 	if err = store.Update(ctx, cur); err != nil {
 		if err == chronograf.ErrUpstreamTimeout {
@@ -450,3 +574,138 @@ func (s *Service) UpdateAnnotation(w http.ResponseWriter, r *http.Request) {
 	location(w, res.Links.Self)
 	encodeJSON(w, http.StatusOK, res, s.Logger)
 }
+
+// annotationsCSV parses the annotations of CSV with a header. The startTime
+// column is required and endTime defaults to it; both are RFC3339 times.
+// Columns other than startTime, endTime, text and type are tags of the
+// annotations, which empty values omit.
+func annotationsCSV(r io.Reader) ([]*chronograf.Annotation, error) {
+	rd := csv.NewReader(r)
+	rd.TrimLeadingSpace = true
+	header, err := rd.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("annotations CSV must have a header")
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]bool{}
+	for _, col := range header {
+		if col == "" {
+			return nil, fmt.Errorf("annotations CSV header has an empty column")
+		}
+		if columns[col] {
+			return nil, fmt.Errorf("annotations CSV header has column %q more than once", col)
+		}
+		columns[col] = true
+	}
+	if !columns[startTimeColumn] {
+		return nil, fmt.Errorf("annotations CSV header must have a %s column", startTimeColumn)
+	}
+
+	annos := []*chronograf.Annotation{}
+	for row := 1; ; row++ {
+		record, err := rd.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		anno := &chronograf.Annotation{}
+		var end string
+		for i, v := range record {
+			switch col := header[i]; col {
+			case startTimeColumn:
+				if anno.StartTime, err = time.Parse(time.RFC3339Nano, v); err != nil {
+					return nil, fmt.Errorf("row %d: invalid %s: %v", row, col, err)
+				}
+			case endTimeColumn:
+				end = v
+			case textColumn:
+				anno.Text = v
+			case typeColumn:
+				anno.Type = v
+			default:
+				if v == "" {
+					continue
+				}
+				if anno.Tags == nil {
+					anno.Tags = chronograf.AnnotationTags{}
+				}
+				anno.Tags[col] = v
+			}
+		}
+
+		anno.EndTime = anno.StartTime
+		if end != "" {
+			if anno.EndTime, err = time.Parse(time.RFC3339Nano, end); err != nil {
+				return nil, fmt.Errorf("row %d: invalid %s: %v", row, endTimeColumn, err)
+			}
+		}
+		if anno.StartTime.After(anno.EndTime) {
+			anno.StartTime, anno.EndTime = anno.EndTime, anno.StartTime
+		}
+		annos = append(annos, anno)
+	}
+
+	if len(annos) == 0 {
+		return nil, fmt.Errorf("annotations CSV has no annotations")
+	}
+	return annos, nil
+}
+
+// ImportAnnotations adds all the annotations of a CSV body to the annotations store
+func (s *Service) ImportAnnotations(w http.ResponseWriter, r *http.Request) {
+	id, err := paramID("id", r)
+	if err != nil {
+		Error(w, http.StatusUnprocessableEntity, err.Error(), s.Logger)
+		return
+	}
+
+	ctx := r.Context()
+	src, err := s.Store.Sources(ctx).Get(ctx, id)
+	if err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	ts, err := s.TimeSeries(src)
+	if err != nil {
+		msg := fmt.Sprintf("unable to connect to source %d: %v", id, err)
+		Error(w, http.StatusBadRequest, msg, s.Logger)
+		return
+	}
+
+	if err = ts.Connect(ctx, &src); err != nil {
+		msg := fmt.Sprintf("unable to connect to source %d: %v", id, err)
+		Error(w, http.StatusBadRequest, msg, s.Logger)
+		return
+	}
+
+	annos, err := annotationsCSV(r.Body)
+	if err != nil {
+		invalidData(w, err, s.Logger)
+		return
+	}
+
+	store := influx.NewAnnotationStore(ts)
+	annos, err = store.AddMany(ctx, annos)
+	if err != nil {
+		if err == chronograf.ErrUpstreamTimeout {
+			msg := "Timeout waiting for response"
+			Error(w, http.StatusRequestTimeout, msg, s.Logger)
+			return
+		}
+		Error(w, http.StatusBadRequest, err.Error(), s.Logger)
+		return
+	}
+
+	res := annotationsResponse{
+		Annotations: make([]annotationResponse, len(annos)),
+	}
+	for i, a := range annos {
+		res.Annotations[i] = newAnnotationResponse(src, a)
+	}
+	encodeJSON(w, http.StatusCreated, res, s.Logger)
+}
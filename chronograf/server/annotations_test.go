@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/influx"
 	"github.com/influxdata/influxdb/chronograf/mocks"
 	"github.com/influxdata/httprouter"
 )
@@ -165,6 +168,78 @@ func TestService_Annotations(t *testing.T) {
 			want: `{"annotations":[{"id":"ea0aa94b-969a-4cd5-912a-5db61d502268","startTime":"1970-01-01T00:00:00Z","endTime":"2018-01-25T22:42:57.345Z","text":"mytext","type":"mytype","links":{"self":"/chronograf/v1/sources/1/annotations/ea0aa94b-969a-4cd5-912a-5db61d502268"}}]}
 `,
 		},
+		{
+			name: "annotations are filtered by tag and text and paginated",
+			fields: fields{
+				Store: &mocks.Store{
+					SourcesStore: &mocks.SourcesStore{
+						GetF: func(ctx context.Context, ID int) (chronograf.Source, error) {
+							return chronograf.Source{
+								ID: ID,
+							}, nil
+						},
+					},
+				},
+				TimeSeriesClient: &mocks.TimeSeries{
+					ConnectF: func(context.Context, *chronograf.Source) error {
+						return nil
+					},
+					QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+						return mocks.NewResponse(`[
+							{
+								"series": [
+									{
+										"name": "annotations",
+										"tags": {
+											"id": "1",
+											"service": "api"
+										},
+										"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+										"values": [
+											[1516920177345000000, 0, 1516989242129417403, "deploy v1", "mytype", "1"]
+										]
+									},
+									{
+										"name": "annotations",
+										"tags": {
+											"id": "2",
+											"service": "api"
+										},
+										"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+										"values": [
+											[1516920177345000000, 1, 1516989242129417403, "deploy v2", "mytype", "2"]
+										]
+									},
+									{
+										"name": "annotations",
+										"tags": {
+											"id": "3",
+											"service": "web"
+										},
+										"columns": ["time", "start_time", "modified_time_ns", "text", "type", "id"],
+										"values": [
+											[1516920177345000000, 2, 1516989242129417403, "deploy v3", "mytype", "3"]
+										]
+									}
+								]
+							}
+						]`, nil), nil
+					},
+				},
+			},
+			ID: "1",
+			w:  httptest.NewRecorder(),
+			r:  httptest.NewRequest("GET", "/chronograf/v1/sources/1/annotations?since=1985-04-12T23:20:50.52Z&tag=service:api&text=Deploy&limit=1&offset=1", nil),
+			want: `{"annotations":[{"id":"2","startTime":"1970-01-01T00:00:00Z","endTime":"2018-01-25T22:42:57.345Z","text":"deploy v2","type":"mytype","tags":{"service":"api"},"links":{"self":"/chronograf/v1/sources/1/annotations/2"}}]}
+`,
+		},
+		{
+			name: "invalid tag parameter",
+			ID:   "1",
+			w:    httptest.NewRecorder(),
+			r:    httptest.NewRequest("GET", "/chronograf/v1/sources/1/annotations?since=1985-04-12T23:20:50.52Z&tag=service", nil),
+			want: `{"code":422,"message":"tag parameter \"service\" must be key:value"}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -190,3 +265,192 @@ func TestService_Annotations(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ImportAnnotations(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantPoints []chronograf.Point
+	}{
+		{
+			name: "imports all annotations with tags",
+			body: "startTime,endTime,text,type,service\n" +
+				"2018-01-25T22:42:57.345Z,,deploy v1,deploy,api\n" +
+				"2018-01-25T22:50:00Z,2018-01-25T22:45:00Z,outage,incident,\n",
+			wantStatus: http.StatusCreated,
+			wantPoints: []chronograf.Point{
+				{
+					Time: time.Date(2018, 1, 25, 22, 42, 57, 345000000, time.UTC).UnixNano(),
+					Tags: map[string]string{"service": "api"},
+					Fields: map[string]interface{}{
+						"start_time": time.Date(2018, 1, 25, 22, 42, 57, 345000000, time.UTC).UnixNano(),
+						"text":       "deploy v1",
+						"type":       "deploy",
+					},
+				},
+				{
+					Time: time.Date(2018, 1, 25, 22, 50, 0, 0, time.UTC).UnixNano(),
+					Tags: map[string]string{},
+					Fields: map[string]interface{}{
+						"start_time": time.Date(2018, 1, 25, 22, 45, 0, 0, time.UTC).UnixNano(),
+						"text":       "outage",
+						"type":       "incident",
+					},
+				},
+			},
+		},
+		{
+			name:       "header requires the start time",
+			body:       "text,type\ndeploy,deploy\n",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid time",
+			body:       "startTime,text\nyesterday,deploy\n",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no annotations",
+			body:       "startTime,text\n",
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "reserved tag keys are rejected",
+			body:       "startTime,id\n2018-01-25T22:42:57.345Z,1\n",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var points []chronograf.Point
+			s := &Service{
+				Store: &mocks.Store{
+					SourcesStore: &mocks.SourcesStore{
+						GetF: func(ctx context.Context, ID int) (chronograf.Source, error) {
+							return chronograf.Source{
+								ID: ID,
+							}, nil
+						},
+					},
+				},
+				TimeSeriesClient: &mocks.TimeSeries{
+					ConnectF: func(context.Context, *chronograf.Source) error {
+						return nil
+					},
+					WriteF: func(ctx context.Context, ps []chronograf.Point) error {
+						// the versions of the annotations are written with them
+						for _, p := range ps {
+							if p.Measurement == influx.DefaultMeasurement {
+								points = append(points, p)
+							}
+						}
+						return nil
+					},
+				},
+				Logger: mocks.NewLogger(),
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/chronograf/v1/sources/1/annotations/import", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.TODO(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: "1",
+					},
+				}))
+			s.ImportAnnotations(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ImportAnnotations() status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if len(points) != len(tt.wantPoints) {
+				t.Fatalf("ImportAnnotations() wrote %d points, want %d", len(points), len(tt.wantPoints))
+			}
+			for i, p := range points {
+				want := tt.wantPoints[i]
+				if p.Time != want.Time {
+					t.Errorf("point %d has time %d, want %d", i, p.Time, want.Time)
+				}
+				for k, v := range want.Tags {
+					if p.Tags[k] != v {
+						t.Errorf("point %d has tag %s=%q, want %q", i, k, p.Tags[k], v)
+					}
+				}
+				if len(p.Tags) != len(want.Tags)+1 {
+					t.Errorf("point %d has tags %v, want the id and %v", i, p.Tags, want.Tags)
+				}
+				for k, v := range want.Fields {
+					if p.Fields[k] != v {
+						t.Errorf("point %d has field %s=%v, want %v", i, k, p.Fields[k], v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestService_AnnotationVersions(t *testing.T) {
+	store := &mocks.Store{
+		SourcesStore: &mocks.SourcesStore{
+			GetF: func(ctx context.Context, ID int) (chronograf.Source, error) {
+				return chronograf.Source{
+					ID: ID,
+				}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name: "lists the versions",
+			response: `[{"series":[{"name":"annotation_versions","columns":["time","start_time","end_time","text","type","tags","deleted"],"values":[
+				[1516920177345000000,0,1516920177345000000,"deploy","mytype","{\"service\":\"api\"}",false],
+				[1516920178345000000,0,1516920177345000000,"deploy","mytype","{\"service\":\"api\"}",true]
+			]}]}]`,
+			want: `{"versions":[{"id":"1","startTime":"1970-01-01T00:00:00Z","endTime":"2018-01-25T22:42:57.345Z","text":"deploy","type":"mytype","tags":{"service":"api"},"links":{"self":"/chronograf/v1/sources/1/annotations/1"},"modifiedTime":"2018-01-25T22:42:57.345Z","deleted":false},{"id":"1","startTime":"1970-01-01T00:00:00Z","endTime":"2018-01-25T22:42:57.345Z","text":"deploy","type":"mytype","tags":{"service":"api"},"links":{"self":"/chronograf/v1/sources/1/annotations/1"},"modifiedTime":"2018-01-25T22:42:58.345Z","deleted":true}]}
+`,
+		},
+		{
+			name:     "unknown annotation",
+			response: `[{}]`,
+			want:     `{"code":404,"message":"annotation not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/chronograf/v1/sources/1/annotations/1/versions", nil)
+			r = r.WithContext(context.WithValue(
+				context.TODO(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{Key: "id", Value: "1"},
+					{Key: "aid", Value: "1"},
+				}))
+			s := &Service{
+				Store: store,
+				TimeSeriesClient: &mocks.TimeSeries{
+					ConnectF: func(context.Context, *chronograf.Source) error {
+						return nil
+					},
+					QueryF: func(context.Context, chronograf.Query) (chronograf.Response, error) {
+						return mocks.NewResponse(tt.response, nil), nil
+					},
+				},
+				Logger: mocks.NewLogger(),
+			}
+			s.AnnotationVersions(w, r)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("AnnotationVersions() got != want:\n%s\n%s", got, tt.want)
+			}
+		})
+	}
+}
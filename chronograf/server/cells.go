@@ -221,6 +221,11 @@ func (s *Service) NewDashboardCell(w http.ResponseWriter, r *http.Request) {
 		notFound(w, id, s.Logger)
 		return
 	}
+
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
 	var cell chronograf.DashboardCell
 	if err := json.NewDecoder(r.Body).Decode(&cell); err != nil {
 		invalidJSON(w, s.Logger)
@@ -298,6 +303,11 @@ func (s *Service) RemoveDashboardCell(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	cid := httprouter.ParamsFromContext(ctx).ByName("cid")
 	cellid := -1
 	for i, cell := range dash.Cells {
@@ -335,6 +345,11 @@ func (s *Service) ReplaceDashboardCell(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	cid := httprouter.ParamsFromContext(ctx).ByName("cid")
 	cellid := -1
 	for i, cell := range dash.Cells {
@@ -1,11 +1,14 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/roles"
 )
 
 type dashboardLinks struct {
@@ -20,9 +23,15 @@ type dashboardResponse struct {
 	Templates    []templateResponse      `json:"templates"`
 	Name         string                  `json:"name"`
 	Organization string                  `json:"organization"`
+	Roles        []dashboardRole         `json:"roles"`
 	Links        dashboardLinks          `json:"links"`
 }
 
+type dashboardRole struct {
+	UserID uint64 `json:"userID,string"`
+	Role   string `json:"role"`
+}
+
 type getDashboardsResponse struct {
 	Dashboards []*dashboardResponse `json:"dashboards"`
 }
@@ -32,6 +41,13 @@ func newDashboardResponse(d chronograf.Dashboard) *dashboardResponse {
 	dd := AddQueryConfigs(DashboardDefaults(d))
 	cells := newCellResponses(dd.ID, dd.Cells)
 	templates := newTemplateResponses(dd.ID, dd.Templates)
+	dashRoles := make([]dashboardRole, len(d.Roles))
+	for i, r := range d.Roles {
+		dashRoles[i] = dashboardRole{
+			UserID: r.UserID,
+			Role:   r.Role,
+		}
+	}
 
 	return &dashboardResponse{
 		ID:           dd.ID,
@@ -39,6 +55,7 @@ func newDashboardResponse(d chronograf.Dashboard) *dashboardResponse {
 		Cells:        cells,
 		Templates:    templates,
 		Organization: d.Organization,
+		Roles:        dashRoles,
 		Links: dashboardLinks{
 			Self:      fmt.Sprintf("%s/%d", base, dd.ID),
 			Cells:     fmt.Sprintf("%s/%d/cells", base, dd.ID),
@@ -106,6 +123,11 @@ func (s *Service) NewDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(dashboard.Roles) > 0 && !canChangeDashboardRoles(ctx) {
+		Error(w, http.StatusForbidden, "User is not authorized to change the roles of the dashboard", s.Logger)
+		return
+	}
+
 	if dashboard, err = s.Store.Dashboards(ctx).Add(r.Context(), dashboard); err != nil {
 		msg := fmt.Errorf("error storing dashboard %v: %v", dashboard, err)
 		unknownErrorWithMessage(w, msg, s.Logger)
@@ -132,6 +154,11 @@ func (s *Service) RemoveDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, e) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	if err := s.Store.Dashboards(ctx).Delete(ctx, e); err != nil {
 		unknownErrorWithMessage(w, err, s.Logger)
 		return
@@ -149,12 +176,17 @@ func (s *Service) ReplaceDashboard(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chronograf.DashboardID(idParam)
 
-	_, err = s.Store.Dashboards(ctx).Get(ctx, id)
+	orig, err := s.Store.Dashboards(ctx).Get(ctx, id)
 	if err != nil {
 		Error(w, http.StatusNotFound, fmt.Sprintf("ID %d not found", id), s.Logger)
 		return
 	}
 
+	if !canEditDashboard(ctx, orig) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	var req chronograf.Dashboard
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		invalidJSON(w, s.Logger)
@@ -173,6 +205,11 @@ func (s *Service) ReplaceDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !sameDashboardRoles(orig.Roles, req.Roles) && !canChangeDashboardRoles(ctx) {
+		Error(w, http.StatusForbidden, "User is not authorized to change the roles of the dashboard", s.Logger)
+		return
+	}
+
 	if err := s.Store.Dashboards(ctx).Update(ctx, req); err != nil {
 		msg := fmt.Sprintf("Error updating dashboard ID %d: %v", id, err)
 		Error(w, http.StatusInternalServerError, msg, s.Logger)
@@ -183,7 +220,8 @@ func (s *Service) ReplaceDashboard(w http.ResponseWriter, r *http.Request) {
 	encodeJSON(w, http.StatusOK, res, s.Logger)
 }
 
-// UpdateDashboard completely updates either the dashboard name or the cells
+// UpdateDashboard updates the name, cells and roles of the dashboard that
+// are present in the request
 func (s *Service) UpdateDashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idParam, err := paramID("id", r)
@@ -200,6 +238,11 @@ func (s *Service) UpdateDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, orig) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	var req chronograf.Dashboard
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		invalidJSON(w, s.Logger)
@@ -207,9 +250,23 @@ func (s *Service) UpdateDashboard(w http.ResponseWriter, r *http.Request) {
 	}
 	req.ID = id
 
-	if req.Name != "" {
-		orig.Name = req.Name
-	} else if len(req.Cells) > 0 {
+	if req.Name == "" && len(req.Cells) == 0 && req.Roles == nil {
+		invalidData(w, fmt.Errorf("update must include either name, cells or roles"), s.Logger)
+		return
+	}
+
+	// every field of the update is validated before any is applied
+	if req.Roles != nil {
+		if !canChangeDashboardRoles(ctx) {
+			Error(w, http.StatusForbidden, "User is not authorized to change the roles of the dashboard", s.Logger)
+			return
+		}
+		if err := validDashboardRoles(req.Roles); err != nil {
+			invalidData(w, err, s.Logger)
+			return
+		}
+	}
+	if len(req.Cells) > 0 {
 		defaultOrg, err := s.Store.Organizations(ctx).DefaultOrganization(ctx)
 		if err != nil {
 			unknownErrorWithMessage(w, err, s.Logger)
@@ -219,10 +276,16 @@ func (s *Service) UpdateDashboard(w http.ResponseWriter, r *http.Request) {
 			invalidData(w, err, s.Logger)
 			return
 		}
+	}
+
+	if req.Name != "" {
+		orig.Name = req.Name
+	}
+	if len(req.Cells) > 0 {
 		orig.Cells = req.Cells
-	} else {
-		invalidData(w, fmt.Errorf("update must include either name or cells"), s.Logger)
-		return
+	}
+	if req.Roles != nil {
+		orig.Roles = req.Roles
 	}
 
 	if err := s.Store.Dashboards(ctx).Update(ctx, orig); err != nil {
@@ -251,10 +314,79 @@ func ValidDashboardRequest(d *chronograf.Dashboard, defaultOrgID string) error {
 			return err
 		}
 	}
+	if err := validDashboardRoles(d.Roles); err != nil {
+		return err
+	}
 	(*d) = DashboardDefaults(*d)
 	return nil
 }
 
+// validDashboardRoles verifies that the roles are either viewers or editors
+// and that each user has a single role
+func validDashboardRoles(rs []chronograf.DashboardRole) error {
+	users := map[uint64]bool{}
+	for _, r := range rs {
+		if r.UserID == 0 {
+			return fmt.Errorf("dashboard role must have a user")
+		}
+		if r.Role != roles.ViewerRoleName && r.Role != roles.EditorRoleName {
+			return fmt.Errorf("dashboard role of user %d must be either %s or %s", r.UserID, roles.ViewerRoleName, roles.EditorRoleName)
+		}
+		if users[r.UserID] {
+			return fmt.Errorf("user %d has more than one dashboard role", r.UserID)
+		}
+		users[r.UserID] = true
+	}
+	return nil
+}
+
+// canEditDashboard specifies if the user of the context may change the
+// dashboard. Dashboards without roles may be changed by the editors of their
+// organization; once a dashboard has roles, only its editors and the admins
+// of the organization may change it. Without auth there is no user, and
+// every dashboard may be changed.
+func canEditDashboard(ctx context.Context, d chronograf.Dashboard) bool {
+	if len(d.Roles) == 0 {
+		return true
+	}
+	u, ok := hasUserContext(ctx)
+	if !ok || isDashboardsAdmin(ctx, u) {
+		return true
+	}
+	for _, r := range d.Roles {
+		if r.UserID == u.ID {
+			return r.Role == roles.EditorRoleName
+		}
+	}
+	return false
+}
+
+// canChangeDashboardRoles specifies if the user of the context may change
+// the roles of dashboards, which only the admins of the organization may.
+func canChangeDashboardRoles(ctx context.Context) bool {
+	u, ok := hasUserContext(ctx)
+	return !ok || isDashboardsAdmin(ctx, u)
+}
+
+func isDashboardsAdmin(ctx context.Context, u *chronograf.User) bool {
+	if u.SuperAdmin {
+		return true
+	}
+	role, ok := hasRoleContext(ctx)
+	return ok && role == roles.AdminRoleName
+}
+
+func sameDashboardRoles(a, b []chronograf.DashboardRole) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func dashboardForbidden(w http.ResponseWriter, logger chronograf.Logger) {
+	Error(w, http.StatusForbidden, "User is not authorized to edit the dashboard", logger)
+}
+
 // DashboardDefaults updates the dashboard with the default values
 // if none are specified
 func DashboardDefaults(d chronograf.Dashboard) (newDash chronograf.Dashboard) {
@@ -262,6 +394,7 @@ func DashboardDefaults(d chronograf.Dashboard) (newDash chronograf.Dashboard) {
 	newDash.Templates = d.Templates
 	newDash.Name = d.Name
 	newDash.Organization = d.Organization
+	newDash.Roles = d.Roles
 	newDash.Cells = make([]chronograf.DashboardCell, len(d.Cells))
 
 	for i, c := range d.Cells {
@@ -1,11 +1,18 @@
 package server
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/mocks"
+	"github.com/influxdata/influxdb/chronograf/roles"
 )
 
 func TestCorrectWidthHeight(t *testing.T) {
@@ -190,6 +197,36 @@ func TestValidDashboardRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Rejects dashboard roles other than viewer or editor",
+			d: chronograf.Dashboard{
+				Organization: "1337",
+				Roles: []chronograf.DashboardRole{
+					{
+						UserID: 1,
+						Role:   roles.AdminRoleName,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Rejects more than one dashboard role of a user",
+			d: chronograf.Dashboard{
+				Organization: "1337",
+				Roles: []chronograf.DashboardRole{
+					{
+						UserID: 1,
+						Role:   roles.ViewerRoleName,
+					},
+					{
+						UserID: 1,
+						Role:   roles.EditorRoleName,
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		// TODO(desa): this Okay?
@@ -198,6 +235,9 @@ func TestValidDashboardRequest(t *testing.T) {
 			t.Errorf("%q. ValidDashboardRequest() error = %v, wantErr %v", tt.name, err, tt.wantErr)
 			continue
 		}
+		if tt.wantErr {
+			continue
+		}
 		if diff := cmp.Diff(tt.d, tt.want); diff != "" {
 			t.Errorf("%q. ValidDashboardRequest(). got/want diff:\n%s", tt.name, diff)
 		}
@@ -258,6 +298,7 @@ func Test_newDashboardResponse(t *testing.T) {
 			want: &dashboardResponse{
 				Organization: "0",
 				Templates:    []templateResponse{},
+				Roles:        []dashboardRole{},
 				Cells: []dashboardCellResponse{
 					dashboardCellResponse{
 						Links: dashboardCellLinks{
@@ -364,3 +405,210 @@ func Test_newDashboardResponse(t *testing.T) {
 		}
 	}
 }
+
+func TestService_RemoveDashboard(t *testing.T) {
+	dashboard := chronograf.Dashboard{
+		ID:           1,
+		Organization: "1337",
+		Roles: []chronograf.DashboardRole{
+			{
+				UserID: 1,
+				Role:   roles.EditorRoleName,
+			},
+			{
+				UserID: 2,
+				Role:   roles.ViewerRoleName,
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		dashboard chronograf.Dashboard
+		user      *chronograf.User
+		role      string
+		wantCode  int
+	}{
+		{
+			name:      "Editor of the dashboard removes it",
+			dashboard: dashboard,
+			user:      &chronograf.User{ID: 1},
+			role:      roles.EditorRoleName,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:      "Viewer of the dashboard is not authorized to remove it",
+			dashboard: dashboard,
+			user:      &chronograf.User{ID: 2},
+			role:      roles.EditorRoleName,
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Editor of the organization without a role on the dashboard is not authorized to remove it",
+			dashboard: dashboard,
+			user:      &chronograf.User{ID: 3},
+			role:      roles.EditorRoleName,
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "Admin of the organization removes it",
+			dashboard: dashboard,
+			user:      &chronograf.User{ID: 3},
+			role:      roles.AdminRoleName,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name: "Editor of the organization removes a dashboard without roles",
+			dashboard: chronograf.Dashboard{
+				ID:           1,
+				Organization: "1337",
+			},
+			user:     &chronograf.User{ID: 3},
+			role:     roles.EditorRoleName,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:      "Dashboard is removed without auth",
+			dashboard: dashboard,
+			wantCode:  http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			s := &Service{
+				Store: &mocks.Store{
+					DashboardsStore: &mocks.DashboardsStore{
+						GetF: func(ctx context.Context, id chronograf.DashboardID) (chronograf.Dashboard, error) {
+							return tt.dashboard, nil
+						},
+						DeleteF: func(ctx context.Context, d chronograf.Dashboard) error {
+							deleted = true
+							return nil
+						},
+					},
+				},
+				Logger: &mocks.TestLogger{},
+			}
+
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{
+				{
+					Key:   "id",
+					Value: "1",
+				},
+			})
+			if tt.user != nil {
+				ctx = context.WithValue(ctx, UserContextKey, tt.user)
+				ctx = context.WithValue(ctx, roles.ContextKey, tt.role)
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("DELETE", "/chronograf/v1/dashboards/1", nil).WithContext(ctx)
+			s.RemoveDashboard(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("RemoveDashboard() status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if deleted != (tt.wantCode == http.StatusNoContent) {
+				t.Errorf("RemoveDashboard() deleted = %v", deleted)
+			}
+		})
+	}
+}
+
+func TestService_UpdateDashboard(t *testing.T) {
+	dashboard := chronograf.Dashboard{
+		ID:           1,
+		Name:         "old name",
+		Organization: "1337",
+	}
+	tests := []struct {
+		name      string
+		body      string
+		role      string
+		wantCode  int
+		wantName  string
+		wantRoles []chronograf.DashboardRole
+	}{
+		{
+			name:     "Updates the name",
+			body:     `{"name": "new name"}`,
+			role:     roles.AdminRoleName,
+			wantCode: http.StatusOK,
+			wantName: "new name",
+		},
+		{
+			name:     "Updates the name and roles",
+			body:     `{"name": "new name", "roles": [{"userID": "2", "role": "viewer"}]}`,
+			role:     roles.AdminRoleName,
+			wantCode: http.StatusOK,
+			wantName: "new name",
+			wantRoles: []chronograf.DashboardRole{
+				{
+					UserID: 2,
+					Role:   roles.ViewerRoleName,
+				},
+			},
+		},
+		{
+			name:     "Editor is not authorized to change the roles along with the name",
+			body:     `{"name": "new name", "roles": [{"userID": "2", "role": "viewer"}]}`,
+			role:     roles.EditorRoleName,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Update without fields",
+			body:     `{}`,
+			role:     roles.AdminRoleName,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *chronograf.Dashboard
+			s := &Service{
+				Store: &mocks.Store{
+					DashboardsStore: &mocks.DashboardsStore{
+						GetF: func(ctx context.Context, id chronograf.DashboardID) (chronograf.Dashboard, error) {
+							return dashboard, nil
+						},
+						UpdateF: func(ctx context.Context, d chronograf.Dashboard) error {
+							updated = &d
+							return nil
+						},
+					},
+				},
+				Logger: &mocks.TestLogger{},
+			}
+
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{
+				{
+					Key:   "id",
+					Value: "1",
+				},
+			})
+			ctx = context.WithValue(ctx, UserContextKey, &chronograf.User{ID: 1})
+			ctx = context.WithValue(ctx, roles.ContextKey, tt.role)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", "/chronograf/v1/dashboards/1", strings.NewReader(tt.body)).WithContext(ctx)
+			s.UpdateDashboard(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("UpdateDashboard() status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if updated != nil {
+					t.Errorf("UpdateDashboard() updated the dashboard")
+				}
+				return
+			}
+			if updated == nil {
+				t.Fatalf("UpdateDashboard() did not update the dashboard")
+			}
+			if updated.Name != tt.wantName {
+				t.Errorf("UpdateDashboard() name = %q, want %q", updated.Name, tt.wantName)
+			}
+			if !cmp.Equal(updated.Roles, tt.wantRoles) {
+				t.Errorf("UpdateDashboard() roles = diff:\n%s", cmp.Diff(updated.Roles, tt.wantRoles))
+			}
+		})
+	}
+}
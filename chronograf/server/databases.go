@@ -183,6 +183,7 @@ func (h *Service) NewDatabase(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, err.Error(), h.Logger)
 		return
 	}
+	h.MetaCache.Invalidate(srcID)
 
 	rps, err := h.allRPs(ctx, dbsvc, srcID, database.Name)
 	if err != nil {
@@ -224,6 +225,7 @@ func (h *Service) DropDatabase(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, dropErr.Error(), h.Logger)
 		return
 	}
+	h.MetaCache.Invalidate(srcID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -321,6 +323,7 @@ func (h *Service) NewRetentionPolicy(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, err.Error(), h.Logger)
 		return
 	}
+	h.MetaCache.Invalidate(srcID)
 	res := rpResponse{
 		Name:          rp.Name,
 		Duration:      rp.Duration,
@@ -374,6 +377,7 @@ func (h *Service) UpdateRetentionPolicy(w http.ResponseWriter, r *http.Request)
 		Error(w, http.StatusBadRequest, err.Error(), h.Logger)
 		return
 	}
+	h.MetaCache.Invalidate(srcID)
 
 	res := rpResponse{
 		Name:          p.Name,
@@ -417,6 +421,7 @@ func (s *Service) DropRetentionPolicy(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, dropErr.Error(), s.Logger)
 		return
 	}
+	s.MetaCache.Invalidate(srcID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
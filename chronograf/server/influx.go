@@ -51,6 +51,14 @@ func (s *Service) Influx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if response, ok := s.MetaCache.Get(id, req); ok {
+		encodeJSON(w, http.StatusOK, postInfluxResponse{Results: response}, s.Logger)
+		return
+	}
+	if modifiesSource(req.Command) {
+		s.MetaCache.Invalidate(id)
+	}
+
 	ts, err := s.TimeSeries(src)
 	if err != nil {
 		msg := fmt.Sprintf("unable to connect to source %d: %v", id, err)
@@ -76,6 +84,8 @@ func (s *Service) Influx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.MetaCache.Set(id, req, response)
+
 	res := postInfluxResponse{
 		Results: response,
 	}
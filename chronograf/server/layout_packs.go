@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bouk/httprouter"
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+type layoutPackLinks struct {
+	Self string `json:"self"` // Self link mapping to this resource
+}
+
+type layoutPackResponse struct {
+	ID           string           `json:"id"`
+	Name         string           `json:"name"`
+	Organization string           `json:"organization"`
+	Layouts      []layoutResponse `json:"layouts"`
+	Links        layoutPackLinks  `json:"links"`
+}
+
+type getLayoutPacksResponse struct {
+	LayoutPacks []layoutPackResponse `json:"layoutPacks"`
+}
+
+func newLayoutPackResponse(p chronograf.LayoutPack) layoutPackResponse {
+	layouts := make([]layoutResponse, len(p.Layouts))
+	for i, l := range p.Layouts {
+		// newLayoutResponse fills in the cells, keep the pack untouched
+		l.Cells = append([]chronograf.Cell(nil), l.Cells...)
+		layouts[i] = newLayoutResponse(l)
+	}
+
+	return layoutPackResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		Organization: p.Organization,
+		Layouts:      layouts,
+		Links: layoutPackLinks{
+			Self: fmt.Sprintf("/chronograf/v1/layoutpacks/%s", p.ID),
+		},
+	}
+}
+
+// LayoutPacks returns all layout packs of the organization
+func (s *Service) LayoutPacks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	packs, err := s.Store.LayoutPacks(ctx).All(ctx)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, "Error loading layout packs", s.Logger)
+		return
+	}
+
+	res := getLayoutPacksResponse{
+		LayoutPacks: []layoutPackResponse{},
+	}
+	for _, p := range packs {
+		res.LayoutPacks = append(res.LayoutPacks, newLayoutPackResponse(p))
+	}
+	encodeJSON(w, http.StatusOK, res, s.Logger)
+}
+
+// LayoutPackID returns a single specified layout pack
+func (s *Service) LayoutPackID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := httprouter.GetParamFromContext(ctx, "id")
+
+	p, err := s.Store.LayoutPacks(ctx).Get(ctx, id)
+	if err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	encodeJSON(w, http.StatusOK, newLayoutPackResponse(p), s.Logger)
+}
+
+// NewLayoutPack uploads a new layout pack whose layouts are served next to
+// the canned layouts to the users of the organization
+func (s *Service) NewLayoutPack(w http.ResponseWriter, r *http.Request) {
+	var pack chronograf.LayoutPack
+	if err := json.NewDecoder(r.Body).Decode(&pack); err != nil {
+		invalidJSON(w, s.Logger)
+		return
+	}
+
+	ctx := r.Context()
+	defaultOrg, err := s.Store.Organizations(ctx).DefaultOrganization(ctx)
+	if err != nil {
+		unknownErrorWithMessage(w, err, s.Logger)
+		return
+	}
+
+	if err := ValidLayoutPackRequest(&pack, defaultOrg.ID); err != nil {
+		invalidData(w, err, s.Logger)
+		return
+	}
+
+	if pack, err = s.Store.LayoutPacks(ctx).Add(ctx, pack); err != nil {
+		msg := fmt.Errorf("error storing layout pack %s: %v", pack.Name, err)
+		unknownErrorWithMessage(w, msg, s.Logger)
+		return
+	}
+
+	res := newLayoutPackResponse(pack)
+	location(w, res.Links.Self)
+	encodeJSON(w, http.StatusCreated, res, s.Logger)
+}
+
+// ReplaceLayoutPack completely replaces a layout pack
+func (s *Service) ReplaceLayoutPack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := httprouter.GetParamFromContext(ctx, "id")
+
+	orig, err := s.Store.LayoutPacks(ctx).Get(ctx, id)
+	if err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	var req chronograf.LayoutPack
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidJSON(w, s.Logger)
+		return
+	}
+	req.ID = id
+	req.Organization = orig.Organization
+
+	if err := ValidLayoutPackRequest(&req, orig.Organization); err != nil {
+		invalidData(w, err, s.Logger)
+		return
+	}
+
+	if err := s.Store.LayoutPacks(ctx).Update(ctx, req); err != nil {
+		msg := fmt.Sprintf("Error updating layout pack ID %s: %v", id, err)
+		Error(w, http.StatusInternalServerError, msg, s.Logger)
+		return
+	}
+
+	// Layouts without ID were given one by the store
+	if req, err = s.Store.LayoutPacks(ctx).Get(ctx, id); err != nil {
+		unknownErrorWithMessage(w, err, s.Logger)
+		return
+	}
+
+	encodeJSON(w, http.StatusOK, newLayoutPackResponse(req), s.Logger)
+}
+
+// RemoveLayoutPack deletes a layout pack and its layouts
+func (s *Service) RemoveLayoutPack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := httprouter.GetParamFromContext(ctx, "id")
+
+	p, err := s.Store.LayoutPacks(ctx).Get(ctx, id)
+	if err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	if err := s.Store.LayoutPacks(ctx).Delete(ctx, p); err != nil {
+		unknownErrorWithMessage(w, err, s.Logger)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ValidLayoutPackRequest verifies that the layout pack is named and that its
+// layouts can be matched against measurements
+func ValidLayoutPackRequest(p *chronograf.LayoutPack, defaultOrgID string) error {
+	if p.Organization == "" {
+		p.Organization = defaultOrgID
+	}
+	if p.Name == "" {
+		return fmt.Errorf("layout pack requires a name")
+	}
+	if len(p.Layouts) == 0 {
+		return fmt.Errorf("layout pack %s has no layouts", p.Name)
+	}
+
+	ids := make(map[string]bool, len(p.Layouts))
+	for i, l := range p.Layouts {
+		if l.Application == "" || l.Measurement == "" {
+			return fmt.Errorf("layout %d of pack %s requires an app and a measurement", i, p.Name)
+		}
+		if l.ID == "" {
+			continue
+		}
+		if ids[l.ID] {
+			return fmt.Errorf("layout ID %s is used several times in pack %s", l.ID, p.Name)
+		}
+		ids[l.ID] = true
+	}
+	return nil
+}
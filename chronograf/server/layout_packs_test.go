@@ -0,0 +1,194 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/mocks"
+	"github.com/influxdata/influxdb/chronograf/organizations"
+	"github.com/influxdata/influxdb/chronograf/server"
+)
+
+func TestService_NewLayoutPack(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantPack   chronograf.LayoutPack
+	}{
+		{
+			name:       "valid pack",
+			body:       `{"name":"team","layouts":[{"app":"billing","measurement":"invoices","cells":[{"w":4,"h":4,"name":"total"}]}]}`,
+			wantStatus: http.StatusCreated,
+			wantPack: chronograf.LayoutPack{
+				Name:         "team",
+				Organization: "default",
+				Layouts: []chronograf.Layout{
+					{
+						Application: "billing",
+						Measurement: "invoices",
+						Cells:       []chronograf.Cell{{W: 4, H: 4, Name: "total"}},
+					},
+				},
+			},
+		},
+		{
+			name:       "missing name",
+			body:       `{"layouts":[{"app":"billing","measurement":"invoices"}]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no layouts",
+			body:       `{"name":"team","layouts":[]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "layout without measurement",
+			body:       `{"name":"team","layouts":[{"app":"billing"}]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "duplicate layout IDs",
+			body:       `{"name":"team","layouts":[{"id":"a","app":"billing","measurement":"invoices"},{"id":"a","app":"billing","measurement":"refunds"}]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added chronograf.LayoutPack
+			s := &server.Service{
+				Store: &mocks.Store{
+					OrganizationsStore: &mocks.OrganizationsStore{
+						DefaultOrganizationF: func(ctx context.Context) (*chronograf.Organization, error) {
+							return &chronograf.Organization{ID: "default"}, nil
+						},
+					},
+					LayoutPacksStore: &mocks.LayoutPacksStore{
+						AddF: func(ctx context.Context, p chronograf.LayoutPack) (chronograf.LayoutPack, error) {
+							added = p
+							p.ID = "1"
+							return p, nil
+						},
+					},
+				},
+				Logger: &chronograf.NoopLogger{},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "http://any.url", strings.NewReader(tt.body))
+			s.NewLayoutPack(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("NewLayoutPack() status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			if diff := cmp.Diff(added, tt.wantPack); diff != "" {
+				t.Errorf("NewLayoutPack() stored pack diff: %s", diff)
+			}
+			if loc := w.Header().Get("Location"); loc != "/chronograf/v1/layoutpacks/1" {
+				t.Errorf("NewLayoutPack() Location = %s", loc)
+			}
+		})
+	}
+}
+
+func TestStore_LayoutsOfLayoutPacks(t *testing.T) {
+	store := &server.Store{
+		LayoutsStore: &mocks.LayoutsStore{
+			AllF: func(ctx context.Context) ([]chronograf.Layout, error) {
+				return []chronograf.Layout{
+					{ID: "canned", Application: "system", Measurement: "cpu"},
+					{ID: "shared", Application: "system", Measurement: "mem"},
+				}, nil
+			},
+		},
+		LayoutPacksStore: &mocks.LayoutPacksStore{
+			AllF: func(ctx context.Context) ([]chronograf.LayoutPack, error) {
+				return []chronograf.LayoutPack{
+					{
+						ID:           "1",
+						Organization: "1337",
+						Layouts: []chronograf.Layout{
+							{ID: "shared", Application: "team", Measurement: "mem"},
+						},
+					},
+					{
+						ID:           "2",
+						Organization: "other",
+						Layouts: []chronograf.Layout{
+							{ID: "hidden", Application: "other", Measurement: "disk"},
+						},
+					},
+				}, nil
+			},
+		},
+	}
+
+	ctx := context.WithValue(context.Background(), organizations.ContextKey, "1337")
+	layouts, err := store.Layouts(ctx).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []chronograf.Layout{
+		{ID: "shared", Application: "team", Measurement: "mem"},
+		{ID: "canned", Application: "system", Measurement: "cpu"},
+	}
+	if diff := cmp.Diff(layouts, want); diff != "" {
+		t.Errorf("Layouts().All() diff: %s", diff)
+	}
+}
+
+func TestService_LayoutPacks(t *testing.T) {
+	s := &server.Service{
+		Store: &mocks.Store{
+			LayoutPacksStore: &mocks.LayoutPacksStore{
+				AllF: func(ctx context.Context) ([]chronograf.LayoutPack, error) {
+					return []chronograf.LayoutPack{
+						{
+							ID:           "1",
+							Name:         "team",
+							Organization: "default",
+							Layouts:      []chronograf.Layout{{ID: "l", Application: "app", Measurement: "m"}},
+						},
+					}, nil
+				},
+			},
+		},
+		Logger: &chronograf.NoopLogger{},
+	}
+
+	w := httptest.NewRecorder()
+	s.LayoutPacks(w, httptest.NewRequest("GET", "http://any.url", nil))
+
+	var res struct {
+		LayoutPacks []struct {
+			ID      string `json:"id"`
+			Layouts []struct {
+				Link struct {
+					Href string `json:"href"`
+				} `json:"link"`
+			} `json:"layouts"`
+			Links struct {
+				Self string `json:"self"`
+			} `json:"links"`
+		} `json:"layoutPacks"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.LayoutPacks) != 1 {
+		t.Fatalf("LayoutPacks() returned %d packs, want 1", len(res.LayoutPacks))
+	}
+	p := res.LayoutPacks[0]
+	if p.Links.Self != "/chronograf/v1/layoutpacks/1" || p.Layouts[0].Link.Href != "/chronograf/v1/layouts/l" {
+		t.Errorf("LayoutPacks() unexpected links %+v", p)
+	}
+}
@@ -14,14 +14,20 @@ import (
 )
 
 func (s *Service) mapPrincipalToSuperAdmin(p oauth2.Principal) bool {
-	if p.Issuer != "auth0" {
+	var superAdminGroup string
+	switch {
+	case p.Scheme == oauth2.SAMLScheme:
+		superAdminGroup = s.SuperAdminProviderGroups.saml
+	case p.Issuer == "auth0":
+		superAdminGroup = s.SuperAdminProviderGroups.auth0
+	default:
 		return false
 	}
 
 	groups := strings.Split(p.Group, ",")
 	superAdmin := false
 	for _, group := range groups {
-		if group != "" && group == s.SuperAdminProviderGroups.auth0 {
+		if group != "" && group == superAdminGroup {
 			superAdmin = true
 			break
 		}
@@ -63,7 +69,7 @@ func applyMapping(m chronograf.Mapping, p oauth2.Principal) bool {
 	}
 
 	switch m.Scheme {
-	case chronograf.MappingWildcard, "oauth2":
+	case chronograf.MappingWildcard, principalScheme(p):
 	default:
 		return false
 	}
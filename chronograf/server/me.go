@@ -53,11 +53,21 @@ func newMeResponse(usr *chronograf.User, org string) meResponse {
 	}
 }
 
-// TODO: This Scheme value is hard-coded temporarily since we only currently
-// support OAuth2. This hard-coding should be removed whenever we add
-// support for other authentication schemes.
+// getScheme returns the authentication scheme of the principal in the
+// context; principals without scheme are authenticated with OAuth2.
 func getScheme(ctx context.Context) (string, error) {
-	return "oauth2", nil
+	p, err := getPrincipal(ctx)
+	if err != nil {
+		return "", err
+	}
+	return principalScheme(p), nil
+}
+
+func principalScheme(p oauth2.Principal) string {
+	if p.Scheme == "" {
+		return "oauth2"
+	}
+	return p.Scheme
 }
 
 func getPrincipal(ctx context.Context) (oauth2.Principal, error) {
@@ -272,10 +282,7 @@ func (s *Service) Me(w http.ResponseWriter, r *http.Request) {
 	user := &chronograf.User{
 		Name:     p.Subject,
 		Provider: p.Issuer,
-		// TODO: This Scheme value is hard-coded temporarily since we only currently
-		// support OAuth2. This hard-coding should be removed whenever we add
-		// support for other authentication schemes.
-		Scheme: scheme,
+		Scheme:   scheme,
 		// TODO(desa): this needs a better name
 		SuperAdmin: s.newUsersAreSuperAdmin(),
 	}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxql"
+)
+
+// MetaCache caches per source the responses of the meta queries sent by the
+// query builder, such as SHOW MEASUREMENTS or SHOW TAG VALUES, so that
+// opening a cell editor does not query InfluxDB every time. A nil MetaCache
+// caches nothing.
+type MetaCache struct {
+	TTL time.Duration
+	Now func() time.Time
+
+	mu      sync.Mutex
+	sources map[int]map[metaCacheKey]metaCacheEntry
+}
+
+// metaCacheKey holds the fields of a query changing its response.
+type metaCacheKey struct {
+	command, db, rp, epoch string
+}
+
+type metaCacheEntry struct {
+	response chronograf.Response
+	expires  time.Time
+}
+
+// NewMetaCache returns a MetaCache keeping responses during ttl. A zero ttl
+// disables the cache.
+func NewMetaCache(ttl time.Duration) *MetaCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &MetaCache{
+		TTL:     ttl,
+		Now:     time.Now,
+		sources: map[int]map[metaCacheKey]metaCacheEntry{},
+	}
+}
+
+// Get returns the unexpired response of the query on the source.
+func (c *MetaCache) Get(srcID int, q chronograf.Query) (chronograf.Response, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := newMetaCacheKey(q)
+	e, ok := c.sources[srcID][key]
+	if !ok {
+		return nil, false
+	}
+	if !c.Now().Before(e.expires) {
+		delete(c.sources[srcID], key)
+		return nil, false
+	}
+	return e.response, true
+}
+
+// Set caches the response of the query on the source if it is a meta query.
+func (c *MetaCache) Set(srcID int, q chronograf.Query, res chronograf.Response) {
+	if c == nil || !isMetaQuery(q.Command) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.Now()
+	entries, ok := c.sources[srcID]
+	if !ok {
+		entries = map[metaCacheKey]metaCacheEntry{}
+		c.sources[srcID] = entries
+	}
+	// Tag values filtered by the query builder make many distinct queries,
+	// drop the expired ones instead of letting them pile up.
+	for k, e := range entries {
+		if !now.Before(e.expires) {
+			delete(entries, k)
+		}
+	}
+	entries[newMetaCacheKey(q)] = metaCacheEntry{
+		response: res,
+		expires:  now.Add(c.TTL),
+	}
+}
+
+// Invalidate removes the cached responses of the source.
+func (c *MetaCache) Invalidate(srcID int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sources, srcID)
+}
+
+func newMetaCacheKey(q chronograf.Query) metaCacheKey {
+	return metaCacheKey{
+		command: q.Command,
+		db:      q.DB,
+		rp:      q.RP,
+		epoch:   q.Epoch,
+	}
+}
+
+// isMetaQuery returns true if all the statements of the command show the
+// schema of the source.
+func isMetaQuery(command string) bool {
+	q, err := influxql.ParseQuery(command)
+	if err != nil || len(q.Statements) == 0 {
+		return false
+	}
+	for _, stmt := range q.Statements {
+		switch stmt.(type) {
+		case *influxql.ShowDatabasesStatement,
+			*influxql.ShowRetentionPoliciesStatement,
+			*influxql.ShowMeasurementsStatement,
+			*influxql.ShowTagKeysStatement,
+			*influxql.ShowTagValuesStatement,
+			*influxql.ShowFieldKeysStatement:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// modifiesSource returns true if a statement of the command may change the
+// schema of the source, such as DROP MEASUREMENT or SELECT INTO.
+func modifiesSource(command string) bool {
+	q, err := influxql.ParseQuery(command)
+	if err != nil {
+		return false
+	}
+	for _, stmt := range q.Statements {
+		privileges, err := stmt.RequiredPrivileges()
+		if err != nil {
+			return true
+		}
+		for _, p := range privileges {
+			if p.Privilege != influxql.ReadPrivilege {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// InvalidateMetaCache drops the cached meta queries of a source so that the
+// query builder sees changes made outside of chronograf
+func (s *Service) InvalidateMetaCache(w http.ResponseWriter, r *http.Request) {
+	id, err := paramID("id", r)
+	if err != nil {
+		Error(w, http.StatusUnprocessableEntity, err.Error(), s.Logger)
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := s.Store.Sources(ctx).Get(ctx, id); err != nil {
+		notFound(w, id, s.Logger)
+		return
+	}
+
+	s.MetaCache.Invalidate(id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/mocks"
+)
+
+func Test_isMetaQuery(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{`SHOW DATABASES`, true},
+		{`SHOW MEASUREMENTS ON "telegraf"`, true},
+		{`SHOW TAG KEYS ON "telegraf" FROM "cpu"; SHOW FIELD KEYS ON "telegraf" FROM "cpu"`, true},
+		{`SHOW TAG VALUES ON "telegraf" FROM "cpu" WITH KEY = "host"`, true},
+		{`SHOW RETENTION POLICIES ON "telegraf"`, true},
+		{`SHOW DATABASES; SELECT * FROM cpu`, false},
+		{`SELECT mean(usage_user) FROM cpu`, false},
+		{`SHOW QUERIES`, false},
+		{`SHOW`, false},
+	}
+	for _, tt := range tests {
+		if got := isMetaQuery(tt.command); got != tt.want {
+			t.Errorf("isMetaQuery(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func Test_modifiesSource(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{`SHOW MEASUREMENTS`, false},
+		{`SELECT mean(usage_user) FROM cpu`, false},
+		{`SELECT mean(usage_user) INTO cpu_1h FROM cpu GROUP BY time(1h)`, true},
+		{`DROP MEASUREMENT "cpu"`, true},
+		{`SHOW DATABASES; CREATE DATABASE "db"`, true},
+	}
+	for _, tt := range tests {
+		if got := modifiesSource(tt.command); got != tt.want {
+			t.Errorf("modifiesSource(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestMetaCache(t *testing.T) {
+	now := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMetaCache(time.Minute)
+	c.Now = func() time.Time { return now }
+
+	show := chronograf.Query{Command: `SHOW MEASUREMENTS`, DB: "telegraf"}
+	res := mocks.NewResponse(`{"results":[]}`, nil)
+
+	c.Set(1, chronograf.Query{Command: `SELECT * FROM cpu`}, res)
+	if _, ok := c.Get(1, chronograf.Query{Command: `SELECT * FROM cpu`}); ok {
+		t.Error("expected data queries not to be cached")
+	}
+
+	c.Set(1, show, res)
+	if _, ok := c.Get(1, show); !ok {
+		t.Error("expected meta query to be cached")
+	}
+	if _, ok := c.Get(1, chronograf.Query{Command: show.Command, DB: "other"}); ok {
+		t.Error("expected cache to depend on the database")
+	}
+	if _, ok := c.Get(2, show); ok {
+		t.Error("expected cache to depend on the source")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get(1, show); ok {
+		t.Error("expected cached response to expire")
+	}
+
+	c.Set(1, show, res)
+	c.Invalidate(1)
+	if _, ok := c.Get(1, show); ok {
+		t.Error("expected cached response to be invalidated")
+	}
+
+	disabled := NewMetaCache(0)
+	disabled.Set(1, show, res)
+	if _, ok := disabled.Get(1, show); ok {
+		t.Error("expected disabled cache to cache nothing")
+	}
+}
+
+func TestService_Influx_MetaCache(t *testing.T) {
+	queries := 0
+	s := &Service{
+		Store: &mocks.Store{
+			SourcesStore: &mocks.SourcesStore{
+				GetF: func(ctx context.Context, ID int) (chronograf.Source, error) {
+					return chronograf.Source{ID: ID, URL: "http://any.url"}, nil
+				},
+			},
+		},
+		TimeSeriesClient: &mocks.TimeSeries{
+			ConnectF: func(ctx context.Context, src *chronograf.Source) error {
+				return nil
+			},
+			QueryF: func(ctx context.Context, query chronograf.Query) (chronograf.Response, error) {
+				queries++
+				return mocks.NewResponse(`{"results":[{"statement_id":0}]}`, nil), nil
+			},
+		},
+		Logger:    &chronograf.NoopLogger{},
+		MetaCache: NewMetaCache(time.Minute),
+	}
+
+	ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{
+		{Key: "id", Value: "1"},
+	})
+	do := func(h http.HandlerFunc, method, body string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://any.url", strings.NewReader(body)).WithContext(ctx)
+		h(w, r)
+		return w.Code
+	}
+
+	show := `{"db":"telegraf","query":"SHOW TAG KEYS FROM cpu"}`
+	for i := 0; i < 2; i++ {
+		if code := do(s.Influx, "POST", show); code != http.StatusOK {
+			t.Fatalf("Influx() status = %d", code)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("expected meta query to be sent once, got %d", queries)
+	}
+
+	if code := do(s.InvalidateMetaCache, "DELETE", ""); code != http.StatusNoContent {
+		t.Fatalf("InvalidateMetaCache() status = %d", code)
+	}
+	do(s.Influx, "POST", show)
+	if queries != 2 {
+		t.Errorf("expected meta query to be sent again after invalidation, got %d", queries)
+	}
+
+	do(s.Influx, "POST", `{"db":"telegraf","query":"DROP MEASUREMENT cpu"}`)
+	do(s.Influx, "POST", show)
+	if queries != 4 {
+		t.Errorf("expected meta query to be sent again after a DROP, got %d", queries)
+	}
+}
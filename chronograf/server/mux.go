@@ -169,6 +169,9 @@ func NewMux(opts MuxOpts, service Service) http.Handler {
 	influx := gziphandler.GzipHandler(http.HandlerFunc(EnsureViewer(service.Influx)))
 	router.Handler("POST", "/chronograf/v1/sources/:id/proxy", influx)
 
+	// Responses of meta queries sent to the proxy are cached, this drops them
+	router.DELETE("/chronograf/v1/sources/:id/metacache", EnsureViewer(service.InvalidateMetaCache))
+
 	// Write proxies line protocol write requests to InfluxDB
 	router.POST("/chronograf/v1/sources/:id/write", EnsureViewer(service.Write))
 
@@ -183,7 +186,9 @@ func NewMux(opts MuxOpts, service Service) http.Handler {
 	// Annotations are user-defined events associated with this source
 	router.GET("/chronograf/v1/sources/:id/annotations", EnsureViewer(service.Annotations))
 	router.POST("/chronograf/v1/sources/:id/annotations", EnsureEditor(service.NewAnnotation))
+	router.POST("/chronograf/v1/sources/:id/annotations/import", EnsureEditor(service.ImportAnnotations))
 	router.GET("/chronograf/v1/sources/:id/annotations/:aid", EnsureViewer(service.Annotation))
+	router.GET("/chronograf/v1/sources/:id/annotations/:aid/versions", EnsureViewer(service.AnnotationVersions))
 	router.DELETE("/chronograf/v1/sources/:id/annotations/:aid", EnsureEditor(service.RemoveAnnotation))
 	router.PATCH("/chronograf/v1/sources/:id/annotations/:aid", EnsureEditor(service.UpdateAnnotation))
 
@@ -246,6 +251,14 @@ func NewMux(opts MuxOpts, service Service) http.Handler {
 	router.GET("/chronograf/v1/layouts", EnsureViewer(service.Layouts))
 	router.GET("/chronograf/v1/layouts/:id", EnsureViewer(service.LayoutsID))
 
+	// Layout packs uploaded by the organization
+	router.GET("/chronograf/v1/layoutpacks", EnsureViewer(service.LayoutPacks))
+	router.POST("/chronograf/v1/layoutpacks", EnsureEditor(service.NewLayoutPack))
+
+	router.GET("/chronograf/v1/layoutpacks/:id", EnsureViewer(service.LayoutPackID))
+	router.DELETE("/chronograf/v1/layoutpacks/:id", EnsureEditor(service.RemoveLayoutPack))
+	router.PUT("/chronograf/v1/layoutpacks/:id", EnsureEditor(service.ReplaceLayoutPack))
+
 	// Users associated with Chronograf
 	router.GET("/chronograf/v1/me", service.Me)
 
@@ -364,6 +377,12 @@ func AuthAPI(opts MuxOpts, router chronograf.Router) (http.Handler, AuthRoutes)
 			router.Handler("GET", loginPath, m.Login())
 			router.Handler("GET", logoutPath, m.Logout())
 			router.Handler("GET", callbackPath, m.Callback())
+			// SAML responses are posted to the callback and identity providers
+			// are configured with the metadata of Chronograf.
+			if mm, ok := m.(oauth2.MetadataMux); ok {
+				router.Handler("POST", callbackPath, mm.Callback())
+				router.Handler("GET", path.Join("/oauth", urlName, "metadata"), mm.Metadata())
+			}
 			routes = append(routes, AuthRoute{
 				Name:  p.Name(),
 				Label: strings.Title(p.Name()),
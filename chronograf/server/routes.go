@@ -32,6 +32,7 @@ func (r *AuthRoutes) Lookup(provider string) (AuthRoute, bool) {
 
 type getRoutesResponse struct {
 	Layouts            string                             `json:"layouts"`          // Location of the layouts endpoint
+	LayoutPacks        string                             `json:"layoutPacks"`      // Location of the layout packs endpoint
 	Users              string                             `json:"users"`            // Location of the users endpoint
 	AllUsers           string                             `json:"allUsers"`         // Location of the raw users endpoint
 	Organizations      string                             `json:"organizations"`    // Location of the organizations endpoint
@@ -82,6 +83,7 @@ func (a *AllRoutes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	routes := getRoutesResponse{
 		Sources:       "/chronograf/v1/sources",
 		Layouts:       "/chronograf/v1/layouts",
+		LayoutPacks:   "/chronograf/v1/layoutpacks",
 		Users:         fmt.Sprintf("/chronograf/v1/organizations/%s/users", org),
 		AllUsers:      "/chronograf/v1/users",
 		Organizations: "/chronograf/v1/organizations",
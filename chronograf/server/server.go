@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math/rand"
 	"net"
@@ -14,6 +15,7 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	bbolt "github.com/coreos/bbolt"
@@ -55,14 +57,16 @@ type Server struct {
 
 	NewSources string `long:"new-sources" description:"Config for adding a new InfluxDB source and Kapacitor server, in JSON as an array of objects, and surrounded by single quotes. E.g. --new-sources='[{\"influxdb\":{\"name\":\"Influx 1\",\"username\":\"user1\",\"password\":\"pass1\",\"url\":\"http://localhost:8086\",\"metaUrl\":\"http://metaurl.com\",\"type\":\"influx-enterprise\",\"insecureSkipVerify\":false,\"default\":true,\"telegraf\":\"telegraf\",\"sharedSecret\":\"cubeapples\"},\"kapacitor\":{\"name\":\"Kapa 1\",\"url\":\"http://localhost:9092\",\"active\":true}}]'" env:"NEW_SOURCES" hidden:"true"`
 
-	Develop       bool          `short:"d" long:"develop" description:"Run server in develop mode."`
-	BoltPath      string        `short:"b" long:"bolt-path" description:"Full path to boltDB file (e.g. './chronograf-v1.db')" env:"BOLT_PATH" default:"chronograf-v1.db"`
-	CannedPath    string        `short:"c" long:"canned-path" description:"Path to directory of pre-canned application layouts (/usr/share/chronograf/canned)" env:"CANNED_PATH" default:"canned"`
-	ResourcesPath string        `long:"resources-path" description:"Path to directory of pre-canned dashboards, sources, kapacitors, and organizations (/usr/share/chronograf/resources)" env:"RESOURCES_PATH" default:"canned"`
-	TokenSecret   string        `short:"t" long:"token-secret" description:"Secret to sign tokens" env:"TOKEN_SECRET"`
-	JwksURL       string        `long:"jwks-url" description:"URL that returns OpenID Key Discovery JWKS document." env:"JWKS_URL"`
-	UseIDToken    bool          `long:"use-id-token" description:"Enable id_token processing." env:"USE_ID_TOKEN"`
-	AuthDuration  time.Duration `long:"auth-duration" default:"720h" description:"Total duration of cookie life for authentication (in hours). 0 means authentication expires on browser close." env:"AUTH_DURATION"`
+	Develop         bool          `short:"d" long:"develop" description:"Run server in develop mode."`
+	BoltPath        string        `short:"b" long:"bolt-path" description:"Full path to boltDB file (e.g. './chronograf-v1.db')" env:"BOLT_PATH" default:"chronograf-v1.db"`
+	CannedPath      string        `short:"c" long:"canned-path" description:"Path to directory of pre-canned application layouts (/usr/share/chronograf/canned)" env:"CANNED_PATH" default:"canned"`
+	ResourcesPath   string        `long:"resources-path" description:"Path to directory of pre-canned dashboards, sources, kapacitors, and organizations (/usr/share/chronograf/resources)" env:"RESOURCES_PATH" default:"canned"`
+	TokenSecret     string        `short:"t" long:"token-secret" description:"Secret to sign tokens" env:"TOKEN_SECRET"`
+	JwksURL         string        `long:"jwks-url" description:"URL that returns OpenID Key Discovery JWKS document." env:"JWKS_URL"`
+	UseIDToken      bool          `long:"use-id-token" description:"Enable id_token processing." env:"USE_ID_TOKEN"`
+	AuthDuration    time.Duration `long:"auth-duration" default:"720h" description:"Total duration of cookie life for authentication (in hours). 0 means authentication expires on browser close." env:"AUTH_DURATION"`
+	AuthIdleTimeout time.Duration `long:"auth-idle-timeout" default:"5m" description:"Duration without any activity after which users are logged out" env:"AUTH_IDLE_TIMEOUT"`
+	AuthLifetime    time.Duration `long:"auth-lifetime" description:"Duration after login after which users are logged out regardless of their activity. 0 means the auth-duration." env:"AUTH_LIFETIME"`
 
 	GithubClientID     string   `short:"i" long:"github-client-id" description:"Github Client ID for OAuth 2 support" env:"GH_CLIENT_ID"`
 	GithubClientSecret string   `short:"s" long:"github-client-secret" description:"Github Client Secret for OAuth 2 support" env:"GH_CLIENT_SECRET"`
@@ -93,9 +97,19 @@ type Server struct {
 	Auth0Organizations []string `long:"auth0-organizations" description:"Auth0 organizations permitted to access Chronograf (comma separated)" env:"AUTH0_ORGS" env-delim:","`
 	Auth0SuperAdminOrg string   `long:"auth0-superadmin-org" description:"Auth0 organization from which users are automatically granted SuperAdmin status" env:"AUTH0_SUPERADMIN_ORG"`
 
+	SAMLName            string         `long:"saml-name" description:"SAML 2.0 name presented on the login page" default:"saml" env:"SAML_NAME"`
+	SAMLEntityID        string         `long:"saml-entity-id" description:"SAML 2.0 entity ID of Chronograf. Defaults to the URL of its metadata." env:"SAML_ENTITY_ID"`
+	SAMLIDPSSOURL       string         `long:"saml-idp-sso-url" description:"Single sign-on URL of the SAML 2.0 identity provider (HTTP-Redirect binding)" env:"SAML_IDP_SSO_URL"`
+	SAMLIDPEntityID     string         `long:"saml-idp-entity-id" description:"Entity ID of the SAML 2.0 identity provider, required as the issuer of assertions" env:"SAML_IDP_ENTITY_ID"`
+	SAMLIDPCertificate  flags.Filename `long:"saml-idp-certificate" description:"Path to the PEM encoded certificates signing the responses of the SAML 2.0 identity provider" env:"SAML_IDP_CERTIFICATE"`
+	SAMLNameAttribute   string         `long:"saml-name-attribute" description:"SAML 2.0 assertion attribute identifying users. Defaults to the NameID of the subject." env:"SAML_NAME_ATTRIBUTE"`
+	SAMLGroupAttribute  string         `long:"saml-group-attribute" description:"SAML 2.0 assertion attribute whose values are the groups of users, matched by mappings" env:"SAML_GROUP_ATTRIBUTE"`
+	SAMLSuperAdminGroup string         `long:"saml-superadmin-group" description:"SAML 2.0 group from which users are automatically granted SuperAdmin status" env:"SAML_SUPERADMIN_GROUP"`
+
 	StatusFeedURL          string            `long:"status-feed-url" description:"URL of a JSON Feed to display as a News Feed on the client Status page." default:"https://www.influxdata.com/feed/json" env:"STATUS_FEED_URL"`
 	CustomLinks            map[string]string `long:"custom-link" description:"Custom link to be added to the client User menu. Multiple links can be added by using multiple of the same flag with different 'name:url' values, or as an environment variable with comma-separated 'name:url' values. E.g. via flags: '--custom-link=InfluxData:https://www.influxdata.com --custom-link=Chronograf:https://github.com/influxdata/influxdb/chronograf'. E.g. via environment variable: 'export CUSTOM_LINKS=InfluxData:https://www.influxdata.com,Chronograf:https://github.com/influxdata/influxdb/chronograf'" env:"CUSTOM_LINKS" env-delim:","`
 	TelegrafSystemInterval time.Duration     `long:"telegraf-system-interval" default:"1m" description:"Duration used in the GROUP BY time interval for the hosts list" env:"TELEGRAF_SYSTEM_INTERVAL"`
+	MetaCacheTTL           time.Duration     `long:"meta-cache-ttl" default:"1m" description:"Duration the responses of the meta queries of the query builder (SHOW DATABASES, MEASUREMENTS, TAG KEYS, TAG VALUES, ...) are cached per source. 0 disables the cache." env:"META_CACHE_TTL"`
 
 	ReportingDisabled bool   `short:"r" long:"reporting-disabled" description:"Disable reporting of usage stats (os,arch,version,cluster_id,uptime) once every 24hr" env:"REPORTING_DISABLED"`
 	LogLevel          string `short:"l" long:"log-level" value-name:"choice" choice:"debug" choice:"info" choice:"error" default:"info" description:"Set the logging level" env:"LOG_LEVEL"` //lint:ignore SA5008 duplicate tag choice is expected with go-flags.
@@ -141,6 +155,11 @@ func (s *Server) UseGenericOAuth2() bool {
 		s.GenericTokenURL != ""
 }
 
+// UseSAML validates the CLI parameters to enable SAML 2.0 support
+func (s *Server) UseSAML() bool {
+	return s.TokenSecret != "" && s.PublicURL != "" && s.SAMLIDPSSOURL != "" && s.SAMLIDPCertificate != ""
+}
+
 func (s *Server) githubOAuth(logger chronograf.Logger, auth oauth2.Authenticator) (oauth2.Provider, oauth2.Mux, func() bool) {
 	gh := oauth2.Github{
 		ClientID:     s.GithubClientID,
@@ -219,6 +238,45 @@ func (s *Server) auth0OAuth(logger chronograf.Logger, auth oauth2.Authenticator)
 	return &auth0, genMux, s.UseAuth0
 }
 
+func (s *Server) samlAuth(logger chronograf.Logger, auth oauth2.Authenticator) (oauth2.Provider, oauth2.Mux, func() bool) {
+	saml := oauth2.SAML{
+		PageName:       s.SAMLName,
+		EntityID:       s.SAMLEntityID,
+		IDPSSOURL:      s.SAMLIDPSSOURL,
+		IDPEntityID:    s.SAMLIDPEntityID,
+		NameAttribute:  s.SAMLNameAttribute,
+		GroupAttribute: s.SAMLGroupAttribute,
+		Logger:         logger,
+	}
+	jwt := oauth2.NewJWT(s.TokenSecret, s.JwksURL)
+	samlMux := oauth2.NewSAMLMux(&saml, auth, jwt, s.Basepath, logger)
+	if !s.UseSAML() {
+		return &saml, samlMux, s.UseSAML
+	}
+
+	publicURL, err := url.Parse(s.PublicURL)
+	if err != nil {
+		logger.Error("Error parsing public URL: err:", err)
+		return &saml, samlMux, func() bool { return false }
+	}
+	publicURL.Path = path.Join(publicURL.Path, s.Basepath, "oauth", strings.ToLower(saml.Name()), "callback")
+	saml.ACSURL = publicURL.String()
+	if saml.EntityID == "" {
+		publicURL.Path = path.Join(path.Dir(publicURL.Path), "metadata")
+		saml.EntityID = publicURL.String()
+	}
+
+	data, err := ioutil.ReadFile(string(s.SAMLIDPCertificate))
+	if err == nil {
+		saml.IDPCertificates, err = oauth2.ParseSAMLCertificates(data)
+	}
+	if err != nil {
+		logger.Error("Error reading SAML identity provider certificate: err:", err)
+		return &saml, samlMux, func() bool { return false }
+	}
+	return &saml, samlMux, s.UseSAML
+}
+
 func (s *Server) genericRedirectURL() string {
 	if s.PublicURL == "" {
 		return ""
@@ -239,7 +297,7 @@ func (s *Server) genericRedirectURL() string {
 }
 
 func (s *Server) useAuth() bool {
-	return s.UseGithub() || s.UseGoogle() || s.UseHeroku() || s.UseGenericOAuth2() || s.UseAuth0()
+	return s.UseGithub() || s.UseGoogle() || s.UseHeroku() || s.UseGenericOAuth2() || s.UseAuth0() || s.UseSAML()
 }
 
 func (s *Server) useTLS() bool {
@@ -334,10 +392,12 @@ func (s *Server) Serve(ctx context.Context) error {
 	service := openService(ctx, s.BuildInfo, s.BoltPath, s.newBuilders(logger), logger, s.useAuth())
 	service.SuperAdminProviderGroups = superAdminProviderGroups{
 		auth0: s.Auth0SuperAdminOrg,
+		saml:  s.SAMLSuperAdminGroup,
 	}
 	service.Env = chronograf.Environment{
 		TelegrafSystemInterval: s.TelegrafSystemInterval,
 	}
+	service.MetaCache = NewMetaCache(s.MetaCacheTTL)
 	if err := service.HandleNewSources(ctx, s.NewSources); err != nil {
 		logger.
 			WithField("component", "server").
@@ -357,12 +417,13 @@ func (s *Server) Serve(ctx context.Context) error {
 
 	providerFuncs := []func(func(oauth2.Provider, oauth2.Mux)){}
 
-	auth := oauth2.NewCookieJWT(s.TokenSecret, s.AuthDuration)
+	auth := oauth2.NewCookieJWTWithTimeouts(s.TokenSecret, s.AuthDuration, s.AuthIdleTimeout, s.AuthLifetime)
 	providerFuncs = append(providerFuncs, provide(s.githubOAuth(logger, auth)))
 	providerFuncs = append(providerFuncs, provide(s.googleOAuth(logger, auth)))
 	providerFuncs = append(providerFuncs, provide(s.herokuOAuth(logger, auth)))
 	providerFuncs = append(providerFuncs, provide(s.genericOAuth(logger, auth)))
 	providerFuncs = append(providerFuncs, provide(s.auth0OAuth(logger, auth)))
+	providerFuncs = append(providerFuncs, provide(s.samlAuth(logger, auth)))
 
 	s.handler = NewMux(MuxOpts{
 		Develop:       s.Develop,
@@ -447,6 +508,7 @@ func NewServiceV2(ctx context.Context, d *bbolt.DB) (*Service, error) {
 		TimeSeriesClient: &InfluxClient{},
 		Store: &DirectStore{
 			LayoutsStore:            db.LayoutsStore,
+			LayoutPacksStore:        db.LayoutPacksStore,
 			DashboardsStore:         db.DashboardsStore,
 			SourcesStore:            db.SourcesStore,
 			ServersStore:            db.ServersStore,
@@ -518,6 +580,7 @@ func openService(ctx context.Context, buildInfo chronograf.BuildInfo, boltPath s
 		TimeSeriesClient: &InfluxClient{},
 		Store: &Store{
 			LayoutsStore:            layouts,
+			LayoutPacksStore:        db.LayoutPacksStore,
 			DashboardsStore:         dashboards,
 			SourcesStore:            sources,
 			ServersStore:            kapacitors,
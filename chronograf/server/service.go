@@ -18,10 +18,12 @@ type Service struct {
 	SuperAdminProviderGroups superAdminProviderGroups
 	Env                      chronograf.Environment
 	Databases                chronograf.Databases
+	MetaCache                *MetaCache
 }
 
 type superAdminProviderGroups struct {
 	auth0 string
+	saml  string
 }
 
 // TimeSeriesClient returns the correct client for a time series database.
@@ -226,6 +226,8 @@ func (s *Service) RemoveSource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.MetaCache.Invalidate(id)
+
 	// Remove all the associated kapacitors for this source
 	if err = s.removeSrcsKapa(ctx, id); err != nil {
 		unknownErrorWithMessage(w, err, s.Logger)
@@ -374,6 +376,7 @@ func (s *Service) UpdateSource(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusInternalServerError, msg, s.Logger)
 		return
 	}
+	s.MetaCache.Invalidate(id)
 	encodeJSON(w, http.StatusOK, newSourceResponse(context.Background(), src), s.Logger)
 }
 
@@ -386,9 +389,9 @@ func ValidSourceRequest(s *chronograf.Source, defaultOrgID string) error {
 	if s.URL == "" {
 		return fmt.Errorf("url required")
 	}
-	// Type must be influx or influx-enterprise
+	// Type must be influx, influx-enterprise, influx-relay or influx-v2
 	if s.Type != "" {
-		if s.Type != chronograf.InfluxDB && s.Type != chronograf.InfluxEnterprise && s.Type != chronograf.InfluxRelay {
+		if s.Type != chronograf.InfluxDB && s.Type != chronograf.InfluxEnterprise && s.Type != chronograf.InfluxRelay && s.Type != chronograf.InfluxDBv2 {
 			return fmt.Errorf("invalid source type %s", s.Type)
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/multistore"
 	"github.com/influxdata/influxdb/chronograf/noop"
 	"github.com/influxdata/influxdb/chronograf/organizations"
 	"github.com/influxdata/influxdb/chronograf/roles"
@@ -86,6 +87,7 @@ type DataStore interface {
 	Sources(ctx context.Context) chronograf.SourcesStore
 	Servers(ctx context.Context) chronograf.ServersStore
 	Layouts(ctx context.Context) chronograf.LayoutsStore
+	LayoutPacks(ctx context.Context) chronograf.LayoutPacksStore
 	Users(ctx context.Context) chronograf.UsersStore
 	Organizations(ctx context.Context) chronograf.OrganizationsStore
 	Mappings(ctx context.Context) chronograf.MappingsStore
@@ -102,6 +104,7 @@ type Store struct {
 	SourcesStore            chronograf.SourcesStore
 	ServersStore            chronograf.ServersStore
 	LayoutsStore            chronograf.LayoutsStore
+	LayoutPacksStore        chronograf.LayoutPacksStore
 	UsersStore              chronograf.UsersStore
 	DashboardsStore         chronograf.DashboardsStore
 	MappingsStore           chronograf.MappingsStore
@@ -136,11 +139,38 @@ func (s *Store) Servers(ctx context.Context) chronograf.ServersStore {
 	return &noop.ServersStore{}
 }
 
-// Layouts returns all layouts in the underlying layouts store.
+// Layouts returns all layouts in the underlying layouts store. If there is
+// an organization specified on context, the layouts of its layout packs are
+// returned first.
 func (s *Store) Layouts(ctx context.Context) chronograf.LayoutsStore {
+	if s.LayoutPacksStore == nil {
+		return s.LayoutsStore
+	}
+	if org, ok := hasOrganizationContext(ctx); ok {
+		return &multistore.Layouts{
+			Stores: []chronograf.LayoutsStore{
+				organizations.NewLayoutsStore(s.LayoutPacksStore, org),
+				s.LayoutsStore,
+			},
+		}
+	}
+
 	return s.LayoutsStore
 }
 
+// LayoutPacks returns a noop.LayoutPacksStore if the context has no organization specified
+// and an organization.LayoutPacksStore otherwise.
+func (s *Store) LayoutPacks(ctx context.Context) chronograf.LayoutPacksStore {
+	if isServer := hasServerContext(ctx); isServer {
+		return s.LayoutPacksStore
+	}
+	if org, ok := hasOrganizationContext(ctx); ok {
+		return organizations.NewLayoutPacksStore(s.LayoutPacksStore, org)
+	}
+
+	return &noop.LayoutPacksStore{}
+}
+
 // Users returns a chronograf.UsersStore.
 // If the context is a server context, then the underlying chronograf.UsersStore
 // is returned.
@@ -226,6 +256,7 @@ type DirectStore struct {
 	SourcesStore            chronograf.SourcesStore
 	ServersStore            chronograf.ServersStore
 	LayoutsStore            chronograf.LayoutsStore
+	LayoutPacksStore        chronograf.LayoutPacksStore
 	UsersStore              chronograf.UsersStore
 	DashboardsStore         chronograf.DashboardsStore
 	MappingsStore           chronograf.MappingsStore
@@ -251,6 +282,11 @@ func (s *DirectStore) Layouts(ctx context.Context) chronograf.LayoutsStore {
 	return s.LayoutsStore
 }
 
+// LayoutPacks returns the underlying LayoutPacksStore.
+func (s *DirectStore) LayoutPacks(ctx context.Context) chronograf.LayoutPacksStore {
+	return s.LayoutPacksStore
+}
+
 // Users returns a chronograf.UsersStore.
 // If the context is a server context, then the underlying chronograf.UsersStore
 // is returned.
@@ -104,6 +104,11 @@ func (s *Service) NewTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	var template chronograf.Template
 	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
 		invalidJSON(w, s.Logger)
@@ -177,6 +182,11 @@ func (s *Service) RemoveTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	tid := httprouter.GetParamFromContext(ctx, "tid")
 	pos := -1
 	for i, t := range dash.Templates {
@@ -215,6 +225,11 @@ func (s *Service) ReplaceTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canEditDashboard(ctx, dash) {
+		dashboardForbidden(w, s.Logger)
+		return
+	}
+
 	tid := httprouter.GetParamFromContext(ctx, "tid")
 	pos := -1
 	for i, t := range dash.Templates {
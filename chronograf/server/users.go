@@ -10,6 +10,7 @@ import (
 
 	"github.com/bouk/httprouter"
 	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/influxdata/influxdb/chronograf/roles"
 )
 
@@ -33,10 +34,11 @@ func (r *userRequest) ValidCreate() error {
 		return fmt.Errorf("scheme required on Chronograf User request body")
 	}
 
-	// TODO: This Scheme value is hard-coded temporarily since we only currently
-	// support OAuth2. This hard-coding should be removed whenever we add
-	// support for other authentication schemes.
-	r.Scheme = "oauth2"
+	// Users authenticate with OAuth2 unless they are created for a SAML
+	// identity provider.
+	if r.Scheme != oauth2.SAMLScheme {
+		r.Scheme = "oauth2"
+	}
 	return r.ValidRoles()
 }
 
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	// DefaultBatchSize is the number of lines written in one request.
+	DefaultBatchSize = 5000
+
+	// DefaultFlushInterval is how often buffered lines are written even if
+	// the batch is not full.
+	DefaultFlushInterval = time.Second
+)
+
+// BatchOption configures a BatchWriter.
+type BatchOption func(*BatchWriter)
+
+// WithBatchSize sets the number of lines written in one request.
+func WithBatchSize(n int) BatchOption {
+	return func(w *BatchWriter) {
+		if n > 0 {
+			w.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval sets how often buffered lines are written even if the
+// batch is not full; 0 writes only full batches and on Flush.
+func WithFlushInterval(d time.Duration) BatchOption {
+	return func(w *BatchWriter) {
+		w.flushInterval = d
+	}
+}
+
+// BatchWriter buffers lines of line protocol and writes them to a bucket in
+// batches. It is safe for concurrent use. Errors of writes in the background
+// are returned by the next call to WriteLine, Flush or Close.
+type BatchWriter struct {
+	svc      influxdb.WriteService
+	orgID    influxdb.ID
+	bucketID influxdb.ID
+
+	batchSize     int
+	flushInterval time.Duration
+
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	lines int
+	err   error
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewBatchWriter returns a BatchWriter that writes to the bucket with the write service.
+func NewBatchWriter(svc influxdb.WriteService, orgID, bucketID influxdb.ID, opts ...BatchOption) *BatchWriter {
+	w := &BatchWriter{
+		svc:           svc,
+		orgID:         orgID,
+		bucketID:      bucketID,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.flushInterval > 0 {
+		w.wg.Add(1)
+		go w.flushPeriodically()
+	}
+	return w
+}
+
+func (w *BatchWriter) flushPeriodically() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if err := w.flush(context.Background()); err != nil && w.err == nil {
+				w.err = err
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// WriteLine buffers a line of line protocol and writes the batch if it is full.
+func (w *BatchWriter) WriteLine(ctx context.Context, line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.takeErr(); err != nil {
+		return err
+	}
+
+	line = bytes.TrimRight(line, "\n")
+	if len(line) == 0 {
+		return nil
+	}
+	w.buf.Write(line)
+	w.buf.WriteByte('\n')
+	w.lines++
+
+	if w.lines < w.batchSize {
+		return nil
+	}
+	return w.flush(ctx)
+}
+
+// Flush writes the buffered lines.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.takeErr(); err != nil {
+		return err
+	}
+	return w.flush(ctx)
+}
+
+// Close stops writing in the background and writes the buffered lines.
+func (w *BatchWriter) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() { close(w.done) })
+	w.wg.Wait()
+
+	return w.Flush(ctx)
+}
+
+// takeErr returns and clears the error of a write in the background.
+func (w *BatchWriter) takeErr() error {
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// flush writes the buffered lines. It must be called with the mutex held.
+// The lines are dropped if the write fails, so that a rejected batch does
+// not block later writes.
+func (w *BatchWriter) flush(ctx context.Context) error {
+	if w.lines == 0 {
+		return nil
+	}
+	data := make([]byte, w.buf.Len())
+	copy(data, w.buf.Bytes())
+	w.buf.Reset()
+	w.lines = 0
+
+	return w.svc.Write(ctx, w.orgID, w.bucketID, bytes.NewReader(data))
+}
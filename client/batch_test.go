@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/mock"
+)
+
+type recordingWriter struct {
+	mu      sync.Mutex
+	batches []string
+	err     error
+}
+
+func (r *recordingWriter) service() *mock.WriteService {
+	return &mock.WriteService{
+		WriteF: func(ctx context.Context, orgID, bucketID influxdb.ID, rd io.Reader) error {
+			data, err := ioutil.ReadAll(rd)
+			if err != nil {
+				return err
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.batches = append(r.batches, string(data))
+			return r.err
+		},
+	}
+}
+
+func (r *recordingWriter) written() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.batches...)
+}
+
+func TestBatchWriter(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingWriter{}
+	w := client.NewBatchWriter(rec.service(), 1, 2, client.WithBatchSize(2), client.WithFlushInterval(0))
+
+	for _, line := range []string{"m f=1 1", "m f=2 2\n", "", "m f=3 3"} {
+		if err := w.WriteLine(ctx, []byte(line)); err != nil {
+			t.Fatalf("unexpected error writing line: %v", err)
+		}
+	}
+	if got := rec.written(); len(got) != 1 || got[0] != "m f=1 1\nm f=2 2\n" {
+		t.Fatalf("expected a full batch to be written, got %q", got)
+	}
+
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("unexpected error closing writer: %v", err)
+	}
+	if got := rec.written(); len(got) != 2 || got[1] != "m f=3 3\n" {
+		t.Fatalf("expected buffered lines to be written on close, got %q", got)
+	}
+}
+
+func TestBatchWriter_FlushInterval(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingWriter{}
+	w := client.NewBatchWriter(rec.service(), 1, 2, client.WithFlushInterval(10*time.Millisecond))
+	defer w.Close(ctx)
+
+	if err := w.WriteLine(ctx, []byte("m f=1 1")); err != nil {
+		t.Fatalf("unexpected error writing line: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.written()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected buffered lines to be written in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchWriter_Error(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingWriter{err: errors.New("bad request")}
+	w := client.NewBatchWriter(rec.service(), 1, 2, client.WithBatchSize(1), client.WithFlushInterval(0))
+
+	if err := w.WriteLine(ctx, []byte("m f=1 1")); err == nil {
+		t.Fatal("expected the error of the write to be returned")
+	}
+
+	rec.err = nil
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("expected failed batch to be dropped, got %v", err)
+	}
+	if got := rec.written(); len(got) != 1 {
+		t.Fatalf("expected failed batch not to be written again, got %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+// Package client is a Go client of the InfluxDB v2 HTTP API.
+//
+// The client is built on the HTTP client services of the http package, which
+// share their request and response structs with the handlers of the server,
+// so that the client stays in sync with the API.
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
+)
+
+// Config configures a Client.
+type Config struct {
+	// Addr is the address of the server, e.g. http://localhost:9999.
+	Addr string
+	// Token is the authorization token used for all requests.
+	Token string
+	// AuthorizationID is the ID of the authorization of the token. When it
+	// is set, writes are signed with the token instead of sending it, for
+	// servers reached over networks without TLS.
+	AuthorizationID influxdb.ID
+	// InsecureSkipVerify skips the verification of the certificate of the server.
+	InsecureSkipVerify bool
+	// Precision is the precision of the timestamps of written points;
+	// defaults to ns.
+	Precision string
+}
+
+// Client exposes the resources of the v2 API as typed services.
+type Client struct {
+	Organizations  influxdb.OrganizationService
+	Users          influxdb.UserService
+	Authorizations influxdb.AuthorizationService
+	Buckets        influxdb.BucketService
+	Dashboards     influxdb.DashboardService
+	Labels         influxdb.LabelService
+	Variables      influxdb.VariableService
+	Tasks          *http.TaskService
+
+	writes  influxdb.WriteService
+	queries query.QueryService
+}
+
+// New returns a Client of the server at the address of the config.
+func New(c Config) (*Client, error) {
+	u, err := url.Parse(c.Addr)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid server address",
+			Err:  err,
+		}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "server address must be an absolute http or https url",
+		}
+	}
+	addr := strings.TrimSuffix(c.Addr, "/")
+
+	return &Client{
+		Organizations: &http.OrganizationService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Users: &http.UserService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Authorizations: &http.AuthorizationService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Buckets: &http.BucketService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Dashboards: &http.DashboardService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Labels: &http.LabelService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Variables: &http.VariableService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		Tasks: &http.TaskService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+		writes: &http.WriteService{
+			Addr:               addr,
+			Token:              c.Token,
+			Precision:          c.Precision,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+			AuthorizationID:    c.AuthorizationID,
+		},
+		queries: &http.FluxQueryService{
+			Addr:               addr,
+			Token:              c.Token,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		},
+	}, nil
+}
+
+// Query runs the flux query in the organization. The caller must release the
+// results.
+func (c *Client) Query(ctx context.Context, orgID influxdb.ID, q string) (flux.ResultIterator, error) {
+	return c.queries.Query(ctx, &query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: q},
+	})
+}
+
+// Write writes the line protocol read from r to the bucket.
+func (c *Client) Write(ctx context.Context, orgID, bucketID influxdb.ID, r io.Reader) error {
+	return c.writes.Write(ctx, orgID, bucketID, r)
+}
+
+// BatchWriter returns a BatchWriter that writes batches of lines to the bucket.
+func (c *Client) BatchWriter(orgID, bucketID influxdb.ID, opts ...BatchOption) *BatchWriter {
+	return NewBatchWriter(c.writes, orgID, bucketID, opts...)
+}
@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/client"
+)
+
+func TestNew_InvalidAddr(t *testing.T) {
+	for _, addr := range []string{"", "localhost:9999", "ftp://localhost", "http://"} {
+		if _, err := client.New(client.Config{Addr: addr}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected address %q to be invalid, got %v", addr, err)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	var written string
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc("/api/v2/buckets/020f755c3c082000", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "020f755c3c082000",
+			"orgID": "020f755c3c082001",
+			"name":  "telegraf",
+		})
+	})
+	mux.HandleFunc("/api/v2/write", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Query().Get("bucket") != "020f755c3c082000" || r.URL.Query().Get("precision") != "s" {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(gz)
+		written += string(data)
+		w.WriteHeader(nethttp.StatusNoContent)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c, err := client.New(client.Config{Addr: ts.URL + "/", Token: "secret", Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	bucketID, orgID := influxdb.ID(0x020f755c3c082000), influxdb.ID(0x020f755c3c082001)
+	b, err := c.Buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		t.Fatalf("unexpected error finding bucket: %v", err)
+	}
+	if b.Name != "telegraf" || b.OrgID != orgID {
+		t.Errorf("unexpected bucket %+v", b)
+	}
+
+	if err := c.Write(ctx, orgID, bucketID, strings.NewReader("m f=1 1\n")); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	w := c.BatchWriter(orgID, bucketID)
+	if err := w.WriteLine(ctx, []byte("m f=2 2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("unexpected error closing batch writer: %v", err)
+	}
+	if written != "m f=1 1\nm f=2 2\n" {
+		t.Errorf("unexpected written lines %q", written)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -18,11 +20,13 @@ type AuthorizationCreateFlags struct {
 	writeUserPermission bool
 	readUserPermission  bool
 
-	writeBucketsPermission bool
-	readBucketsPermission  bool
+	writeBucketsPermission  bool
+	readBucketsPermission   bool
+	deleteBucketsPermission bool
 
-	writeBucketPermissions []string
-	readBucketPermissions  []string
+	writeBucketPermissions  []string
+	readBucketPermissions   []string
+	deleteBucketPermissions []string
 
 	writeTasksPermission bool
 	readTasksPermission  bool
@@ -44,6 +48,28 @@ type AuthorizationCreateFlags struct {
 
 	writeNotificationEndpointPermission bool
 	readNotificationEndpointPermission  bool
+
+	writeSecretsPermission   bool
+	readSecretsPermission    bool
+	readSecretKeysPermission bool
+	deleteSecretsPermission  bool
+
+	writeMaterializedViewPermission bool
+	readMaterializedViewPermission  bool
+
+	writeRemoteConnectionPermission bool
+	readRemoteConnectionPermission  bool
+
+	writeNotebookPermission bool
+	readNotebookPermission  bool
+
+	writeQueryTemplatePermission bool
+	readQueryTemplatePermission  bool
+
+	writeReportPermission bool
+	readReportPermission  bool
+
+	tagConstraints []string
 }
 
 var authCreateFlags AuthorizationCreateFlags
@@ -85,9 +111,11 @@ func authCreateCmd() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeBucketsPermission, "write-buckets", "", false, "Grants the permission to perform mutative actions against organization buckets")
 	cmd.Flags().BoolVarP(&authCreateFlags.readBucketsPermission, "read-buckets", "", false, "Grants the permission to perform read actions against organization buckets")
+	cmd.Flags().BoolVarP(&authCreateFlags.deleteBucketsPermission, "delete-buckets", "", false, "Grants the permission to delete data from organization buckets")
 
 	cmd.Flags().StringArrayVarP(&authCreateFlags.writeBucketPermissions, "write-bucket", "", []string{}, "The bucket id")
 	cmd.Flags().StringArrayVarP(&authCreateFlags.readBucketPermissions, "read-bucket", "", []string{}, "The bucket id")
+	cmd.Flags().StringArrayVarP(&authCreateFlags.deleteBucketPermissions, "delete-bucket", "", []string{}, "The bucket id")
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeTasksPermission, "write-tasks", "", false, "Grants the permission to create tasks")
 	cmd.Flags().BoolVarP(&authCreateFlags.readTasksPermission, "read-tasks", "", false, "Grants the permission to read tasks")
@@ -110,6 +138,28 @@ func authCreateCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&authCreateFlags.writeCheckPermission, "write-checks", "", false, "Grants the permission to create checks")
 	cmd.Flags().BoolVarP(&authCreateFlags.readCheckPermission, "read-checks", "", false, "Grants the permission to read checks")
 
+	cmd.Flags().BoolVarP(&authCreateFlags.writeSecretsPermission, "write-secrets", "", false, "Grants the permission to create and update secrets")
+	cmd.Flags().BoolVarP(&authCreateFlags.readSecretsPermission, "read-secrets", "", false, "Grants the permission to read the values of secrets")
+	cmd.Flags().BoolVarP(&authCreateFlags.readSecretKeysPermission, "read-secretKeys", "", false, "Grants the permission to list the keys of secrets")
+	cmd.Flags().BoolVarP(&authCreateFlags.deleteSecretsPermission, "delete-secrets", "", false, "Grants the permission to delete secrets")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeMaterializedViewPermission, "write-materializedViews", "", false, "Grants the permission to create materialized views")
+	cmd.Flags().BoolVarP(&authCreateFlags.readMaterializedViewPermission, "read-materializedViews", "", false, "Grants the permission to read materialized views")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeRemoteConnectionPermission, "write-remotes", "", false, "Grants the permission to create remote connections")
+	cmd.Flags().BoolVarP(&authCreateFlags.readRemoteConnectionPermission, "read-remotes", "", false, "Grants the permission to read remote connections")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeNotebookPermission, "write-notebooks", "", false, "Grants the permission to create notebooks")
+	cmd.Flags().BoolVarP(&authCreateFlags.readNotebookPermission, "read-notebooks", "", false, "Grants the permission to read notebooks")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeQueryTemplatePermission, "write-query-templates", "", false, "Grants the permission to create query templates")
+	cmd.Flags().BoolVarP(&authCreateFlags.readQueryTemplatePermission, "read-query-templates", "", false, "Grants the permission to read query templates")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeReportPermission, "write-reports", "", false, "Grants the permission to create scheduled reports")
+	cmd.Flags().BoolVarP(&authCreateFlags.readReportPermission, "read-reports", "", false, "Grants the permission to read scheduled reports")
+
+	cmd.Flags().StringArrayVarP(&authCreateFlags.tagConstraints, "write-tag", "", []string{}, "Only allows writing points with the tag, in the form key=value")
+
 	return cmd
 }
 
@@ -133,6 +183,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 	}{
 		{action: platform.ReadAction, perms: authCreateFlags.readBucketPermissions},
 		{action: platform.WriteAction, perms: authCreateFlags.writeBucketPermissions},
+		{action: platform.DeleteAction, perms: authCreateFlags.deleteBucketPermissions},
 	}
 
 	for _, bp := range bucketPerms {
@@ -152,12 +203,13 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 	}
 
 	providedPerm := []struct {
-		readPerm, writePerm bool
-		ResourceType        platform.ResourceType
+		readPerm, writePerm, deletePerm bool
+		ResourceType                    platform.ResourceType
 	}{
 		{
 			readPerm:     authCreateFlags.readBucketsPermission,
 			writePerm:    authCreateFlags.writeBucketsPermission,
+			deletePerm:   authCreateFlags.deleteBucketsPermission,
 			ResourceType: platform.BucketsResourceType,
 		},
 		{
@@ -185,6 +237,44 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 			writePerm:    authCreateFlags.writeOrganizationsPermission,
 			ResourceType: platform.OrgsResourceType,
 		},
+		{
+			readPerm:     authCreateFlags.readSecretsPermission,
+			writePerm:    authCreateFlags.writeSecretsPermission,
+			ResourceType: platform.SecretsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readSecretKeysPermission,
+			ResourceType: platform.SecretKeysResourceType,
+		},
+		{
+			writePerm:    authCreateFlags.deleteSecretsPermission,
+			ResourceType: platform.SecretDeletionsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readMaterializedViewPermission,
+			writePerm:    authCreateFlags.writeMaterializedViewPermission,
+			ResourceType: platform.MaterializedViewsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readRemoteConnectionPermission,
+			writePerm:    authCreateFlags.writeRemoteConnectionPermission,
+			ResourceType: platform.RemoteConnectionsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readNotebookPermission,
+			writePerm:    authCreateFlags.writeNotebookPermission,
+			ResourceType: platform.NotebooksResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readQueryTemplatePermission,
+			writePerm:    authCreateFlags.writeQueryTemplatePermission,
+			ResourceType: platform.QueryTemplatesResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readReportPermission,
+			writePerm:    authCreateFlags.writeReportPermission,
+			ResourceType: platform.ReportsResourceType,
+		},
 		{
 			readPerm:     authCreateFlags.readTasksPermission,
 			writePerm:    authCreateFlags.writeTasksPermission,
@@ -211,6 +301,9 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		if provided.writePerm {
 			actions = append(actions, platform.WriteAction)
 		}
+		if provided.deletePerm {
+			actions = append(actions, platform.DeleteAction)
+		}
 
 		for _, action := range actions {
 			p, err := platform.NewPermission(action, provided.ResourceType, o.ID)
@@ -226,6 +319,14 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		OrgID:       o.ID,
 	}
 
+	for _, t := range authCreateFlags.tagConstraints {
+		kv := strings.SplitN(t, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return fmt.Errorf("invalid write tag %q, expected key=value", t)
+		}
+		authorization.TagConstraints = append(authorization.TagConstraints, platform.Tag{Key: kv[0], Value: kv[1]})
+	}
+
 	if userName := authCreateFlags.user; userName != "" {
 		userSvc, err := newUserService()
 		if err != nil {
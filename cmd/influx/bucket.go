@@ -193,9 +193,10 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 
 // BucketUpdateFlags define the Update Command
 type BucketUpdateFlags struct {
-	id        string
-	name      string
-	retention time.Duration
+	id                 string
+	name               string
+	retention          time.Duration
+	shardGroupDuration time.Duration
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -204,12 +205,19 @@ func init() {
 	bucketUpdateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update bucket",
-		RunE:  wrapCheckSetup(bucketUpdateF),
+		Long: `Update bucket
+
+The shard groups of a bucket are windows of time over the storage files
+shared by all buckets, not files of their own. A new --shard-group-duration
+regroups the existing data of the bucket without rewriting it, so no
+re-sharding is needed afterwards.`,
+		RunE: wrapCheckSetup(bucketUpdateF),
 	}
 
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.id, "id", "i", "", "The bucket ID (required)")
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.name, "name", "n", "", "New bucket name")
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "New duration data will live in bucket")
+	bucketUpdateCmd.Flags().DurationVar(&bucketUpdateFlags.shardGroupDuration, "shard-group-duration", 0, "New duration of the shard groups of the bucket; existing data is regrouped without being rewritten")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -233,6 +241,9 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 	if bucketUpdateFlags.retention != 0 {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
+	if bucketUpdateFlags.shardGroupDuration != 0 {
+		update.ShardGroupDuration = &bucketUpdateFlags.shardGroupDuration
+	}
 
 	b, err := s.UpdateBucket(context.Background(), id, update)
 	if err != nil {
@@ -93,7 +93,7 @@ func influxCmd() *cobra.Command {
 		flags.token = tok
 	}
 
-	cmd.PersistentFlags().StringVar(&flags.host, "host", "http://localhost:9999", "HTTP address of Influx")
+	cmd.PersistentFlags().StringVar(&flags.host, "host", "http://localhost:9999", "HTTP address of Influx, or unix:///path/to/influxd.sock to connect to its Unix domain socket")
 	viper.BindEnv("HOST")
 	if h := viper.GetString("HOST"); h != "" {
 		flags.host = h
@@ -3,9 +3,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
+	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/kit/check"
 	"github.com/spf13/cobra"
 )
@@ -22,10 +22,14 @@ func pingF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("local flag not supported for ping command")
 	}
 
-	c := http.Client{
-		Timeout: 5 * time.Second,
+	u, err := http.NewURL(flags.host, "/health")
+	if err != nil {
+		return err
 	}
-	url := flags.host + "/health"
+	c := http.NewClient(u.Scheme, flags.skipVerify)
+	c.Timeout = 5 * time.Second
+
+	url := u.String()
 	resp, err := c.Get(url)
 	if err != nil {
 		return err
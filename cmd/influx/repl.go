@@ -1,14 +1,34 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/c-bata/go-prompt"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	_ "github.com/influxdata/flux/stdlib"
+	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/stdlib"
 	"github.com/spf13/cobra"
@@ -18,13 +38,25 @@ import (
 var replCmd = &cobra.Command{
 	Use:   "repl",
 	Short: "Interactive Flux REPL (read-eval-print-loop)",
-	Args:  cobra.NoArgs,
-	RunE:  wrapCheckSetup(replF),
+	Long: `Interactive Flux REPL (read-eval-print-loop).
+
+Statements may span several lines; input continues while brackets or strings
+are open, or when a line ends with |> or \. Press tab to complete bucket names,
+measurements and functions. History is kept in the influx config directory.
+
+The following commands are available in the REPL:
+  :format [table|csv|json]  show or change the output format of query results
+  :refresh                  reload buckets and measurements used for completion
+  :help                     show the available commands
+  :quit                     exit the REPL`,
+	Args: cobra.NoArgs,
+	RunE: wrapCheckSetup(replF),
 }
 
 var replFlags struct {
-	OrgID string
-	Org   string
+	OrgID  string
+	Org    string
+	Format string
 }
 
 func init() {
@@ -39,6 +71,8 @@ func init() {
 	if h := viper.GetString("ORG"); h != "" {
 		replFlags.Org = h
 	}
+
+	replCmd.PersistentFlags().StringVar(&replFlags.Format, "format", string(replFormatTable), "The output format of query results, one of table, csv or json")
 }
 
 func replF(cmd *cobra.Command, args []string) error {
@@ -54,6 +88,11 @@ func replF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("must specify exactly one of org or org-id")
 	}
 
+	format, err := parseReplFormat(replFlags.Format)
+	if err != nil {
+		return err
+	}
+
 	var orgID platform.ID
 	if replFlags.OrgID != "" {
 		err := orgID.DecodeFromString(replFlags.OrgID)
@@ -73,11 +112,7 @@ func replF(cmd *cobra.Command, args []string) error {
 
 	flux.FinalizeBuiltIns()
 
-	r, err := getFluxREPL(flags.host, flags.token, flags.skipVerify, orgID)
-	if err != nil {
-		return err
-	}
-
+	r := newInteractiveREPL(flags.host, flags.token, flags.skipVerify, orgID, format)
 	r.Run()
 	return nil
 }
@@ -113,3 +148,656 @@ func getFluxREPL(addr, token string, skipVerify bool, orgID platform.ID) (*repl.
 	// server side.
 	return repl.New(context.Background(), flux.NewDefaultDependencies(), q), nil
 }
+
+// replFormat is the format query results are printed in by the REPL.
+type replFormat string
+
+const (
+	replFormatTable replFormat = "table"
+	replFormatCSV   replFormat = "csv"
+	replFormatJSON  replFormat = "json"
+)
+
+func parseReplFormat(s string) (replFormat, error) {
+	switch f := replFormat(strings.ToLower(s)); f {
+	case replFormatTable, replFormatCSV, replFormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid format %q; supported formats are table, csv and json", s)
+}
+
+// interactiveREPL is an interactive Flux console. Statements are evaluated by
+// the flux REPL so variables carry over between statements, while the
+// interactive REPL adds multi-line input, completion, persistent history and
+// output formats.
+type interactiveREPL struct {
+	repl      *repl.REPL
+	querier   *formatQuerier
+	completer *replCompleter
+	history   *replHistory
+
+	// pending holds the lines of a statement that is not yet complete.
+	pending []string
+	// quit is set by the :quit command to end the input of the prompt.
+	quit bool
+}
+
+// errQuitREPL is returned by a command that exits the REPL.
+var errQuitREPL = errors.New("quit")
+
+func newInteractiveREPL(addr, token string, skipVerify bool, orgID platform.ID, format replFormat) *interactiveREPL {
+	qs := &http.FluxQueryService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: skipVerify,
+	}
+	fq := &formatQuerier{
+		querier: &query.REPLQuerier{
+			OrganizationID: orgID,
+			QueryService:   qs,
+		},
+		w:      os.Stdout,
+		format: format,
+	}
+
+	bucketSvc := &http.BucketService{
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: skipVerify,
+	}
+	c := newReplCompleter(
+		func(ctx context.Context) ([]string, error) {
+			bs, _, err := bucketSvc.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &orgID})
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(bs))
+			for _, b := range bs {
+				names = append(names, b.Name)
+			}
+			return names, nil
+		},
+		func(ctx context.Context, bucket string) ([]string, error) {
+			return queryStringValues(ctx, qs, orgID, fmt.Sprintf("import \"influxdata/influxdb/v1\"\nv1.measurements(bucket: %q)", bucket))
+		},
+		preludeNames(),
+	)
+
+	var h *replHistory
+	if dir, err := fs.InfluxDir(); err == nil {
+		h = &replHistory{path: filepath.Join(dir, "repl_history"), max: 1000}
+	}
+
+	return &interactiveREPL{
+		// background context is OK here, and DefaultDependencies are noop deps.  Also safe since we send all queries to the
+		// server side.
+		repl:      repl.New(context.Background(), flux.NewDefaultDependencies(), fq),
+		querier:   fq,
+		completer: c,
+		history:   h,
+	}
+}
+
+// Run reads and evaluates statements until the input is closed.
+func (r *interactiveREPL) Run() {
+	var history []string
+	if r.history != nil {
+		history = r.history.load()
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+	go func() {
+		for range sigs {
+			r.querier.cancelQuery()
+		}
+	}()
+
+	p := prompt.New(
+		r.input,
+		func(d prompt.Document) []prompt.Suggest {
+			return r.completer.complete(strings.Join(r.pending, "\n"), d)
+		},
+		prompt.OptionPrefix("> "),
+		prompt.OptionLivePrefix(func() (string, bool) {
+			return "... ", len(r.pending) > 0
+		}),
+		prompt.OptionTitle("flux"),
+		prompt.OptionHistory(history),
+		prompt.OptionCompletionWordSeparator(replWordSeparators),
+		prompt.OptionParser(&replParser{ConsoleParser: prompt.NewStandardInputParser(), quit: &r.quit}),
+	)
+	p.Run()
+}
+
+// replParser reads the input of the prompt until quit is set. The prompt only
+// returns at the end of its input, so the end of input is read once quit is
+// set, which lets the prompt restore the terminal and return.
+type replParser struct {
+	prompt.ConsoleParser
+	quit *bool
+}
+
+func (p *replParser) Read() ([]byte, error) {
+	if *p.quit {
+		return []byte{0x04}, nil // ^D
+	}
+	return p.ConsoleParser.Read()
+}
+
+// input processes a line of input. The statement is evaluated once it is
+// complete.
+func (r *interactiveREPL) input(line string) {
+	if len(r.pending) == 0 {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return
+		}
+		if strings.HasPrefix(trimmed, ":") {
+			if err := r.command(trimmed); err == errQuitREPL {
+				r.quit = true
+			} else if err != nil {
+				fmt.Println("Error:", err)
+			}
+			return
+		}
+	}
+
+	// An empty line ends a pending statement even if it is incomplete so a
+	// mistyped statement cannot trap the prompt in continuation mode.
+	if strings.TrimSpace(line) != "" {
+		r.pending = append(r.pending, strings.TrimSuffix(strings.TrimRight(line, " \t"), `\`))
+		if !statementComplete(line, strings.Join(r.pending, "\n")) {
+			return
+		}
+	}
+
+	stmt := strings.Join(r.pending, "\n")
+	r.pending = r.pending[:0]
+
+	if r.history != nil {
+		if err := r.history.add(stmt); err != nil {
+			fmt.Println("Error: unable to save history:", err)
+		}
+	}
+
+	if err := r.repl.Input(stmt); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	r.completer.addIdentifiers(stmt)
+}
+
+// command executes a REPL command. It returns errQuitREPL if the command
+// exits the REPL.
+func (r *interactiveREPL) command(cmd string) error {
+	fields := strings.Fields(cmd)
+	switch fields[0] {
+	case ":format":
+		if len(fields) == 1 {
+			fmt.Println("Format:", r.querier.Format())
+			return nil
+		}
+		f, err := parseReplFormat(fields[1])
+		if err != nil {
+			return err
+		}
+		r.querier.SetFormat(f)
+	case ":refresh":
+		r.completer.reset()
+	case ":help":
+		fmt.Println(`:format [table|csv|json]  show or change the output format of query results
+:refresh                  reload buckets and measurements used for completion
+:help                     show the available commands
+:quit                     exit the REPL`)
+	case ":quit", ":exit":
+		return errQuitREPL
+	default:
+		return fmt.Errorf("unknown command %q, type :help for the available commands", fields[0])
+	}
+	return nil
+}
+
+// statementComplete reports whether stmt is a complete statement. A statement
+// continues while brackets or strings are open, or when the last line ends
+// with a pipe forward or a backslash.
+func statementComplete(lastLine, stmt string) bool {
+	last := strings.TrimSpace(lastLine)
+	if strings.HasSuffix(last, `\`) || strings.HasSuffix(last, "|>") {
+		return false
+	}
+
+	var (
+		depth    int
+		inString bool
+	)
+	for _, line := range strings.Split(stmt, "\n") {
+		line = stripLineComment(line)
+		escaped := false
+		for _, c := range line {
+			switch {
+			case inString:
+				switch {
+				case escaped:
+					escaped = false
+				case c == '\\':
+					escaped = true
+				case c == '"':
+					inString = false
+				}
+			case c == '"':
+				inString = true
+			case c == '(' || c == '[' || c == '{':
+				depth++
+			case c == ')' || c == ']' || c == '}':
+				depth--
+			}
+		}
+	}
+	return !inString && depth <= 0
+}
+
+// stripLineComment removes a // comment from the end of line.
+func stripLineComment(line string) string {
+	var inString, escaped bool
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// formatQuerier executes queries with the wrapped querier and prints the
+// results in the configured format. Results printed by the querier are not
+// returned so the flux REPL only prints tables itself.
+type formatQuerier struct {
+	querier repl.Querier
+	w       io.Writer
+
+	mu     sync.Mutex
+	format replFormat
+	cancel context.CancelFunc
+}
+
+// Format returns the current output format.
+func (q *formatQuerier) Format() replFormat {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.format
+}
+
+// SetFormat changes the output format of later queries.
+func (q *formatQuerier) SetFormat(f replFormat) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.format = f
+}
+
+// cancelQuery cancels the query currently executing, if any.
+func (q *formatQuerier) cancelQuery() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		q.cancel()
+	}
+}
+
+// Query executes the query and prints the results unless the format is table.
+func (q *formatQuerier) Query(ctx context.Context, deps flux.Dependencies, compiler flux.Compiler) (flux.ResultIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	q.mu.Lock()
+	q.cancel = cancel
+	format := q.format
+	q.mu.Unlock()
+
+	results, err := q.querier.Query(ctx, deps, compiler)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	switch format {
+	case replFormatCSV:
+		defer cancel()
+		defer results.Release()
+		if _, err := csv.NewMultiResultEncoder(csv.DefaultEncoderConfig()).Encode(q.w, results); err != nil {
+			return nil, err
+		}
+	case replFormatJSON:
+		defer cancel()
+		defer results.Release()
+		if err := encodeJSONResults(q.w, results); err != nil {
+			return nil, err
+		}
+	default:
+		// The flux REPL prints the tables after the query returns, so the
+		// context is canceled once it releases the results.
+		return &cancelResults{ResultIterator: results, cancel: cancel}, nil
+	}
+	return emptyResults{}, nil
+}
+
+// cancelResults cancels the context of the query once its results are released.
+type cancelResults struct {
+	flux.ResultIterator
+	cancel context.CancelFunc
+}
+
+func (r *cancelResults) Release() {
+	r.ResultIterator.Release()
+	r.cancel()
+}
+
+// encodeJSONResults writes every row of the results as a JSON object on its
+// own line. Rows carry the name of their result and the index of their table
+// along with the row's columns.
+func encodeJSONResults(w io.Writer, results flux.ResultIterator) error {
+	enc := json.NewEncoder(w)
+	for results.More() {
+		res := results.Next()
+		table := 0
+		err := res.Tables().Do(func(tbl flux.Table) error {
+			cols := tbl.Cols()
+			err := tbl.Do(func(cr flux.ColReader) error {
+				for i := 0; i < cr.Len(); i++ {
+					row := make(map[string]interface{}, len(cols)+2)
+					row["result"] = res.Name()
+					row["table"] = table
+					for j, c := range cols {
+						row[c.Label] = jsonValue(cr, i, j, c.Type)
+					}
+					if err := enc.Encode(row); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			table++
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return results.Err()
+}
+
+func jsonValue(cr flux.ColReader, i, j int, typ flux.ColType) interface{} {
+	v := execute.ValueForRow(cr, i, j)
+	if v.IsNull() {
+		return nil
+	}
+	switch typ {
+	case flux.TBool:
+		return v.Bool()
+	case flux.TInt:
+		return v.Int()
+	case flux.TUInt:
+		return v.UInt()
+	case flux.TFloat:
+		// JSON has no representation for NaN or infinity.
+		if f := v.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+		return nil
+	case flux.TString:
+		return v.Str()
+	case flux.TTime:
+		return v.Time().Time().Format(time.RFC3339Nano)
+	}
+	return nil
+}
+
+// emptyResults is a result iterator without results.
+type emptyResults struct{}
+
+func (emptyResults) More() bool                  { return false }
+func (emptyResults) Next() flux.Result           { panic("no more results") }
+func (emptyResults) Release()                    {}
+func (emptyResults) Err() error                  { return nil }
+func (emptyResults) Statistics() flux.Statistics { return flux.Statistics{} }
+
+// queryStringValues executes q and returns the _value column of every table.
+func queryStringValues(ctx context.Context, qs query.QueryService, orgID platform.ID, q string) ([]string, error) {
+	results, err := qs.Query(ctx, &query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: q},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var values []string
+	for results.More() {
+		err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			j := execute.ColIdx("_value", tbl.Cols())
+			if j < 0 || tbl.Cols()[j].Type != flux.TString {
+				return tbl.Do(func(flux.ColReader) error { return nil })
+			}
+			return tbl.Do(func(cr flux.ColReader) error {
+				vs := cr.Strings(j)
+				for i := 0; i < vs.Len(); i++ {
+					if vs.IsValid(i) {
+						values = append(values, vs.ValueString(i))
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// preludeNames returns the names of the functions available without an import.
+func preludeNames() []string {
+	var names []string
+	flux.Prelude().Range(func(k string, _ values.Value) {
+		if !strings.HasPrefix(k, "_") {
+			names = append(names, k)
+		}
+	})
+	return names
+}
+
+// replWordSeparators are the characters that separate the word completed by
+// the REPL from the text before it.
+const replWordSeparators = " \t\n()[]{},:\"=<>|+-*/."
+
+var (
+	bucketArgPattern   = regexp.MustCompile(`bucket\s*:\s*"[^"]*$`)
+	bucketNamePattern  = regexp.MustCompile(`bucket\s*:\s*"([^"]+)"`)
+	measurementPattern = regexp.MustCompile(`_measurement"?\]?\s*==\s*"[^"]*$`)
+	assignmentPattern  = regexp.MustCompile(`(?m)^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=[^=>]`)
+	importPattern      = regexp.MustCompile(`import\s+"([^"]+)"`)
+)
+
+// replCompleter suggests bucket names inside a bucket argument, measurement
+// names when comparing _measurement, and otherwise the names of functions and
+// variables. Buckets and measurements are fetched once and cached until reset.
+type replCompleter struct {
+	ctx          context.Context
+	buckets      func(ctx context.Context) ([]string, error)
+	measurements func(ctx context.Context, bucket string) ([]string, error)
+
+	mu              sync.Mutex
+	identifiers     map[string]bool
+	bucketNames     []string
+	haveBuckets     bool
+	measurementsFor map[string][]string
+}
+
+func newReplCompleter(
+	buckets func(ctx context.Context) ([]string, error),
+	measurements func(ctx context.Context, bucket string) ([]string, error),
+	functions []string,
+) *replCompleter {
+	c := &replCompleter{
+		ctx:             context.Background(),
+		buckets:         buckets,
+		measurements:    measurements,
+		identifiers:     make(map[string]bool),
+		measurementsFor: make(map[string][]string),
+	}
+	for _, f := range functions {
+		c.identifiers[f] = true
+	}
+	return c
+}
+
+// reset discards the cached buckets and measurements.
+func (c *replCompleter) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bucketNames, c.haveBuckets = nil, false
+	c.measurementsFor = make(map[string][]string)
+}
+
+// addIdentifiers records the variables and packages defined by stmt.
+func (c *replCompleter) addIdentifiers(stmt string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range assignmentPattern.FindAllStringSubmatch(stmt, -1) {
+		c.identifiers[m[1]] = true
+	}
+	for _, m := range importPattern.FindAllStringSubmatch(stmt, -1) {
+		c.identifiers[m[1][strings.LastIndex(m[1], "/")+1:]] = true
+	}
+}
+
+func (c *replCompleter) complete(pending string, d prompt.Document) []prompt.Suggest {
+	text := d.TextBeforeCursor()
+	if pending != "" {
+		text = pending + "\n" + text
+	}
+	word := d.GetWordBeforeCursorUntilSeparator(replWordSeparators)
+
+	if bucketArgPattern.MatchString(text) {
+		return suggest(c.bucketList(), word)
+	}
+	if measurementPattern.MatchString(text) {
+		bs := bucketNamePattern.FindAllStringSubmatch(text, -1)
+		if len(bs) == 0 {
+			return nil
+		}
+		return suggest(c.measurementList(bs[len(bs)-1][1]), word)
+	}
+	if word == "" || strings.Count(text, `"`)%2 == 1 {
+		return nil
+	}
+
+	c.mu.Lock()
+	names := make([]string, 0, len(c.identifiers))
+	for n := range c.identifiers {
+		names = append(names, n)
+	}
+	c.mu.Unlock()
+	sort.Strings(names)
+	return suggest(names, word)
+}
+
+func (c *replCompleter) bucketList() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.haveBuckets {
+		// Failures are cached as well so an unreachable server does not slow
+		// down every key press; :refresh tries again.
+		c.bucketNames, _ = c.buckets(c.ctx)
+		c.haveBuckets = true
+	}
+	return c.bucketNames
+}
+
+func (c *replCompleter) measurementList(bucket string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ms, ok := c.measurementsFor[bucket]
+	if !ok {
+		ms, _ = c.measurements(c.ctx, bucket)
+		c.measurementsFor[bucket] = ms
+	}
+	return ms
+}
+
+func suggest(names []string, prefix string) []prompt.Suggest {
+	s := make([]prompt.Suggest, 0, len(names))
+	for _, n := range names {
+		s = append(s, prompt.Suggest{Text: n})
+	}
+	return prompt.FilterHasPrefix(s, prefix, true)
+}
+
+// replHistory persists the statements entered in the REPL, one per line.
+type replHistory struct {
+	path string
+	max  int
+}
+
+// load returns the most recent statements. The history file is truncated
+// to the most recent statements when it has grown too large.
+func (h *replHistory) load() []string {
+	f, err := os.Open(h.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var entries []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if e := s.Text(); e != "" {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > h.max {
+		entries = entries[len(entries)-h.max:]
+		ioutil.WriteFile(h.path, []byte(strings.Join(entries, "\n")+"\n"), 0600)
+	}
+	return entries
+}
+
+// add appends stmt to the history file. Multi-line statements are stored on
+// a single line without their comments.
+func (h *replHistory) add(stmt string) error {
+	lines := strings.Split(stmt, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(stripLineComment(l))
+	}
+	stmt = strings.TrimSpace(strings.Join(lines, " "))
+	if stmt == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(stmt + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+)
+
+func Test_statementComplete(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  bool
+	}{
+		{lines: []string{`x = 1`}, want: true},
+		{lines: []string{`from(bucket: "b")`}, want: true},
+		{lines: []string{`from(bucket: "b")`, `|> range(start: -1h)`}, want: true},
+		{lines: []string{`from(bucket: "b") |>`}, want: false},
+		{lines: []string{`from(bucket: "b") \`}, want: false},
+		{lines: []string{`from(`}, want: false},
+		{lines: []string{`from(`, `bucket: "b")`}, want: true},
+		{lines: []string{`x = "a(b`}, want: false},
+		{lines: []string{`x = "a\"(b"`}, want: true},
+		{lines: []string{`f = (r) => { // open {`}, want: false},
+		{lines: []string{`f = (r) => {`, `return r }`}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.lines, " "), func(t *testing.T) {
+			got := statementComplete(tt.lines[len(tt.lines)-1], strings.Join(tt.lines, "\n"))
+			if got != tt.want {
+				t.Errorf("statementComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_replCompleter(t *testing.T) {
+	var bucketCalls int
+	c := newReplCompleter(
+		func(ctx context.Context) ([]string, error) {
+			bucketCalls++
+			return []string{"metrics", "monitoring", "telegraf"}, nil
+		},
+		func(ctx context.Context, bucket string) ([]string, error) {
+			if bucket != "telegraf" {
+				t.Errorf("unexpected bucket %q", bucket)
+			}
+			return []string{"cpu", "mem"}, nil
+		},
+		[]string{"from", "filter", "range"},
+	)
+	c.addIdentifiers(`import "influxdata/influxdb/v1"` + "\n" + `myData = from(bucket: "telegraf")`)
+
+	complete := func(pending, text string) []string {
+		b := prompt.NewBuffer()
+		b.InsertText(text, false, true)
+		var texts []string
+		for _, s := range c.complete(pending, *b.Document()) {
+			texts = append(texts, s.Text)
+		}
+		return texts
+	}
+
+	tests := []struct {
+		name    string
+		pending string
+		text    string
+		want    []string
+	}{
+		{name: "bucket", text: `from(bucket: "m`, want: []string{"metrics", "monitoring"}},
+		{name: "measurement", text: `from(bucket: "telegraf") |> filter(fn: (r) => r._measurement == "`, want: []string{"cpu", "mem"}},
+		{name: "measurement on a previous line", pending: `from(bucket: "telegraf")`, text: `|> filter(fn: (r) => r["_measurement"] == "m`, want: []string{"mem"}},
+		{name: "function", text: `from(bucket: "telegraf") |> f`, want: []string{"filter", "from"}},
+		{name: "variable", text: `my`, want: []string{"myData"}},
+		{name: "package", text: `v`, want: []string{"v1"}},
+		{name: "empty word", text: `x = `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := complete(tt.pending, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("complete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if bucketCalls != 1 {
+		t.Errorf("buckets fetched %d times, want 1", bucketCalls)
+	}
+	c.reset()
+	complete("", `from(bucket: "`)
+	if bucketCalls != 2 {
+		t.Errorf("buckets fetched %d times after reset, want 2", bucketCalls)
+	}
+}
+
+func Test_replHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-repl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := &replHistory{path: filepath.Join(dir, "repl_history"), max: 2}
+	for _, stmt := range []string{
+		`x = 1`,
+		"from(bucket: \"b\") // the bucket\n  |> range(start: -1h)",
+		`y = "a // b"`,
+	} {
+		if err := h.add(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{`from(bucket: "b") |> range(start: -1h)`, `y = "a // b"`}
+	if got := h.load(); !reflect.DeepEqual(got, want) {
+		t.Errorf("load() = %q, want %q", got, want)
+	}
+	if got := h.load(); !reflect.DeepEqual(got, want) {
+		t.Errorf("load() after truncation = %q, want %q", got, want)
+	}
+}
+
+func Test_encodeJSONResults(t *testing.T) {
+	res := executetest.NewResult([]*executetest.Table{
+		{
+			KeyCols: []string{"_measurement"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(0), "cpu", 1.5},
+				{execute.Time(1e9), "cpu", nil},
+			},
+		},
+	})
+	res.Nm = "_result"
+
+	var buf bytes.Buffer
+	if err := encodeJSONResults(&buf, flux.NewSliceResultIterator([]flux.Result{res})); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"_measurement":"cpu","_time":"1970-01-01T00:00:00Z","_value":1.5,"result":"_result","table":0}
+{"_measurement":"cpu","_time":"1970-01-01T00:00:01Z","_value":null,"result":"_result","table":0}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("encodeJSONResults() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+type replQuerierFunc func(ctx context.Context) (flux.ResultIterator, error)
+
+func (f replQuerierFunc) Query(ctx context.Context, deps flux.Dependencies, compiler flux.Compiler) (flux.ResultIterator, error) {
+	return f(ctx)
+}
+
+func Test_formatQuerier_cancel(t *testing.T) {
+	for _, format := range []replFormat{replFormatTable, replFormatCSV, replFormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var queryCtx context.Context
+			q := &formatQuerier{
+				querier: replQuerierFunc(func(ctx context.Context) (flux.ResultIterator, error) {
+					queryCtx = ctx
+					return flux.NewSliceResultIterator(nil), nil
+				}),
+				w:      ioutil.Discard,
+				format: format,
+			}
+
+			results, err := q.Query(context.Background(), nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results.Release()
+			if queryCtx.Err() == nil {
+				t.Error("expected the context of the query to be canceled once its results are released")
+			}
+		})
+	}
+}
+
+func Test_interactiveREPL_quit(t *testing.T) {
+	r := &interactiveREPL{}
+	r.input(":quit")
+	if !r.quit {
+		t.Fatal("expected :quit to end the input")
+	}
+
+	p := &replParser{quit: &r.quit}
+	if b, err := p.Read(); err != nil || !bytes.Equal(b, []byte{0x04}) {
+		t.Errorf("got input %v and error %v once quit, want the end of input", b, err)
+	}
+}
@@ -1,36 +1,87 @@
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/kit/signals"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/csv2lp"
 	"github.com/influxdata/influxdb/write"
+	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
 )
 
 var writeCmd = &cobra.Command{
-	Use:   "write line protocol or @/path/to/points.txt",
+	Use:   "write [line protocol or @/path/to/points.txt]...",
 	Short: "Write points to InfluxDB",
 	Long: `Write a single line of line protocol to InfluxDB,
-or add an entire file specified with an @ prefix.`,
-	Args: cobra.ExactArgs(1),
+or add entire files specified with an @ prefix or the --file flag.
+
+Files may be given as paths, directories or glob patterns; directories are
+read recursively. Gzip compressed files are detected and decompressed.
+Batches are written concurrently, and batches that fail because of a
+transient error are retried. With --checkpoint the progress of every file
+is recorded, so running the same command again after a failure resumes
+where it stopped.
+
+With --format csv the inputs are CSV files converted to line protocol using
+the column mapping given with --csv-mapping. The mapping is a YAML or JSON
+file such as:
+
+  measurement: weather
+  timezone: Europe/Berlin
+  columns:
+    - column: Station
+      type: tag
+      name: station
+    - column: Temperature
+      type: field
+      name: temp
+      dataType: float
+    - column: Date
+      type: time
+      format: "2006-01-02 15:04:05"
+
+Column types are tag, field, time and ignore; columns missing from the
+mapping are ignored. Field data types are float, integer, unsignedInteger,
+boolean and string. Time formats are RFC3339, unix, unix_ms, unix_us, unix_ns
+or a Go time layout, interpreted in the timezone of the mapping.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: wrapCheckSetup(fluxWriteF),
 }
 
 var writeFlags struct {
-	OrgID     string
-	Org       string
-	BucketID  string
-	Bucket    string
-	Precision string
+	OrgID       string
+	Org         string
+	BucketID    string
+	Bucket      string
+	Precision   string
+	Files       []string
+	Concurrency int
+	BatchSize   int
+	RateLimit   string
+	MaxRetries  int
+	Checkpoint  string
+	Progress    bool
+	Format      string
+	CSVMapping  string
 }
 
 func init() {
@@ -58,11 +109,21 @@ func init() {
 		writeFlags.Bucket = h
 	}
 
-	writeCmd.PersistentFlags().StringVarP(&writeFlags.Precision, "precision", "p", "ns", "Precision of the timestamps of the lines")
+	writeCmd.PersistentFlags().StringVarP(&writeFlags.Precision, "precision", "p", "ns", "Precision of the timestamps of the lines; with --format csv, the time column format of the csv-mapping sets it instead")
 	viper.BindEnv("PRECISION")
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().StringArrayVarP(&writeFlags.Files, "file", "f", nil, "The path to a file, directory or glob pattern of files to write; may be repeated")
+	writeCmd.PersistentFlags().IntVar(&writeFlags.Concurrency, "concurrency", 1, "The number of batches written at the same time")
+	writeCmd.PersistentFlags().IntVar(&writeFlags.BatchSize, "batch-size", write.DefaultMaxBytes, "The maximum size of a batch in bytes")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.RateLimit, "rate-limit", "", "The maximum number of bytes written per second, e.g. 5MB; unlimited if empty")
+	writeCmd.PersistentFlags().IntVar(&writeFlags.MaxRetries, "max-retries", write.DefaultMaxRetries, "The number of times a batch that failed with a transient error is retried")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Checkpoint, "checkpoint", "", "The path of a file recording the progress of the written files, used to resume a failed write")
+	writeCmd.PersistentFlags().BoolVar(&writeFlags.Progress, "progress", true, "Show the progress of writing files when the output is a terminal")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Format, "format", "lp", "The format of the input, lp (line protocol) or csv")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.CSVMapping, "csv-mapping", "", "The path of the YAML or JSON file mapping CSV columns to the measurement, tags, fields and time")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -83,13 +144,49 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	var mapping *csv2lp.Mapping
+	switch writeFlags.Format {
+	case "lp":
+		if writeFlags.CSVMapping != "" {
+			cmd.Usage()
+			return fmt.Errorf("csv-mapping requires --format csv")
+		}
+	case "csv":
+		if writeFlags.CSVMapping == "" {
+			cmd.Usage()
+			return fmt.Errorf("please specify the csv-mapping for --format csv")
+		}
+		m, err := csv2lp.LoadMapping(writeFlags.CSVMapping)
+		if err != nil {
+			return fmt.Errorf("failed to load csv mapping: %v", err)
+		}
+		mapping = m
+		// Converted points have nanosecond timestamps; the unit of the time
+		// column is set by its format in the mapping.
+		if writeFlags.Precision != "ns" {
+			cmd.Usage()
+			return fmt.Errorf("precision %q is not supported with --format csv; set the format of the time column in the csv-mapping instead, such as unix_ms", writeFlags.Precision)
+		}
+	default:
+		cmd.Usage()
+		return fmt.Errorf("invalid format %q; supported formats are lp and csv", writeFlags.Format)
+	}
+
+	inputs, err := writeInputs(args, writeFlags.Files)
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		cmd.Usage()
+		return fmt.Errorf("please specify line protocol, a file, or - to read from stdin")
+	}
+
 	bs := &http.BucketService{
 		Addr:               flags.host,
 		Token:              flags.token,
 		InsecureSkipVerify: flags.skipVerify,
 	}
 
-	var err error
 	filter := platform.BucketFilter{}
 
 	if writeFlags.BucketID != "" {
@@ -129,33 +226,412 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 
 	bucketID, orgID := buckets[0].ID, buckets[0].OrgID
 
-	var r io.Reader
-	if args[0] == "-" {
-		r = os.Stdin
-	} else if len(args[0]) > 0 && args[0][0] == '@' {
-		f, err := os.Open(args[0][1:])
-		if err != nil {
-			return fmt.Errorf("failed to open %q: %v", args[0][1:], err)
-		}
-		defer f.Close()
-		r = f
-	} else {
-		r = strings.NewReader(args[0])
-	}
-
-	s := write.Batcher{
+	bw := write.BulkWriter{
 		Service: &http.WriteService{
 			Addr:               flags.host,
 			Token:              flags.token,
 			Precision:          writeFlags.Precision,
 			InsecureSkipVerify: flags.skipVerify,
 		},
+		MaxFlushBytes: writeFlags.BatchSize,
+		Concurrency:   writeFlags.Concurrency,
+		MaxRetries:    writeFlags.MaxRetries,
+	}
+	if writeFlags.MaxRetries == 0 {
+		// Zero selects the default number of retries in the bulk writer.
+		bw.MaxRetries = -1
+	}
+	if writeFlags.RateLimit != "" {
+		bps, err := parseByteSize(writeFlags.RateLimit)
+		if err != nil || bps <= 0 {
+			return fmt.Errorf("invalid rate-limit %q", writeFlags.RateLimit)
+		}
+		burst := writeFlags.BatchSize
+		if bps > int64(burst) {
+			burst = int(bps)
+		}
+		bw.Limiter = rate.NewLimiter(rate.Limit(bps), burst)
+	}
+
+	var cp *writeCheckpoint
+	if writeFlags.Checkpoint != "" {
+		if cp, err = loadWriteCheckpoint(writeFlags.Checkpoint); err != nil {
+			return fmt.Errorf("failed to load checkpoint: %v", err)
+		}
+	}
+
+	progress := newWriteProgress(os.Stderr, inputs)
+	if writeFlags.Progress && progress.total > 0 && isatty.IsTerminal(os.Stderr.Fd()) {
+		progress.start(500 * time.Millisecond)
+		defer progress.stop()
 	}
 
 	ctx = signals.WithStandardSignals(ctx)
-	if err := s.Write(ctx, orgID, bucketID, r); err != nil && err != context.Canceled {
-		return fmt.Errorf("failed to write data: %v", err)
+	for _, in := range inputs {
+		if err := writeInputTo(ctx, &bw, orgID, bucketID, in, mapping, cp, progress); err != nil {
+			if err == context.Canceled {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeInputTo writes in with the bulk writer, converting it from CSV when a
+// mapping is given. Files are skipped up to the offset recorded in the
+// checkpoint, and their progress is recorded in it.
+func writeInputTo(ctx context.Context, bw *write.BulkWriter, orgID, bucketID platform.ID, in writeInput, mapping *csv2lp.Mapping, cp *writeCheckpoint, progress *writeProgress) error {
+	var offset int64
+	if cp != nil && in.path != "" {
+		offset = cp.offset(in.path)
+		if offset < 0 {
+			progress.add(in.size)
+			return nil
+		}
+	}
+
+	rc, err := in.open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", in.name(), err)
+	}
+	defer rc.Close()
+
+	r, err := decompress(progress.reader(rc))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", in.name(), err)
+	}
+	if mapping != nil {
+		if r, err = csv2lp.NewReader(r, mapping); err != nil {
+			return err
+		}
+	}
+	if offset > 0 {
+		if _, err := io.CopyN(ioutil.Discard, r, offset); err != nil {
+			return fmt.Errorf("failed to resume %s at offset %d: %v", in.name(), offset, err)
+		}
+	}
+
+	w := *bw
+	var cpErr error
+	if cp != nil && in.path != "" {
+		w.Checkpoint = func(n int64) error {
+			cpErr = cp.set(in.path, offset+n)
+			return cpErr
+		}
+	}
+
+	n, err := w.Write(ctx, orgID, bucketID, r)
+	if cpErr != nil {
+		return fmt.Errorf("failed to save checkpoint: %v", cpErr)
+	}
+	if err != nil {
+		if err == context.Canceled {
+			return err
+		}
+		if cp != nil && in.path != "" {
+			return fmt.Errorf("failed to write %s after %d bytes: %v; run the command again to resume", in.name(), offset+n, err)
+		}
+		return fmt.Errorf("failed to write %s after %d bytes: %v", in.name(), offset+n, err)
 	}
 
+	if cp != nil && in.path != "" {
+		if err := cp.set(in.path, -1); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %v", err)
+		}
+	}
 	return nil
 }
+
+// writeInput is a source of line protocol for the write command.
+type writeInput struct {
+	path string // path is the file of the input, empty for stdin and literal line protocol
+	size int64  // size is the size of the file, zero if unknown
+	open func() (io.ReadCloser, error)
+}
+
+func (in writeInput) name() string {
+	if in.path == "" {
+		return "input"
+	}
+	return in.path
+}
+
+// writeInputs returns the inputs given by the arguments and file flags.
+// Arguments prefixed with @ and file flags name files, directories or glob
+// patterns, - is stdin, and any other argument is line protocol.
+func writeInputs(args, files []string) ([]writeInput, error) {
+	var inputs []writeInput
+	for _, arg := range args {
+		switch {
+		case arg == "-":
+			inputs = append(inputs, writeInput{
+				open: func() (io.ReadCloser, error) { return ioutil.NopCloser(os.Stdin), nil },
+			})
+		case strings.HasPrefix(arg, "@"):
+			files = append(files, arg[1:])
+		default:
+			lp := arg
+			inputs = append(inputs, writeInput{
+				open: func() (io.ReadCloser, error) { return ioutil.NopCloser(strings.NewReader(lp)), nil },
+			})
+		}
+	}
+
+	for _, f := range files {
+		paths, err := expandWritePath(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			fi, err := os.Stat(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %q: %v", p, err)
+			}
+			path := p
+			inputs = append(inputs, writeInput{
+				path: path,
+				size: fi.Size(),
+				open: func() (io.ReadCloser, error) { return os.Open(path) },
+			})
+		}
+	}
+	return inputs, nil
+}
+
+// expandWritePath returns the files matching the glob pattern p. Directories
+// are replaced by the files they contain.
+func expandWritePath(p string) ([]string, error) {
+	matches := []string{p}
+	if strings.ContainsAny(p, "*?[") {
+		var err error
+		if matches, err = filepath.Glob(p); err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %v", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", p)
+		}
+	}
+
+	var paths []string
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %q: %v", m, err)
+		}
+		if !fi.IsDir() {
+			paths = append(paths, m)
+			continue
+		}
+		err = filepath.Walk(m, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %q: %v", m, err)
+		}
+	}
+	return paths, nil
+}
+
+// decompress returns a reader of the decompressed contents of r if r is gzip
+// compressed, or a reader of r otherwise.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// writeCheckpoint records how much of every file has been written. The
+// offset of a file is the number of bytes of line protocol written, after
+// decompression and CSV conversion, or -1 once the file has been written
+// completely.
+type writeCheckpoint struct {
+	path string
+
+	mu    sync.Mutex
+	files map[string]int64
+}
+
+func loadWriteCheckpoint(path string) (*writeCheckpoint, error) {
+	cp := &writeCheckpoint{
+		path:  path,
+		files: make(map[string]int64),
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &cp.files); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %q: %v", path, err)
+	}
+	return cp, nil
+}
+
+func (cp *writeCheckpoint) key(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func (cp *writeCheckpoint) offset(path string) int64 {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.files[cp.key(path)]
+}
+
+// set records the offset of path and saves the checkpoint.
+func (cp *writeCheckpoint) set(path string, offset int64) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.files[cp.key(path)] = offset
+
+	b, err := json.MarshalIndent(cp.files, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write a temporary file first so an interrupted save does not corrupt
+	// the checkpoint.
+	tmp := cp.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cp.path)
+}
+
+// writeProgress reports the progress of reading the write inputs.
+type writeProgress struct {
+	w     io.Writer
+	total int64
+	read  int64 // accessed atomically
+
+	begin time.Time
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newWriteProgress(w io.Writer, inputs []writeInput) *writeProgress {
+	p := &writeProgress{w: w}
+	for _, in := range inputs {
+		p.total += in.size
+	}
+	return p
+}
+
+// add counts n bytes as read.
+func (p *writeProgress) add(n int64) {
+	atomic.AddInt64(&p.read, n)
+}
+
+// reader returns a reader counting the bytes read from r.
+func (p *writeProgress) reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+// start prints the progress every interval until stop is called.
+func (p *writeProgress) start(interval time.Duration) {
+	p.begin = time.Now()
+	p.done = make(chan struct{})
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				p.print()
+			case <-p.done:
+				p.print()
+				fmt.Fprintln(p.w)
+				return
+			}
+		}
+	}()
+}
+
+func (p *writeProgress) stop() {
+	close(p.done)
+	p.wg.Wait()
+}
+
+func (p *writeProgress) print() {
+	const width = 30
+
+	read := atomic.LoadInt64(&p.read)
+	if read > p.total {
+		read = p.total
+	}
+	frac := float64(read) / float64(p.total)
+	bar := strings.Repeat("=", int(frac*width))
+	if len(bar) < width {
+		bar += ">" + strings.Repeat(" ", width-len(bar)-1)
+	}
+
+	var speed float64
+	if d := time.Since(p.begin).Seconds(); d > 0 {
+		speed = float64(read) / d
+	}
+	fmt.Fprintf(p.w, "\r[%s] %5.1f%% %s / %s %s/s ", bar, frac*100, formatByteSize(float64(read)), formatByteSize(float64(p.total)), formatByteSize(speed))
+}
+
+type progressReader struct {
+	r io.Reader
+	p *writeProgress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.add(int64(n))
+	return n, err
+}
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+	{"B", 1},
+}
+
+// parseByteSize parses a size such as 512KB, 10MiB or 1000.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	mult := int64(1)
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(u.suffix)) {
+			s, mult = strings.TrimSpace(s[:len(s)-len(u.suffix)]), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
+
+// formatByteSize formats n bytes with a binary unit.
+func formatByteSize(n float64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", n/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", n/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", n/(1<<10))
+	}
+	return fmt.Sprintf("%.0f B", n)
+}
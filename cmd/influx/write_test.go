@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_writeInputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.lp", "b.lp", "c.txt", "sub/d.lp"} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("m f=1\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := func(args, files []string) []string {
+		t.Helper()
+		inputs, err := writeInputs(args, files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ps []string
+		for _, in := range inputs {
+			if in.path == "" {
+				ps = append(ps, "")
+				continue
+			}
+			rel, _ := filepath.Rel(dir, in.path)
+			ps = append(ps, rel)
+		}
+		return ps
+	}
+
+	if got, want := paths([]string{"m f=1", "@" + filepath.Join(dir, "a.lp")}, nil), []string{"", "a.lp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("line protocol and file = %v, want %v", got, want)
+	}
+	if got, want := paths(nil, []string{filepath.Join(dir, "*.lp")}), []string{"a.lp", "b.lp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("glob = %v, want %v", got, want)
+	}
+	if got, want := paths(nil, []string{dir}), []string{"a.lp", "b.lp", "c.txt", filepath.Join("sub", "d.lp")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("directory = %v, want %v", got, want)
+	}
+
+	if _, err := writeInputs(nil, []string{filepath.Join(dir, "*.csv")}); err == nil {
+		t.Error("expected an error for a pattern without matches")
+	}
+	if _, err := writeInputs(nil, []string{filepath.Join(dir, "missing.lp")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func Test_decompress(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("m f=1\n"))
+	zw.Close()
+
+	for name, in := range map[string][]byte{
+		"gzip":  buf.Bytes(),
+		"plain": []byte("m f=1\n"),
+	} {
+		r, err := decompress(bytes.NewReader(in))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(b) != "m f=1\n" {
+			t.Errorf("%s: decompress() = %q", name, b)
+		}
+	}
+}
+
+func Test_writeCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "checkpoint.json")
+	cp, err := loadWriteCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.set("a.lp", 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.set("b.lp", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err = loadWriteCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]int64{"a.lp": 100, "b.lp": -1, "c.lp": 0} {
+		if got := cp.offset(file); got != want {
+			t.Errorf("offset(%s) = %d, want %d", file, got, want)
+		}
+	}
+}
+
+func Test_parseByteSize(t *testing.T) {
+	for s, want := range map[string]int64{
+		"1000":  1000,
+		"512KB": 512000,
+		"10MiB": 10 << 20,
+		"1 gb":  1e9,
+		"100 B": 100,
+		"2kib":  2048,
+		"0.5MB": -1,
+		"lots":  -1,
+	} {
+		got, err := parseByteSize(s)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("parseByteSize(%q) expected an error", s)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+}
+
+func Test_fluxWriteF_csvPrecision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mapping := filepath.Join(dir, "mapping.yml")
+	if err := ioutil.WriteFile(mapping, []byte("measurement: cpu\ncolumns:\n- {column: usage, type: field}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := writeFlags
+	defer func() { writeFlags = saved }()
+	writeFlags.Bucket = "b"
+	writeFlags.Format = "csv"
+	writeFlags.CSVMapping = mapping
+	writeFlags.Precision = "ms"
+
+	writeCmd.SetOutput(ioutil.Discard)
+	defer writeCmd.SetOutput(nil)
+	if err := fluxWriteF(writeCmd, []string{"usage\n1"}); err == nil || !strings.Contains(err.Error(), "precision") {
+		t.Fatalf("expected the precision to be rejected with --format csv, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+// compactionPlanFlags defines the `compaction-plan` Command.
+var compactionPlanFlags = struct {
+	// Standard output, overridden for testing.
+	stdout io.Writer
+
+	dataDir               string
+	orgID                 string
+	fullWriteColdDuration time.Duration
+}{
+	stdout: os.Stdout,
+}
+
+// NewCompactionPlanCommand returns a new instance of the compaction-plan command.
+func NewCompactionPlanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compaction-plan",
+		Short: "Print the compactions that would run next on TSM files",
+		Long: `
+This command plans the compactions of the TSM files of a storage engine
+directory the way the storage engine does, without running them, to help
+understand and tune compactions offline.
+
+For every compaction, the following is output:
+
+	* The level of the compaction, where level 4 is a full or an optimize
+	  compaction;
+	* The TSM files compacted together; and
+	* The estimated size and number of TSM files written by the compaction,
+	  which is an upper bound since overwritten and deleted values are dropped.
+
+The files of the write ahead log are not read, so a full compaction is planned
+if the TSM files were last modified longer than full-write-cold-duration ago.`,
+		Args: cobra.NoArgs,
+		RunE: inspectCompactionPlan,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	dir = filepath.Join(dir, "engine/data")
+	cmd.Flags().StringVar(&compactionPlanFlags.dataDir, "data-dir", dir, fmt.Sprintf("use provided data directory (defaults to %s).", dir))
+	cmd.Flags().StringVar(&compactionPlanFlags.orgID, "org-id", "", "plan the compactions of the data of organization ID, when the data is partitioned by organization.")
+	cmd.Flags().DurationVar(&compactionPlanFlags.fullWriteColdDuration, "full-write-cold-duration", tsm1.DefaultCompactFullWriteColdDuration, "duration after the last write at which all TSM files are compacted.")
+
+	return cmd
+}
+
+func inspectCompactionPlan(cmd *cobra.Command, args []string) error {
+	dir := compactionPlanFlags.dataDir
+	if compactionPlanFlags.orgID != "" {
+		orgID, err := influxdb.IDFromString(compactionPlanFlags.orgID)
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(dir, orgID.String())
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+
+	store := tsm1.NewFileStore(dir)
+	if err := store.Open(context.Background()); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	plan := tsm1.PlanCompactions(store, compactionPlanFlags.fullWriteColdDuration, store.LastModified())
+	if len(plan) == 0 {
+		fmt.Fprintln(compactionPlanFlags.stdout, "No compactions planned.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(compactionPlanFlags.stdout, 8, 2, 1, ' ', 0)
+	fmt.Fprintln(tw, "Level\tFile\tSize")
+	for _, g := range plan {
+		level := fmt.Sprint(g.Level)
+		if g.Optimize {
+			level += " (optimize)"
+		}
+		for _, f := range g.Files {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", level, filepath.Base(f.Path), f.Size)
+		}
+		fmt.Fprintf(tw, "%s\t=> %d estimated file(s)\t%d\n", level, g.EstimatedFiles, g.EstimatedSize)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,168 @@
+package inspect
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// mustWriteCompactionFiles writes n level 1 TSM files to dir.
+func mustWriteCompactionFiles(t *testing.T, dir string, n int) {
+	t.Helper()
+	for gen := 1; gen <= n; gen++ {
+		mustWriteTSMFile(t, dir, gen,
+			tsmPoint{Org: influxdb.ID(1), Bucket: influxdb.ID(2), Measurement: "cpu", Field: "value", Value: tsm1.NewValue(int64(gen), float64(gen))},
+		)
+	}
+}
+
+func runCompactionPlan(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	cmd := NewCompactionPlanCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	compactionPlanFlags.stdout = &buf
+	defer func() { compactionPlanFlags.stdout = os.Stdout }()
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestCompactionPlan(t *testing.T) {
+	tests := []struct {
+		name  string
+		files int
+		args  []string
+		level string
+	}{
+		{
+			name:  "level 1",
+			files: 8,
+			level: "1",
+		},
+		{
+			name:  "full",
+			files: 2,
+			args:  []string{"--full-write-cold-duration", "1ns"},
+			level: "4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := mustTempDir(t)
+			defer os.RemoveAll(dir)
+			mustWriteCompactionFiles(t, dir, tt.files)
+
+			out, err := runCompactionPlan(t, append([]string{"--data-dir", dir}, tt.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if got, exp := len(lines), tt.files+2; got != exp {
+				t.Fatalf("unexpected number of lines -got/+exp\n%d\n%d\noutput:\n%s", got, exp, out)
+			}
+			if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "Level File Size" {
+				t.Errorf("unexpected header %q", lines[0])
+			}
+			for gen := 1; gen <= tt.files; gen++ {
+				fields := strings.Fields(lines[gen])
+				if len(fields) != 3 || fields[0] != tt.level || fields[1] != tsm1.DefaultFormatFileName(gen, 1)+".tsm" {
+					t.Errorf("unexpected file line %q", lines[gen])
+				}
+			}
+			if last := lines[len(lines)-1]; !strings.HasPrefix(last, tt.level+" ") || !strings.Contains(last, "=> 1 estimated file(s)") {
+				t.Errorf("unexpected estimate line %q", last)
+			}
+		})
+	}
+}
+
+func TestCompactionPlan_None(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+	mustWriteCompactionFiles(t, dir, 2)
+
+	out, err := runCompactionPlan(t, "--data-dir", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "No compactions planned.\n"; out != want {
+		t.Fatalf("unexpected output:\ngot=%s\nwant=%s", out, want)
+	}
+}
+
+func TestCompactionPlan_Org(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+	org := influxdb.ID(1)
+	orgDir := filepath.Join(dir, org.String())
+	if err := os.Mkdir(orgDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	mustWriteCompactionFiles(t, orgDir, 8)
+
+	out, err := runCompactionPlan(t, "--data-dir", dir, "--org-id", org.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "=> 1 estimated file(s)") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestCompactionPlan_Flags(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "missing data-dir",
+			args: []string{"--data-dir", filepath.Join(dir, "missing")},
+			err:  "no such file or directory",
+		},
+		{
+			name: "missing org directory",
+			args: []string{"--data-dir", dir, "--org-id", influxdb.ID(1).String()},
+			err:  "no such file or directory",
+		},
+		{
+			name: "invalid org-id",
+			args: []string{"--data-dir", dir, "--org-id", "bad"},
+			err:  "id must have a length of 16 bytes",
+		},
+		{
+			name: "invalid full-write-cold-duration",
+			args: []string{"--data-dir", dir, "--full-write-cold-duration", "soon"},
+			err:  "invalid argument",
+		},
+		{
+			name: "arguments",
+			args: []string{"--data-dir", dir, dir},
+			err:  "unknown command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCompactionPlan(t, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("unexpected error -got/+exp\n%v\n%s", err, tt.err)
+			}
+		})
+	}
+}
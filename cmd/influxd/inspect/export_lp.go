@@ -0,0 +1,150 @@
+package inspect
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+var exportLPFlags = struct {
+	DataDir    string
+	OutputPath string
+	Compress   bool
+
+	OrgID, BucketID string
+	Measurements    []string
+	Start, End      string
+}{}
+
+// NewExportLPCommand returns a new instance of the export-lp command.
+func NewExportLPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-lp",
+		Short: "Exports TSM data of a bucket as line protocol",
+		Long: `This command reads the TSM files of a storage engine directly and writes the
+points of a bucket as line protocol. It does not require the server to be
+running, so it can recover data when the server cannot start.
+
+Only data in TSM files is exported; points still in the WAL are not. Points
+are written in the order of the TSM files, and a point overwritten in a later
+file is written again, so writing the output to a bucket restores the latest
+value of every point.`,
+		Args: cobra.NoArgs,
+		RunE: inspectExportLPF,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	dir = filepath.Join(dir, "engine/data")
+	cmd.Flags().StringVar(&exportLPFlags.DataDir, "data-dir", dir, fmt.Sprintf("use provided data directory (defaults to %s).", dir))
+	cmd.Flags().StringVar(&exportLPFlags.OutputPath, "output-path", "", "write the line protocol to the file instead of stdout")
+	cmd.Flags().BoolVar(&exportLPFlags.Compress, "compress", false, "gzip compress the output")
+
+	cmd.Flags().StringVar(&exportLPFlags.OrgID, "org-id", "", "export only data belonging to organization ID.")
+	cmd.Flags().StringVar(&exportLPFlags.BucketID, "bucket-id", "", "export data belonging to bucket ID (required).")
+	cmd.Flags().StringArrayVar(&exportLPFlags.Measurements, "measurement", nil, "export only the measurement; may be repeated.")
+	cmd.Flags().StringVar(&exportLPFlags.Start, "start", "", "export only points at or after the RFC3339 time.")
+	cmd.Flags().StringVar(&exportLPFlags.End, "end", "", "export only points at or before the RFC3339 time.")
+
+	return cmd
+}
+
+func inspectExportLPF(cmd *cobra.Command, args []string) error {
+	// Line protocol does not record the bucket of a point, so the export is
+	// limited to a single bucket.
+	if exportLPFlags.BucketID == "" {
+		return errors.New("bucket-id is required")
+	}
+	bucketID, err := influxdb.IDFromString(exportLPFlags.BucketID)
+	if err != nil {
+		return err
+	}
+	var orgID *influxdb.ID
+	if exportLPFlags.OrgID != "" {
+		if orgID, err = influxdb.IDFromString(exportLPFlags.OrgID); err != nil {
+			return err
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(exportLPFlags.DataDir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	} else if len(files) == 0 {
+		return fmt.Errorf("no TSM files found in %s", exportLPFlags.DataDir)
+	}
+
+	var (
+		w   io.Writer = os.Stdout
+		out *os.File
+		zw  *gzip.Writer
+	)
+	if exportLPFlags.OutputPath != "" {
+		if out, err = os.Create(exportLPFlags.OutputPath); err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+	if exportLPFlags.Compress {
+		zw = gzip.NewWriter(w)
+		defer zw.Close()
+		w = zw
+	}
+
+	e := tsm1.NewLineProtocolExporter(w)
+	e.OrgID, e.BucketID = orgID, bucketID
+	if len(exportLPFlags.Measurements) > 0 {
+		e.Measurements = make(map[string]bool, len(exportLPFlags.Measurements))
+		for _, m := range exportLPFlags.Measurements {
+			e.Measurements[m] = true
+		}
+	}
+	if exportLPFlags.Start != "" {
+		t, err := time.Parse(time.RFC3339Nano, exportLPFlags.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+		e.MinTime = t.UnixNano()
+	}
+	if exportLPFlags.End != "" {
+		t, err := time.Parse(time.RFC3339Nano, exportLPFlags.End)
+		if err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+		e.MaxTime = t.UnixNano()
+	}
+	if e.MinTime > e.MaxTime {
+		return errors.New("start time must not be after end time")
+	}
+
+	// Glob returns the files sorted by name, which orders them by
+	// generation so later writes of a point come last.
+	for _, f := range files {
+		if err := e.ExportFile(f); err != nil {
+			return err
+		}
+	}
+	if err := e.Close(); err != nil {
+		return err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+	if out != nil {
+		return out.Close()
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package inspect
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestExportLP(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+	dataDir := filepath.Join(dir, "data")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	org, bucket, other := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	t0 := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	t1 := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano()
+	mustWriteTSMFile(t, dataDir, 1,
+		tsmPoint{Org: org, Bucket: bucket, Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "usage", Value: tsm1.NewValue(t0, 1.5)},
+		tsmPoint{Org: org, Bucket: bucket, Measurement: "cpu", Tags: map[string]string{"host": "a"}, Field: "usage", Value: tsm1.NewValue(t1, 2.5)},
+		tsmPoint{Org: org, Bucket: bucket, Measurement: "mem", Field: "free", Value: tsm1.NewValue(t0, int64(7))},
+		tsmPoint{Org: org, Bucket: other, Measurement: "disk", Field: "used", Value: tsm1.NewValue(t0, "x")},
+	)
+	// a later generation overwrites a point of the first file.
+	mustWriteTSMFile(t, dataDir, 2,
+		tsmPoint{Org: org, Bucket: bucket, Measurement: "mem", Field: "free", Value: tsm1.NewValue(t0, int64(8))},
+	)
+
+	export := func(t *testing.T, compress bool, args ...string) string {
+		t.Helper()
+		output := filepath.Join(dir, "export.lp")
+		defer os.Remove(output)
+
+		cmd := NewExportLPCommand()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--data-dir", dataDir, "--output-path", output}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Open(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var b []byte
+		if compress {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err = ioutil.ReadAll(zr)
+		} else {
+			b, err = ioutil.ReadAll(f)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	t.Run("bucket", func(t *testing.T) {
+		got := export(t, false, "--bucket-id", bucket.String())
+		want := "cpu,host=a usage=1.5 1546300800000000000\n" +
+			"cpu,host=a usage=2.5 1546387200000000000\n" +
+			"mem free=7i 1546300800000000000\n" +
+			"mem free=8i 1546300800000000000\n"
+		if got != want {
+			t.Fatalf("unexpected output:\ngot=%s\nwant=%s", got, want)
+		}
+	})
+
+	t.Run("org", func(t *testing.T) {
+		got := export(t, false, "--org-id", influxdb.ID(4).String(), "--bucket-id", bucket.String())
+		if got != "" {
+			t.Fatalf("unexpected output for other organization:\n%s", got)
+		}
+	})
+
+	t.Run("measurement and time range", func(t *testing.T) {
+		got := export(t, false, "--bucket-id", bucket.String(), "--measurement", "cpu", "--start", "2019-01-01T12:00:00Z", "--end", "2019-01-03T00:00:00Z")
+		want := "cpu,host=a usage=2.5 1546387200000000000\n"
+		if got != want {
+			t.Fatalf("unexpected output:\ngot=%s\nwant=%s", got, want)
+		}
+	})
+
+	t.Run("compress", func(t *testing.T) {
+		got := export(t, true, "--bucket-id", other.String(), "--compress")
+		want := "disk used=\"x\" 1546300800000000000\n"
+		if got != want {
+			t.Fatalf("unexpected output:\ngot=%s\nwant=%s", got, want)
+		}
+	})
+}
+
+func TestExportLP_Flags(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+	mustWriteTSMFile(t, dir, 1,
+		tsmPoint{Org: influxdb.ID(1), Bucket: influxdb.ID(2), Measurement: "cpu", Field: "usage", Value: tsm1.NewValue(1, 1.5)},
+	)
+	empty := filepath.Join(dir, "empty")
+	if err := os.Mkdir(empty, 0777); err != nil {
+		t.Fatal(err)
+	}
+	bucket := influxdb.ID(2).String()
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "missing bucket-id",
+			args: []string{"--data-dir", dir},
+			err:  "bucket-id is required",
+		},
+		{
+			name: "invalid bucket-id",
+			args: []string{"--data-dir", dir, "--bucket-id", "bad"},
+			err:  "id must have a length of 16 bytes",
+		},
+		{
+			name: "no TSM files",
+			args: []string{"--data-dir", empty, "--bucket-id", bucket},
+			err:  "no TSM files found in " + empty,
+		},
+		{
+			name: "invalid start time",
+			args: []string{"--data-dir", dir, "--bucket-id", bucket, "--start", "yesterday"},
+			err:  "invalid start time",
+		},
+		{
+			name: "invalid end time",
+			args: []string{"--data-dir", dir, "--bucket-id", bucket, "--end", "2019-01-01"},
+			err:  "invalid end time",
+		},
+		{
+			name: "start after end",
+			args: []string{"--data-dir", dir, "--bucket-id", bucket, "--start", "2019-01-02T00:00:00Z", "--end", "2019-01-01T00:00:00Z"},
+			err:  "start time must not be after end time",
+		},
+		{
+			name: "arguments",
+			args: []string{"--data-dir", dir, "--bucket-id", bucket, dir},
+			err:  "unknown command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewExportLPCommand()
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(append(tt.args, "--output-path", filepath.Join(dir, "export.lp")))
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("unexpected error -got/+exp\n%v\n%s", err, tt.err)
+			}
+		})
+	}
+}
@@ -16,14 +16,18 @@ func NewCommand() *cobra.Command {
 	subCommands := []*cobra.Command{
 		NewBuildTSICommand(),
 		NewExportBlocksCommand(),
+		NewExportLPCommand(),
 		NewExportIndexCommand(),
+		NewCompactionPlanCommand(),
 		NewReportTSMCommand(),
 		NewVerifyTSMCommand(),
 		NewVerifyWALCommand(),
 		NewReportTSICommand(),
+		NewReportCardinalityCommand(),
 		NewVerifySeriesFileCommand(),
 		NewDumpWALCommand(),
 		NewDumpTSICommand(),
+		NewReplayWALCommand(),
 	}
 
 	base.AddCommand(subCommands...)
@@ -0,0 +1,81 @@
+package inspect
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// tsmPoint is a single field value written to a TSM fixture.
+type tsmPoint struct {
+	Org, Bucket influxdb.ID
+	Measurement string
+	Tags        map[string]string
+	Field       string
+	Value       tsm1.Value
+}
+
+// mustWriteTSMFile writes points to a new TSM file of generation gen in dir
+// and returns the path of the file.
+func mustWriteTSMFile(t *testing.T, dir string, gen int, points ...tsmPoint) string {
+	t.Helper()
+
+	values := make(map[string][]tsm1.Value)
+	for _, p := range points {
+		name := tsdb.EncodeName(p.Org, p.Bucket)
+		tags := map[string]string{
+			models.MeasurementTagKey: p.Measurement,
+			models.FieldKeyTagKey:    p.Field,
+		}
+		for k, v := range p.Tags {
+			tags[k] = v
+		}
+		key := string(tsm1.SeriesFieldKeyBytes(string(models.MakeKey(name[:], models.NewTags(tags))), p.Field))
+		values[key] = append(values[key], p.Value)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	path := filepath.Join(dir, tsm1.DefaultFormatFileName(gen, 1)+"."+tsm1.TSMFileExtension)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if err := w.Write([]byte(k), values[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// mustTempDir returns a new temporary directory.
+func mustTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "inspect-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
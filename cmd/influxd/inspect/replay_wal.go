@@ -0,0 +1,112 @@
+package inspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+var replayWALFlags = struct {
+	// Standard output, overridden for testing.
+	Stdout io.Writer
+
+	Host       string
+	Token      string
+	SkipVerify bool
+
+	OrgID, BucketID string
+	Checkpoint      string
+	Verbose         bool
+}{
+	Stdout: os.Stdout,
+}
+
+// NewReplayWALCommand returns a new instance of the replay-wal command.
+func NewReplayWALCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay-wal",
+		Short: "Writes the points of WAL files to another instance",
+		Long: `This command reads WAL segment files, for example of a node that crashed before
+the WAL was written to TSM files, and writes their points to another instance
+over the HTTP API. Given a list of filepath globs (patterns which match to
+.wal file paths), the segments are replayed in the order of their sequence
+numbers, and a segment found in several files is replayed once.
+
+Points are written to the buckets with the same IDs on the target instance,
+so the buckets must be restored there first. Deletes recorded in the WAL are
+not replayed.
+
+With --checkpoint, the position of the last replayed entry is saved to the
+file, and a replay interrupted by an error skips the replayed entries when
+it is run again.`,
+		RunE: inspectReplayWAL,
+	}
+
+	cmd.Flags().StringVar(&replayWALFlags.Host, "host", "http://localhost:9999", "HTTP address of the instance to write to")
+	cmd.Flags().StringVarP(&replayWALFlags.Token, "token", "t", "", "API token with write permission to the buckets")
+	cmd.Flags().BoolVar(&replayWALFlags.SkipVerify, "skip-verify", false, "skip TLS certificate verification of the instance")
+	cmd.Flags().StringVar(&replayWALFlags.OrgID, "org-id", "", "replay only points belonging to organization ID")
+	cmd.Flags().StringVar(&replayWALFlags.BucketID, "bucket-id", "", "replay only points belonging to bucket ID")
+	cmd.Flags().StringVar(&replayWALFlags.Checkpoint, "checkpoint", "", "file recording the last replayed entry")
+	cmd.Flags().BoolVarP(&replayWALFlags.Verbose, "verbose", "v", false, "log the progress of the replay")
+
+	return cmd
+}
+
+func inspectReplayWAL(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no files provided. aborting")
+	}
+
+	config := logger.NewConfig()
+	config.Level = zapcore.WarnLevel
+	if replayWALFlags.Verbose {
+		config.Level = zapcore.InfoLevel
+	}
+	log, err := config.New(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(client.Config{
+		Addr:               replayWALFlags.Host,
+		Token:              replayWALFlags.Token,
+		InsecureSkipVerify: replayWALFlags.SkipVerify,
+	})
+	if err != nil {
+		return err
+	}
+
+	replay := &wal.Replay{
+		Logger:         log,
+		FileGlobs:      args,
+		Writer:         c,
+		CheckpointPath: replayWALFlags.Checkpoint,
+	}
+	if replayWALFlags.OrgID != "" {
+		if replay.OrgID, err = influxdb.IDFromString(replayWALFlags.OrgID); err != nil {
+			return err
+		}
+	}
+	if replayWALFlags.BucketID != "" {
+		if replay.BucketID, err = influxdb.IDFromString(replayWALFlags.BucketID); err != nil {
+			return err
+		}
+	}
+
+	report, err := replay.Run(context.Background())
+	if report != nil {
+		fmt.Fprintf(replayWALFlags.Stdout, "Replayed %d points of %d entries from %d segments; skipped %d replayed entries and %d deletes\n",
+			report.Points, report.Entries, report.Segments, report.SkippedEntries, report.Deletes)
+	}
+	return err
+}
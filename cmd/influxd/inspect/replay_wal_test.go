@@ -0,0 +1,217 @@
+package inspect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/value"
+)
+
+func mustWriteWALSegment(t *testing.T, path string, entries ...wal.WALEntry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := wal.NewWALSegmentWriter(f)
+	for _, entry := range entries {
+		b, err := entry.Encode(make([]byte, entry.MarshalSize()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(entry.Type(), snappy.Encode(nil, b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type replayWALWrite struct {
+	Org, Bucket string
+	Lines       string
+}
+
+// newReplayWALServer returns a server recording the writes made with the token.
+func newReplayWALServer(token string, writes *[]replayWALWrite) *httptest.Server {
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc("/api/v2/write", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") != "Token "+token {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(nethttp.StatusBadRequest)
+				return
+			}
+			body = gz
+		}
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		*writes = append(*writes, replayWALWrite{
+			Org:    r.URL.Query().Get("org"),
+			Bucket: r.URL.Query().Get("bucket"),
+			Lines:  string(data),
+		})
+		w.WriteHeader(nethttp.StatusNoContent)
+	})
+	return httptest.NewServer(mux)
+}
+
+func runReplayWAL(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	cmd := NewReplayWALCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	replayWALFlags.Stdout = &buf
+	defer func() { replayWALFlags.Stdout = os.Stdout }()
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestReplayWAL(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+
+	org, bucket1, bucket2 := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	name1, name2 := tsdb.EncodeName(org, bucket1), tsdb.EncodeName(org, bucket2)
+	mustWriteWALSegment(t, filepath.Join(dir, "_00001.wal"), &wal.WriteWALEntry{Values: map[string][]value.Value{
+		string(name1[:]) + ",\x00=cpu,host=A,\xff=value#!~#value": {value.NewValue(1, 1.5), value.NewValue(2, 2.5)},
+	}})
+	mustWriteWALSegment(t, filepath.Join(dir, "_00002.wal"),
+		&wal.DeleteBucketRangeWALEntry{OrgID: org, BucketID: bucket1, Min: 0, Max: 1},
+		&wal.WriteWALEntry{Values: map[string][]value.Value{
+			string(name2[:]) + ",\x00=mem,host=B,\xff=used#!~#used": {value.NewValue(1, int64(10))},
+		}},
+	)
+
+	var writes []replayWALWrite
+	ts := newReplayWALServer("secret", &writes)
+	defer ts.Close()
+
+	checkpoint := filepath.Join(dir, "checkpoint")
+	out, err := runReplayWAL(t, "--host", ts.URL, "--token", "secret", "--checkpoint", checkpoint, filepath.Join(dir, "*.wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantWrites := []replayWALWrite{
+		{Org: org.String(), Bucket: bucket1.String(), Lines: "cpu,host=A value=1.5 1\ncpu,host=A value=2.5 2\n"},
+		{Org: org.String(), Bucket: bucket2.String(), Lines: "mem,host=B used=10i 1\n"},
+	}
+	if diff := cmp.Diff(writes, wantWrites); diff != "" {
+		t.Fatalf("unexpected writes -got/+want\n%s", diff)
+	}
+	if want := "Replayed 3 points of 2 entries from 2 segments; skipped 0 replayed entries and 1 deletes\n"; out != want {
+		t.Fatalf("unexpected output:\ngot=%s\nwant=%s", out, want)
+	}
+
+	// a second run skips the entries recorded in the checkpoint.
+	writes = nil
+	out, err = runReplayWAL(t, "--host", ts.URL, "--token", "secret", "--checkpoint", checkpoint, filepath.Join(dir, "*.wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(writes) != 0 {
+		t.Fatalf("unexpected writes after checkpoint: %v", writes)
+	}
+	if want := "Replayed 0 points of 0 entries from 1 segments; skipped 2 replayed entries and 0 deletes\n"; out != want {
+		t.Fatalf("unexpected output:\ngot=%s\nwant=%s", out, want)
+	}
+}
+
+func TestReplayWAL_Bucket(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+
+	org, bucket1, bucket2 := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	name1, name2 := tsdb.EncodeName(org, bucket1), tsdb.EncodeName(org, bucket2)
+	mustWriteWALSegment(t, filepath.Join(dir, "_00001.wal"), &wal.WriteWALEntry{Values: map[string][]value.Value{
+		string(name1[:]) + ",\x00=cpu,\xff=value#!~#value": {value.NewValue(1, 1.5)},
+		string(name2[:]) + ",\x00=cpu,\xff=value#!~#value": {value.NewValue(1, 2.5)},
+	}})
+
+	var writes []replayWALWrite
+	ts := newReplayWALServer("secret", &writes)
+	defer ts.Close()
+
+	if _, err := runReplayWAL(t, "--host", ts.URL, "--token", "secret", "--org-id", org.String(), "--bucket-id", bucket2.String(), filepath.Join(dir, "*.wal")); err != nil {
+		t.Fatal(err)
+	}
+	wantWrites := []replayWALWrite{
+		{Org: org.String(), Bucket: bucket2.String(), Lines: "cpu value=2.5 1\n"},
+	}
+	if diff := cmp.Diff(writes, wantWrites); diff != "" {
+		t.Fatalf("unexpected writes -got/+want\n%s", diff)
+	}
+}
+
+func TestReplayWAL_Flags(t *testing.T) {
+	dir := mustTempDir(t)
+	defer os.RemoveAll(dir)
+	glob := filepath.Join(dir, "*.wal")
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "no files",
+			args: []string{"--token", "secret"},
+			err:  "no files provided. aborting",
+		},
+		{
+			name: "invalid host",
+			args: []string{"--host", "localhost:9999", glob},
+			err:  "server address must be an absolute http or https url",
+		},
+		{
+			name: "invalid org-id",
+			args: []string{"--org-id", "bad", glob},
+			err:  "id must have a length of 16 bytes",
+		},
+		{
+			name: "invalid bucket-id",
+			args: []string{"--bucket-id", "bad", glob},
+			err:  "id must have a length of 16 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runReplayWAL(t, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("unexpected error -got/+exp\n%v\n%s", err, tt.err)
+			}
+		})
+	}
+}
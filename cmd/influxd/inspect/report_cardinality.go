@@ -0,0 +1,235 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+var reportCardinalityFlags = struct {
+	// Standard output, overridden for testing.
+	Stdout io.Writer
+
+	TSM  bool
+	Top  int
+	JSON bool
+
+	OrgID, BucketID string
+}{
+	Stdout: os.Stdout,
+}
+
+// NewReportCardinalityCommand returns a new instance of the report-cardinality command.
+func NewReportCardinalityCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report-cardinality [engine-path]...",
+		Short: "Reports series cardinality by measurement and tag key",
+		Long: `This command reads the series of one or more storage engine directories,
+and reports the number of series of every measurement, broken down by tag key.
+It is meant to run against a stopped node or a copy of an engine directory.
+
+By default the series are read from the TSI index and series file of the
+engine. With --tsm the series are read from the TSM files of the engine
+instead, which works without an index but does not include series only
+present in the WAL. Series found in several engine directories are counted
+once.
+
+For each measurement, the following is output:
+
+	* The organization and bucket of the measurement;
+	* The number of series of the measurement; and
+	* For each tag key, the number of series with the tag key and the
+	  number of distinct values of the tag key. Field keys are reported
+	  as the _field tag key.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: inspectReportCardinalityF,
+	}
+
+	cmd.Flags().BoolVar(&reportCardinalityFlags.TSM, "tsm", false, "Read the series from the TSM files instead of the TSI index")
+	cmd.Flags().IntVarP(&reportCardinalityFlags.Top, "top", "t", 0, "Limit results to the top n measurements")
+	cmd.Flags().BoolVar(&reportCardinalityFlags.JSON, "json", false, "Output the report as JSON")
+	cmd.Flags().StringVar(&reportCardinalityFlags.OrgID, "org-id", "", "Only report series of the organization ID")
+	cmd.Flags().StringVar(&reportCardinalityFlags.BucketID, "bucket-id", "", "Only report series of the bucket ID. Requires org-id to be set")
+
+	return cmd
+}
+
+func inspectReportCardinalityF(cmd *cobra.Command, args []string) error {
+	report := tsdb.NewCardinalityReport()
+
+	if reportCardinalityFlags.OrgID == "" && reportCardinalityFlags.BucketID != "" {
+		return errors.New("org-id must be set for non-empty bucket-id")
+	}
+	if reportCardinalityFlags.OrgID != "" {
+		orgID, err := influxdb.IDFromString(reportCardinalityFlags.OrgID)
+		if err != nil {
+			return err
+		}
+		report.OrgID = orgID
+	}
+	if reportCardinalityFlags.BucketID != "" {
+		bucketID, err := influxdb.IDFromString(reportCardinalityFlags.BucketID)
+		if err != nil {
+			return err
+		}
+		report.BucketID = bucketID
+	}
+
+	paths := args
+	if len(paths) == 0 {
+		dir, err := fs.InfluxDir()
+		if err != nil {
+			return err
+		}
+		paths = []string{filepath.Join(dir, "engine")}
+	}
+
+	for _, path := range paths {
+		var err error
+		if reportCardinalityFlags.TSM {
+			err = addTSMCardinality(report, filepath.Join(path, "data"))
+		} else {
+			err = addTSICardinality(report, filepath.Join(path, "index"), filepath.Join(path, "_series"))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	summary := report.Summary()
+	if n := reportCardinalityFlags.Top; n > 0 && n < len(summary.Measurements) {
+		summary.Measurements = summary.Measurements[:n]
+	}
+
+	if reportCardinalityFlags.JSON {
+		enc := json.NewEncoder(reportCardinalityFlags.Stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(summary)
+	}
+	return printCardinalitySummary(reportCardinalityFlags.Stdout, summary)
+}
+
+// addTSICardinality adds the series of the index at indexPath to the report.
+func addTSICardinality(report *tsdb.CardinalityReport, indexPath, seriesPath string) error {
+	for _, p := range []string{indexPath, seriesPath} {
+		if _, err := os.Stat(p); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	sfile := tsdb.NewSeriesFile(seriesPath)
+	sfile.DisableMetrics()
+	if err := sfile.Open(ctx); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	idx := tsi1.NewIndex(sfile, tsi1.NewConfig(), tsi1.WithPath(indexPath), tsi1.DisableCompactions(), tsi1.DisableMetrics())
+	if err := idx.Open(ctx); err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	mitr, err := idx.MeasurementIterator()
+	if err != nil {
+		return err
+	} else if mitr == nil {
+		return nil
+	}
+	defer mitr.Close()
+
+	for {
+		name, err := mitr.Next()
+		if err != nil {
+			return err
+		} else if name == nil {
+			return nil
+		}
+
+		sitr, err := idx.MeasurementSeriesIDIterator(name)
+		if err != nil {
+			return err
+		} else if sitr == nil {
+			continue
+		}
+		for {
+			e, err := sitr.Next()
+			if err != nil {
+				sitr.Close()
+				return err
+			} else if e.SeriesID.IsZero() {
+				break
+			}
+			if name, tags := sfile.Series(e.SeriesID); name != nil {
+				report.Add(name, tags)
+			}
+		}
+		if err := sitr.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// addTSMCardinality adds the series of the TSM files in dir to the report.
+func addTSMCardinality(report *tsdb.CardinalityReport, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+
+	var tags models.Tags
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("%s: %v", path, err)
+		}
+
+		itr := r.Iterator(nil)
+		for itr.Next() {
+			seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(itr.Key())
+			var name []byte
+			name, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+			report.Add(name, tags)
+		}
+		err = itr.Err()
+		if cerr := r.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func printCardinalitySummary(w io.Writer, summary *tsdb.CardinalitySummary) error {
+	tw := tabwriter.NewWriter(w, 8, 2, 1, ' ', 0)
+	fmt.Fprintf(tw, "Series Cardinality: %d\n\n", summary.Series)
+	fmt.Fprintln(tw, "Organization\tBucket\tMeasurement\tSeries\tTag Key\tSeries\tValues")
+	for _, m := range summary.Measurements {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t\t\t\n", m.OrgID, m.BucketID, m.Measurement, m.Series)
+		for _, tk := range m.TagKeys {
+			fmt.Fprintf(tw, "\t\t\t\t%s\t%d\t%d\n", tk.Key, tk.Series, tk.Values)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,146 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func mustWriteCardinalityEngine(t *testing.T) string {
+	t.Helper()
+	dir := mustTempDir(t)
+	dataDir := filepath.Join(dir, "data")
+	if err := os.Mkdir(dataDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	org, bucket1, bucket2 := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	mustWriteTSMFile(t, dataDir, 1,
+		tsmPoint{Org: org, Bucket: bucket1, Measurement: "cpu", Tags: map[string]string{"host": "A"}, Field: "value", Value: tsm1.NewValue(1, 1.0)},
+		tsmPoint{Org: org, Bucket: bucket1, Measurement: "cpu", Tags: map[string]string{"host": "B"}, Field: "value", Value: tsm1.NewValue(1, 2.0)},
+		tsmPoint{Org: org, Bucket: bucket1, Measurement: "cpu", Tags: map[string]string{"host": "B"}, Field: "idle", Value: tsm1.NewValue(1, 3.0)},
+		tsmPoint{Org: org, Bucket: bucket2, Measurement: "mem", Tags: map[string]string{"host": "A"}, Field: "used", Value: tsm1.NewValue(1, int64(4))},
+	)
+	// a series already present in the first file is counted once.
+	mustWriteTSMFile(t, dataDir, 2,
+		tsmPoint{Org: org, Bucket: bucket1, Measurement: "cpu", Tags: map[string]string{"host": "A"}, Field: "value", Value: tsm1.NewValue(2, 5.0)},
+	)
+	return dir
+}
+
+func runReportCardinality(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	cmd := NewReportCardinalityCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	reportCardinalityFlags.Stdout = &buf
+	defer func() { reportCardinalityFlags.Stdout = os.Stdout }()
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestReportCardinality_TSM(t *testing.T) {
+	dir := mustWriteCardinalityEngine(t)
+	defer os.RemoveAll(dir)
+
+	out, err := runReportCardinality(t, "--tsm", "--json", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var summary tsdb.CardinalitySummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("unable to decode output %q: %v", out, err)
+	}
+	if got, exp := summary.Series, 4; got != exp {
+		t.Errorf("unexpected series cardinality -got/+exp\n%d\n%d", got, exp)
+	}
+	if got, exp := len(summary.Measurements), 2; got != exp {
+		t.Fatalf("unexpected number of measurements -got/+exp\n%d\n%d", got, exp)
+	}
+	cpu := summary.Measurements[0]
+	if cpu.Measurement != "cpu" || cpu.Series != 3 || cpu.BucketID != influxdb.ID(2) {
+		t.Errorf("unexpected cpu cardinality: %+v", cpu)
+	}
+}
+
+func TestReportCardinality_Filter(t *testing.T) {
+	dir := mustWriteCardinalityEngine(t)
+	defer os.RemoveAll(dir)
+
+	out, err := runReportCardinality(t, "--tsm", "--org-id", influxdb.ID(1).String(), "--bucket-id", influxdb.ID(3).String(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "Series Cardinality: 1\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if !strings.Contains(out, "mem") || strings.Contains(out, "cpu") {
+		t.Errorf("expected only the mem measurement in output:\n%s", out)
+	}
+}
+
+func TestReportCardinality_Top(t *testing.T) {
+	dir := mustWriteCardinalityEngine(t)
+	defer os.RemoveAll(dir)
+
+	out, err := runReportCardinality(t, "--tsm", "--json", "--top", "1", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary tsdb.CardinalitySummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("unable to decode output %q: %v", out, err)
+	}
+	if got, exp := len(summary.Measurements), 1; got != exp {
+		t.Errorf("unexpected number of measurements -got/+exp\n%d\n%d", got, exp)
+	}
+}
+
+func TestReportCardinality_Flags(t *testing.T) {
+	dir := mustWriteCardinalityEngine(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "bucket-id without org-id",
+			args: []string{"--tsm", "--bucket-id", influxdb.ID(2).String(), dir},
+			err:  "org-id must be set for non-empty bucket-id",
+		},
+		{
+			name: "invalid org-id",
+			args: []string{"--tsm", "--org-id", "bad", dir},
+			err:  "id must have a length of 16 bytes",
+		},
+		{
+			name: "missing index",
+			args: []string{dir},
+			err:  filepath.Join(dir, "index"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runReportCardinality(t, tt.args...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("unexpected error -got/+exp\n%v\n%s", err, tt.err)
+			}
+		})
+	}
+}
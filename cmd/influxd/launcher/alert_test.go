@@ -0,0 +1,100 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestLauncher_Alerts(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	every, err := parser.ParseDuration("1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chk := &check.Deadman{
+		Base: check.Base{
+			Name:                  "cpu",
+			OrgID:                 l.Org.ID,
+			Every:                 (*notification.Duration)(every),
+			StatusMessageTemplate: "cpu is dead",
+			Query: influxdb.DashboardQuery{
+				Text: fmt.Sprintf(`data = from(bucket: %q) |> range(start: -1m)`, l.Bucket.Name),
+			},
+		},
+		TimeSince: (*notification.Duration)(every),
+		StaleTime: (*notification.Duration)(every),
+		Level:     notification.Critical,
+	}
+	if err := l.KeyValueService().CreateCheck(ctx, influxdb.CheckCreate{Check: chk, Status: influxdb.Active}, l.Auth.UserID); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path string, body string, wantCode int, v interface{}) {
+		t.Helper()
+		req := l.NewHTTPRequestOrFail(t, method, path, l.Auth.Token, body)
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != wantCode {
+			t.Fatalf("%s %s returned %d, want %d: %s", method, path, resp.StatusCode, wantCode, data)
+		}
+		if v != nil {
+			if err := json.Unmarshal(data, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	now := time.Now()
+	statuses := []string{
+		fmt.Sprintf(`statuses,_check_id=%s,_check_name=cpu,_level=crit _message="cpu is dead" %d`, chk.ID, now.Add(-2*time.Minute).UnixNano()),
+		fmt.Sprintf(`statuses,_check_id=%s,_check_name=cpu,_level=ok _message="cpu is alive" %d`, chk.ID, now.Add(-time.Minute).UnixNano()),
+		// A critical status of a check that was deleted.
+		fmt.Sprintf(`statuses,_check_id=020f755c3c082000,_check_name=mem,_level=crit _message="mem is full" %d`, now.Add(-time.Minute).UnixNano()),
+	}
+	do("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, influxdb.MonitoringSystemBucketName), strings.Join(statuses, "\n"), nethttp.StatusNoContent, nil)
+
+	var alerts influxdb.AlertSummary
+	do("GET", fmt.Sprintf("/api/v2/alerts?orgID=%s", l.Org.ID), "", nethttp.StatusOK, &alerts)
+	if alerts.UnacknowledgedCritical != 2 || len(alerts.Alerts) != 2 {
+		t.Fatalf("expected 2 unacknowledged alerts, got %+v", alerts)
+	}
+	if a := alerts.Alerts[1]; a.CheckID != chk.ID || a.Message != "cpu is dead" || a.Acknowledged {
+		t.Fatalf("unexpected alert of the check %+v", a)
+	}
+
+	var ack influxdb.AlertAcknowledgement
+	do("POST", fmt.Sprintf("/api/v2/alerts/%s/ack", chk.ID), "", nethttp.StatusCreated, &ack)
+	if ack.CheckID != chk.ID || ack.OrgID != l.Org.ID || ack.UserID != l.Auth.UserID {
+		t.Fatalf("unexpected acknowledgement %+v", ack)
+	}
+	do("POST", "/api/v2/alerts/020f755c3c082000/ack", "", nethttp.StatusNotFound, nil)
+
+	do("GET", fmt.Sprintf("/api/v2/alerts?org=%s", l.Org.Name), "", nethttp.StatusOK, &alerts)
+	if alerts.UnacknowledgedCritical != 1 {
+		t.Fatalf("expected 1 unacknowledged alert, got %+v", alerts)
+	}
+	if a := alerts.Alerts[1]; a.CheckID != chk.ID || !a.Acknowledged || a.Acknowledgement == nil || a.Acknowledgement.ID != ack.ID {
+		t.Fatalf("expected the alert of the check to be acknowledged, got %+v", a)
+	}
+}
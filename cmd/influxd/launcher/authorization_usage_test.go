@@ -0,0 +1,31 @@
+package launcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_AuthorizationUsage(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--authorization-usage-interval", "10ms")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	svc := l.AuthorizationService()
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		a, err := svc.FindAuthorizationByID(ctx, l.Auth.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.LastUsedAt != nil {
+			if a.LastUsedIP != "127.0.0.1" {
+				t.Fatalf("expected the use to be recorded from the loopback address, got %q", a.LastUsedIP)
+			}
+			return
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("expected the use of the authorization to be recorded")
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_BucketMigration(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// Two series of cpu with a point every 15s for 10m, and a point of
+	// another measurement.
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 40; i++ {
+		ts := start.Add(time.Duration(i) * 15 * time.Second).UnixNano()
+		lines = append(lines,
+			fmt.Sprintf("cpu,host=a usage=%d %d", i, ts),
+			fmt.Sprintf("cpu,host=b usage=%d %d", 2*i, ts),
+		)
+	}
+	lines = append(lines, fmt.Sprintf("mem,host=a used=1 %d", start.UnixNano()))
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	dest := &influxdb.Bucket{OrgID: l.Org.ID, Name: "cpu_5m"}
+	if err := l.BucketService().CreateBucket(ctx, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path, body string) []byte {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest(method, path, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode/100 != 2 {
+			t.Fatalf("%s %s returned %d: %s", method, path, resp.StatusCode, b)
+		}
+		return b
+	}
+
+	var m struct {
+		ID            influxdb.ID `json:"id"`
+		Status        string      `json:"status"`
+		PointsWritten int64       `json:"pointsWritten"`
+		Error         string      `json:"error"`
+	}
+	body := do("POST", "/api/v2/bucketMigrations", fmt.Sprintf(`{
+		"orgID": %q,
+		"sourceBucketID": %q,
+		"destinationBucketID": %q,
+		"start": "2019-12-01T00:00:00Z",
+		"stop": "2019-12-01T00:10:00Z",
+		"measurement": "cpu",
+		"everySeconds": 300,
+		"aggregate": "mean"
+	}`, l.Org.ID, l.Bucket.ID, dest.ID))
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(10 * time.Second); m.Status == influxdb.BucketMigrationRunning; {
+		if time.Now().After(deadline) {
+			t.Fatalf("migration did not finish: %s", body)
+		}
+		time.Sleep(10 * time.Millisecond)
+		body = do("GET", "/api/v2/bucketMigrations/"+m.ID.String(), "")
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// two 5m windows of the two series.
+	if m.Status != influxdb.BucketMigrationCompleted || m.PointsWritten != 4 {
+		t.Fatalf("expected migration to complete with 4 points, got %s", body)
+	}
+
+	q := fmt.Sprintf(`from(bucket: "%s")
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-01T00:10:00Z)
+	|> filter(fn: (r) => r.host == "a")
+	|> keep(columns: ["_time", "_value"])`, dest.Name)
+	got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, q)
+	for _, want := range []string{",2019-12-01T00:00:00Z,9.5\r\n", ",2019-12-01T00:05:00Z,29.5\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected the mean of the windows in the destination bucket, got:\n%s", got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package launcher_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_DashboardRender(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	var points strings.Builder
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&points, "cpu usage=%d %d\n", i%10, start.Add(time.Duration(i)*time.Minute).UnixNano())
+	}
+	l.WritePointsOrFail(t, points.String())
+
+	ds := l.DashboardService()
+	d := &influxdb.Dashboard{OrganizationID: l.Org.ID, Name: "hosts"}
+	if err := ds.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	cell := &influxdb.Cell{CellProperty: influxdb.CellProperty{X: 0, Y: 0, W: 12, H: 4}}
+	if err := ds.AddDashboardCell(ctx, d.ID, cell, influxdb.AddDashboardCellOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	name := "cpu"
+	if _, err := ds.UpdateDashboardCellView(ctx, d.ID, cell.ID, influxdb.ViewUpdate{
+		ViewContentsUpdate: influxdb.ViewContentsUpdate{Name: &name},
+		Properties: influxdb.XYViewProperties{
+			Type: influxdb.ViewPropertyTypeXY,
+			Queries: []influxdb.DashboardQuery{{
+				Text: fmt.Sprintf(`from(bucket: %q) |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> filter(fn: (r) => r._measurement == "cpu")`, l.Bucket.Name),
+			}},
+			ViewColors: []influxdb.ViewColor{{Type: "scale", Hex: "#ff0000"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := l.MustNewHTTPRequest("GET", fmt.Sprintf("/api/v2/dashboards/%s/render.png?start=-2h&width=600", d.ID), "")
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("render returned %d: %s", resp.StatusCode, b)
+	}
+
+	img, err := png.Decode(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 600, 320); got != want {
+		t.Fatalf("expected image of %v, got %v", want, got)
+	}
+	red, n := color.RGBA{0xff, 0x00, 0x00, 0xff}, 0
+	for x := 0; x < 600; x++ {
+		for y := 0; y < 320; y++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == red {
+				n++
+			}
+		}
+	}
+	if n < 300 {
+		t.Errorf("expected the chart of the points to be drawn, got %d pixels of the chart", n)
+	}
+}
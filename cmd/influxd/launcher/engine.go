@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
@@ -14,6 +15,8 @@ import (
 	"github.com/influxdata/influxdb/storage/readservice"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxql"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -31,6 +34,14 @@ type Engine interface {
 	prom.PrometheusCollector
 
 	SeriesCardinality() int64
+	SetCompactionThroughput(bytesPerSec, burst int) error
+	MeasurementStats() (tsm1.MeasurementStats, error)
+	MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats, error)
+	BucketCompactionPlan(ctx context.Context, orgID, bucketID influxdb.ID) ([]tsm1.CompactionPlanGroup, error)
+	storage.BucketOptimizer
+	storage.SeriesFileMaintainer
+	storage.BucketBlockStatsReader
+	storage.Snapshotter
 
 	WithLogger(log *zap.Logger)
 	Open(context.Context) error
@@ -124,6 +135,66 @@ func (t *TemporaryEngine) DeleteBucket(ctx context.Context, orgID, bucketID infl
 	return t.engine.DeleteBucket(ctx, orgID, bucketID)
 }
 
+// WriteSnapshot writes the caches of the engine to TSM files.
+func (t *TemporaryEngine) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
+	return t.engine.WriteSnapshot(ctx, status)
+}
+
+// SetCompactionThroughput changes the rate limit applied to TSM compactions.
+func (t *TemporaryEngine) SetCompactionThroughput(bytesPerSec, burst int) error {
+	return t.engine.SetCompactionThroughput(bytesPerSec, burst)
+}
+
+// MeasurementStats returns the current measurement stats for the engine.
+func (t *TemporaryEngine) MeasurementStats() (tsm1.MeasurementStats, error) {
+	return t.engine.MeasurementStats()
+}
+
+// MeasurementCardinalityStats returns cardinality stats for all measurements.
+func (t *TemporaryEngine) MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats, error) {
+	return t.engine.MeasurementCardinalityStats()
+}
+
+// BucketWindowStats returns the stats of the data of the bucket by window of time.
+func (t *TemporaryEngine) BucketWindowStats(ctx context.Context, orgID, bucketID influxdb.ID, window time.Duration) ([]tsm1.WindowStats, error) {
+	return t.engine.BucketWindowStats(ctx, orgID, bucketID, window)
+}
+
+// BucketCompactionPlan returns the compactions that would run next on the TSM files holding the bucket.
+func (t *TemporaryEngine) BucketCompactionPlan(ctx context.Context, orgID, bucketID influxdb.ID) ([]tsm1.CompactionPlanGroup, error) {
+	return t.engine.BucketCompactionPlan(ctx, orgID, bucketID)
+}
+
+// ScheduleBucketFullCompaction schedules a full compaction of the TSM files holding the bucket.
+func (t *TemporaryEngine) ScheduleBucketFullCompaction(ctx context.Context, orgID, bucketID influxdb.ID) error {
+	return t.engine.ScheduleBucketFullCompaction(ctx, orgID, bucketID)
+}
+
+// OptimizeIndex compacts the index and the series file.
+func (t *TemporaryEngine) OptimizeIndex(ctx context.Context) error {
+	return t.engine.OptimizeIndex(ctx)
+}
+
+// BucketBlockStats returns the stats of the TSM blocks of the bucket by measurement.
+func (t *TemporaryEngine) BucketBlockStats(ctx context.Context, orgID, bucketID influxdb.ID) (map[string]*tsm1.BlockStats, error) {
+	return t.engine.BucketBlockStats(ctx, orgID, bucketID)
+}
+
+// SeriesFileStats returns the stats of the partitions of the series file.
+func (t *TemporaryEngine) SeriesFileStats(ctx context.Context) ([]tsdb.SeriesPartitionStats, error) {
+	return t.engine.SeriesFileStats(ctx)
+}
+
+// CompactSeriesFile compacts the indexes of the partitions of the series file.
+func (t *TemporaryEngine) CompactSeriesFile(ctx context.Context) error {
+	return t.engine.CompactSeriesFile(ctx)
+}
+
+// VerifySeriesFile verifies the partitions of the series file.
+func (t *TemporaryEngine) VerifySeriesFile(ctx context.Context, repair bool) ([]tsdb.SeriesPartitionVerification, error) {
+	return t.engine.VerifySeriesFile(ctx, repair)
+}
+
 // WithLogger sets the logger on the engine. It must be called before Open.
 func (t *TemporaryEngine) WithLogger(log *zap.Logger) {
 	t.logger = log.With(zap.String("service", "temporary_engine"))
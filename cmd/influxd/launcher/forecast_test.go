@@ -0,0 +1,67 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_Forecast(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// Disk usage growing by 10 per hour, with some noise.
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	var points strings.Builder
+	for i := 0; i < 48; i++ {
+		fmt.Fprintf(&points, "disk,host=a used=%d %d\n", 1000+10*i+i*7%5, start.Add(time.Duration(i)*time.Hour).UnixNano())
+	}
+	l.WritePointsOrFail(t, points.String())
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":  fmt.Sprintf(`from(bucket: %q) |> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-03T00:00:00Z) |> filter(fn: (r) => r._measurement == "disk")`, l.Bucket.Name),
+		"method": "arima",
+		"points": 24,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/forecast?orgID=%s", l.Org.ID), string(body))
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("forecast returned %d: %s", resp.StatusCode, b)
+	}
+
+	var got struct {
+		Series []influxdb.ForecastSeries `json:"series"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Series) != 1 || got.Series[0].Tags["host"] != "a" || len(got.Series[0].Forecast) != 24 {
+		t.Fatalf("expected 24 points of host a, got %s", b)
+	}
+	p := got.Series[0].Forecast[23]
+	if want := start.Add(71 * time.Hour); !p.Time.Equal(want) {
+		t.Errorf("expected the last point at %v, got %v", want, p.Time)
+	}
+	if p.Value < 1690 || p.Value > 1730 || p.Lower >= p.Value || p.Upper <= p.Value {
+		t.Errorf("expected the last point to follow the trend around 1710, got %+v", p)
+	}
+}
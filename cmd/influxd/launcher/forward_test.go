@@ -0,0 +1,63 @@
+package launcher_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_StoreAndForward(t *testing.T) {
+	cloud := launcher.RunTestLauncherOrFail(t, ctx)
+	cloud.SetupOrFail(t)
+	defer cloud.ShutdownOrFail(t, ctx)
+
+	edge := launcher.NewTestLauncher()
+	if err := edge.Run(ctx,
+		"--store-and-forward",
+		"--store-and-forward-path", filepath.Join(edge.Path, "forward"),
+		"--store-and-forward-interval", "100ms",
+	); err != nil {
+		t.Fatal(err)
+	}
+	edge.SetupOrFail(t)
+	defer edge.ShutdownOrFail(t, ctx)
+
+	if err := edge.SecretService().PutSecret(ctx, edge.Org.ID, "cloud-token", cloud.Auth.Token); err != nil {
+		t.Fatal(err)
+	}
+	if err := edge.KeyValueService().CreateRemoteConnection(ctx, &influxdb.RemoteConnection{
+		OrganizationID: edge.Org.ID,
+		Name:           "cloud",
+		URL:            cloud.URL(),
+		RemoteOrgID:    cloud.Org.ID,
+		TokenSecretKey: "cloud-token",
+		Forward:        true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the forwarder to pick up the new connection.
+	time.Sleep(500 * time.Millisecond)
+	edge.WritePointsOrFail(t, `cpu,host=a usage=1 1575158400000000000
+cpu,host=a idle=2 1575158400000000000
+cpu,host=b usage=3 1575158401000000000`)
+
+	q := `from(bucket: "` + cloud.Bucket.Name + `")
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-02T00:00:00Z)
+	|> keep(columns: ["_time", "_field", "_value", "host"])`
+	want := edge.FluxQueryOrFail(t, edge.Org, edge.Auth.Token, q)
+	if strings.Count(want, "_result") != 3 {
+		t.Fatalf("expected the three fields written to the edge, got:\n%s", want)
+	}
+	var got string
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline) && got != want; time.Sleep(100 * time.Millisecond) {
+		got = cloud.FluxQueryOrFail(t, cloud.Org, cloud.Auth.Token, q)
+	}
+	if got != want {
+		t.Fatalf("unexpected forwarded data:\n%s\nwant:\n%s", got, want)
+	}
+}
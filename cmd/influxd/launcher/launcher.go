@@ -12,6 +12,8 @@ import (
 	_ "net/http/pprof" // needed to add pprof to our binary.
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,24 +21,37 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/bucketmigration"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/cmd/influxd/inspect"
+	"github.com/influxdata/influxdb/discovery"
+	"github.com/influxdata/influxdb/forecast"
+	"github.com/influxdata/influxdb/forward"
 	"github.com/influxdata/influxdb/gather"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/ingest"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/influxdata/influxdb/kit/cli"
+	"github.com/influxdata/influxdb/kit/i18n"
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/kit/signals"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
 	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/materializedview"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/notification/history"
+	"github.com/influxdata/influxdb/notification/status"
 	"github.com/influxdata/influxdb/pkger"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/remote"
+	"github.com/influxdata/influxdb/render"
+	"github.com/influxdata/influxdb/report"
+	"github.com/influxdata/influxdb/sample"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -47,17 +62,24 @@ import (
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
 	"github.com/influxdata/influxdb/task/backend/middleware"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	tasklint "github.com/influxdata/influxdb/task/lint"
 	"github.com/influxdata/influxdb/telemetry"
-	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
-	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/toml"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxdb/usage"
 	"github.com/influxdata/influxdb/vault"
 	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	jaegerconfig "github.com/uber/jaeger-client-go/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -66,10 +88,24 @@ const (
 	// MemoryStore stores all REST resources in memory (useful for testing).
 	MemoryStore = "memory"
 
+	// PasswordHashBcrypt hashes the passwords of users with bcrypt.
+	PasswordHashBcrypt = "bcrypt"
+	// PasswordHashArgon2id hashes the passwords of users with argon2id.
+	PasswordHashArgon2id = "argon2id"
+
 	// LogTracing enables tracing via zap logs
 	LogTracing = "log"
 	// JaegerTracing enables tracing via the Jaeger client library
 	JaegerTracing = "jaeger"
+	// OTLPTracing enables tracing via an OpenTelemetry OTLP/HTTP endpoint
+	OTLPTracing = "otlp"
+
+	// defaultShutdownDrainTimeout is how long the requests and queries in
+	// flight at shutdown may take to finish by default.
+	defaultShutdownDrainTimeout = 30 * time.Second
+	// shutdownCloseTimeout is how long the services may take to close at
+	// shutdown, after draining.
+	shutdownCloseTimeout = 10 * time.Second
 )
 
 // NewCommand creates the command to run influxdb.
@@ -91,6 +127,12 @@ func NewCommand() *cobra.Command {
 			}
 
 			var wg sync.WaitGroup
+			wg.Add(1)
+			go func(ctx context.Context) {
+				defer wg.Done()
+				l.reloadOnHangup(ctx)
+			}(ctx)
+
 			if !l.ReportingDisabled() {
 				reporter := telemetry.NewReporter(l.Registry())
 				reporter.Interval = 8 * time.Hour
@@ -104,8 +146,9 @@ func NewCommand() *cobra.Command {
 
 			<-ctx.Done()
 
-			// Attempt clean shutdown.
-			ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			// Attempt clean shutdown. The signal context is done, so the
+			// shutdown has a context of its own.
+			ctx, cancel := context.WithTimeout(context.Background(), l.ShutdownTimeout())
 			defer cancel()
 			l.Shutdown(ctx)
 			wg.Wait()
@@ -136,13 +179,25 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			DestP:   &l.tracingType,
 			Flag:    "tracing-type",
 			Default: "",
-			Desc:    fmt.Sprintf("supported tracing types are %s, %s", LogTracing, JaegerTracing),
+			Desc:    fmt.Sprintf("supported tracing types are %s, %s, %s; the %s exporter is configured with the standard JAEGER_* environment variables", LogTracing, JaegerTracing, OTLPTracing, JaegerTracing),
+		},
+		{
+			DestP:   &l.tracingOTLPEndpoint,
+			Flag:    "tracing-otlp-endpoint",
+			Default: tracing.DefaultOTLPEndpoint,
+			Desc:    fmt.Sprintf("URL of the OTLP/HTTP traces endpoint the spans are exported to with tracing type %s", OTLPTracing),
 		},
 		{
 			DestP:   &l.httpBindAddress,
 			Flag:    "http-bind-address",
 			Default: ":9999",
-			Desc:    "bind address for the REST HTTP API",
+			Desc:    "bind address for the REST HTTP API; ignored when sockets are passed by systemd socket activation",
+		},
+		{
+			DestP:   &l.httpUnixSocket,
+			Flag:    "http-unix-socket",
+			Default: "",
+			Desc:    "path of a Unix domain socket to also serve the REST HTTP API on, without TLS",
 		},
 		{
 			DestP:   &l.boltPath,
@@ -155,6 +210,26 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Flag:  "assets-path",
 			Desc:  "override default assets by serving from a specific directory (developer mode)",
 		},
+		{
+			DestP: &l.uiBranding.ProductName,
+			Flag:  "ui-product-name",
+			Desc:  "product name replacing the title of the UI",
+		},
+		{
+			DestP: &l.uiBranding.LogoURL,
+			Flag:  "ui-logo-url",
+			Desc:  "http, https or relative url of a logo shown by the UI and its banner",
+		},
+		{
+			DestP: &l.uiBranding.BannerMessage,
+			Flag:  "ui-banner-message",
+			Desc:  "message shown in a banner at the top of every page of the UI, e.g. \"Production - be careful\"",
+		},
+		{
+			DestP: &l.uiBranding.BannerColor,
+			Flag:  "ui-banner-color",
+			Desc:  "background color of the banner of the UI, as #rrggbb",
+		},
 		{
 			DestP:   &l.storeType,
 			Flag:    "store",
@@ -197,6 +272,82 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.sessionFingerprintBinding,
+			Flag:    "session-fingerprint-binding",
+			Default: false,
+			Desc:    "bind sessions to the user agent and network of the clients signing in; sessions used from other clients are rejected and reported to their users",
+		},
+		{
+			DestP:   &l.sessionFingerprintIPv4Prefix,
+			Flag:    "session-fingerprint-ipv4-prefix",
+			Default: http.DefaultSessionFingerprintIPv4Prefix,
+			Desc:    "length of the prefix of the networks of IPv4 clients sessions are bound to",
+		},
+		{
+			DestP:   &l.sessionFingerprintIPv6Prefix,
+			Flag:    "session-fingerprint-ipv6-prefix",
+			Default: http.DefaultSessionFingerprintIPv6Prefix,
+			Desc:    "length of the prefix of the networks of IPv6 clients sessions are bound to",
+		},
+		{
+			DestP:   &l.passwordHash,
+			Flag:    "password-hash",
+			Default: PasswordHashBcrypt,
+			Desc:    "algorithm hashing the passwords of users (bcrypt or argon2id); passwords hashed otherwise are hashed again when their users sign in",
+		},
+		{
+			DestP:   &l.passwordBcryptCost,
+			Flag:    "password-bcrypt-cost",
+			Default: kv.DefaultCost,
+			Desc:    "cost of the bcrypt password hashes",
+		},
+		{
+			DestP:   &l.passwordArgon2Memory,
+			Flag:    "password-argon2-memory",
+			Default: kv.DefaultArgon2Memory,
+			Desc:    "memory in KiB used to compute argon2id password hashes",
+		},
+		{
+			DestP:   &l.passwordArgon2Iterations,
+			Flag:    "password-argon2-iterations",
+			Default: kv.DefaultArgon2Iterations,
+			Desc:    "number of passes over the memory of argon2id password hashes",
+		},
+		{
+			DestP:   &l.passwordArgon2Parallelism,
+			Flag:    "password-argon2-parallelism",
+			Default: kv.DefaultArgon2Parallelism,
+			Desc:    "number of threads computing argon2id password hashes",
+		},
+		{
+			DestP:   &l.authzDebugHeader,
+			Flag:    "authz-debug-header",
+			Default: false,
+			Desc:    "log the authorization decisions of requests with the " + http.AuthzDebugHeader + " header",
+		},
+		{
+			DestP: &l.authzDebugAuthorizations,
+			Flag:  "authz-debug-authorizations",
+			Desc:  "IDs of authorizations or sessions whose authorization decisions are logged",
+		},
+		{
+			DestP:   &l.authorizationUsageInterval,
+			Flag:    "authorization-usage-interval",
+			Default: http.DefaultAuthorizationUsageInterval,
+			Desc:    "interval at which the last use of authorizations is recorded; 0 disables recording the last use of authorizations",
+		},
+		{
+			DestP:   &l.measurementLastWriteInterval,
+			Flag:    "measurement-last-write-interval",
+			Default: storage.DefaultMeasurementLastWriteInterval,
+			Desc:    "interval at which the last writes to the measurements of buckets are recorded; 0 disables recording the last writes to measurements",
+		},
+		{
+			DestP: &l.trashRetention,
+			Flag:  "trash-retention",
+			Desc:  "how long deleted dashboards and tasks can be restored from the trash; 0 deletes them immediately",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -259,15 +410,410 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: "",
 			Desc:    "TLS key for HTTPs",
 		},
+		{
+			DestP:   &l.httpTLSCertCheckInterval,
+			Flag:    "tls-cert-check-interval",
+			Default: defaultTLSCertCheckInterval,
+			Desc:    "how often the TLS certificate and key files are checked for changes, which are loaded without a restart; 0 disables the checks",
+		},
+		{
+			DestP: &l.httpACME.domains,
+			Flag:  "tls-acme-domains",
+			Desc:  "domains to obtain the TLS certificate for from an ACME certificate authority, accepting its terms of service; cannot be used with tls-cert and tls-key",
+		},
+		{
+			DestP: &l.httpACME.email,
+			Flag:  "tls-acme-email",
+			Desc:  "contact email address of the ACME account",
+		},
+		{
+			DestP:   &l.httpACME.cachePath,
+			Flag:    "tls-acme-cache-path",
+			Default: filepath.Join(dir, "acme"),
+			Desc:    "path to the directory storing the ACME account key and certificates",
+		},
+		{
+			DestP:   &l.httpACME.directoryURL,
+			Flag:    "tls-acme-directory-url",
+			Default: acme.LetsEncryptURL,
+			Desc:    "directory URL of the ACME certificate authority",
+		},
+		{
+			DestP: &l.httpACME.httpBindAddress,
+			Flag:  "tls-acme-http-bind-address",
+			Desc:  "bind address of the server answering ACME HTTP-01 challenges, e.g. :80; without it, the certificate authority must reach the API on port 443",
+		},
+		{
+			DestP:   &l.httpCompression.Disabled,
+			Flag:    "http-compression-disabled",
+			Default: false,
+			Desc:    "disable brotli and gzip compression of HTTP API responses",
+		},
+		{
+			DestP:   &l.httpCompression.MinSize,
+			Flag:    "http-compression-min-size",
+			Default: http.DefaultCompressionMinSize,
+			Desc:    "minimum response size in bytes before HTTP API responses are compressed",
+		},
+		{
+			DestP:   &l.httpCompression.ContentTypes,
+			Flag:    "http-compression-content-types",
+			Default: http.DefaultCompressionContentTypes,
+			Desc:    "response content types eligible for HTTP API compression",
+		},
+		{
+			DestP:   &l.httpBodyLimit.MaxBodyBytes,
+			Flag:    "http-max-body-bytes",
+			Default: http.DefaultMaxBodyBytes,
+			Desc:    "maximum size in bytes of HTTP API request bodies of routes without a limit of their own; 0 means unlimited",
+		},
+		{
+			DestP:   &l.httpBodyLimit.Routes,
+			Flag:    "http-max-body-bytes-routes",
+			Default: http.DefaultMaxBodyBytesRoutes,
+			Desc:    "maximum sizes in bytes of HTTP API request bodies per route prefix, as prefix=bytes; prefixes match whole path segments, the longest matching prefix applies and 0 means unlimited",
+		},
+		{
+			DestP:   &l.httpServerConfig.MaxConnections,
+			Flag:    "http-max-connections",
+			Default: 0,
+			Desc:    "maximum number of simultaneous HTTP connections accepted on each listener; 0 means unlimited",
+		},
+		{
+			DestP:   &l.httpServerConfig.ReadHeaderTimeout,
+			Flag:    "http-read-header-timeout",
+			Default: http.DefaultReadHeaderTimeout,
+			Desc:    "how long a client may take to send the headers of a request; 0 means unlimited",
+		},
+		{
+			DestP: &l.httpServerConfig.ReadTimeout,
+			Flag:  "http-read-timeout",
+			Desc:  "how long a client may take to send a whole request, including its body; 0 means unlimited",
+		},
+		{
+			DestP: &l.httpServerConfig.WriteTimeout,
+			Flag:  "http-write-timeout",
+			Desc:  "how long the server may take to write a response, including streamed query results; 0 means unlimited",
+		},
+		{
+			DestP:   &l.httpServerConfig.IdleTimeout,
+			Flag:    "http-idle-timeout",
+			Default: http.DefaultIdleTimeout,
+			Desc:    "how long an idle keep-alive connection is kept open; 0 means the read timeout applies",
+		},
+		{
+			DestP:   &l.httpServerConfig.KeepAlivesDisabled,
+			Flag:    "http-keep-alives-disabled",
+			Default: false,
+			Desc:    "close HTTP connections after every request",
+		},
+		{
+			DestP:   &l.httpServerConfig.TCPKeepAlive,
+			Flag:    "http-tcp-keep-alive",
+			Default: http.DefaultTCPKeepAlive,
+			Desc:    "period of the TCP keep-alive probes of HTTP connections; 0 disables the probes",
+		},
+		{
+			DestP:   &l.httpServerConfig.MaxHeaderBytes,
+			Flag:    "http-max-header-bytes",
+			Default: nethttp.DefaultMaxHeaderBytes,
+			Desc:    "maximum size in bytes of the headers of a request",
+		},
+		{
+			DestP:   &l.httpServerConfig.H2C,
+			Flag:    "http-h2c",
+			Default: false,
+			Desc:    "serve HTTP/2 without TLS (h2c) to clients that ask for it; HTTP/2 is always served over TLS",
+		},
+		{
+			DestP:   &l.httpServerConfig.MaxConcurrentStreams,
+			Flag:    "http-max-concurrent-streams",
+			Default: http.DefaultMaxConcurrentStreams,
+			Desc:    "number of concurrent HTTP/2 streams a client may open on a connection",
+		},
+		{
+			DestP:   &l.shutdownDrainTimeout,
+			Flag:    "shutdown-drain-timeout",
+			Default: defaultShutdownDrainTimeout,
+			Desc:    "how long in-flight HTTP requests, queries and task runs may take to finish at shutdown before they are canceled",
+		},
+		{
+			DestP:   &l.httpIdempotencyWindow,
+			Flag:    "http-idempotency-window",
+			Default: http.DefaultIdempotencyWindow,
+			Desc:    "how long responses to POST requests with an Idempotency-Key header are replayed for retries; 0 disables idempotency keys",
+		},
+		{
+			DestP:   &l.httpRateLimit.Read.RequestsPerSecond,
+			Flag:    "http-rate-limit-read",
+			Default: 0,
+			Desc:    "requests per second of each organization that read data and resources, including queries; 0 means unlimited unless the organization sets a limit",
+		},
+		{
+			DestP:   &l.httpRateLimit.Write.RequestsPerSecond,
+			Flag:    "http-rate-limit-write",
+			Default: 0,
+			Desc:    "requests per second of each organization that write or delete points; 0 means unlimited unless the organization sets a limit",
+		},
+		{
+			DestP:   &l.httpRateLimit.Admin.RequestsPerSecond,
+			Flag:    "http-rate-limit-admin",
+			Default: 0,
+			Desc:    "requests per second of each organization that change resources; 0 means unlimited unless the organization sets a limit",
+		},
+		{
+			DestP: &l.httpMessageCatalogsPath,
+			Flag:  "http-message-catalogs-path",
+			Desc:  "directory of message catalogs translating the messages of error responses into the languages of the Accept-Language header, one <language>.json file per language",
+		},
+		{
+			DestP:   &l.httpAPIValidation,
+			Flag:    "http-api-validation",
+			Default: false,
+			Desc:    "validate HTTP API requests and responses against the swagger document; nonconforming requests are rejected and nonconforming responses logged. Intended for debugging clients",
+		},
+		{
+			DestP:   &l.httpCORS.AllowedOrigins,
+			Flag:    "http-cors-allowed-origins",
+			Default: http.DefaultCORSAllowedOrigins,
+			Desc:    "origins allowed to call the HTTP API from a browser; a single * in an origin matches subdomains, * alone matches every origin",
+		},
+		{
+			DestP:   &l.httpCORS.AllowedHeaders,
+			Flag:    "http-cors-allowed-headers",
+			Default: http.DefaultCORSAllowedHeaders,
+			Desc:    "request headers allowed in cross-origin requests to the HTTP API; * allows every header",
+		},
+		{
+			DestP: &l.httpCORS.MaxAge,
+			Flag:  "http-cors-max-age",
+			Desc:  "how long browsers may cache the response to a cross-origin preflight request; 0 leaves it to the browser",
+		},
+		{
+			DestP:   &l.httpCORS.AllowCredentials,
+			Flag:    "http-cors-allow-credentials",
+			Default: false,
+			Desc:    "allow browsers to send cookies in cross-origin requests to the HTTP API; requires an explicit list of allowed origins",
+		},
+		{
+			DestP:   &l.httpAccessLog.SampleEvery,
+			Flag:    "http-access-log-sample-every",
+			Default: 1,
+			Desc:    "log only the first and then every nth request per route; errors and slow requests are always logged",
+		},
+		{
+			DestP:   &l.httpAccessLog.SlowThreshold,
+			Flag:    "http-access-log-slow-threshold",
+			Default: 10 * time.Second,
+			Desc:    "always log requests taking longer than this duration; 0 disables the threshold",
+		},
+		{
+			DestP: &l.httpAccessLogOrgID,
+			Flag:  "http-access-log-org-id",
+			Desc:  "organization ID of the bucket that access log entries are exported to",
+		},
+		{
+			DestP: &l.httpAccessLogBucketID,
+			Flag:  "http-access-log-bucket-id",
+			Desc:  "bucket ID that access log entries are exported to as line protocol; empty disables export",
+		},
+		{
+			DestP:   &l.httpMetricsOrgLabel,
+			Flag:    "http-metrics-org-label",
+			Default: false,
+			Desc:    "label per-route HTTP metrics with the organization of the request",
+		},
+		{
+			DestP: &l.writeMaxPointAge,
+			Flag:  "write-max-point-age",
+			Desc:  "reject written points with timestamps further in the past than this duration; 0 accepts any timestamp",
+		},
+		{
+			DestP: &l.writeMaxPointFuture,
+			Flag:  "write-max-point-future",
+			Desc:  "reject written points with timestamps further in the future than this duration; 0 accepts any timestamp",
+		},
+		{
+			DestP: &l.selfMonitoringOrgID,
+			Flag:  "self-monitoring-org-id",
+			Desc:  "organization ID whose _monitoring bucket receives the server's own metrics; empty disables self-monitoring",
+		},
+		{
+			DestP:   &l.selfMonitoringInterval,
+			Flag:    "self-monitoring-interval",
+			Default: 10 * time.Second,
+			Desc:    "interval at which the server's own metrics are written when self-monitoring is enabled",
+		},
 		{
 			DestP:   &l.EnableNewScheduler,
 			Flag:    "feature-enable-new-scheduler",
 			Default: false,
 			Desc:    "feature flag that enables using the new treescheduler",
 		},
+		{
+			DestP: &l.taskWorkerPools,
+			Flag:  "task-worker-pools",
+			Desc:  "named worker pools of the task executor as name=workers pairs, e.g. critical=10,batch=2; tasks select a pool with the pool task option",
+		},
+		{
+			DestP:   &l.taskMissedRunTolerance,
+			Flag:    "task-missed-run-tolerance",
+			Default: time.Minute,
+			Desc:    "time a task run may be overdue, e.g. after downtime or a clock adjustment, before the missedRunPolicy task option applies to it",
+		},
+		{
+			DestP:   &l.taskBackfillConcurrency,
+			Flag:    "task-backfill-concurrency",
+			Default: 4,
+			Desc:    "number of runs of a task backfill that may be in flight at once",
+		},
+		{
+			DestP:   &l.StorageConfig.PartitionByOrganization,
+			Flag:    "storage-partition-by-organization",
+			Default: false,
+			Desc:    "store and compact the data of every organization separately, and label the storage metrics with the organization; cannot be changed once data has been written",
+		},
+		{
+			DestP:   &l.StorageConfig.TSDB.SeriesFileCompactThreshold,
+			Flag:    "storage-series-file-compact-threshold",
+			Default: tsdb.DefaultSeriesPartitionCompactThreshold,
+			Desc:    "number of series created in a partition of the series file that triggers a compaction of its index; 0 disables the trigger",
+		},
+		{
+			DestP:   &l.StorageConfig.TSDB.SeriesFileTombstoneCompactThreshold,
+			Flag:    "storage-series-file-tombstone-compact-threshold",
+			Default: tsdb.DefaultSeriesPartitionTombstoneCompactThreshold,
+			Desc:    "number of series deleted in a partition of the series file that triggers a compaction of its index; 0 disables the trigger",
+		},
+		{
+			DestP:   &l.StorageConfig.TSDB.SeriesFileMaxConcurrentCompactions,
+			Flag:    "storage-series-file-max-concurrent-compactions",
+			Default: 0,
+			Desc:    "maximum number of partitions of the series file compacting at once; 0 means no limit",
+		},
+		{
+			DestP:   &l.tsiMaxIndexLogFileSize,
+			Flag:    "storage-tsi-max-index-log-file-size",
+			Default: tsi1.DefaultMaxIndexLogFileSize,
+			Desc:    "size in bytes at which a log file of an index partition is compacted into an index file; larger sizes trade heap usage for fewer compactions",
+		},
+		{
+			DestP:   &l.StorageConfig.Engine.MADVStrategy,
+			Flag:    "storage-tsm-madvise-strategy",
+			Default: "",
+			Desc:    "advice given to the kernel about the accesses to the mmap'd TSM files: normal, willneed, random or sequential; random stops the kernel from reading ahead",
+		},
+		{
+			DestP:   &l.tsmBlockCacheMaxSize,
+			Flag:    "storage-tsm-block-cache-max-size",
+			Default: 0,
+			Desc:    "size in bytes of the cache of the most recently read blocks of TSM files, which bounds the memory used to cache them instead of depending on the page cache; 0 disables the block cache",
+		},
+		{
+			DestP:   &l.StorageConfig.Index.MaxConcurrentCompactions,
+			Flag:    "storage-tsi-max-concurrent-compactions",
+			Default: 0,
+			Desc:    "maximum number of index compactions running at once across all index partitions; 0 means no limit",
+		},
+		{
+			DestP:   &l.plannerStatisticsInterval,
+			Flag:    "storage-planner-statistics-interval",
+			Default: time.Hour,
+			Desc:    "interval at which the statistics of the measurements of buckets used to plan queries are collected; 0 disables the collection",
+		},
+		{
+			DestP:   &l.schemaCacheTTL,
+			Flag:    "storage-schema-cache-ttl",
+			Default: storage.DefaultSchemaCacheTTL,
+			Desc:    "how long the measurements, fields and tag values of buckets served by the schema API are cached; 0 disables the cache",
+		},
+		{
+			DestP:   &l.materializedViewsInterval,
+			Flag:    "materialized-views-interval",
+			Default: time.Minute,
+			Desc:    "interval at which the windows of materialized views are materialized; 0 disables the maintenance of materialized views",
+		},
+		{
+			DestP:   &l.reportsInterval,
+			Flag:    "reports-interval",
+			Default: time.Minute,
+			Desc:    "interval at which the due scheduled reports are delivered; 0 disables the delivery of scheduled reports",
+		},
+		{
+			DestP: &l.reportSMTPAddr,
+			Flag:  "report-smtp-addr",
+			Desc:  "host:port address of the SMTP server reports are emailed through; reports are not emailed if empty",
+		},
+		{
+			DestP:   &l.reportSMTPFrom,
+			Flag:    "report-smtp-from",
+			Default: "InfluxDB <influxdb@localhost>",
+			Desc:    "address reports are emailed from",
+		},
+		{
+			DestP: &l.reportSMTPUsername,
+			Flag:  "report-smtp-username",
+			Desc:  "username authenticating to the SMTP server reports are emailed through",
+		},
+		{
+			DestP: &l.reportSMTPPassword,
+			Flag:  "report-smtp-password",
+			Desc:  "password authenticating to the SMTP server reports are emailed through",
+		},
+		{
+			DestP: &l.monitoringHistoryRetention,
+			Flag:  "monitoring-history-retention",
+			Desc:  "duration for which statuses, notifications and alert acknowledgements are kept in the history of every organization; 0 keeps them for the retention of the monitoring bucket",
+		},
+		{
+			DestP: &l.discoveryPeers,
+			Flag:  "discovery-peers",
+			Desc:  "URLs of the peer influxd instances registered statically with the discovery of the cluster topology",
+		},
+		{
+			DestP: &l.discoveryDNSName,
+			Flag:  "discovery-dns-name",
+			Desc:  "DNS name under which the peer influxd instances are registered, either a host:port name resolved to its addresses or a name resolved to its SRV records",
+		},
+		{
+			DestP: &l.discoveryAdvertiseURL,
+			Flag:  "discovery-advertise-url",
+			Desc:  "URL advertised to the peer influxd instances; defaults to the host name and the port of the HTTP listener",
+		},
+		{
+			DestP:   &l.discoveryInterval,
+			Flag:    "discovery-interval",
+			Default: 30 * time.Second,
+			Desc:    "interval at which the peer influxd instances are probed",
+		},
+		{
+			DestP:   &l.storeAndForward,
+			Flag:    "store-and-forward",
+			Default: false,
+			Desc:    "forward the writes of organizations to their remote connections that forward, queueing them on disk while the remotes are unreachable",
+		},
+		{
+			DestP:   &l.storeAndForwardPath,
+			Flag:    "store-and-forward-path",
+			Default: filepath.Join(dir, "forward"),
+			Desc:    "path to the queues of the writes forwarded to remote connections",
+		},
+		{
+			DestP:   &l.storeAndForwardMaxSize,
+			Flag:    "store-and-forward-max-size",
+			Default: 1024 * 1024 * 1024,
+			Desc:    "size in bytes of the queue of a remote connection beyond which writes are no longer queued for the remote",
+		},
+		{
+			DestP:   &l.storeAndForwardInterval,
+			Flag:    "store-and-forward-interval",
+			Default: 10 * time.Second,
+			Desc:    "interval at which the forwarding of the queued writes to unreachable remotes is retried",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
+	l.opts, l.flags = opts, cmd.Flags()
 	cmd.AddCommand(inspect.NewCommand())
 
 }
@@ -278,17 +824,50 @@ type Launcher struct {
 	cancel  func()
 	running bool
 
+	// The options of the command line, reapplied from the config file when
+	// the configuration is reloaded.
+	opts       []cli.Opt
+	flags      *pflag.FlagSet
+	configFile *configFile
+
 	storeType            string
 	assetsPath           string
+	uiBranding           http.Branding
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	trashRetention       time.Duration
+
+	sessionFingerprintBinding    bool
+	sessionFingerprintIPv4Prefix int
+	sessionFingerprintIPv6Prefix int
+
+	passwordHash              string
+	passwordBcryptCost        int
+	passwordArgon2Memory      int
+	passwordArgon2Iterations  int
+	passwordArgon2Parallelism int
+
+	authzDebugHeader         bool
+	authzDebugAuthorizations []string
+
+	authorizationUsageInterval time.Duration
+	authorizationUsageTracker  *http.AuthorizationUsageTracker
+
+	usageTracker *usage.Tracker
 
-	logLevel          string
-	tracingType       string
-	reportingDisabled bool
+	measurementLastWriteInterval time.Duration
+	measurementWriteTracker      *storage.MeasurementWriteTracker
+
+	materializedViewMaintainer *materializedview.Maintainer
+
+	logLevel            string
+	tracingType         string
+	tracingOTLPEndpoint string
+	reportingDisabled   bool
 
 	httpBindAddress string
+	httpUnixSocket  string
 	boltPath        string
 	enginePath      string
 	secretStore     string
@@ -298,20 +877,76 @@ type Launcher struct {
 	engine        Engine
 	StorageConfig storage.Config
 
-	queryController *control.Controller
+	tsiMaxIndexLogFileSize     int
+	tsmBlockCacheMaxSize       int
+	plannerStatisticsInterval  time.Duration
+	schemaCacheTTL             time.Duration
+	materializedViewsInterval  time.Duration
+	monitoringHistoryRetention time.Duration
+
+	reportsInterval    time.Duration
+	reportSMTPAddr     string
+	reportSMTPFrom     string
+	reportSMTPUsername string
+	reportSMTPPassword string
+
+	discoveryPeers        []string
+	discoveryDNSName      string
+	discoveryAdvertiseURL string
+	discoveryInterval     time.Duration
+	discoveryService      *discovery.Service
+
+	storeAndForward         bool
+	storeAndForwardPath     string
+	storeAndForwardMaxSize  int
+	storeAndForwardInterval time.Duration
+	forwarder               *forward.Forwarder
 
-	httpPort    int
-	httpServer  *nethttp.Server
-	httpTLSCert string
-	httpTLSKey  string
+	queryController *control.Controller
+	runtimeConfig   *runtimeConfigService
+
+	httpPort         int
+	httpServer       *nethttp.Server
+	httpTLSCert      string
+	httpTLSKey       string
+	httpCert         *certificate
+	httpACME         acmeConfig
+	acmeServer       *nethttp.Server
+	httpCompression  http.CompressionConfig
+	httpCORS         http.CORSConfig
+	httpBodyLimit    http.BodyLimitConfig
+	httpServerConfig http.ServerConfig
+
+	httpTLSCertCheckInterval time.Duration
+
+	httpIdempotencyWindow   time.Duration
+	httpRateLimit           http.RateLimitConfig
+	shutdownDrainTimeout    time.Duration
+	httpAPIValidation       bool
+	httpMessageCatalogsPath string
+
+	httpAccessLog         http.AccessLogConfig
+	httpAccessLogOrgID    string
+	httpAccessLogBucketID string
+	httpMetricsOrgLabel   bool
+
+	writeMaxPointAge    time.Duration
+	writeMaxPointFuture time.Duration
+	writeLimits         *http.WriteLimits
+
+	selfMonitoringOrgID    string
+	selfMonitoringInterval time.Duration
 
 	natsServer *nats.Server
 	natsPort   int
 
-	EnableNewScheduler bool
-	scheduler          *taskbackend.TickScheduler
-	treeScheduler      *scheduler.TreeScheduler
-	taskControlService taskbackend.TaskControlService
+	EnableNewScheduler      bool
+	taskWorkerPools         []string
+	taskMissedRunTolerance  time.Duration
+	taskBackfillConcurrency int
+	scheduler               *taskbackend.TickScheduler
+	treeScheduler           *scheduler.TreeScheduler
+	taskControlService      taskbackend.TaskControlService
 
 	jaegerTracerCloser io.Closer
 	logger             *zap.Logger
@@ -369,15 +1004,72 @@ func (m *Launcher) Engine() Engine {
 	return m.engine
 }
 
+// ShutdownTimeout returns how long Shutdown may take: the drain timeout
+// followed by the time services take to close.
+func (m *Launcher) ShutdownTimeout() time.Duration {
+	return m.shutdownDrainTimeout + shutdownCloseTimeout
+}
+
 // Shutdown shuts down the HTTP server and waits for all services to clean up.
+// New requests are refused right away, while the requests, queries and task
+// runs in flight are given the drain timeout to finish before they are
+// canceled. The caches of the storage engine are then written to TSM files,
+// so that the WAL does not need to be replayed at the next start.
 func (m *Launcher) Shutdown(ctx context.Context) {
-	m.httpServer.Shutdown(ctx)
+	drainCtx, cancel := context.WithTimeout(ctx, m.shutdownDrainTimeout)
+	defer cancel()
 
+	m.logger.Info("Draining", zap.String("service", "http"), zap.Duration("timeout", m.shutdownDrainTimeout))
+	if err := m.httpServer.Shutdown(drainCtx); err != nil {
+		m.logger.Warn("Closing HTTP connections with requests in flight", zap.Error(err))
+		m.httpServer.Close()
+	}
+	if m.acmeServer != nil {
+		m.acmeServer.Close()
+	}
+
+	// The uses of authorizations and the request usage of the last requests
+	// are recorded once no more requests are served.
+	if m.authorizationUsageTracker != nil {
+		if err := m.authorizationUsageTracker.Flush(ctx); err != nil {
+			m.logger.Warn("Unable to record the usage of authorizations", zap.Error(err))
+		}
+	}
+	if m.usageTracker != nil {
+		if err := m.usageTracker.Flush(ctx); err != nil {
+			m.logger.Warn("Unable to write request usage", zap.Error(err))
+		}
+	}
+
+	// The schedulers wait for the runs in flight, whose queries are drained
+	// and canceled with the other queries.
 	m.logger.Info("Stopping", zap.String("service", "task"))
-	if m.EnableNewScheduler {
-		m.treeScheduler.Stop()
-	} else {
-		m.scheduler.Stop()
+	schedulerStopped := make(chan struct{})
+	go func() {
+		defer close(schedulerStopped)
+		if m.EnableNewScheduler {
+			m.treeScheduler.Stop()
+		} else {
+			m.scheduler.Stop()
+		}
+	}()
+
+	m.logger.Info("Draining", zap.String("service", "query"))
+	if err := m.queryController.Drain(drainCtx); err != nil {
+		m.logger.Warn("Canceling queries in flight", zap.Error(err))
+	}
+	m.logger.Info("Stopping", zap.String("service", "query"))
+	if err := m.queryController.Shutdown(ctx); err != nil && err != context.Canceled {
+		m.logger.Info("Failed closing query service", zap.Error(err))
+	}
+	<-schedulerStopped
+
+	// The last writes to measurements are recorded once no more points are
+	// written by requests and tasks.
+	if m.measurementWriteTracker != nil {
+		if err := m.measurementWriteTracker.Flush(ctx); err != nil {
+			m.logger.Warn("Unable to record the last writes to measurements", zap.Error(err))
+		}
 	}
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
@@ -388,9 +1080,9 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.logger.Info("failed closing bolt", zap.Error(err))
 	}
 
-	m.logger.Info("Stopping", zap.String("service", "query"))
-	if err := m.queryController.Shutdown(ctx); err != nil && err != context.Canceled {
-		m.logger.Info("Failed closing query service", zap.Error(err))
+	m.logger.Info("Flushing", zap.String("service", "storage-engine"))
+	if err := m.engine.WriteSnapshot(ctx, tsm1.CacheStatusShutdown); err != nil && err != tsm1.ErrSnapshotInProgress {
+		m.logger.Error("failed to flush the storage engine cache", zap.Error(err))
 	}
 
 	m.logger.Info("Stopping", zap.String("service", "storage-engine"))
@@ -400,6 +1092,13 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 
 	m.wg.Wait()
 
+	if m.forwarder != nil {
+		m.logger.Info("Stopping", zap.String("service", "store-and-forward"))
+		if err := m.forwarder.Close(); err != nil {
+			m.logger.Error("failed to close store-and-forward queues", zap.Error(err))
+		}
+	}
+
 	if m.jaegerTracerCloser != nil {
 		if err := m.jaegerTracerCloser.Close(); err != nil {
 			m.logger.Warn("failed to closer Jaeger tracer", zap.Error(err))
@@ -428,6 +1127,53 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 	return cmd.Execute()
 }
 
+// passwordHasher returns the hasher of the passwords of users of the
+// configuration.
+func (m *Launcher) passwordHasher() (kv.Crypt, error) {
+	switch m.passwordHash {
+	case PasswordHashBcrypt:
+		if m.passwordBcryptCost < bcrypt.MinCost || m.passwordBcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("password-bcrypt-cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return &kv.Bcrypt{Cost: m.passwordBcryptCost}, nil
+	case PasswordHashArgon2id:
+		if m.passwordArgon2Parallelism < 1 || m.passwordArgon2Parallelism > math.MaxUint8 {
+			return nil, fmt.Errorf("password-argon2-parallelism must be between 1 and %d", math.MaxUint8)
+		}
+		if m.passwordArgon2Iterations < 1 {
+			return nil, fmt.Errorf("password-argon2-iterations must be at least 1")
+		}
+		if m.passwordArgon2Memory < 8*m.passwordArgon2Parallelism || int64(m.passwordArgon2Memory) > math.MaxUint32 {
+			return nil, fmt.Errorf("password-argon2-memory must be at least 8 KiB per thread")
+		}
+		return &kv.Argon2id{
+			Memory:      uint32(m.passwordArgon2Memory),
+			Iterations:  uint32(m.passwordArgon2Iterations),
+			Parallelism: uint8(m.passwordArgon2Parallelism),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown password hash %s; expected %s or %s", m.passwordHash, PasswordHashBcrypt, PasswordHashArgon2id)
+	}
+}
+
+// sessionFingerprinter returns the fingerprinter of the clients sessions are
+// bound to, or nil if sessions are not bound.
+func (m *Launcher) sessionFingerprinter() (*http.SessionFingerprinter, error) {
+	if !m.sessionFingerprintBinding {
+		return nil, nil
+	}
+	if m.sessionFingerprintIPv4Prefix < 0 || m.sessionFingerprintIPv4Prefix > 32 {
+		return nil, fmt.Errorf("session-fingerprint-ipv4-prefix must be between 0 and 32")
+	}
+	if m.sessionFingerprintIPv6Prefix < 0 || m.sessionFingerprintIPv6Prefix > 128 {
+		return nil, fmt.Errorf("session-fingerprint-ipv6-prefix must be between 0 and 128")
+	}
+	return &http.SessionFingerprinter{
+		IPv4Prefix: m.sessionFingerprintIPv4Prefix,
+		IPv6Prefix: m.sessionFingerprintIPv6Prefix,
+	}, nil
+}
+
 func (m *Launcher) run(ctx context.Context) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -435,15 +1181,48 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.running = true
 	ctx, m.cancel = context.WithCancel(ctx)
 
+	if err := m.loadConfigFile(); err != nil {
+		return err
+	}
+	if m.httpACME.enabled() && (m.httpTLSCert != "" || m.httpTLSKey != "") {
+		return fmt.Errorf("tls-acme-domains cannot be used with tls-cert and tls-key")
+	}
+	passwordHasher, err := m.passwordHasher()
+	if err != nil {
+		return err
+	}
+	sessionFingerprinter, err := m.sessionFingerprinter()
+	if err != nil {
+		return err
+	}
+	if err := m.uiBranding.Valid(); err != nil {
+		return err
+	}
+	if err := m.StorageConfig.Engine.Validate(); err != nil {
+		return err
+	}
+	authzDebugIDs, err := parseIDs(m.authzDebugAuthorizations)
+	if err != nil {
+		return fmt.Errorf("authz-debug-authorizations: %v", err)
+	}
+	var messageCatalog *i18n.Catalog
+	if m.httpMessageCatalogsPath != "" {
+		messageCatalog = i18n.NewCatalog()
+		if err := messageCatalog.LoadDir(m.httpMessageCatalogsPath); err != nil {
+			return err
+		}
+	}
+
 	var lvl zapcore.Level
 	if err := lvl.Set(m.logLevel); err != nil {
 		return fmt.Errorf("unknown log level; supported levels are debug, info, and error")
 	}
 
-	// Create top level logger
+	// Create top level logger. The level may be changed at runtime.
+	atomicLevel := zap.NewAtomicLevelAt(lvl)
 	logconf := &influxlogger.Config{
 		Format: "auto",
-		Level:  lvl,
+		Level:  atomicLevel,
 	}
 	m.logger, err = logconf.New(m.Stdout)
 	if err != nil {
@@ -479,6 +1258,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 		opentracing.SetGlobalTracer(tracer)
 		m.jaegerTracerCloser = closer
+
+	case OTLPTracing:
+		m.logger.Info("tracing via OTLP", zap.String("endpoint", m.tracingOTLPEndpoint))
+		tracer := tracing.NewOTLPTracer(m.tracingOTLPEndpoint, "influxd", m.logger.With(zap.String("service", "tracing")))
+		opentracing.SetGlobalTracer(tracer)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			tracer.Run(ctx)
+		}()
 	}
 
 	m.boltClient = bolt.NewClient()
@@ -491,7 +1281,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	serviceConfig := kv.ServiceConfig{
-		SessionLength: time.Duration(m.sessionLength) * time.Minute,
+		SessionLength:  time.Duration(m.sessionLength) * time.Minute,
+		TrashRetention: m.trashRetention,
 	}
 
 	flushers := flushers{}
@@ -516,6 +1307,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	m.kvService.Logger = m.logger.With(zap.String("store", "kv"))
+	m.kvService.Hash = passwordHasher
 	if err := m.kvService.Initialize(ctx); err != nil {
 		m.logger.Error("failed to initialize kv service", zap.Error(err))
 		return err
@@ -534,6 +1326,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		authSvc                 platform.AuthorizationService            = m.kvService
 		userSvc                 platform.UserService                     = m.kvService
 		variableSvc             platform.VariableService                 = m.kvService
+		dbrpSvc                 platform.DBRPMappingServiceV2            = m.kvService
+		orgSettingsSvc          platform.OrganizationSettingsService     = m.kvService
+		userSettingsSvc         platform.UserSettingsService             = m.kvService
+		resourceACLSvc          platform.ResourceACLService              = m.kvService
 		bucketSvc               platform.BucketService                   = m.kvService
 		sourceSvc               platform.SourceService                   = m.kvService
 		sessionSvc              platform.SessionService                  = m.kvService
@@ -577,6 +1373,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	if m.tsiMaxIndexLogFileSize > 0 {
+		m.StorageConfig.Index.MaxIndexLogFileSize = toml.Size(m.tsiMaxIndexLogFileSize)
+	}
+	if m.tsmBlockCacheMaxSize > 0 {
+		m.StorageConfig.Engine.BlockCacheMaxSize = toml.Size(m.tsmBlockCacheMaxSize)
+	}
+
 	if m.testing {
 		// the testing engine will write/read into a temporary directory
 		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
@@ -593,11 +1396,45 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	// The Engine's metrics must be registered after it opens.
 	m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
+	// Status events of checks are published as their statuses are written.
+	checkStatusStream := status.NewStream(bucketSvc, m.logger.With(zap.String("service", "check-status-stream")))
+
 	var (
 		deleteService platform.DeleteService = m.engine
-		pointsWriter  storage.PointsWriter   = m.engine
+		pointsWriter  storage.PointsWriter   = checkStatusStream.PointsWriter(m.engine)
 	)
 
+	remoteConnector := http.NewRemoteConnector(secretSvc)
+	if m.storeAndForward {
+		m.forwarder = forward.NewForwarder(m.storeAndForwardPath, int64(m.storeAndForwardMaxSize), m.kvService, bucketSvc, remoteConnector.Write, m.logger.With(zap.String("service", "store-and-forward")))
+		if err := m.forwarder.Open(ctx); err != nil {
+			m.logger.Error("Failed to open store-and-forward queues", zap.Error(err))
+			return err
+		}
+		pointsWriter = m.forwarder.PointsWriter(pointsWriter)
+	}
+
+	// The writes to measurements are tracked once the points are written,
+	// with the measurements the ingest rules left them with.
+	if m.measurementLastWriteInterval > 0 {
+		m.measurementWriteTracker = storage.NewMeasurementWriteTracker(m.kvService)
+		m.measurementWriteTracker.WithLogger(m.logger)
+		pointsWriter = m.measurementWriteTracker.PointsWriter(pointsWriter)
+	}
+
+	// The windows of materialized views that points are written to after they
+	// were materialized are marked stale, with the measurements the ingest
+	// rules left the points with.
+	var staleWindowTracker *materializedview.StaleWindowTracker
+	if m.materializedViewsInterval > 0 {
+		staleWindowTracker = materializedview.NewStaleWindowTracker(m.kvService)
+		pointsWriter = staleWindowTracker.PointsWriter(pointsWriter)
+	}
+
+	// Ingest rules transform the points before they are forwarded or stored.
+	ingestSvc := ingest.NewService(m.kvService, m.logger.With(zap.String("service", "ingest")))
+	pointsWriter = ingestSvc.PointsWriter(pointsWriter)
+
 	// TODO(cwolff): Figure out a good default per-query memory limit:
 	//   https://github.com/influxdata/influxdb/issues/13642
 	const (
@@ -608,7 +1445,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	deps, err := influxdb.NewDependencies(
 		reads.NewReader(readservice.NewStore(m.engine)),
-		m.engine,
+		pointsWriter,
 		authorizer.NewBucketService(bucketSvc),
 		authorizer.NewOrgService(orgSvc),
 		authorizer.NewSecretService(secretSvc),
@@ -618,13 +1455,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		m.logger.Error("Failed to get query controller dependencies", zap.Error(err))
 		return err
 	}
+	deps.StorageDeps.FromDeps.Statistics = m.kvService
 
 	m.queryController, err = control.New(control.Config{
 		ConcurrencyQuota:         concurrencyQuota,
 		MemoryBytesQuotaPerQuery: int64(memoryBytesQuotaPerQuery),
 		QueueSize:                QueueSize,
 		Logger:                   m.logger.With(zap.String("service", "storage-reads")),
-		ExecutorDependencies:     []flux.Dependency{deps},
+		ExecutorDependencies: []flux.Dependency{deps, remote.Dependencies{
+			RemoteConnections: authorizer.NewRemoteConnectionService(m.kvService),
+			Dial:              remoteConnector.QueryService,
+		}},
 	})
 	if err != nil {
 		m.logger.Error("Failed to create query controller", zap.Error(err))
@@ -633,19 +1474,59 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 
+	writeLimits := &http.WriteLimits{}
+	writeLimits.SetMaxPointAge(m.writeMaxPointAge)
+	writeLimits.SetMaxPointFuture(m.writeMaxPointFuture)
+	m.discoveryService = discovery.NewService(m.discoveryAdvertiseURL, info.Version)
+	m.discoveryService.Peers = m.discoveryPeers
+	m.discoveryService.DNSName = m.discoveryDNSName
+	m.discoveryService.WithLogger(m.logger)
+
+	runtimeConfigSvc := &runtimeConfigService{
+		config: platform.RuntimeConfig{
+			LogLevel:                  m.logLevel,
+			QueryConcurrency:          concurrencyQuota,
+			CompactionThroughput:      int64(m.StorageConfig.Engine.Compaction.Throughput),
+			CompactionThroughputBurst: int64(m.StorageConfig.Engine.Compaction.ThroughputBurst),
+		},
+		store:       m.kvService,
+		level:       atomicLevel,
+		controller:  m.queryController,
+		writeLimits: writeLimits,
+		engine:      m.engine,
+		logger:      m.logger.With(zap.String("service", "runtime-config")),
+	}
+	if err := runtimeConfigSvc.restore(ctx); err != nil {
+		m.logger.Error("Failed to restore runtime config", zap.Error(err))
+		return err
+	}
+	m.runtimeConfig, m.writeLimits = runtimeConfigSvc, writeLimits
+
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
-	var taskSvc platform.TaskService
+	var (
+		taskSvc          platform.TaskService
+		trashSvc         platform.TrashService
+		backfillSvc      platform.TaskBackfillService
+		monitoringRunSvc platform.MonitoringRunService
+	)
 	{
 		// create the task stack:
 		// validation(coordinator(analyticalstore(kv.Service)))
 		combinedTaskService := taskbackend.NewAnalyticalStorage(m.logger.With(zap.String("service", "task-analytical-store")), m.kvService, m.kvService, m.kvService, pointsWriter, query.QueryServiceBridge{AsyncQueryService: m.queryController})
+		monitoringRunSvc = history.NewService(m.logger.With(zap.String("service", "monitoring-run")), m.kvService, m.kvService, combinedTaskService, m.kvService, query.QueryServiceBridge{AsyncQueryService: m.queryController})
 		if m.EnableNewScheduler {
+			pools, err := parseTaskWorkerPools(m.taskWorkerPools)
+			if err != nil {
+				m.logger.Error("Invalid task worker pools", zap.Error(err))
+				return err
+			}
 			executor, executorMetrics := taskexecutor.NewExecutor(
 				m.logger.With(zap.String("service", "task-executor")),
 				query.QueryServiceBridge{AsyncQueryService: m.queryController},
 				authSvc,
 				combinedTaskService,
 				combinedTaskService,
+				taskexecutor.WithWorkerPools(pools),
 			)
 			m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
 			schLogger := m.logger.With(zap.String("service", "task-scheduler"))
@@ -660,6 +1541,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 						zap.Time("scheduledAt", scheduledAt),
 						zap.Error(err))
 				}),
+				scheduler.WithMissedRunTolerance(m.taskMissedRunTolerance),
 			)
 			if err != nil {
 				m.logger.Fatal("could not start task scheduler", zap.Error(err))
@@ -673,6 +1555,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				executor)
 
 			taskSvc = middleware.New(combinedTaskService, taskCoord)
+			trashSvc = middleware.NewTrashService(m.kvService, taskCoord)
+			backfillSvc = taskbackend.NewBackfillService(m.logger.With(zap.String("service", "task-backfill")), taskSvc,
+				taskbackend.WithBackfillConcurrency(m.taskBackfillConcurrency))
 			m.taskControlService = combinedTaskService
 			if err := taskbackend.TaskNotifyCoordinatorOfExisting(
 				ctx,
@@ -705,7 +1590,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			}
 
 			taskSvc = middleware.New(combinedTaskService, coordinator)
+			// the backfill runs in the background, so it enqueues runs without the authorization of the request.
+			backfillSvc = taskbackend.NewBackfillService(m.logger.With(zap.String("service", "task-backfill")), taskSvc,
+				taskbackend.WithBackfillConcurrency(m.taskBackfillConcurrency))
 			taskSvc = authorizer.NewTaskService(m.logger.With(zap.String("service", "task-authz-validator")), taskSvc)
+			trashSvc = middleware.NewTrashService(m.kvService, coordinator)
 			m.taskControlService = combinedTaskService
 		}
 
@@ -798,25 +1687,93 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	m.usageTracker = usage.NewTracker(usage.DefaultRetention)
+	m.usageTracker.Service = m.kvService
+	m.usageTracker.WithLogger(m.logger)
+	if err := m.usageTracker.Load(ctx); err != nil {
+		m.logger.Error("failed to load request usage", zap.Error(err))
+		return err
+	}
+
+	if m.authorizationUsageInterval > 0 {
+		m.authorizationUsageTracker = http.NewAuthorizationUsageTracker(m.kvService)
+		m.authorizationUsageTracker.WithLogger(m.logger)
+	}
+
+	var idempotencyCache *http.IdempotencyCache
+	if m.httpIdempotencyWindow > 0 {
+		idempotencyCache = http.NewIdempotencyCache(m.httpIdempotencyWindow)
+	}
+
+	if err := m.httpRateLimit.Valid(); err != nil {
+		m.logger.Error("invalid http rate limit configuration", zap.Error(err))
+		return err
+	}
+	apiRateLimiter := http.NewAPIRateLimiter(m.httpRateLimit, m.kvService, m.kvService, m.kvService)
+	apiRateLimiter.WithLogger(m.logger)
+
+	// Queries of the API read the windows of materialized views when they can.
+	fluxSvc := materializedview.NewProxyQueryService(storageQueryService, &materializedview.Rewriter{
+		Views:         m.kvService,
+		BucketService: bucketSvc,
+	})
+	fluxSvc.WithLogger(m.logger)
+
+	dashboardRenderSvc := render.NewService(dashboardSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.kvService)
+	reportSvc := report.NewService(m.kvService, m.kvService, dashboardSvc, notificationEndpointSvc, secretSvc, dashboardRenderSvc)
+	reportSvc.WithLogger(m.logger)
+	if m.reportSMTPAddr != "" {
+		reportSvc.Mailer = &report.Mailer{
+			Addr:     m.reportSMTPAddr,
+			From:     m.reportSMTPFrom,
+			Username: m.reportSMTPUsername,
+			Password: m.reportSMTPPassword,
+		}
+	}
+
+	// The last writes to measurements are only reported when recorded.
+	var measurementLastWriteSvc platform.MeasurementLastWriteService
+	if m.measurementWriteTracker != nil {
+		measurementLastWriteSvc = m.kvService
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
+		UIBranding:           m.uiBranding,
 		HTTPErrorHandler:     http.ErrorHandler(0),
 		Logger:               m.logger,
 		SessionRenewDisabled: m.sessionRenewDisabled,
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		CheckStatusStream:    checkStatusStream,
+		WriteLimits:          writeLimits,
+		BodyLimit:            m.httpBodyLimit,
+		IdempotencyCache:     idempotencyCache,
+		APIRateLimiter:       apiRateLimiter,
 		DeleteService:        deleteService,
 		AuthorizationService: authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
 		SessionService:                  sessionSvc,
+		SessionFingerprinter:            sessionFingerprinter,
+		SessionAlertService:             m.kvService,
+		WriteLinkService:                m.kvService,
+		AuthzDebugHeaderEnabled:         m.authzDebugHeader,
+		AuthzDebugAuthorizations:        authzDebugIDs,
+		AuthorizationUsageTracker:       m.authorizationUsageTracker,
+		MessageCatalog:                  messageCatalog,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
 		LabelService:                    labelSvc,
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
+		DashboardRenderService:          dashboardRenderSvc,
+		DBRPMappingService:              dbrpSvc,
+		OrgSettingsService:              orgSettingsSvc,
+		UserSettingsService:             userSettingsSvc,
+		ResourceACLService:              resourceACLSvc,
 		BucketOperationLogService:       bucketLogSvc,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
@@ -825,31 +1782,162 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		PasswordsService:                passwdsSvc,
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
-		FluxService:                     storageQueryService,
+		FluxService:                     fluxSvc,
 		TaskService:                     taskSvc,
+		TrashService:                    trashSvc,
+		TaskBackfillService:             backfillSvc,
+		TaskLintService:                 tasklint.NewService(m.kvService),
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     notificationEndpointSvc,
 		CheckService:                    checkSvc,
+		MonitoringRunService:            monitoringRunSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
 		LookupService:                   lookupSvc,
 		DocumentService:                 m.kvService,
 		OrgLookupService:                m.kvService,
-		WriteEventRecorder:              infprom.NewEventRecorder("write"),
-		QueryEventRecorder:              infprom.NewEventRecorder("query"),
+		RuntimeConfigService:            runtimeConfigSvc,
+		InstanceService:                 m.discoveryService,
+		UsageService:                    usage.NewService(m.usageTracker, m.engine),
+		ShardService:                    storage.NewShardService(bucketSvc, m.engine),
+		BucketOptimizationService:       storage.NewBucketOptimizationService(m.logger.With(zap.String("service", "bucket-optimization")), bucketSvc, m.engine),
+		BucketSchemaService:             storage.NewBucketSchemaService(bucketSvc, m.engine, storage.WithSchemaCacheTTL(m.schemaCacheTTL)),
+		MeasurementLastWriteService:     measurementLastWriteSvc,
+		BucketSampleService:             sample.NewService(bucketSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController}),
+		SeriesFileService:               storage.NewSeriesFileService(m.engine),
+		MaterializedViewService:         m.kvService,
+		BucketMigrationService:          bucketmigration.NewService(m.logger.With(zap.String("service", "bucket-migration")), bucketSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController}),
+		RemoteConnectionService:         m.kvService,
+		NotebookService:                 m.kvService,
+		QueryTemplateService:            m.kvService,
+		ReportService:                   m.kvService,
+		ReportDeliveryService:           reportSvc,
+		MaintenanceService:              m.kvService,
+		IngestRuleService:               ingestSvc,
+		AlertService:                    history.NewAlertService(m.logger.With(zap.String("service", "alert")), m.kvService, m.kvService, m.kvService, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.monitoringHistoryRetention),
+		ForecastService:                 forecast.NewService(query.QueryServiceBridge{AsyncQueryService: m.queryController}),
+		WriteEventRecorder:              m.usageTracker.WriteRecorder(infprom.NewEventRecorder("write")),
+		QueryEventRecorder:              m.usageTracker.QueryRecorder(infprom.NewEventRecorder("query")),
 	}
 
 	m.reg.MustRegister(m.apibackend.PrometheusCollectors()...)
 
+	if m.selfMonitoringOrgID != "" {
+		orgID, err := platform.IDFromString(m.selfMonitoringOrgID)
+		if err != nil {
+			m.logger.Error("Invalid self-monitoring-org-id", zap.Error(err))
+			return err
+		}
+		monitor, err := gather.NewSelfMonitor(
+			m.logger.With(zap.String("service", "self-monitoring")),
+			m.reg,
+			bucketSvc,
+			gather.PointWriter{Writer: pointsWriter},
+			*orgID,
+			m.selfMonitoringInterval,
+		)
+		if err != nil {
+			m.logger.Error("Invalid self-monitoring-interval", zap.Error(err))
+			return err
+		}
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			monitor.Run(ctx)
+		}()
+	}
+
+	if m.plannerStatisticsInterval > 0 {
+		collector := storage.NewStatisticsCollector(m.engine, bucketSvc, m.kvService)
+		collector.WithLogger(m.logger)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			collector.Run(ctx, m.plannerStatisticsInterval)
+		}()
+	}
+
+	if m.materializedViewsInterval > 0 {
+		maintainer := materializedview.NewMaintainer(m.kvService, bucketSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController})
+		maintainer.ShardService = storage.NewShardService(bucketSvc, m.engine)
+		maintainer.Tracker = staleWindowTracker
+		maintainer.WithLogger(m.logger)
+		m.materializedViewMaintainer = maintainer
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			maintainer.Run(ctx, m.materializedViewsInterval)
+		}()
+	}
+
+	if m.authorizationUsageTracker != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.authorizationUsageTracker.Run(ctx, m.authorizationUsageInterval)
+		}()
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.usageTracker.Run(ctx, usage.DefaultFlushInterval)
+	}()
+
+	if m.measurementWriteTracker != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.measurementWriteTracker.Run(ctx, m.measurementLastWriteInterval)
+		}()
+	}
+
+	if m.reportsInterval > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			reportSvc.Run(ctx, m.reportsInterval)
+		}()
+	}
+
+	if m.discoveryService.Enabled() {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.discoveryService.Run(ctx, m.discoveryInterval)
+		}()
+	}
+
+	if m.forwarder != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.forwarder.Run(ctx, m.storeAndForwardInterval)
+		}()
+	}
+
+	if m.monitoringHistoryRetention > 0 {
+		enforcer := history.NewRetentionEnforcer(m.logger.With(zap.String("service", "monitoring-history-retention")), m.kvService, m.kvService, deleteService, m.kvService, m.monitoringHistoryRetention)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			enforcer.Run(ctx, history.RetentionInterval)
+		}()
+	}
+
 	var pkgSVC pkger.SVC
 	{
 		b := m.apibackend
 		pkgSVC = pkger.NewService(
 			pkger.WithLogger(m.logger.With(zap.String("service", "pkger"))),
 			pkger.WithBucketSVC(authorizer.NewBucketService(b.BucketService)),
-			pkger.WithDashboardSVC(authorizer.NewDashboardService(b.DashboardService)),
+			pkger.WithDashboardSVC(authorizer.NewDashboardService(b.DashboardService, b.ResourceACLService)),
 			pkger.WithLabelSVC(authorizer.NewLabelService(b.LabelService)),
 			pkger.WithVariableSVC(authorizer.NewVariableService(b.VariableService)),
 		)
@@ -865,11 +1953,47 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(platformHandler.PrometheusCollectors()...)
 
 	h := http.NewHandlerFromRegistry("platform", m.reg)
-	h.Handler = platformHandler
+	h.RouteOrgLabel = m.httpMetricsOrgLabel
+	if err := m.httpCORS.Valid(); err != nil {
+		m.logger.Error("invalid cors configuration", zap.Error(err))
+		return err
+	}
+	if err := m.httpBodyLimit.Valid(); err != nil {
+		m.logger.Error("invalid http body limit configuration", zap.Error(err))
+		return err
+	}
+	if err := m.httpServerConfig.Valid(); err != nil {
+		m.logger.Error("invalid http server configuration", zap.Error(err))
+		return err
+	}
+	var apiHandler nethttp.Handler = platformHandler
+	if m.httpAPIValidation {
+		validationMW, err := http.APIValidationMW(m.logger.With(zap.String("service", "api-validation")))
+		if err != nil {
+			m.logger.Error("failed to load swagger document for api validation", zap.Error(err))
+			return err
+		}
+		apiHandler = validationMW(apiHandler)
+	}
+	h.Handler = http.BodyLimitMW(m.httpBodyLimit)(apiHandler)
+	h.Handler = http.CORSMW(m.httpCORS)(h.Handler)
+	h.Handler = http.CompressionMW(m.httpCompression)(h.Handler)
 	httpLogger := m.logger.With(zap.String("service", "http"))
-	if logconf.Level == zap.DebugLevel {
-		h.Handler = http.LoggingMW(httpLogger)(h.Handler)
+	if m.httpAccessLogBucketID != "" {
+		exporter, err := m.newAccessLogExporter(pointsWriter, httpLogger)
+		if err != nil {
+			httpLogger.Error("invalid access log export configuration", zap.Error(err))
+			return err
+		}
+		m.httpAccessLog.Exporter = exporter
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			exporter.Run(ctx)
+		}()
 	}
+	h.Handler = http.AccessLogMW(httpLogger, m.httpAccessLog)(h.Handler)
 	h.Logger = httpLogger
 
 	m.httpServer.Handler = h
@@ -878,41 +2002,131 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		m.httpServer.Handler = http.DebugFlush(ctx, h, flushers)
 	}
 
-	ln, err := net.Listen("tcp", m.httpBindAddress)
+	// The API is served on the sockets passed by systemd, or else on the
+	// bind address.
+	lns, err := systemdListeners()
 	if err != nil {
-		httpLogger.Error("failed http listener", zap.Error(err))
+		httpLogger.Error("failed socket activation", zap.Error(err))
 		httpLogger.Info("Stopping")
 		return err
 	}
+	if len(lns) == 0 {
+		ln, err := net.Listen("tcp", m.httpBindAddress)
+		if err != nil {
+			httpLogger.Error("failed http listener", zap.Error(err))
+			httpLogger.Info("Stopping")
+			return err
+		}
+		lns = append(lns, ln)
+	}
 
-	var cer tls.Certificate
 	transport := "http"
 
-	if m.httpTLSCert != "" && m.httpTLSKey != "" {
+	var acmeLn net.Listener
+	switch {
+	case m.httpACME.enabled():
+		mgr := m.httpACME.manager()
+		if m.httpACME.httpBindAddress != "" {
+			if acmeLn, err = net.Listen("tcp", m.httpACME.httpBindAddress); err != nil {
+				closeListeners(lns)
+				httpLogger.Error("failed acme challenge listener", zap.Error(err))
+				httpLogger.Info("Stopping")
+				return err
+			}
+			m.acmeServer = &nethttp.Server{
+				Handler:           mgr.HTTPHandler(nil),
+				ReadHeaderTimeout: http.DefaultReadHeaderTimeout,
+			}
+		}
+		transport = "https"
+
+		m.httpServer.TLSConfig = mgr.TLSConfig()
+	case m.httpTLSCert != "" && m.httpTLSKey != "":
+		// The certificate is replaced when its files change or the
+		// configuration is reloaded.
 		var err error
-		cer, err = tls.LoadX509KeyPair(m.httpTLSCert, m.httpTLSKey)
+		m.httpCert, err = loadCertificate(m.httpTLSCert, m.httpTLSKey)
 
 		if err != nil {
+			closeListeners(lns)
 			httpLogger.Error("failed to load x509 key pair", zap.Error(err))
 			httpLogger.Info("Stopping")
 			return err
 		}
 		transport = "https"
 
-		m.httpServer.TLSConfig = &tls.Config{}
+		m.httpServer.TLSConfig = &tls.Config{GetCertificate: m.httpCert.GetCertificate}
+
+		if m.httpTLSCertCheckInterval > 0 {
+			m.wg.Add(1)
+			go func() {
+				defer m.wg.Done()
+				m.httpCert.watch(ctx, m.httpTLSCertCheckInterval, httpLogger)
+			}()
+		}
 	}
 
-	if addr, ok := ln.Addr().(*net.TCPAddr); ok {
+	for _, ln := range lns {
+		addr, ok := ln.Addr().(*net.TCPAddr)
+		if !ok {
+			continue
+		}
 		m.httpPort = addr.Port
+		if m.discoveryService.URL == "" {
+			m.discoveryService.URL = advertiseURL(transport, addr)
+		}
+		break
 	}
 
+	if err := m.httpServerConfig.Apply(m.httpServer); err != nil {
+		closeListeners(lns)
+		httpLogger.Error("failed to configure http server", zap.Error(err))
+		httpLogger.Info("Stopping")
+		return err
+	}
+
+	// Local clients connect to the Unix domain socket without TLS.
+	var unixLn net.Listener
+	if m.httpUnixSocket != "" {
+		if unixLn, err = listenUnix(m.httpUnixSocket); err != nil {
+			closeListeners(lns)
+			httpLogger.Error("failed unix socket listener", zap.Error(err))
+			httpLogger.Info("Stopping")
+			return err
+		}
+	}
+
+	for _, ln := range lns {
+		m.serveHTTP(httpLogger, ln, transport, transport == "https")
+	}
+	if unixLn != nil {
+		m.serveHTTP(httpLogger, unixLn, "http", false)
+	}
+	if acmeLn != nil {
+		m.wg.Add(1)
+		go func(logger *zap.Logger) {
+			defer m.wg.Done()
+			logger.Info("Listening", zap.String("transport", "http"), zap.String("service", "acme"), zap.String("addr", acmeLn.Addr().String()))
+			if err := m.acmeServer.Serve(acmeLn); err != nethttp.ErrServerClosed {
+				logger.Error("failed acme challenge service", zap.Error(err))
+			}
+		}(httpLogger)
+	}
+
+	return nil
+}
+
+// serveHTTP serves the HTTP API on the listener until the server is shut down.
+func (m *Launcher) serveHTTP(logger *zap.Logger, ln net.Listener, transport string, useTLS bool) {
+	ln = m.httpServerConfig.Listener(ln)
 	m.wg.Add(1)
-	go func(logger *zap.Logger) {
+	go func() {
 		defer m.wg.Done()
-		logger.Info("Listening", zap.String("transport", transport), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
+		addr := ln.Addr()
+		logger.Info("Listening", zap.String("transport", transport), zap.String("network", addr.Network()), zap.String("addr", addr.String()))
 
-		if cer.Certificate != nil {
-			if err := m.httpServer.ServeTLS(ln, m.httpTLSCert, m.httpTLSKey); err != nethttp.ErrServerClosed {
+		if useTLS {
+			if err := m.httpServer.ServeTLS(ln, "", ""); err != nethttp.ErrServerClosed {
 				logger.Error("failed https service", zap.Error(err))
 			}
 		} else {
@@ -920,10 +2134,65 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				logger.Error("failed http service", zap.Error(err))
 			}
 		}
-		logger.Info("Stopping")
-	}(httpLogger)
+		logger.Info("Stopping", zap.String("addr", addr.String()))
+	}()
+}
 
-	return nil
+// advertiseURL returns the URL advertised to the peers for the listener
+// address, replacing an unspecified host by the host name of the machine.
+func advertiseURL(scheme string, addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		if host, err = os.Hostname(); err != nil {
+			host = "localhost"
+		}
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// parseTaskWorkerPools parses the name=workers pairs of the task worker pools.
+func parseTaskWorkerPools(specs []string) (map[string]int, error) {
+	pools := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid task worker pool %q: expected name=workers", spec)
+		}
+		workers, err := strconv.Atoi(parts[1])
+		if err != nil || workers < 1 {
+			return nil, fmt.Errorf("invalid task worker pool %q: workers must be a positive integer", spec)
+		}
+		pools[parts[0]] = workers
+	}
+	return pools, nil
+}
+
+// parseIDs parses the IDs of a flag.
+func parseIDs(ids []string) ([]platform.ID, error) {
+	parsed := make([]platform.ID, 0, len(ids))
+	for _, s := range ids {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, *id)
+	}
+	return parsed, nil
+}
+
+func (m *Launcher) newAccessLogExporter(w storage.PointsWriter, logger *zap.Logger) (*http.AccessLogExporter, error) {
+	orgID, err := platform.IDFromString(m.httpAccessLogOrgID)
+	if err != nil {
+		return nil, fmt.Errorf("http-access-log-org-id: %v", err)
+	}
+	bucketID, err := platform.IDFromString(m.httpAccessLogBucketID)
+	if err != nil {
+		return nil, fmt.Errorf("http-access-log-bucket-id: %v", err)
+	}
+	return http.NewAccessLogExporter(*orgID, *bucketID, w, logger.With(zap.String("service", "access-log-exporter"))), nil
 }
 
 // OrganizationService returns the internal organization service.
@@ -972,6 +2241,12 @@ func (m *Launcher) TaskScheduler() taskbackend.Scheduler {
 	return m.scheduler
 }
 
+// MaterializedViewMaintainer returns the maintainer of the materialized
+// views, or nil if they are not maintained.
+func (m *Launcher) MaterializedViewMaintainer() *materializedview.Maintainer {
+	return m.materializedViewMaintainer
+}
+
 // KeyValueService returns the internal key-value service.
 func (m *Launcher) KeyValueService() *kv.Service {
 	return m.kvService
@@ -1,11 +1,17 @@
 package launcher_test
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
@@ -139,3 +145,439 @@ func TestLauncher_SetupWithUsers(t *testing.T) {
 		t.Fatalf("unexpected 2 users: %#+v", exp)
 	}
 }
+
+func TestLauncher_Argon2idPasswords(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--password-hash", "argon2id", "--password-argon2-memory", "1024")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	signin := func(password string) int {
+		r, err := nethttp.NewRequest("POST", l.URL()+"/api/v2/signin", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.SetBasicAuth("USER", password)
+		resp, err := nethttp.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := signin("PASSWORD"); code != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code signing in: %d", code)
+	}
+	if code := signin("WRONG-PASSWORD"); code != nethttp.StatusUnauthorized {
+		t.Fatalf("unexpected status code signing in with a wrong password: %d", code)
+	}
+}
+
+func TestLauncher_UnknownPasswordHash(t *testing.T) {
+	l := launcher.NewTestLauncher()
+	defer os.RemoveAll(l.Path)
+	if err := l.Run(ctx, "--password-hash", "md5"); err == nil || !strings.Contains(err.Error(), "unknown password hash md5") {
+		t.Fatalf("expected an unknown password hash to be rejected, got %v", err)
+	}
+}
+
+func TestLauncher_SessionFingerprintBinding(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--session-fingerprint-binding")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	r, err := nethttp.NewRequest("POST", l.URL()+"/api/v2/signin", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("User-Agent", "browser")
+	r.SetBasicAuth("USER", "PASSWORD")
+	resp, err := nethttp.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent || len(resp.Cookies()) != 1 {
+		t.Fatalf("unexpected status code signing in: %d", resp.StatusCode)
+	}
+	cookie := resp.Cookies()[0]
+
+	get := func(path, userAgent string) (int, []byte) {
+		t.Helper()
+		r, err := nethttp.NewRequest("GET", l.URL()+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("User-Agent", userAgent)
+		r.AddCookie(cookie)
+		resp, err := nethttp.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+
+	if code, _ := get("/api/v2/me", "browser"); code != nethttp.StatusOK {
+		t.Fatalf("unexpected status code using the session: %d", code)
+	}
+	if code, _ := get("/api/v2/me", "curl"); code != nethttp.StatusUnauthorized {
+		t.Fatalf("expected the session to be rejected from another client, got %d", code)
+	}
+
+	code, body := get("/api/v2/me/session-alerts", "browser")
+	if code != nethttp.StatusOK {
+		t.Fatalf("unexpected status code listing session alerts: %d, body: %s", code, body)
+	}
+	var res struct {
+		Alerts []platform.SessionAlert `json:"alerts"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Alerts) != 1 || res.Alerts[0].UserAgent != "curl" {
+		t.Fatalf("unexpected session alerts: %s", body)
+	}
+}
+
+func TestLauncher_RuntimeConfig(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	do := func(method, body string) platform.RuntimeConfig {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest(method, "/api/v2/config/runtime", body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != nethttp.StatusOK {
+			b, _ := ioutil.ReadAll(resp.Body)
+			t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, b)
+		}
+		var c platform.RuntimeConfig
+		if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	c := do("GET", "")
+	if c.QueryConcurrency != 10 || c.WriteMaxBodyBytes != 0 {
+		t.Fatalf("unexpected initial runtime config: %+v", c)
+	}
+
+	c = do("PATCH", `{"queryConcurrency":2,"writeMaxBodyBytes":5}`)
+	if c.QueryConcurrency != 2 || c.WriteMaxBodyBytes != 5 {
+		t.Fatalf("unexpected updated runtime config: %+v", c)
+	}
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", "/api/v2/write?org=ORG&bucket=BUCKET", "m,k=v f=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusRequestEntityTooLarge {
+		t.Fatalf("unexpected write status code: %d", resp.StatusCode)
+	}
+
+	c = do("DELETE", "")
+	if c.QueryConcurrency != 10 || c.WriteMaxBodyBytes != 0 {
+		t.Fatalf("unexpected reset runtime config: %+v", c)
+	}
+	l.WritePointsOrFail(t, "m,k=v f=1")
+}
+
+func TestLauncher_RuntimeConfigZeroBurst(t *testing.T) {
+	l := launcher.NewTestLauncher()
+	l.StorageConfig.Engine.Compaction.ThroughputBurst = 0
+	if err := l.Run(ctx); err != nil {
+		t.Fatalf("expected a compaction throughput burst of 0 to be accepted: %v", err)
+	}
+	defer l.ShutdownOrFail(t, ctx)
+	l.SetupOrFail(t)
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("PATCH", "/api/v2/config/runtime", `{"compactionThroughputBurst":0}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status code updating the burst to 0: %d", resp.StatusCode)
+	}
+}
+
+func TestLauncher_UnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := launcher.NewTestLauncher()
+	sock := filepath.Join(dir, "influxd.sock")
+	if err := l.Run(ctx, "--http-unix-socket", sock); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := "unix://" + sock
+	results, err := (&http.SetupService{Addr: addr}).Generate(ctx, &platform.OnboardingRequest{
+		User:     "USER",
+		Password: "PASSWORD",
+		Org:      "ORG",
+		Bucket:   "BUCKET",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &http.WriteService{Addr: addr, Token: results.Auth.Token}
+	if err := svc.Write(ctx, results.Org.ID, results.Bucket.ID, strings.NewReader("m,k=v f=1")); err != nil {
+		t.Fatal(err)
+	}
+
+	l.ShutdownOrFail(t, ctx)
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Fatalf("expected the socket to be removed at shutdown: %v", err)
+	}
+}
+
+func TestLauncher_ReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "influxd.toml")
+	writeConfig := func(config string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("write-max-point-age = \"1h\"\n")
+	os.Setenv(launcher.ConfigPathEnv, path)
+	defer os.Unsetenv(launcher.ConfigPathEnv)
+
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	point := fmt.Sprintf("m,k=v f=1 %d", time.Now().Add(-2*time.Hour).UnixNano())
+	if err := l.WritePoints(point); err == nil {
+		t.Fatal("expected the point older than the max point age of the config file to be rejected")
+	}
+
+	// The change of the write limits is applied, the change of the bind
+	// address requires a restart.
+	writeConfig("write-max-point-age = \"3h\"\nhttp-bind-address = \":1\"\n")
+	if err := l.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	l.WritePointsOrFail(t, point)
+
+	writeConfig("write-max-point-age = \"-\"\n")
+	if err := l.Reload(ctx); err == nil {
+		t.Fatal("expected an error reloading an invalid config file")
+	}
+}
+
+func TestLauncher_Usage(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	data := "m,k=v f=1"
+	l.WritePointsOrFail(t, data)
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", fmt.Sprintf("/api/v2/orgs/%s/usage", l.Org.ID), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, b)
+	}
+
+	var u struct {
+		Usage map[platform.UsageMetric]float64 `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Usage[platform.UsageWriteRequestCount]; got != 1 {
+		t.Errorf("write request count = %v, want 1", got)
+	}
+	if got, want := u.Usage[platform.UsageWriteRequestBytes], float64(len(data)); got != want {
+		t.Errorf("write request bytes = %v, want %v", got, want)
+	}
+	if _, ok := u.Usage[platform.UsageSeries]; !ok {
+		t.Error("missing series count")
+	}
+}
+
+func TestLauncher_APIRateLimit(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--http-rate-limit-write", "1")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, "m,k=v f=1")
+
+	path := fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID)
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", path, "m,k=v f=2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusTooManyRequests {
+		t.Fatalf("write over the rate limit returned %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") != "1" || resp.Header.Get(http.RateLimitLimitHeader) != "1" {
+		t.Errorf("unexpected rate limit headers %v", resp.Header)
+	}
+}
+
+func TestLauncher_DeletePermission(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	write, err := platform.NewPermissionAtID(l.Bucket.ID, platform.WriteAction, platform.BucketsResourceType, l.Org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := &platform.Authorization{OrgID: l.Org.ID, Permissions: []platform.Permission{*write}}
+	if err := l.AuthorizationService().CreateAuthorization(ctx, writer); err != nil {
+		t.Fatal(err)
+	}
+
+	path := fmt.Sprintf("/api/v2/delete?orgID=%s&bucketID=%s", l.Org.ID, l.Bucket.ID)
+	body := `{"start":"2009-01-01T23:00:00Z","stop":"2019-11-10T01:00:00Z"}`
+	for _, tt := range []struct {
+		token string
+		want  int
+	}{
+		{token: writer.Token, want: nethttp.StatusForbidden},
+		{token: l.Auth.Token, want: nethttp.StatusNoContent},
+	} {
+		resp, err := nethttp.DefaultClient.Do(l.NewHTTPRequestOrFail(t, "POST", path, tt.token, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("delete returned %d, want %d", resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestLauncher_CheckStatusesStream(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	req := l.NewHTTPRequestOrFail(t, "GET", fmt.Sprintf("/api/v2/checks/statuses/stream?orgID=%s", l.Org.ID), l.Auth.Token, "")
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+
+	// Two statuses of the same level only produce a single event.
+	statuses := []string{
+		`statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=ok,host=a _message="cpu is ok" 1000000000`,
+		`statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=ok,host=a _message="cpu is ok" 2000000000`,
+		`statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=crit,host=a _message="cpu is high" 3000000000`,
+	}
+	for _, s := range statuses {
+		req := l.NewHTTPRequestOrFail(t, "POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, platform.MonitoringSystemBucketName), l.Auth.Token, s)
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != nethttp.StatusNoContent {
+			t.Fatalf("unexpected status code %d writing statuses", resp.StatusCode)
+		}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	var events []platform.CheckStatusEvent
+	for len(events) < 2 && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var e platform.CheckStatusEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if e := events[0]; e.Level != "ok" || e.PreviousLevel != "" || e.CheckName != "cpu" || e.Tags["host"] != "a" {
+		t.Errorf("unexpected first event %+v", e)
+	}
+	if e := events[1]; e.Level != "crit" || e.PreviousLevel != "ok" || e.Message != "cpu is high" {
+		t.Errorf("unexpected second event %+v", e)
+	}
+}
+
+func TestLauncher_MessageCatalogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-catalogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"unauthorized access": "nicht autorisierter Zugriff"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--http-message-catalogs-path", dir)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	req, err := nethttp.NewRequest("GET", l.URL()+"/api/v2/buckets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Language", "de-AT")
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != platform.EUnauthorized || body.Message != "nicht autorisierter Zugriff" {
+		t.Fatalf("unexpected error response %+v", body)
+	}
+	if got := resp.Header.Get("Content-Language"); got != "de" {
+		t.Fatalf("unexpected Content-Language %q", got)
+	}
+}
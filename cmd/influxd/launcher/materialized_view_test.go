@@ -0,0 +1,153 @@
+package launcher_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_MaterializedView(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--materialized-views-interval", "24h")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// Two series of cpu with a point every 15s for 10m, and a point of
+	// another measurement that the views ignore.
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 40; i++ {
+		ts := start.Add(time.Duration(i) * 15 * time.Second).UnixNano()
+		lines = append(lines,
+			fmt.Sprintf("cpu,host=a usage=%d %d", i, ts),
+			fmt.Sprintf("cpu,host=b usage=%d %d", 2*i, ts),
+		)
+	}
+	lines = append(lines, fmt.Sprintf("mem,host=a used=1 %d", start.UnixNano()))
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	dest := &influxdb.Bucket{OrgID: l.Org.ID, Name: "cpu_1m"}
+	if err := l.BucketService().CreateBucket(ctx, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	kvs := l.KeyValueService()
+	mvs := make(map[influxdb.MaterializedViewAggregate]*influxdb.MaterializedView)
+	for _, agg := range []influxdb.MaterializedViewAggregate{influxdb.MaterializedViewMean, influxdb.MaterializedViewCount} {
+		if agg != influxdb.MaterializedViewMean {
+			dest = &influxdb.Bucket{OrgID: l.Org.ID, Name: "cpu_1m_" + string(agg)}
+			if err := l.BucketService().CreateBucket(ctx, dest); err != nil {
+				t.Fatal(err)
+			}
+		}
+		mv := &influxdb.MaterializedView{
+			OrganizationID:      l.Org.ID,
+			Name:                "cpu " + string(agg),
+			SourceBucketID:      l.Bucket.ID,
+			DestinationBucketID: dest.ID,
+			Measurement:         "cpu",
+			Aggregate:           agg,
+			Every:               time.Minute,
+		}
+		if err := kvs.CreateMaterializedView(ctx, mv); err != nil {
+			t.Fatal(err)
+		}
+		mvs[agg] = mv
+	}
+
+	// The windows of the shard group holding the data are materialized by
+	// several queries, and the empty windows after it without any.
+	m := l.MaterializedViewMaintainer()
+	m.MaxWindowsPerQuery = 2000
+	if err := m.Maintain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, mv := range mvs {
+		got, err := kvs.FindMaterializedViewByID(ctx, mv.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.LastRunError != "" || !got.Materialized() {
+			t.Fatalf("view %q was not materialized: %q", got.Name, got.LastRunError)
+		}
+	}
+
+	// The query reads the windows from 00:01 to 00:08 from the view, and the
+	// partial windows at the ends of the range from the source bucket. The
+	// reference query cannot be rewritten as its filter uses the values.
+	queries := []struct {
+		name      string
+		aggregate string
+	}{
+		{name: "mean", aggregate: "every: 1m, fn: mean"},
+		{name: "count", aggregate: "every: 1m, fn: count, createEmpty: false"},
+		{name: "count of multiple windows", aggregate: "every: 2m, fn: count, createEmpty: false"},
+	}
+	for _, tc := range queries {
+		t.Run(tc.name, func(t *testing.T) {
+			q := fmt.Sprintf(`from(bucket: "%s")
+	|> range(start: 2019-12-01T00:00:30Z, stop: 2019-12-01T00:08:30Z)
+	|> filter(fn: (r) => r._measurement == "cpu"%%s)
+	|> aggregateWindow(%s)`, l.Bucket.Name, tc.aggregate)
+
+			got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, ""))
+			want := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, ` and r._value > -1.0`))
+			if got != want {
+				t.Fatalf("unexpected results of rewritten query:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	// Data that arrives after its window was materialized marks the window
+	// stale, so the rewritten query reads it from the source bucket until
+	// it is materialized again.
+	q := fmt.Sprintf(`from(bucket: "%s")
+	|> range(start: 2019-12-01T00:03:00Z, stop: 2019-12-01T00:04:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r.host == "a")
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> keep(columns: ["_value"])`, l.Bucket.Name)
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, q); !strings.Contains(got, ",13.5\r\n") {
+		t.Fatalf("expected the materialized mean of the window, got:\n%s", got)
+	}
+	l.WritePointsOrFail(t, fmt.Sprintf("cpu,host=a usage=1000 %d", start.Add(3*time.Minute+time.Second).UnixNano()))
+	got, err := kvs.FindMaterializedViewByID(ctx, mvs[influxdb.MaterializedViewMean].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(3 * time.Minute); !got.StaleFrom.Equal(want) {
+		t.Fatalf("expected the view to be stale from %s, got %s", want, got.StaleFrom)
+	}
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, q); !strings.Contains(got, ",210.8\r\n") {
+		t.Fatalf("expected the mean of the source bucket, got:\n%s", got)
+	}
+
+	// The stale window is materialized again.
+	if err := m.Maintain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = kvs.FindMaterializedViewByID(ctx, got.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !got.StaleFrom.IsZero() {
+		t.Fatalf("expected the stale windows to be materialized again, got stale from %s", got.StaleFrom)
+	}
+	viewQuery := fmt.Sprintf(`from(bucketID: "%s")
+	|> range(start: 2019-12-01T00:03:00Z, stop: 2019-12-01T00:04:00Z)
+	|> filter(fn: (r) => r.host == "a")
+	|> keep(columns: ["_value"])`, got.DestinationBucketID)
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, viewQuery); !strings.Contains(got, ",210.8\r\n") {
+		t.Fatalf("expected the view to hold the mean of the source bucket, got:\n%s", got)
+	}
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, q); !strings.Contains(got, ",210.8\r\n") {
+		t.Fatalf("expected the mean of the materialized window, got:\n%s", got)
+	}
+
+	// Queries that opt out of the views read the source bucket.
+	q = "option materializedViews = {enabled: false}\n" + q
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, q); !strings.Contains(got, ",210.8\r\n") {
+		t.Fatalf("expected the mean of the source bucket, got:\n%s", got)
+	}
+}
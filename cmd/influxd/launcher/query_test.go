@@ -3,6 +3,7 @@ package launcher_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,7 @@ import (
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	phttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/query"
 )
 
@@ -405,3 +407,259 @@ from(bucket: "%s")
 		t.Fatal(err)
 	}
 }
+
+func TestPipeline_Query_Sketch(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 200; i++ {
+		ts := start.Add(time.Duration(i) * time.Second).UnixNano()
+		lines = append(lines,
+			fmt.Sprintf("cpu,host=a usage=%d %d", i%100, ts),
+			fmt.Sprintf("cpu,host=b state=\"s%d\" %d", i%10, ts),
+		)
+	}
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	// The estimates read from storage must match the estimates of the
+	// transformations, which are used when the drop prevents the pushdown.
+	queries := []struct {
+		name  string
+		field string
+		fn    string
+		want  string
+	}{
+		{name: "quantile", field: "usage", fn: "sketch.approxQuantile(q: 0.5)", want: ",49.5\r\n"},
+		{name: "distinct", field: "usage", fn: "sketch.approxDistinct()", want: ",100\r\n"},
+		{name: "distinct strings", field: "state", fn: "sketch.approxDistinct()", want: ",10\r\n"},
+	}
+	for _, tc := range queries {
+		t.Run(tc.name, func(t *testing.T) {
+			q := fmt.Sprintf(`import "influxdata/influxdb/sketch"
+from(bucket: "%s")
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-02T00:00:00Z)
+	|> filter(fn: (r) => r._field == "%s")%%s
+	|> %s`, l.Bucket.Name, tc.field, tc.fn)
+
+			got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, ""))
+			want := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, "\n\t|> drop(columns: [\"_time\"])"))
+			if got != want {
+				t.Fatalf("unexpected results of the pushed down query:\n%s\nwant:\n%s", got, want)
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected %q in the results, got:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPipeline_Query_Remote(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `cpu,host=a usage=1 1575158400000000000
+cpu,host=b usage=2 1575158401000000000
+cpu,host=b usage=3 1575158402000000000
+mem,host=a used=4 1575158400000000000`)
+
+	// The instance reads its own bucket through a remote connection.
+	if err := l.SecretService().PutSecret(ctx, l.Org.ID, "self-token", l.Auth.Token); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", "/api/v2/remotes", fmt.Sprintf(`{
+		"orgID": %q,
+		"name": "self",
+		"url": %q,
+		"remoteOrgID": %q,
+		"tokenSecretKey": "self-token"
+	}`, l.Org.ID, l.URL(), l.Org.ID)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rc influxdb.RemoteConnection
+	err = json.NewDecoder(resp.Body).Decode(&rc)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusCreated {
+		t.Fatalf("unexpected status creating the remote connection: %d", resp.StatusCode)
+	}
+
+	resp, err = nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", "/api/v2/remotes/"+rc.ID.String()+"/test", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res check.Response
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != check.StatusPass {
+		t.Fatalf("expected the remote connection test to pass, got %+v", res)
+	}
+
+	q := `import "influxdata/influxdb/remote"
+threshold = 1.0
+%s
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-02T00:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._value > threshold)`
+	got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, `remote.from(remote: "self", bucket: "`+l.Bucket.Name+`")`))
+	want := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(q, `from(bucket: "`+l.Bucket.Name+`")`))
+	if got != want {
+		t.Fatalf("unexpected results of the remote query:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Count(got, "cpu") != 2 {
+		t.Fatalf("expected the two cpu points above the threshold, got:\n%s", got)
+	}
+
+	err = l.QueryAndNopConsume(ctx, &query.Request{
+		Authorization:  l.Auth,
+		OrganizationID: l.Org.ID,
+		Compiler: lang.FluxCompiler{
+			Query: `import "influxdata/influxdb/remote"
+remote.from(remote: "missing", bucket: "b") |> range(start: -1h)`,
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), `could not find remote "missing"`) {
+		t.Fatalf("expected missing remote to fail, got %v", err)
+	}
+}
+
+func TestPipeline_QueryTemplates(t *testing.T) {
+	be := launcher.RunTestLauncherOrFail(t, ctx)
+	be.SetupOrFail(t)
+	defer be.ShutdownOrFail(t, ctx)
+
+	ts := time.Now().Add(-10 * time.Minute).Truncate(5 * time.Minute).Add(10 * time.Second).UnixNano()
+	be.WritePointsOrFail(t, fmt.Sprintf(`http_requests,status=200 count=90i %[1]d
+http_requests,status=503 count=10i %[1]d
+cpu,host=a usage_user=10 %[1]d
+cpu,host=b usage_user=30 %[1]d
+cpu,host=c usage_user=20 %[1]d`, ts))
+
+	templates := make(map[string]*influxdb.QueryTemplate)
+	for _, tmpl := range influxdb.BuiltinQueryTemplates() {
+		templates[tmpl.Name] = tmpl
+	}
+
+	for _, tt := range []struct {
+		template string
+		params   map[string]string
+		want     []string
+	}{
+		{template: "error-rate", want: []string{",0.1\r\n"}},
+		{template: "burn-rate", params: map[string]string{"objective": "0.5"}, want: []string{",0.2\r\n"}},
+		{template: "top-hosts", params: map[string]string{"k": "2"}, want: []string{",b,30\r\n", ",c,20\r\n"}},
+	} {
+		t.Run(tt.template, func(t *testing.T) {
+			params := map[string]string{"bucket": be.Bucket.Name}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+			flux, err := templates[tt.template].Render(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := be.FluxQueryOrFail(t, be.Org, be.Auth.Token, flux)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected result to contain %q, got:\n%s", want, got)
+				}
+			}
+			if strings.Contains(got, ",a,") {
+				t.Errorf("expected only the top hosts, got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestPipeline_QueryMaxPoints(t *testing.T) {
+	be := launcher.RunTestLauncherOrFail(t, ctx)
+	be.SetupOrFail(t)
+	defer be.ShutdownOrFail(t, ctx)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	var points strings.Builder
+	for i := 0; i < 600; i++ {
+		fmt.Fprintf(&points, "m v=%d %d\n", i, start.Add(time.Duration(i)*time.Second).UnixNano())
+	}
+	be.WritePointsOrFail(t, points.String())
+
+	q := fmt.Sprintf(`from(bucket: %q) |> range(start: %s, stop: %s)`,
+		be.Bucket.Name, start.Format(time.RFC3339), start.Add(10*time.Minute).Format(time.RFC3339))
+	for _, tt := range []struct {
+		maxPoints int
+		want      int
+	}{
+		{want: 600},
+		{maxPoints: 60, want: 60},
+	} {
+		body, err := json.Marshal(phttp.QueryRequest{Query: q, MaxPoints: tt.maxPoints})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := be.MustNewHTTPRequest("POST", "/api/v2/query?orgID="+be.Org.ID.String(), string(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		_, err = io.Copy(&b, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("query with maxPoints %d returned %d: %s", tt.maxPoints, resp.StatusCode, b.String())
+		}
+
+		if got := strings.Count(b.String(), ",_result,"); got != tt.want {
+			t.Errorf("query with maxPoints %d returned %d points, want %d", tt.maxPoints, got, tt.want)
+		}
+	}
+}
+
+func TestPipeline_Query_AnomalyDetect(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 60; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute).UnixNano()
+		spike := 0
+		if i == 59 {
+			spike = 50
+		}
+		lines = append(lines,
+			fmt.Sprintf("cpu,host=a usage=%d %d", 10+i%3+spike, ts),
+			fmt.Sprintf("cpu,host=b usage=%d %d", 10+i%3, ts),
+		)
+	}
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	for _, algorithm := range []string{"mad", "zscore", "holtWinters"} {
+		t.Run(algorithm, func(t *testing.T) {
+			got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(`import "influxdata/influxdb/anomaly"
+import "influxdata/influxdb/v1"
+from(bucket: "%s")
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-02T00:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> v1.fieldsAsCols()
+	|> anomaly.detect(algorithm: "%s", column: "usage")
+	|> keep(columns: ["host", "_anomaly"])`, l.Bucket.Name, algorithm))
+			if !strings.Contains(got, ",a,true\r\n") || !strings.Contains(got, ",b,false\r\n") {
+				t.Fatalf("expected the spike of host a to be anomalous, got:\n%s", got)
+			}
+		})
+	}
+}
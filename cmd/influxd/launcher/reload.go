@@ -0,0 +1,219 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/cli"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ConfigPathEnv is the environment variable naming the config file of the
+// launcher. The keys of the file are the names of the flags, e.g.
+// log-level = "debug" in TOML; flags and environment variables take
+// precedence over the file. The format of the file is given by its
+// extension: toml, yaml or json.
+const ConfigPathEnv = "INFLUXD_CONFIG_PATH"
+
+// reloadableFlags are the settings whose changes are applied when the
+// configuration is reloaded. Changes of other settings require a restart.
+var reloadableFlags = map[string]bool{
+	"log-level":              true,
+	"write-max-point-age":    true,
+	"write-max-point-future": true,
+	"tls-cert":               true,
+	"tls-key":                true,
+}
+
+// configFile reads the settings of the config file.
+type configFile struct {
+	path  string
+	opts  []cli.Opt
+	flags *pflag.FlagSet
+}
+
+// values returns the values of the settings of the file that are not set by
+// flags or environment variables, by flag.
+func (f *configFile) values() (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(f.path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", f.path, err)
+	}
+
+	values := make(map[string]interface{})
+	for _, o := range f.opts {
+		if !v.IsSet(o.Flag) {
+			continue
+		}
+		if fl := f.flags.Lookup(o.Flag); fl != nil && fl.Changed {
+			continue
+		}
+		if _, ok := os.LookupEnv("INFLUXD_" + strings.ToUpper(strings.Replace(o.Flag, "-", "_", -1))); ok {
+			continue
+		}
+
+		var (
+			value interface{}
+			err   error
+		)
+		switch o.DestP.(type) {
+		case *string:
+			value, err = cast.ToStringE(v.Get(o.Flag))
+		case *int:
+			value, err = cast.ToIntE(v.Get(o.Flag))
+		case *bool:
+			value, err = cast.ToBoolE(v.Get(o.Flag))
+		case *time.Duration:
+			value, err = cast.ToDurationE(v.Get(o.Flag))
+		case *[]string:
+			value, err = cast.ToStringSliceE(v.Get(o.Flag))
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in config file %s: %v", o.Flag, f.path, err)
+		}
+		values[o.Flag] = value
+	}
+	return values, nil
+}
+
+// loadConfigFile sets the settings of the config file named by
+// ConfigPathEnv, if any.
+func (m *Launcher) loadConfigFile() error {
+	path := os.Getenv(ConfigPathEnv)
+	if path == "" {
+		return nil
+	}
+	m.configFile = &configFile{path: path, opts: m.opts, flags: m.flags}
+
+	values, err := m.configFile.values()
+	if err != nil {
+		return err
+	}
+	for _, o := range m.opts {
+		if v, ok := values[o.Flag]; ok {
+			reflect.ValueOf(o.DestP).Elem().Set(reflect.ValueOf(v))
+		}
+	}
+	return nil
+}
+
+// Reload re-reads the config file and applies the changes of the settings
+// that can change at runtime: the log level, the write limits and the TLS
+// certificate. The changes of other settings are logged and ignored until
+// the next restart. If a change cannot be applied, none are.
+func (m *Launcher) Reload(ctx context.Context) error {
+	if m.configFile == nil {
+		m.logger.Info("No config file to reload", zap.String("env", ConfigPathEnv))
+		return nil
+	}
+	values, err := m.configFile.values()
+	if err != nil {
+		return err
+	}
+
+	var (
+		reloaded, restart []string
+		previous          = make(map[string]interface{})
+	)
+	for _, o := range m.opts {
+		v, ok := values[o.Flag]
+		if !ok {
+			continue
+		}
+		dest := reflect.ValueOf(o.DestP).Elem()
+		if reflect.DeepEqual(dest.Interface(), v) {
+			continue
+		}
+		if !m.reloadable(o.Flag) {
+			restart = append(restart, o.Flag)
+			continue
+		}
+		previous[o.Flag] = dest.Interface()
+		dest.Set(reflect.ValueOf(v))
+		reloaded = append(reloaded, o.Flag)
+	}
+
+	if len(restart) > 0 {
+		sort.Strings(restart)
+		m.logger.Warn("Changed settings require a restart", zap.Strings("settings", restart))
+	}
+	if len(reloaded) == 0 {
+		m.logger.Info("Reloaded configuration without changes")
+		return nil
+	}
+
+	if err := m.applyReloadable(ctx); err != nil {
+		for _, o := range m.opts {
+			if v, ok := previous[o.Flag]; ok {
+				reflect.ValueOf(o.DestP).Elem().Set(reflect.ValueOf(v))
+			}
+		}
+		return err
+	}
+	sort.Strings(reloaded)
+	m.logger.Info("Reloaded configuration", zap.Strings("settings", reloaded))
+	return nil
+}
+
+// reloadable returns whether changes of the setting are applied at runtime.
+// The TLS certificate can only be replaced if the server was started with one.
+func (m *Launcher) reloadable(flag string) bool {
+	if (flag == "tls-cert" || flag == "tls-key") && m.httpCert == nil {
+		return false
+	}
+	return reloadableFlags[flag]
+}
+
+func (m *Launcher) applyReloadable(ctx context.Context) error {
+	var kp *keyPair
+	if m.httpCert != nil {
+		var err error
+		if kp, err = readKeyPair(m.httpTLSCert, m.httpTLSKey); err != nil {
+			return err
+		}
+	}
+	if err := m.runtimeConfig.reconfigure(func(c *platform.RuntimeConfig) {
+		c.LogLevel = m.logLevel
+	}); err != nil {
+		return err
+	}
+	if m.httpCert != nil {
+		m.httpCert.set(kp)
+	}
+	m.writeLimits.SetMaxPointAge(m.writeMaxPointAge)
+	m.writeLimits.SetMaxPointFuture(m.writeMaxPointFuture)
+	return nil
+}
+
+// reloadOnHangup reloads the configuration on SIGHUP until ctx is done.
+func (m *Launcher) reloadOnHangup(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			m.logger.Info("Reloading configuration")
+			if err := m.Reload(ctx); err != nil {
+				m.logger.Error("Failed to reload configuration", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package launcher_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_ReportDelivery(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	bodies := make(chan map[string]string, 1)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	d := &influxdb.Dashboard{OrganizationID: l.Org.ID, Name: "hosts"}
+	if err := l.DashboardService().CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path, body string, want int) []byte {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest(method, path, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s returned %d, want %d: %s", method, path, resp.StatusCode, want, b)
+		}
+		return b
+	}
+
+	var edp struct {
+		ID influxdb.ID `json:"id"`
+	}
+	b := do("POST", "/api/v2/notificationEndpoints", fmt.Sprintf(`{
+		"orgID": %q,
+		"name": "webhook",
+		"type": "http",
+		"status": "active",
+		"url": %q,
+		"method": "POST",
+		"authMethod": "none"
+	}`, l.Org.ID, srv.URL), nethttp.StatusCreated)
+	if err := json.Unmarshal(b, &edp); err != nil {
+		t.Fatal(err)
+	}
+
+	var r influxdb.Report
+	b = do("POST", "/api/v2/reports", fmt.Sprintf(`{
+		"orgID": %q,
+		"name": "daily",
+		"dashboardID": %q,
+		"every": "24h",
+		"timeRange": {"start": "-24h"},
+		"endpointID": %q
+	}`, l.Org.ID, d.ID, edp.ID), nethttp.StatusCreated)
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.OwnerID != l.User.ID {
+		t.Fatalf("expected the report to be owned by the user creating it, got %s", r.OwnerID)
+	}
+
+	var run influxdb.ReportRun
+	b = do("POST", fmt.Sprintf("/api/v2/reports/%s/runs", r.ID), "", nethttp.StatusCreated)
+	if err := json.Unmarshal(b, &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != influxdb.ReportRunSuccess || !run.Manual {
+		t.Fatalf("expected a successful manual run, got %+v", run)
+	}
+
+	body := <-bodies
+	img, err := base64.StdEncoding.DecodeString(body["_image"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(img)); err != nil {
+		t.Fatalf("expected the rendering of the dashboard, got %v", err)
+	}
+	if body["_report_name"] != "daily" || body["_dashboard_name"] != "hosts" {
+		t.Fatalf("unexpected report %v", body)
+	}
+
+	var runs struct {
+		Runs []influxdb.ReportRun `json:"runs"`
+	}
+	b = do("GET", fmt.Sprintf("/api/v2/reports/%s/runs", r.ID), "", nethttp.StatusOK)
+	if err := json.Unmarshal(b, &runs); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs.Runs) != 1 || runs.Runs[0].ID != run.ID {
+		t.Fatalf("expected the run to be recorded, got %+v", runs.Runs)
+	}
+}
@@ -0,0 +1,160 @@
+package launcher
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query/control"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ platform.RuntimeConfigService = (*runtimeConfigService)(nil)
+
+// runtimeConfigService applies runtime config changes to the running
+// components of the launcher and persists them as overrides of the
+// configured settings.
+type runtimeConfigService struct {
+	mu        sync.Mutex
+	base      platform.RuntimeConfig // The configured settings.
+	config    platform.RuntimeConfig // The configured settings with the overrides.
+	overrides platform.RuntimeConfigUpdate
+
+	store       platform.RuntimeConfigOverrideStore
+	level       zap.AtomicLevel
+	controller  *control.Controller
+	writeLimits *http.WriteLimits
+	engine      Engine
+	logger      *zap.Logger
+}
+
+// restore applies the stored overrides on top of the configured settings.
+// Overrides that are no longer valid, for instance because the query
+// concurrency quota was lowered, are logged and ignored.
+func (s *runtimeConfigService) restore(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides, err := s.store.FindRuntimeConfigOverrides(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.base = s.config
+	next := s.config
+	overrides.Apply(&next)
+	if err := next.Valid(); err != nil {
+		s.logger.Warn("Ignoring invalid runtime config overrides", zap.Error(err))
+		return s.apply(s.config)
+	}
+	if err := s.apply(next); err != nil {
+		s.logger.Warn("Ignoring runtime config overrides", zap.Error(err))
+		return s.apply(s.config)
+	}
+
+	s.config, s.overrides = next, overrides
+	return nil
+}
+
+// FindRuntimeConfig returns the runtime config currently in effect.
+func (s *runtimeConfigService) FindRuntimeConfig(ctx context.Context) (*platform.RuntimeConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.config
+	return &c, nil
+}
+
+// UpdateRuntimeConfig applies the update to the running components and
+// persists it so that it is re-applied at boot.
+func (s *runtimeConfigService) UpdateRuntimeConfig(ctx context.Context, upd platform.RuntimeConfigUpdate) (*platform.RuntimeConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.config
+	upd.Apply(&next)
+	if err := next.Valid(); err != nil {
+		return nil, err
+	}
+
+	// on failure, restore the previous config so that the running components
+	// stay consistent with the config we report.
+	if err := s.apply(next); err != nil {
+		_ = s.apply(s.config)
+		return nil, err
+	}
+	overrides := s.overrides.Merge(upd)
+	if err := s.store.PutRuntimeConfigOverrides(ctx, overrides); err != nil {
+		_ = s.apply(s.config)
+		return nil, err
+	}
+
+	s.config, s.overrides = next, overrides
+	c := next
+	return &c, nil
+}
+
+// ResetRuntimeConfig applies the configured settings to the running
+// components and removes the stored overrides.
+func (s *runtimeConfigService) ResetRuntimeConfig(ctx context.Context) (*platform.RuntimeConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.apply(s.base); err != nil {
+		_ = s.apply(s.config)
+		return nil, err
+	}
+	if err := s.store.PutRuntimeConfigOverrides(ctx, platform.RuntimeConfigUpdate{}); err != nil {
+		_ = s.apply(s.config)
+		return nil, err
+	}
+
+	s.config, s.overrides = s.base, platform.RuntimeConfigUpdate{}
+	c := s.config
+	return &c, nil
+}
+
+func (s *runtimeConfigService) apply(c platform.RuntimeConfig) error {
+	var lvl zapcore.Level
+	if err := lvl.Set(c.LogLevel); err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if err := s.controller.SetConcurrencyLimit(c.QueryConcurrency); err != nil {
+		return err
+	}
+	if err := s.engine.SetCompactionThroughput(int(c.CompactionThroughput), int(c.CompactionThroughputBurst)); err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	s.writeLimits.SetMaxBodyBytes(c.WriteMaxBodyBytes)
+	s.level.SetLevel(lvl)
+	return nil
+}
+
+// reconfigure changes the configured settings, e.g. when the config file is
+// reloaded, and applies them with the overrides on top.
+func (s *runtimeConfigService) reconfigure(update func(*platform.RuntimeConfig)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base := s.base
+	update(&base)
+	next := base
+	s.overrides.Apply(&next)
+	if err := next.Valid(); err != nil {
+		return err
+	}
+	if err := s.apply(next); err != nil {
+		_ = s.apply(s.config)
+		return err
+	}
+	s.base, s.config = base, next
+	return nil
+}
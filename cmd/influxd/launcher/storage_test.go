@@ -1,6 +1,7 @@
 package launcher_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
@@ -157,6 +158,154 @@ func TestLauncher_BucketDelete(t *testing.T) {
 	}
 }
 
+func TestLauncher_BucketSchema(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `cpu,host=a usage_idle=1,usage_user=2 946684800000000000
+cpu,host=b usage_idle=1 946684800000000000
+mem,host=c used=3i 946684800000000000`)
+
+	get := func(path string, v interface{}) {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", fmt.Sprintf("/api/v2/buckets/%s/%s", l.Bucket.ID, path), ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", path, resp.StatusCode, body)
+		}
+		if err := json.Unmarshal(body, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ms struct {
+		Measurements []string `json:"measurements"`
+	}
+	get("measurements", &ms)
+	if diff := cmp.Diff([]string{"cpu", "mem"}, ms.Measurements); diff != "" {
+		t.Errorf("unexpected measurements: %s", diff)
+	}
+
+	var fs struct {
+		Fields []string `json:"fields"`
+	}
+	get("fields?measurement=cpu", &fs)
+	if diff := cmp.Diff([]string{"usage_idle", "usage_user"}, fs.Fields); diff != "" {
+		t.Errorf("unexpected fields: %s", diff)
+	}
+
+	var vs struct {
+		Values []string `json:"values"`
+	}
+	get("tag-values?key=host", &vs)
+	if diff := cmp.Diff([]string{"a", "b", "c"}, vs.Values); diff != "" {
+		t.Errorf("unexpected tag values: %s", diff)
+	}
+	get("tag-values?key=host&measurement=mem", &vs)
+	if diff := cmp.Diff([]string{"c"}, vs.Values); diff != "" {
+		t.Errorf("unexpected tag values of mem: %s", diff)
+	}
+}
+
+func TestLauncher_MeasurementLastWrites(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, "--measurement-last-write-interval", "10ms")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `cpu,host=a usage_idle=1 946684800000000000
+mem,host=c used=3i 946684800000000000`)
+
+	var ms struct {
+		Measurements []string              `json:"measurements"`
+		LastWrites   map[string]*time.Time `json:"lastWrites"`
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", fmt.Sprintf("/api/v2/buckets/%s/measurements?withLastWrite=true", l.Bucket.ID), ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("GET measurements returned %d: %s", resp.StatusCode, body)
+		}
+		if err := json.Unmarshal(body, &ms); err != nil {
+			t.Fatal(err)
+		}
+		if ms.LastWrites["cpu"] != nil && ms.LastWrites["mem"] != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the last writes of the measurements to be recorded, got %s", body)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the last write is the time the points were written, not their timestamp.
+	if since := time.Since(*ms.LastWrites["cpu"]); since < 0 || since > time.Minute {
+		t.Errorf("expected the last write of cpu to be recent, got %v", ms.LastWrites["cpu"])
+	}
+}
+
+func TestLauncher_BucketSample(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `cpu,host=a usage_idle=1,usage_user=2 946684800000000000
+cpu,host=a usage_idle=3,usage_user=4 946684810000000000
+cpu,host=b usage_idle=5 946684820000000000
+mem,host=c used=3i 946684830000000000`)
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", fmt.Sprintf("/api/v2/buckets/%s/sample?measurement=cpu&n=2", l.Bucket.ID), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var sample struct {
+		Rows []*influxdb.BucketSampleRow `json:"rows"`
+	}
+	if err := json.Unmarshal(body, &sample); err != nil {
+		t.Fatal(err)
+	}
+	want := []*influxdb.BucketSampleRow{
+		{
+			Time:        time.Unix(0, 946684820000000000).UTC(),
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "b"},
+			Fields:      map[string]interface{}{"usage_idle": 5.0},
+		},
+		{
+			Time:        time.Unix(0, 946684810000000000).UTC(),
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "a"},
+			Fields:      map[string]interface{}{"usage_idle": 3.0, "usage_user": 4.0},
+		},
+	}
+	if diff := cmp.Diff(want, sample.Rows); diff != "" {
+		t.Errorf("unexpected sample: %s", diff)
+	}
+}
+
 func TestStorage_CacheSnapshot_Size(t *testing.T) {
 	l := launcher.NewTestLauncher()
 	l.StorageConfig.Engine.Cache.SnapshotMemorySize = 10
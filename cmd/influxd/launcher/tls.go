@@ -0,0 +1,151 @@
+package launcher
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// defaultTLSCertCheckInterval is how often the TLS certificate and key files
+// are checked for changes by default.
+const defaultTLSCertCheckInterval = time.Minute
+
+// keyPair is a TLS certificate read from its certificate and key files.
+type keyPair struct {
+	cert              tls.Certificate
+	certFile, keyFile string
+	modTimes          [2]time.Time // The modification times of the files when they were read.
+}
+
+func readKeyPair(certFile, keyFile string) (*keyPair, error) {
+	// The files are stat'ed first, so that changes made while they are read
+	// are loaded at the next check.
+	modTimes, err := fileModTimes(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{cert: cert, certFile: certFile, keyFile: keyFile, modTimes: modTimes}, nil
+}
+
+func fileModTimes(certFile, keyFile string) ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, path := range []string{certFile, keyFile} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = fi.ModTime()
+	}
+	return modTimes, nil
+}
+
+// certificate is the TLS certificate of the HTTP server. It is replaced when
+// its files change or the configuration is reloaded.
+type certificate struct {
+	mu sync.RWMutex
+	kp *keyPair
+}
+
+func loadCertificate(certFile, keyFile string) (*certificate, error) {
+	kp, err := readKeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate{kp: kp}, nil
+}
+
+func (c *certificate) set(kp *keyPair) {
+	c.mu.Lock()
+	c.kp = kp
+	c.mu.Unlock()
+}
+
+// GetCertificate returns the certificate, for tls.Config.
+func (c *certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &c.kp.cert, nil
+}
+
+// reloadIfChanged reads the files of the certificate again if they were
+// modified since they were read, and returns whether the certificate was
+// replaced. A certificate that fails to load, e.g. because only one of its
+// files was replaced yet, is kept until the next check.
+func (c *certificate) reloadIfChanged() (bool, error) {
+	c.mu.RLock()
+	kp := c.kp
+	c.mu.RUnlock()
+
+	modTimes, err := fileModTimes(kp.certFile, kp.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if modTimes[0].Equal(kp.modTimes[0]) && modTimes[1].Equal(kp.modTimes[1]) {
+		return false, nil
+	}
+	next, err := readKeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		return false, err
+	}
+	c.set(next)
+	return true, nil
+}
+
+// watch reloads the certificate when its files change until ctx is done.
+func (c *certificate) watch(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := c.reloadIfChanged()
+			if err != nil {
+				logger.Warn("Failed to reload TLS certificate", zap.Error(err))
+			} else if reloaded {
+				logger.Info("Reloaded TLS certificate")
+			}
+		}
+	}
+}
+
+// acmeConfig configures the provisioning of the TLS certificate of the HTTP
+// server from an ACME certificate authority such as Let's Encrypt.
+type acmeConfig struct {
+	domains      []string
+	email        string
+	cachePath    string
+	directoryURL string
+	// httpBindAddress is the address of the server answering HTTP-01
+	// challenges. Without it, the authority must be able to reach the API
+	// on port 443 for TLS-ALPN-01 challenges.
+	httpBindAddress string
+}
+
+func (c acmeConfig) enabled() bool {
+	return len(c.domains) > 0
+}
+
+// manager returns the manager obtaining and renewing the certificates of the
+// domains. The terms of service of the authority are accepted.
+func (c acmeConfig) manager() *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(c.domains...),
+		Cache:      autocert.DirCache(c.cachePath),
+		Email:      c.email,
+		Client:     &acme.Client{DirectoryURL: c.directoryURL},
+	}
+}
@@ -0,0 +1,113 @@
+package launcher_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+// writeCertificate writes a self-signed certificate with the serial number
+// and its key to the files.
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLauncher_TLSCertificateReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := filepath.Join(dir, "influxd.crt"), filepath.Join(dir, "influxd.key")
+	writeCertificate(t, certFile, keyFile, 1)
+
+	l := launcher.RunTestLauncherOrFail(t, ctx,
+		"--tls-cert", certFile,
+		"--tls-key", keyFile,
+		"--tls-cert-check-interval", "10ms",
+	)
+	defer l.ShutdownOrFail(t, ctx)
+
+	servedSerial := func() int64 {
+		t.Helper()
+		conn, err := tls.Dial("tcp", strings.TrimPrefix(l.URL(), "http://"), &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	if got := servedSerial(); got != 1 {
+		t.Fatalf("unexpected certificate served: %d", got)
+	}
+
+	// The modification times of the rewritten files may be the same at a
+	// coarse resolution.
+	writeCertificate(t, certFile, keyFile, 2)
+	later := time.Now().Add(time.Minute)
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for servedSerial() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the rewritten certificate was not served")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLauncher_TLSACMEWithCertificate(t *testing.T) {
+	l := launcher.NewTestLauncher()
+	defer os.RemoveAll(l.Path)
+
+	err := l.Run(ctx, "--tls-acme-domains", "influxdb.example.com", "--tls-cert", "influxd.crt", "--tls-key", "influxd.key")
+	if err == nil {
+		l.ShutdownOrFail(t, ctx)
+		t.Fatal("expected an error using ACME together with a certificate")
+	}
+}
@@ -0,0 +1,130 @@
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// shardConverter rewrites 1.x TSM files into TSM files of a 2.x engine.
+// Every converted file is written as a new generation of the engine.
+type shardConverter struct {
+	dataDir    string // dataDir is the TSM directory of the 2.x engine
+	generation int    // generation is the last generation written
+}
+
+// convertShard converts the TSM files of a 1.x shard directory into the
+// bucket with the encoded organization and bucket name.
+func (c *shardConverter) convertShard(dir string, name []byte) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := c.convertFile(f, name); err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+	}
+	return nil
+}
+
+// convertFile writes the blocks of a 1.x TSM file to a new TSM file with
+// the keys converted to 2.x keys. Blocks are copied without decoding unless
+// points of the key have been deleted.
+func (c *shardConverter) convertFile(path string, name []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Converted keys sort differently than the 1.x keys, so all keys of the
+	// file are converted before any block is written.
+	type keyPair struct{ v1, v2 []byte }
+	var keys []keyPair
+	itr := r.Iterator(nil)
+	for itr.Next() {
+		v1 := append([]byte(nil), itr.Key()...)
+		keys = append(keys, keyPair{v1: v1, v2: convertKey(name, v1)})
+	}
+	if err := itr.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i].v2, keys[j].v2) < 0 })
+
+	c.generation++
+	dst := filepath.Join(c.dataDir, tsm1.DefaultFormatFileName(c.generation, 1)+"."+tsm1.TSMFileExtension)
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w, err := tsm1.NewTSMWriter(out)
+	if err != nil {
+		return err
+	}
+
+	var (
+		entries    []tsm1.IndexEntry
+		tombstones []tsm1.TimeRange
+	)
+	for _, k := range keys {
+		if tombstones = r.TombstoneRange(k.v1, tombstones[:0]); len(tombstones) > 0 {
+			values, err := r.ReadAll(k.v1)
+			if err != nil {
+				return err
+			}
+			if err := w.Write(k.v2, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if entries, err = r.ReadEntries(k.v1, entries[:0]); err != nil {
+			return err
+		}
+		for i := range entries {
+			_, block, err := r.ReadBytes(&entries[i], nil)
+			if err != nil {
+				return err
+			}
+			if err := w.WriteBlock(k.v2, entries[i].MinTime, entries[i].MaxTime, block); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// convertKey returns the 2.x key of a 1.x TSM key. The measurement and
+// field of the 1.x key become tags of the series in the bucket with the
+// encoded organization and bucket name.
+func convertKey(name, key []byte) []byte {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	measurement, tags := models.ParseKeyBytes(seriesKey)
+
+	tags = append(tags, models.NewTag(models.MeasurementTagKeyBytes, measurement))
+	tags = append(tags, models.NewTag(models.FieldKeyTagKeyBytes, field))
+	sort.Sort(tags)
+
+	return tsm1.SeriesFieldKeyBytes(string(models.MakeKey(name, tags)), string(field))
+}
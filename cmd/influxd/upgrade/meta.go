@@ -0,0 +1,254 @@
+package upgrade
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+var errInvalidProtobuf = errors.New("invalid protobuf message")
+
+// Privileges of 1.x users on a database.
+const (
+	v1NoPrivileges   = 0
+	v1ReadPrivilege  = 1
+	v1WritePrivilege = 2
+	v1AllPrivileges  = 3
+)
+
+// v1Meta is the part of a 1.x meta store needed for an upgrade.
+type v1Meta struct {
+	Databases []v1Database
+	Users     []v1User
+}
+
+type v1Database struct {
+	Name                   string
+	DefaultRetentionPolicy string
+	RetentionPolicies      []v1RetentionPolicy
+}
+
+type v1RetentionPolicy struct {
+	Name        string
+	Duration    time.Duration
+	ShardGroups []v1ShardGroup
+}
+
+type v1ShardGroup struct {
+	ID        uint64
+	DeletedAt int64
+	Shards    []uint64
+}
+
+type v1User struct {
+	Name       string
+	Admin      bool
+	Privileges map[string]int // Privileges maps databases to privileges.
+}
+
+// Field numbers of the 1.x meta store protobuf messages.
+const (
+	dataDatabasesField = 5
+	dataUsersField     = 6
+
+	databaseNameField              = 1
+	databaseDefaultRPField         = 2
+	databaseRetentionPoliciesField = 3
+
+	rpNameField        = 1
+	rpDurationField    = 2
+	rpShardGroupsField = 5
+
+	shardGroupIDField        = 1
+	shardGroupDeletedAtField = 4
+	shardGroupShardsField    = 5
+
+	shardIDField = 1
+
+	userNameField       = 1
+	userAdminField      = 3
+	userPrivilegesField = 4
+
+	privilegeDatabaseField  = 1
+	privilegePrivilegeField = 2
+)
+
+// readV1Meta reads the meta store of a 1.x server from the meta.db file.
+func readV1Meta(path string) (*v1Meta, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := decodeV1Meta(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 1.x meta store %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// decodeV1Meta decodes the protobuf encoded data of a 1.x meta store. Only
+// the fields needed for an upgrade are decoded.
+func decodeV1Meta(b []byte) (*v1Meta, error) {
+	m := &v1Meta{}
+	err := decodeMessage(b, func(field int, v uint64, data []byte) error {
+		switch field {
+		case dataDatabasesField:
+			db, err := decodeV1Database(data)
+			if err != nil {
+				return err
+			}
+			m.Databases = append(m.Databases, db)
+		case dataUsersField:
+			u, err := decodeV1User(data)
+			if err != nil {
+				return err
+			}
+			m.Users = append(m.Users, u)
+		}
+		return nil
+	})
+	return m, err
+}
+
+func decodeV1Database(b []byte) (v1Database, error) {
+	var db v1Database
+	err := decodeMessage(b, func(field int, v uint64, data []byte) error {
+		switch field {
+		case databaseNameField:
+			db.Name = string(data)
+		case databaseDefaultRPField:
+			db.DefaultRetentionPolicy = string(data)
+		case databaseRetentionPoliciesField:
+			rp, err := decodeV1RetentionPolicy(data)
+			if err != nil {
+				return err
+			}
+			db.RetentionPolicies = append(db.RetentionPolicies, rp)
+		}
+		return nil
+	})
+	return db, err
+}
+
+func decodeV1RetentionPolicy(b []byte) (v1RetentionPolicy, error) {
+	var rp v1RetentionPolicy
+	err := decodeMessage(b, func(field int, v uint64, data []byte) error {
+		switch field {
+		case rpNameField:
+			rp.Name = string(data)
+		case rpDurationField:
+			rp.Duration = time.Duration(int64(v))
+		case rpShardGroupsField:
+			sg, err := decodeV1ShardGroup(data)
+			if err != nil {
+				return err
+			}
+			rp.ShardGroups = append(rp.ShardGroups, sg)
+		}
+		return nil
+	})
+	return rp, err
+}
+
+func decodeV1ShardGroup(b []byte) (v1ShardGroup, error) {
+	var sg v1ShardGroup
+	err := decodeMessage(b, func(field int, v uint64, data []byte) error {
+		switch field {
+		case shardGroupIDField:
+			sg.ID = v
+		case shardGroupDeletedAtField:
+			sg.DeletedAt = int64(v)
+		case shardGroupShardsField:
+			return decodeMessage(data, func(field int, v uint64, data []byte) error {
+				if field == shardIDField {
+					sg.Shards = append(sg.Shards, v)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return sg, err
+}
+
+func decodeV1User(b []byte) (v1User, error) {
+	u := v1User{Privileges: make(map[string]int)}
+	err := decodeMessage(b, func(field int, v uint64, data []byte) error {
+		switch field {
+		case userNameField:
+			u.Name = string(data)
+		case userAdminField:
+			u.Admin = v != 0
+		case userPrivilegesField:
+			var (
+				db string
+				p  int
+			)
+			err := decodeMessage(data, func(field int, v uint64, data []byte) error {
+				switch field {
+				case privilegeDatabaseField:
+					db = string(data)
+				case privilegePrivilegeField:
+					p = int(int32(v))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			u.Privileges[db] = p
+		}
+		return nil
+	})
+	return u, err
+}
+
+// decodeMessage calls fn with every field of the protobuf message in b.
+// Varint and fixed size values are passed as v, and length delimited values
+// as data.
+func decodeMessage(b []byte, fn func(field int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errInvalidProtobuf
+		}
+		b = b[n:]
+
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire := key & 7; wire {
+		case 0: // varint
+			if v, n = binary.Uvarint(b); n <= 0 {
+				return errInvalidProtobuf
+			}
+			b = b[n:]
+		case 1: // fixed64
+			if len(b) < 8 {
+				return errInvalidProtobuf
+			}
+			v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case 2: // length delimited
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errInvalidProtobuf
+			}
+			data, b = b[n:n+int(l)], b[n+int(l):]
+		case 5: // fixed32
+			if len(b) < 4 {
+				return errInvalidProtobuf
+			}
+			v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wire)
+		}
+
+		if err := fn(int(key>>3), v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
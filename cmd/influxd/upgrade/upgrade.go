@@ -0,0 +1,401 @@
+// Package upgrade implements the influxd upgrade command, which upgrades the
+// data and meta store of an InfluxDB 1.x server to InfluxDB 2.x.
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// options are the settings of an upgrade.
+type options struct {
+	V1Dir string // V1Dir is the directory of the 1.x server, holding the meta, data and wal directories
+
+	BoltPath   string // BoltPath is the path of the 2.x bolt database
+	EnginePath string // EnginePath is the path of the 2.x storage engine
+
+	Username string
+	Password string
+	Org      string
+	Bucket   string // Bucket is the name of the bucket created by the initial setup
+	Token    string
+
+	TokensPath string // TokensPath is the file the tokens of the 1.x users are written to
+	IgnoreWAL  bool   // IgnoreWAL allows upgrading while 1.x WAL files hold unconverted points
+
+	Stdout io.Writer
+}
+
+var upgradeFlags options
+
+// NewCommand returns the influxd upgrade command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the data and meta store of an InfluxDB 1.x server",
+		Long: `This command upgrades a stopped InfluxDB 1.x server to InfluxDB 2.x.
+
+It reads the 1.x meta store and sets up a new 2.x instance with the given
+user and organization. Every database and retention policy of the 1.x server
+becomes a bucket named <database>/<retention policy> with the duration of the
+retention policy, and a DBRP mapping from the database and retention policy
+to the bucket is created. The TSM files of the 1.x shards are converted into
+the 2.x storage engine and its index is built.
+
+1.x users become members of the organization, or owners if they are admins.
+Passwords cannot be upgraded, so every 1.x user with privileges gets a token
+with read and write permissions on the buckets of the databases it has
+privileges on. The tokens are written to the file given by --tokens-path.
+
+The 1.x server must be stopped and its WAL flushed to TSM files; points that
+are only in the WAL are not upgraded. The 2.x instance must not have been set
+up yet. If the upgrade fails, remove the 2.x bolt file and engine directory
+before running it again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := logger.New(os.Stdout)
+			return upgrade(context.Background(), &upgradeFlags, log)
+		},
+	}
+
+	home := os.Getenv("HOME")
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().StringVar(&upgradeFlags.V1Dir, "v1-dir", filepath.Join(home, ".influxdb"), "path to the directory of the 1.x server")
+	cmd.Flags().StringVar(&upgradeFlags.BoltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to the 2.x bolt database")
+	cmd.Flags().StringVar(&upgradeFlags.EnginePath, "engine-path", filepath.Join(dir, "engine"), "path to the 2.x storage engine")
+	cmd.Flags().StringVarP(&upgradeFlags.Username, "username", "u", "", "name of the initial 2.x user (required)")
+	cmd.Flags().StringVarP(&upgradeFlags.Password, "password", "p", "", "password of the initial 2.x user (required)")
+	cmd.Flags().StringVarP(&upgradeFlags.Org, "org", "o", "", "name of the 2.x organization (required)")
+	cmd.Flags().StringVarP(&upgradeFlags.Bucket, "bucket", "b", "", "name of the bucket created by the initial setup; defaults to the bucket of the default retention policy of the first database")
+	cmd.Flags().StringVarP(&upgradeFlags.Token, "token", "t", "", "token of the initial 2.x user; generated if empty")
+	cmd.Flags().StringVar(&upgradeFlags.TokensPath, "tokens-path", filepath.Join(dir, "v1-tokens.json"), "path of the file the tokens of the 1.x users are written to")
+	cmd.Flags().BoolVar(&upgradeFlags.IgnoreWAL, "ignore-wal", false, "upgrade even though 1.x WAL files hold points that are not upgraded")
+
+	return cmd
+}
+
+// upgradeBucket is a 2.x bucket for a 1.x database and retention policy.
+type upgradeBucket struct {
+	db     v1Database
+	rp     v1RetentionPolicy
+	bucket *influxdb.Bucket
+}
+
+func upgrade(ctx context.Context, opts *options, log *zap.Logger) error {
+	if opts.Username == "" || opts.Password == "" || opts.Org == "" {
+		return errors.New("username, password and org are required")
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+
+	meta, err := readV1Meta(filepath.Join(opts.V1Dir, "meta", "meta.db"))
+	if err != nil {
+		return err
+	}
+	if !opts.IgnoreWAL {
+		if err := checkV1WAL(filepath.Join(opts.V1Dir, "wal")); err != nil {
+			return err
+		}
+	}
+
+	dataDir := filepath.Join(opts.EnginePath, storage.DefaultEngineDirectoryName)
+	if err := checkEmptyDir(dataDir); err != nil {
+		return err
+	}
+
+	store := bolt.NewKVStore(opts.BoltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		return err
+	}
+	if onboarding, err := svc.IsOnboarding(ctx); err != nil {
+		return err
+	} else if !onboarding {
+		return errors.New("the 2.x instance has already been set up")
+	}
+
+	var buckets []*upgradeBucket
+	for _, db := range meta.Databases {
+		// Names starting with an underscore are reserved for system buckets,
+		// which includes the _internal database of 1.x.
+		if strings.HasPrefix(db.Name, "_") {
+			log.Info("Skipping database with reserved name", zap.String("database", db.Name))
+			continue
+		}
+		for _, rp := range db.RetentionPolicies {
+			buckets = append(buckets, &upgradeBucket{db: db, rp: rp})
+		}
+	}
+
+	// The initial setup creates the bucket of the default retention policy
+	// of the first database unless another bucket is requested.
+	bucketName := opts.Bucket
+	if bucketName == "" {
+		bucketName = "default"
+		for _, b := range buckets {
+			if b.rp.Name == b.db.DefaultRetentionPolicy {
+				bucketName = bucketNameOf(b.db, b.rp)
+				break
+			}
+		}
+	}
+
+	res, err := svc.Generate(ctx, &influxdb.OnboardingRequest{
+		User:     opts.Username,
+		Password: opts.Password,
+		Org:      opts.Org,
+		Bucket:   bucketName,
+		Token:    opts.Token,
+	})
+	if err != nil {
+		return err
+	}
+	orgID := res.Org.ID
+	log.Info("Set up 2.x instance", zap.String("user", res.User.Name), zap.String("org", res.Org.Name))
+
+	for _, b := range buckets {
+		name := bucketNameOf(b.db, b.rp)
+		if name == res.Bucket.Name {
+			b.bucket, err = svc.UpdateBucket(ctx, res.Bucket.ID, influxdb.BucketUpdate{RetentionPeriod: &b.rp.Duration})
+		} else {
+			b.bucket = &influxdb.Bucket{
+				OrgID:           orgID,
+				Name:            name,
+				RetentionPeriod: b.rp.Duration,
+			}
+			err = svc.CreateBucket(ctx, b.bucket)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create bucket %q: %v", name, err)
+		}
+
+		if err := svc.CreateDBRPMapping(ctx, &influxdb.DBRPMappingV2{
+			Database:        b.db.Name,
+			RetentionPolicy: b.rp.Name,
+			Default:         b.rp.Name == b.db.DefaultRetentionPolicy,
+			OrganizationID:  orgID,
+			BucketID:        b.bucket.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to create dbrp mapping for bucket %q: %v", name, err)
+		}
+		log.Info("Created bucket", zap.String("bucket", name), zap.Stringer("id", b.bucket.ID), zap.Duration("retention", b.rp.Duration))
+	}
+
+	if err := upgradeData(opts, buckets, log); err != nil {
+		return err
+	}
+	if err := upgradeUsers(ctx, svc, opts, meta.Users, orgID, buckets, log); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.Stdout, "Upgrade complete. The token of user %s is %s\n", res.User.Name, res.Auth.Token)
+	return nil
+}
+
+func bucketNameOf(db v1Database, rp v1RetentionPolicy) string {
+	return db.Name + "/" + rp.Name
+}
+
+// upgradeData converts the TSM files of the shards of the 1.x buckets and
+// builds the index of the 2.x engine.
+func upgradeData(opts *options, buckets []*upgradeBucket, log *zap.Logger) error {
+	dataDir := filepath.Join(opts.EnginePath, storage.DefaultEngineDirectoryName)
+	if err := os.MkdirAll(dataDir, 0777); err != nil {
+		return err
+	}
+
+	c := &shardConverter{dataDir: dataDir}
+	for _, b := range buckets {
+		name := tsdb.EncodeName(b.bucket.OrgID, b.bucket.ID)
+		for _, sg := range b.rp.ShardGroups {
+			if sg.DeletedAt != 0 {
+				continue
+			}
+			for _, id := range sg.Shards {
+				dir := filepath.Join(opts.V1Dir, "data", b.db.Name, b.rp.Name, strconv.FormatUint(id, 10))
+				if _, err := os.Stat(dir); os.IsNotExist(err) {
+					continue
+				}
+				log.Info("Converting shard", zap.String("path", dir), zap.String("bucket", bucketNameOf(b.db, b.rp)))
+				if err := c.convertShard(dir, name[:]); err != nil {
+					return fmt.Errorf("failed to convert shard %d: %v", id, err)
+				}
+			}
+		}
+	}
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(opts.EnginePath, storage.DefaultSeriesFileDirectoryName))
+	sfile.Logger = log
+	if err := sfile.Open(context.Background()); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	return buildtsi.IndexShard(sfile,
+		filepath.Join(opts.EnginePath, storage.DefaultIndexDirectoryName),
+		dataDir,
+		filepath.Join(opts.EnginePath, storage.DefaultWALDirectoryName),
+		tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), 10000,
+		log, false)
+}
+
+// upgradeUsers creates the 1.x users in the organization and writes a token
+// for each of them to the tokens file.
+func upgradeUsers(ctx context.Context, svc *kv.Service, opts *options, users []v1User, orgID influxdb.ID, buckets []*upgradeBucket, log *zap.Logger) error {
+	tokens := make(map[string]string)
+	for _, u1 := range users {
+		if u1.Name == opts.Username {
+			log.Info("Skipping user with the name of the initial user", zap.String("user", u1.Name))
+			continue
+		}
+
+		u := &influxdb.User{Name: u1.Name}
+		if err := svc.CreateUser(ctx, u); err != nil {
+			return fmt.Errorf("failed to create user %q: %v", u1.Name, err)
+		}
+
+		userType := influxdb.Member
+		if u1.Admin {
+			userType = influxdb.Owner
+		}
+		if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			UserID:       u.ID,
+			UserType:     userType,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   orgID,
+		}); err != nil {
+			return fmt.Errorf("failed to add user %q to organization: %v", u1.Name, err)
+		}
+
+		perms, err := v1UserPermissions(u1, orgID, buckets)
+		if err != nil {
+			return err
+		}
+		if len(perms) == 0 {
+			continue
+		}
+		a := &influxdb.Authorization{
+			Description: fmt.Sprintf("%s's Token", u1.Name),
+			OrgID:       orgID,
+			UserID:      u.ID,
+			Permissions: perms,
+		}
+		if err := svc.CreateAuthorization(ctx, a); err != nil {
+			return fmt.Errorf("failed to create token of user %q: %v", u1.Name, err)
+		}
+		tokens[u1.Name] = a.Token
+		log.Info("Created user", zap.String("user", u1.Name), zap.String("type", string(userType)))
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+	b, err := json.MarshalIndent(tokens, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(opts.TokensPath, b, 0600); err != nil {
+		return fmt.Errorf("failed to write tokens of 1.x users: %v", err)
+	}
+	fmt.Fprintf(opts.Stdout, "The tokens of the 1.x users have been written to %s\n", opts.TokensPath)
+	return nil
+}
+
+// v1UserPermissions returns the permissions of a 1.x user. Admins get all
+// permissions of the organization, and other users read and write
+// permissions on the buckets of the databases they have privileges on.
+func v1UserPermissions(u v1User, orgID influxdb.ID, buckets []*upgradeBucket) ([]influxdb.Permission, error) {
+	if u.Admin {
+		return influxdb.OwnerPermissions(orgID), nil
+	}
+
+	var perms []influxdb.Permission
+	for _, b := range buckets {
+		p := u.Privileges[b.db.Name]
+		var actions []influxdb.Action
+		if p == v1ReadPrivilege || p == v1AllPrivileges {
+			actions = append(actions, influxdb.ReadAction)
+		}
+		if p == v1WritePrivilege || p == v1AllPrivileges {
+			actions = append(actions, influxdb.WriteAction)
+		}
+		for _, a := range actions {
+			perm, err := influxdb.NewPermissionAtID(b.bucket.ID, a, influxdb.BucketsResourceType, orgID)
+			if err != nil {
+				return nil, err
+			}
+			perms = append(perms, *perm)
+		}
+	}
+	return perms, nil
+}
+
+// checkV1WAL returns an error if any 1.x WAL file holds points.
+func checkV1WAL(dir string) error {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() && filepath.Ext(path) == ".wal" && info.Size() > 0 {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return fmt.Errorf("%d 1.x WAL files hold points that would not be upgraded, e.g. %s; "+
+			"let the 1.x server flush its WAL before stopping it, or use --ignore-wal", len(files), files[0])
+	}
+	return nil
+}
+
+// checkEmptyDir returns an error if dir exists and is not empty.
+func checkEmptyDir(dir string) error {
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if len(fis) > 0 {
+		return fmt.Errorf("the 2.x engine directory %s is not empty", dir)
+	}
+	return nil
+}
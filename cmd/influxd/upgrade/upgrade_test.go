@@ -0,0 +1,291 @@
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap/zaptest"
+)
+
+// protoMessage encodes protobuf messages for tests.
+type protoMessage []byte
+
+func (m protoMessage) varint(field int, v uint64) protoMessage {
+	m = appendUvarint(m, uint64(field<<3))
+	return appendUvarint(m, v)
+}
+
+func (m protoMessage) bytes(field int, b []byte) protoMessage {
+	m = appendUvarint(m, uint64(field<<3|2))
+	m = appendUvarint(m, uint64(len(b)))
+	return append(m, b...)
+}
+
+func (m protoMessage) string(field int, s string) protoMessage {
+	return m.bytes(field, []byte(s))
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func testV1Meta() []byte {
+	shard := protoMessage{}.varint(shardIDField, 1)
+	sg := protoMessage{}.
+		varint(shardGroupIDField, 1).
+		varint(shardGroupDeletedAtField, 0).
+		bytes(shardGroupShardsField, shard)
+	deleted := protoMessage{}.
+		varint(shardGroupIDField, 2).
+		varint(shardGroupDeletedAtField, 100).
+		bytes(shardGroupShardsField, protoMessage{}.varint(shardIDField, 2))
+	autogen := protoMessage{}.
+		string(rpNameField, "autogen").
+		varint(rpDurationField, 0).
+		bytes(rpShardGroupsField, sg).
+		bytes(rpShardGroupsField, deleted)
+	week := protoMessage{}.
+		string(rpNameField, "week").
+		varint(rpDurationField, uint64(7*24*time.Hour))
+	db := protoMessage{}.
+		string(databaseNameField, "db0").
+		string(databaseDefaultRPField, "autogen").
+		bytes(databaseRetentionPoliciesField, autogen).
+		bytes(databaseRetentionPoliciesField, week)
+	internal := protoMessage{}.
+		string(databaseNameField, "_internal").
+		string(databaseDefaultRPField, "monitor").
+		bytes(databaseRetentionPoliciesField, protoMessage{}.string(rpNameField, "monitor"))
+	reader := protoMessage{}.
+		string(userNameField, "reader").
+		string(2, "hash").
+		varint(userAdminField, 0).
+		bytes(userPrivilegesField, protoMessage{}.string(privilegeDatabaseField, "db0").varint(privilegePrivilegeField, v1ReadPrivilege))
+	admin := protoMessage{}.
+		string(userNameField, "admin").
+		varint(userAdminField, 1)
+
+	return protoMessage{}.
+		varint(1, 1).
+		bytes(dataDatabasesField, db).
+		bytes(dataDatabasesField, internal).
+		bytes(dataUsersField, reader).
+		bytes(dataUsersField, admin)
+}
+
+func TestDecodeV1Meta(t *testing.T) {
+	m, err := decodeV1Meta(testV1Meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Databases) != 2 || len(m.Users) != 2 {
+		t.Fatalf("unexpected meta %+v", m)
+	}
+	db := m.Databases[0]
+	if db.Name != "db0" || db.DefaultRetentionPolicy != "autogen" || len(db.RetentionPolicies) != 2 {
+		t.Fatalf("unexpected database %+v", db)
+	}
+	if rp := db.RetentionPolicies[1]; rp.Name != "week" || rp.Duration != 7*24*time.Hour {
+		t.Fatalf("unexpected retention policy %+v", rp)
+	}
+	if sgs := db.RetentionPolicies[0].ShardGroups; len(sgs) != 2 || sgs[0].Shards[0] != 1 || sgs[1].DeletedAt != 100 {
+		t.Fatalf("unexpected shard groups %+v", sgs)
+	}
+	if u := m.Users[0]; u.Name != "reader" || u.Admin || u.Privileges["db0"] != v1ReadPrivilege {
+		t.Fatalf("unexpected user %+v", u)
+	}
+	if u := m.Users[1]; u.Name != "admin" || !u.Admin {
+		t.Fatalf("unexpected user %+v", u)
+	}
+
+	if _, err := decodeV1Meta([]byte{0x2a, 0x10}); err == nil {
+		t.Fatal("expected an error for a truncated message")
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	v1Dir := filepath.Join(dir, "v1")
+	mustWriteFile(t, filepath.Join(v1Dir, "meta", "meta.db"), testV1Meta())
+	mustWriteTSM(t, filepath.Join(v1Dir, "data", "db0", "autogen", "1", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"cpu,host=a#!~#usage": {tsm1.NewValue(10, 1.5), tsm1.NewValue(20, 2.5)},
+		"mem#!~#free":         {tsm1.NewValue(10, int64(7))},
+	})
+
+	opts := &options{
+		V1Dir:      v1Dir,
+		BoltPath:   filepath.Join(dir, "influxd.bolt"),
+		EnginePath: filepath.Join(dir, "engine"),
+		Username:   "me",
+		Password:   "password",
+		Org:        "org",
+		TokensPath: filepath.Join(dir, "tokens.json"),
+		Stdout:     ioutil.Discard,
+	}
+	ctx := context.Background()
+	if err := upgrade(ctx, opts, zaptest.NewLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	store := bolt.NewKVStore(opts.BoltPath)
+	if err := store.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	svc := kv.NewService(store)
+
+	buckets, _, err := svc.FindBuckets(ctx, influxdb.BucketFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retentions := make(map[string]time.Duration)
+	for _, b := range buckets {
+		if b.Type == influxdb.BucketTypeUser {
+			retentions[b.Name] = b.RetentionPeriod
+		}
+	}
+	if len(retentions) != 2 || retentions["db0/autogen"] != 0 || retentions["db0/week"] != 7*24*time.Hour {
+		t.Fatalf("unexpected buckets %v", retentions)
+	}
+
+	db := "db0"
+	ms, _, err := svc.FindDBRPMappings(ctx, influxdb.DBRPMappingFilterV2{Database: &db})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m *influxdb.DBRPMappingV2
+	defaults := make(map[string]bool)
+	for _, dbrp := range ms {
+		defaults[dbrp.RetentionPolicy] = dbrp.Default
+		if dbrp.Default {
+			m = dbrp
+		}
+	}
+	if len(defaults) != 2 || !defaults["autogen"] || defaults["week"] {
+		t.Fatalf("unexpected dbrp mappings %v", defaults)
+	}
+
+	// The converted data of the shard is in the bucket of the mapping.
+	files, err := filepath.Glob(filepath.Join(opts.EnginePath, "data", "*.tsm"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 1 {
+		t.Fatalf("expected 1 converted TSM file, got %v", files)
+	}
+	var buf bytes.Buffer
+	e := tsm1.NewLineProtocolExporter(&buf)
+	e.BucketID = &m.BucketID
+	if err := e.ExportFile(files[0]); err != nil {
+		t.Fatal(err)
+	} else if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "cpu,host=a usage=1.5 10\ncpu,host=a usage=2.5 20\nmem free=7i 10\n"; got != want {
+		t.Fatalf("unexpected converted data:\ngot=%s\nwant=%s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(opts.EnginePath, "index")); err != nil {
+		t.Fatalf("expected index to be built: %v", err)
+	}
+
+	var tokens map[string]string
+	b, err := ioutil.ReadFile(opts.TokensPath)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(b, &tokens); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := svc.FindAuthorizationByToken(ctx, tokens["reader"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(auth.Permissions) != 2 {
+		t.Fatalf("expected read permissions on 2 buckets, got %v", auth.Permissions)
+	}
+	for _, p := range auth.Permissions {
+		if p.Action != influxdb.ReadAction {
+			t.Fatalf("unexpected permission %v", p)
+		}
+	}
+	if _, ok := tokens["admin"]; !ok {
+		t.Fatalf("expected token of admin, got %v", tokens)
+	}
+
+	// Upgrading an instance that has been set up fails.
+	if err := upgrade(ctx, opts, zaptest.NewLogger(t)); err == nil {
+		t.Fatal("expected second upgrade to fail")
+	}
+}
+
+func TestUpgrade_WAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mustWriteFile(t, filepath.Join(dir, "wal", "db0", "autogen", "1", "_00001.wal"), []byte("x"))
+	if err := checkV1WAL(filepath.Join(dir, "wal")); err == nil {
+		t.Fatal("expected an error for a WAL file with points")
+	}
+	if err := checkV1WAL(filepath.Join(dir, "missing")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustWriteFile(t *testing.T, path string, b []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b, 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustWriteTSM(t *testing.T, path string, values map[string][]tsm1.Value) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := w.Write([]byte(k), values[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
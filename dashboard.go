@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/influxdata/flux/ast"
 )
 
 // ErrDashboardNotFound is the error msg for a missing dashboard.
@@ -79,6 +83,7 @@ type Dashboard struct {
 	Description    string        `json:"description"`
 	Cells          []*Cell       `json:"cells"`
 	Meta           DashboardMeta `json:"meta"`
+	DashboardSettings
 }
 
 // DashboardMeta contains meta information about dashboards
@@ -124,6 +129,9 @@ func SortDashboards(opts FindOptions, ds []*Dashboard) {
 type Cell struct {
 	ID ID `json:"id,omitempty"`
 	CellProperty
+	// DashboardSettings are the settings of the cell that override those of
+	// its dashboard.
+	DashboardSettings
 }
 
 // CellProperty contains the properties of a cell.
@@ -134,6 +142,206 @@ type CellProperty struct {
 	H int32 `json:"h"`
 }
 
+// DashboardSettings are the settings a dashboard opens with, stored with the
+// dashboard so that it opens the same for every viewer. The settings of a
+// cell override those of its dashboard; a cell inherits the settings it does
+// not set from its dashboard.
+type DashboardSettings struct {
+	// RefreshInterval is the interval the queries are refreshed at. A zero
+	// interval disables the refresh.
+	RefreshInterval *Duration `json:"refreshInterval,omitempty"`
+	// TimeRange is the default time range of the queries.
+	TimeRange *DashboardTimeRange `json:"timeRange,omitempty"`
+	// Timezone is the IANA name of the timezone times are displayed in,
+	// e.g. UTC or America/New_York.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Valid returns an error if the settings are invalid.
+func (s DashboardSettings) Valid() error {
+	if s.RefreshInterval != nil && s.RefreshInterval.Duration < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "refresh interval must not be negative",
+		}
+	}
+
+	if s.TimeRange != nil {
+		if err := s.TimeRange.Valid(); err != nil {
+			return err
+		}
+	}
+
+	if s.Timezone != "" {
+		if err := validTimezone(s.Timezone); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validTimezone returns an error if the name is not the IANA name of a
+// timezone.
+func validTimezone(name string) error {
+	// Local is the timezone of the server, not of the viewers.
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown timezone %q", name),
+		}
+	}
+	return nil
+}
+
+// DashboardTimeRange is a time range whose bounds are either durations
+// relative to now, e.g. -1h, or RFC3339 times.
+type DashboardTimeRange struct {
+	Start string `json:"start"`
+	// Stop is now if empty.
+	Stop string `json:"stop,omitempty"`
+}
+
+// Valid returns an error if the time range is invalid.
+func (r DashboardTimeRange) Valid() error {
+	if r.Start == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "time range requires a start",
+		}
+	}
+
+	start, err := parseDashboardTimeRangeBound(r.Start)
+	if err != nil {
+		return err
+	}
+	if r.Stop == "" {
+		return nil
+	}
+	stop, err := parseDashboardTimeRangeBound(r.Stop)
+	if err != nil {
+		return err
+	}
+
+	if !start.IsZero() && !stop.IsZero() && !start.Before(stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "time range start must be before its stop",
+		}
+	}
+	return nil
+}
+
+// Bounds returns the start and stop of the time range at now.
+func (r DashboardTimeRange) Bounds(now time.Time) (start, stop time.Time, err error) {
+	if err := r.Valid(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	start = dashboardTimeRangeBoundAt(r.Start, now)
+	stop = now
+	if r.Stop != "" {
+		stop = dashboardTimeRangeBoundAt(r.Stop, now)
+	}
+	if !start.Before(stop) {
+		return time.Time{}, time.Time{}, &Error{
+			Code: EInvalid,
+			Msg:  "time range start must be before its stop",
+		}
+	}
+	return start, stop, nil
+}
+
+// dashboardTimeRangeBoundAt returns the time of a valid bound at now.
+func dashboardTimeRangeBoundAt(b string, now time.Time) time.Time {
+	if t, err := parseDashboardTimeRangeBound(b); err != nil || !t.IsZero() {
+		return t
+	}
+
+	lit := &ast.DurationLiteral{}
+	for _, m := range fluxDurationPartRegexp.FindAllStringSubmatch(b, -1) {
+		mag, _ := strconv.ParseInt(m[1], 10, 64)
+		lit.Values = append(lit.Values, ast.Duration{Magnitude: mag, Unit: m[2]})
+	}
+	d, _ := ast.DurationFrom(lit, now)
+	if strings.HasPrefix(b, "-") {
+		d = -d
+	}
+	return now.Add(d)
+}
+
+// parseDashboardTimeRangeBound returns the time of an RFC3339 bound, or the
+// zero time for a bound relative to now.
+func parseDashboardTimeRangeBound(b string) (time.Time, error) {
+	if fluxDurationRegexp.MatchString(b) {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, b)
+	if err != nil {
+		return time.Time{}, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("time range bound %q must be a duration relative to now or an RFC3339 time", b),
+		}
+	}
+	return t, nil
+}
+
+// the names of the dashboard settings, to unset them.
+const (
+	DashboardSettingRefreshInterval = "refreshInterval"
+	DashboardSettingTimeRange       = "timeRange"
+	DashboardSettingTimezone        = "timezone"
+)
+
+// DashboardSettingsUpdate is the patch structure for the settings of a
+// dashboard or a cell.
+type DashboardSettingsUpdate struct {
+	RefreshInterval *Duration           `json:"refreshInterval,omitempty"`
+	TimeRange       *DashboardTimeRange `json:"timeRange,omitempty"`
+	Timezone        *string             `json:"timezone,omitempty"`
+	// Unset are the names of the settings to unset, e.g. so that a cell
+	// inherits them from its dashboard again.
+	Unset []string `json:"unset,omitempty"`
+}
+
+func (u DashboardSettingsUpdate) isEmpty() bool {
+	return u.RefreshInterval == nil && u.TimeRange == nil && u.Timezone == nil && len(u.Unset) == 0
+}
+
+// Apply applies an update to settings and returns an error if the updated
+// settings are invalid.
+func (u DashboardSettingsUpdate) Apply(s *DashboardSettings) error {
+	for _, name := range u.Unset {
+		switch name {
+		case DashboardSettingRefreshInterval:
+			s.RefreshInterval = nil
+		case DashboardSettingTimeRange:
+			s.TimeRange = nil
+		case DashboardSettingTimezone:
+			s.Timezone = ""
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("unknown dashboard setting %q", name),
+			}
+		}
+	}
+
+	if u.RefreshInterval != nil {
+		s.RefreshInterval = u.RefreshInterval
+	}
+
+	if u.TimeRange != nil {
+		s.TimeRange = u.TimeRange
+	}
+
+	if u.Timezone != nil {
+		s.Timezone = *u.Timezone
+	}
+
+	return s.Valid()
+}
+
 // DashboardFilter is a filter for dashboards.
 type DashboardFilter struct {
 	IDs            []*ID
@@ -167,6 +375,7 @@ func (f DashboardFilter) QueryParams() map[string][]string {
 type DashboardUpdate struct {
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
+	DashboardSettingsUpdate
 }
 
 // Apply applies an update to a dashboard.
@@ -179,12 +388,12 @@ func (u DashboardUpdate) Apply(d *Dashboard) error {
 		d.Description = *u.Description
 	}
 
-	return nil
+	return u.DashboardSettingsUpdate.Apply(&d.DashboardSettings)
 }
 
 // Valid returns an error if the dashboard update is invalid.
 func (u DashboardUpdate) Valid() *Error {
-	if u.Name == nil && u.Description == nil {
+	if u.Name == nil && u.Description == nil && u.DashboardSettingsUpdate.isEmpty() {
 		return &Error{
 			Code: EInvalid,
 			Msg:  "must update at least one attribute",
@@ -205,6 +414,7 @@ type CellUpdate struct {
 	Y *int32 `json:"y"`
 	W *int32 `json:"w"`
 	H *int32 `json:"h"`
+	DashboardSettingsUpdate
 }
 
 // Apply applies an update to a Cell.
@@ -225,12 +435,12 @@ func (u CellUpdate) Apply(c *Cell) error {
 		c.H = *u.H
 	}
 
-	return nil
+	return u.DashboardSettingsUpdate.Apply(&c.DashboardSettings)
 }
 
 // Valid returns an error if the cell update is invalid.
 func (u CellUpdate) Valid() *Error {
-	if u.H == nil && u.W == nil && u.Y == nil && u.X == nil {
+	if u.H == nil && u.W == nil && u.Y == nil && u.X == nil && u.DashboardSettingsUpdate.isEmpty() {
 		return &Error{
 			Code: EInvalid,
 			Msg:  "must update at least one attribute",
@@ -0,0 +1,23 @@
+package influxdb
+
+import "context"
+
+// OpRenderDashboard is the op of dashboard rendering errors.
+const OpRenderDashboard = "RenderDashboard"
+
+// DashboardRenderService renders dashboards to images, e.g. to embed their
+// charts in notifications and reports.
+type DashboardRenderService interface {
+	// RenderDashboard renders the cells of the dashboard to a PNG image.
+	RenderDashboard(ctx context.Context, id ID, opts DashboardRenderOptions) ([]byte, error)
+}
+
+// DashboardRenderOptions are the options of a rendering of a dashboard.
+type DashboardRenderOptions struct {
+	// TimeRange is the time range of the queries of every cell. The time
+	// range of the settings of the cells or of the dashboard is used if nil.
+	TimeRange *DashboardTimeRange
+	// Width is the width of the image in pixels. A default width is used if
+	// zero.
+	Width int
+}
@@ -3,6 +3,7 @@ package influxdb_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
@@ -88,3 +89,123 @@ func jsonEqual(s1, s2 string) (eq bool, err error) {
 
 	return cmp.Equal(o1, o2), nil
 }
+
+func TestDashboardSettings_Valid(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings platform.DashboardSettings
+		wantErr  bool
+	}{
+		{name: "empty"},
+		{
+			name: "relative time range",
+			settings: platform.DashboardSettings{
+				RefreshInterval: &platform.Duration{Duration: 30 * time.Second},
+				TimeRange:       &platform.DashboardTimeRange{Start: "-1h", Stop: "-5m"},
+				Timezone:        "America/New_York",
+			},
+		},
+		{
+			name:     "absolute time range",
+			settings: platform.DashboardSettings{TimeRange: &platform.DashboardTimeRange{Start: "2020-01-01T00:00:00Z", Stop: "2020-01-02T00:00:00Z"}},
+		},
+		{name: "negative refresh interval", settings: platform.DashboardSettings{RefreshInterval: &platform.Duration{Duration: -time.Second}}, wantErr: true},
+		{name: "time range without a start", settings: platform.DashboardSettings{TimeRange: &platform.DashboardTimeRange{Stop: "-5m"}}, wantErr: true},
+		{name: "invalid time range bound", settings: platform.DashboardSettings{TimeRange: &platform.DashboardTimeRange{Start: "yesterday"}}, wantErr: true},
+		{
+			name:     "time range stopping before its start",
+			settings: platform.DashboardSettings{TimeRange: &platform.DashboardTimeRange{Start: "2020-01-02T00:00:00Z", Stop: "2020-01-01T00:00:00Z"}},
+			wantErr:  true,
+		},
+		{name: "unknown timezone", settings: platform.DashboardSettings{Timezone: "Mars/Olympus_Mons"}, wantErr: true},
+		{name: "server timezone", settings: platform.DashboardSettings{Timezone: "Local"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Valid()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && platform.ErrorCode(err) != platform.EInvalid {
+				t.Errorf("Valid() error code = %s, want %s", platform.ErrorCode(err), platform.EInvalid)
+			}
+		})
+	}
+}
+
+func TestDashboardTimeRange_Bounds(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		timeRange platform.DashboardTimeRange
+		wantStart time.Time
+		wantStop  time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "relative start",
+			timeRange: platform.DashboardTimeRange{Start: "-1h30m"},
+			wantStart: now.Add(-90 * time.Minute),
+			wantStop:  now,
+		},
+		{
+			name:      "relative stop",
+			timeRange: platform.DashboardTimeRange{Start: "2020-01-01T00:00:00Z", Stop: "-2h"},
+			wantStart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantStop:  now.Add(-2 * time.Hour),
+		},
+		{name: "relative stop before start", timeRange: platform.DashboardTimeRange{Start: "-1h", Stop: "-2h"}, wantErr: true},
+		{name: "absolute start after now", timeRange: platform.DashboardTimeRange{Start: "2021-01-01T00:00:00Z"}, wantErr: true},
+		{name: "invalid", timeRange: platform.DashboardTimeRange{Start: "yesterday"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, stop, err := tt.timeRange.Bounds(now)
+			if tt.wantErr {
+				if platform.ErrorCode(err) != platform.EInvalid {
+					t.Fatalf("expected invalid error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !start.Equal(tt.wantStart) || !stop.Equal(tt.wantStop) {
+				t.Errorf("Bounds() = %v, %v, want %v, %v", start, stop, tt.wantStart, tt.wantStop)
+			}
+		})
+	}
+}
+
+func TestCellUpdate_Apply(t *testing.T) {
+	tz := "UTC"
+	c := &platform.Cell{
+		DashboardSettings: platform.DashboardSettings{
+			RefreshInterval: &platform.Duration{Duration: time.Minute},
+			TimeRange:       &platform.DashboardTimeRange{Start: "-1h"},
+		},
+	}
+
+	upd := platform.CellUpdate{}
+	upd.Timezone = &tz
+	upd.Unset = []string{platform.DashboardSettingRefreshInterval}
+	if err := upd.Valid(); err != nil {
+		t.Fatal(err)
+	}
+	if err := upd.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	want := platform.DashboardSettings{
+		TimeRange: &platform.DashboardTimeRange{Start: "-1h"},
+		Timezone:  "UTC",
+	}
+	if diff := cmp.Diff(want, c.DashboardSettings); diff != "" {
+		t.Errorf("Apply() settings diff (-want +got):\n%s", diff)
+	}
+
+	upd = platform.CellUpdate{}
+	upd.Unset = []string{"theme"}
+	if err := upd.Apply(c); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("Apply() of an unknown setting error = %v, want invalid", err)
+	}
+}
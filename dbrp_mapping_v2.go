@@ -0,0 +1,124 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrDBRPNotFound is the error message for a missing dbrp mapping.
+const ErrDBRPNotFound = "dbrp mapping not found"
+
+// ops for dbrp mappings.
+const (
+	OpFindDBRPMappingByID = "FindDBRPMappingByID"
+	OpFindDBRPMappings    = "FindDBRPMappings"
+	OpCreateDBRPMapping   = "CreateDBRPMapping"
+	OpUpdateDBRPMapping   = "UpdateDBRPMapping"
+	OpDeleteDBRPMapping   = "DeleteDBRPMapping"
+)
+
+// errors on dbrp mappings
+var (
+	// ErrDBRPAlreadyExists is the error when the database and retention
+	// policy of the organization are already mapped to a bucket.
+	ErrDBRPAlreadyExists = &Error{
+		Code: EConflict,
+		Msg:  "dbrp mapping for the database and retention policy already exists",
+	}
+
+	// ErrDBRPDefaultAlreadyExists is the error when a database of the
+	// organization already has a default mapping.
+	ErrDBRPDefaultAlreadyExists = &Error{
+		Code: EConflict,
+		Msg:  "default dbrp mapping for the database already exists",
+	}
+)
+
+// DBRPMappingServiceV2 provides the mappings of 1.x databases and retention
+// policies to the buckets of an organization.
+type DBRPMappingServiceV2 interface {
+	// FindDBRPMappingByID returns a single dbrp mapping by ID.
+	FindDBRPMappingByID(ctx context.Context, id ID) (*DBRPMappingV2, error)
+
+	// FindDBRPMappings returns the dbrp mappings that match filter and the total count of matching mappings.
+	FindDBRPMappings(ctx context.Context, filter DBRPMappingFilterV2, opt ...FindOptions) ([]*DBRPMappingV2, int, error)
+
+	// CreateDBRPMapping creates a new dbrp mapping and sets m.ID with the new identifier.
+	CreateDBRPMapping(ctx context.Context, m *DBRPMappingV2) error
+
+	// UpdateDBRPMapping updates a single dbrp mapping with a changeset.
+	UpdateDBRPMapping(ctx context.Context, id ID, upd DBRPMappingUpdate) (*DBRPMappingV2, error)
+
+	// DeleteDBRPMapping removes a dbrp mapping by ID.
+	DeleteDBRPMapping(ctx context.Context, id ID) error
+}
+
+// DBRPMappingV2 represents a mapping of a 1.x database and retention policy
+// to a bucket of an organization. The default mapping of a database is used
+// when a request does not name a retention policy.
+type DBRPMappingV2 struct {
+	ID              ID     `json:"id,omitempty"`
+	OrganizationID  ID     `json:"orgID,omitempty"`
+	BucketID        ID     `json:"bucketID,omitempty"`
+	Database        string `json:"database"`
+	RetentionPolicy string `json:"retentionPolicy"`
+	Default         bool   `json:"default"`
+}
+
+// Validate reports any validation errors for the mapping.
+func (m DBRPMappingV2) Validate() error {
+	if !validName(m.Database) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "database must contain at least one character and only be letters, numbers, '_', '-', and '.'",
+		}
+	}
+	if !validName(m.RetentionPolicy) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "retentionPolicy must contain at least one character and only be letters, numbers, '_', '-', and '.'",
+		}
+	}
+	if !m.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if !m.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is required",
+		}
+	}
+	return nil
+}
+
+// DBRPMappingFilterV2 represents a set of filters that restrict the returned dbrp mappings.
+type DBRPMappingFilterV2 struct {
+	ID              *ID
+	OrgID           *ID
+	BucketID        *ID
+	Database        *string
+	RetentionPolicy *string
+	Default         *bool
+}
+
+// DBRPMappingUpdate is the changeset of a dbrp mapping.
+type DBRPMappingUpdate struct {
+	BucketID        *ID     `json:"bucketID,omitempty"`
+	RetentionPolicy *string `json:"retentionPolicy,omitempty"`
+	Default         *bool   `json:"default,omitempty"`
+}
+
+// Apply applies the changeset to the mapping.
+func (u DBRPMappingUpdate) Apply(m *DBRPMappingV2) {
+	if u.BucketID != nil {
+		m.BucketID = *u.BucketID
+	}
+	if u.RetentionPolicy != nil {
+		m.RetentionPolicy = *u.RetentionPolicy
+	}
+	if u.Default != nil {
+		m.Default = *u.Default
+	}
+}
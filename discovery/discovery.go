@@ -0,0 +1,333 @@
+// Package discovery keeps track of the influxd instances of a cluster.
+//
+// Instances are registered with a static list of peer URLs or under a DNS
+// name. Every instance periodically probes the instances it knows with
+// GET /api/v2/instances, which marks them up or down and teaches it the
+// instances known by its peers, so that the topology spreads from instance to
+// instance like gossip.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// InstancesPath is the path of the endpoint probed on the peers.
+const InstancesPath = "/api/v2/instances"
+
+// DefaultExpiry is the duration after which an unreachable instance that is
+// no longer registered is forgotten.
+const DefaultExpiry = 10 * time.Minute
+
+var _ influxdb.InstanceService = (*Service)(nil)
+
+// Resolver resolves the addresses of the instances registered under a DNS name.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// Service discovers the instances of a cluster and implements
+// influxdb.InstanceService.
+type Service struct {
+	// URL is the URL advertised to the peers. It must be set before Run.
+	URL string
+	// Version is the version of the instance.
+	Version string
+	// Peers are the URLs of the statically registered instances.
+	Peers []string
+	// DNSName registers the instances resolved from DNS. A host:port name is
+	// resolved to its addresses, any other name to its SRV records.
+	DNSName  string
+	Resolver Resolver
+	Client   *http.Client
+	Expiry   time.Duration
+
+	mu        sync.RWMutex
+	instances map[string]*influxdb.Instance
+
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewService returns a Service for the instance advertised with url.
+func NewService(url, version string) *Service {
+	return &Service{
+		URL:       url,
+		Version:   version,
+		Resolver:  net.DefaultResolver,
+		Client:    &http.Client{Timeout: 5 * time.Second},
+		Expiry:    DefaultExpiry,
+		instances: make(map[string]*influxdb.Instance),
+		logger:    zap.NewNop(),
+		now:       time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the service. It must be called before Run.
+func (s *Service) WithLogger(l *zap.Logger) {
+	s.logger = l.With(zap.String("component", "discovery"))
+}
+
+// Enabled returns true if peers are registered statically or through DNS.
+func (s *Service) Enabled() bool {
+	return len(s.Peers) > 0 || s.DNSName != ""
+}
+
+// FindInstances returns the known instances, the instance itself first and
+// the others sorted by URL.
+func (s *Service) FindInstances(ctx context.Context) ([]*influxdb.Instance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now().UTC()
+	instances := make([]*influxdb.Instance, 0, len(s.instances)+1)
+	instances = append(instances, &influxdb.Instance{
+		URL:      s.URL,
+		Version:  s.Version,
+		Status:   influxdb.InstanceStatusUp,
+		Source:   influxdb.InstanceSourceSelf,
+		Self:     true,
+		LastSeen: &now,
+	})
+
+	others := make([]*influxdb.Instance, 0, len(s.instances))
+	for _, i := range s.instances {
+		c := *i
+		others = append(others, &c)
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].URL < others[j].URL
+	})
+	return append(instances, others...), nil
+}
+
+// Run refreshes the topology immediately and then every interval until ctx is canceled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Unable to refresh instances", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type probe struct {
+	target    string
+	source    influxdb.InstanceSource
+	instances []*influxdb.Instance
+	err       error
+}
+
+// Refresh probes the registered and known instances. An error resolving the
+// DNS name is returned after the other instances have been probed.
+func (s *Service) Refresh(ctx context.Context) error {
+	registered, resolveErr := s.registered(ctx)
+
+	targets := make(map[string]influxdb.InstanceSource, len(registered))
+	for u, src := range registered {
+		targets[u] = src
+	}
+	s.mu.RLock()
+	for u, i := range s.instances {
+		if _, ok := targets[u]; !ok {
+			targets[u] = i.Source
+		}
+	}
+	s.mu.RUnlock()
+	delete(targets, s.URL)
+
+	probes := make(chan probe, len(targets))
+	var wg sync.WaitGroup
+	for target, src := range targets {
+		wg.Add(1)
+		go func(target string, src influxdb.InstanceSource) {
+			defer wg.Done()
+			instances, err := s.probe(ctx, target)
+			probes <- probe{target: target, source: src, instances: instances, err: err}
+		}(target, src)
+	}
+	wg.Wait()
+	close(probes)
+
+	now := s.now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range probes {
+		s.apply(p, now)
+	}
+	s.expire(registered, now)
+
+	return resolveErr
+}
+
+// apply records the result of a probe.
+func (s *Service) apply(p probe, now time.Time) {
+	if p.err != nil {
+		s.logger.Debug("Instance is unreachable", zap.String("url", p.target), zap.Error(p.err))
+		i, ok := s.instances[p.target]
+		if !ok {
+			i = &influxdb.Instance{URL: p.target, Source: p.source}
+			s.instances[p.target] = i
+		}
+		i.Status = influxdb.InstanceStatusDown
+		return
+	}
+
+	// The peer reports the URL it advertises, which may differ from the
+	// address it was registered with.
+	peer := &influxdb.Instance{URL: p.target}
+	for _, i := range p.instances {
+		if i.Self {
+			peer = i
+			break
+		}
+	}
+	if peer.URL == s.URL {
+		// The instance registered itself, e.g. with a DNS name.
+		return
+	}
+	if peer.URL != p.target {
+		delete(s.instances, p.target)
+	}
+
+	i, ok := s.instances[peer.URL]
+	if !ok {
+		i = &influxdb.Instance{URL: peer.URL}
+		s.instances[peer.URL] = i
+	}
+	if i.Source == "" || i.Source == influxdb.InstanceSourceGossip {
+		i.Source = p.source
+	}
+	i.Version = peer.Version
+	i.Status = influxdb.InstanceStatusUp
+	i.LastSeen = &now
+
+	for _, g := range p.instances {
+		if g.Self || g.Status != influxdb.InstanceStatusUp || g.URL == s.URL {
+			continue
+		}
+		if _, ok := s.instances[g.URL]; ok {
+			continue
+		}
+		s.instances[g.URL] = &influxdb.Instance{
+			URL:     g.URL,
+			Version: g.Version,
+			Status:  influxdb.InstanceStatusUnknown,
+			Source:  influxdb.InstanceSourceGossip,
+		}
+	}
+}
+
+// expire forgets the unreachable instances that are no longer registered.
+func (s *Service) expire(registered map[string]influxdb.InstanceSource, now time.Time) {
+	for u, i := range s.instances {
+		if _, ok := registered[u]; ok || i.Status != influxdb.InstanceStatusDown {
+			continue
+		}
+		if i.LastSeen == nil || now.Sub(*i.LastSeen) > s.Expiry {
+			delete(s.instances, u)
+		}
+	}
+}
+
+// registered returns the URLs of the statically registered instances and of
+// the ones resolved from the DNS name.
+func (s *Service) registered(ctx context.Context) (map[string]influxdb.InstanceSource, error) {
+	registered := make(map[string]influxdb.InstanceSource, len(s.Peers))
+	for _, p := range s.Peers {
+		registered[strings.TrimSuffix(p, "/")] = influxdb.InstanceSourceStatic
+	}
+	if s.DNSName == "" {
+		return registered, nil
+	}
+
+	urls, err := s.resolve(ctx)
+	if err != nil {
+		return registered, fmt.Errorf("unable to resolve %s: %v", s.DNSName, err)
+	}
+	for _, u := range urls {
+		if _, ok := registered[u]; !ok {
+			registered[u] = influxdb.InstanceSourceDNS
+		}
+	}
+	return registered, nil
+}
+
+// resolve returns the URLs of the instances registered under the DNS name,
+// using the scheme of the advertised URL.
+func (s *Service) resolve(ctx context.Context) ([]string, error) {
+	scheme := "http"
+	if u, err := url.Parse(s.URL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+
+	var hostports []string
+	if host, port, err := net.SplitHostPort(s.DNSName); err == nil {
+		addrs, err := s.Resolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			hostports = append(hostports, net.JoinHostPort(a, port))
+		}
+	} else {
+		_, srvs, err := s.Resolver.LookupSRV(ctx, "", "", s.DNSName)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			hostports = append(hostports, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+		}
+	}
+
+	urls := make([]string, len(hostports))
+	for i, hp := range hostports {
+		urls[i] = scheme + "://" + hp
+	}
+	return urls, nil
+}
+
+// probe returns the instances known by the instance at target.
+func (s *Service) probe(ctx context.Context, target string) ([]*influxdb.Instance, error) {
+	req, err := http.NewRequest("GET", target+InstancesPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var body struct {
+		Instances []*influxdb.Instance `json:"instances"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Instances, nil
+}
@@ -0,0 +1,159 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+// serve exposes the instances of s like the instances endpoint does.
+func serve(s *Service) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != InstancesPath {
+			http.NotFound(w, r)
+			return
+		}
+		instances, _ := s.FindInstances(r.Context())
+		json.NewEncoder(w).Encode(map[string]interface{}{"instances": instances})
+	}))
+	s.URL = srv.URL
+	return srv
+}
+
+func statuses(t *testing.T, s *Service) map[string]influxdb.InstanceStatus {
+	t.Helper()
+	instances, err := s.FindInstances(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]influxdb.InstanceStatus, len(instances))
+	for _, i := range instances {
+		m[i.URL] = i.Status
+	}
+	return m
+}
+
+func TestService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := NewService("", "a"), NewService("", "b"), NewService("", "c")
+	as, bs, cs := serve(a), serve(b), serve(c)
+	defer as.Close()
+	defer bs.Close()
+	defer cs.Close()
+
+	a.Peers = []string{b.URL + "/"}
+	b.Peers = []string{c.URL}
+
+	if err := b.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]influxdb.InstanceStatus{
+		a.URL: influxdb.InstanceStatusUp,
+		b.URL: influxdb.InstanceStatusUp,
+		c.URL: influxdb.InstanceStatusUnknown,
+	}
+	if diff := cmp.Diff(statuses(t, a), want); diff != "" {
+		t.Errorf("instances after first refresh diff: %s", diff)
+	}
+
+	if err := a.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	instances, _ := a.FindInstances(ctx)
+	if got := instances[0]; !got.Self || got.URL != a.URL {
+		t.Errorf("expected the instance itself first, got %+v", got)
+	}
+	for _, i := range instances[1:] {
+		if i.Status != influxdb.InstanceStatusUp {
+			t.Errorf("expected %s to be up, got %s", i.URL, i.Status)
+		}
+		if i.URL == c.URL && (i.Source != influxdb.InstanceSourceGossip || i.Version != "c") {
+			t.Errorf("unexpected instance learned from a peer %+v", i)
+		}
+	}
+
+	// Instances learned from peers are forgotten once unreachable for longer
+	// than the expiry, registered ones are kept down.
+	cs.Close()
+	a.now = func() time.Time { return time.Now().Add(2 * DefaultExpiry) }
+	if err := a.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := statuses(t, a)[c.URL]; ok {
+		t.Errorf("expected unreachable instance %s to be forgotten", c.URL)
+	}
+
+	b.Peers = nil
+	a.Peers = []string{b.URL, "http://127.0.0.1:1"}
+	if err := a.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(t, a)["http://127.0.0.1:1"]; got != influxdb.InstanceStatusDown {
+		t.Errorf("expected registered unreachable instance to be down, got %q", got)
+	}
+}
+
+func TestService_RefreshSelf(t *testing.T) {
+	a := NewService("", "a")
+	defer serve(a).Close()
+	a.Peers = []string{a.URL}
+
+	if err := a.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if instances, _ := a.FindInstances(context.Background()); len(instances) != 1 {
+		t.Errorf("expected the instance not to discover itself, got %d instances", len(instances))
+	}
+}
+
+type resolver struct {
+	hosts map[string][]string
+	srvs  map[string][]*net.SRV
+}
+
+func (r resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.hosts[host], nil
+}
+
+func (r resolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return name, r.srvs[name], nil
+}
+
+func TestService_resolve(t *testing.T) {
+	r := resolver{
+		hosts: map[string][]string{"influxd.local": {"10.0.0.1", "fd00::1"}},
+		srvs: map[string][]*net.SRV{
+			"_influxd._tcp.example.com": {{Target: "node1.example.com.", Port: 9999}},
+		},
+	}
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{name: "influxd.local:8086", url: "http://self:8086", want: []string{"http://10.0.0.1:8086", "http://[fd00::1]:8086"}},
+		{name: "_influxd._tcp.example.com", url: "https://self:8086", want: []string{"https://node1.example.com:9999"}},
+	}
+	for _, tt := range tests {
+		s := NewService(tt.url, "")
+		s.DNSName = tt.name
+		s.Resolver = r
+		got, err := s.resolve(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("resolve(%s) diff: %s", tt.name, diff)
+		}
+	}
+}
@@ -9,21 +9,40 @@ import (
 	"strings"
 )
 
-// Some error code constant, ideally we want define common platform codes here
-// projects on use platform's error, should have their own central place like this.
+// Error codes are the machine-readable codes of errors. They are returned as
+// the code of the JSON body of HTTP error responses and in the
+// X-Platform-Error-Code header, so that clients can branch on the type of an
+// error rather than on its message. The codes are stable: they are never
+// renamed and each maps to one HTTP status code.
 // Any time this set of constants changes, you must also update the swagger for Error.properties.code.enum.
 const (
-	EInternal            = "internal error"
-	ENotFound            = "not found"
-	EConflict            = "conflict"             // action cannot be performed
-	EInvalid             = "invalid"              // validation failed
-	EUnprocessableEntity = "unprocessable entity" // data type is correct, but out of range
-	EEmptyValue          = "empty value"
-	EUnavailable         = "unavailable"
-	EForbidden           = "forbidden"
-	ETooManyRequests     = "too many requests"
-	EUnauthorized        = "unauthorized"
-	EMethodNotAllowed    = "method not allowed"
+	// EInternal is an unexpected failure of the server (500).
+	EInternal = "internal error"
+	// ENotFound is a resource that does not exist (404).
+	ENotFound = "not found"
+	// EConflict is an action that cannot be performed in the current state
+	// of a resource, e.g. creating a resource whose name is taken (422).
+	EConflict = "conflict"
+	// EInvalid is a request that failed validation (400).
+	EInvalid = "invalid"
+	// EUnprocessableEntity is a value of the correct type but out of
+	// range (422).
+	EUnprocessableEntity = "unprocessable entity"
+	// EEmptyValue is a required value that is missing (400).
+	EEmptyValue = "empty value"
+	// EUnavailable is a service that is temporarily unavailable (503).
+	EUnavailable = "unavailable"
+	// EForbidden is an authenticated request that is not allowed (403).
+	EForbidden = "forbidden"
+	// ETooManyRequests is a request rejected by a rate limit (429).
+	ETooManyRequests = "too many requests"
+	// ERequestTooLarge is a request body that exceeds a size limit (413).
+	ERequestTooLarge = "request too large"
+	// EUnauthorized is a request that is not authenticated, or whose
+	// authorization lacks a required permission (401).
+	EUnauthorized = "unauthorized"
+	// EMethodNotAllowed is an HTTP method not supported by a route (405).
+	EMethodNotAllowed = "method not allowed"
 )
 
 // Error is the error struct of platform.
@@ -0,0 +1,146 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OpForecast is the op of forecasting errors.
+const OpForecast = "Forecast"
+
+// Forecasting methods.
+const (
+	// ForecastHoltWinters forecasts with the Holt-Winters damped trend
+	// method, seasonal if a seasonality is given.
+	ForecastHoltWinters = "holtWinters"
+	// ForecastARIMA forecasts with an ARIMA(p, 1, 0) model: an
+	// autoregressive model of order p of the differences of the values.
+	ForecastARIMA = "arima"
+)
+
+// Limits and defaults of forecasts.
+const (
+	// MaxForecastPoints is the maximum number of points forecast per series.
+	MaxForecastPoints = 10000
+	// DefaultForecastConfidence is the confidence of the bands of forecasts
+	// that do not set one.
+	DefaultForecastConfidence = 0.95
+	// DefaultForecastOrder is the order of ARIMA forecasts that do not set one.
+	DefaultForecastOrder = 2
+	// MaxForecastOrder is the maximum order of ARIMA forecasts.
+	MaxForecastOrder = 10
+)
+
+// ForecastService forecasts the values of the series of the results of flux
+// queries, e.g. to project the disk usage of capacity dashboards.
+type ForecastService interface {
+	// Forecast runs the query of the request and forecasts each table of its
+	// results.
+	Forecast(ctx context.Context, req ForecastRequest) ([]*ForecastSeries, error)
+}
+
+// ForecastRequest is a forecast of the results of a flux query. The values
+// of each table of the results are a series, expected to be evenly spaced in
+// time, e.g. by aggregateWindow.
+type ForecastRequest struct {
+	OrganizationID ID     `json:"-"`
+	Query          string `json:"query"`
+	// Column is the numeric column of the values, _value if empty.
+	Column string `json:"column,omitempty"`
+	// Method is the forecasting method, holtWinters if empty.
+	Method string `json:"method,omitempty"`
+	// Points is the number of points forecast after the last value of
+	// each series.
+	Points int `json:"points"`
+	// Interval is the time between forecast points. It is the median time
+	// between the values of each series if zero.
+	Interval Duration `json:"interval"`
+	// Seasonality is the number of values in a season of the holtWinters
+	// method. Forecasts are not seasonal if it is less than 2.
+	Seasonality int `json:"seasonality,omitempty"`
+	// Order is the order of the autoregressive model of the arima method.
+	Order int `json:"order,omitempty"`
+	// Confidence is the probability of the values to fall within the
+	// bands of the forecast.
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// WithDefaults returns the request with the defaults of its unset options.
+func (r ForecastRequest) WithDefaults() ForecastRequest {
+	if r.Column == "" {
+		r.Column = "_value"
+	}
+	if r.Method == "" {
+		r.Method = ForecastHoltWinters
+	}
+	if r.Method == ForecastARIMA && r.Order == 0 {
+		r.Order = DefaultForecastOrder
+	}
+	if r.Confidence == 0 {
+		r.Confidence = DefaultForecastConfidence
+	}
+	return r
+}
+
+// Valid returns an error if the request with its defaults is invalid.
+func (r ForecastRequest) Valid() error {
+	invalid := func(msg string) error {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpForecast,
+			Msg:  msg,
+		}
+	}
+
+	if !r.OrganizationID.Valid() {
+		return invalid("organization is required")
+	}
+	if r.Query == "" {
+		return invalid("query is required")
+	}
+	switch r.Method {
+	case ForecastHoltWinters:
+		if r.Order != 0 {
+			return invalid("order only applies to the arima method")
+		}
+		if r.Seasonality < 0 {
+			return invalid("seasonality can't be negative")
+		}
+	case ForecastARIMA:
+		if r.Seasonality != 0 {
+			return invalid("seasonality only applies to the holtWinters method")
+		}
+		if r.Order < 1 || r.Order > MaxForecastOrder {
+			return invalid(fmt.Sprintf("order must be between 1 and %d", MaxForecastOrder))
+		}
+	default:
+		return invalid(fmt.Sprintf("unknown forecasting method %q", r.Method))
+	}
+	if r.Points < 1 || r.Points > MaxForecastPoints {
+		return invalid(fmt.Sprintf("points must be between 1 and %d", MaxForecastPoints))
+	}
+	if r.Interval.Duration < 0 {
+		return invalid("interval can't be negative")
+	}
+	if r.Confidence <= 0 || r.Confidence >= 1 {
+		return invalid("confidence must be between 0 and 1")
+	}
+	return nil
+}
+
+// ForecastSeries is the forecast of a table of the results of a query.
+type ForecastSeries struct {
+	// Tags are the string columns of the group key of the table.
+	Tags     map[string]string `json:"tags"`
+	Forecast []ForecastPoint   `json:"forecast"`
+}
+
+// ForecastPoint is a forecast value, within the bands of the confidence of
+// the request.
+type ForecastPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
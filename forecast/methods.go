@@ -0,0 +1,153 @@
+package forecast
+
+import (
+	"math"
+
+	"github.com/influxdata/influxdb/pkg/holtwinters"
+)
+
+// A method forecasts n values after the values vs. It returns the forecast
+// values with their standard deviations, and false if vs are too few to be
+// forecast.
+type method func(vs []float64, n int, opts options) (fcast, stddev []float64, ok bool)
+
+// options are the options of the methods.
+type options struct {
+	seasonality int
+	order       int
+}
+
+// holtWinters forecasts with the Holt-Winters damped trend method. The
+// standard deviation is the root mean square error of the fit of the values,
+// growing with the square root of the horizon.
+func holtWinters(vs []float64, n int, opts options) ([]float64, []float64, bool) {
+	fcast, rmse, ok := holtwinters.Forecast(vs, n, opts.seasonality)
+	if !ok {
+		return nil, nil, false
+	}
+	stddev := make([]float64, n)
+	for h := range stddev {
+		stddev[h] = rmse * math.Sqrt(float64(h+1))
+	}
+	return fcast, stddev, true
+}
+
+// arima forecasts with an ARIMA(p, 1, 0) model: the differences of the
+// values, less their mean, are an autoregressive process of order p fit by
+// least squares. The standard deviation is derived from the variance of the
+// residuals of the fit and the coefficients of the model.
+func arima(vs []float64, n int, opts options) ([]float64, []float64, bool) {
+	p := opts.order
+	if len(vs) < 2*p+2 {
+		return nil, nil, false
+	}
+
+	d := make([]float64, len(vs)-1)
+	var mean float64
+	for i := range d {
+		d[i] = vs[i+1] - vs[i]
+		mean += d[i]
+	}
+	mean /= float64(len(d))
+	for i := range d {
+		d[i] -= mean
+	}
+
+	// Normal equations of the regression of d[t] on d[t-1], ..., d[t-p].
+	a := make([][]float64, p)
+	y := make([]float64, p)
+	for i := range a {
+		a[i] = make([]float64, p)
+	}
+	for t := p; t < len(d); t++ {
+		for i := 0; i < p; i++ {
+			y[i] += d[t-i-1] * d[t]
+			for j := 0; j < p; j++ {
+				a[i][j] += d[t-i-1] * d[t-j-1]
+			}
+		}
+	}
+	phi, ok := solve(a, y)
+	if !ok {
+		// The differences do not vary: their mean is the best forecast.
+		phi = make([]float64, p)
+	}
+
+	var ss float64
+	for t := p; t < len(d); t++ {
+		e := d[t] - predict(d[:t], phi)
+		ss += e * e
+	}
+	sigma := math.Sqrt(ss / float64(len(d)-p))
+
+	// The weights psi of the past errors in the differences, whose partial
+	// sums are the weights of the errors in the values.
+	psi := make([]float64, n)
+	fcast := make([]float64, n)
+	stddev := make([]float64, n)
+	last, cum, variance := vs[len(vs)-1], 0.0, 0.0
+	for h := 0; h < n; h++ {
+		next := predict(d, phi)
+		d = append(d, next)
+		last += next + mean
+		fcast[h] = last
+
+		psi[h] = 1
+		if h > 0 {
+			psi[h] = 0
+			for k := 1; k <= p && k <= h; k++ {
+				psi[h] += phi[k-1] * psi[h-k]
+			}
+		}
+		cum += psi[h]
+		variance += cum * cum
+		stddev[h] = sigma * math.Sqrt(variance)
+	}
+	return fcast, stddev, true
+}
+
+// predict returns the next value of the autoregressive process after vs.
+func predict(vs []float64, phi []float64) float64 {
+	var v float64
+	for k, c := range phi {
+		v += c * vs[len(vs)-k-1]
+	}
+	return v
+}
+
+// solve solves the linear system a x = y by Gaussian elimination with
+// partial pivoting. It returns false if the system is singular.
+func solve(a [][]float64, y []float64) ([]float64, bool) {
+	n := len(y)
+	for c := 0; c < n; c++ {
+		pivot := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[pivot][c]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][c]) < 1e-12 {
+			return nil, false
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		y[c], y[pivot] = y[pivot], y[c]
+
+		for r := c + 1; r < n; r++ {
+			f := a[r][c] / a[c][c]
+			for k := c; k < n; k++ {
+				a[r][k] -= f * a[c][k]
+			}
+			y[r] -= f * y[c]
+		}
+	}
+
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		v := y[r]
+		for k := r + 1; k < n; k++ {
+			v -= a[r][k] * x[k]
+		}
+		x[r] = v / a[r][r]
+	}
+	return x, true
+}
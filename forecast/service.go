@@ -0,0 +1,220 @@
+// Package forecast forecasts the series of the results of flux queries, e.g.
+// so that capacity dashboards can project the disk usage of the coming
+// months.
+package forecast
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+var methods = map[string]method{
+	influxdb.ForecastHoltWinters: holtWinters,
+	influxdb.ForecastARIMA:       arima,
+}
+
+var _ influxdb.ForecastService = (*Service)(nil)
+
+// Service implements influxdb.ForecastService. Queries run with the
+// authorization of the caller.
+type Service struct {
+	qs  query.QueryService
+	now func() time.Time
+}
+
+// NewService creates a service forecasting the results of the query service.
+func NewService(qs query.QueryService) *Service {
+	return &Service{
+		qs:  qs,
+		now: time.Now,
+	}
+}
+
+// Forecast runs the query of the request and forecasts each table of its
+// results. Tables without enough values to be forecast by the method have an
+// empty forecast.
+func (s *Service) Forecast(ctx context.Context, req influxdb.ForecastRequest) ([]*influxdb.ForecastSeries, error) {
+	req = req.WithDefaults()
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	auth, err := query.AuthorizationFromContext(ctx, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := s.querySeries(ctx, auth, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := methods[req.Method]
+	opts := options{seasonality: req.Seasonality, order: req.Order}
+	z := math.Sqrt2 * math.Erfinv(req.Confidence)
+
+	fs := make([]*influxdb.ForecastSeries, 0, len(ss))
+	for _, sr := range ss {
+		f := &influxdb.ForecastSeries{
+			Tags:     sr.tags,
+			Forecast: []influxdb.ForecastPoint{},
+		}
+		fs = append(fs, f)
+
+		interval := req.Interval.Duration
+		if interval == 0 {
+			interval = medianInterval(sr.times)
+		}
+		if interval <= 0 {
+			continue
+		}
+		fcast, stddev, ok := fn(sr.values, req.Points, opts)
+		if !ok {
+			continue
+		}
+
+		last := time.Unix(0, sr.times[len(sr.times)-1]).UTC()
+		for h, v := range fcast {
+			f.Forecast = append(f.Forecast, influxdb.ForecastPoint{
+				Time:  last.Add(time.Duration(h+1) * interval),
+				Value: v,
+				Lower: v - z*stddev[h],
+				Upper: v + z*stddev[h],
+			})
+		}
+	}
+	return fs, nil
+}
+
+// medianInterval returns the median time between the times, or zero if
+// there are less than two.
+func medianInterval(times []int64) time.Duration {
+	if len(times) < 2 {
+		return 0
+	}
+	ds := make([]int64, len(times)-1)
+	for i := range ds {
+		ds[i] = times[i+1] - times[i]
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return time.Duration(ds[len(ds)/2])
+}
+
+// series are the values of a table of the results of a query, in time order.
+type series struct {
+	tags   map[string]string
+	times  []int64
+	values []float64
+}
+
+// querySeries runs the query of the request and returns a series per table
+// of its results.
+func (s *Service) querySeries(ctx context.Context, auth *influxdb.Authorization, req influxdb.ForecastRequest) ([]series, error) {
+	pkg := parser.ParseSource(req.Query)
+	if ast.Check(pkg) > 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpForecast,
+			Msg:  "query is not valid flux",
+			Err:  ast.GetError(pkg),
+		}
+	}
+
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: req.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: s.now(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	var ss []series
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			sr := series{tags: make(map[string]string)}
+			for j, c := range tbl.Key().Cols() {
+				if c.Type == flux.TString {
+					sr.tags[c.Label] = tbl.Key().ValueString(j)
+				}
+			}
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				return readValues(&sr, cr, req.Column)
+			}); err != nil {
+				return err
+			}
+			if len(sr.times) > 0 {
+				ss = append(ss, sr)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// readValues appends the values of the column to the series. Tables without
+// a time column or a numeric value column have no values.
+func readValues(sr *series, cr flux.ColReader, column string) error {
+	x, y := -1, -1
+	for j, col := range cr.Cols() {
+		switch {
+		case col.Label == execute.DefaultTimeColLabel && col.Type == flux.TTime:
+			x = j
+		case col.Label == column && (col.Type == flux.TFloat || col.Type == flux.TInt || col.Type == flux.TUInt):
+			y = j
+		}
+	}
+	if x < 0 || y < 0 {
+		return nil
+	}
+
+	times := cr.Times(x)
+	for i := 0; i < cr.Len(); i++ {
+		if times.IsNull(i) {
+			continue
+		}
+		var v float64
+		switch cr.Cols()[y].Type {
+		case flux.TFloat:
+			vs := cr.Floats(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = vs.Value(i)
+		case flux.TInt:
+			vs := cr.Ints(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = float64(vs.Value(i))
+		case flux.TUInt:
+			vs := cr.UInts(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = float64(vs.Value(i))
+		}
+		sr.times = append(sr.times, times.Value(i))
+		sr.values = append(sr.values, v)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package forecast_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/forecast"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+)
+
+func TestService_Forecast(t *testing.T) {
+	orgID := influxdb.ID(1)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	auth := &influxdb.Authorization{ID: 1000, OrgID: orgID, Status: influxdb.Active}
+
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "host", Type: flux.TString},
+	}
+	var a, b [][]interface{}
+	for i := 0; i < 30; i++ {
+		t := values.ConvertTime(start.Add(time.Duration(i) * time.Hour))
+		// Disk usage growing by 2 per hour, with some noise.
+		a = append(a, []interface{}{t, 100 + 2*float64(i) + float64(i%3-1), "a"})
+	}
+	b = append(b, []interface{}{values.ConvertTime(start), 1.0, "b"})
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID || req.Authorization != auth {
+				t.Fatalf("expected query in org %s with the authorization of the caller, got %s %v", orgID, req.OrganizationID, req.Authorization)
+			}
+			r := executetest.NewResult([]*executetest.Table{
+				{KeyCols: []string{"host"}, ColMeta: cols, Data: a},
+				{KeyCols: []string{"host"}, ColMeta: cols, Data: b},
+			})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+
+	s := forecast.NewService(qs)
+	ctx := icontext.SetAuthorizer(context.Background(), auth)
+
+	for _, method := range []string{influxdb.ForecastHoltWinters, influxdb.ForecastARIMA} {
+		t.Run(method, func(t *testing.T) {
+			fs, err := s.Forecast(ctx, influxdb.ForecastRequest{
+				OrganizationID: orgID,
+				Query:          `from(bucket: "b") |> range(start: -30h)`,
+				Method:         method,
+				Points:         10,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 2 {
+				t.Fatalf("expected a forecast per table, got %d", len(fs))
+			}
+			if host := fs[1].Tags["host"]; host != "b" || len(fs[1].Forecast) != 0 {
+				t.Errorf("expected no forecast of a single value, got %d points of %q", len(fs[1].Forecast), host)
+			}
+
+			f := fs[0]
+			if f.Tags["host"] != "a" || len(f.Forecast) != 10 {
+				t.Fatalf("expected 10 points of a, got %d points of %v", len(f.Forecast), f.Tags)
+			}
+			prev := math.Inf(-1)
+			for h, p := range f.Forecast {
+				if want := start.Add(time.Duration(30+h) * time.Hour); !p.Time.Equal(want) {
+					t.Errorf("expected point %d at %v, got %v", h, want, p.Time)
+				}
+				if want := 158 + 2*float64(h+1); math.Abs(p.Value-want) > 5 {
+					t.Errorf("expected point %d to follow the trend around %v, got %v", h, want, p.Value)
+				}
+				if p.Lower >= p.Value || p.Upper <= p.Value {
+					t.Errorf("expected point %d within its bands, got %v < %v < %v", h, p.Lower, p.Value, p.Upper)
+				}
+				if width := p.Upper - p.Lower; width < prev {
+					t.Errorf("expected the bands to widen, got %v after %v", width, prev)
+				} else {
+					prev = width
+				}
+			}
+		})
+	}
+}
+
+func TestService_Forecast_Invalid(t *testing.T) {
+	s := forecast.NewService(&qmock.QueryService{})
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{})
+
+	for _, req := range []influxdb.ForecastRequest{
+		{OrganizationID: 1, Points: 10},
+		{OrganizationID: 1, Query: `from(bucket: "b")`},
+		{OrganizationID: 1, Query: `from(bucket: "b")`, Points: 10, Method: "linear"},
+		{OrganizationID: 1, Query: `from(bucket: "b")`, Points: 10, Order: 2},
+		{OrganizationID: 1, Query: `from(bucket: "b")`, Points: 10, Confidence: 1},
+		{OrganizationID: 1, Query: `from(bucket: `, Points: 10},
+	} {
+		if _, err := s.Forecast(ctx, req); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected %+v to be invalid, got %v", req, err)
+		}
+	}
+}
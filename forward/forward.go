@@ -0,0 +1,428 @@
+// Package forward implements the store-and-forward mode of edge instances.
+//
+// Writes are always accepted locally. The writes of an organization are then
+// queued on disk for every remote connection of the organization that
+// forwards, and sent to the buckets of the same names in the remote
+// organizations whenever the remotes are reachable. The queues survive
+// restarts, so writes are forwarded at least once.
+//
+// Forwarding is conflict free: the fields of a series at a time are
+// forwarded as one point, and like the local storage the remotes keep the
+// last value of a field of a series at a time, so forwarding a write again
+// after a failure or a restart does not duplicate it.
+package forward
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// PointsWriter writes points.
+type PointsWriter interface {
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+// Sender sends line protocol of nanosecond precision to a bucket of the
+// remote organization of a connection.
+type Sender func(ctx context.Context, rc *influxdb.RemoteConnection, bucket string, data []byte) error
+
+// queue is the queue of a remote connection that forwards.
+type queue struct {
+	rc *influxdb.RemoteConnection
+	q  *Queue
+}
+
+// bucket is what the forwarder knows of a local bucket.
+type bucket struct {
+	name   string
+	system bool
+}
+
+// Forwarder forwards the writes of organizations to their remote connections
+// that forward. Its PointsWriter must be in the path of the writes.
+type Forwarder struct {
+	dir     string
+	maxSize int64
+	remotes influxdb.RemoteConnectionService
+	buckets influxdb.BucketService
+	send    Sender
+	logger  *zap.Logger
+
+	mu         sync.RWMutex
+	forwarding map[influxdb.ID][]influxdb.ID // The forwarding connections, by organization ID.
+	queues     map[influxdb.ID]*queue        // The open queues, by connection ID.
+	names      map[influxdb.ID]bucket        // The buckets written to, by ID.
+
+	// notify wakes up Run when blocks are queued.
+	notify chan struct{}
+}
+
+// NewForwarder returns a Forwarder keeping the queues of remote connections
+// in dir, each up to maxSize bytes.
+func NewForwarder(dir string, maxSize int64, remotes influxdb.RemoteConnectionService, buckets influxdb.BucketService, send Sender, logger *zap.Logger) *Forwarder {
+	return &Forwarder{
+		dir:        dir,
+		maxSize:    maxSize,
+		remotes:    remotes,
+		buckets:    buckets,
+		send:       send,
+		logger:     logger,
+		forwarding: make(map[influxdb.ID][]influxdb.ID),
+		queues:     make(map[influxdb.ID]*queue),
+		names:      make(map[influxdb.ID]bucket),
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// Open opens the queues of the remote connections that forward.
+func (f *Forwarder) Open(ctx context.Context) error {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+	return f.refresh(ctx)
+}
+
+// Close closes the queues. Their blocks are forwarded once reopened.
+func (f *Forwarder) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var err error
+	for id, q := range f.queues {
+		if cerr := q.q.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(f.queues, id)
+	}
+	return err
+}
+
+// refresh opens the queues of the connections that forward and closes the
+// others. The queues of deleted connections are removed.
+func (f *Forwarder) refresh(ctx context.Context) error {
+	rcs, _, err := f.remotes.FindRemoteConnections(ctx, influxdb.RemoteConnectionFilter{})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	exists := make(map[string]bool, len(rcs))
+	forwards := make(map[influxdb.ID]bool)
+	forwarding := make(map[influxdb.ID][]influxdb.ID)
+	for _, rc := range rcs {
+		exists[rc.ID.String()] = true
+		if !rc.Forward {
+			continue
+		}
+		forwards[rc.ID] = true
+		if q, ok := f.queues[rc.ID]; ok {
+			q.rc = rc
+		} else {
+			q, err := OpenQueue(filepath.Join(f.dir, rc.ID.String()), f.maxSize)
+			if err != nil {
+				f.logger.Error("Failed to open forward queue", zap.Stringer("remote_id", rc.ID), zap.Error(err))
+				continue
+			}
+			f.queues[rc.ID] = &queue{rc: rc, q: q}
+		}
+		forwarding[rc.OrganizationID] = append(forwarding[rc.OrganizationID], rc.ID)
+	}
+	f.forwarding = forwarding
+
+	for id, q := range f.queues {
+		if !forwards[id] {
+			q.q.Close()
+			delete(f.queues, id)
+		}
+	}
+
+	fis, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() && !exists[fi.Name()] {
+			f.logger.Info("Removing forward queue of deleted remote", zap.String("remote_id", fi.Name()))
+			if err := os.RemoveAll(filepath.Join(f.dir, fi.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Renamed buckets are forwarded under their new names.
+	f.names = make(map[influxdb.ID]bucket)
+	return nil
+}
+
+// PointsWriter returns a PointsWriter writing points to w, and queueing the
+// points written for the remote connections that forward.
+func (f *Forwarder) PointsWriter(w PointsWriter) PointsWriter {
+	return &pointsWriter{w: w, f: f}
+}
+
+type pointsWriter struct {
+	w PointsWriter
+	f *Forwarder
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	if err := w.w.WritePoints(ctx, points); err != nil {
+		return err
+	}
+	// The points are written locally, so failures to queue them do not fail
+	// the write.
+	w.f.enqueue(ctx, points)
+	return nil
+}
+
+// enqueue queues the exploded points for the forwarding connections of
+// their organizations.
+func (f *Forwarder) enqueue(ctx context.Context, points []models.Point) {
+	type batch struct {
+		orgID, bucketID influxdb.ID
+		points          []models.Point
+	}
+	var (
+		batches []*batch
+		byName  = make(map[string]*batch)
+	)
+	f.mu.RLock()
+	for _, p := range points {
+		if len(p.Name()) != len(tsdb.EncodeName(0, 0)) {
+			continue
+		}
+		b, ok := byName[string(p.Name())]
+		if !ok {
+			orgID, bucketID := tsdb.DecodeNameSlice(p.Name())
+			if len(f.forwarding[orgID]) > 0 {
+				b = &batch{orgID: orgID, bucketID: bucketID}
+				batches = append(batches, b)
+			}
+			byName[string(p.Name())] = b
+		}
+		if b != nil {
+			b.points = append(b.points, p)
+		}
+	}
+	f.mu.RUnlock()
+
+	queued := false
+	for _, b := range batches {
+		bkt, ok := f.bucket(ctx, b.bucketID)
+		if !ok || bkt.system {
+			continue
+		}
+		block := encodeBlock(bkt.name, lines(b.points))
+
+		f.mu.RLock()
+		for _, id := range f.forwarding[b.orgID] {
+			q, ok := f.queues[id]
+			if !ok {
+				continue
+			}
+			if err := q.q.Append(block); err != nil {
+				f.logger.Warn("Failed to queue write for remote", zap.Stringer("remote_id", id), zap.String("bucket", bkt.name), zap.Error(err))
+				continue
+			}
+			queued = true
+		}
+		f.mu.RUnlock()
+	}
+
+	if queued {
+		select {
+		case f.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// bucket returns the bucket of the ID.
+func (f *Forwarder) bucket(ctx context.Context, id influxdb.ID) (bucket, bool) {
+	f.mu.RLock()
+	b, ok := f.names[id]
+	f.mu.RUnlock()
+	if ok {
+		return b, true
+	}
+
+	bkt, err := f.buckets.FindBucketByID(ctx, id)
+	if err != nil {
+		f.logger.Debug("Failed to find bucket of forwarded write", zap.Stringer("bucket_id", id), zap.Error(err))
+		return bucket{}, false
+	}
+	b = bucket{name: bkt.Name, system: bkt.Type == influxdb.BucketTypeSystem}
+
+	f.mu.Lock()
+	f.names[id] = b
+	f.mu.Unlock()
+	return b, true
+}
+
+// Run forwards the queued writes every interval and whenever writes are
+// queued, until ctx is done.
+func (f *Forwarder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		f.Forward(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.refresh(ctx); err != nil && ctx.Err() == nil {
+				f.logger.Error("Unable to refresh forwarding remotes", zap.Error(err))
+			}
+		case <-f.notify:
+		}
+	}
+}
+
+// Forward forwards the queued writes of every connection, until its queue
+// is empty or its remote fails.
+func (f *Forwarder) Forward(ctx context.Context) {
+	f.mu.RLock()
+	queues := make([]*queue, 0, len(f.queues))
+	for _, q := range f.queues {
+		queues = append(queues, q)
+	}
+	f.mu.RUnlock()
+
+	for _, q := range queues {
+		if err := f.forward(ctx, q); err != nil && ctx.Err() == nil {
+			f.logger.Info("Unable to forward writes to remote", zap.Stringer("remote_id", q.rc.ID), zap.String("remote", q.rc.Name), zap.Error(err))
+		}
+	}
+}
+
+func (f *Forwarder) forward(ctx context.Context, q *queue) error {
+	for ctx.Err() == nil {
+		block, err := q.q.Peek()
+		if err != nil || block == nil {
+			return err
+		}
+
+		name, data, err := decodeBlock(block)
+		if err != nil {
+			f.logger.Error("Dropping invalid forward block", zap.Stringer("remote_id", q.rc.ID), zap.Error(err))
+		} else if err := f.send(ctx, q.rc, name, data); err != nil {
+			if influxdb.ErrorCode(err) != influxdb.EUnprocessableEntity {
+				return err
+			}
+			// The remote rejected some points, e.g. for its retention, and
+			// wrote the others. Sending them again would not change that.
+			f.logger.Warn("Remote rejected forwarded points", zap.Stringer("remote_id", q.rc.ID), zap.String("bucket", name), zap.Error(err))
+		}
+
+		if err := q.q.Advance(); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// lines returns the line protocol of the exploded points. The fields of the
+// points of the same series and time are written as one line, the last
+// value of a field winning as it does in storage.
+func lines(points []models.Point) []byte {
+	type line struct {
+		measurement []byte
+		tags        models.Tags
+		fields      models.Fields
+		time        time.Time
+	}
+	var (
+		order []*line
+		byKey = make(map[string]*line)
+	)
+	for _, p := range points {
+		var (
+			measurement []byte
+			tags        models.Tags
+		)
+		for _, t := range p.Tags() {
+			switch string(t.Key) {
+			case models.MeasurementTagKey:
+				measurement = t.Value
+			case models.FieldKeyTagKey:
+			default:
+				tags = append(tags, t)
+			}
+		}
+		key := string(models.MakeKey(measurement, tags)) + " " + strconv.FormatInt(p.UnixNano(), 10)
+		l, ok := byKey[key]
+		if !ok {
+			l = &line{measurement: measurement, tags: tags, fields: make(models.Fields), time: p.Time()}
+			byKey[key] = l
+			order = append(order, l)
+		}
+
+		itr := p.FieldIterator()
+		for itr.Next() {
+			var (
+				v   interface{}
+				err error
+			)
+			switch itr.Type() {
+			case models.Float:
+				v, err = itr.FloatValue()
+			case models.Integer:
+				v, err = itr.IntegerValue()
+			case models.Unsigned:
+				v, err = itr.UnsignedValue()
+			case models.Boolean:
+				v, err = itr.BooleanValue()
+			case models.String:
+				v = itr.StringValue()
+			default:
+				continue
+			}
+			if err == nil {
+				l.fields[string(itr.FieldKey())] = v
+			}
+		}
+	}
+
+	var buf []byte
+	for _, l := range order {
+		pt, err := models.NewPoint(string(l.measurement), l.tags, l.fields, l.time)
+		if err != nil {
+			continue
+		}
+		buf = append(pt.AppendString(buf), '\n')
+	}
+	return buf
+}
+
+// encodeBlock encodes the line protocol of the bucket as a block of a queue.
+func encodeBlock(bucket string, data []byte) []byte {
+	b := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(bucket)+len(data))
+	b = b[:binary.PutUvarint(b, uint64(len(bucket)))]
+	b = append(b, bucket...)
+	return append(b, data...)
+}
+
+// decodeBlock decodes the bucket and the line protocol of a block.
+func decodeBlock(b []byte) (string, []byte, error) {
+	n, i := binary.Uvarint(b)
+	if i <= 0 || uint64(len(b)-i) < n {
+		return "", nil, errors.New("invalid bucket of forward block")
+	}
+	return string(b[i : i+int(n)]), b[i+int(n):], nil
+}
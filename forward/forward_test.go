@@ -0,0 +1,176 @@
+package forward_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/forward"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type pointsWriter struct {
+	points []models.Point
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.points = append(w.points, points...)
+	return nil
+}
+
+type sent struct {
+	remote influxdb.ID
+	bucket string
+	data   string
+}
+
+func TestForwarder(t *testing.T) {
+	ctx := context.Background()
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	bucketID, systemBucketID := influxdb.ID(10), influxdb.ID(11)
+
+	dir, err := ioutil.TempDir("", "forward")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rcs := mock.NewRemoteConnectionService()
+	rcs.FindRemoteConnectionsFn = func(context.Context, influxdb.RemoteConnectionFilter, ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+		return []*influxdb.RemoteConnection{
+			{ID: 100, OrganizationID: orgID, Name: "cloud", Forward: true},
+			{ID: 101, OrganizationID: orgID, Name: "reader"},
+		}, 2, nil
+	}
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id == systemBucketID {
+			return &influxdb.Bucket{ID: id, Type: influxdb.BucketTypeSystem, Name: influxdb.TasksSystemBucketName}, nil
+		}
+		return &influxdb.Bucket{ID: id, Type: influxdb.BucketTypeUser, Name: "telegraf"}, nil
+	}
+
+	var (
+		sends   []sent
+		sendErr error
+	)
+	send := func(ctx context.Context, rc *influxdb.RemoteConnection, bucket string, data []byte) error {
+		if sendErr != nil {
+			return sendErr
+		}
+		sends = append(sends, sent{remote: rc.ID, bucket: bucket, data: string(data)})
+		return nil
+	}
+
+	open := func() (*forward.Forwarder, forward.PointsWriter, *pointsWriter) {
+		t.Helper()
+		f := forward.NewForwarder(dir, 0, rcs, bs, send, zaptest.NewLogger(t))
+		if err := f.Open(ctx); err != nil {
+			t.Fatal(err)
+		}
+		w := &pointsWriter{}
+		return f, f.PointsWriter(w), w
+	}
+	write := func(pw forward.PointsWriter, org, bucket influxdb.ID, lines string) {
+		t.Helper()
+		encoded := tsdb.EncodeName(org, bucket)
+		points, err := models.ParsePoints([]byte(lines), models.EscapeMeasurement(encoded[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pw.WritePoints(ctx, points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, pw, w := open()
+
+	// The remote is unreachable, but the writes are accepted locally.
+	sendErr = errors.New("connection refused")
+	write(pw, orgID, bucketID, "cpu,host=a usage=1,idle=2 10\ncpu,host=a usage=3 10\nmem,host=a used=4 10")
+	write(pw, orgID, systemBucketID, "runs,taskID=1 status=\"success\" 10")
+	write(pw, otherOrgID, bucketID, "cpu,host=b usage=5 10")
+	if len(w.points) != 6 {
+		t.Fatalf("expected the points to be written locally, got %d", len(w.points))
+	}
+	f.Forward(ctx)
+	if len(sends) != 0 {
+		t.Fatalf("unexpected sends to an unreachable remote %v", sends)
+	}
+
+	// The queue survives a restart.
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, _, _ = open()
+	defer f.Close()
+
+	sendErr = nil
+	f.Forward(ctx)
+	// The fields of a series at a time are forwarded as one point, the last
+	// value of a field winning.
+	want := []sent{{remote: 100, bucket: "telegraf", data: "cpu,host=a idle=2,usage=3 10\nmem,host=a used=4 10\n"}}
+	if len(sends) != len(want) || sends[0] != want[0] {
+		t.Fatalf("unexpected sends %+v, want %+v", sends, want)
+	}
+
+	// The forwarded writes are acknowledged.
+	sends = nil
+	f.Forward(ctx)
+	if len(sends) != 0 {
+		t.Fatalf("unexpected sends of acknowledged writes %v", sends)
+	}
+}
+
+func TestForwarder_RejectedPoints(t *testing.T) {
+	ctx := context.Background()
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(10)
+
+	dir, err := ioutil.TempDir("", "forward")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rcs := mock.NewRemoteConnectionService()
+	rcs.FindRemoteConnectionsFn = func(context.Context, influxdb.RemoteConnectionFilter, ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+		return []*influxdb.RemoteConnection{{ID: 100, OrganizationID: orgID, Name: "cloud", Forward: true}}, 1, nil
+	}
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, Type: influxdb.BucketTypeUser, Name: "telegraf"}, nil
+	}
+
+	var sends int
+	send := func(ctx context.Context, rc *influxdb.RemoteConnection, bucket string, data []byte) error {
+		sends++
+		return &influxdb.Error{Code: influxdb.EUnprocessableEntity, Msg: "partial write"}
+	}
+	f := forward.NewForwarder(dir, 0, rcs, bs, send, zaptest.NewLogger(t))
+	if err := f.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	points, err := models.ParsePoints([]byte("cpu usage=1 10"), models.EscapeMeasurement(encoded[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.PointsWriter(&pointsWriter{}).WritePoints(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+
+	// Points rejected by the remote are not sent again.
+	f.Forward(ctx)
+	f.Forward(ctx)
+	if sends != 1 {
+		t.Fatalf("expected the rejected points to be sent once, got %d sends", sends)
+	}
+}
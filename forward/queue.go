@@ -0,0 +1,342 @@
+package forward
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMaxSegmentSize is the size at which the queue starts a new segment.
+	DefaultMaxSegmentSize = 16 * 1024 * 1024
+
+	segmentExt   = ".seg"
+	headFile     = "head"
+	recordHeader = 8 // The length and the checksum of the block.
+)
+
+// ErrQueueFull is returned when appending a block would grow the queue
+// beyond its maximum size.
+var ErrQueueFull = errors.New("forward queue is full")
+
+// position is the position of a block in the queue.
+type position struct {
+	segment uint64
+	offset  int64
+}
+
+// Queue is a durable FIFO queue of blocks kept in the segment files of a
+// directory. A block stays in the queue until it is acknowledged, so the
+// blocks that were not forwarded survive restarts of the process.
+//
+// Every block is written with its length and checksum. When the queue is
+// opened, a block torn by a crash at the end of the last segment is
+// truncated.
+type Queue struct {
+	dir            string
+	maxSize        int64
+	maxSegmentSize int64
+
+	mu       sync.Mutex
+	segments []uint64         // The IDs of the segments, oldest first.
+	sizes    map[uint64]int64 // The size of every segment.
+	tail     *os.File         // The last segment, which blocks are appended to.
+	head     position         // The position of the first unacknowledged block.
+	next     position         // The position after the block returned by Peek.
+}
+
+// OpenQueue opens the queue of the directory, creating it if needed. A
+// maxSize of 0 does not limit the size of the queue.
+func OpenQueue(dir string, maxSize int64) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	q := &Queue{
+		dir:            dir,
+		maxSize:        maxSize,
+		maxSegmentSize: DefaultMaxSegmentSize,
+		sizes:          make(map[uint64]int64),
+	}
+
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(fi.Name(), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		q.segments = append(q.segments, id)
+		q.sizes[id] = fi.Size()
+	}
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i] < q.segments[j] })
+
+	if len(q.segments) == 0 {
+		if err := q.createSegment(1); err != nil {
+			return nil, err
+		}
+	} else if err := q.openTail(); err != nil {
+		return nil, err
+	}
+
+	q.head = position{segment: q.segments[0]}
+	if h, err := q.readHead(); err == nil && h.segment >= q.segments[0] && h.offset <= q.sizes[h.segment] {
+		if _, ok := q.sizes[h.segment]; ok {
+			q.head = h
+		}
+	}
+	// Remove the segments that were acknowledged before a crash.
+	for len(q.segments) > 1 && q.segments[0] < q.head.segment {
+		if err := q.removeSegment(); err != nil {
+			return nil, err
+		}
+	}
+	q.next = q.head
+	return q, nil
+}
+
+func (q *Queue) segmentPath(id uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", id, segmentExt))
+}
+
+func (q *Queue) createSegment(id uint64) error {
+	f, err := os.OpenFile(q.segmentPath(id), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if q.tail != nil {
+		q.tail.Close()
+	}
+	q.tail = f
+	q.segments = append(q.segments, id)
+	q.sizes[id] = 0
+	return nil
+}
+
+// openTail opens the last segment and truncates a block torn at its end.
+func (q *Queue) openTail() error {
+	id := q.segments[len(q.segments)-1]
+	f, err := os.OpenFile(q.segmentPath(id), os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	var valid int64
+	for {
+		b, err := readBlock(f, valid)
+		if err != nil {
+			break
+		}
+		valid += recordHeader + int64(len(b))
+	}
+	if valid < q.sizes[id] {
+		if err := f.Truncate(valid); err != nil {
+			f.Close()
+			return err
+		}
+		q.sizes[id] = valid
+	}
+	q.tail = f
+	return nil
+}
+
+// removeSegment removes the oldest segment.
+func (q *Queue) removeSegment() error {
+	id := q.segments[0]
+	if err := os.Remove(q.segmentPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	q.segments = q.segments[1:]
+	delete(q.sizes, id)
+	return nil
+}
+
+func (q *Queue) readHead() (position, error) {
+	b, err := ioutil.ReadFile(filepath.Join(q.dir, headFile))
+	if err != nil {
+		return position{}, err
+	}
+	if len(b) != 16 {
+		return position{}, fmt.Errorf("invalid forward queue head of %d bytes", len(b))
+	}
+	return position{
+		segment: binary.BigEndian.Uint64(b[:8]),
+		offset:  int64(binary.BigEndian.Uint64(b[8:])),
+	}, nil
+}
+
+// writeHead persists the head, replacing the file so that a crash leaves
+// either the previous or the new head.
+func (q *Queue) writeHead() error {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], q.head.segment)
+	binary.BigEndian.PutUint64(b[8:], uint64(q.head.offset))
+	tmp := filepath.Join(q.dir, headFile+".tmp")
+	if err := ioutil.WriteFile(tmp, b[:], 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(q.dir, headFile))
+}
+
+// readBlock reads the block at the offset of the segment.
+func readBlock(f *os.File, offset int64) ([]byte, error) {
+	var hdr [recordHeader]byte
+	if _, err := f.ReadAt(hdr[:], offset); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint32(hdr[:4]))
+	if _, err := f.ReadAt(b, offset+recordHeader); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(b) != binary.BigEndian.Uint32(hdr[4:]) {
+		return nil, fmt.Errorf("corrupt block at offset %d", offset)
+	}
+	return b, nil
+}
+
+// Append appends the block to the queue and syncs it to disk.
+func (q *Queue) Append(b []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := recordHeader + int64(len(b))
+	if q.maxSize > 0 && q.size()+n > q.maxSize {
+		return ErrQueueFull
+	}
+	id := q.segments[len(q.segments)-1]
+	if q.sizes[id] >= q.maxSegmentSize {
+		if err := q.tail.Sync(); err != nil {
+			return err
+		}
+		id++
+		if err := q.createSegment(id); err != nil {
+			return err
+		}
+	}
+
+	rec := make([]byte, n)
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(b)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(b))
+	copy(rec[recordHeader:], b)
+	if _, err := q.tail.Write(rec); err != nil {
+		return err
+	}
+	q.sizes[id] += n
+	return q.tail.Sync()
+}
+
+// Peek returns the first unacknowledged block, or nil if the queue is empty.
+// Peek returns the same block until it is acknowledged with Advance.
+func (q *Queue) Peek() ([]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		last := q.head.segment == q.segments[len(q.segments)-1]
+		if q.head.offset >= q.sizes[q.head.segment] {
+			if last {
+				return nil, nil
+			}
+			if err := q.skipSegment(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		f := q.tail
+		if !last {
+			var err error
+			if f, err = os.Open(q.segmentPath(q.head.segment)); err != nil {
+				return nil, err
+			}
+		}
+		b, err := readBlock(f, q.head.offset)
+		if !last {
+			f.Close()
+		}
+		if err != nil {
+			if last {
+				return nil, err
+			}
+			// The rest of a corrupt segment cannot be framed.
+			if err := q.skipSegment(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		q.next = position{segment: q.head.segment, offset: q.head.offset + recordHeader + int64(len(b))}
+		return b, nil
+	}
+}
+
+// skipSegment moves the head to the next segment and removes the head one.
+func (q *Queue) skipSegment() error {
+	q.head = position{segment: q.segments[1]}
+	q.next = q.head
+	if err := q.writeHead(); err != nil {
+		return err
+	}
+	return q.removeSegment()
+}
+
+// Advance acknowledges the block returned by Peek.
+func (q *Queue) Advance() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.next == q.head {
+		return nil
+	}
+	q.head = q.next
+	if err := q.writeHead(); err != nil {
+		return err
+	}
+	if q.head.offset < q.sizes[q.head.segment] {
+		return nil
+	}
+	if q.head.segment == q.segments[len(q.segments)-1] {
+		// The queue is empty, so the acknowledged blocks of the last segment
+		// are dropped by starting a new one.
+		if err := q.createSegment(q.head.segment + 1); err != nil {
+			return err
+		}
+	}
+	return q.skipSegment()
+}
+
+// Size returns the size in bytes of the segments of the queue.
+func (q *Queue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size()
+}
+
+func (q *Queue) size() int64 {
+	var n int64
+	for _, s := range q.sizes {
+		n += s
+	}
+	return n
+}
+
+// Close closes the queue. The unacknowledged blocks stay in its directory.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.tail.Close()
+}
@@ -0,0 +1,186 @@
+package forward
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestQueue(t *testing.T, maxSize int64) (*Queue, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "forward-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := OpenQueue(dir, maxSize)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return q, dir, func() {
+		q.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func mustPeek(t *testing.T, q *Queue, want string) {
+	t.Helper()
+	b, err := q.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want == "" && b != nil {
+		t.Fatalf("expected an empty queue, got %q", b)
+	}
+	if string(b) != want {
+		t.Fatalf("Peek() = %q, want %q", b, want)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q, dir, cleanup := newTestQueue(t, 0)
+	defer cleanup()
+
+	mustPeek(t, q, "")
+	for _, b := range []string{"a", "bb", "ccc"} {
+		if err := q.Append([]byte(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A block stays in the queue until it is acknowledged.
+	mustPeek(t, q, "a")
+	mustPeek(t, q, "a")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "bb")
+
+	// The unacknowledged blocks survive a restart.
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	q, err := OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "bb")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "ccc")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "")
+
+	// The acknowledged blocks of an empty queue are dropped.
+	if size := q.Size(); size != 0 {
+		t.Errorf("expected the empty queue to be truncated, got %d bytes", size)
+	}
+	if err := q.Append([]byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "d")
+	q.Close()
+}
+
+func TestQueue_Segments(t *testing.T) {
+	q, dir, cleanup := newTestQueue(t, 0)
+	defer cleanup()
+	q.maxSegmentSize = 20
+
+	blocks := []string{"block-1", "block-2", "block-3", "block-4", "block-5"}
+	for _, b := range blocks {
+		if err := q.Append([]byte(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	segments := func() int {
+		t.Helper()
+		matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(matches)
+	}
+	if n := segments(); n != 3 {
+		t.Fatalf("expected 3 segments, got %d", n)
+	}
+
+	for _, b := range blocks[:3] {
+		mustPeek(t, q, b)
+		if err := q.Advance(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The acknowledged segments are removed.
+	if n := segments(); n != 2 {
+		t.Fatalf("expected 2 segments, got %d", n)
+	}
+	mustPeek(t, q, "block-4")
+}
+
+func TestQueue_TornBlock(t *testing.T) {
+	q, dir, cleanup := newTestQueue(t, 0)
+	defer cleanup()
+
+	if err := q.Append([]byte("complete")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("torn")); err != nil {
+		t.Fatal(err)
+	}
+	q.Close()
+
+	// A crash leaves the last block incomplete.
+	path := q.segmentPath(q.segments[len(q.segments)-1])
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, fi.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	mustPeek(t, q, "complete")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "")
+	if err := q.Append([]byte("next")); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "next")
+}
+
+func TestQueue_Full(t *testing.T) {
+	q, _, cleanup := newTestQueue(t, 2*(recordHeader+4))
+	defer cleanup()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Append([]byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Append([]byte("data")); err != ErrQueueFull {
+		t.Fatalf("expected the queue to be full, got %v", err)
+	}
+
+	// Forwarding the blocks makes room.
+	for i := 0; i < 2; i++ {
+		mustPeek(t, q, "data")
+		if err := q.Advance(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Append([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -59,6 +59,18 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 			return collected, fmt.Errorf("reading text format failed: %s", err)
 		}
 	}
+	collected = MetricsCollection{
+		MetricsSlice: newMetricsSlice(metricFamilies, now),
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}
+
+	return collected, nil
+}
+
+// newMetricsSlice converts prometheus metric families into metrics. now is
+// the timestamp of metrics that do not carry their own.
+func newMetricsSlice(metricFamilies map[string]*dto.MetricFamily, now time.Time) MetricsSlice {
 	ms := make([]Metrics, 0)
 
 	// read metrics
@@ -102,13 +114,7 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 
 	}
 
-	collected = MetricsCollection{
-		MetricsSlice: ms,
-		OrgID:        target.OrgID,
-		BucketID:     target.BucketID,
-	}
-
-	return collected, nil
+	return ms
 }
 
 // Get labels from metric
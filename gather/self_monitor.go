@@ -0,0 +1,95 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+// SelfMonitor periodically records the metrics of the server's own
+// prometheus registry, such as Go runtime, storage engine and task
+// scheduler statistics, into the _monitoring system bucket of an
+// organization.
+type SelfMonitor struct {
+	Gatherer      prometheus.Gatherer
+	BucketService influxdb.BucketService
+	Recorder      Recorder
+
+	// OrgID is the organization whose _monitoring bucket receives the metrics.
+	OrgID influxdb.ID
+	// Interval is between each metrics gathering event.
+	Interval time.Duration
+
+	Logger *zap.Logger
+}
+
+// DefaultSelfMonitorInterval is the interval of a SelfMonitor created without one.
+const DefaultSelfMonitorInterval = 10 * time.Second
+
+// NewSelfMonitor returns a SelfMonitor recording the metrics of g into the
+// _monitoring bucket of the organization. A zero interval defaults to
+// DefaultSelfMonitorInterval; negative intervals are invalid.
+func NewSelfMonitor(l *zap.Logger, g prometheus.Gatherer, buckets influxdb.BucketService, r Recorder, orgID influxdb.ID, interval time.Duration) (*SelfMonitor, error) {
+	if interval < 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid self-monitoring interval %s; must not be negative", interval),
+		}
+	} else if interval == 0 {
+		interval = DefaultSelfMonitorInterval
+	}
+	return &SelfMonitor{
+		Gatherer:      g,
+		BucketService: buckets,
+		Recorder:      r,
+		OrgID:         orgID,
+		Interval:      interval,
+		Logger:        l,
+	}, nil
+}
+
+// Run records the metrics every interval until ctx is canceled.
+func (m *SelfMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.record(ctx, time.Now()); err != nil {
+				m.Logger.Error("Failed to record self-monitoring metrics", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (m *SelfMonitor) record(ctx context.Context, now time.Time) error {
+	// the bucket is looked up each time as the organization may be set up
+	// after the server has started.
+	b, err := m.BucketService.FindBucketByName(ctx, m.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	mfs, err := m.Gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	families := make(map[string]*dto.MetricFamily, len(mfs))
+	for _, mf := range mfs {
+		families[mf.GetName()] = mf
+	}
+
+	return m.Recorder.Record(MetricsCollection{
+		OrgID:        m.OrgID,
+		BucketID:     b.ID,
+		MetricsSlice: newMetricsSlice(families, now),
+	})
+}
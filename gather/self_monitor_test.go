@@ -0,0 +1,79 @@
+package gather
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+type collectionRecorder struct {
+	collected []MetricsCollection
+}
+
+func (r *collectionRecorder) Record(collected MetricsCollection) error {
+	r.collected = append(r.collected, collected)
+	return nil
+}
+
+func TestSelfMonitor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: "task_scheduler_total_execution_calls"})
+	reg.MustRegister(c)
+	c.Add(3)
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != *orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Fatalf("unexpected bucket lookup %s in org %s", name, id)
+		}
+		return &influxdb.Bucket{ID: *bucketID, OrgID: id, Name: name}, nil
+	}
+
+	r := &collectionRecorder{}
+	m, err := NewSelfMonitor(zap.NewNop(), reg, buckets, r, *orgID, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(100, 0)
+	if err := m.record(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.collected) != 1 {
+		t.Fatalf("expected 1 collection, got %d", len(r.collected))
+	}
+	got := r.collected[0]
+	if got.OrgID != *orgID || got.BucketID != *bucketID {
+		t.Errorf("recorded into org %s bucket %s, want org %s bucket %s", got.OrgID, got.BucketID, orgID, bucketID)
+	}
+	want := Metrics{
+		Name:      "task_scheduler_total_execution_calls",
+		Tags:      map[string]string{},
+		Fields:    map[string]interface{}{"counter": float64(3)},
+		Timestamp: now,
+		Type:      MetricTypeCounter,
+	}
+	if len(got.MetricsSlice) != 1 || !reflect.DeepEqual(got.MetricsSlice[0], want) {
+		t.Errorf("unexpected metrics: %+v", got.MetricsSlice)
+	}
+}
+
+func TestNewSelfMonitor_Interval(t *testing.T) {
+	m, err := NewSelfMonitor(zap.NewNop(), prometheus.NewRegistry(), mock.NewBucketService(), &collectionRecorder{}, *orgID, 0)
+	if err != nil {
+		t.Fatal(err)
+	} else if m.Interval != DefaultSelfMonitorInterval {
+		t.Errorf("got interval %s, want %s", m.Interval, DefaultSelfMonitorInterval)
+	}
+
+	if _, err := NewSelfMonitor(zap.NewNop(), prometheus.NewRegistry(), mock.NewBucketService(), &collectionRecorder{}, *orgID, -time.Second); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a negative interval to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// AccessLogMeasurement is the measurement name used when access log entries
+// are exported as points.
+const AccessLogMeasurement = "http_access"
+
+// AccessLogConfig configures the structured access log.
+type AccessLogConfig struct {
+	// SampleEvery logs only the first and then every nth request per route.
+	// Requests that fail with a server error or exceed SlowThreshold are
+	// always logged. Values below 2 disable sampling.
+	SampleEvery int
+	// SlowThreshold is the latency above which a request is always logged
+	// at warn level. Zero disables the slow request threshold.
+	SlowThreshold time.Duration
+	// Exporter, if set, receives every logged entry.
+	Exporter *AccessLogExporter
+}
+
+// AccessLogEntry is a single structured access log record.
+type AccessLogEntry struct {
+	Time            time.Time
+	Method          string
+	Route           string
+	Path            string
+	Status          int
+	ResponseBytes   int
+	Took            time.Duration
+	OrgID           platform.ID
+	UserID          platform.ID
+	AuthorizationID platform.ID
+	ErrorCode       string
+}
+
+// Fields returns the zap fields for the entry.
+func (e *AccessLogEntry) Fields() []zap.Field {
+	idField := func(key string, id platform.ID) zap.Field {
+		if !id.Valid() {
+			return zap.Skip()
+		}
+		return zap.Stringer(key, id)
+	}
+
+	errField := zap.Skip()
+	if e.ErrorCode != "" {
+		errField = zap.String("error_code", e.ErrorCode)
+	}
+
+	return []zap.Field{
+		zap.String("method", e.Method),
+		zap.String("route", e.Route),
+		zap.String("path", e.Path),
+		zap.Int("status_code", e.Status),
+		zap.Int("response_size", e.ResponseBytes),
+		zap.Duration("took", e.Took),
+		idField("org_id", e.OrgID),
+		idField("user_id", e.UserID),
+		idField("authorization_id", e.AuthorizationID),
+		errField,
+	}
+}
+
+// Point returns the entry as a point in the AccessLogMeasurement.
+func (e *AccessLogEntry) Point() (models.Point, error) {
+	tags := models.NewTags(map[string]string{
+		"method": e.Method,
+		"route":  e.Route,
+		"status": strconv.Itoa(e.Status),
+	})
+	fields := models.Fields{
+		"duration_ns":    e.Took.Nanoseconds(),
+		"response_bytes": int64(e.ResponseBytes),
+	}
+	if e.OrgID.Valid() {
+		fields["org_id"] = e.OrgID.String()
+	}
+	if e.UserID.Valid() {
+		fields["user_id"] = e.UserID.String()
+	}
+	if e.AuthorizationID.Valid() {
+		fields["authorization_id"] = e.AuthorizationID.String()
+	}
+	if e.ErrorCode != "" {
+		fields["error_code"] = e.ErrorCode
+	}
+	return models.NewPoint(AccessLogMeasurement, tags, fields, e.Time)
+}
+
+// AccessLogMW returns a middleware that writes a structured access log entry
+// for every sampled request. When the logger is enabled at debug level, every
+// request is logged, with the request details and body of the requests that
+// are not blacklisted, for debugging.
+func AccessLogMW(logger *zap.Logger, c AccessLogConfig) Middleware {
+	var counters sync.Map // route -> *uint64
+
+	sampled := func(route string) bool {
+		if c.SampleEvery < 2 {
+			return true
+		}
+		v, _ := counters.LoadOrStore(route, new(uint64))
+		n := atomic.AddUint64(v.(*uint64), 1)
+		return (n-1)%uint64(c.SampleEvery) == 0
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			srw := newStatusResponseWriter(w)
+			entry := &AccessLogEntry{
+				Time:   time.Now(),
+				Method: r.Method,
+				Path:   r.URL.Path,
+			}
+			ctx, id := withRequestIdentity(r.Context())
+			r = r.WithContext(ctx)
+
+			var body *bytes.Buffer
+			debug := logger.Core().Enabled(zap.DebugLevel)
+			if debug {
+				body = new(bytes.Buffer)
+				r.Body = &bodyEchoer{
+					rc:    r.Body,
+					teedR: io.TeeReader(r.Body, body),
+				}
+			}
+
+			defer func() {
+				entry.Took = time.Since(entry.Time)
+				entry.Route = id.route(r)
+				entry.Status = srw.code()
+				entry.ResponseBytes = srw.responseBytes
+				entry.ErrorCode = w.Header().Get(PlatformErrorCodeHeader)
+				entry.OrgID = id.OrgID
+				entry.UserID = id.UserID
+				entry.AuthorizationID = id.AuthorizationID
+
+				fields := entry.Fields()
+				if debug {
+					fields = append(fields, debugRequestFields(r, body)...)
+				}
+
+				slow := c.SlowThreshold > 0 && entry.Took >= c.SlowThreshold
+				switch {
+				case slow:
+					logger.Warn("Slow request", fields...)
+				case entry.Status >= http.StatusInternalServerError:
+					logger.Error("Request", fields...)
+				case sampled(entry.Route):
+					logger.Info("Request", fields...)
+				default:
+					logger.Debug("Request", fields...)
+					return
+				}
+
+				if c.Exporter != nil {
+					c.Exporter.record(entry)
+				}
+			}()
+
+			next.ServeHTTP(srw, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// debugRequestFields returns the zap fields of the request details logged at
+// debug level. The body is left out for blacklisted endpoints.
+func debugRequestFields(r *http.Request, body *bytes.Buffer) []zap.Field {
+	fields := []zap.Field{
+		zap.String("host", r.Host),
+		zap.String("query", redactedQuery(r.URL.Query())),
+		zap.String("proto", r.Proto),
+		zap.Int64("content_length", r.ContentLength),
+		zap.String("referrer", r.Referer()),
+		zap.String("remote", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+
+	invalidMethodFn, ok := mapURLPath(r.URL.Path)
+	if !ok || !invalidMethodFn(r.Method) {
+		fields = append(fields, zap.ByteString("body", body.Bytes()))
+	}
+	return fields
+}
+
+// redactedQueryParams are the query parameters carrying credentials: the
+// username and password of 1.x clients and the signature of write links.
+var redactedQueryParams = []string{"u", "p", "signature"}
+
+// redactedQuery returns the encoded query with the values of the
+// credential parameters replaced, so they are never logged.
+func redactedQuery(qp url.Values) string {
+	for _, k := range redactedQueryParams {
+		if _, ok := qp[k]; ok {
+			qp.Set(k, "[REDACTED]")
+		}
+	}
+	return qp.Encode()
+}
+
+// AccessLogExporter writes access log entries as points into a bucket.
+// Entries are batched and written in the background by Run; when the
+// buffer is full new entries are dropped rather than blocking requests.
+type AccessLogExporter struct {
+	OrgID         platform.ID
+	BucketID      platform.ID
+	Writer        storage.PointsWriter
+	Logger        *zap.Logger
+	FlushInterval time.Duration
+	BatchSize     int
+
+	entries chan AccessLogEntry
+	dropped uint64
+}
+
+// NewAccessLogExporter returns an exporter writing into the given bucket.
+func NewAccessLogExporter(orgID, bucketID platform.ID, w storage.PointsWriter, logger *zap.Logger) *AccessLogExporter {
+	return &AccessLogExporter{
+		OrgID:         orgID,
+		BucketID:      bucketID,
+		Writer:        w,
+		Logger:        logger,
+		FlushInterval: 10 * time.Second,
+		BatchSize:     1000,
+		entries:       make(chan AccessLogEntry, 10000),
+	}
+}
+
+func (e *AccessLogExporter) record(entry *AccessLogEntry) {
+	select {
+	case e.entries <- *entry:
+	default:
+		atomic.AddUint64(&e.dropped, 1)
+	}
+}
+
+// Run writes batched entries until ctx is canceled.
+func (e *AccessLogExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.Point, 0, e.BatchSize)
+	flush := func(ctx context.Context) {
+		if dropped := atomic.SwapUint64(&e.dropped, 0); dropped > 0 {
+			e.Logger.Warn("Dropped access log entries", zap.Uint64("dropped", dropped))
+		}
+		if len(batch) == 0 {
+			return
+		}
+		ps, err := tsdb.ExplodePoints(e.OrgID, e.BucketID, batch)
+		if err == nil {
+			err = e.Writer.WritePoints(ctx, ps)
+		}
+		if err != nil {
+			e.Logger.Error("Failed to write access log entries", zap.Error(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is already canceled, write the final batch without it.
+			flush(context.Background())
+			return
+		case <-ticker.C:
+			flush(ctx)
+		case entry := <-e.entries:
+			p, err := entry.Point()
+			if err != nil {
+				e.Logger.Error("Failed to encode access log entry", zap.Error(err))
+				continue
+			}
+			batch = append(batch, p)
+			if len(batch) >= e.BatchSize {
+				flush(ctx)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogMW(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("logs route, status and identity", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		auth := &platform.Authorization{ID: 3, OrgID: 1, UserID: 2}
+
+		router := NewRouter(ErrorHandler(0))
+		router.HandlerFunc("GET", "/api/v2/buckets/:id/labels", func(w http.ResponseWriter, r *http.Request) {
+			setRequestAuthorizer(r.Context(), auth)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("abc"))
+		})
+		h := AccessLogMW(zap.New(core), AccessLogConfig{})(router)
+
+		r := httptest.NewRequest("GET", "/api/v2/buckets/020f755c3c082000/labels", nil)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		if logs.Len() != 1 {
+			t.Fatalf("expected 1 log entry, got %d", logs.Len())
+		}
+		fields := logs.All()[0].ContextMap()
+		want := map[string]interface{}{
+			"route":            "/api/v2/buckets/:id/labels",
+			"status_code":      int64(http.StatusCreated),
+			"response_size":    int64(3),
+			"org_id":           auth.OrgID.String(),
+			"user_id":          auth.UserID.String(),
+			"authorization_id": auth.ID.String(),
+		}
+		for k, v := range want {
+			if fields[k] != v {
+				t.Errorf("field %s = %v, want %v", k, fields[k], v)
+			}
+		}
+	})
+
+	t.Run("samples per route", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		router := NewRouter(ErrorHandler(0))
+		router.Handler("GET", "/api/v2/buckets", okHandler)
+		router.Handler("GET", "/api/v2/orgs", okHandler)
+		h := AccessLogMW(zap.New(core), AccessLogConfig{SampleEvery: 3})(router)
+
+		for i := 0; i < 6; i++ {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/buckets", nil))
+		}
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/orgs", nil))
+
+		if got, want := logs.Len(), 3; got != want {
+			t.Fatalf("logged %d requests, want %d", got, want)
+		}
+	})
+
+	t.Run("samples unmatched requests as one route", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		h := AccessLogMW(zap.New(core), AccessLogConfig{SampleEvery: 3})(NewRouter(ErrorHandler(0)))
+
+		for _, p := range []string{"/a", "/b", "/c", "/d", "/e", "/f"} {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+		}
+
+		if got, want := logs.FilterField(zap.String("route", routeOther)).Len(), 2; got != want {
+			t.Fatalf("logged %d requests, want %d", got, want)
+		}
+	})
+
+	t.Run("logs unsampled requests at debug level", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		h := AccessLogMW(zap.New(core), AccessLogConfig{SampleEvery: 100})(okHandler)
+
+		for i := 0; i < 3; i++ {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/buckets", nil))
+		}
+
+		if got, want := logs.FilterField(zap.String("host", "example.com")).Len(), 3; got != want {
+			t.Errorf("logged %d requests with their details, want %d", got, want)
+		}
+		var debug int
+		for _, e := range logs.All() {
+			if e.Level == zap.DebugLevel {
+				debug++
+			}
+		}
+		if want := 2; debug != want {
+			t.Errorf("logged %d requests at debug level, want %d", debug, want)
+		}
+	})
+
+	t.Run("redacts credentials in the logged query", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		h := AccessLogMW(zap.New(core), AccessLogConfig{})(okHandler)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/write?db=mydb&u=me&p=secret&signature=abcdef", nil))
+
+		if logs.Len() != 1 {
+			t.Fatalf("expected 1 log entry, got %d", logs.Len())
+		}
+		got := logs.All()[0].ContextMap()["query"]
+		want := "db=mydb&p=%5BREDACTED%5D&signature=%5BREDACTED%5D&u=%5BREDACTED%5D"
+		if got != want {
+			t.Errorf("logged query %q, want %q", got, want)
+		}
+	})
+
+	t.Run("always logs slow requests and server errors", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		h := AccessLogMW(zap.New(core), AccessLogConfig{SampleEvery: 100, SlowThreshold: time.Millisecond})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/slow":
+				time.Sleep(2 * time.Millisecond)
+			case "/error":
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+
+		for _, p := range []string{"/fast", "/fast", "/slow", "/slow", "/error", "/error"} {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+		}
+
+		if got := logs.FilterMessage("Slow request").Len(); got != 2 {
+			t.Errorf("logged %d slow requests, want 2", got)
+		}
+		if got := logs.FilterField(zap.Int("status_code", http.StatusInternalServerError)).Len(); got != 2 {
+			t.Errorf("logged %d failed requests, want 2", got)
+		}
+		if got := logs.FilterField(zap.String("path", "/fast")).Len(); got != 1 {
+			t.Errorf("logged %d fast requests, want 1", got)
+		}
+	})
+}
+
+func TestAccessLogExporter(t *testing.T) {
+	w := &mock.PointsWriter{}
+	exporter := NewAccessLogExporter(1, 2, w, zap.NewNop())
+
+	h := AccessLogMW(zap.NewNop(), AccessLogConfig{Exporter: exporter})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/buckets", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/orgs", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exporter.Run(ctx)
+		close(done)
+	}()
+
+	// wait for the exporter to drain the queued entries before stopping it.
+	for len(exporter.entries) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	// each entry is exploded into one point per field: duration_ns and response_bytes.
+	if got := len(w.Points); got != 4 {
+		t.Fatalf("wrote %d points, want 4", got)
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+const (
+	alertsPath      = "/api/v2/alerts"
+	alertsIDAckPath = "/api/v2/alerts/:id/ack"
+)
+
+// AlertBackend is all services and associated parameters required to construct
+// the AlertHandler.
+type AlertBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AlertService        influxdb.AlertService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewAlertBackend returns a new instance of AlertBackend.
+func NewAlertBackend(b *APIBackend) *AlertBackend {
+	return &AlertBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "alert")),
+
+		AlertService:        b.AlertService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// AlertHandler is the handler for the alerts of checks.
+type AlertHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AlertService        influxdb.AlertService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewAlertHandler creates a new AlertHandler.
+func NewAlertHandler(b *AlertBackend) *AlertHandler {
+	h := &AlertHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		AlertService:        b.AlertService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", alertsPath, h.handleGetAlerts)
+	h.HandlerFunc("POST", alertsIDAckPath, h.handlePostAlertAck)
+
+	return h
+}
+
+type alertResponse struct {
+	*influxdb.Alert
+	Links map[string]string `json:"links"`
+}
+
+type alertsResponse struct {
+	OrgID                  influxdb.ID       `json:"orgID"`
+	UnacknowledgedCritical int               `json:"unacknowledgedCritical"`
+	Alerts                 []alertResponse   `json:"alerts"`
+	Links                  map[string]string `json:"links"`
+}
+
+func newAlertsResponse(s *influxdb.AlertSummary) alertsResponse {
+	resp := alertsResponse{
+		OrgID:                  s.OrgID,
+		UnacknowledgedCritical: s.UnacknowledgedCritical,
+		Alerts:                 make([]alertResponse, 0, len(s.Alerts)),
+		Links: map[string]string{
+			"self": fmt.Sprintf("%s?orgID=%s", alertsPath, s.OrgID),
+		},
+	}
+	for _, a := range s.Alerts {
+		resp.Alerts = append(resp.Alerts, alertResponse{
+			Alert: a,
+			Links: map[string]string{
+				"ack":   fmt.Sprintf("%s/%s/ack", alertsPath, a.CheckID),
+				"check": fmt.Sprintf("/api/v2/checks/%s", a.CheckID),
+			},
+		})
+	}
+	return resp
+}
+
+// handleGetAlerts is the HTTP handler for the GET /api/v2/alerts route.
+func (h *AlertHandler) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	summary, err := h.AlertService.FindAlerts(ctx, org.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("alerts retrieved", zap.String("org_id", org.ID.String()), zap.Int("unacknowledged_critical", summary.UnacknowledgedCritical))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAlertsResponse(summary)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeAlertID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// handlePostAlertAck is the HTTP handler for the POST /api/v2/alerts/:id/ack
+// route. The ID of an alert is the ID of its check.
+func (h *AlertHandler) handlePostAlertAck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	checkID, err := decodeAlertID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ack, err := h.AlertService.AcknowledgeAlert(ctx, checkID, auth.GetUserID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("alert acknowledged", zap.String("check_id", checkID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, ack); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestAlertHandler(t *testing.T) {
+	orgID, checkID, userID := influxdb.ID(1), influxdb.ID(10), influxdb.ID(100)
+	now := time.Date(2019, 11, 1, 3, 0, 0, 0, time.UTC)
+
+	svc := mock.NewAlertService()
+	svc.AcknowledgeAlertFn = func(ctx context.Context, id, uid influxdb.ID) (*influxdb.AlertAcknowledgement, error) {
+		if id != checkID || uid != userID {
+			t.Fatalf("unexpected acknowledgement of %s by %s", id, uid)
+		}
+		return &influxdb.AlertAcknowledgement{ID: 2, OrgID: orgID, CheckID: id, UserID: uid, Time: now}, nil
+	}
+	svc.FindAlertsFn = func(ctx context.Context, id influxdb.ID) (*influxdb.AlertSummary, error) {
+		return &influxdb.AlertSummary{
+			OrgID:                  id,
+			UnacknowledgedCritical: 1,
+			Alerts: []*influxdb.Alert{
+				{CheckID: checkID, CheckName: "cpu", Time: now, Message: "cpu is on fire"},
+			},
+		}, nil
+	}
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		if filter.Name == nil || *filter.Name != "org" {
+			t.Fatalf("unexpected organization filter %+v", filter)
+		}
+		return &influxdb.Organization{ID: orgID, Name: "org"}, nil
+	}
+
+	h := NewAlertHandler(&AlertBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		AlertService:        svc,
+		OrganizationService: orgs,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/alerts?org=org", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200: %s", w.Code, w.Body)
+	}
+	var alerts struct {
+		OrgID                  influxdb.ID `json:"orgID"`
+		UnacknowledgedCritical int         `json:"unacknowledgedCritical"`
+		Alerts                 []struct {
+			CheckID      influxdb.ID       `json:"checkID"`
+			Message      string            `json:"message"`
+			Acknowledged bool              `json:"acknowledged"`
+			Links        map[string]string `json:"links"`
+		} `json:"alerts"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &alerts); err != nil {
+		t.Fatal(err)
+	}
+	if alerts.OrgID != orgID || alerts.UnacknowledgedCritical != 1 || len(alerts.Alerts) != 1 {
+		t.Fatalf("unexpected alerts %s", w.Body)
+	}
+	if a := alerts.Alerts[0]; a.CheckID != checkID || a.Message != "cpu is on fire" || a.Acknowledged || a.Links["ack"] != "/api/v2/alerts/000000000000000a/ack" {
+		t.Fatalf("unexpected alert %s", w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/alerts/000000000000000a/ack", nil)
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: userID}))
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	var ack influxdb.AlertAcknowledgement
+	if err := json.Unmarshal(w.Body.Bytes(), &ack); err != nil {
+		t.Fatal(err)
+	}
+	if ack.ID != 2 || ack.CheckID != checkID || ack.UserID != userID || !ack.Time.Equal(now) {
+		t.Fatalf("unexpected acknowledgement %s", w.Body)
+	}
+}
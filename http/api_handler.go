@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/i18n"
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
@@ -25,20 +26,35 @@ type APIHandler struct {
 	CheckHandler                *CheckHandler
 	ChronografHandler           *ChronografHandler
 	DashboardHandler            *DashboardHandler
+	DBRPMappingHandler          *DBRPMappingHandler
 	DeleteHandler               *DeleteHandler
 	DocumentHandler             *DocumentHandler
+	ForecastHandler             *ForecastHandler
+	InstanceHandler             *InstanceHandler
 	LabelHandler                *LabelHandler
+	MaintenanceHandler          *MaintenanceHandler
 	NotificationEndpointHandler *NotificationEndpointHandler
 	NotificationRuleHandler     *NotificationRuleHandler
 	OrgHandler                  *OrgHandler
 	QueryHandler                *FluxHandler
+	RuntimeConfigHandler        *RuntimeConfigHandler
 	ScraperHandler              *ScraperHandler
+	SeriesFileHandler           *SeriesFileHandler
+	MaterializedViewHandler     *MaterializedViewHandler
+	BucketMigrationHandler      *BucketMigrationHandler
+	RemoteConnectionHandler     *RemoteConnectionHandler
+	NotebookHandler             *NotebookHandler
+	QueryTemplateHandler        *QueryTemplateHandler
+	ReportHandler               *ReportHandler
+	IngestRuleHandler           *IngestRuleHandler
+	AlertHandler                *AlertHandler
 	SessionHandler              *SessionHandler
 	SetupHandler                *SetupHandler
 	SourceHandler               *SourceHandler
 	SwaggerHandler              http.Handler
 	TaskHandler                 *TaskHandler
 	TelegrafHandler             *TelegrafHandler
+	TrashHandler                *TrashHandler
 	UserHandler                 *UserHandler
 	VariableHandler             *VariableHandler
 	WriteHandler                *WriteHandler
@@ -49,11 +65,28 @@ type APIHandler struct {
 // APIBackend is all services and associated parameters required to construct
 // an APIHandler.
 type APIBackend struct {
-	AssetsPath string // if empty then assets are served from bindata.
+	AssetsPath string   // if empty then assets are served from bindata.
+	UIBranding Branding // injected into the pages of the UI, if set.
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
 	SessionRenewDisabled bool
 
+	// AuthzDebugHeaderEnabled and AuthzDebugAuthorizations enable logging
+	// the authorization decisions of requests, see AuthenticationHandler.
+	AuthzDebugHeaderEnabled  bool
+	AuthzDebugAuthorizations []influxdb.ID
+
+	// AuthorizationUsageTracker records the last use of the authorizations
+	// authenticating requests, if set.
+	AuthorizationUsageTracker *AuthorizationUsageTracker
+
+	// SessionFingerprinter binds sessions to the clients signing in, if set.
+	SessionFingerprinter *SessionFingerprinter
+
+	// MessageCatalog translates the messages of error responses into the
+	// languages of the Accept-Language header of requests, if set.
+	MessageCatalog *i18n.Catalog
+
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
@@ -61,6 +94,10 @@ type APIBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	WriteLimits                     *WriteLimits
+	BodyLimit                       BodyLimitConfig
+	IdempotencyCache                *IdempotencyCache
+	APIRateLimiter                  *APIRateLimiter
 	DeleteService                   influxdb.DeleteService
 	AuthorizationService            influxdb.AuthorizationService
 	BucketService                   influxdb.BucketService
@@ -71,6 +108,8 @@ type APIBackend struct {
 	LabelService                    influxdb.LabelService
 	DashboardService                influxdb.DashboardService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
+	DashboardRenderService          influxdb.DashboardRenderService
+	DBRPMappingService              influxdb.DBRPMappingServiceV2
 	BucketOperationLogService       influxdb.BucketOperationLogService
 	UserOperationLogService         influxdb.UserOperationLogService
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
@@ -81,7 +120,12 @@ type APIBackend struct {
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
 	TaskService                     influxdb.TaskService
+	TrashService                    influxdb.TrashService
+	TaskBackfillService             influxdb.TaskBackfillService
+	TaskLintService                 influxdb.TaskLintService
 	CheckService                    influxdb.CheckService
+	MonitoringRunService            influxdb.MonitoringRunService
+	CheckStatusStream               influxdb.CheckStatusStream
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
 	SecretService                   influxdb.SecretService
@@ -91,6 +135,31 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	RuntimeConfigService            influxdb.RuntimeConfigService
+	InstanceService                 influxdb.InstanceService
+	UsageService                    influxdb.UsageService
+	OrgSettingsService              influxdb.OrganizationSettingsService
+	UserSettingsService             influxdb.UserSettingsService
+	SessionAlertService             influxdb.SessionAlertService
+	WriteLinkService                influxdb.WriteLinkService
+	ResourceACLService              influxdb.ResourceACLService
+	ShardService                    influxdb.ShardService
+	BucketOptimizationService       influxdb.BucketOptimizationService
+	BucketSchemaService             influxdb.BucketSchemaService
+	BucketSampleService             influxdb.BucketSampleService
+	MeasurementLastWriteService     influxdb.MeasurementLastWriteService
+	SeriesFileService               influxdb.SeriesFileService
+	MaterializedViewService         influxdb.MaterializedViewService
+	BucketMigrationService          influxdb.BucketMigrationService
+	RemoteConnectionService         influxdb.RemoteConnectionService
+	NotebookService                 influxdb.NotebookService
+	QueryTemplateService            influxdb.QueryTemplateService
+	ReportService                   influxdb.ReportService
+	ReportDeliveryService           influxdb.ReportDeliveryService
+	IngestRuleService               influxdb.IngestRuleService
+	AlertService                    influxdb.AlertService
+	ForecastService                 influxdb.ForecastService
+	MaintenanceService              influxdb.MaintenanceService
 }
 
 // PrometheusCollectors exposes the prometheus collectors associated with an APIBackend.
@@ -148,21 +217,41 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 
 	bucketBackend := NewBucketBackend(b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.ShardService = authorizer.NewShardService(b.OrgLookupService, b.ShardService)
+	bucketBackend.BucketOptimizationService = authorizer.NewBucketOptimizationService(b.OrgLookupService, b.BucketOptimizationService)
+	bucketBackend.BucketSchemaService = authorizer.NewBucketSchemaService(b.OrgLookupService, b.BucketSchemaService)
+	bucketBackend.BucketSampleService = authorizer.NewBucketSampleService(b.OrgLookupService, b.BucketSampleService)
+	if b.MeasurementLastWriteService != nil {
+		bucketBackend.MeasurementLastWriteService = authorizer.NewMeasurementLastWriteService(b.OrgLookupService, b.MeasurementLastWriteService)
+	}
 	h.BucketHandler = NewBucketHandler(bucketBackend)
 
 	orgBackend := NewOrgBackend(b)
 	orgBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	orgBackend.UsageService = authorizer.NewUsageService(b.UsageService)
+	orgBackend.OrgSettingsService = authorizer.NewOrgSettingsService(b.OrgSettingsService)
+	orgBackend.SecretService = authorizer.NewSecretService(b.SecretService)
 	h.OrgHandler = NewOrgHandler(orgBackend)
 
 	userBackend := NewUserBackend(b)
 	userBackend.UserService = authorizer.NewUserService(b.UserService)
 	userBackend.PasswordsService = authorizer.NewPasswordService(b.PasswordsService)
+	userBackend.UserSettingsService = authorizer.NewUserSettingsService(b.UserSettingsService)
+	userBackend.SessionAlertService = authorizer.NewSessionAlertService(b.SessionAlertService)
 	h.UserHandler = NewUserHandler(userBackend)
 
 	dashboardBackend := NewDashboardBackend(b)
-	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService, b.ResourceACLService)
+	dashboardBackend.TrashService = authorizer.NewTrashService(b.TrashService, b.ResourceACLService)
+	dashboardBackend.DashboardRenderService = authorizer.NewDashboardRenderService(b.DashboardRenderService, b.DashboardService, b.ResourceACLService)
+	dashboardBackend.ResourceACLService = authorizer.NewResourceACLService(b.OrgLookupService, b.ResourceACLService)
 	h.DashboardHandler = NewDashboardHandler(dashboardBackend)
 
+	dbrpBackend := NewDBRPMappingBackend(b)
+	dbrpBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	dbrpBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.DBRPMappingHandler = NewDBRPMappingHandler(dbrpBackend)
+
 	variableBackend := NewVariableBackend(b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.VariableHandler = NewVariableHandler(variableBackend)
@@ -186,9 +275,16 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.SetupHandler = NewSetupHandler(setupBackend)
 
 	taskBackend := NewTaskBackend(b)
+	taskBackend.TrashService = authorizer.NewTrashService(b.TrashService, b.ResourceACLService)
+	taskBackend.TaskBackfillService = authorizer.NewTaskBackfillService(b.TaskBackfillService, b.TaskService)
+	taskBackend.TaskLintService = authorizer.NewTaskLintService(b.TaskLintService)
 	h.TaskHandler = NewTaskHandler(taskBackend)
 	h.TaskHandler.UserResourceMappingService = internalURM
 
+	trashBackend := NewTrashBackend(b)
+	trashBackend.TrashService = authorizer.NewTrashService(b.TrashService, b.ResourceACLService)
+	h.TrashHandler = NewTrashHandler(trashBackend)
+
 	telegrafBackend := NewTelegrafBackend(b)
 	telegrafBackend.TelegrafService = authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)
 	h.TelegrafHandler = NewTelegrafHandler(telegrafBackend)
@@ -196,6 +292,8 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	notificationRuleBackend := NewNotificationRuleBackend(b)
 	notificationRuleBackend.NotificationRuleStore = authorizer.NewNotificationRuleStore(b.NotificationRuleStore,
 		b.UserResourceMappingService, b.OrganizationService)
+	notificationRuleBackend.MonitoringRunService = authorizer.NewMonitoringRunService(b.MonitoringRunService,
+		b.CheckService, b.NotificationRuleStore)
 	h.NotificationRuleHandler = NewNotificationRuleHandler(notificationRuleBackend)
 
 	notificationEndpointBackend := NewNotificationEndpointBackend(b)
@@ -206,6 +304,9 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	checkBackend := NewCheckBackend(b)
 	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService,
 		b.UserResourceMappingService, b.OrganizationService)
+	checkBackend.CheckStatusStream = authorizer.NewCheckStatusStream(b.CheckStatusStream)
+	checkBackend.MonitoringRunService = authorizer.NewMonitoringRunService(b.MonitoringRunService,
+		b.CheckService, b.NotificationRuleStore)
 	h.CheckHandler = NewCheckHandler(checkBackend)
 
 	writeBackend := NewWriteBackend(b)
@@ -217,6 +318,59 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	fluxBackend := NewFluxBackend(b)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	runtimeConfigBackend := NewRuntimeConfigBackend(b)
+	runtimeConfigBackend.RuntimeConfigService = authorizer.NewRuntimeConfigService(b.RuntimeConfigService)
+	h.RuntimeConfigHandler = NewRuntimeConfigHandler(runtimeConfigBackend)
+
+	instanceBackend := NewInstanceBackend(b)
+	h.InstanceHandler = NewInstanceHandler(instanceBackend)
+
+	seriesFileBackend := NewSeriesFileBackend(b)
+	seriesFileBackend.SeriesFileService = authorizer.NewSeriesFileService(b.SeriesFileService)
+	h.SeriesFileHandler = NewSeriesFileHandler(seriesFileBackend)
+
+	materializedViewBackend := NewMaterializedViewBackend(b)
+	materializedViewBackend.MaterializedViewService = authorizer.NewMaterializedViewService(b.MaterializedViewService)
+	h.MaterializedViewHandler = NewMaterializedViewHandler(materializedViewBackend)
+
+	bucketMigrationBackend := NewBucketMigrationBackend(b)
+	bucketMigrationBackend.BucketMigrationService = authorizer.NewBucketMigrationService(b.BucketMigrationService)
+	h.BucketMigrationHandler = NewBucketMigrationHandler(bucketMigrationBackend)
+
+	remoteConnectionBackend := NewRemoteConnectionBackend(b)
+	remoteConnectionBackend.RemoteConnectionService = authorizer.NewRemoteConnectionService(b.RemoteConnectionService)
+	h.RemoteConnectionHandler = NewRemoteConnectionHandler(remoteConnectionBackend)
+
+	notebookBackend := NewNotebookBackend(b)
+	notebookBackend.NotebookService = authorizer.NewNotebookService(b.NotebookService, b.ResourceACLService)
+	notebookBackend.ResourceACLService = authorizer.NewResourceACLService(b.OrgLookupService, b.ResourceACLService)
+	h.NotebookHandler = NewNotebookHandler(notebookBackend)
+
+	queryTemplateBackend := NewQueryTemplateBackend(b)
+	queryTemplateBackend.QueryTemplateService = authorizer.NewQueryTemplateService(b.QueryTemplateService)
+	h.QueryTemplateHandler = NewQueryTemplateHandler(queryTemplateBackend)
+
+	reportBackend := NewReportBackend(b)
+	reportBackend.ReportService = authorizer.NewReportService(b.ReportService, b.DashboardService, b.ResourceACLService)
+	reportBackend.ReportDeliveryService = authorizer.NewReportDeliveryService(b.ReportDeliveryService, reportBackend.ReportService)
+	h.ReportHandler = NewReportHandler(reportBackend)
+
+	ingestRuleBackend := NewIngestRuleBackend(b)
+	ingestRuleBackend.IngestRuleService = authorizer.NewIngestRuleService(b.OrgLookupService, b.IngestRuleService)
+	h.IngestRuleHandler = NewIngestRuleHandler(ingestRuleBackend)
+
+	alertBackend := NewAlertBackend(b)
+	alertBackend.AlertService = authorizer.NewAlertService(b.AlertService, b.CheckService)
+	h.AlertHandler = NewAlertHandler(alertBackend)
+
+	forecastBackend := NewForecastBackend(b)
+	forecastBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.ForecastHandler = NewForecastHandler(forecastBackend)
+
+	maintenanceBackend := NewMaintenanceBackend(b)
+	maintenanceBackend.MaintenanceService = authorizer.NewMaintenanceService(b.MaintenanceService)
+	h.MaintenanceHandler = NewMaintenanceHandler(maintenanceBackend)
+
 	h.ChronografHandler = NewChronografHandler(b.ChronografService, b.HTTPErrorHandler)
 	h.SwaggerHandler = newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler)
 	h.LabelHandler = NewLabelHandler(authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler)
@@ -229,13 +383,22 @@ var apiLinks = map[string]interface{}{
 	// as this makes it easier to verify values against the swagger document.
 	"authorizations": "/api/v2/authorizations",
 	"buckets":        "/api/v2/buckets",
-	"dashboards":     "/api/v2/dashboards",
+	"config": map[string]string{
+		"runtime": "/api/v2/config/runtime",
+	},
+	"dashboards": "/api/v2/dashboards",
+	"dbrps":      "/api/v2/dbrps",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
+	"forecast":              "/api/v2/forecast",
+	"ingestRules":           "/api/v2/ingestRules",
+	"instances":             "/api/v2/instances",
 	"labels":                "/api/v2/labels",
+	"maintenance":           "/api/v2/maintenance",
 	"variables":             "/api/v2/variables",
 	"me":                    "/api/v2/me",
+	"notebooks":             "/api/v2/notebooks",
 	"notificationRules":     "/api/v2/notificationRules",
 	"notificationEndpoints": "/api/v2/notificationEndpoints",
 	"orgs":                  "/api/v2/orgs",
@@ -244,7 +407,10 @@ var apiLinks = map[string]interface{}{
 		"ast":         "/api/v2/query/ast",
 		"analyze":     "/api/v2/query/analyze",
 		"suggestions": "/api/v2/query/suggestions",
+		"templates":   "/api/v2/query/templates",
 	},
+	"remotes":  "/api/v2/remotes",
+	"reports":  "/api/v2/reports",
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
@@ -259,6 +425,7 @@ var apiLinks = map[string]interface{}{
 	"tasks":     "/api/v2/tasks",
 	"checks":    "/api/v2/checks",
 	"telegrafs": "/api/v2/telegrafs",
+	"trash":     "/api/v2/trash",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 	"delete":    "/api/v2/delete",
@@ -273,7 +440,6 @@ func (h *APIHandler) serveLinks(w http.ResponseWriter, r *http.Request) {
 
 // ServeHTTP delegates a request to the appropriate subhandler.
 func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	setCORSResponseHeaders(w, r)
 	if r.Method == "OPTIONS" {
 		return
 	}
@@ -304,6 +470,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, queryTemplatesPath) {
+		h.QueryTemplateHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
@@ -379,11 +550,81 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dbrps") {
+		h.DBRPMappingHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/trash") {
+		h.TrashHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/variables") {
 		h.VariableHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if r.URL.Path == runtimeConfigPath {
+		h.RuntimeConfigHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == instancesPath {
+		h.InstanceHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, seriesFilePath) {
+		h.SeriesFileHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, materializedViewsPath) {
+		h.MaterializedViewHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, bucketMigrationsPath) {
+		h.BucketMigrationHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, remotesPath) {
+		h.RemoteConnectionHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == forecastPath {
+		h.ForecastHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, reportsPath) {
+		h.ReportHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, notebooksPath) {
+		h.NotebookHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, ingestRulesPath) {
+		h.IngestRuleHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, alertsPath) {
+		h.AlertHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == maintenancePath {
+		h.MaintenanceHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/documents") {
 		h.DocumentHandler.ServeHTTP(w, r)
 		return
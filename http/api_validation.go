@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// loadSwagger loads the swagger document served by the API.
+func loadSwagger(logger *zap.Logger) (*openapi3.Swagger, error) {
+	data, err := newSwaggerLoader(logger, nil).asset(Asset("swagger.yml"))
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
+}
+
+// APIValidationMW returns a middleware that validates requests and responses
+// of the API against the swagger document. Requests that do not conform to
+// the document are rejected with the details of the violation, and responses
+// that do not conform are logged. Requests to routes missing from the
+// document are passed through. Validation buffers request and response
+// bodies and is intended for debugging clients.
+func APIValidationMW(logger *zap.Logger) (Middleware, error) {
+	swagger, err := loadSwagger(logger)
+	if err != nil {
+		return nil, err
+	}
+	router := openapi3filter.NewRouter()
+	if err := router.AddSwagger(swagger); err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		// authentication is checked by the authentication handler.
+		AuthenticationFunc: func(context.Context, *openapi3filter.AuthenticationInput) error {
+			return nil
+		},
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			route, pathParams, err := router.FindRoute(r.Method, r.URL)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reqOptions := options
+			if r.Header.Get("Content-Encoding") != "" {
+				// compressed bodies are decoded by the handlers.
+				reqOptions = &openapi3filter.Options{
+					ExcludeRequestBody: true,
+					AuthenticationFunc: options.AuthenticationFunc,
+				}
+			}
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    reqOptions,
+			}
+			if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+				ErrorHandler(0).HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Op:   "http/APIValidationMW",
+					Msg:  fmt.Sprintf("request does not conform to %s %s of the API: %v", route.Method, route.Path, err),
+				}, w)
+				return
+			}
+
+			rec := &validationResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			if !rec.json {
+				return
+			}
+			res := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rec.code(),
+				Header:                 w.Header(),
+				Options:                options,
+			}
+			res.SetBodyBytes(rec.body.Bytes())
+			if err := openapi3filter.ValidateResponse(ctx, res); err != nil {
+				logger.Warn("Response does not conform to the API",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("route", route.Path),
+					zap.Int("status_code", rec.code()),
+					zap.Error(err))
+			}
+		}
+		return http.HandlerFunc(fn)
+	}, nil
+}
+
+// validationResponseWriter passes a response through while recording the
+// status code and, for JSON responses, the body.
+type validationResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	json        bool
+	body        bytes.Buffer
+}
+
+func (w *validationResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.statusCode = code
+		w.json = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *validationResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.json {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client, if the underlying
+// ResponseWriter supports it.
+func (w *validationResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *validationResponseWriter) code() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
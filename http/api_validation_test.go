@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAPIValidationMW(t *testing.T) {
+	os.Setenv("INFLUXDB_VALID_SWAGGER_PATH", "./swagger.yml")
+	defer os.Unsetenv("INFLUXDB_VALID_SWAGGER_PATH")
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	mw, err := APIValidationMW(zap.New(core))
+	if err != nil {
+		t.Fatalf("unable to create validation middleware: %v", err)
+	}
+
+	var called int
+	response := `{"id":"020f755c3c082000","orgID":"020f755c3c082001","name":"b","retentionRules":[]}`
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(response))
+	}))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do("POST", "/api/v2/buckets", `{"orgID":"020f755c3c082001","name":"b","retentionRules":[]}`)
+	if w.Code != http.StatusCreated || called != 1 {
+		t.Fatalf("expected conforming request to be served, got %d: %s", w.Code, w.Body.String())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected conforming response not to be logged, got %v", logs.All())
+	}
+
+	w = do("POST", "/api/v2/buckets", `{"orgID":"020f755c3c082001","retentionRules":[]}`)
+	if w.Code != http.StatusBadRequest || called != 1 {
+		t.Fatalf("expected request without name to be rejected, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "name") {
+		t.Errorf("expected error to name the missing property, got %s", w.Body.String())
+	}
+
+	w = do("GET", "/api/v2/buckets?limit=1000", "")
+	if w.Code != http.StatusBadRequest || called != 1 {
+		t.Fatalf("expected request with invalid parameter to be rejected, got %d", w.Code)
+	}
+
+	if w = do("GET", "/api/v2/undocumented", ""); called != 2 {
+		t.Fatalf("expected request to undocumented route to be served, got %d", w.Code)
+	}
+
+	response = `{"id":1}`
+	do("POST", "/api/v2/buckets", `{"orgID":"020f755c3c082001","name":"b","retentionRules":[]}`)
+	if logs.FilterMessage("Response does not conform to the API").Len() != 1 {
+		t.Errorf("expected nonconforming response to be logged, got %v", logs.All())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"path/filepath"
 
+	"github.com/influxdata/influxdb"
 	// TODO: use platform version of the code
 	"github.com/influxdata/influxdb/chronograf"
 	"github.com/influxdata/influxdb/chronograf/dist"
@@ -23,6 +24,13 @@ const (
 // AssetHandler is an http handler for serving chronograf assets.
 type AssetHandler struct {
 	Path string
+
+	// Branding is injected into the HTML pages of the UI, if set.
+	Branding Branding
+
+	// MaintenanceService, if set, is used to show the maintenance of the
+	// instance in the banner of the UI.
+	MaintenanceService influxdb.MaintenanceService
 }
 
 // NewAssetHandler is the constructor an asset handler.
@@ -46,5 +54,55 @@ func (h *AssetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	assets.Handler().ServeHTTP(w, r)
+	if !isPageRequest(r) {
+		assets.Handler().ServeHTTP(w, r)
+		return
+	}
+	branding := h.branding(r)
+	if branding.Empty() {
+		assets.Handler().ServeHTTP(w, r)
+		return
+	}
+
+	// The cached and partial versions of the page are without the branding.
+	header := make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		header[k] = v
+	}
+	header.Del("If-None-Match")
+	header.Del("If-Modified-Since")
+	header.Del("Range")
+	r = r.WithContext(r.Context())
+	r.Header = header
+
+	bw := &brandingResponseWriter{ResponseWriter: w, branding: branding}
+	assets.Handler().ServeHTTP(bw, r)
+	if err := bw.flush(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// branding returns the branding of the pages, with the maintenance of the
+// instance as the banner message if it is in maintenance.
+func (h *AssetHandler) branding(r *http.Request) Branding {
+	b := h.Branding
+	if h.MaintenanceService == nil {
+		return b
+	}
+
+	ms, err := h.MaintenanceService.FindMaintenances(r.Context())
+	if err != nil {
+		return b
+	}
+	for _, m := range ms {
+		if m.OrganizationID != nil {
+			continue
+		}
+		b.BannerMessage = "This instance is in maintenance mode"
+		if m.Message != "" {
+			b.BannerMessage += ": " + m.Message
+		}
+		b.BannerColor = maintenanceBannerColor
+	}
+	return b
 }
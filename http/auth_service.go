@@ -44,7 +44,7 @@ func NewAuthorizationBackend(b *APIBackend) *AuthorizationBackend {
 
 // AuthorizationHandler represents an HTTP API handler for authorizations.
 type AuthorizationHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -76,53 +76,62 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 }
 
 type authResponse struct {
-	ID          platform.ID          `json:"id"`
-	Token       string               `json:"token"`
-	Status      platform.Status      `json:"status"`
-	Description string               `json:"description"`
-	OrgID       platform.ID          `json:"orgID"`
-	Org         string               `json:"org"`
-	UserID      platform.ID          `json:"userID"`
-	User        string               `json:"user"`
-	Permissions []permissionResponse `json:"permissions"`
-	Links       map[string]string    `json:"links"`
-	CreatedAt   time.Time            `json:"createdAt"`
-	UpdatedAt   time.Time            `json:"updatedAt"`
+	ID             platform.ID          `json:"id"`
+	Token          string               `json:"token"`
+	Status         platform.Status      `json:"status"`
+	Description    string               `json:"description"`
+	OrgID          platform.ID          `json:"orgID"`
+	Org            string               `json:"org"`
+	UserID         platform.ID          `json:"userID"`
+	User           string               `json:"user"`
+	Permissions    []permissionResponse `json:"permissions"`
+	TagConstraints []platform.Tag       `json:"tagConstraints,omitempty"`
+	Links          map[string]string    `json:"links"`
+	CreatedAt      time.Time            `json:"createdAt"`
+	UpdatedAt      time.Time            `json:"updatedAt"`
+	LastUsedAt     *time.Time           `json:"lastUsedAt,omitempty"`
+	LastUsedIP     string               `json:"lastUsedIP,omitempty"`
 }
 
 func newAuthResponse(a *platform.Authorization, org *platform.Organization, user *platform.User, ps []permissionResponse) *authResponse {
 	res := &authResponse{
-		ID:          a.ID,
-		Token:       a.Token,
-		Status:      a.Status,
-		Description: a.Description,
-		OrgID:       a.OrgID,
-		UserID:      a.UserID,
-		User:        user.Name,
-		Org:         org.Name,
-		Permissions: ps,
+		ID:             a.ID,
+		Token:          a.Token,
+		Status:         a.Status,
+		Description:    a.Description,
+		OrgID:          a.OrgID,
+		UserID:         a.UserID,
+		User:           user.Name,
+		Org:            org.Name,
+		Permissions:    ps,
+		TagConstraints: a.TagConstraints,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
 		},
-		CreatedAt: a.CreatedAt,
-		UpdatedAt: a.UpdatedAt,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+		LastUsedAt: a.LastUsedAt,
+		LastUsedIP: a.LastUsedIP,
 	}
 	return res
 }
 
 func (a *authResponse) toPlatform() *platform.Authorization {
 	res := &platform.Authorization{
-		ID:          a.ID,
-		Token:       a.Token,
-		Status:      a.Status,
-		Description: a.Description,
-		OrgID:       a.OrgID,
-		UserID:      a.UserID,
+		ID:             a.ID,
+		Token:          a.Token,
+		Status:         a.Status,
+		Description:    a.Description,
+		OrgID:          a.OrgID,
+		UserID:         a.UserID,
+		TagConstraints: a.TagConstraints,
 		CRUDLog: platform.CRUDLog{
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
 		},
+		LastUsedAt: a.LastUsedAt,
+		LastUsedIP: a.LastUsedIP,
 	}
 	for _, p := range a.Permissions {
 		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
@@ -240,29 +249,32 @@ func (h *AuthorizationHandler) handlePostAuthorization(w http.ResponseWriter, r
 }
 
 type postAuthorizationRequest struct {
-	Status      platform.Status       `json:"status"`
-	OrgID       platform.ID           `json:"orgID"`
-	UserID      *platform.ID          `json:"userID,omitempty"`
-	Description string                `json:"description"`
-	Permissions []platform.Permission `json:"permissions"`
+	Status         platform.Status       `json:"status"`
+	OrgID          platform.ID           `json:"orgID"`
+	UserID         *platform.ID          `json:"userID,omitempty"`
+	Description    string                `json:"description"`
+	Permissions    []platform.Permission `json:"permissions"`
+	TagConstraints []platform.Tag        `json:"tagConstraints,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
 	return &platform.Authorization{
-		OrgID:       p.OrgID,
-		Status:      p.Status,
-		Description: p.Description,
-		Permissions: p.Permissions,
-		UserID:      userID,
+		OrgID:          p.OrgID,
+		Status:         p.Status,
+		Description:    p.Description,
+		Permissions:    p.Permissions,
+		TagConstraints: p.TagConstraints,
+		UserID:         userID,
 	}
 }
 
 func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationRequest, error) {
 	res := &postAuthorizationRequest{
-		OrgID:       a.OrgID,
-		Description: a.Description,
-		Permissions: a.Permissions,
-		Status:      a.Status,
+		OrgID:          a.OrgID,
+		Description:    a.Description,
+		Permissions:    a.Permissions,
+		TagConstraints: a.TagConstraints,
+		Status:         a.Status,
 	}
 
 	if a.UserID.Valid() {
@@ -296,6 +308,16 @@ func (p *postAuthorizationRequest) Validate() error {
 		}
 	}
 
+	for _, t := range p.TagConstraints {
+		if err := t.Valid(); err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid tag constraint",
+				Err:  err,
+			}
+		}
+	}
+
 	if !p.OrgID.Valid() {
 		return &platform.Error{
 			Err:  platform.ErrInvalidID,
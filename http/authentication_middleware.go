@@ -9,6 +9,7 @@ import (
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	platcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/jsonweb"
 	"go.uber.org/zap"
@@ -25,10 +26,40 @@ type AuthenticationHandler struct {
 	TokenParser          *jsonweb.TokenParser
 	SessionRenewDisabled bool
 
+	// AuthzDebugHeaderEnabled logs the authorization decisions of requests
+	// with the AuthzDebugHeader header.
+	AuthzDebugHeaderEnabled bool
+	// AuthzDebugAuthorizations are the IDs of the authorizations and
+	// sessions whose authorization decisions are logged.
+	AuthzDebugAuthorizations map[platform.ID]bool
+
+	// AuthorizationUsageTracker records the last use of the authorizations
+	// authenticating requests, if set.
+	AuthorizationUsageTracker *AuthorizationUsageTracker
+
+	// WriteLimits and BodyLimit limit the size of the bodies of signed
+	// requests, which are read into memory to verify their signatures.
+	WriteLimits *WriteLimits
+	BodyLimit   BodyLimitConfig
+
+	// SessionFingerprinter rejects the requests of sessions from other
+	// clients than the ones they are bound to, if set. The rejections are
+	// recorded as alerts of the users of the sessions in the
+	// SessionAlertService, if set.
+	SessionFingerprinter *SessionFingerprinter
+	SessionAlertService  platform.SessionAlertService
+
+	// WriteLinkService verifies write links and records the ones used.
+	// Write links are rejected if it is not set.
+	WriteLinkService platform.WriteLinkService
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
 
+	// signatures are the signatures of recent signed requests.
+	signatures *signatureCache
+
 	Handler http.Handler
 }
 
@@ -40,6 +71,7 @@ func NewAuthenticationHandler(h platform.HTTPErrorHandler) *AuthenticationHandle
 		Handler:          http.DefaultServeMux,
 		TokenParser:      jsonweb.NewTokenParser(jsonweb.EmptyKeyStore),
 		noAuthRouter:     httprouter.New(),
+		signatures:       newSignatureCache(),
 	}
 }
 
@@ -49,13 +81,26 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 	h.noAuthRouter.HandlerFunc(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 }
 
+// AuthzDebugHeader is the header requesting the authorization decisions of
+// a request to be logged, if enabled by the server.
+const AuthzDebugHeader = "Influx-Debug-Authz"
+
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme     = "token"
+	sessionAuthScheme   = "session"
+	signatureAuthScheme = "signature"
+	writeLinkAuthScheme = "writelink"
 )
 
-// ProbeAuthScheme probes the http request for the requests for token or cookie session.
+// ProbeAuthScheme probes the http request for the requests for token, request signature, write link or cookie session.
 func ProbeAuthScheme(r *http.Request) (string, error) {
+	if hasRequestSignature(r) {
+		return signatureAuthScheme, nil
+	}
+	if hasWriteLink(r) {
+		return writeLinkAuthScheme, nil
+	}
+
 	_, tokenErr := GetToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
@@ -89,17 +134,28 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var auth platform.Authorizer
+	var (
+		auth platform.Authorizer
+		link *writeLink
+	)
 	switch scheme {
 	case tokenAuthScheme:
 		auth, err = h.extractAuthorization(ctx, r)
 	case sessionAuthScheme:
 		auth, err = h.extractSession(ctx, r)
+	case signatureAuthScheme:
+		auth, err = h.extractSignedAuthorization(r)
+	case writeLinkAuthScheme:
+		auth, link, err = h.extractWriteLinkAuthorization(r)
 	default:
 		// TODO: this error will be nil if it gets here, this should be remedied with some
 		//  sentinel error I'm thinking
 		err = errors.New("invalid auth scheme")
 	}
+	if platform.ErrorCode(err) == platform.ERequestTooLarge {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
 	if err != nil {
 		h.unauthorized(ctx, w, err)
 		return
@@ -109,19 +165,50 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	// and therefor has no associated user. if the user ID is invalid
 	// disregard the user active check
 	if auth.GetUserID().Valid() {
-		if err = h.isUserActive(ctx, auth); err != nil {
+		if err = isUserActive(ctx, h.UserService, auth); err != nil {
 			InactiveUserError(ctx, h, w)
 			return
 		}
 	}
 
 	ctx = platcontext.SetAuthorizer(ctx, auth)
+	if link != nil {
+		ctx = withWriteLink(ctx, link)
+	}
+	setRequestAuthorizer(ctx, auth)
+	trackAuthorizationUsage(h.AuthorizationUsageTracker, auth, r)
+	if h.debugAuthz(r, auth) {
+		ctx = authorizer.WithDecisionLog(ctx, h.Logger.With(
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		))
+	}
 
 	h.Handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-func (h *AuthenticationHandler) isUserActive(ctx context.Context, auth platform.Authorizer) error {
-	u, err := h.UserService.FindUserByID(ctx, auth.GetUserID())
+// debugAuthz returns whether the authorization decisions of the request are
+// logged.
+func (h *AuthenticationHandler) debugAuthz(r *http.Request, auth platform.Authorizer) bool {
+	if h.AuthzDebugHeaderEnabled && r.Header.Get(AuthzDebugHeader) != "" {
+		return true
+	}
+	return h.AuthzDebugAuthorizations[auth.Identifier()]
+}
+
+// trackAuthorizationUsage records the use of the authorization by the
+// request. Sessions and JWTs are not authorizations and are not tracked.
+func trackAuthorizationUsage(t *AuthorizationUsageTracker, auth platform.Authorizer, r *http.Request) {
+	if t == nil {
+		return
+	}
+	if a, ok := auth.(*platform.Authorization); ok {
+		t.Track(a.ID, r)
+	}
+}
+
+func isUserActive(ctx context.Context, svc platform.UserService, auth platform.Authorizer) error {
+	u, err := svc.FindUserByID(ctx, auth.GetUserID())
 	if err != nil {
 		return err
 	}
@@ -166,6 +253,10 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 		return nil, err
 	}
 
+	if err := h.checkSessionFingerprint(ctx, r, s); err != nil {
+		return nil, err
+	}
+
 	if !h.SessionRenewDisabled {
 		// if the session is not expired, renew the session
 		err = h.SessionService.RenewSession(ctx, s, time.Now().Add(platform.RenewSessionTime))
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// DefaultAuthorizationUsageInterval is the interval at which the last use of
+// authorizations is written.
+const DefaultAuthorizationUsageInterval = 10 * time.Second
+
+// AuthorizationUsageTracker records the last use of the authorizations
+// authenticating requests. Uses are kept in memory and written in batches, so
+// that authenticating a request never waits on a write.
+type AuthorizationUsageTracker struct {
+	Service influxdb.AuthorizationUsageService
+
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[influxdb.ID]influxdb.AuthorizationUsage
+}
+
+// NewAuthorizationUsageTracker returns a tracker writing the usage of
+// authorizations to s.
+func NewAuthorizationUsageTracker(s influxdb.AuthorizationUsageService) *AuthorizationUsageTracker {
+	return &AuthorizationUsageTracker{
+		Service: s,
+		logger:  zap.NewNop(),
+		now:     time.Now,
+		pending: make(map[influxdb.ID]influxdb.AuthorizationUsage),
+	}
+}
+
+// WithLogger sets the logger l on the tracker. It must be called before Run.
+func (t *AuthorizationUsageTracker) WithLogger(l *zap.Logger) {
+	t.logger = l.With(zap.String("component", "authorization_usage"))
+}
+
+// Track records the use of the authorization by the request. Only the latest
+// use of an authorization is kept until the next flush.
+func (t *AuthorizationUsageTracker) Track(id influxdb.ID, r *http.Request) {
+	u := influxdb.AuthorizationUsage{
+		ID:   id,
+		Time: t.now(),
+		IP:   remoteIP(r),
+	}
+
+	t.mu.Lock()
+	t.pending[id] = u
+	t.mu.Unlock()
+}
+
+// Flush writes the uses tracked since the previous flush.
+func (t *AuthorizationUsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[influxdb.ID]influxdb.AuthorizationUsage)
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usage := make([]influxdb.AuthorizationUsage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, u)
+	}
+	return t.Service.RecordAuthorizationUsage(ctx, usage)
+}
+
+// Run flushes the tracked uses every interval until ctx is canceled. The uses
+// tracked after the last flush are flushed by calling Flush.
+func (t *AuthorizationUsageTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := t.Flush(ctx); err != nil && ctx.Err() == nil {
+			t.logger.Error("Unable to record the usage of authorizations", zap.Error(err))
+		}
+	}
+}
+
+// remoteIP returns the address of the client of the request, without its port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
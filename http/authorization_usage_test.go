@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestAuthenticationHandler_AuthorizationUsage(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	a := &influxdb.Authorization{UserID: u.ID, OrgID: o.ID}
+	if err := svc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	used := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	tracker := NewAuthorizationUsageTracker(svc)
+	tracker.now = func() time.Time { return used }
+
+	h := NewAuthenticationHandler(ErrorHandler(0))
+	h.AuthorizationService = svc
+	h.UserService = svc
+	h.AuthorizationUsageTracker = tracker
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets", nil)
+	r.RemoteAddr = "10.0.0.1:51234"
+	SetToken(a.Token, r)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("request returned %d, want 200: %s", w.Code, w.Body)
+	}
+
+	got, err := svc.FindAuthorizationByID(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastUsedAt != nil {
+		t.Fatalf("expected the use to be recorded asynchronously, got %v", got.LastUsedAt)
+	}
+
+	if err := tracker.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err = svc.FindAuthorizationByID(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastUsedAt == nil || !got.LastUsedAt.Equal(used) || got.LastUsedIP != "10.0.0.1" {
+		t.Fatalf("expected the use to be recorded after a flush, got %v from %q", got.LastUsedAt, got.LastUsedIP)
+	}
+
+	if len(tracker.pending) != 0 {
+		t.Fatalf("expected the flushed uses to be cleared, got %v", tracker.pending)
+	}
+}
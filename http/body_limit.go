@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+// DefaultMaxBodyBytes is the maximum size in bytes of request bodies of
+// routes without a limit of their own.
+const DefaultMaxBodyBytes = 32 << 20
+
+// DefaultMaxBodyBytesRoutes are the limits of routes that differ from the
+// default. Writes are limited by the write limits of the runtime config
+// instead, as they apply to the decompressed body; this does not extend to
+// the write links created below the write route.
+var DefaultMaxBodyBytesRoutes = []string{
+	"/api/v2/write=0",
+	"/api/v2/write/links=1048576",
+	"/write=0",
+	"/api/v2/dashboards=8388608",
+	"/api/v2/packages=67108864",
+}
+
+// BodyLimitConfig configures the maximum sizes of request bodies.
+type BodyLimitConfig struct {
+	// MaxBodyBytes is the maximum size in bytes of request bodies of routes
+	// without a limit of their own; 0 means unlimited.
+	MaxBodyBytes int
+	// Routes are the limits of routes in the form prefix=bytes, e.g.
+	// /api/v2/dashboards=8388608. Prefixes match whole path segments, so
+	// /write does not match /writes, and the limit of the longest prefix of
+	// the request path applies; 0 means unlimited.
+	Routes []string
+}
+
+// NewBodyLimitConfig returns a BodyLimitConfig with default values.
+func NewBodyLimitConfig() BodyLimitConfig {
+	return BodyLimitConfig{
+		MaxBodyBytes: DefaultMaxBodyBytes,
+		Routes:       DefaultMaxBodyBytesRoutes,
+	}
+}
+
+type routeBodyLimit struct {
+	prefix string
+	limit  int64
+}
+
+// Valid returns an error if a limit is negative or a route is malformed.
+func (c BodyLimitConfig) Valid() error {
+	_, err := c.routeLimits()
+	return err
+}
+
+// routeLimits returns the limits of the routes with the longest prefixes first.
+func (c BodyLimitConfig) routeLimits() ([]routeBodyLimit, error) {
+	if c.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("max body bytes must not be negative")
+	}
+
+	limits := make([]routeBodyLimit, 0, len(c.Routes))
+	for _, route := range c.Routes {
+		i := strings.LastIndexByte(route, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid max body bytes route %q; must be prefix=bytes", route)
+		}
+		prefix := strings.TrimSpace(route[:i])
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid max body bytes route %q; prefix must start with /", route)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(route[i+1:]), 10, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid max body bytes route %q; bytes must be a non-negative integer", route)
+		}
+		limits = append(limits, routeBodyLimit{prefix: prefix, limit: limit})
+	}
+	sort.SliceStable(limits, func(i, j int) bool {
+		return len(limits[i].prefix) > len(limits[j].prefix)
+	})
+	return limits, nil
+}
+
+// limitOf returns the limit of the path and the prefix of the route it falls
+// in, if any.
+func (c BodyLimitConfig) limitOf(routes []routeBodyLimit, path string) (int64, string) {
+	for _, r := range routes {
+		if hasPathPrefix(path, r.prefix) {
+			return r.limit, r.prefix
+		}
+	}
+	return int64(c.MaxBodyBytes), ""
+}
+
+// hasPathPrefix reports whether the path is the prefix or lies below it.
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// BodyLimitMW returns a middleware that rejects requests with bodies larger
+// than the limit of their route with 413 Request Entity Too Large, before
+// the body is decoded by a handler. Bodies of unknown length are read into
+// memory up to the limit. Malformed routes of the config are ignored; check
+// the config with Valid first.
+func BodyLimitMW(c BodyLimitConfig) Middleware {
+	routes, _ := c.routeLimits()
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			limit, prefix := c.limitOf(routes, r.URL.Path)
+			if limit == 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tooLarge := func() {
+				msg := fmt.Sprintf("request body exceeds the maximum of %d bytes", limit)
+				if prefix != "" {
+					msg = fmt.Sprintf("request body exceeds the maximum of %d bytes for %s", limit, prefix)
+				}
+				ErrorHandler(0).HandleHTTPError(r.Context(), &influxdb.Error{
+					Code: influxdb.ERequestTooLarge,
+					Op:   "http/BodyLimitMW",
+					Msg:  msg,
+				}, w)
+			}
+
+			if r.ContentLength > limit {
+				tooLarge()
+				return
+			}
+			// the server never reads more than the content length of a body.
+			if r.ContentLength >= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// read one byte past the limit to detect oversized bodies.
+			data, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+			if err != nil {
+				ErrorHandler(0).HandleHTTPError(r.Context(), &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Op:   "http/BodyLimitMW",
+					Msg:  "unable to read request body",
+					Err:  err,
+				}, w)
+				return
+			}
+			if int64(len(data)) > limit {
+				tooLarge()
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+			r.ContentLength = int64(len(data))
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimitMW(t *testing.T) {
+	config := BodyLimitConfig{
+		MaxBodyBytes: 16,
+		Routes: []string{
+			"/api/v2/write=0",
+			"/api/v2/write/links=8",
+			"/api/v2/dashboards=8",
+			"/api/v2/dashboards/special=32",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		chunked       bool
+		wantStatus    int
+		wantMsgSubstr string
+	}{
+		{name: "default limit", path: "/api/v2/buckets", body: strings.Repeat("a", 16), wantStatus: http.StatusOK},
+		{name: "default limit exceeded", path: "/api/v2/buckets", body: strings.Repeat("a", 17), wantStatus: http.StatusRequestEntityTooLarge, wantMsgSubstr: "maximum of 16 bytes"},
+		{name: "route limit exceeded", path: "/api/v2/dashboards", body: strings.Repeat("a", 9), wantStatus: http.StatusRequestEntityTooLarge, wantMsgSubstr: "maximum of 8 bytes for /api/v2/dashboards"},
+		{name: "longest prefix", path: "/api/v2/dashboards/special", body: strings.Repeat("a", 20), wantStatus: http.StatusOK},
+		{name: "unlimited route", path: "/api/v2/write", body: strings.Repeat("a", 100), wantStatus: http.StatusOK},
+		{name: "route below unlimited route", path: "/api/v2/write/links", body: strings.Repeat("a", 9), wantStatus: http.StatusRequestEntityTooLarge, wantMsgSubstr: "maximum of 8 bytes for /api/v2/write/links"},
+		{name: "prefix matches whole segments", path: "/api/v2/writes", body: strings.Repeat("a", 17), wantStatus: http.StatusRequestEntityTooLarge, wantMsgSubstr: "maximum of 16 bytes"},
+		{name: "chunked body", path: "/api/v2/buckets", body: strings.Repeat("a", 16), chunked: true, wantStatus: http.StatusOK},
+		{name: "chunked body exceeded", path: "/api/v2/buckets", body: strings.Repeat("a", 17), chunked: true, wantStatus: http.StatusRequestEntityTooLarge, wantMsgSubstr: "maximum of 16 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(b)
+			})
+
+			r := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				r.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			BodyLimitMW(config)(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && got != tt.body {
+				t.Errorf("handler read %q, want %q", got, tt.body)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMsgSubstr) {
+				t.Errorf("body %q does not contain %q", w.Body.String(), tt.wantMsgSubstr)
+			}
+		})
+	}
+}
+
+func TestBodyLimitConfig_Valid(t *testing.T) {
+	if err := NewBodyLimitConfig().Valid(); err != nil {
+		t.Fatalf("expected default config to be valid, got %v", err)
+	}
+	for _, route := range []string{"/api/v2/dashboards", "api/v2/dashboards=1", "/api/v2/dashboards=-1", "/api/v2/dashboards=1MB"} {
+		if err := (BodyLimitConfig{Routes: []string{route}}).Valid(); err == nil {
+			t.Errorf("expected route %q to be invalid", route)
+		}
+	}
+}
+
+func TestBodyLimitMW_DefaultWriteLinks(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected the oversized write link request to be rejected")
+	})
+
+	r := httptest.NewRequest("POST", writeLinksPath, strings.NewReader(strings.Repeat("a", 1<<20+1)))
+	w := httptest.NewRecorder()
+	BodyLimitMW(NewBodyLimitConfig())(next).ServeHTTP(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+}
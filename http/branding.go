@@ -0,0 +1,199 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+)
+
+// Branding customizes the UI served by the AssetHandler, e.g. to tell
+// instances apart when the UI is exposed to many teams.
+type Branding struct {
+	// ProductName replaces the title of the UI.
+	ProductName string `json:"productName,omitempty"`
+	// LogoURL is the address of the logo shown with the banner and by the UI.
+	LogoURL string `json:"logoURL,omitempty"`
+	// BannerMessage is shown in a banner at the top of every page, e.g.
+	// "Production - be careful".
+	BannerMessage string `json:"bannerMessage,omitempty"`
+	// BannerColor is the background color of the banner, as #rrggbb.
+	BannerColor string `json:"bannerColor,omitempty"`
+}
+
+// DefaultBannerColor is the background color of the banner if the branding
+// does not set one.
+const DefaultBannerColor = "#dc4e58"
+
+// maintenanceBannerColor is the background color of the banner when the
+// instance is in maintenance.
+const maintenanceBannerColor = "#f48d38"
+
+var bannerColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Empty returns true if the branding does not customize the UI.
+func (b Branding) Empty() bool {
+	return b.ProductName == "" && b.LogoURL == "" && b.BannerMessage == ""
+}
+
+// Valid returns an error if the logo is not an http, https or relative URL,
+// or the banner color is not a #rrggbb color.
+func (b Branding) Valid() error {
+	if b.LogoURL != "" {
+		u, err := url.Parse(b.LogoURL)
+		if err != nil || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("logo url %q must be an http, https or relative url", b.LogoURL)
+		}
+	}
+	if b.BannerColor != "" && !bannerColorPattern.MatchString(b.BannerColor) {
+		return fmt.Errorf("banner color %q must be a #rrggbb color", b.BannerColor)
+	}
+	return nil
+}
+
+var bannerTemplate = template.Must(template.New("banner").Parse(
+	`<div id="influx-branding-banner" style="position:fixed;top:0;left:0;right:0;z-index:10000;` +
+		`height:24px;line-height:24px;text-align:center;font:13px sans-serif;color:#fff;background:{{.Color}}">` +
+		`{{if .LogoURL}}<img src="{{.LogoURL}}" alt="" style="height:16px;vertical-align:middle;margin-right:6px">{{end}}` +
+		`{{.Message}}</div>`,
+))
+
+var (
+	titlePattern = regexp.MustCompile(`(?is)<title>.*?</title>`)
+	headPattern  = regexp.MustCompile(`(?i)</head>`)
+	bodyPattern  = regexp.MustCompile(`(?i)<body[^>]*>`)
+)
+
+// Inject returns the HTML page with the branding: its title is replaced by
+// the product name, the branding is exposed to the scripts of the page as
+// window.__INFLUX_BRANDING__, and the banner is inserted at the start of its
+// body.
+func (b Branding) Inject(page []byte) ([]byte, error) {
+	if b.ProductName != "" {
+		title := "<title>" + template.HTMLEscapeString(b.ProductName) + "</title>"
+		page = titlePattern.ReplaceAllLiteral(page, []byte(title))
+	}
+
+	// json.Marshal escapes <, > and &, so the JSON cannot close the script.
+	js, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	script := []byte("<script>window.__INFLUX_BRANDING__=" + string(js) + ";</script></head>")
+	page = replaceFirst(headPattern, page, script)
+
+	if b.BannerMessage != "" {
+		color := DefaultBannerColor
+		if bannerColorPattern.MatchString(b.BannerColor) {
+			color = b.BannerColor
+		}
+		var banner bytes.Buffer
+		if err := bannerTemplate.Execute(&banner, struct {
+			Color   template.CSS
+			LogoURL string
+			Message string
+		}{
+			Color:   template.CSS(color),
+			LogoURL: b.LogoURL,
+			Message: b.BannerMessage,
+		}); err != nil {
+			return nil, err
+		}
+		if loc := bodyPattern.FindIndex(page); loc != nil {
+			page = append(page[:loc[1]:loc[1]], append(banner.Bytes(), page[loc[1]:]...)...)
+		}
+	}
+	return page, nil
+}
+
+// replaceFirst replaces the first match of the pattern in b with repl.
+func replaceFirst(pattern *regexp.Regexp, b, repl []byte) []byte {
+	loc := pattern.FindIndex(b)
+	if loc == nil {
+		return b
+	}
+	out := make([]byte, 0, len(b)+len(repl))
+	out = append(out, b[:loc[0]]...)
+	out = append(out, repl...)
+	return append(out, b[loc[1]:]...)
+}
+
+// isPageRequest returns true if the request may be answered with an HTML
+// page: the single-page UI is served for any path without an extension.
+func isPageRequest(r *http.Request) bool {
+	ext := path.Ext(r.URL.Path)
+	return ext == "" || ext == ".html"
+}
+
+// brandingResponseWriter buffers HTML responses to inject the branding into
+// them, and passes other responses through.
+type brandingResponseWriter struct {
+	http.ResponseWriter
+	branding Branding
+
+	decided bool
+	html    bool
+	status  int
+	buf     bytes.Buffer
+}
+
+func (w *brandingResponseWriter) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.status = status
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" || status != http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if mt, _, _ := mime.ParseMediaType(contentType); mt != "text/html" {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.html = true
+}
+
+func (w *brandingResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.html {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// flush writes the buffered HTML response with the branding.
+func (w *brandingResponseWriter) flush() error {
+	if !w.html {
+		return nil
+	}
+
+	page, err := w.branding.Inject(w.buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	h := w.Header()
+	// The page changes with the branding, so it is revalidated instead of
+	// being cached by the version of the asset.
+	h.Del("ETag")
+	h.Del("Last-Modified")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Content-Length", strconv.Itoa(len(page)))
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err = w.ResponseWriter.Write(page)
+	return err
+}
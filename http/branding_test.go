@@ -0,0 +1,135 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testIndexHTML = `<!DOCTYPE html><html><head><title>InfluxDB 2.0</title></head><body class="app"><div id="react-root"></div></body></html>`
+
+func TestBranding_Inject(t *testing.T) {
+	b := Branding{
+		ProductName:   "Acme <Metrics>",
+		LogoURL:       "https://acme.example/logo.png",
+		BannerMessage: "Production - be careful </div><script>alert(1)</script>",
+		BannerColor:   "#123456",
+	}
+
+	page, err := b.Inject([]byte(testIndexHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(page)
+
+	for _, want := range []string{
+		`<title>Acme &lt;Metrics&gt;</title>`,
+		`<script>window.__INFLUX_BRANDING__={"productName":"Acme \u003cMetrics\u003e",`,
+		`<body class="app"><div id="influx-branding-banner"`,
+		`background:#123456`,
+		`<img src="https://acme.example/logo.png"`,
+		`Production - be careful &lt;/div&gt;&lt;script&gt;alert(1)&lt;/script&gt;</div><div id="react-root">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected page to contain %s, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "InfluxDB 2.0") {
+		t.Errorf("expected the title to be replaced, got %s", got)
+	}
+
+	page, err = Branding{ProductName: "Acme"}.Inject([]byte(testIndexHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), "influx-branding-banner") {
+		t.Errorf("expected no banner without a banner message, got %s", page)
+	}
+}
+
+func TestBranding_Valid(t *testing.T) {
+	tests := []struct {
+		name     string
+		branding Branding
+		wantErr  bool
+	}{
+		{name: "empty", branding: Branding{}},
+		{name: "https logo", branding: Branding{LogoURL: "https://acme.example/logo.png", BannerColor: "#AABBCC"}},
+		{name: "relative logo", branding: Branding{LogoURL: "/static/logo.png"}},
+		{name: "javascript logo", branding: Branding{LogoURL: "javascript:alert(1)"}, wantErr: true},
+		{name: "named color", branding: Branding{BannerColor: "red"}, wantErr: true},
+		{name: "css in color", branding: Branding{BannerColor: "#123456;display:none"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.branding.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssetHandler_Branding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(testIndexHTML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte(`console.log("<title>")`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewAssetHandler()
+	h.Path = dir
+	h.Branding = Branding{ProductName: "Acme", BannerMessage: "Production"}
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest("GET", "http://any.url"+path, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/orgs/0000000000000001/dashboards", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "<title>Acme</title>") || !strings.Contains(body, "influx-branding-banner") {
+		t.Errorf("expected the branded page, got %s", body)
+	}
+	if got, want := w.Header().Get("Content-Length"), strconv.Itoa(len(body)); got != want {
+		t.Errorf("expected Content-Length %s, got %s", want, got)
+	}
+	if w.Header().Get("Last-Modified") != "" || w.Header().Get("ETag") != "" {
+		t.Errorf("expected the branded page without validators, got %v", w.Header())
+	}
+
+	w = serve("/orgs/0000000000000001", http.Header{"If-Modified-Since": {"Mon, 01 Jan 2090 00:00:00 GMT"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "<title>Acme</title>") {
+		t.Errorf("expected conditional requests of the page to get the branded page, got %d %s", w.Code, w.Body)
+	}
+
+	w = serve("/app.js", nil)
+	if w.Code != http.StatusOK || w.Body.String() != `console.log("<title>")` {
+		t.Errorf("expected other assets to be served unchanged, got %d %s", w.Code, w.Body)
+	}
+
+	h.Branding = Branding{}
+	w = serve("/orgs/0000000000000001", nil)
+	if !strings.Contains(w.Body.String(), "<title>InfluxDB 2.0</title>") {
+		t.Errorf("expected the page unchanged without branding, got %s", w.Body)
+	}
+}
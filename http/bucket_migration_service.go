@@ -0,0 +1,304 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	bucketMigrationsPath = "/api/v2/bucketMigrations"
+)
+
+// BucketMigrationBackend is all services and associated parameters required to construct
+// the BucketMigrationHandler.
+type BucketMigrationBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	BucketMigrationService influxdb.BucketMigrationService
+	OrganizationService    influxdb.OrganizationService
+}
+
+// NewBucketMigrationBackend returns a new instance of BucketMigrationBackend.
+func NewBucketMigrationBackend(b *APIBackend) *BucketMigrationBackend {
+	return &BucketMigrationBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "bucket_migration")),
+
+		BucketMigrationService: b.BucketMigrationService,
+		OrganizationService:    b.OrganizationService,
+	}
+}
+
+// BucketMigrationHandler is the handler for bucket migrations.
+type BucketMigrationHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	BucketMigrationService influxdb.BucketMigrationService
+	OrganizationService    influxdb.OrganizationService
+}
+
+// NewBucketMigrationHandler creates a new BucketMigrationHandler.
+func NewBucketMigrationHandler(b *BucketMigrationBackend) *BucketMigrationHandler {
+	h := &BucketMigrationHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		BucketMigrationService: b.BucketMigrationService,
+		OrganizationService:    b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", bucketMigrationsPath)
+
+	h.HandlerFunc("GET", bucketMigrationsPath, h.handleGetBucketMigrations)
+	h.HandlerFunc("POST", bucketMigrationsPath, h.handlePostBucketMigration)
+	h.HandlerFunc("GET", entityPath, h.handleGetBucketMigration)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteBucketMigration)
+
+	return h
+}
+
+type bucketMigrationLinks struct {
+	Self              string `json:"self"`
+	Org               string `json:"org"`
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"`
+}
+
+// bucketMigration is the bucket migration of the API, whose window is in
+// seconds.
+type bucketMigration struct {
+	OrgID               influxdb.ID                        `json:"orgID"`
+	SourceBucketID      influxdb.ID                        `json:"sourceBucketID"`
+	DestinationBucketID influxdb.ID                        `json:"destinationBucketID"`
+	Start               time.Time                          `json:"start"`
+	Stop                time.Time                          `json:"stop"`
+	Measurement         string                             `json:"measurement,omitempty"`
+	EverySeconds        int64                              `json:"everySeconds,omitempty"`
+	Aggregate           influxdb.MaterializedViewAggregate `json:"aggregate,omitempty"`
+	MaxPointsPerSecond  int                                `json:"maxPointsPerSecond,omitempty"`
+}
+
+func (m bucketMigration) toInfluxDB() *influxdb.BucketMigration {
+	return &influxdb.BucketMigration{
+		OrgID:               m.OrgID,
+		SourceBucketID:      m.SourceBucketID,
+		DestinationBucketID: m.DestinationBucketID,
+		Start:               m.Start,
+		Stop:                m.Stop,
+		Measurement:         m.Measurement,
+		Every:               time.Duration(m.EverySeconds) * time.Second,
+		Aggregate:           m.Aggregate,
+		MaxPointsPerSecond:  m.MaxPointsPerSecond,
+	}
+}
+
+type bucketMigrationResponse struct {
+	ID influxdb.ID `json:"id"`
+	bucketMigration
+	Status        string               `json:"status"`
+	MigratedUntil time.Time            `json:"migratedUntil"`
+	PointsWritten int64                `json:"pointsWritten"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	StartedAt     *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time           `json:"finishedAt,omitempty"`
+	Error         string               `json:"error,omitempty"`
+	Links         bucketMigrationLinks `json:"links"`
+}
+
+func newBucketMigrationResponse(m *influxdb.BucketMigration) bucketMigrationResponse {
+	return bucketMigrationResponse{
+		ID: m.ID,
+		bucketMigration: bucketMigration{
+			OrgID:               m.OrgID,
+			SourceBucketID:      m.SourceBucketID,
+			DestinationBucketID: m.DestinationBucketID,
+			Start:               m.Start,
+			Stop:                m.Stop,
+			Measurement:         m.Measurement,
+			EverySeconds:        int64(m.Every.Round(time.Second) / time.Second),
+			Aggregate:           m.Aggregate,
+			MaxPointsPerSecond:  m.MaxPointsPerSecond,
+		},
+		Status:        m.Status,
+		MigratedUntil: m.MigratedUntil,
+		PointsWritten: m.PointsWritten,
+		CreatedAt:     m.CreatedAt,
+		StartedAt:     m.StartedAt,
+		FinishedAt:    m.FinishedAt,
+		Error:         m.Error,
+		Links: bucketMigrationLinks{
+			Self:              bucketMigrationIDPath(m.ID),
+			Org:               fmt.Sprintf("/api/v2/orgs/%s", m.OrgID),
+			SourceBucket:      fmt.Sprintf("/api/v2/buckets/%s", m.SourceBucketID),
+			DestinationBucket: fmt.Sprintf("/api/v2/buckets/%s", m.DestinationBucketID),
+		},
+	}
+}
+
+type getBucketMigrationsResponse struct {
+	Migrations []bucketMigrationResponse `json:"migrations"`
+}
+
+func newGetBucketMigrationsResponse(ms []*influxdb.BucketMigration) getBucketMigrationsResponse {
+	resp := getBucketMigrationsResponse{
+		Migrations: make([]bucketMigrationResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		resp.Migrations = append(resp.Migrations, newBucketMigrationResponse(m))
+	}
+	return resp
+}
+
+func decodeGetBucketMigrationsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*influxdb.BucketMigrationFilter, error) {
+	qp := r.URL.Query()
+	filter := &influxdb.BucketMigrationFilter{}
+
+	for _, p := range []struct {
+		name string
+		id   **influxdb.ID
+	}{
+		{"orgID", &filter.OrgID},
+		{"sourceBucketID", &filter.SourceBucketID},
+	} {
+		if v := qp.Get(p.name); v != "" {
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("invalid %s", p.name),
+					Err:  err,
+				}
+			}
+			*p.id = id
+		}
+	}
+
+	if org := qp.Get("org"); org != "" && filter.OrgID == nil {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	return filter, nil
+}
+
+func (h *BucketMigrationHandler) handleGetBucketMigrations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeGetBucketMigrationsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, err := h.BucketMigrationService.FindBucketMigrations(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket migrations retrieved", zap.Int("count", len(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetBucketMigrationsResponse(ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestBucketMigrationID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *BucketMigrationHandler) handleGetBucketMigration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestBucketMigrationID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m, err := h.BucketMigrationService.FindBucketMigrationByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket migration retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketMigrationResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *BucketMigrationHandler) handlePostBucketMigration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var v bucketMigration
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	m := v.toInfluxDB()
+	if err := h.BucketMigrationService.CreateBucketMigration(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket migration created", zap.Stringer("id", m.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newBucketMigrationResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *BucketMigrationHandler) handleDeleteBucketMigration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestBucketMigrationID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BucketMigrationService.CancelBucketMigration(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket migration canceled", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func bucketMigrationIDPath(id influxdb.ID) string {
+	return path.Join(bucketMigrationsPath, id.String())
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestBucketMigrationHandler(t *testing.T) {
+	var (
+		created  *influxdb.BucketMigration
+		filter   influxdb.BucketMigrationFilter
+		canceled influxdb.ID
+	)
+	svc := mock.NewBucketMigrationService()
+	svc.CreateBucketMigrationFn = func(_ context.Context, m *influxdb.BucketMigration) error {
+		m.ID = 1
+		created = m
+		return nil
+	}
+	svc.FindBucketMigrationByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.BucketMigration, error) {
+		m := *created
+		m.Status = influxdb.BucketMigrationRunning
+		m.MigratedUntil = m.Start.Add(time.Hour)
+		m.PointsWritten = 1000
+		return &m, nil
+	}
+	svc.FindBucketMigrationsFn = func(_ context.Context, f influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+		filter = f
+		return []*influxdb.BucketMigration{created}, nil
+	}
+	svc.CancelBucketMigrationFn = func(_ context.Context, id influxdb.ID) error {
+		canceled = id
+		return nil
+	}
+
+	h := NewBucketMigrationHandler(&BucketMigrationBackend{
+		HTTPErrorHandler:       ErrorHandler(0),
+		Logger:                 zap.NewNop(),
+		BucketMigrationService: svc,
+		OrganizationService:    mock.NewOrganizationService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/api/v2/bucketMigrations", `{
+		"orgID": "0000000000000002",
+		"sourceBucketID": "0000000000000003",
+		"destinationBucketID": "0000000000000004",
+		"start": "2020-01-01T00:00:00Z",
+		"stop": "2020-02-01T00:00:00Z",
+		"aggregate": "mean",
+		"everySeconds": 300,
+		"maxPointsPerSecond": 10000
+	}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("POST returned %d, want 202: %s", w.Code, w.Body)
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := &influxdb.BucketMigration{
+		ID:                  1,
+		OrgID:               2,
+		SourceBucketID:      3,
+		DestinationBucketID: 4,
+		Start:               start,
+		Stop:                start.AddDate(0, 1, 0),
+		Every:               5 * time.Minute,
+		Aggregate:           influxdb.MaterializedViewMean,
+		MaxPointsPerSecond:  10000,
+	}
+	if diff := cmp.Diff(created, want); diff != "" {
+		t.Errorf("unexpected created migration -got/+want\n%s", diff)
+	}
+
+	w = do("GET", "/api/v2/bucketMigrations/0000000000000001", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var resp bucketMigrationResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.EverySeconds != 300 || resp.PointsWritten != 1000 || !resp.MigratedUntil.Equal(start.Add(time.Hour)) ||
+		resp.Links.Self != "/api/v2/bucketMigrations/0000000000000001" {
+		t.Errorf("unexpected migration %+v", resp)
+	}
+
+	w = do("GET", "/api/v2/bucketMigrations?orgID=0000000000000002", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	if filter.OrgID == nil || *filter.OrgID != 2 || filter.SourceBucketID != nil {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+
+	if w := do("DELETE", "/api/v2/bucketMigrations/0000000000000001", ""); w.Code != http.StatusNoContent || canceled != 1 {
+		t.Errorf("DELETE returned %d, canceled %s; want 204, 0000000000000001", w.Code, canceled)
+	}
+
+	if w := do("GET", "/api/v2/bucketMigrations?sourceBucketID=invalid", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid filter returned %d, want 400", w.Code)
+	}
+}
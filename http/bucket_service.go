@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"time"
 
 	"github.com/influxdata/httprouter"
@@ -28,6 +29,12 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	ShardService               influxdb.ShardService
+	BucketOptimizationService  influxdb.BucketOptimizationService
+	BucketSchemaService        influxdb.BucketSchemaService
+	BucketSampleService        influxdb.BucketSampleService
+
+	MeasurementLastWriteService influxdb.MeasurementLastWriteService
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -42,12 +49,18 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		ShardService:               b.ShardService,
+		BucketOptimizationService:  b.BucketOptimizationService,
+		BucketSchemaService:        b.BucketSchemaService,
+		BucketSampleService:        b.BucketSampleService,
+
+		MeasurementLastWriteService: b.MeasurementLastWriteService,
 	}
 }
 
 // BucketHandler represents an HTTP API handler for buckets.
 type BucketHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -57,18 +70,31 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	ShardService               influxdb.ShardService
+	BucketOptimizationService  influxdb.BucketOptimizationService
+	BucketSchemaService        influxdb.BucketSchemaService
+	BucketSampleService        influxdb.BucketSampleService
+
+	MeasurementLastWriteService influxdb.MeasurementLastWriteService
 }
 
 const (
-	bucketsPath            = "/api/v2/buckets"
-	bucketsIDPath          = "/api/v2/buckets/:id"
-	bucketsIDLogPath       = "/api/v2/buckets/:id/logs"
-	bucketsIDMembersPath   = "/api/v2/buckets/:id/members"
-	bucketsIDMembersIDPath = "/api/v2/buckets/:id/members/:userID"
-	bucketsIDOwnersPath    = "/api/v2/buckets/:id/owners"
-	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
-	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
-	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+	bucketsPath               = "/api/v2/buckets"
+	bucketsIDPath             = "/api/v2/buckets/:id"
+	bucketsIDLogPath          = "/api/v2/buckets/:id/logs"
+	bucketsIDShardsPath       = "/api/v2/buckets/:id/shards"
+	bucketsIDShardsPlanPath   = "/api/v2/buckets/:id/shards/compactionPlan"
+	bucketsIDOptimizePath     = "/api/v2/buckets/:id/optimize"
+	bucketsIDMeasurementsPath = "/api/v2/buckets/:id/measurements"
+	bucketsIDFieldsPath       = "/api/v2/buckets/:id/fields"
+	bucketsIDTagValuesPath    = "/api/v2/buckets/:id/tag-values"
+	bucketsIDSamplePath       = "/api/v2/buckets/:id/sample"
+	bucketsIDMembersPath      = "/api/v2/buckets/:id/members"
+	bucketsIDMembersIDPath    = "/api/v2/buckets/:id/members/:userID"
+	bucketsIDOwnersPath       = "/api/v2/buckets/:id/owners"
+	bucketsIDOwnersIDPath     = "/api/v2/buckets/:id/owners/:userID"
+	bucketsIDLabelsPath       = "/api/v2/buckets/:id/labels"
+	bucketsIDLabelsIDPath     = "/api/v2/buckets/:id/labels/:lid"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -84,12 +110,27 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		ShardService:               b.ShardService,
+		BucketOptimizationService:  b.BucketOptimizationService,
+		BucketSchemaService:        b.BucketSchemaService,
+		BucketSampleService:        b.BucketSampleService,
+
+		MeasurementLastWriteService: b.MeasurementLastWriteService,
 	}
 
 	h.HandlerFunc("POST", bucketsPath, h.handlePostBucket)
 	h.HandlerFunc("GET", bucketsPath, h.handleGetBuckets)
 	h.HandlerFunc("GET", bucketsIDPath, h.handleGetBucket)
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
+	h.HandlerFunc("GET", bucketsIDShardsPath, h.handleGetBucketShards)
+	h.HandlerFunc("GET", bucketsIDShardsPlanPath, h.handleGetBucketCompactionPlan)
+	h.HandlerFunc("POST", bucketsIDOptimizePath, h.handlePostBucketOptimize)
+	h.HandlerFunc("GET", bucketsIDOptimizePath, h.handleGetBucketOptimize)
+	h.HandlerFunc("DELETE", bucketsIDOptimizePath, h.handleDeleteBucketOptimize)
+	h.HandlerFunc("GET", bucketsIDMeasurementsPath, h.handleGetBucketMeasurements)
+	h.HandlerFunc("GET", bucketsIDFieldsPath, h.handleGetBucketFields)
+	h.HandlerFunc("GET", bucketsIDTagValuesPath, h.handleGetBucketTagValues)
+	h.HandlerFunc("GET", bucketsIDSamplePath, h.handleGetBucketSample)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
@@ -132,20 +173,27 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Type                string          `json:"type"`
-	Description         string          `json:"description,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                      influxdb.ID     `json:"id,omitempty"`
+	OrgID                   influxdb.ID     `json:"orgID,omitempty"`
+	Type                    string          `json:"type"`
+	Description             string          `json:"description,omitempty"`
+	Name                    string          `json:"name"`
+	RetentionPolicyName     string          `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules          []retentionRule `json:"retentionRules"`
+	ShardGroupDuration      int64           `json:"shardGroupDurationSeconds,omitempty"`
+	SchemaType              string          `json:"schemaType,omitempty"`
+	FieldTypeConflictPolicy string          `json:"fieldTypeConflictPolicy,omitempty"`
 	influxdb.CRUDLog
 }
 
-// retentionRule is the retention rule action for a bucket.
+// retentionRule is the retention rule action for a bucket. A rule with a
+// measurement or tags overrides the retention period of the bucket for the
+// matching series.
 type retentionRule struct {
-	Type         string `json:"type"`
-	EverySeconds int64  `json:"everySeconds"`
+	Type         string         `json:"type"`
+	EverySeconds int64          `json:"everySeconds"`
+	Measurement  string         `json:"measurement,omitempty"`
+	Tags         []influxdb.Tag `json:"tags,omitempty"`
 }
 
 func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
@@ -160,33 +208,84 @@ func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
 	return t, nil
 }
 
-func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
-	if b == nil {
-		return nil, nil
-	}
-
+// bucketRetention returns the retention period of the bucket, given by the
+// first rule without a measurement or tags, and the rules overriding it.
+func bucketRetention(rules []retentionRule) (time.Duration, []influxdb.BucketRetentionRule, error) {
 	var d time.Duration // zero value implies infinite retention policy
+	var overrides []influxdb.BucketRetentionRule
+	var found bool
+	for _, rr := range rules {
+		if rr.Measurement == "" && len(rr.Tags) == 0 {
+			if found {
+				continue
+			}
+			var err error
+			if d, err = rr.RetentionPeriod(); err != nil {
+				return 0, nil, err
+			}
+			found = true
+			continue
+		}
 
-	// Only support a single retention period for the moment
-	if len(b.RetentionRules) > 0 {
-		d = time.Duration(b.RetentionRules[0].EverySeconds) * time.Second
-		if d < time.Second {
-			return nil, &influxdb.Error{
+		if rr.EverySeconds < 0 {
+			return 0, nil, &influxdb.Error{
 				Code: influxdb.EUnprocessableEntity,
-				Msg:  "expiration seconds must be greater than or equal to one second",
+				Msg:  "expiration seconds must not be negative",
 			}
 		}
+		overrides = append(overrides, influxdb.BucketRetentionRule{
+			Measurement:     rr.Measurement,
+			Tags:            rr.Tags,
+			RetentionPeriod: time.Duration(rr.EverySeconds) * time.Second,
+		})
+	}
+	return d, overrides, nil
+}
+
+// newRetentionRules returns the retention rules of the retention period and
+// the retention rules of a bucket.
+func newRetentionRules(rp time.Duration, overrides []influxdb.BucketRetentionRule) []retentionRule {
+	rules := []retentionRule{}
+	if s := int64(rp.Round(time.Second) / time.Second); s > 0 {
+		rules = append(rules, retentionRule{
+			Type:         "expire",
+			EverySeconds: s,
+		})
+	}
+	for _, r := range overrides {
+		rules = append(rules, retentionRule{
+			Type:         "expire",
+			EverySeconds: int64(r.RetentionPeriod.Round(time.Second) / time.Second),
+			Measurement:  r.Measurement,
+			Tags:         r.Tags,
+		})
+	}
+	return rules
+}
+
+func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	d, overrides, err := bucketRetention(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
 	return &influxdb.Bucket{
-		ID:                  b.ID,
-		OrgID:               b.OrgID,
-		Type:                influxdb.ParseBucketType(b.Type),
-		Description:         b.Description,
-		Name:                b.Name,
-		RetentionPolicyName: b.RetentionPolicyName,
-		RetentionPeriod:     d,
-		CRUDLog:             b.CRUDLog,
+		ID:                      b.ID,
+		OrgID:                   b.OrgID,
+		Type:                    influxdb.ParseBucketType(b.Type),
+		Description:             b.Description,
+		Name:                    b.Name,
+		RetentionPolicyName:     b.RetentionPolicyName,
+		RetentionPeriod:         d,
+		ShardGroupDuration:      time.Duration(b.ShardGroupDuration) * time.Second,
+		SchemaType:              influxdb.SchemaType(b.SchemaType),
+		FieldTypeConflictPolicy: influxdb.FieldTypeConflictPolicy(b.FieldTypeConflictPolicy),
+		RetentionRules:          overrides,
+		CRUDLog:                 b.CRUDLog,
 	}, nil
 }
 
@@ -195,24 +294,18 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		return nil
 	}
 
-	rules := []retentionRule{}
-	rp := int64(pb.RetentionPeriod.Round(time.Second) / time.Second)
-	if rp > 0 {
-		rules = append(rules, retentionRule{
-			Type:         "expire",
-			EverySeconds: rp,
-		})
-	}
-
 	return &bucket{
-		ID:                  pb.ID,
-		OrgID:               pb.OrgID,
-		Type:                pb.Type.String(),
-		Name:                pb.Name,
-		Description:         pb.Description,
-		RetentionPolicyName: pb.RetentionPolicyName,
-		RetentionRules:      rules,
-		CRUDLog:             pb.CRUDLog,
+		ID:                      pb.ID,
+		OrgID:                   pb.OrgID,
+		Type:                    pb.Type.String(),
+		Name:                    pb.Name,
+		Description:             pb.Description,
+		RetentionPolicyName:     pb.RetentionPolicyName,
+		RetentionRules:          newRetentionRules(pb.RetentionPeriod, pb.RetentionRules),
+		ShardGroupDuration:      int64(pb.ShardGroupDuration.Round(time.Second) / time.Second),
+		SchemaType:              string(pb.SchemaType),
+		FieldTypeConflictPolicy: string(pb.FieldTypeConflictPolicy),
+		CRUDLog:                 pb.CRUDLog,
 	}
 }
 
@@ -221,6 +314,10 @@ type bucketUpdate struct {
 	Name           *string         `json:"name,omitempty"`
 	Description    *string         `json:"description,omitempty"`
 	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	// ShardGroupDuration is in seconds.
+	ShardGroupDuration *int64 `json:"shardGroupDurationSeconds,omitempty"`
+
+	FieldTypeConflictPolicy *influxdb.FieldTypeConflictPolicy `json:"fieldTypeConflictPolicy,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -228,22 +325,27 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		return nil, nil
 	}
 
-	// For now, only use a single retention rule.
-	var d time.Duration
-	var err error
-
-	if len(b.RetentionRules) > 0 {
-		d, err = b.RetentionRules[0].RetentionPeriod()
-		if err != nil {
-			return nil, err
-		}
+	d, overrides, err := bucketRetention(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
-	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
-	}, nil
+	upd := &influxdb.BucketUpdate{
+		Name:                    b.Name,
+		Description:             b.Description,
+		FieldTypeConflictPolicy: b.FieldTypeConflictPolicy,
+	}
+	// an empty list of retention rules keeps data forever, while a missing
+	// list leaves the retention period and rules unchanged.
+	if b.RetentionRules != nil {
+		upd.RetentionPeriod = &d
+		upd.RetentionRules = &overrides
+	}
+	if b.ShardGroupDuration != nil {
+		sgd := time.Duration(*b.ShardGroupDuration) * time.Second
+		upd.ShardGroupDuration = &sgd
+	}
+	return upd, nil
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -252,9 +354,10 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:                    pb.Name,
+		Description:             pb.Description,
+		RetentionRules:          []retentionRule{},
+		FieldTypeConflictPolicy: pb.FieldTypeConflictPolicy,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -264,6 +367,13 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 			EverySeconds: d,
 		})
 	}
+	if pb.RetentionRules != nil {
+		up.RetentionRules = append(up.RetentionRules, newRetentionRules(0, *pb.RetentionRules)...)
+	}
+	if pb.ShardGroupDuration != nil {
+		sgd := int64((*pb.ShardGroupDuration).Round(time.Second) / time.Second)
+		up.ShardGroupDuration = &sgd
+	}
 	return up
 }
 
@@ -340,11 +450,14 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 }
 
 type postBucketRequest struct {
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Name                string          `json:"name"`
-	Description         string          `json:"description"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	OrgID                   influxdb.ID     `json:"orgID,omitempty"`
+	Name                    string          `json:"name"`
+	Description             string          `json:"description"`
+	RetentionPolicyName     string          `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules          []retentionRule `json:"retentionRules"`
+	ShardGroupDuration      int64           `json:"shardGroupDurationSeconds,omitempty"`
+	SchemaType              string          `json:"schemaType,omitempty"`
+	FieldTypeConflictPolicy string          `json:"fieldTypeConflictPolicy,omitempty"`
 }
 
 func (b postBucketRequest) Validate() error {
@@ -359,25 +472,23 @@ func (b postBucketRequest) Validate() error {
 }
 
 func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
-	var dur time.Duration // zero value implies infinite retention policy
-	var err error
-
-	// Only support a single retention period for the moment
-	if len(b.RetentionRules) > 0 {
-		dur, err = b.RetentionRules[0].RetentionPeriod()
-		if err != nil {
-			return nil, err
-		}
+	dur, overrides, err := bucketRetention(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
 	return &influxdb.Bucket{
-		OrgID:               b.OrgID,
-		Description:         b.Description,
-		Name:                b.Name,
-		Type:                influxdb.BucketTypeUser,
-		RetentionPolicyName: b.RetentionPolicyName,
-		RetentionPeriod:     dur,
-	}, err
+		OrgID:                   b.OrgID,
+		Description:             b.Description,
+		Name:                    b.Name,
+		Type:                    influxdb.BucketTypeUser,
+		RetentionPolicyName:     b.RetentionPolicyName,
+		RetentionPeriod:         dur,
+		ShardGroupDuration:      time.Duration(b.ShardGroupDuration) * time.Second,
+		SchemaType:              influxdb.SchemaType(b.SchemaType),
+		FieldTypeConflictPolicy: influxdb.FieldTypeConflictPolicy(b.FieldTypeConflictPolicy),
+		RetentionRules:          overrides,
+	}, nil
 }
 
 func decodePostBucketRequest(ctx context.Context, r *http.Request) (*postBucketRequest, error) {
@@ -416,7 +527,7 @@ func (h *BucketHandler) handleGetBucket(w http.ResponseWriter, r *http.Request)
 
 	h.Logger.Debug("bucket retrieved", zap.String("bucket", fmt.Sprint(b)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketResponse(b, labels)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newBucketResponse(b, labels), req.fields, ""); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -424,6 +535,7 @@ func (h *BucketHandler) handleGetBucket(w http.ResponseWriter, r *http.Request)
 
 type getBucketRequest struct {
 	BucketID influxdb.ID
+	fields   fieldSelection
 }
 
 func bucketIDPath(id influxdb.ID) string {
@@ -497,6 +609,445 @@ func newBucketLogResponse(id influxdb.ID, es []*influxdb.OperationLogEntry) *ope
 	}
 }
 
+// handleGetBucketShards is the HTTP handler for the GET /api/v2/buckets/:id/shards route.
+func (h *BucketHandler) handleGetBucketShards(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	stats, err := h.ShardService.FindShardGroupStats(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket shards retrieved", zap.String("bucket", req.BucketID.String()), zap.Int("shardGroups", len(stats)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketShardsResponse(req.BucketID, stats)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type bucketShardsResponse struct {
+	Links       map[string]string           `json:"links"`
+	ShardGroups []*influxdb.ShardGroupStats `json:"shardGroups"`
+}
+
+func newBucketShardsResponse(id influxdb.ID, stats []*influxdb.ShardGroupStats) *bucketShardsResponse {
+	if stats == nil {
+		stats = []*influxdb.ShardGroupStats{}
+	}
+	return &bucketShardsResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/shards", id),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", id),
+		},
+		ShardGroups: stats,
+	}
+}
+
+// handleGetBucketCompactionPlan is the HTTP handler for the GET /api/v2/buckets/:id/shards/compactionPlan route.
+func (h *BucketHandler) handleGetBucketCompactionPlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	plan, err := h.ShardService.FindCompactionPlan(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket compaction plan retrieved", zap.String("bucket", req.BucketID.String()), zap.Int("groups", len(plan)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketCompactionPlanResponse(req.BucketID, plan)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type bucketCompactionPlanResponse struct {
+	Links  map[string]string               `json:"links"`
+	Groups []*influxdb.CompactionPlanGroup `json:"groups"`
+}
+
+func newBucketCompactionPlanResponse(id influxdb.ID, plan []*influxdb.CompactionPlanGroup) *bucketCompactionPlanResponse {
+	if plan == nil {
+		plan = []*influxdb.CompactionPlanGroup{}
+	}
+	return &bucketCompactionPlanResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/shards/compactionPlan", id),
+			"shards": fmt.Sprintf("/api/v2/buckets/%s/shards", id),
+		},
+		Groups: plan,
+	}
+}
+
+// handlePostBucketOptimize is the HTTP handler for the POST /api/v2/buckets/:id/optimize route.
+func (h *BucketHandler) handlePostBucketOptimize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodePostBucketOptimizeRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.BucketOptimizationService.OptimizeBucket(ctx, req.BucketID, req.WindowStart, req.WindowStop)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket optimization scheduled", zap.String("bucket", req.BucketID.String()), zap.Time("windowStart", o.WindowStart))
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newBucketOptimizationResponse(*o)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postBucketOptimizeRequest struct {
+	BucketID    influxdb.ID
+	WindowStart time.Time
+	WindowStop  time.Time
+}
+
+func decodePostBucketOptimizeRequest(ctx context.Context, r *http.Request) (*postBucketOptimizeRequest, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		WindowStart time.Time `json:"windowStart"`
+		WindowStop  time.Time `json:"windowStop"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+	if body.WindowStop.IsZero() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "you must provide the stop of the optimization window",
+		}
+	}
+
+	return &postBucketOptimizeRequest{
+		BucketID:    req.BucketID,
+		WindowStart: body.WindowStart,
+		WindowStop:  body.WindowStop,
+	}, nil
+}
+
+// handleGetBucketOptimize is the HTTP handler for the GET /api/v2/buckets/:id/optimize route.
+func (h *BucketHandler) handleGetBucketOptimize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.BucketOptimizationService.FindBucketOptimization(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketOptimizationResponse(*o)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteBucketOptimize is the HTTP handler for the DELETE /api/v2/buckets/:id/optimize route.
+func (h *BucketHandler) handleDeleteBucketOptimize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BucketOptimizationService.CancelBucketOptimization(ctx, req.BucketID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket optimization canceled", zap.String("bucket", req.BucketID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type bucketOptimizationResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.BucketOptimization
+}
+
+func newBucketOptimizationResponse(o influxdb.BucketOptimization) bucketOptimizationResponse {
+	return bucketOptimizationResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/optimize", o.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", o.BucketID),
+			"shards": fmt.Sprintf("/api/v2/buckets/%s/shards", o.BucketID),
+		},
+		BucketOptimization: o,
+	}
+}
+
+// handleGetBucketMeasurements is the HTTP handler for the GET /api/v2/buckets/:id/measurements route.
+func (h *BucketHandler) handleGetBucketMeasurements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketMeasurementsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, err := h.BucketSchemaService.FindMeasurements(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket measurements retrieved", zap.String("bucket", req.BucketID.String()), zap.Int("measurements", len(ms)))
+
+	res := bucketMeasurementsResponse{
+		Links:        newBucketSchemaLinks(r, req.BucketID),
+		Measurements: nonNilStrings(ms),
+	}
+	if req.withLastWrite {
+		if h.MeasurementLastWriteService == nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EUnavailable,
+				Msg:  "the last writes to measurements are not recorded",
+			}, w)
+			return
+		}
+		lws, err := h.MeasurementLastWriteService.FindMeasurementLastWrites(ctx, req.BucketID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		res.LastWrites = newMeasurementLastWrites(ms, lws)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetBucketFields is the HTTP handler for the GET /api/v2/buckets/:id/fields route.
+func (h *BucketHandler) handleGetBucketFields(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketSchemaRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	fs, err := h.BucketSchemaService.FindFields(ctx, req.BucketID, req.filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket fields retrieved", zap.String("bucket", req.BucketID.String()), zap.Int("fields", len(fs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, bucketFieldsResponse{
+		Links:  newBucketSchemaLinks(r, req.BucketID),
+		Fields: nonNilStrings(fs),
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetBucketTagValues is the HTTP handler for the GET /api/v2/buckets/:id/tag-values route.
+func (h *BucketHandler) handleGetBucketTagValues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketSchemaRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	vs, err := h.BucketSchemaService.FindTagValues(ctx, req.BucketID, key, req.filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket tag values retrieved", zap.String("bucket", req.BucketID.String()), zap.String("key", key), zap.Int("values", len(vs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, bucketTagValuesResponse{
+		Links:  newBucketSchemaLinks(r, req.BucketID),
+		Key:    key,
+		Values: nonNilStrings(vs),
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type bucketMeasurementsResponse struct {
+	Links        map[string]string `json:"links"`
+	Measurements []string          `json:"measurements"`
+	// LastWrites are the times of the last writes to the measurements, by
+	// measurement, if requested. A measurement whose last write is unknown
+	// has none.
+	LastWrites map[string]*time.Time `json:"lastWrites,omitempty"`
+}
+
+// newMeasurementLastWrites returns the last writes of the measurements ms,
+// which may not be known. Measurements whose data was deleted are left out.
+func newMeasurementLastWrites(ms []string, lws influxdb.MeasurementLastWrites) map[string]*time.Time {
+	res := make(map[string]*time.Time, len(ms))
+	for _, m := range ms {
+		if t, ok := lws[m]; ok {
+			t := t
+			res[m] = &t
+		} else {
+			res[m] = nil
+		}
+	}
+	return res
+}
+
+type getBucketMeasurementsRequest struct {
+	getBucketRequest
+	withLastWrite bool
+}
+
+func decodeGetBucketMeasurementsRequest(ctx context.Context, r *http.Request) (*getBucketMeasurementsRequest, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &getBucketMeasurementsRequest{getBucketRequest: *req}
+	if v := r.URL.Query().Get("withLastWrite"); v != "" {
+		if res.withLastWrite, err = strconv.ParseBool(v); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "withLastWrite must be a boolean",
+			}
+		}
+	}
+	return res, nil
+}
+
+type bucketFieldsResponse struct {
+	Links  map[string]string `json:"links"`
+	Fields []string          `json:"fields"`
+}
+
+type bucketTagValuesResponse struct {
+	Links  map[string]string `json:"links"`
+	Key    string            `json:"key"`
+	Values []string          `json:"values"`
+}
+
+func newBucketSchemaLinks(r *http.Request, id influxdb.ID) map[string]string {
+	return map[string]string{
+		"self":   r.URL.RequestURI(),
+		"bucket": fmt.Sprintf("/api/v2/buckets/%s", id),
+	}
+}
+
+func nonNilStrings(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
+
+type getBucketSchemaRequest struct {
+	BucketID influxdb.ID
+	filter   influxdb.BucketSchemaFilter
+}
+
+func decodeGetBucketSchemaRequest(ctx context.Context, r *http.Request) (*getBucketSchemaRequest, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter influxdb.BucketSchemaFilter
+	if m, ok := r.URL.Query()["measurement"]; ok {
+		filter.Measurement = &m[0]
+	}
+	return &getBucketSchemaRequest{
+		BucketID: req.BucketID,
+		filter:   filter,
+	}, nil
+}
+
+// handleGetBucketSample is the HTTP handler for the GET /api/v2/buckets/:id/sample route.
+func (h *BucketHandler) handleGetBucketSample(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetBucketSampleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rows, err := h.BucketSampleService.SampleBucket(ctx, req.BucketID, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("bucket sampled", zap.String("bucket", req.BucketID.String()), zap.Int("rows", len(rows)))
+
+	if rows == nil {
+		rows = []*influxdb.BucketSampleRow{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, bucketSampleResponse{
+		Links: newBucketSchemaLinks(r, req.BucketID),
+		Rows:  rows,
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type bucketSampleResponse struct {
+	Links map[string]string           `json:"links"`
+	Rows  []*influxdb.BucketSampleRow `json:"rows"`
+}
+
+type getBucketSampleRequest struct {
+	BucketID influxdb.ID
+	opts     influxdb.BucketSampleOptions
+}
+
+func decodeGetBucketSampleRequest(ctx context.Context, r *http.Request) (*getBucketSampleRequest, error) {
+	req, err := decodeGetBucketSchemaRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := influxdb.BucketSampleOptions{Measurement: req.filter.Measurement}
+	if n := r.URL.Query().Get("n"); n != "" {
+		if opts.N, err = strconv.Atoi(n); err != nil || opts.N < 1 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "n must be a positive integer",
+			}
+		}
+	}
+	return &getBucketSampleRequest{
+		BucketID: req.BucketID,
+		opts:     opts,
+	}, nil
+}
+
 func decodeGetBucketRequest(ctx context.Context, r *http.Request) (*getBucketRequest, error) {
 	params := httprouter.ParamsFromContext(ctx)
 	id := params.ByName("id")
@@ -511,8 +1062,14 @@ func decodeGetBucketRequest(ctx context.Context, r *http.Request) (*getBucketReq
 	if err := i.DecodeFromString(id); err != nil {
 		return nil, err
 	}
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &getBucketRequest{
 		BucketID: i,
+		fields:   fields,
 	}
 
 	return req, nil
@@ -581,7 +1138,7 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 	}
 	h.Logger.Debug("buckets retrieved", zap.String("buckets", fmt.Sprint(bs)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService), req.fields, "buckets"); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -590,6 +1147,7 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 type getBucketsRequest struct {
 	filter influxdb.BucketFilter
 	opts   influxdb.FindOptions
+	fields fieldSelection
 }
 
 func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsRequest, error) {
@@ -627,6 +1185,12 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 		req.filter.ID = id
 	}
 
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+	req.fields = fields
+
 	return req, nil
 }
 
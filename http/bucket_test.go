@@ -8,6 +8,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/pkg/testttp"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/influxdata/httprouter"
 	"go.uber.org/zap"
@@ -31,6 +34,10 @@ func NewMockBucketBackend() *BucketBackend {
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
 		OrganizationService:        mock.NewOrganizationService(),
+		ShardService:               mock.NewShardService(),
+		BucketOptimizationService:  mock.NewBucketOptimizationService(),
+		BucketSchemaService:        mock.NewBucketSchemaService(),
+		BucketSampleService:        mock.NewBucketSampleService(),
 	}
 }
 
@@ -363,6 +370,317 @@ func TestService_handleGetBucket(t *testing.T) {
 	}
 }
 
+func TestService_handleGetBucketShards(t *testing.T) {
+	bucketID := platform.ID(0x020f755c3c082000)
+	start := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	shards := mock.NewShardService()
+	shards.FindShardGroupStatsFn = func(ctx context.Context, id platform.ID) ([]*platform.ShardGroupStats, error) {
+		if id != bucketID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return []*platform.ShardGroupStats{
+			{
+				BucketID:         id,
+				StartTime:        start,
+				EndTime:          start.Add(7 * 24 * time.Hour),
+				DiskBytes:        2048,
+				TSMFileCount:     2,
+				CompactionLevels: map[int]int{1: 1, 4: 1},
+				WALBytes:         512,
+			},
+		}, nil
+	}
+	backend.ShardService = shards
+	h := NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/shards").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/shards",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "shardGroups": [
+    {
+      "bucketID": "020f755c3c082000",
+      "startTime": "2020-01-06T00:00:00Z",
+      "endTime": "2020-01-13T00:00:00Z",
+      "diskBytes": 2048,
+      "tsmFileCount": 2,
+      "compactionLevels": {"1": 1, "4": 1},
+      "walBytes": 512
+    }
+  ]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082001/shards").
+		Do(h).
+		ExpectStatus(t, http.StatusNotFound)
+
+	testttp.Get("/api/v2/buckets/invalid/shards").
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest).
+		ExpectBody(func(body *bytes.Buffer) {
+			if !strings.Contains(body.String(), "invalid") {
+				t.Errorf("unexpected error %s", body.String())
+			}
+		})
+}
+
+func TestService_handleGetBucketCompactionPlan(t *testing.T) {
+	bucketID := platform.ID(0x020f755c3c082000)
+
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	shards := mock.NewShardService()
+	shards.FindCompactionPlanFn = func(ctx context.Context, id platform.ID) ([]*platform.CompactionPlanGroup, error) {
+		if id != bucketID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return []*platform.CompactionPlanGroup{
+			{
+				Level:          1,
+				Files:          []string{"000000001-000000001.tsm", "000000002-000000001.tsm"},
+				EstimatedBytes: 4096,
+				EstimatedFiles: 1,
+			},
+		}, nil
+	}
+	backend.ShardService = shards
+	h := NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/shards/compactionPlan").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/shards/compactionPlan",
+    "shards": "/api/v2/buckets/020f755c3c082000/shards"
+  },
+  "groups": [
+    {
+      "level": 1,
+      "optimize": false,
+      "files": ["000000001-000000001.tsm", "000000002-000000001.tsm"],
+      "estimatedBytes": 4096,
+      "estimatedFiles": 1
+    }
+  ]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082001/shards/compactionPlan").
+		Do(h).
+		ExpectStatus(t, http.StatusNotFound)
+}
+
+func TestService_handleGetBucketSchema(t *testing.T) {
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	schema := mock.NewBucketSchemaService()
+	schema.FindMeasurementsFn = func(ctx context.Context, id platform.ID) ([]string, error) {
+		return []string{"cpu", "mem"}, nil
+	}
+	schema.FindFieldsFn = func(ctx context.Context, id platform.ID, filter platform.BucketSchemaFilter) ([]string, error) {
+		if filter.Measurement == nil || *filter.Measurement != "cpu" {
+			return nil, nil
+		}
+		return []string{"usage_idle"}, nil
+	}
+	schema.FindTagValuesFn = func(ctx context.Context, id platform.ID, key string, filter platform.BucketSchemaFilter) ([]string, error) {
+		if key == "" {
+			return nil, &platform.Error{Code: platform.EInvalid, Msg: "tag key is required"}
+		}
+		return []string{"a", "b"}, nil
+	}
+	backend.BucketSchemaService = schema
+	h := NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/measurements").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/measurements",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "measurements": ["cpu", "mem"]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/fields?measurement=cpu").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/fields?measurement=cpu",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "fields": ["usage_idle"]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/fields").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if !strings.Contains(body.String(), `"fields":[]`) {
+				t.Errorf("expected no fields, got %s", body.String())
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/tag-values?key=host").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/tag-values?key=host",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "key": "host",
+  "values": ["a", "b"]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/tag-values").
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+}
+
+func TestService_handleGetBucketSample(t *testing.T) {
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	samples := mock.NewBucketSampleService()
+	samples.SampleBucketFn = func(ctx context.Context, id platform.ID, opts platform.BucketSampleOptions) ([]*platform.BucketSampleRow, error) {
+		if opts.Measurement == nil || *opts.Measurement != "cpu" || opts.N != 1 {
+			return nil, &platform.Error{Code: platform.EInvalid, Msg: "unexpected options"}
+		}
+		return []*platform.BucketSampleRow{{
+			Time:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "a"},
+			Fields:      map[string]interface{}{"usage": 0.5},
+		}}, nil
+	}
+	backend.BucketSampleService = samples
+	h := NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/sample?measurement=cpu&n=1").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/sample?measurement=cpu&n=1",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "rows": [
+    {
+      "time": "2020-01-01T00:00:00Z",
+      "measurement": "cpu",
+      "tags": {"host": "a"},
+      "fields": {"usage": 0.5}
+    }
+  ]
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/sample?n=none").
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+}
+
+func TestService_handleBucketOptimize(t *testing.T) {
+	start := time.Date(2020, 1, 6, 2, 0, 0, 0, time.UTC)
+
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	optimizations := mock.NewBucketOptimizationService()
+	optimizations.OptimizeBucketFn = func(ctx context.Context, id platform.ID, windowStart, windowStop time.Time) (*platform.BucketOptimization, error) {
+		if !windowStart.Equal(start) || !windowStop.Equal(start.Add(2*time.Hour)) {
+			return nil, &platform.Error{Code: platform.EInvalid, Msg: "unexpected window"}
+		}
+		return &platform.BucketOptimization{
+			BucketID:    id,
+			OrgID:       1,
+			WindowStart: windowStart,
+			WindowStop:  windowStop,
+			Status:      platform.BucketOptimizationScheduled,
+			CreatedAt:   start.Add(-time.Hour),
+		}, nil
+	}
+	optimizations.CancelBucketOptimizationFn = func(ctx context.Context, id platform.ID) error {
+		return platform.ErrBucketOptimizationNotFound
+	}
+	backend.BucketOptimizationService = optimizations
+	h := NewBucketHandler(backend)
+
+	testttp.Post("/api/v2/buckets/020f755c3c082000/optimize", strings.NewReader(`{"windowStart": "2020-01-06T02:00:00Z", "windowStop": "2020-01-06T04:00:00Z"}`)).
+		Do(h).
+		ExpectStatus(t, http.StatusAccepted).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/optimize",
+    "bucket": "/api/v2/buckets/020f755c3c082000",
+    "shards": "/api/v2/buckets/020f755c3c082000/shards"
+  },
+  "bucketID": "020f755c3c082000",
+  "orgID": "0000000000000001",
+  "windowStart": "2020-01-06T02:00:00Z",
+  "windowStop": "2020-01-06T04:00:00Z",
+  "status": "scheduled",
+  "shardGroups": 0,
+  "optimizedShardGroups": 0,
+  "indexOptimized": false,
+  "createdAt": "2020-01-06T01:00:00Z"
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Post("/api/v2/buckets/020f755c3c082000/optimize", strings.NewReader(`{"windowStart": "2020-01-06T02:00:00Z"}`)).
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/optimize").
+		Do(h).
+		ExpectStatus(t, http.StatusOK)
+
+	testttp.Delete("/api/v2/buckets/020f755c3c082000/optimize").
+		Do(h).
+		ExpectStatus(t, http.StatusNotFound)
+}
+
 func TestService_handlePostBucket(t *testing.T) {
 	type fields struct {
 		BucketService       platform.BucketService
@@ -1122,3 +1440,96 @@ func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.B
 func TestBucketService(t *testing.T) {
 	platformtesting.BucketService(initBucketService, t)
 }
+
+func TestBucketRetentionRules(t *testing.T) {
+	const day = 24 * time.Hour
+	rules := `[
+  {"type": "expire", "everySeconds": 7776000},
+  {"type": "expire", "everySeconds": 259200, "measurement": "debug_*"},
+  {"type": "expire", "everySeconds": 0, "tags": [{"key": "host", "value": "core-*"}]}
+]`
+	wantRules := []platform.BucketRetentionRule{
+		{Measurement: "debug_*", RetentionPeriod: 3 * day},
+		{Tags: []platform.Tag{{Key: "host", Value: "core-*"}}},
+	}
+
+	var bu bucketUpdate
+	if err := json.Unmarshal([]byte(`{"retentionRules": `+rules+`}`), &bu); err != nil {
+		t.Fatal(err)
+	}
+	upd, err := bu.toInfluxDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upd.RetentionPeriod == nil || *upd.RetentionPeriod != 90*day {
+		t.Fatalf("unexpected retention period %v", upd.RetentionPeriod)
+	}
+	if upd.RetentionRules == nil || !reflect.DeepEqual(*upd.RetentionRules, wantRules) {
+		t.Fatalf("unexpected retention rules %+v", upd.RetentionRules)
+	}
+
+	b := &platform.Bucket{RetentionPeriod: 90 * day, RetentionRules: wantRules}
+	got, err := json.Marshal(map[string]interface{}{"retentionRules": newBucket(b).RetentionRules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eq, diff, err := jsonEqual(string(got), `{"retentionRules": `+rules+`}`); err != nil || !eq {
+		t.Fatalf("unexpected retention rules %s: %s %v", got, diff, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"retentionRules": [{"type": "expire", "everySeconds": -1, "measurement": "cpu"}]}`), &bu); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bu.toInfluxDB(); platform.ErrorCode(err) != platform.EUnprocessableEntity {
+		t.Fatalf("expected negative expiration to be rejected, got %v", err)
+	}
+}
+
+func TestService_handleGetBucketMeasurementsWithLastWrite(t *testing.T) {
+	backend := NewMockBucketBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	schema := mock.NewBucketSchemaService()
+	schema.FindMeasurementsFn = func(ctx context.Context, id platform.ID) ([]string, error) {
+		return []string{"cpu", "mem"}, nil
+	}
+	backend.BucketSchemaService = schema
+	h := NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/measurements?withLastWrite=true").
+		Do(h).
+		ExpectStatus(t, http.StatusServiceUnavailable)
+
+	lastWrites := mock.NewMeasurementLastWriteService()
+	lastWrites.FindMeasurementLastWritesFn = func(ctx context.Context, id platform.ID) (platform.MeasurementLastWrites, error) {
+		return platform.MeasurementLastWrites{
+			"cpu":  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			"disk": time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, nil
+	}
+	backend.MeasurementLastWriteService = lastWrites
+	h = NewBucketHandler(backend)
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/measurements?withLastWrite=true").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			if eq, diff, _ := jsonEqual(body.String(), `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/measurements?withLastWrite=true",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "measurements": ["cpu", "mem"],
+  "lastWrites": {
+    "cpu": "2020-01-01T00:00:00Z",
+    "mem": null
+  }
+}`); !eq {
+				t.Errorf("unexpected response: %s", diff)
+			}
+		})
+
+	testttp.Get("/api/v2/buckets/020f755c3c082000/measurements?withLastWrite=maybe").
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
@@ -22,6 +23,8 @@ type CheckBackend struct {
 
 	TaskService                influxdb.TaskService
 	CheckService               influxdb.CheckService
+	MonitoringRunService       influxdb.MonitoringRunService
+	CheckStatusStream          influxdb.CheckStatusStream
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -36,6 +39,8 @@ func NewCheckBackend(b *APIBackend) *CheckBackend {
 
 		TaskService:                b.TaskService,
 		CheckService:               b.CheckService,
+		MonitoringRunService:       b.MonitoringRunService,
+		CheckStatusStream:          b.CheckStatusStream,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -45,12 +50,14 @@ func NewCheckBackend(b *APIBackend) *CheckBackend {
 
 // CheckHandler is the handler for the check service
 type CheckHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
 	TaskService                influxdb.TaskService
 	CheckService               influxdb.CheckService
+	MonitoringRunService       influxdb.MonitoringRunService
+	CheckStatusStream          influxdb.CheckStatusStream
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -61,12 +68,23 @@ const (
 	checksPath            = "/api/v2/checks"
 	checksIDPath          = "/api/v2/checks/:id"
 	checksIDQueryPath     = "/api/v2/checks/:id/query"
+	checksIDRunsPath      = "/api/v2/checks/:id/runs"
 	checksIDMembersPath   = "/api/v2/checks/:id/members"
 	checksIDMembersIDPath = "/api/v2/checks/:id/members/:userID"
 	checksIDOwnersPath    = "/api/v2/checks/:id/owners"
 	checksIDOwnersIDPath  = "/api/v2/checks/:id/owners/:userID"
 	checksIDLabelsPath    = "/api/v2/checks/:id/labels"
 	checksIDLabelsIDPath  = "/api/v2/checks/:id/labels/:lid"
+
+	// checksStatusesStreamPath is the path of the stream of status events,
+	// "/api/v2/checks/statuses/stream". The router does not allow a static
+	// segment next to the :id wildcard, so it is routed as a check path.
+	checksStatusesStreamPath = "/api/v2/checks/:id/stream"
+	checksStatusesID         = "statuses"
+
+	// checkStatusesKeepAlive is the interval of the comments keeping streams
+	// of status events alive.
+	checkStatusesKeepAlive = 30 * time.Second
 )
 
 // NewCheckHandler returns a new instance of CheckHandler.
@@ -77,6 +95,8 @@ func NewCheckHandler(b *CheckBackend) *CheckHandler {
 		Logger:           b.Logger,
 
 		CheckService:               b.CheckService,
+		MonitoringRunService:       b.MonitoringRunService,
+		CheckStatusStream:          b.CheckStatusStream,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -87,6 +107,8 @@ func NewCheckHandler(b *CheckBackend) *CheckHandler {
 	h.HandlerFunc("GET", checksPath, h.handleGetChecks)
 	h.HandlerFunc("GET", checksIDPath, h.handleGetCheck)
 	h.HandlerFunc("GET", checksIDQueryPath, h.handleGetCheckQuery)
+	h.HandlerFunc("GET", checksIDRunsPath, h.handleGetCheckRuns)
+	h.HandlerFunc("GET", checksStatusesStreamPath, h.handleGetCheckStatusesStream)
 	h.HandlerFunc("DELETE", checksIDPath, h.handleDeleteCheck)
 	h.HandlerFunc("PUT", checksIDPath, h.handlePutCheck)
 	h.HandlerFunc("PATCH", checksIDPath, h.handlePatchCheck)
@@ -134,6 +156,7 @@ type checkLinks struct {
 	Members string `json:"members"`
 	Owners  string `json:"owners"`
 	Query   string `json:"query"`
+	Runs    string `json:"runs"`
 }
 
 type checkResponse struct {
@@ -197,6 +220,7 @@ func (h *CheckHandler) newCheckResponse(ctx context.Context, chk influxdb.Check,
 			Members: fmt.Sprintf("/api/v2/checks/%s/members", chk.GetID()),
 			Owners:  fmt.Sprintf("/api/v2/checks/%s/owners", chk.GetID()),
 			Query:   fmt.Sprintf("/api/v2/checks/%s/query", chk.GetID()),
+			Runs:    fmt.Sprintf("/api/v2/checks/%s/runs", chk.GetID()),
 		},
 		Labels: []influxdb.Label{},
 	}
@@ -289,6 +313,31 @@ func (h *CheckHandler) handleGetCheckQuery(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+func (h *CheckHandler) handleGetCheckRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetRunsRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	id := req.filter.Task
+	runs, _, err := h.MonitoringRunService.FindCheckRuns(ctx, id, req.filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("check runs retrieved", zap.String("checkID", id.String()), zap.Int("runs", len(runs)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newMonitoringRunsResponse(runs, fmt.Sprintf("/api/v2/checks/%s", id))); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 type fluxResp struct {
 	Flux string `json:"flux"`
 }
@@ -665,3 +714,105 @@ func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func decodeCheckStatusEventFilter(ctx context.Context, r *http.Request, orgs influxdb.OrganizationService) (influxdb.CheckStatusEventFilter, error) {
+	var f influxdb.CheckStatusEventFilter
+	if id := httprouter.ParamsFromContext(ctx).ByName("id"); id != checksStatusesID {
+		return f, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "path not found",
+		}
+	}
+
+	q := r.URL.Query()
+	if orgIDStr := q.Get("orgID"); orgIDStr != "" {
+		if err := f.OrgID.DecodeFromString(orgIDStr); err != nil {
+			return f, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is invalid",
+				Err:  err,
+			}
+		}
+	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
+		org, err := orgs.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &orgNameStr})
+		if err != nil {
+			return f, err
+		}
+		f.OrgID = org.ID
+	} else {
+		return f, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}
+	}
+
+	if checkIDStr := q.Get("checkID"); checkIDStr != "" {
+		checkID, err := influxdb.IDFromString(checkIDStr)
+		if err != nil {
+			return f, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "checkID is invalid",
+				Err:  err,
+			}
+		}
+		f.CheckID = checkID
+	}
+	return f, nil
+}
+
+// handleGetCheckStatusesStream streams the status events of checks as
+// server-sent events, until the client disconnects.
+func (h *CheckHandler) handleGetCheckStatusesStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeCheckStatusEventFilter(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "streaming is not supported",
+		}, w)
+		return
+	}
+
+	events, err := h.CheckStatusStream.SubscribeCheckStatuses(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(checkStatusesKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				h.Logger.Info("Failed to encode status event", zap.Error(err))
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/flux/parser"
 	pcontext "github.com/influxdata/influxdb/context"
@@ -28,6 +29,7 @@ func NewMockCheckBackend() *CheckBackend {
 		Logger: zap.NewNop().With(zap.String("handler", "check")),
 
 		CheckService:               mock.NewCheckService(),
+		MonitoringRunService:       mock.NewMonitoringRunService(),
 		UserResourceMappingService: mock.NewUserResourceMappingService(),
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
@@ -132,6 +134,7 @@ func TestService_handleGetChecks(t *testing.T) {
         "self": "/api/v2/checks/0b501e7e557ab1ed",
         "labels": "/api/v2/checks/0b501e7e557ab1ed/labels",
         "query": "/api/v2/checks/0b501e7e557ab1ed/query",
+        "runs": "/api/v2/checks/0b501e7e557ab1ed/runs",
         "owners": "/api/v2/checks/0b501e7e557ab1ed/owners",
         "members": "/api/v2/checks/0b501e7e557ab1ed/members"
       },
@@ -175,7 +178,8 @@ func TestService_handleGetChecks(t *testing.T) {
         "labels": "/api/v2/checks/c0175f0077a77005/labels",
         "members": "/api/v2/checks/c0175f0077a77005/members",
         "owners": "/api/v2/checks/c0175f0077a77005/owners",
-        "query": "/api/v2/checks/c0175f0077a77005/query"
+        "query": "/api/v2/checks/c0175f0077a77005/query",
+        "runs": "/api/v2/checks/c0175f0077a77005/runs"
       },
 			"createdAt": "0001-01-01T00:00:00Z",
 			"updatedAt": "0001-01-01T00:00:00Z",
@@ -463,6 +467,116 @@ func TestService_handleGetCheckQuery(t *testing.T) {
 	}
 }
 
+func TestService_handleGetCheckRuns(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+	scheduledFor := time.Date(2019, 11, 1, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		id    string
+		query string
+		wants wants
+	}{
+		{
+			name:  "get the runs of a check",
+			id:    "020f755c3c082000",
+			query: "?limit=1",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/checks/020f755c3c082000/runs"
+  },
+  "runs": [
+    {
+      "links": {
+        "self": "/api/v2/tasks/0000000000000003/runs/0000000000000004",
+        "task": "/api/v2/tasks/0000000000000003",
+        "logs": "/api/v2/tasks/0000000000000003/runs/0000000000000004/logs",
+        "retry": "/api/v2/tasks/0000000000000003/runs/0000000000000004/retry"
+      },
+      "id": "0000000000000004",
+      "taskID": "0000000000000003",
+      "status": "success",
+      "scheduledFor": "2019-11-01T03:00:00Z",
+      "records": [
+        {
+          "time": "2019-11-01T03:00:00Z",
+          "checkID": "020f755c3c082000",
+          "checkName": "hello",
+          "level": "crit",
+          "message": "whoa!"
+        }
+      ]
+    }
+  ]
+}
+`,
+			},
+		},
+		{
+			name:  "invalid limit",
+			id:    "020f755c3c082000",
+			query: "?limit=-1",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkBackend := NewMockCheckBackend()
+			checkBackend.HTTPErrorHandler = ErrorHandler(0)
+			checkBackend.MonitoringRunService = &mock.MonitoringRunService{
+				FindCheckRunsFn: func(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+					if filter.Limit != 1 {
+						return nil, 0, fmt.Errorf("unexpected limit %d", filter.Limit)
+					}
+					return []*influxdb.MonitoringRun{
+						{
+							Run: influxdb.Run{ID: 4, TaskID: 3, Status: "success", ScheduledFor: scheduledFor},
+							Records: []influxdb.MonitoringRecord{
+								{Time: scheduledFor, CheckID: checkID, CheckName: "hello", Level: "crit", Message: "whoa!"},
+							},
+						},
+					}, 1, nil
+				},
+			}
+			h := NewCheckHandler(checkBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url"+tt.query, nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.id,
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handleGetCheckRuns(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handleGetCheckRuns() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil || !eq {
+					t.Errorf("%q. handleGetCheckRuns() = ***%v***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
 func TestService_handleGetCheck(t *testing.T) {
 	type fields struct {
 		CheckService influxdb.CheckService
@@ -516,7 +630,8 @@ func TestService_handleGetCheck(t *testing.T) {
 		    "labels": "/api/v2/checks/020f755c3c082000/labels",
 		    "members": "/api/v2/checks/020f755c3c082000/members",
 		    "owners": "/api/v2/checks/020f755c3c082000/owners",
-		    "query": "/api/v2/checks/020f755c3c082000/query"
+		    "query": "/api/v2/checks/020f755c3c082000/query",
+		    "runs": "/api/v2/checks/020f755c3c082000/runs"
 		  },
 		  "labels": [],
 		  "level": "CRIT",
@@ -688,7 +803,8 @@ func TestService_handlePostCheck(t *testing.T) {
     "labels": "/api/v2/checks/020f755c3c082000/labels",
     "members": "/api/v2/checks/020f755c3c082000/members",
     "owners": "/api/v2/checks/020f755c3c082000/owners",
-    "query": "/api/v2/checks/020f755c3c082000/query"
+    "query": "/api/v2/checks/020f755c3c082000/query",
+    "runs": "/api/v2/checks/020f755c3c082000/runs"
   },
   "reportZero": true,
   "statusMessageTemplate": "msg1",
@@ -947,7 +1063,8 @@ func TestService_handlePatchCheck(t *testing.T) {
 		    "labels": "/api/v2/checks/020f755c3c082000/labels",
 		    "members": "/api/v2/checks/020f755c3c082000/members",
 		    "owners": "/api/v2/checks/020f755c3c082000/owners",
-		    "query": "/api/v2/checks/020f755c3c082000/query"
+		    "query": "/api/v2/checks/020f755c3c082000/query",
+		    "runs": "/api/v2/checks/020f755c3c082000/runs"
 		  },
 		  "createdAt": "0001-01-01T00:00:00Z",
 		  "updatedAt": "0001-01-01T00:00:00Z",
@@ -1127,7 +1244,8 @@ func TestService_handleUpdateCheck(t *testing.T) {
 		    "labels": "/api/v2/checks/020f755c3c082000/labels",
 		    "members": "/api/v2/checks/020f755c3c082000/members",
 		    "owners": "/api/v2/checks/020f755c3c082000/owners",
-		    "query": "/api/v2/checks/020f755c3c082000/query"
+		    "query": "/api/v2/checks/020f755c3c082000/query",
+		    "runs": "/api/v2/checks/020f755c3c082000/runs"
 		  },
 		  "createdAt": "0001-01-01T00:00:00Z",
 		  "updatedAt": "0001-01-01T00:00:00Z",
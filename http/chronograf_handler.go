@@ -4,14 +4,13 @@ import (
 	"net/http"
 
 	"github.com/NYTimes/gziphandler"
-	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/chronograf/server"
 )
 
 // ChronografHandler is an http handler for serving chronograf chronografs.
 type ChronografHandler struct {
-	*httprouter.Router
+	*Router
 	Service *server.Service
 }
 
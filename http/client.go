@@ -1,6 +1,7 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -54,12 +55,19 @@ func NewService(addr, token string) *Service {
 	}
 }
 
-// NewURL concats addr and path.
+// NewURL concats addr and path. The addr unix:///path/to/influxd.sock
+// addresses the server listening on the Unix domain socket at the path.
 func NewURL(addr, path string) (*url.URL, error) {
 	u, err := url.Parse(addr)
 	if err != nil {
 		return nil, err
 	}
+	if u.Scheme == unixSocketScheme {
+		if u.Path == "" {
+			return nil, fmt.Errorf("unix socket address %q requires a path", addr)
+		}
+		u = unixSocketURL(u.Path)
+	}
 	u.Path = path
 	return u, nil
 }
@@ -0,0 +1,279 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultCompressionMinSize is the minimum response size in bytes before
+// a response body is compressed.
+const DefaultCompressionMinSize = 1400
+
+// DefaultCompressionContentTypes are the response media types that are
+// compressed when no explicit allow-list is configured.
+var DefaultCompressionContentTypes = []string{
+	"application/json",
+	"application/csv",
+	"text/csv",
+	"text/plain",
+	"text/html",
+	"text/css",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+// CompressionConfig configures response compression.
+type CompressionConfig struct {
+	// Disabled turns off response compression entirely.
+	Disabled bool
+	// MinSize is the minimum size in bytes a response must reach before it
+	// is compressed. Smaller responses are written uncompressed.
+	MinSize int
+	// ContentTypes is the allow-list of response media types to compress.
+	// Parameters such as charset are ignored when matching.
+	ContentTypes []string
+}
+
+// NewCompressionConfig returns a CompressionConfig with default values.
+func NewCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		MinSize:      DefaultCompressionMinSize,
+		ContentTypes: DefaultCompressionContentTypes,
+	}
+}
+
+// compressWriter is implemented by the writers of the supported content
+// encodings.
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressWriterPools holds the writers of the supported content encodings,
+// in order of preference when a client accepts several of them equally.
+var compressWriterPools = []struct {
+	encoding string
+	pool     *sync.Pool
+}{
+	{
+		encoding: "br",
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+			},
+		},
+	},
+	{
+		encoding: "gzip",
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return gzip.NewWriter(nil)
+			},
+		},
+	},
+}
+
+// CompressionMW returns a middleware that brotli or gzip encodes responses
+// for clients that accept either encoding, preferring brotli. Responses that
+// already carry a Content-Encoding, such as the query endpoint, are passed
+// through untouched.
+func CompressionMW(c CompressionConfig) Middleware {
+	if c.Disabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	contentTypes := c.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = DefaultCompressionContentTypes
+	}
+	allowed := make(map[string]bool, len(contentTypes))
+	for _, ct := range contentTypes {
+		allowed[strings.ToLower(strings.TrimSpace(ct))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding, pool := acceptedEncoding(r)
+			if pool == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				minSize:        c.MinSize,
+				allowed:        allowed,
+				encoding:       encoding,
+				pool:           pool,
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// acceptedEncoding returns the supported content encoding with the highest
+// q-value in the Accept-Encoding header of r, along with the pool of its
+// writers. The pool is nil when the client accepts none of them.
+func acceptedEncoding(r *http.Request) (string, *sync.Pool) {
+	qs := make(map[string]float64)
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(enc, ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		q := 1.0
+		for _, param := range parts[1:] {
+			param = strings.Replace(strings.TrimSpace(param), " ", "", -1)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(param[2:], 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		qs[name] = q
+	}
+
+	var (
+		encoding string
+		pool     *sync.Pool
+		best     float64
+	)
+	for _, p := range compressWriterPools {
+		// a q-value of zero explicitly refuses the encoding.
+		if q := qs[p.encoding]; q > best {
+			encoding, pool, best = p.encoding, p.pool, q
+		}
+	}
+	return encoding, pool
+}
+
+// compressResponseWriter buffers the response until either minSize bytes
+// have been written, at which point it decides whether to compress, or the
+// handler returns, in which case the response is written as is.
+type compressResponseWriter struct {
+	http.ResponseWriter
+
+	minSize  int
+	allowed  map[string]bool
+	encoding string
+	pool     *sync.Pool
+
+	code    int
+	buf     []byte
+	decided bool
+	cw      compressWriter
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.cw != nil {
+			return w.cw.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.minSize {
+		return len(b), nil
+	}
+
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// decide chooses between the accepted and identity encoding and flushes any
+// buffered bytes.
+func (w *compressResponseWriter) decide() error {
+	w.decided = true
+
+	h := w.Header()
+	if _, ok := h["Content-Type"]; !ok && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if len(w.buf) >= w.minSize && h.Get("Content-Encoding") == "" && w.compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		w.cw = w.pool.Get().(compressWriter)
+		w.cw.Reset(w.ResponseWriter)
+	}
+
+	if w.code != 0 {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.cw != nil {
+		_, err = w.cw.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (w *compressResponseWriter) compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return w.allowed[mt]
+}
+
+// Flush sends any buffered data to the client, compressing it if the
+// response was large enough.
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.cw != nil {
+		w.cw.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close writes out the remaining response and releases the compress writer.
+func (w *compressResponseWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.cw == nil {
+		return nil
+	}
+
+	err := w.cw.Close()
+	w.pool.Put(w.cw)
+	w.cw = nil
+	return err
+}
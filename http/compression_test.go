@@ -0,0 +1,135 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompressionMW(t *testing.T) {
+	large := strings.Repeat("a", 2*DefaultCompressionMinSize)
+
+	tests := []struct {
+		name           string
+		config         CompressionConfig
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantEncoding   string
+	}{
+		{
+			name:           "compresses large json",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "gzip",
+			contentType:    "application/json; charset=utf-8",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "compresses large csv",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "gzip, deflate",
+			contentType:    "text/csv; charset=utf-8",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "prefers brotli",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "gzip, deflate, br",
+			contentType:    "application/json",
+			body:           large,
+			wantEncoding:   "br",
+		},
+		{
+			name:           "honors q-values",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "br;q=0.5, gzip;q=0.8",
+			contentType:    "application/json",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "refused encoding",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "br;q=0, gzip",
+			contentType:    "application/json",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "skips small responses",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           "{}",
+		},
+		{
+			name:           "skips content types outside the allow-list",
+			config:         NewCompressionConfig(),
+			acceptEncoding: "gzip",
+			contentType:    "application/octet-stream",
+			body:           large,
+		},
+		{
+			name:        "skips clients that do not accept compression",
+			config:      NewCompressionConfig(),
+			contentType: "application/json",
+			body:        large,
+		},
+		{
+			name:           "disabled",
+			config:         CompressionConfig{Disabled: true},
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           large,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CompressionMW(tt.config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+
+			r := httptest.NewRequest("GET", "http://any.url", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			if got := res.Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("content encoding = %q, want %q", got, tt.wantEncoding)
+			}
+
+			var body io.Reader = res.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				gr, err := gzip.NewReader(res.Body)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				body = gr
+			case "br":
+				body = brotli.NewReader(res.Body)
+			}
+			b, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tt.body {
+				t.Errorf("body = %q, want %q", b, tt.body)
+			}
+		})
+	}
+}
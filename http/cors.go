@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCORSAllowedOrigins are the origins allowed to call the API from a
+// browser when no explicit list is configured.
+var DefaultCORSAllowedOrigins = []string{"*"}
+
+// DefaultCORSAllowedMethods are the methods browsers may use in cross-origin requests.
+var DefaultCORSAllowedMethods = []string{"POST", "GET", "OPTIONS", "PUT", "PATCH", "DELETE"}
+
+// DefaultCORSAllowedHeaders are the request headers browsers may send in
+// cross-origin requests when no explicit list is configured.
+var DefaultCORSAllowedHeaders = []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", IdempotencyKeyHeader}
+
+// CORSConfig configures the cross-origin resource sharing headers of the API.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the API from a browser,
+	// e.g. https://app.example.com. An origin may contain a single *
+	// wildcard, e.g. https://*.example.com; * alone allows every origin.
+	// No origin is allowed if the list is empty.
+	AllowedOrigins []string
+	// AllowedHeaders are the request headers allowed in cross-origin
+	// requests; * allows every header.
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache the response to a preflight
+	// request; 0 leaves it to the browser.
+	MaxAge time.Duration
+	// AllowCredentials allows browsers to send cookies, such as the session
+	// of the UI, in cross-origin requests.
+	AllowCredentials bool
+}
+
+// NewCORSConfig returns a CORSConfig with default values.
+func NewCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: DefaultCORSAllowedOrigins,
+		AllowedHeaders: DefaultCORSAllowedHeaders,
+	}
+}
+
+// Valid returns an error if the config allows credentials from every origin,
+// which would let any website act on behalf of a signed in user.
+func (c CORSConfig) Valid() error {
+	if c.MaxAge < 0 {
+		return errors.New("cors max age must not be negative")
+	}
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, o := range c.AllowedOrigins {
+		if strings.TrimSpace(o) == "*" {
+			return errors.New("cors credentials must not be allowed for every origin")
+		}
+	}
+	return nil
+}
+
+// CORSMW returns a middleware that sets the cross-origin resource sharing
+// headers of responses to requests from allowed origins and answers
+// preflight requests.
+func CORSMW(c CORSConfig) Middleware {
+	origins := make([]string, 0, len(c.AllowedOrigins))
+	for _, o := range c.AllowedOrigins {
+		if o = strings.ToLower(strings.TrimSpace(o)); o != "" {
+			origins = append(origins, o)
+		}
+	}
+
+	headers := c.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSAllowedHeaders
+	}
+	var anyHeader bool
+	for _, h := range headers {
+		if strings.TrimSpace(h) == "*" {
+			anyHeader = true
+		}
+	}
+	allowHeaders := strings.Join(headers, ", ")
+	allowMethods := strings.Join(DefaultCORSAllowedMethods, ", ")
+	maxAge := strconv.Itoa(int(c.MaxAge / time.Second))
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			preflight := r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != ""
+			if !corsOriginAllowed(origins, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if c.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			if anyHeader {
+				w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+			} else {
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			}
+			if c.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// corsOriginAllowed returns true if the origin matches one of the lower
+// case origin patterns.
+func corsOriginAllowed(patterns []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, p := range patterns {
+		if p == "*" || p == origin {
+			return true
+		}
+		i := strings.IndexByte(p, '*')
+		if i < 0 {
+			continue
+		}
+		prefix, suffix := p[:i], p[i+1:]
+		if len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) &&
+			strings.HasSuffix(origin, suffix) &&
+			// the wildcard matches subdomains, not schemes, ports or paths.
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMW(t *testing.T) {
+	restricted := CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.dashboards.example.com"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	}
+
+	tests := []struct {
+		name        string
+		config      CORSConfig
+		method      string
+		origin      string
+		preflight   bool
+		wantOrigin  string
+		wantHeaders string
+		wantMaxAge  string
+		wantCreds   bool
+		wantNext    bool
+	}{
+		{
+			name:       "default allows every origin",
+			config:     NewCORSConfig(),
+			method:     "GET",
+			origin:     "https://other.example.org",
+			wantOrigin: "https://other.example.org",
+			wantNext:   true,
+		},
+		{
+			name:     "requests without origin",
+			config:   restricted,
+			method:   "GET",
+			wantNext: true,
+		},
+		{
+			name:       "allowed origin",
+			config:     restricted,
+			method:     "GET",
+			origin:     "https://app.example.com",
+			wantOrigin: "https://app.example.com",
+			wantCreds:  true,
+			wantNext:   true,
+		},
+		{
+			name:       "wildcard origin",
+			config:     restricted,
+			method:     "POST",
+			origin:     "https://team.dashboards.example.com",
+			wantOrigin: "https://team.dashboards.example.com",
+			wantCreds:  true,
+			wantNext:   true,
+		},
+		{
+			name:     "wildcard does not match ports",
+			config:   restricted,
+			method:   "GET",
+			origin:   "https://evil.com:443.dashboards.example.com",
+			wantNext: true,
+		},
+		{
+			name:     "origin not allowed",
+			config:   restricted,
+			method:   "GET",
+			origin:   "https://evil.example.org",
+			wantNext: true,
+		},
+		{
+			name:        "preflight",
+			config:      restricted,
+			method:      "OPTIONS",
+			origin:      "https://app.example.com",
+			preflight:   true,
+			wantOrigin:  "https://app.example.com",
+			wantHeaders: "Authorization, Content-Type",
+			wantMaxAge:  "600",
+			wantCreds:   true,
+		},
+		{
+			name:      "preflight from origin not allowed",
+			config:    restricted,
+			method:    "OPTIONS",
+			origin:    "https://evil.example.org",
+			preflight: true,
+		},
+		{
+			name:        "any header",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}},
+			method:      "OPTIONS",
+			origin:      "https://app.example.com",
+			preflight:   true,
+			wantOrigin:  "https://app.example.com",
+			wantHeaders: "X-Custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			r := httptest.NewRequest(tt.method, "/api/v2/buckets", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", "PATCH")
+				r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+			}
+			w := httptest.NewRecorder()
+			CORSMW(tt.config)(next).ServeHTTP(w, r)
+
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+			if got := w.Header().Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.wantMaxAge)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCreds {
+				t.Errorf("Access-Control-Allow-Credentials = %v, want %v", got, tt.wantCreds)
+			}
+			if tt.preflight && w.Code != http.StatusNoContent {
+				t.Errorf("preflight status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestCORSConfig_Valid(t *testing.T) {
+	c := NewCORSConfig()
+	if err := c.Valid(); err != nil {
+		t.Fatalf("expected default config to be valid, got %v", err)
+	}
+	c.AllowCredentials = true
+	if err := c.Valid(); err == nil {
+		t.Fatal("expected credentials for every origin to be invalid")
+	}
+	c.AllowedOrigins = []string{"https://app.example.com"}
+	if err := c.Valid(); err != nil {
+		t.Fatalf("expected credentials for an explicit origin to be valid, got %v", err)
+	}
+}
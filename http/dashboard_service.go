@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
@@ -24,6 +25,9 @@ type DashboardBackend struct {
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	TrashService                 platform.TrashService
+	ResourceACLService           platform.ResourceACLService
+	DashboardRenderService       platform.DashboardRenderService
 }
 
 // NewDashboardBackend creates a backend used by the dashboard handler.
@@ -37,12 +41,15 @@ func NewDashboardBackend(b *APIBackend) *DashboardBackend {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		TrashService:                 b.TrashService,
+		ResourceACLService:           b.ResourceACLService,
+		DashboardRenderService:       b.DashboardRenderService,
 	}
 }
 
 // DashboardHandler is the handler for the dashboard service
 type DashboardHandler struct {
-	*httprouter.Router
+	*Router
 
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
@@ -52,6 +59,9 @@ type DashboardHandler struct {
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	TrashService                 platform.TrashService
+	ResourceACLService           platform.ResourceACLService
+	DashboardRenderService       platform.DashboardRenderService
 }
 
 const (
@@ -62,11 +72,14 @@ const (
 	dashboardsIDCellsIDViewPath = "/api/v2/dashboards/:id/cells/:cellID/view"
 	dashboardsIDMembersPath     = "/api/v2/dashboards/:id/members"
 	dashboardsIDLogPath         = "/api/v2/dashboards/:id/logs"
+	dashboardsIDRestorePath     = "/api/v2/dashboards/:id/restore"
+	dashboardsIDRenderPath      = "/api/v2/dashboards/:id/render.png"
 	dashboardsIDMembersIDPath   = "/api/v2/dashboards/:id/members/:userID"
 	dashboardsIDOwnersPath      = "/api/v2/dashboards/:id/owners"
 	dashboardsIDOwnersIDPath    = "/api/v2/dashboards/:id/owners/:userID"
 	dashboardsIDLabelsPath      = "/api/v2/dashboards/:id/labels"
 	dashboardsIDLabelsIDPath    = "/api/v2/dashboards/:id/labels/:lid"
+	dashboardsIDPermissionsPath = "/api/v2/dashboards/:id/permissions"
 )
 
 // NewDashboardHandler returns a new instance of DashboardHandler.
@@ -81,6 +94,9 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		TrashService:                 b.TrashService,
+		ResourceACLService:           b.ResourceACLService,
+		DashboardRenderService:       b.DashboardRenderService,
 	}
 
 	h.HandlerFunc("POST", dashboardsPath, h.handlePostDashboard)
@@ -89,6 +105,8 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 	h.HandlerFunc("GET", dashboardsIDLogPath, h.handleGetDashboardLog)
 	h.HandlerFunc("DELETE", dashboardsIDPath, h.handleDeleteDashboard)
 	h.HandlerFunc("PATCH", dashboardsIDPath, h.handlePatchDashboard)
+	h.HandlerFunc("POST", dashboardsIDRestorePath, h.handleRestoreDashboard)
+	h.HandlerFunc("GET", dashboardsIDRenderPath, h.handleGetDashboardRender)
 
 	h.HandlerFunc("PUT", dashboardsIDCellsPath, h.handlePutDashboardCells)
 	h.HandlerFunc("POST", dashboardsIDCellsPath, h.handlePostDashboardCell)
@@ -132,6 +150,16 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 	h.HandlerFunc("POST", dashboardsIDLabelsPath, newPostLabelHandler(labelBackend))
 	h.HandlerFunc("DELETE", dashboardsIDLabelsIDPath, newDeleteLabelHandler(labelBackend))
 
+	aclBackend := &ResourceACLBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Logger:             b.Logger.With(zap.String("handler", "resource_acl")),
+		ResourceACLService: b.ResourceACLService,
+		ResourceType:       platform.DashboardsResourceType,
+	}
+	h.HandlerFunc("GET", dashboardsIDPermissionsPath, newGetResourceACLHandler(aclBackend))
+	h.HandlerFunc("PUT", dashboardsIDPermissionsPath, newPutResourceACLHandler(aclBackend))
+	h.HandlerFunc("DELETE", dashboardsIDPermissionsPath, newDeleteResourceACLHandler(aclBackend))
+
 	return h
 }
 
@@ -154,6 +182,7 @@ type dashboardResponse struct {
 	Cells          []dashboardCellResponse `json:"cells"`
 	Labels         []platform.Label        `json:"labels"`
 	Links          dashboardLinks          `json:"links"`
+	platform.DashboardSettings
 }
 
 func (d dashboardResponse) toPlatform() *platform.Dashboard {
@@ -171,6 +200,8 @@ func (d dashboardResponse) toPlatform() *platform.Dashboard {
 		Description:    d.Description,
 		Meta:           d.Meta,
 		Cells:          cells,
+
+		DashboardSettings: d.DashboardSettings,
 	}
 }
 
@@ -192,6 +223,8 @@ func newDashboardResponse(d *platform.Dashboard, labels []*platform.Label) dashb
 		Meta:           d.Meta,
 		Labels:         []platform.Label{},
 		Cells:          []dashboardCellResponse{},
+
+		DashboardSettings: d.DashboardSettings,
 	}
 
 	for _, l := range labels {
@@ -352,7 +385,7 @@ func (h *DashboardHandler) handleGetDashboards(w http.ResponseWriter, r *http.Re
 
 	h.Logger.Debug("dashboards retrieved", zap.String("dashboards", fmt.Sprint(dashboards)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newGetDashboardsResponse(ctx, dashboards, req.filter, req.opts, h.LabelService)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newGetDashboardsResponse(ctx, dashboards, req.filter, req.opts, h.LabelService), req.fields, "dashboards"); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -362,6 +395,7 @@ type getDashboardsRequest struct {
 	filter  platform.DashboardFilter
 	opts    platform.FindOptions
 	ownerID *platform.ID
+	fields  fieldSelection
 }
 
 func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashboardsRequest, error) {
@@ -398,6 +432,12 @@ func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashb
 		req.filter.Organization = &org
 	}
 
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+	req.fields = fields
+
 	return req, nil
 }
 
@@ -486,7 +526,7 @@ func (h *DashboardHandler) handleGetDashboard(w http.ResponseWriter, r *http.Req
 
 	h.Logger.Debug("dashboard retrieved", zap.String("dashboard", fmt.Sprint(dashboard)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newDashboardResponse(dashboard, labels)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newDashboardResponse(dashboard, labels), req.fields, ""); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -494,6 +534,7 @@ func (h *DashboardHandler) handleGetDashboard(w http.ResponseWriter, r *http.Req
 
 type getDashboardRequest struct {
 	DashboardID platform.ID
+	fields      fieldSelection
 }
 
 func decodeGetDashboardRequest(ctx context.Context, r *http.Request) (*getDashboardRequest, error) {
@@ -511,8 +552,14 @@ func decodeGetDashboardRequest(ctx context.Context, r *http.Request) (*getDashbo
 		return nil, err
 	}
 
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+
 	return &getDashboardRequest{
 		DashboardID: i,
+		fields:      fields,
 	}, nil
 }
 
@@ -570,6 +617,64 @@ func decodeGetDashboardLogRequest(ctx context.Context, r *http.Request) (*getDas
 	}, nil
 }
 
+// handleGetDashboardRender renders a dashboard to a PNG image.
+func (h *DashboardHandler) handleGetDashboardRender(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDashboardRenderRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	img, err := h.DashboardRenderService.RenderDashboard(ctx, req.DashboardID, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("dashboard rendered", zap.String("dashboardID", req.DashboardID.String()), zap.Int("bytes", len(img)))
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(img); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+type getDashboardRenderRequest struct {
+	DashboardID platform.ID
+	opts        platform.DashboardRenderOptions
+}
+
+func decodeGetDashboardRenderRequest(ctx context.Context, r *http.Request) (*getDashboardRenderRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	var i platform.ID
+	if err := i.DecodeFromString(params.ByName("id")); err != nil {
+		return nil, err
+	}
+	req := &getDashboardRenderRequest{DashboardID: i}
+
+	qp := r.URL.Query()
+	if start, stop := qp.Get("start"), qp.Get("stop"); start != "" || stop != "" {
+		req.opts.TimeRange = &platform.DashboardTimeRange{Start: start, Stop: stop}
+		if err := req.opts.TimeRange.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	if width := qp.Get("width"); width != "" {
+		n, err := strconv.Atoi(width)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "width must be an integer",
+			}
+		}
+		req.opts.Width = n
+	}
+
+	return req, nil
+}
+
 // handleDeleteDashboard removes a dashboard by ID.
 func (h *DashboardHandler) handleDeleteDashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -589,6 +694,35 @@ func (h *DashboardHandler) handleDeleteDashboard(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRestoreDashboard moves a dashboard out of the trash.
+func (h *DashboardHandler) handleRestoreDashboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestTrashedResourceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dashboard, err := h.TrashService.RestoreDashboard(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: dashboard.ID})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("dashboard restored", zap.String("dashboard", fmt.Sprint(dashboard)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDashboardResponse(dashboard, labels)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 type deleteDashboardRequest struct {
 	DashboardID platform.ID
 }
@@ -700,6 +834,7 @@ func (r *patchDashboardRequest) Valid() error {
 type postDashboardCellRequest struct {
 	dashboardID platform.ID
 	*platform.CellProperty
+	platform.DashboardSettings
 	UsingView *platform.ID `json:"usingView"`
 	Name      *string      `json:"name"`
 }
@@ -765,6 +900,7 @@ func (h *DashboardHandler) handlePostDashboardCell(w http.ResponseWriter, r *htt
 	if req.CellProperty != nil {
 		cell.CellProperty = *req.CellProperty
 	}
+	cell.DashboardSettings = req.DashboardSettings
 
 	if err := h.DashboardService.AddDashboardCell(ctx, req.dashboardID, cell, *opts); err != nil {
 		h.HandleHTTPError(ctx, err, w)
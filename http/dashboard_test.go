@@ -33,6 +33,8 @@ func NewMockDashboardBackend() *DashboardBackend {
 		UserResourceMappingService:   mock.NewUserResourceMappingService(),
 		LabelService:                 mock.NewLabelService(),
 		UserService:                  mock.NewUserService(),
+		ResourceACLService:           mock.NewResourceACLService(),
+		DashboardRenderService:       mock.NewDashboardRenderService(),
 	}
 }
 
@@ -991,7 +993,7 @@ func TestService_handlePostDashboardCell(t *testing.T) {
 			wants: wants{
 				statusCode:  http.StatusBadRequest,
 				contentType: "application/json; charset=utf-8",
-				body:        `{"code":"invalid","message":"bad request json body: EOF"}`,
+				body:        `{"code":"invalid","message":"bad request json body: EOF","error":"EOF"}`,
 			},
 		},
 		{
@@ -1219,12 +1221,13 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 		DashboardService platform.DashboardService
 	}
 	type args struct {
-		id     string
-		cellID string
-		x      int32
-		y      int32
-		w      int32
-		h      int32
+		id       string
+		cellID   string
+		x        int32
+		y        int32
+		w        int32
+		h        int32
+		timezone string
 	}
 	type wants struct {
 		statusCode  int
@@ -1276,6 +1279,47 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
     "view": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/view"
   }
 }
+`,
+			},
+		},
+		{
+			name: "override the timezone of the dashboard in a cell",
+			fields: fields{
+				&mock.DashboardService{
+					UpdateDashboardCellF: func(ctx context.Context, id, cellID platform.ID, upd platform.CellUpdate) (*platform.Cell, error) {
+						cell := &platform.Cell{
+							ID: platformtesting.MustIDBase16("020f755c3c082000"),
+						}
+
+						if err := upd.Apply(cell); err != nil {
+							return nil, err
+						}
+
+						return cell, nil
+					},
+				},
+			},
+			args: args{
+				id:       "020f755c3c082000",
+				cellID:   "020f755c3c082000",
+				timezone: "UTC",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "id": "020f755c3c082000",
+  "x": 0,
+  "y": 0,
+  "w": 0,
+  "h": 0,
+  "timezone": "UTC",
+  "links": {
+    "self": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000",
+    "view": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/view"
+  }
+}
 `,
 			},
 		},
@@ -1301,6 +1345,9 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 			if tt.args.h != 0 {
 				upd.H = &tt.args.h
 			}
+			if tt.args.timezone != "" {
+				upd.Timezone = &tt.args.timezone
+			}
 
 			b, err := json.Marshal(upd)
 			if err != nil {
@@ -1348,6 +1395,66 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 	}
 }
 
+func TestService_handleGetDashboardRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantOpts platform.DashboardRenderOptions
+	}{
+		{
+			name:     "defaults",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "time range and width",
+			query:    "?start=-6h&stop=-1h&width=800",
+			wantCode: http.StatusOK,
+			wantOpts: platform.DashboardRenderOptions{
+				TimeRange: &platform.DashboardTimeRange{Start: "-6h", Stop: "-1h"},
+				Width:     800,
+			},
+		},
+		{name: "stop without start", query: "?stop=-1h", wantCode: http.StatusBadRequest},
+		{name: "invalid width", query: "?width=wide", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts platform.DashboardRenderOptions
+			rs := mock.NewDashboardRenderService()
+			rs.RenderDashboardFn = func(ctx context.Context, id platform.ID, opts platform.DashboardRenderOptions) ([]byte, error) {
+				if id != platformtesting.MustIDBase16("020f755c3c082000") {
+					t.Fatalf("unexpected dashboard id %s", id)
+				}
+				gotOpts = opts
+				return []byte("\x89PNG"), nil
+			}
+
+			dashboardBackend := NewMockDashboardBackend()
+			dashboardBackend.HTTPErrorHandler = ErrorHandler(0)
+			dashboardBackend.DashboardRenderService = rs
+			h := NewDashboardHandler(dashboardBackend)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/dashboards/020f755c3c082000/render.png"+tt.query, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GET returned %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+				t.Errorf("GET returned content type %q, want image/png", ct)
+			}
+			if diff := cmp.Diff(tt.wantOpts, gotOpts); diff != "" {
+				t.Errorf("GET rendered with unexpected options -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func Test_dashboardCellIDPath(t *testing.T) {
 	t.Parallel()
 	dashboard, err := platform.IDFromString("deadbeefdeadbeef")
@@ -0,0 +1,435 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	dbrpPath = "/api/v2/dbrps"
+)
+
+// DBRPMappingBackend is all services and associated parameters required to construct
+// the DBRPMappingHandler.
+type DBRPMappingBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewDBRPMappingBackend returns a new instance of DBRPMappingBackend.
+func NewDBRPMappingBackend(b *APIBackend) *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "dbrp")),
+
+		DBRPMappingService:  b.DBRPMappingService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// DBRPMappingHandler is the handler for the mappings of 1.x databases and
+// retention policies to buckets.
+type DBRPMappingHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewDBRPMappingHandler creates a new DBRPMappingHandler.
+func NewDBRPMappingHandler(b *DBRPMappingBackend) *DBRPMappingHandler {
+	h := &DBRPMappingHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DBRPMappingService:  b.DBRPMappingService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", dbrpPath)
+
+	h.HandlerFunc("GET", dbrpPath, h.handleGetDBRPMappings)
+	h.HandlerFunc("POST", dbrpPath, h.handlePostDBRPMapping)
+	h.HandlerFunc("GET", entityPath, h.handleGetDBRPMapping)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchDBRPMapping)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteDBRPMapping)
+
+	return h
+}
+
+type dbrpLinks struct {
+	Self   string `json:"self"`
+	Bucket string `json:"bucket"`
+	Org    string `json:"org"`
+}
+
+type dbrpResponse struct {
+	*influxdb.DBRPMappingV2
+	Links dbrpLinks `json:"links"`
+}
+
+func newDBRPResponse(m *influxdb.DBRPMappingV2) dbrpResponse {
+	return dbrpResponse{
+		DBRPMappingV2: m,
+		Links: dbrpLinks{
+			Self:   dbrpIDPath(m.ID),
+			Bucket: fmt.Sprintf("/api/v2/buckets/%s", m.BucketID),
+			Org:    fmt.Sprintf("/api/v2/orgs/%s", m.OrganizationID),
+		},
+	}
+}
+
+type getDBRPsResponse struct {
+	DBRPs []dbrpResponse `json:"dbrps"`
+}
+
+func newGetDBRPsResponse(ms []*influxdb.DBRPMappingV2) getDBRPsResponse {
+	resp := getDBRPsResponse{
+		DBRPs: make([]dbrpResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		resp.DBRPs = append(resp.DBRPs, newDBRPResponse(m))
+	}
+	return resp
+}
+
+type getDBRPsRequest struct {
+	filter influxdb.DBRPMappingFilterV2
+	opts   influxdb.FindOptions
+}
+
+func decodeGetDBRPsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getDBRPsRequest, error) {
+	qp := r.URL.Query()
+	req := &getDBRPsRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	for _, p := range []struct {
+		name string
+		id   **influxdb.ID
+	}{
+		{"id", &req.filter.ID},
+		{"orgID", &req.filter.OrgID},
+		{"bucketID", &req.filter.BucketID},
+	} {
+		if v := qp.Get(p.name); v != "" {
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("invalid %s", p.name),
+					Err:  err,
+				}
+			}
+			*p.id = id
+		}
+	}
+
+	if org := qp.Get("org"); org != "" && req.filter.OrgID == nil {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if db := qp.Get("db"); db != "" {
+		req.filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		req.filter.RetentionPolicy = &rp
+	}
+	if v := qp.Get("default"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid default",
+				Err:  err,
+			}
+		}
+		req.filter.Default = &b
+	}
+
+	return req, nil
+}
+
+func (h *DBRPMappingHandler) handleGetDBRPMappings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDBRPsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.DBRPMappingService.FindDBRPMappings(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mappings retrieved", zap.String("dbrps", fmt.Sprint(ms)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetDBRPsResponse(ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestDBRPID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *DBRPMappingHandler) handleGetDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestDBRPID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m, err := h.DBRPMappingService.FindDBRPMappingByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping retrieved", zap.String("dbrp", fmt.Sprint(m)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *DBRPMappingHandler) handlePostDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	m := &influxdb.DBRPMappingV2{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.CreateDBRPMapping(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping created", zap.String("dbrp", fmt.Sprint(m)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDBRPResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *DBRPMappingHandler) handlePatchDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestDBRPID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.DBRPMappingUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	m, err := h.DBRPMappingService.UpdateDBRPMapping(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping updated", zap.String("dbrp", fmt.Sprint(m)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *DBRPMappingHandler) handleDeleteDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestDBRPID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.DeleteDBRPMapping(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dbrp mapping deleted", zap.String("dbrpID", fmt.Sprint(id)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func dbrpIDPath(id influxdb.ID) string {
+	return path.Join(dbrpPath, id.String())
+}
+
+// DBRPMappingService is a dbrp mapping service over HTTP to the influxdb server.
+type DBRPMappingService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.DBRPMappingServiceV2 = (*DBRPMappingService)(nil)
+
+// FindDBRPMappingByID returns a single dbrp mapping by ID.
+func (s *DBRPMappingService) FindDBRPMappingByID(ctx context.Context, id influxdb.ID) (*influxdb.DBRPMappingV2, error) {
+	var resp dbrpResponse
+	if err := s.do(ctx, "GET", dbrpIDPath(id), nil, nil, http.StatusOK, &resp); err != nil {
+		return nil, err
+	}
+	return resp.DBRPMappingV2, nil
+}
+
+// FindDBRPMappings returns the dbrp mappings that match filter and their count.
+func (s *DBRPMappingService) FindDBRPMappings(ctx context.Context, filter influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+	query := make(map[string]string)
+	if filter.ID != nil {
+		query["id"] = filter.ID.String()
+	}
+	if filter.OrgID != nil {
+		query["orgID"] = filter.OrgID.String()
+	}
+	if filter.BucketID != nil {
+		query["bucketID"] = filter.BucketID.String()
+	}
+	if filter.Database != nil {
+		query["db"] = *filter.Database
+	}
+	if filter.RetentionPolicy != nil {
+		query["rp"] = *filter.RetentionPolicy
+	}
+	if filter.Default != nil {
+		query["default"] = strconv.FormatBool(*filter.Default)
+	}
+
+	var resp getDBRPsResponse
+	if err := s.do(ctx, "GET", dbrpPath, query, nil, http.StatusOK, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	ms := make([]*influxdb.DBRPMappingV2, 0, len(resp.DBRPs))
+	for _, m := range resp.DBRPs {
+		ms = append(ms, m.DBRPMappingV2)
+	}
+	return ms, len(ms), nil
+}
+
+// CreateDBRPMapping creates a dbrp mapping and sets m.ID with the new identifier.
+func (s *DBRPMappingService) CreateDBRPMapping(ctx context.Context, m *influxdb.DBRPMappingV2) error {
+	var resp dbrpResponse
+	if err := s.do(ctx, "POST", dbrpPath, nil, m, http.StatusCreated, &resp); err != nil {
+		return err
+	}
+	*m = *resp.DBRPMappingV2
+	return nil
+}
+
+// UpdateDBRPMapping updates a dbrp mapping with the changeset.
+func (s *DBRPMappingService) UpdateDBRPMapping(ctx context.Context, id influxdb.ID, upd influxdb.DBRPMappingUpdate) (*influxdb.DBRPMappingV2, error) {
+	var resp dbrpResponse
+	if err := s.do(ctx, "PATCH", dbrpIDPath(id), nil, upd, http.StatusOK, &resp); err != nil {
+		return nil, err
+	}
+	return resp.DBRPMappingV2, nil
+}
+
+// DeleteDBRPMapping removes a dbrp mapping by ID.
+func (s *DBRPMappingService) DeleteDBRPMapping(ctx context.Context, id influxdb.ID) error {
+	return s.do(ctx, "DELETE", dbrpIDPath(id), nil, nil, http.StatusNoContent, nil)
+}
+
+// do sends a request with the JSON encoded body, checks the response has the
+// status code and decodes the response into v when v is not nil.
+func (s *DBRPMappingService) do(ctx context.Context, method, urlPath string, query map[string]string, body interface{}, code int, v interface{}) error {
+	u, err := NewURL(s.Addr, urlPath)
+	if err != nil {
+		return err
+	}
+	if len(query) > 0 {
+		qp := u.Query()
+		for k, v := range query {
+			qp.Set(k, v)
+		}
+		u.RawQuery = qp.Encode()
+	}
+
+	var b bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&b).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), &b)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckErrorStatus(code, resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func initDBRPMappingService(f influxdbtesting.DBRPMappingFieldsV2, t *testing.T) (influxdb.DBRPMappingServiceV2, func()) {
+	svc := kv.NewService(inmem.NewKVStore())
+	if f.IDGenerator != nil {
+		svc.IDGenerator = f.IDGenerator
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets")
+		}
+	}
+	for _, m := range f.DBRPMappings {
+		if err := svc.PutDBRPMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate dbrp mappings")
+		}
+	}
+
+	handler := NewDBRPMappingHandler(&DBRPMappingBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		DBRPMappingService:  svc,
+		OrganizationService: svc,
+	})
+	server := httptest.NewServer(handler)
+	client := DBRPMappingService{
+		Addr: server.URL,
+	}
+	return &client, server.Close
+}
+
+func TestDBRPMappingService(t *testing.T) {
+	influxdbtesting.DBRPMappingServiceV2(initDBRPMappingService, t)
+}
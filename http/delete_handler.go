@@ -8,8 +8,8 @@ import (
 	http "net/http"
 	"time"
 
-	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/predicate"
@@ -25,6 +25,7 @@ type DeleteBackend struct {
 	DeleteService       influxdb.DeleteService
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	MaintenanceService  influxdb.MaintenanceService
 }
 
 // NewDeleteBackend returns a new instance of DeleteBackend
@@ -36,19 +37,21 @@ func NewDeleteBackend(b *APIBackend) *DeleteBackend {
 		DeleteService:       b.DeleteService,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		MaintenanceService:  b.MaintenanceService,
 	}
 }
 
 // DeleteHandler receives a delete request with a predicate and sends it to storage.
 type DeleteHandler struct {
 	influxdb.HTTPErrorHandler
-	*httprouter.Router
+	*Router
 
 	Logger *zap.Logger
 
 	DeleteService       influxdb.DeleteService
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	MaintenanceService  influxdb.MaintenanceService
 }
 
 const (
@@ -65,6 +68,7 @@ func NewDeleteHandler(b *DeleteBackend) *DeleteHandler {
 		BucketService:       b.BucketService,
 		DeleteService:       b.DeleteService,
 		OrganizationService: b.OrganizationService,
+		MaintenanceService:  b.MaintenanceService,
 	}
 
 	h.HandlerFunc("POST", deletePath, h.handleDelete)
@@ -94,7 +98,7 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	p, err := influxdb.NewPermissionAtID(dr.Bucket.ID, influxdb.WriteAction, influxdb.BucketsResourceType, dr.Org.ID)
+	p, err := influxdb.NewPermissionAtID(dr.Bucket.ID, influxdb.DeleteAction, influxdb.BucketsResourceType, dr.Org.ID)
 	if err != nil {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EInternal,
@@ -105,7 +109,7 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.Allowed(*p) {
+	if !authorizer.Allowed(ctx, a, *p) {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EForbidden,
 			Op:   "http/handleDelete",
@@ -114,6 +118,11 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkMaintenance(ctx, h.MaintenanceService, dr.Org.ID, w); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	// send delete points request to storage
 	err = h.DeleteService.DeleteBucketRangePredicate(ctx,
 		dr.Org.ID,
@@ -64,7 +64,12 @@ func TestDelete(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `{
 					"code": "invalid",
-					"message": "invalid request; error parsing request json: invalid RFC3339Nano for field start, please format your time with RFC3339Nano format, example: 2009-01-02T23:00:00Z"
+					"message": "invalid request; error parsing request json: invalid RFC3339Nano for field start, please format your time with RFC3339Nano format, example: 2009-01-02T23:00:00Z",
+					"error": {
+						"code": "invalid",
+						"message": "invalid RFC3339Nano for field start, please format your time with RFC3339Nano format, example: 2009-01-02T23:00:00Z",
+						"op": "http/Delete"
+					}
 				  }`,
 			},
 		},
@@ -81,7 +86,12 @@ func TestDelete(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `{
 					"code": "invalid",
-					"message": "invalid request; error parsing request json: invalid RFC3339Nano for field stop, please format your time with RFC3339Nano format, example: 2009-01-01T23:00:00Z"
+					"message": "invalid request; error parsing request json: invalid RFC3339Nano for field stop, please format your time with RFC3339Nano format, example: 2009-01-01T23:00:00Z",
+					"error": {
+						"code": "invalid",
+						"message": "invalid RFC3339Nano for field stop, please format your time with RFC3339Nano format, example: 2009-01-01T23:00:00Z",
+						"op": "http/Delete"
+					}
 				  }`,
 			},
 		},
@@ -178,12 +188,13 @@ func TestDelete(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `{
 					"code": "forbidden",
-					"message": "insufficient permissions to delete"
+					"message": "insufficient permissions to delete",
+					"op": "http/handleDelete"
 				  }`,
 			},
 		},
 		{
-			name: "no predicate delete",
+			name: "write permission delete",
 			args: args{
 				queryParams: map[string][]string{
 					"org":    []string{"org1"},
@@ -205,6 +216,57 @@ func TestDelete(t *testing.T) {
 					},
 				},
 			},
+			fields: fields{
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+						return &influxdb.Bucket{
+							ID:   influxdb.ID(2),
+							Name: "bucket1",
+						}, nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+						return &influxdb.Organization{
+							ID:   influxdb.ID(1),
+							Name: "org1",
+						}, nil
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusForbidden,
+				contentType: "application/json; charset=utf-8",
+				body: `{
+					"code": "forbidden",
+					"message": "insufficient permissions to delete",
+					"op": "http/handleDelete"
+				  }`,
+			},
+		},
+		{
+			name: "no predicate delete",
+			args: args{
+				queryParams: map[string][]string{
+					"org":    []string{"org1"},
+					"bucket": []string{"buck1"},
+				},
+				body: []byte(`{"start":"2009-01-01T23:00:00Z","stop":"2019-11-10T01:00:00Z"}`),
+				authorizer: &influxdb.Authorization{
+					UserID: user1ID,
+					Status: influxdb.Active,
+					Permissions: []influxdb.Permission{
+						{
+							Action: influxdb.DeleteAction,
+							Resource: influxdb.Resource{
+								Type:  influxdb.BucketsResourceType,
+								ID:    influxtesting.IDPtr(influxdb.ID(2)),
+								OrgID: influxtesting.IDPtr(influxdb.ID(1)),
+							},
+						},
+					},
+				},
+			},
 			fields: fields{
 				DeleteService: mock.NewDeleteService(),
 				BucketService: &mock.BucketService{
@@ -246,7 +308,7 @@ func TestDelete(t *testing.T) {
 					Status: influxdb.Active,
 					Permissions: []influxdb.Permission{
 						{
-							Action: influxdb.WriteAction,
+							Action: influxdb.DeleteAction,
 							Resource: influxdb.Resource{
 								Type:  influxdb.BucketsResourceType,
 								ID:    influxtesting.IDPtr(influxdb.ID(2)),
@@ -279,7 +341,11 @@ func TestDelete(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 				body: `{
 					"code": "invalid",
-					"message": "invalid request; error parsing request json: the logical operator OR is not supported yet at position 25"
+					"message": "invalid request; error parsing request json: the logical operator OR is not supported yet at position 25",
+					"error": {
+						"code": "invalid",
+						"message": "the logical operator OR is not supported yet at position 25"
+					}
 				  }`,
 			},
 		},
@@ -300,7 +366,7 @@ func TestDelete(t *testing.T) {
 					Status: influxdb.Active,
 					Permissions: []influxdb.Permission{
 						{
-							Action: influxdb.WriteAction,
+							Action: influxdb.DeleteAction,
 							Resource: influxdb.Resource{
 								Type:  influxdb.BucketsResourceType,
 								ID:    influxtesting.IDPtr(influxdb.ID(2)),
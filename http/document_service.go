@@ -34,7 +34,7 @@ func NewDocumentBackend(b *APIBackend) *DocumentBackend {
 
 // DocumentHandler represents an HTTP API handler for documents.
 type DocumentHandler struct {
-	*httprouter.Router
+	*Router
 
 	Logger *zap.Logger
 	influxdb.HTTPErrorHandler
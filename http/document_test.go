@@ -786,7 +786,7 @@ func TestService_handlePostDocuments(t *testing.T) {
 			wants: wants{
 				statusCode:  http.StatusBadRequest,
 				contentType: "application/json; charset=utf-8",
-				body:        `{"code":"invalid","message": "document body error: EOF"}`,
+				body:        `{"code":"invalid","message": "document body error: EOF","error":"EOF"}`,
 			},
 		},
 		{
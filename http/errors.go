@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/i18n"
 	"github.com/pkg/errors"
 )
 
@@ -87,6 +88,7 @@ func CheckError(resp *http.Response) (err error) {
 			line, _ := buf.ReadString('\n')
 			return errors.Wrap(stderrors.New(strings.TrimSuffix(line, "\n")), parseErr.Error())
 		}
+		unflattenMessage(pe)
 		return pe
 	default:
 		line, _ := buf.ReadString('\n')
@@ -111,16 +113,41 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	if !ok {
 		httpCode = http.StatusBadRequest
 	}
+	// The messages are translated into the language negotiated by
+	// LocalizationMW, if any. The code is never translated.
+	if pe, ok := err.(*platform.Error); ok {
+		if l := i18n.FromContext(ctx); l != nil {
+			if localized, translated := localizeError(l, pe); translated {
+				err = localized
+				w.Header().Set("Content-Language", l.Language().String())
+			}
+		}
+	}
 	w.Header().Set(PlatformErrorCodeHeader, code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(httpCode)
+	// The message is the flattened message of the error chain, the op and
+	// the errors that caused the error are given by op and error. Internal
+	// errors do not give them, as their causes are details of the server
+	// such as file paths and messages of its storage.
 	var e struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string      `json:"code"`
+		Message string      `json:"message"`
+		Op      string      `json:"op,omitempty"`
+		Err     interface{} `json:"error,omitempty"`
 	}
-	e.Code = platform.ErrorCode(err)
+	e.Code = code
 	if err, ok := err.(*platform.Error); ok {
 		e.Message = err.Error()
+		if code != platform.EInternal {
+			e.Op = err.Op
+			cause := publicCause(err.Err)
+			if pe, ok := cause.(*platform.Error); ok {
+				e.Err = pe
+			} else if cause != nil {
+				e.Err = cause.Error()
+			}
+		}
 	} else {
 		e.Message = "An internal error has occurred"
 	}
@@ -128,6 +155,39 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	_, _ = w.Write(b)
 }
 
+// publicCause returns the chain of errors that caused an error as it is
+// given in responses. The chain ends before the first internal error.
+func publicCause(err error) error {
+	pe, ok := err.(*platform.Error)
+	if !ok {
+		return err
+	}
+	if pe.Code == platform.EInternal {
+		return nil
+	}
+	return &platform.Error{
+		Code: pe.Code,
+		Msg:  pe.Msg,
+		Op:   pe.Op,
+		Err:  publicCause(pe.Err),
+	}
+}
+
+// unflattenMessage removes the messages of the errors that caused the error
+// from its message, which HandleHTTPError flattens, so that they are not
+// repeated by its Error method.
+func unflattenMessage(e *platform.Error) {
+	if e.Err == nil {
+		return
+	}
+	cause := e.Err.Error()
+	if e.Msg == cause {
+		e.Msg = ""
+		return
+	}
+	e.Msg = strings.TrimSuffix(e.Msg, ": "+cause)
+}
+
 // UnauthorizedError encodes a error message and status code for unauthorized access.
 func UnauthorizedError(ctx context.Context, h platform.HTTPErrorHandler, w http.ResponseWriter) {
 	h.HandleHTTPError(ctx, &platform.Error{
@@ -155,6 +215,7 @@ var statusCodePlatformError = map[string]int{
 	platform.EUnavailable:         http.StatusServiceUnavailable,
 	platform.EForbidden:           http.StatusForbidden,
 	platform.ETooManyRequests:     http.StatusTooManyRequests,
+	platform.ERequestTooLarge:     http.StatusRequestEntityTooLarge,
 	platform.EUnauthorized:        http.StatusUnauthorized,
 	platform.EMethodNotAllowed:    http.StatusMethodNotAllowed,
 }
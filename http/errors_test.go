@@ -48,16 +48,80 @@ func TestEncodeErrorWithError(t *testing.T) {
 		t.Errorf("expected X-Platform-Error-Code: %s, got: %s", influxdb.EInternal, errHeader)
 	}
 
-	// The http handler will flatten the message and it will not
-	// have an error property, so reading the serialization results
-	// in a different error.
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Err     string `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	// The http handler flattens the message, the cause of an internal
+	// error is not given.
+	if want, got := "an error occurred: there's an error here, be aware", body.Message; want != got {
+		t.Errorf("unexpected message -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if body.Err != "" {
+		t.Errorf("expected no cause of an internal error, got %q", body.Err)
+	}
+
 	pe := http.CheckError(w.Result()).(*influxdb.Error)
 	if want, got := influxdb.EInternal, pe.Code; want != got {
 		t.Errorf("unexpected code -want/+got:\n\t- %q\n\t+ %q", want, got)
 	}
-	if want, got := "an error occurred: there's an error here, be aware", pe.Msg; want != got {
+	if want, got := err.Error(), pe.Msg; want != got {
 		t.Errorf("unexpected message -want/+got:\n\t- %q\n\t+ %q", want, got)
 	}
+	if want, got := err.Error(), pe.Error(); want != got {
+		t.Errorf("unexpected error -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
+
+func TestEncodeErrorCause(t *testing.T) {
+	err := &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Op:   "influxdb/write",
+		Msg:  "invalid points",
+		Err: &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  "line 3",
+			Err: &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "unable to open /var/lib/influxdb/engine/data",
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	http.ErrorHandler(0).HandleHTTPError(context.TODO(), err, w)
+
+	var body struct {
+		Op  string          `json:"op"`
+		Err *influxdb.Error `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "influxdb/write", body.Op; want != got {
+		t.Errorf("unexpected op -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	// The chain of causes ends before the internal error.
+	want := &influxdb.Error{Code: influxdb.EUnprocessableEntity, Msg: "line 3"}
+	if diff := cmp.Diff(want, body.Err); diff != "" {
+		t.Errorf("unexpected cause -want/+got:\n%s", diff)
+	}
+}
+
+func TestEncodeErrorNotPlatformError(t *testing.T) {
+	w := httptest.NewRecorder()
+	http.ErrorHandler(0).HandleHTTPError(context.TODO(), fmt.Errorf("secret detail"), w)
+
+	if w.Code != 500 {
+		t.Errorf("expected status code 500, got: %d", w.Code)
+	}
+	if want, got := `{"code":"internal error","message":"An internal error has occurred"}`, w.Body.String(); want != got {
+		t.Errorf("unexpected body -want/+got:\n\t- %s\n\t+ %s", want, got)
+	}
 }
 
 func TestCheckError(t *testing.T) {
@@ -81,6 +145,31 @@ func TestCheckError(t *testing.T) {
 				Code: influxdb.EInvalid,
 			},
 		},
+		{
+			name: "error chain",
+			write: func(w *httptest.ResponseRecorder) {
+				h := http.ErrorHandler(0)
+				err := &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Op:   "http/handleGetBucket",
+					Err: &influxdb.Error{
+						Code: influxdb.ENotFound,
+						Msg:  "bucket not found",
+						Op:   "kv/findBucketByID",
+					},
+				}
+				h.HandleHTTPError(context.Background(), err, w)
+			},
+			want: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   "http/handleGetBucket",
+				Err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "bucket not found",
+					Op:   "kv/findBucketByID",
+				},
+			},
+		},
 		{
 			name: "text error",
 			write: func(w *httptest.ResponseRecorder) {
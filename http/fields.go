@@ -0,0 +1,90 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// fieldSelection is the set of top level resource fields requested via the
+// fields query parameter. A nil selection returns the full resource.
+type fieldSelection map[string]bool
+
+// decodeFieldSelection returns the fields requested with the fields query
+// parameter. Fields may be comma separated or the parameter may be repeated,
+// e.g. ?fields=id,name&fields=labels.
+func decodeFieldSelection(r *http.Request) (fieldSelection, error) {
+	vs, ok := r.URL.Query()["fields"]
+	if !ok {
+		return nil, nil
+	}
+
+	fs := fieldSelection{}
+	for _, v := range vs {
+		for _, f := range strings.Split(v, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			fs[f] = true
+		}
+	}
+
+	if len(fs) == 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "fields must contain at least one field name",
+		}
+	}
+	return fs, nil
+}
+
+// trim removes every key from obj that was not selected.
+func (fs fieldSelection) trim(obj map[string]interface{}) {
+	for k := range obj {
+		if !fs[k] {
+			delete(obj, k)
+		}
+	}
+}
+
+// encodeSparseResponse encodes res like encodeResponse, trimming the resource
+// down to the selected fields. When collection is non-empty, res is a list
+// response and the selection is applied to each element of the array stored
+// under that key; the remaining top level keys (e.g. links) are left untouched.
+func encodeSparseResponse(ctx context.Context, w http.ResponseWriter, code int, res interface{}, fs fieldSelection, collection string) error {
+	if fs == nil {
+		return encodeResponse(ctx, w, code, res)
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	// Numbers are decoded as json.Number so that integers beyond the
+	// precision of a float64 are encoded as they were.
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return err
+	}
+
+	if collection == "" {
+		fs.trim(obj)
+		return encodeResponse(ctx, w, code, obj)
+	}
+
+	elems, _ := obj[collection].([]interface{})
+	for _, e := range elems {
+		if m, ok := e.(map[string]interface{}); ok {
+			fs.trim(m)
+		}
+	}
+	return encodeResponse(ctx, w, code, obj)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFields_decodeFieldSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    fieldSelection
+		wantErr bool
+	}{
+		{
+			name:  "no fields parameter",
+			query: "",
+			want:  nil,
+		},
+		{
+			name:  "comma separated fields",
+			query: "fields=id,name",
+			want:  fieldSelection{"id": true, "name": true},
+		},
+		{
+			name:  "repeated fields parameter",
+			query: "fields=id&fields=labels,%20name",
+			want:  fieldSelection{"id": true, "name": true, "labels": true},
+		},
+		{
+			name:    "empty fields parameter",
+			query:   "fields=,",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://any.url?"+tt.query, nil)
+			got, err := decodeFieldSelection(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeFieldSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("decodeFieldSelection() diff = %s", diff)
+			}
+		})
+	}
+}
+
+func TestFields_encodeSparseResponse(t *testing.T) {
+	type resource struct {
+		ID     string   `json:"id"`
+		Name   string   `json:"name"`
+		Labels []string `json:"labels"`
+		Body   string   `json:"body"`
+		Count  int64    `json:"count,omitempty"`
+	}
+	type list struct {
+		Links     map[string]string `json:"links"`
+		Resources []resource        `json:"resources"`
+	}
+
+	tests := []struct {
+		name       string
+		res        interface{}
+		fields     fieldSelection
+		collection string
+		want       string
+	}{
+		{
+			name:   "full resource without selection",
+			res:    resource{ID: "1", Name: "a", Labels: []string{"l"}, Body: "b"},
+			fields: nil,
+			want:   `{"id":"1","name":"a","labels":["l"],"body":"b"}` + "\n",
+		},
+		{
+			name:   "single resource",
+			res:    resource{ID: "1", Name: "a", Labels: []string{"l"}, Body: "b"},
+			fields: fieldSelection{"id": true, "labels": true},
+			want:   `{"id":"1","labels":["l"]}` + "\n",
+		},
+		{
+			name:   "integers keep their precision",
+			res:    resource{ID: "1", Name: "a", Count: 1<<53 + 1},
+			fields: fieldSelection{"id": true, "count": true},
+			want:   `{"count":9007199254740993,"id":"1"}` + "\n",
+		},
+		{
+			name: "list keeps top level keys",
+			res: list{
+				Links: map[string]string{"self": "/r"},
+				Resources: []resource{
+					{ID: "1", Name: "a", Body: "b"},
+					{ID: "2", Name: "c", Body: "d"},
+				},
+			},
+			fields:     fieldSelection{"name": true},
+			collection: "resources",
+			want:       `{"links":{"self":"/r"},"resources":[{"name":"a"},{"name":"c"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := encodeSparseResponse(context.Background(), w, 200, tt.res, tt.fields, tt.collection); err != nil {
+				t.Fatalf("encodeSparseResponse() error = %v", err)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("encodeSparseResponse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const forecastPath = "/api/v2/forecast"
+
+// ForecastBackend is all services and associated parameters required to
+// construct the ForecastHandler.
+type ForecastBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	ForecastService     influxdb.ForecastService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewForecastBackend returns a new instance of ForecastBackend.
+func NewForecastBackend(b *APIBackend) *ForecastBackend {
+	return &ForecastBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "forecast")),
+
+		ForecastService:     b.ForecastService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// ForecastHandler is the handler for the forecasts of query results.
+type ForecastHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	ForecastService     influxdb.ForecastService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewForecastHandler creates a new ForecastHandler.
+func NewForecastHandler(b *ForecastBackend) *ForecastHandler {
+	h := &ForecastHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		ForecastService:     b.ForecastService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", forecastPath, h.handlePostForecast)
+
+	return h
+}
+
+type forecastResponse struct {
+	Series []*influxdb.ForecastSeries `json:"series"`
+}
+
+// handlePostForecast is the HTTP handler for the POST /api/v2/forecast route.
+// The query of the request runs in the organization of the org or orgID
+// parameter.
+func (h *ForecastHandler) handlePostForecast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req influxdb.ForecastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpForecast,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	req.OrganizationID = org.ID
+
+	series, err := h.ForecastService.Forecast(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("forecast computed", zap.String("org_id", org.ID.String()), zap.Int("series", len(series)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, forecastResponse{Series: series}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
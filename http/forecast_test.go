@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestForecastHandler(t *testing.T) {
+	orgID := influxdb.ID(1)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := mock.NewForecastService()
+	svc.ForecastFn = func(ctx context.Context, req influxdb.ForecastRequest) ([]*influxdb.ForecastSeries, error) {
+		if req.OrganizationID != orgID || req.Query != `from(bucket: "b")` || req.Method != influxdb.ForecastARIMA || req.Points != 2 || req.Interval.Duration != time.Hour {
+			t.Fatalf("unexpected forecast request %+v", req)
+		}
+		if req.Confidence == 0 {
+			return nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "confidence must be between 0 and 1"}
+		}
+		return []*influxdb.ForecastSeries{{
+			Tags: map[string]string{"host": "a"},
+			Forecast: []influxdb.ForecastPoint{
+				{Time: now, Value: 10, Lower: 8, Upper: 12},
+				{Time: now.Add(time.Hour), Value: 11, Lower: 8, Upper: 14},
+			},
+		}}, nil
+	}
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		if filter.Name == nil || *filter.Name != "org" {
+			t.Fatalf("unexpected organization filter %+v", filter)
+		}
+		return &influxdb.Organization{ID: orgID, Name: "org"}, nil
+	}
+
+	h := NewForecastHandler(&ForecastBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		ForecastService:     svc,
+		OrganizationService: orgs,
+	})
+
+	w := httptest.NewRecorder()
+	body := `{"query": "from(bucket: \"b\")", "method": "arima", "points": 2, "interval": "1h", "confidence": 0.9}`
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/forecast?org=org", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST returned %d, want 200: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Series []influxdb.ForecastSeries `json:"series"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Series) != 1 || resp.Series[0].Tags["host"] != "a" || len(resp.Series[0].Forecast) != 2 {
+		t.Fatalf("unexpected forecast %s", w.Body)
+	}
+	if p := resp.Series[0].Forecast[1]; !p.Time.Equal(now.Add(time.Hour)) || p.Value != 11 || p.Lower != 8 || p.Upper != 14 {
+		t.Fatalf("unexpected forecast point %+v", p)
+	}
+
+	w = httptest.NewRecorder()
+	body = `{"query": "from(bucket: \"b\")", "method": "arima", "points": 2, "interval": "1h"}`
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/forecast?org=org", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("POST of an invalid forecast returned %d, want 400: %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/forecast?org=org", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("POST of invalid json returned %d, want 400: %s", w.Code, w.Body)
+	}
+}
@@ -44,6 +44,12 @@ type Handler struct {
 
 	requests   *prometheus.CounterVec
 	requestDur *prometheus.HistogramVec
+	routes     *routeMetrics
+
+	// RouteOrgLabel labels the per-route metrics with the organization of
+	// the request. This multiplies the number of series by the number of
+	// organizations, so it is disabled by default.
+	RouteOrgLabel bool
 
 	// Logger if set will log all HTTP requests as they are served
 	Logger *zap.Logger
@@ -92,6 +98,9 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	defer span.Finish()
 
+	ctx, id := withRequestIdentity(r.Context())
+	r = r.WithContext(ctx)
+
 	// TODO: better way to do this?
 	statusW := newStatusResponseWriter(w)
 	w = statusW
@@ -114,6 +123,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"status":     statusClass,
 			"user_agent": userAgent,
 		}).Observe(duration.Seconds())
+
+		var org string
+		if h.RouteOrgLabel {
+			if orgID := id.org(r); orgID.Valid() {
+				org = orgID.String()
+			}
+		}
+		h.routes.observe(h.name, r.Method, id.route(r), org, statusW.code(), statusW.responseBytes, duration)
 	}(time.Now())
 
 	switch {
@@ -139,10 +156,10 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, code int, res in
 
 // PrometheusCollectors satisifies prom.PrometheusCollector.
 func (h *Handler) PrometheusCollectors() []prometheus.Collector {
-	return []prometheus.Collector{
+	return append([]prometheus.Collector{
 		h.requests,
 		h.requestDur,
-	}
+	}, h.routes.collectors()...)
 }
 
 func (h *Handler) initMetrics() {
@@ -162,6 +179,8 @@ func (h *Handler) initMetrics() {
 		Name:      "request_duration_seconds",
 		Help:      "Time taken to respond to HTTP request",
 	}, []string{"handler", "method", "path", "status", "user_agent"})
+
+	h.routes = newRouteMetrics()
 }
 
 func logEncodingError(logger *zap.Logger, r *http.Request, err error) {
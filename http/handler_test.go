@@ -6,6 +6,7 @@ import (
 	_ "net/http/pprof"
 	"testing"
 
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/kit/prom/promtest"
 	"go.uber.org/zap"
@@ -83,3 +84,158 @@ func TestHandler_ServeHTTP(t *testing.T) {
 
 	}
 }
+
+func TestHandler_RouteMetrics(t *testing.T) {
+	router := NewRouter(ErrorHandler(0))
+	router.HandlerFunc(http.MethodGet, "/api/v2/buckets/:id", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/buckets/020f755c3c082000" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		setRequestAuthorizer(r.Context(), &platform.Authorization{ID: 3, OrgID: 1, UserID: 2})
+		w.Write([]byte("abc"))
+	})
+	router.HandlerFunc(http.MethodGet, "/api/v2/write", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	h := &Handler{
+		name:          "test",
+		Handler:       router,
+		Logger:        zap.NewNop(),
+		RouteOrgLabel: true,
+	}
+	h.initMetrics()
+	reg := prom.NewRegistry()
+	reg.MustRegister(h.PrometheusCollectors()...)
+
+	for _, p := range []string{
+		"/api/v2/buckets/020f755c3c082000",
+		"/api/v2/buckets/020f755c3c082001",
+		"/api/v2/write?orgID=020f755c3c082002",
+		"/api/v2/nope/020f755c3c082003",
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := promtest.MustFindMetric(t, mfs, "http_api_route_response_size_bytes", map[string]string{
+		"handler": "test",
+		"method":  "GET",
+		"route":   "/api/v2/buckets/:id",
+		"status":  "200",
+		"org":     platform.ID(1).String(),
+	})
+	if got := ok.GetHistogram().GetSampleSum(); got != 3 {
+		t.Errorf("expected response size sum to be 3, got %v", got)
+	}
+
+	unauthorized := promtest.MustFindMetric(t, mfs, "http_api_auth_failures_total", map[string]string{
+		"handler": "test",
+		"route":   "/api/v2/buckets/:id",
+		"status":  "401",
+	})
+	if got := unauthorized.GetCounter().GetValue(); got != 1 {
+		t.Errorf("expected 1 auth failure, got %v", got)
+	}
+
+	// the org of an unauthenticated request is not taken from its parameters.
+	limited := promtest.MustFindMetric(t, mfs, "http_api_rate_limited_total", map[string]string{
+		"handler": "test",
+		"route":   "/api/v2/write",
+		"org":     "",
+	})
+	if got := limited.GetCounter().GetValue(); got != 1 {
+		t.Errorf("expected 1 rate limited request, got %v", got)
+	}
+
+	notFound := promtest.MustFindMetric(t, mfs, "http_api_route_response_size_bytes", map[string]string{
+		"handler": "test",
+		"method":  "GET",
+		"route":   routeOther,
+		"status":  "404",
+		"org":     "",
+	})
+	if got := notFound.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("expected 1 unmatched request, got %v", got)
+	}
+}
+
+func TestRequestIdentity_Route(t *testing.T) {
+	for path, want := range map[string]string{
+		"/api/v2/orgs/020f755c3c082000/members": routeOther,
+		"/static/a1b2c3.js":                     routeOther,
+		MetricsPath:                             MetricsPath,
+		DebugPath + "/pprof/heap":               DebugPath,
+	} {
+		id := &requestIdentity{}
+		if got := id.route(httptest.NewRequest(http.MethodGet, path, nil)); got != want {
+			t.Errorf("route(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	id := &requestIdentity{Route: "/api/v2/orgs/:id/members"}
+	if got := id.route(httptest.NewRequest(http.MethodGet, "/api/v2/orgs/020f755c3c082000/members", nil)); got != id.Route {
+		t.Errorf("route() = %q, want %q", got, id.Route)
+	}
+}
+
+func TestRequestIdentity_Org(t *testing.T) {
+	orgID, otherOrgID := platform.ID(0x020f755c3c082000), platform.ID(0x020f755c3c082001)
+	session := &platform.Session{
+		ID:          1,
+		UserID:      2,
+		Permissions: platform.MemberPermissions(orgID),
+	}
+
+	for _, tt := range []struct {
+		name string
+		id   *requestIdentity
+		path string
+		want platform.ID
+	}{
+		{
+			name: "authorization",
+			id:   &requestIdentity{OrgID: otherOrgID},
+			path: "/api/v2/buckets?orgID=" + orgID.String(),
+			want: otherOrgID,
+		},
+		{
+			name: "session orgID parameter",
+			id:   &requestIdentity{session: session},
+			path: "/api/v2/buckets?orgID=" + orgID.String(),
+			want: orgID,
+		},
+		{
+			name: "session organization path",
+			id:   &requestIdentity{session: session},
+			path: "/api/v2/orgs/" + orgID.String() + "/members",
+			want: orgID,
+		},
+		{
+			name: "session without permissions in the organization",
+			id:   &requestIdentity{session: session},
+			path: "/api/v2/buckets?orgID=" + otherOrgID.String(),
+		},
+		{
+			name: "session without organization",
+			id:   &requestIdentity{session: session},
+			path: "/api/v2/me",
+		},
+		{
+			name: "unauthenticated",
+			id:   &requestIdentity{},
+			path: "/api/v2/buckets?orgID=" + orgID.String(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.org(httptest.NewRequest(http.MethodGet, tt.path, nil)); got != tt.want {
+				t.Errorf("org() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the key that
+	// identifies retries of the same request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses that are replayed for a
+	// retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyWindow is how long the response to a request with an
+	// idempotency key is replayed for retries.
+	DefaultIdempotencyWindow = 24 * time.Hour
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key.
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentResponses is the maximum number of responses kept by an
+	// IdempotencyCache, and maxIdempotentResponsesPerAuthorizer the maximum
+	// number kept for a single authorizer. The oldest responses are evicted
+	// first when a limit is reached.
+	maxIdempotentResponses              = 10000
+	maxIdempotentResponsesPerAuthorizer = 100
+)
+
+// DefaultIdempotentPaths are the routes whose POST requests honor idempotency keys.
+var DefaultIdempotentPaths = []string{
+	"/api/v2/buckets",
+	"/api/v2/tasks",
+	"/api/v2/authorizations",
+}
+
+// IdempotencyCache keeps the successful responses to requests with an
+// idempotency key in memory for a window, so that retries of a request are
+// answered with the response to the first request instead of creating
+// another resource. The number of responses is limited in total and by
+// authorizer, so that a single client cannot fill the memory.
+type IdempotencyCache struct {
+	window time.Duration
+	now    func() time.Time
+
+	maxEntries      int
+	maxOwnerEntries int
+
+	mu      sync.Mutex
+	entries map[string]*idempotentResponse
+	// order and owners list the entries from the oldest to the newest, in
+	// total and by authorizer.
+	order   *list.List
+	owners  map[string]*list.List
+	sweepAt time.Time
+}
+
+type idempotentResponse struct {
+	key      string
+	owner    string
+	bodyHash [sha256.Size]byte
+	expires  time.Time
+
+	elem      *list.Element
+	ownerElem *list.Element
+
+	// done is false while the first request is being served.
+	done   bool
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// NewIdempotencyCache returns an IdempotencyCache that replays responses for the window.
+func NewIdempotencyCache(window time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		window:          window,
+		now:             time.Now,
+		maxEntries:      maxIdempotentResponses,
+		maxOwnerEntries: maxIdempotentResponsesPerAuthorizer,
+		entries:         make(map[string]*idempotentResponse),
+		order:           list.New(),
+		owners:          make(map[string]*list.List),
+	}
+}
+
+// begin returns the response stored for the key of the owner, or nil if the
+// request is the first one with the key and should be served.
+func (c *IdempotencyCache) begin(owner, key string, bodyHash [sha256.Size]byte) (*idempotentResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+
+	if e, ok := c.entries[key]; ok && now.Before(e.expires) {
+		if e.bodyHash != bodyHash {
+			return nil, &influxdb.Error{
+				Code: influxdb.EUnprocessableEntity,
+				Msg:  "idempotency key was already used for a request with a different body",
+			}
+		}
+		if !e.done {
+			return nil, &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "a request with the same idempotency key is in progress",
+			}
+		}
+		return e, nil
+	}
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	ownerEntries, ok := c.owners[owner]
+	if !ok {
+		ownerEntries = list.New()
+		c.owners[owner] = ownerEntries
+	}
+	for ownerEntries.Len() >= c.maxOwnerEntries {
+		c.remove(ownerEntries.Front().Value.(*idempotentResponse))
+	}
+	for c.order.Len() >= c.maxEntries {
+		c.remove(c.order.Front().Value.(*idempotentResponse))
+	}
+
+	e := &idempotentResponse{
+		key:      key,
+		owner:    owner,
+		bodyHash: bodyHash,
+		expires:  now.Add(c.window),
+	}
+	e.elem = c.order.PushBack(e)
+	e.ownerElem = c.owners[owner].PushBack(e)
+	c.entries[key] = e
+	return nil, nil
+}
+
+// remove removes the entry from the cache. It must be called with the mutex
+// held.
+func (c *IdempotencyCache) remove(e *idempotentResponse) {
+	delete(c.entries, e.key)
+	c.order.Remove(e.elem)
+	if ownerEntries, ok := c.owners[e.owner]; ok {
+		ownerEntries.Remove(e.ownerElem)
+		if ownerEntries.Len() == 0 {
+			delete(c.owners, e.owner)
+		}
+	}
+}
+
+// finish stores the response to the first request with the key. Responses
+// that are not successful are not stored, so that a retry is served again.
+func (c *IdempotencyCache) finish(key string, rec *idempotencyRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if rec.code < 200 || rec.code > 299 {
+		c.remove(e)
+		return
+	}
+	e.done = true
+	e.code = rec.code
+	e.header = make(http.Header, len(rec.Header()))
+	for k, v := range rec.Header() {
+		e.header[k] = append([]string(nil), v...)
+	}
+	e.body = rec.body.Bytes()
+}
+
+// sweep removes expired responses at most once per minute. It must be
+// called with the mutex held.
+func (c *IdempotencyCache) sweep(now time.Time) {
+	if now.Before(c.sweepAt) {
+		return
+	}
+	for _, e := range c.entries {
+		if !now.Before(e.expires) {
+			c.remove(e)
+		}
+	}
+	c.sweepAt = now.Add(time.Minute)
+}
+
+// idempotencyRecorder passes the response through while recording it.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// IdempotencyMW returns a middleware that replays the response to the first
+// POST request to one of the paths with an Idempotency-Key header for retries
+// with the same key by the same authorizer. It must be applied after
+// authentication.
+func IdempotencyMW(c *IdempotencyCache, paths ...string) Middleware {
+	idempotent := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		idempotent[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || r.Method != "POST" || !idempotent[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				ErrorHandler(0).HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "idempotency key must not be longer than 255 characters",
+				}, w)
+				return
+			}
+
+			a, err := icontext.GetAuthorizer(ctx)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				ErrorHandler(0).HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "unable to read request body",
+					Err:  err,
+				}, w)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			// keys are scoped to the authorizer, so that a key cannot be used to read another client's response.
+			owner := a.Identifier().String()
+			cacheKey := owner + " " + r.URL.Path + " " + key
+			stored, err := c.begin(owner, cacheKey, sha256.Sum256(body))
+			if err != nil {
+				ErrorHandler(0).HandleHTTPError(ctx, err, w)
+				return
+			}
+			if stored != nil {
+				for k, v := range stored.header {
+					w.Header()[k] = v
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(stored.code)
+				_, _ = w.Write(stored.body)
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w}
+			defer c.finish(cacheKey, rec)
+			next.ServeHTTP(rec, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+func TestIdempotencyMW(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewIdempotencyCache(time.Hour)
+	cache.now = func() time.Time { return now }
+
+	var created int
+	status := http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":"` + strconv.Itoa(created) + `"}`))
+	})
+	h := IdempotencyMW(cache, "/api/v2/buckets")(next)
+
+	do := func(path, key, body string, auth influxdb.Authorizer) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(IdempotencyKeyHeader, key)
+		}
+		if auth != nil {
+			r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	user := &influxdb.Authorization{ID: 1}
+	other := &influxdb.Authorization{ID: 2}
+
+	w := do("/api/v2/buckets", "abc", `{"name":"b"}`, user)
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":"1"}` {
+		t.Fatalf("unexpected first response %d %s", w.Code, w.Body.String())
+	}
+
+	w = do("/api/v2/buckets", "abc", `{"name":"b"}`, user)
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":"1"}` || w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected first response to be replayed, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected headers to be replayed, got %v", w.Header())
+	}
+
+	if w = do("/api/v2/buckets", "abc", `{"name":"c"}`, user); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected reuse of key with another body to be rejected, got %d", w.Code)
+	}
+
+	// keys are scoped to the authorizer, path and window.
+	if w = do("/api/v2/buckets", "abc", `{"name":"b"}`, other); w.Body.String() != `{"id":"2"}` {
+		t.Fatalf("expected key of another authorizer not to be replayed, got %s", w.Body.String())
+	}
+	if w = do("/api/v2/buckets", "", `{"name":"b"}`, user); w.Body.String() != `{"id":"3"}` {
+		t.Fatalf("expected request without key to be served, got %s", w.Body.String())
+	}
+	if w = do("/api/v2/tasks", "abc", `{"name":"b"}`, user); w.Body.String() != `{"id":"4"}` {
+		t.Fatalf("expected request to other path to be served, got %s", w.Body.String())
+	}
+	now = now.Add(2 * time.Hour)
+	if w = do("/api/v2/buckets", "abc", `{"name":"b"}`, user); w.Body.String() != `{"id":"5"}` {
+		t.Fatalf("expected expired key to be served again, got %s", w.Body.String())
+	}
+
+	// unsuccessful responses are not replayed.
+	status = http.StatusInternalServerError
+	do("/api/v2/buckets", "def", `{}`, user)
+	status = http.StatusCreated
+	if w = do("/api/v2/buckets", "def", `{}`, user); w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("expected retry of failed request to be served, got %d", w.Code)
+	}
+
+	if w = do("/api/v2/buckets", strings.Repeat("k", 256), `{}`, user); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected too long key to be rejected, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyMW_InProgress(t *testing.T) {
+	cache := NewIdempotencyCache(time.Hour)
+	auth := &influxdb.Authorization{ID: 1}
+
+	var inner *httptest.ResponseRecorder
+	var h http.Handler
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a retry arrives while the first request is being served.
+		retry := httptest.NewRequest("POST", "/api/v2/tasks", strings.NewReader(`{}`))
+		retry.Header.Set(IdempotencyKeyHeader, "abc")
+		retry = retry.WithContext(icontext.SetAuthorizer(retry.Context(), auth))
+		inner = httptest.NewRecorder()
+		h.ServeHTTP(inner, retry)
+		w.WriteHeader(http.StatusCreated)
+	})
+	h = IdempotencyMW(cache, "/api/v2/tasks")(next)
+
+	r := httptest.NewRequest("POST", "/api/v2/tasks", strings.NewReader(`{}`))
+	r.Header.Set(IdempotencyKeyHeader, "abc")
+	r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if inner.Code != http.StatusUnprocessableEntity && inner.Code != http.StatusConflict {
+		t.Fatalf("expected concurrent retry to be rejected, got %d", inner.Code)
+	}
+}
+
+func TestIdempotencyMW_Limits(t *testing.T) {
+	cache := NewIdempotencyCache(time.Hour)
+	cache.maxEntries = 3
+	cache.maxOwnerEntries = 2
+
+	var created int
+	h := IdempotencyMW(cache, "/api/v2/buckets")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	replayed := func(key string, auth influxdb.Authorizer) bool {
+		r := httptest.NewRequest("POST", "/api/v2/buckets", strings.NewReader(`{}`))
+		r.Header.Set(IdempotencyKeyHeader, key)
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Header().Get(IdempotentReplayedHeader) == "true"
+	}
+	user := &influxdb.Authorization{ID: 1}
+	other := &influxdb.Authorization{ID: 2}
+
+	// the oldest response of an authorizer is evicted by its third key.
+	for _, key := range []string{"a", "b", "c"} {
+		replayed(key, user)
+	}
+	if replayed("a", user) {
+		t.Error("expected the oldest response of the authorizer to be evicted")
+	}
+	if !replayed("c", user) {
+		t.Error("expected the newest response of the authorizer to be kept")
+	}
+	if got := len(cache.entries); got != 2 {
+		t.Fatalf("expected 2 responses for the authorizer, got %d", got)
+	}
+
+	// the oldest response of every authorizer is evicted by the total limit.
+	replayed("x", other)
+	replayed("y", other)
+	if got := len(cache.entries); got != 3 {
+		t.Fatalf("expected the cache to be limited to 3 responses, got %d", got)
+	}
+	if !replayed("y", other) {
+		t.Error("expected the newest response to be kept")
+	}
+}
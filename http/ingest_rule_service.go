@@ -0,0 +1,285 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const ingestRulesPath = "/api/v2/ingestRules"
+
+// IngestRuleBackend is all services and associated parameters required to construct
+// the IngestRuleHandler.
+type IngestRuleBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	IngestRuleService   influxdb.IngestRuleService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewIngestRuleBackend returns a new instance of IngestRuleBackend.
+func NewIngestRuleBackend(b *APIBackend) *IngestRuleBackend {
+	return &IngestRuleBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "ingest_rule")),
+
+		IngestRuleService:   b.IngestRuleService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// IngestRuleHandler is the handler for the ingest rules of buckets.
+type IngestRuleHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	IngestRuleService   influxdb.IngestRuleService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewIngestRuleHandler creates a new IngestRuleHandler.
+func NewIngestRuleHandler(b *IngestRuleBackend) *IngestRuleHandler {
+	h := &IngestRuleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		IngestRuleService:   b.IngestRuleService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", ingestRulesPath)
+
+	h.HandlerFunc("GET", ingestRulesPath, h.handleGetIngestRules)
+	h.HandlerFunc("POST", ingestRulesPath, h.handlePostIngestRule)
+	h.HandlerFunc("GET", entityPath, h.handleGetIngestRule)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchIngestRule)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteIngestRule)
+
+	return h
+}
+
+type ingestRuleLinks struct {
+	Self   string `json:"self"`
+	Bucket string `json:"bucket"`
+	Org    string `json:"org"`
+}
+
+type ingestRuleResponse struct {
+	*influxdb.IngestRule
+	Links ingestRuleLinks `json:"links"`
+}
+
+func newIngestRuleResponse(r *influxdb.IngestRule) ingestRuleResponse {
+	return ingestRuleResponse{
+		IngestRule: r,
+		Links: ingestRuleLinks{
+			Self:   path.Join(ingestRulesPath, r.ID.String()),
+			Bucket: fmt.Sprintf("/api/v2/buckets/%s", r.BucketID),
+			Org:    fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+	}
+}
+
+type getIngestRulesResponse struct {
+	Rules []ingestRuleResponse `json:"rules"`
+}
+
+func newGetIngestRulesResponse(rs []*influxdb.IngestRule) getIngestRulesResponse {
+	resp := getIngestRulesResponse{
+		Rules: make([]ingestRuleResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		resp.Rules = append(resp.Rules, newIngestRuleResponse(r))
+	}
+	return resp
+}
+
+type getIngestRulesRequest struct {
+	filter influxdb.IngestRuleFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetIngestRulesRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getIngestRulesRequest, error) {
+	qp := r.URL.Query()
+	req := &getIngestRulesRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	if v := qp.Get("orgID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if v := qp.Get("bucketID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid bucketID",
+				Err:  err,
+			}
+		}
+		req.filter.BucketID = id
+	}
+
+	return req, nil
+}
+
+func (h *IngestRuleHandler) handleGetIngestRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetIngestRulesRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.IngestRuleService.FindIngestRules(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("ingest rules retrieved", zap.Int("count", len(rs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetIngestRulesResponse(rs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestIngestRuleID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *IngestRuleHandler) handleGetIngestRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestIngestRuleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rule, err := h.IngestRuleService.FindIngestRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("ingest rule retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newIngestRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *IngestRuleHandler) handlePostIngestRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rule := &influxdb.IngestRule{}
+	if err := json.NewDecoder(r.Body).Decode(rule); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.IngestRuleService.CreateIngestRule(ctx, rule); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("ingest rule created", zap.Stringer("id", rule.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newIngestRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *IngestRuleHandler) handlePatchIngestRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestIngestRuleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.IngestRuleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	rule, err := h.IngestRuleService.UpdateIngestRule(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("ingest rule updated", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newIngestRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *IngestRuleHandler) handleDeleteIngestRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestIngestRuleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.IngestRuleService.DeleteIngestRule(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("ingest rule deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestIngestRuleHandler(t *testing.T) {
+	var (
+		created *influxdb.IngestRule
+		filter  influxdb.IngestRuleFilter
+		updated influxdb.IngestRuleUpdate
+		deleted influxdb.ID
+	)
+	svc := mock.NewIngestRuleService()
+	svc.CreateIngestRuleFn = func(_ context.Context, r *influxdb.IngestRule) error {
+		r.ID, r.OrgID, r.Position = 1, 2, 1
+		created = r
+		return nil
+	}
+	svc.FindIngestRuleByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+		return created, nil
+	}
+	svc.FindIngestRulesFn = func(_ context.Context, f influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+		filter = f
+		return []*influxdb.IngestRule{created}, 1, nil
+	}
+	svc.UpdateIngestRuleFn = func(_ context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+		updated = upd
+		upd.Apply(created)
+		return created, nil
+	}
+	svc.DeleteIngestRuleFn = func(_ context.Context, id influxdb.ID) error {
+		deleted = id
+		return nil
+	}
+
+	h := NewIngestRuleHandler(&IngestRuleBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		IngestRuleService:   svc,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/api/v2/ingestRules", `{
+		"bucketID": "0000000000000003",
+		"name": "hostname",
+		"type": "renameTag",
+		"measurement": "^cpu",
+		"tag": "hostname",
+		"newTag": "host"
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	want := &influxdb.IngestRule{
+		ID:          1,
+		OrgID:       2,
+		BucketID:    3,
+		Name:        "hostname",
+		Position:    1,
+		Type:        influxdb.IngestRuleRenameTag,
+		Measurement: "^cpu",
+		Tag:         "hostname",
+		NewTag:      "host",
+	}
+	if diff := cmp.Diff(created, want); diff != "" {
+		t.Errorf("unexpected created ingest rule -got/+want\n%s", diff)
+	}
+
+	w = do("GET", "/api/v2/ingestRules?bucketID=0000000000000003", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var resp struct {
+		Rules []struct {
+			influxdb.IngestRule
+			Links ingestRuleLinks `json:"links"`
+		} `json:"rules"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if filter.BucketID == nil || *filter.BucketID != 3 {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+	if len(resp.Rules) != 1 || resp.Rules[0].Links.Bucket != "/api/v2/buckets/0000000000000003" || resp.Rules[0].Links.Self != "/api/v2/ingestRules/0000000000000001" {
+		t.Errorf("unexpected ingest rules %+v", resp)
+	}
+
+	w = do("PATCH", "/api/v2/ingestRules/0000000000000001", `{"position": 2}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200", w.Code)
+	}
+	if *updated.Position != 2 || updated.Name != nil {
+		t.Errorf("unexpected update %+v", updated)
+	}
+
+	if w := do("DELETE", "/api/v2/ingestRules/0000000000000001", ""); w.Code != http.StatusNoContent || deleted != 1 {
+		t.Errorf("DELETE returned %d, deleted %s; want 204, 0000000000000001", w.Code, deleted)
+	}
+
+	if w := do("GET", "/api/v2/ingestRules?bucketID=invalid", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid filter returned %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const instancesPath = "/api/v2/instances"
+
+// InstanceBackend is all services and associated parameters required to construct
+// the InstanceHandler.
+type InstanceBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	InstanceService influxdb.InstanceService
+}
+
+// NewInstanceBackend returns a new instance of InstanceBackend.
+func NewInstanceBackend(b *APIBackend) *InstanceBackend {
+	return &InstanceBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "instance")),
+
+		InstanceService: b.InstanceService,
+	}
+}
+
+// InstanceHandler represents an HTTP API handler for the topology of the
+// influxd instances. It does not require authentication, as the instances
+// probe each other through it.
+type InstanceHandler struct {
+	*Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	InstanceService influxdb.InstanceService
+}
+
+// NewInstanceHandler returns a new instance of InstanceHandler.
+func NewInstanceHandler(b *InstanceBackend) *InstanceHandler {
+	h := &InstanceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		InstanceService: b.InstanceService,
+	}
+
+	h.HandlerFunc("GET", instancesPath, h.handleGetInstances)
+	return h
+}
+
+type instancesResponse struct {
+	Links     map[string]string    `json:"links"`
+	Instances []*influxdb.Instance `json:"instances"`
+}
+
+// handleGetInstances is the HTTP handler for the GET /api/v2/instances route.
+func (h *InstanceHandler) handleGetInstances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	instances, err := h.InstanceService.FindInstances(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := instancesResponse{
+		Links: map[string]string{
+			"self": instancesPath,
+		},
+		Instances: instances,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestInstanceHandler(t *testing.T) {
+	lastSeen := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	svc := mock.NewInstanceService()
+	svc.FindInstancesFn = func(context.Context) ([]*influxdb.Instance, error) {
+		return []*influxdb.Instance{
+			{
+				URL:      "http://influxd-0:8086",
+				Version:  "2.0.0",
+				Status:   influxdb.InstanceStatusUp,
+				Source:   influxdb.InstanceSourceSelf,
+				Self:     true,
+				LastSeen: &lastSeen,
+			},
+			{
+				URL:    "http://influxd-1:8086",
+				Status: influxdb.InstanceStatusUnknown,
+				Source: influxdb.InstanceSourceGossip,
+			},
+		}, nil
+	}
+
+	h := NewInstanceHandler(&InstanceBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		InstanceService:  svc,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/instances", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	want := `{
+  "links": {
+    "self": "/api/v2/instances"
+  },
+  "instances": [
+    {
+      "url": "http://influxd-0:8086",
+      "version": "2.0.0",
+      "status": "up",
+      "source": "self",
+      "self": true,
+      "lastSeen": "2019-10-01T00:00:00Z"
+    },
+    {
+      "url": "http://influxd-1:8086",
+      "status": "unknown",
+      "source": "gossip"
+    }
+  ]
+}`
+	if eq, diff, _ := jsonEqual(w.Body.String(), want); !eq {
+		t.Errorf("GET body diff: %s", diff)
+	}
+}
@@ -17,7 +17,7 @@ import (
 
 // LabelHandler represents an HTTP API handler for labels
 type LabelHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// LegacyAuthenticationHandler is a middleware for authenticating requests made
+// to the 1.x compatible endpoints. It accepts the credentials understood by
+// InfluxDB 1.x clients: a token in the Authorization header, HTTP basic auth,
+// or the u and p query parameters.
+type LegacyAuthenticationHandler struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AuthorizationService influxdb.AuthorizationService
+	UserService          influxdb.UserService
+	PasswordsService     influxdb.PasswordsService
+
+	// AuthorizationUsageTracker records the last use of the authorizations
+	// authenticating requests, if set.
+	AuthorizationUsageTracker *AuthorizationUsageTracker
+
+	Handler http.Handler
+}
+
+// NewLegacyAuthenticationHandler creates a legacy authentication handler.
+func NewLegacyAuthenticationHandler(h influxdb.HTTPErrorHandler) *LegacyAuthenticationHandler {
+	return &LegacyAuthenticationHandler{
+		Logger:           zap.NewNop(),
+		HTTPErrorHandler: h,
+		Handler:          http.DefaultServeMux,
+	}
+}
+
+func (h *LegacyAuthenticationHandler) unauthorized(ctx context.Context, w http.ResponseWriter, err error) {
+	h.Logger.Info("unauthorized", zap.Error(err))
+	UnauthorizedError(ctx, h, w)
+}
+
+// ServeHTTP extracts the 1.x credentials from the http request and places the resulting authorizer on the request context.
+func (h *LegacyAuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := h.extractAuthorizer(ctx, r)
+	if err != nil {
+		h.unauthorized(ctx, w, err)
+		return
+	}
+
+	if auth.GetUserID().Valid() {
+		if err = isUserActive(ctx, h.UserService, auth); err != nil {
+			InactiveUserError(ctx, h, w)
+			return
+		}
+	}
+
+	ctx = platcontext.SetAuthorizer(ctx, auth)
+	setRequestAuthorizer(ctx, auth)
+	trackAuthorizationUsage(h.AuthorizationUsageTracker, auth, r)
+
+	h.Handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (h *LegacyAuthenticationHandler) extractAuthorizer(ctx context.Context, r *http.Request) (influxdb.Authorizer, error) {
+	if t, err := GetToken(r); err == nil {
+		return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		qp := r.URL.Query()
+		username, password = qp.Get("u"), qp.Get("p")
+	}
+	if password == "" {
+		return nil, errors.New("credentials required")
+	}
+
+	if username != "" {
+		u, err := h.UserService.FindUser(ctx, influxdb.UserFilter{Name: &username})
+		if err == nil && h.PasswordsService.ComparePassword(ctx, u.ID, password) == nil {
+			return h.userAuthorizer(ctx, u)
+		}
+	}
+
+	// Most 1.x clients, Telegraf included, only have a place to configure a
+	// username and password, so the password is also accepted as a token.
+	return h.AuthorizationService.FindAuthorizationByToken(ctx, password)
+}
+
+// userAuthorizer returns an authorizer granting the user the permissions of
+// all of its active authorizations. As the permissions are not tied to the
+// authorization granting them, the points written by the user must satisfy
+// the tag constraints of all of its authorizations.
+func (h *LegacyAuthenticationHandler) userAuthorizer(ctx context.Context, u *influxdb.User) (influxdb.Authorizer, error) {
+	as, _, err := h.AuthorizationService.FindAuthorizations(ctx, influxdb.AuthorizationFilter{UserID: &u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	a := &legacyUserAuthorizer{userID: u.ID}
+	for _, auth := range as {
+		if auth.IsActive() {
+			a.permissions = append(a.permissions, auth.Permissions...)
+			a.tagConstraints = append(a.tagConstraints, auth.TagConstraints...)
+		}
+	}
+	return a, nil
+}
+
+// legacyUserAuthorizer is the authorizer for a user who authenticated with a
+// username and password on a 1.x compatible endpoint.
+type legacyUserAuthorizer struct {
+	userID         influxdb.ID
+	permissions    []influxdb.Permission
+	tagConstraints []influxdb.Tag
+}
+
+// Allowed returns true if the permission is granted by one of the user's authorizations.
+func (a *legacyUserAuthorizer) Allowed(p influxdb.Permission) bool {
+	return influxdb.PermissionAllowed(p, a.permissions)
+}
+
+// Identifier returns the user's ID and is used for auditing.
+func (a *legacyUserAuthorizer) Identifier() influxdb.ID { return a.userID }
+
+// GetUserID returns the user's ID.
+func (a *legacyUserAuthorizer) GetUserID() influxdb.ID { return a.userID }
+
+// Kind returns user and is used for auditing.
+func (a *legacyUserAuthorizer) Kind() string { return "user" }
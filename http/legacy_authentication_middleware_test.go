@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestLegacyAuthenticationHandler(t *testing.T) {
+	userID := influxtesting.MustIDBase16("000000000000000a")
+	orgID := influxtesting.MustIDBase16("043e0780ee2b1000")
+	perm := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	constraints := []influxdb.Tag{{Key: "host", Value: "a"}}
+
+	type args struct {
+		token     string
+		basicUser string
+		basicPass string
+		query     string
+	}
+	type wants struct {
+		code int
+		kind string
+	}
+
+	tests := []struct {
+		name   string
+		status influxdb.Status
+		args   args
+		wants  wants
+	}{
+		{
+			name: "token in authorization header",
+			args: args{token: "mytoken"},
+			wants: wants{
+				code: http.StatusOK,
+				kind: influxdb.AuthorizationKind,
+			},
+		},
+		{
+			name: "basic auth username and password",
+			args: args{basicUser: "user", basicPass: "password"},
+			wants: wants{
+				code: http.StatusOK,
+				kind: "user",
+			},
+		},
+		{
+			name: "query parameter username and password",
+			args: args{query: "u=user&p=password"},
+			wants: wants{
+				code: http.StatusOK,
+				kind: "user",
+			},
+		},
+		{
+			name: "token as the password",
+			args: args{query: "u=telegraf&p=mytoken"},
+			wants: wants{
+				code: http.StatusOK,
+				kind: influxdb.AuthorizationKind,
+			},
+		},
+		{
+			name: "wrong password",
+			args: args{basicUser: "user", basicPass: "wrong"},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "no credentials",
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:   "inactive user",
+			status: influxdb.Inactive,
+			args:   args{query: "u=user&p=password"},
+			wants: wants{
+				code: http.StatusForbidden,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == "" {
+				status = influxdb.Active
+			}
+
+			users := mock.NewUserService()
+			users.FindUserFn = func(ctx context.Context, f influxdb.UserFilter) (*influxdb.User, error) {
+				if *f.Name != "user" {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "user not found"}
+				}
+				return &influxdb.User{ID: userID, Name: "user", Status: status}, nil
+			}
+			users.FindUserByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.User, error) {
+				return &influxdb.User{ID: userID, Name: "user", Status: status}, nil
+			}
+
+			passwords := mock.NewPasswordsService()
+			passwords.ComparePasswordFn = func(ctx context.Context, id influxdb.ID, password string) error {
+				if id != userID || password != "password" {
+					return fmt.Errorf("your username or password is incorrect")
+				}
+				return nil
+			}
+
+			auth := &influxdb.Authorization{
+				ID:          influxtesting.MustIDBase16("0000000000000001"),
+				UserID:      userID,
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{perm},
+
+				TagConstraints: constraints,
+			}
+			authorizations := &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*influxdb.Authorization, error) {
+					if token != "mytoken" {
+						return nil, fmt.Errorf("authorization not found")
+					}
+					return auth, nil
+				},
+				FindAuthorizationsFn: func(ctx context.Context, f influxdb.AuthorizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Authorization, int, error) {
+					return []*influxdb.Authorization{auth}, 1, nil
+				},
+			}
+
+			var a influxdb.Authorizer
+			h := NewLegacyAuthenticationHandler(DefaultErrorHandler)
+			h.AuthorizationService = authorizations
+			h.UserService = users
+			h.PasswordsService = passwords
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				a, _ = pcontext.GetAuthorizer(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/write?"+tt.args.query, nil)
+			if tt.args.token != "" {
+				SetToken(tt.args.token, r)
+			}
+			if tt.args.basicUser != "" {
+				r.SetBasicAuth(tt.args.basicUser, tt.args.basicPass)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Fatalf("unexpected status code: got %d want %d", got, want)
+			}
+			if tt.wants.kind == "" {
+				return
+			}
+
+			if got, want := a.Kind(), tt.wants.kind; got != want {
+				t.Errorf("unexpected authorizer kind: got %s want %s", got, want)
+			}
+			if !a.Allowed(perm) {
+				t.Errorf("expected authorizer to allow %s", perm)
+			}
+			if got := tagConstraints(pcontext.SetAuthorizer(context.Background(), a)); !reflect.DeepEqual(got, constraints) {
+				t.Errorf("unexpected tag constraints: got %v want %v", got, constraints)
+			}
+		})
+	}
+}
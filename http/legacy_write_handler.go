@@ -0,0 +1,273 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/storage"
+	"go.uber.org/zap"
+)
+
+// LegacyWriteBackend is all services and associated parameters required to construct
+// the LegacyWriteHandler.
+type LegacyWriteBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger             *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+
+	PointsWriter       storage.PointsWriter
+	BucketService      influxdb.BucketService
+	DBRPMappingService influxdb.DBRPMappingServiceV2
+	MaintenanceService influxdb.MaintenanceService
+	WriteLimits        *WriteLimits
+}
+
+// NewLegacyWriteBackend returns a new instance of LegacyWriteBackend.
+func NewLegacyWriteBackend(b *APIBackend) *LegacyWriteBackend {
+	return &LegacyWriteBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Logger:             b.Logger.With(zap.String("handler", "legacy_write")),
+		WriteEventRecorder: b.WriteEventRecorder,
+
+		PointsWriter:       b.PointsWriter,
+		BucketService:      b.BucketService,
+		DBRPMappingService: b.DBRPMappingService,
+		MaintenanceService: b.MaintenanceService,
+		WriteLimits:        b.WriteLimits,
+	}
+}
+
+// LegacyWriteHandler receives line protocol on the 1.x /write endpoint and
+// writes it to the bucket mapped to the requested database and retention policy.
+type LegacyWriteHandler struct {
+	*Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	BucketService      influxdb.BucketService
+	DBRPMappingService influxdb.DBRPMappingServiceV2
+	MaintenanceService influxdb.MaintenanceService
+
+	PointsWriter storage.PointsWriter
+	WriteLimits  *WriteLimits
+
+	EventRecorder metric.EventRecorder
+}
+
+const (
+	legacyWritePath           = "/write"
+	errInvalidLegacyPrecision = "invalid precision; valid precision units are n, ns, u, us, µ, ms, s, m, and h"
+)
+
+// NewLegacyWriteHandler creates a new handler at /write to receive line protocol.
+func NewLegacyWriteHandler(b *LegacyWriteBackend) *LegacyWriteHandler {
+	h := &LegacyWriteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		PointsWriter:       b.PointsWriter,
+		BucketService:      b.BucketService,
+		DBRPMappingService: b.DBRPMappingService,
+		MaintenanceService: b.MaintenanceService,
+		WriteLimits:        b.WriteLimits,
+		EventRecorder:      b.WriteEventRecorder,
+	}
+
+	h.HandlerFunc("POST", legacyWritePath, h.handleWrite)
+	return h
+}
+
+func (h *LegacyWriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyWriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	var requestBytes int
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func(start time.Time) {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+			Duration:      time.Since(start),
+		})
+	}(time.Now())
+
+	in := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		var err error
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/handleLegacyWrite",
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			}, w)
+			return
+		}
+		defer in.Close()
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLegacyWriteRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	logger := h.Logger.With(zap.String("db", req.Database), zap.String("rp", req.RetentionPolicy))
+
+	m, err := h.findDBRPMapping(ctx, a, req)
+	if err != nil {
+		logger.Info("Failed to find dbrp mapping", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID = m.OrganizationID
+
+	if err := checkMaintenance(ctx, h.MaintenanceService, m.OrganizationID, w); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bucket, err := h.BucketService.FindBucketByID(ctx, m.BucketID)
+	if err != nil {
+		logger.Info("Failed to find mapped bucket", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	wctx := storage.WithFieldTypeConflictPolicy(ctx, bucket.FieldTypeConflictPolicy)
+	requestBytes, err = writePoints(wctx, h.PointsWriter, h.WriteLimits, in, m.OrganizationID, m.BucketID, req.Precision, logger)
+	if err != nil {
+		handleWriteError(ctx, h.HTTPErrorHandler, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findDBRPMapping returns the mapping for the requested database and retention
+// policy that the authorizer is allowed to write to. When no retention policy
+// is given the default mapping of the database is used.
+func (h *LegacyWriteHandler) findDBRPMapping(ctx context.Context, a influxdb.Authorizer, req *legacyWriteRequest) (*influxdb.DBRPMappingV2, error) {
+	filter := influxdb.DBRPMappingFilterV2{
+		Database: &req.Database,
+	}
+	if req.RetentionPolicy != "" {
+		filter.RetentionPolicy = &req.RetentionPolicy
+	} else {
+		isDefault := true
+		filter.Default = &isDefault
+	}
+	if auth, ok := a.(*influxdb.Authorization); ok {
+		filter.OrgID = &auth.OrgID
+	}
+
+	ms, _, err := h.DBRPMappingService.FindDBRPMappings(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*influxdb.DBRPMappingV2
+	for _, m := range ms {
+		p, err := influxdb.NewPermissionAtID(m.BucketID, influxdb.WriteAction, influxdb.BucketsResourceType, m.OrganizationID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleLegacyWrite",
+				Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+				Err:  err,
+			}
+		}
+		if authorizer.Allowed(ctx, a, *p) {
+			found = append(found, m)
+		}
+	}
+
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) > 1:
+		return nil, &influxdb.Error{
+			Code: influxdb.EConflict,
+			Op:   "http/handleLegacyWrite",
+			Msg:  "database and retention policy are mapped in more than one organization; use a token scoped to a single organization",
+		}
+	case len(ms) > 0:
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handleLegacyWrite",
+			Msg:  "insufficient permissions for write",
+		}
+	}
+
+	return nil, &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Op:   "http/handleLegacyWrite",
+		Msg:  fmt.Sprintf("database not found: %q", req.Database),
+	}
+}
+
+func decodeLegacyWriteRequest(ctx context.Context, r *http.Request) (*legacyWriteRequest, error) {
+	qp := r.URL.Query()
+
+	db := qp.Get("db")
+	if db == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeLegacyWriteRequest",
+			Msg:  "database is required",
+		}
+	}
+
+	var p string
+	switch precision := qp.Get("precision"); precision {
+	case "", "n", "ns":
+		p = "ns"
+	case "u", "us", "µ":
+		p = "us"
+	case "ms", "s", "m", "h":
+		p = precision
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeLegacyWriteRequest",
+			Msg:  errInvalidLegacyPrecision,
+		}
+	}
+
+	return &legacyWriteRequest{
+		Database:        db,
+		RetentionPolicy: qp.Get("rp"),
+		Precision:       p,
+	}, nil
+}
+
+type legacyWriteRequest struct {
+	Database        string
+	RetentionPolicy string
+	Precision       string
+}
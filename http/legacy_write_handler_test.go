@@ -0,0 +1,318 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	influxtesting "github.com/influxdata/influxdb/testing"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLegacyWriteHandler_handleWrite(t *testing.T) {
+	// state is the internal state of the dbrp mapping service
+	type state struct {
+		mappings []*influxdb.DBRPMappingV2 // mappings to return from the dbrp mapping service
+	}
+
+	// want is the expected output of the HTTP endpoint
+	type wants struct {
+		body   string
+		code   int
+		filter influxdb.DBRPMappingFilterV2
+		bucket string // bucket the point was written to, empty if nothing was written
+		time   int64  // timestamp of the written point
+	}
+
+	// request is sent to the HTTP endpoint
+	type request struct {
+		auth      influxdb.Authorizer
+		db        string
+		rp        string
+		precision string
+		body      string
+	}
+
+	orgID := influxtesting.MustIDBase16("043e0780ee2b1000")
+	otherOrgID := influxtesting.MustIDBase16("043e0780ee2b2000")
+	db, rp := "telegraf", "autogen"
+	isDefault := true
+
+	mapping := func(org, bucket string) *influxdb.DBRPMappingV2 {
+		return &influxdb.DBRPMappingV2{
+			ID:              influxtesting.MustIDBase16("0000000000000001"),
+			OrganizationID:  influxtesting.MustIDBase16(org),
+			BucketID:        influxtesting.MustIDBase16(bucket),
+			Database:        db,
+			RetentionPolicy: rp,
+			Default:         true,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		request request
+		state   state
+		wants   wants
+	}{
+		{
+			name: "writes to the default mapping of the database",
+			request: request{
+				db:   db,
+				body: "m1,t1=v1 f1=1 1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mappings: []*influxdb.DBRPMappingV2{mapping("043e0780ee2b1000", "04504b356e23b000")},
+			},
+			wants: wants{
+				code:   204,
+				filter: influxdb.DBRPMappingFilterV2{OrgID: &orgID, Database: &db, Default: &isDefault},
+				bucket: "04504b356e23b000",
+				time:   1,
+			},
+		},
+		{
+			name: "writes to the mapping of the retention policy with legacy precision",
+			request: request{
+				db:        db,
+				rp:        rp,
+				precision: "h",
+				body:      "m1,t1=v1 f1=1 1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mappings: []*influxdb.DBRPMappingV2{mapping("043e0780ee2b1000", "04504b356e23b000")},
+			},
+			wants: wants{
+				code:   204,
+				filter: influxdb.DBRPMappingFilterV2{OrgID: &orgID, Database: &db, RetentionPolicy: &rp},
+				bucket: "04504b356e23b000",
+				time:   3600000000000,
+			},
+		},
+		{
+			name: "database is required",
+			request: request{
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"database is required","op":"http/decodeLegacyWriteRequest"}`,
+			},
+		},
+		{
+			name: "invalid precision returns 400",
+			request: request{
+				db:        db,
+				precision: "d",
+				body:      "m1,t1=v1 f1=1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid precision; valid precision units are n, ns, u, us, µ, ms, s, m, and h","op":"http/decodeLegacyWriteRequest"}`,
+			},
+		},
+		{
+			name: "unmapped database returns 404",
+			request: request{
+				db:   db,
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code:   404,
+				body:   `{"code":"not found","message":"database not found: \"telegraf\"","op":"http/handleLegacyWrite"}`,
+				filter: influxdb.DBRPMappingFilterV2{OrgID: &orgID, Database: &db, Default: &isDefault},
+			},
+		},
+		{
+			name: "forbidden to write with insufficient permission",
+			request: request{
+				db:   db,
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "000000000000000a"),
+			},
+			state: state{
+				mappings: []*influxdb.DBRPMappingV2{mapping("043e0780ee2b1000", "04504b356e23b000")},
+			},
+			wants: wants{
+				code:   403,
+				body:   `{"code":"forbidden","message":"insufficient permissions for write","op":"http/handleLegacyWrite"}`,
+				filter: influxdb.DBRPMappingFilterV2{OrgID: &orgID, Database: &db, Default: &isDefault},
+			},
+		},
+		{
+			name: "forbidden to write with a username and password against the tag constraints of a token",
+			request: request{
+				db:   db,
+				body: "m1,host=b f1=1",
+				auth: &legacyUserAuthorizer{
+					userID: influxtesting.MustIDBase16("000000000000000b"),
+					permissions: []influxdb.Permission{
+						{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID}},
+					},
+					tagConstraints: []influxdb.Tag{{Key: "host", Value: "a"}},
+				},
+			},
+			state: state{
+				mappings: []*influxdb.DBRPMappingV2{mapping("043e0780ee2b1000", "04504b356e23b000")},
+			},
+			wants: wants{
+				code:   403,
+				body:   `{"code":"forbidden","message":"point of measurement \"m1\" does not have the tag host=a required by the token"}`,
+				filter: influxdb.DBRPMappingFilterV2{Database: &db, Default: &isDefault},
+			},
+		},
+		{
+			name: "database mapped in several writable organizations is a conflict",
+			request: request{
+				db:   db,
+				body: "m1,t1=v1 f1=1",
+				auth: &legacyUserAuthorizer{
+					userID: influxtesting.MustIDBase16("000000000000000b"),
+					permissions: []influxdb.Permission{
+						{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID}},
+						{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &otherOrgID}},
+					},
+				},
+			},
+			state: state{
+				mappings: []*influxdb.DBRPMappingV2{
+					mapping("043e0780ee2b1000", "04504b356e23b000"),
+					mapping("043e0780ee2b2000", "04504b356e23c000"),
+				},
+			},
+			wants: wants{
+				code:   422,
+				body:   `{"code":"conflict","message":"database and retention policy are mapped in more than one organization; use a token scoped to a single organization","op":"http/handleLegacyWrite"}`,
+				filter: influxdb.DBRPMappingFilterV2{Database: &db, Default: &isDefault},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter influxdb.DBRPMappingFilterV2
+			dbrps := mock.NewDBRPMappingServiceV2()
+			dbrps.FindDBRPMappingsFn = func(ctx context.Context, f influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+				filter = f
+				return tt.state.mappings, len(tt.state.mappings), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				return &influxdb.Bucket{ID: id, OrgID: orgID}, nil
+			}
+			pw := &mock.PointsWriter{}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				BucketService:      buckets,
+				DBRPMappingService: dbrps,
+				PointsWriter:       pw,
+				WriteEventRecorder: &metric.NopEventRecorder{},
+				WriteLimits:        &WriteLimits{},
+			}
+			writeHandler := NewLegacyWriteHandler(NewLegacyWriteBackend(b))
+			handler := httpmock.NewAuthMiddlewareHandler(writeHandler, tt.request.auth)
+
+			r := httptest.NewRequest(
+				"POST",
+				"http://localhost:9999/write",
+				strings.NewReader(tt.request.body),
+			)
+
+			params := r.URL.Query()
+			params.Set("db", tt.request.db)
+			params.Set("rp", tt.request.rp)
+			params.Set("precision", tt.request.precision)
+			r.URL.RawQuery = params.Encode()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if diff := cmp.Diff(tt.wants.filter, filter); diff != "" {
+				t.Errorf("unexpected dbrp mapping filter -want/+got:\n%s", diff)
+			}
+
+			if tt.wants.bucket == "" {
+				if len(pw.Points) != 0 {
+					t.Fatalf("unexpected points written: %v", pw.Points)
+				}
+				return
+			}
+			if len(pw.Points) != 1 {
+				t.Fatalf("unexpected number of points written: got %d want 1", len(pw.Points))
+			}
+			p := pw.Points[0]
+			if got, want := p.UnixNano(), tt.wants.time; got != want {
+				t.Errorf("unexpected point time: got %d want %d", got, want)
+			}
+			if org, bucket := tsdb.DecodeNameSlice(p.Name()); org != orgID || bucket != influxtesting.MustIDBase16(tt.wants.bucket) {
+				t.Errorf("unexpected point bucket: got %s/%s want %s/%s", org, bucket, orgID, tt.wants.bucket)
+			}
+		})
+	}
+}
+
+func TestLegacyWriteHandler_FieldTypeConflictPolicy(t *testing.T) {
+	bucket := testBucket("043e0780ee2b1000", "04504b356e23b000")
+	bucket.FieldTypeConflictPolicy = influxdb.FieldTypeConflictDrop
+
+	dbrps := mock.NewDBRPMappingServiceV2()
+	dbrps.FindDBRPMappingsFn = func(ctx context.Context, f influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+		return []*influxdb.DBRPMappingV2{{
+			ID:             influxtesting.MustIDBase16("0000000000000001"),
+			OrganizationID: bucket.OrgID,
+			BucketID:       bucket.ID,
+			Database:       "telegraf",
+			Default:        true,
+		}}, 1, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id != bucket.ID {
+			t.Errorf("unexpected bucket lookup: got %s want %s", id, bucket.ID)
+		}
+		return bucket, nil
+	}
+	pw := &policyPointsWriter{}
+
+	b := &APIBackend{
+		HTTPErrorHandler:   DefaultErrorHandler,
+		Logger:             zaptest.NewLogger(t),
+		BucketService:      buckets,
+		DBRPMappingService: dbrps,
+		PointsWriter:       pw,
+		WriteEventRecorder: &metric.NopEventRecorder{},
+		WriteLimits:        &WriteLimits{},
+	}
+	handler := httpmock.NewAuthMiddlewareHandler(NewLegacyWriteHandler(NewLegacyWriteBackend(b)), bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"))
+
+	r := httptest.NewRequest("POST", "http://localhost:9999/write?db=telegraf", strings.NewReader("m1,t1=v1 f1=1"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status code: got %d want %d: %s", w.Code, http.StatusNoContent, w.Body.String())
+	}
+	if pw.policy != influxdb.FieldTypeConflictDrop {
+		t.Errorf("unexpected field type conflict policy: got %q want %q", pw.policy, influxdb.FieldTypeConflictDrop)
+	}
+}
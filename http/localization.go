@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/i18n"
+)
+
+// LocalizationMW returns a middleware that negotiates the language of the
+// messages of error responses from the Accept-Language header of requests.
+// Messages are translated with the catalog by HandleHTTPError.
+func LocalizationMW(c *i18n.Catalog) Middleware {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+			accept := r.Header.Get("Accept-Language")
+			if accept == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := i18n.NewContext(r.Context(), c.Localizer(accept))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// localizeError returns a copy of the error chain with the messages
+// translated by the localizer, and whether any message was translated.
+func localizeError(l *i18n.Localizer, err *platform.Error) (*platform.Error, bool) {
+	localized := *err
+	msg, translated := l.TranslateOK(err.Msg)
+	localized.Msg = msg
+
+	switch cause := err.Err.(type) {
+	case nil:
+	case *platform.Error:
+		c, ok := localizeError(l, cause)
+		localized.Err = c
+		translated = translated || ok
+	default:
+		if msg, ok := l.TranslateOK(cause.Error()); ok {
+			localized.Err = errString(msg)
+			translated = true
+		}
+	}
+	return &localized, translated
+}
+
+// errString is an error with a translated message.
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -0,0 +1,121 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizationMW(t *testing.T) {
+	c := i18n.NewCatalog()
+	if err := c.Add(language.German, map[string]string{
+		"bucket %s not found":      "Bucket %s nicht gefunden",
+		"failed to find bucket":    "Bucket konnte nicht gefunden werden",
+		"connection refused":       "Verbindung abgelehnt",
+		"organization %s is empty": "Organisation %s ist leer",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		err             error
+		wantMessage     string
+		wantContentLang string
+	}{
+		{
+			name:           "translated",
+			acceptLanguage: "de-DE, en;q=0.5",
+			err: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "failed to find bucket",
+				Err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "bucket telegraf not found",
+				},
+			},
+			wantMessage:     "Bucket konnte nicht gefunden werden: Bucket telegraf nicht gefunden",
+			wantContentLang: "de",
+		},
+		{
+			name:           "translated cause",
+			acceptLanguage: "de",
+			err: &influxdb.Error{
+				Code: influxdb.EUnavailable,
+				Msg:  "failed to find bucket",
+				Err:  fmt.Errorf("connection refused"),
+			},
+			wantMessage:     "Bucket konnte nicht gefunden werden: Verbindung abgelehnt",
+			wantContentLang: "de",
+		},
+		{
+			name:           "without translation",
+			acceptLanguage: "de",
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid precision",
+			},
+			wantMessage: "invalid precision",
+		},
+		{
+			name:           "unsupported language",
+			acceptLanguage: "fr",
+			err: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "bucket telegraf not found",
+			},
+			wantMessage: "bucket telegraf not found",
+		},
+		{
+			name: "no accept language",
+			err: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "bucket telegraf not found",
+			},
+			wantMessage: "bucket telegraf not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.LocalizationMW(c)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				http.ErrorHandler(0).HandleHTTPError(r.Context(), tt.err, w)
+			}))
+
+			r := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			var body struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := body.Code, influxdb.ErrorCode(tt.err); got != want {
+				t.Errorf("unexpected code: got %q want %q", got, want)
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("unexpected message: got %q want %q", body.Message, tt.wantMessage)
+			}
+			if got := w.Header().Get("Content-Language"); got != tt.wantContentLang {
+				t.Errorf("unexpected Content-Language: got %q want %q", got, tt.wantContentLang)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Language" {
+				t.Errorf("unexpected Vary: %q", got)
+			}
+		})
+	}
+}
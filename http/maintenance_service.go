@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const maintenancePath = "/api/v2/maintenance"
+
+// MaintenanceBackend is all services and associated parameters required to construct
+// the MaintenanceHandler.
+type MaintenanceBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	MaintenanceService influxdb.MaintenanceService
+}
+
+// NewMaintenanceBackend returns a new instance of MaintenanceBackend.
+func NewMaintenanceBackend(b *APIBackend) *MaintenanceBackend {
+	return &MaintenanceBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "maintenance")),
+
+		MaintenanceService: b.MaintenanceService,
+	}
+}
+
+// MaintenanceHandler represents an HTTP API handler for the maintenance mode.
+type MaintenanceHandler struct {
+	*Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	MaintenanceService influxdb.MaintenanceService
+}
+
+// NewMaintenanceHandler returns a new instance of MaintenanceHandler.
+func NewMaintenanceHandler(b *MaintenanceBackend) *MaintenanceHandler {
+	h := &MaintenanceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		MaintenanceService: b.MaintenanceService,
+	}
+
+	h.HandlerFunc("GET", maintenancePath, h.handleGetMaintenances)
+	h.HandlerFunc("PUT", maintenancePath, h.handlePutMaintenance)
+	h.HandlerFunc("DELETE", maintenancePath, h.handleDeleteMaintenance)
+	return h
+}
+
+type getMaintenancesResponse struct {
+	Maintenances []*influxdb.Maintenance `json:"maintenances"`
+}
+
+// handleGetMaintenances is the HTTP handler for the GET /api/v2/maintenance route.
+func (h *MaintenanceHandler) handleGetMaintenances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ms, err := h.MaintenanceService.FindMaintenances(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if ms == nil {
+		ms = []*influxdb.Maintenance{}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, getMaintenancesResponse{Maintenances: ms}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutMaintenance is the HTTP handler for the PUT /api/v2/maintenance route.
+func (h *MaintenanceHandler) handlePutMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	m := &influxdb.Maintenance{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid maintenance",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.MaintenanceService.StartMaintenance(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Info("Maintenance started", zap.Any("maintenance", m))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, m); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteMaintenance is the HTTP handler for the DELETE /api/v2/maintenance route.
+func (h *MaintenanceHandler) handleDeleteMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var orgID *influxdb.ID
+	if id := r.URL.Query().Get("orgID"); id != "" {
+		i, err := influxdb.IDFromString(id)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}, w)
+			return
+		}
+		orgID = i
+	}
+
+	if err := h.MaintenanceService.StopMaintenance(ctx, orgID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Info("Maintenance stopped", zap.String("orgID", r.URL.Query().Get("orgID")))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkMaintenance returns an unavailable error if the organization or the
+// instance is in maintenance, and tells the client when to retry with the
+// Retry-After header. It allows everything if s is nil.
+func checkMaintenance(ctx context.Context, s influxdb.MaintenanceService, orgID influxdb.ID, w http.ResponseWriter) error {
+	if s == nil {
+		return nil
+	}
+
+	ms, err := s.FindMaintenances(ctx)
+	if err != nil {
+		return err
+	}
+	m := influxdb.MaintenanceOf(ms, orgID)
+	if m == nil {
+		return nil
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(m.RetryAfter))
+	msg := "organization is in maintenance mode"
+	if m.OrganizationID == nil {
+		msg = "instance is in maintenance mode"
+	}
+	if m.Message != "" {
+		msg += ": " + m.Message
+	}
+	return &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  msg,
+	}
+}
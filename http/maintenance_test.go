@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceHandler(t *testing.T) {
+	var (
+		started *influxdb.Maintenance
+		stopped *influxdb.ID
+	)
+	svc := mock.NewMaintenanceService()
+	svc.StartMaintenanceFn = func(_ context.Context, m *influxdb.Maintenance) error {
+		started = m
+		return nil
+	}
+	svc.StopMaintenanceFn = func(_ context.Context, orgID *influxdb.ID) error {
+		stopped = orgID
+		return nil
+	}
+
+	h := NewMaintenanceHandler(&MaintenanceBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		MaintenanceService: svc,
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("GET", "/api/v2/maintenance", "")
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"maintenances":[]}` {
+		t.Errorf("GET returned %d %s, want 200 and no maintenances", w.Code, w.Body)
+	}
+
+	w = do("PUT", "/api/v2/maintenance", `{"orgID": "0000000000000002", "message": "migrating", "retryAfter": 60}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT returned %d, want 200: %s", w.Code, w.Body)
+	}
+	if started == nil || *started.OrganizationID != 2 || started.Message != "migrating" || started.RetryAfter != 60 {
+		t.Errorf("unexpected started maintenance %+v", started)
+	}
+
+	if w := do("DELETE", "/api/v2/maintenance?orgID=0000000000000002", ""); w.Code != http.StatusNoContent || stopped == nil || *stopped != 2 {
+		t.Errorf("DELETE returned %d, stopped %v; want 204, 0000000000000002", w.Code, stopped)
+	}
+	if w := do("DELETE", "/api/v2/maintenance", ""); w.Code != http.StatusNoContent || stopped != nil {
+		t.Errorf("DELETE returned %d, stopped %v; want 204 and the instance", w.Code, stopped)
+	}
+	if w := do("DELETE", "/api/v2/maintenance?orgID=bad", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("DELETE of an invalid orgID returned %d, want 400", w.Code)
+	}
+}
+
+func TestCheckMaintenance(t *testing.T) {
+	orgID := influxdb.ID(2)
+	svc := mock.NewMaintenanceService()
+	svc.FindMaintenancesFn = func(context.Context) ([]*influxdb.Maintenance, error) {
+		return []*influxdb.Maintenance{
+			{OrganizationID: &orgID, RetryAfter: 60},
+		}, nil
+	}
+
+	w := httptest.NewRecorder()
+	err := checkMaintenance(context.Background(), svc, orgID, w)
+	if influxdb.ErrorCode(err) != influxdb.EUnavailable {
+		t.Fatalf("expected the org to be unavailable, got %v", err)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("expected Retry-After 60, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	if err := checkMaintenance(context.Background(), svc, influxdb.ID(3), w); err != nil {
+		t.Errorf("expected other orgs to be available, got %v", err)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("expected no Retry-After, got %q", got)
+	}
+
+	if err := checkMaintenance(context.Background(), nil, orgID, w); err != nil {
+		t.Errorf("expected no maintenance without a maintenance service, got %v", err)
+	}
+}
+
+func TestAssetHandler_Maintenance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(testIndexHTML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var ms []*influxdb.Maintenance
+	svc := mock.NewMaintenanceService()
+	svc.FindMaintenancesFn = func(context.Context) ([]*influxdb.Maintenance, error) {
+		return ms, nil
+	}
+
+	h := NewAssetHandler()
+	h.Path = dir
+	h.MaintenanceService = svc
+
+	serve := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/orgs/0000000000000001", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		return w.Body.String()
+	}
+
+	if body := serve(); strings.Contains(body, "influx-branding-banner") {
+		t.Errorf("expected no banner without maintenance, got %s", body)
+	}
+
+	orgID := influxdb.ID(1)
+	ms = []*influxdb.Maintenance{{OrganizationID: &orgID, Message: "org migration"}}
+	if body := serve(); strings.Contains(body, "influx-branding-banner") {
+		t.Errorf("expected no banner for the maintenance of an org, got %s", body)
+	}
+
+	ms = append(ms, &influxdb.Maintenance{Message: "upgrading storage"})
+	body := serve()
+	if !strings.Contains(body, "This instance is in maintenance mode: upgrading storage</div>") {
+		t.Errorf("expected the maintenance banner, got %s", body)
+	}
+	if !strings.Contains(body, "<title>InfluxDB 2.0</title>") {
+		t.Errorf("expected the title to be unchanged, got %s", body)
+	}
+
+	var branding Branding
+	start := strings.Index(body, "window.__INFLUX_BRANDING__=") + len("window.__INFLUX_BRANDING__=")
+	end := strings.Index(body[start:], ";</script>")
+	if err := json.Unmarshal([]byte(body[start:start+end]), &branding); err != nil {
+		t.Fatal(err)
+	}
+	if branding.BannerColor != maintenanceBannerColor {
+		t.Errorf("expected the maintenance banner color, got %+v", branding)
+	}
+}
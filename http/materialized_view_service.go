@@ -0,0 +1,375 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	materializedViewsPath = "/api/v2/materializedViews"
+)
+
+// MaterializedViewBackend is all services and associated parameters required to construct
+// the MaterializedViewHandler.
+type MaterializedViewBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	MaterializedViewService influxdb.MaterializedViewService
+	OrganizationService     influxdb.OrganizationService
+}
+
+// NewMaterializedViewBackend returns a new instance of MaterializedViewBackend.
+func NewMaterializedViewBackend(b *APIBackend) *MaterializedViewBackend {
+	return &MaterializedViewBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "materialized_view")),
+
+		MaterializedViewService: b.MaterializedViewService,
+		OrganizationService:     b.OrganizationService,
+	}
+}
+
+// MaterializedViewHandler is the handler for materialized views.
+type MaterializedViewHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	MaterializedViewService influxdb.MaterializedViewService
+	OrganizationService     influxdb.OrganizationService
+}
+
+// NewMaterializedViewHandler creates a new MaterializedViewHandler.
+func NewMaterializedViewHandler(b *MaterializedViewBackend) *MaterializedViewHandler {
+	h := &MaterializedViewHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		MaterializedViewService: b.MaterializedViewService,
+		OrganizationService:     b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", materializedViewsPath)
+
+	h.HandlerFunc("GET", materializedViewsPath, h.handleGetMaterializedViews)
+	h.HandlerFunc("POST", materializedViewsPath, h.handlePostMaterializedView)
+	h.HandlerFunc("GET", entityPath, h.handleGetMaterializedView)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchMaterializedView)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteMaterializedView)
+
+	return h
+}
+
+type materializedViewLinks struct {
+	Self              string `json:"self"`
+	Org               string `json:"org"`
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"`
+}
+
+// materializedView is the materialized view of the API, whose durations are
+// in seconds.
+type materializedView struct {
+	ID                    influxdb.ID                        `json:"id,omitempty"`
+	OrganizationID        influxdb.ID                        `json:"orgID"`
+	Name                  string                             `json:"name"`
+	Description           string                             `json:"description,omitempty"`
+	SourceBucketID        influxdb.ID                        `json:"sourceBucketID"`
+	DestinationBucketID   influxdb.ID                        `json:"destinationBucketID"`
+	Measurement           string                             `json:"measurement"`
+	Aggregate             influxdb.MaterializedViewAggregate `json:"aggregate"`
+	EverySeconds          int64                              `json:"everySeconds"`
+	LateDataWindowSeconds int64                              `json:"lateDataWindowSeconds"`
+	Status                influxdb.Status                    `json:"status,omitempty"`
+}
+
+func (v materializedView) toInfluxDB() *influxdb.MaterializedView {
+	return &influxdb.MaterializedView{
+		ID:                  v.ID,
+		OrganizationID:      v.OrganizationID,
+		Name:                v.Name,
+		Description:         v.Description,
+		SourceBucketID:      v.SourceBucketID,
+		DestinationBucketID: v.DestinationBucketID,
+		Measurement:         v.Measurement,
+		Aggregate:           v.Aggregate,
+		Every:               time.Duration(v.EverySeconds) * time.Second,
+		LateDataWindow:      time.Duration(v.LateDataWindowSeconds) * time.Second,
+		Status:              v.Status,
+	}
+}
+
+type materializedViewResponse struct {
+	materializedView
+	MaterializedFrom  *time.Time            `json:"materializedFrom,omitempty"`
+	MaterializedUntil *time.Time            `json:"materializedUntil,omitempty"`
+	LastRunError      string                `json:"lastRunError,omitempty"`
+	CreatedAt         time.Time             `json:"createdAt"`
+	UpdatedAt         time.Time             `json:"updatedAt"`
+	Links             materializedViewLinks `json:"links"`
+}
+
+func newMaterializedViewResponse(mv *influxdb.MaterializedView) materializedViewResponse {
+	resp := materializedViewResponse{
+		materializedView: materializedView{
+			ID:                    mv.ID,
+			OrganizationID:        mv.OrganizationID,
+			Name:                  mv.Name,
+			Description:           mv.Description,
+			SourceBucketID:        mv.SourceBucketID,
+			DestinationBucketID:   mv.DestinationBucketID,
+			Measurement:           mv.Measurement,
+			Aggregate:             mv.Aggregate,
+			EverySeconds:          int64(mv.Every.Round(time.Second) / time.Second),
+			LateDataWindowSeconds: int64(mv.LateDataWindow.Round(time.Second) / time.Second),
+			Status:                mv.Status,
+		},
+		LastRunError: mv.LastRunError,
+		CreatedAt:    mv.CreatedAt,
+		UpdatedAt:    mv.UpdatedAt,
+		Links: materializedViewLinks{
+			Self:              materializedViewIDPath(mv.ID),
+			Org:               fmt.Sprintf("/api/v2/orgs/%s", mv.OrganizationID),
+			SourceBucket:      fmt.Sprintf("/api/v2/buckets/%s", mv.SourceBucketID),
+			DestinationBucket: fmt.Sprintf("/api/v2/buckets/%s", mv.DestinationBucketID),
+		},
+	}
+	if mv.Materialized() {
+		resp.MaterializedFrom = &mv.MaterializedFrom
+		resp.MaterializedUntil = &mv.MaterializedUntil
+	}
+	return resp
+}
+
+type getMaterializedViewsResponse struct {
+	MaterializedViews []materializedViewResponse `json:"materializedViews"`
+}
+
+func newGetMaterializedViewsResponse(mvs []*influxdb.MaterializedView) getMaterializedViewsResponse {
+	resp := getMaterializedViewsResponse{
+		MaterializedViews: make([]materializedViewResponse, 0, len(mvs)),
+	}
+	for _, mv := range mvs {
+		resp.MaterializedViews = append(resp.MaterializedViews, newMaterializedViewResponse(mv))
+	}
+	return resp
+}
+
+type materializedViewUpdate struct {
+	Name                  *string          `json:"name,omitempty"`
+	Description           *string          `json:"description,omitempty"`
+	Status                *influxdb.Status `json:"status,omitempty"`
+	LateDataWindowSeconds *int64           `json:"lateDataWindowSeconds,omitempty"`
+}
+
+func (u materializedViewUpdate) toInfluxDB() influxdb.MaterializedViewUpdate {
+	upd := influxdb.MaterializedViewUpdate{
+		Name:        u.Name,
+		Description: u.Description,
+		Status:      u.Status,
+	}
+	if u.LateDataWindowSeconds != nil {
+		d := time.Duration(*u.LateDataWindowSeconds) * time.Second
+		upd.LateDataWindow = &d
+	}
+	return upd
+}
+
+type getMaterializedViewsRequest struct {
+	filter influxdb.MaterializedViewFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetMaterializedViewsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getMaterializedViewsRequest, error) {
+	qp := r.URL.Query()
+	req := &getMaterializedViewsRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	for _, p := range []struct {
+		name string
+		id   **influxdb.ID
+	}{
+		{"id", &req.filter.ID},
+		{"orgID", &req.filter.OrganizationID},
+		{"sourceBucketID", &req.filter.SourceBucketID},
+	} {
+		if v := qp.Get(p.name); v != "" {
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("invalid %s", p.name),
+					Err:  err,
+				}
+			}
+			*p.id = id
+		}
+	}
+
+	if org := qp.Get("org"); org != "" && req.filter.OrganizationID == nil {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	return req, nil
+}
+
+func (h *MaterializedViewHandler) handleGetMaterializedViews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetMaterializedViewsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mvs, _, err := h.MaterializedViewService.FindMaterializedViews(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("materialized views retrieved", zap.Int("count", len(mvs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetMaterializedViewsResponse(mvs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestMaterializedViewID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *MaterializedViewHandler) handleGetMaterializedView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestMaterializedViewID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mv, err := h.MaterializedViewService.FindMaterializedViewByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("materialized view retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMaterializedViewResponse(mv)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *MaterializedViewHandler) handlePostMaterializedView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var v materializedView
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	mv := v.toInfluxDB()
+	if err := h.MaterializedViewService.CreateMaterializedView(ctx, mv); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("materialized view created", zap.Stringer("id", mv.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newMaterializedViewResponse(mv)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *MaterializedViewHandler) handlePatchMaterializedView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestMaterializedViewID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd materializedViewUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	mv, err := h.MaterializedViewService.UpdateMaterializedView(ctx, id, upd.toInfluxDB())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("materialized view updated", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMaterializedViewResponse(mv)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *MaterializedViewHandler) handleDeleteMaterializedView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestMaterializedViewID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.MaterializedViewService.DeleteMaterializedView(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("materialized view deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func materializedViewIDPath(id influxdb.ID) string {
+	return path.Join(materializedViewsPath, id.String())
+}
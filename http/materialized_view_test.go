@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestMaterializedViewHandler(t *testing.T) {
+	var (
+		created *influxdb.MaterializedView
+		updated influxdb.MaterializedViewUpdate
+		deleted influxdb.ID
+	)
+	svc := mock.NewMaterializedViewService()
+	svc.CreateMaterializedViewFn = func(_ context.Context, mv *influxdb.MaterializedView) error {
+		mv.ID = 1
+		created = mv
+		return nil
+	}
+	svc.FindMaterializedViewByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.MaterializedView, error) {
+		return created, nil
+	}
+	svc.UpdateMaterializedViewFn = func(_ context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+		updated = upd
+		upd.Apply(created)
+		return created, nil
+	}
+	svc.DeleteMaterializedViewFn = func(_ context.Context, id influxdb.ID) error {
+		deleted = id
+		return nil
+	}
+
+	h := NewMaterializedViewHandler(&MaterializedViewBackend{
+		HTTPErrorHandler:        ErrorHandler(0),
+		Logger:                  zap.NewNop(),
+		MaterializedViewService: svc,
+		OrganizationService:     mock.NewOrganizationService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/api/v2/materializedViews", `{
+		"orgID": "0000000000000002",
+		"name": "cpu",
+		"sourceBucketID": "0000000000000003",
+		"destinationBucketID": "0000000000000004",
+		"measurement": "cpu",
+		"aggregate": "mean",
+		"everySeconds": 60,
+		"lateDataWindowSeconds": 30
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	want := &influxdb.MaterializedView{
+		ID:                  1,
+		OrganizationID:      2,
+		Name:                "cpu",
+		SourceBucketID:      3,
+		DestinationBucketID: 4,
+		Measurement:         "cpu",
+		Aggregate:           influxdb.MaterializedViewMean,
+		Every:               time.Minute,
+		LateDataWindow:      30 * time.Second,
+	}
+	if diff := cmp.Diff(created, want); diff != "" {
+		t.Errorf("unexpected created view -got/+want\n%s", diff)
+	}
+
+	w = do("GET", "/api/v2/materializedViews/0000000000000001", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var resp materializedViewResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.EverySeconds != 60 || resp.Links.Self != "/api/v2/materializedViews/0000000000000001" {
+		t.Errorf("unexpected view %+v", resp)
+	}
+	if resp.MaterializedFrom != nil || resp.MaterializedUntil != nil {
+		t.Errorf("unexpected materialized windows of a new view %v, %v", resp.MaterializedFrom, resp.MaterializedUntil)
+	}
+
+	w = do("PATCH", "/api/v2/materializedViews/0000000000000001", `{"status": "inactive", "lateDataWindowSeconds": 120}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200", w.Code)
+	}
+	if *updated.Status != influxdb.Inactive || *updated.LateDataWindow != 2*time.Minute {
+		t.Errorf("unexpected update %+v", updated)
+	}
+
+	if w := do("DELETE", "/api/v2/materializedViews/0000000000000001", ""); w.Code != http.StatusNoContent || deleted != 1 {
+		t.Errorf("DELETE returned %d, deleted %s; want 204, 0000000000000001", w.Code, deleted)
+	}
+
+	if w := do("GET", "/api/v2/materializedViews?sourceBucketID=invalid", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid filter returned %d, want 400", w.Code)
+	}
+}
@@ -2,6 +2,7 @@ package metric
 
 import (
 	"context"
+	"time"
 
 	"github.com/influxdata/influxdb"
 )
@@ -18,6 +19,7 @@ type Event struct {
 	RequestBytes  int
 	ResponseBytes int
 	Status        int
+	Duration      time.Duration
 }
 
 // NopEventRecorder never records events.
@@ -1,76 +1,15 @@
 package http
 
 import (
-	"bytes"
-	"errors"
 	"io"
 	"net/http"
 	"path"
 	"strings"
-	"time"
-
-	"go.uber.org/zap"
 )
 
 // Middleware constructor.
 type Middleware func(http.Handler) http.Handler
 
-// LoggingMW middleware for logging inflight http requests.
-func LoggingMW(logger *zap.Logger) Middleware {
-	return func(next http.Handler) http.Handler {
-		fn := func(w http.ResponseWriter, r *http.Request) {
-			srw := &statusResponseWriter{
-				ResponseWriter: w,
-			}
-
-			var buf bytes.Buffer
-			r.Body = &bodyEchoer{
-				rc:    r.Body,
-				teedR: io.TeeReader(r.Body, &buf),
-			}
-
-			defer func(start time.Time) {
-				errField := zap.Skip()
-				if errStr := w.Header().Get(PlatformErrorCodeHeader); errStr != "" {
-					errField = zap.Error(errors.New(errStr))
-				}
-
-				errReferenceField := zap.Skip()
-				if errReference := w.Header().Get(PlatformErrorCodeHeader); errReference != "" {
-					errReferenceField = zap.String("error_code", PlatformErrorCodeHeader)
-				}
-
-				fields := []zap.Field{
-					zap.String("method", r.Method),
-					zap.String("host", r.Host),
-					zap.String("path", r.URL.Path),
-					zap.String("query", r.URL.Query().Encode()),
-					zap.String("proto", r.Proto),
-					zap.Int("status_code", srw.code()),
-					zap.Int("response_size", srw.responseBytes),
-					zap.Int64("content_length", r.ContentLength),
-					zap.String("referrer", r.Referer()),
-					zap.String("remote", r.RemoteAddr),
-					zap.String("user_agent", r.UserAgent()),
-					zap.Duration("took", time.Since(start)),
-					errField,
-					errReferenceField,
-				}
-
-				invalidMethodFn, ok := mapURLPath(r.URL.Path)
-				if !ok || !invalidMethodFn(r.Method) {
-					fields = append(fields, zap.ByteString("body", buf.Bytes()))
-				}
-
-				logger.Debug("Request", fields...)
-			}(time.Now())
-
-			next.ServeHTTP(srw, r)
-		}
-		return http.HandlerFunc(fn)
-	}
-}
-
 type isValidMethodFn func(method string) bool
 
 func mapURLPath(rawPath string) (isValidMethodFn, bool) {
@@ -17,7 +17,7 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func TestLoggingMW(t *testing.T) {
+func TestAccessLogMW_Debug(t *testing.T) {
 	newDebugLogger := func(t *testing.T) (*zap.Logger, *bytes.Buffer) {
 		t.Helper()
 
@@ -120,10 +120,11 @@ func TestLoggingMW(t *testing.T) {
 			req := httptest.NewRequest(tt.method, reqURL.String(), teeReader(body, &trackerBuf))
 			rec := httptest.NewRecorder()
 
-			LoggingMW(log)(echoHandler).ServeHTTP(rec, req)
+			AccessLogMW(log, AccessLogConfig{})(echoHandler).ServeHTTP(rec, req)
 
 			expected := map[string]string{
 				"method":         tt.method,
+				"route":          routeOther,
 				"host":           "example.com",
 				"path":           reqURL.Path,
 				"query":          reqURL.RawQuery,
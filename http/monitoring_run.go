@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/influxdata/influxdb"
+)
+
+type monitoringRunResponse struct {
+	runResponse
+	Records []influxdb.MonitoringRecord `json:"records"`
+}
+
+type monitoringRunsResponse struct {
+	Links map[string]string        `json:"links"`
+	Runs  []*monitoringRunResponse `json:"runs"`
+}
+
+// newMonitoringRunsResponse returns the runs of a check or notification rule;
+// resourcePath is the path of the check or notification rule.
+func newMonitoringRunsResponse(mrs []*influxdb.MonitoringRun, resourcePath string) monitoringRunsResponse {
+	r := monitoringRunsResponse{
+		Links: map[string]string{
+			"self": resourcePath + "/runs",
+		},
+		Runs: make([]*monitoringRunResponse, 0, len(mrs)),
+	}
+
+	for _, mr := range mrs {
+		records := mr.Records
+		if records == nil {
+			records = []influxdb.MonitoringRecord{}
+		}
+		r.Runs = append(r.Runs, &monitoringRunResponse{
+			runResponse: newRunResponse(mr.Run),
+			Records:     records,
+		})
+	}
+
+	return r
+}
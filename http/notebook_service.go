@@ -0,0 +1,439 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	notebooksPath                        = "/api/v2/notebooks"
+	notebooksIDPath                      = "/api/v2/notebooks/:id"
+	notebooksIDVersionsPath              = "/api/v2/notebooks/:id/versions"
+	notebooksIDVersionsVersionPath       = "/api/v2/notebooks/:id/versions/:version"
+	notebooksIDVersionsVersionRestoreURL = "/api/v2/notebooks/:id/versions/:version/restore"
+	notebooksIDPermissionsPath           = "/api/v2/notebooks/:id/permissions"
+)
+
+// NotebookBackend is all services and associated parameters required to construct
+// the NotebookHandler.
+type NotebookBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotebookService     influxdb.NotebookService
+	OrganizationService influxdb.OrganizationService
+	ResourceACLService  influxdb.ResourceACLService
+}
+
+// NewNotebookBackend returns a new instance of NotebookBackend.
+func NewNotebookBackend(b *APIBackend) *NotebookBackend {
+	return &NotebookBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "notebook")),
+
+		NotebookService:     b.NotebookService,
+		OrganizationService: b.OrganizationService,
+		ResourceACLService:  b.ResourceACLService,
+	}
+}
+
+// NotebookHandler is the handler for notebooks.
+type NotebookHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotebookService     influxdb.NotebookService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewNotebookHandler creates a new NotebookHandler.
+func NewNotebookHandler(b *NotebookBackend) *NotebookHandler {
+	h := &NotebookHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		NotebookService:     b.NotebookService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", notebooksPath, h.handleGetNotebooks)
+	h.HandlerFunc("POST", notebooksPath, h.handlePostNotebook)
+	h.HandlerFunc("GET", notebooksIDPath, h.handleGetNotebook)
+	h.HandlerFunc("PATCH", notebooksIDPath, h.handlePatchNotebook)
+	h.HandlerFunc("DELETE", notebooksIDPath, h.handleDeleteNotebook)
+	h.HandlerFunc("GET", notebooksIDVersionsPath, h.handleGetNotebookVersions)
+	h.HandlerFunc("GET", notebooksIDVersionsVersionPath, h.handleGetNotebookVersion)
+	h.HandlerFunc("POST", notebooksIDVersionsVersionRestoreURL, h.handleRestoreNotebookVersion)
+
+	aclBackend := &ResourceACLBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Logger:             b.Logger.With(zap.String("handler", "resource_acl")),
+		ResourceACLService: b.ResourceACLService,
+		ResourceType:       influxdb.NotebooksResourceType,
+	}
+	h.HandlerFunc("GET", notebooksIDPermissionsPath, newGetResourceACLHandler(aclBackend))
+	h.HandlerFunc("PUT", notebooksIDPermissionsPath, newPutResourceACLHandler(aclBackend))
+	h.HandlerFunc("DELETE", notebooksIDPermissionsPath, newDeleteResourceACLHandler(aclBackend))
+
+	return h
+}
+
+type notebookLinks struct {
+	Self        string `json:"self"`
+	Org         string `json:"org"`
+	Versions    string `json:"versions"`
+	Permissions string `json:"permissions"`
+}
+
+type notebookResponse struct {
+	*influxdb.Notebook
+	Links notebookLinks `json:"links"`
+}
+
+func notebookIDPath(id influxdb.ID) string {
+	return fmt.Sprintf("%s/%s", notebooksPath, id)
+}
+
+func newNotebookResponse(n *influxdb.Notebook) notebookResponse {
+	if n.Cells == nil {
+		n.Cells = []influxdb.NotebookCell{}
+	}
+	return notebookResponse{
+		Notebook: n,
+		Links: notebookLinks{
+			Self:        notebookIDPath(n.ID),
+			Org:         fmt.Sprintf("/api/v2/orgs/%s", n.OrganizationID),
+			Versions:    notebookIDPath(n.ID) + "/versions",
+			Permissions: notebookIDPath(n.ID) + "/permissions",
+		},
+	}
+}
+
+type getNotebooksResponse struct {
+	Notebooks []notebookResponse `json:"notebooks"`
+}
+
+func newGetNotebooksResponse(ns []*influxdb.Notebook) getNotebooksResponse {
+	resp := getNotebooksResponse{
+		Notebooks: make([]notebookResponse, 0, len(ns)),
+	}
+	for _, n := range ns {
+		resp.Notebooks = append(resp.Notebooks, newNotebookResponse(n))
+	}
+	return resp
+}
+
+type notebookVersionLinks struct {
+	Self     string `json:"self"`
+	Notebook string `json:"notebook"`
+	Restore  string `json:"restore"`
+}
+
+type notebookVersionResponse struct {
+	*influxdb.NotebookVersion
+	Links notebookVersionLinks `json:"links"`
+}
+
+func newNotebookVersionResponse(v *influxdb.NotebookVersion) notebookVersionResponse {
+	if v.Cells == nil {
+		v.Cells = []influxdb.NotebookCell{}
+	}
+	self := fmt.Sprintf("%s/versions/%d", notebookIDPath(v.NotebookID), v.Version)
+	return notebookVersionResponse{
+		NotebookVersion: v,
+		Links: notebookVersionLinks{
+			Self:     self,
+			Notebook: notebookIDPath(v.NotebookID),
+			Restore:  self + "/restore",
+		},
+	}
+}
+
+type getNotebookVersionsResponse struct {
+	Versions []notebookVersionResponse `json:"versions"`
+}
+
+func newGetNotebookVersionsResponse(vs []*influxdb.NotebookVersion) getNotebookVersionsResponse {
+	resp := getNotebookVersionsResponse{
+		Versions: make([]notebookVersionResponse, 0, len(vs)),
+	}
+	for _, v := range vs {
+		resp.Versions = append(resp.Versions, newNotebookVersionResponse(v))
+	}
+	return resp
+}
+
+type getNotebooksRequest struct {
+	filter influxdb.NotebookFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetNotebooksRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getNotebooksRequest, error) {
+	qp := r.URL.Query()
+	req := &getNotebooksRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	if v := qp.Get("orgID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		req.filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	return req, nil
+}
+
+func (h *NotebookHandler) handleGetNotebooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetNotebooksRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ns, _, err := h.NotebookService.FindNotebooks(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebooks retrieved", zap.Int("count", len(ns)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetNotebooksResponse(ns)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestNotebookID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func requestNotebookVersion(ctx context.Context) (influxdb.ID, int, error) {
+	id, err := requestNotebookID(ctx)
+	if err != nil {
+		return influxdb.InvalidID(), 0, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	version, err := strconv.Atoi(params.ByName("version"))
+	if err != nil || version < 1 {
+		return influxdb.InvalidID(), 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url version must be a positive integer",
+		}
+	}
+
+	return id, version, nil
+}
+
+func (h *NotebookHandler) handleGetNotebook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestNotebookID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	n, err := h.NotebookService.FindNotebookByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotebookResponse(n)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotebookHandler) handlePostNotebook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	n := &influxdb.Notebook{}
+	if err := json.NewDecoder(r.Body).Decode(n); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.NotebookService.CreateNotebook(ctx, n); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook created", zap.Stringer("id", n.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newNotebookResponse(n)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotebookHandler) handlePatchNotebook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestNotebookID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.NotebookUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	n, err := h.NotebookService.UpdateNotebook(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook updated", zap.Stringer("id", id), zap.Int("version", n.Version))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotebookResponse(n)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotebookHandler) handleDeleteNotebook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestNotebookID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotebookService.DeleteNotebook(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotebookHandler) handleGetNotebookVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestNotebookID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	vs, err := h.NotebookService.FindNotebookVersions(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook versions retrieved", zap.Stringer("id", id), zap.Int("count", len(vs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetNotebookVersionsResponse(vs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotebookHandler) handleGetNotebookVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, version, err := requestNotebookVersion(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	v, err := h.NotebookService.FindNotebookVersion(ctx, id, version)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook version retrieved", zap.Stringer("id", id), zap.Int("version", version))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotebookVersionResponse(v)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleRestoreNotebookVersion updates a notebook to the contents of one of
+// its versions. The restore is a new version, so that it can be undone.
+func (h *NotebookHandler) handleRestoreNotebookVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, version, err := requestNotebookVersion(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	v, err := h.NotebookService.FindNotebookVersion(ctx, id, version)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	n, err := h.NotebookService.UpdateNotebook(ctx, id, influxdb.NotebookUpdate{
+		Name:        &v.Name,
+		Description: &v.Description,
+		Cells:       &v.Cells,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notebook version restored", zap.Stringer("id", id), zap.Int("version", version))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotebookResponse(n)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestNotebookHandler(t *testing.T) {
+	var (
+		created *influxdb.Notebook
+		updated influxdb.NotebookUpdate
+		deleted influxdb.ID
+	)
+	svc := mock.NewNotebookService()
+	svc.CreateNotebookFn = func(_ context.Context, n *influxdb.Notebook) error {
+		n.ID = 1
+		n.Version = 1
+		created = n
+		return nil
+	}
+	svc.FindNotebookByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Notebook, error) {
+		return created, nil
+	}
+	svc.UpdateNotebookFn = func(_ context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error) {
+		updated = upd
+		if err := upd.Apply(created); err != nil {
+			return nil, err
+		}
+		created.Version++
+		return created, nil
+	}
+	svc.DeleteNotebookFn = func(_ context.Context, id influxdb.ID) error {
+		deleted = id
+		return nil
+	}
+	svc.FindNotebookVersionFn = func(_ context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error) {
+		if version != 1 {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notebook version not found"}
+		}
+		return &influxdb.NotebookVersion{NotebookID: id, Version: 1, Name: "cpu"}, nil
+	}
+	svc.FindNotebookVersionsFn = func(_ context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error) {
+		return []*influxdb.NotebookVersion{{NotebookID: id, Version: 1, Name: "cpu"}}, nil
+	}
+
+	h := NewNotebookHandler(&NotebookBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		NotebookService:     svc,
+		OrganizationService: mock.NewOrganizationService(),
+		ResourceACLService:  mock.NewResourceACLService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/api/v2/notebooks", `{
+		"orgID": "0000000000000002",
+		"name": "cpu",
+		"cells": [
+			{"id": "0000000000000003", "type": "flux", "query": "from(bucket: \"b\")"},
+			{"type": "visualization", "sourceID": "0000000000000003", "properties": {"shape": "chronograf-v2", "type": "xy", "geom": "line"}}
+		]
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	if len(created.Cells) != 2 || created.Cells[0].Properties != nil {
+		t.Fatalf("unexpected created cells %+v", created.Cells)
+	}
+	if props, ok := created.Cells[1].Properties.(influxdb.XYViewProperties); !ok || props.Geom != "line" {
+		t.Errorf("expected the view properties of the visualization cell, got %#v", created.Cells[1].Properties)
+	}
+
+	w = do("GET", "/api/v2/notebooks/0000000000000001", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var resp struct {
+		Name    string        `json:"name"`
+		Version int           `json:"version"`
+		Links   notebookLinks `json:"links"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Name != "cpu" || resp.Version != 1 || resp.Links.Versions != "/api/v2/notebooks/0000000000000001/versions" {
+		t.Errorf("unexpected notebook %+v", resp)
+	}
+
+	w = do("PATCH", "/api/v2/notebooks/0000000000000001", `{"name": "cpu usage", "version": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200: %s", w.Code, w.Body)
+	}
+	if *updated.Name != "cpu usage" || *updated.Version != 1 || updated.Cells != nil {
+		t.Errorf("unexpected update %+v", updated)
+	}
+	if w := do("PATCH", "/api/v2/notebooks/0000000000000001", `{"name": "cpu", "version": 1}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("PATCH of a stale version returned %d, want 422", w.Code)
+	}
+
+	w = do("GET", "/api/v2/notebooks/0000000000000001/versions", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET versions returned %d, want 200", w.Code)
+	}
+	var versions getNotebookVersionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&versions); err != nil {
+		t.Fatal(err)
+	}
+	if len(versions.Versions) != 1 || versions.Versions[0].Links.Restore != "/api/v2/notebooks/0000000000000001/versions/1/restore" {
+		t.Errorf("unexpected versions %+v", versions)
+	}
+
+	w = do("POST", "/api/v2/notebooks/0000000000000001/versions/1/restore", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST restore returned %d, want 200: %s", w.Code, w.Body)
+	}
+	if *updated.Name != "cpu" || updated.Version != nil || created.Version != 3 {
+		t.Errorf("expected the restore to update the notebook to version 1, got update %+v", updated)
+	}
+	if w := do("GET", "/api/v2/notebooks/0000000000000001/versions/0", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET of an invalid version returned %d, want 400", w.Code)
+	}
+	if w := do("GET", "/api/v2/notebooks/0000000000000001/versions/2", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET of a missing version returned %d, want 404", w.Code)
+	}
+
+	if w := do("GET", "/api/v2/notebooks/0000000000000001/permissions", ""); w.Code != http.StatusOK {
+		t.Errorf("GET permissions returned %d, want 200", w.Code)
+	}
+
+	if w := do("DELETE", "/api/v2/notebooks/0000000000000001", ""); w.Code != http.StatusNoContent || deleted != 1 {
+		t.Errorf("DELETE returned %d, deleted %s; want 204, 0000000000000001", w.Code, deleted)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pctx "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"go.uber.org/zap"
@@ -45,7 +48,7 @@ func NewNotificationEndpointBackend(b *APIBackend) *NotificationEndpointBackend
 
 // NotificationEndpointHandler is the handler for the notificationEndpoint service
 type NotificationEndpointHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -66,8 +69,12 @@ const (
 	notificationEndpointsIDOwnersIDPath  = "/api/v2/notificationEndpoints/:id/owners/:userID"
 	notificationEndpointsIDLabelsPath    = "/api/v2/notificationEndpoints/:id/labels"
 	notificationEndpointsIDLabelsIDPath  = "/api/v2/notificationEndpoints/:id/labels/:lid"
+	notificationEndpointsIDTestPath      = "/api/v2/notificationEndpoints/:id/test"
 )
 
+// notificationEndpointTestClient sends the test notifications of endpoints.
+var notificationEndpointTestClient = &http.Client{Timeout: 10 * time.Second}
+
 // NewNotificationEndpointHandler returns a new instance of NotificationEndpointHandler.
 func NewNotificationEndpointHandler(b *NotificationEndpointBackend) *NotificationEndpointHandler {
 	h := &NotificationEndpointHandler{
@@ -88,6 +95,7 @@ func NewNotificationEndpointHandler(b *NotificationEndpointBackend) *Notificatio
 	h.HandlerFunc("DELETE", notificationEndpointsIDPath, h.handleDeleteNotificationEndpoint)
 	h.HandlerFunc("PUT", notificationEndpointsIDPath, h.handlePutNotificationEndpoint)
 	h.HandlerFunc("PATCH", notificationEndpointsIDPath, h.handlePatchNotificationEndpoint)
+	h.HandlerFunc("POST", notificationEndpointsIDTestPath, h.handleTestNotificationEndpoint)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -592,3 +600,62 @@ func (h *NotificationEndpointHandler) handleDeleteNotificationEndpoint(w http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func decodeTestNotificationEndpointRequest(ctx context.Context, r *http.Request) (influxdb.ID, endpoint.TestOptions, error) {
+	var opts endpoint.TestOptions
+	id, err := decodeGetNotificationEndpointRequest(ctx, r)
+	if err != nil {
+		return id, opts, err
+	}
+
+	// The options are optional, so an empty body is allowed.
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+		return id, opts, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid test options",
+			Err:  err,
+		}
+	}
+	return id, opts, nil
+}
+
+// handleTestNotificationEndpoint sends a synthetic notification through the
+// endpoint and responds with the response of its provider. Since the
+// notification is sent with the secrets of the endpoint, it requires write
+// access to the organization of the endpoint.
+func (h *NotificationEndpointHandler) handleTestNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, opts, err := decodeTestNotificationEndpointRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	edp, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID := edp.GetOrgID()
+	if err := authorizer.IsAllowed(ctx, influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID},
+	}); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := endpoint.Test(ctx, notificationEndpointTestClient, edp, opts, func(key string) (string, error) {
+		return h.SecretService.LoadSecret(ctx, orgID, key)
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notificationEndpoint tested", zap.String("notificationEndpointID", id.String()), zap.Int("statusCode", res.StatusCode))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -1174,3 +1175,87 @@ func TestService_handlePostNotificationEndpointOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestService_handleTestNotificationEndpoint(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("invalid_token"))
+	}))
+	defer srv.Close()
+
+	orgID := influxTesting.MustIDBase16("020f755c3c082000")
+	notificationEndpointBackend := NewMockNotificationEndpointBackend()
+	notificationEndpointBackend.HTTPErrorHandler = ErrorHandler(0)
+	notificationEndpointBackend.NotificationEndpointService = &mock.NotificationEndpointService{
+		FindNotificationEndpointByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+			return &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:     id,
+					OrgID:  orgID,
+					Name:   "hello",
+					Status: influxdb.Active,
+				},
+				URL:   srv.URL,
+				Token: influxdb.SecretField{Key: id.String() + "-token"},
+			}, nil
+		},
+	}
+	notificationEndpointBackend.SecretService = &mock.SecretService{
+		LoadSecretFn: func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+			return "slack-token", nil
+		},
+	}
+	h := NewNotificationEndpointHandler(notificationEndpointBackend)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		statusCode  int
+		body        string
+	}{
+		{
+			name: "test endpoint",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID},
+			}},
+			statusCode: http.StatusOK,
+			body:       `{"statusCode": 403, "body": "invalid_token"}`,
+		},
+		{
+			name:       "requires write access to the organization",
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/notificationEndpoints/020f755c3c082001/test", nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{
+				ExpiresAt:   time.Now().Add(time.Hour),
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("handleTestNotificationEndpoint() = %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.body); err != nil {
+					t.Errorf("handleTestNotificationEndpoint(). error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("handleTestNotificationEndpoint() = ***%s***", diff)
+				}
+				if gotAuth != "Bearer slack-token" {
+					t.Errorf("got authorization %q, want the token of the endpoint", gotAuth)
+				}
+			}
+		})
+	}
+}
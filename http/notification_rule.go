@@ -25,6 +25,7 @@ type NotificationRuleBackend struct {
 	Logger *zap.Logger
 
 	NotificationRuleStore       influxdb.NotificationRuleStore
+	MonitoringRunService        influxdb.MonitoringRunService
 	NotificationEndpointService influxdb.NotificationEndpointService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
@@ -40,6 +41,7 @@ func NewNotificationRuleBackend(b *APIBackend) *NotificationRuleBackend {
 		Logger:           b.Logger.With(zap.String("handler", "notification_rule")),
 
 		NotificationRuleStore:       b.NotificationRuleStore,
+		MonitoringRunService:        b.MonitoringRunService,
 		NotificationEndpointService: b.NotificationEndpointService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
@@ -51,11 +53,12 @@ func NewNotificationRuleBackend(b *APIBackend) *NotificationRuleBackend {
 
 // NotificationRuleHandler is the handler for the notification rule service
 type NotificationRuleHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
 	NotificationRuleStore       influxdb.NotificationRuleStore
+	MonitoringRunService        influxdb.MonitoringRunService
 	NotificationEndpointService influxdb.NotificationEndpointService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
@@ -68,6 +71,7 @@ const (
 	notificationRulesPath            = "/api/v2/notificationRules"
 	notificationRulesIDPath          = "/api/v2/notificationRules/:id"
 	notificationRulesIDQueryPath     = "/api/v2/notificationRules/:id/query"
+	notificationRulesIDRunsPath      = "/api/v2/notificationRules/:id/runs"
 	notificationRulesIDMembersPath   = "/api/v2/notificationRules/:id/members"
 	notificationRulesIDMembersIDPath = "/api/v2/notificationRules/:id/members/:userID"
 	notificationRulesIDOwnersPath    = "/api/v2/notificationRules/:id/owners"
@@ -84,6 +88,7 @@ func NewNotificationRuleHandler(b *NotificationRuleBackend) *NotificationRuleHan
 		Logger:           b.Logger,
 
 		NotificationRuleStore:       b.NotificationRuleStore,
+		MonitoringRunService:        b.MonitoringRunService,
 		NotificationEndpointService: b.NotificationEndpointService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
@@ -95,6 +100,7 @@ func NewNotificationRuleHandler(b *NotificationRuleBackend) *NotificationRuleHan
 	h.HandlerFunc("GET", notificationRulesPath, h.handleGetNotificationRules)
 	h.HandlerFunc("GET", notificationRulesIDPath, h.handleGetNotificationRule)
 	h.HandlerFunc("GET", notificationRulesIDQueryPath, h.handleGetNotificationRuleQuery)
+	h.HandlerFunc("GET", notificationRulesIDRunsPath, h.handleGetNotificationRuleRuns)
 	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
 	h.HandlerFunc("PUT", notificationRulesIDPath, h.handlePutNotificationRule)
 	h.HandlerFunc("PATCH", notificationRulesIDPath, h.handlePatchNotificationRule)
@@ -142,6 +148,7 @@ type notificationRuleLinks struct {
 	Members string `json:"members"`
 	Owners  string `json:"owners"`
 	Query   string `json:"query"`
+	Runs    string `json:"runs"`
 }
 
 type notificationRuleResponse struct {
@@ -194,6 +201,7 @@ func (h *NotificationRuleHandler) newNotificationRuleResponse(ctx context.Contex
 			Members: fmt.Sprintf("/api/v2/notificationRules/%s/members", nr.GetID()),
 			Owners:  fmt.Sprintf("/api/v2/notificationRules/%s/owners", nr.GetID()),
 			Query:   fmt.Sprintf("/api/v2/notificationRules/%s/query", nr.GetID()),
+			Runs:    fmt.Sprintf("/api/v2/notificationRules/%s/runs", nr.GetID()),
 		},
 		Labels: []influxdb.Label{},
 		Status: t.Status,
@@ -298,6 +306,31 @@ func (h *NotificationRuleHandler) handleGetNotificationRuleQuery(w http.Response
 	}
 }
 
+func (h *NotificationRuleHandler) handleGetNotificationRuleRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetRunsRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	id := req.filter.Task
+	runs, _, err := h.MonitoringRunService.FindNotificationRuleRuns(ctx, id, req.filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule runs retrieved", zap.String("notificationRuleID", id.String()), zap.Int("runs", len(runs)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newMonitoringRunsResponse(runs, fmt.Sprintf("/api/v2/notificationRules/%s", id))); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 func (h *NotificationRuleHandler) handleGetNotificationRule(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := decodeGetNotificationRuleRequest(ctx, r)
@@ -116,6 +116,7 @@ func Test_newNotificationRuleResponses(t *testing.T) {
         "members": "/api/v2/notificationRules/0000000000000001/members",
         "owners": "/api/v2/notificationRules/0000000000000001/owners",
         "query": "/api/v2/notificationRules/0000000000000001/query",
+        "runs": "/api/v2/notificationRules/0000000000000001/runs",
         "self": "/api/v2/notificationRules/0000000000000001"
       },
       "messageTemplate": "message 1{var1}",
@@ -162,6 +163,7 @@ func Test_newNotificationRuleResponses(t *testing.T) {
         "members": "/api/v2/notificationRules/000000000000000b/members",
         "owners": "/api/v2/notificationRules/000000000000000b/owners",
         "query": "/api/v2/notificationRules/000000000000000b/query",
+        "runs": "/api/v2/notificationRules/000000000000000b/runs",
         "self": "/api/v2/notificationRules/000000000000000b"
       },
       "messageTemplate": "body 2{var2}",
@@ -256,6 +258,7 @@ func Test_newNotificationRuleResponse(t *testing.T) {
    "members": "/api/v2/notificationRules/0000000000000001/members",
    "owners": "/api/v2/notificationRules/0000000000000001/owners",
    "query": "/api/v2/notificationRules/0000000000000001/query",
+   "runs": "/api/v2/notificationRules/0000000000000001/runs",
    "self": "/api/v2/notificationRules/0000000000000001"
  },
  "messageTemplate": "message 1{var1}",
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
 	"go.uber.org/zap"
 )
@@ -31,7 +30,7 @@ func NewSetupBackend(b *APIBackend) *SetupBackend {
 
 // SetupHandler represents an HTTP API handler for onboarding setup.
 type SetupHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -101,27 +100,56 @@ type onboardingResponse struct {
 	Bucket       *bucketResponse `json:"bucket"`
 	Organization *orgResponse    `json:"org"`
 	Auth         *authResponse   `json:"auth"`
+
+	Buckets []*bucketResponse `json:"buckets,omitempty"`
+	Users   []*UserResponse   `json:"users,omitempty"`
+	Auths   []*authResponse   `json:"auths,omitempty"`
 }
 
 func newOnboardingResponse(results *platform.OnboardingResults) *onboardingResponse {
-	// when onboarding the permissions are for all resources and no
-	// specifically named resources.  Therefore, there is no need to
-	// lookup the name.
-	ps := make([]permissionResponse, len(results.Auth.Permissions))
-	for i, p := range results.Auth.Permissions {
-		ps[i] = permissionResponse{
-			Action: p.Action,
-			Resource: resourceResponse{
-				Resource: p.Resource,
-			},
-		}
-	}
-	return &onboardingResponse{
+	res := &onboardingResponse{
 		User:         newUserResponse(results.User),
 		Bucket:       newBucketResponse(results.Bucket, []*platform.Label{}),
 		Organization: newOrgResponse(results.Org),
-		Auth:         newAuthResponse(results.Auth, results.Org, results.User, ps),
 	}
+
+	// every resource created during onboarding is part of the results,
+	// so the names of the permission resources are looked up there.
+	users := map[platform.ID]*platform.User{results.User.ID: results.User}
+	buckets := map[platform.ID]string{results.Bucket.ID: results.Bucket.Name}
+	for _, b := range results.Buckets {
+		buckets[b.ID] = b.Name
+		res.Buckets = append(res.Buckets, newBucketResponse(b, []*platform.Label{}))
+	}
+	for _, u := range results.Users {
+		users[u.ID] = u
+		res.Users = append(res.Users, newUserResponse(u))
+	}
+
+	newPermissions := func(ps []platform.Permission) []permissionResponse {
+		res := make([]permissionResponse, len(ps))
+		for i, p := range ps {
+			res[i] = permissionResponse{
+				Action: p.Action,
+				Resource: resourceResponse{
+					Resource: p.Resource,
+				},
+			}
+			if p.Resource.OrgID != nil {
+				res[i].Resource.Organization = results.Org.Name
+			}
+			if p.Resource.ID != nil && p.Resource.Type == platform.BucketsResourceType {
+				res[i].Resource.Name = buckets[*p.Resource.ID]
+			}
+		}
+		return res
+	}
+
+	res.Auth = newAuthResponse(results.Auth, results.Org, results.User, newPermissions(results.Auth.Permissions))
+	for _, a := range results.Auths {
+		res.Auths = append(res.Auths, newAuthResponse(a, results.Org, users[a.UserID], newPermissions(a.Permissions)))
+	}
+	return res
 }
 
 func decodePostSetupRequest(ctx context.Context, r *http.Request) (*platform.OnboardingRequest, error) {
@@ -203,10 +231,24 @@ func (s *SetupService) Generate(ctx context.Context, or *platform.OnboardingRequ
 	if err != nil {
 		return nil, err
 	}
-	return &platform.OnboardingResults{
+	results := &platform.OnboardingResults{
 		Org:    &oResp.Organization.Organization,
 		User:   &oResp.User.User,
 		Auth:   oResp.Auth.toPlatform(),
 		Bucket: bkt,
-	}, nil
+	}
+	for _, b := range oResp.Buckets {
+		bkt, err := b.toInfluxDB()
+		if err != nil {
+			return nil, err
+		}
+		results.Buckets = append(results.Buckets, bkt)
+	}
+	for _, u := range oResp.Users {
+		results.Users = append(results.Users, &u.User)
+	}
+	for _, a := range oResp.Auths {
+		results.Auths = append(results.Auths, a.toPlatform())
+	}
+	return results, nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	"go.uber.org/zap"
@@ -27,6 +28,8 @@ type OrgBackend struct {
 	SecretService                   influxdb.SecretService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	UsageService                    influxdb.UsageService
+	OrgSettingsService              influxdb.OrganizationSettingsService
 }
 
 // NewOrgBackend is a datasource used by the org handler.
@@ -41,12 +44,14 @@ func NewOrgBackend(b *APIBackend) *OrgBackend {
 		SecretService:                   b.SecretService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		UsageService:                    b.UsageService,
+		OrgSettingsService:              b.OrgSettingsService,
 	}
 }
 
 // OrgHandler represents an HTTP API handler for orgs.
 type OrgHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -56,6 +61,8 @@ type OrgHandler struct {
 	SecretService                   influxdb.SecretService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	UsageService                    influxdb.UsageService
+	OrgSettingsService              influxdb.OrganizationSettingsService
 }
 
 const (
@@ -71,6 +78,8 @@ const (
 	organizationsIDSecretsDeletePath = "/api/v2/orgs/:id/secrets/delete"
 	organizationsIDLabelsPath        = "/api/v2/orgs/:id/labels"
 	organizationsIDLabelsIDPath      = "/api/v2/orgs/:id/labels/:lid"
+	organizationsIDUsagePath         = "/api/v2/orgs/:id/usage"
+	organizationsIDSettingsPath      = "/api/v2/orgs/:id/settings"
 )
 
 func checkOrganziationExists(handler *OrgHandler) Middleware {
@@ -108,6 +117,8 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 		SecretService:                   b.SecretService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		UsageService:                    b.UsageService,
+		OrgSettingsService:              b.OrgSettingsService,
 	}
 
 	h.HandlerFunc("POST", organizationsPath, h.handlePostOrg)
@@ -146,6 +157,11 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 	// TODO(desa): need a way to specify which secrets to delete. this should work for now
 	h.HandlerFunc("POST", organizationsIDSecretsDeletePath, h.handleDeleteSecrets)
 
+	h.HandlerFunc("GET", organizationsIDUsagePath, h.handleGetUsage)
+
+	h.HandlerFunc("GET", organizationsIDSettingsPath, h.handleGetOrgSettings)
+	h.HandlerFunc("PATCH", organizationsIDSettingsPath, h.handlePatchOrgSettings)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger.With(zap.String("handler", "label")),
@@ -198,6 +214,8 @@ func newOrgResponse(o *influxdb.Organization) *orgResponse {
 			"members":    fmt.Sprintf("/api/v2/orgs/%s/members", o.ID),
 			"owners":     fmt.Sprintf("/api/v2/orgs/%s/owners", o.ID),
 			"secrets":    fmt.Sprintf("/api/v2/orgs/%s/secrets", o.ID),
+			"usage":      fmt.Sprintf("/api/v2/orgs/%s/usage", o.ID),
+			"settings":   fmt.Sprintf("/api/v2/orgs/%s/settings", o.ID),
 			"labels":     fmt.Sprintf("/api/v2/orgs/%s/labels", o.ID),
 			"buckets":    fmt.Sprintf("/api/v2/buckets?org=%s", o.Name),
 			"tasks":      fmt.Sprintf("/api/v2/tasks?org=%s", o.Name),
@@ -592,6 +610,234 @@ func decodeDeleteSecretsRequest(ctx context.Context, r *http.Request) (*deleteSe
 	return req, nil
 }
 
+type orgUsageResponse struct {
+	Links map[string]string                `json:"links"`
+	OrgID influxdb.ID                      `json:"orgID"`
+	Start time.Time                        `json:"start"`
+	Stop  time.Time                        `json:"stop"`
+	Usage map[influxdb.UsageMetric]float64 `json:"usage"`
+}
+
+func newOrgUsageResponse(orgID influxdb.ID, rng influxdb.Timespan, u map[influxdb.UsageMetric]*influxdb.Usage) *orgUsageResponse {
+	res := &orgUsageResponse{
+		Links: map[string]string{
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", orgID),
+			"self": fmt.Sprintf("/api/v2/orgs/%s/usage", orgID),
+		},
+		OrgID: orgID,
+		Start: rng.Start,
+		Stop:  rng.Stop,
+		Usage: make(map[influxdb.UsageMetric]float64, len(u)),
+	}
+	for m, v := range u {
+		res.Usage[m] = v.Value
+	}
+	return res
+}
+
+// handleGetUsage is the HTTP handler for the GET /api/v2/orgs/:id/usage route.
+func (h *OrgHandler) handleGetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetOrgUsageRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UsageService.GetUsage(ctx, req.filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgUsageResponse(*req.filter.OrgID, *req.filter.Range, u)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getOrgUsageRequest struct {
+	filter influxdb.UsageFilter
+}
+
+func decodeGetOrgUsageRequest(ctx context.Context, r *http.Request) (*getOrgUsageRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	rng, err := decodeUsageRange(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return &getOrgUsageRequest{
+		filter: influxdb.UsageFilter{
+			OrgID: &i,
+			Range: rng,
+		},
+	}, nil
+}
+
+// orgSettingsResponse is the settings of an organization with durations in seconds.
+type orgSettingsResponse struct {
+	Links                            map[string]string       `json:"links"`
+	OrgID                            influxdb.ID             `json:"orgID"`
+	DefaultRetentionSeconds          int64                   `json:"defaultRetentionSeconds"`
+	DefaultShardGroupDurationSeconds int64                   `json:"defaultShardGroupDurationSeconds"`
+	DefaultSchemaType                influxdb.SchemaType     `json:"defaultSchemaType,omitempty"`
+	QueryMaxRows                     int64                   `json:"queryMaxRows"`
+	QueryMaxBytes                    int64                   `json:"queryMaxBytes"`
+	Timezone                         string                  `json:"timezone,omitempty"`
+	WeekStart                        string                  `json:"weekStart,omitempty"`
+	APIRateLimits                    *influxdb.APIRateLimits `json:"apiRateLimits,omitempty"`
+	UpdatedAt                        *time.Time              `json:"updatedAt,omitempty"`
+}
+
+func newOrgSettingsResponse(s *influxdb.OrganizationSettings) *orgSettingsResponse {
+	res := &orgSettingsResponse{
+		Links: map[string]string{
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", s.OrgID),
+			"self": fmt.Sprintf("/api/v2/orgs/%s/settings", s.OrgID),
+		},
+		OrgID:                            s.OrgID,
+		DefaultRetentionSeconds:          int64(s.DefaultRetentionPeriod.Round(time.Second) / time.Second),
+		DefaultShardGroupDurationSeconds: int64(s.DefaultShardGroupDuration.Round(time.Second) / time.Second),
+		DefaultSchemaType:                s.DefaultSchemaType,
+		QueryMaxRows:                     s.QueryMaxRows,
+		QueryMaxBytes:                    s.QueryMaxBytes,
+		Timezone:                         s.Timezone,
+		WeekStart:                        s.WeekStart,
+		APIRateLimits:                    s.APIRateLimits,
+	}
+	if !s.UpdatedAt.IsZero() {
+		res.UpdatedAt = &s.UpdatedAt
+	}
+	return res
+}
+
+// orgSettingsUpdate is used for deserialization of settings updates with durations in seconds.
+type orgSettingsUpdate struct {
+	DefaultRetentionSeconds          *int64                  `json:"defaultRetentionSeconds,omitempty"`
+	DefaultShardGroupDurationSeconds *int64                  `json:"defaultShardGroupDurationSeconds,omitempty"`
+	DefaultSchemaType                *influxdb.SchemaType    `json:"defaultSchemaType,omitempty"`
+	QueryMaxRows                     *int64                  `json:"queryMaxRows,omitempty"`
+	QueryMaxBytes                    *int64                  `json:"queryMaxBytes,omitempty"`
+	Timezone                         *string                 `json:"timezone,omitempty"`
+	WeekStart                        *string                 `json:"weekStart,omitempty"`
+	APIRateLimits                    *influxdb.APIRateLimits `json:"apiRateLimits,omitempty"`
+}
+
+func (u *orgSettingsUpdate) toInfluxDB() influxdb.OrganizationSettingsUpdate {
+	upd := influxdb.OrganizationSettingsUpdate{
+		DefaultSchemaType: u.DefaultSchemaType,
+		QueryMaxRows:      u.QueryMaxRows,
+		QueryMaxBytes:     u.QueryMaxBytes,
+		Timezone:          u.Timezone,
+		WeekStart:         u.WeekStart,
+		APIRateLimits:     u.APIRateLimits,
+	}
+	if u.DefaultRetentionSeconds != nil {
+		d := time.Duration(*u.DefaultRetentionSeconds) * time.Second
+		upd.DefaultRetentionPeriod = &d
+	}
+	if u.DefaultShardGroupDurationSeconds != nil {
+		d := time.Duration(*u.DefaultShardGroupDurationSeconds) * time.Second
+		upd.DefaultShardGroupDuration = &d
+	}
+	return upd
+}
+
+// handleGetOrgSettings is the HTTP handler for the GET /api/v2/orgs/:id/settings route.
+func (h *OrgHandler) handleGetOrgSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	settings, err := h.OrgSettingsService.FindOrganizationSettings(ctx, req.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgSettingsResponse(settings)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchOrgSettings is the HTTP handler for the PATCH /api/v2/orgs/:id/settings route.
+func (h *OrgHandler) handlePatchOrgSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePatchOrgSettingsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	settings, err := h.OrgSettingsService.UpdateOrganizationSettings(ctx, req.orgID, req.upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("org settings updated", zap.String("settings", fmt.Sprint(settings)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgSettingsResponse(settings)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type patchOrgSettingsRequest struct {
+	orgID influxdb.ID
+	upd   influxdb.OrganizationSettingsUpdate
+}
+
+func decodePatchOrgSettingsRequest(ctx context.Context, r *http.Request) (*patchOrgSettingsRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	upd := &orgSettingsUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(upd); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	return &patchOrgSettingsRequest{
+		orgID: i,
+		upd:   upd.toInfluxDB(),
+	}, nil
+}
+
 const (
 	organizationPath = "/api/v2/orgs"
 )
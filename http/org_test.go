@@ -30,6 +30,8 @@ func NewMockOrgBackend() *OrgBackend {
 		SecretService:                   mock.NewSecretService(),
 		LabelService:                    mock.NewLabelService(),
 		UserService:                     mock.NewUserService(),
+		UsageService:                    mock.NewUsageService(),
+		OrgSettingsService:              mock.NewOrganizationSettingsService(),
 	}
 }
 
@@ -349,3 +351,235 @@ func TestSecretService_handleDeleteSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestOrgHandler_handleGetUsage(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		wants wants
+	}{
+		{
+			name:  "get usage for a range",
+			query: "?start=2019-10-01T00:00:00Z&stop=2019-10-02T00:00:00Z",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/0000000000000001",
+    "self": "/api/v2/orgs/0000000000000001/usage"
+  },
+  "orgID": "0000000000000001",
+  "start": "2019-10-01T00:00:00Z",
+  "stop": "2019-10-02T00:00:00Z",
+  "usage": {
+    "usage_query_duration_seconds": 1.5,
+    "usage_storage_bytes": 2048,
+    "usage_write_request_bytes": 100
+  }
+}
+`,
+			},
+		},
+		{
+			name:  "stop without start",
+			query: "?stop=2019-10-02T00:00:00Z",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:  "invalid start",
+			query: "?start=yesterday&stop=2019-10-02T00:00:00Z",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:  "stop before start",
+			query: "?start=2019-10-02T00:00:00Z&stop=2019-10-01T00:00:00Z",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgBackend := NewMockOrgBackend()
+			orgBackend.HTTPErrorHandler = ErrorHandler(0)
+			orgBackend.UsageService = &mock.UsageService{
+				GetUsageFn: func(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+					if filter.OrgID == nil || *filter.OrgID != 1 {
+						return nil, fmt.Errorf("unexpected org %v", filter.OrgID)
+					}
+					return map[platform.UsageMetric]*platform.Usage{
+						platform.UsageWriteRequestBytes: {OrganizationID: filter.OrgID, Type: platform.UsageWriteRequestBytes, Value: 100},
+						platform.UsageQueryDuration:     {OrganizationID: filter.OrgID, Type: platform.UsageQueryDuration, Value: 1.5},
+						platform.UsageStorageBytes:      {OrganizationID: filter.OrgID, Type: platform.UsageStorageBytes, Value: 2048},
+					}, nil
+				},
+			}
+			h := NewOrgHandler(orgBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/orgs/0000000000000001/usage"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("handleGetUsage() = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handleGetUsage(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handleGetUsage() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgHandler_handlePatchOrgSettings(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		wants wants
+	}{
+		{
+			name: "update bucket defaults",
+			body: `{"defaultRetentionSeconds": 604800, "defaultShardGroupDurationSeconds": 86400, "defaultSchemaType": "implicit"}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/0000000000000001",
+    "self": "/api/v2/orgs/0000000000000001/settings"
+  },
+  "orgID": "0000000000000001",
+  "defaultRetentionSeconds": 604800,
+  "defaultShardGroupDurationSeconds": 86400,
+  "defaultSchemaType": "implicit",
+  "queryMaxRows": 0,
+  "queryMaxBytes": 0
+}
+`,
+			},
+		},
+		{
+			name: "update query limits",
+			body: `{"queryMaxRows": 10000, "queryMaxBytes": 1048576}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/0000000000000001",
+    "self": "/api/v2/orgs/0000000000000001/settings"
+  },
+  "orgID": "0000000000000001",
+  "defaultRetentionSeconds": 0,
+  "defaultShardGroupDurationSeconds": 0,
+  "queryMaxRows": 10000,
+  "queryMaxBytes": 1048576
+}
+`,
+			},
+		},
+		{
+			name: "update locale",
+			body: `{"timezone": "Europe/Berlin", "weekStart": "sunday"}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/0000000000000001",
+    "self": "/api/v2/orgs/0000000000000001/settings"
+  },
+  "orgID": "0000000000000001",
+  "defaultRetentionSeconds": 0,
+  "defaultShardGroupDurationSeconds": 0,
+  "queryMaxRows": 0,
+  "queryMaxBytes": 0,
+  "timezone": "Europe/Berlin",
+  "weekStart": "sunday"
+}
+`,
+			},
+		},
+		{
+			name: "update api rate limits",
+			body: `{"apiRateLimits": {"write": {"requestsPerSecond": 100, "burst": 500}, "admin": {"requestsPerSecond": 0}}}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/0000000000000001",
+    "self": "/api/v2/orgs/0000000000000001/settings"
+  },
+  "orgID": "0000000000000001",
+  "defaultRetentionSeconds": 0,
+  "defaultShardGroupDurationSeconds": 0,
+  "queryMaxRows": 0,
+  "queryMaxBytes": 0,
+  "apiRateLimits": {
+    "write": {"requestsPerSecond": 100, "burst": 500},
+    "admin": {"requestsPerSecond": 0}
+  }
+}
+`,
+			},
+		},
+		{
+			name: "invalid json",
+			body: `{"defaultRetentionSeconds": "1w"}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgBackend := NewMockOrgBackend()
+			orgBackend.HTTPErrorHandler = ErrorHandler(0)
+			h := NewOrgHandler(orgBackend)
+
+			r := httptest.NewRequest("PATCH", "http://any.url/api/v2/orgs/0000000000000001/settings", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("handlePatchOrgSettings() = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePatchOrgSettings(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePatchOrgSettings() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	platform "github.com/influxdata/influxdb"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -12,24 +13,38 @@ type PlatformHandler struct {
 	AssetHandler *AssetHandler
 	DocsHandler  http.HandlerFunc
 	APIHandler   http.Handler
-}
 
-func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
-	if origin := r.Header.Get("Origin"); origin != "" {
-		w.Header().Set("Access-Control-Allow-Origin", origin)
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization")
-	}
+	// LegacyWriteHandler serves the 1.x compatible /write endpoint.
+	LegacyWriteHandler http.Handler
 }
 
 // NewPlatformHandler returns a platform handler that serves the API and associated assets.
 func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler {
 	h := NewAuthenticationHandler(b.HTTPErrorHandler)
 	h.Handler = NewAPIHandler(b, opts...)
+	if b.IdempotencyCache != nil {
+		h.Handler = IdempotencyMW(b.IdempotencyCache, DefaultIdempotentPaths...)(h.Handler)
+	}
+	if b.APIRateLimiter != nil {
+		h.Handler = RateLimitMW(b.APIRateLimiter, b.HTTPErrorHandler)(h.Handler)
+	}
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
+	h.AuthzDebugHeaderEnabled = b.AuthzDebugHeaderEnabled
+	if len(b.AuthzDebugAuthorizations) > 0 {
+		h.AuthzDebugAuthorizations = make(map[platform.ID]bool, len(b.AuthzDebugAuthorizations))
+		for _, id := range b.AuthzDebugAuthorizations {
+			h.AuthzDebugAuthorizations[id] = true
+		}
+	}
 	h.UserService = b.UserService
+	h.AuthorizationUsageTracker = b.AuthorizationUsageTracker
+	h.WriteLimits = b.WriteLimits
+	h.BodyLimit = b.BodyLimit
+	h.SessionFingerprinter = b.SessionFingerprinter
+	h.SessionAlertService = b.SessionAlertService
+	h.WriteLinkService = b.WriteLinkService
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
@@ -37,20 +52,40 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
+	h.RegisterNoAuthRoute("GET", instancesPath)
+
+	lh := NewLegacyAuthenticationHandler(b.HTTPErrorHandler)
+	lh.Handler = NewLegacyWriteHandler(NewLegacyWriteBackend(b))
+	if b.APIRateLimiter != nil {
+		lh.Handler = RateLimitMW(b.APIRateLimiter, b.HTTPErrorHandler)(lh.Handler)
+	}
+	lh.AuthorizationService = b.AuthorizationService
+	lh.UserService = b.UserService
+	lh.PasswordsService = b.PasswordsService
+	lh.AuthorizationUsageTracker = b.AuthorizationUsageTracker
 
 	assetHandler := NewAssetHandler()
 	assetHandler.Path = b.AssetsPath
+	assetHandler.Branding = b.UIBranding
+	assetHandler.MaintenanceService = b.MaintenanceService
+
+	var apiHandler, legacyWriteHandler http.Handler = h, lh
+	if b.MessageCatalog != nil {
+		apiHandler = LocalizationMW(b.MessageCatalog)(apiHandler)
+		legacyWriteHandler = LocalizationMW(b.MessageCatalog)(legacyWriteHandler)
+	}
 
 	return &PlatformHandler{
-		AssetHandler: assetHandler,
-		DocsHandler:  Redoc("/api/v2/swagger.json"),
-		APIHandler:   h,
+		AssetHandler:       assetHandler,
+		DocsHandler:        Redoc("/api/v2/swagger.json"),
+		APIHandler:         apiHandler,
+		LegacyWriteHandler: legacyWriteHandler,
 	}
 }
 
-// ServeHTTP delegates a request to the appropriate subhandler.
+// ServeHTTP delegates a request to the appropriate subhandler. Cross-origin
+// headers are set by CORSMW.
 func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	setCORSResponseHeaders(w, r)
 	if r.Method == "OPTIONS" {
 		return
 	}
@@ -60,6 +95,11 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Path == legacyWritePath {
+		h.LegacyWriteHandler.ServeHTTP(w, r)
+		return
+	}
+
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
@@ -34,6 +34,10 @@ type QueryRequest struct {
 	Query   string       `json:"query"`
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
+	// MaxPoints, if set, has the results of a flux query downsampled to
+	// about that many points per series, e.g. the pixels of the width of
+	// the dashboard cell charting them.
+	MaxPoints int `json:"maxPoints,omitempty"`
 
 	Org *influxdb.Organization `json:"-"`
 }
@@ -84,6 +88,20 @@ func (r QueryRequest) Validate() error {
 		return fmt.Errorf(`unknown query type: %s`, r.Type)
 	}
 
+	if r.MaxPoints < 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "maxPoints must not be negative",
+		}
+	}
+
+	if r.MaxPoints > 0 && r.Spec != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "request body cannot specify both a spec and maxPoints",
+		}
+	}
+
 	if len(r.Dialect.CommentPrefix) > 1 {
 		return fmt.Errorf("invalid dialect comment prefix: must be length 0 or 1")
 	}
@@ -248,6 +266,12 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 		}
 	}
 
+	if r.MaxPoints > 0 {
+		if c := r.downsampledCompiler(now()); c != nil {
+			compiler = c
+		}
+	}
+
 	delimiter, _ := utf8.DecodeRuneInString(r.Dialect.Delimiter)
 
 	noHeader := false
@@ -272,6 +296,35 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 	}, nil
 }
 
+// downsampledCompiler returns the compiler of the flux query with its results
+// downsampled to MaxPoints, or nil if the query cannot be downsampled, e.g. as
+// its time range is unknown. Queries with syntax errors are not downsampled,
+// so that their errors are reported as usual.
+func (r QueryRequest) downsampledCompiler(now time.Time) flux.Compiler {
+	var pkg *ast.Package
+	if r.Query != "" {
+		pkg = parser.ParseSource(r.Query)
+		if ast.Check(pkg) > 0 {
+			return nil
+		}
+	} else {
+		pkg = r.AST.Copy().(*ast.Package)
+	}
+
+	if !query.Downsample(pkg, r.Extern, now, r.MaxPoints) {
+		return nil
+	}
+
+	c := lang.ASTCompiler{
+		AST: pkg,
+		Now: now,
+	}
+	if r.Extern != nil {
+		c.PrependFile(r.Extern)
+	}
+	return c
+}
+
 // QueryRequestFromProxyRequest converts a query.ProxyRequest into a QueryRequest.
 // The ProxyRequest must contain supported compilers and dialects otherwise an error occurs.
 func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error) {
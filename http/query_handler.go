@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
@@ -32,6 +33,11 @@ import (
 
 const (
 	fluxPath = "/api/v2/query"
+
+	// queryMaxRowsHeader and queryMaxBytesHeader lower the result limits of
+	// the organization for a single query.
+	queryMaxRowsHeader  = "X-Influx-Query-Max-Rows"
+	queryMaxBytesHeader = "X-Influx-Query-Max-Bytes"
 )
 
 // FluxBackend is all services and associated parameters required to construct
@@ -42,6 +48,8 @@ type FluxBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	OrganizationService influxdb.OrganizationService
+	OrgSettingsService  influxdb.OrganizationSettingsService
+	MaintenanceService  influxdb.MaintenanceService
 	ProxyQueryService   query.ProxyQueryService
 }
 
@@ -54,6 +62,8 @@ func NewFluxBackend(b *APIBackend) *FluxBackend {
 
 		ProxyQueryService:   b.FluxService,
 		OrganizationService: b.OrganizationService,
+		OrgSettingsService:  b.OrgSettingsService,
+		MaintenanceService:  b.MaintenanceService,
 	}
 }
 
@@ -64,12 +74,14 @@ type HTTPDialect interface {
 
 // FluxHandler implements handling flux queries.
 type FluxHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
 	Now                 func() time.Time
 	OrganizationService influxdb.OrganizationService
+	OrgSettingsService  influxdb.OrganizationSettingsService
+	MaintenanceService  influxdb.MaintenanceService
 	ProxyQueryService   query.ProxyQueryService
 
 	EventRecorder metric.EventRecorder
@@ -85,6 +97,8 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 
 		ProxyQueryService:   b.ProxyQueryService,
 		OrganizationService: b.OrganizationService,
+		OrgSettingsService:  b.OrgSettingsService,
+		MaintenanceService:  b.MaintenanceService,
 		EventRecorder:       b.QueryEventRecorder,
 	}
 
@@ -92,6 +106,7 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 	qh := gziphandler.GzipHandler(http.HandlerFunc(h.handleQuery))
 	h.Handler("POST", fluxPath, qh)
 	h.HandlerFunc("POST", "/api/v2/query/ast", h.postFluxAST)
+	h.HandlerFunc("POST", "/api/v2/query/ast/analyze", h.postFluxASTAnalyze)
 	h.HandlerFunc("POST", "/api/v2/query/analyze", h.postQueryAnalyze)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions", h.getFluxSuggestions)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions/:name", h.getFluxSuggestion)
@@ -111,15 +126,16 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	var requestBytes int
 	sw := newStatusResponseWriter(w)
 	w = sw
-	defer func() {
+	defer func(start time.Time) {
 		h.EventRecorder.Record(ctx, metric.Event{
 			OrgID:         orgID,
 			Endpoint:      r.URL.Path, // This should be sufficient for the time being as it should only be single endpoint.
 			RequestBytes:  requestBytes,
 			ResponseBytes: sw.responseBytes,
 			Status:        sw.code(),
+			Duration:      time.Since(start),
 		})
-	}()
+	}(time.Now())
 
 	a, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
@@ -147,6 +163,11 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	orgID = req.Request.OrganizationID
 	requestBytes = n
 
+	if err := checkMaintenance(ctx, h.MaintenanceService, orgID, w); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	// Transform the context into one with the request's authorization.
 	ctx = pcontext.SetAuthorizer(ctx, req.Request.Authorization)
 
@@ -160,10 +181,36 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	limits, err := h.resultLimits(ctx, r, orgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	hd.SetHeaders(w)
 
 	cw := iocounter.Writer{Writer: w}
-	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+	var qw io.Writer = &cw
+	var lw *query.LimitWriter
+	if d, ok := req.Dialect.(*csv.Dialect); ok && !limits.IsZero() {
+		lw = query.NewLimitWriter(&cw, limits, !d.ResultEncoderConfig.NoHeader)
+		qw = lw
+	}
+
+	_, err = h.ProxyQueryService.Query(ctx, qw, req)
+	if lw != nil && lw.Truncated() {
+		h.Logger.Info("Query result truncated",
+			zap.String("handler", "flux"),
+			zap.Int64("max_rows", limits.MaxRows),
+			zap.Int64("max_bytes", limits.MaxBytes),
+		)
+		return
+	}
+	if err == nil && lw != nil {
+		err = lw.Flush()
+	}
+	if err != nil {
 		if cw.Count() == 0 {
 			// Only record the error headers IFF nothing has been written to w.
 			h.HandleHTTPError(ctx, err, w)
@@ -176,6 +223,47 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// resultLimits returns the limits of the query result of the organization,
+// lowered by the limits of the request headers.
+func (h *FluxHandler) resultLimits(ctx context.Context, r *http.Request, orgID influxdb.ID) (query.ResultLimits, error) {
+	var limits query.ResultLimits
+	if h.OrgSettingsService != nil {
+		settings, err := h.OrgSettingsService.FindOrganizationSettings(ctx, orgID)
+		if err != nil {
+			return limits, err
+		}
+		limits.MaxRows = settings.QueryMaxRows
+		limits.MaxBytes = settings.QueryMaxBytes
+	}
+
+	var (
+		requested query.ResultLimits
+		err       error
+	)
+	if requested.MaxRows, err = parseLimitHeader(r, queryMaxRowsHeader); err != nil {
+		return limits, err
+	}
+	if requested.MaxBytes, err = parseLimitHeader(r, queryMaxBytesHeader); err != nil {
+		return limits, err
+	}
+	return limits.Min(requested), nil
+}
+
+func parseLimitHeader(r *http.Request, name string) (int64, error) {
+	v := r.Header.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("%s must be a non-negative integer", name),
+		}
+	}
+	return n, nil
+}
+
 type langRequest struct {
 	Query string `json:"query"`
 }
@@ -223,6 +311,90 @@ func (h *FluxHandler) postFluxAST(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type bucketReference struct {
+	ID    *influxdb.ID `json:"id,omitempty"`
+	Name  string       `json:"name,omitempty"`
+	OrgID *influxdb.ID `json:"orgID,omitempty"`
+	Org   string       `json:"org,omitempty"`
+}
+
+func newBucketReferences(fs []influxdb.BucketFilter) []bucketReference {
+	refs := make([]bucketReference, 0, len(fs))
+	for _, f := range fs {
+		ref := bucketReference{ID: f.ID, OrgID: f.OrganizationID}
+		if f.Name != nil {
+			ref.Name = *f.Name
+		}
+		if f.Org != nil {
+			ref.Org = *f.Org
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+type postFluxASTAnalyzeResponse struct {
+	ReadBuckets  []bucketReference `json:"readBuckets"`
+	WriteBuckets []bucketReference `json:"writeBuckets"`
+	Functions    []string          `json:"functions"`
+	Ranges       []query.TimeRange `json:"ranges"`
+	Bounded      bool              `json:"bounded"`
+}
+
+// postFluxASTAnalyze returns the buckets read and written, the functions
+// called and the time ranges of the provided flux string, without executing it.
+func (h *FluxHandler) postFluxASTAnalyze(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	var request langRequest
+	ctx := r.Context()
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	pkg := parser.ParseSource(request.Query)
+	if ast.Check(pkg) > 0 {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid AST",
+			Err:  ast.GetError(pkg),
+		}, w)
+		return
+	}
+
+	a, err := query.Analyze(ctx, pkg, nil)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to analyze query",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	res := postFluxASTAnalyzeResponse{
+		ReadBuckets:  newBucketReferences(a.ReadBuckets),
+		WriteBuckets: newBucketReferences(a.WriteBuckets),
+		Functions:    a.Functions,
+		Ranges:       a.Ranges,
+		Bounded:      a.Bounded,
+	}
+	if res.Ranges == nil {
+		res.Ranges = []query.TimeRange{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 // postQueryAnalyze parses a query and returns any query errors.
 func (h *FluxHandler) postQueryAnalyze(w http.ResponseWriter, r *http.Request) {
 	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
@@ -401,6 +573,8 @@ type FluxQueryService struct {
 	Addr               string
 	Token              string
 	InsecureSkipVerify bool
+	// Transport, if set, sends the requests instead of the shared transports.
+	Transport http.RoundTripper
 }
 
 // Query runs a flux query against a influx server and decodes the result
@@ -441,6 +615,9 @@ func (s *FluxQueryService) Query(ctx context.Context, r *query.Request) (flux.Re
 	hreq = hreq.WithContext(ctx)
 
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	if s.Transport != nil {
+		hc.Transport = s.Transport
+	}
 	resp, err := hc.Do(hreq)
 	if err != nil {
 		return nil, tracing.LogError(span, err)
@@ -251,7 +251,7 @@ func TestFluxHandler_postFluxAST(t *testing.T) {
 			name:   "error from bad json",
 			w:      httptest.NewRecorder(),
 			r:      httptest.NewRequest("POST", "/api/v2/query/ast", bytes.NewBufferString(`error!`)),
-			want:   `{"code":"invalid","message":"invalid json: invalid character 'e' looking for beginning of value"}`,
+			want:   `{"code":"invalid","message":"invalid json: invalid character 'e' looking for beginning of value","error":"invalid character 'e' looking for beginning of value"}`,
 			status: http.StatusBadRequest,
 		},
 	}
@@ -271,6 +271,56 @@ func TestFluxHandler_postFluxAST(t *testing.T) {
 	}
 }
 
+func TestFluxHandler_postFluxASTAnalyze(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   string
+		status int
+	}{
+		{
+			name:   "analyze a bounded copy",
+			body:   `{"query": "from(bucket: \"a\") |> range(start: -1h) |> to(bucket: \"b\", org: \"o\")"}`,
+			want:   `{"readBuckets":[{"name":"a"}],"writeBuckets":[{"name":"b","org":"o"}],"functions":["from","range","to"],"ranges":[{"start":"-1h0m0s","stop":"now"}],"bounded":true}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "analyze an unbounded read",
+			body:   `{"query": "from(bucket: \"a\")"}`,
+			want:   `{"readBuckets":[{"name":"a"}],"writeBuckets":[],"functions":["from"],"ranges":[],"bounded":false}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "error from invalid query",
+			body:   `{"query": "from(bucket: "}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "error from undefined identifier",
+			body:   `{"query": "from(bucket: b)"}`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &FluxHandler{
+				HTTPErrorHandler: ErrorHandler(0),
+			}
+			w := httptest.NewRecorder()
+			h.postFluxASTAnalyze(w, httptest.NewRequest("POST", "/api/v2/query/ast/analyze", bytes.NewBufferString(tt.body)))
+			if got := w.Code; got != tt.status {
+				t.Fatalf("http.postFluxASTAnalyze = got %d\nwant %d", got, tt.status)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, _ := jsonEqual(w.Body.String(), tt.want); !eq {
+				t.Errorf("http.postFluxASTAnalyze = got %v, diff %s", w.Body.String(), diff)
+			}
+		})
+	}
+}
+
 func TestFluxService_Check(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(HealthHandler))
 	defer ts.Close()
@@ -434,6 +484,94 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 	})
 }
 
+func TestFluxHandler_PostQuery_ResultLimits(t *testing.T) {
+	const result = ",result,table,_value\r\n,,0,1\r\n,,0,2\r\n,,0,3\r\n"
+
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		orgRows  int64
+		header   string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "unlimited",
+			wantCode: http.StatusOK,
+			want:     result,
+		},
+		{
+			name:     "org limit",
+			orgRows:  2,
+			wantCode: http.StatusOK,
+			want:     ",result,table,_value\r\n,,0,1\r\n,,0,2\r\n\r\n#truncated,true,\"row limit of 2 reached\"\r\n",
+		},
+		{
+			name:     "request header lowers org limit",
+			orgRows:  2,
+			header:   "1",
+			wantCode: http.StatusOK,
+			want:     ",result,table,_value\r\n,,0,1\r\n\r\n#truncated,true,\"row limit of 1 reached\"\r\n",
+		},
+		{
+			name:     "request header cannot raise org limit",
+			orgRows:  1,
+			header:   "5",
+			wantCode: http.StatusOK,
+			want:     ",result,table,_value\r\n,,0,1\r\n\r\n#truncated,true,\"row limit of 1 reached\"\r\n",
+		},
+		{
+			name:     "invalid request header",
+			header:   "-1",
+			wantCode: http.StatusBadRequest,
+			want:     `{"code":"invalid","message":"X-Influx-Query-Max-Rows must be a non-negative integer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := influxmock.NewOrganizationSettingsService()
+			settings.FindOrganizationSettingsFn = func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+				return &influxdb.OrganizationSettings{OrgID: orgID, QueryMaxRows: tt.orgRows}, nil
+			}
+			h := NewFluxHandler(&FluxBackend{
+				HTTPErrorHandler:    ErrorHandler(0),
+				Logger:              zaptest.NewLogger(t),
+				QueryEventRecorder:  noopEventRecorder{},
+				OrganizationService: i,
+				OrgSettingsService:  settings,
+				ProxyQueryService: &mock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						_, err := io.WriteString(w, result)
+						return flux.Statistics{}, err
+					},
+				},
+			})
+
+			req := httptest.NewRequest("POST", "/api/v2/query?orgID="+org.ID.String(), strings.NewReader("buckets()"))
+			req = req.WithContext(icontext.SetAuthorizer(req.Context(), &influxdb.Authorization{}))
+			req.Header.Set("Content-Type", "application/vnd.flux")
+			if tt.header != "" {
+				req.Header.Set("X-Influx-Query-Max-Rows", tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			h.handleQuery(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("unexpected status code: got %d want %d", w.Code, tt.wantCode)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("unexpected body:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFluxService_Query_gzip(t *testing.T) {
 	// orgService is just to mock out orgs by returning
 	// the same org every time.
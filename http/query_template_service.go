@@ -0,0 +1,368 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	queryTemplatesPath       = "/api/v2/query/templates"
+	queryTemplatesRenderPath = "/api/v2/query/templates/render"
+	queryTemplatesSeedPath   = "/api/v2/query/templates/seed"
+	queryTemplatesIDPath     = "/api/v2/query/templates/:id"
+)
+
+// QueryTemplateBackend is all services and associated parameters required to construct
+// the QueryTemplateHandler.
+type QueryTemplateBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryTemplateService influxdb.QueryTemplateService
+	OrganizationService  influxdb.OrganizationService
+}
+
+// NewQueryTemplateBackend returns a new instance of QueryTemplateBackend.
+func NewQueryTemplateBackend(b *APIBackend) *QueryTemplateBackend {
+	return &QueryTemplateBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "query_template")),
+
+		QueryTemplateService: b.QueryTemplateService,
+		OrganizationService:  b.OrganizationService,
+	}
+}
+
+// QueryTemplateHandler is the handler for the catalog of query templates.
+type QueryTemplateHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryTemplateService influxdb.QueryTemplateService
+	OrganizationService  influxdb.OrganizationService
+}
+
+// NewQueryTemplateHandler creates a new QueryTemplateHandler.
+func NewQueryTemplateHandler(b *QueryTemplateBackend) *QueryTemplateHandler {
+	h := &QueryTemplateHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		QueryTemplateService: b.QueryTemplateService,
+		OrganizationService:  b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", queryTemplatesPath, h.handleGetQueryTemplates)
+	h.HandlerFunc("POST", queryTemplatesPath, h.handlePostQueryTemplate)
+	h.HandlerFunc("POST", queryTemplatesRenderPath, h.handleRenderQueryTemplate)
+	h.HandlerFunc("POST", queryTemplatesSeedPath, h.handleSeedQueryTemplates)
+	h.HandlerFunc("GET", queryTemplatesIDPath, h.handleGetQueryTemplate)
+	h.HandlerFunc("PATCH", queryTemplatesIDPath, h.handlePatchQueryTemplate)
+	h.HandlerFunc("DELETE", queryTemplatesIDPath, h.handleDeleteQueryTemplate)
+
+	return h
+}
+
+type queryTemplateLinks struct {
+	Self string `json:"self,omitempty"`
+	Org  string `json:"org,omitempty"`
+}
+
+type queryTemplateResponse struct {
+	*influxdb.QueryTemplate
+	Builtin bool               `json:"builtin"`
+	Links   queryTemplateLinks `json:"links"`
+}
+
+func newQueryTemplateResponse(t *influxdb.QueryTemplate) queryTemplateResponse {
+	if t.Params == nil {
+		t.Params = []influxdb.QueryTemplateParam{}
+	}
+	if !t.ID.Valid() {
+		return queryTemplateResponse{QueryTemplate: t, Builtin: true}
+	}
+	return queryTemplateResponse{
+		QueryTemplate: t,
+		Links: queryTemplateLinks{
+			Self: fmt.Sprintf("%s/%s", queryTemplatesPath, t.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", t.OrganizationID),
+		},
+	}
+}
+
+type getQueryTemplatesResponse struct {
+	Templates []queryTemplateResponse `json:"templates"`
+}
+
+func newGetQueryTemplatesResponse(ts []*influxdb.QueryTemplate) getQueryTemplatesResponse {
+	resp := getQueryTemplatesResponse{
+		Templates: make([]queryTemplateResponse, 0, len(ts)),
+	}
+	for _, t := range ts {
+		resp.Templates = append(resp.Templates, newQueryTemplateResponse(t))
+	}
+	return resp
+}
+
+// decodeQueryTemplateOrgID returns the organization of the orgID or org query
+// parameter, or nil if neither is given.
+func decodeQueryTemplateOrgID(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*influxdb.ID, error) {
+	qp := r.URL.Query()
+	if v := qp.Get("orgID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		return id, nil
+	}
+	if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		return &o.ID, nil
+	}
+	return nil, nil
+}
+
+// handleGetQueryTemplates returns the catalog of query templates: the
+// templates of the organization, if one is given, followed by the built-in
+// templates it does not override.
+func (h *QueryTemplateHandler) handleGetQueryTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID, err := decodeQueryTemplateOrgID(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var ts []*influxdb.QueryTemplate
+	if orgID != nil {
+		ts, err = h.QueryTemplateService.FindQueryTemplates(ctx, influxdb.QueryTemplateFilter{OrganizationID: orgID})
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	names := make(map[string]bool, len(ts))
+	for _, t := range ts {
+		names[t.Name] = true
+	}
+	for _, t := range influxdb.BuiltinQueryTemplates() {
+		if !names[t.Name] {
+			ts = append(ts, t)
+		}
+	}
+	h.Logger.Debug("query templates retrieved", zap.Int("count", len(ts)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetQueryTemplatesResponse(ts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestQueryTemplateID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *QueryTemplateHandler) handleGetQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestQueryTemplateID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	t, err := h.QueryTemplateService.FindQueryTemplateByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query template retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newQueryTemplateResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *QueryTemplateHandler) handlePostQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	t := &influxdb.QueryTemplate{}
+	if err := json.NewDecoder(r.Body).Decode(t); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.QueryTemplateService.CreateQueryTemplate(ctx, t); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query template created", zap.Stringer("id", t.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newQueryTemplateResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *QueryTemplateHandler) handlePatchQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestQueryTemplateID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.QueryTemplateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	t, err := h.QueryTemplateService.UpdateQueryTemplate(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query template updated", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newQueryTemplateResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *QueryTemplateHandler) handleDeleteQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestQueryTemplateID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.QueryTemplateService.DeleteQueryTemplate(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query template deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type renderQueryTemplateRequest struct {
+	OrganizationID influxdb.ID       `json:"orgID,omitempty"`
+	Name           string            `json:"name"`
+	Params         map[string]string `json:"params"`
+}
+
+type renderQueryTemplateResponse struct {
+	Flux string `json:"flux"`
+}
+
+// handleRenderQueryTemplate renders the template of the organization with the
+// name, or else the built-in template with the name, to a flux query that can
+// be used in dashboards and checks.
+func (h *QueryTemplateHandler) handleRenderQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req renderQueryTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.Name == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "query template name is required",
+		}, w)
+		return
+	}
+
+	t, err := influxdb.FindQueryTemplate(ctx, h.QueryTemplateService, req.OrganizationID, req.Name)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	flux, err := t.Render(req.Params)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query template rendered", zap.String("name", req.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, renderQueryTemplateResponse{Flux: flux}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleSeedQueryTemplates creates the built-in templates in the organization
+// and returns the created templates.
+func (h *QueryTemplateHandler) handleSeedQueryTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID, err := decodeQueryTemplateOrgID(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if orgID == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}, w)
+		return
+	}
+
+	ts, err := influxdb.SeedQueryTemplates(ctx, h.QueryTemplateService, *orgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query templates seeded", zap.Stringer("orgID", orgID), zap.Int("count", len(ts)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newGetQueryTemplatesResponse(ts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
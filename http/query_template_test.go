@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestQueryTemplateHandler(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewQueryTemplateHandler(&QueryTemplateBackend{
+		HTTPErrorHandler:     ErrorHandler(0),
+		Logger:               zap.NewNop(),
+		QueryTemplateService: svc,
+		OrganizationService:  mock.NewOrganizationService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+	decodeTemplates := func(w *httptest.ResponseRecorder) []queryTemplateResponse {
+		t.Helper()
+		var resp struct {
+			Templates []queryTemplateResponse `json:"templates"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Templates
+	}
+
+	w := do("GET", "/api/v2/query/templates", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200: %s", w.Code, w.Body)
+	}
+	ts := decodeTemplates(w)
+	if len(ts) != len(influxdb.BuiltinQueryTemplates()) || !ts[0].Builtin || ts[0].Links.Self != "" {
+		t.Fatalf("GET returned %+v, want the built-in templates", ts)
+	}
+
+	w = do("POST", "/api/v2/query/templates", `{
+		"orgID": "`+org.ID.String()+`",
+		"name": "error-rate",
+		"params": [{"name": "bucket", "type": "string", "default": "web"}],
+		"flux": "from(bucket: bucket) |> range(start: -5m)"
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	var created queryTemplateResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Builtin || created.Links.Self != "/api/v2/query/templates/"+created.ID.String() {
+		t.Fatalf("POST returned %+v", created)
+	}
+
+	w = do("GET", "/api/v2/query/templates?orgID="+org.ID.String(), "")
+	ts = decodeTemplates(w)
+	if len(ts) != len(influxdb.BuiltinQueryTemplates()) || ts[0].ID != created.ID {
+		t.Fatalf("GET of the organization returned %+v, want its template to override the built-in one", ts)
+	}
+
+	w = do("POST", "/api/v2/query/templates/render", `{"orgID": "`+org.ID.String()+`", "name": "error-rate"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST render returned %d, want 200: %s", w.Code, w.Body)
+	}
+	var rendered renderQueryTemplateResponse
+	if err := json.NewDecoder(w.Body).Decode(&rendered); err != nil {
+		t.Fatal(err)
+	}
+	if want := "bucket = \"web\"\nfrom(bucket: bucket) |> range(start: -5m)"; rendered.Flux != want {
+		t.Errorf("POST render returned %q, want %q", rendered.Flux, want)
+	}
+
+	w = do("POST", "/api/v2/query/templates/render", `{"name": "top-hosts", "params": {"bucket": "b", "k": "3"}}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `k = 3\n`) {
+		t.Errorf("POST render of a built-in template returned %d: %s", w.Code, w.Body)
+	}
+	w = do("POST", "/api/v2/query/templates/render", `{"name": "top-hosts", "params": {"k": "3"}}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("POST render without a required parameter returned %d, want 400: %s", w.Code, w.Body)
+	}
+	w = do("POST", "/api/v2/query/templates/render", `{"name": "latency"}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("POST render of an unknown template returned %d, want 404: %s", w.Code, w.Body)
+	}
+
+	w = do("POST", "/api/v2/query/templates/seed?orgID="+org.ID.String(), "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST seed returned %d, want 201: %s", w.Code, w.Body)
+	}
+	if ts := decodeTemplates(w); len(ts) != len(influxdb.BuiltinQueryTemplates())-1 {
+		t.Errorf("POST seed created %d templates, want the %d templates the organization lacks", len(ts), len(influxdb.BuiltinQueryTemplates())-1)
+	}
+
+	w = do("PATCH", "/api/v2/query/templates/"+created.ID.String(), `{"description": "5xx ratio"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"description":"5xx ratio"`) {
+		t.Errorf("PATCH returned %d: %s", w.Code, w.Body)
+	}
+	w = do("PATCH", "/api/v2/query/templates/"+created.ID.String(), `{"flux": "from(bucket: "}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("PATCH with invalid flux returned %d, want 400: %s", w.Code, w.Body)
+	}
+
+	w = do("DELETE", "/api/v2/query/templates/"+created.ID.String(), "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE returned %d, want 204: %s", w.Code, w.Body)
+	}
+	w = do("GET", "/api/v2/query/templates/"+created.ID.String(), "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET of a deleted template returned %d, want 404: %s", w.Code, w.Body)
+	}
+}
@@ -76,13 +76,14 @@ func TestQueryRequest_WithDefaults(t *testing.T) {
 
 func TestQueryRequest_Validate(t *testing.T) {
 	type fields struct {
-		Extern  *ast.File
-		Spec    *flux.Spec
-		AST     *ast.Package
-		Query   string
-		Type    string
-		Dialect QueryDialect
-		org     *platform.Organization
+		Extern    *ast.File
+		Spec      *flux.Spec
+		AST       *ast.Package
+		Query     string
+		Type      string
+		Dialect   QueryDialect
+		MaxPoints int
+		org       *platform.Organization
 	}
 	tests := []struct {
 		name    string
@@ -109,6 +110,32 @@ func TestQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "maxPoints cannot be negative",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+				MaxPoints: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "query cannot have both maxPoints and spec",
+			fields: fields{
+				Spec: &flux.Spec{},
+				Type: "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+				MaxPoints: 100,
+			},
+			wantErr: true,
+		},
 		{
 			name: "requires flux type",
 			fields: fields{
@@ -189,13 +216,14 @@ func TestQueryRequest_Validate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := QueryRequest{
-				Extern:  tt.fields.Extern,
-				Spec:    tt.fields.Spec,
-				AST:     tt.fields.AST,
-				Query:   tt.fields.Query,
-				Type:    tt.fields.Type,
-				Dialect: tt.fields.Dialect,
-				Org:     tt.fields.org,
+				Extern:    tt.fields.Extern,
+				Spec:      tt.fields.Spec,
+				AST:       tt.fields.AST,
+				Query:     tt.fields.Query,
+				Type:      tt.fields.Type,
+				Dialect:   tt.fields.Dialect,
+				MaxPoints: tt.fields.MaxPoints,
+				Org:       tt.fields.org,
 			}
 			if err := r.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("QueryRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// Headers of the rate limit of the organization of a request.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// rateLimitSettingsTTL is how long the rate limits of an organization are
+// used before its settings are read again.
+const rateLimitSettingsTTL = 10 * time.Second
+
+// RateLimitConfig is the API rate limits of the server, which apply to the
+// organizations that do not override them in their settings.
+type RateLimitConfig struct {
+	Read  influxdb.APIRateLimit
+	Write influxdb.APIRateLimit
+	Admin influxdb.APIRateLimit
+}
+
+// Valid returns an error if a limit is invalid.
+func (c RateLimitConfig) Valid() error {
+	ls := influxdb.APIRateLimits{Read: &c.Read, Write: &c.Write, Admin: &c.Admin}
+	return ls.Valid()
+}
+
+// APIRateLimiter limits the rate of the API requests of each organization
+// with a token bucket per class of requests.
+type APIRateLimiter struct {
+	Config                      RateLimitConfig
+	OrganizationSettingsService influxdb.OrganizationSettingsService
+	OrganizationService         influxdb.OrganizationService
+	UserResourceMappingService  influxdb.UserResourceMappingService
+
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[rateLimitKey]*rateLimitBucket
+	limits  map[influxdb.ID]*orgRateLimits
+}
+
+// rateLimitKey is the key of a bucket: the organization of the requests or,
+// for requests of users that are not members of an organization, the user.
+type rateLimitKey struct {
+	orgID  influxdb.ID
+	userID influxdb.ID
+	class  influxdb.APIRequestClass
+}
+
+// orgRateLimits are the overrides of an organization read from its settings.
+type orgRateLimits struct {
+	limits *influxdb.APIRateLimits
+	readAt time.Time
+}
+
+// NewAPIRateLimiter returns a limiter of the rates of c that organizations
+// override with the API rate limits of their settings in os. The
+// organizations of the requests of users are those in orgs they are members
+// of in urms. The services must not be authorized, as they are used for
+// every request.
+func NewAPIRateLimiter(c RateLimitConfig, os influxdb.OrganizationSettingsService, orgs influxdb.OrganizationService, urms influxdb.UserResourceMappingService) *APIRateLimiter {
+	return &APIRateLimiter{
+		Config:                      c,
+		OrganizationSettingsService: os,
+		OrganizationService:         orgs,
+		UserResourceMappingService:  urms,
+		logger:                      zap.NewNop(),
+		now:                         time.Now,
+		buckets:                     make(map[rateLimitKey]*rateLimitBucket),
+		limits:                      make(map[influxdb.ID]*orgRateLimits),
+	}
+}
+
+// WithLogger sets the logger l on the limiter.
+func (l *APIRateLimiter) WithLogger(logger *zap.Logger) {
+	l.logger = logger.With(zap.String("component", "api_rate_limit"))
+}
+
+// rateLimitBucket is a token bucket of requests.
+type rateLimitBucket struct {
+	limit  influxdb.APIRateLimit
+	tokens float64
+	last   time.Time
+}
+
+// take takes a token from the bucket if there is one. It returns the tokens
+// remaining, the time until the bucket is full and, if no token was taken,
+// the time until there is one.
+func (b *rateLimitBucket) take(now time.Time) (ok bool, remaining int, reset, retryAfter time.Duration) {
+	rate, burst := float64(b.limit.RequestsPerSecond), float64(b.limit.BurstSize())
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		ok = true
+	} else {
+		retryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	reset = time.Duration((burst - b.tokens) / rate * float64(time.Second))
+	return ok, int(b.tokens), reset, retryAfter
+}
+
+// limitOf returns the limit of the class of requests of the organization.
+// Organizations whose settings cannot be read, e.g. as they do not exist, are
+// not limited, so that buckets are only kept for existing organizations.
+func (l *APIRateLimiter) limitOf(ctx context.Context, orgID influxdb.ID, class influxdb.APIRequestClass) (influxdb.APIRateLimit, bool) {
+	limit := l.serverLimit(class)
+	if l.OrganizationSettingsService == nil {
+		return limit, true
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	ol, ok := l.limits[orgID]
+	l.mu.Unlock()
+	if !ok || now.Sub(ol.readAt) > rateLimitSettingsTTL {
+		s, err := l.OrganizationSettingsService.FindOrganizationSettings(ctx, orgID)
+		if err != nil {
+			l.logger.Debug("Unable to read the API rate limits of the organization", zap.Stringer("org_id", orgID), zap.Error(err))
+			return limit, false
+		}
+		ol = &orgRateLimits{limits: s.APIRateLimits, readAt: now}
+		l.mu.Lock()
+		l.limits[orgID] = ol
+		l.mu.Unlock()
+	}
+	if o := ol.limits.Of(class); o != nil {
+		limit = *o
+	}
+	return limit, true
+}
+
+// serverLimit returns the limit of the class of requests of the server.
+func (l *APIRateLimiter) serverLimit(class influxdb.APIRequestClass) influxdb.APIRateLimit {
+	switch class {
+	case influxdb.APIRequestRead:
+		return l.Config.Read
+	case influxdb.APIRequestWrite:
+		return l.Config.Write
+	case influxdb.APIRequestAdmin:
+		return l.Config.Admin
+	}
+	return influxdb.APIRateLimit{}
+}
+
+// allow takes a request from the bucket of key.
+func (l *APIRateLimiter) allow(key rateLimitKey, limit influxdb.APIRateLimit) (ok bool, remaining int, reset, retryAfter time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &rateLimitBucket{limit: limit, tokens: float64(limit.BurstSize()), last: now}
+		l.buckets[key] = b
+	} else if b.limit != limit {
+		// the limit of the organization changed; the bucket keeps the
+		// tokens it has up to its new size.
+		b.limit = limit
+		b.tokens = math.Min(b.tokens, float64(limit.BurstSize()))
+	}
+	return b.take(now)
+}
+
+// apiRequestClass returns the class of the request. Queries and their
+// analysis are reads, but query templates are resources.
+func apiRequestClass(r *http.Request) influxdb.APIRequestClass {
+	p := r.URL.Path
+	switch {
+	case p == writePath || p == legacyWritePath || p == deletePath:
+		return influxdb.APIRequestWrite
+	case r.Method == "GET" || r.Method == "HEAD":
+		return influxdb.APIRequestRead
+	case p == fluxPath || strings.HasPrefix(p, fluxPath+"/") && !strings.HasPrefix(p, queryTemplatesPath):
+		return influxdb.APIRequestRead
+	default:
+		return influxdb.APIRequestAdmin
+	}
+}
+
+// requestKey returns the key of the bucket of the request. Requests of
+// tokens are those of the organization of the token. Requests of sessions are
+// those of the organization of their orgID or org query parameter if the user
+// is a member of it, or else of the first organization the user is a member
+// of, so that the organizations of requests are never chosen by clients.
+// Requests of users that are not members of any organization are those of the
+// user. Requests without an authorizer have no key.
+func (l *APIRateLimiter) requestKey(ctx context.Context, r *http.Request, class influxdb.APIRequestClass) (rateLimitKey, bool) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return rateLimitKey{}, false
+	}
+	if auth, ok := a.(*influxdb.Authorization); ok && auth.OrgID.Valid() {
+		return rateLimitKey{orgID: auth.OrgID, class: class}, true
+	}
+
+	userID := a.GetUserID()
+	if !userID.Valid() {
+		return rateLimitKey{}, false
+	}
+	key := rateLimitKey{userID: userID, class: class}
+	if l.UserResourceMappingService == nil {
+		return key, true
+	}
+	ms, _, err := l.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil || len(ms) == 0 {
+		return key, true
+	}
+
+	orgID := ms[0].ResourceID
+	if id, ok := l.requestedOrgID(ctx, r); ok {
+		for _, m := range ms {
+			if m.ResourceID == id {
+				orgID = id
+				break
+			}
+		}
+	}
+	return rateLimitKey{orgID: orgID, class: class}, true
+}
+
+// requestedOrgID returns the organization of the orgID or org query parameter
+// of the request.
+func (l *APIRateLimiter) requestedOrgID(ctx context.Context, r *http.Request) (influxdb.ID, bool) {
+	qp := r.URL.Query()
+	if id := qp.Get("orgID"); id != "" {
+		orgID, err := influxdb.IDFromString(id)
+		if err != nil {
+			return 0, false
+		}
+		return *orgID, true
+	}
+	if name := qp.Get("org"); name != "" && l.OrganizationService != nil {
+		o, err := l.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &name})
+		if err != nil {
+			return 0, false
+		}
+		return o.ID, true
+	}
+	return 0, false
+}
+
+// RateLimitMW returns a middleware that limits the rate of the requests of
+// each organization by their class. Requests over the limit are rejected
+// with 429 Too Many Requests and a Retry-After header, and errors are handled
+// by h. Limited requests tell the client about the limit with the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, the latter
+// in seconds. Requests of users that are not members of an organization are
+// limited by the limits of the server. It must be applied after
+// authentication.
+func RateLimitMW(l *APIRateLimiter, h influxdb.HTTPErrorHandler) Middleware {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			class := apiRequestClass(r)
+			key, ok := l.requestKey(ctx, r, class)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limit, ok := l.serverLimit(class), true
+			if key.orgID.Valid() {
+				limit, ok = l.limitOf(ctx, key.orgID, class)
+			}
+			if !ok || limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, remaining, reset, retryAfter := l.allow(key, limit)
+			w.Header().Set(RateLimitLimitHeader, strconv.Itoa(limit.BurstSize()))
+			w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(remaining))
+			w.Header().Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(reset)))
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+				h.HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.ETooManyRequests,
+					Op:   "http/RateLimitMW",
+					Msg:  "organization exceeded its rate limit of " + string(class) + " requests",
+				}, w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
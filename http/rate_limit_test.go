@@ -0,0 +1,153 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestRateLimitMW(t *testing.T) {
+	orgID, limitedOrgID := influxdb.ID(1), influxdb.ID(2)
+	settings := mock.NewOrganizationSettingsService()
+	settings.FindOrganizationSettingsFn = func(_ context.Context, id influxdb.ID) (*influxdb.OrganizationSettings, error) {
+		switch id {
+		case orgID:
+			return &influxdb.OrganizationSettings{OrgID: id}, nil
+		case limitedOrgID:
+			return &influxdb.OrganizationSettings{OrgID: id, APIRateLimits: &influxdb.APIRateLimits{
+				Read: &influxdb.APIRateLimit{RequestsPerSecond: 1},
+			}}, nil
+		default:
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		}
+	}
+
+	memberID, loneUserID := influxdb.ID(10), influxdb.ID(11)
+	urms := mock.NewUserResourceMappingService()
+	urms.FindMappingsFn = func(_ context.Context, f influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+		if f.UserID == memberID && f.ResourceType == influxdb.OrgsResourceType {
+			return []*influxdb.UserResourceMapping{{UserID: memberID, ResourceID: limitedOrgID, ResourceType: influxdb.OrgsResourceType}}, 1, nil
+		}
+		return nil, 0, nil
+	}
+
+	l := NewAPIRateLimiter(RateLimitConfig{
+		Write: influxdb.APIRateLimit{RequestsPerSecond: 2, Burst: 3},
+	}, settings, mock.NewOrganizationService(), urms)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	h := RateLimitMW(l, ErrorHandler(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	do := func(method, path string, orgID influxdb.ID) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, "http://any.url"+path, nil)
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Authorization{OrgID: orgID, Status: influxdb.Active}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// the burst of 3 writes is allowed, the 4th has to wait for a token.
+	for i := 0; i < 3; i++ {
+		w := do("POST", "/api/v2/write", orgID)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("write %d returned %d, want 204", i, w.Code)
+		}
+		if got := w.Header().Get(RateLimitRemainingHeader); got != strconv.Itoa(2-i) {
+			t.Errorf("write %d has %s remaining, want %d", i, got, 2-i)
+		}
+	}
+	w := do("POST", "/api/v2/write", orgID)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("write over the limit returned %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" || w.Header().Get(RateLimitLimitHeader) != "3" || w.Header().Get(RateLimitResetHeader) != "2" {
+		t.Errorf("unexpected rate limit headers %v", w.Header())
+	}
+
+	// reads of the org are unlimited, as neither the server nor the org limits them.
+	if w := do("GET", "/api/v2/buckets", orgID); w.Code != http.StatusNoContent || w.Header().Get(RateLimitLimitHeader) != "" {
+		t.Errorf("unlimited read returned %d with headers %v", w.Code, w.Header())
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if w := do("POST", "/api/v2/write", orgID); w.Code != http.StatusNoContent {
+		t.Errorf("write after a token was added returned %d, want 204", w.Code)
+	}
+
+	// the org overrides the read limit of the server.
+	if w := do("POST", "/api/v2/query", limitedOrgID); w.Code != http.StatusNoContent {
+		t.Fatalf("query returned %d, want 204", w.Code)
+	}
+	if w := do("GET", "/api/v2/buckets", limitedOrgID); w.Code != http.StatusTooManyRequests {
+		t.Errorf("read over the limit of the org returned %d, want 429", w.Code)
+	}
+
+	// unknown orgs are not limited.
+	for i := 0; i < 5; i++ {
+		if w := do("POST", "/api/v2/write", 3); w.Code != http.StatusNoContent {
+			t.Fatalf("write of an unknown org returned %d, want 204", w.Code)
+		}
+	}
+
+	doSession := func(method, path string, userID influxdb.ID) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, "http://any.url"+path, nil)
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: userID, ExpiresAt: now.Add(time.Hour)}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// sessions are limited by the org of the user, whatever org they ask for.
+	now = now.Add(time.Minute)
+	if w := doSession("GET", "/api/v2/buckets?orgID="+orgID.String(), memberID); w.Code != http.StatusNoContent {
+		t.Fatalf("read of a session returned %d, want 204", w.Code)
+	}
+	for _, path := range []string{"/api/v2/buckets?orgID=" + orgID.String(), "/api/v2/buckets?orgID=00000000000000ff", "/api/v2/buckets"} {
+		if w := doSession("GET", path, memberID); w.Code != http.StatusTooManyRequests {
+			t.Errorf("read of a session of %s over the limit of the org of the user returned %d, want 429", path, w.Code)
+		}
+	}
+
+	// users without orgs are limited by the limits of the server.
+	for i := 0; i < 3; i++ {
+		if w := doSession("POST", "/api/v2/write?orgID=00000000000000f"+strconv.Itoa(i), loneUserID); w.Code != http.StatusNoContent {
+			t.Fatalf("write %d of a user without orgs returned %d, want 204", i, w.Code)
+		}
+	}
+	if w := doSession("POST", "/api/v2/write?orgID=00000000000000fe", loneUserID); w.Code != http.StatusTooManyRequests {
+		t.Errorf("write of a user without orgs over the limit of the server returned %d, want 429", w.Code)
+	}
+}
+
+func TestAPIRequestClass(t *testing.T) {
+	for _, tt := range []struct {
+		method, path string
+		want         influxdb.APIRequestClass
+	}{
+		{"POST", "/api/v2/write", influxdb.APIRequestWrite},
+		{"POST", "/write", influxdb.APIRequestWrite},
+		{"POST", "/api/v2/delete", influxdb.APIRequestWrite},
+		{"GET", "/api/v2/buckets", influxdb.APIRequestRead},
+		{"POST", "/api/v2/query", influxdb.APIRequestRead},
+		{"POST", "/api/v2/query/analyze", influxdb.APIRequestRead},
+		{"POST", "/api/v2/query/templates", influxdb.APIRequestAdmin},
+		{"POST", "/api/v2/buckets", influxdb.APIRequestAdmin},
+		{"DELETE", "/api/v2/buckets/0000000000000001", influxdb.APIRequestAdmin},
+	} {
+		r := httptest.NewRequest(tt.method, "http://any.url"+tt.path, nil)
+		if got := apiRequestClass(r); got != tt.want {
+			t.Errorf("%s %s is a %s request, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,516 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+const (
+	remotesPath = "/api/v2/remotes"
+
+	// remoteTestTimeout bounds the requests testing a remote instance.
+	remoteTestTimeout = 10 * time.Second
+)
+
+// RemoteConnectionBackend is all services and associated parameters required to construct
+// the RemoteConnectionHandler.
+type RemoteConnectionBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RemoteConnectionService influxdb.RemoteConnectionService
+	OrganizationService     influxdb.OrganizationService
+	SecretService           influxdb.SecretService
+}
+
+// NewRemoteConnectionBackend returns a new instance of RemoteConnectionBackend.
+func NewRemoteConnectionBackend(b *APIBackend) *RemoteConnectionBackend {
+	return &RemoteConnectionBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "remote_connection")),
+
+		RemoteConnectionService: b.RemoteConnectionService,
+		OrganizationService:     b.OrganizationService,
+		SecretService:           b.SecretService,
+	}
+}
+
+// RemoteConnectionHandler is the handler for remote connections.
+type RemoteConnectionHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RemoteConnectionService influxdb.RemoteConnectionService
+	OrganizationService     influxdb.OrganizationService
+
+	connector *RemoteConnector
+}
+
+// NewRemoteConnectionHandler creates a new RemoteConnectionHandler.
+func NewRemoteConnectionHandler(b *RemoteConnectionBackend) *RemoteConnectionHandler {
+	h := &RemoteConnectionHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		RemoteConnectionService: b.RemoteConnectionService,
+		OrganizationService:     b.OrganizationService,
+
+		connector: NewRemoteConnector(b.SecretService),
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", remotesPath)
+
+	h.HandlerFunc("GET", remotesPath, h.handleGetRemoteConnections)
+	h.HandlerFunc("POST", remotesPath, h.handlePostRemoteConnection)
+	h.HandlerFunc("GET", entityPath, h.handleGetRemoteConnection)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchRemoteConnection)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteRemoteConnection)
+	h.HandlerFunc("POST", entityPath+"/test", h.handleTestRemoteConnection)
+
+	return h
+}
+
+type remoteConnectionLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+	Test string `json:"test"`
+}
+
+type remoteConnectionResponse struct {
+	*influxdb.RemoteConnection
+	Links remoteConnectionLinks `json:"links"`
+}
+
+func newRemoteConnectionResponse(rc *influxdb.RemoteConnection) remoteConnectionResponse {
+	return remoteConnectionResponse{
+		RemoteConnection: rc,
+		Links: remoteConnectionLinks{
+			Self: remoteConnectionIDPath(rc.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", rc.OrganizationID),
+			Test: path.Join(remoteConnectionIDPath(rc.ID), "test"),
+		},
+	}
+}
+
+type getRemoteConnectionsResponse struct {
+	Remotes []remoteConnectionResponse `json:"remotes"`
+}
+
+func newGetRemoteConnectionsResponse(rcs []*influxdb.RemoteConnection) getRemoteConnectionsResponse {
+	resp := getRemoteConnectionsResponse{
+		Remotes: make([]remoteConnectionResponse, 0, len(rcs)),
+	}
+	for _, rc := range rcs {
+		resp.Remotes = append(resp.Remotes, newRemoteConnectionResponse(rc))
+	}
+	return resp
+}
+
+type getRemoteConnectionsRequest struct {
+	filter influxdb.RemoteConnectionFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetRemoteConnectionsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getRemoteConnectionsRequest, error) {
+	qp := r.URL.Query()
+	req := &getRemoteConnectionsRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	if v := qp.Get("orgID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		req.filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	return req, nil
+}
+
+func (h *RemoteConnectionHandler) handleGetRemoteConnections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetRemoteConnectionsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rcs, _, err := h.RemoteConnectionService.FindRemoteConnections(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("remote connections retrieved", zap.Int("count", len(rcs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRemoteConnectionsResponse(rcs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestRemoteConnectionID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *RemoteConnectionHandler) handleGetRemoteConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRemoteConnectionID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rc, err := h.RemoteConnectionService.FindRemoteConnectionByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("remote connection retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRemoteConnectionResponse(rc)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *RemoteConnectionHandler) handlePostRemoteConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := &influxdb.RemoteConnection{}
+	if err := json.NewDecoder(r.Body).Decode(rc); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.RemoteConnectionService.CreateRemoteConnection(ctx, rc); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("remote connection created", zap.Stringer("id", rc.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRemoteConnectionResponse(rc)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *RemoteConnectionHandler) handlePatchRemoteConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRemoteConnectionID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.RemoteConnectionUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	rc, err := h.RemoteConnectionService.UpdateRemoteConnection(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("remote connection updated", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRemoteConnectionResponse(rc)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *RemoteConnectionHandler) handleDeleteRemoteConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRemoteConnectionID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RemoteConnectionService.DeleteRemoteConnection(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("remote connection deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestRemoteConnection checks that the remote instance is reachable
+// and that the token of the connection can read the remote organization.
+// Since the test sends the token of the connection, it requires write
+// access to the connection. Failures of the remote instance are reported
+// in the body of the response.
+func (h *RemoteConnectionHandler) handleTestRemoteConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRemoteConnectionID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rc, err := h.RemoteConnectionService.FindRemoteConnectionByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	p, err := influxdb.NewPermissionAtID(id, influxdb.WriteAction, influxdb.RemoteConnectionsResourceType, rc.OrganizationID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := authorizer.IsAllowed(ctx, *p); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := check.Response{Name: rc.Name, Status: check.StatusPass}
+	if err := h.connector.Test(ctx, rc); err != nil {
+		res.Status = check.StatusFail
+		res.Message = err.Error()
+	}
+	h.Logger.Debug("remote connection tested", zap.Stringer("id", id), zap.String("status", string(res.Status)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func remoteConnectionIDPath(id influxdb.ID) string {
+	return path.Join(remotesPath, id.String())
+}
+
+// RemoteConnector connects to the instances of remote connections with the
+// tokens kept in the secrets of their organizations.
+type RemoteConnector struct {
+	SecretService influxdb.SecretService
+
+	mu sync.Mutex
+	// transports are shared by the connections with the same certificate
+	// authorities.
+	transports map[influxdb.RemoteConnectionTLS]http.RoundTripper
+}
+
+// NewRemoteConnector returns a RemoteConnector loading tokens from the secret service.
+func NewRemoteConnector(ss influxdb.SecretService) *RemoteConnector {
+	return &RemoteConnector{
+		SecretService: ss,
+		transports:    make(map[influxdb.RemoteConnectionTLS]http.RoundTripper),
+	}
+}
+
+func (c *RemoteConnector) token(ctx context.Context, rc *influxdb.RemoteConnection) (string, error) {
+	token, err := c.SecretService.LoadSecret(ctx, rc.OrganizationID, rc.TokenSecretKey)
+	if err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("could not load the token of remote %q from secret %q", rc.Name, rc.TokenSecretKey),
+			Err:  err,
+		}
+	}
+	return token, nil
+}
+
+func (c *RemoteConnector) transport(opts influxdb.RemoteConnectionTLS) (http.RoundTripper, error) {
+	if opts.CACert == "" {
+		if opts.InsecureSkipVerify {
+			return skipVerifyTransport, nil
+		}
+		return defaultTransport, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.transports[opts]; ok {
+		return t, nil
+	}
+	pool, err := opts.CertPool()
+	if err != nil {
+		return nil, err
+	}
+	t := newTLSTransport(&tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	})
+	c.transports[opts] = t
+	return t, nil
+}
+
+// QueryService returns a query service running queries on the instance of
+// the remote connection.
+func (c *RemoteConnector) QueryService(ctx context.Context, rc *influxdb.RemoteConnection) (query.QueryService, error) {
+	token, err := c.token(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.transport(rc.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return &FluxQueryService{
+		Addr:      rc.URL,
+		Token:     token,
+		Transport: t,
+	}, nil
+}
+
+// Test checks that the instance of the remote connection is healthy and that
+// the token of the connection can read the remote organization.
+func (c *RemoteConnector) Test(ctx context.Context, rc *influxdb.RemoteConnection) error {
+	token, err := c.token(ctx, rc)
+	if err != nil {
+		return err
+	}
+	t, err := c.transport(rc.TLS)
+	if err != nil {
+		return err
+	}
+	hc := &traceClient{Client: http.Client{Transport: t, Timeout: remoteTestTimeout}}
+
+	get := func(p string, v interface{}) error {
+		u, err := NewURL(rc.URL, p)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return err
+		}
+		SetToken(token, req)
+		resp, err := hc.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if err := CheckError(resp); err != nil {
+			return err
+		}
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+
+	var health check.Response
+	if err := get("/health", &health); err != nil {
+		return fmt.Errorf("remote instance is unreachable: %v", err)
+	}
+	if health.Status != check.StatusPass {
+		return fmt.Errorf("remote instance is unhealthy: %s", health.Message)
+	}
+
+	var org orgResponse
+	if err := get(organizationIDPath(rc.RemoteOrgID), &org); err != nil {
+		return fmt.Errorf("remote organization %s could not be read: %v", rc.RemoteOrgID, err)
+	}
+	return nil
+}
+
+// Write writes the line protocol of nanosecond precision to the bucket of
+// the remote organization of the connection.
+func (c *RemoteConnector) Write(ctx context.Context, rc *influxdb.RemoteConnection, bucket string, data []byte) error {
+	token, err := c.token(ctx, rc)
+	if err != nil {
+		return err
+	}
+	t, err := c.transport(rc.TLS)
+	if err != nil {
+		return err
+	}
+
+	u, err := NewURL(rc.URL, writePath)
+	if err != nil {
+		return err
+	}
+	params := url.Values{}
+	params.Set(OrgID, rc.RemoteOrgID.String())
+	params.Set(Bucket, bucket)
+	params.Set("precision", "ns")
+	u.RawQuery = params.Encode()
+
+	// The body is compressed since remotes are often reached over slow links.
+	var body bytes.Buffer
+	gw := gzip.NewWriter(&body)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	SetToken(token, req)
+
+	hc := &traceClient{Client: http.Client{Transport: t}}
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
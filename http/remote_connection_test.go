@@ -0,0 +1,222 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestRemoteConnectionHandler(t *testing.T) {
+	var (
+		created *influxdb.RemoteConnection
+		updated influxdb.RemoteConnectionUpdate
+		deleted influxdb.ID
+	)
+	svc := mock.NewRemoteConnectionService()
+	svc.CreateRemoteConnectionFn = func(_ context.Context, rc *influxdb.RemoteConnection) error {
+		rc.ID = 1
+		created = rc
+		return nil
+	}
+	svc.FindRemoteConnectionByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+		return created, nil
+	}
+	svc.UpdateRemoteConnectionFn = func(_ context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error) {
+		updated = upd
+		upd.Apply(created)
+		return created, nil
+	}
+	svc.DeleteRemoteConnectionFn = func(_ context.Context, id influxdb.ID) error {
+		deleted = id
+		return nil
+	}
+
+	h := NewRemoteConnectionHandler(&RemoteConnectionBackend{
+		HTTPErrorHandler:        ErrorHandler(0),
+		Logger:                  zap.NewNop(),
+		RemoteConnectionService: svc,
+		OrganizationService:     mock.NewOrganizationService(),
+		SecretService:           mock.NewSecretService(),
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/api/v2/remotes", `{
+		"orgID": "0000000000000002",
+		"name": "edge-1",
+		"url": "https://edge-1:9999",
+		"remoteOrgID": "0000000000000003",
+		"tokenSecretKey": "edge-1-token",
+		"tls": {"insecureSkipVerify": true}
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	want := &influxdb.RemoteConnection{
+		ID:             1,
+		OrganizationID: 2,
+		Name:           "edge-1",
+		URL:            "https://edge-1:9999",
+		RemoteOrgID:    3,
+		TokenSecretKey: "edge-1-token",
+		TLS:            influxdb.RemoteConnectionTLS{InsecureSkipVerify: true},
+	}
+	if diff := cmp.Diff(created, want); diff != "" {
+		t.Errorf("unexpected created remote connection -got/+want\n%s", diff)
+	}
+
+	w = do("GET", "/api/v2/remotes/0000000000000001", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var resp struct {
+		influxdb.RemoteConnection
+		Links remoteConnectionLinks `json:"links"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.TokenSecretKey != "edge-1-token" || resp.Links.Test != "/api/v2/remotes/0000000000000001/test" {
+		t.Errorf("unexpected remote connection %+v", resp)
+	}
+
+	w = do("PATCH", "/api/v2/remotes/0000000000000001", `{"url": "https://edge-2:9999"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200", w.Code)
+	}
+	if *updated.URL != "https://edge-2:9999" || updated.Name != nil {
+		t.Errorf("unexpected update %+v", updated)
+	}
+
+	if w := do("DELETE", "/api/v2/remotes/0000000000000001", ""); w.Code != http.StatusNoContent || deleted != 1 {
+		t.Errorf("DELETE returned %d, deleted %s; want 204, 0000000000000001", w.Code, deleted)
+	}
+
+	if w := do("GET", "/api/v2/remotes?orgID=invalid", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid filter returned %d, want 400", w.Code)
+	}
+}
+
+func TestRemoteConnectionHandler_Test(t *testing.T) {
+	orgID, remoteOrgID := influxdb.ID(2), influxdb.ID(3)
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			HealthHandler(w, r)
+		case organizationIDPath(remoteOrgID):
+			if r.Header.Get("Authorization") != "Token edge-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code":"unauthorized","message":"unauthorized access"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"0000000000000003","name":"edge"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer remote.Close()
+
+	tests := []struct {
+		name        string
+		tokenKey    string
+		permissions []influxdb.Permission
+		statusCode  int
+		status      check.Status
+	}{
+		{
+			name:     "passes with the token of the connection",
+			tokenKey: "edge-token",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.RemoteConnectionsResourceType, OrgID: &orgID},
+			}},
+			statusCode: http.StatusOK,
+			status:     check.StatusPass,
+		},
+		{
+			name:     "fails with a token rejected by the remote",
+			tokenKey: "other-token",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.RemoteConnectionsResourceType, OrgID: &orgID},
+			}},
+			statusCode: http.StatusOK,
+			status:     check.StatusFail,
+		},
+		{
+			name:     "requires write access to the connection",
+			tokenKey: "edge-token",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.RemoteConnectionsResourceType, OrgID: &orgID},
+			}},
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewRemoteConnectionService()
+			svc.FindRemoteConnectionByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+				return &influxdb.RemoteConnection{
+					ID:             id,
+					OrganizationID: orgID,
+					Name:           "edge",
+					URL:            remote.URL,
+					RemoteOrgID:    remoteOrgID,
+					TokenSecretKey: tt.tokenKey,
+				}, nil
+			}
+			secrets := mock.NewSecretService()
+			secrets.LoadSecretFn = func(_ context.Context, _ influxdb.ID, key string) (string, error) {
+				return key, nil
+			}
+			h := NewRemoteConnectionHandler(&RemoteConnectionBackend{
+				HTTPErrorHandler:        ErrorHandler(0),
+				Logger:                  zap.NewNop(),
+				RemoteConnectionService: svc,
+				OrganizationService:     mock.NewOrganizationService(),
+				SecretService:           secrets,
+			})
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/remotes/0000000000000001/test", nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{
+				ExpiresAt:   time.Now().Add(time.Hour),
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.statusCode {
+				t.Fatalf("POST returned %d, want %d: %s", w.Code, tt.statusCode, w.Body)
+			}
+			if tt.status == "" {
+				return
+			}
+			var res check.Response
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != tt.status {
+				t.Errorf("test returned %+v, want status %s", res, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+const (
+	reportsPath       = "/api/v2/reports"
+	reportsIDPath     = "/api/v2/reports/:id"
+	reportsIDRunsPath = "/api/v2/reports/:id/runs"
+)
+
+// ReportBackend is all services and associated parameters required to construct
+// the ReportHandler.
+type ReportBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	ReportService         influxdb.ReportService
+	ReportDeliveryService influxdb.ReportDeliveryService
+	OrganizationService   influxdb.OrganizationService
+}
+
+// NewReportBackend returns a new instance of ReportBackend.
+func NewReportBackend(b *APIBackend) *ReportBackend {
+	return &ReportBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "report")),
+
+		ReportService:         b.ReportService,
+		ReportDeliveryService: b.ReportDeliveryService,
+		OrganizationService:   b.OrganizationService,
+	}
+}
+
+// ReportHandler is the handler for scheduled reports.
+type ReportHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	ReportService         influxdb.ReportService
+	ReportDeliveryService influxdb.ReportDeliveryService
+	OrganizationService   influxdb.OrganizationService
+}
+
+// NewReportHandler creates a new ReportHandler.
+func NewReportHandler(b *ReportBackend) *ReportHandler {
+	h := &ReportHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		ReportService:         b.ReportService,
+		ReportDeliveryService: b.ReportDeliveryService,
+		OrganizationService:   b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", reportsPath, h.handleGetReports)
+	h.HandlerFunc("POST", reportsPath, h.handlePostReport)
+	h.HandlerFunc("GET", reportsIDPath, h.handleGetReport)
+	h.HandlerFunc("PATCH", reportsIDPath, h.handlePatchReport)
+	h.HandlerFunc("DELETE", reportsIDPath, h.handleDeleteReport)
+	h.HandlerFunc("GET", reportsIDRunsPath, h.handleGetReportRuns)
+	h.HandlerFunc("POST", reportsIDRunsPath, h.handlePostReportRun)
+
+	return h
+}
+
+type reportLinks struct {
+	Self      string `json:"self"`
+	Org       string `json:"org"`
+	Dashboard string `json:"dashboard"`
+	Runs      string `json:"runs"`
+}
+
+type reportResponse struct {
+	*influxdb.Report
+	Links reportLinks `json:"links"`
+}
+
+func reportIDPath(id influxdb.ID) string {
+	return fmt.Sprintf("%s/%s", reportsPath, id)
+}
+
+func newReportResponse(r *influxdb.Report) reportResponse {
+	if r.Emails == nil {
+		r.Emails = []string{}
+	}
+	return reportResponse{
+		Report: r,
+		Links: reportLinks{
+			Self:      reportIDPath(r.ID),
+			Org:       fmt.Sprintf("/api/v2/orgs/%s", r.OrganizationID),
+			Dashboard: fmt.Sprintf("/api/v2/dashboards/%s", r.DashboardID),
+			Runs:      reportIDPath(r.ID) + "/runs",
+		},
+	}
+}
+
+type getReportsResponse struct {
+	Reports []reportResponse `json:"reports"`
+}
+
+func newGetReportsResponse(rs []*influxdb.Report) getReportsResponse {
+	resp := getReportsResponse{
+		Reports: make([]reportResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		resp.Reports = append(resp.Reports, newReportResponse(r))
+	}
+	return resp
+}
+
+type reportRunLinks struct {
+	Report string `json:"report"`
+}
+
+type reportRunResponse struct {
+	*influxdb.ReportRun
+	Links reportRunLinks `json:"links"`
+}
+
+func newReportRunResponse(run *influxdb.ReportRun) reportRunResponse {
+	return reportRunResponse{
+		ReportRun: run,
+		Links: reportRunLinks{
+			Report: reportIDPath(run.ReportID),
+		},
+	}
+}
+
+type getReportRunsResponse struct {
+	Runs []reportRunResponse `json:"runs"`
+}
+
+func newGetReportRunsResponse(runs []*influxdb.ReportRun) getReportRunsResponse {
+	resp := getReportRunsResponse{
+		Runs: make([]reportRunResponse, 0, len(runs)),
+	}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, newReportRunResponse(run))
+	}
+	return resp
+}
+
+type getReportsRequest struct {
+	filter influxdb.ReportFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetReportsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getReportsRequest, error) {
+	qp := r.URL.Query()
+	req := &getReportsRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	if v := qp.Get("orgID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		req.filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = &o.ID
+	}
+
+	if v := qp.Get("dashboardID"); v != "" {
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid dashboardID",
+				Err:  err,
+			}
+		}
+		req.filter.DashboardID = id
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	return req, nil
+}
+
+func (h *ReportHandler) handleGetReports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetReportsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.ReportService.FindReports(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("reports retrieved", zap.Int("count", len(rs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetReportsResponse(rs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestReportID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *ReportHandler) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReportID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rep, err := h.ReportService.FindReportByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report retrieved", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newReportResponse(rep)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *ReportHandler) handlePostReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rep := &influxdb.Report{}
+	if err := json.NewDecoder(r.Body).Decode(rep); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	// the report is rendered with the permissions of the user creating it.
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	rep.OwnerID = auth.GetUserID()
+
+	if err := h.ReportService.CreateReport(ctx, rep); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report created", zap.Stringer("id", rep.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newReportResponse(rep)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *ReportHandler) handlePatchReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReportID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.ReportUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	rep, err := h.ReportService.UpdateReport(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report updated", zap.Stringer("id", id))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newReportResponse(rep)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *ReportHandler) handleDeleteReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReportID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.ReportService.DeleteReport(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report deleted", zap.Stringer("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ReportHandler) handleGetReportRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReportID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	runs, err := h.ReportService.FindReportRuns(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report runs retrieved", zap.Stringer("id", id), zap.Int("count", len(runs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetReportRunsResponse(runs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostReportRun delivers the report immediately, outside of its
+// schedule, and returns the recorded run. A failed delivery is a run with
+// the failed status rather than an error.
+func (h *ReportHandler) handlePostReportRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestReportID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	run, err := h.ReportDeliveryService.DeliverReport(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("report delivered", zap.Stringer("id", id), zap.String("status", run.Status))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newReportRunResponse(run)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestReportHandler(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	d := &influxdb.Dashboard{OrganizationID: org.ID, Name: "overview"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := mock.NewReportDeliveryService()
+	deliveries.DeliverReportFn = func(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error) {
+		run := &influxdb.ReportRun{ReportID: id, Manual: true, Status: influxdb.ReportRunSuccess}
+		return run, svc.AddReportRun(ctx, run)
+	}
+	h := NewReportHandler(&ReportBackend{
+		HTTPErrorHandler:      ErrorHandler(0),
+		Logger:                zap.NewNop(),
+		ReportService:         svc,
+		ReportDeliveryService: deliveries,
+		OrganizationService:   svc,
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://any.url"+path, strings.NewReader(body))
+		h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: 3})))
+		return w
+	}
+
+	w := do("POST", "/api/v2/reports", `{
+		"orgID": "`+org.ID.String()+`",
+		"name": "daily",
+		"dashboardID": "`+d.ID.String()+`",
+		"every": "24h",
+		"offset": "9h",
+		"timeRange": {"start": "-24h"},
+		"emails": ["ops@example.com"],
+		"ownerID": "0000000000000009"
+	}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST returned %d, want 201: %s", w.Code, w.Body)
+	}
+	var created reportResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != influxdb.Active || created.Links.Runs != "/api/v2/reports/"+created.ID.String()+"/runs" {
+		t.Fatalf("POST returned %+v", created)
+	}
+	if created.OwnerID != 3 {
+		t.Fatalf("expected the report to be owned by the user creating it, got %s", created.OwnerID)
+	}
+
+	w = do("POST", "/api/v2/reports", `{"orgID": "`+org.ID.String()+`", "name": "r", "dashboardID": "`+d.ID.String()+`", "every": "1s", "emails": ["ops@example.com"]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("POST of an invalid report returned %d, want 400: %s", w.Code, w.Body)
+	}
+
+	w = do("GET", "/api/v2/reports?org=o", "")
+	var reports getReportsResponse
+	if err := json.NewDecoder(w.Body).Decode(&reports); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports.Reports) != 1 || reports.Reports[0].ID != created.ID {
+		t.Fatalf("GET returned %+v, want the created report", reports)
+	}
+
+	w = do("PATCH", "/api/v2/reports/"+created.ID.String(), `{"status": "inactive", "latestScheduled": "2020-01-01T00:00:00Z"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200: %s", w.Code, w.Body)
+	}
+	var updated reportResponse
+	if err := json.NewDecoder(w.Body).Decode(&updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status != influxdb.Inactive || !updated.LatestScheduled.IsZero() {
+		t.Fatalf("PATCH returned %+v, want an inactive report whose schedule is unchanged", updated)
+	}
+
+	w = do("POST", "/api/v2/reports/"+created.ID.String()+"/runs", "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST runs returned %d, want 201: %s", w.Code, w.Body)
+	}
+	w = do("GET", "/api/v2/reports/"+created.ID.String()+"/runs", "")
+	var runs getReportRunsResponse
+	if err := json.NewDecoder(w.Body).Decode(&runs); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs.Runs) != 1 || !runs.Runs[0].Manual || runs.Runs[0].Links.Report != "/api/v2/reports/"+created.ID.String() {
+		t.Fatalf("GET runs returned %+v, want the manual run", runs)
+	}
+
+	if w := do("DELETE", "/api/v2/reports/"+created.ID.String(), ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE returned %d, want 204: %s", w.Code, w.Body)
+	}
+	if w := do("GET", "/api/v2/reports/"+created.ID.String(), ""); w.Code != http.StatusNotFound {
+		t.Fatalf("GET of a deleted report returned %d, want 404", w.Code)
+	}
+}
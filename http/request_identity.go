@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// routeOther is the route of the requests that no router matched. Labeling
+// them with their paths would let clients create any number of routes.
+const routeOther = "other"
+
+// requestIdentity is the matched route and authenticated identity of a
+// request. Middleware that runs outside of routing and authentication places
+// it on the request context so that it can be filled in once the request is
+// routed and authenticated.
+type requestIdentity struct {
+	Route           string
+	OrgID           platform.ID
+	UserID          platform.ID
+	AuthorizationID platform.ID
+
+	// session is the session authenticating the request, if any. Sessions
+	// are not bound to an organization, so the organization of the request
+	// is resolved from the request itself.
+	session *platform.Session
+}
+
+type requestIdentityCtxKey struct{}
+
+// withRequestIdentity returns the request identity carried by ctx, adding
+// one if ctx does not carry it yet.
+func withRequestIdentity(ctx context.Context) (context.Context, *requestIdentity) {
+	if id, ok := ctx.Value(requestIdentityCtxKey{}).(*requestIdentity); ok {
+		return ctx, id
+	}
+	id := &requestIdentity{}
+	return context.WithValue(ctx, requestIdentityCtxKey{}, id), id
+}
+
+// setRequestAuthorizer records the authenticated identity on the request
+// identity carried by ctx, if any.
+func setRequestAuthorizer(ctx context.Context, a platform.Authorizer) {
+	id, ok := ctx.Value(requestIdentityCtxKey{}).(*requestIdentity)
+	if !ok {
+		return
+	}
+
+	id.UserID = a.GetUserID()
+	switch a := a.(type) {
+	case *platform.Authorization:
+		id.AuthorizationID = a.ID
+		id.OrgID = a.OrgID
+	case *platform.Session:
+		id.session = a
+	}
+}
+
+// setRequestRoute records the route template matched by a router on the
+// request identity carried by ctx, if any.
+func setRequestRoute(ctx context.Context, route string) {
+	if id, ok := ctx.Value(requestIdentityCtxKey{}).(*requestIdentity); ok {
+		id.Route = route
+	}
+}
+
+// route returns the route template of the request. Requests of the paths the
+// Handler serves itself have their path as route, and requests that no router
+// matched have routeOther.
+func (id *requestIdentity) route(r *http.Request) string {
+	if id.Route != "" {
+		return id.Route
+	}
+	switch p := r.URL.Path; {
+	case p == MetricsPath, p == ReadyPath, p == HealthPath:
+		return p
+	case strings.HasPrefix(p, DebugPath):
+		return DebugPath
+	default:
+		return routeOther
+	}
+}
+
+// org returns the organization of the request. Requests authenticated by a
+// session are attributed to the organization named by their orgID parameter
+// or organization path, as long as the session holds permissions within it,
+// so that clients cannot label the metrics with arbitrary organizations.
+func (id *requestIdentity) org(r *http.Request) platform.ID {
+	if id.OrgID.Valid() || id.session == nil {
+		return id.OrgID
+	}
+
+	v := r.URL.Query().Get("orgID")
+	if rest := strings.TrimPrefix(r.URL.Path, organizationsPath+"/"); v == "" && rest != r.URL.Path {
+		v = strings.SplitN(rest, "/", 2)[0]
+	}
+	orgID, err := platform.IDFromString(v)
+	if err != nil {
+		return 0
+	}
+
+	for _, p := range id.session.Permissions {
+		if p.Resource.OrgID != nil && *p.Resource.OrgID == *orgID ||
+			p.Resource.Type == platform.OrgsResourceType && p.Resource.ID != nil && *p.Resource.ID == *orgID {
+			return *orgID
+		}
+	}
+	return 0
+}
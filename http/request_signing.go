@@ -0,0 +1,277 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// Requests may be signed with the token of an authorization instead of
+// sending the token, so that tokens never traverse the wire in plaintext.
+// The Authorization header of a signed request is
+//
+//	INFLUX-HMAC-SHA256 Credential=<authorization ID>, Timestamp=<unix seconds>, Nonce=<nonce>, Signature=<hex>
+//
+// where the signature is the HMAC-SHA256, keyed with the token, of the
+// newline separated method, escaped path, sorted query, timestamp, nonce and
+// hex SHA-256 of the body of the request.
+const (
+	signatureScheme = "INFLUX-HMAC-SHA256"
+
+	// maxSignatureSkew is how far the timestamp of a signed request may be
+	// from the time of the server. Signatures are remembered for twice as
+	// long to reject replayed requests.
+	maxSignatureSkew = 5 * time.Minute
+
+	// maxNonceLength is the maximum length of the nonce of a signed request.
+	maxNonceLength = 128
+)
+
+var (
+	errSignatureMalformed = errors.New("malformed request signature")
+	errSignatureExpired   = errors.New("request signature timestamp is outside the accepted window")
+	errSignatureMismatch  = errors.New("request signature does not match")
+	errSignatureReplayed  = errors.New("request signature was already used")
+)
+
+// requestSignature is the parsed Authorization header of a signed request.
+type requestSignature struct {
+	authID    platform.ID
+	timestamp int64
+	nonce     string
+	signature []byte
+}
+
+// hasRequestSignature returns whether the request is signed.
+func hasRequestSignature(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), signatureScheme+" ")
+}
+
+func parseRequestSignature(header string) (*requestSignature, error) {
+	if !strings.HasPrefix(header, signatureScheme+" ") {
+		return nil, ErrAuthBadScheme
+	}
+
+	params := make(map[string]string)
+	for _, kv := range strings.Split(header[len(signatureScheme)+1:], ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 {
+			return nil, errSignatureMalformed
+		}
+		params[parts[0]] = parts[1]
+	}
+
+	sig := &requestSignature{nonce: params["Nonce"]}
+	if err := sig.authID.DecodeFromString(params["Credential"]); err != nil {
+		return nil, errSignatureMalformed
+	}
+	ts, err := strconv.ParseInt(params["Timestamp"], 10, 64)
+	if err != nil {
+		return nil, errSignatureMalformed
+	}
+	sig.timestamp = ts
+	if sig.signature, err = hex.DecodeString(params["Signature"]); err != nil || len(sig.signature) != sha256.Size {
+		return nil, errSignatureMalformed
+	}
+	if sig.nonce == "" || len(sig.nonce) > maxNonceLength {
+		return nil, errSignatureMalformed
+	}
+	return sig, nil
+}
+
+// stringToSign returns the canonical form of the request that is signed.
+func stringToSign(r *http.Request, timestamp int64, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+func computeSignature(token, s string) []byte {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// readBody reads the body of the request and replaces it with a reader of
+// the data read.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// SignRequest signs the request with the token of the authorization of the
+// ID. The token itself is not sent. The body of the request is read into
+// memory to be hashed.
+func SignRequest(req *http.Request, authID platform.ID, token string) error {
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(b[:])
+	ts := time.Now().Unix()
+
+	sig := computeSignature(token, stringToSign(req, ts, nonce, body))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s, Timestamp=%d, Nonce=%s, Signature=%s",
+		signatureScheme, authID, ts, nonce, hex.EncodeToString(sig)))
+	return nil
+}
+
+// signatureCache remembers the signatures of recent requests to reject
+// replayed requests.
+type signatureCache struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time // The expiration of the signatures.
+	lastSweep time.Time
+}
+
+func newSignatureCache() *signatureCache {
+	return &signatureCache{seen: make(map[string]time.Time)}
+}
+
+// used returns true if the signature was already used.
+func (c *signatureCache) used(sig []byte, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	exp, ok := c.seen[string(sig)]
+	return ok && !now.After(exp)
+}
+
+// add adds the signature and returns false if it was already used.
+func (c *signatureCache) add(sig []byte, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) > maxSignatureSkew {
+		for k, exp := range c.seen {
+			if now.After(exp) {
+				delete(c.seen, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	k := string(sig)
+	if exp, ok := c.seen[k]; ok && !now.After(exp) {
+		return false
+	}
+	c.seen[k] = now.Add(2 * maxSignatureSkew)
+	return true
+}
+
+// extractSignedAuthorization verifies the signature of the request with the
+// token of the authorization of its credential. The header is checked before
+// the body is read, and the body is read up to the body limit of the request,
+// so that unauthenticated clients cannot make the server buffer large bodies.
+func (h *AuthenticationHandler) extractSignedAuthorization(r *http.Request) (platform.Authorizer, error) {
+	ctx := r.Context()
+	sig, err := parseRequestSignature(r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if d := now.Sub(time.Unix(sig.timestamp, 0)); d > maxSignatureSkew || d < -maxSignatureSkew {
+		return nil, errSignatureExpired
+	}
+	if h.signatures.used(sig.signature, now) {
+		return nil, errSignatureReplayed
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, sig.authID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Token == "" {
+		return nil, errSignatureMismatch
+	}
+
+	body, err := readLimitedBody(r, h.signedBodyLimit(r))
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(sig.signature, computeSignature(a.Token, stringToSign(r, sig.timestamp, sig.nonce, body))) {
+		return nil, errSignatureMismatch
+	}
+
+	if !h.signatures.add(sig.signature, now) {
+		return nil, errSignatureReplayed
+	}
+	return a, nil
+}
+
+// signedBodyLimit returns the maximum size of the body of the signed request:
+// the body limit of its route, or the write body limit for writes. Bodies of
+// signed requests are always limited, as they are read into memory.
+func (h *AuthenticationHandler) signedBodyLimit(r *http.Request) int64 {
+	routes, _ := h.BodyLimit.routeLimits()
+	if limit, _ := h.BodyLimit.limitOf(routes, r.URL.Path); limit > 0 {
+		return limit
+	}
+	if r.URL.Path == writePath {
+		if limit := h.WriteLimits.MaxBodyBytes(); limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxBodyBytes
+}
+
+// readLimitedBody reads the body of the request up to limit bytes and
+// replaces it with a reader of the data read. Bodies larger than the limit
+// are rejected with ERequestTooLarge.
+func readLimitedBody(r *http.Request, limit int64) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	tooLarge := &platform.Error{
+		Code: platform.ERequestTooLarge,
+		Msg:  fmt.Sprintf("signed request body exceeds the maximum of %d bytes", limit),
+	}
+	if r.ContentLength > limit {
+		return nil, tooLarge
+	}
+
+	// read one byte past the limit to detect oversized bodies.
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, tooLarge
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func newSigningTestHandler(t *testing.T, body *string) *AuthenticationHandler {
+	auths := mock.NewAuthorizationService()
+	auths.FindAuthorizationByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
+		if id != 1 {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "authorization not found"}
+		}
+		return &influxdb.Authorization{ID: 1, Token: "secret", Status: influxdb.Active}, nil
+	}
+
+	h := NewAuthenticationHandler(ErrorHandler(0))
+	h.AuthorizationService = auths
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := pcontext.GetAuthorizer(r.Context())
+		if err != nil || a.Identifier() != 1 {
+			t.Errorf("unexpected authorizer %v: %v", a, err)
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return h
+}
+
+func TestAuthenticationHandler_SignedRequests(t *testing.T) {
+	var body string
+	h := newSigningTestHandler(t, &body)
+
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest("POST", "http://any.url/api/v2/write?org=a&bucket=b", strings.NewReader(body))
+	}
+	do := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	r := newRequest("m f=1")
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(r.Header.Get("Authorization"), "secret") {
+		t.Fatalf("token sent with the signed request: %s", r.Header.Get("Authorization"))
+	}
+	replay := r.Header.Get("Authorization")
+	if code := do(r); code != http.StatusNoContent || body != "m f=1" {
+		t.Fatalf("signed request returned %d with body %q; want 204 with the body of the request", code, body)
+	}
+
+	r = newRequest("m f=1")
+	r.Header.Set("Authorization", replay)
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("replayed request returned %d, want 401", code)
+	}
+
+	r = newRequest("m f=1")
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	r.Body = ioutil.NopCloser(strings.NewReader("m f=2"))
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request with a tampered body returned %d, want 401", code)
+	}
+
+	r = newRequest("m f=1")
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	r.URL.RawQuery = "org=a&bucket=c"
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request with a tampered query returned %d, want 401", code)
+	}
+
+	r = newRequest("m f=1")
+	if err := SignRequest(r, 1, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request signed with another token returned %d, want 401", code)
+	}
+
+	r = newRequest("m f=1")
+	ts := time.Now().Add(-2 * maxSignatureSkew).Unix()
+	sig := computeSignature("secret", stringToSign(r, ts, "nonce", []byte("m f=1")))
+	r.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s, Timestamp=%d, Nonce=nonce, Signature=%s",
+		signatureScheme, influxdb.ID(1), ts, hex.EncodeToString(sig)))
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request with an expired timestamp returned %d, want 401", code)
+	}
+
+	r = newRequest("m f=1")
+	r.Header.Set("Authorization", signatureScheme+" Credential=0000000000000001")
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request with a malformed signature returned %d, want 401", code)
+	}
+}
+
+// unreadBody fails the test if the body of a request is read.
+type unreadBody struct {
+	t *testing.T
+}
+
+func (b unreadBody) Read(p []byte) (int, error) {
+	b.t.Error("body read before the credential of the request was checked")
+	return 0, fmt.Errorf("unexpected read")
+}
+
+func (b unreadBody) Close() error { return nil }
+
+func TestAuthenticationHandler_SignedRequestBody(t *testing.T) {
+	var body string
+	h := newSigningTestHandler(t, &body)
+	h.WriteLimits = &WriteLimits{}
+	h.WriteLimits.SetMaxBodyBytes(8)
+
+	do := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// the body of a request with an unknown credential is not read.
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/write?org=a&bucket=b", nil)
+	ts := time.Now().Unix()
+	r.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s, Timestamp=%d, Nonce=nonce, Signature=%s",
+		signatureScheme, influxdb.ID(2), ts, strings.Repeat("00", 32)))
+	r.Body = unreadBody{t: t}
+	if code := do(r); code != http.StatusUnauthorized {
+		t.Errorf("request with an unknown credential returned %d, want 401", code)
+	}
+
+	// the body of a signed write is limited by the write body limit.
+	r = httptest.NewRequest("POST", "http://any.url/api/v2/write?org=a&bucket=b", strings.NewReader("m f=1,g=2"))
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	r.ContentLength = -1
+	if code := do(r); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("signed write over the body limit returned %d, want 413", code)
+	}
+
+	// the body of other signed requests is limited by the body limit of their route.
+	h.BodyLimit = BodyLimitConfig{Routes: []string{"/api/v2/dashboards=4"}}
+	r = httptest.NewRequest("POST", "http://any.url/api/v2/dashboards", strings.NewReader(`{"name":"d"}`))
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if code := do(r); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("signed request over the route body limit returned %d, want 413", code)
+	}
+
+	r = httptest.NewRequest("POST", "http://any.url/api/v2/write?org=a&bucket=b", strings.NewReader("m f=1"))
+	if err := SignRequest(r, 1, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if code := do(r); code != http.StatusNoContent || body != "m f=1" {
+		t.Errorf("signed write within the body limit returned %d with body %q; want 204", code, body)
+	}
+}
+
+func TestWriteService_SignedWrite(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(newSigningTestHandler(t, &body))
+	defer ts.Close()
+
+	s := &WriteService{Addr: ts.URL, Token: "secret", AuthorizationID: 1}
+	if err := s.Write(context.Background(), 2, 3, strings.NewReader("m f=1")); err != nil {
+		t.Fatal(err)
+	}
+	// the body is compressed by the write service.
+	if body == "" {
+		t.Error("expected the body of the write to be received")
+	}
+}
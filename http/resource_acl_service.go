@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// ResourceACLBackend is all services and associated parameters required to
+// construct the handlers of the permissions of a resource.
+type ResourceACLBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+	ResourceACLService influxdb.ResourceACLService
+	ResourceType       influxdb.ResourceType
+}
+
+type resourceACLResponse struct {
+	Links        map[string]string        `json:"links"`
+	ResourceType influxdb.ResourceType    `json:"resourceType"`
+	ResourceID   influxdb.ID              `json:"resourceID"`
+	Restricted   bool                     `json:"restricted"`
+	Grants       []influxdb.ResourceGrant `json:"grants"`
+	UpdatedAt    *time.Time               `json:"updatedAt,omitempty"`
+}
+
+func newResourceACLResponse(acl *influxdb.ResourceACL) *resourceACLResponse {
+	resource := fmt.Sprintf("/api/v2/%s/%s", acl.ResourceType, acl.ResourceID)
+	res := &resourceACLResponse{
+		Links: map[string]string{
+			"self":     resource + "/permissions",
+			"resource": resource,
+		},
+		ResourceType: acl.ResourceType,
+		ResourceID:   acl.ResourceID,
+		Restricted:   acl.Restricted(),
+		Grants:       acl.Grants,
+	}
+	if res.Grants == nil {
+		res.Grants = []influxdb.ResourceGrant{}
+	}
+	if !acl.UpdatedAt.IsZero() {
+		res.UpdatedAt = &acl.UpdatedAt
+	}
+	return res
+}
+
+// newGetResourceACLHandler returns a handler func for a GET to /permissions endpoints
+func newGetResourceACLHandler(b *ResourceACLBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := decodeResourceACLID(ctx)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		acl, err := b.ResourceACLService.FindResourceACL(ctx, b.ResourceType, id)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := encodeResponse(ctx, w, http.StatusOK, newResourceACLResponse(acl)); err != nil {
+			logEncodingError(b.Logger, r, err)
+			return
+		}
+	}
+}
+
+// newPutResourceACLHandler returns a handler func for a PUT to /permissions endpoints
+func newPutResourceACLHandler(b *ResourceACLBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		acl, err := decodePutResourceACLRequest(ctx, r, b.ResourceType)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := b.ResourceACLService.PutResourceACL(ctx, acl); err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+		b.Logger.Debug("resource permissions updated", zap.String("resource", string(b.ResourceType)), zap.String("id", acl.ResourceID.String()))
+
+		if err := encodeResponse(ctx, w, http.StatusOK, newResourceACLResponse(acl)); err != nil {
+			logEncodingError(b.Logger, r, err)
+			return
+		}
+	}
+}
+
+// newDeleteResourceACLHandler returns a handler func for a DELETE to /permissions endpoints
+func newDeleteResourceACLHandler(b *ResourceACLBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := decodeResourceACLID(ctx)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := b.ResourceACLService.DeleteResourceACL(ctx, b.ResourceType, id); err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+		b.Logger.Debug("resource permissions deleted", zap.String("resource", string(b.ResourceType)), zap.String("id", id.String()))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func decodeResourceACLID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+type putResourceACLRequest struct {
+	Grants []influxdb.ResourceGrant `json:"grants"`
+}
+
+func decodePutResourceACLRequest(ctx context.Context, r *http.Request, rt influxdb.ResourceType) (*influxdb.ResourceACL, error) {
+	id, err := decodeResourceACLID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &putResourceACLRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	acl := &influxdb.ResourceACL{
+		ResourceType: rt,
+		ResourceID:   id,
+		Grants:       req.Grants,
+	}
+	if err := acl.Valid(); err != nil {
+		return nil, err
+	}
+	return acl, nil
+}
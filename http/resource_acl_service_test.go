@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/pkg/testttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHandler_Permissions(t *testing.T) {
+	dashboardID, userID := influxdb.ID(1), influxdb.ID(2)
+	var stored *influxdb.ResourceACL
+
+	acls := mock.NewResourceACLService()
+	acls.FindResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+		if stored == nil {
+			return &influxdb.ResourceACL{ResourceType: rt, ResourceID: id}, nil
+		}
+		return stored, nil
+	}
+	acls.PutResourceACLFn = func(ctx context.Context, acl *influxdb.ResourceACL) error {
+		stored = acl
+		return nil
+	}
+	acls.DeleteResourceACLFn = func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error {
+		stored = nil
+		return nil
+	}
+
+	b := NewMockDashboardBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.ResourceACLService = acls
+	h := NewDashboardHandler(b)
+
+	addr := "/api/v2/dashboards/" + dashboardID.String() + "/permissions"
+	decode := func(body *bytes.Buffer) resourceACLResponse {
+		var res resourceACLResponse
+		require.NoError(t, json.NewDecoder(body).Decode(&res))
+		return res
+	}
+
+	testttp.Get(addr).
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			res := decode(body)
+			require.False(t, res.Restricted)
+			require.Empty(t, res.Grants)
+		})
+
+	body := `{"grants":[{"userID":"` + userID.String() + `","access":"edit"},{"access":"view"}]}`
+	testttp.Put(addr, strings.NewReader(body)).
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			res := decode(body)
+			require.True(t, res.Restricted)
+			require.Equal(t, influxdb.DashboardsResourceType, res.ResourceType)
+			require.Equal(t, dashboardID, res.ResourceID)
+			require.Len(t, res.Grants, 2)
+		})
+	require.NotNil(t, stored)
+	require.True(t, stored.Allowed(userID, influxdb.WriteAction))
+	require.False(t, stored.Allowed(userID+1, influxdb.WriteAction))
+
+	testttp.Put(addr, strings.NewReader(`{"grants":[{"access":"owner"}]}`)).
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+
+	testttp.Delete(addr).
+		Do(h).
+		ExpectStatus(t, http.StatusNoContent)
+	require.Nil(t, stored)
+}
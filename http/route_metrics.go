@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// routeMetrics are request metrics labeled by route template, so that the
+// latency and response sizes of individual APIs can be monitored without a
+// series per resource ID.
+type routeMetrics struct {
+	duration     *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	authFailures *prometheus.CounterVec
+	rateLimited  *prometheus.CounterVec
+}
+
+func newRouteMetrics() *routeMetrics {
+	const namespace = "http"
+	const subsystem = "api"
+
+	labels := []string{"handler", "method", "route", "status", "org"}
+	return &routeMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "route_request_duration_seconds",
+			Help:      "Time taken to respond to HTTP requests by route",
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "route_response_size_bytes",
+			Help:      "Size of HTTP response bodies by route",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 9),
+		}, labels),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "auth_failures_total",
+			Help:      "Number of HTTP requests rejected as unauthorized or forbidden",
+		}, []string{"handler", "route", "status"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rate_limited_total",
+			Help:      "Number of HTTP requests rejected by rate limits",
+		}, []string{"handler", "route", "org"}),
+	}
+}
+
+func (m *routeMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.duration,
+		m.responseSize,
+		m.authFailures,
+		m.rateLimited,
+	}
+}
+
+func (m *routeMetrics) observe(handler, method, route, org string, status, responseBytes int, took time.Duration) {
+	statusCode := strconv.Itoa(status)
+	labels := prometheus.Labels{
+		"handler": handler,
+		"method":  method,
+		"route":   route,
+		"status":  statusCode,
+		"org":     org,
+	}
+	m.duration.With(labels).Observe(took.Seconds())
+	m.responseSize.With(labels).Observe(float64(responseBytes))
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		m.authFailures.With(prometheus.Labels{
+			"handler": handler,
+			"route":   route,
+			"status":  statusCode,
+		}).Inc()
+	case http.StatusTooManyRequests:
+		m.rateLimited.With(prometheus.Labels{
+			"handler": handler,
+			"route":   route,
+			"org":     org,
+		}).Inc()
+	}
+}
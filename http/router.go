@@ -15,15 +15,35 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Router is a router that records the route matched by a request on its
+// request identity, so that requests can be labeled by route outside of it.
+type Router struct {
+	*httprouter.Router
+}
+
 // NewRouter returns a new router with a 404 handler, a 405 handler, and a panic handler.
-func NewRouter(h platform.HTTPErrorHandler) *httprouter.Router {
+func NewRouter(h platform.HTTPErrorHandler) *Router {
 	b := baseHandler{HTTPErrorHandler: h}
 	router := httprouter.New()
 	router.NotFound = http.HandlerFunc(b.notFound)
 	router.MethodNotAllowed = http.HandlerFunc(b.methodNotAllowed)
 	router.PanicHandler = b.panic
 	router.AddMatchedRouteToContext = true
-	return router
+	return &Router{Router: router}
+}
+
+// Handler registers the handler of the method and path.
+func (r *Router) Handler(method, path string, handler http.Handler) {
+	r.Router.Handler(method, path, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		setRequestRoute(ctx, httprouter.MatchedRouteFromContext(ctx))
+		handler.ServeHTTP(w, req)
+	}))
+}
+
+// HandlerFunc registers the handler function of the method and path.
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.Handler(method, path, handler)
 }
 
 func newBaseChiRouter(errorHandler platform.HTTPErrorHandler) chi.Router {
@@ -31,9 +51,25 @@ func newBaseChiRouter(errorHandler platform.HTTPErrorHandler) chi.Router {
 	bh := baseHandler{HTTPErrorHandler: errorHandler}
 	router.NotFound(bh.notFound)
 	router.MethodNotAllowed(bh.methodNotAllowed)
+	router.Use(recordChiRoute)
 	return router
 }
 
+// recordChiRoute records the route pattern matched by a chi router on the
+// request identity, unless a router of the matched handler recorded its own.
+func recordChiRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		ctx := r.Context()
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			if id, ok := ctx.Value(requestIdentityCtxKey{}).(*requestIdentity); ok && id.Route == "" {
+				id.Route = rctx.RoutePattern()
+			}
+		}
+	})
+}
+
 type baseHandler struct {
 	platform.HTTPErrorHandler
 }
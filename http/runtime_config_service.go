@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const runtimeConfigPath = "/api/v2/config/runtime"
+
+// RuntimeConfigBackend is all services and associated parameters required to construct
+// the RuntimeConfigHandler.
+type RuntimeConfigBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RuntimeConfigService influxdb.RuntimeConfigService
+}
+
+// NewRuntimeConfigBackend returns a new instance of RuntimeConfigBackend.
+func NewRuntimeConfigBackend(b *APIBackend) *RuntimeConfigBackend {
+	return &RuntimeConfigBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "runtime_config")),
+
+		RuntimeConfigService: b.RuntimeConfigService,
+	}
+}
+
+// RuntimeConfigHandler represents an HTTP API handler for the runtime config.
+type RuntimeConfigHandler struct {
+	*Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RuntimeConfigService influxdb.RuntimeConfigService
+}
+
+// NewRuntimeConfigHandler returns a new instance of RuntimeConfigHandler.
+func NewRuntimeConfigHandler(b *RuntimeConfigBackend) *RuntimeConfigHandler {
+	h := &RuntimeConfigHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		RuntimeConfigService: b.RuntimeConfigService,
+	}
+
+	h.HandlerFunc("GET", runtimeConfigPath, h.handleGetRuntimeConfig)
+	h.HandlerFunc("PATCH", runtimeConfigPath, h.handlePatchRuntimeConfig)
+	h.HandlerFunc("DELETE", runtimeConfigPath, h.handleDeleteRuntimeConfig)
+	return h
+}
+
+// handleGetRuntimeConfig is the HTTP handler for the GET /api/v2/config/runtime route.
+func (h *RuntimeConfigHandler) handleGetRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	c, err := h.RuntimeConfigService.FindRuntimeConfig(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, c); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchRuntimeConfig is the HTTP handler for the PATCH /api/v2/config/runtime route.
+func (h *RuntimeConfigHandler) handlePatchRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var upd influxdb.RuntimeConfigUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid runtime config update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	c, err := h.RuntimeConfigService.UpdateRuntimeConfig(ctx, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Info("Runtime config updated", zap.Any("config", c))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, c); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRuntimeConfig is the HTTP handler for the DELETE /api/v2/config/runtime route.
+func (h *RuntimeConfigHandler) handleDeleteRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	c, err := h.RuntimeConfigService.ResetRuntimeConfig(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Info("Runtime config reset", zap.Any("config", c))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, c); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestRuntimeConfigHandler(t *testing.T) {
+	configured := influxdb.RuntimeConfig{
+		LogLevel:                  "info",
+		QueryConcurrency:          10,
+		CompactionThroughputBurst: 1024,
+	}
+	current := configured
+
+	svc := mock.NewRuntimeConfigService()
+	svc.FindRuntimeConfigFn = func(context.Context) (*influxdb.RuntimeConfig, error) {
+		c := current
+		return &c, nil
+	}
+	svc.UpdateRuntimeConfigFn = func(_ context.Context, upd influxdb.RuntimeConfigUpdate) (*influxdb.RuntimeConfig, error) {
+		c := current
+		upd.Apply(&c)
+		if err := c.Valid(); err != nil {
+			return nil, err
+		}
+		current = c
+		return &c, nil
+	}
+	svc.ResetRuntimeConfigFn = func(context.Context) (*influxdb.RuntimeConfig, error) {
+		current = configured
+		c := current
+		return &c, nil
+	}
+
+	h := NewRuntimeConfigHandler(&RuntimeConfigBackend{
+		HTTPErrorHandler:     ErrorHandler(0),
+		Logger:               zap.NewNop(),
+		RuntimeConfigService: svc,
+	})
+
+	do := func(method, body string) (int, influxdb.RuntimeConfig) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url/api/v2/config/runtime", bytes.NewBufferString(body)))
+
+		var c influxdb.RuntimeConfig
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&c); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return w.Code, c
+	}
+
+	if code, c := do("GET", ""); code != http.StatusOK || c != current {
+		t.Fatalf("GET = %d %+v, want 200 %+v", code, c, current)
+	}
+
+	code, c := do("PATCH", `{"logLevel":"debug"}`)
+	if code != http.StatusOK {
+		t.Fatalf("PATCH returned %d, want 200", code)
+	}
+	if want := (influxdb.RuntimeConfig{LogLevel: "debug", QueryConcurrency: 10, CompactionThroughputBurst: 1024}); c != want {
+		t.Errorf("PATCH = %+v, want %+v", c, want)
+	}
+
+	if code, _ := do("PATCH", `{"queryConcurrency":0}`); code != http.StatusBadRequest {
+		t.Errorf("invalid PATCH returned %d, want 400", code)
+	}
+	if code, _ := do("PATCH", `{"logLevel":`); code != http.StatusBadRequest {
+		t.Errorf("malformed PATCH returned %d, want 400", code)
+	}
+
+	if code, c := do("DELETE", ""); code != http.StatusOK || c != configured {
+		t.Errorf("DELETE = %d %+v, want 200 %+v", code, c, configured)
+	}
+}
@@ -45,7 +45,7 @@ func NewScraperBackend(b *APIBackend) *ScraperBackend {
 
 // ScraperHandler represents an HTTP API handler for scraper targets.
 type ScraperHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger                     *zap.Logger
 	UserService                influxdb.UserService
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	seriesFilePath        = "/api/v2/storage/seriesfile"
+	seriesFileCompactPath = "/api/v2/storage/seriesfile/compact"
+	seriesFileVerifyPath  = "/api/v2/storage/seriesfile/verify"
+)
+
+// SeriesFileBackend is all services and associated parameters required to construct
+// the SeriesFileHandler.
+type SeriesFileBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SeriesFileService influxdb.SeriesFileService
+}
+
+// NewSeriesFileBackend returns a new instance of SeriesFileBackend.
+func NewSeriesFileBackend(b *APIBackend) *SeriesFileBackend {
+	return &SeriesFileBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "series_file")),
+
+		SeriesFileService: b.SeriesFileService,
+	}
+}
+
+// SeriesFileHandler represents an HTTP API handler for the series file of the storage engine.
+type SeriesFileHandler struct {
+	*Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SeriesFileService influxdb.SeriesFileService
+}
+
+// NewSeriesFileHandler returns a new instance of SeriesFileHandler.
+func NewSeriesFileHandler(b *SeriesFileBackend) *SeriesFileHandler {
+	h := &SeriesFileHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		SeriesFileService: b.SeriesFileService,
+	}
+
+	h.HandlerFunc("GET", seriesFilePath, h.handleGetSeriesFileStats)
+	h.HandlerFunc("POST", seriesFileCompactPath, h.handlePostSeriesFileCompact)
+	h.HandlerFunc("POST", seriesFileVerifyPath, h.handlePostSeriesFileVerify)
+	return h
+}
+
+// handleGetSeriesFileStats is the HTTP handler for the GET /api/v2/storage/seriesfile route.
+func (h *SeriesFileHandler) handleGetSeriesFileStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	stats, err := h.SeriesFileService.FindSeriesFileStats(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, stats); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostSeriesFileCompact is the HTTP handler for the POST /api/v2/storage/seriesfile/compact route.
+func (h *SeriesFileHandler) handlePostSeriesFileCompact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.SeriesFileService.CompactSeriesFile(ctx); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Info("Series file compacted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePostSeriesFileVerify is the HTTP handler for the POST /api/v2/storage/seriesfile/verify route.
+func (h *SeriesFileHandler) handlePostSeriesFileVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var repair bool
+	if s := r.URL.Query().Get("repair"); s != "" {
+		var err error
+		if repair, err = strconv.ParseBool(s); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid repair parameter",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	v, err := h.SeriesFileService.VerifySeriesFile(ctx, repair)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if !v.Valid {
+		h.Logger.Warn("Series file verification found errors", zap.Bool("repair", repair))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, v); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestSeriesFileHandler(t *testing.T) {
+	stats := &influxdb.SeriesFileStats{
+		Series:     10,
+		Tombstones: 2,
+		DiskBytes:  4096,
+		Partitions: []influxdb.SeriesFilePartitionStats{
+			{ID: 0, Series: 10, Tombstones: 2, TombstoneRatio: 2.0 / 12, DiskBytes: 4096, Segments: 1},
+		},
+	}
+
+	var compacted bool
+	var repaired []bool
+	svc := mock.NewSeriesFileService()
+	svc.FindSeriesFileStatsFn = func(context.Context) (*influxdb.SeriesFileStats, error) {
+		return stats, nil
+	}
+	svc.CompactSeriesFileFn = func(context.Context) error {
+		compacted = true
+		return nil
+	}
+	svc.VerifySeriesFileFn = func(_ context.Context, repair bool) (*influxdb.SeriesFileVerification, error) {
+		repaired = append(repaired, repair)
+		return &influxdb.SeriesFileVerification{
+			Valid:      true,
+			Partitions: []influxdb.SeriesFilePartitionVerification{{ID: 0, Valid: true, Entries: 12}},
+		}, nil
+	}
+
+	h := NewSeriesFileHandler(&SeriesFileBackend{
+		HTTPErrorHandler:  ErrorHandler(0),
+		Logger:            zap.NewNop(),
+		SeriesFileService: svc,
+	})
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://any.url"+path, nil))
+		return w
+	}
+
+	w := do("GET", "/api/v2/storage/seriesfile")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d, want 200", w.Code)
+	}
+	var gotStats influxdb.SeriesFileStats
+	if err := json.NewDecoder(w.Body).Decode(&gotStats); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&gotStats, stats); diff != "" {
+		t.Errorf("unexpected stats -got/+want\n%s", diff)
+	}
+
+	if w := do("POST", "/api/v2/storage/seriesfile/compact"); w.Code != http.StatusNoContent || !compacted {
+		t.Errorf("compact returned %d, compacted %v; want 204, true", w.Code, compacted)
+	}
+
+	if w := do("POST", "/api/v2/storage/seriesfile/verify"); w.Code != http.StatusOK {
+		t.Errorf("verify returned %d, want 200", w.Code)
+	}
+	if w := do("POST", "/api/v2/storage/seriesfile/verify?repair=true"); w.Code != http.StatusOK {
+		t.Errorf("verify with repair returned %d, want 200", w.Code)
+	}
+	if diff := cmp.Diff(repaired, []bool{false, true}); diff != "" {
+		t.Errorf("unexpected repair arguments -got/+want\n%s", diff)
+	}
+
+	if w := do("POST", "/api/v2/storage/seriesfile/verify?repair=maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("verify with invalid repair returned %d, want 400", w.Code)
+	}
+}
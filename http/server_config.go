@@ -0,0 +1,151 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/net/netutil"
+)
+
+const (
+	// DefaultReadHeaderTimeout is how long a client may take to send the
+	// headers of a request. Bodies are not limited by default, since large
+	// writes from slow clients are legitimate.
+	DefaultReadHeaderTimeout = 10 * time.Second
+
+	// DefaultIdleTimeout is how long an idle keep-alive connection is kept open.
+	DefaultIdleTimeout = 3 * time.Minute
+
+	// DefaultTCPKeepAlive is the period of the TCP keep-alive probes of
+	// accepted connections.
+	DefaultTCPKeepAlive = 3 * time.Minute
+
+	// DefaultMaxConcurrentStreams is the number of concurrent HTTP/2 streams
+	// a client may open on a connection.
+	DefaultMaxConcurrentStreams = 250
+)
+
+// ServerConfig configures the connections of the HTTP server.
+type ServerConfig struct {
+	// MaxConnections is the maximum number of simultaneous connections
+	// accepted on each listener; 0 means unlimited.
+	MaxConnections int
+	// ReadHeaderTimeout is how long a client may take to send the headers of
+	// a request; 0 means unlimited.
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout is how long a client may take to send a whole request; 0
+	// means unlimited.
+	ReadTimeout time.Duration
+	// WriteTimeout is how long the server may take to write a response; 0
+	// means unlimited.
+	WriteTimeout time.Duration
+	// IdleTimeout is how long an idle keep-alive connection is kept open; 0
+	// means the read timeout applies.
+	IdleTimeout time.Duration
+	// KeepAlivesDisabled closes connections after every request.
+	KeepAlivesDisabled bool
+	// TCPKeepAlive is the period of the TCP keep-alive probes of accepted TCP
+	// connections; 0 disables the probes.
+	TCPKeepAlive time.Duration
+	// MaxHeaderBytes is the maximum size in bytes of the headers of a
+	// request; 0 means http.DefaultMaxHeaderBytes.
+	MaxHeaderBytes int
+	// H2C serves HTTP/2 without TLS to clients that ask for it, with prior
+	// knowledge or an h2c upgrade. HTTP/2 is always served over TLS.
+	H2C bool
+	// MaxConcurrentStreams is the number of concurrent HTTP/2 streams a
+	// client may open on a connection.
+	MaxConcurrentStreams int
+}
+
+// NewServerConfig returns a ServerConfig with default values.
+func NewServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadHeaderTimeout:    DefaultReadHeaderTimeout,
+		IdleTimeout:          DefaultIdleTimeout,
+		TCPKeepAlive:         DefaultTCPKeepAlive,
+		MaxHeaderBytes:       http.DefaultMaxHeaderBytes,
+		MaxConcurrentStreams: DefaultMaxConcurrentStreams,
+	}
+}
+
+// Valid returns an error if a setting is negative.
+func (c ServerConfig) Valid() error {
+	for _, v := range []struct {
+		name  string
+		value int64
+	}{
+		{"max connections", int64(c.MaxConnections)},
+		{"read header timeout", int64(c.ReadHeaderTimeout)},
+		{"read timeout", int64(c.ReadTimeout)},
+		{"write timeout", int64(c.WriteTimeout)},
+		{"idle timeout", int64(c.IdleTimeout)},
+		{"tcp keep-alive", int64(c.TCPKeepAlive)},
+		{"max header bytes", int64(c.MaxHeaderBytes)},
+		{"max concurrent streams", int64(c.MaxConcurrentStreams)},
+	} {
+		if v.value < 0 {
+			return fmt.Errorf("http %s must not be negative", v.name)
+		}
+	}
+	return nil
+}
+
+// Apply configures the server. It must be called after the handler and TLS
+// config of the server are set, and before the server is started.
+func (c ServerConfig) Apply(srv *http.Server) error {
+	srv.ReadHeaderTimeout = c.ReadHeaderTimeout
+	srv.ReadTimeout = c.ReadTimeout
+	srv.WriteTimeout = c.WriteTimeout
+	srv.IdleTimeout = c.IdleTimeout
+	srv.MaxHeaderBytes = c.MaxHeaderBytes
+	srv.SetKeepAlivesEnabled(!c.KeepAlivesDisabled)
+
+	h2s := &http2.Server{
+		MaxConcurrentStreams: uint32(c.MaxConcurrentStreams),
+		IdleTimeout:          c.IdleTimeout,
+	}
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		return err
+	}
+	if c.H2C {
+		srv.Handler = h2c.NewHandler(srv.Handler, h2s)
+	}
+	return nil
+}
+
+// Listener returns the listener accepting the connections of ln with the
+// connection limit and TCP keep-alives of the config.
+func (c ServerConfig) Listener(ln net.Listener) net.Listener {
+	if tcp, ok := ln.(*net.TCPListener); ok {
+		ln = &tcpKeepAliveListener{TCPListener: tcp, period: c.TCPKeepAlive}
+	}
+	if c.MaxConnections > 0 {
+		ln = netutil.LimitListener(ln, c.MaxConnections)
+	}
+	return ln
+}
+
+// tcpKeepAliveListener sets the TCP keep-alive period of accepted connections.
+type tcpKeepAliveListener struct {
+	*net.TCPListener
+	period time.Duration
+}
+
+func (ln *tcpKeepAliveListener) Accept() (net.Conn, error) {
+	conn, err := ln.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	if ln.period > 0 {
+		conn.SetKeepAlive(true)
+		conn.SetKeepAlivePeriod(ln.period)
+	} else {
+		conn.SetKeepAlive(false)
+	}
+	return conn, nil
+}
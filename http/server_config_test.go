@@ -0,0 +1,75 @@
+package http
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+func TestServerConfig_Valid(t *testing.T) {
+	c := NewServerConfig()
+	if err := c.Valid(); err != nil {
+		t.Fatalf("unexpected error for the default config: %v", err)
+	}
+	c.IdleTimeout = -time.Second
+	if err := c.Valid(); err == nil {
+		t.Fatal("expected an error for a negative idle timeout")
+	}
+}
+
+func TestServerConfig_Apply(t *testing.T) {
+	newServer := func(c ServerConfig) *httptest.Server {
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Proto))
+		}))
+		if err := c.Apply(ts.Config); err != nil {
+			t.Fatal(err)
+		}
+		ts.Listener = c.Listener(ts.Listener)
+		ts.Start()
+		return ts
+	}
+	// h2c clients connect with prior knowledge of HTTP/2.
+	h2cClient := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	c := NewServerConfig()
+	c.H2C = true
+	ts := newServer(c)
+	defer ts.Close()
+
+	if ts.Config.ReadHeaderTimeout != DefaultReadHeaderTimeout || ts.Config.IdleTimeout != DefaultIdleTimeout {
+		t.Fatalf("unexpected timeouts of the server: %+v", ts.Config)
+	}
+	resp, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.ProtoMajor != 1 {
+		t.Fatalf("expected an HTTP/1 response to an HTTP/1 client, got %s", resp.Proto)
+	}
+	resp, err = h2cClient.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected an HTTP/2 response with h2c, got %s", resp.Proto)
+	}
+
+	ts = newServer(NewServerConfig())
+	defer ts.Close()
+	if _, err := h2cClient.Get(ts.URL); err == nil {
+		t.Fatal("expected HTTP/2 without TLS to fail without h2c")
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// Default lengths of the prefixes of the networks sessions are bound to, so
+// that clients moving to another address of their network keep their
+// sessions.
+const (
+	DefaultSessionFingerprintIPv4Prefix = 24
+	DefaultSessionFingerprintIPv6Prefix = 64
+)
+
+// errSessionFingerprintMismatch is returned for requests of a session from
+// another client than the one the session is bound to.
+var errSessionFingerprintMismatch = errors.New("session is bound to another client")
+
+// SessionFingerprinter computes the fingerprints of the clients sessions are
+// bound to, made of the user agent and the network of the address of the
+// client.
+type SessionFingerprinter struct {
+	// IPv4Prefix and IPv6Prefix are the lengths of the prefixes of the
+	// networks of IPv4 and IPv6 clients.
+	IPv4Prefix int
+	IPv6Prefix int
+}
+
+// Fingerprint returns the fingerprint of the client of the request.
+func (f *SessionFingerprinter) Fingerprint(r *http.Request) string {
+	network := remoteIP(r)
+	if ip := net.ParseIP(network); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			network = ip4.Mask(net.CIDRMask(f.IPv4Prefix, 8*net.IPv4len)).String()
+		} else {
+			network = ip.Mask(net.CIDRMask(f.IPv6Prefix, 8*net.IPv6len)).String()
+		}
+	}
+	sum := sha256.Sum256([]byte(r.UserAgent() + "\n" + network))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkSessionFingerprint returns an error if the session is bound to another
+// client than the client of the request, and records an alert for the user
+// of the session. Sessions created before binding was enabled are not bound.
+func (h *AuthenticationHandler) checkSessionFingerprint(ctx context.Context, r *http.Request, s *platform.Session) error {
+	if h.SessionFingerprinter == nil || s.Fingerprint == "" || s.Fingerprint == h.SessionFingerprinter.Fingerprint(r) {
+		return nil
+	}
+
+	h.Logger.Warn("Rejected a session used from another client",
+		zap.String("session_id", s.ID.String()),
+		zap.String("user_id", s.UserID.String()),
+		zap.String("remote_ip", remoteIP(r)))
+	if h.SessionAlertService != nil {
+		if err := h.SessionAlertService.CreateSessionAlert(ctx, &platform.SessionAlert{
+			UserID:    s.UserID,
+			SessionID: s.ID,
+			RemoteIP:  remoteIP(r),
+			UserAgent: r.UserAgent(),
+		}); err != nil {
+			h.Logger.Error("Unable to record the session alert", zap.Error(err))
+		}
+	}
+	return errSessionFingerprintMismatch
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSessionFingerprinter_Fingerprint(t *testing.T) {
+	f := &SessionFingerprinter{
+		IPv4Prefix: DefaultSessionFingerprintIPv4Prefix,
+		IPv6Prefix: DefaultSessionFingerprintIPv6Prefix,
+	}
+	fingerprint := func(addr, ua string) string {
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/me", nil)
+		r.RemoteAddr = addr
+		r.Header.Set("User-Agent", ua)
+		return f.Fingerprint(r)
+	}
+
+	tests := []struct {
+		name  string
+		addr  string
+		ua    string
+		equal bool
+	}{
+		{name: "same ipv4 network", addr: "10.0.0.77:1234", ua: "browser", equal: true},
+		{name: "other ipv4 network", addr: "10.0.1.5:1234", ua: "browser"},
+		{name: "other user agent", addr: "10.0.0.5:1234", ua: "curl"},
+	}
+	want := fingerprint("10.0.0.5:4321", "browser")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fingerprint(tt.addr, tt.ua); (got == want) != tt.equal {
+				t.Errorf("expected fingerprints equal to be %v", tt.equal)
+			}
+		})
+	}
+
+	if fingerprint("[2001:db8::1]:1234", "browser") != fingerprint("[2001:db8::ffff]:1234", "browser") {
+		t.Error("expected fingerprints of the same ipv6 network to be equal")
+	}
+	if fingerprint("[2001:db8::1]:1234", "browser") == fingerprint("[2001:db8:0:1::1]:1234", "browser") {
+		t.Error("expected fingerprints of other ipv6 networks to differ")
+	}
+}
+
+func TestSessionFingerprintBinding(t *testing.T) {
+	fingerprinter := &SessionFingerprinter{
+		IPv4Prefix: DefaultSessionFingerprintIPv4Prefix,
+		IPv6Prefix: DefaultSessionFingerprintIPv6Prefix,
+	}
+
+	var stored *platform.Session
+	sessions := mock.NewSessionService()
+	sessions.CreateSessionFn = func(context.Context, string) (*platform.Session, error) {
+		return &platform.Session{
+			ID:        2,
+			Key:       "abc123xyz",
+			UserID:    1,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil
+	}
+	sessions.RenewSessionFn = func(ctx context.Context, s *platform.Session, expiresAt time.Time) error {
+		c := *s
+		stored = &c
+		return nil
+	}
+	sessions.FindSessionFn = func(context.Context, string) (*platform.Session, error) {
+		c := *stored
+		return &c, nil
+	}
+	users := mock.NewUserService()
+	users.FindUserFn = func(context.Context, platform.UserFilter) (*platform.User, error) {
+		return &platform.User{ID: 1}, nil
+	}
+	users.FindUserByIDFn = func(context.Context, platform.ID) (*platform.User, error) {
+		return &platform.User{ID: 1, Status: platform.Active}, nil
+	}
+	passwords := mock.NewPasswordsService()
+	passwords.ComparePasswordFn = func(context.Context, platform.ID, string) error {
+		return nil
+	}
+	var alerts []*platform.SessionAlert
+	alertSvc := mock.NewSessionAlertService()
+	alertSvc.CreateSessionAlertFn = func(ctx context.Context, a *platform.SessionAlert) error {
+		alerts = append(alerts, a)
+		return nil
+	}
+
+	signin := NewSessionHandler(&SessionBackend{
+		Logger:               zaptest.NewLogger(t),
+		HTTPErrorHandler:     ErrorHandler(0),
+		PasswordsService:     passwords,
+		SessionService:       sessions,
+		UserService:          users,
+		SessionFingerprinter: fingerprinter,
+	})
+	r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signin", nil)
+	r.RemoteAddr = "10.0.0.5:1234"
+	r.Header.Set("User-Agent", "browser")
+	r.SetBasicAuth("user1", "supersecret")
+	w := httptest.NewRecorder()
+	signin.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status signing in: %d", w.Code)
+	}
+	if stored == nil || stored.Fingerprint == "" {
+		t.Fatal("expected the session to be bound to the client signing in")
+	}
+
+	h := NewAuthenticationHandler(ErrorHandler(0))
+	h.Logger = zaptest.NewLogger(t)
+	h.SessionService = sessions
+	h.UserService = users
+	h.SessionFingerprinter = fingerprinter
+	h.SessionAlertService = alertSvc
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(addr, ua string) int {
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/me", nil)
+		r.RemoteAddr = addr
+		r.Header.Set("User-Agent", ua)
+		SetCookieSession("abc123xyz", r)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if got := request("10.0.0.77:1234", "browser"); got != http.StatusOK {
+		t.Errorf("expected the session to be accepted from its network, got %d", got)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("expected no alerts, got %d", len(alerts))
+	}
+	if got := request("192.0.2.1:1234", "browser"); got != http.StatusUnauthorized {
+		t.Errorf("expected the session to be rejected from another network, got %d", got)
+	}
+	if got := request("10.0.0.5:1234", "curl"); got != http.StatusUnauthorized {
+		t.Errorf("expected the session to be rejected from another user agent, got %d", got)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+	if a := alerts[0]; a.UserID != 1 || a.SessionID != 2 || a.RemoteIP != "192.0.2.1" || a.UserAgent != "browser" {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+
+	// sessions created before binding was enabled are not bound.
+	stored.Fingerprint = ""
+	if got := request("192.0.2.1:1234", "curl"); got != http.StatusOK {
+		t.Errorf("expected an unbound session to be accepted, got %d", got)
+	}
+}
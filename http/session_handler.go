@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
 	"go.uber.org/zap"
 )
@@ -18,6 +17,10 @@ type SessionBackend struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+
+	// SessionFingerprinter binds the sessions to the clients signing in, if
+	// set.
+	SessionFingerprinter *SessionFingerprinter
 }
 
 // newSessionBackend creates a new SessionBackend with associated logger.
@@ -29,18 +32,22 @@ func newSessionBackend(b *APIBackend) *SessionBackend {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+
+		SessionFingerprinter: b.SessionFingerprinter,
 	}
 }
 
 // SessionHandler represents an HTTP API handler for authorizations.
 type SessionHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+
+	SessionFingerprinter *SessionFingerprinter
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -53,6 +60,8 @@ func NewSessionHandler(b *SessionBackend) *SessionHandler {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+
+		SessionFingerprinter: b.SessionFingerprinter,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
@@ -90,6 +99,16 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.SessionFingerprinter != nil {
+		// bind the session to the client signing in; renewing the session
+		// to its expiration stores its fingerprint.
+		s.Fingerprint = h.SessionFingerprinter.Fingerprint(r)
+		if err := h.SessionService.RenewSession(ctx, s, s.ExpiresAt); err != nil {
+			UnauthorizedError(ctx, h, w)
+			return
+		}
+	}
+
 	encodeCookieSession(w, s)
 	w.WriteHeader(http.StatusNoContent)
 }
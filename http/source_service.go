@@ -102,7 +102,7 @@ func NewSourceBackend(b *APIBackend) *SourceBackend {
 
 // SourceHandler is a handler for sources
 type SourceHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger        *zap.Logger
 	SourceService platform.SourceService
@@ -26,6 +26,14 @@ func (w *statusResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Flush sends any buffered data to the client, if the underlying
+// ResponseWriter supports it, so that responses can be streamed.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func (w *statusResponseWriter) code() int {
 	code := w.statusCode
 	if code == 0 {
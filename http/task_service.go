@@ -35,6 +35,9 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TrashService               influxdb.TrashService
+	TaskBackfillService        influxdb.TaskBackfillService
+	TaskLintService            influxdb.TaskLintService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -49,12 +52,15 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TrashService:               b.TrashService,
+		TaskBackfillService:        b.TaskBackfillService,
+		TaskLintService:            b.TaskLintService,
 	}
 }
 
 // TaskHandler represents an HTTP API handler for tasks.
 type TaskHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	logger *zap.Logger
 
@@ -65,12 +71,17 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TrashService               influxdb.TrashService
+	TaskBackfillService        influxdb.TaskBackfillService
+	TaskLintService            influxdb.TaskLintService
 }
 
 const (
 	tasksPath              = "/api/v2/tasks"
 	tasksIDPath            = "/api/v2/tasks/:id"
 	tasksIDLogsPath        = "/api/v2/tasks/:id/logs"
+	tasksIDRestorePath     = "/api/v2/tasks/:id/restore"
+	tasksIDBackfillPath    = "/api/v2/tasks/:id/backfill"
 	tasksIDMembersPath     = "/api/v2/tasks/:id/members"
 	tasksIDMembersIDPath   = "/api/v2/tasks/:id/members/:userID"
 	tasksIDOwnersPath      = "/api/v2/tasks/:id/owners"
@@ -97,6 +108,9 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TrashService:               b.TrashService,
+		TaskBackfillService:        b.TaskBackfillService,
+		TaskLintService:            b.TaskLintService,
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
@@ -105,6 +119,10 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("GET", tasksIDPath, h.handleGetTask)
 	h.HandlerFunc("PATCH", tasksIDPath, h.handleUpdateTask)
 	h.HandlerFunc("DELETE", tasksIDPath, h.handleDeleteTask)
+	// the router does not allow a static path next to the task ID, so
+	// POST /api/v2/tasks/validate is served from the task ID path.
+	h.HandlerFunc("POST", tasksIDPath, h.handlePostTaskID)
+	h.HandlerFunc("POST", tasksIDRestorePath, h.handleRestoreTask)
 
 	h.HandlerFunc("GET", tasksIDLogsPath, h.handleGetLogs)
 	h.HandlerFunc("GET", tasksIDRunsIDLogsPath, h.handleGetLogs)
@@ -139,6 +157,10 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostBackfill)
+	h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetBackfill)
+	h.HandlerFunc("DELETE", tasksIDBackfillPath, h.handleDeleteBackfill)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger.With(zap.String("handler", "label")),
@@ -441,7 +463,7 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	h.logger.Debug("tasks retrived", zap.String("tasks", fmt.Sprint(tasks)))
-	if err := encodeResponse(ctx, w, http.StatusOK, newTasksResponse(ctx, tasks, req.filter, h.LabelService)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newTasksResponse(ctx, tasks, req.filter, h.LabelService), req.fields, "tasks"); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
@@ -449,6 +471,7 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 
 type getTasksRequest struct {
 	filter influxdb.TaskFilter
+	fields fieldSelection
 }
 
 func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.OrganizationService) (*getTasksRequest, error) {
@@ -518,6 +541,12 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.O
 		req.filter.Name = &name
 	}
 
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+	req.fields = fields
+
 	return req, nil
 }
 
@@ -611,6 +640,60 @@ func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskReque
 	}, nil
 }
 
+func (h *TaskHandler) handlePostTaskID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if httprouter.ParamsFromContext(ctx).ByName("id") == "validate" {
+		h.handleValidateTask(w, r)
+		return
+	}
+
+	w.Header().Set("Allow", "GET, PATCH, DELETE")
+	h.HandleHTTPError(ctx, &influxdb.Error{
+		Code: influxdb.EMethodNotAllowed,
+		Msg:  "allow: GET, PATCH, DELETE",
+	}, w)
+}
+
+// handleValidateTask checks the flux script of a task the way the task
+// would be created, without creating it.
+func (h *TaskHandler) handleValidateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var tc influxdb.TaskCreate
+	if err := json.NewDecoder(r.Body).Decode(&tc); err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.populateTaskCreateOrg(ctx, &tc); err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.TaskLintService.LintTask(ctx, tc.OrganizationID, tc.Flux)
+	if err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to validate task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
 func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req, err := decodeGetTaskRequest(ctx, r)
@@ -645,7 +728,7 @@ func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	h.logger.Debug("task retrived", zap.String("tasks", fmt.Sprint(task)))
-	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+	if err := encodeSparseResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels), req.fields, ""); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
@@ -653,6 +736,7 @@ func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 
 type getTaskRequest struct {
 	TaskID influxdb.ID
+	fields fieldSelection
 }
 
 func decodeGetTaskRequest(ctx context.Context, r *http.Request) (*getTaskRequest, error) {
@@ -670,8 +754,14 @@ func decodeGetTaskRequest(ctx context.Context, r *http.Request) (*getTaskRequest
 		return nil, err
 	}
 
+	fields, err := decodeFieldSelection(r)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &getTaskRequest{
 		TaskID: i,
+		fields: fields,
 	}
 
 	return req, nil
@@ -781,6 +871,45 @@ func (h *TaskHandler) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *TaskHandler) handleRestoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeDeleteTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TrashService.RestoreTask(ctx, req.TaskID)
+	if err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to restore task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID})
+	if err != nil {
+		err = &influxdb.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.logger.Debug("task restored", zap.String("task", fmt.Sprint(task)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
 type deleteTaskRequest struct {
 	TaskID influxdb.ID
 }
@@ -1100,6 +1229,135 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	}, nil
 }
 
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostBackfillRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskBackfillService.BackfillTask(ctx, req.TaskID, req.Start, req.Stop)
+	if err != nil {
+		err := &influxdb.Error{
+			Err: err,
+			Msg: "failed to backfill task",
+		}
+		if err.Err == influxdb.ErrTaskNotFound {
+			err.Code = influxdb.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.logger.Debug("task backfill started", zap.String("taskID", req.TaskID.String()), zap.Int("runs", b.Total))
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newTaskBackfillResponse(*b)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+type postBackfillRequest struct {
+	TaskID influxdb.ID
+	Start  time.Time
+	Stop   time.Time
+}
+
+func decodePostBackfillRequest(ctx context.Context, r *http.Request) (*postBackfillRequest, error) {
+	req, err := decodeDeleteTaskRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Start time.Time `json:"start"`
+		Stop  time.Time `json:"stop"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Start.IsZero() || body.Stop.IsZero() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "you must provide the start and stop of the backfill",
+		}
+	}
+
+	return &postBackfillRequest{
+		TaskID: req.TaskID,
+		Start:  body.Start,
+		Stop:   body.Stop,
+	}, nil
+}
+
+func (h *TaskHandler) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDeleteTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskBackfillService.FindTaskBackfill(ctx, req.TaskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskBackfillResponse(*b)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleDeleteBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDeleteTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskBackfillService.CancelTaskBackfill(ctx, req.TaskID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.logger.Debug("task backfill canceled", zap.String("taskID", req.TaskID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type taskBackfillResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.TaskBackfill
+}
+
+func newTaskBackfillResponse(b influxdb.TaskBackfill) taskBackfillResponse {
+	return taskBackfillResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill", b.TaskID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", b.TaskID),
+			"runs": fmt.Sprintf("/api/v2/tasks/%s/runs", b.TaskID),
+		},
+		TaskBackfill: b,
+	}
+}
+
 func (h *TaskHandler) handleGetRun(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -1348,10 +1606,6 @@ func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunReque
 }
 
 func (h *TaskHandler) populateTaskCreateOrg(ctx context.Context, tc *influxdb.TaskCreate) error {
-	if tc.OrganizationID.Valid() && tc.Organization != "" {
-		return nil
-	}
-
 	if !tc.OrganizationID.Valid() && tc.Organization == "" {
 		return errors.New("missing orgID and organization name")
 	}
@@ -1361,6 +1615,12 @@ func (h *TaskHandler) populateTaskCreateOrg(ctx context.Context, tc *influxdb.Ta
 		if err != nil {
 			return err
 		}
+		if tc.Organization != "" && tc.Organization != o.Name {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID and organization name do not match",
+			}
+		}
 		tc.Organization = o.Name
 	} else {
 		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &tc.Organization})
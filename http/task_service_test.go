@@ -58,6 +58,8 @@ func NewMockTaskBackend(t *testing.T) *TaskBackend {
 		UserResourceMappingService: inmem.NewService(),
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
+		TaskBackfillService:        mock.NewTaskBackfillService(),
+		TaskLintService:            mock.NewTaskLintService(),
 	}
 }
 
@@ -380,7 +382,12 @@ func TestTaskHandler_handleGetTasks(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `{
 "code": "invalid",
-"message": "failed to decode request: org non-existent-org not found or unauthorized: org not found or unauthorized"
+"message": "failed to decode request: org non-existent-org not found or unauthorized: org not found or unauthorized",
+"error": {
+	"code": "not found",
+	"message": "org non-existent-org not found or unauthorized",
+	"error": "org not found or unauthorized"
+}
 }`,
 			},
 		},
@@ -512,7 +519,8 @@ func TestTaskHandler_handlePostTasks(t *testing.T) {
 				body: `
 {
     "code": "invalid",
-    "message": "something really went wrong: something went wrong"
+    "message": "something really went wrong: something went wrong",
+    "error": "something went wrong"
 }
 `,
 			},
@@ -817,6 +825,164 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handlePostBackfill(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name                string
+		body                string
+		taskBackfillService platform.TaskBackfillService
+		wants               wants
+	}{
+		{
+			name: "backfill a task",
+			body: `{"start": "2020-01-01T00:00:00Z", "stop": "2020-01-01T06:00:00Z"}`,
+			taskBackfillService: &mock.TaskBackfillService{
+				BackfillTaskFn: func(ctx context.Context, taskID platform.ID, start, stop time.Time) (*platform.TaskBackfill, error) {
+					return &platform.TaskBackfill{
+						TaskID:    taskID,
+						OrgID:     2,
+						Start:     start,
+						Stop:      stop,
+						Status:    platform.TaskBackfillRunning,
+						Total:     6,
+						CreatedAt: stop,
+					}, nil
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusAccepted,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/tasks/0000000000000001/backfill",
+    "task": "/api/v2/tasks/0000000000000001",
+    "runs": "/api/v2/tasks/0000000000000001/runs"
+  },
+  "taskID": "0000000000000001",
+  "orgID": "0000000000000002",
+  "start": "2020-01-01T00:00:00Z",
+  "stop": "2020-01-01T06:00:00Z",
+  "status": "running",
+  "total": 6,
+  "enqueued": 0,
+  "skipped": 0,
+  "succeeded": 0,
+  "failed": 0,
+  "createdAt": "2020-01-01T06:00:00Z"
+}`,
+			},
+		},
+		{
+			name:                "missing stop",
+			body:                `{"start": "2020-01-01T00:00:00Z"}`,
+			taskBackfillService: mock.NewTaskBackfillService(),
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "backfill in progress",
+			body: `{"start": "2020-01-01T00:00:00Z", "stop": "2020-01-01T06:00:00Z"}`,
+			taskBackfillService: &mock.TaskBackfillService{
+				BackfillTaskFn: func(ctx context.Context, taskID platform.ID, start, stop time.Time) (*platform.TaskBackfill, error) {
+					return nil, platform.ErrTaskBackfillInProgress
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "http://any.url", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: platform.ID(1).String(),
+					},
+				}))
+			w := httptest.NewRecorder()
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.TaskBackfillService = tt.taskBackfillService
+			h := NewTaskHandler(taskBackend)
+			h.handlePostBackfill(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostBackfill() = %v, want %v: %s", tt.name, res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePostBackfill(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePostBackfill() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleValidateTask(t *testing.T) {
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskLintService = &mock.TaskLintService{
+		LintTaskFn: func(ctx context.Context, orgID platform.ID, script string) (*platform.TaskLintResult, error) {
+			if orgID != 1 || script != "from(bucket: \"b\")" {
+				t.Errorf("unexpected org %s or script %q", orgID, script)
+			}
+			return &platform.TaskLintResult{
+				Diagnostics: []platform.TaskDiagnostic{
+					{Severity: platform.TaskDiagnosticError, Message: "invalid task options: missing task option", Line: 1, Column: 1},
+				},
+			}, nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/tasks/validate", strings.NewReader(`{"orgID": "0000000000000001", "flux": "from(bucket: \"b\")"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleValidateTask() = %v, want %v: %s", res.StatusCode, http.StatusOK, body)
+	}
+	want := `
+{
+  "valid": false,
+  "diagnostics": [
+    {"severity": "error", "message": "invalid task options: missing task option", "line": 1, "column": 1}
+  ]
+}`
+	if eq, diff, err := jsonEqual(string(body), want); err != nil {
+		t.Errorf("handleValidateTask(). error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("handleValidateTask() = ***%s***", diff)
+	}
+
+	// The organization name must be the one of the organization ID.
+	r = httptest.NewRequest("POST", "http://any.url/api/v2/tasks/validate", strings.NewReader(`{"orgID": "0000000000000001", "org": "other", "flux": "from(bucket: \"b\")"}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if res := w.Result(); res.StatusCode != http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(res.Body)
+		t.Fatalf("handleValidateTask() = %v, want %v: %s", res.StatusCode, http.StatusBadRequest, body)
+	}
+}
+
 func TestTaskHandler_NotFoundStatus(t *testing.T) {
 	// Ensure that the HTTP handlers return 404s for missing resources, and OKs for matching.
 
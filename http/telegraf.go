@@ -44,7 +44,7 @@ func NewTelegrafBackend(b *APIBackend) *TelegrafBackend {
 
 // TelegrafHandler is the handler for the telegraf service
 type TelegrafHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
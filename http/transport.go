@@ -12,16 +12,17 @@ import (
 // used by traceClient. It establishes network connections as needed
 // and caches them for reuse by subsequent calls. It uses HTTP proxies
 // as directed by the $HTTP_PROXY and $NO_PROXY (or $http_proxy and
-// $no_proxy) environment variables.
-// This is the same as http.DefaultTransport.
+// $no_proxy) environment variables, and dials the Unix domain sockets
+// of unix:// addresses.
+// This is the same as http.DefaultTransport otherwise.
 //
 var defaultTransport http.RoundTripper = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
+	Proxy: proxyFromEnvironment,
+	DialContext: dialContext(&net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
 		DualStack: true,
-	}).DialContext,
+	}),
 	MaxIdleConns:          100,
 	IdleConnTimeout:       90 * time.Second,
 	TLSHandshakeTimeout:   10 * time.Second,
@@ -36,12 +37,12 @@ var defaultTransport http.RoundTripper = &http.Transport{
 // This is the same as http.DefaultTransport but with TLS skip verify.
 //
 var skipVerifyTransport = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
+	Proxy: proxyFromEnvironment,
+	DialContext: dialContext(&net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
 		DualStack: true,
-	}).DialContext,
+	}),
 	MaxIdleConns:          100,
 	IdleConnTimeout:       90 * time.Second,
 	TLSHandshakeTimeout:   10 * time.Second,
@@ -49,3 +50,22 @@ var skipVerifyTransport = &http.Transport{
 	// This is the value that changes between this and http.DefaultTransport
 	TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 }
+
+// newTLSTransport returns a transport like defaultTransport that connects to
+// servers with the TLS config. Since a transport caches its connections,
+// clients should share the transports of the same config.
+func newTLSTransport(config *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: proxyFromEnvironment,
+		DialContext: dialContext(&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}),
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       config,
+	}
+}
@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	trashPath = "/api/v2/trash"
+)
+
+// TrashBackend is all services and associated parameters required to construct
+// the TrashHandler.
+type TrashBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TrashService influxdb.TrashService
+}
+
+// NewTrashBackend returns a new instance of TrashBackend.
+func NewTrashBackend(b *APIBackend) *TrashBackend {
+	return &TrashBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "trash")),
+
+		TrashService: b.TrashService,
+	}
+}
+
+// TrashHandler is the handler for the soft deleted dashboards and tasks of
+// organizations. Trashed resources are restored with the restore endpoints
+// of their resource type.
+type TrashHandler struct {
+	*Router
+
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TrashService influxdb.TrashService
+}
+
+// NewTrashHandler creates a new TrashHandler.
+func NewTrashHandler(b *TrashBackend) *TrashHandler {
+	h := &TrashHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		TrashService: b.TrashService,
+	}
+
+	h.HandlerFunc("GET", trashPath, h.handleGetTrashedResources)
+	h.HandlerFunc("GET", fmt.Sprintf("%s/:id", trashPath), h.handleGetTrashedResource)
+
+	return h
+}
+
+type trashedResourceLinks struct {
+	Self    string `json:"self"`
+	Restore string `json:"restore"`
+	Org     string `json:"org"`
+}
+
+type trashedResourceResponse struct {
+	*influxdb.TrashedResource
+	Links trashedResourceLinks `json:"links"`
+}
+
+func newTrashedResourceResponse(r *influxdb.TrashedResource) trashedResourceResponse {
+	return trashedResourceResponse{
+		TrashedResource: r,
+		Links: trashedResourceLinks{
+			Self:    path.Join(trashPath, r.ID.String()),
+			Restore: fmt.Sprintf("/api/v2/%s/%s/restore", r.Type, r.ID),
+			Org:     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+	}
+}
+
+type getTrashedResourcesResponse struct {
+	Resources []trashedResourceResponse `json:"resources"`
+}
+
+func newGetTrashedResourcesResponse(rs []*influxdb.TrashedResource) getTrashedResourcesResponse {
+	resp := getTrashedResourcesResponse{
+		Resources: make([]trashedResourceResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		resp.Resources = append(resp.Resources, newTrashedResourceResponse(r))
+	}
+	return resp
+}
+
+type getTrashedResourcesRequest struct {
+	filter influxdb.TrashFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetTrashedResourcesRequest(ctx context.Context, r *http.Request) (*getTrashedResourcesRequest, error) {
+	qp := r.URL.Query()
+	req := &getTrashedResourcesRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		req.filter.OrgID = id
+	}
+
+	if typ := qp.Get("type"); typ != "" {
+		rt := influxdb.ResourceType(typ)
+		if rt != influxdb.DashboardsResourceType && rt != influxdb.TasksResourceType {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid type %q; must be %s or %s", typ, influxdb.DashboardsResourceType, influxdb.TasksResourceType),
+			}
+		}
+		req.filter.Type = &rt
+	}
+
+	return req, nil
+}
+
+func (h *TrashHandler) handleGetTrashedResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetTrashedResourcesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.TrashService.FindTrashedResources(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("trashed resources retrieved", zap.String("resources", fmt.Sprint(rs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetTrashedResourcesResponse(rs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *TrashHandler) handleGetTrashedResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestTrashedResourceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.TrashService.FindTrashedResourceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("trashed resource retrieved", zap.String("resource", fmt.Sprint(res)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTrashedResourceResponse(res)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestTrashedResourceID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Clients connect to a server listening on a Unix domain socket with the
+// address unix:///path/to/influxd.sock. Since a URL cannot hold both the
+// path of the socket and the path of the request, the socket is encoded in
+// the host of the URLs of the requests, which the transports of the clients
+// dial as the socket instead of resolving it.
+const (
+	unixSocketScheme = "unix"
+
+	// unixSocketHostSuffix is the suffix of the hosts of sockets. The
+	// .localhost domain is reserved, so these hosts are never resolved.
+	unixSocketHostSuffix = ".unix.localhost"
+)
+
+// unixSocketURL returns the base URL of requests to the server listening on
+// the socket at path.
+func unixSocketURL(path string) *url.URL {
+	return &url.URL{
+		Scheme: "http",
+		Host:   hex.EncodeToString([]byte(path)) + unixSocketHostSuffix,
+	}
+}
+
+// unixSocketPath returns the path of the socket encoded in the host of the
+// address, with an optional port.
+func unixSocketPath(addr string) (string, bool) {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	if !strings.HasSuffix(host, unixSocketHostSuffix) {
+		return "", false
+	}
+	path, err := hex.DecodeString(strings.TrimSuffix(host, unixSocketHostSuffix))
+	if err != nil || len(path) == 0 {
+		return "", false
+	}
+	return string(path), true
+}
+
+// dialContext returns a dial function dialing the sockets encoded in the
+// addresses, and other addresses with d.
+func dialContext(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if path, ok := unixSocketPath(addr); ok {
+			return d.DialContext(ctx, "unix", path)
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+// proxyFromEnvironment is http.ProxyFromEnvironment, except that requests to
+// sockets are never proxied.
+func proxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	if _, ok := unixSocketPath(req.URL.Host); ok {
+		return nil, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
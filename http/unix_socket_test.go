@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_UnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxdb-unix-socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "influxd.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		body = r.URL.Path + "?" + r.URL.RawQuery + " " + string(data)
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	u, err := NewURL("unix://"+path, "/api/v2/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewClient(u.Scheme, false).Get(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || body != "/api/v2/health? " {
+		t.Fatalf("unexpected response %d to request %q", resp.StatusCode, body)
+	}
+
+	s := &WriteService{Addr: "unix://" + path, Token: "token"}
+	if err := s.Write(context.Background(), 1, 2, strings.NewReader("m f=1")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(body, "/api/v2/write?") {
+		t.Fatalf("unexpected write request %q", body)
+	}
+
+	if _, err := NewURL("unix://", "/api/v2/health"); err == nil {
+		t.Fatal("expected an error for a unix address without a path")
+	}
+}
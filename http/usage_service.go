@@ -2,18 +2,17 @@ package http
 
 import (
 	"context"
-	"errors"
 	"net/http"
+	"net/url"
 	"time"
 
-	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
 	"go.uber.org/zap"
 )
 
 // UsageHandler represents an HTTP API handler for usages.
 type UsageHandler struct {
-	*httprouter.Router
+	*Router
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
@@ -79,44 +78,73 @@ func decodeGetUsageRequest(ctx context.Context, r *http.Request) (*getUsageReque
 		req.filter.BucketID = &id
 	}
 
+	rng, err := decodeUsageRange(qp)
+	if err != nil {
+		return nil, err
+	}
+	req.filter.Range = rng
+
+	return req, nil
+}
+
+// decodeUsageRange decodes the start and stop query params. When neither is
+// given the range is the current month up to now.
+func decodeUsageRange(qp url.Values) (*platform.Timespan, error) {
 	start := qp.Get("start")
 	stop := qp.Get("stop")
 
 	if start == "" && stop != "" {
-		return nil, errors.New("start query param required")
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "start query param required",
+		}
 	}
 	if stop == "" && start != "" {
-		return nil, errors.New("stop query param required")
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "stop query param required",
+		}
 	}
 
 	if start == "" && stop == "" {
 		now := time.Now()
 		month := roundToMonth(now)
 
-		req.filter.Range = &platform.Timespan{
+		return &platform.Timespan{
 			Start: month,
 			Stop:  now,
-		}
+		}, nil
 	}
 
-	if start != "" && stop != "" {
-		startTime, err := time.Parse(time.RFC3339, start)
-		if err != nil {
-			return nil, err
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "start query param must be an RFC3339 timestamp",
+			Err:  err,
 		}
+	}
 
-		stopTime, err := time.Parse(time.RFC3339, stop)
-		if err != nil {
-			return nil, err
+	stopTime, err := time.Parse(time.RFC3339, stop)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "stop query param must be an RFC3339 timestamp",
+			Err:  err,
 		}
+	}
 
-		req.filter.Range = &platform.Timespan{
-			Start: startTime,
-			Stop:  stopTime,
+	if stopTime.Before(startTime) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "stop query param must not be before start",
 		}
 	}
 
-	return req, nil
+	return &platform.Timespan{
+		Start: startTime,
+		Stop:  stopTime,
+	}, nil
 }
 
 func roundToMonth(t time.Time) time.Time {
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb"
@@ -22,6 +25,8 @@ type UserBackend struct {
 	UserService             influxdb.UserService
 	UserOperationLogService influxdb.UserOperationLogService
 	PasswordsService        influxdb.PasswordsService
+	UserSettingsService     influxdb.UserSettingsService
+	SessionAlertService     influxdb.SessionAlertService
 }
 
 // NewUserBackend creates a UserBackend using information in the APIBackend.
@@ -32,26 +37,33 @@ func NewUserBackend(b *APIBackend) *UserBackend {
 		UserService:             b.UserService,
 		UserOperationLogService: b.UserOperationLogService,
 		PasswordsService:        b.PasswordsService,
+		UserSettingsService:     b.UserSettingsService,
+		SessionAlertService:     b.SessionAlertService,
 	}
 }
 
 // UserHandler represents an HTTP API handler for users.
 type UserHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger                  *zap.Logger
 	UserService             influxdb.UserService
 	UserOperationLogService influxdb.UserOperationLogService
 	PasswordsService        influxdb.PasswordsService
+	UserSettingsService     influxdb.UserSettingsService
+	SessionAlertService     influxdb.SessionAlertService
 }
 
 const (
-	usersPath         = "/api/v2/users"
-	mePath            = "/api/v2/me"
-	mePasswordPath    = "/api/v2/me/password"
-	usersIDPath       = "/api/v2/users/:id"
-	usersPasswordPath = "/api/v2/users/:id/password"
-	usersLogPath      = "/api/v2/users/:id/logs"
+	usersPath           = "/api/v2/users"
+	mePath              = "/api/v2/me"
+	mePasswordPath      = "/api/v2/me/password"
+	meSettingsPath      = "/api/v2/me/settings"
+	meSettingPath       = "/api/v2/me/settings/:namespace"
+	meSessionAlertsPath = "/api/v2/me/session-alerts"
+	usersIDPath         = "/api/v2/users/:id"
+	usersPasswordPath   = "/api/v2/users/:id/password"
+	usersLogPath        = "/api/v2/users/:id/logs"
 )
 
 // NewUserHandler returns a new instance of UserHandler.
@@ -64,6 +76,8 @@ func NewUserHandler(b *UserBackend) *UserHandler {
 		UserService:             b.UserService,
 		UserOperationLogService: b.UserOperationLogService,
 		PasswordsService:        b.PasswordsService,
+		UserSettingsService:     b.UserSettingsService,
+		SessionAlertService:     b.SessionAlertService,
 	}
 
 	h.HandlerFunc("POST", usersPath, h.handlePostUser)
@@ -80,6 +94,12 @@ func NewUserHandler(b *UserBackend) *UserHandler {
 
 	h.HandlerFunc("GET", mePath, h.handleGetMe)
 	h.HandlerFunc("PUT", mePasswordPath, h.handlePutUserPassword)
+	h.HandlerFunc("GET", meSettingsPath, h.handleGetMeSettings)
+	h.HandlerFunc("GET", meSettingPath, h.handleGetMeSetting)
+	h.HandlerFunc("PUT", meSettingPath, h.handlePutMeSetting)
+	h.HandlerFunc("DELETE", meSettingPath, h.handleDeleteMeSetting)
+	h.HandlerFunc("GET", meSessionAlertsPath, h.handleGetMeSessionAlerts)
+	h.HandlerFunc("DELETE", meSessionAlertsPath, h.handleDeleteMeSessionAlerts)
 
 	return h
 }
@@ -247,6 +267,222 @@ func (h *UserHandler) handleGetMe(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type userSettingsResponse struct {
+	Links     map[string]string          `json:"links"`
+	UserID    influxdb.ID                `json:"userID"`
+	Settings  map[string]json.RawMessage `json:"settings"`
+	UpdatedAt *time.Time                 `json:"updatedAt,omitempty"`
+}
+
+func newUserSettingsResponse(s *influxdb.UserSettings) *userSettingsResponse {
+	res := &userSettingsResponse{
+		Links: map[string]string{
+			"self": meSettingsPath,
+			"user": fmt.Sprintf("/api/v2/users/%s", s.UserID),
+		},
+		UserID:   s.UserID,
+		Settings: s.Settings,
+	}
+	if res.Settings == nil {
+		res.Settings = map[string]json.RawMessage{}
+	}
+	if !s.UpdatedAt.IsZero() {
+		res.UpdatedAt = &s.UpdatedAt
+	}
+	return res
+}
+
+// handleGetMeSettings is the HTTP handler for the GET /api/v2/me/settings route.
+func (h *UserHandler) handleGetMeSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	settings, err := h.UserSettingsService.FindUserSettings(ctx, a.GetUserID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newUserSettingsResponse(settings)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetMeSetting is the HTTP handler for the GET /api/v2/me/settings/:namespace route.
+// The response body is the stored JSON value of the namespace.
+func (h *UserHandler) handleGetMeSetting(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeSettingRequest(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	settings, err := h.UserSettingsService.FindUserSettings(ctx, req.userID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	v, ok := settings.Settings[req.namespace]
+	if !ok {
+		h.HandleHTTPError(ctx, influxdb.ErrUserSettingNotFound, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, v); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutMeSetting is the HTTP handler for the PUT /api/v2/me/settings/:namespace route.
+// The request body is the JSON value stored in the namespace.
+func (h *UserHandler) handlePutMeSetting(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeSettingRequest(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// read one byte past the limit so that too large values are told apart.
+	value, err := ioutil.ReadAll(io.LimitReader(r.Body, influxdb.MaxUserSettingSize+1))
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := influxdb.ValidUserSetting(req.namespace, value); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	settings, err := h.UserSettingsService.PutUserSetting(ctx, req.userID, req.namespace, value)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("user setting updated", zap.String("namespace", req.namespace))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newUserSettingsResponse(settings)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteMeSetting is the HTTP handler for the DELETE /api/v2/me/settings/:namespace route.
+func (h *UserHandler) handleDeleteMeSetting(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeSettingRequest(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.UserSettingsService.DeleteUserSetting(ctx, req.userID, req.namespace); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("user setting deleted", zap.String("namespace", req.namespace))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type sessionAlertsResponse struct {
+	Links  map[string]string        `json:"links"`
+	Alerts []*influxdb.SessionAlert `json:"alerts"`
+}
+
+func newSessionAlertsResponse(userID influxdb.ID, alerts []*influxdb.SessionAlert) *sessionAlertsResponse {
+	res := &sessionAlertsResponse{
+		Links: map[string]string{
+			"self": meSessionAlertsPath,
+			"user": fmt.Sprintf("/api/v2/users/%s", userID),
+		},
+		Alerts: alerts,
+	}
+	if res.Alerts == nil {
+		res.Alerts = []*influxdb.SessionAlert{}
+	}
+	return res
+}
+
+// handleGetMeSessionAlerts is the HTTP handler for the GET /api/v2/me/session-alerts route.
+// The alerts are the uses of the sessions of the user rejected for coming from
+// other clients than the ones the sessions are bound to, newest first.
+func (h *UserHandler) handleGetMeSessionAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	alerts, err := h.SessionAlertService.FindSessionAlerts(ctx, a.GetUserID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSessionAlertsResponse(a.GetUserID(), alerts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteMeSessionAlerts is the HTTP handler for the DELETE /api/v2/me/session-alerts route.
+func (h *UserHandler) handleDeleteMeSessionAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SessionAlertService.DeleteSessionAlerts(ctx, a.GetUserID()); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type meSettingRequest struct {
+	userID    influxdb.ID
+	namespace string
+}
+
+func decodeMeSettingRequest(ctx context.Context) (*meSettingRequest, error) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := httprouter.ParamsFromContext(ctx).ByName("namespace")
+	if err := influxdb.ValidUserSettingNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	return &meSettingRequest{
+		userID:    a.GetUserID(),
+		namespace: namespace,
+	}, nil
+}
+
 // handleGetUser is the HTTP handler for the GET /api/v2/users/:id route.
 func (h *UserHandler) handleGetUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,7 @@ import (
 	"github.com/influxdata/influxdb/pkg/testttp"
 
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
@@ -28,6 +30,8 @@ func NewMockUserBackend() *UserBackend {
 		UserService:             mock.NewUserService(),
 		UserOperationLogService: mock.NewUserOperationLogService(),
 		PasswordsService:        mock.NewPasswordsService(),
+		UserSettingsService:     mock.NewUserSettingsService(),
+		SessionAlertService:     mock.NewSessionAlertService(),
 		HTTPErrorHandler:        ErrorHandler(0),
 	}
 }
@@ -88,6 +92,120 @@ func TestUserHandler_SettingPassword(t *testing.T) {
 	testttp.Post(addr, body).Do(h).ExpectStatus(t, http.StatusNoContent)
 }
 
+func TestUserHandler_MeSettings(t *testing.T) {
+	userID := platform.ID(1)
+	stored := map[string]json.RawMessage{}
+
+	be := NewMockUserBackend()
+	be.UserSettingsService = &mock.UserSettingsService{
+		FindUserSettingsFn: func(_ context.Context, id platform.ID) (*platform.UserSettings, error) {
+			if id != userID {
+				return nil, errors.New("unexpected id: " + id.String())
+			}
+			return &platform.UserSettings{UserID: id, Settings: stored}, nil
+		},
+		PutUserSettingFn: func(_ context.Context, id platform.ID, namespace string, value json.RawMessage) (*platform.UserSettings, error) {
+			stored[namespace] = value
+			return &platform.UserSettings{UserID: id, Settings: stored}, nil
+		},
+		DeleteUserSettingFn: func(_ context.Context, id platform.ID, namespace string) error {
+			if _, ok := stored[namespace]; !ok {
+				return platform.ErrUserSettingNotFound
+			}
+			delete(stored, namespace)
+			return nil
+		},
+	}
+
+	uh := NewUserHandler(be)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Session{UserID: userID}))
+		uh.ServeHTTP(w, r)
+	})
+
+	testttp.Put("/api/v2/me/settings/theme", strings.NewReader(`{"mode":"dark"}`)).
+		Do(h).
+		ExpectStatus(t, http.StatusOK)
+	testttp.Get("/api/v2/me/settings/theme").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			require.JSONEq(t, `{"mode":"dark"}`, body.String())
+		})
+	testttp.Get("/api/v2/me/settings").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			var res userSettingsResponse
+			require.NoError(t, json.NewDecoder(body).Decode(&res))
+			require.Equal(t, userID, res.UserID)
+			require.JSONEq(t, `{"mode":"dark"}`, string(res.Settings["theme"]))
+		})
+
+	testttp.Put("/api/v2/me/settings/theme", strings.NewReader(`{"mode":`)).
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+	tooLarge := `"` + strings.Repeat("a", platform.MaxUserSettingSize) + `"`
+	testttp.Put("/api/v2/me/settings/theme", strings.NewReader(tooLarge)).
+		Do(h).
+		ExpectStatus(t, http.StatusRequestEntityTooLarge)
+	testttp.Put("/api/v2/me/settings/-theme", strings.NewReader(`1`)).
+		Do(h).
+		ExpectStatus(t, http.StatusBadRequest)
+
+	testttp.Delete("/api/v2/me/settings/theme").
+		Do(h).
+		ExpectStatus(t, http.StatusNoContent)
+	testttp.Get("/api/v2/me/settings/theme").
+		Do(h).
+		ExpectStatus(t, http.StatusNotFound)
+}
+
+func TestUserHandler_MeSessionAlerts(t *testing.T) {
+	userID := platform.ID(1)
+	stored := []*platform.SessionAlert{{UserID: userID, SessionID: 2, RemoteIP: "192.0.2.1", UserAgent: "curl"}}
+
+	be := NewMockUserBackend()
+	be.SessionAlertService = &mock.SessionAlertService{
+		FindSessionAlertsFn: func(_ context.Context, id platform.ID) ([]*platform.SessionAlert, error) {
+			if id != userID {
+				return nil, errors.New("unexpected id: " + id.String())
+			}
+			return stored, nil
+		},
+		DeleteSessionAlertsFn: func(_ context.Context, id platform.ID) error {
+			stored = nil
+			return nil
+		},
+	}
+
+	uh := NewUserHandler(be)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Session{UserID: userID}))
+		uh.ServeHTTP(w, r)
+	})
+
+	testttp.Get("/api/v2/me/session-alerts").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			var res sessionAlertsResponse
+			require.NoError(t, json.NewDecoder(body).Decode(&res))
+			require.Len(t, res.Alerts, 1)
+			require.Equal(t, "192.0.2.1", res.Alerts[0].RemoteIP)
+		})
+
+	testttp.Delete("/api/v2/me/session-alerts").
+		Do(h).
+		ExpectStatus(t, http.StatusNoContent)
+	testttp.Get("/api/v2/me/session-alerts").
+		Do(h).
+		ExpectStatus(t, http.StatusOK).
+		ExpectBody(func(body *bytes.Buffer) {
+			require.JSONEq(t, `{"links":{"self":"/api/v2/me/session-alerts","user":"/api/v2/users/0000000000000001"},"alerts":[]}`, body.String())
+		})
+}
+
 func newReqBody(t *testing.T, v interface{}) *bytes.Buffer {
 	t.Helper()
 
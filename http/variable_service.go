@@ -38,7 +38,7 @@ func NewVariableBackend(b *APIBackend) *VariableBackend {
 
 // VariableHandler is the handler for the variable service
 type VariableHandler struct {
-	*httprouter.Router
+	*Router
 
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
@@ -7,13 +7,14 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sync/atomic"
 	"time"
 
-	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/http/metric"
 	"go.uber.org/zap"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
@@ -31,6 +32,8 @@ type WriteBackend struct {
 	PointsWriter        storage.PointsWriter
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	MaintenanceService  influxdb.MaintenanceService
+	WriteLimits         *WriteLimits
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
@@ -43,19 +46,191 @@ func NewWriteBackend(b *APIBackend) *WriteBackend {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		MaintenanceService:  b.MaintenanceService,
+		WriteLimits:         b.WriteLimits,
+	}
+}
+
+// WriteLimits are the limits applied to write requests. They are safe for
+// concurrent use and may be changed while the server is running.
+type WriteLimits struct {
+	maxBodyBytes int64
+	maxPointAge  int64
+	maxFuture    int64
+}
+
+// MaxBodyBytes returns the maximum size of a decompressed write request body.
+// Zero means unlimited.
+func (l *WriteLimits) MaxBodyBytes() int64 {
+	if l == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&l.maxBodyBytes)
+}
+
+// SetMaxBodyBytes sets the maximum size of a decompressed write request body.
+func (l *WriteLimits) SetMaxBodyBytes(n int64) {
+	atomic.StoreInt64(&l.maxBodyBytes, n)
+}
+
+// MaxPointAge returns how far in the past the timestamp of a written point
+// may be. Zero means unlimited.
+func (l *WriteLimits) MaxPointAge() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&l.maxPointAge))
+}
+
+// SetMaxPointAge sets how far in the past the timestamp of a written point
+// may be.
+func (l *WriteLimits) SetMaxPointAge(d time.Duration) {
+	atomic.StoreInt64(&l.maxPointAge, int64(d))
+}
+
+// MaxPointFuture returns how far in the future the timestamp of a written
+// point may be. Zero means unlimited.
+func (l *WriteLimits) MaxPointFuture() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&l.maxFuture))
+}
+
+// SetMaxPointFuture sets how far in the future the timestamp of a written
+// point may be.
+func (l *WriteLimits) SetMaxPointFuture(d time.Duration) {
+	atomic.StoreInt64(&l.maxFuture, int64(d))
+}
+
+// rejectPoints splits points into the points whose timestamps are within the
+// accepted time bounds relative to now and the rejections of the others.
+func (l *WriteLimits) rejectPoints(points []models.Point, now time.Time) ([]models.Point, []pointRejection) {
+	maxAge, maxFuture := l.MaxPointAge(), l.MaxPointFuture()
+	if maxAge == 0 && maxFuture == 0 {
+		return points, nil
+	}
+
+	var (
+		accepted = points[:0]
+		rejected []pointRejection
+	)
+	for i, p := range points {
+		var reason string
+		switch t := p.Time(); {
+		case maxAge > 0 && t.Before(now.Add(-maxAge)):
+			reason = fmt.Sprintf("timestamp is more than %s in the past", maxAge)
+		case maxFuture > 0 && t.After(now.Add(maxFuture)):
+			reason = fmt.Sprintf("timestamp is more than %s in the future", maxFuture)
+		default:
+			accepted = append(accepted, p)
+			continue
+		}
+		rejected = append(rejected, pointRejection{
+			Index:  i,
+			Time:   p.Time().UTC(),
+			Reason: reason,
+		})
+	}
+	return accepted, rejected
+}
+
+// pointRejection describes a point of a write request that was not written.
+type pointRejection struct {
+	// Index is the position of the point in the request body, starting at 0.
+	Index  int       `json:"index"`
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+}
+
+// pointsRejectedError is returned when some of the points of a write request
+// were rejected. The other points of the request were written.
+type pointsRejectedError struct {
+	rejected []pointRejection
+}
+
+func (e *pointsRejectedError) Error() string {
+	return fmt.Sprintf("partial write: %d points outside the accepted time bounds were rejected", len(e.rejected))
+}
+
+// lineProtocolParseError describes a line of a write request that could not
+// be parsed.
+type lineProtocolParseError struct {
+	// Line is the 1-based number of the line in the request body.
+	Line int `json:"line"`
+	// Offset is the byte offset of the offending token in the request body.
+	Offset  int    `json:"offset"`
+	Token   string `json:"token"`
+	Message string `json:"message"`
+}
+
+// lineProtocolError is returned when lines of a write request could not be
+// parsed. None of the points of the request were written.
+type lineProtocolError struct {
+	errs models.ParseErrors
+}
+
+func (e *lineProtocolError) Error() string {
+	return e.errs.Error()
+}
+
+// handleWriteError encodes write errors, reporting each rejected point when
+// points were rejected and the position of each malformed line when the body
+// could not be parsed.
+func handleWriteError(ctx context.Context, h influxdb.HTTPErrorHandler, err error, w http.ResponseWriter) {
+	switch e := err.(type) {
+	case *pointsRejectedError:
+		w.Header().Set(PlatformErrorCodeHeader, influxdb.EUnprocessableEntity)
+		res := struct {
+			Code     string           `json:"code"`
+			Message  string           `json:"message"`
+			Rejected []pointRejection `json:"rejected"`
+		}{
+			Code:     influxdb.EUnprocessableEntity,
+			Message:  e.Error(),
+			Rejected: e.rejected,
+		}
+		_ = encodeResponse(ctx, w, http.StatusUnprocessableEntity, res)
+	case *lineProtocolError:
+		errs := make([]lineProtocolParseError, 0, len(e.errs))
+		for _, pe := range e.errs {
+			errs = append(errs, lineProtocolParseError{
+				Line:    pe.Line,
+				Offset:  pe.Offset,
+				Token:   pe.Token,
+				Message: pe.Error(),
+			})
+		}
+		w.Header().Set(PlatformErrorCodeHeader, influxdb.EInvalid)
+		res := struct {
+			Code    string                   `json:"code"`
+			Message string                   `json:"message"`
+			Line    int                      `json:"line"`
+			Errors  []lineProtocolParseError `json:"errors"`
+		}{
+			Code:    influxdb.EInvalid,
+			Message: e.Error(),
+			Line:    errs[0].Line,
+			Errors:  errs,
+		}
+		_ = encodeResponse(ctx, w, http.StatusBadRequest, res)
+	default:
+		h.HandleHTTPError(ctx, err, w)
 	}
 }
 
 // WriteHandler receives line protocol and sends to a publish function.
 type WriteHandler struct {
-	*httprouter.Router
+	*Router
 	influxdb.HTTPErrorHandler
 	Logger *zap.Logger
 
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	MaintenanceService  influxdb.MaintenanceService
 
 	PointsWriter storage.PointsWriter
+	WriteLimits  *WriteLimits
 
 	EventRecorder metric.EventRecorder
 }
@@ -76,10 +251,13 @@ func NewWriteHandler(b *WriteBackend) *WriteHandler {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		MaintenanceService:  b.MaintenanceService,
+		WriteLimits:         b.WriteLimits,
 		EventRecorder:       b.WriteEventRecorder,
 	}
 
 	h.HandlerFunc("POST", writePath, h.handleWrite)
+	h.HandlerFunc("POST", writeLinksPath, h.handlePostWriteLink)
 	return h
 }
 
@@ -96,15 +274,16 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	var requestBytes int
 	sw := newStatusResponseWriter(w)
 	w = sw
-	defer func() {
+	defer func(start time.Time) {
 		h.EventRecorder.Record(ctx, metric.Event{
 			OrgID:         orgID,
 			Endpoint:      r.URL.Path, // This should be sufficient for the time being as it should only be single endpoint.
 			RequestBytes:  requestBytes,
 			ResponseBytes: sw.responseBytes,
 			Status:        sw.code(),
+			Duration:      time.Since(start),
 		})
-	}()
+	}(time.Now())
 
 	in := r.Body
 	if r.Header.Get("Content-Encoding") == "gzip" {
@@ -185,7 +364,7 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.Allowed(*p) {
+	if !authorizer.Allowed(ctx, a, *p) {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EForbidden,
 			Op:   "http/handleWrite",
@@ -194,55 +373,134 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkMaintenance(ctx, h.MaintenanceService, org.ID, w); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	wctx := storage.WithFieldTypeConflictPolicy(ctx, bucket.FieldTypeConflictPolicy)
+	requestBytes, err = writePoints(wctx, h.PointsWriter, h.WriteLimits, in, org.ID, bucket.ID, req.Precision, logger)
+	if err != nil {
+		handleWriteError(ctx, h.HTTPErrorHandler, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writePoints reads line protocol from in, parses it with the given precision
+// and writes the resulting points into the bucket. Points outside the time
+// bounds of the limits are rejected with a *pointsRejectedError after the
+// other points are written. No points are written when one of them violates
+// the tag constraints of the token. The body of a write link request is also
+// limited by the size of the link. It returns the number of bytes read from
+// the request body.
+func writePoints(ctx context.Context, pw storage.PointsWriter, limits *WriteLimits, in io.Reader, orgID, bucketID influxdb.ID, precision string, logger *zap.Logger) (int, error) {
 	// TODO(jeff): we should be publishing with the org and bucket instead of
 	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
 	// be sure to remove this when it is there!
-	data, err := ioutil.ReadAll(in)
+	var body io.Reader = in
+	maxBodyBytes := limits.MaxBodyBytes()
+	if l := writeLinkFromContext(ctx); l != nil && (maxBodyBytes == 0 || l.maxBytes < maxBodyBytes) {
+		maxBodyBytes = l.maxBytes
+	}
+	if maxBodyBytes > 0 {
+		// read one byte past the limit to detect oversized bodies.
+		body = io.LimitReader(in, maxBodyBytes+1)
+	}
+	data, err := ioutil.ReadAll(body)
 	if err != nil {
 		logger.Error("Error reading body", zap.Error(err))
-		h.HandleHTTPError(ctx, &influxdb.Error{
+		return 0, &influxdb.Error{
 			Code: influxdb.EInternal,
-			Op:   "http/handleWrite",
+			Op:   "http/writePoints",
 			Msg:  fmt.Sprintf("unable to read data: %v", err),
 			Err:  err,
-		}, w)
-		return
+		}
 	}
 
-	requestBytes = len(data)
+	requestBytes := len(data)
+	if maxBodyBytes > 0 && int64(requestBytes) > maxBodyBytes {
+		return requestBytes, &influxdb.Error{
+			Code: influxdb.ERequestTooLarge,
+			Op:   "http/writePoints",
+			Msg:  fmt.Sprintf("request body exceeds the maximum of %d bytes", maxBodyBytes),
+		}
+	}
 	if requestBytes == 0 {
-		h.HandleHTTPError(ctx, &influxdb.Error{
+		return requestBytes, &influxdb.Error{
 			Code: influxdb.EInvalid,
-			Op:   "http/handleWrite",
+			Op:   "http/writePoints",
 			Msg:  "writing requires points",
-		}, w)
-		return
+		}
 	}
 
-	encoded := tsdb.EncodeName(org.ID, bucket.ID)
+	encoded := tsdb.EncodeName(orgID, bucketID)
 	mm := models.EscapeMeasurement(encoded[:])
-	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
+	now := time.Now()
+	points, err := models.ParsePointsWithPrecision(data, mm, now, precision)
 	if err != nil {
 		logger.Error("Error parsing points", zap.Error(err))
-		h.HandleHTTPError(ctx, &influxdb.Error{
+		if errs, ok := err.(models.ParseErrors); ok {
+			return requestBytes, &lineProtocolError{errs: errs}
+		}
+		return requestBytes, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Msg:  err.Error(),
-		}, w)
-		return
+		}
+	}
+
+	if err := storage.CheckTagConstraints(tagConstraints(ctx), points); err != nil {
+		logger.Info("Rejected points violating the tag constraints of the token", zap.Error(err))
+		return requestBytes, err
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+	points, rejected := limits.rejectPoints(points, now)
+	if len(points) == 0 && len(rejected) > 0 {
+		return requestBytes, &pointsRejectedError{rejected: rejected}
+	}
+
+	if err := pw.WritePoints(ctx, points); err != nil {
+		// the points that could be written were written; report the dropped ones.
+		if e, ok := err.(tsdb.PartialWriteError); ok {
+			logger.Info("Dropped points of partial write", zap.Error(e))
+			return requestBytes, &influxdb.Error{
+				Code: influxdb.EUnprocessableEntity,
+				Op:   "http/writePoints",
+				Msg:  e.Error(),
+			}
+		}
 		logger.Error("Error writing points", zap.Error(err))
-		h.HandleHTTPError(ctx, &influxdb.Error{
+		return requestBytes, &influxdb.Error{
 			Code: influxdb.EInternal,
-			Op:   "http/handleWrite",
+			Op:   "http/writePoints",
 			Msg:  "unexpected error writing points to database",
 			Err:  err,
-		}, w)
-		return
+		}
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	if len(rejected) > 0 {
+		logger.Info("Rejected points outside the accepted time bounds", zap.Int("rejected", len(rejected)))
+		return requestBytes, &pointsRejectedError{rejected: rejected}
+	}
+
+	return requestBytes, nil
+}
+
+// tagConstraints returns the tag constraints of the authorizer on context,
+// which all written points must satisfy.
+func tagConstraints(ctx context.Context) []influxdb.Tag {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil
+	}
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		return a.TagConstraints
+	case *legacyUserAuthorizer:
+		return a.tagConstraints
+	}
+	return nil
 }
 
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
@@ -279,6 +537,10 @@ type WriteService struct {
 	Token              string
 	Precision          string
 	InsecureSkipVerify bool
+
+	// AuthorizationID is the ID of the authorization of the token. When it
+	// is set, requests are signed with the token instead of sending it.
+	AuthorizationID influxdb.ID
 }
 
 var _ influxdb.WriteService = (*WriteService)(nil)
@@ -314,7 +576,6 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r
 
 	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
 	req.Header.Set("Content-Encoding", "gzip")
-	SetToken(s.Token, req)
 
 	org, err := orgID.Encode()
 	if err != nil {
@@ -332,6 +593,15 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r
 	params.Set("precision", string(precision))
 	req.URL.RawQuery = params.Encode()
 
+	// the query is part of the signature, so the request is signed last.
+	if s.AuthorizationID.Valid() {
+		if err := SignRequest(req, s.AuthorizationID, s.Token); err != nil {
+			return err
+		}
+	} else {
+		SetToken(s.Token, req)
+	}
+
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
 
 	resp, err := hc.Do(req)
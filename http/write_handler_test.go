@@ -10,11 +10,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http/metric"
 	httpmock "github.com/influxdata/influxdb/http/mock"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
 	influxtesting "github.com/influxdata/influxdb/testing"
 	"go.uber.org/zap/zaptest"
 )
@@ -84,6 +87,12 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 		bucket    *influxdb.Bucket       // bucket to return in bucket service
 		bucketErr error                  // err to return in bucket service
 		writeErr  error                  // err to return from the points writer
+
+		maxBodyBytes   int64         // write body size limit
+		maxPointAge    time.Duration // how far in the past points may be
+		maxPointFuture time.Duration // how far in the future points may be
+
+		maintenances []*influxdb.Maintenance // maintenances in effect
 	}
 
 	// want is the expected output of the HTTP endpoint
@@ -122,6 +131,97 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				code: 204,
 			},
 		},
+		{
+			name: "body larger than the limit is rejected",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:          testOrg("043e0780ee2b1000"),
+				bucket:       testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				maxBodyBytes: 5,
+			},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"request body exceeds the maximum of 5 bytes","op":"http/writePoints"}`,
+			},
+		},
+		{
+			name: "writes to an org in maintenance are unavailable",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				maintenances: []*influxdb.Maintenance{
+					{OrganizationID: influxtesting.IDPtr(influxtesting.MustIDBase16("043e0780ee2b1000")), Message: "migrating", RetryAfter: 60},
+				},
+			},
+			wants: wants{
+				code: 503,
+				body: `{"code":"unavailable","message":"organization is in maintenance mode: migrating"}`,
+			},
+		},
+		{
+			name: "body within the limit is accepted",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:          testOrg("043e0780ee2b1000"),
+				bucket:       testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				maxBodyBytes: 13,
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "points older than the max point age are rejected",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1 1\nm1,t1=v1 f1=2",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:         testOrg("043e0780ee2b1000"),
+				bucket:      testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				maxPointAge: time.Hour,
+			},
+			wants: wants{
+				code: 422,
+				body: `{"code":"unprocessable entity","message":"partial write: 1 points outside the accepted time bounds were rejected","rejected":[{"index":0,"time":"1970-01-01T00:00:00.000000001Z","reason":"timestamp is more than 1h0m0s in the past"}]}` + "\n",
+			},
+		},
+		{
+			name: "points further in the future than the max point future are rejected",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1 4102444800000000000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:            testOrg("043e0780ee2b1000"),
+				bucket:         testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				maxPointFuture: time.Hour,
+			},
+			wants: wants{
+				code: 422,
+				body: `{"code":"unprocessable entity","message":"partial write: 1 points outside the accepted time bounds were rejected","rejected":[{"index":0,"time":"2100-01-01T00:00:00Z","reason":"timestamp is more than 1h0m0s in the future"}]}` + "\n",
+			},
+		},
 		{
 			name: "points writer error is an internal error",
 			request: request{
@@ -153,7 +253,7 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			},
 			wants: wants{
 				code: 400,
-				body: `{"code":"invalid","message":"writing requires points"}`,
+				body: `{"code":"invalid","message":"writing requires points","op":"http/writePoints"}`,
 			},
 		},
 		{
@@ -219,7 +319,24 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			},
 			wants: wants{
 				code: 400,
-				body: `{"code":"invalid","message":"unable to parse 'invalid': missing fields"}`,
+				body: `{"code":"invalid","message":"unable to parse 'invalid': missing fields","line":1,"errors":[{"line":1,"offset":0,"token":"invalid","message":"unable to parse 'invalid': missing fields"}]}` + "\n",
+			},
+		},
+		{
+			name: "invalid lines report their positions",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:   "\xEF\xBB\xBFm1,t1=v1 f1=1\r\nm1,t1 f1=1\r\nm1 f1=1 bad\r\n",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"unable to parse 'm1,t1 f1=1': missing tag value\nunable to parse 'm1 f1=1 bad': bad timestamp","line":2,"errors":[{"line":2,"offset":18,"token":"m1,t1","message":"unable to parse 'm1,t1 f1=1': missing tag value"},{"line":3,"offset":38,"token":"bad","message":"unable to parse 'm1 f1=1 bad': bad timestamp"}]}` + "\n",
 			},
 		},
 		{
@@ -236,7 +353,40 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			},
 			wants: wants{
 				code: 403,
-				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
+				body: `{"code":"forbidden","message":"insufficient permissions for write","op":"http/handleWrite"}`,
+			},
+		},
+		{
+			name: "points with the tags of the token are accepted",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,host=web-01 f1=1,f2=2\nm2,host=web-01,t1=v1 f1=1",
+				auth:   tagConstrainedWritePermission("043e0780ee2b1000", "04504b356e23b000", "host", "web-01"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "forbidden to write points without the tags of the token",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,host=web-01 f1=1\nm1,host=web-02 f1=1",
+				auth:   tagConstrainedWritePermission("043e0780ee2b1000", "04504b356e23b000", "host", "web-01"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 403,
+				body: `{"code":"forbidden","message":"point of measurement \"m1\" does not have the tag host=web-01 required by the token"}`,
 			},
 		},
 		{
@@ -267,13 +417,25 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				return tt.state.bucket, tt.state.bucketErr
 			}
 
+			limits := &WriteLimits{}
+			limits.SetMaxBodyBytes(tt.state.maxBodyBytes)
+			limits.SetMaxPointAge(tt.state.maxPointAge)
+			limits.SetMaxPointFuture(tt.state.maxPointFuture)
+
+			maintenance := mock.NewMaintenanceService()
+			maintenance.FindMaintenancesFn = func(context.Context) ([]*influxdb.Maintenance, error) {
+				return tt.state.maintenances, nil
+			}
+
 			b := &APIBackend{
 				HTTPErrorHandler:    DefaultErrorHandler,
 				Logger:              zaptest.NewLogger(t),
 				OrganizationService: orgs,
 				BucketService:       buckets,
+				MaintenanceService:  maintenance,
 				PointsWriter:        &mock.PointsWriter{Err: tt.state.writeErr},
 				WriteEventRecorder:  &metric.NopEventRecorder{},
+				WriteLimits:         limits,
 			}
 			writeHandler := NewWriteHandler(NewWriteBackend(b))
 			handler := httpmock.NewAuthMiddlewareHandler(writeHandler, tt.request.auth)
@@ -304,6 +466,54 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 
 var DefaultErrorHandler = ErrorHandler(0)
 
+// policyPointsWriter records the field type conflict policy of the writes.
+type policyPointsWriter struct {
+	mock.PointsWriter
+	policy influxdb.FieldTypeConflictPolicy
+}
+
+func (w *policyPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.policy = storage.FieldTypeConflictPolicyFromContext(ctx)
+	return w.PointsWriter.WritePoints(ctx, points)
+}
+
+func TestWriteHandler_FieldTypeConflictPolicy(t *testing.T) {
+	bucket := testBucket("043e0780ee2b1000", "04504b356e23b000")
+	bucket.FieldTypeConflictPolicy = influxdb.FieldTypeConflictCoerce
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		return testOrg("043e0780ee2b1000"), nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		return bucket, nil
+	}
+	pw := &policyPointsWriter{}
+
+	b := &APIBackend{
+		HTTPErrorHandler:    DefaultErrorHandler,
+		Logger:              zaptest.NewLogger(t),
+		OrganizationService: orgs,
+		BucketService:       buckets,
+		MaintenanceService:  mock.NewMaintenanceService(),
+		PointsWriter:        pw,
+		WriteEventRecorder:  &metric.NopEventRecorder{},
+		WriteLimits:         &WriteLimits{},
+	}
+	handler := httpmock.NewAuthMiddlewareHandler(NewWriteHandler(NewWriteBackend(b)), bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"))
+
+	r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/write?org=043e0780ee2b1000&bucket=04504b356e23b000", strings.NewReader("m1,t1=v1 f1=1"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status code: got %d want %d: %s", w.Code, http.StatusNoContent, w.Body.String())
+	}
+	if pw.policy != influxdb.FieldTypeConflictCoerce {
+		t.Errorf("unexpected field type conflict policy: got %q want %q", pw.policy, influxdb.FieldTypeConflictCoerce)
+	}
+}
+
 func bucketWritePermission(org, bucket string) *influxdb.Authorization {
 	oid := influxtesting.MustIDBase16(org)
 	bid := influxtesting.MustIDBase16(bucket)
@@ -323,6 +533,12 @@ func bucketWritePermission(org, bucket string) *influxdb.Authorization {
 	}
 }
 
+func tagConstrainedWritePermission(org, bucket, key, value string) *influxdb.Authorization {
+	a := bucketWritePermission(org, bucket)
+	a.TagConstraints = []influxdb.Tag{{Key: key, Value: value}}
+	return a
+}
+
 func testOrg(org string) *influxdb.Organization {
 	oid := influxtesting.MustIDBase16(org)
 	return &influxdb.Organization{
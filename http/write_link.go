@@ -0,0 +1,316 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	pcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// Write links are short-lived URLs writing a single batch of points to a
+// bucket without a token, e.g. for browsers or embedded devices that should
+// not hold long-lived tokens. A link is signed with the token of an
+// authorization allowed to write to the bucket, so that revoking or
+// deactivating the authorization invalidates its links. The query of a link
+// is
+//
+//	org=<org ID>&bucket=<bucket ID>&credential=<authorization ID>&expires=<unix seconds>&maxBytes=<n>&nonce=<nonce>&signature=<hex>
+//
+// where the signature is the HMAC-SHA256, keyed with the token, of the
+// newline separated write path, org, bucket, expires, maxBytes and nonce.
+// Other parameters, e.g. precision, may be added to the query. A link is used
+// up by its first request, even when the write fails. The WriteLinkService of
+// the AuthenticationHandler records the links used until they expire.
+const (
+	writeLinksPath = "/api/v2/write/links"
+
+	// DefaultWriteLinkTTL is how long write links are valid if the request
+	// minting them does not say.
+	DefaultWriteLinkTTL = 15 * time.Minute
+	// MaxWriteLinkTTL is the longest write links may be valid.
+	MaxWriteLinkTTL = time.Hour
+	// DefaultWriteLinkMaxBytes is the size limit of the decompressed body of
+	// the write of a link if the request minting it does not say.
+	DefaultWriteLinkMaxBytes = 1 << 20
+)
+
+var (
+	errWriteLinkMalformed = errors.New("malformed write link")
+	errWriteLinkExpired   = errors.New("write link expired")
+	errWriteLinkMismatch  = errors.New("write link signature does not match")
+	errWriteLinkDisabled  = errors.New("write links are not enabled")
+	errWriteLinkNotWrite  = errors.New("write links only authorize writes")
+	errWriteLinkForbidden = errors.New("write link authorization cannot write to the bucket")
+)
+
+// writeLink is the signed query of a write link.
+type writeLink struct {
+	orgID     platform.ID
+	bucketID  platform.ID
+	authID    platform.ID
+	expires   int64
+	maxBytes  int64
+	nonce     string
+	signature []byte
+}
+
+type writeLinkCtxKey struct{}
+
+// withWriteLink returns a context of the request of the write link.
+func withWriteLink(ctx context.Context, l *writeLink) context.Context {
+	return context.WithValue(ctx, writeLinkCtxKey{}, l)
+}
+
+// writeLinkFromContext returns the write link authenticating the request,
+// or nil.
+func writeLinkFromContext(ctx context.Context) *writeLink {
+	l, _ := ctx.Value(writeLinkCtxKey{}).(*writeLink)
+	return l
+}
+
+// hasWriteLink returns whether the request is authenticated by a write link.
+func hasWriteLink(r *http.Request) bool {
+	return r.URL.Path == writePath && r.URL.Query().Get("signature") != ""
+}
+
+func parseWriteLink(qp url.Values) (*writeLink, error) {
+	l := &writeLink{nonce: qp.Get("nonce")}
+	if err := l.orgID.DecodeFromString(qp.Get("org")); err != nil {
+		return nil, errWriteLinkMalformed
+	}
+	if err := l.bucketID.DecodeFromString(qp.Get("bucket")); err != nil {
+		return nil, errWriteLinkMalformed
+	}
+	if err := l.authID.DecodeFromString(qp.Get("credential")); err != nil {
+		return nil, errWriteLinkMalformed
+	}
+	var err error
+	if l.expires, err = strconv.ParseInt(qp.Get("expires"), 10, 64); err != nil {
+		return nil, errWriteLinkMalformed
+	}
+	if l.maxBytes, err = strconv.ParseInt(qp.Get("maxBytes"), 10, 64); err != nil || l.maxBytes <= 0 {
+		return nil, errWriteLinkMalformed
+	}
+	if l.signature, err = hex.DecodeString(qp.Get("signature")); err != nil || len(l.signature) != sha256.Size {
+		return nil, errWriteLinkMalformed
+	}
+	if l.nonce == "" {
+		return nil, errWriteLinkMalformed
+	}
+	return l, nil
+}
+
+// stringToSign returns the canonical form of the link that is signed.
+func (l *writeLink) stringToSign() string {
+	return strings.Join([]string{
+		writePath,
+		l.orgID.String(),
+		l.bucketID.String(),
+		strconv.FormatInt(l.expires, 10),
+		strconv.FormatInt(l.maxBytes, 10),
+		l.nonce,
+	}, "\n")
+}
+
+// url returns the write URL of the link, relative to the server.
+func (l *writeLink) url() string {
+	qp := url.Values{}
+	qp.Set("org", l.orgID.String())
+	qp.Set("bucket", l.bucketID.String())
+	qp.Set("credential", l.authID.String())
+	qp.Set("expires", strconv.FormatInt(l.expires, 10))
+	qp.Set("maxBytes", strconv.FormatInt(l.maxBytes, 10))
+	qp.Set("nonce", l.nonce)
+	qp.Set("signature", hex.EncodeToString(l.signature))
+	return writePath + "?" + qp.Encode()
+}
+
+// extractWriteLinkAuthorization verifies the write link of the request with
+// the token of the authorization of its credential. The authorizer returned
+// is the authorization restricted to writing to the bucket of the link.
+func (h *AuthenticationHandler) extractWriteLinkAuthorization(r *http.Request) (platform.Authorizer, *writeLink, error) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		return nil, nil, errWriteLinkNotWrite
+	}
+	l, err := parseWriteLink(r.URL.Query())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	expires := time.Unix(l.expires, 0)
+	if !now.Before(expires) || expires.Sub(now) > MaxWriteLinkTTL {
+		return nil, nil, errWriteLinkExpired
+	}
+
+	p, err := platform.NewPermissionAtID(l.bucketID, platform.WriteAction, platform.BucketsResourceType, l.orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.WriteLinkService == nil {
+		return nil, nil, errWriteLinkDisabled
+	}
+	a, err := h.WriteLinkService.UseWriteLink(ctx, l.authID, l.signature, expires, func(a *platform.Authorization) error {
+		if a.Token == "" || !hmac.Equal(l.signature, computeSignature(a.Token, l.stringToSign())) {
+			return errWriteLinkMismatch
+		}
+		if !a.Allowed(*p) {
+			return errWriteLinkForbidden
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &platform.Authorization{
+		ID:             a.ID,
+		Status:         a.Status,
+		OrgID:          a.OrgID,
+		UserID:         a.UserID,
+		Permissions:    []platform.Permission{*p},
+		TagConstraints: a.TagConstraints,
+	}, l, nil
+}
+
+type postWriteLinkRequest struct {
+	BucketID  platform.ID       `json:"bucketID"`
+	MaxBytes  int64             `json:"maxBytes"`
+	ExpiresIn platform.Duration `json:"expiresIn"`
+}
+
+func decodePostWriteLinkRequest(r *http.Request) (*postWriteLinkRequest, error) {
+	req := &postWriteLinkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if !req.BucketID.Valid() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "bucketID is required",
+		}
+	}
+	if req.MaxBytes < 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "maxBytes must not be negative",
+		}
+	}
+	if req.MaxBytes == 0 {
+		req.MaxBytes = DefaultWriteLinkMaxBytes
+	}
+	if req.ExpiresIn.Duration < 0 || req.ExpiresIn.Duration > MaxWriteLinkTTL {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "expiresIn must be between 0s and " + MaxWriteLinkTTL.String(),
+		}
+	}
+	if req.ExpiresIn.Duration == 0 {
+		req.ExpiresIn.Duration = DefaultWriteLinkTTL
+	}
+	return req, nil
+}
+
+type writeLinkResponse struct {
+	URL       string      `json:"url"`
+	BucketID  platform.ID `json:"bucketID"`
+	MaxBytes  int64       `json:"maxBytes"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+// handlePostWriteLink is the HTTP handler for the POST /api/v2/write/links
+// route. Links are signed with the token of the request, which must be
+// allowed to write to the bucket.
+func (h *WriteHandler) handlePostWriteLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	auth, ok := a.(*platform.Authorization)
+	if !ok || auth.Token == "" {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   "http/handlePostWriteLink",
+			Msg:  "write links must be created with a token",
+		}, w)
+		return
+	}
+
+	req, err := decodePostWriteLinkRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	p, err := platform.NewPermissionAtID(b.ID, platform.WriteAction, platform.BucketsResourceType, b.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if !authorizer.Allowed(ctx, auth, *p) {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   "http/handlePostWriteLink",
+			Msg:  "insufficient permissions for write",
+		}, w)
+		return
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	expiresAt := time.Now().Add(req.ExpiresIn.Duration).Truncate(time.Second)
+	l := &writeLink{
+		orgID:    b.OrgID,
+		bucketID: b.ID,
+		authID:   auth.ID,
+		expires:  expiresAt.Unix(),
+		maxBytes: req.MaxBytes,
+		nonce:    hex.EncodeToString(nonce[:]),
+	}
+	l.signature = computeSignature(auth.Token, l.stringToSign())
+	h.Logger.Debug("write link created",
+		zap.String("bucket_id", b.ID.String()),
+		zap.String("authorization_id", auth.ID.String()),
+		zap.Time("expires_at", expiresAt))
+
+	res := writeLinkResponse{
+		URL:       l.url(),
+		BucketID:  b.ID,
+		MaxBytes:  l.maxBytes,
+		ExpiresAt: expiresAt.UTC(),
+	}
+	if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWriteLinks(t *testing.T) {
+	auth := bucketWritePermission("043e0780ee2b1000", "04504b356e23b000")
+	auth.ID = 1
+	auth.Token = "secret"
+	bucket := testBucket("043e0780ee2b1000", "04504b356e23b000")
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		return testOrg("043e0780ee2b1000"), nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		return bucket, nil
+	}
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id != bucket.ID {
+			return testBucket("043e0780ee2b1000", "04504b356e23b001"), nil
+		}
+		return bucket, nil
+	}
+	links := kv.NewService(inmem.NewKVStore())
+	if err := links.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := links.PutAuthorization(context.Background(), auth); err != nil {
+		t.Fatal(err)
+	}
+
+	writeHandler := NewWriteHandler(NewWriteBackend(&APIBackend{
+		HTTPErrorHandler:    DefaultErrorHandler,
+		Logger:              zaptest.NewLogger(t),
+		OrganizationService: orgs,
+		BucketService:       buckets,
+		MaintenanceService:  mock.NewMaintenanceService(),
+		PointsWriter:        &mock.PointsWriter{},
+		WriteEventRecorder:  &metric.NopEventRecorder{},
+		WriteLimits:         &WriteLimits{},
+	}))
+	authHandler := NewAuthenticationHandler(ErrorHandler(0))
+	authHandler.WriteLinkService = links
+	authHandler.Handler = writeHandler
+
+	mint := func(a influxdb.Authorizer, body string) (int, writeLinkResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/write/links", strings.NewReader(body))
+		httpmock.NewAuthMiddlewareHandler(writeHandler, a).ServeHTTP(w, r)
+		var res writeLinkResponse
+		if w.Code == http.StatusCreated {
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code, res
+	}
+	write := func(url, body string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		authHandler.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:9999"+url, strings.NewReader(body)))
+		return w.Code
+	}
+
+	code, link := mint(auth, `{"bucketID": "04504b356e23b000", "maxBytes": 20, "expiresIn": "5m"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("unexpected status minting a link: %d", code)
+	}
+	if link.MaxBytes != 20 || link.BucketID != bucket.ID {
+		t.Errorf("unexpected link: %+v", link)
+	}
+	if got := write(link.URL, "m1,t1=v1 f1=1"); got != http.StatusNoContent {
+		t.Errorf("unexpected status writing with the link: %d", got)
+	}
+	if got := write(link.URL, "m1,t1=v1 f1=1"); got != http.StatusUnauthorized {
+		t.Errorf("expected a used link to be rejected, got %d", got)
+	}
+
+	_, link = mint(auth, `{"bucketID": "04504b356e23b000", "maxBytes": 20}`)
+	if got := write(link.URL, "m1,t1=v1 f1=1\nm1,t1=v1 f1=2"); got != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected a body larger than the link to be rejected, got %d", got)
+	}
+
+	_, link = mint(auth, `{"bucketID": "04504b356e23b000", "maxBytes": 20}`)
+	if got := write(strings.Replace(link.URL, "maxBytes=20", "maxBytes=2000", 1), "m1,t1=v1 f1=1"); got != http.StatusUnauthorized {
+		t.Errorf("expected a tampered link to be rejected, got %d", got)
+	}
+
+	authHandler.WriteLinkService = nil
+	_, link = mint(auth, `{"bucketID": "04504b356e23b000"}`)
+	if got := write(link.URL, "m1,t1=v1 f1=1"); got != http.StatusUnauthorized {
+		t.Errorf("expected links to be rejected without a write link service, got %d", got)
+	}
+
+	if code, _ := mint(auth, `{"bucketID": "04504b356e23b001"}`); code != http.StatusForbidden {
+		t.Errorf("expected a link to a bucket the token cannot write to be forbidden, got %d", code)
+	}
+	if code, _ := mint(&influxdb.Session{}, `{"bucketID": "04504b356e23b000"}`); code != http.StatusForbidden {
+		t.Errorf("expected a link minted by a session to be forbidden, got %d", code)
+	}
+	if code, _ := mint(auth, `{"bucketID": "04504b356e23b000", "expiresIn": "2h"}`); code != http.StatusBadRequest {
+		t.Errorf("expected a link valid for too long to be invalid, got %d", code)
+	}
+}
@@ -0,0 +1,321 @@
+// Package ingest applies the ingest rules of buckets to the points written
+// to them.
+//
+// The points of the write path are exploded: every field of a line is a
+// point of its own, with the measurement and the field key as the tags
+// models.MeasurementTagKey and models.FieldKeyTagKey. Rules that change the
+// tags of a line are applied to all of its points.
+package ingest
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// PointsWriter writes points.
+type PointsWriter interface {
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+var _ influxdb.IngestRuleService = (*Service)(nil)
+
+// Service manages the ingest rules of buckets with the wrapped service and
+// applies them to the points written with its PointsWriter. The rules are
+// cached by bucket; the cache is reset whenever the rules are changed
+// through the Service, so all changes must go through it.
+type Service struct {
+	influxdb.IngestRuleService
+	logger *zap.Logger
+
+	mu    sync.RWMutex
+	rules map[influxdb.ID][]*rule // The compiled rules, by bucket ID.
+}
+
+// NewService returns a Service managing the rules with s.
+func NewService(s influxdb.IngestRuleService, logger *zap.Logger) *Service {
+	return &Service{
+		IngestRuleService: s,
+		logger:            logger,
+		rules:             make(map[influxdb.ID][]*rule),
+	}
+}
+
+// CreateIngestRule creates an ingest rule and resets the cache of the rules.
+func (s *Service) CreateIngestRule(ctx context.Context, r *influxdb.IngestRule) error {
+	defer s.reset()
+	return s.IngestRuleService.CreateIngestRule(ctx, r)
+}
+
+// UpdateIngestRule updates an ingest rule and resets the cache of the rules.
+func (s *Service) UpdateIngestRule(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+	defer s.reset()
+	return s.IngestRuleService.UpdateIngestRule(ctx, id, upd)
+}
+
+// DeleteIngestRule deletes an ingest rule and resets the cache of the rules.
+func (s *Service) DeleteIngestRule(ctx context.Context, id influxdb.ID) error {
+	defer s.reset()
+	return s.IngestRuleService.DeleteIngestRule(ctx, id)
+}
+
+func (s *Service) reset() {
+	s.mu.Lock()
+	s.rules = make(map[influxdb.ID][]*rule)
+	s.mu.Unlock()
+}
+
+// bucketRules returns the compiled rules of the bucket, ordered by position.
+func (s *Service) bucketRules(ctx context.Context, bucketID influxdb.ID) ([]*rule, error) {
+	s.mu.RLock()
+	rules, ok := s.rules[bucketID]
+	s.mu.RUnlock()
+	if ok {
+		return rules, nil
+	}
+
+	rs, _, err := s.IngestRuleService.FindIngestRules(ctx, influxdb.IngestRuleFilter{BucketID: &bucketID})
+	if err != nil {
+		return nil, err
+	}
+	rules = make([]*rule, 0, len(rs))
+	for _, r := range rs {
+		c, err := compile(r)
+		if err != nil {
+			// rules are validated when they are stored.
+			s.logger.Warn("Skipping invalid ingest rule", zap.Stringer("rule_id", r.ID), zap.Error(err))
+			continue
+		}
+		rules = append(rules, c)
+	}
+
+	s.mu.Lock()
+	s.rules[bucketID] = rules
+	s.mu.Unlock()
+	return rules, nil
+}
+
+// PointsWriter returns a PointsWriter writing points to w after applying the
+// ingest rules of their buckets.
+func (s *Service) PointsWriter(w PointsWriter) PointsWriter {
+	return &pointsWriter{w: w, s: s}
+}
+
+type pointsWriter struct {
+	w PointsWriter
+	s *Service
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	points, err := w.s.Apply(ctx, points)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	return w.w.WritePoints(ctx, points)
+}
+
+// Apply applies the ingest rules of their buckets to the points and returns
+// the points to write.
+func (s *Service) Apply(ctx context.Context, points []models.Point) ([]models.Point, error) {
+	var (
+		out     = points[:0:0]
+		buckets = make(map[string][]*rule)
+	)
+	// The points of a bucket are transformed together, since rules deriving
+	// tags from fields look at all the points of a line.
+	start := 0
+	for i := 1; i <= len(points); i++ {
+		if i < len(points) && string(points[i].Name()) == string(points[start].Name()) {
+			continue
+		}
+		batch := points[start:i]
+		start = i
+
+		name := string(batch[0].Name())
+		rules, ok := buckets[name]
+		if !ok {
+			if len(name) == len(tsdb.EncodeName(0, 0)) {
+				_, bucketID := tsdb.DecodeNameSlice([]byte(name))
+				var err error
+				if rules, err = s.bucketRules(ctx, bucketID); err != nil {
+					return nil, &influxdb.Error{
+						Code: influxdb.EInternal,
+						Msg:  "unable to find the ingest rules of the bucket",
+						Err:  err,
+					}
+				}
+			}
+			buckets[name] = rules
+		}
+		if len(rules) == 0 {
+			out = append(out, batch...)
+			continue
+		}
+		out = append(out, transform(batch, rules)...)
+	}
+	return out, nil
+}
+
+// rule is a compiled ingest rule.
+type rule struct {
+	*influxdb.IngestRule
+	measurement *regexp.Regexp
+}
+
+func compile(r *influxdb.IngestRule) (*rule, error) {
+	if err := r.Valid(); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(r.Measurement)
+	if err != nil {
+		return nil, err
+	}
+	return &rule{IngestRule: r, measurement: re}, nil
+}
+
+// entry is a point being transformed.
+type entry struct {
+	point       models.Point
+	tags        models.Tags // The tags of the point, including the measurement and field.
+	measurement []byte
+	field       []byte
+	changed     bool
+	dropped     bool
+}
+
+// lineKey returns the key of the line of the entry: its series without the
+// field, and its time.
+func (e *entry) lineKey() string {
+	tags := e.tags.Clone()
+	tags.Delete(models.FieldKeyTagKeyBytes)
+	return string(tags.HashKey()) + " " + strconv.FormatInt(e.point.UnixNano(), 10)
+}
+
+func (e *entry) setTag(key string, value []byte) {
+	e.tags.Set([]byte(key), value)
+	e.changed = true
+}
+
+// transform applies the rules, in order, to the points of a bucket.
+func transform(points []models.Point, rules []*rule) []models.Point {
+	entries := make([]*entry, len(points))
+	for i, p := range points {
+		tags := p.Tags().Clone()
+		entries[i] = &entry{
+			point:       p,
+			tags:        tags,
+			measurement: tags.Get(models.MeasurementTagKeyBytes),
+			field:       tags.Get(models.FieldKeyTagKeyBytes),
+		}
+	}
+
+	for _, r := range rules {
+		var matching []*entry
+		for _, e := range entries {
+			if !e.dropped && r.measurement.Match(e.measurement) {
+				matching = append(matching, e)
+			}
+		}
+
+		switch r.Type {
+		case influxdb.IngestRuleRenameTag:
+			for _, e := range matching {
+				if v := e.tags.Get([]byte(r.Tag)); v != nil {
+					e.tags.Delete([]byte(r.Tag))
+					e.setTag(r.NewTag, v)
+				}
+			}
+		case influxdb.IngestRuleDropField:
+			for _, e := range matching {
+				if string(e.field) == r.Field {
+					e.dropped = true
+				}
+			}
+		case influxdb.IngestRuleDeriveTag:
+			if r.Template != "" {
+				for _, e := range matching {
+					m := r.measurement.FindSubmatchIndex(e.measurement)
+					if v := r.measurement.Expand(nil, []byte(r.Template), e.measurement, m); len(v) > 0 {
+						e.setTag(r.Tag, v)
+					}
+				}
+				continue
+			}
+
+			values := make(map[string][]byte)
+			for _, e := range matching {
+				if string(e.field) == r.Field {
+					if v := fieldValue(e.point); len(v) > 0 {
+						values[e.lineKey()] = v
+					}
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			for _, e := range matching {
+				if v, ok := values[e.lineKey()]; ok {
+					e.setTag(r.Tag, v)
+				}
+			}
+		}
+	}
+
+	out := make([]models.Point, 0, len(entries))
+	for _, e := range entries {
+		if e.dropped {
+			continue
+		}
+		if e.changed {
+			e.point.SetTags(e.tags)
+		}
+		out = append(out, e.point)
+	}
+	return out
+}
+
+// fieldValue returns the value of the field of an exploded point as a tag value.
+func fieldValue(p models.Point) []byte {
+	itr := p.FieldIterator()
+	if !itr.Next() {
+		return nil
+	}
+	switch itr.Type() {
+	case models.Float:
+		v, err := itr.FloatValue()
+		if err != nil {
+			return nil
+		}
+		return strconv.AppendFloat(nil, v, 'f', -1, 64)
+	case models.Integer:
+		v, err := itr.IntegerValue()
+		if err != nil {
+			return nil
+		}
+		return strconv.AppendInt(nil, v, 10)
+	case models.Unsigned:
+		v, err := itr.UnsignedValue()
+		if err != nil {
+			return nil
+		}
+		return strconv.AppendUint(nil, v, 10)
+	case models.Boolean:
+		v, err := itr.BooleanValue()
+		if err != nil {
+			return nil
+		}
+		return strconv.AppendBool(nil, v)
+	case models.String:
+		return []byte(itr.StringValue())
+	}
+	return nil
+}
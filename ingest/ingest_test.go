@@ -0,0 +1,195 @@
+package ingest_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/ingest"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type pointsWriter struct {
+	points []models.Point
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.points = append(w.points, points...)
+	return nil
+}
+
+// series returns the series keys of the points, with the measurement and
+// field as tags, and without the organization and bucket.
+func series(points []models.Point) []string {
+	var keys []string
+	for _, p := range points {
+		var tags []string
+		for _, t := range p.Tags() {
+			k := string(t.Key)
+			switch k {
+			case models.MeasurementTagKey:
+				k = "_m"
+			case models.FieldKeyTagKey:
+				k = "_f"
+			}
+			tags = append(tags, k+"="+string(t.Value))
+		}
+		keys = append(keys, strings.Join(tags, ","))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestService_PointsWriter(t *testing.T) {
+	const orgID, bucketID, otherBucketID = influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	tests := []struct {
+		name  string
+		rules []*influxdb.IngestRule
+		lines string
+		want  []string
+	}{
+		{
+			name: "rename tag",
+			rules: []*influxdb.IngestRule{
+				{Type: influxdb.IngestRuleRenameTag, Tag: "hostname", NewTag: "host"},
+			},
+			lines: "cpu,hostname=a usage=1,idle=2 10\nmem,host=b used=3 10",
+			want: []string{
+				"_m=cpu,host=a,_f=idle",
+				"_m=cpu,host=a,_f=usage",
+				"_m=mem,host=b,_f=used",
+			},
+		},
+		{
+			name: "drop field of measurements",
+			rules: []*influxdb.IngestRule{
+				{Type: influxdb.IngestRuleDropField, Measurement: "^cpu", Field: "uptime"},
+			},
+			lines: "cpu,host=a usage=1,uptime=2 10\ncpu2,host=a uptime=3 10\nsystem,host=a uptime=4 10",
+			want: []string{
+				"_m=cpu,host=a,_f=usage",
+				"_m=system,host=a,_f=uptime",
+			},
+		},
+		{
+			name: "derive tag from field and drop the field",
+			rules: []*influxdb.IngestRule{
+				{Type: influxdb.IngestRuleDeriveTag, Tag: "region", Field: "region"},
+				{Type: influxdb.IngestRuleDropField, Field: "region"},
+			},
+			lines: "cpu,host=a usage=1,region=\"us-west\" 10\ncpu,host=a usage=2 20\ncpu,host=b usage=3,region=4i 10",
+			want: []string{
+				"_m=cpu,host=a,_f=usage",
+				"_m=cpu,host=a,region=us-west,_f=usage",
+				"_m=cpu,host=b,region=4,_f=usage",
+			},
+		},
+		{
+			name: "derive tag from measurement",
+			rules: []*influxdb.IngestRule{
+				{Type: influxdb.IngestRuleDeriveTag, Tag: "interface", Measurement: `^net_(\w+)$`, Template: "$1"},
+			},
+			lines: "net_eth0 bytes=1 10\nnet bytes=2 10",
+			want: []string{
+				"_m=net,_f=bytes",
+				"_m=net_eth0,interface=eth0,_f=bytes",
+			},
+		},
+		{
+			name: "rules apply in order",
+			rules: []*influxdb.IngestRule{
+				{Type: influxdb.IngestRuleRenameTag, Tag: "hostname", NewTag: "host"},
+				{Type: influxdb.IngestRuleRenameTag, Tag: "host", NewTag: "server"},
+			},
+			lines: "cpu,hostname=a usage=1 10",
+			want: []string{
+				"_m=cpu,server=a,_f=usage",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := mock.NewIngestRuleService()
+			rules.FindIngestRulesFn = func(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+				if *filter.BucketID != bucketID {
+					return nil, 0, nil
+				}
+				for i, r := range tt.rules {
+					r.ID, r.OrgID, r.BucketID, r.Name = influxdb.ID(i+1), orgID, bucketID, "rule"
+				}
+				return tt.rules, len(tt.rules), nil
+			}
+			s := ingest.NewService(rules, zaptest.NewLogger(t))
+
+			parse := func(bucketID influxdb.ID) []models.Point {
+				name := tsdb.EncodeName(orgID, bucketID)
+				points, err := models.ParsePointsWithPrecision([]byte(tt.lines), models.EscapeMeasurement(name[:]), time.Now(), "ns")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return points
+			}
+
+			w := &pointsWriter{}
+			if err := s.PointsWriter(w).WritePoints(context.Background(), parse(bucketID)); err != nil {
+				t.Fatal(err)
+			}
+			if got := series(w.points); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected series:\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+
+			// the points of buckets without rules are written unchanged.
+			w.points = nil
+			other := parse(otherBucketID)
+			if err := s.PointsWriter(w).WritePoints(context.Background(), other); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := series(w.points), series(other); !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected series of bucket without rules:\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+func TestService_ResetsCache(t *testing.T) {
+	var finds int
+	rules := mock.NewIngestRuleService()
+	rules.FindIngestRulesFn = func(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+		finds++
+		return nil, 0, nil
+	}
+	s := ingest.NewService(rules, zaptest.NewLogger(t))
+
+	name := tsdb.EncodeName(1, 2)
+	points, err := models.ParsePointsWithPrecision([]byte("cpu usage=1 10"), models.EscapeMeasurement(name[:]), time.Now(), "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := s.PointsWriter(&pointsWriter{})
+	for i := 0; i < 2; i++ {
+		if err := w.WritePoints(context.Background(), points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if finds != 1 {
+		t.Fatalf("expected the rules to be cached, found them %d times", finds)
+	}
+
+	if err := s.DeleteIngestRule(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+	if finds != 2 {
+		t.Fatalf("expected the rules to be found again after a change, found them %d times", finds)
+	}
+}
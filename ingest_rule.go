@@ -0,0 +1,190 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// ops for ingest rule errors and op logs.
+const (
+	OpFindIngestRuleByID = "FindIngestRuleByID"
+	OpFindIngestRules    = "FindIngestRules"
+	OpCreateIngestRule   = "CreateIngestRule"
+	OpUpdateIngestRule   = "UpdateIngestRule"
+	OpDeleteIngestRule   = "DeleteIngestRule"
+)
+
+// Ingest rule types.
+const (
+	// IngestRuleRenameTag renames the tag Tag to NewTag.
+	IngestRuleRenameTag = "renameTag"
+	// IngestRuleDropField drops the field Field.
+	IngestRuleDropField = "dropField"
+	// IngestRuleDeriveTag sets the tag Tag to the value of the field Field,
+	// or to Template expanded with the submatches of Measurement.
+	IngestRuleDeriveTag = "deriveTag"
+)
+
+// IngestRuleService represents a service for managing the ingest rules of buckets.
+type IngestRuleService interface {
+	// FindIngestRuleByID returns a single ingest rule by ID.
+	FindIngestRuleByID(ctx context.Context, id ID) (*IngestRule, error)
+
+	// FindIngestRules returns a list of ingest rules that match the filter
+	// and the total count of matching rules. The rules of a bucket are
+	// ordered by position.
+	FindIngestRules(ctx context.Context, filter IngestRuleFilter, opt ...FindOptions) ([]*IngestRule, int, error)
+
+	// CreateIngestRule creates a new ingest rule and sets r.ID with the new identifier.
+	CreateIngestRule(ctx context.Context, r *IngestRule) error
+
+	// UpdateIngestRule updates a single ingest rule with the changeset.
+	UpdateIngestRule(ctx context.Context, id ID, upd IngestRuleUpdate) (*IngestRule, error)
+
+	// DeleteIngestRule removes an ingest rule by ID.
+	DeleteIngestRule(ctx context.Context, id ID) error
+}
+
+// IngestRule transforms the points written to a bucket before they are
+// stored, e.g. to fix the naming inconsistencies of agents without
+// redeploying them. The rules of a bucket are applied in ascending position,
+// each to the result of the previous ones.
+type IngestRule struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID"`
+	BucketID    ID     `json:"bucketID"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Position    int    `json:"position"`
+	Type        string `json:"type"`
+	// Measurement is a regular expression restricting the rule to the
+	// measurements it matches. An empty expression matches all measurements.
+	Measurement string `json:"measurement,omitempty"`
+	// Tag is the tag renamed by renameTag rules and set by deriveTag rules.
+	Tag string `json:"tag,omitempty"`
+	// NewTag is the new name of the tag of renameTag rules.
+	NewTag string `json:"newTag,omitempty"`
+	// Field is the field dropped by dropField rules, or the field whose
+	// value deriveTag rules set the tag to.
+	Field string `json:"field,omitempty"`
+	// Template is the value deriveTag rules without a field set the tag to,
+	// where $1 or ${name} are replaced by the submatches of Measurement.
+	Template string `json:"template,omitempty"`
+
+	CRUDLog
+}
+
+// reservedTagKeys are the keys of tags that ingest rules do not rename or set.
+var reservedTagKeys = map[string]bool{
+	"_measurement": true,
+	"_field":       true,
+	"\x00":         true,
+	"\xff":         true,
+}
+
+func invalidIngestRule(format string, a ...interface{}) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf(format, a...),
+	}
+}
+
+// Valid returns an error if the ingest rule is invalid.
+func (r *IngestRule) Valid() error {
+	if !r.OrgID.Valid() {
+		return invalidIngestRule("ingest rule requires an organization")
+	}
+	if !r.BucketID.Valid() {
+		return invalidIngestRule("ingest rule requires a bucket")
+	}
+	if r.Name == "" {
+		return invalidIngestRule("ingest rule requires a name")
+	}
+	if _, err := regexp.Compile(r.Measurement); err != nil {
+		return invalidIngestRule("ingest rule measurement is not a valid regular expression: %v", err)
+	}
+
+	switch r.Type {
+	case IngestRuleRenameTag:
+		if r.Tag == "" || r.NewTag == "" {
+			return invalidIngestRule("renameTag rule requires a tag and a new tag")
+		}
+		if reservedTagKeys[r.Tag] || reservedTagKeys[r.NewTag] {
+			return invalidIngestRule("renameTag rule cannot rename the measurement or field")
+		}
+	case IngestRuleDropField:
+		if r.Field == "" {
+			return invalidIngestRule("dropField rule requires a field")
+		}
+	case IngestRuleDeriveTag:
+		if r.Tag == "" {
+			return invalidIngestRule("deriveTag rule requires a tag")
+		}
+		if reservedTagKeys[r.Tag] {
+			return invalidIngestRule("deriveTag rule cannot set the measurement or field")
+		}
+		if (r.Field == "") == (r.Template == "") {
+			return invalidIngestRule("deriveTag rule requires either a field or a template")
+		}
+		if r.Template != "" && r.Measurement == "" {
+			return invalidIngestRule("deriveTag rule with a template requires a measurement expression")
+		}
+	default:
+		return invalidIngestRule("ingest rule type must be one of %s, %s or %s", IngestRuleRenameTag, IngestRuleDropField, IngestRuleDeriveTag)
+	}
+	return nil
+}
+
+// IngestRuleFilter represents a set of filters that restrict the returned
+// ingest rules.
+type IngestRuleFilter struct {
+	ID       *ID
+	OrgID    *ID
+	BucketID *ID
+}
+
+// IngestRuleUpdate represents updates to an ingest rule. Only fields which
+// are set are updated.
+type IngestRuleUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Position    *int    `json:"position,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Measurement *string `json:"measurement,omitempty"`
+	Tag         *string `json:"tag,omitempty"`
+	NewTag      *string `json:"newTag,omitempty"`
+	Field       *string `json:"field,omitempty"`
+	Template    *string `json:"template,omitempty"`
+}
+
+// Apply applies the update to the ingest rule.
+func (u IngestRuleUpdate) Apply(r *IngestRule) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Position != nil {
+		r.Position = *u.Position
+	}
+	if u.Type != nil {
+		r.Type = *u.Type
+	}
+	if u.Measurement != nil {
+		r.Measurement = *u.Measurement
+	}
+	if u.Tag != nil {
+		r.Tag = *u.Tag
+	}
+	if u.NewTag != nil {
+		r.NewTag = *u.NewTag
+	}
+	if u.Field != nil {
+		r.Field = *u.Field
+	}
+	if u.Template != nil {
+		r.Template = *u.Template
+	}
+}
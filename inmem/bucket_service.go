@@ -279,6 +279,10 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
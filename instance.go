@@ -0,0 +1,52 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// InstanceStatus is the status of an influxd instance as last seen by discovery.
+type InstanceStatus string
+
+const (
+	// InstanceStatusUp is the status of an instance that answered the last probe.
+	InstanceStatusUp InstanceStatus = "up"
+	// InstanceStatusDown is the status of an instance that did not answer the last probe.
+	InstanceStatusDown InstanceStatus = "down"
+	// InstanceStatusUnknown is the status of an instance learned from a peer
+	// that has not been probed yet.
+	InstanceStatusUnknown InstanceStatus = "unknown"
+)
+
+// InstanceSource tells how an instance was discovered.
+type InstanceSource string
+
+const (
+	// InstanceSourceSelf is the source of the instance serving the request.
+	InstanceSourceSelf InstanceSource = "self"
+	// InstanceSourceStatic is the source of the instances of the configured peer list.
+	InstanceSourceStatic InstanceSource = "static"
+	// InstanceSourceDNS is the source of the instances resolved from DNS.
+	InstanceSourceDNS InstanceSource = "dns"
+	// InstanceSourceGossip is the source of the instances learned from the
+	// instances known by a peer.
+	InstanceSourceGossip InstanceSource = "gossip"
+)
+
+// Instance is an influxd instance of the topology known by discovery.
+type Instance struct {
+	// URL is the URL the instance advertises to its peers. It identifies the instance.
+	URL     string         `json:"url"`
+	Version string         `json:"version,omitempty"`
+	Status  InstanceStatus `json:"status"`
+	Source  InstanceSource `json:"source"`
+	Self    bool           `json:"self,omitempty"`
+	// LastSeen is the time the instance last answered a probe.
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// InstanceService lists the influxd instances known by the instance.
+type InstanceService interface {
+	// FindInstances returns the known instances, the instance itself first.
+	FindInstances(ctx context.Context) ([]*Instance, error)
+}
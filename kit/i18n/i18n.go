@@ -0,0 +1,256 @@
+// Package i18n translates the user-facing messages of the API into the
+// languages requested by clients with the Accept-Language header.
+//
+// Messages are written in English and translated with catalogs: a catalog of
+// a language maps English messages to their translations. The key of a
+// formatted message may contain the verbs of its format, e.g.
+//
+//	"bucket %s not found": "Bucket %s nicht gefunden"
+//
+// so that the values of a message are kept in its translation, as they
+// appear in the message. A translation may reorder the values with explicit
+// argument indexes, e.g. %[2]s. Messages without a translation are returned
+// in English.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// SourceLanguage is the language of the untranslated messages.
+var SourceLanguage = language.English
+
+// verbPattern matches the verbs of formatted messages and their translations.
+var verbPattern = regexp.MustCompile(`%(\[(\d+)\])?[sdvq]`)
+
+// message is the translation of a message.
+type message struct {
+	key         string
+	pattern     *regexp.Regexp // matches the formatted message, if the key has verbs.
+	translation string
+}
+
+// Catalog holds the translations of messages by language.
+type Catalog struct {
+	mu        sync.RWMutex
+	languages []language.Tag // The first language is SourceLanguage.
+	exact     map[language.Tag]map[string]string
+	formatted map[language.Tag][]message
+	matcher   language.Matcher
+}
+
+// NewCatalog returns a catalog without translations.
+func NewCatalog() *Catalog {
+	c := &Catalog{
+		languages: []language.Tag{SourceLanguage},
+		exact:     make(map[language.Tag]map[string]string),
+		formatted: make(map[language.Tag][]message),
+	}
+	c.matcher = language.NewMatcher(c.languages)
+	return c
+}
+
+// Add adds the translations of messages into the language.
+func (c *Catalog) Add(tag language.Tag, translations map[string]string) error {
+	var formatted []message
+	for key, translation := range translations {
+		if !verbPattern.MatchString(key) {
+			continue
+		}
+		if n, m := len(verbPattern.FindAllString(key, -1)), countArgs(translation); m > n {
+			return fmt.Errorf("translation of %q into %s uses %d values, the message has %d", key, tag, m, n)
+		}
+		formatted = append(formatted, message{
+			key:         key,
+			pattern:     formatPattern(key),
+			translation: translation,
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.exact[tag]; !ok {
+		c.exact[tag] = make(map[string]string, len(translations))
+		if tag != SourceLanguage {
+			c.languages = append(c.languages, tag)
+			c.matcher = language.NewMatcher(c.languages)
+		}
+	}
+	for key, translation := range translations {
+		c.exact[tag][key] = translation
+	}
+	c.formatted[tag] = append(c.formatted[tag], formatted...)
+	return nil
+}
+
+// LoadDir adds the catalogs of the JSON files of the directory. Each file
+// is named by the BCP 47 tag of its language, e.g. de.json or pt-BR.json,
+// and holds an object mapping messages to their translations.
+func (c *Catalog) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		tag, err := language.Parse(name)
+		if err != nil {
+			return fmt.Errorf("invalid language of message catalog %s: %v", path, err)
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var translations map[string]string
+		if err := json.Unmarshal(b, &translations); err != nil {
+			return fmt.Errorf("invalid message catalog %s: %v", path, err)
+		}
+		if err := c.Add(tag, translations); err != nil {
+			return fmt.Errorf("invalid message catalog %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Languages returns the languages of the catalog, starting with
+// SourceLanguage.
+func (c *Catalog) Languages() []language.Tag {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]language.Tag(nil), c.languages...)
+}
+
+// Localizer returns the localizer of the language of the catalog that best
+// matches the value of an Accept-Language header. SourceLanguage is used
+// if no language matches.
+func (c *Catalog) Localizer(acceptLanguage string) *Localizer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tag := SourceLanguage
+	if desired, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(desired) > 0 {
+		if _, i, conf := c.matcher.Match(desired...); conf != language.No {
+			tag = c.languages[i]
+		}
+	}
+	return &Localizer{catalog: c, tag: tag}
+}
+
+func (c *Catalog) translate(tag language.Tag, msg string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if translation, ok := c.exact[tag][msg]; ok {
+		return translation, true
+	}
+	for _, m := range c.formatted[tag] {
+		if args := m.pattern.FindStringSubmatch(msg); args != nil {
+			return format(m.translation, args[1:]), true
+		}
+	}
+	return msg, false
+}
+
+// Localizer translates messages into a language.
+type Localizer struct {
+	catalog *Catalog
+	tag     language.Tag
+}
+
+// Language returns the language of the translations.
+func (l *Localizer) Language() language.Tag {
+	return l.tag
+}
+
+// Translate returns the translation of the message, or the message if it
+// has no translation.
+func (l *Localizer) Translate(msg string) string {
+	translation, _ := l.TranslateOK(msg)
+	return translation
+}
+
+// TranslateOK returns the translation of the message and whether it has
+// one. The message is returned if it has no translation.
+func (l *Localizer) TranslateOK(msg string) (string, bool) {
+	if l.tag == SourceLanguage || msg == "" {
+		return msg, false
+	}
+	return l.catalog.translate(l.tag, msg)
+}
+
+type localizerKey struct{}
+
+// NewContext returns a context with the localizer.
+func NewContext(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext returns the localizer of the context, or nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return l
+}
+
+// formatPattern returns the pattern matching the messages formatted with
+// the key, capturing the value of each verb.
+func formatPattern(key string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range verbPattern.FindAllStringIndex(key, -1) {
+		b.WriteString(regexp.QuoteMeta(key[last:loc[0]]))
+		b.WriteString("(.*?)")
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(key[last:]))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// countArgs returns the number of values used by the verbs of the
+// translation.
+func countArgs(translation string) int {
+	n, next := 0, 0
+	for _, m := range verbPattern.FindAllStringSubmatch(translation, -1) {
+		i := next
+		if m[2] != "" {
+			i, _ = strconv.Atoi(m[2])
+			i--
+		}
+		next = i + 1
+		if next > n {
+			n = next
+		}
+	}
+	return n
+}
+
+// format replaces the verbs of the translation with the values, which are
+// already formatted.
+func format(translation string, args []string) string {
+	next := 0
+	return verbPattern.ReplaceAllStringFunc(translation, func(verb string) string {
+		i := next
+		if m := verbPattern.FindStringSubmatch(verb); m[2] != "" {
+			i, _ = strconv.Atoi(m[2])
+			i--
+		}
+		next = i + 1
+		if i < 0 || i >= len(args) {
+			return verb
+		}
+		return args[i]
+	})
+}
@@ -0,0 +1,127 @@
+package i18n_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/kit/i18n"
+	"golang.org/x/text/language"
+)
+
+func newCatalog(t *testing.T) *i18n.Catalog {
+	t.Helper()
+	c := i18n.NewCatalog()
+	if err := c.Add(language.German, map[string]string{
+		"unauthorized access":                    "nicht autorisierter Zugriff",
+		"bucket %s not found":                    "Bucket %s nicht gefunden",
+		"%s must be between %d and %d":           "%[1]s muss zwischen %[2]s und %[3]s liegen",
+		"organization %s has no bucket named %s": "Bucket %[2]s fehlt in Organisation %[1]s",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(language.BrazilianPortuguese, map[string]string{
+		"unauthorized access": "acesso não autorizado",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCatalog_Localizer(t *testing.T) {
+	c := newCatalog(t)
+
+	tests := []struct {
+		acceptLanguage string
+		want           language.Tag
+	}{
+		{acceptLanguage: "", want: language.English},
+		{acceptLanguage: "de", want: language.German},
+		{acceptLanguage: "de-CH", want: language.German},
+		{acceptLanguage: "fr, de;q=0.8, en;q=0.5", want: language.German},
+		{acceptLanguage: "en, de;q=0.8", want: language.English},
+		{acceptLanguage: "pt-BR", want: language.BrazilianPortuguese},
+		{acceptLanguage: "ja", want: language.English},
+		{acceptLanguage: "not a language", want: language.English},
+	}
+	for _, tt := range tests {
+		if got := c.Localizer(tt.acceptLanguage).Language(); got != tt.want {
+			t.Errorf("unexpected language for %q: got %s want %s", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizer_Translate(t *testing.T) {
+	l := newCatalog(t).Localizer("de")
+
+	tests := []struct {
+		msg  string
+		want string
+		ok   bool
+	}{
+		{msg: "unauthorized access", want: "nicht autorisierter Zugriff", ok: true},
+		{msg: "bucket telegraf not found", want: "Bucket telegraf nicht gefunden", ok: true},
+		{msg: "shard duration must be between 1 and 24", want: "shard duration muss zwischen 1 und 24 liegen", ok: true},
+		{msg: "organization acme has no bucket named telegraf", want: "Bucket telegraf fehlt in Organisation acme", ok: true},
+		{msg: "bucket telegraf not found!", want: "bucket telegraf not found!"},
+		{msg: "something else", want: "something else"},
+	}
+	for _, tt := range tests {
+		got, ok := l.TranslateOK(tt.msg)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("unexpected translation of %q: got %q, %v want %q, %v", tt.msg, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got := newCatalog(t).Localizer("en").Translate("unauthorized access"); got != "unauthorized access" {
+		t.Errorf("unexpected translation into English: %q", got)
+	}
+}
+
+func TestCatalog_AddInvalidTranslation(t *testing.T) {
+	c := i18n.NewCatalog()
+	err := c.Add(language.German, map[string]string{
+		"bucket %s not found": "Bucket %s in %s nicht gefunden",
+	})
+	if err == nil {
+		t.Fatal("expected an error for a translation with more values than the message")
+	}
+}
+
+func TestCatalog_LoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "fr.json"), []byte(`{"unauthorized access": "accès non autorisé"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := i18n.NewCatalog()
+	if err := c.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Localizer("fr-FR").Translate("unauthorized access"); got != "accès non autorisé" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "messages_1.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := i18n.NewCatalog().LoadDir(dir); err == nil {
+		t.Fatal("expected an error for a catalog not named by a language")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if l := i18n.FromContext(context.Background()); l != nil {
+		t.Fatal("expected no localizer")
+	}
+	l := newCatalog(t).Localizer("de")
+	if got := i18n.FromContext(i18n.NewContext(context.Background(), l)); got != l {
+		t.Fatal("expected the localizer of the context")
+	}
+}
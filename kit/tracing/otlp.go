@@ -0,0 +1,659 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"go.uber.org/zap"
+)
+
+// DefaultOTLPEndpoint is the default OTLP/HTTP traces endpoint of a local
+// OpenTelemetry collector.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+
+// otlpScopeName is the instrumentation scope of the exported spans.
+const otlpScopeName = "github.com/influxdata/influxdb"
+
+// OTLP span kinds and status codes, see
+// https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/trace/v1/trace.proto.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+	otlpSpanKindProducer = 4
+	otlpSpanKindConsumer = 5
+
+	otlpStatusCodeError = 2
+)
+
+// OTLPTracer implements opentracing.Tracer and records spans as OpenTelemetry
+// spans, which Run exports in batches to an OTLP/HTTP endpoint using the
+// JSON encoding. Span contexts are propagated with the W3C traceparent and
+// tracestate headers; baggage items are not propagated across processes.
+// Spans of unsampled traces are propagated but not exported, and finished
+// spans are dropped rather than blocking when the buffer is full.
+type OTLPTracer struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint,
+	// e.g. http://localhost:4318/v1/traces.
+	Endpoint      string
+	ServiceName   string
+	Client        *http.Client
+	Logger        *zap.Logger
+	FlushInterval time.Duration
+	BatchSize     int
+
+	spans   chan otlpSpan
+	dropped uint64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewOTLPTracer returns a tracer exporting the spans of the service to the
+// OTLP/HTTP traces endpoint.
+func NewOTLPTracer(endpoint, serviceName string, logger *zap.Logger) *OTLPTracer {
+	var seed int64
+	if err := binary.Read(crand.Reader, binary.LittleEndian, &seed); err != nil {
+		seed = time.Now().UnixNano()
+	}
+	return &OTLPTracer{
+		Endpoint:      endpoint,
+		ServiceName:   serviceName,
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		Logger:        logger,
+		FlushInterval: 5 * time.Second,
+		BatchSize:     512,
+		spans:         make(chan otlpSpan, 4096),
+		rnd:           rand.New(rand.NewSource(seed)),
+	}
+}
+
+// randomID fills id with random bytes, making sure it is not all zeros,
+// which is an invalid trace or span ID.
+func (t *OTLPTracer) randomID(id []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		t.rnd.Read(id)
+		for _, b := range id {
+			if b != 0 {
+				return
+			}
+		}
+	}
+}
+
+func (t *OTLPTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	startOpts := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&startOpts)
+	}
+	if startOpts.StartTime.IsZero() {
+		startOpts.StartTime = time.Now()
+	}
+
+	s := &otlpSpanRecorder{
+		tracer: t,
+		span: otlpSpan{
+			name:  operationName,
+			kind:  otlpSpanKindInternal,
+			start: startOpts.StartTime,
+		},
+		ctx: OTLPSpanContext{sampled: true},
+	}
+	for _, ref := range startOpts.References {
+		parent, ok := ref.ReferencedContext.(OTLPSpanContext)
+		if !ok {
+			continue
+		}
+		s.ctx.traceID = parent.traceID
+		s.ctx.sampled = parent.sampled
+		s.ctx.traceState = parent.traceState
+		s.ctx.baggage = copyBaggage(parent.baggage)
+		s.span.parentSpanID = parent.spanID
+		break
+	}
+	if s.ctx.traceID == ([16]byte{}) {
+		t.randomID(s.ctx.traceID[:])
+	}
+	t.randomID(s.ctx.spanID[:])
+
+	for k, v := range startOpts.Tags {
+		s.SetTag(k, v)
+	}
+	return s
+}
+
+func (t *OTLPTracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	ctx, ok := sm.(OTLPSpanContext)
+	if !ok {
+		return fmt.Errorf("unsupported span context %T", sm)
+	}
+	switch format {
+	case opentracing.TextMap, opentracing.HTTPHeaders:
+		w, ok := carrier.(opentracing.TextMapWriter)
+		if !ok {
+			return fmt.Errorf("carrier must be an opentracing.TextMapWriter for text map and http header formats, got %T", carrier)
+		}
+		w.Set(TraceParentHeader, ctx.traceParent().String())
+		if ctx.traceState != "" {
+			w.Set(TraceStateHeader, ctx.traceState)
+		}
+		return nil
+	default:
+		return opentracing.ErrUnsupportedFormat
+	}
+}
+
+func (t *OTLPTracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	switch format {
+	case opentracing.TextMap, opentracing.HTTPHeaders:
+	default:
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	r, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, fmt.Errorf("carrier must be an opentracing.TextMapReader for text map and http header formats, got %T", carrier)
+	}
+
+	var parent string
+	var states []string
+	_ = r.ForeachKey(func(k, v string) error {
+		switch http.CanonicalHeaderKey(k) {
+		case http.CanonicalHeaderKey(TraceParentHeader):
+			parent = v
+		case http.CanonicalHeaderKey(TraceStateHeader):
+			states = append(states, v)
+		}
+		return nil
+	})
+	if parent == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	tp, ok := parseTraceParent(parent)
+	if !ok {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	ctx := OTLPSpanContext{sampled: tp.Sampled}
+	if _, err := hex.Decode(ctx.traceID[:], []byte(tp.TraceID)); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if _, err := hex.Decode(ctx.spanID[:], []byte(tp.SpanID)); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if ts, ok := parseTraceState(states); ok {
+		ctx.traceState = ts
+	}
+	return ctx, nil
+}
+
+func (t *OTLPTracer) record(s otlpSpan) {
+	select {
+	case t.spans <- s:
+	default:
+		atomic.AddUint64(&t.dropped, 1)
+	}
+}
+
+// Run exports batched spans until ctx is canceled.
+func (t *OTLPTracer) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlpSpan, 0, t.BatchSize)
+	flush := func(ctx context.Context) {
+		if dropped := atomic.SwapUint64(&t.dropped, 0); dropped > 0 {
+			t.Logger.Warn("Dropped trace spans", zap.Uint64("dropped", dropped))
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.export(ctx, batch); err != nil {
+			t.Logger.Error("Failed to export trace spans", zap.Int("spans", len(batch)), zap.Error(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is already canceled, export the spans finished so far
+			// without it.
+			for {
+				select {
+				case s := <-t.spans:
+					batch = append(batch, s)
+					if len(batch) >= t.BatchSize {
+						flush(context.Background())
+					}
+					continue
+				default:
+				}
+				break
+			}
+			flush(context.Background())
+			return
+		case <-ticker.C:
+			flush(ctx)
+		case s := <-t.spans:
+			batch = append(batch, s)
+			if len(batch) >= t.BatchSize {
+				flush(ctx)
+			}
+		}
+	}
+}
+
+// export sends the spans to the endpoint as an OTLP
+// ExportTraceServiceRequest.
+func (t *OTLPTracer) export(ctx context.Context, spans []otlpSpan) error {
+	req := otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{otlpAttribute("service.name", t.ServiceName)},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: otlpScopeName},
+				Spans: make([]otlpJSONSpan, 0, len(spans)),
+			}},
+		}},
+	}
+	for _, s := range spans {
+		req.ResourceSpans[0].ScopeSpans[0].Spans = append(req.ResourceSpans[0].ScopeSpans[0].Spans, s.json())
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest("POST", t.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	resp, err := t.Client.Do(r.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, body)
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// OTLPSpanContext implements opentracing.SpanContext for spans of the
+// OTLPTracer.
+type OTLPSpanContext struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	sampled    bool
+	traceState string
+	baggage    map[string]string
+}
+
+// TraceID returns the hex encoded W3C trace ID of the span.
+func (c OTLPSpanContext) TraceID() string {
+	return hex.EncodeToString(c.traceID[:])
+}
+
+func (c OTLPSpanContext) traceParent() traceParent {
+	return traceParent{
+		TraceID: hex.EncodeToString(c.traceID[:]),
+		SpanID:  hex.EncodeToString(c.spanID[:]),
+		Sampled: c.sampled,
+	}
+}
+
+func (c OTLPSpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+func copyBaggage(b map[string]string) map[string]string {
+	if len(b) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(b))
+	for k, v := range b {
+		c[k] = v
+	}
+	return c
+}
+
+// otlpSpan is a finished span to be exported.
+type otlpSpan struct {
+	traceID      [16]byte
+	spanID       [8]byte
+	parentSpanID [8]byte
+	traceState   string
+	name         string
+	kind         int
+	start, end   time.Time
+	attributes   []otlpKeyValue
+	events       []otlpEvent
+	failed       bool
+}
+
+func (s otlpSpan) json() otlpJSONSpan {
+	j := otlpJSONSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		TraceState:        s.traceState,
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        s.attributes,
+		Events:            s.events,
+	}
+	if s.parentSpanID != ([8]byte{}) {
+		j.ParentSpanID = hex.EncodeToString(s.parentSpanID[:])
+	}
+	if s.failed {
+		j.Status = &otlpStatus{Code: otlpStatusCodeError}
+	}
+	return j
+}
+
+// otlpSpanRecorder implements opentracing.Span, recording the span until it
+// is finished.
+type otlpSpanRecorder struct {
+	tracer *OTLPTracer
+
+	mu       sync.Mutex
+	span     otlpSpan
+	ctx      OTLPSpanContext
+	finished bool
+}
+
+func (s *otlpSpanRecorder) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+func (s *otlpSpanRecorder) FinishWithOptions(opts opentracing.FinishOptions) {
+	if opts.FinishTime.IsZero() {
+		opts.FinishTime = time.Now()
+	}
+	for _, r := range opts.LogRecords {
+		s.logFields(r.Timestamp, r.Fields...)
+	}
+
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	span := s.span
+	span.traceID, span.spanID, span.traceState = s.ctx.traceID, s.ctx.spanID, s.ctx.traceState
+	span.end = opts.FinishTime
+	sampled := s.ctx.sampled
+	s.mu.Unlock()
+
+	if sampled {
+		s.tracer.record(span)
+	}
+}
+
+func (s *otlpSpanRecorder) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx := s.ctx
+	ctx.baggage = copyBaggage(s.ctx.baggage)
+	return ctx
+}
+
+func (s *otlpSpanRecorder) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.span.name = operationName
+	return s
+}
+
+func (s *otlpSpanRecorder) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch key {
+	case string(ext.SpanKind):
+		s.span.kind = otlpSpanKind(value)
+		return s
+	case string(ext.Error):
+		if failed, ok := value.(bool); ok {
+			s.span.failed = failed
+			return s
+		}
+	}
+	s.span.attributes = append(s.span.attributes, otlpAttribute(key, value))
+	return s
+}
+
+func otlpSpanKind(v interface{}) int {
+	switch fmt.Sprint(v) {
+	case string(ext.SpanKindRPCServerEnum):
+		return otlpSpanKindServer
+	case string(ext.SpanKindRPCClientEnum):
+		return otlpSpanKindClient
+	case string(ext.SpanKindProducerEnum):
+		return otlpSpanKindProducer
+	case string(ext.SpanKindConsumerEnum):
+		return otlpSpanKindConsumer
+	default:
+		return otlpSpanKindInternal
+	}
+}
+
+func (s *otlpSpanRecorder) LogFields(fields ...log.Field) {
+	s.logFields(time.Now(), fields...)
+}
+
+// logFields records the fields as an event, named by the "event" field if
+// present.
+func (s *otlpSpanRecorder) logFields(ts time.Time, fields ...log.Field) {
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	e := otlpEvent{
+		TimeUnixNano: strconv.FormatInt(ts.UnixNano(), 10),
+		Name:         "log",
+	}
+	for _, f := range fields {
+		if f.Key() == "event" {
+			e.Name = fmt.Sprint(f.Value())
+			continue
+		}
+		e.Attributes = append(e.Attributes, otlpAttribute(f.Key(), f.Value()))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.span.events = append(s.span.events, e)
+}
+
+func (s *otlpSpanRecorder) LogKV(keyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(keyValues...)
+	if err != nil {
+		s.LogFields(log.Error(err), log.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+func (s *otlpSpanRecorder) SetBaggageItem(restrictedKey string, value string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := copyBaggage(s.ctx.baggage)
+	if b == nil {
+		b = make(map[string]string)
+	}
+	b[restrictedKey] = value
+	s.ctx.baggage = b
+	return s
+}
+
+func (s *otlpSpanRecorder) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx.baggage[restrictedKey]
+}
+
+func (s *otlpSpanRecorder) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent is deprecated, as such it is not implemented.
+func (s *otlpSpanRecorder) LogEvent(event string) {
+	panic("use of deprecated LogEvent: not implemented")
+}
+
+// LogEventWithPayload is deprecated, as such it is not implemented.
+func (s *otlpSpanRecorder) LogEventWithPayload(event string, payload interface{}) {
+	panic("use of deprecated LogEventWithPayload: not implemented")
+}
+
+// Log is deprecated, as such it is not implemented.
+func (s *otlpSpanRecorder) Log(data opentracing.LogData) {
+	panic("use of deprecated Log: not implemented")
+}
+
+// The types below are the JSON encoding of an OTLP ExportTraceServiceRequest.
+// 64 bit integers are encoded as strings and IDs as hex strings.
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope      `json:"scope"`
+	Spans []otlpJSONSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpJSONSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	TraceState        string         `json:"traceState,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func otlpAttribute(key string, value interface{}) otlpKeyValue {
+	kv := otlpKeyValue{Key: key}
+	intValue := func(i int64) {
+		s := strconv.FormatInt(i, 10)
+		kv.Value.IntValue = &s
+	}
+	switch v := value.(type) {
+	case bool:
+		kv.Value.BoolValue = &v
+	case int:
+		intValue(int64(v))
+	case int8:
+		intValue(int64(v))
+	case int16:
+		intValue(int64(v))
+	case int32:
+		intValue(int64(v))
+	case int64:
+		intValue(v)
+	case uint8:
+		intValue(int64(v))
+	case uint16:
+		intValue(int64(v))
+	case uint32:
+		intValue(int64(v))
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			s := strconv.FormatUint(uint64(v), 10)
+			kv.Value.StringValue = &s
+			break
+		}
+		intValue(int64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			s := strconv.FormatUint(v, 10)
+			kv.Value.StringValue = &s
+			break
+		}
+		intValue(int64(v))
+	case float32:
+		f := float64(v)
+		kv.Value.DoubleValue = &f
+	case float64:
+		kv.Value.DoubleValue = &v
+	case string:
+		kv.Value.StringValue = &v
+	default:
+		s := fmt.Sprint(v)
+		kv.Value.StringValue = &s
+	}
+	// JSON has no representation of NaN and infinities.
+	if f := kv.Value.DoubleValue; f != nil && (math.IsNaN(*f) || math.IsInf(*f, 0)) {
+		s := strconv.FormatFloat(*f, 'g', -1, 64)
+		kv.Value.DoubleValue, kv.Value.StringValue = nil, &s
+	}
+	return kv
+}
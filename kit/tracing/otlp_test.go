@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"go.uber.org/zap"
+)
+
+func TestOTLPTracer_Propagation(t *testing.T) {
+	tracer := NewOTLPTracer(DefaultOTLPEndpoint, "test", zap.NewNop())
+
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	h.Set(TraceStateHeader, "congo=t61rcWkgMzE")
+	parent, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	span := tracer.StartSpan("request", opentracing.ChildOf(parent))
+	out := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(out)); err != nil {
+		t.Fatal(err)
+	}
+
+	tp, ok := parseTraceParent(out.Get(TraceParentHeader))
+	if !ok {
+		t.Fatalf("invalid injected traceparent %q", out.Get(TraceParentHeader))
+	}
+	if tp.TraceID != "0af7651916cd43dd8448eb211c80319c" || !tp.Sampled {
+		t.Errorf("unexpected injected traceparent %+v", tp)
+	}
+	if tp.SpanID == "b7ad6b7169203331" {
+		t.Error("expected the child span to have its own span ID")
+	}
+	if got, want := out.Get(TraceStateHeader), "congo=t61rcWkgMzE"; got != want {
+		t.Errorf("injected tracestate %q, want %q", got, want)
+	}
+
+	if _, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{})); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected no span context without a traceparent, got %v", err)
+	}
+}
+
+func TestOTLPTracer_Export(t *testing.T) {
+	requests := make(chan otlpExportRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		var req otlpExportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		requests <- req
+	}))
+	defer srv.Close()
+
+	tracer := NewOTLPTracer(srv.URL, "influxd", zap.NewNop())
+	tracer.FlushInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracer.Run(ctx)
+	}()
+
+	root := tracer.StartSpan("request", ext.SpanKindRPCServer)
+	root.SetTag("route", "/api/v2/query")
+	child := tracer.StartSpan("query", opentracing.ChildOf(root.Context()))
+	ext.Error.Set(child, true)
+	child.LogFields(log.String("event", "compile"), log.Int("statements", 2))
+	child.Finish()
+	root.Finish()
+
+	// spans of unsampled traces are not exported.
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	unsampled, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.StartSpan("unsampled", opentracing.ChildOf(unsampled)).Finish()
+
+	// the final batch is exported when ctx is canceled.
+	cancel()
+	<-done
+
+	var req otlpExportRequest
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("expected the spans to be exported")
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected export request %+v", req)
+	}
+	if attrs := req.ResourceSpans[0].Resource.Attributes; len(attrs) != 1 || attrs[0].Key != "service.name" || *attrs[0].Value.StringValue != "influxd" {
+		t.Errorf("unexpected resource attributes %+v", attrs)
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	q, r := spans[0], spans[1]
+	if q.Name != "query" || r.Name != "request" {
+		t.Fatalf("unexpected spans %q and %q", q.Name, r.Name)
+	}
+	if q.TraceID != r.TraceID || q.ParentSpanID != r.SpanID || r.ParentSpanID != "" {
+		t.Errorf("expected query to be a child of request, got %+v and %+v", q, r)
+	}
+	if r.Kind != otlpSpanKindServer || q.Kind != otlpSpanKindInternal {
+		t.Errorf("unexpected span kinds %d and %d", q.Kind, r.Kind)
+	}
+	if len(r.Attributes) != 1 || r.Attributes[0].Key != "route" || *r.Attributes[0].Value.StringValue != "/api/v2/query" {
+		t.Errorf("unexpected attributes %+v", r.Attributes)
+	}
+	if q.Status == nil || q.Status.Code != otlpStatusCodeError {
+		t.Errorf("expected the failed span to have an error status, got %+v", q.Status)
+	}
+	if len(q.Events) != 1 || q.Events[0].Name != "compile" || len(q.Events[0].Attributes) != 1 || *q.Events[0].Attributes[0].Value.IntValue != "2" {
+		t.Errorf("unexpected events %+v", q.Events)
+	}
+	if q.StartTimeUnixNano == "" || q.EndTimeUnixNano == "" {
+		t.Errorf("missing span times %+v", q)
+	}
+}
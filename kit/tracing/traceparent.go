@@ -0,0 +1,243 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header carrying the trace
+	// and parent span IDs, see https://www.w3.org/TR/trace-context/.
+	TraceParentHeader = "traceparent"
+
+	// TraceStateHeader is the W3C Trace Context header carrying vendor
+	// specific trace information along with the traceparent header.
+	TraceStateHeader = "tracestate"
+
+	// jaegerTraceHeader is the header used by the jaeger tracer to propagate
+	// span contexts. W3C trace contexts are translated to and from it.
+	jaegerTraceHeader = "uber-trace-id"
+
+	// jaegerBaggageHeaderPrefix prefixes the headers used by the jaeger
+	// tracer to propagate baggage items.
+	jaegerBaggageHeaderPrefix = "uberctx-"
+
+	// traceStateBaggageKey is the baggage item carrying the W3C tracestate
+	// of the incoming request to the spans started from it.
+	traceStateBaggageKey = "w3c-tracestate"
+
+	// maxTraceStateMembers is the maximum number of list members of a
+	// tracestate header.
+	maxTraceStateMembers = 32
+)
+
+// traceParent is a decoded W3C traceparent header.
+type traceParent struct {
+	TraceID string // 32 lower case hex characters
+	SpanID  string // 16 lower case hex characters
+	Sampled bool
+}
+
+// parseTraceParent decodes a version 00 W3C traceparent header value.
+func parseTraceParent(v string) (traceParent, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || parts[0] != "00" {
+		return traceParent{}, false
+	}
+
+	traceID, spanID, flags := strings.ToLower(parts[1]), strings.ToLower(parts[2]), parts[3]
+	if len(traceID) != 32 || !isHex(traceID) || strings.Trim(traceID, "0") == "" {
+		return traceParent{}, false
+	}
+	if len(spanID) != 16 || !isHex(spanID) || strings.Trim(spanID, "0") == "" {
+		return traceParent{}, false
+	}
+	f, err := strconv.ParseUint(flags, 16, 8)
+	if err != nil || len(flags) != 2 {
+		return traceParent{}, false
+	}
+
+	return traceParent{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: f&0x01 == 0x01,
+	}, true
+}
+
+// jaegerValue returns the traceparent in the jaeger propagation format
+// {trace-id}:{span-id}:{parent-span-id}:{flags}.
+func (tp traceParent) jaegerValue() string {
+	flags := "0"
+	if tp.Sampled {
+		flags = "1"
+	}
+	return tp.TraceID + ":" + tp.SpanID + ":0:" + flags
+}
+
+// traceParentFromJaeger converts a jaeger propagation header value into a
+// W3C traceparent.
+func traceParentFromJaeger(v string) (traceParent, bool) {
+	parts := strings.Split(v, ":")
+	if len(parts) != 4 {
+		return traceParent{}, false
+	}
+
+	traceID, spanID := strings.ToLower(parts[0]), strings.ToLower(parts[1])
+	if len(traceID) == 0 || len(traceID) > 32 || !isHex(traceID) {
+		return traceParent{}, false
+	}
+	if len(spanID) == 0 || len(spanID) > 16 || !isHex(spanID) {
+		return traceParent{}, false
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return traceParent{}, false
+	}
+
+	return traceParent{
+		TraceID: strings.Repeat("0", 32-len(traceID)) + traceID,
+		SpanID:  strings.Repeat("0", 16-len(spanID)) + spanID,
+		Sampled: flags&0x01 == 0x01,
+	}, true
+}
+
+// String encodes the traceparent as a header value.
+func (tp traceParent) String() string {
+	flags := "00"
+	if tp.Sampled {
+		flags = "01"
+	}
+	return "00-" + tp.TraceID + "-" + tp.SpanID + "-" + flags
+}
+
+// parseTraceState validates the values of the W3C tracestate headers and
+// returns them combined into a single header value. The tracestate is
+// discarded as a whole when any of its list members is invalid.
+func parseTraceState(values []string) (string, bool) {
+	var members []string
+	keys := make(map[string]bool)
+	for _, v := range values {
+		for _, m := range strings.Split(v, ",") {
+			m = strings.TrimSpace(m)
+			if m == "" {
+				continue
+			}
+			i := strings.IndexByte(m, '=')
+			if i < 0 {
+				return "", false
+			}
+			key, value := m[:i], m[i+1:]
+			if !isTraceStateKey(key) || !isTraceStateValue(value) || keys[key] {
+				return "", false
+			}
+			keys[key] = true
+			members = append(members, m)
+		}
+	}
+	if len(members) == 0 || len(members) > maxTraceStateMembers {
+		return "", false
+	}
+	return strings.Join(members, ","), true
+}
+
+// isTraceStateKey reports whether key is either a simple key or a multi
+// tenant key of the form tenant@system.
+func isTraceStateKey(key string) bool {
+	i := strings.IndexByte(key, '@')
+	if i < 0 {
+		return len(key) <= 256 && len(key) > 0 && isLowerAlpha(key[0]) && isTraceStateKeyChars(key[1:])
+	}
+	tenant, system := key[:i], key[i+1:]
+	if len(tenant) == 0 || len(tenant) > 241 || !(isLowerAlpha(tenant[0]) || isDigit(tenant[0])) || !isTraceStateKeyChars(tenant[1:]) {
+		return false
+	}
+	return len(system) > 0 && len(system) <= 14 && isLowerAlpha(system[0]) && isTraceStateKeyChars(system[1:])
+}
+
+func isTraceStateKeyChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(isLowerAlpha(c) || isDigit(c) || c == '_' || c == '-' || c == '*' || c == '/') {
+			return false
+		}
+	}
+	return true
+}
+
+// isTraceStateValue reports whether value is made of at most 256 printable
+// ASCII characters other than ',' and '=', not ending with a space.
+func isTraceStateValue(value string) bool {
+	if len(value) == 0 || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLowerAlpha(c byte) bool { return 'a' <= c && c <= 'z' }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// extractCarrier returns the carrier used to extract a span context from h.
+// When the request carries a W3C traceparent header but no tracer specific
+// context, the traceparent is presented to the tracer in its native format.
+func extractCarrier(h http.Header) opentracing.HTTPHeadersCarrier {
+	if h.Get(jaegerTraceHeader) != "" {
+		return opentracing.HTTPHeadersCarrier(h)
+	}
+	tp, ok := parseTraceParent(h.Get(TraceParentHeader))
+	if !ok {
+		return opentracing.HTTPHeadersCarrier(h)
+	}
+
+	c := make(http.Header, len(h)+1)
+	for k, v := range h {
+		c[k] = v
+	}
+	c.Set(jaegerTraceHeader, tp.jaegerValue())
+	return opentracing.HTTPHeadersCarrier(c)
+}
+
+// extractTraceState returns the W3C tracestate of h. It is only honored
+// along with a valid traceparent header.
+func extractTraceState(h http.Header) (string, bool) {
+	if _, ok := parseTraceParent(h.Get(TraceParentHeader)); !ok {
+		return "", false
+	}
+	return parseTraceState(h[http.CanonicalHeaderKey(TraceStateHeader)])
+}
+
+// injectTraceParent sets the W3C traceparent header from the tracer specific
+// context previously injected into h, and forwards the tracestate the span
+// was started with.
+func injectTraceParent(span opentracing.Span, h http.Header) {
+	tp, ok := traceParentFromJaeger(h.Get(jaegerTraceHeader))
+	if !ok {
+		return
+	}
+	h.Set(TraceParentHeader, tp.String())
+
+	// The tracestate travels as a baggage item between spans, but is sent
+	// in its own header rather than as tracer specific baggage.
+	h.Del(jaegerBaggageHeaderPrefix + traceStateBaggageKey)
+	if ts := span.BaggageItem(traceStateBaggageKey); ts != "" {
+		h.Set(TraceStateHeader, ts)
+	}
+}
@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value string
+		want  traceParent
+		ok    bool
+	}{
+		{
+			name:  "sampled",
+			value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			want:  traceParent{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331", Sampled: true},
+			ok:    true,
+		},
+		{
+			name:  "not sampled upper case",
+			value: "00-0AF7651916CD43DD8448EB211C80319C-B7AD6B7169203331-00",
+			want:  traceParent{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331"},
+			ok:    true,
+		},
+		{name: "unknown version", value: "01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-b7ad6b7169203331-01"},
+		{name: "zero span id", value: "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01"},
+		{name: "short trace id", value: "00-0af7651916cd43dd-b7ad6b7169203331-01"},
+		{name: "bad flags", value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-x"},
+		{name: "empty", value: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTraceParent(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("parseTraceParent() ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("parseTraceParent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTraceState(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		values []string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "single member",
+			values: []string{"congo=t61rcWkgMzE"},
+			want:   "congo=t61rcWkgMzE",
+			ok:     true,
+		},
+		{
+			name:   "combined headers",
+			values: []string{"rojo=00f067aa0ba902b7 , ", "congo=t61rcWkgMzE,tenant@vendor=a b"},
+			want:   "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE,tenant@vendor=a b",
+			ok:     true,
+		},
+		{name: "empty", values: []string{" , "}},
+		{name: "missing value", values: []string{"congo"}},
+		{name: "upper case key", values: []string{"Congo=t61rcWkgMzE"}},
+		{name: "duplicate key", values: []string{"congo=1", "congo=2"}},
+		{name: "bad value", values: []string{"congo=a=b"}},
+		{name: "long system id", values: []string{"tenant@averylongsystemid=1"}},
+		{name: "too many members", values: []string{strings.Repeat("a=1,", 32) + "b=1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTraceState(tt.values)
+			if ok != tt.ok {
+				t.Fatalf("parseTraceState() ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("parseTraceState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceParentWithJaeger(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close()
+
+	oldTracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(oldTracer)
+
+	t.Run("extract honors traceparent", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+		span, _ := ExtractFromHTTPRequest(req, "MyStruct")
+		defer span.Finish()
+
+		sc := span.Context().(jaeger.SpanContext)
+		if got, want := sc.TraceID().String(), "af7651916cd43dd8448eb211c80319c"; got != want {
+			t.Errorf("trace ID = %s, want %s", got, want)
+		}
+		if got, want := sc.ParentID().String(), "b7ad6b7169203331"; got != want {
+			t.Errorf("parent span ID = %s, want %s", got, want)
+		}
+		if req.Header.Get(jaegerTraceHeader) != "" {
+			t.Error("request headers should not be modified")
+		}
+	})
+
+	t.Run("tracestate is forwarded", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		req.Header.Set(TraceStateHeader, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7")
+
+		span, req := ExtractFromHTTPRequest(req, "MyStruct")
+		defer span.Finish()
+
+		child, _ := StartSpanFromContext(req.Context())
+		defer child.Finish()
+
+		out, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		InjectToHTTPRequest(child, out)
+
+		if got, want := out.Header.Get(TraceStateHeader), "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7"; got != want {
+			t.Errorf("tracestate = %q, want %q", got, want)
+		}
+		if tp, ok := parseTraceParent(out.Header.Get(TraceParentHeader)); !ok || tp.TraceID != "0af7651916cd43dd8448eb211c80319c" {
+			t.Errorf("unexpected traceparent %q", out.Header.Get(TraceParentHeader))
+		}
+		for k := range out.Header {
+			if strings.HasPrefix(strings.ToLower(k), jaegerBaggageHeaderPrefix) {
+				t.Errorf("unexpected baggage header %s", k)
+			}
+		}
+	})
+
+	t.Run("tracestate without traceparent is ignored", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(TraceStateHeader, "congo=t61rcWkgMzE")
+
+		span, _ := ExtractFromHTTPRequest(req, "MyStruct")
+		defer span.Finish()
+
+		if got := span.BaggageItem(traceStateBaggageKey); got != "" {
+			t.Errorf("unexpected tracestate %q", got)
+		}
+	})
+
+	t.Run("inject adds traceparent", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		span := tracer.StartSpan("operation name")
+		defer span.Finish()
+		InjectToHTTPRequest(span, req)
+
+		tp, ok := parseTraceParent(req.Header.Get(TraceParentHeader))
+		if !ok {
+			t.Fatalf("invalid traceparent header %q", req.Header.Get(TraceParentHeader))
+		}
+		sc := span.Context().(jaeger.SpanContext)
+		if got, want := tp.SpanID, fmt.Sprintf("%016x", uint64(sc.SpanID())); got != want {
+			t.Errorf("span ID = %s, want %s", got, want)
+		}
+		if !tp.Sampled {
+			t.Error("expected sampled traceparent")
+		}
+	})
+}
@@ -37,19 +37,25 @@ func LogError(span opentracing.Span, err error) error {
 }
 
 // InjectToHTTPRequest adds tracing headers to an HTTP request.
+// Besides the tracer's own headers, W3C traceparent and tracestate headers are
+// added when the tracer's context can be represented in that format.
 // Easier than adding this boilerplate everywhere.
 func InjectToHTTPRequest(span opentracing.Span, req *http.Request) {
 	err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
 	if err != nil {
 		LogError(span, err)
+		return
 	}
+	injectTraceParent(span, req.Header)
 }
 
 // ExtractFromHTTPRequest gets a child span of the parent referenced in HTTP request headers.
+// A W3C traceparent header is honored when no tracer specific headers are present,
+// and the W3C tracestate is forwarded to the requests injected from the span.
 // Returns the request with updated tracing context.
 // Easier than adding this boilerplate everywhere.
 func ExtractFromHTTPRequest(req *http.Request, handlerName string) (opentracing.Span, *http.Request) {
-	spanContext, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	spanContext, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, extractCarrier(req.Header))
 	if err != nil {
 		span, ctx := opentracing.StartSpanFromContext(req.Context(), "request")
 		annotateSpan(span, handlerName, req)
@@ -61,6 +67,9 @@ func ExtractFromHTTPRequest(req *http.Request, handlerName string) (opentracing.
 
 	span := opentracing.StartSpan("request", opentracing.ChildOf(spanContext), ext.RPCServerOption(spanContext))
 	annotateSpan(span, handlerName, req)
+	if ts, ok := extractTraceState(req.Header); ok {
+		span.SetBaggageItem(traceStateBaggageKey, ts)
+	}
 
 	return span, req.WithContext(opentracing.ContextWithSpan(req.Context(), span))
 }
@@ -0,0 +1,172 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrInvalidAlertAcknowledgementID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidAlertAcknowledgementID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided alert acknowledgement ID has invalid format",
+	}
+)
+
+var alertAcknowledgementsBucket = []byte("alertacknowledgementsv1")
+
+var _ influxdb.AlertAcknowledgementService = (*Service)(nil)
+
+func (s *Service) initializeAlertAcknowledgements(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(alertAcknowledgementsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateAlertAcknowledgement creates an acknowledgement and sets a.ID with
+// the new identifier. The time of the acknowledgement is set to now if it is
+// zero.
+func (s *Service) CreateAlertAcknowledgement(ctx context.Context, a *influxdb.AlertAcknowledgement) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if !a.OrgID.Valid() || !a.CheckID.Valid() || !a.UserID.Valid() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "alert acknowledgement requires an organization, a check and a user",
+			}
+		}
+
+		a.ID = s.IDGenerator.ID()
+		if a.Time.IsZero() {
+			a.Time = s.Now()
+		}
+		return s.putAlertAcknowledgement(ctx, tx, a)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateAlertAcknowledgement,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindAlertAcknowledgements returns the acknowledgements that match the
+// filter, the latest first.
+func (s *Service) FindAlertAcknowledgements(ctx context.Context, filter influxdb.AlertAcknowledgementFilter) ([]*influxdb.AlertAcknowledgement, int, error) {
+	var as []*influxdb.AlertAcknowledgement
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachAlertAcknowledgement(ctx, tx, func(a *influxdb.AlertAcknowledgement) {
+			if filterAlertAcknowledgement(a, filter) {
+				as = append(as, a)
+			}
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindAlertAcknowledgements,
+			Err: err,
+		}
+	}
+
+	sort.SliceStable(as, func(i, j int) bool {
+		return as[i].Time.After(as[j].Time)
+	})
+	return as, len(as), nil
+}
+
+// DeleteAlertAcknowledgements deletes the acknowledgements that match the
+// filter and are older than before.
+func (s *Service) DeleteAlertAcknowledgements(ctx context.Context, filter influxdb.AlertAcknowledgementFilter, before time.Time) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var keys [][]byte
+		err := s.forEachAlertAcknowledgement(ctx, tx, func(a *influxdb.AlertAcknowledgement) {
+			if !filterAlertAcknowledgement(a, filter) || !a.Time.Before(before) {
+				return
+			}
+			if k, err := a.ID.Encode(); err == nil {
+				keys = append(keys, k)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		// The keys are deleted after iterating, as the cursor must not be
+		// modified.
+		b, err := tx.Bucket(alertAcknowledgementsBucket)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteAlertAcknowledgements,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func filterAlertAcknowledgement(a *influxdb.AlertAcknowledgement, filter influxdb.AlertAcknowledgementFilter) bool {
+	return (filter.OrgID == nil || a.OrgID == *filter.OrgID) &&
+		(filter.CheckID == nil || a.CheckID == *filter.CheckID)
+}
+
+func (s *Service) forEachAlertAcknowledgement(ctx context.Context, tx Tx, fn func(*influxdb.AlertAcknowledgement)) error {
+	b, err := tx.Bucket(alertAcknowledgementsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		a := &influxdb.AlertAcknowledgement{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "unable to unmarshal alert acknowledgement",
+				Err:  err,
+			}
+		}
+		fn(a)
+	}
+	return nil
+}
+
+func (s *Service) putAlertAcknowledgement(ctx context.Context, tx Tx, a *influxdb.AlertAcknowledgement) error {
+	k, err := a.ID.Encode()
+	if err != nil {
+		return ErrInvalidAlertAcknowledgementID
+	}
+
+	v, err := json.Marshal(a)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(alertAcknowledgementsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
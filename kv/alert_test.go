@@ -0,0 +1,84 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestAlertAcknowledgements(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+			checkID, otherCheckID := influxdb.ID(10), influxdb.ID(11)
+			first := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+			if err := svc.CreateAlertAcknowledgement(ctx, &influxdb.AlertAcknowledgement{OrgID: orgID, CheckID: checkID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid error for acknowledgement without user, got %v", err)
+			}
+
+			for i, a := range []*influxdb.AlertAcknowledgement{
+				{OrgID: orgID, CheckID: checkID, UserID: 100, Time: first},
+				{OrgID: orgID, CheckID: checkID, UserID: 101, Time: first.Add(time.Hour)},
+				{OrgID: orgID, CheckID: otherCheckID, UserID: 100, Time: first.Add(2 * time.Hour)},
+				{OrgID: otherOrgID, CheckID: 12, UserID: 102},
+			} {
+				if err := svc.CreateAlertAcknowledgement(ctx, a); err != nil {
+					t.Fatal(err)
+				}
+				if !a.ID.Valid() {
+					t.Fatalf("acknowledgement %d has no ID", i)
+				}
+			}
+
+			as, n, err := svc.FindAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{OrgID: &orgID, CheckID: &checkID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 || as[0].UserID != 101 || as[1].UserID != 100 {
+				t.Fatalf("expected the acknowledgements of the check, the latest first, got %+v", as)
+			}
+
+			if _, n, _ := svc.FindAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{OrgID: &otherOrgID}); n != 1 {
+				t.Fatalf("expected 1 acknowledgement of the other organization, got %d", n)
+			}
+
+			if err := svc.DeleteAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{OrgID: &orgID}, first.Add(90*time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+			as, n, err = svc.FindAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 {
+				t.Fatalf("expected 2 acknowledgements after deleting the old ones, got %d", n)
+			}
+			for _, a := range as {
+				if a.CheckID == checkID {
+					t.Fatalf("unexpected acknowledgement %+v", a)
+				}
+			}
+		})
+	}
+}
@@ -15,7 +15,10 @@ var (
 	authIndex  = []byte("authorizationindexv1")
 )
 
-var _ influxdb.AuthorizationService = (*Service)(nil)
+var (
+	_ influxdb.AuthorizationService      = (*Service)(nil)
+	_ influxdb.AuthorizationUsageService = (*Service)(nil)
+)
 
 func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(authBucket); err != nil {
@@ -24,7 +27,7 @@ func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := authIndexBucket(tx); err != nil {
 		return err
 	}
-	return nil
+	return s.migrateAuths(ctx, tx)
 }
 
 // FindAuthorizationByID retrieves a authorization by id.
@@ -535,6 +538,34 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	return a, nil
 }
 
+// RecordAuthorizationUsage sets the last use of the authorizations to their
+// usage, unless they were used later. Deleted authorizations are skipped. The
+// use of an authorization is not an update, so the update time of the
+// authorizations is not changed.
+func (s *Service) RecordAuthorizationUsage(ctx context.Context, usage []influxdb.AuthorizationUsage) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		for _, u := range usage {
+			a, err := s.findAuthorizationByID(ctx, tx, u.ID)
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if a.LastUsedAt != nil && !u.Time.After(*a.LastUsedAt) {
+				continue
+			}
+
+			t := u.Time.UTC()
+			a.LastUsedAt, a.LastUsedIP = &t, u.IP
+			if err := s.putAuthorization(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func authIndexBucket(tx Tx) (Bucket, error) {
 	b, err := tx.Bucket([]byte(authIndex))
 	if err != nil {
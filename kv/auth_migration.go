@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"context"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var authMigrationBucket = []byte("authorizationmigrationsv1")
+
+// authMigration grants the authorizations that were created before a new
+// permission was introduced the permission, wherever they held the permission
+// it was split from.
+type authMigration struct {
+	// name identifies the migration in the migration bucket, so that it only
+	// applies to the authorizations that existed when it was introduced.
+	name string
+	// grant returns the permissions granted along with p.
+	grant func(p influxdb.Permission) []influxdb.Permission
+}
+
+// authMigrations are applied in order, once per store.
+var authMigrations = []authMigration{
+	{
+		// Listing the keys of secrets and deleting secrets used to require
+		// read and write access to the secrets.
+		name: "secretKeysAndDeletions",
+		grant: func(p influxdb.Permission) []influxdb.Permission {
+			if p.Resource.Type != influxdb.SecretsResourceType {
+				return nil
+			}
+			r := p.Resource
+			switch p.Action {
+			case influxdb.ReadAction:
+				r.Type = influxdb.SecretKeysResourceType
+				return []influxdb.Permission{{Action: influxdb.ReadAction, Resource: r}}
+			case influxdb.WriteAction:
+				r.Type = influxdb.SecretDeletionsResourceType
+				return []influxdb.Permission{{Action: influxdb.WriteAction, Resource: r}}
+			}
+			return nil
+		},
+	},
+	{
+		// Deleting the data of buckets used to require write access to them.
+		name: "bucketDeletions",
+		grant: func(p influxdb.Permission) []influxdb.Permission {
+			if p.Resource.Type != influxdb.BucketsResourceType || p.Action != influxdb.WriteAction {
+				return nil
+			}
+			return []influxdb.Permission{{Action: influxdb.DeleteAction, Resource: p.Resource}}
+		},
+	},
+}
+
+// migrateAuths applies the migrations that have not been applied yet to the
+// stored authorizations.
+func (s *Service) migrateAuths(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(authMigrationBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range authMigrations {
+		if _, err := b.Get([]byte(m.name)); err == nil {
+			continue
+		} else if !IsNotFound(err) {
+			return err
+		}
+
+		var as []*influxdb.Authorization
+		err := s.forEachAuthorization(ctx, tx, nil, func(a *influxdb.Authorization) bool {
+			as = append(as, a)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, a := range as {
+			if !m.apply(a) {
+				continue
+			}
+			if err := s.putAuthorization(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+
+		if err := b.Put([]byte(m.name), []byte{1}); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+// apply grants the permissions of the migration that the authorization does
+// not hold yet, and returns true if the authorization changed.
+func (m authMigration) apply(a *influxdb.Authorization) bool {
+	var granted []influxdb.Permission
+	for _, p := range a.Permissions {
+		for _, g := range m.grant(p) {
+			if !influxdb.PermissionAllowed(g, a.Permissions) && !influxdb.PermissionAllowed(g, granted) {
+				granted = append(granted, g)
+			}
+		}
+	}
+	a.Permissions = append(a.Permissions, granted...)
+	return len(granted) > 0
+}
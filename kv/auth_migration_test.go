@@ -0,0 +1,117 @@
+package kv_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_MigrateAuths(t *testing.T) {
+	store, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+	ctx := context.Background()
+
+	orgID := influxdb.ID(10)
+	secrets := func(a influxdb.Action) influxdb.Permission {
+		return influxdb.Permission{Action: a, Resource: influxdb.Resource{Type: influxdb.SecretsResourceType, OrgID: &orgID}}
+	}
+	secretKeys := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.SecretKeysResourceType, OrgID: &orgID}}
+	secretDeletions := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.SecretDeletionsResourceType, OrgID: &orgID}}
+	bucketID := influxdb.ID(20)
+	bucketWrite := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bucketID}}
+	bucketDelete := influxdb.Permission{Action: influxdb.DeleteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bucketID}}
+
+	// The authorizations are stored as they were before the migrations.
+	var allAccess []influxdb.Permission
+	for _, p := range influxdb.OperPermissions() {
+		if p.Resource.Type != influxdb.SecretKeysResourceType && p.Resource.Type != influxdb.SecretDeletionsResourceType && p.Action != influxdb.DeleteAction {
+			allAccess = append(allAccess, p)
+		}
+	}
+	old := []*influxdb.Authorization{
+		{ID: 1, Token: "operator", OrgID: orgID, UserID: 1, Status: influxdb.Active, Permissions: allAccess},
+		{ID: 2, Token: "secrets", OrgID: orgID, UserID: 1, Status: influxdb.Active, Permissions: []influxdb.Permission{secrets(influxdb.ReadAction), secrets(influxdb.WriteAction)}},
+		{ID: 3, Token: "read", OrgID: orgID, UserID: 1, Status: influxdb.Active, Permissions: []influxdb.Permission{secrets(influxdb.ReadAction)}},
+		{ID: 4, Token: "bucket", OrgID: orgID, UserID: 1, Status: influxdb.Active, Permissions: []influxdb.Permission{bucketWrite}},
+	}
+	err = store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		for _, a := range old {
+			k, _ := a.ID.Encode()
+			v, _ := json.Marshal(a)
+			if err := b.Put(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	tests := []struct {
+		id                influxdb.ID
+		wantKeys          bool
+		wantDeletes       bool
+		wantBucketDeletes bool
+	}{
+		{id: 1, wantKeys: true, wantDeletes: true, wantBucketDeletes: true},
+		{id: 2, wantKeys: true, wantDeletes: true},
+		{id: 3, wantKeys: true},
+		{id: 4, wantBucketDeletes: true},
+	}
+	for _, tt := range tests {
+		a, err := svc.FindAuthorizationByID(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Allowed(secretKeys); got != tt.wantKeys {
+			t.Errorf("authorization %s allowed %s = %v, want %v", tt.id, secretKeys, got, tt.wantKeys)
+		}
+		if got := a.Allowed(secretDeletions); got != tt.wantDeletes {
+			t.Errorf("authorization %s allowed %s = %v, want %v", tt.id, secretDeletions, got, tt.wantDeletes)
+		}
+		if got := a.Allowed(bucketDelete); got != tt.wantBucketDeletes {
+			t.Errorf("authorization %s allowed %s = %v, want %v", tt.id, bucketDelete, got, tt.wantBucketDeletes)
+		}
+	}
+
+	// Authorizations created after the migrations keep their permissions.
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	a := &influxdb.Authorization{OrgID: o.ID, UserID: u.ID, Permissions: []influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.SecretsResourceType, OrgID: &o.ID}},
+	}}
+	if err := svc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+	if a, err = svc.FindAuthorizationByID(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Permissions) != 1 {
+		t.Errorf("expected the permissions of a new authorization to be kept, got %v", a.Permissions)
+	}
+}
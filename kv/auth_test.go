@@ -3,6 +3,7 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
@@ -92,3 +93,61 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+func TestService_RecordAuthorizationUsage(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			u := &influxdb.User{Name: "user"}
+			if err := svc.CreateUser(ctx, u); err != nil {
+				t.Fatal(err)
+			}
+			o := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, o); err != nil {
+				t.Fatal(err)
+			}
+			a := &influxdb.Authorization{UserID: u.ID, OrgID: o.ID}
+			if err := svc.CreateAuthorization(ctx, a); err != nil {
+				t.Fatal(err)
+			}
+
+			used := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+			usage := []influxdb.AuthorizationUsage{
+				{ID: a.ID, Time: used, IP: "10.0.0.2"},
+				{ID: a.ID, Time: used.Add(-time.Hour), IP: "10.0.0.1"},
+				{ID: a.ID + 1, Time: used, IP: "10.0.0.3"},
+			}
+			if err := svc.RecordAuthorizationUsage(ctx, usage); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := svc.FindAuthorizationByToken(ctx, a.Token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.LastUsedAt == nil || !got.LastUsedAt.Equal(used) || got.LastUsedIP != "10.0.0.2" {
+				t.Fatalf("expected the latest use to be recorded, got %v from %q", got.LastUsedAt, got.LastUsedIP)
+			}
+			if !got.UpdatedAt.Equal(a.UpdatedAt) {
+				t.Fatalf("expected the use not to update the authorization, got %v", got.UpdatedAt)
+			}
+		})
+	}
+}
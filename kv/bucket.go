@@ -459,6 +459,16 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 				Err: pe,
 			}
 		}
+
+		settings, err := s.findOrganizationSettings(ctx, tx, b.OrgID)
+		if err != nil {
+			return err
+		}
+		settings.ApplyBucketDefaults(b)
+	}
+
+	if err := validBucketSchema(b); err != nil {
+		return err
 	}
 
 	if err := s.validBucketName(ctx, tx, b); err != nil {
@@ -656,6 +666,50 @@ func (s *Service) validBucketName(ctx context.Context, tx Tx, b *influxdb.Bucket
 	return err
 }
 
+// validBucketSchema checks the shard group duration, schema type and
+// retention rules of a bucket after the defaults of its organization were applied.
+func validBucketSchema(b *influxdb.Bucket) error {
+	if err := validShardGroupDuration(b); err != nil {
+		return err
+	}
+	if b.SchemaType != "" {
+		if err := b.SchemaType.Valid(); err != nil {
+			return err
+		}
+	}
+	if err := b.FieldTypeConflictPolicy.Valid(); err != nil {
+		return err
+	}
+	return validRetentionRules(b.RetentionRules)
+}
+
+func validRetentionRules(rules []influxdb.BucketRetentionRule) error {
+	for _, r := range rules {
+		if err := r.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validShardGroupDuration checks the shard group duration of a bucket
+// against its retention period.
+func validShardGroupDuration(b *influxdb.Bucket) error {
+	if b.ShardGroupDuration < 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "shard group duration must not be negative",
+		}
+	}
+	if b.RetentionPeriod > 0 && b.ShardGroupDuration > b.RetentionPeriod {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "shard group duration must not be longer than the retention period",
+		}
+	}
+	return nil
+}
+
 // UpdateBucket updates a bucket according the parameters set on upd.
 func (s *Service) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -696,10 +750,34 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
+	if upd.RetentionPeriod != nil || upd.ShardGroupDuration != nil {
+		if err := validShardGroupDuration(b); err != nil {
+			return nil, err
+		}
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
 
+	if upd.FieldTypeConflictPolicy != nil {
+		if err := upd.FieldTypeConflictPolicy.Valid(); err != nil {
+			return nil, err
+		}
+		b.FieldTypeConflictPolicy = *upd.FieldTypeConflictPolicy
+	}
+
+	if upd.RetentionRules != nil {
+		if err := validRetentionRules(*upd.RetentionRules); err != nil {
+			return nil, err
+		}
+		b.RetentionRules = *upd.RetentionRules
+	}
+
 	if upd.Name != nil {
 		b0, err := s.findBucketByName(ctx, tx, b.OrgID, *upd.Name)
 		if err == nil && b0.ID != id {
@@ -808,6 +886,18 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.deleteBucketDBRPMappings(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteBucketIngestRules(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteMeasurementLastWrites(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,84 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var bucketStatisticsBucket = []byte("bucketstatisticsv1")
+
+var _ influxdb.BucketStatisticsStore = (*Service)(nil)
+
+func (s *Service) initializeBucketStatistics(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(bucketStatisticsBucket)
+	return err
+}
+
+// FindBucketStatistics returns the stored statistics of the bucket.
+func (s *Service) FindBucketStatistics(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketStatistics, error) {
+	key, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var stats influxdb.BucketStatistics
+	err = s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(bucketStatisticsBucket)
+		if err != nil {
+			return err
+		}
+		v, err := b.Get(key)
+		if IsNotFound(err) {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "bucket statistics not found",
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(v, &stats)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return &stats, nil
+}
+
+// PutBucketStatistics replaces the stored statistics of the bucket.
+func (s *Service) PutBucketStatistics(ctx context.Context, stats *influxdb.BucketStatistics) error {
+	key, err := stats.BucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	v, err := json.Marshal(stats)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(bucketStatisticsBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
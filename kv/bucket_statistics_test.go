@@ -0,0 +1,59 @@
+package kv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestBucketStatistics(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			bucketID := influxdb.ID(10)
+			if _, err := svc.FindBucketStatistics(ctx, bucketID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected not found error, got %v", err)
+			}
+
+			want := &influxdb.BucketStatistics{
+				BucketID:    bucketID,
+				CollectedAt: time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC),
+				Measurements: map[string]influxdb.MeasurementStatistics{
+					"cpu": {Series: 10, Blocks: 40, MinTime: 1, MaxTime: 100},
+				},
+			}
+			if err := svc.PutBucketStatistics(ctx, want); err != nil {
+				t.Fatalf("unexpected error putting statistics: %v", err)
+			}
+
+			got, err := svc.FindBucketStatistics(ctx, bucketID)
+			if err != nil {
+				t.Fatalf("unexpected error finding statistics: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("statistics = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
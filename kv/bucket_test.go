@@ -3,6 +3,7 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
@@ -79,3 +80,76 @@ func initBucketService(s kv.Store, f influxdbtesting.BucketFields, t *testing.T)
 		}
 	}
 }
+
+func TestService_UpdateBucket_ShardGroupDuration(t *testing.T) {
+	retention := time.Hour
+	svc, _, done := initInmemBucketService(influxdbtesting.BucketFields{
+		Organizations: []*influxdb.Organization{
+			{
+				Name: "theorg",
+				ID:   influxdbtesting.MustIDBase16("020f755c3c083000"),
+			},
+		},
+		Buckets: []*influxdb.Bucket{
+			{
+				ID:              influxdbtesting.MustIDBase16("020f755c3c081000"),
+				OrgID:           influxdbtesting.MustIDBase16("020f755c3c083000"),
+				Name:            "bucket1",
+				RetentionPeriod: retention,
+			},
+		},
+	}, t)
+	defer done()
+
+	ctx := context.Background()
+	id := influxdbtesting.MustIDBase16("020f755c3c081000")
+
+	tests := []struct {
+		name string
+		upd  influxdb.BucketUpdate
+		want time.Duration
+		err  bool
+	}{
+		{
+			name: "shorter than retention",
+			upd:  influxdb.BucketUpdate{ShardGroupDuration: durationPtr(30 * time.Minute)},
+			want: 30 * time.Minute,
+		},
+		{
+			name: "longer than retention",
+			upd:  influxdb.BucketUpdate{ShardGroupDuration: durationPtr(2 * time.Hour)},
+			err:  true,
+		},
+		{
+			name: "negative",
+			upd:  influxdb.BucketUpdate{ShardGroupDuration: durationPtr(-time.Hour)},
+			err:  true,
+		},
+		{
+			name: "retention shorter than shard group duration",
+			upd:  influxdb.BucketUpdate{RetentionPeriod: durationPtr(10 * time.Minute)},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := svc.UpdateBucket(ctx, id, tt.upd)
+			if tt.err {
+				if influxdb.ErrorCode(err) != influxdb.EInvalid {
+					t.Fatalf("expected invalid error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.ShardGroupDuration != tt.want {
+				t.Errorf("unexpected shard group duration -want/+got\n\t- %v\n\t+ %v", tt.want, b.ShardGroupDuration)
+			}
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -366,7 +366,7 @@ func (s *Service) createCheck(ctx context.Context, tx Tx, c influxdb.CheckCreate
 }
 
 func (s *Service) createCheckTask(ctx context.Context, tx Tx, c influxdb.CheckCreate) (*influxdb.Task, error) {
-	script, err := c.GenerateFlux()
+	script, err := s.generateCheckFlux(ctx, tx, c.GetOrgID(), c)
 	if err != nil {
 		return nil, err
 	}
@@ -593,7 +593,7 @@ func (s *Service) updateCheck(ctx context.Context, tx Tx, id influxdb.ID, chk in
 	}
 
 	chk.SetTaskID(current.GetTaskID())
-	flux, err := chk.GenerateFlux()
+	flux, err := s.generateCheckFlux(ctx, tx, current.GetOrgID(), chk)
 	if err != nil {
 		return nil, err
 	}
@@ -633,6 +633,87 @@ func (s *Service) updateCheck(ctx context.Context, tx Tx, id influxdb.ID, chk in
 	return chk.Check, nil
 }
 
+// generateCheckFlux generates the flux of the task of a check with the
+// variables that the check references resolved to their current values.
+func (s *Service) generateCheckFlux(ctx context.Context, tx Tx, orgID influxdb.ID, c influxdb.Check) (string, error) {
+	script, err := c.GenerateFlux()
+	if err != nil {
+		return "", err
+	}
+
+	if len(check.ReferencedVariables(script)) == 0 {
+		return script, nil
+	}
+
+	vars, err := s.findOrganizationVariables(ctx, tx, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	return check.ResolveVariables(script, vars)
+}
+
+// findVariableChecks returns the checks of the organization that reference
+// the variable with the name.
+func (s *Service) findVariableChecks(ctx context.Context, tx Tx, orgID influxdb.ID, name string) ([]influxdb.Check, error) {
+	var (
+		cs     []influxdb.Check
+		genErr error
+	)
+	err := s.forEachCheck(ctx, tx, false, func(c influxdb.Check) bool {
+		if c.GetOrgID() != orgID {
+			return true
+		}
+
+		script, err := c.GenerateFlux()
+		if err != nil {
+			genErr = err
+			return false
+		}
+
+		for _, n := range check.ReferencedVariables(script) {
+			if n == name {
+				cs = append(cs, c)
+				break
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if genErr != nil {
+		return nil, genErr
+	}
+	return cs, nil
+}
+
+// updateVariableChecks regenerates the tasks of the checks that reference a
+// variable, so that they run with the current value of the variable. A check
+// that can no longer be generated, e.g. because the variable was renamed,
+// fails the update.
+func (s *Service) updateVariableChecks(ctx context.Context, tx Tx, orgID influxdb.ID, name string) error {
+	cs, err := s.findVariableChecks(ctx, tx, orgID, name)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cs {
+		flux, err := s.generateCheckFlux(ctx, tx, orgID, c)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("variable %q is referenced by check %q: %v", name, c.GetName(), err),
+			}
+		}
+
+		if _, err := s.updateTask(ctx, tx, c.GetTaskID(), influxdb.TaskUpdate{Flux: &flux}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func strPtr(s string) *string {
 	ss := new(string)
 	*ss = s
@@ -0,0 +1,110 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestService_CheckVariables(t *testing.T) {
+	store, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing check service: %v", err)
+	}
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	u := &influxdb.User{Name: "owner"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &influxdb.Variable{
+		OrganizationID: o.ID,
+		Name:           "environment",
+		Selected:       []string{"production"},
+		Arguments: &influxdb.VariableArguments{
+			Type:   "constant",
+			Values: influxdb.VariableConstantValues{"production", "staging"},
+		},
+	}
+	if err := svc.CreateVariable(ctx, v); err != nil {
+		t.Fatal(err)
+	}
+
+	every := notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "m"}}}
+	stale := notification.Duration{Values: []ast.Duration{{Magnitude: 10, Unit: "m"}}}
+	since := notification.Duration{Values: []ast.Duration{{Magnitude: 60, Unit: "s"}}}
+	c := influxdb.CheckCreate{
+		Check: &check.Deadman{
+			Base: check.Base{
+				Name:  "deadman",
+				OrgID: o.ID,
+				Every: &every,
+				Query: influxdb.DashboardQuery{
+					Text: `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r.env == v.environment)`,
+				},
+			},
+			TimeSince: &since,
+			StaleTime: &stale,
+			Level:     notification.Critical,
+		},
+		Status: influxdb.Active,
+	}
+	if err := svc.CreateCheck(ctx, c, u.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, c.GetTaskID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	if flux := taskFlux(); !strings.Contains(flux, `option v = {environment: "production"}`) {
+		t.Fatalf("expected variable to be resolved in task, got %s", flux)
+	}
+
+	if _, err := svc.UpdateVariable(ctx, v.ID, &influxdb.VariableUpdate{Selected: []string{"staging"}}); err != nil {
+		t.Fatal(err)
+	}
+	if flux := taskFlux(); !strings.Contains(flux, `option v = {environment: "staging"}`) {
+		t.Fatalf("expected task to be regenerated with the new variable value, got %s", flux)
+	}
+
+	if _, err := svc.UpdateVariable(ctx, v.ID, &influxdb.VariableUpdate{Name: "env"}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected renaming a referenced variable to conflict, got %v", err)
+	}
+	if err := svc.DeleteVariable(ctx, v.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected deleting a referenced variable to conflict, got %v", err)
+	}
+
+	c.Check.(*check.Deadman).Query.Text = `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r.env == v.region)`
+	if _, err := svc.UpdateCheck(ctx, c.GetID(), c); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected check referencing an unknown variable to be invalid, got %v", err)
+	}
+
+	if err := svc.DeleteCheck(ctx, c.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteVariable(ctx, v.ID); err != nil {
+		t.Fatalf("expected unreferenced variable to be deleted, got %v", err)
+	}
+}
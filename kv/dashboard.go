@@ -282,9 +282,25 @@ func (s *Service) findDashboards(ctx context.Context, tx Tx, filter influxdb.Das
 // CreateDashboard creates a influxdb dashboard and sets d.ID.
 func (s *Service) CreateDashboard(ctx context.Context, d *influxdb.Dashboard) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := d.DashboardSettings.Valid(); err != nil {
+			return err
+		}
+
+		if d.OrganizationID.Valid() {
+			settings, err := s.findOrganizationSettings(ctx, tx, d.OrganizationID)
+			if err != nil {
+				return err
+			}
+			settings.ApplyDashboardDefaults(d)
+		}
+
 		d.ID = s.IDGenerator.ID()
 
 		for _, cell := range d.Cells {
+			if err := cell.DashboardSettings.Valid(); err != nil {
+				return err
+			}
+
 			cell.ID = s.IDGenerator.ID()
 
 			if err := s.createCellView(ctx, tx, d.ID, cell.ID, nil); err != nil {
@@ -358,12 +374,21 @@ func (s *Service) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, cs
 				}
 			}
 
-			if _, ok := ids[cell.ID.String()]; !ok {
+			existing, ok := ids[cell.ID.String()]
+			if !ok {
 				return &influxdb.Error{
 					Code: influxdb.EConflict,
 					Msg:  "cannot replace cells that were not already present",
 				}
 			}
+
+			// The cells are replaced to move them, so cells replaced
+			// without settings keep theirs.
+			if cell.DashboardSettings == (influxdb.DashboardSettings{}) {
+				cell.DashboardSettings = existing.DashboardSettings
+			} else if err := cell.DashboardSettings.Valid(); err != nil {
+				return err
+			}
 		}
 
 		d.Cells = cs
@@ -382,6 +407,10 @@ func (s *Service) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, cs
 }
 
 func (s *Service) addDashboardCell(ctx context.Context, tx Tx, id influxdb.ID, cell *influxdb.Cell, opts influxdb.AddDashboardCellOptions) error {
+	if err := cell.DashboardSettings.Valid(); err != nil {
+		return err
+	}
+
 	d, err := s.findDashboardByID(ctx, tx, id)
 	if err != nil {
 		return err
@@ -842,8 +871,21 @@ func (s *Service) updateDashboard(ctx context.Context, tx Tx, id influxdb.ID, up
 }
 
 // DeleteDashboard deletes a dashboard and prunes it from the index.
+// When the trash is enabled the dashboard can be restored until its retention expires.
 func (s *Service) DeleteDashboard(ctx context.Context, id influxdb.ID) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
+		if s.trashEnabled() {
+			d, err := s.findDashboardByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if err := s.trashDashboard(ctx, tx, d); err != nil {
+				return &influxdb.Error{
+					Err: err,
+				}
+			}
+		}
+
 		if pe := s.deleteDashboard(ctx, tx, id); pe != nil {
 			return &influxdb.Error{
 				Err: pe,
@@ -878,6 +920,10 @@ func (s *Service) deleteDashboard(ctx context.Context, tx Tx, id influxdb.ID) er
 		return influxdb.NewError(influxdb.WithErrorErr(err))
 	}
 
+	if err := s.deleteResourceACL(ctx, tx, influxdb.DashboardsResourceType, id); err != nil {
+		return err
+	}
+
 	b, err := tx.Bucket(dashboardBucket)
 	if err != nil {
 		return err
@@ -2,9 +2,12 @@ package kv_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 )
@@ -70,3 +73,75 @@ func initDashboardService(s kv.Store, f influxdbtesting.DashboardFields, t *test
 		}
 	}
 }
+
+func TestDashboardSettings(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &influxdb.Dashboard{
+		OrganizationID: org.ID,
+		Name:           "d",
+		DashboardSettings: influxdb.DashboardSettings{
+			RefreshInterval: &influxdb.Duration{Duration: 10 * time.Second},
+			TimeRange:       &influxdb.DashboardTimeRange{Start: "-1h"},
+			Timezone:        "UTC",
+		},
+		Cells: []*influxdb.Cell{
+			{DashboardSettings: influxdb.DashboardSettings{TimeRange: &influxdb.DashboardTimeRange{Start: "-7d"}}},
+		},
+	}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := &influxdb.Dashboard{
+		OrganizationID:    org.ID,
+		Name:              "invalid",
+		DashboardSettings: influxdb.DashboardSettings{Timezone: "Nowhere"},
+	}
+	if err := svc.CreateDashboard(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("CreateDashboard() with an unknown timezone error = %v, want invalid", err)
+	}
+
+	// Moving the cells keeps their settings.
+	cellID := d.Cells[0].ID
+	if err := svc.ReplaceDashboardCells(ctx, d.ID, []*influxdb.Cell{{ID: cellID, CellProperty: influxdb.CellProperty{X: 2}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tz := "Europe/Paris"
+	upd := influxdb.DashboardUpdate{}
+	upd.Timezone = &tz
+	upd.Unset = []string{influxdb.DashboardSettingRefreshInterval}
+	if _, err := svc.UpdateDashboard(ctx, d.ID, upd); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.FindDashboardByID(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := influxdb.DashboardSettings{
+		TimeRange: &influxdb.DashboardTimeRange{Start: "-1h"},
+		Timezone:  "Europe/Paris",
+	}
+	if !reflect.DeepEqual(got.DashboardSettings, want) {
+		t.Errorf("dashboard settings = %+v, want %+v", got.DashboardSettings, want)
+	}
+	if c := got.Cells[0]; c.X != 2 || c.TimeRange == nil || c.TimeRange.Start != "-7d" {
+		t.Errorf("cell = %+v, want it moved with its time range", c)
+	}
+
+	cellUpd := influxdb.CellUpdate{}
+	cellUpd.TimeRange = &influxdb.DashboardTimeRange{Start: "-1h", Stop: "now"}
+	if _, err := svc.UpdateDashboardCell(ctx, d.ID, cellID, cellUpd); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("UpdateDashboardCell() with an invalid time range error = %v, want invalid", err)
+	}
+}
@@ -0,0 +1,419 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	dbrpBucket = []byte("dbrpv1")
+	dbrpIndex  = []byte("dbrpbyorganddbindexv1")
+)
+
+var _ influxdb.DBRPMappingServiceV2 = (*Service)(nil)
+
+func (s *Service) initializeDBRPMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dbrpBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(dbrpIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// dbrpIndexPrefix returns the prefix of the index keys of the mappings of a
+// database. Database names never contain a slash, so the prefix of one
+// database is never the prefix of another.
+func dbrpIndexPrefix(orgID influxdb.ID, db string) ([]byte, error) {
+	encodedOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	prefix := make([]byte, 0, len(encodedOrgID)+len(db)+1)
+	prefix = append(prefix, encodedOrgID...)
+	prefix = append(prefix, db...)
+	return append(prefix, '/'), nil
+}
+
+func dbrpIndexKey(m *influxdb.DBRPMappingV2) ([]byte, error) {
+	prefix, err := dbrpIndexPrefix(m.OrganizationID, m.Database)
+	if err != nil {
+		return nil, err
+	}
+	encodedID, err := m.ID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, encodedID...), nil
+}
+
+// FindDBRPMappingByID returns a single dbrp mapping by ID.
+func (s *Service) FindDBRPMappingByID(ctx context.Context, id influxdb.ID) (*influxdb.DBRPMappingV2, error) {
+	var m *influxdb.DBRPMappingV2
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		m, err = s.findDBRPMappingByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDBRPMappingByID,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+func (s *Service) findDBRPMappingByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.DBRPMappingV2, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dbrpBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrDBRPNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m influxdb.DBRPMappingV2
+	if err := json.Unmarshal(v, &m); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &m, nil
+}
+
+func filterDBRPMappingsFn(filter influxdb.DBRPMappingFilterV2) func(m *influxdb.DBRPMappingV2) bool {
+	return func(m *influxdb.DBRPMappingV2) bool {
+		return (filter.ID == nil || *filter.ID == m.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == m.OrganizationID) &&
+			(filter.BucketID == nil || *filter.BucketID == m.BucketID) &&
+			(filter.Database == nil || *filter.Database == m.Database) &&
+			(filter.RetentionPolicy == nil || *filter.RetentionPolicy == m.RetentionPolicy) &&
+			(filter.Default == nil || *filter.Default == m.Default)
+	}
+}
+
+// FindDBRPMappings returns the dbrp mappings that match the filter and their count.
+func (s *Service) FindDBRPMappings(ctx context.Context, filter influxdb.DBRPMappingFilterV2, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+	var ms []*influxdb.DBRPMappingV2
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		ms, err = s.findDBRPMappings(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindDBRPMappings,
+			Err: err,
+		}
+	}
+	return ms, len(ms), nil
+}
+
+func (s *Service) findDBRPMappings(ctx context.Context, tx Tx, filter influxdb.DBRPMappingFilterV2) ([]*influxdb.DBRPMappingV2, error) {
+	ms := []*influxdb.DBRPMappingV2{}
+	if filter.ID != nil {
+		m, err := s.findDBRPMappingByID(ctx, tx, *filter.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return nil, err
+		}
+		if m != nil && filterDBRPMappingsFn(filter)(m) {
+			ms = append(ms, m)
+		}
+		return ms, nil
+	}
+
+	filterFn := filterDBRPMappingsFn(filter)
+	fn := func(m *influxdb.DBRPMappingV2) bool {
+		if filterFn(m) {
+			ms = append(ms, m)
+		}
+		return true
+	}
+
+	var err error
+	if filter.OrgID != nil && filter.Database != nil {
+		err = s.forEachDatabaseDBRPMapping(ctx, tx, *filter.OrgID, *filter.Database, fn)
+	} else {
+		err = s.forEachDBRPMapping(ctx, tx, fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (s *Service) forEachDBRPMapping(ctx context.Context, tx Tx, fn func(m *influxdb.DBRPMappingV2) bool) error {
+	b, err := tx.Bucket(dbrpBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		m := &influxdb.DBRPMappingV2{}
+		if err := json.Unmarshal(v, m); err != nil {
+			return err
+		}
+		if !fn(m) {
+			break
+		}
+	}
+	return nil
+}
+
+// forEachDatabaseDBRPMapping calls fn with the mappings of a database of an
+// organization.
+func (s *Service) forEachDatabaseDBRPMapping(ctx context.Context, tx Tx, orgID influxdb.ID, db string, fn func(m *influxdb.DBRPMappingV2) bool) error {
+	prefix, err := dbrpIndexPrefix(orgID, db)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(dbrpIndex)
+	if err != nil {
+		return err
+	}
+
+	cur, err := idx.Cursor(WithCursorHintPrefix(string(prefix)))
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		var id influxdb.ID
+		if err := id.Decode(v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		m, err := s.findDBRPMappingByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !fn(m) {
+			break
+		}
+	}
+	return nil
+}
+
+// CreateDBRPMapping creates a dbrp mapping and sets m.ID with the new identifier.
+func (s *Service) CreateDBRPMapping(ctx context.Context, m *influxdb.DBRPMappingV2) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m.ID = s.IDGenerator.ID()
+		if err := s.validDBRPMapping(ctx, tx, m); err != nil {
+			return err
+		}
+		return s.putDBRPMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateDBRPMapping,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// validDBRPMapping returns an error if the mapping is invalid, maps to a
+// bucket of another organization or conflicts with another mapping of the
+// database.
+func (s *Service) validDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMappingV2) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	b, err := s.findBucketByID(ctx, tx, m.BucketID)
+	if err != nil {
+		return err
+	}
+	if b.OrgID != m.OrganizationID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucket does not belong to the organization",
+		}
+	}
+
+	var conflict error
+	err = s.forEachDatabaseDBRPMapping(ctx, tx, m.OrganizationID, m.Database, func(o *influxdb.DBRPMappingV2) bool {
+		if o.ID == m.ID {
+			return true
+		}
+		if o.RetentionPolicy == m.RetentionPolicy {
+			conflict = influxdb.ErrDBRPAlreadyExists
+			return false
+		}
+		if o.Default && m.Default {
+			conflict = influxdb.ErrDBRPDefaultAlreadyExists
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return conflict
+}
+
+func (s *Service) putDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMappingV2) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := m.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key, err := dbrpIndexKey(m)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(dbrpIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put(key, encodedID); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(dbrpBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// UpdateDBRPMapping updates a dbrp mapping with the changeset.
+func (s *Service) UpdateDBRPMapping(ctx context.Context, id influxdb.ID, upd influxdb.DBRPMappingUpdate) (*influxdb.DBRPMappingV2, error) {
+	var m *influxdb.DBRPMappingV2
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var err error
+		if m, err = s.findDBRPMappingByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		upd.Apply(m)
+		if err := s.validDBRPMapping(ctx, tx, m); err != nil {
+			return err
+		}
+		return s.putDBRPMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateDBRPMapping,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+// DeleteDBRPMapping removes a dbrp mapping by ID.
+func (s *Service) DeleteDBRPMapping(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findDBRPMappingByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return s.deleteDBRPMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteDBRPMapping,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMappingV2) error {
+	key, err := dbrpIndexKey(m)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(dbrpIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete(key); err != nil {
+		return err
+	}
+
+	encodedID, err := m.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dbrpBucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete(encodedID)
+}
+
+// deleteBucketDBRPMappings removes the dbrp mappings of a deleted bucket.
+func (s *Service) deleteBucketDBRPMappings(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	ms, err := s.findDBRPMappings(ctx, tx, influxdb.DBRPMappingFilterV2{BucketID: &bucketID})
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := s.deleteDBRPMapping(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PutDBRPMapping writes a dbrp mapping without generating a new ID or
+// validating it.
+func (s *Service) PutDBRPMapping(ctx context.Context, m *influxdb.DBRPMappingV2) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.putDBRPMapping(ctx, tx, m)
+	})
+}
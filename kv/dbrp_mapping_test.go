@@ -0,0 +1,112 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltDBRPMappingService(t *testing.T) {
+	influxdbtesting.DBRPMappingServiceV2(initBoltDBRPMappingService, t)
+}
+
+func TestInmemDBRPMappingService(t *testing.T) {
+	influxdbtesting.DBRPMappingServiceV2(initInmemDBRPMappingService, t)
+}
+
+func initBoltDBRPMappingService(f influxdbtesting.DBRPMappingFieldsV2, t *testing.T) (influxdb.DBRPMappingServiceV2, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemDBRPMappingService(f influxdbtesting.DBRPMappingFieldsV2, t *testing.T) (influxdb.DBRPMappingServiceV2, func()) {
+	s, closeBolt, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initDBRPMappingService(s kv.Store, f influxdbtesting.DBRPMappingFieldsV2, t *testing.T) (influxdb.DBRPMappingServiceV2, func()) {
+	svc := kv.NewService(s)
+	if f.IDGenerator != nil {
+		svc.IDGenerator = f.IDGenerator
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing dbrp mapping service: %v", err)
+	}
+
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, m := range f.DBRPMappings {
+		if err := svc.PutDBRPMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate dbrp mappings: %v", err)
+		}
+	}
+
+	return svc, func() {
+		for _, m := range f.DBRPMappings {
+			if err := svc.DeleteDBRPMapping(ctx, m.ID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Logf("failed to remove dbrp mapping: %v", err)
+			}
+		}
+	}
+}
+
+func TestService_DeleteBucketDBRPMappings(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &influxdb.Bucket{ID: 2, OrgID: 1, Name: "db/autogen"}
+	if err := svc.PutBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateDBRPMapping(ctx, &influxdb.DBRPMappingV2{
+		OrganizationID:  1,
+		BucketID:        2,
+		Database:        "db",
+		RetentionPolicy: "autogen",
+		Default:         true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteBucket(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, err := svc.FindDBRPMappings(ctx, influxdb.DBRPMappingFilterV2{}); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("expected dbrp mappings of deleted bucket to be removed, got %d", n)
+	}
+}
@@ -0,0 +1,309 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrIngestRuleNotFound is used when the ingest rule is not found.
+	ErrIngestRuleNotFound = &influxdb.Error{
+		Msg:  "ingest rule not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidIngestRuleID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidIngestRuleID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided ingest rule ID has invalid format",
+	}
+)
+
+var ingestRulesBucket = []byte("ingestrulesv1")
+
+var _ influxdb.IngestRuleService = (*Service)(nil)
+
+func (s *Service) initializeIngestRules(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(ingestRulesBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindIngestRuleByID returns a single ingest rule by ID.
+func (s *Service) FindIngestRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+	var r *influxdb.IngestRule
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rule, err := s.findIngestRuleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = rule
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindIngestRuleByID,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// FindIngestRules returns the ingest rules that match the filter, ordered
+// by bucket and then by position.
+func (s *Service) FindIngestRules(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+	var rs []*influxdb.IngestRule
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		rs, err = s.findIngestRules(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindIngestRules,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+func (s *Service) findIngestRules(ctx context.Context, tx Tx, filter influxdb.IngestRuleFilter) ([]*influxdb.IngestRule, error) {
+	var rs []*influxdb.IngestRule
+	if filter.ID != nil {
+		r, err := s.findIngestRuleByID(ctx, tx, *filter.ID)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if filterIngestRule(r, filter) {
+			rs = append(rs, r)
+		}
+		return rs, nil
+	}
+
+	err := s.forEachIngestRule(ctx, tx, func(r *influxdb.IngestRule) bool {
+		if filterIngestRule(r, filter) {
+			rs = append(rs, r)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// rules of the same position are applied in the order they were created.
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].BucketID != rs[j].BucketID {
+			return rs[i].BucketID < rs[j].BucketID
+		}
+		return rs[i].Position < rs[j].Position
+	})
+	return rs, nil
+}
+
+func filterIngestRule(r *influxdb.IngestRule, filter influxdb.IngestRuleFilter) bool {
+	return (filter.ID == nil || r.ID == *filter.ID) &&
+		(filter.OrgID == nil || r.OrgID == *filter.OrgID) &&
+		(filter.BucketID == nil || r.BucketID == *filter.BucketID)
+}
+
+// CreateIngestRule creates an ingest rule and sets r.ID with the new
+// identifier. The organization of the rule is the one of its bucket. A rule
+// without a position is placed after the other rules of the bucket.
+func (s *Service) CreateIngestRule(ctx context.Context, r *influxdb.IngestRule) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := s.findBucketByID(ctx, tx, r.BucketID)
+		if err != nil {
+			return err
+		}
+		r.OrgID = b.OrgID
+		if err := r.Valid(); err != nil {
+			return err
+		}
+
+		if r.Position == 0 {
+			rs, err := s.findIngestRules(ctx, tx, influxdb.IngestRuleFilter{BucketID: &r.BucketID})
+			if err != nil {
+				return err
+			}
+			r.Position = 1
+			if len(rs) > 0 {
+				r.Position = rs[len(rs)-1].Position + 1
+			}
+		}
+
+		r.ID = s.IDGenerator.ID()
+		now := s.Now()
+		r.SetCreatedAt(now)
+		r.SetUpdatedAt(now)
+		return s.putIngestRule(ctx, tx, r)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateIngestRule,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateIngestRule updates an ingest rule with the changeset.
+func (s *Service) UpdateIngestRule(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+	var r *influxdb.IngestRule
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rule, err := s.findIngestRuleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(rule)
+		if err := rule.Valid(); err != nil {
+			return err
+		}
+		rule.SetUpdatedAt(s.Now())
+
+		if err := s.putIngestRule(ctx, tx, rule); err != nil {
+			return err
+		}
+		r = rule
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateIngestRule,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// DeleteIngestRule removes an ingest rule by ID.
+func (s *Service) DeleteIngestRule(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findIngestRuleByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.deleteIngestRule(ctx, tx, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteIngestRule,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteIngestRule(ctx context.Context, tx Tx, id influxdb.ID) error {
+	k, err := id.Encode()
+	if err != nil {
+		return ErrInvalidIngestRuleID
+	}
+
+	b, err := tx.Bucket(ingestRulesBucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete(k)
+}
+
+// deleteBucketIngestRules removes the ingest rules of a deleted bucket.
+func (s *Service) deleteBucketIngestRules(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	rs, err := s.findIngestRules(ctx, tx, influxdb.IngestRuleFilter{BucketID: &bucketID})
+	if err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := s.deleteIngestRule(ctx, tx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) findIngestRuleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.IngestRule, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidIngestRuleID
+	}
+
+	b, err := tx.Bucket(ingestRulesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrIngestRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalIngestRule(v)
+}
+
+// forEachIngestRule calls fn with each ingest rule until fn returns false.
+func (s *Service) forEachIngestRule(ctx context.Context, tx Tx, fn func(*influxdb.IngestRule) bool) error {
+	b, err := tx.Bucket(ingestRulesBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		r, err := unmarshalIngestRule(v)
+		if err != nil {
+			return err
+		}
+		if !fn(r) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Service) putIngestRule(ctx context.Context, tx Tx, r *influxdb.IngestRule) error {
+	k, err := r.ID.Encode()
+	if err != nil {
+		return ErrInvalidIngestRuleID
+	}
+
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(ingestRulesBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func unmarshalIngestRule(v []byte) (*influxdb.IngestRule, error) {
+	r := &influxdb.IngestRule{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal ingest rule",
+			Err:  err,
+		}
+	}
+	return r, nil
+}
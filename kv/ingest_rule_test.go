@@ -0,0 +1,108 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestIngestRules(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			bucket := &influxdb.Bucket{OrgID: org.ID, Name: "telegraf"}
+			if err := svc.CreateBucket(ctx, bucket); err != nil {
+				t.Fatal(err)
+			}
+
+			rename := &influxdb.IngestRule{
+				BucketID: bucket.ID,
+				Name:     "hostname",
+				Type:     influxdb.IngestRuleRenameTag,
+				Tag:      "hostname",
+				NewTag:   "host",
+			}
+			if err := svc.CreateIngestRule(ctx, rename); err != nil {
+				t.Fatal(err)
+			}
+			if !rename.ID.Valid() || rename.OrgID != org.ID || rename.Position != 1 || rename.CreatedAt.IsZero() {
+				t.Fatalf("expected an ID, the organization of the bucket and a position, got %+v", rename)
+			}
+
+			drop := &influxdb.IngestRule{
+				BucketID: bucket.ID,
+				Name:     "drop uptime",
+				Type:     influxdb.IngestRuleDropField,
+				Field:    "uptime",
+			}
+			if err := svc.CreateIngestRule(ctx, drop); err != nil {
+				t.Fatal(err)
+			}
+			if drop.Position != 2 {
+				t.Fatalf("expected rule to be placed after the others, got position %d", drop.Position)
+			}
+
+			invalid := &influxdb.IngestRule{BucketID: bucket.ID, Name: "invalid", Type: influxdb.IngestRuleDeriveTag, Tag: "region"}
+			if err := svc.CreateIngestRule(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected deriveTag rule without source to be rejected, got %v", err)
+			}
+			invalid = &influxdb.IngestRule{BucketID: bucket.ID, Name: "invalid", Type: influxdb.IngestRuleRenameTag, Tag: "m", NewTag: "_measurement"}
+			if err := svc.CreateIngestRule(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected rename to the measurement to be rejected, got %v", err)
+			}
+			invalid = &influxdb.IngestRule{BucketID: influxdb.ID(100), Name: "invalid", Type: influxdb.IngestRuleDropField, Field: "f"}
+			if err := svc.CreateIngestRule(ctx, invalid); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected rule of missing bucket to be rejected, got %v", err)
+			}
+
+			position := 0
+			if _, err := svc.UpdateIngestRule(ctx, drop.ID, influxdb.IngestRuleUpdate{Position: &position}); err != nil {
+				t.Fatal(err)
+			}
+			rs, n, err := svc.FindIngestRules(ctx, influxdb.IngestRuleFilter{BucketID: &bucket.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 || rs[0].ID != drop.ID || rs[1].ID != rename.ID {
+				t.Fatalf("expected rules ordered by position, got %+v", rs)
+			}
+
+			if err := svc.DeleteIngestRule(ctx, rename.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindIngestRuleByID(ctx, rename.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected deleted ingest rule to be not found, got %v", err)
+			}
+
+			if err := svc.DeleteBucket(ctx, bucket.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, n, err := svc.FindIngestRules(ctx, influxdb.IngestRuleFilter{}); err != nil || n != 0 {
+				t.Fatalf("expected the rules of the deleted bucket to be deleted, got %d rules and %v", n, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrMaintenanceNotFound is used when the instance or organization is
+	// not in maintenance.
+	ErrMaintenanceNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "maintenance not found",
+	}
+)
+
+var (
+	maintenanceBucket      = []byte("maintenancev1")
+	instanceMaintenanceKey = []byte("instance")
+)
+
+var _ influxdb.MaintenanceService = (*Service)(nil)
+
+func (s *Service) initializeMaintenance(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(maintenanceBucket)
+	return err
+}
+
+// maintenanceKey returns the key of the maintenance of the organization, or
+// of the instance if orgID is nil.
+func maintenanceKey(orgID *influxdb.ID) ([]byte, error) {
+	if orgID == nil {
+		return instanceMaintenanceKey, nil
+	}
+	k, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return k, nil
+}
+
+// FindMaintenances returns the maintenances in effect.
+func (s *Service) FindMaintenances(ctx context.Context) ([]*influxdb.Maintenance, error) {
+	var ms []*influxdb.Maintenance
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(maintenanceBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			m := &influxdb.Maintenance{}
+			if err := json.Unmarshal(v, m); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Msg:  "unable to unmarshal maintenance",
+					Err:  err,
+				}
+			}
+			ms = append(ms, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMaintenances,
+			Err: err,
+		}
+	}
+	return ms, nil
+}
+
+// StartMaintenance puts the organization of m, or the instance, in
+// maintenance mode.
+func (s *Service) StartMaintenance(ctx context.Context, m *influxdb.Maintenance) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := m.Valid(); err != nil {
+			return err
+		}
+		if m.OrganizationID != nil {
+			if _, err := s.findOrganizationByID(ctx, tx, *m.OrganizationID); err != nil {
+				return err
+			}
+		}
+
+		k, err := maintenanceKey(m.OrganizationID)
+		if err != nil {
+			return err
+		}
+
+		if m.RetryAfter == 0 {
+			m.RetryAfter = influxdb.DefaultMaintenanceRetryAfter
+		}
+		m.StartedAt = s.Now()
+
+		v, err := json.Marshal(m)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(maintenanceBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpStartMaintenance,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// StopMaintenance ends the maintenance of the organization, or of the
+// instance if orgID is nil.
+func (s *Service) StopMaintenance(ctx context.Context, orgID *influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		k, err := maintenanceKey(orgID)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(maintenanceBucket)
+		if err != nil {
+			return err
+		}
+
+		if _, err := b.Get(k); err != nil {
+			if IsNotFound(err) {
+				return ErrMaintenanceNotFound
+			}
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpStopMaintenance,
+			Err: err,
+		}
+	}
+	return nil
+}
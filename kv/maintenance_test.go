@@ -0,0 +1,94 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestMaintenance(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+
+			ms, err := svc.FindMaintenances(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ms) != 0 {
+				t.Fatalf("expected no maintenances, got %+v", ms)
+			}
+
+			if err := svc.StartMaintenance(ctx, &influxdb.Maintenance{OrganizationID: &org.ID, Message: "migrating"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.StartMaintenance(ctx, &influxdb.Maintenance{RetryAfter: 60}); err != nil {
+				t.Fatal(err)
+			}
+			missing := influxdb.ID(1000)
+			if err := svc.StartMaintenance(ctx, &influxdb.Maintenance{OrganizationID: &missing}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Errorf("expected starting the maintenance of a missing org to be not found, got %v", err)
+			}
+
+			ms, err = svc.FindMaintenances(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ms) != 2 {
+				t.Fatalf("expected the maintenances of the org and the instance, got %+v", ms)
+			}
+			for _, m := range ms {
+				if m.StartedAt.IsZero() {
+					t.Errorf("expected a start time, got %+v", m)
+				}
+				if m.OrganizationID != nil && (m.Message != "migrating" || m.RetryAfter != influxdb.DefaultMaintenanceRetryAfter) {
+					t.Errorf("unexpected org maintenance %+v", m)
+				}
+			}
+			if m := influxdb.MaintenanceOf(ms, org.ID); m == nil || m.OrganizationID != nil || m.RetryAfter != 60 {
+				t.Errorf("expected the maintenance of the instance to apply to the org, got %+v", m)
+			}
+
+			if err := svc.StopMaintenance(ctx, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.StopMaintenance(ctx, nil); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Errorf("expected stopping a stopped maintenance to be not found, got %v", err)
+			}
+
+			ms, err = svc.FindMaintenances(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m := influxdb.MaintenanceOf(ms, org.ID); m == nil || *m.OrganizationID != org.ID {
+				t.Errorf("expected the maintenance of the org to remain, got %+v", m)
+			}
+			if m := influxdb.MaintenanceOf(ms, missing); m != nil {
+				t.Errorf("expected other orgs not to be in maintenance, got %+v", m)
+			}
+		})
+	}
+}
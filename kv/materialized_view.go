@@ -0,0 +1,293 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrMaterializedViewNotFound is used when the materialized view is not found.
+	ErrMaterializedViewNotFound = &influxdb.Error{
+		Msg:  "materialized view not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidMaterializedViewID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidMaterializedViewID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided materialized view ID has invalid format",
+	}
+
+	// ErrMaterializedViewDestinationInUse is used when the destination bucket
+	// of a new view is the destination bucket of another view.
+	ErrMaterializedViewDestinationInUse = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "destination bucket is the destination of another materialized view",
+	}
+)
+
+var materializedViewsBucket = []byte("materializedviewsv1")
+
+var _ influxdb.MaterializedViewService = (*Service)(nil)
+
+func (s *Service) initializeMaterializedViews(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(materializedViewsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindMaterializedViewByID returns a single materialized view by ID.
+func (s *Service) FindMaterializedViewByID(ctx context.Context, id influxdb.ID) (*influxdb.MaterializedView, error) {
+	var mv *influxdb.MaterializedView
+	err := s.kv.View(ctx, func(tx Tx) error {
+		v, err := s.findMaterializedViewByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		mv = v
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMaterializedViewByID,
+			Err: err,
+		}
+	}
+	return mv, nil
+}
+
+// FindMaterializedViews returns the materialized views that match the filter.
+func (s *Service) FindMaterializedViews(ctx context.Context, filter influxdb.MaterializedViewFilter, opt ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+	var mvs []*influxdb.MaterializedView
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			mv, err := s.findMaterializedViewByID(ctx, tx, *filter.ID)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					return nil
+				}
+				return err
+			}
+			if filterMaterializedView(mv, filter) {
+				mvs = append(mvs, mv)
+			}
+			return nil
+		}
+
+		return s.forEachMaterializedView(ctx, tx, func(mv *influxdb.MaterializedView) bool {
+			if filterMaterializedView(mv, filter) {
+				mvs = append(mvs, mv)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindMaterializedViews,
+			Err: err,
+		}
+	}
+	return mvs, len(mvs), nil
+}
+
+func filterMaterializedView(mv *influxdb.MaterializedView, filter influxdb.MaterializedViewFilter) bool {
+	return (filter.ID == nil || mv.ID == *filter.ID) &&
+		(filter.OrganizationID == nil || mv.OrganizationID == *filter.OrganizationID) &&
+		(filter.SourceBucketID == nil || mv.SourceBucketID == *filter.SourceBucketID) &&
+		(filter.Name == nil || mv.Name == *filter.Name)
+}
+
+// CreateMaterializedView creates a materialized view and sets mv.ID with the new identifier.
+func (s *Service) CreateMaterializedView(ctx context.Context, mv *influxdb.MaterializedView) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if mv.Status == "" {
+			mv.Status = influxdb.Active
+		}
+		if err := mv.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, mv.OrganizationID); err != nil {
+			return err
+		}
+		for _, id := range []influxdb.ID{mv.SourceBucketID, mv.DestinationBucketID} {
+			b, err := s.findBucketByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if b.OrgID != mv.OrganizationID {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "materialized view buckets must belong to its organization",
+				}
+			}
+		}
+
+		var inUse bool
+		err := s.forEachMaterializedView(ctx, tx, func(other *influxdb.MaterializedView) bool {
+			inUse = other.DestinationBucketID == mv.DestinationBucketID
+			return !inUse
+		})
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrMaterializedViewDestinationInUse
+		}
+
+		mv.ID = s.IDGenerator.ID()
+		now := s.Now()
+		mv.SetCreatedAt(now)
+		mv.SetUpdatedAt(now)
+		return s.putMaterializedView(ctx, tx, mv)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateMaterializedView,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateMaterializedView updates a materialized view with the changeset.
+func (s *Service) UpdateMaterializedView(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+	var mv *influxdb.MaterializedView
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		v, err := s.findMaterializedViewByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(v)
+		if err := v.Valid(); err != nil {
+			return err
+		}
+		v.SetUpdatedAt(s.Now())
+
+		if err := s.putMaterializedView(ctx, tx, v); err != nil {
+			return err
+		}
+		mv = v
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateMaterializedView,
+			Err: err,
+		}
+	}
+	return mv, nil
+}
+
+// DeleteMaterializedView removes a materialized view by ID. The materialized
+// windows remain in the destination bucket.
+func (s *Service) DeleteMaterializedView(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findMaterializedViewByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := id.Encode()
+		if err != nil {
+			return ErrInvalidMaterializedViewID
+		}
+
+		b, err := tx.Bucket(materializedViewsBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteMaterializedView,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) findMaterializedViewByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.MaterializedView, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidMaterializedViewID
+	}
+
+	b, err := tx.Bucket(materializedViewsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrMaterializedViewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMaterializedView(v)
+}
+
+// forEachMaterializedView calls fn with each materialized view until fn returns false.
+func (s *Service) forEachMaterializedView(ctx context.Context, tx Tx, fn func(*influxdb.MaterializedView) bool) error {
+	b, err := tx.Bucket(materializedViewsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		mv, err := unmarshalMaterializedView(v)
+		if err != nil {
+			return err
+		}
+		if !fn(mv) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Service) putMaterializedView(ctx context.Context, tx Tx, mv *influxdb.MaterializedView) error {
+	k, err := mv.ID.Encode()
+	if err != nil {
+		return ErrInvalidMaterializedViewID
+	}
+
+	v, err := json.Marshal(mv)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(materializedViewsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func unmarshalMaterializedView(v []byte) (*influxdb.MaterializedView, error) {
+	mv := &influxdb.MaterializedView{}
+	if err := json.Unmarshal(v, mv); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal materialized view",
+			Err:  err,
+		}
+	}
+	return mv, nil
+}
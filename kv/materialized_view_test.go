@@ -0,0 +1,131 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestMaterializedViews(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			other := &influxdb.Organization{Name: "other"}
+			if err := svc.CreateOrganization(ctx, other); err != nil {
+				t.Fatal(err)
+			}
+			var buckets []*influxdb.Bucket
+			for _, b := range []*influxdb.Bucket{
+				{OrgID: org.ID, Name: "source"},
+				{OrgID: org.ID, Name: "dest"},
+				{OrgID: other.ID, Name: "other"},
+			} {
+				if err := svc.CreateBucket(ctx, b); err != nil {
+					t.Fatal(err)
+				}
+				buckets = append(buckets, b)
+			}
+			source, dest, otherBucket := buckets[0], buckets[1], buckets[2]
+
+			newView := func() *influxdb.MaterializedView {
+				return &influxdb.MaterializedView{
+					OrganizationID:      org.ID,
+					Name:                "cpu mean",
+					SourceBucketID:      source.ID,
+					DestinationBucketID: dest.ID,
+					Measurement:         "cpu",
+					Aggregate:           influxdb.MaterializedViewMean,
+					Every:               time.Minute,
+					LateDataWindow:      time.Minute,
+				}
+			}
+
+			mv := newView()
+			if err := svc.CreateMaterializedView(ctx, mv); err != nil {
+				t.Fatal(err)
+			}
+			if !mv.ID.Valid() || mv.Status != influxdb.Active || mv.CreatedAt.IsZero() {
+				t.Fatalf("unexpected created view %+v", mv)
+			}
+
+			// The destination of a view cannot be shared.
+			if err := svc.CreateMaterializedView(ctx, newView()); influxdb.ErrorCode(err) != influxdb.EConflict {
+				t.Fatalf("expected conflict error, got %v", err)
+			}
+			// The buckets of a view must belong to its organization.
+			invalid := newView()
+			invalid.DestinationBucketID = otherBucket.ID
+			if err := svc.CreateMaterializedView(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid error, got %v", err)
+			}
+
+			got, err := svc.FindMaterializedViewByID(ctx, mv.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != mv.Name || got.Every != mv.Every || got.Aggregate != mv.Aggregate {
+				t.Fatalf("got %+v, want %+v", got, mv)
+			}
+
+			mvs, n, err := svc.FindMaterializedViews(ctx, influxdb.MaterializedViewFilter{SourceBucketID: &source.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 || mvs[0].ID != mv.ID {
+				t.Fatalf("expected the view, got %+v", mvs)
+			}
+			if _, n, _ := svc.FindMaterializedViews(ctx, influxdb.MaterializedViewFilter{OrganizationID: &other.ID}); n != 0 {
+				t.Fatalf("expected no views of the other org, got %d", n)
+			}
+
+			until := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+			inactive := influxdb.Inactive
+			upd, err := svc.UpdateMaterializedView(ctx, mv.ID, influxdb.MaterializedViewUpdate{
+				Status:            &inactive,
+				MaterializedUntil: &until,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upd.Status != influxdb.Inactive || !upd.MaterializedUntil.Equal(until) {
+				t.Fatalf("unexpected updated view %+v", upd)
+			}
+
+			negative := -time.Minute
+			if _, err := svc.UpdateMaterializedView(ctx, mv.ID, influxdb.MaterializedViewUpdate{LateDataWindow: &negative}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid error, got %v", err)
+			}
+
+			if err := svc.DeleteMaterializedView(ctx, mv.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindMaterializedViewByID(ctx, mv.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected not found error, got %v", err)
+			}
+		})
+	}
+}
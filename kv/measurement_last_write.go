@@ -0,0 +1,157 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var measurementLastWritesBucket = []byte("measurementlastwritesv1")
+
+var _ influxdb.MeasurementLastWriteService = (*Service)(nil)
+
+func (s *Service) initializeMeasurementLastWrites(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(measurementLastWritesBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindMeasurementLastWrites returns the times of the last writes to the
+// measurements of the bucket.
+func (s *Service) FindMeasurementLastWrites(ctx context.Context, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error) {
+	var ws influxdb.MeasurementLastWrites
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findBucketByID(ctx, tx, bucketID); err != nil {
+			return err
+		}
+
+		lws, err := s.findMeasurementLastWrites(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		ws = lws
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementLastWrites,
+			Err: err,
+		}
+	}
+	return ws, nil
+}
+
+// RecordMeasurementLastWrites records the times of writes to the measurements
+// of buckets, keeping the latest time of each measurement. Writes to buckets
+// that do not exist, e.g. as they were deleted since, are skipped.
+func (s *Service) RecordMeasurementLastWrites(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		for bucketID, w := range writes {
+			if _, err := s.findBucketByID(ctx, tx, bucketID); err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					continue
+				}
+				return err
+			}
+
+			ws, err := s.findMeasurementLastWrites(ctx, tx, bucketID)
+			if err != nil {
+				return err
+			}
+			for m, t := range w {
+				if t.After(ws[m]) {
+					ws[m] = t
+				}
+			}
+			if err := s.putMeasurementLastWrites(ctx, tx, bucketID, ws); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRecordMeasurementLastWrites,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// findMeasurementLastWrites returns the stored last writes of the bucket, or
+// none if the bucket was never written to.
+func (s *Service) findMeasurementLastWrites(ctx context.Context, tx Tx, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error) {
+	k, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementLastWritesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return influxdb.MeasurementLastWrites{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ws := influxdb.MeasurementLastWrites{}
+	if err := json.Unmarshal(v, &ws); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return ws, nil
+}
+
+func (s *Service) putMeasurementLastWrites(ctx context.Context, tx Tx, bucketID influxdb.ID, ws influxdb.MeasurementLastWrites) error {
+	k, err := bucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(ws)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementLastWritesBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func (s *Service) deleteMeasurementLastWrites(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	k, err := bucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementLastWritesBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
@@ -0,0 +1,73 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_MeasurementLastWrites(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing measurement last write service: %v", err)
+	}
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &influxdb.Bucket{OrgID: o.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := svc.FindMeasurementLastWrites(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) != 0 {
+		t.Fatalf("expected no last writes, got %v", ws)
+	}
+
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	if err := svc.RecordMeasurementLastWrites(ctx, map[influxdb.ID]influxdb.MeasurementLastWrites{
+		b.ID: {"cpu": t2, "mem": t1},
+		// writes to deleted buckets are skipped.
+		influxdb.ID(1): {"cpu": t1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RecordMeasurementLastWrites(ctx, map[influxdb.ID]influxdb.MeasurementLastWrites{
+		b.ID: {"cpu": t1, "mem": t2, "disk": t1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err = svc.FindMeasurementLastWrites(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := influxdb.MeasurementLastWrites{"cpu": t2, "mem": t2, "disk": t1}
+	if diff := cmp.Diff(want, ws); diff != "" {
+		t.Fatalf("unexpected last writes: %s", diff)
+	}
+
+	if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindMeasurementLastWrites(ctx, b.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected last writes of deleted bucket to be not found, got %v", err)
+	}
+}
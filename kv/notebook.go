@@ -0,0 +1,491 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrNotebookNotFound is used when the notebook is not found.
+	ErrNotebookNotFound = &influxdb.Error{
+		Msg:  "notebook not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrNotebookVersionNotFound is used when the version of a notebook is
+	// not found, or is no longer kept.
+	ErrNotebookVersionNotFound = &influxdb.Error{
+		Msg:  "notebook version not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidNotebookID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidNotebookID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided notebook ID has invalid format",
+	}
+)
+
+var (
+	notebooksBucket        = []byte("notebooksv1")
+	notebookVersionsBucket = []byte("notebookversionsv1")
+)
+
+var _ influxdb.NotebookService = (*Service)(nil)
+
+func (s *Service) initializeNotebooks(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(notebooksBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(notebookVersionsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindNotebookByID returns a single notebook by ID.
+func (s *Service) FindNotebookByID(ctx context.Context, id influxdb.ID) (*influxdb.Notebook, error) {
+	var n *influxdb.Notebook
+	err := s.kv.View(ctx, func(tx Tx) error {
+		nb, err := s.findNotebookByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		n = nb
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindNotebookByID,
+			Err: err,
+		}
+	}
+	return n, nil
+}
+
+// FindNotebooks returns the notebooks that match the filter.
+func (s *Service) FindNotebooks(ctx context.Context, filter influxdb.NotebookFilter, opt ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error) {
+	var ns []*influxdb.Notebook
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			n, err := s.findNotebookByID(ctx, tx, *filter.ID)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					return nil
+				}
+				return err
+			}
+			if filterNotebook(n, filter) {
+				ns = append(ns, n)
+			}
+			return nil
+		}
+
+		return s.forEachNotebook(ctx, tx, func(n *influxdb.Notebook) bool {
+			if filterNotebook(n, filter) {
+				ns = append(ns, n)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindNotebooks,
+			Err: err,
+		}
+	}
+	return ns, len(ns), nil
+}
+
+func filterNotebook(n *influxdb.Notebook, filter influxdb.NotebookFilter) bool {
+	return (filter.ID == nil || n.ID == *filter.ID) &&
+		(filter.OrganizationID == nil || n.OrganizationID == *filter.OrganizationID) &&
+		(filter.Name == nil || n.Name == *filter.Name)
+}
+
+// CreateNotebook creates a notebook at version 1 and sets n.ID with the new
+// identifier.
+func (s *Service) CreateNotebook(ctx context.Context, n *influxdb.Notebook) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := n.Valid(); err != nil {
+			return err
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, n.OrganizationID); err != nil {
+			return err
+		}
+
+		n.ID = s.IDGenerator.ID()
+		s.setNotebookCellIDs(n)
+		n.Version = 1
+
+		now := s.Now()
+		n.SetCreatedAt(now)
+		n.SetUpdatedAt(now)
+		if err := s.putNotebook(ctx, tx, n); err != nil {
+			return err
+		}
+		return s.putNotebookVersion(ctx, tx, n)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateNotebook,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateNotebook updates a notebook with the changeset and keeps its
+// previous contents as a version.
+func (s *Service) UpdateNotebook(ctx context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error) {
+	var n *influxdb.Notebook
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := upd.Valid(); err != nil {
+			return err
+		}
+
+		nb, err := s.findNotebookByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := upd.Apply(nb); err != nil {
+			return err
+		}
+		if err := nb.Valid(); err != nil {
+			return err
+		}
+		s.setNotebookCellIDs(nb)
+		nb.Version++
+		nb.SetUpdatedAt(s.Now())
+
+		if err := s.putNotebook(ctx, tx, nb); err != nil {
+			return err
+		}
+		if err := s.putNotebookVersion(ctx, tx, nb); err != nil {
+			return err
+		}
+		n = nb
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateNotebook,
+			Err: err,
+		}
+	}
+	return n, nil
+}
+
+// DeleteNotebook removes a notebook, its versions and its acl by ID.
+func (s *Service) DeleteNotebook(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findNotebookByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := id.Encode()
+		if err != nil {
+			return ErrInvalidNotebookID
+		}
+
+		if err := s.deleteResourceACL(ctx, tx, influxdb.NotebooksResourceType, id); err != nil {
+			return err
+		}
+
+		var keys [][]byte
+		if err := s.forEachNotebookVersion(ctx, tx, id, func(k []byte, v *influxdb.NotebookVersion) bool {
+			keys = append(keys, k)
+			return true
+		}); err != nil {
+			return err
+		}
+
+		vb, err := tx.Bucket(notebookVersionsBucket)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := vb.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		b, err := tx.Bucket(notebooksBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteNotebook,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindNotebookVersions returns the kept versions of a notebook, the most
+// recent first.
+func (s *Service) FindNotebookVersions(ctx context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error) {
+	var vs []*influxdb.NotebookVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findNotebookByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		return s.forEachNotebookVersion(ctx, tx, id, func(_ []byte, v *influxdb.NotebookVersion) bool {
+			vs = append(vs, v)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindNotebookVersions,
+			Err: err,
+		}
+	}
+
+	for i, j := 0, len(vs)-1; i < j; i, j = i+1, j-1 {
+		vs[i], vs[j] = vs[j], vs[i]
+	}
+	return vs, nil
+}
+
+// FindNotebookVersion returns a single version of a notebook.
+func (s *Service) FindNotebookVersion(ctx context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error) {
+	var v *influxdb.NotebookVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findNotebookByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := notebookVersionKey(id, version)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(notebookVersionsBucket)
+		if err != nil {
+			return err
+		}
+
+		val, err := b.Get(k)
+		if IsNotFound(err) {
+			return ErrNotebookVersionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		v, err = unmarshalNotebookVersion(val)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindNotebookVersion,
+			Err: err,
+		}
+	}
+	return v, nil
+}
+
+// setNotebookCellIDs gives an ID to the cells of the notebook without one.
+func (s *Service) setNotebookCellIDs(n *influxdb.Notebook) {
+	for i := range n.Cells {
+		if !n.Cells[i].ID.Valid() {
+			n.Cells[i].ID = s.IDGenerator.ID()
+		}
+	}
+}
+
+func (s *Service) findNotebookByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Notebook, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidNotebookID
+	}
+
+	b, err := tx.Bucket(notebooksBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrNotebookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalNotebook(v)
+}
+
+// forEachNotebook calls fn with each notebook until fn returns false.
+func (s *Service) forEachNotebook(ctx context.Context, tx Tx, fn func(*influxdb.Notebook) bool) error {
+	b, err := tx.Bucket(notebooksBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		n, err := unmarshalNotebook(v)
+		if err != nil {
+			return err
+		}
+		if !fn(n) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Service) putNotebook(ctx context.Context, tx Tx, n *influxdb.Notebook) error {
+	k, err := n.ID.Encode()
+	if err != nil {
+		return ErrInvalidNotebookID
+	}
+
+	v, err := json.Marshal(n)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notebooksBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+// notebookVersionKey returns the key of a version of a notebook. The keys
+// of the versions of a notebook share the encoded notebook ID as prefix and
+// sort by version.
+func notebookVersionKey(id influxdb.ID, version int) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidNotebookID
+	}
+	if version < 1 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid notebook version %d", version),
+		}
+	}
+
+	k := make([]byte, len(encodedID)+8)
+	copy(k, encodedID)
+	binary.BigEndian.PutUint64(k[len(encodedID):], uint64(version))
+	return k, nil
+}
+
+// putNotebookVersion keeps the contents of the notebook as its current
+// version, and removes the version that is no longer kept.
+func (s *Service) putNotebookVersion(ctx context.Context, tx Tx, n *influxdb.Notebook) error {
+	k, err := notebookVersionKey(n.ID, n.Version)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(&influxdb.NotebookVersion{
+		NotebookID:  n.ID,
+		Version:     n.Version,
+		Name:        n.Name,
+		Description: n.Description,
+		Cells:       n.Cells,
+		CreatedAt:   n.UpdatedAt,
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notebookVersionsBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(k, v); err != nil {
+		return err
+	}
+
+	if old := n.Version - influxdb.MaxNotebookVersions; old > 0 {
+		k, err := notebookVersionKey(n.ID, old)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	}
+	return nil
+}
+
+// forEachNotebookVersion calls fn with the key and each version of a
+// notebook, the oldest first, until fn returns false.
+func (s *Service) forEachNotebookVersion(ctx context.Context, tx Tx, id influxdb.ID, fn func([]byte, *influxdb.NotebookVersion) bool) error {
+	prefix, err := id.Encode()
+	if err != nil {
+		return ErrInvalidNotebookID
+	}
+
+	b, err := tx.Bucket(notebookVersionsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		nv, err := unmarshalNotebookVersion(v)
+		if err != nil {
+			return err
+		}
+		if !fn(k, nv) {
+			break
+		}
+	}
+	return nil
+}
+
+func unmarshalNotebook(v []byte) (*influxdb.Notebook, error) {
+	n := &influxdb.Notebook{}
+	if err := json.Unmarshal(v, n); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal notebook",
+			Err:  err,
+		}
+	}
+	return n, nil
+}
+
+func unmarshalNotebookVersion(v []byte) (*influxdb.NotebookVersion, error) {
+	nv := &influxdb.NotebookVersion{}
+	if err := json.Unmarshal(v, nv); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal notebook version",
+			Err:  err,
+		}
+	}
+	return nv, nil
+}
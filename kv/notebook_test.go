@@ -0,0 +1,149 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestNotebooks(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+
+			n := &influxdb.Notebook{
+				OrganizationID: org.ID,
+				Name:           "cpu",
+				Cells: []influxdb.NotebookCell{
+					{NotebookCellContents: influxdb.NotebookCellContents{Type: influxdb.NotebookCellTypeMarkdown, Text: "# CPU"}},
+					{NotebookCellContents: influxdb.NotebookCellContents{Type: influxdb.NotebookCellTypeFlux, Query: `from(bucket: "b") |> range(start: -1h)`}},
+				},
+			}
+			if err := svc.CreateNotebook(ctx, n); err != nil {
+				t.Fatal(err)
+			}
+			if !n.ID.Valid() || n.Version != 1 || n.CreatedAt.IsZero() {
+				t.Fatalf("expected an ID, version 1 and a creation time, got %+v", n)
+			}
+			for _, c := range n.Cells {
+				if !c.ID.Valid() {
+					t.Fatalf("expected cells to be given IDs, got %+v", n.Cells)
+				}
+			}
+
+			invalid := &influxdb.Notebook{
+				OrganizationID: org.ID,
+				Name:           "invalid",
+				Cells:          []influxdb.NotebookCell{{NotebookCellContents: influxdb.NotebookCellContents{Type: "chart"}}},
+			}
+			if err := svc.CreateNotebook(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid cell type to be rejected, got %v", err)
+			}
+
+			fluxID, markdownID := n.Cells[1].ID, n.Cells[0].ID
+			cells := append(n.Cells, influxdb.NotebookCell{
+				NotebookCellContents: influxdb.NotebookCellContents{Type: influxdb.NotebookCellTypeVisualization, SourceID: &markdownID},
+				Properties:           influxdb.XYViewProperties{Type: influxdb.ViewPropertyTypeXY, Geom: "line"},
+			})
+			if _, err := svc.UpdateNotebook(ctx, n.ID, influxdb.NotebookUpdate{Cells: &cells}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected visualization of a markdown cell to be rejected, got %v", err)
+			}
+
+			cells[2].SourceID = &fluxID
+			version := 1
+			updated, err := svc.UpdateNotebook(ctx, n.ID, influxdb.NotebookUpdate{Cells: &cells, Version: &version})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Version != 2 || len(updated.Cells) != 3 || !updated.Cells[2].ID.Valid() {
+				t.Fatalf("expected version 2 with 3 cells, got %+v", updated)
+			}
+
+			got, err := svc.FindNotebookByID(ctx, n.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if props, ok := got.Cells[2].Properties.(influxdb.XYViewProperties); !ok || props.Geom != "line" {
+				t.Fatalf("expected the view properties of the visualization cell to be kept, got %#v", got.Cells[2].Properties)
+			}
+
+			name := "cpu usage"
+			if _, err := svc.UpdateNotebook(ctx, n.ID, influxdb.NotebookUpdate{Name: &name, Version: &version}); influxdb.ErrorCode(err) != influxdb.EConflict {
+				t.Fatalf("expected update of a stale version to conflict, got %v", err)
+			}
+			if _, err := svc.UpdateNotebook(ctx, n.ID, influxdb.NotebookUpdate{Name: &name}); err != nil {
+				t.Fatal(err)
+			}
+
+			vs, err := svc.FindNotebookVersions(ctx, n.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(vs) != 3 || vs[0].Version != 3 || vs[2].Version != 1 {
+				t.Fatalf("expected versions 3, 2 and 1, got %d versions", len(vs))
+			}
+			v, err := svc.FindNotebookVersion(ctx, n.ID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Name != "cpu" || len(v.Cells) != 2 {
+				t.Fatalf("expected the contents of version 1, got %+v", v)
+			}
+			if _, err := svc.FindNotebookVersion(ctx, n.ID, 4); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected missing version to be not found, got %v", err)
+			}
+
+			for i := 0; i < influxdb.MaxNotebookVersions; i++ {
+				desc := "revision"
+				if _, err := svc.UpdateNotebook(ctx, n.ID, influxdb.NotebookUpdate{Description: &desc}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			vs, err = svc.FindNotebookVersions(ctx, n.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(vs) != influxdb.MaxNotebookVersions || vs[len(vs)-1].Version != 4 {
+				t.Fatalf("expected the %d most recent versions to be kept, got %d", influxdb.MaxNotebookVersions, len(vs))
+			}
+
+			ns, count, err := svc.FindNotebooks(ctx, influxdb.NotebookFilter{OrganizationID: &org.ID, Name: &name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != 1 || ns[0].ID != n.ID {
+				t.Fatalf("expected to find the notebook by name, got %d notebooks", count)
+			}
+
+			if err := svc.DeleteNotebook(ctx, n.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindNotebookByID(ctx, n.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected deleted notebook to be not found, got %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package kv
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,12 +12,19 @@ import (
 )
 
 var (
-	onboardingBucket = []byte("onboardingv1")
-	onboardingKey    = []byte("onboarding_key")
+	onboardingBucket     = []byte("onboardingv1")
+	onboardingKey        = []byte("onboarding_key")
+	onboardingConfirmKey = []byte("onboarding_confirmation")
 )
 
 var _ influxdb.OnboardingService = (*Service)(nil)
 
+// OnboardingReplayWindow is how long after a confirmed onboarding repeating
+// its request returns the original results. The results hold the tokens
+// created by the onboarding, so they are not served to replays any longer
+// than a client may take to retry a request whose response it lost.
+const OnboardingReplayWindow = 10 * time.Minute
+
 func (s *Service) initializeOnboarding(ctx context.Context, tx Tx) error {
 	_, err := tx.Bucket(onboardingBucket)
 	return err
@@ -102,6 +112,9 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 		return nil, err
 	}
 	if !isOnboarding {
+		if req != nil && req.ConfirmationToken != "" {
+			return s.findConfirmedOnboarding(ctx, req.ConfirmationToken)
+		}
 		return nil, &influxdb.Error{
 			Code: influxdb.EConflict,
 			Msg:  "onboarding has already been completed",
@@ -128,6 +141,7 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 		Token:       req.Token,
 	}
 
+	var res *influxdb.OnboardingResults
 	err = s.kv.Update(ctx, func(tx Tx) error {
 		if err := s.createUser(ctx, tx, u); err != nil {
 			return err
@@ -158,16 +172,243 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 			return err
 		}
 
+		results := &influxdb.OnboardingResults{
+			User:   u,
+			Org:    o,
+			Bucket: bucket,
+			Auth:   auth,
+		}
+		if err := s.generateProfile(ctx, tx, req, results); err != nil {
+			return err
+		}
+		res = results
+
+		if req.ConfirmationToken != "" {
+			if err := s.putOnboardingConfirmation(ctx, tx, req.ConfirmationToken, results); err != nil {
+				return err
+			}
+		}
+
 		return s.putOnboardingStatus(ctx, tx, true)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &influxdb.OnboardingResults{
-		User:   u,
-		Org:    o,
-		Bucket: bucket,
-		Auth:   auth,
-	}, nil
+	return res, nil
+}
+
+// generateProfile creates the additional buckets, users and tokens of the
+// setup profile and appends them to the results.
+func (s *Service) generateProfile(ctx context.Context, tx Tx, req *influxdb.OnboardingRequest, results *influxdb.OnboardingResults) error {
+	buckets := map[string]influxdb.ID{results.Bucket.Name: results.Bucket.ID}
+	for _, ob := range req.Buckets {
+		b := &influxdb.Bucket{
+			OrgID:           results.Org.ID,
+			Name:            ob.Name,
+			Description:     ob.Description,
+			RetentionPeriod: time.Duration(ob.RetentionPeriod) * time.Hour,
+		}
+		if err := s.createBucket(ctx, tx, b); err != nil {
+			return err
+		}
+		buckets[b.Name] = b.ID
+		results.Buckets = append(results.Buckets, b)
+	}
+
+	users := map[string]influxdb.ID{results.User.Name: results.User.ID}
+	for _, ou := range req.Users {
+		u := &influxdb.User{Name: ou.Name}
+		if err := s.createUser(ctx, tx, u); err != nil {
+			return err
+		}
+		if ou.Password != "" {
+			if err := s.setPassword(ctx, tx, u.ID, ou.Password); err != nil {
+				return err
+			}
+		}
+
+		role := ou.Role
+		if role == "" {
+			role = influxdb.Member
+		}
+		if err := s.createUserResourceMapping(ctx, tx, &influxdb.UserResourceMapping{
+			UserID:       u.ID,
+			UserType:     role,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   results.Org.ID,
+		}); err != nil {
+			return err
+		}
+		users[u.Name] = u.ID
+		results.Users = append(results.Users, u)
+	}
+
+	for _, ot := range req.Tokens {
+		userID := results.User.ID
+		if ot.User != "" {
+			userID = users[ot.User]
+		}
+
+		a := &influxdb.Authorization{
+			OrgID:       results.Org.ID,
+			UserID:      userID,
+			Description: ot.Description,
+			Token:       ot.Token,
+		}
+		for _, op := range ot.Permissions {
+			p := influxdb.Permission{
+				Action: op.Action,
+				Resource: influxdb.Resource{
+					Type:  op.Resource.Type,
+					OrgID: &results.Org.ID,
+				},
+			}
+			if op.Resource.Name != "" {
+				id := buckets[op.Resource.Name]
+				p.Resource.ID = &id
+			}
+			a.Permissions = append(a.Permissions, p)
+		}
+		if err := s.createAuthorization(ctx, tx, a); err != nil {
+			return err
+		}
+		results.Auths = append(results.Auths, a)
+	}
+
+	return nil
+}
+
+// onboardingConfirmation records the resources created by an onboarding
+// request that carried a confirmation token.
+type onboardingConfirmation struct {
+	Hash        []byte        `json:"hash"`
+	ConfirmedAt time.Time     `json:"confirmedAt"`
+	UserID      influxdb.ID   `json:"userID"`
+	OrgID       influxdb.ID   `json:"orgID"`
+	BucketID    influxdb.ID   `json:"bucketID"`
+	AuthID      influxdb.ID   `json:"authID"`
+	BucketIDs   []influxdb.ID `json:"bucketIDs,omitempty"`
+	UserIDs     []influxdb.ID `json:"userIDs,omitempty"`
+	AuthIDs     []influxdb.ID `json:"authIDs,omitempty"`
+}
+
+func hashConfirmationToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
+
+func (s *Service) putOnboardingConfirmation(ctx context.Context, tx Tx, token string, results *influxdb.OnboardingResults) error {
+	c := onboardingConfirmation{
+		Hash:        hashConfirmationToken(token),
+		ConfirmedAt: s.Now(),
+		UserID:      results.User.ID,
+		OrgID:       results.Org.ID,
+		BucketID:    results.Bucket.ID,
+		AuthID:      results.Auth.ID,
+	}
+	for _, b := range results.Buckets {
+		c.BucketIDs = append(c.BucketIDs, b.ID)
+	}
+	for _, u := range results.Users {
+		c.UserIDs = append(c.UserIDs, u.ID)
+	}
+	for _, a := range results.Auths {
+		c.AuthIDs = append(c.AuthIDs, a.ID)
+	}
+
+	v, err := json.Marshal(c)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	bucket, err := tx.Bucket(onboardingBucket)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(onboardingConfirmKey, v)
+}
+
+// findConfirmedOnboarding returns the results of the completed onboarding
+// when it was confirmed with the same token less than OnboardingReplayWindow
+// ago.
+func (s *Service) findConfirmedOnboarding(ctx context.Context, token string) (*influxdb.OnboardingResults, error) {
+	var res *influxdb.OnboardingResults
+	err := s.kv.View(ctx, func(tx Tx) error {
+		bucket, err := tx.Bucket(onboardingBucket)
+		if err != nil {
+			return err
+		}
+
+		v, err := bucket.Get(onboardingConfirmKey)
+		if IsNotFound(err) {
+			return errOnboardingNotConfirmed
+		}
+		if err != nil {
+			return err
+		}
+
+		var c onboardingConfirmation
+		if err := json.Unmarshal(v, &c); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+		if subtle.ConstantTimeCompare(c.Hash, hashConfirmationToken(token)) != 1 {
+			return errOnboardingNotConfirmed
+		}
+		if s.Now().Sub(c.ConfirmedAt) >= OnboardingReplayWindow {
+			return errOnboardingNotConfirmed
+		}
+
+		results := &influxdb.OnboardingResults{}
+		if results.User, err = s.findUserByID(ctx, tx, c.UserID); err != nil {
+			return err
+		}
+		if results.Org, err = s.findOrganizationByID(ctx, tx, c.OrgID); err != nil {
+			return err
+		}
+		if results.Bucket, err = s.findBucketByID(ctx, tx, c.BucketID); err != nil {
+			return err
+		}
+		if results.Auth, err = s.findAuthorizationByID(ctx, tx, c.AuthID); err != nil {
+			return err
+		}
+		for _, id := range c.BucketIDs {
+			b, err := s.findBucketByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			results.Buckets = append(results.Buckets, b)
+		}
+		for _, id := range c.UserIDs {
+			u, err := s.findUserByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			results.Users = append(results.Users, u)
+		}
+		for _, id := range c.AuthIDs {
+			a, err := s.findAuthorizationByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			results.Auths = append(results.Auths, a)
+		}
+
+		res = results
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+var errOnboardingNotConfirmed = &influxdb.Error{
+	Code: influxdb.EConflict,
+	Msg:  "onboarding has already been completed",
 }
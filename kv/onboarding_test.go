@@ -3,9 +3,11 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 )
 
@@ -17,6 +19,48 @@ func TestInmemOnboardingService(t *testing.T) {
 	influxdbtesting.Generate(initInmemOnboardingService, t)
 }
 
+func TestService_OnboardingReplayWindow(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := &mock.TimeGenerator{FakeValue: time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)}
+	svc := kv.NewService(store)
+	svc.TimeGenerator = now
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("unable to initialize kv store: %v", err)
+	}
+
+	req := &influxdb.OnboardingRequest{
+		User:              "admin",
+		Password:          "password1",
+		Org:               "org1",
+		Bucket:            "bucket1",
+		ConfirmationToken: "confirm1",
+	}
+	results, err := svc.Generate(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now.FakeValue = now.FakeValue.Add(kv.OnboardingReplayWindow - time.Second)
+	replayed, err := svc.Generate(ctx, req)
+	if err != nil {
+		t.Fatalf("expected a replay within the window to succeed, got %v", err)
+	}
+	if replayed.Auth.Token != results.Auth.Token {
+		t.Errorf("expected the replay to return the original results, got token %q", replayed.Auth.Token)
+	}
+
+	now.FakeValue = now.FakeValue.Add(time.Second)
+	if _, err := svc.Generate(ctx, req); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a replay after the window to conflict, got %v", err)
+	}
+}
+
 func initBoltOnboardingService(f influxdbtesting.OnboardingFields, t *testing.T) (influxdb.OnboardingService, func()) {
 	s, closeStore, err := NewTestBoltStore()
 	if err != nil {
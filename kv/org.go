@@ -481,6 +481,9 @@ func (s *Service) DeleteOrganization(ctx context.Context, id influxdb.ID) error
 		if err := s.deleteOrganizationsBuckets(ctx, tx, id); err != nil {
 			return err
 		}
+		if err := s.deleteOrganizationSettings(ctx, tx, id); err != nil {
+			return err
+		}
 		if pe := s.deleteOrganization(ctx, tx, id); pe != nil {
 			return pe
 		}
@@ -678,6 +681,24 @@ func (s *Service) FindResourceOrganizationID(ctx context.Context, rt influxdb.Re
 			return influxdb.InvalidID(), err
 		}
 		return r.OrgID, nil
+	case influxdb.NotebooksResourceType:
+		r, err := s.FindNotebookByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrganizationID, nil
+	case influxdb.QueryTemplatesResourceType:
+		r, err := s.FindQueryTemplateByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrganizationID, nil
+	case influxdb.ReportsResourceType:
+		r, err := s.FindReportByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrganizationID, nil
 	}
 
 	return influxdb.InvalidID(), &influxdb.Error{
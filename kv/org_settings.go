@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var orgSettingsBucket = []byte("orgsettingsv1")
+
+var _ influxdb.OrganizationSettingsService = (*Service)(nil)
+
+func (s *Service) initializeOrgSettings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(orgSettingsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindOrganizationSettings returns the settings of an organization.
+func (s *Service) FindOrganizationSettings(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+	var os *influxdb.OrganizationSettings
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, orgID); err != nil {
+			return err
+		}
+
+		settings, err := s.findOrganizationSettings(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		os = settings
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindOrganizationSettings,
+			Err: err,
+		}
+	}
+	return os, nil
+}
+
+// UpdateOrganizationSettings updates the settings of an organization with the changeset.
+func (s *Service) UpdateOrganizationSettings(ctx context.Context, orgID influxdb.ID, upd influxdb.OrganizationSettingsUpdate) (*influxdb.OrganizationSettings, error) {
+	var os *influxdb.OrganizationSettings
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, orgID); err != nil {
+			return err
+		}
+
+		settings, err := s.findOrganizationSettings(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(settings)
+		if err := settings.Valid(); err != nil {
+			return err
+		}
+		settings.UpdatedAt = s.Now()
+
+		if err := s.putOrganizationSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+		os = settings
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateOrganizationSettings,
+			Err: err,
+		}
+	}
+	return os, nil
+}
+
+// findOrganizationSettings returns the stored settings of the organization
+// or empty settings if the organization was never configured.
+func (s *Service) findOrganizationSettings(ctx context.Context, tx Tx, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+	k, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgSettingsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return &influxdb.OrganizationSettings{OrgID: orgID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	os := &influxdb.OrganizationSettings{}
+	if err := json.Unmarshal(v, os); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return os, nil
+}
+
+func (s *Service) putOrganizationSettings(ctx context.Context, tx Tx, os *influxdb.OrganizationSettings) error {
+	k, err := os.OrgID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(os)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgSettingsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func (s *Service) deleteOrganizationSettings(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	k, err := orgID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgSettingsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
@@ -0,0 +1,148 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_OrganizationSettingsBucketDefaults(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing org settings service: %v", err)
+	}
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := svc.FindOrganizationSettings(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.OrgID != o.ID || settings.DefaultRetentionPeriod != 0 || settings.DefaultSchemaType != "" {
+		t.Fatalf("expected empty settings, got %+v", settings)
+	}
+
+	week, day := 7*24*time.Hour, 24*time.Hour
+	implicit := influxdb.SchemaTypeImplicit
+	if _, err := svc.UpdateOrganizationSettings(ctx, o.ID, influxdb.OrganizationSettingsUpdate{
+		DefaultRetentionPeriod:    &week,
+		DefaultShardGroupDuration: &day,
+		DefaultSchemaType:         &implicit,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	explicit := influxdb.SchemaType("explicit")
+	if _, err := svc.UpdateOrganizationSettings(ctx, o.ID, influxdb.OrganizationSettingsUpdate{
+		DefaultSchemaType: &explicit,
+	}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected explicit schemas to be rejected, got %v", err)
+	}
+
+	tooLong := 2 * week
+	if _, err := svc.UpdateOrganizationSettings(ctx, o.ID, influxdb.OrganizationSettingsUpdate{
+		DefaultShardGroupDuration: &tooLong,
+	}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected shard group duration longer than retention to be invalid, got %v", err)
+	}
+
+	b := &influxdb.Bucket{OrgID: o.ID, Name: "defaults"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if b.RetentionPeriod != week || b.ShardGroupDuration != day || b.SchemaType != implicit {
+		t.Fatalf("expected org defaults to be applied, got %+v", b)
+	}
+
+	b = &influxdb.Bucket{OrgID: o.ID, Name: "explicit", RetentionPeriod: time.Hour}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if b.RetentionPeriod != time.Hour || b.ShardGroupDuration != 0 {
+		t.Fatalf("expected explicit values to be kept, got %+v", b)
+	}
+
+	for _, typ := range []influxdb.SchemaType{"columnar", explicit} {
+		b = &influxdb.Bucket{OrgID: o.ID, Name: "invalid", SchemaType: typ}
+		if err := svc.CreateBucket(ctx, b); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("expected schema type %q to be rejected, got %v", typ, err)
+		}
+	}
+
+	if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindOrganizationSettings(ctx, o.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected settings of deleted org to be not found, got %v", err)
+	}
+}
+
+func TestService_OrganizationSettingsLocale(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing org settings service: %v", err)
+	}
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	strPtr := func(s string) *string { return &s }
+	for _, upd := range []influxdb.OrganizationSettingsUpdate{
+		{Timezone: strPtr("Mars/Olympus_Mons")},
+		{Timezone: strPtr("Local")},
+		{WeekStart: strPtr("Sunday")},
+	} {
+		if _, err := svc.UpdateOrganizationSettings(ctx, o.ID, upd); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("expected %+v to be invalid, got %v", upd, err)
+		}
+	}
+
+	settings, err := svc.UpdateOrganizationSettings(ctx, o.ID, influxdb.OrganizationSettingsUpdate{
+		Timezone:  strPtr("Europe/Berlin"),
+		WeekStart: strPtr("sunday"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Location().String() != "Europe/Berlin" || settings.FirstDayOfWeek() != time.Sunday {
+		t.Fatalf("expected the locale of the org, got %+v", settings)
+	}
+
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "defaults"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Timezone != "Europe/Berlin" {
+		t.Fatalf("expected the timezone of the org to be applied, got %q", d.Timezone)
+	}
+
+	d = &influxdb.Dashboard{OrganizationID: o.ID, Name: "explicit", DashboardSettings: influxdb.DashboardSettings{Timezone: "UTC"}}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Timezone != "UTC" {
+		t.Fatalf("expected the explicit timezone to be kept, got %q", d.Timezone)
+	}
+}
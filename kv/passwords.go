@@ -1,9 +1,16 @@
 package kv
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
 
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/influxdata/influxdb"
@@ -81,7 +88,7 @@ func (s *Service) initializePasswords(ctx context.Context, tx Tx) error {
 // updates to the new password.
 func (s *Service) CompareAndSetPassword(ctx context.Context, userID influxdb.ID, old string, new string) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
-		if err := s.comparePassword(ctx, tx, userID, old); err != nil {
+		if _, err := s.comparePassword(ctx, tx, userID, old); err != nil {
 			return err
 		}
 		return s.setPassword(ctx, tx, userID, new)
@@ -96,13 +103,59 @@ func (s *Service) SetPassword(ctx context.Context, userID influxdb.ID, password
 }
 
 // ComparePassword checks if the password matches the password recorded.
-// Passwords that do not match return errors.
+// Passwords that do not match return errors. A matching password hashed with
+// other parameters than those of the hasher of the service, e.g. after the
+// hash algorithm or its cost changed, is hashed again.
 func (s *Service) ComparePassword(ctx context.Context, userID influxdb.ID, password string) error {
-	return s.kv.View(ctx, func(tx Tx) error {
-		return s.comparePassword(ctx, tx, userID, password)
+	var hash []byte
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		hash, err = s.comparePassword(ctx, tx, userID, password)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if r, ok := s.hasher().(Rehasher); ok && r.NeedsRehash(hash) {
+		if err := s.rehashPassword(ctx, userID, hash, password); err != nil {
+			// the password matched, failing to rehash it does not fail the comparison.
+			s.Logger.Info("Failed to rehash password", zap.Stringer("user_id", userID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// rehashPassword replaces the hash of the password of the user with a hash
+// generated by the hasher of the service, unless the password changed since
+// it was compared to the hash.
+func (s *Service) rehashPassword(ctx context.Context, userID influxdb.ID, hash []byte, password string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		encodedID, err := userID.Encode()
+		if err != nil {
+			return CorruptUserIDError(userID.String(), err)
+		}
+
+		b, err := tx.Bucket(userpasswordBucket)
+		if err != nil {
+			return UnavailablePasswordServiceError(err)
+		}
+
+		current, err := b.Get(encodedID)
+		if err != nil || !bytes.Equal(current, hash) {
+			return err
+		}
+		return s.putPassword(b, encodedID, password)
 	})
 }
 
+func (s *Service) hasher() Crypt {
+	if s.Hash == nil {
+		return &Bcrypt{}
+	}
+	return s.Hash
+}
+
 func (s *Service) setPassword(ctx context.Context, tx Tx, userID influxdb.ID, password string) error {
 	if len(password) < MinPasswordLength {
 		return EShortPassword
@@ -122,12 +175,11 @@ func (s *Service) setPassword(ctx context.Context, tx Tx, userID influxdb.ID, pa
 		return UnavailablePasswordServiceError(err)
 	}
 
-	hasher := s.Hash
-	if hasher == nil {
-		hasher = &Bcrypt{}
-	}
+	return s.putPassword(b, encodedID, password)
+}
 
-	hash, err := hasher.GenerateFromPassword([]byte(password), DefaultCost)
+func (s *Service) putPassword(b Bucket, encodedID []byte, password string) error {
+	hash, err := s.hasher().GenerateFromPassword([]byte(password), DefaultCost)
 	if err != nil {
 		return InternalPasswordHashError(err)
 	}
@@ -138,37 +190,34 @@ func (s *Service) setPassword(ctx context.Context, tx Tx, userID influxdb.ID, pa
 	return nil
 }
 
-func (s *Service) comparePassword(ctx context.Context, tx Tx, userID influxdb.ID, password string) error {
+// comparePassword returns the hash of the password of the user if it matches
+// the password.
+func (s *Service) comparePassword(ctx context.Context, tx Tx, userID influxdb.ID, password string) ([]byte, error) {
 	encodedID, err := userID.Encode()
 	if err != nil {
-		return CorruptUserIDError(userID.String(), err)
+		return nil, CorruptUserIDError(userID.String(), err)
 	}
 
 	if _, err := s.findUserByID(ctx, tx, userID); err != nil {
-		return EIncorrectUser
+		return nil, EIncorrectUser
 	}
 
 	b, err := tx.Bucket(userpasswordBucket)
 	if err != nil {
-		return UnavailablePasswordServiceError(err)
+		return nil, UnavailablePasswordServiceError(err)
 	}
 
 	hash, err := b.Get(encodedID)
 	if err != nil {
 		// User exists but has no password has been set.
-		return EIncorrectPassword
-	}
-
-	hasher := s.Hash
-	if hasher == nil {
-		hasher = &Bcrypt{}
+		return nil, EIncorrectPassword
 	}
 
-	if err := hasher.CompareHashAndPassword(hash, []byte(password)); err != nil {
+	if err := s.hasher().CompareHashAndPassword(hash, []byte(password)); err != nil {
 		// User exists but the password was incorrect
-		return EIncorrectPassword
+		return nil, EIncorrectPassword
 	}
-	return nil
+	return hash, nil
 }
 
 // DefaultCost is the cost that will actually be set if a cost below MinCost
@@ -185,22 +234,195 @@ type Crypt interface {
 	GenerateFromPassword(password []byte, cost int) ([]byte, error)
 }
 
-var _ Crypt = (*Bcrypt)(nil)
+// Rehasher is implemented by the Crypts that tell whether a hash was generated
+// with other parameters than those of the hashes they generate.
+type Rehasher interface {
+	// NeedsRehash returns whether the hash was generated with another
+	// algorithm or other parameters.
+	NeedsRehash(hashedPassword []byte) bool
+}
 
-// Bcrypt implements Crypt using golang.org/x/crypto/bcrypt
-type Bcrypt struct{}
+var (
+	_ Crypt    = (*Bcrypt)(nil)
+	_ Rehasher = (*Bcrypt)(nil)
+)
+
+// Bcrypt implements Crypt using golang.org/x/crypto/bcrypt. Argon2id hashes
+// are verified with argon2id, so that passwords set before switching back to
+// bcrypt keep working.
+type Bcrypt struct {
+	// Cost is the cost of the hashes generated, overriding the cost given to
+	// GenerateFromPassword if set.
+	Cost int
+}
 
 // CompareHashAndPassword compares a hashed password with its possible plaintext equivalent.
 // Returns nil on success, or an error on failure.
 func (b *Bcrypt) CompareHashAndPassword(hashedPassword, password []byte) error {
+	if isArgon2idHash(hashedPassword) {
+		return (&Argon2id{}).CompareHashAndPassword(hashedPassword, password)
+	}
 	return bcrypt.CompareHashAndPassword(hashedPassword, password)
 }
 
 // GenerateFromPassword returns the hash of the password at the given cost.
 // If the cost given is less than MinCost, the cost will be set to DefaultCost, instead.
 func (b *Bcrypt) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
+	if b.Cost != 0 {
+		cost = b.Cost
+	}
 	if cost < bcrypt.MinCost {
 		cost = DefaultCost
 	}
 	return bcrypt.GenerateFromPassword(password, cost)
 }
+
+// NeedsRehash returns whether the hash is not a bcrypt hash of the cost of
+// the hashes generated.
+func (b *Bcrypt) NeedsRehash(hashedPassword []byte) bool {
+	cost, err := bcrypt.Cost(hashedPassword)
+	if err != nil {
+		return true
+	}
+	want := b.Cost
+	if want < bcrypt.MinCost {
+		want = DefaultCost
+	}
+	return cost != want
+}
+
+// Default parameters of argon2id, the second recommended option of RFC 9106
+// but with a parallelism of 2.
+const (
+	DefaultArgon2Memory      = 64 * 1024 // 64 MiB
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	errArgon2idMalformed = errors.New("malformed argon2id hash")
+	errArgon2idMismatch  = errors.New("hashedPassword is not the hash of the given password")
+)
+
+var (
+	_ Crypt    = (*Argon2id)(nil)
+	_ Rehasher = (*Argon2id)(nil)
+)
+
+// Argon2id implements Crypt using the argon2id key derivation function of
+// golang.org/x/crypto/argon2. Hashes are encoded in the PHC string format
+//
+//	$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+//
+// with unpadded base64 salt and key. Bcrypt hashes are verified with bcrypt,
+// so that passwords set before switching to argon2id keep working.
+type Argon2id struct {
+	// Memory is the memory used to hash passwords, in KiB. Defaults to
+	// DefaultArgon2Memory.
+	Memory uint32
+	// Iterations is the number of passes over the memory. Defaults to
+	// DefaultArgon2Iterations.
+	Iterations uint32
+	// Parallelism is the number of threads hashing passwords. Defaults to
+	// DefaultArgon2Parallelism.
+	Parallelism uint8
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func (a *Argon2id) params() argon2Params {
+	p := argon2Params{
+		memory:      a.Memory,
+		iterations:  a.Iterations,
+		parallelism: a.Parallelism,
+	}
+	if p.memory == 0 {
+		p.memory = DefaultArgon2Memory
+	}
+	if p.iterations == 0 {
+		p.iterations = DefaultArgon2Iterations
+	}
+	if p.parallelism == 0 {
+		p.parallelism = DefaultArgon2Parallelism
+	}
+	return p
+}
+
+// CompareHashAndPassword compares a hashed password with its possible plaintext equivalent.
+// Returns nil on success, or an error on failure.
+func (a *Argon2id) CompareHashAndPassword(hashedPassword, password []byte) error {
+	if !isArgon2idHash(hashedPassword) {
+		return bcrypt.CompareHashAndPassword(hashedPassword, password)
+	}
+
+	p, salt, key, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+	other := argon2.IDKey(password, salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errArgon2idMismatch
+	}
+	return nil
+}
+
+// GenerateFromPassword returns the argon2id hash of the password with a
+// random salt. The cost is ignored, the parameters of a are used instead.
+func (a *Argon2id) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	p := a.params()
+	key := argon2.IDKey(password, salt, p.iterations, p.memory, p.parallelism, argon2KeyLength)
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)), nil
+}
+
+// NeedsRehash returns whether the hash is not an argon2id hash of the
+// parameters of a.
+func (a *Argon2id) NeedsRehash(hashedPassword []byte) bool {
+	p, _, _, err := decodeArgon2idHash(hashedPassword)
+	return err != nil || p != a.params()
+}
+
+func isArgon2idHash(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte("$argon2id$"))
+}
+
+func decodeArgon2idHash(hash []byte) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+	parts := bytes.Split(hash, []byte("$"))
+	if len(parts) != 6 || string(parts[1]) != "argon2id" {
+		return p, nil, nil, errArgon2idMalformed
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(string(parts[2]), "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errArgon2idMalformed
+	}
+	if _, err := fmt.Sscanf(string(parts[3]), "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil || p.iterations == 0 || p.parallelism == 0 {
+		return p, nil, nil, errArgon2idMalformed
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(string(parts[4]))
+	if err != nil {
+		return p, nil, nil, errArgon2idMalformed
+	}
+	key, err := base64.RawStdEncoding.DecodeString(string(parts[5]))
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errArgon2idMalformed
+	}
+	return p, salt, key, nil
+}
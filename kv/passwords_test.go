@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/influxdata/influxdb"
@@ -506,3 +507,104 @@ func TestService_ComparePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestArgon2id(t *testing.T) {
+	a := &kv.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}
+	hash, err := a.GenerateFromPassword([]byte("howdydoody"), kv.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected hash %s", hash)
+	}
+	if err := a.CompareHashAndPassword(hash, []byte("howdydoody")); err != nil {
+		t.Errorf("expected the password to match: %v", err)
+	}
+	if err := a.CompareHashAndPassword(hash, []byte("doodyhowdy")); err == nil {
+		t.Error("expected another password not to match")
+	}
+	if err := (&kv.Bcrypt{}).CompareHashAndPassword(hash, []byte("howdydoody")); err != nil {
+		t.Errorf("expected bcrypt to verify argon2id hashes: %v", err)
+	}
+	if a.NeedsRehash(hash) {
+		t.Error("expected a hash of the same parameters not to need a rehash")
+	}
+	if !(&kv.Argon2id{Memory: 2048, Iterations: 1, Parallelism: 1}).NeedsRehash(hash) {
+		t.Error("expected a hash of other parameters to need a rehash")
+	}
+
+	bhash, err := (&kv.Bcrypt{Cost: 4}).GenerateFromPassword([]byte("howdydoody"), kv.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CompareHashAndPassword(bhash, []byte("howdydoody")); err != nil {
+		t.Errorf("expected argon2id to verify bcrypt hashes: %v", err)
+	}
+	if !a.NeedsRehash(bhash) {
+		t.Error("expected a bcrypt hash to need a rehash")
+	}
+	if (&kv.Bcrypt{Cost: 4}).NeedsRehash(bhash) || !(&kv.Bcrypt{Cost: 5}).NeedsRehash(bhash) {
+		t.Error("expected bcrypt hashes of another cost only to need a rehash")
+	}
+}
+
+func TestService_ComparePassword_Rehash(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	svc.Hash = &kv.Bcrypt{Cost: 4}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	u := &influxdb.User{Name: "user1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetPassword(ctx, u.ID, "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+
+	hash := func() string {
+		var h []byte
+		if err := store.View(ctx, func(tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("userspasswordv1"))
+			if err != nil {
+				return err
+			}
+			id, _ := u.ID.Encode()
+			h, err = b.Get(id)
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return string(h)
+	}
+	before := hash()
+
+	svc.Hash = &kv.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}
+	if err := svc.ComparePassword(ctx, u.ID, "doodyhowdy"); err == nil {
+		t.Fatal("expected another password not to match")
+	}
+	if hash() != before {
+		t.Fatal("expected a password that does not match not to be rehashed")
+	}
+	if err := svc.ComparePassword(ctx, u.ID, "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+	after := hash()
+	if !strings.HasPrefix(after, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("expected the password to be rehashed with argon2id, got %s", after)
+	}
+
+	if err := svc.ComparePassword(ctx, u.ID, "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+	if hash() != after {
+		t.Error("expected a password hashed with the parameters of the hasher not to be rehashed")
+	}
+}
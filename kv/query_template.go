@@ -0,0 +1,295 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrQueryTemplateNotFound is used when the query template is not found.
+	ErrQueryTemplateNotFound = &influxdb.Error{
+		Msg:  "query template not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidQueryTemplateID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidQueryTemplateID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided query template ID has invalid format",
+	}
+)
+
+var queryTemplatesBucket = []byte("querytemplatesv1")
+
+var _ influxdb.QueryTemplateService = (*Service)(nil)
+
+func (s *Service) initializeQueryTemplates(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(queryTemplatesBucket)
+	return err
+}
+
+// FindQueryTemplateByID returns a single query template by ID.
+func (s *Service) FindQueryTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryTemplate, error) {
+	var t *influxdb.QueryTemplate
+	err := s.kv.View(ctx, func(tx Tx) error {
+		qt, err := s.findQueryTemplateByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		t = qt
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQueryTemplateByID,
+			Err: err,
+		}
+	}
+	return t, nil
+}
+
+// FindQueryTemplates returns the query templates that match the filter.
+func (s *Service) FindQueryTemplates(ctx context.Context, filter influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error) {
+	var ts []*influxdb.QueryTemplate
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			t, err := s.findQueryTemplateByID(ctx, tx, *filter.ID)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					return nil
+				}
+				return err
+			}
+			if filterQueryTemplate(t, filter) {
+				ts = append(ts, t)
+			}
+			return nil
+		}
+
+		return s.forEachQueryTemplate(ctx, tx, func(t *influxdb.QueryTemplate) bool {
+			if filterQueryTemplate(t, filter) {
+				ts = append(ts, t)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQueryTemplates,
+			Err: err,
+		}
+	}
+	return ts, nil
+}
+
+func filterQueryTemplate(t *influxdb.QueryTemplate, filter influxdb.QueryTemplateFilter) bool {
+	return (filter.ID == nil || t.ID == *filter.ID) &&
+		(filter.OrganizationID == nil || t.OrganizationID == *filter.OrganizationID) &&
+		(filter.Name == nil || t.Name == *filter.Name)
+}
+
+// CreateQueryTemplate creates a query template and sets t.ID with the new
+// identifier.
+func (s *Service) CreateQueryTemplate(ctx context.Context, t *influxdb.QueryTemplate) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+		if !t.OrganizationID.Valid() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "query template requires an organization",
+			}
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, t.OrganizationID); err != nil {
+			return err
+		}
+		if err := s.uniqueQueryTemplateName(ctx, tx, t); err != nil {
+			return err
+		}
+
+		t.ID = s.IDGenerator.ID()
+		now := s.Now()
+		t.SetCreatedAt(now)
+		t.SetUpdatedAt(now)
+		return s.putQueryTemplate(ctx, tx, t)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQueryTemplate,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateQueryTemplate updates a query template with the changeset.
+func (s *Service) UpdateQueryTemplate(ctx context.Context, id influxdb.ID, upd influxdb.QueryTemplateUpdate) (*influxdb.QueryTemplate, error) {
+	var t *influxdb.QueryTemplate
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := upd.Valid(); err != nil {
+			return err
+		}
+
+		qt, err := s.findQueryTemplateByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(qt)
+		if err := qt.Valid(); err != nil {
+			return err
+		}
+		if upd.Name != nil {
+			if err := s.uniqueQueryTemplateName(ctx, tx, qt); err != nil {
+				return err
+			}
+		}
+		qt.SetUpdatedAt(s.Now())
+
+		if err := s.putQueryTemplate(ctx, tx, qt); err != nil {
+			return err
+		}
+		t = qt
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateQueryTemplate,
+			Err: err,
+		}
+	}
+	return t, nil
+}
+
+// DeleteQueryTemplate removes a query template by ID.
+func (s *Service) DeleteQueryTemplate(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findQueryTemplateByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := id.Encode()
+		if err != nil {
+			return ErrInvalidQueryTemplateID
+		}
+
+		b, err := tx.Bucket(queryTemplatesBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteQueryTemplate,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueQueryTemplateName returns a conflict if another template of the
+// organization of t has its name.
+func (s *Service) uniqueQueryTemplateName(ctx context.Context, tx Tx, t *influxdb.QueryTemplate) error {
+	var exists bool
+	err := s.forEachQueryTemplate(ctx, tx, func(other *influxdb.QueryTemplate) bool {
+		exists = other.ID != t.ID && other.OrganizationID == t.OrganizationID && other.Name == t.Name
+		return !exists
+	})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("query template with name %s already exists", t.Name),
+		}
+	}
+	return nil
+}
+
+func (s *Service) findQueryTemplateByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.QueryTemplate, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidQueryTemplateID
+	}
+
+	b, err := tx.Bucket(queryTemplatesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrQueryTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalQueryTemplate(v)
+}
+
+// forEachQueryTemplate calls fn with each query template until fn returns
+// false.
+func (s *Service) forEachQueryTemplate(ctx context.Context, tx Tx, fn func(*influxdb.QueryTemplate) bool) error {
+	b, err := tx.Bucket(queryTemplatesBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		t, err := unmarshalQueryTemplate(v)
+		if err != nil {
+			return err
+		}
+		if !fn(t) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Service) putQueryTemplate(ctx context.Context, tx Tx, t *influxdb.QueryTemplate) error {
+	k, err := t.ID.Encode()
+	if err != nil {
+		return ErrInvalidQueryTemplateID
+	}
+
+	v, err := json.Marshal(t)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(queryTemplatesBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func unmarshalQueryTemplate(v []byte) (*influxdb.QueryTemplate, error) {
+	t := &influxdb.QueryTemplate{}
+	if err := json.Unmarshal(v, t); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal query template",
+			Err:  err,
+		}
+	}
+	return t, nil
+}
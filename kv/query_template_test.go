@@ -0,0 +1,115 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestQueryTemplates(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+
+			qt := &influxdb.QueryTemplate{
+				OrganizationID: org.ID,
+				Name:           "error-rate",
+				Params:         []influxdb.QueryTemplateParam{{Name: "bucket", Type: influxdb.QueryTemplateParamString}},
+				Flux:           `from(bucket: bucket) |> range(start: -1h)`,
+			}
+			if err := svc.CreateQueryTemplate(ctx, qt); err != nil {
+				t.Fatal(err)
+			}
+			if !qt.ID.Valid() || qt.CreatedAt.IsZero() {
+				t.Fatalf("expected an ID and a creation time, got %+v", qt)
+			}
+
+			dup := &influxdb.QueryTemplate{OrganizationID: org.ID, Name: "error-rate", Flux: `from(bucket: "b")`}
+			if err := svc.CreateQueryTemplate(ctx, dup); influxdb.ErrorCode(err) != influxdb.EConflict {
+				t.Fatalf("expected a conflict of names, got %v", err)
+			}
+			invalid := &influxdb.QueryTemplate{OrganizationID: org.ID, Name: "invalid", Flux: `from(bucket: `}
+			if err := svc.CreateQueryTemplate(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid flux to be rejected, got %v", err)
+			}
+
+			// Seeding the organization skips the built-in template it has
+			// its own template of.
+			seeded, err := influxdb.SeedQueryTemplates(ctx, svc, org.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(seeded) != len(influxdb.BuiltinQueryTemplates())-1 {
+				t.Fatalf("expected the other built-in templates to be seeded, got %d", len(seeded))
+			}
+			for _, s := range seeded {
+				if !s.ID.Valid() || s.OrganizationID != org.ID || s.Name == "error-rate" {
+					t.Fatalf("unexpected seeded template %+v", s)
+				}
+			}
+			if seeded, err := influxdb.SeedQueryTemplates(ctx, svc, org.ID); err != nil || len(seeded) != 0 {
+				t.Fatalf("expected seeding again to create no templates, got %d, %v", len(seeded), err)
+			}
+
+			found, err := influxdb.FindQueryTemplate(ctx, svc, org.ID, "error-rate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.ID != qt.ID {
+				t.Fatalf("expected the template of the organization to override the built-in template, got %+v", found)
+			}
+
+			flux := `from(bucket: bucket) |> range(start: -5m)`
+			updated, err := svc.UpdateQueryTemplate(ctx, qt.ID, influxdb.QueryTemplateUpdate{Flux: &flux})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Flux != flux {
+				t.Fatalf("expected the flux to be updated, got %+v", updated)
+			}
+			name := "burn-rate"
+			if _, err := svc.UpdateQueryTemplate(ctx, qt.ID, influxdb.QueryTemplateUpdate{Name: &name}); influxdb.ErrorCode(err) != influxdb.EConflict {
+				t.Fatalf("expected a conflict of names, got %v", err)
+			}
+
+			orgID, err := svc.FindResourceOrganizationID(ctx, influxdb.QueryTemplatesResourceType, qt.ID)
+			if err != nil || orgID != org.ID {
+				t.Fatalf("expected the organization of the template, got %s, %v", orgID, err)
+			}
+
+			if err := svc.DeleteQueryTemplate(ctx, qt.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindQueryTemplateByID(ctx, qt.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected the template to be deleted, got %v", err)
+			}
+			ts, err := svc.FindQueryTemplates(ctx, influxdb.QueryTemplateFilter{OrganizationID: &org.ID})
+			if err != nil || len(ts) != len(seeded) {
+				t.Fatalf("expected the seeded templates to remain, got %d, %v", len(ts), err)
+			}
+		})
+	}
+}
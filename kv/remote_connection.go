@@ -0,0 +1,287 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// ErrRemoteConnectionNotFound is used when the remote connection is not found.
+	ErrRemoteConnectionNotFound = &influxdb.Error{
+		Msg:  "remote connection not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidRemoteConnectionID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidRemoteConnectionID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided remote connection ID has invalid format",
+	}
+)
+
+var remoteConnectionsBucket = []byte("remoteconnectionsv1")
+
+var _ influxdb.RemoteConnectionService = (*Service)(nil)
+
+func (s *Service) initializeRemoteConnections(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(remoteConnectionsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindRemoteConnectionByID returns a single remote connection by ID.
+func (s *Service) FindRemoteConnectionByID(ctx context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+	var rc *influxdb.RemoteConnection
+	err := s.kv.View(ctx, func(tx Tx) error {
+		c, err := s.findRemoteConnectionByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		rc = c
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRemoteConnectionByID,
+			Err: err,
+		}
+	}
+	return rc, nil
+}
+
+// FindRemoteConnections returns the remote connections that match the filter.
+func (s *Service) FindRemoteConnections(ctx context.Context, filter influxdb.RemoteConnectionFilter, opt ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+	var rcs []*influxdb.RemoteConnection
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			rc, err := s.findRemoteConnectionByID(ctx, tx, *filter.ID)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					return nil
+				}
+				return err
+			}
+			if filterRemoteConnection(rc, filter) {
+				rcs = append(rcs, rc)
+			}
+			return nil
+		}
+
+		return s.forEachRemoteConnection(ctx, tx, func(rc *influxdb.RemoteConnection) bool {
+			if filterRemoteConnection(rc, filter) {
+				rcs = append(rcs, rc)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindRemoteConnections,
+			Err: err,
+		}
+	}
+	return rcs, len(rcs), nil
+}
+
+func filterRemoteConnection(rc *influxdb.RemoteConnection, filter influxdb.RemoteConnectionFilter) bool {
+	return (filter.ID == nil || rc.ID == *filter.ID) &&
+		(filter.OrganizationID == nil || rc.OrganizationID == *filter.OrganizationID) &&
+		(filter.Name == nil || rc.Name == *filter.Name)
+}
+
+// CreateRemoteConnection creates a remote connection and sets rc.ID with the new identifier.
+func (s *Service) CreateRemoteConnection(ctx context.Context, rc *influxdb.RemoteConnection) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := rc.Valid(); err != nil {
+			return err
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, rc.OrganizationID); err != nil {
+			return err
+		}
+
+		rc.ID = s.IDGenerator.ID()
+		if err := s.uniqueRemoteConnectionName(ctx, tx, rc); err != nil {
+			return err
+		}
+
+		now := s.Now()
+		rc.SetCreatedAt(now)
+		rc.SetUpdatedAt(now)
+		return s.putRemoteConnection(ctx, tx, rc)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateRemoteConnection,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateRemoteConnection updates a remote connection with the changeset.
+func (s *Service) UpdateRemoteConnection(ctx context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error) {
+	var rc *influxdb.RemoteConnection
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		c, err := s.findRemoteConnectionByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(c)
+		if err := c.Valid(); err != nil {
+			return err
+		}
+		if upd.Name != nil {
+			if err := s.uniqueRemoteConnectionName(ctx, tx, c); err != nil {
+				return err
+			}
+		}
+		c.SetUpdatedAt(s.Now())
+
+		if err := s.putRemoteConnection(ctx, tx, c); err != nil {
+			return err
+		}
+		rc = c
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateRemoteConnection,
+			Err: err,
+		}
+	}
+	return rc, nil
+}
+
+// DeleteRemoteConnection removes a remote connection by ID.
+func (s *Service) DeleteRemoteConnection(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findRemoteConnectionByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := id.Encode()
+		if err != nil {
+			return ErrInvalidRemoteConnectionID
+		}
+
+		b, err := tx.Bucket(remoteConnectionsBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteRemoteConnection,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueRemoteConnectionName returns an error if another remote connection
+// of the organization has the name of rc, since queries refer to remote
+// connections by name.
+func (s *Service) uniqueRemoteConnectionName(ctx context.Context, tx Tx, rc *influxdb.RemoteConnection) error {
+	var taken bool
+	err := s.forEachRemoteConnection(ctx, tx, func(other *influxdb.RemoteConnection) bool {
+		taken = other.ID != rc.ID && other.OrganizationID == rc.OrganizationID && other.Name == rc.Name
+		return !taken
+	})
+	if err != nil {
+		return err
+	}
+	if taken {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("remote connection with name %s already exists", rc.Name),
+		}
+	}
+	return nil
+}
+
+func (s *Service) findRemoteConnectionByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidRemoteConnectionID
+	}
+
+	b, err := tx.Bucket(remoteConnectionsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrRemoteConnectionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalRemoteConnection(v)
+}
+
+// forEachRemoteConnection calls fn with each remote connection until fn returns false.
+func (s *Service) forEachRemoteConnection(ctx context.Context, tx Tx, fn func(*influxdb.RemoteConnection) bool) error {
+	b, err := tx.Bucket(remoteConnectionsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		rc, err := unmarshalRemoteConnection(v)
+		if err != nil {
+			return err
+		}
+		if !fn(rc) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Service) putRemoteConnection(ctx context.Context, tx Tx, rc *influxdb.RemoteConnection) error {
+	k, err := rc.ID.Encode()
+	if err != nil {
+		return ErrInvalidRemoteConnectionID
+	}
+
+	v, err := json.Marshal(rc)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(remoteConnectionsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func unmarshalRemoteConnection(v []byte) (*influxdb.RemoteConnection, error) {
+	rc := &influxdb.RemoteConnection{}
+	if err := json.Unmarshal(v, rc); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal remote connection",
+			Err:  err,
+		}
+	}
+	return rc, nil
+}
@@ -0,0 +1,102 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestRemoteConnections(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			other := &influxdb.Organization{Name: "other"}
+			if err := svc.CreateOrganization(ctx, other); err != nil {
+				t.Fatal(err)
+			}
+
+			newConnection := func(orgID influxdb.ID, name string) *influxdb.RemoteConnection {
+				return &influxdb.RemoteConnection{
+					OrganizationID: orgID,
+					Name:           name,
+					URL:            "https://edge-1:9999",
+					RemoteOrgID:    influxdb.ID(10),
+					TokenSecretKey: "edge-token",
+				}
+			}
+
+			rc := newConnection(org.ID, "edge-1")
+			if err := svc.CreateRemoteConnection(ctx, rc); err != nil {
+				t.Fatal(err)
+			}
+			if !rc.ID.Valid() || rc.CreatedAt.IsZero() {
+				t.Fatalf("expected an ID and a creation time, got %+v", rc)
+			}
+
+			invalid := newConnection(org.ID, "invalid")
+			invalid.URL = "edge-1:9999"
+			if err := svc.CreateRemoteConnection(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid url to be rejected, got %v", err)
+			}
+			invalid = newConnection(org.ID, "invalid")
+			invalid.TLS.CACert = "not a certificate"
+			if err := svc.CreateRemoteConnection(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid ca certificate to be rejected, got %v", err)
+			}
+			if err := svc.CreateRemoteConnection(ctx, newConnection(org.ID, "edge-1")); influxdb.ErrorCode(err) != influxdb.EConflict {
+				t.Fatalf("expected duplicate name to conflict, got %v", err)
+			}
+			if err := svc.CreateRemoteConnection(ctx, newConnection(other.ID, "edge-1")); err != nil {
+				t.Fatalf("expected the name to be available in another organization: %v", err)
+			}
+
+			name := "edge-1"
+			rcs, n, err := svc.FindRemoteConnections(ctx, influxdb.RemoteConnectionFilter{OrganizationID: &org.ID, Name: &name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 || rcs[0].ID != rc.ID || rcs[0].TokenSecretKey != "edge-token" {
+				t.Fatalf("unexpected remote connections %+v", rcs)
+			}
+
+			url := "https://edge-2:9999"
+			updated, err := svc.UpdateRemoteConnection(ctx, rc.ID, influxdb.RemoteConnectionUpdate{URL: &url})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.URL != url || updated.Name != "edge-1" {
+				t.Fatalf("unexpected updated remote connection %+v", updated)
+			}
+
+			if err := svc.DeleteRemoteConnection(ctx, rc.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindRemoteConnectionByID(ctx, rc.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected deleted remote connection to be not found, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,520 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+)
+
+var (
+	// ErrReportNotFound is used when the report is not found.
+	ErrReportNotFound = &influxdb.Error{
+		Msg:  "report not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidReportID is used when the service was provided an invalid
+	// ID format.
+	ErrInvalidReportID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided report ID has invalid format",
+	}
+
+	// ErrReportEndpointType is used when the notification endpoint of a
+	// report cannot deliver reports.
+	ErrReportEndpointType = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "reports can only be delivered through slack and http notification endpoints",
+	}
+)
+
+var (
+	reportsBucket    = []byte("reportsv1")
+	reportRunsBucket = []byte("reportrunsv1")
+)
+
+var (
+	_ influxdb.ReportService              = (*Service)(nil)
+	_ influxdb.ReportAuthorizationService = (*Service)(nil)
+)
+
+func (s *Service) initializeReports(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(reportsBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(reportRunsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindReportByID returns a single report by ID.
+func (s *Service) FindReportByID(ctx context.Context, id influxdb.ID) (*influxdb.Report, error) {
+	var r *influxdb.Report
+	err := s.kv.View(ctx, func(tx Tx) error {
+		v, err := s.findReportByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = v
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindReportByID,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// FindReports returns the reports that match the filter.
+func (s *Service) FindReports(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+	var rs []*influxdb.Report
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			r, err := s.findReportByID(ctx, tx, *filter.ID)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					return nil
+				}
+				return err
+			}
+			if filterReport(r, filter) {
+				rs = append(rs, r)
+			}
+			return nil
+		}
+
+		return s.forEachReport(ctx, tx, func(r *influxdb.Report) bool {
+			if filterReport(r, filter) {
+				rs = append(rs, r)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindReports,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+func filterReport(r *influxdb.Report, filter influxdb.ReportFilter) bool {
+	return (filter.ID == nil || r.ID == *filter.ID) &&
+		(filter.OrganizationID == nil || r.OrganizationID == *filter.OrganizationID) &&
+		(filter.DashboardID == nil || r.DashboardID == *filter.DashboardID) &&
+		(filter.Name == nil || r.Name == *filter.Name)
+}
+
+// CreateReport creates a report and sets r.ID with the new identifier.
+func (s *Service) CreateReport(ctx context.Context, r *influxdb.Report) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if r.Status == "" {
+			r.Status = influxdb.Active
+		}
+		if err := r.Valid(); err != nil {
+			return err
+		}
+		if err := s.validReportTargets(ctx, tx, r); err != nil {
+			return err
+		}
+
+		r.ID = s.IDGenerator.ID()
+		r.LatestScheduled = time.Time{}
+		now := s.Now()
+		r.SetCreatedAt(now)
+		r.SetUpdatedAt(now)
+		return s.putReport(ctx, tx, r)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateReport,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// validReportTargets returns an error if the dashboard or the notification
+// endpoint of the report do not belong to its organization.
+func (s *Service) validReportTargets(ctx context.Context, tx Tx, r *influxdb.Report) error {
+	if _, err := s.findOrganizationByID(ctx, tx, r.OrganizationID); err != nil {
+		return err
+	}
+
+	d, err := s.findDashboardByID(ctx, tx, r.DashboardID)
+	if err != nil {
+		return err
+	}
+	if d.OrganizationID != r.OrganizationID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "report dashboard must belong to its organization",
+		}
+	}
+
+	if !r.EndpointID.Valid() {
+		return nil
+	}
+	edp, _, _, err := s.findNotificationEndpointByID(ctx, tx, r.EndpointID)
+	if err != nil {
+		return err
+	}
+	if edp.GetOrgID() != r.OrganizationID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "report notification endpoint must belong to its organization",
+		}
+	}
+	switch edp.(type) {
+	case *endpoint.Slack, *endpoint.HTTP:
+		return nil
+	default:
+		return ErrReportEndpointType
+	}
+}
+
+// UpdateReport updates a report with the changeset.
+func (s *Service) UpdateReport(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error) {
+	var r *influxdb.Report
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		v, err := s.findReportByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(v)
+		if err := v.Valid(); err != nil {
+			return err
+		}
+		// The targets are only checked when they change, so that the schedule
+		// of a report is still updated after its dashboard was deleted.
+		if upd.DashboardID != nil || upd.EndpointID != nil {
+			if err := s.validReportTargets(ctx, tx, v); err != nil {
+				return err
+			}
+		}
+		v.SetUpdatedAt(s.Now())
+
+		if err := s.putReport(ctx, tx, v); err != nil {
+			return err
+		}
+		r = v
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateReport,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// DeleteReport removes a report and its runs by ID.
+func (s *Service) DeleteReport(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findReportByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		k, err := id.Encode()
+		if err != nil {
+			return ErrInvalidReportID
+		}
+
+		var keys [][]byte
+		if err := s.forEachReportRun(ctx, tx, id, func(k []byte, run *influxdb.ReportRun) bool {
+			keys = append(keys, k)
+			return true
+		}); err != nil {
+			return err
+		}
+
+		rb, err := tx.Bucket(reportRunsBucket)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := rb.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		b, err := tx.Bucket(reportsBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteReport,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindReportRuns returns the kept runs of a report, the most recent first.
+func (s *Service) FindReportRuns(ctx context.Context, id influxdb.ID) ([]*influxdb.ReportRun, error) {
+	var runs []*influxdb.ReportRun
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findReportByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		return s.forEachReportRun(ctx, tx, id, func(k []byte, run *influxdb.ReportRun) bool {
+			runs = append(runs, run)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindReportRuns,
+			Err: err,
+		}
+	}
+
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	return runs, nil
+}
+
+// AddReportRun records a run of a report and sets run.ID with the new
+// identifier. The oldest runs beyond influxdb.MaxReportRuns are removed.
+func (s *Service) AddReportRun(ctx context.Context, run *influxdb.ReportRun) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findReportByID(ctx, tx, run.ReportID); err != nil {
+			return err
+		}
+
+		run.ID = s.IDGenerator.ID()
+		k, err := reportRunKey(run)
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(run)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(reportRunsBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(k, v); err != nil {
+			return err
+		}
+
+		var keys [][]byte
+		if err := s.forEachReportRun(ctx, tx, run.ReportID, func(k []byte, run *influxdb.ReportRun) bool {
+			keys = append(keys, k)
+			return true
+		}); err != nil {
+			return err
+		}
+		for len(keys) > influxdb.MaxReportRuns {
+			if err := b.Delete(keys[0]); err != nil {
+				return err
+			}
+			keys = keys[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAddReportRun,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindReportAuthorization returns the authorization the report is rendered
+// with. It has the permissions of the owner of the report at the time of the
+// rendering, so a report cannot show more than its owner can read. Reports
+// without an owner, or whose owner was deleted or deactivated, are rendered
+// without permissions.
+func (s *Service) FindReportAuthorization(ctx context.Context, r *influxdb.Report) (*influxdb.Authorization, error) {
+	a := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     r.ID,
+		OrgID:  r.OrganizationID,
+		UserID: r.OwnerID,
+	}
+	if !r.OwnerID.Valid() {
+		return a, nil
+	}
+
+	err := s.kv.View(ctx, func(tx Tx) error {
+		u, err := s.findUserByID(ctx, tx, r.OwnerID)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return nil
+			}
+			return err
+		}
+		if u.Status == influxdb.Inactive {
+			return nil
+		}
+
+		ps, err := s.maxPermissions(ctx, tx, r.OwnerID)
+		if err != nil {
+			return err
+		}
+		a.Permissions = ps
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Service) findReportByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Report, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidReportID
+	}
+
+	b, err := tx.Bucket(reportsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalReport(v)
+}
+
+// forEachReport calls fn with each report until fn returns false.
+func (s *Service) forEachReport(ctx context.Context, tx Tx, fn func(*influxdb.Report) bool) error {
+	b, err := tx.Bucket(reportsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		r, err := unmarshalReport(v)
+		if err != nil {
+			return err
+		}
+		if !fn(r) {
+			break
+		}
+	}
+	return nil
+}
+
+// forEachReportRun calls fn with the key and each run of a report, the
+// oldest first, until fn returns false.
+func (s *Service) forEachReportRun(ctx context.Context, tx Tx, id influxdb.ID, fn func([]byte, *influxdb.ReportRun) bool) error {
+	prefix, err := id.Encode()
+	if err != nil {
+		return ErrInvalidReportID
+	}
+
+	b, err := tx.Bucket(reportRunsBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		run := &influxdb.ReportRun{}
+		if err := json.Unmarshal(v, run); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "unable to unmarshal report run",
+				Err:  err,
+			}
+		}
+		if !fn(k, run) {
+			break
+		}
+	}
+	return nil
+}
+
+// reportRunKey returns the key of a run of a report. The keys of the runs of
+// a report share the encoded report ID as prefix and sort by the time the
+// runs started at.
+func reportRunKey(run *influxdb.ReportRun) ([]byte, error) {
+	encodedReportID, err := run.ReportID.Encode()
+	if err != nil {
+		return nil, ErrInvalidReportID
+	}
+	encodedID, err := run.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	k := make([]byte, 0, len(encodedReportID)+8+len(encodedID))
+	k = append(k, encodedReportID...)
+	var started [8]byte
+	binary.BigEndian.PutUint64(started[:], uint64(run.StartedAt.UnixNano()))
+	k = append(k, started[:]...)
+	return append(k, encodedID...), nil
+}
+
+func (s *Service) putReport(ctx context.Context, tx Tx, r *influxdb.Report) error {
+	k, err := r.ID.Encode()
+	if err != nil {
+		return ErrInvalidReportID
+	}
+
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(reportsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func unmarshalReport(v []byte) (*influxdb.Report, error) {
+	r := &influxdb.Report{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to unmarshal report",
+			Err:  err,
+		}
+	}
+	return r, nil
+}
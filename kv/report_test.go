@@ -0,0 +1,217 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/notification/endpoint"
+)
+
+func TestReports(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			org := &influxdb.Organization{Name: "org"}
+			other := &influxdb.Organization{Name: "other"}
+			for _, o := range []*influxdb.Organization{org, other} {
+				if err := svc.CreateOrganization(ctx, o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			d := &influxdb.Dashboard{OrganizationID: org.ID, Name: "d"}
+			od := &influxdb.Dashboard{OrganizationID: other.ID, Name: "d"}
+			for _, d := range []*influxdb.Dashboard{d, od} {
+				if err := svc.CreateDashboard(ctx, d); err != nil {
+					t.Fatal(err)
+				}
+			}
+			webhook := &endpoint.HTTP{
+				Base:       endpoint.Base{OrgID: org.ID, Name: "webhook", Status: influxdb.Active},
+				URL:        "http://example.com",
+				Method:     "POST",
+				AuthMethod: "none",
+			}
+			routingKey := "key"
+			pagerDuty := &endpoint.PagerDuty{
+				Base:       endpoint.Base{OrgID: org.ID, Name: "pagerduty", Status: influxdb.Active},
+				ClientURL:  "http://example.com",
+				RoutingKey: influxdb.SecretField{Value: &routingKey},
+			}
+			for _, edp := range []influxdb.NotificationEndpoint{webhook, pagerDuty} {
+				if err := svc.CreateNotificationEndpoint(ctx, edp, 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := &influxdb.Report{
+				OrganizationID: org.ID,
+				Name:           "daily",
+				DashboardID:    d.ID,
+				Every:          influxdb.Duration{Duration: 24 * time.Hour},
+				Offset:         influxdb.Duration{Duration: 9 * time.Hour},
+				EndpointID:     webhook.ID,
+				Emails:         []string{"ops@example.com"},
+			}
+			if err := svc.CreateReport(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+			if !r.ID.Valid() || r.Status != influxdb.Active || r.CreatedAt.IsZero() {
+				t.Fatalf("expected an active report with an ID and a creation time, got %+v", r)
+			}
+
+			for name, invalid := range map[string]*influxdb.Report{
+				"dashboard of another organization": {OrganizationID: org.ID, Name: "r", DashboardID: od.ID, Every: r.Every, EndpointID: webhook.ID},
+				"pagerduty endpoint":                {OrganizationID: org.ID, Name: "r", DashboardID: d.ID, Every: r.Every, EndpointID: pagerDuty.ID},
+				"short interval":                    {OrganizationID: org.ID, Name: "r", DashboardID: d.ID, Every: influxdb.Duration{Duration: time.Second}, EndpointID: webhook.ID},
+			} {
+				if err := svc.CreateReport(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+					t.Errorf("expected the report with a %s to be invalid, got %v", name, err)
+				}
+			}
+
+			scheduled := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+			name := "weekly"
+			updated, err := svc.UpdateReport(ctx, r.ID, influxdb.ReportUpdate{Name: &name, LatestScheduled: &scheduled})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Name != name || !updated.LatestScheduled.Equal(scheduled) {
+				t.Fatalf("expected the report to be updated, got %+v", updated)
+			}
+			if _, err := svc.UpdateReport(ctx, r.ID, influxdb.ReportUpdate{DashboardID: &od.ID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected the dashboard of another organization to be rejected, got %v", err)
+			}
+
+			rs, n, err := svc.FindReports(ctx, influxdb.ReportFilter{DashboardID: &d.ID})
+			if err != nil || n != 1 || rs[0].ID != r.ID {
+				t.Fatalf("expected the report of the dashboard, got %v, %v", rs, err)
+			}
+			if rs, _, err := svc.FindReports(ctx, influxdb.ReportFilter{OrganizationID: &other.ID}); err != nil || len(rs) != 0 {
+				t.Fatalf("expected no reports in the other organization, got %v, %v", rs, err)
+			}
+
+			started := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < influxdb.MaxReportRuns+5; i++ {
+				run := &influxdb.ReportRun{
+					ReportID:  r.ID,
+					StartedAt: started.Add(time.Duration(i) * time.Minute),
+					Status:    influxdb.ReportRunSuccess,
+				}
+				if err := svc.AddReportRun(ctx, run); err != nil {
+					t.Fatal(err)
+				}
+			}
+			runs, err := svc.FindReportRuns(ctx, r.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(runs) != influxdb.MaxReportRuns {
+				t.Fatalf("expected %d runs to be kept, got %d", influxdb.MaxReportRuns, len(runs))
+			}
+			if want := started.Add(time.Duration(influxdb.MaxReportRuns+4) * time.Minute); !runs[0].StartedAt.Equal(want) {
+				t.Fatalf("expected the most recent run first, got %s", runs[0].StartedAt)
+			}
+			if want := started.Add(5 * time.Minute); !runs[len(runs)-1].StartedAt.Equal(want) {
+				t.Fatalf("expected the oldest runs to be removed, got %s", runs[len(runs)-1].StartedAt)
+			}
+
+			orgID, err := svc.FindResourceOrganizationID(ctx, influxdb.ReportsResourceType, r.ID)
+			if err != nil || orgID != org.ID {
+				t.Fatalf("expected the organization of the report, got %s, %v", orgID, err)
+			}
+
+			if err := svc.DeleteReport(ctx, r.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FindReportByID(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected the report to be deleted, got %v", err)
+			}
+			if err := svc.AddReportRun(ctx, &influxdb.ReportRun{ReportID: r.ID}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Fatalf("expected runs of a deleted report to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestService_FindReportAuthorization(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("unable to initialize kv store: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	other := &influxdb.Organization{Name: "other"}
+	for _, o := range []*influxdb.Organization{org, other} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u := &influxdb.User{Name: "owner", Status: influxdb.Active}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       u.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &influxdb.Report{ID: 10, OrganizationID: org.ID, OwnerID: u.ID}
+	readBuckets := func(orgID influxdb.ID) influxdb.Permission {
+		return influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID}}
+	}
+
+	a, err := svc.FindReportAuthorization(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.GetUserID() != u.ID || !a.Allowed(readBuckets(org.ID)) {
+		t.Fatalf("expected the report to be rendered with the permissions of its owner, got %v", a)
+	}
+	if a.Allowed(readBuckets(other.ID)) {
+		t.Fatalf("expected the report not to read the buckets of an organization its owner is not a member of, got %v", a)
+	}
+
+	inactive := influxdb.Inactive
+	if _, err := svc.UpdateUser(ctx, u.ID, influxdb.UserUpdate{Status: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	if a, err := svc.FindReportAuthorization(ctx, r); err != nil || len(a.Permissions) != 0 {
+		t.Fatalf("expected the report of a deactivated owner to be rendered without permissions, got %v, %v", a, err)
+	}
+
+	r.OwnerID = 0
+	if a, err := svc.FindReportAuthorization(ctx, r); err != nil || len(a.Permissions) != 0 {
+		t.Fatalf("expected a report without an owner to be rendered without permissions, got %v, %v", a, err)
+	}
+}
@@ -0,0 +1,177 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var resourceACLBucket = []byte("resourceaclsv1")
+
+var _ influxdb.ResourceACLService = (*Service)(nil)
+
+func (s *Service) initializeResourceACLs(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(resourceACLBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindResourceACL returns the acl of a resource. The acl of a trashed
+// resource is the one kept with it in the trash, so that it stays
+// restricted until it is restored.
+func (s *Service) FindResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+	var acl *influxdb.ResourceACL
+	err := s.kv.View(ctx, func(tx Tx) error {
+		a, err := s.findResourceACL(ctx, tx, rt, id)
+		if err != nil {
+			return err
+		}
+		acl = a
+		if a.Restricted() {
+			return nil
+		}
+
+		rec, err := s.findTrashRecord(ctx, tx, id)
+		if err != nil {
+			if err == influxdb.ErrTrashedResourceNotFound {
+				return nil
+			}
+			return err
+		}
+		if rec.Type == rt && rec.ACL != nil {
+			acl = rec.ACL
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindResourceACL,
+			Err: err,
+		}
+	}
+	return acl, nil
+}
+
+// PutResourceACL replaces the grants of the acl of a resource.
+func (s *Service) PutResourceACL(ctx context.Context, acl *influxdb.ResourceACL) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := acl.Valid(); err != nil {
+			return err
+		}
+		if !acl.Restricted() {
+			return s.deleteResourceACL(ctx, tx, acl.ResourceType, acl.ResourceID)
+		}
+		acl.UpdatedAt = s.Now()
+		return s.putResourceACL(ctx, tx, acl)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutResourceACL,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// DeleteResourceACL removes the acl of a resource.
+func (s *Service) DeleteResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteResourceACL(ctx, tx, rt, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteResourceACL,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func resourceACLKey(rt influxdb.ResourceType, id influxdb.ID) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	k := make([]byte, 0, len(rt)+1+len(encodedID))
+	k = append(k, rt...)
+	k = append(k, '/')
+	k = append(k, encodedID...)
+	return k, nil
+}
+
+// findResourceACL returns the stored acl of the resource or an acl without
+// grants if the resource is not restricted.
+func (s *Service) findResourceACL(ctx context.Context, tx Tx, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+	k, err := resourceACLKey(rt, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(resourceACLBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return &influxdb.ResourceACL{
+			ResourceType: rt,
+			ResourceID:   id,
+			Grants:       []influxdb.ResourceGrant{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	acl := &influxdb.ResourceACL{}
+	if err := json.Unmarshal(v, acl); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return acl, nil
+}
+
+func (s *Service) putResourceACL(ctx context.Context, tx Tx, acl *influxdb.ResourceACL) error {
+	k, err := resourceACLKey(acl.ResourceType, acl.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(acl)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(resourceACLBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func (s *Service) deleteResourceACL(ctx context.Context, tx Tx, rt influxdb.ResourceType, id influxdb.ID) error {
+	k, err := resourceACLKey(rt, id)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(resourceACLBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
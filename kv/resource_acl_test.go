@@ -0,0 +1,69 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_ResourceACL(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing resource acl service: %v", err)
+	}
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "executive"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	acl, err := svc.FindResourceACL(ctx, influxdb.DashboardsResourceType, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acl.Restricted() {
+		t.Fatalf("expected dashboard not to be restricted, got %+v", acl)
+	}
+
+	userID := influxdb.ID(1)
+	acl.Grants = []influxdb.ResourceGrant{{UserID: &userID, Access: influxdb.ResourceAccessView}}
+	if err := svc.PutResourceACL(ctx, acl); err != nil {
+		t.Fatal(err)
+	}
+	acl, err = svc.FindResourceACL(ctx, influxdb.DashboardsResourceType, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acl.Allowed(userID, influxdb.ReadAction) || acl.Allowed(userID+1, influxdb.ReadAction) || acl.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected acl %+v", acl)
+	}
+
+	invalid := &influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: d.ID, Grants: []influxdb.ResourceGrant{{Access: "owner"}}}
+	if err := svc.PutResourceACL(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid acl to be rejected, got %v", err)
+	}
+
+	if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	acl, err = svc.FindResourceACL(ctx, influxdb.DashboardsResourceType, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acl.Restricted() {
+		t.Fatalf("expected acl of deleted dashboard to be removed, got %+v", acl)
+	}
+}
@@ -0,0 +1,69 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	runtimeConfigBucket = []byte("runtimeconfigv1")
+	runtimeConfigKey    = []byte("overrides")
+)
+
+var _ influxdb.RuntimeConfigOverrideStore = (*Service)(nil)
+
+func (s *Service) initializeRuntimeConfig(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(runtimeConfigBucket)
+	return err
+}
+
+// FindRuntimeConfigOverrides returns the stored runtime config overrides.
+func (s *Service) FindRuntimeConfigOverrides(ctx context.Context) (influxdb.RuntimeConfigUpdate, error) {
+	var upd influxdb.RuntimeConfigUpdate
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(runtimeConfigBucket)
+		if err != nil {
+			return err
+		}
+		v, err := b.Get(runtimeConfigKey)
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(v, &upd)
+	})
+	if err != nil {
+		return influxdb.RuntimeConfigUpdate{}, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return upd, nil
+}
+
+// PutRuntimeConfigOverrides replaces the stored runtime config overrides.
+func (s *Service) PutRuntimeConfigOverrides(ctx context.Context, upd influxdb.RuntimeConfigUpdate) error {
+	v, err := json.Marshal(upd)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(runtimeConfigBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(runtimeConfigKey, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
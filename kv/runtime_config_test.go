@@ -0,0 +1,56 @@
+package kv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestRuntimeConfigOverrides(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			upd, err := svc.FindRuntimeConfigOverrides(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error finding overrides: %v", err)
+			}
+			if !reflect.DeepEqual(upd, influxdb.RuntimeConfigUpdate{}) {
+				t.Fatalf("expected no overrides, got %+v", upd)
+			}
+
+			level, concurrency := "debug", 4
+			want := influxdb.RuntimeConfigUpdate{LogLevel: &level, QueryConcurrency: &concurrency}
+			if err := svc.PutRuntimeConfigOverrides(ctx, want); err != nil {
+				t.Fatalf("unexpected error putting overrides: %v", err)
+			}
+
+			got, err := svc.FindRuntimeConfigOverrides(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error finding overrides: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("overrides = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
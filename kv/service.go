@@ -62,6 +62,10 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 // ServiceConfig allows us to configure Services
 type ServiceConfig struct {
 	SessionLength time.Duration
+
+	// TrashRetention is how long deleted dashboards and tasks are kept in
+	// the trash of their organization. Zero deletes them immediately.
+	TrashRetention time.Duration
 }
 
 // Initialize creates Buckets needed.
@@ -79,10 +83,54 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeBucketStatistics(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeMaterializedViews(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeAlertAcknowledgements(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeRemoteConnections(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeNotebooks(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeQueryTemplates(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeReports(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeMaintenance(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeIngestRules(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeMeasurementLastWrites(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeDashboards(ctx, tx); err != nil {
 			return err
 		}
 
+		if err := s.initializeDBRPMappings(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeKVLog(ctx, tx); err != nil {
 			return err
 		}
@@ -103,6 +151,26 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeTrash(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeOrgSettings(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeUserSettings(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeSessionAlerts(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeResourceACLs(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializePasswords(ctx, tx); err != nil {
 			return err
 		}
@@ -147,6 +215,18 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeRuntimeConfig(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeUsageWindows(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeWriteLinks(ctx, tx); err != nil {
+			return err
+		}
+
 		return s.initializeUsers(ctx, tx)
 	})
 }
@@ -0,0 +1,166 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var sessionAlertsBucket = []byte("sessionalertsv1")
+
+var _ influxdb.SessionAlertService = (*Service)(nil)
+
+func (s *Service) initializeSessionAlerts(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(sessionAlertsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateSessionAlert records the alert for its user, dropping the oldest
+// alerts of the user beyond influxdb.MaxSessionAlerts.
+func (s *Service) CreateSessionAlert(ctx context.Context, a *influxdb.SessionAlert) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, a.UserID); err != nil {
+			return err
+		}
+
+		alerts, err := s.findSessionAlerts(ctx, tx, a.UserID)
+		if err != nil {
+			return err
+		}
+
+		if a.CreatedAt.IsZero() {
+			a.CreatedAt = s.Now()
+		}
+		alerts = append([]*influxdb.SessionAlert{a}, alerts...)
+		if len(alerts) > influxdb.MaxSessionAlerts {
+			alerts = alerts[:influxdb.MaxSessionAlerts]
+		}
+		return s.putSessionAlerts(ctx, tx, a.UserID, alerts)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateSessionAlert,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindSessionAlerts returns the alerts of a user, the most recent first.
+func (s *Service) FindSessionAlerts(ctx context.Context, userID influxdb.ID) ([]*influxdb.SessionAlert, error) {
+	var alerts []*influxdb.SessionAlert
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		as, err := s.findSessionAlerts(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		alerts = as
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSessionAlerts,
+			Err: err,
+		}
+	}
+	return alerts, nil
+}
+
+// DeleteSessionAlerts removes the alerts of a user.
+func (s *Service) DeleteSessionAlerts(ctx context.Context, userID influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+		return s.deleteSessionAlerts(ctx, tx, userID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteSessionAlerts,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// findSessionAlerts returns the stored alerts of the user, or none.
+func (s *Service) findSessionAlerts(ctx context.Context, tx Tx, userID influxdb.ID) ([]*influxdb.SessionAlert, error) {
+	k, err := userID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(sessionAlertsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return []*influxdb.SessionAlert{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []*influxdb.SessionAlert{}
+	if err := json.Unmarshal(v, &alerts); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return alerts, nil
+}
+
+func (s *Service) putSessionAlerts(ctx context.Context, tx Tx, userID influxdb.ID, alerts []*influxdb.SessionAlert) error {
+	k, err := userID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(alerts)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(sessionAlertsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func (s *Service) deleteSessionAlerts(ctx context.Context, tx Tx, userID influxdb.ID) error {
+	k, err := userID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(sessionAlertsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
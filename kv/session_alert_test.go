@@ -0,0 +1,71 @@
+package kv_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_SessionAlerts(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing session alert service: %v", err)
+	}
+
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err := svc.FindSessionAlerts(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts, got %d", len(alerts))
+	}
+
+	for i := 0; i < influxdb.MaxSessionAlerts+2; i++ {
+		if err := svc.CreateSessionAlert(ctx, &influxdb.SessionAlert{
+			UserID:    u.ID,
+			SessionID: 1,
+			RemoteIP:  fmt.Sprintf("10.0.0.%d", i),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alerts, err = svc.FindSessionAlerts(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != influxdb.MaxSessionAlerts {
+		t.Fatalf("expected %d alerts, got %d", influxdb.MaxSessionAlerts, len(alerts))
+	}
+	if got, want := alerts[0].RemoteIP, fmt.Sprintf("10.0.0.%d", influxdb.MaxSessionAlerts+1); got != want {
+		t.Errorf("expected the most recent alert first, got %s want %s", got, want)
+	}
+	if alerts[0].CreatedAt.IsZero() {
+		t.Error("expected the time of the alert to be set")
+	}
+
+	if err := svc.DeleteSessionAlerts(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if alerts, err = svc.FindSessionAlerts(ctx, u.ID); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts after deleting them, got %d: %v", len(alerts), err)
+	}
+
+	if err := svc.CreateSessionAlert(ctx, &influxdb.SessionAlert{UserID: u.ID + 1}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected an alert of a missing user to be not found, got %v", err)
+	}
+}
@@ -41,6 +41,7 @@ type kvTask struct {
 	OrganizationID  influxdb.ID            `json:"orgID"`
 	Organization    string                 `json:"org"`
 	OwnerID         influxdb.ID            `json:"ownerID"`
+	AuthorizationID influxdb.ID            `json:"scopedAuthorizationID,omitempty"`
 	Name            string                 `json:"name"`
 	Description     string                 `json:"description,omitempty"`
 	Status          string                 `json:"status"`
@@ -64,6 +65,7 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		OrganizationID:  k.OrganizationID,
 		Organization:    k.Organization,
 		OwnerID:         k.OwnerID,
+		AuthorizationID: k.AuthorizationID,
 		Name:            k.Name,
 		Description:     k.Description,
 		Status:          k.Status,
@@ -129,19 +131,9 @@ func (s *Service) findTaskByIDWithAuth(ctx context.Context, tx Tx, id influxdb.I
 		return nil, err
 	}
 
-	t.Authorization = &influxdb.Authorization{
-		Status: influxdb.Active,
-		ID:     influxdb.ID(1),
-		OrgID:  t.OrganizationID,
-	}
-
-	if t.OwnerID.Valid() {
-		// populate task Auth
-		ps, err := s.maxPermissions(ctx, tx, t.OwnerID)
-		if err != nil {
-			return nil, err
-		}
-		t.Authorization.Permissions = ps
+	t.Authorization, err = s.taskAuthorization(ctx, tx, t)
+	if err != nil {
+		return nil, err
 	}
 
 	return t, nil
@@ -596,6 +588,10 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		return nil, influxdb.ErrOrgNotFound
 	}
 
+	if err := checkTaskScriptAuthorizer(ctx); err != nil {
+		return nil, err
+	}
+
 	// TODO: Uncomment this once the checks/notifications no longer create tasks in kv
 	// confirm the owner is a real user.
 	// if _, err = s.findUserByID(ctx, tx, tc.OwnerID); err != nil {
@@ -638,6 +634,10 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	}
 
+	if task.AuthorizationID, err = s.createTaskAuthorization(ctx, tx, task); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -681,13 +681,7 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	// populate permissions so the task can be used immediately
 	// if we cant populate here we shouldn't error.
-	ps, _ := s.maxPermissions(ctx, tx, task.OwnerID)
-	task.Authorization = &influxdb.Authorization{
-		Status:      influxdb.Active,
-		ID:          influxdb.ID(1),
-		OrgID:       task.OrganizationID,
-		Permissions: ps,
-	}
+	task.Authorization, _ = s.taskAuthorization(ctx, tx, task)
 	return task, nil
 }
 
@@ -734,6 +728,9 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 
 	// update the flux script
 	if !upd.Options.IsZero() || upd.Flux != nil {
+		if err := checkTaskScriptAuthorizer(ctx); err != nil {
+			return nil, err
+		}
 		if err = upd.UpdateFlux(task.Flux); err != nil {
 			return nil, err
 		}
@@ -756,6 +753,10 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		}
 		task.Offset = off
 		task.UpdatedAt = updatedAt
+
+		if err := s.rotateTaskAuthorization(ctx, tx, task); err != nil {
+			return nil, err
+		}
 	}
 
 	if upd.Description != nil {
@@ -820,8 +821,22 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 }
 
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
+// When the trash is enabled the task can be restored until its retention expires.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if s.trashEnabled() {
+			task, err := s.findTaskByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			// tasks of checks and notification rules are deleted together with them.
+			if task.Type == "" || task.Type == influxdb.TaskSystemType {
+				if err := s.trashTask(ctx, tx, task); err != nil {
+					return err
+				}
+			}
+		}
+
 		err := s.deleteTask(ctx, tx, id)
 		if err != nil {
 			return err
@@ -903,6 +918,10 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	if err := s.deleteTaskAuthorization(ctx, tx, task); err != nil {
+		return err
+	}
+
 	if err := s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID: task.ID,
 	}); err != nil {
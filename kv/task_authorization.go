@@ -0,0 +1,246 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// Tasks, including the tasks of checks and notification rules, run with an
+// authorization of their owner minted for them and scoped to the buckets
+// their script reads and writes, as derived by static analysis of the
+// script. The authorization is rotated when the script changes and deleted
+// with the task. Tasks whose buckets cannot be derived run with an
+// authorization without permissions until their script is updated, and only
+// tasks created before scoped authorizations run with the permissions of
+// their owner. Tasks do not enforce the tag constraints of the authorization
+// setting their script, so authorizations with tag constraints cannot set
+// the scripts of tasks, nor of checks and notification rules.
+
+// secretsPackage is the import path of the flux package reading secrets.
+const secretsPackage = "influxdata/influxdb/secrets"
+
+// checkTaskScriptAuthorizer returns a forbidden error if the authorizer of
+// the request has tag constraints, which the task would not enforce.
+func checkTaskScriptAuthorizer(ctx context.Context) error {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil
+	}
+	if auth, ok := a.(*influxdb.Authorization); ok && len(auth.TagConstraints) > 0 {
+		return &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "authorizations with tag constraints cannot set the script of a task",
+		}
+	}
+	return nil
+}
+
+// createTaskAuthorization mints the scoped authorization of the task and
+// returns its ID, or an invalid ID if the task has no owner.
+func (s *Service) createTaskAuthorization(ctx context.Context, tx Tx, t *influxdb.Task) (influxdb.ID, error) {
+	if !t.OwnerID.Valid() {
+		return 0, nil
+	}
+
+	ps, err := s.taskPermissions(ctx, tx, t)
+	if err != nil {
+		s.Logger.Warn("Task runs without permissions as its buckets cannot be derived", zap.Stringer("taskID", t.ID), zap.Error(err))
+		ps = nil
+	}
+	a := &influxdb.Authorization{
+		Status:      influxdb.Active,
+		Description: fmt.Sprintf("scoped token of task %q (%s)", t.Name, t.ID),
+		OrgID:       t.OrganizationID,
+		UserID:      t.OwnerID,
+		Permissions: ps,
+	}
+	if err := s.createAuthorization(ctx, tx, a); err != nil {
+		return 0, err
+	}
+	return a.ID, nil
+}
+
+// rotateTaskAuthorization replaces the scoped authorization of the task with
+// one minted for its current script.
+func (s *Service) rotateTaskAuthorization(ctx context.Context, tx Tx, t *influxdb.Task) error {
+	if err := s.deleteTaskAuthorization(ctx, tx, t); err != nil {
+		return err
+	}
+	id, err := s.createTaskAuthorization(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+	t.AuthorizationID = id
+	return nil
+}
+
+// deleteTaskAuthorization deletes the scoped authorization of the task, if
+// it still exists.
+func (s *Service) deleteTaskAuthorization(ctx context.Context, tx Tx, t *influxdb.Task) error {
+	if !t.AuthorizationID.Valid() {
+		return nil
+	}
+	if err := s.deleteAuthorization(ctx, tx, t.AuthorizationID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	t.AuthorizationID = 0
+	return nil
+}
+
+// taskAuthorization returns the authorization runs of the task execute with.
+// The permissions of a scoped authorization are limited to the permissions
+// of the owner, and none if the authorization was deleted or deactivated.
+func (s *Service) taskAuthorization(ctx context.Context, tx Tx, t *influxdb.Task) (*influxdb.Authorization, error) {
+	a := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     influxdb.ID(1),
+		OrgID:  t.OrganizationID,
+	}
+	if !t.OwnerID.Valid() {
+		return a, nil
+	}
+
+	ps, err := s.maxPermissions(ctx, tx, t.OwnerID)
+	if err != nil {
+		return a, err
+	}
+	if !t.AuthorizationID.Valid() {
+		a.Permissions = ps
+		return a, nil
+	}
+
+	a.ID = t.AuthorizationID
+	scoped, err := s.findAuthorizationByID(ctx, tx, t.AuthorizationID)
+	if err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return a, nil
+		}
+		return a, err
+	}
+	if !scoped.IsActive() {
+		return a, nil
+	}
+	for _, p := range scoped.Permissions {
+		if influxdb.PermissionAllowed(p, ps) {
+			a.Permissions = append(a.Permissions, p)
+		}
+	}
+	return a, nil
+}
+
+// taskPermissions returns the permissions the script of the task needs:
+// reading the buckets it reads, reading and writing the buckets it writes,
+// reading the organizations it looks up by name and reading the secrets of
+// its organization if it imports the secrets package. Buckets are always
+// permitted by ID, so that functions that are not bucket aware, such as
+// buckets(), only see the buckets read and written with from() and to().
+// Buckets of other organizations cannot be permitted by an authorization of
+// the organization of the task.
+func (s *Service) taskPermissions(ctx context.Context, tx Tx, t *influxdb.Task) ([]influxdb.Permission, error) {
+	pkg := parser.ParseSource(t.Flux)
+	if err := ast.GetError(pkg); err != nil {
+		return nil, err
+	}
+	orgID := t.OrganizationID
+	analysis, err := query.Analyze(ctx, pkg, &orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []influxdb.Permission
+	add := func(id influxdb.ID, a influxdb.Action, rt influxdb.ResourceType, orgID influxdb.ID) error {
+		p, err := influxdb.NewPermissionAtID(id, a, rt, orgID)
+		if err != nil {
+			return err
+		}
+		if !influxdb.PermissionAllowed(*p, ps) {
+			ps = append(ps, *p)
+		}
+		return nil
+	}
+	addBucket := func(f influxdb.BucketFilter, actions ...influxdb.Action) error {
+		b, err := s.findTaskBucket(ctx, tx, f, &orgID)
+		if err != nil {
+			return err
+		}
+		if b.OrgID != orgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("bucket %q is not in the organization of the task", b.Name),
+			}
+		}
+		if f.Org != nil {
+			if err := add(b.OrgID, influxdb.ReadAction, influxdb.OrgsResourceType, b.OrgID); err != nil {
+				return err
+			}
+		}
+		for _, a := range actions {
+			if err := add(b.ID, a, influxdb.BucketsResourceType, b.OrgID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, f := range analysis.ReadBuckets {
+		if err := addBucket(f, influxdb.ReadAction); err != nil {
+			return nil, err
+		}
+	}
+	// buckets are looked up before they are written, which reads them.
+	for _, f := range analysis.WriteBuckets {
+		if err := addBucket(f, influxdb.ReadAction, influxdb.WriteAction); err != nil {
+			return nil, err
+		}
+	}
+	if importsPackage(pkg, secretsPackage) {
+		p, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.SecretsResourceType, orgID)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, *p)
+	}
+	return ps, nil
+}
+
+// findTaskBucket returns the bucket accessed by a task, by ID or by name in
+// the organization of the filter or else of the task.
+func (s *Service) findTaskBucket(ctx context.Context, tx Tx, f influxdb.BucketFilter, orgID *influxdb.ID) (*influxdb.Bucket, error) {
+	if f.ID != nil {
+		return s.findBucketByID(ctx, tx, *f.ID)
+	}
+	if f.Name == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucket must be specified by ID or name",
+		}
+	}
+	if f.OrganizationID != nil {
+		orgID = f.OrganizationID
+	} else if f.Org != nil {
+		o, err := s.findOrganizationByName(ctx, tx, *f.Org)
+		if err != nil {
+			return nil, err
+		}
+		orgID = &o.ID
+	}
+	return s.findBucketByName(ctx, tx, *orgID, *f.Name)
+}
+
+func importsPackage(pkg *ast.Package, path string) bool {
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			if imp.Path != nil && imp.Path.Value == path {
+				return true
+			}
+		}
+	}
+	return false
+}
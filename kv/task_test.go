@@ -273,3 +273,173 @@ func TestRetrieveTaskWithBadAuth(t *testing.T) {
 		t.Fatal("failed to return task")
 	}
 }
+
+func TestTaskScopedAuthorization(t *testing.T) {
+	store, close, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close()
+
+	service := kv.NewService(store)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing task service: %v", err)
+	}
+	defer cancelFunc()
+
+	u := &influxdb.User{Name: t.Name() + "-user"}
+	if err := service.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: t.Name() + "-org"}
+	if err := service.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   o.ID,
+		UserID:       u.ID,
+		UserType:     influxdb.Owner,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	buckets := map[string]*influxdb.Bucket{}
+	for _, name := range []string{"src", "dst", "other"} {
+		b := &influxdb.Bucket{OrgID: o.ID, Name: name}
+		if err := service.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		buckets[name] = b
+	}
+	allowed := func(a *influxdb.Authorization, action influxdb.Action, bucket string) bool {
+		p, err := influxdb.NewPermissionAtID(buckets[bucket].ID, action, influxdb.BucketsResourceType, o.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.Allowed(*p)
+	}
+	ctx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{OrgID: o.ID, UserID: u.ID, Status: influxdb.Active})
+
+	task, err := service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "copy", every: 1h} from(bucket:"src") |> range(start:-1h) |> to(bucket:"dst")`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.AuthorizationID.Valid() {
+		t.Fatal("expected a scoped authorization to be minted for the task")
+	}
+	scoped, err := service.FindAuthorizationByID(ctx, task.AuthorizationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scoped.UserID != u.ID || len(scoped.Permissions) != 3 {
+		t.Fatalf("unexpected scoped authorization: %+v", scoped)
+	}
+
+	task, err = service.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed(task.Authorization, influxdb.ReadAction, "src") || !allowed(task.Authorization, influxdb.WriteAction, "dst") {
+		t.Error("expected the task to read src and write dst")
+	}
+	if allowed(task.Authorization, influxdb.WriteAction, "src") || allowed(task.Authorization, influxdb.ReadAction, "other") {
+		t.Error("expected the task not to write src nor read other")
+	}
+
+	// the authorization is rotated when the script changes.
+	flux := `option task = {name: "copy", every: 1h} from(bucket:"other") |> range(start:-1h) |> to(bucket:"dst")`
+	task, err = service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Flux: &flux})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.AuthorizationID == scoped.ID {
+		t.Fatal("expected the scoped authorization to be rotated")
+	}
+	if _, err := service.FindAuthorizationByID(ctx, scoped.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the previous scoped authorization to be deleted, got %v", err)
+	}
+	task, err = service.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed(task.Authorization, influxdb.ReadAction, "other") || allowed(task.Authorization, influxdb.ReadAction, "src") {
+		t.Error("expected the task to read other and not src")
+	}
+
+	// deactivating the authorization revokes the permissions of the task.
+	inactive := influxdb.Inactive
+	if _, err := service.UpdateAuthorization(ctx, task.AuthorizationID, &influxdb.AuthorizationUpdate{Status: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	task, err = service.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Authorization.Permissions) != 0 {
+		t.Errorf("expected a task with an inactive authorization to have no permissions, got %v", task.Authorization.Permissions)
+	}
+
+	if err := service.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.FindAuthorizationByID(ctx, task.AuthorizationID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the scoped authorization to be deleted with the task, got %v", err)
+	}
+
+	// buckets() does not widen the scope beyond the buckets read by ID.
+	task, err = service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "list", every: 1h} buckets() |> yield(name: "buckets") from(bucket:"src") |> range(start:-1h)`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed(task.Authorization, influxdb.ReadAction, "src") || allowed(task.Authorization, influxdb.ReadAction, "other") {
+		t.Error("expected the task to read src and not other")
+	}
+
+	// tasks whose buckets cannot be derived run without permissions.
+	task, err = service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "missing", every: 1h} from(bucket:"missing") |> range(start:-1h)`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.AuthorizationID.Valid() {
+		t.Fatal("expected a scoped authorization for a task reading a missing bucket")
+	}
+	if len(task.Authorization.Permissions) != 0 {
+		t.Errorf("expected a task reading a missing bucket to have no permissions, got %v", task.Authorization.Permissions)
+	}
+
+	// authorizations with tag constraints cannot set scripts, as tasks would
+	// write without the constraints.
+	constrainedCtx := icontext.SetAuthorizer(ctx, &influxdb.Authorization{
+		OrgID:          o.ID,
+		UserID:         u.ID,
+		Status:         influxdb.Active,
+		TagConstraints: []influxdb.Tag{{Key: "device", Value: "a"}},
+	})
+	if _, err := service.CreateTask(constrainedCtx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "copy", every: 1h} from(bucket:"src") |> range(start:-1h) |> to(bucket:"dst")`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	}); influxdb.ErrorCode(err) != influxdb.EForbidden {
+		t.Errorf("expected creating a task with tag constraints to be forbidden, got %v", err)
+	}
+	if _, err := service.UpdateTask(constrainedCtx, task.ID, influxdb.TaskUpdate{Flux: &flux}); influxdb.ErrorCode(err) != influxdb.EForbidden {
+		t.Errorf("expected updating the script of a task with tag constraints to be forbidden, got %v", err)
+	}
+	description := "updated"
+	if _, err := service.UpdateTask(constrainedCtx, task.ID, influxdb.TaskUpdate{Description: &description}); err != nil {
+		t.Errorf("expected updating the description of a task with tag constraints to succeed, got %v", err)
+	}
+}
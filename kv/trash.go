@@ -0,0 +1,469 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var trashBucket = []byte("trashv1")
+
+const dashboardRestoredEvent = "Dashboard Restored"
+
+var _ influxdb.TrashService = (*Service)(nil)
+
+// trashRecord is a trashed resource together with everything that is needed
+// to restore it.
+type trashRecord struct {
+	influxdb.TrashedResource
+
+	// Resource is the resource as it was stored before deletion.
+	Resource json.RawMessage                 `json:"resource"`
+	Views    []*trashedCellView              `json:"views,omitempty"`
+	URMs     []*influxdb.UserResourceMapping `json:"urms,omitempty"`
+	// ACL is the acl of the resource if it was restricted.
+	ACL *influxdb.ResourceACL `json:"acl,omitempty"`
+}
+
+type trashedCellView struct {
+	CellID influxdb.ID    `json:"cellID"`
+	View   *influxdb.View `json:"view"`
+}
+
+func (s *Service) initializeTrash(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(trashBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// trashEnabled returns true if deleted dashboards and tasks are moved to the trash.
+func (s *Service) trashEnabled() bool {
+	return s.Config.TrashRetention > 0
+}
+
+// FindTrashedResourceByID returns a single trashed resource by the ID it had before deletion.
+func (s *Service) FindTrashedResourceByID(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	var r *influxdb.TrashedResource
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rec, err := s.findTrashRecord(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = &rec.TrashedResource
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTrashedResourceByID,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// FindTrashedResources returns a list of trashed resources that match the filter
+// and the total count of matching resources.
+func (s *Service) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	var offset, limit, count int
+	if len(opts) > 0 {
+		offset = opts[0].Offset
+		limit = opts[0].Limit
+	}
+
+	rs := []*influxdb.TrashedResource{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachTrashRecord(ctx, tx, func(rec *trashRecord) bool {
+			if filter.OrgID != nil && rec.OrgID != *filter.OrgID {
+				return true
+			}
+			if filter.Type != nil && rec.Type != *filter.Type {
+				return true
+			}
+			// every matching record is counted, while only the ones within
+			// the offset and limit are returned.
+			if count >= offset && (limit <= 0 || len(rs) < limit) {
+				rs = append(rs, &rec.TrashedResource)
+			}
+			count++
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindTrashedResources,
+			Err: err,
+		}
+	}
+	return rs, count, nil
+}
+
+// RestoreDashboard moves a dashboard out of the trash.
+func (s *Service) RestoreDashboard(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+	var d *influxdb.Dashboard
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rec, err := s.findTrashRecord(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if rec.Type != influxdb.DashboardsResourceType {
+			return influxdb.ErrTrashedResourceNotFound
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, rec.OrgID); err != nil {
+			return err
+		}
+
+		dash := &influxdb.Dashboard{}
+		if err := json.Unmarshal(rec.Resource, dash); err != nil {
+			return err
+		}
+
+		for _, v := range rec.Views {
+			if err := s.putDashboardCellView(ctx, tx, dash.ID, v.CellID, v.View); err != nil {
+				return err
+			}
+		}
+		if err := s.putOrganizationDashboardIndex(ctx, tx, dash); err != nil {
+			return err
+		}
+		if err := s.putDashboardWithMeta(ctx, tx, dash); err != nil {
+			return err
+		}
+		if err := s.restoreTrashedURMs(ctx, tx, rec); err != nil {
+			return err
+		}
+		if err := s.restoreTrashedACL(ctx, tx, rec); err != nil {
+			return err
+		}
+		if err := s.appendDashboardEventToLog(ctx, tx, dash.ID, dashboardRestoredEvent); err != nil {
+			return err
+		}
+		if err := s.deleteTrashRecord(ctx, tx, id); err != nil {
+			return err
+		}
+
+		d = dash
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpRestoreDashboard,
+			Err: err,
+		}
+	}
+	return d, nil
+}
+
+// RestoreTask moves a task out of the trash. The task resumes from the time
+// it is restored; runs it missed while in the trash are not scheduled.
+func (s *Service) RestoreTask(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	var t *influxdb.Task
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rec, err := s.findTrashRecord(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if rec.Type != influxdb.TasksResourceType {
+			return influxdb.ErrTrashedResourceNotFound
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, rec.OrgID); err != nil {
+			return err
+		}
+
+		task := &kvTask{}
+		if err := json.Unmarshal(rec.Resource, task); err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		now := s.Now().UTC().Truncate(time.Second)
+		task.LatestCompleted = now
+		task.UpdatedAt = now
+		// the scoped authorization of the task was deleted with it.
+		authID, err := s.createTaskAuthorization(ctx, tx, kvToInfluxTask(task))
+		if err != nil {
+			return err
+		}
+		task.AuthorizationID = authID
+
+		v, err := json.Marshal(task)
+		if err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+
+		key, err := taskKey(task.ID)
+		if err != nil {
+			return err
+		}
+		orgKey, err := taskOrgKey(task.OrganizationID, task.ID)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(taskBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		if err := b.Put(key, v); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		idx, err := tx.Bucket(taskIndexBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		if err := idx.Put(orgKey, key); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		if err := s.restoreTrashedURMs(ctx, tx, rec); err != nil {
+			return err
+		}
+		if err := s.deleteTrashRecord(ctx, tx, id); err != nil {
+			return err
+		}
+
+		t, err = s.findTaskByIDWithAuth(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpRestoreTask,
+			Err: err,
+		}
+	}
+	return t, nil
+}
+
+// trashDashboard records the dashboard and its cell views in the trash
+// before the dashboard is deleted.
+func (s *Service) trashDashboard(ctx context.Context, tx Tx, d *influxdb.Dashboard) error {
+	rec := &trashRecord{
+		TrashedResource: influxdb.TrashedResource{
+			ID:    d.ID,
+			OrgID: d.OrganizationID,
+			Type:  influxdb.DashboardsResourceType,
+			Name:  d.Name,
+		},
+	}
+
+	v, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	rec.Resource = v
+
+	for _, cell := range d.Cells {
+		view, err := s.findDashboardCellView(ctx, tx, d.ID, cell.ID)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				continue
+			}
+			return err
+		}
+		rec.Views = append(rec.Views, &trashedCellView{CellID: cell.ID, View: view})
+	}
+
+	return s.putTrashRecord(ctx, tx, rec)
+}
+
+// trashTask records the task in the trash before it is deleted.
+func (s *Service) trashTask(ctx context.Context, tx Tx, t *influxdb.Task) error {
+	key, err := taskKey(t.ID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	v, err := b.Get(key)
+	if err != nil {
+		return err
+	}
+
+	return s.putTrashRecord(ctx, tx, &trashRecord{
+		TrashedResource: influxdb.TrashedResource{
+			ID:    t.ID,
+			OrgID: t.OrganizationID,
+			Type:  influxdb.TasksResourceType,
+			Name:  t.Name,
+		},
+		Resource: v,
+	})
+}
+
+// putTrashRecord stores the record with the user resource mappings and the
+// acl of the resource and removes expired records from the trash.
+func (s *Service) putTrashRecord(ctx context.Context, tx Tx, rec *trashRecord) error {
+	urms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   rec.ID,
+		ResourceType: rec.Type,
+	})
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	rec.URMs = urms
+
+	acl, err := s.findResourceACL(ctx, tx, rec.Type, rec.ID)
+	if err != nil {
+		return err
+	}
+	if acl.Restricted() {
+		rec.ACL = acl
+	}
+
+	now := s.Now()
+	rec.DeletedAt = now
+	rec.ExpiresAt = now.Add(s.Config.TrashRetention)
+
+	if err := s.deleteExpiredTrashRecords(ctx, tx); err != nil {
+		return err
+	}
+
+	k, err := rec.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+// findTrashRecord returns the record of a trashed resource. Expired records
+// are treated as missing.
+func (s *Service) findTrashRecord(ctx context.Context, tx Tx, id influxdb.ID) (*trashRecord, error) {
+	k, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, influxdb.ErrTrashedResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &trashRecord{}
+	if err := json.Unmarshal(v, rec); err != nil {
+		return nil, err
+	}
+	if rec.Expired(s.Now()) {
+		return nil, influxdb.ErrTrashedResourceNotFound
+	}
+	return rec, nil
+}
+
+// forEachTrashRecord calls fn for every record in the trash that has not
+// expired while fn returns true.
+func (s *Service) forEachTrashRecord(ctx context.Context, tx Tx, fn func(*trashRecord) bool) error {
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	now := s.Now()
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		rec := &trashRecord{}
+		if err := json.Unmarshal(v, rec); err != nil {
+			return err
+		}
+		if rec.Expired(now) {
+			continue
+		}
+		if !fn(rec) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteTrashRecord(ctx context.Context, tx Tx, id influxdb.ID) error {
+	k, err := id.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
+
+func (s *Service) deleteExpiredTrashRecords(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	now := s.Now()
+	var expired [][]byte
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		rec := &trashRecord{}
+		if err := json.Unmarshal(v, rec); err != nil {
+			return err
+		}
+		if rec.Expired(now) {
+			expired = append(expired, k)
+		}
+	}
+
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) restoreTrashedURMs(ctx context.Context, tx Tx, rec *trashRecord) error {
+	for _, m := range rec.URMs {
+		if err := s.createUserResourceMapping(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreTrashedACL restores the acl of the record, so that a restricted
+// resource is restored restricted to the same users.
+func (s *Service) restoreTrashedACL(ctx context.Context, tx Tx, rec *trashRecord) error {
+	if rec.ACL == nil {
+		return nil
+	}
+	return s.putResourceACL(ctx, tx, rec.ACL)
+}
@@ -0,0 +1,279 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func newTrashTestService(t *testing.T, retention time.Duration) (*kv.Service, *mock.TimeGenerator, func()) {
+	t.Helper()
+
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	now := &mock.TimeGenerator{FakeValue: time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)}
+	svc := kv.NewService(store, kv.ServiceConfig{TrashRetention: retention})
+	svc.TimeGenerator = now
+
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatalf("error initializing trash service: %v", err)
+	}
+	return svc, now, closeStore
+}
+
+func TestService_TrashDashboard(t *testing.T) {
+	svc, now, done := newTrashTestService(t, time.Hour)
+	defer done()
+	ctx := context.Background()
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "dashboard"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	cell := &influxdb.Cell{CellProperty: influxdb.CellProperty{W: 4, H: 4}}
+	if err := svc.AddDashboardCell(ctx, d.ID, cell, influxdb.AddDashboardCellOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	name := "view"
+	if _, err := svc.UpdateDashboardCellView(ctx, d.ID, cell.ID, influxdb.ViewUpdate{ViewContentsUpdate: influxdb.ViewContentsUpdate{Name: &name}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindDashboardByID(ctx, d.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleted dashboard to be not found, got %v", err)
+	}
+
+	rs, n, err := svc.FindTrashedResources(ctx, influxdb.TrashFilter{OrgID: &o.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || rs[0].ID != d.ID || rs[0].Type != influxdb.DashboardsResourceType {
+		t.Fatalf("unexpected trashed resources %+v", rs)
+	}
+	if want := now.FakeValue.Add(time.Hour); !rs[0].ExpiresAt.Equal(want) {
+		t.Fatalf("expected trashed dashboard to expire at %v, got %v", want, rs[0].ExpiresAt)
+	}
+
+	if _, err := svc.RestoreTask(ctx, d.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected restoring a dashboard as a task to fail, got %v", err)
+	}
+
+	restored, err := svc.RestoreDashboard(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Name != d.Name || len(restored.Cells) != 1 {
+		t.Fatalf("unexpected restored dashboard %+v", restored)
+	}
+
+	view, err := svc.GetDashboardCellView(ctx, d.ID, cell.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Name != name {
+		t.Fatalf("expected restored view name %q, got %q", name, view.Name)
+	}
+
+	if _, err := svc.FindTrashedResourceByID(ctx, d.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected restored dashboard to be removed from the trash, got %v", err)
+	}
+}
+
+func TestService_TrashRestrictedDashboard(t *testing.T) {
+	svc, _, done := newTrashTestService(t, time.Hour)
+	defer done()
+	ctx := context.Background()
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "executive"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	userID := influxdb.ID(1)
+	if err := svc.PutResourceACL(ctx, &influxdb.ResourceACL{
+		ResourceType: influxdb.DashboardsResourceType,
+		ResourceID:   d.ID,
+		Grants:       []influxdb.ResourceGrant{{UserID: &userID, Access: influxdb.ResourceAccessView}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := svc.FindResourceACL(ctx, influxdb.DashboardsResourceType, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trashed.Restricted() || trashed.Allowed(userID+1, influxdb.ReadAction) {
+		t.Fatalf("expected trashed dashboard to stay restricted, got %+v", trashed)
+	}
+
+	if _, err := svc.RestoreDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	acl, err := svc.FindResourceACL(ctx, influxdb.DashboardsResourceType, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acl.Restricted() || !acl.Allowed(userID, influxdb.ReadAction) || acl.Allowed(userID+1, influxdb.ReadAction) {
+		t.Fatalf("expected restored dashboard to be restricted as before, got %+v", acl)
+	}
+}
+
+func TestService_FindTrashedResources_Paginated(t *testing.T) {
+	svc, _, done := newTrashTestService(t, time.Hour)
+	defer done()
+	ctx := context.Background()
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		d := &influxdb.Dashboard{OrganizationID: o.ID, Name: name}
+		if err := svc.CreateDashboard(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs, n, err := svc.FindTrashedResources(ctx, influxdb.TrashFilter{OrgID: &o.ID}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("expected a page of 1 trashed resource, got %d", len(rs))
+	}
+	if n != 3 {
+		t.Fatalf("expected a total of 3 trashed resources, got %d", n)
+	}
+}
+
+func TestService_TrashExpired(t *testing.T) {
+	svc, now, done := newTrashTestService(t, time.Hour)
+	defer done()
+	ctx := context.Background()
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "dashboard"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	now.FakeValue = now.FakeValue.Add(time.Hour)
+
+	if _, err := svc.RestoreDashboard(ctx, d.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected expired dashboard to be not found, got %v", err)
+	}
+	if rs, _, err := svc.FindTrashedResources(ctx, influxdb.TrashFilter{}); err != nil || len(rs) != 0 {
+		t.Fatalf("expected no trashed resources, got %+v %v", rs, err)
+	}
+}
+
+func TestService_TrashDisabled(t *testing.T) {
+	svc, _, done := newTrashTestService(t, 0)
+	defer done()
+	ctx := context.Background()
+
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &influxdb.Dashboard{OrganizationID: o.ID, Name: "dashboard"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.FindTrashedResourceByID(ctx, d.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected dashboard to be deleted immediately, got %v", err)
+	}
+}
+
+func TestService_TrashTask(t *testing.T) {
+	svc, _, done := newTrashTestService(t, time.Hour)
+	defer done()
+	ctx := context.Background()
+
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	authz := influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := svc.CreateAuthorization(ctx, &authz); err != nil {
+		t.Fatal(err)
+	}
+	ctx = icontext.SetAuthorizer(ctx, &authz)
+
+	task, err := svc.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task", every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: o.ID,
+		OwnerID:        u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindTaskByID(ctx, task.ID); err != influxdb.ErrTaskNotFound {
+		t.Fatalf("expected deleted task to be not found, got %v", err)
+	}
+
+	restored, err := svc.RestoreTask(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Name != task.Name || restored.Flux != task.Flux || restored.OwnerID != u.ID {
+		t.Fatalf("unexpected restored task %+v", restored)
+	}
+
+	if _, err := svc.FindTaskByID(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+}
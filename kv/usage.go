@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var usageWindowsBucket = []byte("usagewindowsv1")
+
+var _ influxdb.UsageWindowService = (*Service)(nil)
+
+func (s *Service) initializeUsageWindows(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(usageWindowsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// usageWindowKey is the start of the window followed by the organization, so
+// that the windows are ordered by time.
+func usageWindowKey(start time.Time, orgID influxdb.ID) ([]byte, error) {
+	id, err := orgID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	k := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(k, uint64(start.UnixNano()))
+	return append(k, id...), nil
+}
+
+// usageWindowStart returns the start of the window of the key k.
+func usageWindowStart(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k[:8]))).UTC()
+}
+
+// FindUsageWindows returns the usage windows starting at or after since.
+func (s *Service) FindUsageWindows(ctx context.Context, since time.Time) ([]*influxdb.UsageWindow, error) {
+	ws := []*influxdb.UsageWindow{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(usageWindowsBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			if usageWindowStart(k).Before(since) {
+				continue
+			}
+			w := &influxdb.UsageWindow{}
+			if err := json.Unmarshal(v, w); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			ws = append(ws, w)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindUsageWindows,
+			Err: err,
+		}
+	}
+	return ws, nil
+}
+
+// PutUsageWindows stores the usage windows, replacing the stored windows of
+// the same organizations and starts.
+func (s *Service) PutUsageWindows(ctx context.Context, windows []*influxdb.UsageWindow) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(usageWindowsBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, w := range windows {
+			k, err := usageWindowKey(w.Start, w.OrgID)
+			if err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Err:  err,
+				}
+			}
+			v, err := json.Marshal(w)
+			if err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			if err := b.Put(k, v); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutUsageWindows,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// DeleteUsageWindows removes the usage windows starting before before.
+func (s *Service) DeleteUsageWindows(ctx context.Context, before time.Time) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(usageWindowsBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		// keys are collected first, as deleting moves the cursor.
+		var keys [][]byte
+		for k, _ := cur.First(); k != nil && usageWindowStart(k).Before(before); k, _ = cur.Next() {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteUsageWindows,
+			Err: err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestUsageWindows(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		newStore func() (kv.Store, func(), error)
+	}{
+		{name: "bolt", newStore: NewTestBoltStore},
+		{name: "inmem", newStore: NewTestInmemStore},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, closeStore, err := tt.newStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			defer closeStore()
+
+			ctx := context.Background()
+			svc := kv.NewService(s)
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatalf("unable to initialize kv store: %v", err)
+			}
+
+			start := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+			if err := svc.PutUsageWindows(ctx, []*influxdb.UsageWindow{
+				{OrgID: 1, Start: start, WriteRequests: 1},
+				{OrgID: 2, Start: start, WriteRequests: 2},
+				{OrgID: 1, Start: start.Add(time.Hour), WriteRequests: 3},
+			}); err != nil {
+				t.Fatalf("unexpected error putting windows: %v", err)
+			}
+			// windows of the same organization and start are replaced.
+			if err := svc.PutUsageWindows(ctx, []*influxdb.UsageWindow{
+				{OrgID: 1, Start: start.Add(time.Hour), WriteRequests: 4, QueryDuration: time.Second},
+			}); err != nil {
+				t.Fatalf("unexpected error putting windows: %v", err)
+			}
+
+			ws, err := svc.FindUsageWindows(ctx, start.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("unexpected error finding windows: %v", err)
+			}
+			if len(ws) != 1 || ws[0].OrgID != 1 || ws[0].WriteRequests != 4 || ws[0].QueryDuration != time.Second {
+				t.Fatalf("unexpected windows %+v", ws)
+			}
+
+			if err := svc.DeleteUsageWindows(ctx, start.Add(time.Hour)); err != nil {
+				t.Fatalf("unexpected error deleting windows: %v", err)
+			}
+			ws, err = svc.FindUsageWindows(ctx, time.Time{})
+			if err != nil {
+				t.Fatalf("unexpected error finding windows: %v", err)
+			}
+			if len(ws) != 1 || !ws[0].Start.Equal(start.Add(time.Hour)) {
+				t.Fatalf("expected only the latest window to be kept, got %+v", ws)
+			}
+		})
+	}
+}
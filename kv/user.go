@@ -418,6 +418,14 @@ func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return err
 	}
 
+	if err := s.deleteUserSettings(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteSessionAlerts(ctx, tx, id); err != nil {
+		return err
+	}
+
 	encodedID, err := id.Encode()
 	if err != nil {
 		return InvalidUserIDError(err)
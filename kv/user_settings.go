@@ -0,0 +1,188 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var userSettingsBucket = []byte("usersettingsv1")
+
+var _ influxdb.UserSettingsService = (*Service)(nil)
+
+func (s *Service) initializeUserSettings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(userSettingsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindUserSettings returns the settings of a user.
+func (s *Service) FindUserSettings(ctx context.Context, userID influxdb.ID) (*influxdb.UserSettings, error) {
+	var us *influxdb.UserSettings
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		settings, err := s.findUserSettings(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		us = settings
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindUserSettings,
+			Err: err,
+		}
+	}
+	return us, nil
+}
+
+// PutUserSetting replaces the value of the namespace of the settings of a user.
+func (s *Service) PutUserSetting(ctx context.Context, userID influxdb.ID, namespace string, value json.RawMessage) (*influxdb.UserSettings, error) {
+	var us *influxdb.UserSettings
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		settings, err := s.findUserSettings(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		settings.Settings[namespace] = value
+		if err := settings.Valid(); err != nil {
+			return err
+		}
+		settings.UpdatedAt = s.Now()
+
+		if err := s.putUserSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+		us = settings
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpPutUserSetting,
+			Err: err,
+		}
+	}
+	return us, nil
+}
+
+// DeleteUserSetting removes the namespace from the settings of a user.
+func (s *Service) DeleteUserSetting(ctx context.Context, userID influxdb.ID, namespace string) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		settings, err := s.findUserSettings(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := settings.Settings[namespace]; !ok {
+			return influxdb.ErrUserSettingNotFound
+		}
+		delete(settings.Settings, namespace)
+		settings.UpdatedAt = s.Now()
+
+		return s.putUserSettings(ctx, tx, settings)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteUserSetting,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// findUserSettings returns the stored settings of the user or empty
+// settings if the user never stored a setting.
+func (s *Service) findUserSettings(ctx context.Context, tx Tx, userID influxdb.ID) (*influxdb.UserSettings, error) {
+	k, err := userID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(userSettingsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return &influxdb.UserSettings{
+			UserID:   userID,
+			Settings: map[string]json.RawMessage{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	us := &influxdb.UserSettings{}
+	if err := json.Unmarshal(v, us); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	if us.Settings == nil {
+		us.Settings = map[string]json.RawMessage{}
+	}
+	return us, nil
+}
+
+func (s *Service) putUserSettings(ctx context.Context, tx Tx, us *influxdb.UserSettings) error {
+	k, err := us.UserID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(us)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(userSettingsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(k, v)
+}
+
+func (s *Service) deleteUserSettings(ctx context.Context, tx Tx, userID influxdb.ID) error {
+	k, err := userID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(userSettingsBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(k)
+}
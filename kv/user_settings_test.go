@@ -0,0 +1,95 @@
+package kv_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestService_UserSettings(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(store)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing user settings service: %v", err)
+	}
+
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := svc.FindUserSettings(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.UserID != u.ID || len(settings.Settings) != 0 {
+		t.Fatalf("expected empty settings, got %+v", settings)
+	}
+
+	if _, err := svc.PutUserSetting(ctx, u.ID, "theme", json.RawMessage(`"dark"`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PutUserSetting(ctx, u.ID, "savedSearches", json.RawMessage(`[{"name":"errors","filter":"level == \"error\""}]`)); err != nil {
+		t.Fatal(err)
+	}
+	settings, err = svc.PutUserSetting(ctx, u.ID, "theme", json.RawMessage(`"light"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Settings) != 2 || string(settings.Settings["theme"]) != `"light"` {
+		t.Fatalf("unexpected settings %+v", settings)
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		value     json.RawMessage
+		code      string
+	}{
+		{name: "invalid namespace", namespace: "../theme", value: json.RawMessage(`1`), code: influxdb.EInvalid},
+		{name: "invalid json", namespace: "theme", value: json.RawMessage(`{`), code: influxdb.EInvalid},
+		{name: "value too large", namespace: "big", value: json.RawMessage(`"` + strings.Repeat("a", influxdb.MaxUserSettingSize) + `"`), code: influxdb.ERequestTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.PutUserSetting(ctx, u.ID, tt.namespace, tt.value); influxdb.ErrorCode(err) != tt.code {
+				t.Fatalf("expected %s error, got %v", tt.code, err)
+			}
+		})
+	}
+
+	// fill the settings up to the total limit with values that are each small enough.
+	value := json.RawMessage(`"` + strings.Repeat("a", influxdb.MaxUserSettingSize-2) + `"`)
+	var i int
+	for err == nil {
+		_, err = svc.PutUserSetting(ctx, u.ID, "filler"+string(rune('a'+i)), value)
+		i++
+	}
+	if influxdb.ErrorCode(err) != influxdb.ERequestTooLarge {
+		t.Fatalf("expected total size limit error, got %v", err)
+	}
+
+	if err := svc.DeleteUserSetting(ctx, u.ID, "theme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteUserSetting(ctx, u.ID, "theme"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleted setting to be not found, got %v", err)
+	}
+
+	if err := svc.DeleteUser(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindUserSettings(ctx, u.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected settings of deleted user to be not found, got %v", err)
+	}
+}
@@ -274,20 +274,34 @@ func (s *Service) CreateVariable(ctx context.Context, variable *influxdb.Variabl
 // ReplaceVariable puts a variable in the store
 func (s *Service) ReplaceVariable(ctx context.Context, variable *influxdb.Variable) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findVariableByID(ctx, tx, variable.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+
 		if err := s.putVariableOrgsIndex(ctx, tx, variable); err != nil {
 			return &influxdb.Error{
 				Err: err,
 			}
 		}
 
-		err := s.uniqueVariableName(ctx, tx, variable)
+		err = s.uniqueVariableName(ctx, tx, variable)
 		if err != nil {
 			return &influxdb.Error{
 				Err: err,
 			}
 		}
 
-		return s.putVariable(ctx, tx, variable)
+		if err := s.putVariable(ctx, tx, variable); err != nil {
+			return err
+		}
+
+		if current == nil {
+			return nil
+		}
+		return s.updateVariableChecks(ctx, tx, current.OrganizationID, current.Name)
 	})
 }
 
@@ -403,6 +417,7 @@ func (s *Service) UpdateVariable(ctx context.Context, id influxdb.ID, update *in
 		m.UpdatedAt = s.Now()
 
 		variable = m
+		name := m.Name
 
 		if update.Name != "" {
 			update.Name = strings.TrimSpace(update.Name)
@@ -433,7 +448,8 @@ func (s *Service) UpdateVariable(ctx context.Context, id influxdb.ID, update *in
 				Err: err,
 			}
 		}
-		return nil
+
+		return s.updateVariableChecks(ctx, tx, variable.OrganizationID, name)
 	})
 
 	return variable, err
@@ -449,6 +465,19 @@ func (s *Service) DeleteVariable(ctx context.Context, id influxdb.ID) error {
 			}
 		}
 
+		cs, err := s.findVariableChecks(ctx, tx, v.OrganizationID, v.Name)
+		if err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+		if len(cs) > 0 {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("variable %q is referenced by check %q", v.Name, cs[0].GetName()),
+			}
+		}
+
 		encID, err := id.Encode()
 		if err != nil {
 			return &influxdb.Error{
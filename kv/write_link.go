@@ -0,0 +1,105 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// writeLinksBucket holds the used write links, keyed by their expiration
+// time and signature so that the expired ones are found at its start.
+var writeLinksBucket = []byte("writelinksv1")
+
+// maxExpiredWriteLinks is the maximum number of expired write links removed
+// each time a link is used, bounding the work of a single transaction.
+const maxExpiredWriteLinks = 100
+
+var _ influxdb.WriteLinkService = (*Service)(nil)
+
+func (s *Service) initializeWriteLinks(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(writeLinksBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseWriteLink verifies the write link with the authorization signing it and
+// marks it used until it expires.
+func (s *Service) UseWriteLink(ctx context.Context, authID influxdb.ID, signature []byte, expiresAt time.Time, verify func(*influxdb.Authorization) error) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		auth, err := s.findAuthorizationByID(ctx, tx, authID)
+		if err != nil {
+			return err
+		}
+		if err := verify(auth); err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(writeLinksBucket)
+		if err != nil {
+			return err
+		}
+		if err := deleteExpiredWriteLinks(b, s.Now()); err != nil {
+			return err
+		}
+
+		key := writeLinkKey(expiresAt, signature)
+		if _, err := b.Get(key); err == nil {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "write link was already used",
+			}
+		} else if !IsNotFound(err) {
+			return err
+		}
+		if err := b.Put(key, []byte{1}); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		a = auth
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUseWriteLink,
+			Err: err,
+		}
+	}
+	return a, nil
+}
+
+func writeLinkKey(expiresAt time.Time, signature []byte) []byte {
+	key := make([]byte, 8, 8+len(signature))
+	binary.BigEndian.PutUint64(key, uint64(expiresAt.Unix()))
+	return append(key, signature...)
+}
+
+// deleteExpiredWriteLinks removes the oldest write links that expired
+// before now.
+func deleteExpiredWriteLinks(b Bucket, now time.Time) error {
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var expired [][]byte
+	for k, _ := cur.First(); k != nil && len(expired) < maxExpiredWriteLinks; k, _ = cur.Next() {
+		if len(k) < 8 || int64(binary.BigEndian.Uint64(k)) >= now.Unix() {
+			break
+		}
+		expired = append(expired, append([]byte(nil), k...))
+	}
+
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_UseWriteLink(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing write link service: %v", err)
+	}
+
+	auth := &influxdb.Authorization{
+		ID:     1,
+		Token:  "secret",
+		Status: influxdb.Active,
+		OrgID:  2,
+		UserID: 3,
+	}
+	if err := svc.PutAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	ok := func(*influxdb.Authorization) error { return nil }
+	expiresAt := now.Add(time.Minute)
+
+	errMismatch := errors.New("signature does not match")
+	if _, err := svc.UseWriteLink(ctx, auth.ID, []byte("sig1"), expiresAt, func(*influxdb.Authorization) error {
+		return errMismatch
+	}); err == nil {
+		t.Fatal("expected a link failing verification to be rejected")
+	}
+
+	a, err := svc.UseWriteLink(ctx, auth.ID, []byte("sig1"), expiresAt, ok)
+	if err != nil {
+		t.Fatalf("expected a link failing verification to remain unused: %v", err)
+	}
+	if a.Token != auth.Token {
+		t.Errorf("unexpected authorization %v", a)
+	}
+
+	_, err = svc.UseWriteLink(ctx, auth.ID, []byte("sig1"), expiresAt, ok)
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a used link to be rejected with a conflict, got %v", err)
+	}
+
+	if _, err := svc.UseWriteLink(ctx, auth.ID, []byte("sig2"), expiresAt, ok); err != nil {
+		t.Fatalf("unexpected error using another link: %v", err)
+	}
+
+	if _, err := svc.UseWriteLink(ctx, 4, []byte("sig3"), expiresAt, ok); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a link of a missing authorization to be rejected, got %v", err)
+	}
+
+	// once expired, the links used are forgotten
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: expiresAt.Add(time.Second)}
+	if _, err := svc.UseWriteLink(ctx, auth.ID, []byte("sig4"), expiresAt.Add(time.Minute), ok); err != nil {
+		t.Fatalf("unexpected error using another link: %v", err)
+	}
+	if _, err := svc.UseWriteLink(ctx, auth.ID, []byte("sig1"), expiresAt, ok); err != nil {
+		t.Fatalf("expected the expired link to be forgotten: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/pkg/snowflake"
 	"github.com/opentracing/opentracing-go"
 	"github.com/uber/jaeger-client-go"
@@ -87,12 +88,15 @@ func Shard(id uint64) zapcore.Field {
 	return zap.Uint64(DBShardIDKey, id)
 }
 
-// TraceID returns a field "trace_id", value pulled from the (Jaeger) trace ID found in the given context.
+// TraceID returns a field "trace_id", value pulled from the (Jaeger or OTLP) trace ID found in the given context.
 // Returns zap.Skip() if the context doesn't have a trace ID.
 func TraceID(ctx context.Context) zap.Field {
 	if span := opentracing.SpanFromContext(ctx); span != nil {
-		if spanContext, ok := span.Context().(jaeger.SpanContext); ok {
+		switch spanContext := span.Context().(type) {
+		case jaeger.SpanContext:
 			return zap.String("trace_id", spanContext.TraceID().String())
+		case tracing.OTLPSpanContext:
+			return zap.String("trace_id", spanContext.TraceID())
 		}
 	}
 	return zap.Skip()
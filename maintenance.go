@@ -0,0 +1,83 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for maintenance errors and op logs.
+const (
+	OpFindMaintenances = "FindMaintenances"
+	OpStartMaintenance = "StartMaintenance"
+	OpStopMaintenance  = "StopMaintenance"
+)
+
+// DefaultMaintenanceRetryAfter is the number of seconds clients are told to
+// wait before retrying requests rejected by a maintenance that does not set
+// one.
+const DefaultMaintenanceRetryAfter = 300
+
+// MaintenanceService puts the instance, or single organizations, in
+// maintenance mode, e.g. during a planned migration. Writes and queries of
+// the organizations in maintenance are rejected as unavailable, while the
+// rest of the API stays available to administer them.
+type MaintenanceService interface {
+	// FindMaintenances returns the maintenances in effect.
+	FindMaintenances(ctx context.Context) ([]*Maintenance, error)
+
+	// StartMaintenance puts the organization of m, or the instance if m has
+	// none, in maintenance mode. It replaces the maintenance of the
+	// organization or instance in effect.
+	StartMaintenance(ctx context.Context, m *Maintenance) error
+
+	// StopMaintenance ends the maintenance of the organization, or of the
+	// instance if orgID is nil.
+	StopMaintenance(ctx context.Context, orgID *ID) error
+}
+
+// Maintenance is the maintenance mode of the instance or of an organization.
+type Maintenance struct {
+	// OrganizationID is the organization in maintenance, or nil if the whole
+	// instance is.
+	OrganizationID *ID `json:"orgID,omitempty"`
+	// Message tells the users about the maintenance. It is returned with the
+	// rejected requests and shown by the UI.
+	Message string `json:"message,omitempty"`
+	// RetryAfter is the number of seconds clients are told to wait before
+	// retrying rejected requests.
+	RetryAfter int       `json:"retryAfter"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// Valid returns an error if the maintenance is invalid.
+func (m *Maintenance) Valid() error {
+	if m.OrganizationID != nil && !m.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "maintenance organization ID is invalid",
+		}
+	}
+	if m.RetryAfter < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "maintenance retry after must not be negative",
+		}
+	}
+	return nil
+}
+
+// MaintenanceOf returns the maintenance of the maintenances that applies to
+// the organization: the maintenance of the instance or else the one of the
+// organization. It returns nil if the organization is not in maintenance.
+func MaintenanceOf(ms []*Maintenance, orgID ID) *Maintenance {
+	var org *Maintenance
+	for _, m := range ms {
+		switch {
+		case m.OrganizationID == nil:
+			return m
+		case *m.OrganizationID == orgID:
+			org = m
+		}
+	}
+	return org
+}
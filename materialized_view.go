@@ -0,0 +1,235 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ops for materialized view errors and op logs.
+const (
+	OpFindMaterializedViewByID = "FindMaterializedViewByID"
+	OpFindMaterializedViews    = "FindMaterializedViews"
+	OpCreateMaterializedView   = "CreateMaterializedView"
+	OpUpdateMaterializedView   = "UpdateMaterializedView"
+	OpDeleteMaterializedView   = "DeleteMaterializedView"
+)
+
+// MaterializedViewService represents a service for managing materialized views.
+type MaterializedViewService interface {
+	// FindMaterializedViewByID returns a single materialized view by ID.
+	FindMaterializedViewByID(ctx context.Context, id ID) (*MaterializedView, error)
+
+	// FindMaterializedViews returns a list of materialized views that match
+	// the filter and the total count of matching views.
+	FindMaterializedViews(ctx context.Context, filter MaterializedViewFilter, opt ...FindOptions) ([]*MaterializedView, int, error)
+
+	// CreateMaterializedView creates a new materialized view and sets mv.ID
+	// with the new identifier.
+	CreateMaterializedView(ctx context.Context, mv *MaterializedView) error
+
+	// UpdateMaterializedView updates a single materialized view with the changeset.
+	UpdateMaterializedView(ctx context.Context, id ID, upd MaterializedViewUpdate) (*MaterializedView, error)
+
+	// DeleteMaterializedView removes a materialized view by ID.
+	DeleteMaterializedView(ctx context.Context, id ID) error
+}
+
+// MaterializedView is an aggregation of a measurement of a bucket by windows
+// of time that is maintained by the server in a destination bucket. A window
+// is materialized once the late data window has passed after its end; data
+// that arrives later than that marks its window stale until the window is
+// materialized again, and stale windows are read from the source bucket.
+//
+// Each window of a series is written to the destination bucket as a single
+// point at the start of the window, with the measurement, field and tags of
+// the series. Queries aggregating the measurement with aggregateWindow and
+// the same aggregate read the materialized windows instead of the source
+// bucket when their window is that of the view, or a multiple of it for
+// aggregates other than mean.
+type MaterializedView struct {
+	ID                  ID                        `json:"id,omitempty"`
+	OrganizationID      ID                        `json:"orgID"`
+	Name                string                    `json:"name"`
+	Description         string                    `json:"description,omitempty"`
+	SourceBucketID      ID                        `json:"sourceBucketID"`
+	DestinationBucketID ID                        `json:"destinationBucketID"`
+	Measurement         string                    `json:"measurement"`
+	Aggregate           MaterializedViewAggregate `json:"aggregate"`
+	Every               time.Duration             `json:"every"`
+	LateDataWindow      time.Duration             `json:"lateDataWindow"`
+	Status              Status                    `json:"status"`
+
+	// MaterializedFrom and MaterializedUntil bound the windows that have
+	// been materialized. They are zero until the view was first maintained.
+	MaterializedFrom  time.Time `json:"materializedFrom,omitempty"`
+	MaterializedUntil time.Time `json:"materializedUntil,omitempty"`
+	// StaleFrom is the start of the earliest materialized window that data
+	// was written to after it was materialized, or zero. The windows from
+	// StaleFrom are not read from the view until they are materialized again.
+	StaleFrom time.Time `json:"staleFrom,omitempty"`
+	// StaleMarks counts the writes that marked windows stale, so that the
+	// maintenance of the view does not clear the marks of the writes made
+	// while it materialized the windows again.
+	StaleMarks int64 `json:"staleMarks,omitempty"`
+	// LastRunError is the error of the last maintenance of the view, if it failed.
+	LastRunError string `json:"lastRunError,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the definition of the view is invalid.
+func (mv *MaterializedView) Valid() error {
+	switch {
+	case !mv.OrganizationID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view requires an organization",
+		}
+	case mv.Name == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view requires a name",
+		}
+	case !mv.SourceBucketID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view requires a source bucket",
+		}
+	case !mv.DestinationBucketID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view requires a destination bucket",
+		}
+	case mv.SourceBucketID == mv.DestinationBucketID:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view destination bucket must differ from its source bucket",
+		}
+	case mv.Measurement == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view requires a measurement",
+		}
+	case mv.Every <= 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view window must be positive",
+		}
+	case mv.LateDataWindow < 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "materialized view late data window must not be negative",
+		}
+	}
+	if err := mv.Aggregate.Valid(); err != nil {
+		return err
+	}
+	return mv.Status.Valid()
+}
+
+// Materialized reports whether any windows of the view have been materialized.
+func (mv *MaterializedView) Materialized() bool {
+	return mv.MaterializedUntil.After(mv.MaterializedFrom)
+}
+
+// FreshUntil returns the end of the materialized windows that are not stale.
+func (mv *MaterializedView) FreshUntil() time.Time {
+	if !mv.StaleFrom.IsZero() && mv.StaleFrom.Before(mv.MaterializedUntil) {
+		return mv.StaleFrom
+	}
+	return mv.MaterializedUntil
+}
+
+// MaterializedViewAggregate is the aggregate function of a materialized view.
+type MaterializedViewAggregate string
+
+// The aggregates a materialized view can maintain. They are named after the
+// Flux functions that compute them.
+const (
+	MaterializedViewMean  MaterializedViewAggregate = "mean"
+	MaterializedViewSum   MaterializedViewAggregate = "sum"
+	MaterializedViewCount MaterializedViewAggregate = "count"
+	MaterializedViewMin   MaterializedViewAggregate = "min"
+	MaterializedViewMax   MaterializedViewAggregate = "max"
+	MaterializedViewFirst MaterializedViewAggregate = "first"
+	MaterializedViewLast  MaterializedViewAggregate = "last"
+)
+
+// Valid returns an error if the aggregate is unknown.
+func (a MaterializedViewAggregate) Valid() error {
+	switch a {
+	case MaterializedViewMean, MaterializedViewSum, MaterializedViewCount,
+		MaterializedViewMin, MaterializedViewMax, MaterializedViewFirst, MaterializedViewLast:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid materialized view aggregate %q", a),
+		}
+	}
+}
+
+// MaterializedViewFilter represents a set of filters that restrict the
+// returned materialized views.
+type MaterializedViewFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	SourceBucketID *ID
+	Name           *string
+}
+
+// MaterializedViewUpdate represents updates to a materialized view. Only
+// fields which are set are updated. The definition of the aggregation cannot
+// be changed, as it would invalidate the materialized windows.
+type MaterializedViewUpdate struct {
+	Name           *string        `json:"name,omitempty"`
+	Description    *string        `json:"description,omitempty"`
+	Status         *Status        `json:"status,omitempty"`
+	LateDataWindow *time.Duration `json:"lateDataWindow,omitempty"`
+
+	// The progress of the maintenance of the view is not updated through the API.
+	MaterializedFrom  *time.Time `json:"-"`
+	MaterializedUntil *time.Time `json:"-"`
+	LastRunError      *string    `json:"-"`
+	// MarkStale marks the windows from the time stale, in addition to the
+	// windows already marked.
+	MarkStale *time.Time `json:"-"`
+	// ClearStale clears the stale windows if no windows were marked since
+	// the view had the StaleMarks.
+	ClearStale *int64 `json:"-"`
+}
+
+// Apply applies the update to the view.
+func (u MaterializedViewUpdate) Apply(mv *MaterializedView) {
+	if u.Name != nil {
+		mv.Name = *u.Name
+	}
+	if u.Description != nil {
+		mv.Description = *u.Description
+	}
+	if u.Status != nil {
+		mv.Status = *u.Status
+	}
+	if u.LateDataWindow != nil {
+		mv.LateDataWindow = *u.LateDataWindow
+	}
+	if u.MaterializedFrom != nil {
+		mv.MaterializedFrom = *u.MaterializedFrom
+	}
+	if u.MaterializedUntil != nil {
+		mv.MaterializedUntil = *u.MaterializedUntil
+	}
+	if u.LastRunError != nil {
+		mv.LastRunError = *u.LastRunError
+	}
+	if u.ClearStale != nil && *u.ClearStale == mv.StaleMarks {
+		mv.StaleFrom = time.Time{}
+	}
+	if u.MarkStale != nil {
+		if mv.StaleFrom.IsZero() || u.MarkStale.Before(mv.StaleFrom) {
+			mv.StaleFrom = *u.MarkStale
+		}
+		mv.StaleMarks++
+	}
+}
@@ -0,0 +1,302 @@
+package materializedview
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxWindowsPerQuery is the number of windows of a view that are
+// materialized by a query at most.
+const DefaultMaxWindowsPerQuery = 10000
+
+// Maintainer incrementally materializes the windows of the active
+// materialized views. A window is materialized once the late data window of
+// the view has passed after its end, and again once data written to it
+// afterwards marked it stale.
+type Maintainer struct {
+	Views         influxdb.MaterializedViewService
+	BucketService influxdb.BucketService
+	QueryService  query.QueryService
+
+	// ShardService, if set, is used to skip the windows outside of the shard
+	// groups of the source bucket holding data without querying them.
+	ShardService influxdb.ShardService
+	// Tracker, if set, is told the windows of the views that are
+	// materialized, so that it marks the windows written to stale.
+	Tracker *StaleWindowTracker
+	// MaxWindowsPerQuery bounds the windows materialized by a query, so that
+	// the windows of new views are materialized by several queries.
+	MaxWindowsPerQuery int
+
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewMaintainer returns a maintainer of the views of vs. The services must
+// not be authorized, as the maintainer acts on behalf of every organization.
+func NewMaintainer(vs influxdb.MaterializedViewService, bs influxdb.BucketService, qs query.QueryService) *Maintainer {
+	return &Maintainer{
+		Views:         vs,
+		BucketService: bs,
+		QueryService:  qs,
+
+		MaxWindowsPerQuery: DefaultMaxWindowsPerQuery,
+
+		logger: zap.NewNop(),
+		now:    time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the maintainer. It must be called before Run.
+func (m *Maintainer) WithLogger(l *zap.Logger) {
+	m.logger = l.With(zap.String("component", "materialized_views"))
+}
+
+// Run maintains the views immediately and then every interval until ctx is canceled.
+func (m *Maintainer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Maintain(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Error("Unable to maintain materialized views", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Maintain materializes the windows of all active views that have become
+// complete since they were last maintained. The error of a view is recorded
+// in the view and does not stop the maintenance of the others.
+func (m *Maintainer) Maintain(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	log, logEnd := logger.NewOperation(ctx, m.logger, "Materialized view maintenance", "materialized_view_maintenance")
+	defer logEnd()
+
+	views, _, err := m.Views.FindMaterializedViews(ctx, influxdb.MaterializedViewFilter{})
+	if err != nil {
+		return err
+	}
+	if m.Tracker != nil {
+		m.Tracker.setViews(views)
+	}
+
+	for _, mv := range views {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if mv.Status != influxdb.Active {
+			continue
+		}
+		if err := m.maintainView(ctx, mv); err != nil && ctx.Err() == nil {
+			log.Warn("Unable to maintain materialized view",
+				zap.Stringer("org_id", mv.OrganizationID),
+				zap.Stringer("materialized_view_id", mv.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (m *Maintainer) maintainView(ctx context.Context, mv *influxdb.MaterializedView) error {
+	now := m.now()
+	until := query.WindowStart(now.Add(-mv.LateDataWindow), mv.Every)
+
+	from := mv.MaterializedUntil
+	if from.IsZero() {
+		var err error
+		if from, err = m.firstWindow(ctx, mv, now); err != nil {
+			return m.recordError(ctx, mv, err)
+		}
+	}
+
+	// The stale windows are materialized again along with the new ones. The
+	// marks are cleared once they are, unless windows were marked meanwhile.
+	stale := !mv.StaleFrom.IsZero()
+	if stale && mv.StaleFrom.Before(from) {
+		from = mv.StaleFrom
+	}
+	if !until.After(from) {
+		return nil
+	}
+
+	// The windows are tracked before the shard groups are read, so that the
+	// points written to the windows that are skipped as empty mark them.
+	if m.Tracker != nil {
+		m.Tracker.materializing(mv, from, until)
+	}
+	var groups []*influxdb.ShardGroupStats
+	if m.ShardService != nil {
+		var err error
+		if groups, err = m.ShardService.FindShardGroupStats(ctx, mv.SourceBucketID); err != nil {
+			return m.recordError(ctx, mv, err)
+		}
+	}
+
+	max := m.MaxWindowsPerQuery
+	if max <= 0 {
+		max = DefaultMaxWindowsPerQuery
+	}
+	for start := from; start.Before(until); {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		stop := start.Add(time.Duration(max) * mv.Every)
+		if stop.After(until) || stop.Before(start) {
+			stop = until
+		}
+
+		// The windows before the next shard group holding data are empty,
+		// so they are materialized without a query.
+		if next := nextWindowWithData(groups, start, mv.Every); m.ShardService == nil || next.Equal(start) {
+			if err := m.materialize(ctx, mv, start, stop, now); err != nil {
+				return m.recordError(ctx, mv, err)
+			}
+		} else if !next.IsZero() && next.Before(until) {
+			stop = next
+		} else {
+			stop = until
+		}
+
+		upd := influxdb.MaterializedViewUpdate{}
+		if stop.After(mv.MaterializedUntil) {
+			upd.MaterializedUntil = &stop
+		}
+		if mv.MaterializedFrom.IsZero() {
+			upd.MaterializedFrom = &from
+		}
+		if stale && !stop.Before(mv.MaterializedUntil) {
+			upd.ClearStale = &mv.StaleMarks
+			stale = false
+		}
+		if mv.LastRunError != "" {
+			noError := ""
+			upd.LastRunError = &noError
+		}
+		updated, err := m.Views.UpdateMaterializedView(ctx, mv.ID, upd)
+		if err != nil {
+			return err
+		}
+		// The marks of the view read before the windows were materialized
+		// are kept, so that the marks made meanwhile are not cleared.
+		marks := mv.StaleMarks
+		mv = updated
+		mv.StaleMarks = marks
+		start = stop
+	}
+	return nil
+}
+
+// firstWindow returns the start of the first window to materialize when the
+// view is new: the one of the data the source bucket retains.
+func (m *Maintainer) firstWindow(ctx context.Context, mv *influxdb.MaterializedView, now time.Time) (time.Time, error) {
+	b, err := m.BucketService.FindBucketByID(ctx, mv.SourceBucketID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if b.RetentionPeriod > 0 {
+		return query.WindowStart(now.Add(-b.RetentionPeriod), mv.Every), nil
+	}
+	return time.Unix(0, 0).UTC(), nil
+}
+
+// nextWindowWithData returns the start of the first window at or after start
+// that overlaps one of the shard groups, which are sorted by time. It returns
+// the zero time if no shard group ends after start.
+func nextWindowWithData(groups []*influxdb.ShardGroupStats, start time.Time, every time.Duration) time.Time {
+	for _, g := range groups {
+		if !g.EndTime.After(start) {
+			continue
+		}
+		if first := query.WindowStart(g.StartTime, every); first.After(start) {
+			return first
+		}
+		return start
+	}
+	return time.Time{}
+}
+
+// materialize writes the windows of the view between from and until to its
+// destination bucket.
+func (m *Maintainer) materialize(ctx context.Context, mv *influxdb.MaterializedView, from, until, now time.Time) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// The maintainer is behind authorization, so it acts with a permission
+	// to read the source bucket and write the destination bucket of the view.
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     mv.ID,
+		OrgID:  mv.OrganizationID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &mv.OrganizationID,
+					ID:    &mv.SourceBucketID,
+				},
+			},
+			{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &mv.OrganizationID,
+					ID:    &mv.DestinationBucketID,
+				},
+			},
+		},
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	req := &query.Request{
+		Authorization:  auth,
+		OrganizationID: mv.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: maintenanceQuery(mv, from, until),
+			Now: now,
+		},
+	}
+	it, err := m.QueryService.Query(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(flux.ColReader) error { return nil })
+		})
+		if err != nil {
+			return err
+		}
+	}
+	it.Release()
+	return it.Err()
+}
+
+func (m *Maintainer) recordError(ctx context.Context, mv *influxdb.MaterializedView, err error) error {
+	msg := err.Error()
+	if _, uerr := m.Views.UpdateMaterializedView(ctx, mv.ID, influxdb.MaterializedViewUpdate{LastRunError: &msg}); uerr != nil {
+		m.logger.Info("Unable to record materialized view error",
+			zap.Stringer("materialized_view_id", mv.ID),
+			zap.Error(uerr))
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package materializedview
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// ProxyQueryService rewrites Flux queries to read materialized views before
+// they are performed by the wrapped query service. Queries that cannot be
+// rewritten are performed as they are.
+type ProxyQueryService struct {
+	query.ProxyQueryService
+	Rewriter *Rewriter
+
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewProxyQueryService returns a query service that rewrites the queries
+// performed by qs with r.
+func NewProxyQueryService(qs query.ProxyQueryService, r *Rewriter) *ProxyQueryService {
+	return &ProxyQueryService{
+		ProxyQueryService: qs,
+		Rewriter:          r,
+		logger:            zap.NewNop(),
+		now:               time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the service.
+func (s *ProxyQueryService) WithLogger(l *zap.Logger) {
+	s.logger = l.With(zap.String("component", "materialized_views"))
+}
+
+// Query performs the request, rewritten to read materialized views if possible.
+func (s *ProxyQueryService) Query(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	c, ok := req.Request.Compiler.(lang.FluxCompiler)
+	if !ok {
+		return s.ProxyQueryService.Query(ctx, w, req)
+	}
+
+	// The rewritten query fixes the times of the query, so they must be
+	// evaluated at the same time.
+	if c.Now.IsZero() {
+		c.Now = s.now()
+	}
+	q, rewritten, err := s.Rewriter.Rewrite(ctx, req.Request.Authorization, req.Request.OrganizationID, c.Now, c.Query, c.Extern)
+	if err != nil {
+		s.logger.Info("Unable to rewrite query to read materialized views", zap.Error(err))
+	}
+	if !rewritten {
+		return s.ProxyQueryService.Query(ctx, w, req)
+	}
+
+	span.LogKV("materialized_views", true)
+	c.Query = q
+	r := *req
+	r.Request.Compiler = c
+	return s.ProxyQueryService.Query(ctx, w, &r)
+}
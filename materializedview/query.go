@@ -0,0 +1,51 @@
+// Package materializedview maintains materialized views and rewrites queries
+// to read the windows materialized by them.
+package materializedview
+
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// maintenanceQuery returns the query that materializes the windows of the
+// view between from and until, which are aligned to its windows.
+func maintenanceQuery(mv *influxdb.MaterializedView, from, until time.Time) *ast.Package {
+	src := flux.Pipe(rangeExpr(fromExpr(mv.SourceBucketID), from, until),
+		flux.Call(flux.Identifier("filter"), flux.Object(
+			// (r) => r._measurement == m
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.Equal(flux.Member("r", "_measurement"), flux.String(mv.Measurement)))),
+		)),
+		flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+			flux.Property("every", flux.TimeDuration(mv.Every)),
+			flux.Property("fn", flux.Identifier(string(mv.Aggregate))),
+			flux.Property("createEmpty", flux.Bool(false)),
+			flux.Property("timeSrc", flux.String("_start")),
+		)),
+		flux.Call(flux.Identifier("to"), flux.Object(
+			flux.Property("bucketID", flux.String(mv.DestinationBucketID.String())),
+			flux.Property("orgID", flux.String(mv.OrganizationID.String())),
+		)),
+	)
+
+	return &ast.Package{
+		Package: "main",
+		Files: []*ast.File{{
+			Body: []ast.Statement{flux.ExpressionStatement(src)},
+		}},
+	}
+}
+
+func fromExpr(bucketID influxdb.ID) *ast.CallExpression {
+	return flux.Call(flux.Identifier("from"), flux.Object(flux.Property("bucketID", flux.String(bucketID.String()))))
+}
+
+func rangeExpr(src ast.Expression, start, stop time.Time) *ast.PipeExpression {
+	return flux.Pipe(src, flux.Call(flux.Identifier("range"), flux.Object(
+		flux.Property("start", &ast.DateTimeLiteral{Value: start.UTC()}),
+		flux.Property("stop", &ast.DateTimeLiteral{Value: stop.UTC()}),
+	)))
+}
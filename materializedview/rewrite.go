@@ -0,0 +1,667 @@
+package materializedview
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+	"github.com/influxdata/influxdb/query"
+)
+
+// Rewriter rewrites queries that aggregate a measurement of a bucket with
+// aggregateWindow to read the windows materialized by a view instead of the
+// bucket. It rewrites pipelines of the form
+//
+//	from(bucket: "b") |> range(start: s, stop: e) |> filter(fn: f)... |> aggregateWindow(every: w, fn: a)
+//
+// where the filters restrict the measurement to that of a view of the bucket
+// with aggregate a, and do not use the values or times of the points. The
+// window of the view must be w, or divide w if a is not mean, in which case
+// the windows of the view are aggregated again into the windows of the
+// query. When several views of different windows match, the one with the
+// longest window is read, so that queries of long ranges, which aggregate
+// long windows, read few points, while queries of short windows read the
+// views of short windows or the bucket.
+//
+// The windows of the range that are materialized and not stale are read from
+// the view and the others from the bucket, so the results are those of the
+// original query.
+//
+// Queries opt out of reading views with
+//
+//	option materializedViews = {enabled: false}
+type Rewriter struct {
+	Views         influxdb.MaterializedViewService
+	BucketService influxdb.BucketService
+}
+
+// Rewrite returns the query rewritten to read the views of the organization
+// that the authorization may read, and whether it was rewritten. The query
+// is evaluated at now, and extern holds options of the query.
+func (r *Rewriter) Rewrite(ctx context.Context, auth *influxdb.Authorization, orgID influxdb.ID, now time.Time, q string, extern *ast.File) (string, bool, error) {
+	if auth == nil {
+		return q, false, nil
+	}
+
+	views, _, err := r.Views.FindMaterializedViews(ctx, influxdb.MaterializedViewFilter{OrganizationID: &orgID})
+	if err != nil {
+		return q, false, err
+	}
+	views = usableViews(views, auth)
+	if len(views) == 0 {
+		return q, false, nil
+	}
+
+	pkg := parser.ParseSource(q)
+	if ast.Check(pkg) > 0 {
+		// Leave it to the compiler to report the errors.
+		return q, false, nil
+	}
+
+	rw := &rewriter{
+		ctx:     ctx,
+		buckets: r.BucketService,
+		orgID:   orgID,
+		views:   views,
+		now:     now,
+		options: make(map[string]ast.Expression),
+	}
+	if !rw.collectOptions(extern) {
+		return q, false, nil
+	}
+	for _, f := range pkg.Files {
+		if !rw.collectOptions(f) {
+			return q, false, nil
+		}
+	}
+	if enabled, ok := rw.options["materializedViews.enabled"]; ok {
+		if b, ok := boolValue(enabled); !ok || !b {
+			return q, false, nil
+		}
+	}
+
+	for _, f := range pkg.Files {
+		for _, s := range f.Body {
+			switch s := s.(type) {
+			case *ast.ExpressionStatement:
+				s.Expression = rw.rewrite(s.Expression)
+			case *ast.VariableAssignment:
+				s.Init = rw.rewrite(s.Init)
+			}
+		}
+	}
+	if rw.err != nil {
+		return q, false, rw.err
+	}
+	if rw.rewritten == 0 {
+		return q, false, nil
+	}
+	return ast.Format(pkg), true, nil
+}
+
+// usableViews returns the views that have materialized windows and whose
+// source and destination buckets may be read with auth.
+func usableViews(views []*influxdb.MaterializedView, auth *influxdb.Authorization) []*influxdb.MaterializedView {
+	usable := views[:0]
+	for _, mv := range views {
+		if mv.Status != influxdb.Active || !mv.Materialized() {
+			continue
+		}
+		if !canReadBucket(auth, mv.OrganizationID, mv.SourceBucketID) ||
+			!canReadBucket(auth, mv.OrganizationID, mv.DestinationBucketID) {
+			continue
+		}
+		usable = append(usable, mv)
+	}
+	return usable
+}
+
+func canReadBucket(auth *influxdb.Authorization, orgID, bucketID influxdb.ID) bool {
+	p, err := influxdb.NewPermissionAtID(bucketID, influxdb.ReadAction, influxdb.BucketsResourceType, orgID)
+	if err != nil {
+		return false
+	}
+	return auth.Allowed(*p)
+}
+
+type rewriter struct {
+	ctx     context.Context
+	buckets influxdb.BucketService
+	orgID   influxdb.ID
+	views   []*influxdb.MaterializedView
+	now     time.Time
+
+	// options maps the properties of object options, such as
+	// v.timeRangeStart, to their values.
+	options map[string]ast.Expression
+
+	rewritten int
+	err       error
+}
+
+// collectOptions records the object options of f. It returns false if f sets
+// an option that changes how times are evaluated.
+func (rw *rewriter) collectOptions(f *ast.File) bool {
+	if f == nil {
+		return true
+	}
+	for _, s := range f.Body {
+		opt, ok := s.(*ast.OptionStatement)
+		if !ok {
+			continue
+		}
+		va, ok := opt.Assignment.(*ast.VariableAssignment)
+		if !ok {
+			continue
+		}
+		if va.ID.Name == "now" {
+			return false
+		}
+		obj, ok := va.Init.(*ast.ObjectExpression)
+		if !ok {
+			continue
+		}
+		for _, p := range obj.Properties {
+			rw.options[va.ID.Name+"."+p.Key.Key()] = p.Value
+		}
+	}
+	return true
+}
+
+// rewrite returns e with the pipelines it contains rewritten.
+func (rw *rewriter) rewrite(e ast.Expression) ast.Expression {
+	switch e := e.(type) {
+	case *ast.PipeExpression:
+		if r := rw.rewritePipeline(e); r != nil {
+			rw.rewritten++
+			return r
+		}
+		e.Argument = rw.rewrite(e.Argument)
+		rw.rewriteCall(e.Call)
+	case *ast.CallExpression:
+		rw.rewriteCall(e)
+	case *ast.ArrayExpression:
+		for i, el := range e.Elements {
+			e.Elements[i] = rw.rewrite(el)
+		}
+	case *ast.ObjectExpression:
+		for _, p := range e.Properties {
+			p.Value = rw.rewrite(p.Value)
+		}
+	}
+	return e
+}
+
+func (rw *rewriter) rewriteCall(c *ast.CallExpression) {
+	for i, arg := range c.Arguments {
+		c.Arguments[i] = rw.rewrite(arg)
+	}
+}
+
+// pipeline is a pipeline that aggregates windows of a measurement.
+type pipeline struct {
+	from        *ast.CallExpression
+	start, stop time.Time
+	filters     []*ast.CallExpression
+	measurement string
+	every       time.Duration
+	aggregate   influxdb.MaterializedViewAggregate
+	createEmpty *ast.Property
+}
+
+// rewritePipeline returns the rewritten pipeline of p, or nil if it cannot be
+// rewritten.
+func (rw *rewriter) rewritePipeline(p *ast.PipeExpression) ast.Expression {
+	pl, ok := rw.matchPipeline(p)
+	if !ok {
+		return nil
+	}
+
+	b, ok := rw.findBucket(pl.from)
+	if !ok {
+		return nil
+	}
+
+	mv := rw.selectView(b.ID, pl)
+	if mv == nil {
+		return nil
+	}
+	// Counts of empty windows are null in the view and 0 in the bucket.
+	if pl.aggregate == influxdb.MaterializedViewCount && (pl.createEmpty == nil || isTrue(pl.createEmpty.Value)) {
+		return nil
+	}
+
+	// The windows of the query that are entirely in the range and
+	// materialized. The windows of the view are aligned to those of the
+	// query, as its window divides the one of the query.
+	viewStart := query.WindowStart(pl.start.Add(pl.every-1), pl.every)
+	if from := query.WindowStart(mv.MaterializedFrom.Add(pl.every-1), pl.every); viewStart.Before(from) {
+		viewStart = from
+	}
+	viewStop := query.WindowStart(pl.stop, pl.every)
+	if until := query.WindowStart(mv.FreshUntil(), pl.every); viewStop.After(until) {
+		viewStop = until
+	}
+	if !viewStop.After(viewStart) {
+		return nil
+	}
+
+	var pieces []ast.Expression
+	if pl.start.Before(viewStart) {
+		pieces = append(pieces, pl.build(pl.from, pl.start, viewStart, pl.aggregate))
+	}
+	// Each window of the view is a single point, which the aggregate of the
+	// query aggregates again into the windows of the query, except for counts
+	// that are summed.
+	fn := pl.aggregate
+	if fn == influxdb.MaterializedViewCount {
+		fn = influxdb.MaterializedViewSum
+	}
+	pieces = append(pieces, pl.build(fromExpr(mv.DestinationBucketID), viewStart, viewStop, fn))
+	if viewStop.Before(pl.stop) {
+		pieces = append(pieces, pl.build(pl.from, viewStop, pl.stop, pl.aggregate))
+	}
+	if len(pieces) == 1 {
+		return pieces[0]
+	}
+
+	// The bounds of the union are those of the range of the query, which
+	// window restores as the start and stop of the tables.
+	return flux.Pipe(flux.Call(flux.Identifier("union"), flux.Object(flux.Property("tables", flux.Array(pieces...)))),
+		flux.Call(flux.Identifier("window"), flux.Object(
+			flux.Property("every", flux.Identifier("inf")),
+			flux.Property("timeColumn", flux.String("_time")),
+		)),
+		flux.Call(flux.Identifier("sort"), flux.Object(flux.Property("columns", flux.Array(flux.String("_time"))))),
+	)
+}
+
+// selectView returns the view of the bucket with the longest window that the
+// windows of the pipeline may be computed from, or nil if there is none.
+func (rw *rewriter) selectView(bucketID influxdb.ID, pl *pipeline) *influxdb.MaterializedView {
+	var selected *influxdb.MaterializedView
+	for _, v := range rw.views {
+		if v.SourceBucketID != bucketID || v.Measurement != pl.measurement || v.Aggregate != pl.aggregate {
+			continue
+		}
+		// The mean of the means of windows is not the mean of their points.
+		if pl.every%v.Every != 0 || (v.Every != pl.every && v.Aggregate == influxdb.MaterializedViewMean) {
+			continue
+		}
+		if selected == nil || v.Every > selected.Every {
+			selected = v
+		}
+	}
+	return selected
+}
+
+// build returns the pipeline aggregating the windows of src between start and stop with fn.
+func (pl *pipeline) build(src *ast.CallExpression, start, stop time.Time, fn influxdb.MaterializedViewAggregate) ast.Expression {
+	var e ast.Expression = rangeExpr(src, start, stop)
+	for _, f := range pl.filters {
+		e = flux.Pipe(e, f)
+	}
+	props := []*ast.Property{
+		flux.Property("every", flux.TimeDuration(pl.every)),
+		flux.Property("fn", &ast.Identifier{Name: string(fn)}),
+	}
+	if pl.createEmpty != nil {
+		props = append(props, pl.createEmpty)
+	}
+	return flux.Pipe(e, flux.Call(flux.Identifier("aggregateWindow"), flux.Object(props...)))
+}
+
+// matchPipeline matches p against the pipelines that may be rewritten.
+func (rw *rewriter) matchPipeline(p *ast.PipeExpression) (*pipeline, bool) {
+	pl := &pipeline{}
+
+	props, ok := callProperties(p.Call, "aggregateWindow")
+	if !ok {
+		return nil, false
+	}
+	for _, prop := range props {
+		switch prop.Key.Key() {
+		case "every":
+			if pl.every, ok = rw.evalDuration(prop.Value, 0); !ok || pl.every <= 0 {
+				return nil, false
+			}
+		case "fn":
+			id, ok := prop.Value.(*ast.Identifier)
+			if !ok {
+				return nil, false
+			}
+			pl.aggregate = influxdb.MaterializedViewAggregate(id.Name)
+			if pl.aggregate.Valid() != nil {
+				return nil, false
+			}
+		case "createEmpty":
+			if _, ok := boolValue(prop.Value); !ok {
+				return nil, false
+			}
+			pl.createEmpty = prop
+		default:
+			return nil, false
+		}
+	}
+	if pl.every == 0 || pl.aggregate == "" {
+		return nil, false
+	}
+
+	// The filters are in reverse order as the pipeline is walked from its end.
+	var filters []*ast.CallExpression
+	src := p.Argument
+	for {
+		fp, ok := src.(*ast.PipeExpression)
+		if !ok {
+			return nil, false
+		}
+		props, ok := callProperties(fp.Call, "filter")
+		if !ok {
+			break
+		}
+		if len(props) != 1 || props[0].Key.Key() != "fn" {
+			return nil, false
+		}
+		m, ok := filterMeasurement(props[0].Value)
+		if !ok {
+			return nil, false
+		}
+		if m != "" {
+			if pl.measurement != "" && pl.measurement != m {
+				return nil, false
+			}
+			pl.measurement = m
+		}
+		filters = append(filters, fp.Call)
+		src = fp.Argument
+	}
+	if pl.measurement == "" {
+		return nil, false
+	}
+	for i := len(filters) - 1; i >= 0; i-- {
+		pl.filters = append(pl.filters, filters[i])
+	}
+
+	rp := src.(*ast.PipeExpression)
+	props, ok = callProperties(rp.Call, "range")
+	if !ok {
+		return nil, false
+	}
+	pl.stop = rw.now
+	var hasStart bool
+	for _, prop := range props {
+		switch prop.Key.Key() {
+		case "start":
+			pl.start, hasStart = rw.evalTime(prop.Value, 0)
+		case "stop":
+			if pl.stop, ok = rw.evalTime(prop.Value, 0); !ok {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	if !hasStart || !pl.stop.After(pl.start) {
+		return nil, false
+	}
+
+	fc, ok := rp.Argument.(*ast.CallExpression)
+	if !ok {
+		return nil, false
+	}
+	props, ok = callProperties(fc, "from")
+	if !ok || len(props) != 1 {
+		return nil, false
+	}
+	switch props[0].Key.Key() {
+	case "bucket", "bucketID":
+		if _, ok := props[0].Value.(*ast.StringLiteral); !ok {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	pl.from = fc
+	return pl, true
+}
+
+// findBucket returns the bucket that the from call reads.
+func (rw *rewriter) findBucket(from *ast.CallExpression) (*influxdb.Bucket, bool) {
+	props, _ := callProperties(from, "from")
+	name := props[0].Value.(*ast.StringLiteral).Value
+
+	filter := influxdb.BucketFilter{OrganizationID: &rw.orgID}
+	if props[0].Key.Key() == "bucketID" {
+		id, err := influxdb.IDFromString(name)
+		if err != nil {
+			return nil, false
+		}
+		filter.ID = id
+	} else {
+		filter.Name = &name
+	}
+
+	b, err := rw.buckets.FindBucket(rw.ctx, filter)
+	if err != nil {
+		if influxdb.ErrorCode(err) != influxdb.ENotFound && rw.err == nil {
+			rw.err = err
+		}
+		return nil, false
+	}
+	return b, true
+}
+
+// callProperties returns the properties of the arguments of c if it calls
+// the function name.
+func callProperties(c *ast.CallExpression, name string) ([]*ast.Property, bool) {
+	id, ok := c.Callee.(*ast.Identifier)
+	if !ok || id.Name != name {
+		return nil, false
+	}
+	switch len(c.Arguments) {
+	case 0:
+		return nil, true
+	case 1:
+		obj, ok := c.Arguments[0].(*ast.ObjectExpression)
+		if !ok || obj.With != nil {
+			return nil, false
+		}
+		return obj.Properties, true
+	default:
+		return nil, false
+	}
+}
+
+// filterMeasurement returns the measurement the filter function restricts
+// the data to, if any. It returns false if the function uses the values or
+// times of the points, or is not a simple predicate.
+func filterMeasurement(e ast.Expression) (string, bool) {
+	fn, ok := e.(*ast.FunctionExpression)
+	if !ok || len(fn.Params) != 1 || fn.Params[0].Value != nil {
+		return "", false
+	}
+	body, ok := fn.Body.(ast.Expression)
+	if !ok {
+		return "", false
+	}
+	param := fn.Params[0].Key.Key()
+	if !onlyUsesTags(body, param) {
+		return "", false
+	}
+
+	var measurement string
+	var conjuncts func(e ast.Expression)
+	conjuncts = func(e ast.Expression) {
+		switch e := e.(type) {
+		case *ast.LogicalExpression:
+			if e.Operator == ast.AndOperator {
+				conjuncts(e.Left)
+				conjuncts(e.Right)
+			}
+		case *ast.BinaryExpression:
+			if e.Operator != ast.EqualOperator {
+				return
+			}
+			if m, ok := measurementEquals(e.Left, e.Right, param); ok {
+				measurement = m
+			} else if m, ok := measurementEquals(e.Right, e.Left, param); ok {
+				measurement = m
+			}
+		}
+	}
+	conjuncts(body)
+	return measurement, true
+}
+
+func measurementEquals(col, value ast.Expression, param string) (string, bool) {
+	if name, ok := column(col, param); !ok || name != "_measurement" {
+		return "", false
+	}
+	s, ok := value.(*ast.StringLiteral)
+	if !ok {
+		return "", false
+	}
+	return s.Value, true
+}
+
+// column returns the name of the column of the record param that e accesses.
+func column(e ast.Expression, param string) (string, bool) {
+	m, ok := e.(*ast.MemberExpression)
+	if !ok {
+		return "", false
+	}
+	if id, ok := m.Object.(*ast.Identifier); !ok || id.Name != param {
+		return "", false
+	}
+	return m.Property.Key(), true
+}
+
+// onlyUsesTags reports whether e only compares the columns of the group key
+// of the record param that the views keep, with literals.
+func onlyUsesTags(e ast.Expression, param string) bool {
+	switch e := e.(type) {
+	case *ast.LogicalExpression:
+		return onlyUsesTags(e.Left, param) && onlyUsesTags(e.Right, param)
+	case *ast.BinaryExpression:
+		return onlyUsesTags(e.Left, param) && onlyUsesTags(e.Right, param)
+	case *ast.UnaryExpression:
+		return onlyUsesTags(e.Argument, param)
+	case *ast.MemberExpression:
+		name, ok := column(e, param)
+		if !ok {
+			return false
+		}
+		switch name {
+		case "_value", "_time", "_start", "_stop":
+			return false
+		}
+		return true
+	case *ast.StringLiteral, *ast.RegexpLiteral, *ast.BooleanLiteral,
+		*ast.IntegerLiteral, *ast.UnsignedIntegerLiteral, *ast.FloatLiteral:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxOptionDepth limits how many options are followed to evaluate a value.
+const maxOptionDepth = 4
+
+// evalTime evaluates the time e is relative to the time of the query.
+func (rw *rewriter) evalTime(e ast.Expression, depth int) (time.Time, bool) {
+	switch e := e.(type) {
+	case *ast.DateTimeLiteral:
+		return e.Value, true
+	case *ast.IntegerLiteral:
+		return time.Unix(e.Value, 0), true
+	case *ast.CallExpression:
+		if id, ok := e.Callee.(*ast.Identifier); ok && id.Name == "now" && len(e.Arguments) == 0 {
+			return rw.now, true
+		}
+	case *ast.MemberExpression:
+		if v, ok := rw.option(e, depth); ok {
+			return rw.evalTime(v, depth+1)
+		}
+	case *ast.DurationLiteral, *ast.UnaryExpression:
+		if d, ok := rw.evalDuration(e, depth); ok {
+			return rw.now.Add(d), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// evalDuration evaluates a duration of fixed length.
+func (rw *rewriter) evalDuration(e ast.Expression, depth int) (time.Duration, bool) {
+	switch e := e.(type) {
+	case *ast.DurationLiteral:
+		var d time.Duration
+		for _, v := range e.Values {
+			unit, ok := durationUnits[v.Unit]
+			if !ok {
+				return 0, false
+			}
+			d += time.Duration(v.Magnitude) * unit
+		}
+		return d, true
+	case *ast.UnaryExpression:
+		if e.Operator != ast.SubtractionOperator {
+			return 0, false
+		}
+		d, ok := rw.evalDuration(e.Argument, depth)
+		return -d, ok
+	case *ast.MemberExpression:
+		if v, ok := rw.option(e, depth); ok {
+			return rw.evalDuration(v, depth+1)
+		}
+	}
+	return 0, false
+}
+
+// durationUnits are the units of durations of fixed length.
+var durationUnits = map[string]time.Duration{
+	ast.NanosecondUnit:  time.Nanosecond,
+	ast.MicrosecondUnit: time.Microsecond,
+	ast.MillisecondUnit: time.Millisecond,
+	ast.SecondUnit:      time.Second,
+	ast.MinuteUnit:      time.Minute,
+	ast.HourUnit:        time.Hour,
+	ast.DayUnit:         24 * time.Hour,
+	ast.WeekUnit:        7 * 24 * time.Hour,
+}
+
+func (rw *rewriter) option(e *ast.MemberExpression, depth int) (ast.Expression, bool) {
+	if depth >= maxOptionDepth {
+		return nil, false
+	}
+	id, ok := e.Object.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+	v, ok := rw.options[id.Name+"."+e.Property.Key()]
+	return v, ok
+}
+
+// boolValue returns the value of a boolean literal, which may be parsed as
+// the identifiers true and false.
+func boolValue(e ast.Expression) (bool, bool) {
+	switch e := e.(type) {
+	case *ast.BooleanLiteral:
+		return e.Value, true
+	case *ast.Identifier:
+		switch e.Name {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+func isTrue(e ast.Expression) bool {
+	b, _ := boolValue(e)
+	return b
+}
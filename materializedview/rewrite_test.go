@@ -0,0 +1,280 @@
+package materializedview
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestRewriter_Rewrite(t *testing.T) {
+	const (
+		orgID    influxdb.ID = 1
+		sourceID influxdb.ID = 2
+		destID   influxdb.ID = 3
+	)
+	now := time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+	view := &influxdb.MaterializedView{
+		ID:                  10,
+		OrganizationID:      orgID,
+		SourceBucketID:      sourceID,
+		DestinationBucketID: destID,
+		Measurement:         "cpu",
+		Aggregate:           influxdb.MaterializedViewMean,
+		Every:               time.Minute,
+		Status:              influxdb.Active,
+		MaterializedFrom:    now.Add(-24 * time.Hour),
+		MaterializedUntil:   now.Add(-5 * time.Minute),
+	}
+	maxMinutely := *view
+	maxMinutely.ID, maxMinutely.DestinationBucketID, maxMinutely.Aggregate = 11, 4, influxdb.MaterializedViewMax
+	maxHourly := maxMinutely
+	maxHourly.ID, maxHourly.DestinationBucketID, maxHourly.Every = 12, 5, time.Hour
+	maxHourly.MaterializedFrom, maxHourly.MaterializedUntil = now.Add(-30*24*time.Hour), now.Add(-time.Hour)
+
+	views := mock.NewMaterializedViewService()
+	views.FindMaterializedViewsFn = func(context.Context, influxdb.MaterializedViewFilter, ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+		var vs []*influxdb.MaterializedView
+		for _, v := range []influxdb.MaterializedView{*view, maxMinutely, maxHourly} {
+			v := v
+			vs = append(vs, &v)
+		}
+		return vs, len(vs), nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(_ context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		if (filter.Name != nil && *filter.Name == "telegraf") || (filter.ID != nil && *filter.ID == sourceID) {
+			return &influxdb.Bucket{ID: sourceID, OrgID: orgID, Name: "telegraf"}, nil
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound}
+	}
+	rw := &Rewriter{Views: views, BucketService: buckets}
+
+	readAll := &influxdb.Authorization{
+		Status: influxdb.Active,
+		Permissions: []influxdb.Permission{{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: idPtr(orgID)},
+		}},
+	}
+	readSource := &influxdb.Authorization{
+		Status: influxdb.Active,
+		Permissions: []influxdb.Permission{{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: idPtr(orgID), ID: idPtr(sourceID)},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		extern string
+		auth   *influxdb.Authorization
+		// want are the ranges of the pipelines of the rewritten query, or
+		// nil if it is not rewritten.
+		want []string
+	}{
+		{
+			name: "view and bucket",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" and r.host == "a")
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> yield(name: "mean")`,
+			auth: readAll,
+			want: []string{
+				`from(bucketID: "0000000000000003") |> range(start: 2019-12-01T11:00:00Z, stop: 2019-12-01T11:55:00Z)`,
+				`from(bucket: "telegraf") |> range(start: 2019-12-01T11:55:00Z, stop: 2019-12-01T12:00:00Z)`,
+			},
+		},
+		{
+			name: "unaligned range",
+			query: `from(bucketID: "0000000000000002")
+	|> range(start: 2019-12-01T10:00:30Z, stop: 2019-12-01T11:00:30Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: mean, createEmpty: false)`,
+			auth: readAll,
+			want: []string{
+				`from(bucketID: "0000000000000002") |> range(start: 2019-12-01T10:00:30Z, stop: 2019-12-01T10:01:00Z)`,
+				`from(bucketID: "0000000000000003") |> range(start: 2019-12-01T10:01:00Z, stop: 2019-12-01T11:00:00Z)`,
+				`from(bucketID: "0000000000000002") |> range(start: 2019-12-01T11:00:00Z, stop: 2019-12-01T11:00:30Z)`,
+			},
+		},
+		{
+			name: "options",
+			query: `from(bucket: "telegraf")
+	|> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: v.windowPeriod, fn: mean)`,
+			extern: `option v = {timeRangeStart: -2h, timeRangeStop: -1h, windowPeriod: 60000ms}`,
+			auth:   readAll,
+			want: []string{
+				`from(bucketID: "0000000000000003") |> range(start: 2019-12-01T10:00:00Z, stop: 2019-12-01T11:00:00Z)`,
+			},
+		},
+		{
+			name: "destination bucket not readable",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			auth: readSource,
+		},
+		{
+			name: "value filter",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._value > 0.0)
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			auth: readAll,
+		},
+		{
+			name: "other measurement",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "mem")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			auth: readAll,
+		},
+		{
+			name: "multiple of mean window",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 5m, fn: mean)`,
+			auth: readAll,
+		},
+		{
+			name: "other aggregate",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: min)`,
+			auth: readAll,
+		},
+		{
+			name: "multiple of window",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 2m, fn: max)`,
+			auth: readAll,
+			want: []string{
+				`from(bucketID: "0000000000000004") |> range(start: 2019-12-01T11:00:00Z, stop: 2019-12-01T11:54:00Z)`,
+				`from(bucket: "telegraf") |> range(start: 2019-12-01T11:54:00Z, stop: 2019-12-01T12:00:00Z)`,
+			},
+		},
+		{
+			name: "longest window",
+			query: `from(bucket: "telegraf")
+	|> range(start: -7d)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1d, fn: max)`,
+			auth: readAll,
+			want: []string{
+				`from(bucket: "telegraf") |> range(start: 2019-11-24T12:00:00Z, stop: 2019-11-25T00:00:00Z)`,
+				`from(bucketID: "0000000000000005") |> range(start: 2019-11-25T00:00:00Z, stop: 2019-12-01T00:00:00Z)`,
+				`from(bucket: "telegraf") |> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-01T12:00:00Z)`,
+			},
+		},
+		{
+			name: "opt out",
+			query: `option materializedViews = {enabled: false}
+
+from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			auth: readAll,
+		},
+		{
+			name: "opt out in extern",
+			query: `from(bucket: "telegraf")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			extern: `option materializedViews = {enabled: false}`,
+			auth:   readAll,
+		},
+		{
+			name: "not materialized range",
+			query: `from(bucket: "telegraf")
+	|> range(start: -3m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> aggregateWindow(every: 1m, fn: mean)`,
+			auth: readAll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var extern *ast.File
+			if tt.extern != "" {
+				extern = parser.ParseSource(tt.extern).Files[0]
+			}
+
+			got, rewritten, err := rw.Rewrite(context.Background(), tt.auth, orgID, now, tt.query, extern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rewritten != (tt.want != nil) {
+				t.Fatalf("got rewritten %v, want %v:\n%s", rewritten, tt.want != nil, got)
+			}
+			if !rewritten {
+				if got != tt.query {
+					t.Fatalf("query was modified although it was not rewritten:\n%s", got)
+				}
+				return
+			}
+
+			if n := ast.Check(parser.ParseSource(got)); n > 0 {
+				t.Fatalf("rewritten query has %d errors:\n%s", n, got)
+			}
+			got = normalize(got)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("rewritten query does not contain %q:\n%s", w, got)
+				}
+			}
+			if union := strings.Contains(got, "union("); union != (len(tt.want) > 1) {
+				t.Errorf("got union %v, want %v:\n%s", union, len(tt.want) > 1, got)
+			}
+		})
+	}
+}
+
+func TestMaintenanceQuery(t *testing.T) {
+	mv := &influxdb.MaterializedView{
+		OrganizationID:      1,
+		SourceBucketID:      2,
+		DestinationBucketID: 3,
+		Measurement:         `c"pu`,
+		Aggregate:           influxdb.MaterializedViewCount,
+		Every:               90 * time.Second,
+	}
+	from := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	got := ast.Format(maintenanceQuery(mv, from, from.Add(time.Hour)))
+	want := ast.Format(parser.ParseSource(`from(bucketID: "0000000000000002")
+	|> range(start: 2019-12-01T00:00:00Z, stop: 2019-12-01T01:00:00Z)
+	|> filter(fn: (r) => r._measurement == "c\"pu")
+	|> aggregateWindow(every: 90000000000ns, fn: count, createEmpty: false, timeSrc: "_start")
+	|> to(bucketID: "0000000000000003", orgID: "0000000000000001")`))
+	if got != want {
+		t.Errorf("unexpected maintenance query:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// normalize removes the package clause and the line breaks of a formatted query.
+func normalize(q string) string {
+	q = strings.TrimPrefix(q, "package main\n")
+	return strings.Join(strings.Fields(q), " ")
+}
+
+func idPtr(id influxdb.ID) *influxdb.ID {
+	return &id
+}
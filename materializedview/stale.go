@@ -0,0 +1,185 @@
+package materializedview
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// StaleWindowTracker marks the windows of views stale when points are
+// written to them after they were materialized. The windows materialized by
+// the views are those the maintainer last reported, so writes are checked
+// without reading the views.
+type StaleWindowTracker struct {
+	Views influxdb.MaterializedViewService
+
+	mu    sync.Mutex
+	views map[influxdb.ID][]*trackedView // by source bucket
+}
+
+// trackedView are the materialized windows of a view.
+type trackedView struct {
+	id          influxdb.ID
+	measurement []byte
+	every       time.Duration
+	from, until time.Time
+	// staleFrom is the earliest window marked stale by the tracker.
+	staleFrom time.Time
+}
+
+// NewStaleWindowTracker returns a tracker marking the windows of the views
+// of vs stale. The service must not be authorized, as points are written to
+// the views of every organization.
+func NewStaleWindowTracker(vs influxdb.MaterializedViewService) *StaleWindowTracker {
+	return &StaleWindowTracker{
+		Views: vs,
+		views: make(map[influxdb.ID][]*trackedView),
+	}
+}
+
+// PointsWriter returns a PointsWriter writing points to w, after it marked
+// the windows the points are written to stale. The points must be exploded.
+func (t *StaleWindowTracker) PointsWriter(w storage.PointsWriter) storage.PointsWriter {
+	return &staleWindowPointsWriter{w: w, t: t}
+}
+
+type staleWindowPointsWriter struct {
+	w storage.PointsWriter
+	t *StaleWindowTracker
+}
+
+func (w *staleWindowPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	if err := w.t.Track(ctx, points); err != nil {
+		return err
+	}
+	return w.w.WritePoints(ctx, points)
+}
+
+// Track marks the windows of the views the points are written to stale. The
+// windows are marked before the points are written, so that no query reads
+// a window of a view that does not reflect the points of its bucket.
+func (t *StaleWindowTracker) Track(ctx context.Context, points []models.Point) error {
+	stale := t.staleWindows(points)
+	for id, from := range stale {
+		from := from
+		_, err := t.Views.UpdateMaterializedView(ctx, id, influxdb.MaterializedViewUpdate{MarkStale: &from})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staleWindows returns the start of the earliest window the points make
+// stale by view, and records them as marked.
+func (t *StaleWindowTracker) staleWindows(points []models.Point) map[influxdb.ID]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.views) == 0 {
+		return nil
+	}
+
+	var stale map[influxdb.ID]time.Time
+	for _, p := range points {
+		name := p.Name()
+		if len(name) != len(tsdb.EncodeName(0, 0)) {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+		views := t.views[bucketID]
+		if len(views) == 0 {
+			continue
+		}
+
+		var m []byte
+		p.ForEachTag(func(k, v []byte) bool {
+			if bytes.Equal(k, models.MeasurementTagKeyBytes) {
+				m = v
+			}
+			return false
+		})
+		ts := p.Time()
+		for _, v := range views {
+			if !bytes.Equal(m, v.measurement) || ts.Before(v.from) || !ts.Before(v.until) {
+				continue
+			}
+			start := query.WindowStart(ts, v.every)
+			if !v.staleFrom.IsZero() && !start.Before(v.staleFrom) {
+				continue
+			}
+			v.staleFrom = start
+			if stale == nil {
+				stale = make(map[influxdb.ID]time.Time)
+			}
+			if from, ok := stale[v.id]; !ok || start.Before(from) {
+				stale[v.id] = start
+			}
+		}
+	}
+	return stale
+}
+
+// setViews replaces the views tracked with the active views.
+func (t *StaleWindowTracker) setViews(views []*influxdb.MaterializedView) {
+	byBucket := make(map[influxdb.ID][]*trackedView)
+	for _, mv := range views {
+		if mv.Status != influxdb.Active || !mv.Materialized() {
+			continue
+		}
+		byBucket[mv.SourceBucketID] = append(byBucket[mv.SourceBucketID], &trackedView{
+			id:          mv.ID,
+			measurement: []byte(mv.Measurement),
+			every:       mv.Every,
+			from:        mv.MaterializedFrom,
+			until:       mv.MaterializedUntil,
+			staleFrom:   mv.StaleFrom,
+		})
+	}
+
+	t.mu.Lock()
+	t.views = byBucket
+	t.mu.Unlock()
+}
+
+// materializing tracks the windows of the view between from and until,
+// before they are materialized, so that the points written to them while
+// they are materialized, or after they were skipped as empty, mark them stale.
+func (t *StaleWindowTracker) materializing(mv *influxdb.MaterializedView, from, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, v := range t.views[mv.SourceBucketID] {
+		if v.id != mv.ID {
+			continue
+		}
+		if from.Before(v.from) {
+			v.from = from
+		}
+		if until.After(v.until) {
+			v.until = until
+		}
+		// The windows are materialized again, so writes to them mark them.
+		if !v.staleFrom.IsZero() && !v.staleFrom.Before(from) {
+			v.staleFrom = time.Time{}
+		}
+		return
+	}
+	t.views[mv.SourceBucketID] = append(t.views[mv.SourceBucketID], &trackedView{
+		id:          mv.ID,
+		measurement: []byte(mv.Measurement),
+		every:       mv.Every,
+		from:        from,
+		until:       until,
+	})
+}
@@ -0,0 +1,140 @@
+package materializedview
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestStaleWindowTracker_Track(t *testing.T) {
+	const (
+		orgID    influxdb.ID = 1
+		sourceID influxdb.ID = 2
+	)
+	now := time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+	view := &influxdb.MaterializedView{
+		ID:                10,
+		OrganizationID:    orgID,
+		SourceBucketID:    sourceID,
+		Measurement:       "cpu",
+		Every:             time.Minute,
+		Status:            influxdb.Active,
+		MaterializedFrom:  now.Add(-time.Hour),
+		MaterializedUntil: now.Add(-5 * time.Minute),
+	}
+
+	marks := make(map[influxdb.ID][]time.Time)
+	views := mock.NewMaterializedViewService()
+	views.UpdateMaterializedViewFn = func(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+		marks[id] = append(marks[id], *upd.MarkStale)
+		return nil, nil
+	}
+	tr := NewStaleWindowTracker(views)
+	tr.setViews([]*influxdb.MaterializedView{view})
+
+	point := func(bucketID influxdb.ID, measurement string, ts time.Time) models.Point {
+		tags := models.NewTags(map[string]string{models.MeasurementTagKey: measurement, models.FieldKeyTagKey: "usage"})
+		name := tsdb.EncodeName(orgID, bucketID)
+		p, err := models.NewPoint(string(name[:]), tags, models.Fields{"usage": 1.0}, ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		points []models.Point
+		want   []time.Time
+	}{
+		{
+			name:   "point of a window that is not materialized",
+			points: []models.Point{point(sourceID, "cpu", now.Add(-time.Minute))},
+		},
+		{
+			name:   "point of another measurement",
+			points: []models.Point{point(sourceID, "mem", now.Add(-30*time.Minute))},
+		},
+		{
+			name:   "point of another bucket",
+			points: []models.Point{point(3, "cpu", now.Add(-30*time.Minute))},
+		},
+		{
+			name: "points of materialized windows",
+			points: []models.Point{
+				point(sourceID, "cpu", now.Add(-20*time.Minute+time.Second)),
+				point(sourceID, "cpu", now.Add(-30*time.Minute+time.Second)),
+			},
+			want: []time.Time{now.Add(-30 * time.Minute)},
+		},
+		{
+			name:   "point of a window after a stale window",
+			points: []models.Point{point(sourceID, "cpu", now.Add(-10*time.Minute))},
+			want:   []time.Time{now.Add(-30 * time.Minute)},
+		},
+		{
+			name:   "point of a window before the stale windows",
+			points: []models.Point{point(sourceID, "cpu", now.Add(-40*time.Minute))},
+			want:   []time.Time{now.Add(-30 * time.Minute), now.Add(-40 * time.Minute)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tr.Track(context.Background(), tt.points); err != nil {
+				t.Fatal(err)
+			}
+			got := marks[view.ID]
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected the marks %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("expected the marks %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	// The windows materialized again are marked again by the writes to them.
+	tr.materializing(view, now.Add(-40*time.Minute), now.Add(-4*time.Minute))
+	if err := tr.Track(context.Background(), []models.Point{point(sourceID, "cpu", now.Add(-5*time.Minute))}); err != nil {
+		t.Fatal(err)
+	}
+	if got := marks[view.ID]; len(got) != 3 || !got[2].Equal(now.Add(-5*time.Minute)) {
+		t.Fatalf("expected the window being materialized to be marked, got %v", got)
+	}
+}
+
+func TestMaterializedViewUpdate_Stale(t *testing.T) {
+	now := time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+	mv := &influxdb.MaterializedView{MaterializedFrom: now.Add(-time.Hour), MaterializedUntil: now}
+
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	influxdb.MaterializedViewUpdate{MarkStale: at(-10 * time.Minute)}.Apply(mv)
+	influxdb.MaterializedViewUpdate{MarkStale: at(-5 * time.Minute)}.Apply(mv)
+	if !mv.FreshUntil().Equal(now.Add(-10 * time.Minute)) {
+		t.Fatalf("expected the view to be fresh until the earliest mark, got %s", mv.FreshUntil())
+	}
+
+	// The windows marked while the view was materialized again stay stale.
+	marks := mv.StaleMarks
+	influxdb.MaterializedViewUpdate{MarkStale: at(-20 * time.Minute)}.Apply(mv)
+	influxdb.MaterializedViewUpdate{ClearStale: &marks}.Apply(mv)
+	if !mv.FreshUntil().Equal(now.Add(-20 * time.Minute)) {
+		t.Fatalf("expected the marks made meanwhile to be kept, got fresh until %s", mv.FreshUntil())
+	}
+
+	marks = mv.StaleMarks
+	influxdb.MaterializedViewUpdate{ClearStale: &marks}.Apply(mv)
+	if !mv.FreshUntil().Equal(now) {
+		t.Fatalf("expected the marks to be cleared, got fresh until %s", mv.FreshUntil())
+	}
+}
@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	_ influxdb.AlertService                = (*AlertService)(nil)
+	_ influxdb.AlertAcknowledgementService = (*AlertAcknowledgementService)(nil)
+)
+
+// AlertService is a mock implementation of influxdb.AlertService.
+type AlertService struct {
+	AcknowledgeAlertFn func(ctx context.Context, checkID, userID influxdb.ID) (*influxdb.AlertAcknowledgement, error)
+	FindAlertsFn       func(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error)
+}
+
+// NewAlertService returns a mock AlertService where its methods will return
+// zero values.
+func NewAlertService() *AlertService {
+	return &AlertService{
+		AcknowledgeAlertFn: func(ctx context.Context, checkID, userID influxdb.ID) (*influxdb.AlertAcknowledgement, error) {
+			return nil, nil
+		},
+		FindAlertsFn: func(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error) {
+			return nil, nil
+		},
+	}
+}
+
+// AcknowledgeAlert acknowledges the alert of a check.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, checkID, userID influxdb.ID) (*influxdb.AlertAcknowledgement, error) {
+	return s.AcknowledgeAlertFn(ctx, checkID, userID)
+}
+
+// FindAlerts returns the alerts of an organization.
+func (s *AlertService) FindAlerts(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error) {
+	return s.FindAlertsFn(ctx, orgID)
+}
+
+// AlertAcknowledgementService is a mock implementation of
+// influxdb.AlertAcknowledgementService.
+type AlertAcknowledgementService struct {
+	CreateAlertAcknowledgementFn  func(ctx context.Context, a *influxdb.AlertAcknowledgement) error
+	FindAlertAcknowledgementsFn   func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter) ([]*influxdb.AlertAcknowledgement, int, error)
+	DeleteAlertAcknowledgementsFn func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter, before time.Time) error
+}
+
+// NewAlertAcknowledgementService returns a mock AlertAcknowledgementService
+// where its methods will return zero values.
+func NewAlertAcknowledgementService() *AlertAcknowledgementService {
+	return &AlertAcknowledgementService{
+		CreateAlertAcknowledgementFn: func(ctx context.Context, a *influxdb.AlertAcknowledgement) error {
+			return nil
+		},
+		FindAlertAcknowledgementsFn: func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter) ([]*influxdb.AlertAcknowledgement, int, error) {
+			return nil, 0, nil
+		},
+		DeleteAlertAcknowledgementsFn: func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter, before time.Time) error {
+			return nil
+		},
+	}
+}
+
+// CreateAlertAcknowledgement creates an acknowledgement.
+func (s *AlertAcknowledgementService) CreateAlertAcknowledgement(ctx context.Context, a *influxdb.AlertAcknowledgement) error {
+	return s.CreateAlertAcknowledgementFn(ctx, a)
+}
+
+// FindAlertAcknowledgements returns the acknowledgements matching the filter.
+func (s *AlertAcknowledgementService) FindAlertAcknowledgements(ctx context.Context, filter influxdb.AlertAcknowledgementFilter) ([]*influxdb.AlertAcknowledgement, int, error) {
+	return s.FindAlertAcknowledgementsFn(ctx, filter)
+}
+
+// DeleteAlertAcknowledgements deletes the acknowledgements matching the filter
+// that are older than before.
+func (s *AlertAcknowledgementService) DeleteAlertAcknowledgements(ctx context.Context, filter influxdb.AlertAcknowledgementFilter, before time.Time) error {
+	return s.DeleteAlertAcknowledgementsFn(ctx, filter, before)
+}
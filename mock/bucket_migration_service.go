@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketMigrationService = (*BucketMigrationService)(nil)
+
+// BucketMigrationService is a mock implementation of influxdb.BucketMigrationService.
+type BucketMigrationService struct {
+	CreateBucketMigrationFn   func(ctx context.Context, m *influxdb.BucketMigration) error
+	FindBucketMigrationByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.BucketMigration, error)
+	FindBucketMigrationsFn    func(ctx context.Context, filter influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error)
+	CancelBucketMigrationFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewBucketMigrationService returns a mock BucketMigrationService where its
+// methods will return zero values.
+func NewBucketMigrationService() *BucketMigrationService {
+	return &BucketMigrationService{
+		CreateBucketMigrationFn: func(ctx context.Context, m *influxdb.BucketMigration) error { return nil },
+		FindBucketMigrationByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.BucketMigration, error) {
+			return &influxdb.BucketMigration{ID: id}, nil
+		},
+		FindBucketMigrationsFn: func(ctx context.Context, filter influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+			return nil, nil
+		},
+		CancelBucketMigrationFn: func(ctx context.Context, id influxdb.ID) error { return nil },
+	}
+}
+
+// CreateBucketMigration starts a migration.
+func (s *BucketMigrationService) CreateBucketMigration(ctx context.Context, m *influxdb.BucketMigration) error {
+	return s.CreateBucketMigrationFn(ctx, m)
+}
+
+// FindBucketMigrationByID returns a single migration.
+func (s *BucketMigrationService) FindBucketMigrationByID(ctx context.Context, id influxdb.ID) (*influxdb.BucketMigration, error) {
+	return s.FindBucketMigrationByIDFn(ctx, id)
+}
+
+// FindBucketMigrations returns the migrations matching a filter.
+func (s *BucketMigrationService) FindBucketMigrations(ctx context.Context, filter influxdb.BucketMigrationFilter) ([]*influxdb.BucketMigration, error) {
+	return s.FindBucketMigrationsFn(ctx, filter)
+}
+
+// CancelBucketMigration cancels a migration.
+func (s *BucketMigrationService) CancelBucketMigration(ctx context.Context, id influxdb.ID) error {
+	return s.CancelBucketMigrationFn(ctx, id)
+}
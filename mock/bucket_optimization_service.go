@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketOptimizationService = (*BucketOptimizationService)(nil)
+
+// BucketOptimizationService is a mock implementation of influxdb.BucketOptimizationService.
+type BucketOptimizationService struct {
+	OptimizeBucketFn           func(ctx context.Context, bucketID influxdb.ID, windowStart, windowStop time.Time) (*influxdb.BucketOptimization, error)
+	FindBucketOptimizationFn   func(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketOptimization, error)
+	CancelBucketOptimizationFn func(ctx context.Context, bucketID influxdb.ID) error
+}
+
+// NewBucketOptimizationService returns a mock BucketOptimizationService where
+// its methods will return zero values.
+func NewBucketOptimizationService() *BucketOptimizationService {
+	return &BucketOptimizationService{
+		OptimizeBucketFn: func(ctx context.Context, bucketID influxdb.ID, windowStart, windowStop time.Time) (*influxdb.BucketOptimization, error) {
+			return &influxdb.BucketOptimization{BucketID: bucketID, WindowStart: windowStart, WindowStop: windowStop}, nil
+		},
+		FindBucketOptimizationFn: func(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketOptimization, error) {
+			return &influxdb.BucketOptimization{BucketID: bucketID}, nil
+		},
+		CancelBucketOptimizationFn: func(ctx context.Context, bucketID influxdb.ID) error { return nil },
+	}
+}
+
+// OptimizeBucket schedules an optimization of a bucket.
+func (s *BucketOptimizationService) OptimizeBucket(ctx context.Context, bucketID influxdb.ID, windowStart, windowStop time.Time) (*influxdb.BucketOptimization, error) {
+	return s.OptimizeBucketFn(ctx, bucketID, windowStart, windowStop)
+}
+
+// FindBucketOptimization returns the latest optimization of a bucket.
+func (s *BucketOptimizationService) FindBucketOptimization(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketOptimization, error) {
+	return s.FindBucketOptimizationFn(ctx, bucketID)
+}
+
+// CancelBucketOptimization cancels the optimization of a bucket.
+func (s *BucketOptimizationService) CancelBucketOptimization(ctx context.Context, bucketID influxdb.ID) error {
+	return s.CancelBucketOptimizationFn(ctx, bucketID)
+}
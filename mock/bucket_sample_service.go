@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketSampleService = (*BucketSampleService)(nil)
+
+// BucketSampleService is a mock implementation of influxdb.BucketSampleService.
+type BucketSampleService struct {
+	SampleBucketFn func(ctx context.Context, bucketID influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error)
+}
+
+// NewBucketSampleService returns a mock BucketSampleService where its methods
+// will return zero values.
+func NewBucketSampleService() *BucketSampleService {
+	return &BucketSampleService{
+		SampleBucketFn: func(ctx context.Context, bucketID influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error) {
+			return nil, nil
+		},
+	}
+}
+
+// SampleBucket returns the latest rows of the bucket.
+func (s *BucketSampleService) SampleBucket(ctx context.Context, bucketID influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error) {
+	return s.SampleBucketFn(ctx, bucketID, opts)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService is a mock implementation of influxdb.BucketSchemaService.
+type BucketSchemaService struct {
+	FindMeasurementsFn func(ctx context.Context, bucketID influxdb.ID) ([]string, error)
+	FindFieldsFn       func(ctx context.Context, bucketID influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error)
+	FindTagValuesFn    func(ctx context.Context, bucketID influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error)
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where its methods
+// will return zero values.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindMeasurementsFn: func(ctx context.Context, bucketID influxdb.ID) ([]string, error) {
+			return nil, nil
+		},
+		FindFieldsFn: func(ctx context.Context, bucketID influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error) {
+			return nil, nil
+		},
+		FindTagValuesFn: func(ctx context.Context, bucketID influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindMeasurements returns the measurements of the bucket.
+func (s *BucketSchemaService) FindMeasurements(ctx context.Context, bucketID influxdb.ID) ([]string, error) {
+	return s.FindMeasurementsFn(ctx, bucketID)
+}
+
+// FindFields returns the field keys of the bucket matching the filter.
+func (s *BucketSchemaService) FindFields(ctx context.Context, bucketID influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	return s.FindFieldsFn(ctx, bucketID, filter)
+}
+
+// FindTagValues returns the values of the tag key in the bucket matching the filter.
+func (s *BucketSchemaService) FindTagValues(ctx context.Context, bucketID influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	return s.FindTagValuesFn(ctx, bucketID, key, filter)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DashboardRenderService = (*DashboardRenderService)(nil)
+
+// DashboardRenderService is a mock implementation of
+// influxdb.DashboardRenderService.
+type DashboardRenderService struct {
+	RenderDashboardFn func(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error)
+}
+
+// NewDashboardRenderService returns a mock DashboardRenderService where its
+// methods will return zero values.
+func NewDashboardRenderService() *DashboardRenderService {
+	return &DashboardRenderService{
+		RenderDashboardFn: func(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+			return nil, nil
+		},
+	}
+}
+
+// RenderDashboard renders the dashboard to a PNG image.
+func (s *DashboardRenderService) RenderDashboard(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+	return s.RenderDashboardFn(ctx, id, opts)
+}
@@ -49,3 +49,51 @@ func (s *DBRPMappingService) Create(ctx context.Context, dbrpMap *platform.DBRPM
 func (s *DBRPMappingService) Delete(ctx context.Context, cluster string, db string, rp string) error {
 	return s.DeleteFn(ctx, cluster, db, rp)
 }
+
+var _ platform.DBRPMappingServiceV2 = (*DBRPMappingServiceV2)(nil)
+
+// DBRPMappingServiceV2 is a mock implementation of platform.DBRPMappingServiceV2.
+type DBRPMappingServiceV2 struct {
+	FindDBRPMappingByIDFn func(ctx context.Context, id platform.ID) (*platform.DBRPMappingV2, error)
+	FindDBRPMappingsFn    func(ctx context.Context, filter platform.DBRPMappingFilterV2, opt ...platform.FindOptions) ([]*platform.DBRPMappingV2, int, error)
+	CreateDBRPMappingFn   func(ctx context.Context, m *platform.DBRPMappingV2) error
+	UpdateDBRPMappingFn   func(ctx context.Context, id platform.ID, upd platform.DBRPMappingUpdate) (*platform.DBRPMappingV2, error)
+	DeleteDBRPMappingFn   func(ctx context.Context, id platform.ID) error
+}
+
+// NewDBRPMappingServiceV2 returns a mock dbrp mapping service where its methods will return zero values.
+func NewDBRPMappingServiceV2() *DBRPMappingServiceV2 {
+	return &DBRPMappingServiceV2{
+		FindDBRPMappingByIDFn: func(ctx context.Context, id platform.ID) (*platform.DBRPMappingV2, error) {
+			return nil, nil
+		},
+		FindDBRPMappingsFn: func(ctx context.Context, filter platform.DBRPMappingFilterV2, opt ...platform.FindOptions) ([]*platform.DBRPMappingV2, int, error) {
+			return nil, 0, nil
+		},
+		CreateDBRPMappingFn: func(ctx context.Context, m *platform.DBRPMappingV2) error { return nil },
+		UpdateDBRPMappingFn: func(ctx context.Context, id platform.ID, upd platform.DBRPMappingUpdate) (*platform.DBRPMappingV2, error) {
+			return nil, nil
+		},
+		DeleteDBRPMappingFn: func(ctx context.Context, id platform.ID) error { return nil },
+	}
+}
+
+func (s *DBRPMappingServiceV2) FindDBRPMappingByID(ctx context.Context, id platform.ID) (*platform.DBRPMappingV2, error) {
+	return s.FindDBRPMappingByIDFn(ctx, id)
+}
+
+func (s *DBRPMappingServiceV2) FindDBRPMappings(ctx context.Context, filter platform.DBRPMappingFilterV2, opt ...platform.FindOptions) ([]*platform.DBRPMappingV2, int, error) {
+	return s.FindDBRPMappingsFn(ctx, filter, opt...)
+}
+
+func (s *DBRPMappingServiceV2) CreateDBRPMapping(ctx context.Context, m *platform.DBRPMappingV2) error {
+	return s.CreateDBRPMappingFn(ctx, m)
+}
+
+func (s *DBRPMappingServiceV2) UpdateDBRPMapping(ctx context.Context, id platform.ID, upd platform.DBRPMappingUpdate) (*platform.DBRPMappingV2, error) {
+	return s.UpdateDBRPMappingFn(ctx, id, upd)
+}
+
+func (s *DBRPMappingServiceV2) DeleteDBRPMapping(ctx context.Context, id platform.ID) error {
+	return s.DeleteDBRPMappingFn(ctx, id)
+}
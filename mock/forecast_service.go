@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ForecastService = (*ForecastService)(nil)
+
+// ForecastService is a mock implementation of influxdb.ForecastService.
+type ForecastService struct {
+	ForecastFn func(ctx context.Context, req influxdb.ForecastRequest) ([]*influxdb.ForecastSeries, error)
+}
+
+// NewForecastService returns a mock ForecastService where its methods will
+// return zero values.
+func NewForecastService() *ForecastService {
+	return &ForecastService{
+		ForecastFn: func(ctx context.Context, req influxdb.ForecastRequest) ([]*influxdb.ForecastSeries, error) {
+			return nil, nil
+		},
+	}
+}
+
+// Forecast forecasts the results of the query of the request.
+func (s *ForecastService) Forecast(ctx context.Context, req influxdb.ForecastRequest) ([]*influxdb.ForecastSeries, error) {
+	return s.ForecastFn(ctx, req)
+}
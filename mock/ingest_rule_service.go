@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.IngestRuleService = (*IngestRuleService)(nil)
+
+// IngestRuleService is a mock implementation of influxdb.IngestRuleService.
+type IngestRuleService struct {
+	FindIngestRuleByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error)
+	FindIngestRulesFn    func(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error)
+	CreateIngestRuleFn   func(ctx context.Context, r *influxdb.IngestRule) error
+	UpdateIngestRuleFn   func(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error)
+	DeleteIngestRuleFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewIngestRuleService returns a mock IngestRuleService where its methods
+// will return zero values.
+func NewIngestRuleService() *IngestRuleService {
+	return &IngestRuleService{
+		FindIngestRuleByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+			return nil, nil
+		},
+		FindIngestRulesFn: func(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+			return nil, 0, nil
+		},
+		CreateIngestRuleFn: func(ctx context.Context, r *influxdb.IngestRule) error {
+			return nil
+		},
+		UpdateIngestRuleFn: func(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+			return nil, nil
+		},
+		DeleteIngestRuleFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindIngestRuleByID returns a single ingest rule by ID.
+func (s *IngestRuleService) FindIngestRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.IngestRule, error) {
+	return s.FindIngestRuleByIDFn(ctx, id)
+}
+
+// FindIngestRules returns a list of ingest rules that match filter and the total count of matching rules.
+func (s *IngestRuleService) FindIngestRules(ctx context.Context, filter influxdb.IngestRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.IngestRule, int, error) {
+	return s.FindIngestRulesFn(ctx, filter, opt...)
+}
+
+// CreateIngestRule creates a new ingest rule and sets r.ID with the new identifier.
+func (s *IngestRuleService) CreateIngestRule(ctx context.Context, r *influxdb.IngestRule) error {
+	return s.CreateIngestRuleFn(ctx, r)
+}
+
+// UpdateIngestRule updates a single ingest rule with the changeset.
+func (s *IngestRuleService) UpdateIngestRule(ctx context.Context, id influxdb.ID, upd influxdb.IngestRuleUpdate) (*influxdb.IngestRule, error) {
+	return s.UpdateIngestRuleFn(ctx, id, upd)
+}
+
+// DeleteIngestRule removes a ingest rule by ID.
+func (s *IngestRuleService) DeleteIngestRule(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteIngestRuleFn(ctx, id)
+}
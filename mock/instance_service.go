@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.InstanceService = (*InstanceService)(nil)
+
+// InstanceService is a mock implementation of influxdb.InstanceService.
+type InstanceService struct {
+	FindInstancesFn func(context.Context) ([]*influxdb.Instance, error)
+}
+
+// NewInstanceService returns a mock InstanceService where its methods
+// will return zero values.
+func NewInstanceService() *InstanceService {
+	return &InstanceService{
+		FindInstancesFn: func(context.Context) ([]*influxdb.Instance, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindInstances returns the known instances.
+func (s *InstanceService) FindInstances(ctx context.Context) ([]*influxdb.Instance, error) {
+	return s.FindInstancesFn(ctx)
+}
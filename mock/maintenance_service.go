@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MaintenanceService = (*MaintenanceService)(nil)
+
+// MaintenanceService is a mock implementation of influxdb.MaintenanceService.
+type MaintenanceService struct {
+	FindMaintenancesFn func(context.Context) ([]*influxdb.Maintenance, error)
+	StartMaintenanceFn func(context.Context, *influxdb.Maintenance) error
+	StopMaintenanceFn  func(context.Context, *influxdb.ID) error
+}
+
+// NewMaintenanceService returns a mock MaintenanceService where its methods
+// will return zero values.
+func NewMaintenanceService() *MaintenanceService {
+	return &MaintenanceService{
+		FindMaintenancesFn: func(context.Context) ([]*influxdb.Maintenance, error) {
+			return nil, nil
+		},
+		StartMaintenanceFn: func(context.Context, *influxdb.Maintenance) error {
+			return nil
+		},
+		StopMaintenanceFn: func(context.Context, *influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindMaintenances returns the maintenances in effect.
+func (s *MaintenanceService) FindMaintenances(ctx context.Context) ([]*influxdb.Maintenance, error) {
+	return s.FindMaintenancesFn(ctx)
+}
+
+// StartMaintenance puts the organization of m, or the instance, in maintenance mode.
+func (s *MaintenanceService) StartMaintenance(ctx context.Context, m *influxdb.Maintenance) error {
+	return s.StartMaintenanceFn(ctx, m)
+}
+
+// StopMaintenance ends the maintenance of the organization, or of the instance.
+func (s *MaintenanceService) StopMaintenance(ctx context.Context, orgID *influxdb.ID) error {
+	return s.StopMaintenanceFn(ctx, orgID)
+}
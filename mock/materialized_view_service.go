@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MaterializedViewService = (*MaterializedViewService)(nil)
+
+// MaterializedViewService is a mock implementation of influxdb.MaterializedViewService.
+type MaterializedViewService struct {
+	FindMaterializedViewByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.MaterializedView, error)
+	FindMaterializedViewsFn    func(ctx context.Context, filter influxdb.MaterializedViewFilter, opt ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error)
+	CreateMaterializedViewFn   func(ctx context.Context, mv *influxdb.MaterializedView) error
+	UpdateMaterializedViewFn   func(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error)
+	DeleteMaterializedViewFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewMaterializedViewService returns a mock MaterializedViewService where its methods
+// will return zero values.
+func NewMaterializedViewService() *MaterializedViewService {
+	return &MaterializedViewService{
+		FindMaterializedViewByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.MaterializedView, error) {
+			return nil, nil
+		},
+		FindMaterializedViewsFn: func(ctx context.Context, filter influxdb.MaterializedViewFilter, opt ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+			return nil, 0, nil
+		},
+		CreateMaterializedViewFn: func(ctx context.Context, mv *influxdb.MaterializedView) error {
+			return nil
+		},
+		UpdateMaterializedViewFn: func(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+			return nil, nil
+		},
+		DeleteMaterializedViewFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindMaterializedViewByID returns a single materialized view by ID.
+func (s *MaterializedViewService) FindMaterializedViewByID(ctx context.Context, id influxdb.ID) (*influxdb.MaterializedView, error) {
+	return s.FindMaterializedViewByIDFn(ctx, id)
+}
+
+// FindMaterializedViews returns a list of materialized views that match filter and the total count of matching views.
+func (s *MaterializedViewService) FindMaterializedViews(ctx context.Context, filter influxdb.MaterializedViewFilter, opt ...influxdb.FindOptions) ([]*influxdb.MaterializedView, int, error) {
+	return s.FindMaterializedViewsFn(ctx, filter, opt...)
+}
+
+// CreateMaterializedView creates a new materialized view and sets mv.ID with the new identifier.
+func (s *MaterializedViewService) CreateMaterializedView(ctx context.Context, mv *influxdb.MaterializedView) error {
+	return s.CreateMaterializedViewFn(ctx, mv)
+}
+
+// UpdateMaterializedView updates a single materialized view with the changeset.
+func (s *MaterializedViewService) UpdateMaterializedView(ctx context.Context, id influxdb.ID, upd influxdb.MaterializedViewUpdate) (*influxdb.MaterializedView, error) {
+	return s.UpdateMaterializedViewFn(ctx, id, upd)
+}
+
+// DeleteMaterializedView removes a materialized view by ID.
+func (s *MaterializedViewService) DeleteMaterializedView(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteMaterializedViewFn(ctx, id)
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MeasurementLastWriteService = (*MeasurementLastWriteService)(nil)
+
+// MeasurementLastWriteService is a mock implementation of influxdb.MeasurementLastWriteService.
+type MeasurementLastWriteService struct {
+	FindMeasurementLastWritesFn   func(ctx context.Context, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error)
+	RecordMeasurementLastWritesFn func(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error
+}
+
+// NewMeasurementLastWriteService returns a mock MeasurementLastWriteService
+// where its methods will return zero values.
+func NewMeasurementLastWriteService() *MeasurementLastWriteService {
+	return &MeasurementLastWriteService{
+		FindMeasurementLastWritesFn: func(ctx context.Context, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error) {
+			return influxdb.MeasurementLastWrites{}, nil
+		},
+		RecordMeasurementLastWritesFn: func(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error {
+			return nil
+		},
+	}
+}
+
+// FindMeasurementLastWrites returns the times of the last writes to the measurements of the bucket.
+func (s *MeasurementLastWriteService) FindMeasurementLastWrites(ctx context.Context, bucketID influxdb.ID) (influxdb.MeasurementLastWrites, error) {
+	return s.FindMeasurementLastWritesFn(ctx, bucketID)
+}
+
+// RecordMeasurementLastWrites records the times of writes to the measurements of buckets.
+func (s *MeasurementLastWriteService) RecordMeasurementLastWrites(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error {
+	return s.RecordMeasurementLastWritesFn(ctx, writes)
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MonitoringRunService = (*MonitoringRunService)(nil)
+
+// MonitoringRunService is a mock implementation of influxdb.MonitoringRunService.
+type MonitoringRunService struct {
+	FindCheckRunsFn            func(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error)
+	FindNotificationRuleRunsFn func(ctx context.Context, ruleID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error)
+}
+
+// NewMonitoringRunService returns a mock MonitoringRunService where its methods
+// will return no runs.
+func NewMonitoringRunService() *MonitoringRunService {
+	return &MonitoringRunService{
+		FindCheckRunsFn: func(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+			return nil, 0, nil
+		},
+		FindNotificationRuleRunsFn: func(ctx context.Context, ruleID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+			return nil, 0, nil
+		},
+	}
+}
+
+// FindCheckRuns returns the runs of a check.
+func (s *MonitoringRunService) FindCheckRuns(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	return s.FindCheckRunsFn(ctx, checkID, filter)
+}
+
+// FindNotificationRuleRuns returns the runs of a notification rule.
+func (s *MonitoringRunService) FindNotificationRuleRuns(ctx context.Context, ruleID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	return s.FindNotificationRuleRunsFn(ctx, ruleID, filter)
+}
@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotebookService = (*NotebookService)(nil)
+
+// NotebookService is a mock implementation of influxdb.NotebookService.
+type NotebookService struct {
+	FindNotebookByIDFn     func(ctx context.Context, id influxdb.ID) (*influxdb.Notebook, error)
+	FindNotebooksFn        func(ctx context.Context, filter influxdb.NotebookFilter, opt ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error)
+	CreateNotebookFn       func(ctx context.Context, n *influxdb.Notebook) error
+	UpdateNotebookFn       func(ctx context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error)
+	DeleteNotebookFn       func(ctx context.Context, id influxdb.ID) error
+	FindNotebookVersionsFn func(ctx context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error)
+	FindNotebookVersionFn  func(ctx context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error)
+}
+
+// NewNotebookService returns a mock NotebookService where its methods
+// will return zero values.
+func NewNotebookService() *NotebookService {
+	return &NotebookService{
+		FindNotebookByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Notebook, error) {
+			return nil, nil
+		},
+		FindNotebooksFn: func(ctx context.Context, filter influxdb.NotebookFilter, opt ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error) {
+			return nil, 0, nil
+		},
+		CreateNotebookFn: func(ctx context.Context, n *influxdb.Notebook) error {
+			return nil
+		},
+		UpdateNotebookFn: func(ctx context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error) {
+			return nil, nil
+		},
+		DeleteNotebookFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+		FindNotebookVersionsFn: func(ctx context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error) {
+			return nil, nil
+		},
+		FindNotebookVersionFn: func(ctx context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindNotebookByID returns a single notebook by ID.
+func (s *NotebookService) FindNotebookByID(ctx context.Context, id influxdb.ID) (*influxdb.Notebook, error) {
+	return s.FindNotebookByIDFn(ctx, id)
+}
+
+// FindNotebooks returns a list of notebooks that match filter and the total count of matching notebooks.
+func (s *NotebookService) FindNotebooks(ctx context.Context, filter influxdb.NotebookFilter, opt ...influxdb.FindOptions) ([]*influxdb.Notebook, int, error) {
+	return s.FindNotebooksFn(ctx, filter, opt...)
+}
+
+// CreateNotebook creates a new notebook and sets n.ID with the new identifier.
+func (s *NotebookService) CreateNotebook(ctx context.Context, n *influxdb.Notebook) error {
+	return s.CreateNotebookFn(ctx, n)
+}
+
+// UpdateNotebook updates a single notebook with the changeset.
+func (s *NotebookService) UpdateNotebook(ctx context.Context, id influxdb.ID, upd influxdb.NotebookUpdate) (*influxdb.Notebook, error) {
+	return s.UpdateNotebookFn(ctx, id, upd)
+}
+
+// DeleteNotebook removes a notebook by ID.
+func (s *NotebookService) DeleteNotebook(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteNotebookFn(ctx, id)
+}
+
+// FindNotebookVersions returns the kept versions of a notebook.
+func (s *NotebookService) FindNotebookVersions(ctx context.Context, id influxdb.ID) ([]*influxdb.NotebookVersion, error) {
+	return s.FindNotebookVersionsFn(ctx, id)
+}
+
+// FindNotebookVersion returns a single version of a notebook.
+func (s *NotebookService) FindNotebookVersion(ctx context.Context, id influxdb.ID, version int) (*influxdb.NotebookVersion, error) {
+	return s.FindNotebookVersionFn(ctx, id, version)
+}
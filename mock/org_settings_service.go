@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.OrganizationSettingsService = (*OrganizationSettingsService)(nil)
+
+// OrganizationSettingsService is a mock implementation of influxdb.OrganizationSettingsService.
+type OrganizationSettingsService struct {
+	FindOrganizationSettingsFn   func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error)
+	UpdateOrganizationSettingsFn func(ctx context.Context, orgID influxdb.ID, upd influxdb.OrganizationSettingsUpdate) (*influxdb.OrganizationSettings, error)
+}
+
+// NewOrganizationSettingsService returns a mock OrganizationSettingsService where its methods
+// will return empty settings of the organization.
+func NewOrganizationSettingsService() *OrganizationSettingsService {
+	return &OrganizationSettingsService{
+		FindOrganizationSettingsFn: func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+			return &influxdb.OrganizationSettings{OrgID: orgID}, nil
+		},
+		UpdateOrganizationSettingsFn: func(ctx context.Context, orgID influxdb.ID, upd influxdb.OrganizationSettingsUpdate) (*influxdb.OrganizationSettings, error) {
+			s := &influxdb.OrganizationSettings{OrgID: orgID}
+			upd.Apply(s)
+			return s, nil
+		},
+	}
+}
+
+// FindOrganizationSettings returns the settings of an organization.
+func (s *OrganizationSettingsService) FindOrganizationSettings(ctx context.Context, orgID influxdb.ID) (*influxdb.OrganizationSettings, error) {
+	return s.FindOrganizationSettingsFn(ctx, orgID)
+}
+
+// UpdateOrganizationSettings updates the settings of an organization.
+func (s *OrganizationSettingsService) UpdateOrganizationSettings(ctx context.Context, orgID influxdb.ID, upd influxdb.OrganizationSettingsUpdate) (*influxdb.OrganizationSettings, error) {
+	return s.UpdateOrganizationSettingsFn(ctx, orgID, upd)
+}
@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.QueryTemplateService = (*QueryTemplateService)(nil)
+
+// QueryTemplateService is a mock implementation of influxdb.QueryTemplateService.
+type QueryTemplateService struct {
+	FindQueryTemplateByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.QueryTemplate, error)
+	FindQueryTemplatesFn    func(ctx context.Context, filter influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error)
+	CreateQueryTemplateFn   func(ctx context.Context, t *influxdb.QueryTemplate) error
+	UpdateQueryTemplateFn   func(ctx context.Context, id influxdb.ID, upd influxdb.QueryTemplateUpdate) (*influxdb.QueryTemplate, error)
+	DeleteQueryTemplateFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewQueryTemplateService returns a mock QueryTemplateService where its methods
+// will return zero values.
+func NewQueryTemplateService() *QueryTemplateService {
+	return &QueryTemplateService{
+		FindQueryTemplateByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.QueryTemplate, error) {
+			return nil, nil
+		},
+		FindQueryTemplatesFn: func(ctx context.Context, filter influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error) {
+			return nil, nil
+		},
+		CreateQueryTemplateFn: func(ctx context.Context, t *influxdb.QueryTemplate) error {
+			return nil
+		},
+		UpdateQueryTemplateFn: func(ctx context.Context, id influxdb.ID, upd influxdb.QueryTemplateUpdate) (*influxdb.QueryTemplate, error) {
+			return nil, nil
+		},
+		DeleteQueryTemplateFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindQueryTemplateByID returns a single query template by ID.
+func (s *QueryTemplateService) FindQueryTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryTemplate, error) {
+	return s.FindQueryTemplateByIDFn(ctx, id)
+}
+
+// FindQueryTemplates returns the query templates that match the filter.
+func (s *QueryTemplateService) FindQueryTemplates(ctx context.Context, filter influxdb.QueryTemplateFilter) ([]*influxdb.QueryTemplate, error) {
+	return s.FindQueryTemplatesFn(ctx, filter)
+}
+
+// CreateQueryTemplate creates a new query template and sets t.ID with the new identifier.
+func (s *QueryTemplateService) CreateQueryTemplate(ctx context.Context, t *influxdb.QueryTemplate) error {
+	return s.CreateQueryTemplateFn(ctx, t)
+}
+
+// UpdateQueryTemplate updates a single query template with the changeset.
+func (s *QueryTemplateService) UpdateQueryTemplate(ctx context.Context, id influxdb.ID, upd influxdb.QueryTemplateUpdate) (*influxdb.QueryTemplate, error) {
+	return s.UpdateQueryTemplateFn(ctx, id, upd)
+}
+
+// DeleteQueryTemplate removes a query template by ID.
+func (s *QueryTemplateService) DeleteQueryTemplate(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteQueryTemplateFn(ctx, id)
+}
@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RemoteConnectionService = (*RemoteConnectionService)(nil)
+
+// RemoteConnectionService is a mock implementation of influxdb.RemoteConnectionService.
+type RemoteConnectionService struct {
+	FindRemoteConnectionByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error)
+	FindRemoteConnectionsFn    func(ctx context.Context, filter influxdb.RemoteConnectionFilter, opt ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error)
+	CreateRemoteConnectionFn   func(ctx context.Context, rc *influxdb.RemoteConnection) error
+	UpdateRemoteConnectionFn   func(ctx context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error)
+	DeleteRemoteConnectionFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewRemoteConnectionService returns a mock RemoteConnectionService where its methods
+// will return zero values.
+func NewRemoteConnectionService() *RemoteConnectionService {
+	return &RemoteConnectionService{
+		FindRemoteConnectionByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+			return nil, nil
+		},
+		FindRemoteConnectionsFn: func(ctx context.Context, filter influxdb.RemoteConnectionFilter, opt ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+			return nil, 0, nil
+		},
+		CreateRemoteConnectionFn: func(ctx context.Context, rc *influxdb.RemoteConnection) error {
+			return nil
+		},
+		UpdateRemoteConnectionFn: func(ctx context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error) {
+			return nil, nil
+		},
+		DeleteRemoteConnectionFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindRemoteConnectionByID returns a single remote connection by ID.
+func (s *RemoteConnectionService) FindRemoteConnectionByID(ctx context.Context, id influxdb.ID) (*influxdb.RemoteConnection, error) {
+	return s.FindRemoteConnectionByIDFn(ctx, id)
+}
+
+// FindRemoteConnections returns a list of remote connections that match filter and the total count of matching connections.
+func (s *RemoteConnectionService) FindRemoteConnections(ctx context.Context, filter influxdb.RemoteConnectionFilter, opt ...influxdb.FindOptions) ([]*influxdb.RemoteConnection, int, error) {
+	return s.FindRemoteConnectionsFn(ctx, filter, opt...)
+}
+
+// CreateRemoteConnection creates a new remote connection and sets rc.ID with the new identifier.
+func (s *RemoteConnectionService) CreateRemoteConnection(ctx context.Context, rc *influxdb.RemoteConnection) error {
+	return s.CreateRemoteConnectionFn(ctx, rc)
+}
+
+// UpdateRemoteConnection updates a single remote connection with the changeset.
+func (s *RemoteConnectionService) UpdateRemoteConnection(ctx context.Context, id influxdb.ID, upd influxdb.RemoteConnectionUpdate) (*influxdb.RemoteConnection, error) {
+	return s.UpdateRemoteConnectionFn(ctx, id, upd)
+}
+
+// DeleteRemoteConnection removes a remote connection by ID.
+func (s *RemoteConnectionService) DeleteRemoteConnection(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteRemoteConnectionFn(ctx, id)
+}
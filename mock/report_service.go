@@ -0,0 +1,130 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ReportService = (*ReportService)(nil)
+
+// ReportService is a mock implementation of influxdb.ReportService.
+type ReportService struct {
+	FindReportByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.Report, error)
+	FindReportsFn    func(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error)
+	CreateReportFn   func(ctx context.Context, r *influxdb.Report) error
+	UpdateReportFn   func(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error)
+	DeleteReportFn   func(ctx context.Context, id influxdb.ID) error
+	FindReportRunsFn func(ctx context.Context, id influxdb.ID) ([]*influxdb.ReportRun, error)
+	AddReportRunFn   func(ctx context.Context, run *influxdb.ReportRun) error
+}
+
+// NewReportService returns a mock ReportService where its methods will return
+// zero values.
+func NewReportService() *ReportService {
+	return &ReportService{
+		FindReportByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Report, error) {
+			return nil, nil
+		},
+		FindReportsFn: func(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+			return nil, 0, nil
+		},
+		CreateReportFn: func(ctx context.Context, r *influxdb.Report) error {
+			return nil
+		},
+		UpdateReportFn: func(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error) {
+			return nil, nil
+		},
+		DeleteReportFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+		FindReportRunsFn: func(ctx context.Context, id influxdb.ID) ([]*influxdb.ReportRun, error) {
+			return nil, nil
+		},
+		AddReportRunFn: func(ctx context.Context, run *influxdb.ReportRun) error {
+			return nil
+		},
+	}
+}
+
+// FindReportByID returns a single report by ID.
+func (s *ReportService) FindReportByID(ctx context.Context, id influxdb.ID) (*influxdb.Report, error) {
+	return s.FindReportByIDFn(ctx, id)
+}
+
+// FindReports returns the reports that match the filter.
+func (s *ReportService) FindReports(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+	return s.FindReportsFn(ctx, filter, opt...)
+}
+
+// CreateReport creates a new report and sets r.ID with the new identifier.
+func (s *ReportService) CreateReport(ctx context.Context, r *influxdb.Report) error {
+	return s.CreateReportFn(ctx, r)
+}
+
+// UpdateReport updates a single report with the changeset.
+func (s *ReportService) UpdateReport(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error) {
+	return s.UpdateReportFn(ctx, id, upd)
+}
+
+// DeleteReport removes a report and its runs by ID.
+func (s *ReportService) DeleteReport(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteReportFn(ctx, id)
+}
+
+// FindReportRuns returns the kept runs of a report.
+func (s *ReportService) FindReportRuns(ctx context.Context, id influxdb.ID) ([]*influxdb.ReportRun, error) {
+	return s.FindReportRunsFn(ctx, id)
+}
+
+// AddReportRun records a run of a report.
+func (s *ReportService) AddReportRun(ctx context.Context, run *influxdb.ReportRun) error {
+	return s.AddReportRunFn(ctx, run)
+}
+
+var _ influxdb.ReportAuthorizationService = (*ReportAuthorizationService)(nil)
+
+// ReportAuthorizationService is a mock implementation of
+// influxdb.ReportAuthorizationService.
+type ReportAuthorizationService struct {
+	FindReportAuthorizationFn func(ctx context.Context, r *influxdb.Report) (*influxdb.Authorization, error)
+}
+
+// NewReportAuthorizationService returns a mock ReportAuthorizationService
+// where its methods will return zero values.
+func NewReportAuthorizationService() *ReportAuthorizationService {
+	return &ReportAuthorizationService{
+		FindReportAuthorizationFn: func(ctx context.Context, r *influxdb.Report) (*influxdb.Authorization, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindReportAuthorization returns the authorization the report is rendered
+// with.
+func (s *ReportAuthorizationService) FindReportAuthorization(ctx context.Context, r *influxdb.Report) (*influxdb.Authorization, error) {
+	return s.FindReportAuthorizationFn(ctx, r)
+}
+
+var _ influxdb.ReportDeliveryService = (*ReportDeliveryService)(nil)
+
+// ReportDeliveryService is a mock implementation of
+// influxdb.ReportDeliveryService.
+type ReportDeliveryService struct {
+	DeliverReportFn func(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error)
+}
+
+// NewReportDeliveryService returns a mock ReportDeliveryService where its
+// methods will return zero values.
+func NewReportDeliveryService() *ReportDeliveryService {
+	return &ReportDeliveryService{
+		DeliverReportFn: func(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error) {
+			return nil, nil
+		},
+	}
+}
+
+// DeliverReport delivers the report immediately.
+func (s *ReportDeliveryService) DeliverReport(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error) {
+	return s.DeliverReportFn(ctx, id)
+}
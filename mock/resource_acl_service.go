@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ResourceACLService = (*ResourceACLService)(nil)
+
+// ResourceACLService is a mock implementation of influxdb.ResourceACLService.
+type ResourceACLService struct {
+	FindResourceACLFn   func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error)
+	PutResourceACLFn    func(ctx context.Context, acl *influxdb.ResourceACL) error
+	DeleteResourceACLFn func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error
+}
+
+// NewResourceACLService returns a mock ResourceACLService where its methods
+// will return acls of resources that are not restricted.
+func NewResourceACLService() *ResourceACLService {
+	return &ResourceACLService{
+		FindResourceACLFn: func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+			return &influxdb.ResourceACL{ResourceType: rt, ResourceID: id, Grants: []influxdb.ResourceGrant{}}, nil
+		},
+		PutResourceACLFn: func(ctx context.Context, acl *influxdb.ResourceACL) error {
+			return nil
+		},
+		DeleteResourceACLFn: func(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindResourceACL returns the acl of a resource.
+func (s *ResourceACLService) FindResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (*influxdb.ResourceACL, error) {
+	return s.FindResourceACLFn(ctx, rt, id)
+}
+
+// PutResourceACL replaces the grants of the acl of a resource.
+func (s *ResourceACLService) PutResourceACL(ctx context.Context, acl *influxdb.ResourceACL) error {
+	return s.PutResourceACLFn(ctx, acl)
+}
+
+// DeleteResourceACL removes the acl of a resource.
+func (s *ResourceACLService) DeleteResourceACL(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) error {
+	return s.DeleteResourceACLFn(ctx, rt, id)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RuntimeConfigService = (*RuntimeConfigService)(nil)
+
+// RuntimeConfigService is a mock implementation of influxdb.RuntimeConfigService.
+type RuntimeConfigService struct {
+	FindRuntimeConfigFn   func(context.Context) (*influxdb.RuntimeConfig, error)
+	UpdateRuntimeConfigFn func(context.Context, influxdb.RuntimeConfigUpdate) (*influxdb.RuntimeConfig, error)
+	ResetRuntimeConfigFn  func(context.Context) (*influxdb.RuntimeConfig, error)
+}
+
+// NewRuntimeConfigService returns a mock RuntimeConfigService where its methods
+// will return zero values.
+func NewRuntimeConfigService() *RuntimeConfigService {
+	return &RuntimeConfigService{
+		FindRuntimeConfigFn: func(context.Context) (*influxdb.RuntimeConfig, error) {
+			return nil, nil
+		},
+		UpdateRuntimeConfigFn: func(context.Context, influxdb.RuntimeConfigUpdate) (*influxdb.RuntimeConfig, error) {
+			return nil, nil
+		},
+		ResetRuntimeConfigFn: func(context.Context) (*influxdb.RuntimeConfig, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindRuntimeConfig returns the runtime config currently in effect.
+func (s *RuntimeConfigService) FindRuntimeConfig(ctx context.Context) (*influxdb.RuntimeConfig, error) {
+	return s.FindRuntimeConfigFn(ctx)
+}
+
+// UpdateRuntimeConfig applies the update to the runtime config.
+func (s *RuntimeConfigService) UpdateRuntimeConfig(ctx context.Context, upd influxdb.RuntimeConfigUpdate) (*influxdb.RuntimeConfig, error) {
+	return s.UpdateRuntimeConfigFn(ctx, upd)
+}
+
+// ResetRuntimeConfig restores the configured runtime config.
+func (s *RuntimeConfigService) ResetRuntimeConfig(ctx context.Context) (*influxdb.RuntimeConfig, error) {
+	return s.ResetRuntimeConfigFn(ctx)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SeriesFileService = (*SeriesFileService)(nil)
+
+// SeriesFileService is a mock implementation of influxdb.SeriesFileService.
+type SeriesFileService struct {
+	FindSeriesFileStatsFn func(context.Context) (*influxdb.SeriesFileStats, error)
+	CompactSeriesFileFn   func(context.Context) error
+	VerifySeriesFileFn    func(context.Context, bool) (*influxdb.SeriesFileVerification, error)
+}
+
+// NewSeriesFileService returns a mock SeriesFileService where its methods
+// will return zero values.
+func NewSeriesFileService() *SeriesFileService {
+	return &SeriesFileService{
+		FindSeriesFileStatsFn: func(context.Context) (*influxdb.SeriesFileStats, error) {
+			return nil, nil
+		},
+		CompactSeriesFileFn: func(context.Context) error {
+			return nil
+		},
+		VerifySeriesFileFn: func(context.Context, bool) (*influxdb.SeriesFileVerification, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindSeriesFileStats returns the stats of the series file.
+func (s *SeriesFileService) FindSeriesFileStats(ctx context.Context) (*influxdb.SeriesFileStats, error) {
+	return s.FindSeriesFileStatsFn(ctx)
+}
+
+// CompactSeriesFile compacts the series file.
+func (s *SeriesFileService) CompactSeriesFile(ctx context.Context) error {
+	return s.CompactSeriesFileFn(ctx)
+}
+
+// VerifySeriesFile verifies the series file.
+func (s *SeriesFileService) VerifySeriesFile(ctx context.Context, repair bool) (*influxdb.SeriesFileVerification, error) {
+	return s.VerifySeriesFileFn(ctx, repair)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SessionAlertService = (*SessionAlertService)(nil)
+
+// SessionAlertService is a mock implementation of influxdb.SessionAlertService.
+type SessionAlertService struct {
+	CreateSessionAlertFn  func(ctx context.Context, a *influxdb.SessionAlert) error
+	FindSessionAlertsFn   func(ctx context.Context, userID influxdb.ID) ([]*influxdb.SessionAlert, error)
+	DeleteSessionAlertsFn func(ctx context.Context, userID influxdb.ID) error
+}
+
+// NewSessionAlertService returns a mock SessionAlertService where its methods
+// will return no alerts.
+func NewSessionAlertService() *SessionAlertService {
+	return &SessionAlertService{
+		CreateSessionAlertFn: func(ctx context.Context, a *influxdb.SessionAlert) error {
+			return nil
+		},
+		FindSessionAlertsFn: func(ctx context.Context, userID influxdb.ID) ([]*influxdb.SessionAlert, error) {
+			return []*influxdb.SessionAlert{}, nil
+		},
+		DeleteSessionAlertsFn: func(ctx context.Context, userID influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// CreateSessionAlert records an alert for its user.
+func (s *SessionAlertService) CreateSessionAlert(ctx context.Context, a *influxdb.SessionAlert) error {
+	return s.CreateSessionAlertFn(ctx, a)
+}
+
+// FindSessionAlerts returns the alerts of a user.
+func (s *SessionAlertService) FindSessionAlerts(ctx context.Context, userID influxdb.ID) ([]*influxdb.SessionAlert, error) {
+	return s.FindSessionAlertsFn(ctx, userID)
+}
+
+// DeleteSessionAlerts removes the alerts of a user.
+func (s *SessionAlertService) DeleteSessionAlerts(ctx context.Context, userID influxdb.ID) error {
+	return s.DeleteSessionAlertsFn(ctx, userID)
+}
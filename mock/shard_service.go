@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ShardService = (*ShardService)(nil)
+
+// ShardService is a mock implementation of influxdb.ShardService.
+type ShardService struct {
+	FindShardGroupStatsFn func(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.ShardGroupStats, error)
+	FindCompactionPlanFn  func(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.CompactionPlanGroup, error)
+}
+
+// NewShardService returns a mock ShardService where its methods will return
+// no shard groups and no compactions.
+func NewShardService() *ShardService {
+	return &ShardService{
+		FindShardGroupStatsFn: func(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.ShardGroupStats, error) {
+			return nil, nil
+		},
+		FindCompactionPlanFn: func(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.CompactionPlanGroup, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindShardGroupStats returns the stats of the shard groups of the bucket.
+func (s *ShardService) FindShardGroupStats(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.ShardGroupStats, error) {
+	return s.FindShardGroupStatsFn(ctx, bucketID)
+}
+
+// FindCompactionPlan returns the compactions planned for the data of the bucket.
+func (s *ShardService) FindCompactionPlan(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.CompactionPlanGroup, error) {
+	return s.FindCompactionPlanFn(ctx, bucketID)
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskBackfillService)(nil)
+
+// TaskBackfillService is a mock implementation of influxdb.TaskBackfillService.
+type TaskBackfillService struct {
+	BackfillTaskFn       func(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error)
+	FindTaskBackfillFn   func(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error)
+	CancelTaskBackfillFn func(ctx context.Context, taskID influxdb.ID) error
+}
+
+// NewTaskBackfillService returns a mock TaskBackfillService where its methods
+// will return zero values.
+func NewTaskBackfillService() *TaskBackfillService {
+	return &TaskBackfillService{
+		BackfillTaskFn: func(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+			return &influxdb.TaskBackfill{TaskID: taskID, Start: start, Stop: stop}, nil
+		},
+		FindTaskBackfillFn: func(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+			return &influxdb.TaskBackfill{TaskID: taskID}, nil
+		},
+		CancelTaskBackfillFn: func(ctx context.Context, taskID influxdb.ID) error { return nil },
+	}
+}
+
+// BackfillTask starts a backfill of a task.
+func (s *TaskBackfillService) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	return s.BackfillTaskFn(ctx, taskID, start, stop)
+}
+
+// FindTaskBackfill returns the latest backfill of a task.
+func (s *TaskBackfillService) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	return s.FindTaskBackfillFn(ctx, taskID)
+}
+
+// CancelTaskBackfill cancels the backfill of a task.
+func (s *TaskBackfillService) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	return s.CancelTaskBackfillFn(ctx, taskID)
+}
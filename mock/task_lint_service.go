@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskLintService = (*TaskLintService)(nil)
+
+// TaskLintService is a mock implementation of influxdb.TaskLintService.
+type TaskLintService struct {
+	LintTaskFn func(ctx context.Context, orgID influxdb.ID, script string) (*influxdb.TaskLintResult, error)
+}
+
+// NewTaskLintService returns a mock TaskLintService that finds every script valid.
+func NewTaskLintService() *TaskLintService {
+	return &TaskLintService{
+		LintTaskFn: func(ctx context.Context, orgID influxdb.ID, script string) (*influxdb.TaskLintResult, error) {
+			return &influxdb.TaskLintResult{Valid: true, Diagnostics: []influxdb.TaskDiagnostic{}}, nil
+		},
+	}
+}
+
+// LintTask checks the script of a task.
+func (s *TaskLintService) LintTask(ctx context.Context, orgID influxdb.ID, script string) (*influxdb.TaskLintResult, error) {
+	return s.LintTaskFn(ctx, orgID, script)
+}
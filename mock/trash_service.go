@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TrashService = (*TrashService)(nil)
+
+// TrashService is a mock implementation of influxdb.TrashService.
+type TrashService struct {
+	FindTrashedResourceByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error)
+	FindTrashedResourcesFn    func(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error)
+	RestoreDashboardFn        func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error)
+	RestoreTaskFn             func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error)
+}
+
+// NewTrashService returns a mock trash service where its methods will return zero values.
+func NewTrashService() *TrashService {
+	return &TrashService{
+		FindTrashedResourceByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error) {
+			return nil, nil
+		},
+		FindTrashedResourcesFn: func(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+			return nil, 0, nil
+		},
+		RestoreDashboardFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) { return nil, nil },
+		RestoreTaskFn:      func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) { return nil, nil },
+	}
+}
+
+// FindTrashedResourceByID returns a single trashed resource by ID.
+func (s *TrashService) FindTrashedResourceByID(ctx context.Context, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	return s.FindTrashedResourceByIDFn(ctx, id)
+}
+
+// FindTrashedResources returns a list of trashed resources that match the filter.
+func (s *TrashService) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	return s.FindTrashedResourcesFn(ctx, filter, opts...)
+}
+
+// RestoreDashboard moves a dashboard out of the trash.
+func (s *TrashService) RestoreDashboard(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+	return s.RestoreDashboardFn(ctx, id)
+}
+
+// RestoreTask moves a task out of the trash.
+func (s *TrashService) RestoreTask(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	return s.RestoreTaskFn(ctx, id)
+}
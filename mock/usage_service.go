@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.UsageService = (*UsageService)(nil)
+
+// UsageService is a mock implementation of influxdb.UsageService.
+type UsageService struct {
+	GetUsageFn func(context.Context, influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error)
+}
+
+// NewUsageService returns a mock UsageService where its methods will return
+// zero values.
+func NewUsageService() *UsageService {
+	return &UsageService{
+		GetUsageFn: func(context.Context, influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+			return map[influxdb.UsageMetric]*influxdb.Usage{}, nil
+		},
+	}
+}
+
+// GetUsage returns the usage matching the filter.
+func (s *UsageService) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	return s.GetUsageFn(ctx, filter)
+}
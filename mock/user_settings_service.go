@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.UserSettingsService = (*UserSettingsService)(nil)
+
+// UserSettingsService is a mock implementation of influxdb.UserSettingsService.
+type UserSettingsService struct {
+	FindUserSettingsFn  func(ctx context.Context, userID influxdb.ID) (*influxdb.UserSettings, error)
+	PutUserSettingFn    func(ctx context.Context, userID influxdb.ID, namespace string, value json.RawMessage) (*influxdb.UserSettings, error)
+	DeleteUserSettingFn func(ctx context.Context, userID influxdb.ID, namespace string) error
+}
+
+// NewUserSettingsService returns a mock UserSettingsService where its methods
+// will return empty settings of the user.
+func NewUserSettingsService() *UserSettingsService {
+	return &UserSettingsService{
+		FindUserSettingsFn: func(ctx context.Context, userID influxdb.ID) (*influxdb.UserSettings, error) {
+			return &influxdb.UserSettings{UserID: userID, Settings: map[string]json.RawMessage{}}, nil
+		},
+		PutUserSettingFn: func(ctx context.Context, userID influxdb.ID, namespace string, value json.RawMessage) (*influxdb.UserSettings, error) {
+			return &influxdb.UserSettings{UserID: userID, Settings: map[string]json.RawMessage{namespace: value}}, nil
+		},
+		DeleteUserSettingFn: func(ctx context.Context, userID influxdb.ID, namespace string) error {
+			return nil
+		},
+	}
+}
+
+// FindUserSettings returns the settings of a user.
+func (s *UserSettingsService) FindUserSettings(ctx context.Context, userID influxdb.ID) (*influxdb.UserSettings, error) {
+	return s.FindUserSettingsFn(ctx, userID)
+}
+
+// PutUserSetting replaces the value of a namespace of the settings of a user.
+func (s *UserSettingsService) PutUserSetting(ctx context.Context, userID influxdb.ID, namespace string, value json.RawMessage) (*influxdb.UserSettings, error) {
+	return s.PutUserSettingFn(ctx, userID, namespace, value)
+}
+
+// DeleteUserSetting removes a namespace from the settings of a user.
+func (s *UserSettingsService) DeleteUserSetting(ctx context.Context, userID influxdb.ID, namespace string) error {
+	return s.DeleteUserSettingFn(ctx, userID, namespace)
+}
@@ -393,17 +393,99 @@ func ParsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 	return parsePointsWithPrecision(buf, mm, defaultTime, precision, true)
 }
 
+// utf8BOM is the byte order mark that may prefix UTF-8 encoded line protocol.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseError describes a line of line protocol that could not be parsed.
+type ParseError struct {
+	// Line is the 1-based number of the line within the parsed buffer.
+	Line int
+	// Offset is the byte offset of the offending token within the parsed buffer.
+	Offset int
+	// Token is the whitespace delimited section of the line that failed to parse.
+	Token string
+	// Err is the reason the line failed to parse.
+	Err error
+
+	input string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("unable to parse '%s': %v", e.input, e.Err)
+}
+
+// ParseErrors are the errors of all the lines that failed to parse.
+type ParseErrors []*ParseError
+
+// Error implements the error interface.
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, pe := range e {
+		msgs = append(msgs, pe.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// parseErrorAt returns a ParseError for the token of buf starting at i.
+func parseErrorAt(buf []byte, i int, err error) *ParseError {
+	return &ParseError{
+		Offset: i,
+		Token:  string(buf[i:scanToken(buf, i)]),
+		Err:    err,
+	}
+}
+
+// scanToken returns the end position of the token starting at i, which
+// ends at the first unescaped space outside of a quoted string.
+func scanToken(buf []byte, i int) int {
+	quoted := false
+	for i < len(buf) {
+		switch {
+		case buf[i] == '\\' && i+1 < len(buf):
+			i += 2
+			continue
+		case buf[i] == '"':
+			quoted = !quoted
+		case buf[i] == ' ' && !quoted:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
 func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) (_ []Point, err error) {
 	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
 	var (
 		pos    int
 		block  []byte
-		failed []string
+		line   = 1
+		failed ParseErrors
 	)
+
+	// skip the UTF-8 byte order mark some clients prepend to the body.
+	if bytes.HasPrefix(buf, utf8BOM) {
+		pos = len(utf8BOM)
+	}
+
 	for pos < len(buf) {
+		offset, blockLine := pos, line
 		pos, block = scanLine(buf, pos)
+		line += bytes.Count(block, []byte{'\n'})
+		if pos < len(buf) {
+			line++
+		}
 		pos++
 
+		// strip the newline and carriage return if present
+		if len(block) > 0 && block[len(block)-1] == '\n' {
+			block = block[:len(block)-1]
+		}
+		if len(block) > 0 && block[len(block)-1] == '\r' {
+			block = block[:len(block)-1]
+		}
+
 		if len(block) == 0 {
 			continue
 		}
@@ -420,37 +502,36 @@ func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 			continue
 		}
 
-		// strip the newline if one is present
-		if block[len(block)-1] == '\n' {
-			block = block[:len(block)-1]
-		}
-
-		points, err = parsePointsAppend(points, block[start:], mm, defaultTime, precision, rewrite)
-		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
+		var perr *ParseError
+		points, perr = parsePointsAppend(points, block[start:], mm, defaultTime, precision, rewrite)
+		if perr != nil {
+			perr.Line = blockLine
+			perr.Offset += offset + start
+			perr.input = string(block[start:])
+			failed = append(failed, perr)
 		}
 	}
 	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+		return points, failed
 	}
 
 	return points, nil
 }
 
-func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) ([]Point, error) {
+func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) ([]Point, *ParseError) {
 	// scan the first block which is measurement[,tag1=value1,tag2=value=2...]
 	pos, key, err := scanKey(buf, 0)
 	if err != nil {
-		return nil, err
+		return points, parseErrorAt(buf, 0, err)
 	}
 
 	// measurement name is required
 	if len(key) == 0 {
-		return points, fmt.Errorf("missing measurement")
+		return points, parseErrorAt(buf, 0, fmt.Errorf("missing measurement"))
 	}
 
 	if len(key) > MaxKeyLength {
-		return points, fmt.Errorf("max key length exceeded: %v > %v", len(key), MaxKeyLength)
+		return points, parseErrorAt(buf, 0, fmt.Errorf("max key length exceeded: %v > %v", len(key), MaxKeyLength))
 	}
 
 	// Since the measurement is converted to a tag and measurements & tags have
@@ -468,17 +549,19 @@ func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.T
 
 	// scan the second block is which is field1=value1[,field2=value2,...]
 	// at least one field is required
+	fieldsPos := skipWhitespace(buf, pos)
 	pos, fields, err := scanFields(buf, pos)
 	if err != nil {
-		return points, err
+		return points, parseErrorAt(buf, fieldsPos, err)
 	} else if len(fields) == 0 {
-		return points, fmt.Errorf("missing fields")
+		return points, parseErrorAt(buf, fieldsPos, fmt.Errorf("missing fields"))
 	}
 
 	// scan the last block which is an optional integer timestamp
+	timePos := skipWhitespace(buf, pos)
 	pos, ts, err := scanTime(buf, pos)
 	if err != nil {
-		return points, err
+		return points, parseErrorAt(buf, timePos, err)
 	}
 
 	// Build point with timestamp only.
@@ -490,18 +573,18 @@ func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.T
 	} else {
 		ts, err := parseIntBytes(ts, 10, 64)
 		if err != nil {
-			return points, err
+			return points, parseErrorAt(buf, timePos, err)
 		}
 		pt.time, err = SafeCalcTime(ts, precision)
 		if err != nil {
-			return points, err
+			return points, parseErrorAt(buf, timePos, err)
 		}
 
 		// Determine if there are illegal non-whitespace characters after the
 		// timestamp block.
 		for pos < len(buf) {
 			if buf[pos] != ' ' {
-				return points, ErrInvalidPoint
+				return points, parseErrorAt(buf, pos, ErrInvalidPoint)
 			}
 			pos++
 		}
@@ -528,9 +611,9 @@ func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.T
 
 		return true
 	}); err != nil {
-		return points, err
+		return points, parseErrorAt(buf, fieldsPos, err)
 	} else if maxKeyErr != nil {
-		return points, maxKeyErr
+		return points, parseErrorAt(buf, fieldsPos, maxKeyErr)
 	}
 
 	return points, nil
@@ -564,6 +647,10 @@ func GetPrecisionMultiplier(precision string) int64 {
 		d = time.Millisecond
 	case "s":
 		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
 	}
 	return int64(d)
 }
@@ -1770,6 +1857,10 @@ func (p *point) SetPrecision(precision string) {
 		p.SetTime(p.Time().Truncate(time.Millisecond))
 	case "s":
 		p.SetTime(p.Time().Truncate(time.Second))
+	case "m":
+		p.SetTime(p.Time().Truncate(time.Minute))
+	case "h":
+		p.SetTime(p.Time().Truncate(time.Hour))
 	}
 }
 
@@ -2134,6 +2134,71 @@ cpu,host=serverA,region=us-east value=1.0 946730096789012345`,
 	}
 }
 
+func TestParsePointsWithPrecisionBOMAndCRLF(t *testing.T) {
+	batch := "\xEF\xBB\xBFcpu value=1.0 946730096789012345\r\n\r\nmem value=2.0 946730096789012345\r\n"
+	pts, err := models.ParsePointsWithPrecision([]byte(batch), []byte("mm"), time.Now().UTC(), "ns")
+	if err != nil {
+		t.Fatalf("ParsePoints() failed. got %s", err)
+	}
+
+	exp := []string{
+		"mm,\x00=cpu,\xff=value value=1.0 946730096789012345",
+		"mm,\x00=mem,\xff=value value=2.0 946730096789012345",
+	}
+	if len(pts) != len(exp) {
+		t.Fatalf("ParsePoint() len mismatch: got %v, exp %v", len(pts), len(exp))
+	}
+	for i, pt := range pts {
+		if got := pt.String(); got != exp[i] {
+			t.Errorf("ParsePoint() to string mismatch:\n got %v\n exp %v", got, exp[i])
+		}
+	}
+}
+
+func TestParsePointsWithPrecisionErrorPositions(t *testing.T) {
+	batch := "\xEF\xBB\xBFcpu value=1.0 1\r\n" +
+		"cpu,host value=1.0\n" +
+		"# comment\n" +
+		"cpu value=\"a\nb\" 1\n" +
+		"  cpu value=1.0 12a\n" +
+		"cpu value=1.0 1 trailing\n" +
+		"cpu\n"
+	pts, err := models.ParsePointsWithPrecision([]byte(batch), []byte("mm"), time.Now().UTC(), "ns")
+	if len(pts) != 2 {
+		t.Errorf("ParsePoint() len mismatch: got %v, exp %v", len(pts), 2)
+	}
+
+	errs, ok := err.(models.ParseErrors)
+	if !ok {
+		t.Fatalf("expected models.ParseErrors, got %T: %v", err, err)
+	}
+
+	exp := []struct {
+		line   int
+		offset int
+		token  string
+		msg    string
+	}{
+		{line: 2, offset: 20, token: "cpu,host", msg: "unable to parse 'cpu,host value=1.0': missing tag value"},
+		{line: 6, offset: 83, token: "12a", msg: "unable to parse 'cpu value=1.0 12a': bad timestamp"},
+		{line: 7, offset: 103, token: "trailing", msg: "unable to parse 'cpu value=1.0 1 trailing': point is invalid"},
+		{line: 8, offset: 112, token: "cpu", msg: "unable to parse 'cpu': missing fields"},
+	}
+	if len(errs) != len(exp) {
+		t.Fatalf("unexpected errors: %v", err)
+	}
+	for i, e := range exp {
+		got := errs[i]
+		if got.Line != e.line || got.Offset != e.offset || got.Token != e.token || got.Error() != e.msg {
+			t.Errorf("unexpected error %d:\n got line=%d offset=%d token=%q msg=%q\n exp line=%d offset=%d token=%q msg=%q",
+				i, got.Line, got.Offset, got.Token, got.Error(), e.line, e.offset, e.token, e.msg)
+		}
+		if batch[got.Offset:got.Offset+len(got.Token)] != got.Token {
+			t.Errorf("offset %d of error %d does not point at token %q", got.Offset, i, got.Token)
+		}
+	}
+}
+
 func TestNewPointEscaped(t *testing.T) {
 	// commas
 	pt := models.MustNewPoint("cpu,main", models.NewTags(map[string]string{"tag,bar": "value"}), models.Fields{"name,bar": 1.0}, time.Unix(0, 0))
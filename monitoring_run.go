@@ -0,0 +1,43 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for monitoring run errors and op logs.
+const (
+	OpFindCheckRuns            = "FindCheckRuns"
+	OpFindNotificationRuleRuns = "FindNotificationRuleRuns"
+)
+
+// MonitoringRunService lists the task runs of checks and notification rules
+// together with what each run wrote to the monitoring system bucket.
+type MonitoringRunService interface {
+	// FindCheckRuns returns the runs of the task of a check and the statuses
+	// written by each run. The task of the filter is ignored.
+	FindCheckRuns(ctx context.Context, checkID ID, filter RunFilter) ([]*MonitoringRun, int, error)
+
+	// FindNotificationRuleRuns returns the runs of the task of a notification
+	// rule and the notifications sent by each run. The task of the filter is
+	// ignored.
+	FindNotificationRuleRuns(ctx context.Context, ruleID ID, filter RunFilter) ([]*MonitoringRun, int, error)
+}
+
+// MonitoringRun is a task run of a check or notification rule.
+type MonitoringRun struct {
+	Run
+	Records []MonitoringRecord `json:"records"`
+}
+
+// MonitoringRecord is a status written by a check run or a notification
+// written by a notification rule run.
+type MonitoringRecord struct {
+	Time      time.Time `json:"time"`
+	CheckID   ID        `json:"checkID"`
+	CheckName string    `json:"checkName,omitempty"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message,omitempty"`
+	// Sent is only set for notifications.
+	Sent *bool `json:"sent,omitempty"`
+}
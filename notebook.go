@@ -0,0 +1,262 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ops for notebook errors and op logs.
+const (
+	OpFindNotebookByID     = "FindNotebookByID"
+	OpFindNotebooks        = "FindNotebooks"
+	OpCreateNotebook       = "CreateNotebook"
+	OpUpdateNotebook       = "UpdateNotebook"
+	OpDeleteNotebook       = "DeleteNotebook"
+	OpFindNotebookVersions = "FindNotebookVersions"
+	OpFindNotebookVersion  = "FindNotebookVersion"
+)
+
+// MaxNotebookVersions is the number of the most recent versions of a
+// notebook that are kept.
+const MaxNotebookVersions = 100
+
+// NotebookService represents a service for managing notebooks.
+type NotebookService interface {
+	// FindNotebookByID returns a single notebook by ID.
+	FindNotebookByID(ctx context.Context, id ID) (*Notebook, error)
+
+	// FindNotebooks returns a list of notebooks that match the filter and
+	// the total count of matching notebooks.
+	FindNotebooks(ctx context.Context, filter NotebookFilter, opt ...FindOptions) ([]*Notebook, int, error)
+
+	// CreateNotebook creates a new notebook and sets n.ID with the new
+	// identifier. The notebook is created at version 1.
+	CreateNotebook(ctx context.Context, n *Notebook) error
+
+	// UpdateNotebook updates a single notebook with the changeset. Each
+	// update creates a new version of the notebook.
+	UpdateNotebook(ctx context.Context, id ID, upd NotebookUpdate) (*Notebook, error)
+
+	// DeleteNotebook removes a notebook and its versions by ID.
+	DeleteNotebook(ctx context.Context, id ID) error
+
+	// FindNotebookVersions returns the kept versions of a notebook, the
+	// most recent first.
+	FindNotebookVersions(ctx context.Context, id ID) ([]*NotebookVersion, error)
+
+	// FindNotebookVersion returns a single version of a notebook.
+	FindNotebookVersion(ctx context.Context, id ID, version int) (*NotebookVersion, error)
+}
+
+// Notebook is a document of an organization for the multi-step analysis of
+// data: a sequence of cells of flux queries, markdown notes and
+// visualizations of the results of the queries.
+type Notebook struct {
+	ID             ID             `json:"id,omitempty"`
+	OrganizationID ID             `json:"orgID"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Cells          []NotebookCell `json:"cells"`
+	// Version is the number of the current version of the notebook. It
+	// starts at 1 and is incremented by each update.
+	Version int `json:"version"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the notebook is invalid.
+func (n *Notebook) Valid() error {
+	switch {
+	case !n.OrganizationID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notebook requires an organization",
+		}
+	case n.Name == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notebook requires a name",
+		}
+	}
+	return validNotebookCells(n.Cells)
+}
+
+// NotebookCellType is the type of a notebook cell.
+type NotebookCellType string
+
+// the types of notebook cells.
+const (
+	// NotebookCellTypeFlux is a cell of a flux query.
+	NotebookCellTypeFlux NotebookCellType = "flux"
+	// NotebookCellTypeMarkdown is a cell of markdown text.
+	NotebookCellTypeMarkdown NotebookCellType = "markdown"
+	// NotebookCellTypeVisualization is a cell visualizing the results of
+	// the query of a flux cell.
+	NotebookCellTypeVisualization NotebookCellType = "visualization"
+)
+
+// NotebookCell is a cell of a notebook.
+type NotebookCell struct {
+	NotebookCellContents
+	// Properties are the view properties of a visualization cell.
+	Properties ViewProperties
+}
+
+// NotebookCellContents are the contents of a notebook cell but its view
+// properties.
+type NotebookCellContents struct {
+	ID   ID               `json:"id,omitempty"`
+	Type NotebookCellType `json:"type"`
+	Name string           `json:"name,omitempty"`
+	// Query is the flux query of a flux cell.
+	Query string `json:"query,omitempty"`
+	// Text is the markdown of a markdown cell.
+	Text string `json:"text,omitempty"`
+	// SourceID is the flux cell of the notebook whose results a
+	// visualization cell visualizes.
+	SourceID *ID `json:"sourceID,omitempty"`
+}
+
+// MarshalJSON encodes a notebook cell to JSON bytes.
+func (c NotebookCell) MarshalJSON() ([]byte, error) {
+	if c.Type != NotebookCellTypeVisualization || c.Properties == nil {
+		return json.Marshal(c.NotebookCellContents)
+	}
+
+	vis, err := MarshalViewPropertiesJSON(c.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		NotebookCellContents
+		ViewProperties json.RawMessage `json:"properties"`
+	}{
+		NotebookCellContents: c.NotebookCellContents,
+		ViewProperties:       vis,
+	})
+}
+
+// UnmarshalJSON decodes JSON bytes into a notebook cell. Only the view
+// properties of visualization cells are decoded.
+func (c *NotebookCell) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &c.NotebookCellContents); err != nil {
+		return err
+	}
+	if c.Type != NotebookCellTypeVisualization {
+		return nil
+	}
+
+	v, err := UnmarshalViewPropertiesJSON(b)
+	if err != nil {
+		return err
+	}
+	c.Properties = v
+	return nil
+}
+
+// validNotebookCells returns an error if a cell is invalid or the cells
+// do not have unique IDs. Cells without an ID are given one when the
+// notebook is saved.
+func validNotebookCells(cells []NotebookCell) error {
+	types := make(map[ID]NotebookCellType, len(cells))
+	for _, c := range cells {
+		if !c.ID.Valid() {
+			continue
+		}
+		if _, ok := types[c.ID]; ok {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("notebook cell ID %s is not unique", c.ID),
+			}
+		}
+		types[c.ID] = c.Type
+	}
+
+	for _, c := range cells {
+		switch c.Type {
+		case NotebookCellTypeFlux, NotebookCellTypeMarkdown:
+		case NotebookCellTypeVisualization:
+			if c.SourceID == nil {
+				continue
+			}
+			if types[*c.SourceID] != NotebookCellTypeFlux {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("source %s of notebook cell must be a flux cell of the notebook", *c.SourceID),
+				}
+			}
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg: fmt.Sprintf("invalid notebook cell type %q; must be %s, %s or %s",
+					c.Type, NotebookCellTypeFlux, NotebookCellTypeMarkdown, NotebookCellTypeVisualization),
+			}
+		}
+	}
+	return nil
+}
+
+// NotebookVersion is the content of a notebook as of one of its versions.
+type NotebookVersion struct {
+	NotebookID  ID             `json:"notebookID"`
+	Version     int            `json:"version"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Cells       []NotebookCell `json:"cells"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+// NotebookFilter represents a set of filters that restrict the returned
+// notebooks.
+type NotebookFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Name           *string
+}
+
+// NotebookUpdate represents updates to a notebook. Only fields which are set
+// are updated.
+type NotebookUpdate struct {
+	Name        *string         `json:"name,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Cells       *[]NotebookCell `json:"cells,omitempty"`
+	// Version, if set, is the version of the notebook the update was made
+	// to. The update is rejected with a conflict if the notebook has been
+	// updated since, so that concurrent edits are not lost.
+	Version *int `json:"version,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u NotebookUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Cells == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "must update at least one attribute",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the notebook, or returns a conflict if the
+// notebook is not at the version of the update.
+func (u NotebookUpdate) Apply(n *Notebook) error {
+	if u.Version != nil && *u.Version != n.Version {
+		return &Error{
+			Code: EConflict,
+			Msg:  fmt.Sprintf("notebook was updated to version %d since version %d", n.Version, *u.Version),
+		}
+	}
+	if u.Name != nil {
+		n.Name = *u.Name
+	}
+	if u.Description != nil {
+		n.Description = *u.Description
+	}
+	if u.Cells != nil {
+		n.Cells = *u.Cells
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = &Anomaly{}
+
+// Anomaly detection algorithms.
+const (
+	// AnomalyMAD compares values to the median of the history, scaled by
+	// the median absolute deviation. It is robust to outliers in the history.
+	AnomalyMAD = "mad"
+	// AnomalyZScore compares values to the mean of the history, scaled by
+	// its standard deviation.
+	AnomalyZScore = "zscore"
+	// AnomalyHoltWinters compares values to the Holt-Winters forecast of
+	// the history, scaled by the error of the fit of the history. It
+	// follows trends and, with a seasonality, seasons.
+	AnomalyHoltWinters = "holtWinters"
+)
+
+// DefaultAnomalySensitivity is the sensitivity of anomaly checks that do not
+// set one.
+const DefaultAnomalySensitivity = 3.0
+
+// Anomaly is the anomaly check. It alerts on the latest value of each series
+// deviating from the value expected from the history of the series by more
+// than the sensitivity, in standard deviations.
+type Anomaly struct {
+	Base
+	Algorithm string `json:"algorithm"`
+	// Sensitivity is the number of standard deviations by which a value
+	// deviates from the expected value before it is anomalous.
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+	// History is how far back the values the latest value is compared to go.
+	History *notification.Duration `json:"history,omitempty"`
+	// Seasonality is the number of values in a season of the Holt-Winters
+	// algorithm. Forecasts are not seasonal if it is less than 2.
+	Seasonality int                     `json:"seasonality,omitempty"`
+	Level       notification.CheckLevel `json:"level"`
+}
+
+// Type returns the type of the check.
+func (c Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (c Anomaly) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	switch c.Algorithm {
+	case AnomalyMAD, AnomalyZScore, AnomalyHoltWinters:
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid anomaly detection algorithm %q", c.Algorithm),
+		}
+	}
+	if c.Sensitivity < 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Sensitivity can't be negative",
+		}
+	}
+	if c.History == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "History is required",
+		}
+	}
+	if c.Every != nil && c.History.TimeDuration() <= c.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "History should be greater than the interval",
+		}
+	}
+	if c.Seasonality < 0 || c.Seasonality > 0 && c.Algorithm != AnomalyHoltWinters {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Seasonality must be positive and only applies to the holtWinters algorithm",
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the anomaly check provided.
+func (c Anomaly) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly check provided. The query
+// reads the history of each series, aggregated every interval of the check,
+// and its latest value is tested against the values preceding it. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Anomaly) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(c.Query.Text)
+	if c.Every != nil {
+		replaceDurationsWithEvery(p, c.Every)
+	}
+	replaceRangeStart(p, c.History)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	field, err := getSelectedField(c.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/anomaly", "influxdata/influxdb/v1")...)
+	f.Body = append(f.Body, c.generateFluxASTBody(field)...)
+
+	return p, nil
+}
+
+func (c Anomaly) generateFluxASTBody(field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("anomaly"))
+	statements = append(statements, c.generateLevelFn())
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	statements = append(statements, c.generateFluxASTChecksFunction(field))
+	return statements
+}
+
+func (c Anomaly) generateLevelFn() ast.Statement {
+	fn := flux.Function(flux.FunctionParams("r"), flux.Member("r", "_anomaly"))
+
+	lvl := strings.ToLower(c.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+func (c Anomaly) generateFluxASTChecksFunction(field string) ast.Statement {
+	sensitivity := c.Sensitivity
+	if sensitivity == 0 {
+		sensitivity = DefaultAnomalySensitivity
+	}
+	props := []*ast.Property{
+		flux.Property("algorithm", flux.String(c.Algorithm)),
+		flux.Property("sensitivity", flux.Float(sensitivity)),
+		flux.Property("column", flux.String(field)),
+	}
+	if c.Seasonality > 0 {
+		props = append(props, flux.Property("seasonality", flux.Integer(int64(c.Seasonality))))
+	}
+
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		flux.Call(flux.Member("anomaly", "detect"), flux.Object(props...)),
+		c.generateFluxASTChecksCall(),
+	))
+}
+
+func (c Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	lvl := strings.ToLower(c.Level.String())
+	objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+// replaceRangeStart sets the start of the range of the query to d before now.
+func replaceRangeStart(pkg *ast.Package, d *notification.Duration) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if id, ok := call.Callee.(*ast.Identifier); ok && id.Name == "range" {
+				for _, args := range call.Arguments {
+					if obj, ok := args.(*ast.ObjectExpression); ok {
+						for _, prop := range obj.Properties {
+							if prop.Key.Key() == "start" {
+								start := (ast.DurationLiteral)(*d)
+								prop.Value = flux.Negative(&start)
+							}
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(c),
+			Type:         c.Type(),
+		})
+}
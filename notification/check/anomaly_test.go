@@ -0,0 +1,135 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	c := check.Anomaly{
+		Base: check.Base{
+			ID:   10,
+			Name: "moo",
+			Tags: []influxdb.Tag{
+				{Key: "aaa", Value: "vaaa"},
+			},
+			Every:                 mustDuration("1m"),
+			StatusMessageTemplate: "whoa! {r.usage_user}",
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+				BuilderConfig: influxdb.BuilderConfig{
+					Tags: []struct {
+						Key    string   `json:"key"`
+						Values []string `json:"values"`
+					}{
+						{
+							Key:    "_field",
+							Values: []string{"usage_user"},
+						},
+					},
+				},
+			},
+		},
+		Algorithm:   check.AnomalyHoltWinters,
+		History:     mustDuration("6h"),
+		Seasonality: 60,
+		Level:       notification.Warn,
+	}
+
+	want := `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/anomaly"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -6h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+warn = (r) =>
+	(r._anomaly)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+data
+	|> v1.fieldsAsCols()
+	|> anomaly.detect(
+		algorithm: "holtWinters",
+		sensitivity: 3.0,
+		column: "usage_user",
+		seasonality: 60,
+	)
+	|> monitor.check(data: check, messageFn: messageFn, warn: warn)`
+
+	s, err := c.GenerateFlux()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s != want {
+		t.Errorf("expected:\n%v\n\ngot:\n%v\n", want, s)
+	}
+}
+
+func TestAnomaly_Valid(t *testing.T) {
+	base := goodBase
+	base.Every = mustDuration("1m")
+
+	tests := []struct {
+		name  string
+		check check.Anomaly
+		err   string
+	}{
+		{
+			name:  "valid",
+			check: check.Anomaly{Base: base, Algorithm: check.AnomalyMAD, History: mustDuration("1h")},
+		},
+		{
+			name:  "unknown algorithm",
+			check: check.Anomaly{Base: base, Algorithm: "magic", History: mustDuration("1h")},
+			err:   `invalid anomaly detection algorithm "magic"`,
+		},
+		{
+			name:  "negative sensitivity",
+			check: check.Anomaly{Base: base, Algorithm: check.AnomalyZScore, Sensitivity: -1, History: mustDuration("1h")},
+			err:   "Sensitivity can't be negative",
+		},
+		{
+			name:  "missing history",
+			check: check.Anomaly{Base: base, Algorithm: check.AnomalyZScore},
+			err:   "History is required",
+		},
+		{
+			name:  "history shorter than the interval",
+			check: check.Anomaly{Base: base, Algorithm: check.AnomalyZScore, History: mustDuration("30s")},
+			err:   "History should be greater than the interval",
+		},
+		{
+			name:  "seasonality of another algorithm",
+			check: check.Anomaly{Base: base, Algorithm: check.AnomalyMAD, History: mustDuration("1h"), Seasonality: 10},
+			err:   "Seasonality must be positive and only applies to the holtWinters algorithm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check.Valid()
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || influxdb.ErrorMessage(err) != tt.err) {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -219,6 +219,27 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple anomaly",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Algorithm:   check.AnomalyHoltWinters,
+				Sensitivity: 2.5,
+				History:     mustDuration("7d"),
+				Seasonality: 24,
+				Level:       notification.Critical,
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -45,11 +45,14 @@ type thresholdDecode struct {
 
 type thresholdConfigDecode struct {
 	ThresholdConfigBase
-	Type   string  `json:"type"`
-	Value  float64 `json:"value"`
-	Min    float64 `json:"min"`
-	Max    float64 `json:"max"`
-	Within bool    `json:"within"`
+	Type          string  `json:"type"`
+	Value         float64 `json:"value"`
+	ValueVariable string  `json:"valueVariable"`
+	Min           float64 `json:"min"`
+	MinVariable   string  `json:"minVariable"`
+	Max           float64 `json:"max"`
+	MaxVariable   string  `json:"maxVariable"`
+	Within        bool    `json:"within"`
 }
 
 // UnmarshalJSON implement json.Unmarshaler interface.
@@ -65,19 +68,23 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 			td := &Lesser{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
+				ValueVariable:       tdRaw.ValueVariable,
 			}
 			t.Thresholds = append(t.Thresholds, td)
 		case "greater":
 			td := &Greater{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
+				ValueVariable:       tdRaw.ValueVariable,
 			}
 			t.Thresholds = append(t.Thresholds, td)
 		case "range":
 			td := &Range{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Min:                 tdRaw.Min,
+				MinVariable:         tdRaw.MinVariable,
 				Max:                 tdRaw.Max,
+				MaxVariable:         tdRaw.MaxVariable,
 				Within:              tdRaw.Within,
 			}
 			t.Thresholds = append(t.Thresholds, td)
@@ -141,8 +148,9 @@ func (t Threshold) GenerateFluxAST() (*ast.Package, error) {
 	return p, nil
 }
 
-func (t Threshold) getSelectedField() (string, error) {
-	for _, kv := range t.Query.BuilderConfig.Tags {
+// getSelectedField returns the field selected in the builder config of the query.
+func getSelectedField(q influxdb.DashboardQuery) (string, error) {
+	for _, kv := range q.BuilderConfig.Tags {
 		if kv.Key == "_field" && len(kv.Values) != 1 {
 			return "", fmt.Errorf("expect there to be a single field value in builder config")
 		}
@@ -283,7 +291,7 @@ func (t Threshold) generateFluxASTChecksCall() *ast.CallExpression {
 func (t Threshold) generateFluxASTThresholdFunctions() []ast.Statement {
 	thresholdStatements := make([]ast.Statement, len(t.Thresholds))
 
-	field, err := t.getSelectedField()
+	field, err := getSelectedField(t.Query)
 	if err != nil {
 		// the error here should never happen since it should be validated before this
 		// function is ever called.
@@ -298,7 +306,7 @@ func (t Threshold) generateFluxASTThresholdFunctions() []ast.Statement {
 }
 
 func (td Greater) generateFluxASTThresholdFunction(field string) ast.Statement {
-	fnBody := flux.GreaterThan(flux.Member("r", field), flux.Float(td.Value))
+	fnBody := flux.GreaterThan(flux.Member("r", field), thresholdValue(td.Value, td.ValueVariable))
 	fn := flux.Function(flux.FunctionParams("r"), fnBody)
 
 	lvl := strings.ToLower(td.Level.String())
@@ -307,7 +315,7 @@ func (td Greater) generateFluxASTThresholdFunction(field string) ast.Statement {
 }
 
 func (td Lesser) generateFluxASTThresholdFunction(field string) ast.Statement {
-	fnBody := flux.LessThan(flux.Member("r", field), flux.Float(td.Value))
+	fnBody := flux.LessThan(flux.Member("r", field), thresholdValue(td.Value, td.ValueVariable))
 	fn := flux.Function(flux.FunctionParams("r"), fnBody)
 
 	lvl := strings.ToLower(td.Level.String())
@@ -316,16 +324,17 @@ func (td Lesser) generateFluxASTThresholdFunction(field string) ast.Statement {
 }
 
 func (td Range) generateFluxASTThresholdFunction(field string) ast.Statement {
+	min, max := thresholdValue(td.Min, td.MinVariable), thresholdValue(td.Max, td.MaxVariable)
 	var fnBody *ast.LogicalExpression
 	if !td.Within {
 		fnBody = flux.Or(
-			flux.LessThan(flux.Member("r", field), flux.Float(td.Min)),
-			flux.GreaterThan(flux.Member("r", field), flux.Float(td.Max)),
+			flux.LessThan(flux.Member("r", field), min),
+			flux.GreaterThan(flux.Member("r", field), max),
 		)
 	} else {
 		fnBody = flux.And(
-			flux.LessThan(flux.Member("r", field), flux.Float(td.Max)),
-			flux.GreaterThan(flux.Member("r", field), flux.Float(td.Min)),
+			flux.LessThan(flux.Member("r", field), max),
+			flux.GreaterThan(flux.Member("r", field), min),
 		)
 	}
 
@@ -336,6 +345,15 @@ func (td Range) generateFluxASTThresholdFunction(field string) ast.Statement {
 	return flux.DefineVariable(lvl, fn)
 }
 
+// thresholdValue returns the value of a threshold, which is the value of the
+// variable of the organization with the name if one is set.
+func thresholdValue(value float64, variable string) ast.Expression {
+	if variable == "" {
+		return flux.Float(value)
+	}
+	return flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", flux.Member(VariablesIdentifier, variable))))
+}
+
 type thresholdAlias Threshold
 
 // MarshalJSON implement json.Marshaler interface.
@@ -380,6 +398,9 @@ func (b ThresholdConfigBase) GetLevel() notification.CheckLevel {
 type Lesser struct {
 	ThresholdConfigBase
 	Value float64 `json:"value,omitempty"`
+	// ValueVariable is the name of the variable of the organization whose
+	// value is used instead of Value, if set.
+	ValueVariable string `json:"valueVariable,omitempty"`
 }
 
 // Type of the threshold config.
@@ -405,6 +426,9 @@ func (td Lesser) MarshalJSON() ([]byte, error) {
 type Greater struct {
 	ThresholdConfigBase
 	Value float64 `json:"value,omitempty"`
+	// ValueVariable is the name of the variable of the organization whose
+	// value is used instead of Value, if set.
+	ValueVariable string `json:"valueVariable,omitempty"`
 }
 
 // Type of the threshold config.
@@ -429,9 +453,15 @@ func (td Greater) MarshalJSON() ([]byte, error) {
 // Range threshold type.
 type Range struct {
 	ThresholdConfigBase
-	Min    float64 `json:"min,omitempty"`
-	Max    float64 `json:"max,omitempty"`
-	Within bool    `json:"within"`
+	Min float64 `json:"min,omitempty"`
+	// MinVariable is the name of the variable of the organization whose
+	// value is used instead of Min, if set.
+	MinVariable string  `json:"minVariable,omitempty"`
+	Max         float64 `json:"max,omitempty"`
+	// MaxVariable is the name of the variable of the organization whose
+	// value is used instead of Max, if set.
+	MaxVariable string `json:"maxVariable,omitempty"`
+	Within      bool   `json:"within"`
 }
 
 // Type of the threshold config.
@@ -455,7 +485,13 @@ func (td Range) MarshalJSON() ([]byte, error) {
 
 // Valid overwrite the base threshold.
 func (td Range) Valid() error {
-	if td.Min > td.Max {
+	if err := validThresholdVariable(td.MinVariable); err != nil {
+		return err
+	}
+	if err := validThresholdVariable(td.MaxVariable); err != nil {
+		return err
+	}
+	if td.MinVariable == "" && td.MaxVariable == "" && td.Min > td.Max {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Msg:  "range threshold min can't be larger than max",
@@ -463,3 +499,31 @@ func (td Range) Valid() error {
 	}
 	return nil
 }
+
+// Valid returns error if the variable of the threshold is invalid.
+func (td Lesser) Valid() error {
+	return validThresholdVariable(td.ValueVariable)
+}
+
+// Valid returns error if the variable of the threshold is invalid.
+func (td Greater) Valid() error {
+	return validThresholdVariable(td.ValueVariable)
+}
+
+// validThresholdVariable returns an error if the name of the variable of a
+// threshold can not be referenced from flux, e.g. v.name.
+func validThresholdVariable(name string) error {
+	if name == "" {
+		return nil
+	}
+	for i, c := range name {
+		if c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9' {
+			continue
+		}
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("threshold variable %q is not a valid identifier", name),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// VariablesIdentifier is the identifier through which check queries
+// reference the variables of their organization, e.g. v.environment.
+const VariablesIdentifier = "v"
+
+// ReferencedVariables returns the names of the variables referenced by the
+// flux script in the order they are first referenced.
+func ReferencedVariables(script string) []string {
+	return referencedVariables(parser.ParseSource(script))
+}
+
+func referencedVariables(pkg *ast.Package) []string {
+	seen := map[string]bool{}
+	var names []string
+	ast.Visit(pkg, func(n ast.Node) {
+		m, ok := n.(*ast.MemberExpression)
+		if !ok {
+			return
+		}
+		if id, ok := m.Object.(*ast.Identifier); !ok || id.Name != VariablesIdentifier {
+			return
+		}
+		name := m.Property.Key()
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// ResolveVariables defines the variables referenced by the flux script of a
+// check with an option statement, so that the task of the check runs with the
+// current values of the variables. A script that references no variables is
+// returned as is.
+func ResolveVariables(script string, vars []*influxdb.Variable) (string, error) {
+	p := parser.ParseSource(script)
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return "", multiError(errs)
+	}
+
+	names := referencedVariables(p)
+	if len(names) == 0 {
+		return script, nil
+	}
+	numeric := numericVariables(p)
+
+	byName := make(map[string]*influxdb.Variable, len(vars))
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+
+	props := make([]*ast.Property, 0, len(names))
+	for _, name := range names {
+		v, ok := byName[name]
+		if !ok {
+			return "", &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("check references unknown variable %q", name),
+			}
+		}
+		value, err := variableValue(v)
+		if err != nil {
+			return "", err
+		}
+		if _, err := strconv.ParseFloat(value, 64); numeric[name] && err != nil {
+			return "", &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("variable %q is used as a number, e.g. by a threshold, but its value %q is not a number", name, value),
+			}
+		}
+		props = append(props, flux.Property(name, flux.String(value)))
+	}
+
+	f := p.Files[0]
+	opt := &ast.OptionStatement{
+		Assignment: flux.DefineVariable(VariablesIdentifier, flux.Object(props...)),
+	}
+	f.Body = append([]ast.Statement{opt}, f.Body...)
+
+	return ast.Format(p), nil
+}
+
+// numericVariables returns the names of the variables that are converted to
+// numbers, as the values of thresholds are, e.g. float(v: v.limit).
+func numericVariables(pkg *ast.Package) map[string]bool {
+	names := map[string]bool{}
+	ast.Visit(pkg, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || len(call.Arguments) != 1 {
+			return
+		}
+		if id, ok := call.Callee.(*ast.Identifier); !ok || id.Name != "float" {
+			return
+		}
+		obj, ok := call.Arguments[0].(*ast.ObjectExpression)
+		if !ok {
+			return
+		}
+		for _, prop := range obj.Properties {
+			m, ok := prop.Value.(*ast.MemberExpression)
+			if !ok || prop.Key.Key() != "v" {
+				continue
+			}
+			if id, ok := m.Object.(*ast.Identifier); ok && id.Name == VariablesIdentifier {
+				names[m.Property.Key()] = true
+			}
+		}
+	})
+	return names
+}
+
+// variableValue returns the selected value of a constant or map variable,
+// falling back to its first value. Query variables can not be resolved
+// without running their query and are rejected.
+func variableValue(v *influxdb.Variable) (string, error) {
+	var args interface{}
+	if v.Arguments != nil {
+		args = v.Arguments.Values
+	}
+
+	switch values := args.(type) {
+	case influxdb.VariableConstantValues:
+		if len(values) == 0 {
+			break
+		}
+		for _, value := range values {
+			if len(v.Selected) > 0 && value == v.Selected[0] {
+				return value, nil
+			}
+		}
+		return values[0], nil
+	case influxdb.VariableMapValues:
+		if len(values) == 0 {
+			break
+		}
+		if len(v.Selected) > 0 {
+			if value, ok := values[v.Selected[0]]; ok {
+				return value, nil
+			}
+		}
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return values[keys[0]], nil
+	}
+
+	return "", &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("variable %q can not be used in a check; only constant and map variables with values are supported", v.Name),
+	}
+}
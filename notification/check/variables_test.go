@@ -0,0 +1,173 @@
+package check_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestReferencedVariables(t *testing.T) {
+	script := `from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r.env == v.environment and r._value > float(v: v.limit))
+	|> filter(fn: (r) => r.host == v.environment)`
+
+	got := check.ReferencedVariables(script)
+	if want := []string{"environment", "limit"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReferencedVariables() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveVariables(t *testing.T) {
+	vars := []*influxdb.Variable{
+		{
+			Name:      "environment",
+			Selected:  []string{"staging"},
+			Arguments: &influxdb.VariableArguments{Type: "constant", Values: influxdb.VariableConstantValues{"production", "staging"}},
+		},
+		{
+			Name:      "limit",
+			Arguments: &influxdb.VariableArguments{Type: "map", Values: influxdb.VariableMapValues{"low": "10", "high": "90"}},
+		},
+		{
+			Name:      "hosts",
+			Arguments: &influxdb.VariableArguments{Type: "query", Values: influxdb.VariableQueryValues{Query: "buckets()", Language: "flux"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "no variables",
+			script: `from(bucket: "foo") |> range(start: -1h)`,
+			want:   `from(bucket: "foo") |> range(start: -1h)`,
+		},
+		{
+			name:   "constant and map variables",
+			script: `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r.env == v.environment and r._value > float(v: v.limit))`,
+			want: `package main
+option v = {environment: "staging", limit: "90"}
+
+from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r.env == v.environment and r._value > float(v: v.limit)))`,
+		},
+		{
+			name:    "unknown variable",
+			script:  `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r.env == v.region)`,
+			wantErr: true,
+		},
+		{
+			name:    "variable used as a number",
+			script:  `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._value > float(v: v.environment))`,
+			wantErr: true,
+		},
+		{
+			name:    "query variable",
+			script:  `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r.host == v.hosts)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := check.ResolveVariables(tt.script, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if influxdb.ErrorCode(err) != influxdb.EInvalid {
+					t.Fatalf("ResolveVariables() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EInvalid)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Fatalf("ResolveVariables() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreshold_VariableValue(t *testing.T) {
+	c := check.Threshold{
+		Base: check.Base{
+			ID:    10,
+			Name:  "moo",
+			Every: mustDuration("1m"),
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "foo") |> range(start: -1m) |> filter(fn: (r) => r._field == "usage_user")`,
+				BuilderConfig: influxdb.BuilderConfig{
+					Tags: []struct {
+						Key    string   `json:"key"`
+						Values []string `json:"values"`
+					}{
+						{
+							Key:    "_field",
+							Values: []string{"usage_user"},
+						},
+					},
+				},
+			},
+		},
+		Thresholds: []check.ThresholdConfig{
+			check.Greater{
+				ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical},
+				ValueVariable:       "limit",
+			},
+			check.Range{
+				ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Warn},
+				Min:                 10,
+				MaxVariable:         "limit",
+				Within:              true,
+			},
+		},
+	}
+	for _, td := range c.Thresholds {
+		if err := td.Valid(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	script, err := c.GenerateFlux()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`(r.usage_user > float(v: v.limit))`,
+		`(r.usage_user < float(v: v.limit) and r.usage_user > 10.0)`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected script to contain %s, got:\n%s", want, script)
+		}
+	}
+
+	limit := &influxdb.Variable{
+		Name:      "limit",
+		Arguments: &influxdb.VariableArguments{Type: "constant", Values: influxdb.VariableConstantValues{"90"}},
+	}
+	got, err := check.ResolveVariables(script, []*influxdb.Variable{limit})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, `option v = {limit: "90"}`) {
+		t.Errorf("expected the variable to be resolved, got:\n%s", got)
+	}
+
+	limit.Arguments.Values = influxdb.VariableConstantValues{"high"}
+	if _, err := check.ResolveVariables(script, []*influxdb.Variable{limit}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a threshold variable that is not a number to be invalid, got %v", err)
+	}
+
+	if err := (check.Greater{ValueVariable: "my-limit"}).Valid(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a threshold variable that is not an identifier to be invalid, got %v", err)
+	}
+}
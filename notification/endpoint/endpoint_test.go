@@ -1,8 +1,11 @@
 package endpoint_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -403,3 +406,101 @@ func strPtr(s string) *string {
 	*ss = s
 	return ss
 }
+
+func TestTest(t *testing.T) {
+	var got struct {
+		method, auth string
+		body         map[string]interface{}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method, got.auth, got.body = r.Method, r.Header.Get("Authorization"), nil
+		if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+			t.Errorf("unexpected body: %v", err)
+		}
+		if r.URL.Path == "/large" {
+			w.Write(bytes.Repeat([]byte("x"), 2000))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	defer func(url string) { endpoint.PagerDutyURL = url }(endpoint.PagerDutyURL)
+	endpoint.PagerDutyURL = srv.URL
+
+	secrets := map[string]string{id1 + "-token": "secret-token", id1 + "-routing-key": "secret-key"}
+	secret := func(key string) (string, error) {
+		if v, ok := secrets[key]; ok {
+			return v, nil
+		}
+		return "", &influxdb.Error{Code: influxdb.ENotFound, Msg: "secret not found"}
+	}
+
+	cases := []struct {
+		name   string
+		edp    influxdb.NotificationEndpoint
+		method string
+		auth   string
+		field  string
+		value  interface{}
+		body   string
+		trunc  bool
+	}{
+		{
+			name:   "slack webhook",
+			edp:    &endpoint.Slack{Base: goodBase, URL: srv.URL},
+			method: "POST",
+			field:  "text",
+			value:  endpoint.TestMessage,
+			body:   "ok",
+		},
+		{
+			name:   "pagerduty",
+			edp:    &endpoint.PagerDuty{Base: goodBase, RoutingKey: influxdb.SecretField{Key: id1 + "-routing-key"}},
+			method: "POST",
+			field:  "routing_key",
+			value:  "secret-key",
+			body:   "ok",
+		},
+		{
+			name: "http with bearer token",
+			edp: &endpoint.HTTP{
+				Base:       goodBase,
+				URL:        srv.URL + "/large",
+				Method:     "PUT",
+				AuthMethod: "bearer",
+				Token:      influxdb.SecretField{Key: id1 + "-token"},
+			},
+			method: "PUT",
+			auth:   "Bearer secret-token",
+			field:  "_message",
+			value:  endpoint.TestMessage,
+			body:   string(bytes.Repeat([]byte("x"), 1024)),
+			trunc:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := endpoint.Test(context.Background(), srv.Client(), c.edp, endpoint.TestOptions{}, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.method != c.method || got.auth != c.auth {
+				t.Errorf("got %s request with authorization %q, want %s with %q", got.method, got.auth, c.method, c.auth)
+			}
+			if v := got.body[c.field]; v != c.value {
+				t.Errorf("got %s %v, want %v", c.field, v, c.value)
+			}
+			if res.Body != c.body || res.Truncated != c.trunc {
+				t.Errorf("unexpected response body %q (truncated %v)", res.Body, res.Truncated)
+			}
+		})
+	}
+
+	// Missing secrets are reported.
+	_, err := endpoint.Test(context.Background(), srv.Client(), &endpoint.PagerDuty{Base: goodBase, RoutingKey: influxdb.SecretField{Key: "missing"}}, endpoint.TestOptions{}, secret)
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid error", err)
+	}
+}
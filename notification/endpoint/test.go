@@ -0,0 +1,198 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// TestMessage is the message of the notifications sent to test endpoints.
+const TestMessage = "This is a test notification from InfluxDB."
+
+// maxTestResponseBody is the length of the excerpt of the body of the
+// response to a test notification.
+const maxTestResponseBody = 1024
+
+var (
+	// PagerDutyURL is the URL of the PagerDuty events API.
+	PagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+	// SlackURL is the URL of the Slack API posting messages, used by Slack
+	// endpoints that have a token but no webhook URL.
+	SlackURL = "https://slack.com/api/chat.postMessage"
+)
+
+// TestOptions are the options of a test notification.
+type TestOptions struct {
+	// Channel is the Slack channel to post the test message to. Webhook URLs
+	// already determine the channel; it is required for tokens otherwise.
+	Channel string `json:"channel,omitempty"`
+}
+
+// TestResult is the response of the provider of an endpoint to a test
+// notification.
+type TestResult struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"` // The first bytes of the body.
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+// Test sends a synthetic notification through the endpoint and returns the
+// response of its provider. The values of the secret fields of the endpoint
+// are loaded with secret.
+func Test(ctx context.Context, client *http.Client, edp influxdb.NotificationEndpoint, opts TestOptions, secret func(key string) (string, error)) (*TestResult, error) {
+	req, err := newTestRequest(edp, opts, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "failed to send test notification",
+			Err:  err,
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTestResponseBody+1))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "failed to read the response to the test notification",
+			Err:  err,
+		}
+	}
+
+	r := &TestResult{StatusCode: resp.StatusCode}
+	if len(body) > maxTestResponseBody {
+		body, r.Truncated = body[:maxTestResponseBody], true
+	}
+	r.Body = string(body)
+	return r, nil
+}
+
+// newTestRequest returns the request sending a test notification through
+// the endpoint.
+func newTestRequest(edp influxdb.NotificationEndpoint, opts TestOptions, secret func(key string) (string, error)) (*http.Request, error) {
+	load := func(f influxdb.SecretField) (string, error) {
+		if f.Key == "" {
+			return "", nil
+		}
+		v, err := secret(f.Key)
+		if err != nil {
+			return "", &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("failed to load secret %q of the notification endpoint", f.Key),
+				Err:  err,
+			}
+		}
+		return v, nil
+	}
+
+	switch e := edp.(type) {
+	case *Slack:
+		token, err := load(e.Token)
+		if err != nil {
+			return nil, err
+		}
+		url, msg := e.URL, map[string]string{"text": TestMessage}
+		if url == "" {
+			url = SlackURL
+		}
+		if opts.Channel != "" {
+			msg["channel"] = opts.Channel
+		}
+		req, err := newTestJSONRequest(http.MethodPost, url, msg)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+
+	case *PagerDuty:
+		routingKey, err := load(e.RoutingKey)
+		if err != nil {
+			return nil, err
+		}
+		return newTestJSONRequest(http.MethodPost, PagerDutyURL, map[string]interface{}{
+			"routing_key":  routingKey,
+			"event_action": "trigger",
+			"client":       "influxdata",
+			"client_url":   e.ClientURL,
+			"payload": map[string]string{
+				"summary":   TestMessage,
+				"source":    e.Name,
+				"severity":  "info",
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+			},
+		})
+
+	case *HTTP:
+		body := map[string]interface{}{
+			"_message":                    TestMessage,
+			"_level":                      "ok",
+			"_notification_endpoint_id":   e.ID.String(),
+			"_notification_endpoint_name": e.Name,
+			"_time":                       time.Now().UTC().Format(time.RFC3339Nano),
+		}
+		req, err := newTestJSONRequest(e.Method, e.URL, body)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range e.Headers {
+			req.Header.Set(k, v)
+		}
+		switch e.AuthMethod {
+		case "basic":
+			username, err := load(e.Username)
+			if err != nil {
+				return nil, err
+			}
+			password, err := load(e.Password)
+			if err != nil {
+				return nil, err
+			}
+			req.SetBasicAuth(username, password)
+		case "bearer":
+			token, err := load(e.Token)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	}
+
+	return nil, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("notification endpoints of type %s cannot be tested", edp.Type()),
+	}
+}
+
+func newTestJSONRequest(method, url string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid notification endpoint URL",
+			Err:  err,
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
@@ -1,6 +1,10 @@
 package flux
 
-import "github.com/influxdata/flux/ast"
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
 
 // File creates a new *ast.File.
 func File(name string, imports []*ast.ImportDeclaration, body []ast.Statement) *ast.File {
@@ -172,6 +176,11 @@ func Duration(m int64, u string) *ast.DurationLiteral {
 	}
 }
 
+// TimeDuration returns an *ast.DurationLiteral of d in nanoseconds.
+func TimeDuration(d time.Duration) *ast.DurationLiteral {
+	return Duration(int64(d), ast.NanosecondUnit)
+}
+
 // Identifier returns an *ast.Identifier of i.
 func Identifier(i string) *ast.Identifier {
 	return &ast.Identifier{Name: i}
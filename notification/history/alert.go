@@ -0,0 +1,221 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// RetentionInterval is the interval at which the retention of the history
+// is enforced.
+const RetentionInterval = time.Hour
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService implements influxdb.AlertService. The alerts are read from the
+// statuses in the monitoring system bucket and joined with the stored
+// acknowledgements.
+type AlertService struct {
+	checks    influxdb.CheckService
+	acks      influxdb.AlertAcknowledgementService
+	bs        influxdb.BucketService
+	qs        query.QueryService
+	retention time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewAlertService creates an alert service. Only the statuses within the
+// retention are read; a retention of 0 reads all the statuses in the
+// monitoring bucket.
+func NewAlertService(logger *zap.Logger, cs influxdb.CheckService, as influxdb.AlertAcknowledgementService, bs influxdb.BucketService, qs query.QueryService, retention time.Duration) *AlertService {
+	return &AlertService{
+		checks:    cs,
+		acks:      as,
+		bs:        bs,
+		qs:        qs,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// AcknowledgeAlert acknowledges the statuses written by the check so far.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, checkID, userID influxdb.ID) (*influxdb.AlertAcknowledgement, error) {
+	chk, err := s.checks.FindCheckByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &influxdb.AlertAcknowledgement{
+		OrgID:   chk.GetOrgID(),
+		CheckID: checkID,
+		UserID:  userID,
+		Time:    s.now().UTC(),
+	}
+	if err := s.acks.CreateAlertAcknowledgement(ctx, a); err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpAcknowledgeAlert,
+			Err: err,
+		}
+	}
+	return a, nil
+}
+
+// FindAlerts returns the alerts of the checks of the organization, the
+// latest first.
+func (s *AlertService) FindAlerts(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error) {
+	summary, err := s.findAlerts(ctx, orgID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindAlerts,
+			Err: err,
+		}
+	}
+	return summary, nil
+}
+
+func (s *AlertService) findAlerts(ctx context.Context, orgID influxdb.ID) (*influxdb.AlertSummary, error) {
+	sb, err := s.bs.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Unix(0, 0)
+	if s.retention > 0 {
+		start = s.now().Add(-s.retention)
+	} else if sb.RetentionPeriod > 0 {
+		start = s.now().Add(-sb.RetentionPeriod)
+	}
+
+	// The last critical status of every check.
+	script := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: %s)
+	  |> filter(fn: (r) => r._measurement == %q and r._field == "_message" and r.%s == %q)
+	  |> group(columns: [%q])
+	  |> last()
+	  |> group()
+	  `, sb.ID.String(), start.UTC().Format(time.RFC3339Nano), statusesMeasurement, levelTag, influxdb.AlertCriticalLevel, checkIDTag)
+	records, err := queryRecords(ctx, s.qs, s.logger, orgID, sb.ID, script)
+	if err != nil {
+		return nil, err
+	}
+
+	acks, _, err := s.acks.FindAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	lastAcks := make(map[influxdb.ID]*influxdb.AlertAcknowledgement)
+	for _, a := range acks {
+		if _, ok := lastAcks[a.CheckID]; !ok {
+			lastAcks[a.CheckID] = a
+		}
+	}
+
+	summary := &influxdb.AlertSummary{
+		OrgID:  orgID,
+		Alerts: make([]*influxdb.Alert, 0, len(records)),
+	}
+	for _, rec := range records {
+		a := &influxdb.Alert{
+			CheckID:         rec.CheckID,
+			CheckName:       rec.CheckName,
+			Time:            rec.Time,
+			Message:         rec.Message,
+			Acknowledgement: lastAcks[rec.CheckID],
+		}
+		a.Acknowledged = a.Acknowledgement != nil && !a.Acknowledgement.Time.Before(a.Time)
+		if !a.Acknowledged {
+			summary.UnacknowledgedCritical++
+		}
+		summary.Alerts = append(summary.Alerts, a)
+	}
+	sort.SliceStable(summary.Alerts, func(i, j int) bool {
+		return summary.Alerts[i].Time.After(summary.Alerts[j].Time)
+	})
+	return summary, nil
+}
+
+// RetentionEnforcer deletes the statuses and notifications in the monitoring
+// buckets, and the acknowledgements of alerts, that are older than the
+// retention of the history.
+type RetentionEnforcer struct {
+	orgs      influxdb.OrganizationService
+	bs        influxdb.BucketService
+	deleter   influxdb.DeleteService
+	acks      influxdb.AlertAcknowledgementService
+	retention time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewRetentionEnforcer returns an enforcer of the retention of the history
+// of every organization. The services must not be authorized.
+func NewRetentionEnforcer(logger *zap.Logger, os influxdb.OrganizationService, bs influxdb.BucketService, ds influxdb.DeleteService, as influxdb.AlertAcknowledgementService, retention time.Duration) *RetentionEnforcer {
+	return &RetentionEnforcer{
+		orgs:      os,
+		bs:        bs,
+		deleter:   ds,
+		acks:      as,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run enforces the retention immediately and then every interval until ctx
+// is canceled.
+func (e *RetentionEnforcer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Enforce(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Error("Unable to enforce the retention of the monitoring history", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enforce deletes the history older than the retention. The error of an
+// organization is logged and does not stop the enforcement for the others.
+func (e *RetentionEnforcer) Enforce(ctx context.Context) error {
+	orgs, _, err := e.orgs.FindOrganizations(ctx, influxdb.OrganizationFilter{})
+	if err != nil {
+		return err
+	}
+
+	before := e.now().Add(-e.retention)
+	for _, o := range orgs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := e.enforce(ctx, o.ID, before); err != nil {
+			e.logger.Error("Unable to enforce the retention of the monitoring history of organization",
+				zap.Stringer("org_id", o.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (e *RetentionEnforcer) enforce(ctx context.Context, orgID influxdb.ID, before time.Time) error {
+	sb, err := e.bs.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+	if err := e.deleter.DeleteBucketRangePredicate(ctx, orgID, sb.ID, math.MinInt64, before.UnixNano()-1, nil); err != nil {
+		return err
+	}
+	return e.acks.DeleteAlertAcknowledgements(ctx, influxdb.AlertAcknowledgementFilter{OrgID: &orgID}, before)
+}
@@ -0,0 +1,160 @@
+package history_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/history"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAlertService_FindAlerts(t *testing.T) {
+	orgID := influxdb.ID(1)
+	cpuID, memID := influxdb.ID(10), influxdb.ID(11)
+	first := time.Date(2019, 11, 1, 3, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: influxdb.MonitoringSystemBucketID, OrgID: id, Name: name}, nil
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID {
+				t.Fatalf("expected query in org %s, got %s", orgID, req.OrganizationID)
+			}
+			if q := req.Compiler.(lang.FluxCompiler).Query; !strings.Contains(q, `r._level == "crit"`) || !strings.Contains(q, "last()") {
+				t.Fatalf("expected query of the last critical statuses, got %s", q)
+			}
+			r := executetest.NewResult([]*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_check_id", Type: flux.TString},
+					{Label: "_check_name", Type: flux.TString},
+					{Label: "_level", Type: flux.TString},
+					{Label: "_value", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{values.ConvertTime(first), cpuID.String(), "cpu", "crit", "cpu is on fire"},
+					{values.ConvertTime(second), memID.String(), "mem", "crit", "mem is full"},
+				},
+			}})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+
+	as := mock.NewAlertAcknowledgementService()
+	as.FindAlertAcknowledgementsFn = func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter) ([]*influxdb.AlertAcknowledgement, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != orgID {
+			t.Fatalf("expected acknowledgements of org %s", orgID)
+		}
+		// The acknowledgement of the memory check is older than its last
+		// critical status.
+		return []*influxdb.AlertAcknowledgement{
+			{ID: 3, OrgID: orgID, CheckID: cpuID, UserID: 100, Time: first.Add(time.Second)},
+			{ID: 2, OrgID: orgID, CheckID: memID, UserID: 100, Time: first},
+			{ID: 1, OrgID: orgID, CheckID: cpuID, UserID: 101, Time: first.Add(-time.Hour)},
+		}, 3, nil
+	}
+
+	svc := history.NewAlertService(zaptest.NewLogger(t), mock.NewCheckService(), as, bs, qs, 0)
+	summary, err := svc.FindAlerts(context.Background(), orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.OrgID != orgID || summary.UnacknowledgedCritical != 1 || len(summary.Alerts) != 2 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	mem, cpu := summary.Alerts[0], summary.Alerts[1]
+	if mem.CheckID != memID || mem.Acknowledged || mem.Acknowledgement.ID != 2 || !mem.Time.Equal(second) || mem.Message != "mem is full" {
+		t.Fatalf("unexpected memory alert %+v", mem)
+	}
+	if cpu.CheckID != cpuID || !cpu.Acknowledged || cpu.Acknowledgement.ID != 3 || cpu.CheckName != "cpu" {
+		t.Fatalf("unexpected cpu alert %+v", cpu)
+	}
+}
+
+func TestAlertService_AcknowledgeAlert(t *testing.T) {
+	orgID, checkID, userID := influxdb.ID(1), influxdb.ID(10), influxdb.ID(100)
+
+	cs := mock.NewCheckService()
+	cs.FindCheckByIDFn = func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: orgID}}, nil
+	}
+
+	var created *influxdb.AlertAcknowledgement
+	as := mock.NewAlertAcknowledgementService()
+	as.CreateAlertAcknowledgementFn = func(ctx context.Context, a *influxdb.AlertAcknowledgement) error {
+		a.ID = 1
+		created = a
+		return nil
+	}
+
+	svc := history.NewAlertService(zaptest.NewLogger(t), cs, as, mock.NewBucketService(), &qmock.QueryService{}, 0)
+	a, err := svc.AcknowledgeAlert(context.Background(), checkID, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != created || a.OrgID != orgID || a.CheckID != checkID || a.UserID != userID || a.Time.IsZero() {
+		t.Fatalf("unexpected acknowledgement %+v", a)
+	}
+}
+
+func TestRetentionEnforcer_Enforce(t *testing.T) {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, filter influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
+		return []*influxdb.Organization{{ID: 1}, {ID: 2}}, 2, nil
+	}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id == 1 {
+			return nil, &influxdb.Error{Code: influxdb.EInternal, Msg: "bucket service failed"}
+		}
+		return &influxdb.Bucket{ID: 20, OrgID: id, Name: name}, nil
+	}
+
+	retention := 24 * time.Hour
+	var deleted []influxdb.ID
+	ds := mock.NewDeleteService()
+	ds.DeleteBucketRangePredicateF = func(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+		if bucketID != 20 || min != math.MinInt64 || pred != nil {
+			t.Fatalf("unexpected delete of bucket %s from %d", bucketID, min)
+		}
+		if d := time.Since(time.Unix(0, max)); d < retention || d > retention+time.Minute {
+			t.Fatalf("unexpected end of deleted range %s ago", d)
+		}
+		deleted = append(deleted, orgID)
+		return nil
+	}
+
+	var ackOrgs []influxdb.ID
+	as := mock.NewAlertAcknowledgementService()
+	as.DeleteAlertAcknowledgementsFn = func(ctx context.Context, filter influxdb.AlertAcknowledgementFilter, before time.Time) error {
+		ackOrgs = append(ackOrgs, *filter.OrgID)
+		return nil
+	}
+
+	// The failure of the first organization does not stop the enforcement.
+	e := history.NewRetentionEnforcer(zaptest.NewLogger(t), orgs, bs, ds, as, retention)
+	if err := e.Enforce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != 2 || len(ackOrgs) != 1 || ackOrgs[0] != 2 {
+		t.Fatalf("expected the history of org 2 to be deleted, got %v and %v", deleted, ackOrgs)
+	}
+}
@@ -0,0 +1,232 @@
+// Package history joins the task runs of checks and notification rules with
+// the statuses and notifications they wrote to the monitoring system bucket.
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+const (
+	statusesMeasurement      = "statuses"
+	notificationsMeasurement = "notifications"
+
+	timeColumn    = "_time"
+	messageColumn = "_value"
+	checkIDTag    = "_check_id"
+	checkNameTag  = "_check_name"
+	levelTag      = "_level"
+	sentTag       = "_sent"
+	ruleIDTag     = "_notification_rule_id"
+)
+
+var _ influxdb.MonitoringRunService = (*Service)(nil)
+
+// Service implements influxdb.MonitoringRunService. The monitoring functions
+// stamp every status and notification with the time the task run was
+// scheduled for, which is what runs and records are joined on.
+type Service struct {
+	checks influxdb.CheckService
+	rules  influxdb.NotificationRuleStore
+	runs   influxdb.TaskService
+	bs     influxdb.BucketService
+	qs     query.QueryService
+	logger *zap.Logger
+}
+
+// NewService creates a monitoring run service. The task service must be able
+// to find completed runs, e.g. the analytical storage of the task backend.
+func NewService(logger *zap.Logger, cs influxdb.CheckService, rs influxdb.NotificationRuleStore, ts influxdb.TaskService, bs influxdb.BucketService, qs query.QueryService) *Service {
+	return &Service{
+		checks: cs,
+		rules:  rs,
+		runs:   ts,
+		bs:     bs,
+		qs:     qs,
+		logger: logger,
+	}
+}
+
+// FindCheckRuns returns the runs of the task of a check and the statuses
+// written by each run.
+func (s *Service) FindCheckRuns(ctx context.Context, checkID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	chk, err := s.checks.FindCheckByID(ctx, checkID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	predicate := fmt.Sprintf(`r._measurement == %q and r.%s == %q`, statusesMeasurement, checkIDTag, checkID.String())
+	runs, err := s.findRuns(ctx, chk.GetOrgID(), chk.GetTaskID(), filter, predicate)
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindCheckRuns,
+			Err: err,
+		}
+	}
+	return runs, len(runs), nil
+}
+
+// FindNotificationRuleRuns returns the runs of the task of a notification
+// rule and the notifications sent by each run.
+func (s *Service) FindNotificationRuleRuns(ctx context.Context, ruleID influxdb.ID, filter influxdb.RunFilter) ([]*influxdb.MonitoringRun, int, error) {
+	nr, err := s.rules.FindNotificationRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	predicate := fmt.Sprintf(`r._measurement == %q and r.%s == %q`, notificationsMeasurement, ruleIDTag, ruleID.String())
+	runs, err := s.findRuns(ctx, nr.GetOrgID(), nr.GetTaskID(), filter, predicate)
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindNotificationRuleRuns,
+			Err: err,
+		}
+	}
+	return runs, len(runs), nil
+}
+
+func (s *Service) findRuns(ctx context.Context, orgID, taskID influxdb.ID, filter influxdb.RunFilter, predicate string) ([]*influxdb.MonitoringRun, error) {
+	filter.Task = taskID
+	runs, _, err := s.runs.FindRuns(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	mrs := make([]*influxdb.MonitoringRun, 0, len(runs))
+	if len(runs) == 0 {
+		return mrs, nil
+	}
+
+	start, stop := runs[0].ScheduledFor, runs[0].ScheduledFor
+	for _, r := range runs {
+		if r.ScheduledFor.Before(start) {
+			start = r.ScheduledFor
+		}
+		if r.ScheduledFor.After(stop) {
+			stop = r.ScheduledFor
+		}
+	}
+
+	records, err := s.findRecords(ctx, orgID, start, stop.Add(time.Nanosecond), predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range runs {
+		mr := &influxdb.MonitoringRun{
+			Run:     *r,
+			Records: []influxdb.MonitoringRecord{},
+		}
+		for _, rec := range records {
+			if rec.Time.Equal(r.ScheduledFor) {
+				mr.Records = append(mr.Records, rec)
+			}
+		}
+		mrs = append(mrs, mr)
+	}
+	return mrs, nil
+}
+
+func (s *Service) findRecords(ctx context.Context, orgID influxdb.ID, start, stop time.Time, predicate string) ([]influxdb.MonitoringRecord, error) {
+	sb, err := s.bs.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: %s, stop: %s)
+	  |> filter(fn: (r) => %s)
+	  |> filter(fn: (r) => r._field == "_message")
+	  |> group()
+	  |> sort(columns: ["_time"])
+	  `, sb.ID.String(), start.UTC().Format(time.RFC3339Nano), stop.UTC().Format(time.RFC3339Nano), predicate)
+	return queryRecords(ctx, s.qs, s.logger, orgID, sb.ID, script)
+}
+
+// queryRecords runs the script reading the monitoring bucket of the
+// organization and returns the records of its results.
+func queryRecords(ctx context.Context, qs query.QueryService, logger *zap.Logger, orgID, bucketID influxdb.ID, script string) ([]influxdb.MonitoringRecord, error) {
+	// At this point we are behind authorization
+	// so we are faking a read only permission to the org's system bucket
+	monitoringBucketID := bucketID
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     bucketID,
+		OrgID:  orgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &orgID,
+					ID:    &monitoringBucketID,
+				},
+			},
+		},
+	}
+	request := &query.Request{Authorization: auth, OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	ittr, err := qs.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer ittr.Release()
+
+	re := &recordReader{logger: logger}
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(re.readTable); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ittr.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected internal error while decoding monitoring records: %v", err)
+	}
+	return re.records, nil
+}
+
+type recordReader struct {
+	records []influxdb.MonitoringRecord
+	logger  *zap.Logger
+}
+
+func (re *recordReader) readTable(tbl flux.Table) error {
+	return tbl.Do(re.readRecords)
+}
+
+func (re *recordReader) readRecords(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		var rec influxdb.MonitoringRecord
+		for j, col := range cr.Cols() {
+			switch col.Label {
+			case timeColumn:
+				rec.Time = time.Unix(0, cr.Times(j).Value(i)).UTC()
+			case messageColumn:
+				rec.Message = cr.Strings(j).ValueString(i)
+			case checkIDTag:
+				id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+				if err != nil {
+					re.logger.Info("failed to parse check id", zap.Error(err))
+					continue
+				}
+				rec.CheckID = *id
+			case checkNameTag:
+				rec.CheckName = cr.Strings(j).ValueString(i)
+			case levelTag:
+				rec.Level = cr.Strings(j).ValueString(i)
+			case sentTag:
+				sent := cr.Strings(j).ValueString(i) == "true"
+				rec.Sent = &sent
+			}
+		}
+		re.records = append(re.records, rec)
+	}
+	return nil
+}
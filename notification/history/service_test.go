@@ -0,0 +1,127 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/history"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_FindCheckRuns(t *testing.T) {
+	checkID, orgID, taskID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	first := time.Date(2019, 11, 1, 3, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	cs := mock.NewCheckService()
+	cs.FindCheckByIDFn = func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: orgID, TaskID: taskID}}, nil
+	}
+
+	ts := &mock.TaskService{}
+	ts.FindRunsFn = func(ctx context.Context, filter influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+		if filter.Task != taskID {
+			t.Fatalf("expected runs of task %s, got %s", taskID, filter.Task)
+		}
+		return []*influxdb.Run{
+			{ID: 10, TaskID: taskID, Status: "success", ScheduledFor: second},
+			{ID: 11, TaskID: taskID, Status: "success", ScheduledFor: first},
+		}, 2, nil
+	}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if name != influxdb.MonitoringSystemBucketName {
+			t.Fatalf("expected monitoring bucket, got %q", name)
+		}
+		return &influxdb.Bucket{ID: influxdb.MonitoringSystemBucketID, OrgID: id, Name: name}, nil
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID {
+				t.Fatalf("expected query in org %s, got %s", orgID, req.OrganizationID)
+			}
+			bucketID := influxdb.MonitoringSystemBucketID
+			if !req.Authorization.Allowed(influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bucketID},
+			}) {
+				t.Fatal("expected query to be allowed to read the monitoring bucket")
+			}
+			r := executetest.NewResult([]*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_check_id", Type: flux.TString},
+					{Label: "_check_name", Type: flux.TString},
+					{Label: "_level", Type: flux.TString},
+					{Label: "_value", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{values.ConvertTime(first), checkID.String(), "cpu", "ok", "cpu is fine"},
+					{values.ConvertTime(second), checkID.String(), "cpu", "crit", "cpu is on fire"},
+				},
+			}})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+
+	svc := history.NewService(zaptest.NewLogger(t), cs, &mock.NotificationRuleStore{}, ts, bs, qs)
+	runs, n, err := svc.FindCheckRuns(context.Background(), checkID, influxdb.RunFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", n)
+	}
+
+	for _, tt := range []struct {
+		run     *influxdb.MonitoringRun
+		id      influxdb.ID
+		level   string
+		message string
+	}{
+		{run: runs[0], id: 10, level: "crit", message: "cpu is on fire"},
+		{run: runs[1], id: 11, level: "ok", message: "cpu is fine"},
+	} {
+		if tt.run.ID != tt.id {
+			t.Fatalf("expected run %s, got %s", tt.id, tt.run.ID)
+		}
+		if len(tt.run.Records) != 1 {
+			t.Fatalf("expected run %s to have 1 status, got %d", tt.id, len(tt.run.Records))
+		}
+		rec := tt.run.Records[0]
+		if !rec.Time.Equal(tt.run.ScheduledFor) || rec.CheckID != checkID || rec.Level != tt.level || rec.Message != tt.message || rec.Sent != nil {
+			t.Fatalf("unexpected status of run %s: %+v", tt.id, rec)
+		}
+	}
+}
+
+func TestService_FindNotificationRuleRunsNotFound(t *testing.T) {
+	nrs := &mock.NotificationRuleStore{}
+	nrs.FindNotificationRuleByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification rule not found"}
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			t.Fatal("unexpected query")
+			return nil, nil
+		},
+	}
+
+	svc := history.NewService(zaptest.NewLogger(t), mock.NewCheckService(), nrs, &mock.TaskService{}, mock.NewBucketService(), qs)
+	_, _, err := svc.FindNotificationRuleRuns(context.Background(), influxdb.ID(1), influxdb.RunFilter{})
+	if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+// Package status streams the status events of checks as their statuses are
+// written to the monitoring buckets.
+package status
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// The schema of the statuses written by checks.
+const (
+	statusesMeasurement = "statuses"
+	messageField        = "_message"
+	checkIDTag          = "_check_id"
+	checkNameTag        = "_check_name"
+	levelTag            = "_level"
+)
+
+// subscriptionBuffer is the number of events buffered for a subscriber. The
+// events of subscribers that do not keep up are dropped.
+const subscriptionBuffer = 100
+
+var _ influxdb.CheckStatusStream = (*Stream)(nil)
+
+// PointsWriter writes points.
+type PointsWriter interface {
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+type subscription struct {
+	filter influxdb.CheckStatusEventFilter
+	events chan *influxdb.CheckStatusEvent
+}
+
+// Stream publishes the status events of checks to its subscribers. Its
+// PointsWriter must be in the path of the writes of the statuses.
+type Stream struct {
+	buckets influxdb.BucketService
+	logger  *zap.Logger
+
+	mu            sync.Mutex
+	monitoring    map[influxdb.ID]bool   // Whether the bucket is a monitoring bucket, by bucket ID.
+	levels        map[string]string      // The last level of every status series.
+	subscriptions map[*subscription]bool // The value is unused.
+}
+
+// NewStream returns a Stream finding the monitoring buckets with buckets.
+func NewStream(buckets influxdb.BucketService, logger *zap.Logger) *Stream {
+	return &Stream{
+		buckets:       buckets,
+		logger:        logger,
+		monitoring:    make(map[influxdb.ID]bool),
+		levels:        make(map[string]string),
+		subscriptions: make(map[*subscription]bool),
+	}
+}
+
+// PointsWriter returns a PointsWriter writing points to w, and publishing
+// the events of the statuses written to monitoring buckets.
+func (s *Stream) PointsWriter(w PointsWriter) PointsWriter {
+	return &pointsWriter{w: w, s: s}
+}
+
+type pointsWriter struct {
+	w PointsWriter
+	s *Stream
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	if err := w.w.WritePoints(ctx, points); err != nil {
+		return err
+	}
+	w.s.publish(ctx, points)
+	return nil
+}
+
+// SubscribeCheckStatuses implements influxdb.CheckStatusStream.
+func (s *Stream) SubscribeCheckStatuses(ctx context.Context, filter influxdb.CheckStatusEventFilter) (<-chan *influxdb.CheckStatusEvent, error) {
+	sub := &subscription{
+		filter: filter,
+		events: make(chan *influxdb.CheckStatusEvent, subscriptionBuffer),
+	}
+
+	s.mu.Lock()
+	s.subscriptions[sub] = true
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.subscriptions, sub)
+		close(sub.events)
+		s.mu.Unlock()
+	}()
+	return sub.events, nil
+}
+
+// publish publishes the events of the statuses of the points that change
+// the level of their series.
+func (s *Stream) publish(ctx context.Context, points []models.Point) {
+	for _, p := range points {
+		if len(p.Name()) != len(tsdb.EncodeName(0, 0)) {
+			continue
+		}
+		tags := p.Tags()
+		if !bytes.Equal(tags.Get(models.MeasurementTagKeyBytes), []byte(statusesMeasurement)) ||
+			!bytes.Equal(tags.Get(models.FieldKeyTagKeyBytes), []byte(messageField)) {
+			continue
+		}
+
+		orgID, bucketID := tsdb.DecodeNameSlice(p.Name())
+		if !s.isMonitoringBucket(ctx, bucketID) {
+			continue
+		}
+		if e := s.event(orgID, p); e != nil {
+			s.send(e)
+		}
+	}
+}
+
+// isMonitoringBucket returns whether the bucket is a monitoring bucket.
+func (s *Stream) isMonitoringBucket(ctx context.Context, id influxdb.ID) bool {
+	// Organizations without their own monitoring bucket share the one of
+	// fixed ID, which is not stored.
+	if id == influxdb.MonitoringSystemBucketID {
+		return true
+	}
+
+	s.mu.Lock()
+	monitoring, ok := s.monitoring[id]
+	s.mu.Unlock()
+	if ok {
+		return monitoring
+	}
+
+	b, err := s.buckets.FindBucketByID(ctx, id)
+	if err != nil {
+		s.logger.Debug("Failed to find bucket of statuses", zap.Stringer("bucket_id", id), zap.Error(err))
+		return false
+	}
+	monitoring = b.Type == influxdb.BucketTypeSystem && b.Name == influxdb.MonitoringSystemBucketName
+
+	s.mu.Lock()
+	s.monitoring[id] = monitoring
+	s.mu.Unlock()
+	return monitoring
+}
+
+// event returns the event of the status point, or nil if the status does
+// not change the level of its series.
+func (s *Stream) event(orgID influxdb.ID, p models.Point) *influxdb.CheckStatusEvent {
+	e := &influxdb.CheckStatusEvent{
+		Time:  p.Time(),
+		OrgID: orgID,
+		Tags:  make(map[string]string),
+	}
+
+	// The series of the status is identified by its tags other than the level.
+	series := append([]byte(nil), p.Name()...)
+	for _, t := range p.Tags() {
+		switch string(t.Key) {
+		case models.MeasurementTagKey, models.FieldKeyTagKey:
+			continue
+		case levelTag:
+			e.Level = string(t.Value)
+			continue
+		case checkIDTag:
+			if err := e.CheckID.DecodeFromString(string(t.Value)); err != nil {
+				return nil
+			}
+		case checkNameTag:
+			e.CheckName = string(t.Value)
+		default:
+			e.Tags[string(t.Key)] = string(t.Value)
+		}
+		series = append(append(append(append(series, ','), t.Key...), '='), t.Value...)
+	}
+	if !e.CheckID.Valid() || e.Level == "" {
+		return nil
+	}
+
+	if itr := p.FieldIterator(); itr.Next() && itr.Type() == models.String {
+		e.Message = itr.StringValue()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.PreviousLevel = s.levels[string(series)]
+	if e.PreviousLevel == e.Level {
+		return nil
+	}
+	s.levels[string(series)] = e.Level
+	return e
+}
+
+// send sends the event to the matching subscribers.
+func (s *Stream) send(e *influxdb.CheckStatusEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscriptions {
+		if sub.filter.OrgID != e.OrgID || (sub.filter.CheckID != nil && *sub.filter.CheckID != e.CheckID) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			s.logger.Debug("Dropped status event of slow subscriber", zap.Stringer("check_id", e.CheckID))
+		}
+	}
+}
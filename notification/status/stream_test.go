@@ -0,0 +1,93 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/notification/status"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type pointsWriter struct {
+	points []models.Point
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.points = append(w.points, points...)
+	return nil
+}
+
+func TestStream_SubscribeCheckStatuses(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	monitoringID, otherBucketID := influxdb.ID(10), influxdb.ID(20)
+	checkID := influxdb.ID(0x020f755c3c082000)
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id == monitoringID {
+			return &influxdb.Bucket{ID: id, Type: influxdb.BucketTypeSystem, Name: influxdb.MonitoringSystemBucketName}, nil
+		}
+		return &influxdb.Bucket{ID: id, Type: influxdb.BucketTypeUser, Name: "other"}, nil
+	}
+
+	s := status.NewStream(bs, zaptest.NewLogger(t))
+	w := &pointsWriter{}
+	pw := s.PointsWriter(w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := s.SubscribeCheckStatuses(ctx, influxdb.CheckStatusEventFilter{OrgID: orgID, CheckID: &checkID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(org, bucket influxdb.ID, lines string) {
+		t.Helper()
+		encoded := tsdb.EncodeName(org, bucket)
+		points, err := models.ParsePoints([]byte(lines), models.EscapeMeasurement(encoded[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pw.WritePoints(ctx, points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(monitoringID, monitoringID, "") // Nothing is published without points.
+	write(orgID, monitoringID, `statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=ok,host=a _message="cpu is ok" 1000000000
+statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=ok,host=a _message="cpu is ok" 2000000000
+statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=ok,host=b _message="cpu is ok" 2000000000
+statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=crit,host=a _message="cpu is high",_type="threshold" 3000000000`)
+	// Statuses of other checks, of other organizations and in other buckets
+	// are not published to the subscriber.
+	write(orgID, monitoringID, `statuses,_check_id=020f755c3c082001,_check_name=mem,_level=warn _message="mem" 3000000000`)
+	write(otherOrgID, monitoringID, `statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=warn _message="cpu" 3000000000`)
+	write(orgID, otherBucketID, `statuses,_check_id=020f755c3c082000,_check_name=cpu,_level=info _message="cpu" 3000000000`)
+
+	if got, want := len(w.points), 8; got != want {
+		t.Fatalf("got %d points written, want %d", got, want)
+	}
+
+	want := []influxdb.CheckStatusEvent{
+		{Time: time.Unix(1, 0), OrgID: orgID, CheckID: checkID, CheckName: "cpu", Level: "ok", Message: "cpu is ok", Tags: map[string]string{"host": "a"}},
+		{Time: time.Unix(2, 0), OrgID: orgID, CheckID: checkID, CheckName: "cpu", Level: "ok", Message: "cpu is ok", Tags: map[string]string{"host": "b"}},
+		{Time: time.Unix(3, 0), OrgID: orgID, CheckID: checkID, CheckName: "cpu", Level: "crit", PreviousLevel: "ok", Message: "cpu is high", Tags: map[string]string{"host": "a"}},
+	}
+	for i, w := range want {
+		e := <-events
+		if !e.Time.Equal(w.Time) || e.OrgID != w.OrgID || e.CheckID != w.CheckID || e.CheckName != w.CheckName ||
+			e.Level != w.Level || e.PreviousLevel != w.PreviousLevel || e.Message != w.Message ||
+			len(e.Tags) != len(w.Tags) || e.Tags["host"] != w.Tags["host"] {
+			t.Errorf("event %d: got %+v, want %+v", i, e, w)
+		}
+	}
+
+	cancel()
+	if e, ok := <-events; ok {
+		t.Fatalf("unexpected event %+v", e)
+	}
+}
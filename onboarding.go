@@ -1,6 +1,9 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // OnboardingService represents a service for the first run.
 type OnboardingService interface {
@@ -22,6 +25,12 @@ type OnboardingResults struct {
 	Org    *Organization  `json:"org"`
 	Bucket *Bucket        `json:"bucket"`
 	Auth   *Authorization `json:"auth"`
+
+	// Buckets, Users and Auths are the additional resources
+	// created from the setup profile of the request.
+	Buckets []*Bucket        `json:"buckets,omitempty"`
+	Users   []*User          `json:"users,omitempty"`
+	Auths   []*Authorization `json:"auths,omitempty"`
 }
 
 // OnboardingRequest is the request
@@ -33,6 +42,53 @@ type OnboardingRequest struct {
 	Bucket          string `json:"bucket"`
 	RetentionPeriod uint   `json:"retentionPeriodHrs,omitempty"`
 	Token           string `json:"token,omitempty"`
+
+	// Buckets, Users and Tokens form an optional setup profile of
+	// additional resources created in the new organization.
+	Buckets []OnboardingBucket `json:"buckets,omitempty"`
+	Users   []OnboardingUser   `json:"users,omitempty"`
+	Tokens  []OnboardingToken  `json:"tokens,omitempty"`
+
+	// ConfirmationToken makes the request idempotent. For ten minutes
+	// after setup has completed, repeating a request with the same
+	// confirmation token returns the original results instead of a
+	// conflict.
+	ConfirmationToken string `json:"confirmationToken,omitempty"`
+}
+
+// OnboardingBucket is an additional bucket created during setup.
+type OnboardingBucket struct {
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	RetentionPeriod uint   `json:"retentionPeriodHrs,omitempty"`
+}
+
+// OnboardingUser is an additional user created during setup and added
+// to the new organization. Role defaults to member.
+type OnboardingUser struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password,omitempty"`
+	Role     UserType `json:"role,omitempty"`
+}
+
+// OnboardingToken is an additional token created during setup. It is
+// owned by the named user, or by the setup user when User is empty.
+type OnboardingToken struct {
+	Description string                 `json:"description,omitempty"`
+	Token       string                 `json:"token,omitempty"`
+	User        string                 `json:"user,omitempty"`
+	Permissions []OnboardingPermission `json:"permissions"`
+}
+
+// OnboardingPermission is a permission of an onboarding token. It is
+// scoped to the new organization, and to a single bucket when the
+// resource names one of the buckets created during setup.
+type OnboardingPermission struct {
+	Action   Action `json:"action"`
+	Resource struct {
+		Type ResourceType `json:"type"`
+		Name string       `json:"name,omitempty"`
+	} `json:"resource"`
 }
 
 func (r *OnboardingRequest) Valid() error {
@@ -63,5 +119,72 @@ func (r *OnboardingRequest) Valid() error {
 			Msg:  "bucket name is empty",
 		}
 	}
+
+	buckets := map[string]bool{r.Bucket: true}
+	for _, b := range r.Buckets {
+		if b.Name == "" {
+			return &Error{
+				Code: EEmptyValue,
+				Msg:  "bucket name is empty",
+			}
+		}
+		buckets[b.Name] = true
+	}
+
+	users := map[string]bool{r.User: true}
+	for _, u := range r.Users {
+		if u.Name == "" {
+			return &Error{
+				Code: EEmptyValue,
+				Msg:  "username is empty",
+			}
+		}
+		if u.Role != "" {
+			if err := u.Role.Valid(); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("invalid role for user %q", u.Name),
+					Err:  err,
+				}
+			}
+		}
+		users[u.Name] = true
+	}
+
+	for _, t := range r.Tokens {
+		if t.User != "" && !users[t.User] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("token user %q is not part of the setup", t.User),
+			}
+		}
+		if len(t.Permissions) == 0 {
+			return &Error{
+				Code: EEmptyValue,
+				Msg:  "token permissions are empty",
+			}
+		}
+		for _, p := range t.Permissions {
+			perm := Permission{Action: p.Action, Resource: Resource{Type: p.Resource.Type}}
+			if err := perm.Valid(); err != nil {
+				return err
+			}
+			if p.Resource.Name == "" {
+				continue
+			}
+			if p.Resource.Type != BucketsResourceType {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "only bucket permissions can name a resource",
+				}
+			}
+			if !buckets[p.Resource.Name] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("permission bucket %q is not part of the setup", p.Resource.Name),
+				}
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,240 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ops for organization settings error and op logs.
+const (
+	OpFindOrganizationSettings   = "FindOrganizationSettings"
+	OpUpdateOrganizationSettings = "UpdateOrganizationSettings"
+)
+
+// OrganizationSettingsService represents a service for managing the settings
+// of organizations.
+type OrganizationSettingsService interface {
+	// FindOrganizationSettings returns the settings of an organization. An
+	// organization that was never configured has empty settings.
+	FindOrganizationSettings(ctx context.Context, orgID ID) (*OrganizationSettings, error)
+
+	// UpdateOrganizationSettings updates the settings of an organization with
+	// the changeset and returns the new settings.
+	UpdateOrganizationSettings(ctx context.Context, orgID ID, upd OrganizationSettingsUpdate) (*OrganizationSettings, error)
+}
+
+// OrganizationSettings are the settings of an organization. The bucket
+// defaults are applied to user buckets of the organization that are created
+// without the corresponding value. The query limits bound the rows and bytes
+// returned by a query of the organization; zero means unlimited. The timezone
+// and the first day of the week are the locale that dashboards of the
+// organization default to and that windows of whole days are aligned to. The
+// API rate limits override the limits of the server for the organization.
+type OrganizationSettings struct {
+	OrgID                     ID            `json:"orgID"`
+	DefaultRetentionPeriod    time.Duration `json:"defaultRetentionPeriod"`
+	DefaultShardGroupDuration time.Duration `json:"defaultShardGroupDuration"`
+	DefaultSchemaType         SchemaType    `json:"defaultSchemaType,omitempty"`
+	QueryMaxRows              int64         `json:"queryMaxRows,omitempty"`
+	QueryMaxBytes             int64         `json:"queryMaxBytes,omitempty"`
+	// Timezone is the IANA name of the timezone of the organization, e.g.
+	// Europe/Berlin; UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+	// WeekStart is the lowercase name of the first day of the week of the
+	// organization, e.g. sunday; monday if empty.
+	WeekStart     string         `json:"weekStart,omitempty"`
+	APIRateLimits *APIRateLimits `json:"apiRateLimits,omitempty"`
+	UpdatedAt     time.Time      `json:"updatedAt,omitempty"`
+}
+
+// Valid returns an error if the settings are invalid.
+func (s *OrganizationSettings) Valid() error {
+	if s.DefaultRetentionPeriod < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "default retention period must not be negative",
+		}
+	}
+	if s.DefaultShardGroupDuration < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "default shard group duration must not be negative",
+		}
+	}
+	if s.DefaultRetentionPeriod > 0 && s.DefaultShardGroupDuration > s.DefaultRetentionPeriod {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "default shard group duration must not be longer than the default retention period",
+		}
+	}
+	if s.DefaultSchemaType != "" {
+		if err := s.DefaultSchemaType.Valid(); err != nil {
+			return err
+		}
+	}
+	if s.QueryMaxRows < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query max rows must not be negative",
+		}
+	}
+	if s.QueryMaxBytes < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query max bytes must not be negative",
+		}
+	}
+	if s.Timezone != "" {
+		if err := validTimezone(s.Timezone); err != nil {
+			return err
+		}
+	}
+	if s.WeekStart != "" {
+		if _, err := parseWeekday(s.WeekStart); err != nil {
+			return err
+		}
+	}
+	if s.APIRateLimits != nil {
+		if err := s.APIRateLimits.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Location returns the timezone of the organization.
+func (s *OrganizationSettings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FirstDayOfWeek returns the first day of the week of the organization.
+func (s *OrganizationSettings) FirstDayOfWeek() time.Weekday {
+	if s.WeekStart == "" {
+		return time.Monday
+	}
+	d, err := parseWeekday(s.WeekStart)
+	if err != nil {
+		return time.Monday
+	}
+	return d
+}
+
+// HasLocale returns whether the timezone or the first day of the week of the
+// organization is set.
+func (s *OrganizationSettings) HasLocale() bool {
+	return s.Timezone != "" || s.WeekStart != ""
+}
+
+// ApplyDashboardDefaults sets the timezone of the dashboard to the timezone
+// of the organization when it is unset.
+func (s *OrganizationSettings) ApplyDashboardDefaults(d *Dashboard) {
+	if d.Timezone == "" {
+		d.Timezone = s.Timezone
+	}
+}
+
+// parseWeekday returns the day of the week with the lowercase name.
+func parseWeekday(name string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name == strings.ToLower(d.String()) {
+			return d, nil
+		}
+	}
+	return 0, &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid week start %q; must be the lowercase name of a day of the week, e.g. monday", name),
+	}
+}
+
+// ApplyBucketDefaults sets the retention period, shard group duration and
+// schema type of the bucket to the defaults of the organization when they
+// are unset. The default shard group duration is skipped for buckets that
+// retain data for a shorter time. System buckets are left as they are.
+func (s *OrganizationSettings) ApplyBucketDefaults(b *Bucket) {
+	if b.Type == BucketTypeSystem {
+		return
+	}
+	if b.RetentionPeriod == 0 {
+		b.RetentionPeriod = s.DefaultRetentionPeriod
+	}
+	if b.ShardGroupDuration == 0 && (b.RetentionPeriod == 0 || s.DefaultShardGroupDuration <= b.RetentionPeriod) {
+		b.ShardGroupDuration = s.DefaultShardGroupDuration
+	}
+	if b.SchemaType == "" {
+		b.SchemaType = s.DefaultSchemaType
+	}
+}
+
+// OrganizationSettingsUpdate represents updates to the settings of an
+// organization. Only fields which are set are updated.
+type OrganizationSettingsUpdate struct {
+	DefaultRetentionPeriod    *time.Duration `json:"defaultRetentionPeriod,omitempty"`
+	DefaultShardGroupDuration *time.Duration `json:"defaultShardGroupDuration,omitempty"`
+	DefaultSchemaType         *SchemaType    `json:"defaultSchemaType,omitempty"`
+	QueryMaxRows              *int64         `json:"queryMaxRows,omitempty"`
+	QueryMaxBytes             *int64         `json:"queryMaxBytes,omitempty"`
+	Timezone                  *string        `json:"timezone,omitempty"`
+	WeekStart                 *string        `json:"weekStart,omitempty"`
+	// APIRateLimits replaces the API rate limits of the organization.
+	APIRateLimits *APIRateLimits `json:"apiRateLimits,omitempty"`
+}
+
+// Apply applies the update to the settings.
+func (u OrganizationSettingsUpdate) Apply(s *OrganizationSettings) {
+	if u.DefaultRetentionPeriod != nil {
+		s.DefaultRetentionPeriod = *u.DefaultRetentionPeriod
+	}
+	if u.DefaultShardGroupDuration != nil {
+		s.DefaultShardGroupDuration = *u.DefaultShardGroupDuration
+	}
+	if u.DefaultSchemaType != nil {
+		s.DefaultSchemaType = *u.DefaultSchemaType
+	}
+	if u.QueryMaxRows != nil {
+		s.QueryMaxRows = *u.QueryMaxRows
+	}
+	if u.QueryMaxBytes != nil {
+		s.QueryMaxBytes = *u.QueryMaxBytes
+	}
+	if u.Timezone != nil {
+		s.Timezone = *u.Timezone
+	}
+	if u.WeekStart != nil {
+		s.WeekStart = *u.WeekStart
+	}
+	if u.APIRateLimits != nil {
+		s.APIRateLimits = u.APIRateLimits
+	}
+}
+
+// SchemaType is the schema of a bucket. Implicit schemas are defined by the
+// data that is written. Explicit schemas, which restrict measurements to
+// declared columns, are not supported, as the write path cannot enforce them.
+type SchemaType string
+
+const (
+	// SchemaTypeImplicit is the schema of a bucket that accepts any data.
+	SchemaTypeImplicit SchemaType = "implicit"
+)
+
+// Valid returns an error if the schema type is unknown or not supported.
+func (t SchemaType) Valid() error {
+	switch t {
+	case SchemaTypeImplicit:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid schema type %q; only %s schemas are supported", t, SchemaTypeImplicit),
+		}
+	}
+}
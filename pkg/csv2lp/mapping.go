@@ -0,0 +1,169 @@
+// Package csv2lp converts CSV data to line protocol using a mapping of the
+// CSV columns to the measurement, tags, fields and time of points.
+package csv2lp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ghodss/yaml"
+)
+
+// Column types.
+const (
+	ColumnTag    = "tag"
+	ColumnField  = "field"
+	ColumnTime   = "time"
+	ColumnIgnore = "ignore"
+)
+
+// Field data types.
+const (
+	DataTypeFloat    = "float"
+	DataTypeInteger  = "integer"
+	DataTypeUnsigned = "unsignedInteger"
+	DataTypeBoolean  = "boolean"
+	DataTypeString   = "string"
+)
+
+// Time formats in addition to Go time layouts.
+const (
+	TimeFormatRFC3339 = "RFC3339"
+	TimeFormatUnix    = "unix"
+	TimeFormatUnixMs  = "unix_ms"
+	TimeFormatUnixUs  = "unix_us"
+	TimeFormatUnixNs  = "unix_ns"
+)
+
+// Mapping describes how the columns of a CSV file are converted to points.
+// The first row of the CSV data is the header naming the columns; columns
+// not present in the mapping are ignored.
+type Mapping struct {
+	// Measurement is the measurement of all points.
+	Measurement string `json:"measurement,omitempty"`
+	// MeasurementColumn is the column holding the measurement of each point.
+	// It takes precedence over Measurement.
+	MeasurementColumn string `json:"measurementColumn,omitempty"`
+	// Timezone is the IANA name of the location of times without a zone;
+	// UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+	// Separator is the field separator of the CSV data; a comma if empty.
+	Separator string `json:"separator,omitempty"`
+	// Columns maps columns to tags, fields and the time of points.
+	Columns []Column `json:"columns"`
+}
+
+// Column maps a CSV column to a part of a point.
+type Column struct {
+	// Column is the name of the column in the CSV header.
+	Column string `json:"column"`
+	// Type is one of tag, field, time or ignore.
+	Type string `json:"type"`
+	// Name is the tag or field key; the column name if empty.
+	Name string `json:"name,omitempty"`
+	// DataType is the type a field value is coerced to; float if empty.
+	DataType string `json:"dataType,omitempty"`
+	// Format is the format of a time column; RFC3339, unix, unix_ms,
+	// unix_us, unix_ns or a Go time layout. RFC3339 if empty.
+	Format string `json:"format,omitempty"`
+}
+
+// key returns the tag or field key of the column.
+func (c Column) key() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Column
+}
+
+// LoadMapping reads a mapping from a YAML or JSON file.
+func LoadMapping(path string) (*Mapping, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMapping(b)
+}
+
+// ParseMapping decodes a mapping from YAML or JSON and validates it.
+func ParseMapping(b []byte) (*Mapping, error) {
+	var m Mapping
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid mapping: %v", err)
+	}
+	if err := m.Valid(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Valid returns an error if the mapping is invalid.
+func (m *Mapping) Valid() error {
+	if m.Measurement == "" && m.MeasurementColumn == "" {
+		return fmt.Errorf("mapping requires a measurement or measurement column")
+	}
+	if _, err := m.location(); err != nil {
+		return err
+	}
+	if _, err := m.separator(); err != nil {
+		return err
+	}
+
+	var fields, times int
+	seen := make(map[string]bool)
+	for _, c := range m.Columns {
+		if c.Column == "" {
+			return fmt.Errorf("mapping column requires a name")
+		}
+		if seen[c.Column] {
+			return fmt.Errorf("column %q is mapped more than once", c.Column)
+		}
+		seen[c.Column] = true
+
+		switch c.Type {
+		case ColumnTag, ColumnIgnore:
+		case ColumnField:
+			switch c.DataType {
+			case "", DataTypeFloat, DataTypeInteger, DataTypeUnsigned, DataTypeBoolean, DataTypeString:
+			default:
+				return fmt.Errorf("column %q has invalid data type %q", c.Column, c.DataType)
+			}
+			fields++
+		case ColumnTime:
+			times++
+		default:
+			return fmt.Errorf("column %q has invalid type %q; supported types are tag, field, time and ignore", c.Column, c.Type)
+		}
+	}
+	if fields == 0 {
+		return fmt.Errorf("mapping requires at least one field column")
+	}
+	if times > 1 {
+		return fmt.Errorf("mapping has more than one time column")
+	}
+	return nil
+}
+
+func (m *Mapping) location() (*time.Location, error) {
+	if m.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", m.Timezone, err)
+	}
+	return loc, nil
+}
+
+func (m *Mapping) separator() (rune, error) {
+	if m.Separator == "" {
+		return ',', nil
+	}
+	r, n := utf8.DecodeRuneInString(m.Separator)
+	if n != len(m.Separator) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid separator %q", m.Separator)
+	}
+	return r, nil
+}
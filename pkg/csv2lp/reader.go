@@ -0,0 +1,259 @@
+package csv2lp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Reader reads CSV data from an underlying reader and returns it as line
+// protocol with nanosecond timestamps. Rows without field values are
+// skipped.
+type Reader struct {
+	r   *csv.Reader
+	m   *Mapping
+	loc *time.Location
+
+	header      bool
+	measurement int
+	columns     []column
+
+	row int
+	buf []byte
+	err error
+}
+
+// column is a mapped column with its index in the CSV rows.
+type column struct {
+	Column
+	index int
+}
+
+// NewReader returns a reader converting the CSV data of r with the mapping m.
+func NewReader(r io.Reader, m *Mapping) (*Reader, error) {
+	if err := m.Valid(); err != nil {
+		return nil, err
+	}
+	loc, _ := m.location()
+	sep, _ := m.separator()
+
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &Reader{r: cr, m: m, loc: loc, measurement: -1}, nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 && r.err == nil {
+		r.err = r.next()
+	}
+	if len(r.buf) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// next converts the next row of the CSV data and appends it to the buffer.
+func (r *Reader) next() error {
+	record, err := r.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			if !r.header {
+				return fmt.Errorf("csv data has no header")
+			}
+			return io.EOF
+		}
+		return err
+	}
+
+	if !r.header {
+		r.header = true
+		return r.readHeader(record)
+	}
+	r.row++
+
+	pt, err := r.point(record)
+	if err != nil {
+		return fmt.Errorf("row %d: %v", r.row, err)
+	}
+	if pt == nil {
+		return nil
+	}
+	r.buf = pt.AppendString(r.buf[:0])
+	r.buf = append(r.buf, '\n')
+	return nil
+}
+
+// readHeader resolves the mapped columns to their indexes in the rows.
+func (r *Reader) readHeader(header []string) error {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			// Some tools prefix CSV exports with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		index[name] = i
+	}
+
+	if c := r.m.MeasurementColumn; c != "" {
+		i, ok := index[c]
+		if !ok {
+			return fmt.Errorf("measurement column %q not found in csv header", c)
+		}
+		r.measurement = i
+	}
+	for _, c := range r.m.Columns {
+		if c.Type == ColumnIgnore {
+			continue
+		}
+		i, ok := index[c.Column]
+		if !ok {
+			return fmt.Errorf("column %q not found in csv header", c.Column)
+		}
+		r.columns = append(r.columns, column{Column: c, index: i})
+	}
+	return nil
+}
+
+// point converts a row to a point. It returns nil if the row has no field
+// values.
+func (r *Reader) point(record []string) (models.Point, error) {
+	value := func(i int) string {
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	name := r.m.Measurement
+	if r.measurement >= 0 {
+		if v := value(r.measurement); v != "" {
+			name = v
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("measurement is empty")
+	}
+
+	var (
+		tags   = make(map[string]string)
+		fields = make(models.Fields)
+		t      time.Time
+	)
+	for _, c := range r.columns {
+		v := value(c.index)
+		if v == "" {
+			continue
+		}
+		switch c.Type {
+		case ColumnTag:
+			tags[c.key()] = v
+		case ColumnField:
+			fv, err := parseField(v, c.DataType)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %v", c.Column.Column, err)
+			}
+			fields[c.key()] = fv
+		case ColumnTime:
+			tv, err := parseTime(v, c.Format, r.loc)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %v", c.Column.Column, err)
+			}
+			t = tv
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return models.NewPoint(name, models.NewTags(tags), fields, t)
+}
+
+// parseField coerces v to the data type of a field.
+func parseField(v, dataType string) (interface{}, error) {
+	switch dataType {
+	case "", DataTypeFloat:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("invalid float %q", v)
+		}
+		return f, nil
+	case DataTypeInteger:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return i, nil
+	case DataTypeUnsigned:
+		u, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unsigned integer %q", v)
+		}
+		return u, nil
+	case DataTypeBoolean:
+		switch strings.ToLower(v) {
+		case "true", "t", "yes", "y", "1":
+			return true, nil
+		case "false", "f", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid boolean %q", v)
+	case DataTypeString:
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid data type %q", dataType)
+}
+
+// parseTime parses v in the given format. Times without a zone are in loc.
+func parseTime(v, format string, loc *time.Location) (time.Time, error) {
+	var unit time.Duration
+	switch format {
+	case "", TimeFormatRFC3339:
+		t, err := time.ParseInLocation(time.RFC3339Nano, v, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid RFC3339 time %q", v)
+		}
+		return t, nil
+	case TimeFormatUnix:
+		unit = time.Second
+	case TimeFormatUnixMs:
+		unit = time.Millisecond
+	case TimeFormatUnixUs:
+		unit = time.Microsecond
+	case TimeFormatUnixNs:
+		unit = time.Nanosecond
+	default:
+		t, err := time.ParseInLocation(format, v, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q for layout %q", v, format)
+		}
+		return t, nil
+	}
+
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if i > math.MaxInt64/int64(unit) || i < math.MinInt64/int64(unit) {
+			return time.Time{}, fmt.Errorf("time %q out of range", v)
+		}
+		return time.Unix(0, i*int64(unit)).UTC(), nil
+	}
+	// Allow fractions of the unit, e.g. seconds with milliseconds.
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, fmt.Errorf("invalid %s time %q", format, v)
+	}
+	ns := f * float64(unit)
+	if ns > math.MaxInt64 || ns < math.MinInt64 {
+		return time.Time{}, fmt.Errorf("time %q out of range", v)
+	}
+	return time.Unix(0, int64(ns)).UTC(), nil
+}
@@ -0,0 +1,127 @@
+package csv2lp_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/csv2lp"
+)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		csv     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "tags fields and time",
+			mapping: `
+measurement: weather
+columns:
+  - {column: Station, type: tag, name: station}
+  - {column: Temp, type: field, name: temp}
+  - {column: Count, type: field, dataType: integer}
+  - {column: Ok, type: field, dataType: boolean}
+  - {column: Note, type: field, dataType: string}
+  - {column: Time, type: time, format: unix_ms}
+`,
+			csv: "Time,Station,Temp,Count,Ok,Note\n" +
+				"1000,a b,1.5,3,yes,\"hi, there\"\n" +
+				"2000,c,2,,f,\n",
+			want: "weather,station=a\\ b Count=3i,Note=\"hi, there\",Ok=true,temp=1.5 1000000000\n" +
+				"weather,station=c Ok=false,temp=2 2000000000\n",
+		},
+		{
+			name: "measurement column and timezone",
+			mapping: `
+measurement: fallback
+measurementColumn: m
+timezone: Europe/Berlin
+separator: ";"
+columns:
+  - {column: v, type: field, dataType: unsignedInteger}
+  - {column: t, type: time, format: "2006-01-02 15:04"}
+  - {column: skip, type: ignore}
+`,
+			csv: "m;v;t;skip\n" +
+				"cpu;7;2019-07-01 12:00;x\n" +
+				";8;2019-07-01 12:30;x\n",
+			want: "cpu v=7u 1561975200000000000\n" +
+				"fallback v=8u 1561977000000000000\n",
+		},
+		{
+			name: "rows without fields are skipped",
+			mapping: `
+measurement: m
+columns:
+  - {column: v, type: field}
+`,
+			csv:  "v\n\n1\n,\n",
+			want: "m v=1\n",
+		},
+		{
+			name: "invalid value",
+			mapping: `
+measurement: m
+columns:
+  - {column: v, type: field, dataType: integer}
+`,
+			csv:     "v\n1\nx\n",
+			wantErr: `row 2: column "v": invalid integer "x"`,
+		},
+		{
+			name: "missing column",
+			mapping: `
+measurement: m
+columns:
+  - {column: v, type: field}
+`,
+			csv:     "w\n1\n",
+			wantErr: `column "v" not found in csv header`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := csv2lp.ParseMapping([]byte(tt.mapping))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := csv2lp.NewReader(strings.NewReader(tt.csv), m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ioutil.ReadAll(r)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("unexpected line protocol:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMapping_Invalid(t *testing.T) {
+	for name, mapping := range map[string]string{
+		"no measurement": `columns: [{column: v, type: field}]`,
+		"no field":       `{"measurement": "m", "columns": [{"column": "t", "type": "tag"}]}`,
+		"bad type":       `{"measurement": "m", "columns": [{"column": "v", "type": "value"}]}`,
+		"bad data type":  `{"measurement": "m", "columns": [{"column": "v", "type": "field", "dataType": "int"}]}`,
+		"two times":      `{"measurement": "m", "columns": [{"column": "v", "type": "field"}, {"column": "a", "type": "time"}, {"column": "b", "type": "time"}]}`,
+		"bad timezone":   `{"measurement": "m", "timezone": "Nowhere/Void", "columns": [{"column": "v", "type": "field"}]}`,
+		"bad separator":  `{"measurement": "m", "separator": ",,", "columns": [{"column": "v", "type": "field"}]}`,
+	} {
+		if _, err := csv2lp.ParseMapping([]byte(mapping)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package tdigest implements the merging t-digest of Dunning and Ertl for
+// estimating quantiles of a stream of values. Unlike other implementations,
+// digests can be serialized and merged, which allows them to be computed
+// ahead of time and combined at query time.
+package tdigest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultCompression is the compression used by New. Larger values
+	// produce more accurate quantiles at the cost of more centroids.
+	DefaultCompression = 100
+
+	// version is the version of the binary format.
+	version byte = 1
+)
+
+// Centroid is a cluster of values summarized by their mean and count.
+type Centroid struct {
+	Mean  float64
+	Count uint64
+}
+
+// TDigest estimates quantiles of the values added to it.
+type TDigest struct {
+	compression float64
+
+	processed   []Centroid
+	unprocessed []Centroid
+
+	processedCount   uint64
+	unprocessedCount uint64
+
+	min, max float64
+}
+
+// New returns an empty TDigest with the default compression.
+func New() *TDigest {
+	return NewWithCompression(DefaultCompression)
+}
+
+// NewWithCompression returns an empty TDigest with the given compression.
+func NewWithCompression(compression float64) *TDigest {
+	if compression < 1 {
+		compression = 1
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(+1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Compression returns the compression of t.
+func (t *TDigest) Compression() float64 { return t.compression }
+
+// Count returns the number of values added to t.
+func (t *TDigest) Count() uint64 { return t.processedCount + t.unprocessedCount }
+
+// Add adds the value x to t. NaN values are ignored.
+func (t *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	t.add(Centroid{Mean: x, Count: 1})
+}
+
+func (t *TDigest) add(c Centroid) {
+	t.unprocessed = append(t.unprocessed, c)
+	t.unprocessedCount += c.Count
+	if c.Mean < t.min {
+		t.min = c.Mean
+	}
+	if c.Mean > t.max {
+		t.max = c.Mean
+	}
+	if len(t.unprocessed) > t.maxUnprocessed() {
+		t.process()
+	}
+}
+
+// Merge adds the values summarized by other to t.
+func (t *TDigest) Merge(other *TDigest) {
+	if other == nil || other.Count() == 0 {
+		return
+	}
+	other.process()
+	for _, c := range other.processed {
+		t.add(c)
+	}
+	// The extremes of other may not be centroid means.
+	t.min = math.Min(t.min, other.min)
+	t.max = math.Max(t.max, other.max)
+}
+
+// Centroids returns the centroids of t in ascending order of their means.
+func (t *TDigest) Centroids() []Centroid {
+	t.process()
+	a := make([]Centroid, len(t.processed))
+	copy(a, t.processed)
+	return a
+}
+
+// Quantile returns an estimate of the value at quantile q, which must be
+// between 0 and 1. It returns NaN if t is empty.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.process()
+	if q < 0 || q > 1 || len(t.processed) == 0 {
+		return math.NaN()
+	}
+	if len(t.processed) == 1 {
+		return t.processed[0].Mean
+	}
+
+	// Each centroid is treated as if its values were centered on its mean,
+	// and the quantile is interpolated between the neighbouring centers, or
+	// between the extremes and the outer centroids.
+	index := q * float64(t.processedCount)
+	first := t.processed[0]
+	if center := float64(first.Count) / 2; index < center {
+		return t.min + (first.Mean-t.min)*index/center
+	}
+
+	var total float64
+	for i := 0; i < len(t.processed)-1; i++ {
+		lo, hi := t.processed[i], t.processed[i+1]
+		left := total + float64(lo.Count)/2
+		right := total + float64(lo.Count) + float64(hi.Count)/2
+		if index <= right {
+			return lo.Mean + (hi.Mean-lo.Mean)*(index-left)/(right-left)
+		}
+		total += float64(lo.Count)
+	}
+
+	last := t.processed[len(t.processed)-1]
+	center := float64(t.processedCount) - float64(last.Count)/2
+	if index >= float64(t.processedCount) {
+		return t.max
+	}
+	return last.Mean + (t.max-last.Mean)*(index-center)/(float64(t.processedCount)-center)
+}
+
+// maxUnprocessed returns the number of centroids that are buffered before
+// they are merged.
+func (t *TDigest) maxUnprocessed() int {
+	return int(8 * t.compression)
+}
+
+// process merges the unprocessed centroids into the processed ones.
+func (t *TDigest) process() {
+	if len(t.unprocessed) == 0 {
+		return
+	}
+
+	all := append(t.unprocessed, t.processed...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Mean < all[j].Mean })
+
+	t.processedCount += t.unprocessedCount
+	t.unprocessedCount = 0
+
+	processed := make([]Centroid, 0, len(t.processed)+1)
+	processed = append(processed, all[0])
+	total := float64(t.processedCount)
+	soFar := float64(all[0].Count)
+	limit := total * t.integratedQ(1)
+	for _, c := range all[1:] {
+		projected := soFar + float64(c.Count)
+		if projected <= limit {
+			last := &processed[len(processed)-1]
+			count := last.Count + c.Count
+			last.Mean += (c.Mean - last.Mean) * float64(c.Count) / float64(count)
+			last.Count = count
+		} else {
+			k := t.integratedLocation(soFar / total)
+			limit = total * t.integratedQ(k+1)
+			processed = append(processed, c)
+		}
+		soFar = projected
+	}
+
+	t.processed = processed
+	t.unprocessed = t.unprocessed[:0]
+}
+
+// integratedQ is the inverse of integratedLocation.
+func (t *TDigest) integratedQ(k float64) float64 {
+	return (math.Sin(math.Min(k, t.compression)*math.Pi/t.compression-math.Pi/2) + 1) / 2
+}
+
+// integratedLocation is the scale function that bounds the size of the
+// centroids, which are smaller near the extremes.
+func (t *TDigest) integratedLocation(q float64) float64 {
+	return t.compression * (math.Asin(2*q-1) + math.Pi/2) / math.Pi
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.process()
+
+	data := make([]byte, 0, 1+3*8+binary.MaxVarintLen64+len(t.processed)*(8+binary.MaxVarintLen64))
+	data = append(data, version)
+	data = appendFloat(data, t.compression)
+	data = appendFloat(data, t.min)
+	data = appendFloat(data, t.max)
+	data = appendUvarint(data, uint64(len(t.processed)))
+	for _, c := range t.processed {
+		data = appendFloat(data, c.Mean)
+		data = appendUvarint(data, c.Count)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+3*8 {
+		return errors.New("tdigest: buffer too short")
+	} else if data[0] != version {
+		return fmt.Errorf("tdigest: unsupported version %d", data[0])
+	}
+	data = data[1:]
+
+	nt := &TDigest{
+		compression: math.Float64frombits(binary.BigEndian.Uint64(data[0:8])),
+		min:         math.Float64frombits(binary.BigEndian.Uint64(data[8:16])),
+		max:         math.Float64frombits(binary.BigEndian.Uint64(data[16:24])),
+	}
+	data = data[24:]
+
+	n, sz := binary.Uvarint(data)
+	if sz <= 0 || n > uint64(len(data)) {
+		return errors.New("tdigest: invalid centroid count")
+	}
+	data = data[sz:]
+
+	nt.processed = make([]Centroid, 0, n)
+	for i := uint64(0); i < n; i++ {
+		if len(data) < 8 {
+			return errors.New("tdigest: buffer too short")
+		}
+		mean := math.Float64frombits(binary.BigEndian.Uint64(data[:8]))
+		count, sz := binary.Uvarint(data[8:])
+		if sz <= 0 {
+			return errors.New("tdigest: invalid centroid")
+		}
+		data = data[8+sz:]
+
+		nt.processed = append(nt.processed, Centroid{Mean: mean, Count: count})
+		nt.processedCount += count
+	}
+	if len(data) > 0 {
+		return errors.New("tdigest: unexpected trailing bytes")
+	}
+
+	*t = *nt
+	return nil
+}
+
+func appendFloat(b []byte, v float64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+	return append(b, buf[:]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
@@ -0,0 +1,98 @@
+package tdigest
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTDigest_Quantile(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	td := New()
+	for _, v := range rnd.Perm(100000) {
+		td.Add(float64(v))
+	}
+
+	if got := td.Count(); got != 100000 {
+		t.Fatalf("got count %d, want 100000", got)
+	}
+	for _, q := range []float64{0, 0.01, 0.25, 0.5, 0.75, 0.99, 0.999, 1} {
+		want := q * 99999
+		if got := td.Quantile(q); math.Abs(got-want) > 0.01*100000 {
+			t.Errorf("Quantile(%v) = %v, want %v", q, got, want)
+		}
+	}
+	if got := td.Quantile(0); got != 0 {
+		t.Errorf("Quantile(0) = %v, want the minimum", got)
+	}
+	if got := td.Quantile(1); got != 99999 {
+		t.Errorf("Quantile(1) = %v, want the maximum", got)
+	}
+}
+
+func TestTDigest_Quantile_Small(t *testing.T) {
+	td := New()
+	if got := td.Quantile(0.5); !math.IsNaN(got) {
+		t.Errorf("Quantile of an empty digest = %v, want NaN", got)
+	}
+	td.Add(5)
+	if got := td.Quantile(0.99); got != 5 {
+		t.Errorf("Quantile of a single value = %v, want 5", got)
+	}
+	td.Add(math.NaN())
+	if got := td.Count(); got != 1 {
+		t.Errorf("NaN was added to the digest")
+	}
+}
+
+func TestTDigest_Merge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	whole := New()
+	merged := New()
+	for i := 0; i < 100; i++ {
+		part := NewWithCompression(50)
+		for j := 0; j < 1000; j++ {
+			v := rnd.NormFloat64()
+			whole.Add(v)
+			part.Add(v)
+		}
+		merged.Merge(part)
+	}
+
+	if merged.Count() != whole.Count() {
+		t.Fatalf("got count %d, want %d", merged.Count(), whole.Count())
+	}
+	for _, q := range []float64{0.01, 0.5, 0.99} {
+		got, want := merged.Quantile(q), whole.Quantile(q)
+		if math.Abs(got-want) > 0.05 {
+			t.Errorf("Quantile(%v) = %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestTDigest_MarshalBinary(t *testing.T) {
+	td := NewWithCompression(20)
+	for i := 0; i < 1000; i++ {
+		td.Add(float64(i % 37))
+	}
+	data, err := td.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got TDigest
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Centroids(), td.Centroids()) {
+		t.Fatalf("unexpected centroids after unmarshal\ngot:  %v\nwant: %v", got.Centroids(), td.Centroids())
+	}
+	if got.Count() != td.Count() || got.Compression() != td.Compression() || got.Quantile(1) != 36 {
+		t.Fatalf("unexpected digest after unmarshal")
+	}
+
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected an error for a truncated buffer")
+	}
+}
@@ -0,0 +1,42 @@
+// Package holtwinters forecasts series with the Holt-Winters method of Flux.
+package holtwinters
+
+import (
+	"math"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux/stdlib/universe/holt_winters"
+)
+
+// Forecast forecasts the n values after vs with the Holt-Winters damped
+// trend method, which is seasonal if the seasonality is at least 2. It
+// returns the forecast values with the root mean square error of the fit of
+// vs, and false if vs are too few to be forecast.
+func Forecast(vs []float64, n, seasonality int) (fcast []float64, rmse float64, ok bool) {
+	if len(vs) < 3 || seasonality >= 2 && len(vs) < 2*seasonality {
+		return nil, 0, false
+	}
+
+	b := array.NewFloat64Builder(memory.DefaultAllocator)
+	b.AppendValues(vs, nil)
+	in := b.NewFloat64Array()
+	defer in.Release()
+
+	out := holt_winters.New(n, seasonality, true, memory.DefaultAllocator).Do(in)
+	defer out.Release()
+	if out.Len() != len(vs)+n {
+		return nil, 0, false
+	}
+
+	var ss float64
+	for i, v := range vs {
+		ss += (out.Value(i) - v) * (out.Value(i) - v)
+	}
+
+	fcast = make([]float64, n)
+	for h := range fcast {
+		fcast[h] = out.Value(len(vs) + h)
+	}
+	return fcast, math.Sqrt(ss / float64(len(vs))), true
+}
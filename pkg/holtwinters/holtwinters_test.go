@@ -0,0 +1,32 @@
+package holtwinters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestForecast(t *testing.T) {
+	trend := make([]float64, 20)
+	for i := range trend {
+		trend[i] = float64(i)
+	}
+	fcast, rmse, ok := Forecast(trend, 3, 0)
+	if !ok {
+		t.Fatal("expected the trend to be forecast")
+	}
+	for h, v := range fcast {
+		if want := float64(len(trend) + h); math.Abs(v-want) > 1 {
+			t.Errorf("forecast %d = %v, want about %v", h, v, want)
+		}
+	}
+	if rmse > 1 {
+		t.Errorf("unexpected root mean square error %v of the fit of a trend", rmse)
+	}
+
+	if _, _, ok := Forecast([]float64{1, 2}, 1, 0); ok {
+		t.Error("expected two values to be too few to forecast")
+	}
+	if _, _, ok := Forecast(trend[:7], 1, 4); ok {
+		t.Error("expected fewer than two seasons to be too few to forecast")
+	}
+}
@@ -47,6 +47,50 @@ func TestWriter_Limiter_ExceedBurst(t *testing.T) {
 	}
 }
 
+func TestUpdateRate(t *testing.T) {
+	r := limiter.NewRate(10, 20)
+	if err := limiter.UpdateRate(r, 0, 1024*1024); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Burst(), 1024*1024; got != want {
+		t.Errorf("unexpected burst: got %d want %d", got, want)
+	}
+
+	// the limit is removed so this must not wait on the original 10 bytes/s.
+	w := limiter.NewWriterWithRate(nopWriteCloser{ioutil.Discard}, r)
+	start := time.Now()
+	if _, err := w.Write(make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("write took %s after removing the limit", elapsed)
+	}
+
+	if err := limiter.UpdateRate(r, 10, -1); err == nil {
+		t.Error("expected error for negative burst limit")
+	}
+
+	// a zero burst limit defaults to the limit.
+	if err := limiter.UpdateRate(r, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Burst(), 10; got != want {
+		t.Errorf("unexpected burst: got %d want %d", got, want)
+	}
+
+	// without a limit nor a burst, writes are neither split nor delayed.
+	if err := limiter.UpdateRate(r, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	start = time.Now()
+	if n, err := w.Write(make([]byte, 1024)); err != nil || n != 1024 {
+		t.Fatalf("unexpected write of %d bytes: %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("write took %s without a limit", elapsed)
+	}
+}
+
 type nopWriteCloser struct {
 	io.Writer
 }
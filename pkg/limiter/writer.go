@@ -2,8 +2,10 @@ package limiter
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -20,12 +22,66 @@ type Rate interface {
 	Burst() int
 }
 
+// NewRate returns a Rate limited to bytesPerSec, with bursts of burstLimit.
+// A bytesPerSec of zero removes the limit and a burstLimit of zero makes the
+// burst equal to bytesPerSec.
 func NewRate(bytesPerSec, burstLimit int) Rate {
-	limiter := rate.NewLimiter(rate.Limit(bytesPerSec), burstLimit)
+	return &updatableRate{limiter: newLimiter(bytesPerSec, burstLimit)}
+}
+
+func newLimiter(bytesPerSec, burstLimit int) *rate.Limiter {
+	limit := rate.Limit(bytesPerSec)
+	if bytesPerSec <= 0 {
+		limit = rate.Inf
+	}
+	if burstLimit <= 0 {
+		burstLimit = bytesPerSec
+	}
+	limiter := rate.NewLimiter(limit, burstLimit)
 	limiter.AllowN(time.Now(), burstLimit) // spend initial burst
 	return limiter
 }
 
+// updatableRate is a Rate whose limit can be replaced while it is in use.
+type updatableRate struct {
+	mu      sync.RWMutex
+	limiter *rate.Limiter
+}
+
+func (r *updatableRate) get() *rate.Limiter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limiter
+}
+
+func (r *updatableRate) WaitN(ctx context.Context, n int) error {
+	return r.get().WaitN(ctx, n)
+}
+
+func (r *updatableRate) Burst() int {
+	return r.get().Burst()
+}
+
+// UpdateRate changes the limit and burst of a Rate created by NewRate.
+// A bytesPerSec of zero removes the limit and a burstLimit of zero makes the
+// burst equal to bytesPerSec.
+func UpdateRate(r Rate, bytesPerSec, burstLimit int) error {
+	u, ok := r.(*updatableRate)
+	if !ok {
+		return errors.New("rate was not created by NewRate")
+	}
+	if burstLimit < 0 {
+		return errors.New("burst limit must not be negative")
+	}
+
+	l := newLimiter(bytesPerSec, burstLimit)
+
+	u.mu.Lock()
+	u.limiter = l
+	u.mu.Unlock()
+	return nil
+}
+
 // NewWriter returns a writer that implements io.Writer with rate limiting.
 // The limiter use a token bucket approach and limits the rate to bytesPerSec
 // with a maximum burst of burstLimit.
@@ -57,8 +113,9 @@ func (s *Writer) Write(b []byte) (int, error) {
 	var n int
 	for n < len(b) {
 		wantToWriteN := len(b[n:])
-		if wantToWriteN > s.limiter.Burst() {
-			wantToWriteN = s.limiter.Burst()
+		// an unlimited rate may have no burst to split the writes by.
+		if burst := s.limiter.Burst(); burst > 0 && wantToWriteN > burst {
+			wantToWriteN = burst
 		}
 
 		wroteN, err := s.w.Write(b[n : n+wantToWriteN])
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+	platform "github.com/influxdata/influxdb"
+)
+
+// Analysis is what a flux query does, as derived without executing it.
+type Analysis struct {
+	// ReadBuckets and WriteBuckets are the buckets the query reads and writes.
+	ReadBuckets  []platform.BucketFilter
+	WriteBuckets []platform.BucketFilter
+
+	// Functions are the names of the functions the query calls, qualified
+	// by the name of the package of functions of imported packages, sorted.
+	Functions []string
+
+	// Ranges are the time ranges the query filters tables with.
+	Ranges []TimeRange
+	// Bounded is whether all the buckets the query reads are filtered with
+	// a time range.
+	Bounded bool
+}
+
+// TimeRange is the time range of a call to range, absolute or relative to
+// the time the query runs.
+type TimeRange struct {
+	Start flux.Time `json:"start"`
+	Stop  flux.Time `json:"stop"`
+}
+
+// Analyze evaluates the query of the package without executing it. Buckets
+// read and written by name are in the organization, if not nil.
+func Analyze(ctx context.Context, pkg *ast.Package, orgID *platform.ID) (*Analysis, error) {
+	ses, _, err := flux.EvalAST(newDeps().Inject(ctx), pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{
+		Functions: functionsCalled(pkg),
+		Bounded:   true,
+	}
+
+	// tables are walked from the results of the query up to their sources,
+	// once for each of the paths with and without a range.
+	type visit struct {
+		t      *flux.TableObject
+		ranged bool
+	}
+	seen := make(map[*flux.TableObject]bool)
+	walked := make(map[visit]bool)
+	var walk func(t *flux.TableObject, ranged bool)
+	walk = func(t *flux.TableObject, ranged bool) {
+		if walked[visit{t, ranged}] {
+			return
+		}
+		walked[visit{t, ranged}] = true
+
+		if s, ok := t.Spec.(*universe.RangeOpSpec); ok {
+			if !seen[t] {
+				a.Ranges = append(a.Ranges, TimeRange{Start: s.Start, Stop: s.Stop})
+			}
+			ranged = true
+		}
+		if s, ok := t.Spec.(BucketAwareOperationSpec); ok {
+			readBuckets, writeBuckets := s.BucketsAccessed(orgID)
+			if !seen[t] {
+				a.ReadBuckets = append(a.ReadBuckets, readBuckets...)
+				a.WriteBuckets = append(a.WriteBuckets, writeBuckets...)
+			}
+			if len(readBuckets) > 0 && !ranged {
+				a.Bounded = false
+			}
+		}
+		seen[t] = true
+
+		t.Parents.Range(func(i int, v values.Value) {
+			walk(v.(*flux.TableObject), ranged)
+		})
+	}
+	for _, se := range ses {
+		if t, ok := se.Value.(*flux.TableObject); ok {
+			walk(t, false)
+		}
+	}
+	return a, nil
+}
+
+// functionsCalled returns the sorted names of the functions called in the
+// package.
+func functionsCalled(pkg *ast.Package) []string {
+	called := make(map[string]bool)
+	ast.Visit(pkg, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+		switch callee := call.Callee.(type) {
+		case *ast.Identifier:
+			called[callee.Name] = true
+		case *ast.MemberExpression:
+			obj, ok := callee.Object.(*ast.Identifier)
+			if !ok {
+				return
+			}
+			switch p := callee.Property.(type) {
+			case *ast.Identifier:
+				called[obj.Name+"."+p.Name] = true
+			case *ast.StringLiteral:
+				called[obj.Name+"."+p.Value] = true
+			}
+		}
+	})
+
+	names := make([]string, 0, len(called))
+	for name := range called {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
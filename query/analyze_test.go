@@ -0,0 +1,104 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func TestAnalyze(t *testing.T) {
+	orgID := influxdb.ID(1)
+	name := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		query        string
+		readBuckets  []string
+		writeBuckets []string
+		functions    []string
+		ranges       []string
+		bounded      bool
+	}{
+		{
+			name:        "bounded read",
+			query:       `from(bucket: "a") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "m")`,
+			readBuckets: []string{"a"},
+			functions:   []string{"filter", "from", "range"},
+			ranges:      []string{"-1h0m0s", "now"},
+			bounded:     true,
+		},
+		{
+			name:         "unbounded copy",
+			query:        `from(bucket: "a") |> filter(fn: (r) => true) |> to(bucket: "b", org: "o")`,
+			readBuckets:  []string{"a"},
+			writeBuckets: []string{"b"},
+			functions:    []string{"filter", "from", "to"},
+		},
+		{
+			name: "one of two reads bounded",
+			query: `import "experimental"
+a = from(bucket: "a") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)
+b = from(bucket: "b")
+join(tables: {a: a, b: b}, on: ["_time"]) |> experimental.group(columns: ["_time"], mode: "extend")`,
+			readBuckets: []string{"a", "b"},
+			functions:   []string{"experimental.group", "from", "join", "range"},
+			ranges:      []string{"2019-01-01T00:00:00Z", "2019-01-02T00:00:00Z"},
+		},
+		{
+			name:      "no tables",
+			query:     `x = 1`,
+			functions: []string{},
+			bounded:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := query.Analyze(context.Background(), parser.ParseSource(tt.query), &orgID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := func(fs []influxdb.BucketFilter) []string {
+				var ns []string
+				for _, f := range fs {
+					if f.Name == nil {
+						f.Name = name("")
+					}
+					ns = append(ns, *f.Name)
+				}
+				return ns
+			}
+			var ranges []string
+			for _, r := range a.Ranges {
+				start, _ := r.Start.MarshalText()
+				stop, _ := r.Stop.MarshalText()
+				ranges = append(ranges, string(start), string(stop))
+			}
+
+			if diff := cmp.Diff(tt.readBuckets, names(a.ReadBuckets)); diff != "" {
+				t.Errorf("unexpected read buckets -want/+got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.writeBuckets, names(a.WriteBuckets)); diff != "" {
+				t.Errorf("unexpected write buckets -want/+got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.functions, a.Functions); diff != "" {
+				t.Errorf("unexpected functions -want/+got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.ranges, ranges); diff != "" {
+				t.Errorf("unexpected ranges -want/+got:\n%s", diff)
+			}
+			if a.Bounded != tt.bounded {
+				t.Errorf("expected bounded to be %v", tt.bounded)
+			}
+		})
+	}
+
+	if _, err := query.Analyze(context.Background(), parser.ParseSource(`from(bucket: "a") |> range(start: x)`), &orgID); err == nil {
+		t.Error("expected an error analyzing an undefined identifier")
+	}
+}
@@ -0,0 +1,195 @@
+package query
+
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// Calendar is the timezone and the first day of the week that windows of
+// whole days are aligned to. Flux windows are aligned to the Unix epoch, i.e.
+// daily windows start at midnight UTC and weekly windows on Thursdays.
+type Calendar struct {
+	// Location is the timezone of the calendar, UTC if nil.
+	Location *time.Location
+	// WeekStart is the first day of the week of the calendar.
+	WeekStart time.Weekday
+}
+
+// WindowOffset returns the offset that aligns windows of the duration to
+// midnight in the timezone of the calendar, and windows of whole weeks to
+// midnight of the first day of the week. The offset is that of the timezone
+// at now, so that windows around a daylight saving change are off by the
+// change.
+func (c Calendar) WindowOffset(every time.Duration, now time.Time) time.Duration {
+	if every <= 0 {
+		return 0
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t := now.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if every%week == 0 {
+		start = start.AddDate(0, 0, -int((start.Weekday()-c.WeekStart+7)%7))
+	}
+
+	offset := time.Duration(start.UnixNano() % int64(every))
+	if offset < 0 {
+		offset += every
+	}
+	return offset
+}
+
+// AlignWindows aligns the windows of whole days of the query to the calendar.
+// An offset is added to window calls whose every is a duration literal of
+// whole days and that have no offset. As aggregateWindow has no offset, its
+// piped calls with such a duration are expanded to the window, aggregate and
+// duplicate calls it is made of. Other windows are left as they are.
+// AlignWindows returns whether the query was modified.
+func AlignWindows(pkg *ast.Package, cal Calendar, now time.Time) bool {
+	aligned := false
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpression:
+			if !isCallTo(n, "window") {
+				return
+			}
+			args, ok := callArgs(n)
+			if !ok || args.get("offset") != nil {
+				return
+			}
+			every, ok := wholeDays(args.get("every"), now)
+			if !ok {
+				return
+			}
+			args.set("offset", durationLiteral(cal.WindowOffset(every, now)))
+			aligned = true
+		case *ast.PipeExpression:
+			if !isCallTo(n.Call, "aggregateWindow") {
+				return
+			}
+			args, ok := callArgs(n.Call)
+			if !ok {
+				return
+			}
+			every, ok := wholeDays(args.get("every"), now)
+			if !ok || args.get("fn") == nil {
+				return
+			}
+			expandAggregateWindow(n, args, cal.WindowOffset(every, now))
+			aligned = true
+		}
+	}), pkg)
+	return aligned
+}
+
+// expandAggregateWindow replaces the piped aggregateWindow call with the
+// calls of its definition, with the window offset by the duration.
+func expandAggregateWindow(p *ast.PipeExpression, args callArguments, offset time.Duration) {
+	column := args.getOr("column", &ast.StringLiteral{Value: "_value"})
+	timeSrc := args.getOr("timeSrc", &ast.StringLiteral{Value: "_stop"})
+	timeDst := args.getOr("timeDst", &ast.StringLiteral{Value: "_time"})
+	createEmpty := args.getOr("createEmpty", &ast.BooleanLiteral{Value: true})
+
+	windowed := pipeCall(p.Argument, &ast.Identifier{Name: "window"},
+		property("every", args.get("every")),
+		property("offset", durationLiteral(offset)),
+		property("createEmpty", createEmpty),
+	)
+	aggregated := pipeCall(windowed, args.get("fn"), property("column", column))
+	duplicated := pipeCall(aggregated, &ast.Identifier{Name: "duplicate"},
+		property("column", timeSrc),
+		property("as", timeDst),
+	)
+
+	p.Argument = duplicated
+	p.Call = &ast.CallExpression{
+		Callee: &ast.Identifier{Name: "window"},
+		Arguments: []ast.Expression{&ast.ObjectExpression{Properties: []*ast.Property{
+			property("every", &ast.Identifier{Name: "inf"}),
+			property("timeColumn", timeDst),
+		}}},
+	}
+}
+
+// wholeDays returns the duration of the duration literal if it is a positive
+// number of whole days. Months and years are not of a fixed duration.
+func wholeDays(e ast.Expression, now time.Time) (time.Duration, bool) {
+	lit, ok := e.(*ast.DurationLiteral)
+	if !ok {
+		return 0, false
+	}
+	for _, v := range lit.Values {
+		if v.Unit == "mo" || v.Unit == "y" {
+			return 0, false
+		}
+	}
+	d, err := ast.DurationFrom(lit, now)
+	if err != nil || d <= 0 || d%day != 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// callArguments is the object argument of a call.
+type callArguments struct {
+	*ast.ObjectExpression
+}
+
+// callArgs returns the arguments of the call, or false if they are not an
+// object.
+func callArgs(c *ast.CallExpression) (callArguments, bool) {
+	if len(c.Arguments) != 1 {
+		return callArguments{}, false
+	}
+	obj, ok := c.Arguments[0].(*ast.ObjectExpression)
+	return callArguments{obj}, ok
+}
+
+func (a callArguments) get(name string) ast.Expression {
+	for _, p := range a.Properties {
+		if p.Key.Key() == name {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+func (a callArguments) getOr(name string, def ast.Expression) ast.Expression {
+	if v := a.get(name); v != nil {
+		return v
+	}
+	return def
+}
+
+func (a callArguments) set(name string, v ast.Expression) {
+	a.Properties = append(a.Properties, property(name, v))
+}
+
+func property(name string, v ast.Expression) *ast.Property {
+	return &ast.Property{Key: &ast.Identifier{Name: name}, Value: v}
+}
+
+func pipeCall(arg, callee ast.Expression, props ...*ast.Property) *ast.PipeExpression {
+	return &ast.PipeExpression{
+		Argument: arg,
+		Call: &ast.CallExpression{
+			Callee:    callee,
+			Arguments: []ast.Expression{&ast.ObjectExpression{Properties: props}},
+		},
+	}
+}
+
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	return &ast.DurationLiteral{
+		Values: []ast.Duration{{Magnitude: int64(d / time.Millisecond), Unit: "ms"}},
+	}
+}
@@ -0,0 +1,203 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func TestCalendar_WindowOffset(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2019, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		cal   query.Calendar
+		every time.Duration
+		want  time.Duration
+	}{
+		{
+			name:  "utc days",
+			cal:   query.Calendar{},
+			every: 24 * time.Hour,
+			want:  0,
+		},
+		{
+			name:  "utc weeks starting monday",
+			cal:   query.Calendar{WeekStart: time.Monday},
+			every: 7 * 24 * time.Hour,
+			// the epoch is a Thursday; the following Monday is 4 days later.
+			want: 4 * 24 * time.Hour,
+		},
+		{
+			name:  "summer time days",
+			cal:   query.Calendar{Location: berlin},
+			every: 24 * time.Hour,
+			want:  22 * time.Hour,
+		},
+		{
+			name:  "summer time weeks starting sunday",
+			cal:   query.Calendar{Location: berlin, WeekStart: time.Sunday},
+			every: 7 * 24 * time.Hour,
+			// midnight of Sundays in Berlin is 22:00 UTC of Saturdays.
+			want: 2*24*time.Hour + 22*time.Hour,
+		},
+		{
+			name:  "hours",
+			cal:   query.Calendar{Location: berlin},
+			every: time.Hour,
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cal.WindowOffset(tt.every, now); got != tt.want {
+				t.Errorf("unexpected offset: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlignWindows(t *testing.T) {
+	now := time.Date(2019, 10, 17, 12, 0, 0, 0, time.UTC)
+	cal := query.Calendar{WeekStart: time.Monday}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		aligned bool
+	}{
+		{
+			name:    "window",
+			query:   `from(bucket: "a") |> range(start: -30d) |> window(every: 1w)`,
+			want:    `from(bucket: "a") |> range(start: -30d) |> window(every: 1w, offset: 345600000ms)`,
+			aligned: true,
+		},
+		{
+			name:  "window with offset",
+			query: `from(bucket: "a") |> range(start: -30d) |> window(every: 1w, offset: 1d)`,
+			want:  `from(bucket: "a") |> range(start: -30d) |> window(every: 1w, offset: 1d)`,
+		},
+		{
+			name:    "aggregate window",
+			query:   `from(bucket: "a") |> range(start: -30d) |> aggregateWindow(every: 1w, fn: sum)`,
+			want:    `from(bucket: "a") |> range(start: -30d) |> window(every: 1w, offset: 345600000ms, createEmpty: true) |> sum(column: "_value") |> duplicate(column: "_stop", as: "_time") |> window(every: inf, timeColumn: "_time")`,
+			aligned: true,
+		},
+		{
+			name:  "aggregate window of minutes",
+			query: `from(bucket: "a") |> range(start: -1h) |> aggregateWindow(every: 1m, fn: mean)`,
+			want:  `from(bucket: "a") |> range(start: -1h) |> aggregateWindow(every: 1m, fn: mean)`,
+		},
+		{
+			name:  "window of months",
+			query: `from(bucket: "a") |> range(start: -1y) |> window(every: 1mo)`,
+			want:  `from(bucket: "a") |> range(start: -1y) |> window(every: 1mo)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parser.ParseSource(tt.query)
+			if aligned := query.AlignWindows(pkg, cal, now); aligned != tt.aligned {
+				t.Errorf("unexpected aligned: got %v, want %v", aligned, tt.aligned)
+			}
+			got, want := ast.Format(pkg.Files[0]), ast.Format(parser.ParseSource(tt.want).Files[0])
+			if got != want {
+				t.Errorf("unexpected query:\ngot  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestAlignWindows_Results(t *testing.T) {
+	now := time.Date(2019, 10, 17, 12, 0, 0, 0, time.UTC)
+	pkg := parser.ParseSource(`
+import "csv"
+
+data = "
+#datatype,string,long,dateTime:RFC3339,double
+#group,false,false,false,false
+#default,_result,,,
+,result,table,_time,_value
+,,0,2019-10-06T23:30:00Z,1
+,,0,2019-10-07T00:30:00Z,2
+,,0,2019-10-08T12:00:00Z,3
+"
+
+csv.from(csv: data)
+	|> range(start: 2019-10-01T00:00:00Z, stop: 2019-10-15T00:00:00Z)
+	|> aggregateWindow(every: 1w, fn: sum, createEmpty: false)
+`)
+	query.AlignWindows(pkg, query.Calendar{WeekStart: time.Monday}, now)
+
+	ctx := dependenciestest.Default().Inject(context.Background())
+	prog, err := lang.ASTCompiler{AST: pkg, Now: now}.Compile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := prog.Start(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	type point struct {
+		time  time.Time
+		value float64
+	}
+	var got []point
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				ti, vi := -1, -1
+				for i, c := range cr.Cols() {
+					switch c.Label {
+					case "_time":
+						ti = i
+					case "_value":
+						vi = i
+					}
+				}
+				for i := 0; i < cr.Len(); i++ {
+					got = append(got, point{
+						time:  time.Unix(0, cr.Times(ti).Value(i)).UTC(),
+						value: cr.Floats(vi).Value(i),
+					})
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the weeks end on Mondays.
+	want := []point{
+		{time: time.Date(2019, 10, 7, 0, 0, 0, 0, time.UTC), value: 1},
+		{time: time.Date(2019, 10, 14, 0, 0, 0, 0, time.UTC), value: 5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected points: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].time.Equal(want[i].time) || got[i].value != want[i].value {
+			t.Errorf("unexpected point %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
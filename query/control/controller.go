@@ -57,6 +57,13 @@ type Controller struct {
 	abort      chan struct{}
 	memory     *memoryManager
 
+	// concurrencyQuota is the number of workers processing the query queue.
+	// Only the first concurrencyLimit of them execute queries.
+	concurrencyQuota int
+	limitMu          sync.Mutex
+	concurrencyLimit int
+	limitChanged     chan struct{}
+
 	metrics   *controllerMetrics
 	labelKeys []string
 
@@ -181,13 +188,17 @@ func New(config Config) (*Controller, error) {
 		metrics:      newControllerMetrics(c.MetricLabelKeys),
 		labelKeys:    c.MetricLabelKeys,
 		dependencies: c.ExecutorDependencies,
+
+		concurrencyQuota: c.ConcurrencyQuota,
+		concurrencyLimit: c.ConcurrencyQuota,
+		limitChanged:     make(chan struct{}),
 	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
-		go func() {
+		go func(worker int) {
 			defer ctrl.wg.Done()
-			ctrl.processQueryQueue()
-		}()
+			ctrl.processQueryQueue(worker)
+		}(i)
 	}
 	return ctrl, nil
 }
@@ -371,11 +382,59 @@ func (c *Controller) enqueueQuery(q *Query) error {
 	return nil
 }
 
-func (c *Controller) processQueryQueue() {
+// ConcurrencyLimit returns the number of queries currently allowed to execute concurrently.
+func (c *Controller) ConcurrencyLimit() int {
+	limit, _ := c.concurrencyState()
+	return limit
+}
+
+// SetConcurrencyLimit changes the number of queries allowed to execute
+// concurrently. The limit must be positive and may not exceed the
+// ConcurrencyQuota the controller was created with. Queries that are already
+// executing are not interrupted when the limit is lowered.
+func (c *Controller) SetConcurrencyLimit(n int) error {
+	if n <= 0 || n > c.concurrencyQuota {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("query concurrency must be between 1 and %d", c.concurrencyQuota),
+		}
+	}
+
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	if n == c.concurrencyLimit {
+		return nil
+	}
+	c.concurrencyLimit = n
+	close(c.limitChanged)
+	c.limitChanged = make(chan struct{})
+	c.logger.Info("Changed query concurrency limit", zap.Int("concurrency_limit", n))
+	return nil
+}
+
+func (c *Controller) concurrencyState() (int, <-chan struct{}) {
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	return c.concurrencyLimit, c.limitChanged
+}
+
+func (c *Controller) processQueryQueue(worker int) {
 	for {
+		limit, changed := c.concurrencyState()
+		if worker >= limit {
+			// this worker is idle until the limit is raised again.
+			select {
+			case <-c.done:
+				return
+			case <-changed:
+			}
+			continue
+		}
+
 		select {
 		case <-c.done:
 			return
+		case <-changed:
 		case q := <-c.queryQueue:
 			c.executeQuery(q)
 		}
@@ -451,6 +510,27 @@ func (c *Controller) Queries() []*Query {
 	return queries
 }
 
+// Drain signals to the Controller that it should not accept any new
+// queries and waits for the executing queries to finish on their own, until
+// the Context is done. Unlike Shutdown, it does not cancel the queries; the
+// queries that are still executing when it returns are canceled by Shutdown.
+func (c *Controller) Drain(ctx context.Context) error {
+	c.queriesMu.Lock()
+	c.shutdown = true
+	n := len(c.queries)
+	c.queriesMu.Unlock()
+	if n == 0 {
+		return nil
+	}
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Shutdown will signal to the Controller that it should not accept any
 // new queries and that it should finish executing any existing queries.
 // This will return once the Controller's run loop has been exited and all
@@ -620,6 +620,60 @@ func TestController_ShutdownWithTimeout(t *testing.T) {
 	cancel()
 }
 
+func TestController_Drain(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing, finish := make(chan struct{}), make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					close(executing)
+					select {
+					case <-finish:
+					case <-ctx.Done():
+						t.Error("query canceled while draining")
+					}
+				},
+			}, nil
+		},
+	}
+
+	q, err := ctrl.Query(context.Background(), makeRequest(compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+	}()
+	<-executing
+
+	// The query is not canceled and keeps the controller from draining.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	if err := ctrl.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error draining with an executing query: %v", err)
+	}
+	cancel()
+
+	if _, err := ctrl.Query(context.Background(), makeRequest(mockCompiler)); err == nil {
+		t.Error("expected new queries to be rejected while draining")
+	}
+
+	close(finish)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ctrl.Drain(ctx); err != nil {
+		t.Errorf("unexpected error draining after the query finished: %v", err)
+	}
+}
+
 func TestController_PerQueryMemoryLimit(t *testing.T) {
 	ctrl, err := control.New(config)
 	if err != nil {
@@ -727,6 +781,69 @@ func TestController_ConcurrencyQuota(t *testing.T) {
 	}
 }
 
+func TestController_SetConcurrencyLimit(t *testing.T) {
+	const concurrencyQuota = 2
+
+	config := config
+	config.ConcurrencyQuota = concurrencyQuota
+	config.QueueSize = concurrencyQuota
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	for _, n := range []int{0, concurrencyQuota + 1} {
+		if err := ctrl.SetConcurrencyLimit(n); err == nil {
+			t.Errorf("expected error setting concurrency limit to %d", n)
+		}
+	}
+	if err := ctrl.SetConcurrencyLimit(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctrl.ConcurrencyLimit(); got != 1 {
+		t.Fatalf("unexpected concurrency limit: got %d want 1", got)
+	}
+
+	executing := make(chan struct{}, concurrencyQuota)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+					<-q.Canceled
+				},
+			}, nil
+		},
+	}
+
+	for i := 0; i < concurrencyQuota; i++ {
+		q, err := ctrl.Query(context.Background(), makeRequest(compiler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer q.Done()
+	}
+
+	// Only one query may execute while the limit is 1.
+	<-executing
+	select {
+	case <-executing:
+		t.Fatal("expected second query to stay queued")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Raising the limit lets the queued query execute.
+	if err := ctrl.SetConcurrencyLimit(concurrencyQuota); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-executing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected second query to execute after raising the limit")
+	}
+}
+
 func TestController_QueueSize(t *testing.T) {
 	const (
 		concurrencyQuota = 2
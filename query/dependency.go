@@ -49,6 +49,21 @@ func (b *BucketLookup) LookupName(ctx context.Context, orgID platform.ID, id pla
 	return bucket.Name
 }
 
+// LookupFieldTypeConflictPolicy returns the field type conflict policy of a
+// bucket given its organization ID and its bucket ID. The default policy is
+// returned when the bucket cannot be found.
+func (b *BucketLookup) LookupFieldTypeConflictPolicy(ctx context.Context, orgID platform.ID, id platform.ID) platform.FieldTypeConflictPolicy {
+	filter := platform.BucketFilter{
+		OrganizationID: &orgID,
+		ID:             &id,
+	}
+	bucket, err := b.BucketService.FindBucket(ctx, filter)
+	if err != nil || bucket == nil {
+		return ""
+	}
+	return bucket.FieldTypeConflictPolicy
+}
+
 func (b *BucketLookup) FindAllBuckets(ctx context.Context, orgID platform.ID) ([]*platform.Bucket, int) {
 	oid := platform.ID(orgID)
 	filter := platform.BucketFilter{
@@ -0,0 +1,208 @@
+package query
+
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
+
+// minDownsampleWindow is the smallest window results are downsampled with.
+const minDownsampleWindow = time.Millisecond
+
+// Downsample windows the results of the query to about maxPoints points per
+// series, by piping each result into aggregateWindow with the mean, e.g. so
+// that a chart gets about one point per pixel instead of every point of its
+// time range. The window is the duration of the time range of the first
+// range call of the query divided by maxPoints.
+//
+// The bounds of the range must be durations relative to now, times, now(),
+// or variables and options, e.g. v.timeRangeStart, of those, declared in the
+// query or in extern. Downsample returns false and leaves the query
+// unmodified if they are not. Results must have numeric values.
+func Downsample(pkg *ast.Package, extern *ast.File, now time.Time, maxPoints int) bool {
+	if maxPoints <= 0 {
+		return false
+	}
+
+	files := pkg.Files
+	if extern != nil {
+		files = append([]*ast.File{extern}, files...)
+	}
+	r := downsampleResolver{files: files, now: now}
+
+	args := findRangeArgs(pkg)
+	if args == nil {
+		return false
+	}
+	start, ok := r.time(args["start"])
+	if !ok {
+		return false
+	}
+	stop := now
+	if e, found := args["stop"]; found {
+		if stop, ok = r.time(e); !ok {
+			return false
+		}
+	}
+	if !stop.After(start) {
+		return false
+	}
+
+	every := stop.Sub(start) / time.Duration(maxPoints)
+	every = (every + minDownsampleWindow - 1).Truncate(minDownsampleWindow)
+	if every < minDownsampleWindow {
+		every = minDownsampleWindow
+	}
+
+	applied := false
+	for _, f := range pkg.Files {
+		for _, s := range f.Body {
+			stmt, ok := s.(*ast.ExpressionStatement)
+			if !ok {
+				continue
+			}
+			// Results are downsampled before they are yielded, so that
+			// yield stays the last call.
+			if p, ok := stmt.Expression.(*ast.PipeExpression); ok && isCallTo(p.Call, "yield") {
+				p.Argument = pipeAggregateWindow(p.Argument, every)
+			} else {
+				stmt.Expression = pipeAggregateWindow(stmt.Expression, every)
+			}
+			applied = true
+		}
+	}
+	return applied
+}
+
+func pipeAggregateWindow(e ast.Expression, every time.Duration) *ast.PipeExpression {
+	return &ast.PipeExpression{
+		Argument: e,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "aggregateWindow"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{Name: "every"},
+							Value: &ast.DurationLiteral{
+								Values: []ast.Duration{{Magnitude: int64(every / time.Millisecond), Unit: "ms"}},
+							},
+						},
+						{Key: &ast.Identifier{Name: "fn"}, Value: &ast.Identifier{Name: "mean"}},
+						{Key: &ast.Identifier{Name: "createEmpty"}, Value: &ast.BooleanLiteral{Value: false}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func isCallTo(c *ast.CallExpression, name string) bool {
+	id, ok := c.Callee.(*ast.Identifier)
+	return ok && id.Name == name
+}
+
+// findRangeArgs returns the arguments of the first range call of the query
+// by name, or nil if it has none.
+func findRangeArgs(pkg *ast.Package) map[string]ast.Expression {
+	var args map[string]ast.Expression
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		c, ok := n.(*ast.CallExpression)
+		if !ok || args != nil || !isCallTo(c, "range") || len(c.Arguments) != 1 {
+			return
+		}
+		obj, ok := c.Arguments[0].(*ast.ObjectExpression)
+		if !ok {
+			return
+		}
+		args = make(map[string]ast.Expression, len(obj.Properties))
+		for _, p := range obj.Properties {
+			args[p.Key.Key()] = p.Value
+		}
+	}), pkg)
+	return args
+}
+
+// downsampleResolver resolves the bounds of a range to times.
+type downsampleResolver struct {
+	files []*ast.File
+	now   time.Time
+}
+
+func (r downsampleResolver) time(e ast.Expression) (time.Time, bool) {
+	return r.resolve(e, 0)
+}
+
+// maxResolveDepth bounds the variables followed to resolve a bound.
+const maxResolveDepth = 4
+
+func (r downsampleResolver) resolve(e ast.Expression, depth int) (time.Time, bool) {
+	if depth > maxResolveDepth {
+		return time.Time{}, false
+	}
+
+	switch e := e.(type) {
+	case *ast.DateTimeLiteral:
+		return e.Value, true
+	case *ast.DurationLiteral:
+		d, err := ast.DurationFrom(e, r.now)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return r.now.Add(d), true
+	case *ast.UnaryExpression:
+		lit, ok := e.Argument.(*ast.DurationLiteral)
+		if !ok || e.Operator != ast.SubtractionOperator {
+			return time.Time{}, false
+		}
+		d, err := ast.DurationFrom(lit, r.now)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return r.now.Add(-d), true
+	case *ast.CallExpression:
+		if isCallTo(e, "now") && len(e.Arguments) == 0 {
+			return r.now, true
+		}
+	case *ast.Identifier:
+		if v := r.lookup(e.Name); v != nil {
+			return r.resolve(v, depth+1)
+		}
+	case *ast.MemberExpression:
+		id, ok := e.Object.(*ast.Identifier)
+		if !ok {
+			return time.Time{}, false
+		}
+		obj, ok := r.lookup(id.Name).(*ast.ObjectExpression)
+		if !ok {
+			return time.Time{}, false
+		}
+		for _, p := range obj.Properties {
+			if p.Key.Key() == e.Property.Key() {
+				return r.resolve(p.Value, depth+1)
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// lookup returns the value of the last declaration of the variable or option
+// with the name, or nil if there is none.
+func (r downsampleResolver) lookup(name string) ast.Expression {
+	var v ast.Expression
+	for _, f := range r.files {
+		for _, s := range f.Body {
+			var a *ast.VariableAssignment
+			switch s := s.(type) {
+			case *ast.VariableAssignment:
+				a = s
+			case *ast.OptionStatement:
+				a, _ = s.Assignment.(*ast.VariableAssignment)
+			}
+			if a != nil && a.ID.Name == name {
+				v = a.Init
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestDownsample(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	extern := parser.ParseSource(`option v = {timeRangeStart: -30d, timeRangeStop: now()}`).Files[0]
+
+	tests := []struct {
+		name      string
+		query     string
+		extern    *ast.File
+		maxPoints int
+		want      string
+	}{
+		{
+			name:      "relative range",
+			query:     `from(bucket: "b") |> range(start: -1h)`,
+			maxPoints: 600,
+			want:      `from(bucket: "b") |> range(start: -1h) |> aggregateWindow(every: 6000ms, fn: mean, createEmpty: false)`,
+		},
+		{
+			name:      "before yield",
+			query:     `from(bucket: "b") |> range(start: 2019-12-31T00:00:00Z, stop: 2019-12-31T01:00:00Z) |> yield(name: "cpu")`,
+			maxPoints: 7,
+			want:      `from(bucket: "b") |> range(start: 2019-12-31T00:00:00Z, stop: 2019-12-31T01:00:00Z) |> aggregateWindow(every: 514286ms, fn: mean, createEmpty: false) |> yield(name: "cpu")`,
+		},
+		{
+			name:      "range of the dashboard",
+			query:     `from(bucket: "b") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`,
+			extern:    extern,
+			maxPoints: 1000,
+			want:      `from(bucket: "b") |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> aggregateWindow(every: 2592000ms, fn: mean, createEmpty: false)`,
+		},
+		{
+			name:      "range of a variable",
+			query:     "start = -2m\ndata = from(bucket: \"b\") |> range(start: start)\ndata",
+			maxPoints: 1000000,
+			want:      "start = -2m\ndata = from(bucket: \"b\") |> range(start: start)\ndata |> aggregateWindow(every: 1ms, fn: mean, createEmpty: false)",
+		},
+		{
+			name:      "unknown range",
+			query:     `from(bucket: "b") |> range(start: v.timeRangeStart)`,
+			maxPoints: 1000,
+		},
+		{
+			name:      "range stopping before its start",
+			query:     `from(bucket: "b") |> range(start: -1h, stop: -2h)`,
+			maxPoints: 1000,
+		},
+		{
+			name:      "no range",
+			query:     `from(bucket: "b")`,
+			maxPoints: 1000,
+		},
+		{
+			name:  "no maximum",
+			query: `from(bucket: "b") |> range(start: -1h)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parser.ParseSource(tt.query)
+			got := query.Downsample(pkg, tt.extern, now, tt.maxPoints)
+			if got != (tt.want != "") {
+				t.Fatalf("Downsample() = %v, want %v", got, tt.want != "")
+			}
+			if !got {
+				if f := ast.Format(pkg.Files[0]); f != ast.Format(parser.ParseSource(tt.query).Files[0]) {
+					t.Errorf("Downsample() modified the query to %s", f)
+				}
+				return
+			}
+			if got, want := ast.Format(pkg.Files[0]), ast.Format(parser.ParseSource(tt.want).Files[0]); got != want {
+				t.Errorf("Downsample() query = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package query
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrResultTruncated is returned by a LimitWriter once a limit of the result
+// is reached. The query writing the result should stop.
+var ErrResultTruncated = errors.New("query result truncated")
+
+// ResultLimits bound the size of an encoded query result. Zero means unlimited.
+type ResultLimits struct {
+	MaxRows  int64
+	MaxBytes int64
+}
+
+// Min returns the limits that satisfy both l and o.
+func (l ResultLimits) Min(o ResultLimits) ResultLimits {
+	return ResultLimits{
+		MaxRows:  minLimit(l.MaxRows, o.MaxRows),
+		MaxBytes: minLimit(l.MaxBytes, o.MaxBytes),
+	}
+}
+
+// IsZero reports whether the limits are unlimited.
+func (l ResultLimits) IsZero() bool {
+	return l.MaxRows <= 0 && l.MaxBytes <= 0
+}
+
+func minLimit(a, b int64) int64 {
+	switch {
+	case a <= 0:
+		return b
+	case b <= 0 || a < b:
+		return a
+	default:
+		return b
+	}
+}
+
+// LimitWriter writes an annotated CSV query result to the underlying writer
+// until one of the limits is reached. The rows of the result are only written
+// whole; once a row would exceed a limit, a #truncated annotation describing
+// the limit is written in place of the rest of the result.
+type LimitWriter struct {
+	w      io.Writer
+	limits ResultLimits
+	header bool
+
+	line      []byte
+	quoted    bool
+	newTable  bool
+	rows      int64
+	written   int64
+	truncated bool
+}
+
+// NewLimitWriter returns a LimitWriter that writes to w. The header reports
+// whether the tables of the result start with a header row.
+func NewLimitWriter(w io.Writer, limits ResultLimits, header bool) *LimitWriter {
+	return &LimitWriter{
+		w:        w,
+		limits:   limits,
+		header:   header,
+		newTable: true,
+	}
+}
+
+// Write buffers p and writes each of its complete lines. It returns
+// ErrResultTruncated once the result was truncated.
+func (w *LimitWriter) Write(p []byte) (int, error) {
+	if w.truncated {
+		return 0, ErrResultTruncated
+	}
+	for i, b := range p {
+		w.line = append(w.line, b)
+		if b == '"' {
+			w.quoted = !w.quoted
+		}
+		if b != '\n' || w.quoted {
+			continue
+		}
+		if err := w.writeLine(); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the last line of the result when it has no line terminator.
+func (w *LimitWriter) Flush() error {
+	if w.truncated || len(w.line) == 0 {
+		return nil
+	}
+	return w.writeLine()
+}
+
+// Truncated reports whether the result was truncated.
+func (w *LimitWriter) Truncated() bool {
+	return w.truncated
+}
+
+func (w *LimitWriter) writeLine() error {
+	line := w.line
+	w.line = w.line[:0]
+
+	switch content := bytes.TrimRight(line, "\r\n"); {
+	case len(content) == 0:
+		// blank lines separate the tables of differing schemas.
+		w.newTable = true
+	case content[0] == '#':
+		// annotations precede the header of a table.
+	case w.newTable && w.header:
+		w.newTable = false
+	default:
+		w.newTable = false
+		if w.limits.MaxRows > 0 && w.rows >= w.limits.MaxRows {
+			return w.truncate(fmt.Sprintf("row limit of %d reached", w.limits.MaxRows))
+		}
+		w.rows++
+	}
+
+	if w.limits.MaxBytes > 0 && w.written+int64(len(line)) > w.limits.MaxBytes {
+		return w.truncate(fmt.Sprintf("byte limit of %d reached", w.limits.MaxBytes))
+	}
+	n, err := w.w.Write(line)
+	w.written += int64(n)
+	return err
+}
+
+func (w *LimitWriter) truncate(reason string) error {
+	w.truncated = true
+	trailer := fmt.Sprintf("\r\n#truncated,true,%q\r\n", reason)
+	if _, err := io.WriteString(w.w, trailer); err != nil {
+		return err
+	}
+	return ErrResultTruncated
+}
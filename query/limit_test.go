@@ -0,0 +1,103 @@
+package query_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/query"
+)
+
+const limitResult = "#datatype,string,long,double\r\n" +
+	"#group,false,false,false\r\n" +
+	"#default,_result,,\r\n" +
+	",result,table,_value\r\n" +
+	",,0,1\r\n" +
+	",,0,2\r\n" +
+	"\r\n" +
+	"#datatype,string,long,string\r\n" +
+	"#group,false,false,false\r\n" +
+	"#default,_result,,\r\n" +
+	",result,table,_value\r\n" +
+	",,1,\"a\r\nb\"\r\n" +
+	",,1,c\r\n"
+
+func TestLimitWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    query.ResultLimits
+		header    bool
+		want      string
+		truncated bool
+	}{
+		{
+			name:   "result within limits",
+			limits: query.ResultLimits{MaxRows: 4, MaxBytes: int64(len(limitResult))},
+			header: true,
+			want:   limitResult,
+		},
+		{
+			name:   "row limit",
+			limits: query.ResultLimits{MaxRows: 3},
+			header: true,
+			want: limitResult[:strings.Index(limitResult, ",,1,c")] +
+				"\r\n#truncated,true,\"row limit of 3 reached\"\r\n",
+			truncated: true,
+		},
+		{
+			name:   "byte limit",
+			limits: query.ResultLimits{MaxBytes: 106},
+			header: true,
+			want: limitResult[:strings.Index(limitResult, ",,0,2")] +
+				"\r\n#truncated,true,\"byte limit of 106 reached\"\r\n",
+			truncated: true,
+		},
+		{
+			name:   "headers are counted as rows without header",
+			limits: query.ResultLimits{MaxRows: 2},
+			header: false,
+			want: limitResult[:strings.Index(limitResult, ",,0,2")] +
+				"\r\n#truncated,true,\"row limit of 2 reached\"\r\n",
+			truncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := query.NewLimitWriter(&buf, tt.limits, tt.header)
+
+			// write in small chunks to split the lines between writes.
+			var err error
+			for r := strings.NewReader(limitResult); err == nil; {
+				_, err = io.CopyN(w, r, 7)
+			}
+			if tt.truncated && err != query.ErrResultTruncated {
+				t.Fatalf("expected truncation error, got %v", err)
+			} else if !tt.truncated && err != io.EOF {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("unexpected flush error: %v", err)
+			}
+
+			if got := w.Truncated(); got != tt.truncated {
+				t.Errorf("unexpected truncation: got %v want %v", got, tt.truncated)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("unexpected result:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultLimits_Min(t *testing.T) {
+	org := query.ResultLimits{MaxRows: 100}
+	req := query.ResultLimits{MaxRows: 1000, MaxBytes: 10}
+	if got, want := org.Min(req), (query.ResultLimits{MaxRows: 100, MaxBytes: 10}); got != want {
+		t.Errorf("unexpected limits: got %+v want %+v", got, want)
+	}
+	if !(query.ResultLimits{}).IsZero() {
+		t.Error("expected empty limits to be unlimited")
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/jsonweb"
 )
 
 // Request respresents the query to run.
@@ -79,7 +81,7 @@ func ContextWithRequest(ctx context.Context, req *Request) context.Context {
 	return context.WithValue(ctx, activeContextKey, req)
 }
 
-//RequestFromContext retrieves a *Request from a context.
+// RequestFromContext retrieves a *Request from a context.
 // If not request exists on the context nil is returned.
 func RequestFromContext(ctx context.Context) *Request {
 	v := ctx.Value(activeContextKey)
@@ -89,6 +91,27 @@ func RequestFromContext(ctx context.Context) *Request {
 	return v.(*Request)
 }
 
+// AuthorizationFromContext returns the authorization the queries of the
+// authorizer on ctx run with in the organization orgID. Sessions and tokens
+// are converted to an ephemeral authorization for the organization.
+func AuthorizationFromContext(ctx context.Context, orgID platform.ID) (*platform.Authorization, error) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a := a.(type) {
+	case *platform.Authorization:
+		return a, nil
+	case *platform.Session:
+		return a.EphemeralAuth(orgID), nil
+	case *jsonweb.Token:
+		return a.EphemeralAuth(orgID), nil
+	default:
+		return nil, fmt.Errorf("%v: %T", platform.ErrAuthorizerNotSupported, a)
+	}
+}
+
 // ProxyRequest specifies a query request and the dialect for the results.
 type ProxyRequest struct {
 	// Request is the basic query request
@@ -0,0 +1,106 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+
+	"github.com/influxdata/influxdb/pkg/holtwinters"
+)
+
+// Algorithms of detect.
+const (
+	MAD         = "mad"
+	ZScore      = "zscore"
+	HoltWinters = "holtWinters"
+)
+
+// An algorithm returns the value expected after the baseline, and the
+// standard deviation of the values around it.
+type algorithm func(baseline []float64, seasonality int) (expected, stddev float64, ok bool)
+
+var algorithms = map[string]algorithm{
+	MAD:         mad,
+	ZScore:      zScore,
+	HoltWinters: holtWinters,
+}
+
+// madScale scales the median absolute deviation to the standard deviation
+// of normally distributed values.
+const madScale = 1.4826
+
+// detect returns the value expected in place of x after the baseline, and
+// the deviation of x from it in standard deviations. A deviation from a
+// baseline without any variation has the maximum score, and a baseline too
+// short to expect a value has none.
+func detect(alg algorithm, baseline []float64, x float64, seasonality int) (expected, score float64) {
+	expected, stddev, ok := alg(baseline, seasonality)
+	if !ok {
+		return x, 0
+	}
+
+	d := math.Abs(x - expected)
+	switch {
+	case d == 0:
+		return expected, 0
+	case stddev == 0:
+		// The score is written with the status of checks, which cannot
+		// be infinite.
+		return expected, math.MaxFloat64
+	default:
+		return expected, d / stddev
+	}
+}
+
+// mad expects the median of the baseline, with the scaled median absolute
+// deviation from it as the standard deviation. Unlike the mean, the median
+// is robust to the outliers of the baseline.
+func mad(baseline []float64, _ int) (float64, float64, bool) {
+	if len(baseline) < 2 {
+		return 0, 0, false
+	}
+	m := median(baseline)
+	ds := make([]float64, len(baseline))
+	for i, v := range baseline {
+		ds[i] = math.Abs(v - m)
+	}
+	return m, madScale * median(ds), true
+}
+
+func median(vs []float64) float64 {
+	s := append([]float64(nil), vs...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// zScore expects the mean of the baseline, with its sample standard deviation.
+func zScore(baseline []float64, _ int) (float64, float64, bool) {
+	n := float64(len(baseline))
+	if n < 2 {
+		return 0, 0, false
+	}
+	var sum float64
+	for _, v := range baseline {
+		sum += v
+	}
+	mean := sum / n
+	var ss float64
+	for _, v := range baseline {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / (n - 1)), true
+}
+
+// holtWinters expects the Holt-Winters forecast of the baseline, with the
+// root mean square error of the fit of the baseline as the standard
+// deviation. The forecast is seasonal if the seasonality is at least 2.
+func holtWinters(baseline []float64, seasonality int) (float64, float64, bool) {
+	fcast, rmse, ok := holtwinters.Forecast(baseline, 1, seasonality)
+	if !ok {
+		return 0, 0, false
+	}
+	return fcast[0], rmse, true
+}
@@ -0,0 +1,298 @@
+// Package anomaly provides a Flux function that tests whether the latest
+// value of each series is anomalous compared to the values preceding it.
+// It is used by anomaly checks, so that users can alert on unusual values
+// without writing the statistics in Flux.
+package anomaly
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	PackagePath = "influxdata/influxdb/anomaly"
+
+	DetectKind = "detect"
+
+	// DefaultSensitivity is the number of standard deviations by which a
+	// value deviates from the expected value before it is anomalous.
+	DefaultSensitivity = 3.0
+
+	// Columns added to the latest row of each table by detect.
+	ExpectedColLabel = "_expected"
+	ScoreColLabel    = "_score"
+	AnomalyColLabel  = "_anomaly"
+)
+
+// source declares the builtin values of the package.
+const source = `package anomaly
+
+builtin detect
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "anomaly.flux"
+	flux.RegisterPackage(pkg)
+
+	detectSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"algorithm":   semantic.String,
+			"sensitivity": semantic.Float,
+			"column":      semantic.String,
+			"seasonality": semantic.Int,
+		},
+		[]string{"algorithm"},
+	)
+	flux.RegisterPackageValue(PackagePath, DetectKind, flux.FunctionValue(DetectKind, createDetectOpSpec, detectSignature))
+	flux.RegisterOpSpec(DetectKind, newDetectOp)
+	plan.RegisterProcedureSpec(DetectKind, newDetectProcedure, DetectKind)
+	execute.RegisterTransformation(DetectKind, createDetectTransformation)
+}
+
+// DetectOpSpec tests the latest value of the column of each table against
+// the values preceding it with an algorithm.
+type DetectOpSpec struct {
+	Algorithm   string  `json:"algorithm"`
+	Sensitivity float64 `json:"sensitivity"`
+	Column      string  `json:"column"`
+	Seasonality int64   `json:"seasonality"`
+}
+
+func createDetectOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := &DetectOpSpec{
+		Sensitivity: DefaultSensitivity,
+		Column:      execute.DefaultValueColLabel,
+	}
+
+	algorithm, err := args.GetRequiredString("algorithm")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := algorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("unknown anomaly detection algorithm %q", algorithm)
+	}
+	spec.Algorithm = algorithm
+
+	if s, ok, err := args.GetFloat("sensitivity"); err != nil {
+		return nil, err
+	} else if ok {
+		if s <= 0 {
+			return nil, fmt.Errorf("sensitivity must be positive, got %v", s)
+		}
+		spec.Sensitivity = s
+	}
+
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	}
+
+	if s, ok, err := args.GetInt("seasonality"); err != nil {
+		return nil, err
+	} else if ok {
+		if s < 0 {
+			return nil, fmt.Errorf("seasonality must not be negative, got %d", s)
+		}
+		spec.Seasonality = s
+	}
+
+	return spec, nil
+}
+
+func newDetectOp() flux.OperationSpec {
+	return new(DetectOpSpec)
+}
+
+func (s *DetectOpSpec) Kind() flux.OperationKind {
+	return DetectKind
+}
+
+type DetectProcedureSpec struct {
+	plan.DefaultCost
+	Algorithm   string
+	Sensitivity float64
+	Column      string
+	Seasonality int64
+}
+
+func newDetectProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*DetectOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &DetectProcedureSpec{
+		Algorithm:   spec.Algorithm,
+		Sensitivity: spec.Sensitivity,
+		Column:      spec.Column,
+		Seasonality: spec.Seasonality,
+	}, nil
+}
+
+func (s *DetectProcedureSpec) Kind() plan.ProcedureKind {
+	return DetectKind
+}
+
+func (s *DetectProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(DetectProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec
+func (s *DetectProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createDetectTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*DetectProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewDetectTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type detectTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+
+	algorithm   algorithm
+	sensitivity float64
+	column      string
+	seasonality int
+}
+
+// NewDetectTransformation returns the transformation of detect. For each
+// table, it outputs the latest row with a non-null value, to which the
+// expected value, the score of the deviation from the expected value in
+// standard deviations, and whether the score exceeds the sensitivity are
+// added. The rows of tables are expected to be sorted by time.
+func NewDetectTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *DetectProcedureSpec) execute.Transformation {
+	return &detectTransformation{
+		d:           d,
+		cache:       cache,
+		algorithm:   algorithms[spec.Algorithm],
+		sensitivity: spec.Sensitivity,
+		column:      spec.Column,
+		seasonality: int(spec.Seasonality),
+	}
+}
+
+func (t *detectTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	cols := tbl.Cols()
+	colIdx := execute.ColIdx(t.column, cols)
+	if colIdx < 0 {
+		return fmt.Errorf("cannot find column %s", t.column)
+	}
+	switch typ := cols[colIdx].Type; typ {
+	case flux.TInt, flux.TUInt, flux.TFloat:
+	default:
+		return fmt.Errorf("cannot detect anomalies of non-numerical type %s", typ)
+	}
+	for _, label := range []string{ExpectedColLabel, ScoreColLabel, AnomalyColLabel} {
+		if execute.ColIdx(label, cols) >= 0 {
+			return fmt.Errorf("column %s already exists", label)
+		}
+	}
+
+	var (
+		vs   []float64
+		last []values.Value
+	)
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			v, ok := floatValue(cr, i, colIdx)
+			if !ok {
+				continue
+			}
+			vs = append(vs, v)
+			last = last[:0]
+			for j := range cols {
+				last = append(last, execute.ValueForRow(cr, i, j))
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(vs) == 0 {
+		return nil
+	}
+
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("detect found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableCols(tbl, builder); err != nil {
+		return err
+	}
+	for _, c := range []flux.ColMeta{
+		{Label: ExpectedColLabel, Type: flux.TFloat},
+		{Label: ScoreColLabel, Type: flux.TFloat},
+		{Label: AnomalyColLabel, Type: flux.TBool},
+	} {
+		if _, err := builder.AddCol(c); err != nil {
+			return err
+		}
+	}
+
+	expected, score := detect(t.algorithm, vs[:len(vs)-1], vs[len(vs)-1], t.seasonality)
+	row := append(last,
+		values.NewFloat(expected),
+		values.NewFloat(score),
+		values.NewBool(score > t.sensitivity),
+	)
+	for j, v := range row {
+		if err := builder.AppendValue(j, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// floatValue returns the numerical value of the column of the row as a
+// float, and false if it is null.
+func floatValue(cr flux.ColReader, i, j int) (float64, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	default:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i)
+	}
+}
+
+func (t *detectTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *detectTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *detectTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *detectTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
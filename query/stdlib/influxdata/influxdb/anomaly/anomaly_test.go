@@ -0,0 +1,117 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+)
+
+func TestDetect_Process(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "host", Type: flux.TString},
+	}
+	wantCols := append(cols[:len(cols):len(cols)],
+		flux.ColMeta{Label: ExpectedColLabel, Type: flux.TFloat},
+		flux.ColMeta{Label: ScoreColLabel, Type: flux.TFloat},
+		flux.ColMeta{Label: AnomalyColLabel, Type: flux.TBool},
+	)
+
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(1), 8.0, "a"},
+				{execute.Time(2), 10.0, "a"},
+				{execute.Time(3), 12.0, "a"},
+				{execute.Time(4), 10.0, "a"},
+				{execute.Time(5), 14.0, "a"},
+				{execute.Time(6), 16.0, "a"},
+				{execute.Time(7), nil, "a"},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(1), 10.0, "b"},
+				{execute.Time(2), 10.0, "b"},
+				{execute.Time(3), 10.0, "b"},
+				{execute.Time(4), 11.0, "b"},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(1), 10.0, "c"},
+			},
+		},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"host"},
+			ColMeta: wantCols,
+			Data: [][]interface{}{
+				{execute.Time(6), 16.0, "a", 10.0, 6 / (madScale * 2), false},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: wantCols,
+			Data: [][]interface{}{
+				{execute.Time(4), 11.0, "b", 10.0, math.MaxFloat64, true},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: wantCols,
+			Data: [][]interface{}{
+				{execute.Time(1), 10.0, "c", 10.0, 0.0, false},
+			},
+		},
+	}
+
+	executetest.ProcessTestHelper(t, data, want, nil, func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+		return NewDetectTransformation(d, c, &DetectProcedureSpec{
+			Algorithm:   MAD,
+			Sensitivity: DefaultSensitivity,
+			Column:      "_value",
+		})
+	})
+}
+
+func TestDetect(t *testing.T) {
+	trend := make([]float64, 30)
+	for i := range trend {
+		trend[i] = float64(i)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		alg         string
+		baseline    []float64
+		x           float64
+		wantAnomaly bool
+	}{
+		{name: "zscore within", alg: ZScore, baseline: []float64{9, 10, 11, 10, 9, 11}, x: 11.5},
+		{name: "zscore outside", alg: ZScore, baseline: []float64{9, 10, 11, 10, 9, 11}, x: 14, wantAnomaly: true},
+		{name: "mad ignores outliers", alg: MAD, baseline: []float64{9, 10, 11, 10, 100, 11}, x: 14, wantAnomaly: true},
+		{name: "zscore skewed by outliers", alg: ZScore, baseline: []float64{9, 10, 11, 10, 100, 11}, x: 14},
+		{name: "holt-winters follows the trend", alg: HoltWinters, baseline: trend, x: 30},
+		{name: "holt-winters outside the trend", alg: HoltWinters, baseline: trend, x: 15, wantAnomaly: true},
+		{name: "zscore outside the trend", alg: ZScore, baseline: trend, x: 15},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, score := detect(algorithms[tt.alg], tt.baseline, tt.x, 0)
+			if got := score > DefaultSensitivity; got != tt.wantAnomaly {
+				t.Fatalf("expected anomaly %v, got a score of %v around %v", tt.wantAnomaly, score, expected)
+			}
+		})
+	}
+}
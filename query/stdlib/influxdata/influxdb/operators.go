@@ -17,6 +17,7 @@ const (
 	ReadGroupPhysKind     = "ReadGroupPhysKind"
 	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
 	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+	ReadSketchPhysKind    = "ReadSketchPhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -146,3 +147,24 @@ func (s *ReadTagValuesPhysSpec) Copy() plan.ProcedureSpec {
 	ns.TagKey = s.TagKey
 	return ns
 }
+
+// ReadSketchPhysSpec reads an estimate for each series from the sketches
+// that storage keeps of the series values.
+type ReadSketchPhysSpec struct {
+	ReadRangePhysSpec
+
+	Method   string
+	Quantile float64
+}
+
+func (s *ReadSketchPhysSpec) Kind() plan.ProcedureKind {
+	return ReadSketchPhysKind
+}
+
+func (s *ReadSketchPhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadSketchPhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+	ns.Method = s.Method
+	ns.Quantile = s.Quantile
+	return ns
+}
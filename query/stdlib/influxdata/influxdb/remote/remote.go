@@ -0,0 +1,323 @@
+// Package remote provides the Flux function remote.from, which reads a
+// bucket of another InfluxDB 2.x instance through a remote connection of the
+// organization running the query:
+//
+//	import "influxdata/influxdb/remote"
+//
+//	remote.from(remote: "edge-1", bucket: "telegraf")
+//	    |> range(start: -1h)
+//	    |> filter(fn: (r) => r._measurement == "cpu")
+//
+// The range and the predicates of the filters that directly follow
+// remote.from are pushed down to the remote instance, so that only the
+// matching data is transferred.
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+)
+
+const (
+	PackagePath = "influxdata/influxdb/remote"
+
+	FromRemoteKind = "fromRemote"
+)
+
+// source declares the builtin values of the package.
+const source = `package remote
+
+builtin from
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "remote.flux"
+	flux.RegisterPackage(pkg)
+
+	fromSignature := semantic.FunctionPolySignature{
+		Parameters: map[string]semantic.PolyType{
+			"remote": semantic.String,
+			"bucket": semantic.String,
+		},
+		Required: semantic.LabelSet{"remote", "bucket"},
+		Return:   flux.TableObjectType,
+	}
+	flux.RegisterPackageValue(PackagePath, "from", flux.FunctionValue(FromRemoteKind, createFromRemoteOpSpec, fromSignature))
+	flux.RegisterOpSpec(FromRemoteKind, newFromRemoteOp)
+	plan.RegisterProcedureSpec(FromRemoteKind, newFromRemoteProcedure, FromRemoteKind)
+	execute.RegisterSource(FromRemoteKind, createFromRemoteSource)
+}
+
+type key int
+
+const dependenciesKey key = iota
+
+// Dependencies are the dependencies of remote.from.
+type Dependencies struct {
+	// RemoteConnections finds the remote connection of the organization
+	// running the query.
+	RemoteConnections influxdb.RemoteConnectionService
+	// Dial returns the query service of the instance of a remote connection.
+	Dial func(ctx context.Context, rc *influxdb.RemoteConnection) (query.QueryService, error)
+}
+
+// Inject implements flux.Dependency.
+func (d Dependencies) Inject(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dependenciesKey, d)
+}
+
+// GetDependencies returns the dependencies injected in ctx.
+func GetDependencies(ctx context.Context) (Dependencies, bool) {
+	d, ok := ctx.Value(dependenciesKey).(Dependencies)
+	return d, ok
+}
+
+type FromRemoteOpSpec struct {
+	Remote string `json:"remote"`
+	Bucket string `json:"bucket"`
+}
+
+func createFromRemoteOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromRemoteOpSpec)
+
+	remote, err := args.GetRequiredString("remote")
+	if err != nil {
+		return nil, err
+	}
+	spec.Remote = remote
+
+	bucket, err := args.GetRequiredString("bucket")
+	if err != nil {
+		return nil, err
+	}
+	spec.Bucket = bucket
+
+	return spec, nil
+}
+
+func newFromRemoteOp() flux.OperationSpec {
+	return new(FromRemoteOpSpec)
+}
+
+func (s *FromRemoteOpSpec) Kind() flux.OperationKind {
+	return FromRemoteKind
+}
+
+// FromRemoteProcedureSpec reads a bucket of a remote instance. The bounds
+// and the filter are set when a range and the predicates of a filter are
+// pushed down to the remote instance.
+type FromRemoteProcedureSpec struct {
+	plan.DefaultCost
+
+	Remote string
+	Bucket string
+
+	BoundsSet bool
+	Bounds    flux.Bounds
+
+	FilterSet bool
+	Filter    *semantic.FunctionExpression
+}
+
+func newFromRemoteProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromRemoteOpSpec)
+	if !ok {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("invalid spec type %T", qs),
+		}
+	}
+
+	return &FromRemoteProcedureSpec{
+		Remote: spec.Remote,
+		Bucket: spec.Bucket,
+	}, nil
+}
+
+func (s *FromRemoteProcedureSpec) Kind() plan.ProcedureKind {
+	return FromRemoteKind
+}
+
+func (s *FromRemoteProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	if s.FilterSet {
+		ns.Filter = s.Filter.Copy().(*semantic.FunctionExpression)
+	}
+	return &ns
+}
+
+// TimeBounds implements plan.BoundsAwareProcedureSpec.
+func (s *FromRemoteProcedureSpec) TimeBounds(predecessorBounds *plan.Bounds) *plan.Bounds {
+	if !s.BoundsSet {
+		return nil
+	}
+	return &plan.Bounds{
+		Start: values.ConvertTime(s.Bounds.Start.Time(s.Bounds.Now)),
+		Stop:  values.ConvertTime(s.Bounds.Stop.Time(s.Bounds.Now)),
+	}
+}
+
+// PostPhysicalValidate rejects reads of remote instances whose range could
+// not be pushed down, since storage does not support unbounded reads.
+func (s *FromRemoteProcedureSpec) PostPhysicalValidate(id plan.NodeID) error {
+	if s.BoundsSet {
+		return nil
+	}
+	return &flux.Error{
+		Code: codes.Invalid,
+		Msg:  fmt.Sprintf("cannot submit unbounded read to %q of remote %q; try bounding 'remote.from' with a call to 'range'", s.Bucket, s.Remote),
+	}
+}
+
+func createFromRemoteSource(s plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	span, ctx := tracing.StartSpanFromContext(a.Context())
+	defer span.Finish()
+
+	spec := s.(*FromRemoteProcedureSpec)
+
+	bounds := a.StreamContext().Bounds()
+	if bounds == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "nil bounds passed to remote.from",
+		}
+	}
+
+	deps, ok := GetDependencies(a.Context())
+	if !ok {
+		return nil, &flux.Error{
+			Code: codes.Unimplemented,
+			Msg:  "remote connections are not available",
+		}
+	}
+
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  "missing request on context",
+		}
+	}
+
+	rcs, _, err := deps.RemoteConnections.FindRemoteConnections(ctx, influxdb.RemoteConnectionFilter{
+		OrganizationID: &req.OrganizationID,
+		Name:           &spec.Remote,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rcs) == 0 {
+		return nil, &flux.Error{
+			Code: codes.NotFound,
+			Msg:  fmt.Sprintf("could not find remote %q", spec.Remote),
+		}
+	}
+	rc := rcs[0]
+
+	qs, err := deps.Dial(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	q, err := remoteQuery(spec, *bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fromRemoteSource{
+		id:      id,
+		remote:  rc,
+		querier: qs,
+		query:   q,
+		bounds:  *bounds,
+	}, nil
+}
+
+// fromRemoteSource runs the query on the remote instance and passes the
+// tables of its results to the transformations.
+type fromRemoteSource struct {
+	id      execute.DatasetID
+	ts      []execute.Transformation
+	remote  *influxdb.RemoteConnection
+	querier query.QueryService
+	query   string
+	bounds  execute.Bounds
+}
+
+func (s *fromRemoteSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *fromRemoteSource) Run(ctx context.Context) {
+	err := s.run(ctx)
+	if err != nil {
+		err = &flux.Error{
+			Code: codes.Inherit,
+			Msg:  fmt.Sprintf("error reading remote %q", s.remote.Name),
+			Err:  err,
+		}
+	}
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+func (s *fromRemoteSource) run(ctx context.Context) error {
+	results, err := s.querier.Query(ctx, &query.Request{
+		OrganizationID: s.remote.RemoteOrgID,
+		Compiler:       lang.FluxCompiler{Query: s.query},
+	})
+	if err != nil {
+		return err
+	}
+	defer results.Release()
+
+	for results.More() {
+		if err := results.Next().Tables().Do(s.process); err != nil {
+			return err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return err
+	}
+
+	for _, t := range s.ts {
+		if err := t.UpdateWatermark(s.id, s.bounds.Stop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// process passes a table to every transformation. The tables decoded from
+// the response can only be read once, so each transformation but the last
+// is given a copy.
+func (s *fromRemoteSource) process(tbl flux.Table) error {
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	for i, t := range s.ts {
+		var next flux.Table = buf
+		if i < len(s.ts)-1 {
+			next = buf.Copy()
+		}
+		if err := t.Process(s.id, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
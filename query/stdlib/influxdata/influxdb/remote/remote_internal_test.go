@@ -0,0 +1,100 @@
+package remote
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+)
+
+func Test_remoteQuery(t *testing.T) {
+	bounds := execute.Bounds{
+		Start: execute.Time(time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC).UnixNano()),
+		Stop:  execute.Time(time.Date(2019, 10, 1, 1, 0, 0, 500, time.UTC).UnixNano()),
+	}
+	member := func(property string) semantic.Expression {
+		return &semantic.MemberExpression{
+			Object:   &semantic.IdentifierExpression{Name: "r"},
+			Property: property,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		bucket string
+		filter semantic.Expression
+		want   string
+	}{
+		{
+			name:   "range",
+			bucket: "telegraf",
+			want: `from(bucket: "telegraf")
+	|> range(start: 2019-10-01T00:00:00Z, stop: 2019-10-01T01:00:00.0000005Z)`,
+		},
+		{
+			name:   "escaped bucket",
+			bucket: `a "quoted" ${bucket}`,
+			want: `from(bucket: "a \"quoted\" \${bucket}")
+	|> range(start: 2019-10-01T00:00:00Z, stop: 2019-10-01T01:00:00.0000005Z)`,
+		},
+		{
+			name:   "filter",
+			bucket: "telegraf",
+			filter: &semantic.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left: &semantic.BinaryExpression{
+					Operator: ast.EqualOperator,
+					Left:     member("_measurement"),
+					Right:    &semantic.StringLiteral{Value: "cpu"},
+				},
+				Right: &semantic.LogicalExpression{
+					Operator: ast.OrOperator,
+					Left: &semantic.BinaryExpression{
+						Operator: ast.RegexpMatchOperator,
+						Left:     member("host host"),
+						Right:    &semantic.RegexpLiteral{Value: regexp.MustCompile(`^edge/\d+$`)},
+					},
+					Right: &semantic.BinaryExpression{
+						Operator: ast.GreaterThanEqualOperator,
+						Left:     member("_value"),
+						Right:    &semantic.FloatLiteral{Value: 0.5},
+					},
+				},
+			},
+			want: `from(bucket: "telegraf")
+	|> range(start: 2019-10-01T00:00:00Z, stop: 2019-10-01T01:00:00.0000005Z)
+	|> filter(fn: (r) =>
+		(r["_measurement"] == "cpu" and (r["host host"] =~ /^edge\/\d+$/ or r["_value"] >= 0.5)))`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &FromRemoteProcedureSpec{Bucket: tt.bucket}
+			if tt.filter != nil {
+				spec.FilterSet = true
+				spec.Filter = &semantic.FunctionExpression{
+					Block: &semantic.FunctionBlock{
+						Parameters: &semantic.FunctionParameters{
+							List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+						},
+						Body: tt.filter,
+					},
+				}
+			}
+			got, err := remoteQuery(spec, bounds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("remoteQuery() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if err := ast.GetError(parser.ParseSource(got)); err != nil {
+				t.Errorf("remote query does not parse: %v", err)
+			}
+		})
+	}
+}
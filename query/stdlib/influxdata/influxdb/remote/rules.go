@@ -0,0 +1,266 @@
+package remote
+
+import (
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+func init() {
+	plan.RegisterPhysicalRules(
+		PushDownRemoteRangeRule{},
+		PushDownRemoteFilterRule{},
+	)
+}
+
+// rowParam is the name of the parameter of the filter sent to the remote instance.
+const rowParam = "r"
+
+// PushDownRemoteRangeRule pushes down a range to the remote instance.
+type PushDownRemoteRangeRule struct{}
+
+func (rule PushDownRemoteRangeRule) Name() string {
+	return "PushDownRemoteRangeRule"
+}
+
+// Pattern matches 'remote.from |> range'
+func (rule PushDownRemoteRangeRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.RangeKind, plan.Pat(FromRemoteKind))
+}
+
+func (rule PushDownRemoteRangeRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	fromNode := node.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*FromRemoteProcedureSpec)
+	rangeSpec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
+
+	// The remote range is written with the default columns.
+	if fromSpec.BoundsSet ||
+		rangeSpec.TimeColumn != execute.DefaultTimeColLabel ||
+		rangeSpec.StartColumn != execute.DefaultStartColLabel ||
+		rangeSpec.StopColumn != execute.DefaultStopColLabel {
+		return node, false, nil
+	}
+
+	newFromSpec := fromSpec.Copy().(*FromRemoteProcedureSpec)
+	newFromSpec.BoundsSet = true
+	newFromSpec.Bounds = rangeSpec.Bounds
+	return plan.CreatePhysicalNode("ReadRemoteRange", newFromSpec), true, nil
+}
+
+// PushDownRemoteFilterRule pushes down the predicates of a filter that the
+// remote instance can evaluate. The predicates that refer to values of the
+// local scope stay in the local filter.
+type PushDownRemoteFilterRule struct{}
+
+func (rule PushDownRemoteFilterRule) Name() string {
+	return "PushDownRemoteFilterRule"
+}
+
+// Pattern matches 'remote.from |> filter'
+func (rule PushDownRemoteFilterRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.FilterKind, plan.Pat(FromRemoteKind))
+}
+
+func (rule PushDownRemoteFilterRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	filterSpec := pn.ProcedureSpec().(*universe.FilterProcedureSpec)
+	fromNode := pn.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*FromRemoteProcedureSpec)
+
+	// The filter is sent after the range, so the range must be pushed first.
+	if !fromSpec.BoundsSet {
+		return pn, false, nil
+	}
+
+	bodyExpr, ok := filterSpec.Fn.Fn.Block.Body.(semantic.Expression)
+	if !ok || len(filterSpec.Fn.Fn.Block.Parameters.List) != 1 {
+		return pn, false, nil
+	}
+	paramName := filterSpec.Fn.Fn.Block.Parameters.List[0].Key.Name
+
+	pushable, notPushable, err := semantic.PartitionPredicates(bodyExpr, func(e semantic.Expression) (bool, error) {
+		_, ok := predicateToAST(paramName, e)
+		return ok, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if pushable == nil {
+		return pn, false, nil
+	}
+	// The pushed predicates refer to the row as rowParam.
+	pushable = renameParam(pushable, paramName)
+
+	newFromSpec := fromSpec.Copy().(*FromRemoteProcedureSpec)
+	if newFromSpec.FilterSet {
+		newFromSpec.Filter.Block.Body = semantic.ExprsToConjunction(newFromSpec.Filter.Block.Body.(semantic.Expression), pushable)
+	} else {
+		newFromSpec.FilterSet = true
+		newFromSpec.Filter = &semantic.FunctionExpression{
+			Block: &semantic.FunctionBlock{
+				Parameters: &semantic.FunctionParameters{
+					List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: rowParam}}},
+				},
+				Body: pushable,
+			},
+		}
+	}
+
+	if notPushable == nil {
+		mergedNode, err := plan.MergeToPhysicalNode(pn, fromNode, newFromSpec)
+		if err != nil {
+			return nil, false, err
+		}
+		return mergedNode, true, nil
+	}
+
+	if err := fromNode.ReplaceSpec(newFromSpec); err != nil {
+		return nil, false, err
+	}
+	newFilterSpec := filterSpec.Copy().(*universe.FilterProcedureSpec)
+	newFilterSpec.Fn.Fn.Block.Body = notPushable
+	if err := pn.ReplaceSpec(newFilterSpec); err != nil {
+		return nil, false, err
+	}
+	return pn, true, nil
+}
+
+// renameParam returns a copy of the predicate that refers to the row as rowParam.
+func renameParam(e semantic.Expression, param string) semantic.Expression {
+	e = e.Copy().(semantic.Expression)
+	if param == rowParam {
+		return e
+	}
+	semantic.Walk(semantic.CreateVisitor(func(n semantic.Node) {
+		if m, ok := n.(*semantic.MemberExpression); ok {
+			if id, ok := m.Object.(*semantic.IdentifierExpression); ok && id.Name == param {
+				m.Object = &semantic.IdentifierExpression{Name: rowParam}
+			}
+		}
+	}), e)
+	return e
+}
+
+// predicateToAST converts a predicate on the row param to its Flux syntax.
+// It returns false if the predicate refers to anything but the row and
+// literals, since the remote instance cannot resolve the local scope.
+func predicateToAST(param string, e semantic.Expression) (ast.Expression, bool) {
+	switch e := e.(type) {
+	case *semantic.LogicalExpression:
+		left, ok := predicateToAST(param, e.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := predicateToAST(param, e.Right)
+		if !ok {
+			return nil, false
+		}
+		return &ast.LogicalExpression{Operator: e.Operator, Left: left, Right: right}, true
+	case *semantic.BinaryExpression:
+		left, ok := predicateToAST(param, e.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := predicateToAST(param, e.Right)
+		if !ok {
+			return nil, false
+		}
+		return &ast.BinaryExpression{Operator: e.Operator, Left: left, Right: right}, true
+	case *semantic.UnaryExpression:
+		arg, ok := predicateToAST(param, e.Argument)
+		if !ok {
+			return nil, false
+		}
+		return &ast.UnaryExpression{Operator: e.Operator, Argument: arg}, true
+	case *semantic.MemberExpression:
+		if id, ok := e.Object.(*semantic.IdentifierExpression); !ok || id.Name != param {
+			return nil, false
+		}
+		return &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: rowParam},
+			Property: stringLiteral(e.Property),
+		}, true
+	case *semantic.StringLiteral:
+		return stringLiteral(e.Value), true
+	case *semantic.IntegerLiteral:
+		return &ast.IntegerLiteral{Value: e.Value}, true
+	case *semantic.UnsignedIntegerLiteral:
+		return &ast.UnsignedIntegerLiteral{Value: e.Value}, true
+	case *semantic.FloatLiteral:
+		return &ast.FloatLiteral{Value: e.Value}, true
+	case *semantic.BooleanLiteral:
+		return &ast.BooleanLiteral{Value: e.Value}, true
+	case *semantic.RegexpLiteral:
+		return &ast.RegexpLiteral{Value: e.Value}, true
+	case *semantic.DateTimeLiteral:
+		return &ast.DateTimeLiteral{Value: e.Value}, true
+	case *semantic.DurationLiteral:
+		return &ast.DurationLiteral{Values: e.Values}, true
+	default:
+		return nil, false
+	}
+}
+
+// stringLiteral returns a string literal whose source escapes the value,
+// including the start of an interpolation that the formatter leaves as is.
+func stringLiteral(v string) *ast.StringLiteral {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range v {
+		switch r {
+		case '"', '\\', '$':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return &ast.StringLiteral{
+		Value:    v,
+		BaseNode: ast.BaseNode{Loc: &ast.SourceLocation{Source: b.String()}},
+	}
+}
+
+// remoteQuery returns the Flux query run on the remote instance. It reads
+// the bucket within the bounds of the local query with the pushed down
+// predicates.
+func remoteQuery(spec *FromRemoteProcedureSpec, bounds execute.Bounds) (string, error) {
+	pipe := func(arg ast.Expression, name string, props ...*ast.Property) ast.Expression {
+		call := &ast.CallExpression{Callee: &ast.Identifier{Name: name}}
+		if len(props) > 0 {
+			call.Arguments = []ast.Expression{&ast.ObjectExpression{Properties: props}}
+		}
+		if arg == nil {
+			return call
+		}
+		return &ast.PipeExpression{Argument: arg, Call: call}
+	}
+	prop := func(key string, value ast.Expression) *ast.Property {
+		return &ast.Property{Key: &ast.Identifier{Name: key}, Value: value}
+	}
+
+	expr := pipe(nil, "from", prop("bucket", stringLiteral(spec.Bucket)))
+	expr = pipe(expr, "range",
+		prop("start", &ast.DateTimeLiteral{Value: bounds.Start.Time().UTC()}),
+		prop("stop", &ast.DateTimeLiteral{Value: bounds.Stop.Time().UTC()}),
+	)
+	if spec.FilterSet {
+		body, ok := predicateToAST(rowParam, spec.Filter.Block.Body.(semantic.Expression))
+		if !ok {
+			// The rule only pushes down predicates that can be converted.
+			return "", &flux.Error{
+				Code: codes.Internal,
+				Msg:  "remote filter cannot be written as Flux",
+			}
+		}
+		expr = pipe(expr, "filter", prop("fn", &ast.FunctionExpression{
+			Params: []*ast.Property{{Key: &ast.Identifier{Name: rowParam}}},
+			Body:   body,
+		}))
+	}
+	return ast.Format(&ast.ExpressionStatement{Expression: expr}), nil
+}
@@ -0,0 +1,182 @@
+package remote_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/remote"
+)
+
+func TestPushDownRemoteRules(t *testing.T) {
+	var (
+		bounds = flux.Bounds{
+			Start: flux.Time{Absolute: time.Unix(0, 5).UTC()},
+			Stop:  flux.Time{Absolute: time.Unix(0, 10).UTC()},
+		}
+
+		rules = []plan.Rule{
+			remote.PushDownRemoteRangeRule{},
+			remote.PushDownRemoteFilterRule{},
+		}
+
+		memberEq = func(param, property, value string) semantic.Expression {
+			return &semantic.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left: &semantic.MemberExpression{
+					Object:   &semantic.IdentifierExpression{Name: param},
+					Property: property,
+				},
+				Right: &semantic.StringLiteral{Value: value},
+			}
+		}
+
+		// scopedExpr compares the value with a variable of the local scope.
+		scopedExpr = &semantic.BinaryExpression{
+			Operator: ast.GreaterThanOperator,
+			Left: &semantic.MemberExpression{
+				Object:   &semantic.IdentifierExpression{Name: "r"},
+				Property: "_value",
+			},
+			Right: &semantic.IdentifierExpression{Name: "threshold"},
+		}
+	)
+
+	makeFilterFn := func(param string, exprs ...semantic.Expression) *semantic.FunctionExpression {
+		return &semantic.FunctionExpression{
+			Block: &semantic.FunctionBlock{
+				Parameters: &semantic.FunctionParameters{
+					List: []*semantic.FunctionParameter{
+						{Key: &semantic.Identifier{Name: param}},
+					},
+				},
+				Body: semantic.ExprsToConjunction(exprs...),
+			},
+		}
+	}
+	makeFilterSpec := func(param string, exprs ...semantic.Expression) *universe.FilterProcedureSpec {
+		return &universe.FilterProcedureSpec{
+			Fn: interpreter.ResolvedFunction{Fn: makeFilterFn(param, exprs...)},
+		}
+	}
+	from := func() *remote.FromRemoteProcedureSpec {
+		return &remote.FromRemoteProcedureSpec{Remote: "edge", Bucket: "telegraf"}
+	}
+	ranged := func() *remote.FromRemoteProcedureSpec {
+		spec := from()
+		spec.BoundsSet = true
+		spec.Bounds = bounds
+		return spec
+	}
+	filtered := func(exprs ...semantic.Expression) *remote.FromRemoteProcedureSpec {
+		spec := ranged()
+		spec.FilterSet = true
+		spec.Filter = makeFilterFn("r", exprs...)
+		return spec
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name:  "range",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("fromRemote", from()),
+					plan.CreatePhysicalNode("range", &universe.RangeProcedureSpec{
+						Bounds:      bounds,
+						TimeColumn:  "_time",
+						StartColumn: "_start",
+						StopColumn:  "_stop",
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRemoteRange", ranged()),
+				},
+			},
+		},
+		{
+			Name:  "range on other columns",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("fromRemote", from()),
+					plan.CreatePhysicalNode("range", &universe.RangeProcedureSpec{
+						Bounds:      bounds,
+						TimeColumn:  "timestamp",
+						StartColumn: "_start",
+						StopColumn:  "_stop",
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "filters",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRemoteRange", ranged()),
+					plan.CreatePhysicalNode("filter1", makeFilterSpec("r", memberEq("r", "_measurement", "cpu"))),
+					plan.CreatePhysicalNode("filter2", makeFilterSpec("row", memberEq("row", "host", "a"))),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_ReadRemoteRange_filter1_filter2", filtered(
+						memberEq("r", "_measurement", "cpu"),
+						memberEq("r", "host", "a"),
+					)),
+				},
+			},
+		},
+		{
+			Name:  "partially pushable filter",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRemoteRange", ranged()),
+					plan.CreatePhysicalNode("filter", makeFilterSpec("r", memberEq("r", "_measurement", "cpu"), scopedExpr)),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRemoteRange", filtered(memberEq("r", "_measurement", "cpu"))),
+					plan.CreatePhysicalNode("filter", makeFilterSpec("r", scopedExpr)),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name:  "filter before range",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("fromRemote", from()),
+					plan.CreatePhysicalNode("filter", makeFilterSpec("r", memberEq("r", "_measurement", "cpu"))),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package sketch
+
+import (
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+func init() {
+	plan.RegisterPhysicalRules(
+		PushDownApproxQuantileRule{},
+		PushDownApproxDistinctRule{},
+	)
+}
+
+// PushDownApproxQuantileRule matches 'ReadRange |> approxQuantile()' and
+// reads the quantile of each series from storage.
+type PushDownApproxQuantileRule struct{}
+
+func (rule PushDownApproxQuantileRule) Name() string {
+	return "PushDownApproxQuantileRule"
+}
+
+func (rule PushDownApproxQuantileRule) Pattern() plan.Pattern {
+	return plan.Pat(ApproxQuantileKind, plan.Pat(influxdb.ReadRangePhysKind))
+}
+
+func (rule PushDownApproxQuantileRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	src := node.Predecessors()[0].ProcedureSpec().(*influxdb.ReadRangePhysSpec)
+	spec := node.ProcedureSpec().(*ApproxQuantileProcedureSpec)
+
+	// Storage only keeps sketches of the value column.
+	if !isValueColumn(spec.AggregateConfig) {
+		return node, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadSketch", &influxdb.ReadSketchPhysSpec{
+		ReadRangePhysSpec: *src.Copy().(*influxdb.ReadRangePhysSpec),
+		Method:            influxdb.SketchMethodQuantile,
+		Quantile:          spec.Quantile,
+	}), true, nil
+}
+
+// PushDownApproxDistinctRule matches 'ReadRange |> approxDistinct()' and
+// reads the number of distinct values of each series from storage.
+type PushDownApproxDistinctRule struct{}
+
+func (rule PushDownApproxDistinctRule) Name() string {
+	return "PushDownApproxDistinctRule"
+}
+
+func (rule PushDownApproxDistinctRule) Pattern() plan.Pattern {
+	return plan.Pat(ApproxDistinctKind, plan.Pat(influxdb.ReadRangePhysKind))
+}
+
+func (rule PushDownApproxDistinctRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	src := node.Predecessors()[0].ProcedureSpec().(*influxdb.ReadRangePhysSpec)
+	spec := node.ProcedureSpec().(*ApproxDistinctProcedureSpec)
+
+	// Storage only keeps sketches of the value column.
+	if !isValueColumn(spec.AggregateConfig) {
+		return node, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadSketch", &influxdb.ReadSketchPhysSpec{
+		ReadRangePhysSpec: *src.Copy().(*influxdb.ReadRangePhysSpec),
+		Method:            influxdb.SketchMethodDistinct,
+	}), true, nil
+}
+
+func isValueColumn(config execute.AggregateConfig) bool {
+	return len(config.Columns) == 1 && config.Columns[0] == execute.DefaultValueColLabel
+}
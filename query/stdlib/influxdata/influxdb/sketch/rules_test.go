@@ -0,0 +1,111 @@
+package sketch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/sketch"
+)
+
+func TestPushDownSketchRules(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: flux.Time{Absolute: time.Unix(0, 5).UTC()},
+			Stop:  flux.Time{Absolute: time.Unix(0, 10).UTC()},
+		},
+	}
+	quantileSpec := func(column string) *sketch.ApproxQuantileProcedureSpec {
+		return &sketch.ApproxQuantileProcedureSpec{
+			AggregateConfig: execute.AggregateConfig{Columns: []string{column}},
+			Quantile:        0.99,
+		}
+	}
+	rules := []plan.Rule{
+		sketch.PushDownApproxQuantileRule{},
+		sketch.PushDownApproxDistinctRule{},
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name:  "quantile",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("approxQuantile", quantileSpec("_value")),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadSketch", &influxdb.ReadSketchPhysSpec{
+						ReadRangePhysSpec: readRange,
+						Method:            influxdb.SketchMethodQuantile,
+						Quantile:          0.99,
+					}),
+				},
+			},
+		},
+		{
+			Name:  "distinct",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("approxDistinct", &sketch.ApproxDistinctProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadSketch", &influxdb.ReadSketchPhysSpec{
+						ReadRangePhysSpec: readRange,
+						Method:            influxdb.SketchMethodDistinct,
+					}),
+				},
+			},
+		},
+		{
+			Name:  "other column",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("approxQuantile", quantileSpec("host")),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "other predecessor",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+					plan.CreatePhysicalNode("approxQuantile", quantileSpec("_value")),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
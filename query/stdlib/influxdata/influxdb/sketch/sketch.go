@@ -0,0 +1,297 @@
+// Package sketch provides Flux functions that estimate quantiles and the
+// number of distinct values of a column. When they directly follow a
+// storage read, the estimates are computed by storage from the sketches it
+// keeps of each TSM block, instead of reading every value.
+package sketch
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	PackagePath = "influxdata/influxdb/sketch"
+
+	ApproxQuantileKind = "approxQuantile"
+	ApproxDistinctKind = "approxDistinct"
+)
+
+// source declares the builtin values of the package.
+const source = `package sketch
+
+builtin approxQuantile
+builtin approxDistinct
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "sketch.flux"
+	flux.RegisterPackage(pkg)
+
+	approxQuantileSignature := execute.AggregateSignature(map[string]semantic.PolyType{
+		"q": semantic.Float,
+	}, []string{"q"})
+	flux.RegisterPackageValue(PackagePath, ApproxQuantileKind, flux.FunctionValue(ApproxQuantileKind, createApproxQuantileOpSpec, approxQuantileSignature))
+	flux.RegisterOpSpec(ApproxQuantileKind, newApproxQuantileOp)
+	plan.RegisterProcedureSpec(ApproxQuantileKind, newApproxQuantileProcedure, ApproxQuantileKind)
+	execute.RegisterTransformation(ApproxQuantileKind, createApproxQuantileTransformation)
+
+	approxDistinctSignature := execute.AggregateSignature(nil, nil)
+	flux.RegisterPackageValue(PackagePath, ApproxDistinctKind, flux.FunctionValue(ApproxDistinctKind, createApproxDistinctOpSpec, approxDistinctSignature))
+	flux.RegisterOpSpec(ApproxDistinctKind, newApproxDistinctOp)
+	plan.RegisterProcedureSpec(ApproxDistinctKind, newApproxDistinctProcedure, ApproxDistinctKind)
+	execute.RegisterTransformation(ApproxDistinctKind, createApproxDistinctTransformation)
+}
+
+type ApproxQuantileOpSpec struct {
+	execute.AggregateConfig
+	Quantile float64 `json:"quantile"`
+}
+
+func createApproxQuantileOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(ApproxQuantileOpSpec)
+	q, err := args.GetRequiredFloat("q")
+	if err != nil {
+		return nil, err
+	} else if q < 0 || q > 1 {
+		return nil, fmt.Errorf("quantile must be between 0 and 1, got %v", q)
+	}
+	spec.Quantile = q
+
+	if err := spec.AggregateConfig.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func newApproxQuantileOp() flux.OperationSpec {
+	return new(ApproxQuantileOpSpec)
+}
+
+func (s *ApproxQuantileOpSpec) Kind() flux.OperationKind {
+	return ApproxQuantileKind
+}
+
+type ApproxQuantileProcedureSpec struct {
+	execute.AggregateConfig
+	Quantile float64
+}
+
+func newApproxQuantileProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ApproxQuantileOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &ApproxQuantileProcedureSpec{
+		AggregateConfig: spec.AggregateConfig,
+		Quantile:        spec.Quantile,
+	}, nil
+}
+
+func (s *ApproxQuantileProcedureSpec) Kind() plan.ProcedureKind {
+	return ApproxQuantileKind
+}
+
+func (s *ApproxQuantileProcedureSpec) Copy() plan.ProcedureSpec {
+	return &ApproxQuantileProcedureSpec{
+		AggregateConfig: s.AggregateConfig.Copy(),
+		Quantile:        s.Quantile,
+	}
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec
+func (s *ApproxQuantileProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createApproxQuantileTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ApproxQuantileProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+
+	agg := &SketchAgg{quantile: s.Quantile}
+	t, d := execute.NewAggregateTransformationAndDataset(id, mode, agg, s.AggregateConfig, a.Allocator())
+	return t, d, nil
+}
+
+type ApproxDistinctOpSpec struct {
+	execute.AggregateConfig
+}
+
+func createApproxDistinctOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(ApproxDistinctOpSpec)
+	if err := spec.AggregateConfig.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func newApproxDistinctOp() flux.OperationSpec {
+	return new(ApproxDistinctOpSpec)
+}
+
+func (s *ApproxDistinctOpSpec) Kind() flux.OperationKind {
+	return ApproxDistinctKind
+}
+
+type ApproxDistinctProcedureSpec struct {
+	execute.AggregateConfig
+}
+
+func newApproxDistinctProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ApproxDistinctOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &ApproxDistinctProcedureSpec{
+		AggregateConfig: spec.AggregateConfig,
+	}, nil
+}
+
+func (s *ApproxDistinctProcedureSpec) Kind() plan.ProcedureKind {
+	return ApproxDistinctKind
+}
+
+func (s *ApproxDistinctProcedureSpec) Copy() plan.ProcedureSpec {
+	return &ApproxDistinctProcedureSpec{
+		AggregateConfig: s.AggregateConfig.Copy(),
+	}
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec
+func (s *ApproxDistinctProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createApproxDistinctTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ApproxDistinctProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+
+	agg := &SketchAgg{distinct: true}
+	t, d := execute.NewAggregateTransformationAndDataset(id, mode, agg, s.AggregateConfig, a.Allocator())
+	return t, d, nil
+}
+
+// SketchAgg estimates a quantile or the number of distinct values of the
+// values of a column. It is used when the estimate cannot be read from
+// storage, and sketches the values the same way storage does.
+type SketchAgg struct {
+	distinct bool
+	quantile float64
+	sketch   *cursors.Sketch
+}
+
+func (a *SketchAgg) new(numeric bool) *SketchAgg {
+	return &SketchAgg{
+		distinct: a.distinct,
+		quantile: a.quantile,
+		sketch:   cursors.NewSketch(numeric),
+	}
+}
+
+func (a *SketchAgg) NewBoolAgg() execute.DoBoolAgg {
+	if !a.distinct {
+		return nil
+	}
+	return a.new(false)
+}
+
+func (a *SketchAgg) NewIntAgg() execute.DoIntAgg {
+	return a.new(true)
+}
+
+func (a *SketchAgg) NewUIntAgg() execute.DoUIntAgg {
+	return a.new(true)
+}
+
+func (a *SketchAgg) NewFloatAgg() execute.DoFloatAgg {
+	return a.new(true)
+}
+
+func (a *SketchAgg) NewStringAgg() execute.DoStringAgg {
+	if !a.distinct {
+		return nil
+	}
+	return a.new(false)
+}
+
+func (a *SketchAgg) DoBool(vs *array.Boolean) {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			a.sketch.AddBoolean(vs.Value(i))
+		}
+	}
+}
+
+func (a *SketchAgg) DoInt(vs *array.Int64) {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			a.sketch.AddInteger(vs.Value(i))
+		}
+	}
+}
+
+func (a *SketchAgg) DoUInt(vs *array.Uint64) {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			a.sketch.AddUnsigned(vs.Value(i))
+		}
+	}
+}
+
+func (a *SketchAgg) DoFloat(vs *array.Float64) {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			a.sketch.AddFloat(vs.Value(i))
+		}
+	}
+}
+
+func (a *SketchAgg) DoString(vs *array.Binary) {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			a.sketch.AddString(vs.ValueString(i))
+		}
+	}
+}
+
+func (a *SketchAgg) Type() flux.ColType {
+	if a.distinct {
+		return flux.TInt
+	}
+	return flux.TFloat
+}
+
+func (a *SketchAgg) ValueFloat() float64 {
+	return a.sketch.Digest.Quantile(a.quantile)
+}
+
+func (a *SketchAgg) ValueInt() int64 {
+	return int64(a.sketch.Distinct.Count())
+}
+
+func (a *SketchAgg) IsNull() bool {
+	if a.distinct {
+		return false
+	}
+	return a.sketch.Digest.Count() == 0
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/query"
@@ -22,6 +23,7 @@ func init() {
 	execute.RegisterSource(ReadGroupPhysKind, createReadGroupSource)
 	execute.RegisterSource(ReadTagKeysPhysKind, createReadTagKeysSource)
 	execute.RegisterSource(ReadTagValuesPhysKind, createReadTagValuesSource)
+	execute.RegisterSource(ReadSketchPhysKind, createReadSketchSource)
 }
 
 type runner interface {
@@ -255,19 +257,104 @@ func createReadGroupSource(s plan.ProcedureSpec, id execute.DatasetID, a execute
 	if spec.FilterSet {
 		filter = spec.Filter
 	}
-	return ReadGroupSource(
+	readSpec := ReadGroupSpec{
+		ReadFilterSpec: ReadFilterSpec{
+			OrganizationID: orgID,
+			BucketID:       bucketID,
+			Bounds:         *bounds,
+			Predicate:      filter,
+		},
+		GroupMode:       ToGroupMode(spec.GroupMode),
+		GroupKeys:       spec.GroupKeys,
+		AggregateMethod: spec.AggregateMethod,
+	}
+
+	if useStreamingGroup(ctx, deps.Statistics, bucketID, spec) {
+		groupSpec := &universe.GroupProcedureSpec{
+			GroupMode: spec.GroupMode,
+			GroupKeys: spec.GroupKeys,
+		}
+		return newStreamingGroupSource(id, deps.Reader, readSpec, groupSpec, a), nil
+	}
+	return ReadGroupSource(id, deps.Reader, readSpec, a), nil
+}
+
+type readSketchSource struct {
+	Source
+	reader   Reader
+	readSpec ReadSketchSpec
+}
+
+func ReadSketchSource(id execute.DatasetID, r Reader, readSpec ReadSketchSpec, a execute.Administration) execute.Source {
+	src := new(readSketchSource)
+
+	src.id = id
+	src.alloc = a.Allocator()
+
+	src.reader = r
+	src.readSpec = readSpec
+
+	src.m = GetStorageDependencies(a.Context()).FromDeps.Metrics
+	src.orgID = readSpec.OrganizationID
+	src.op = "readSketch"
+
+	src.runner = src
+	return src
+}
+
+func (s *readSketchSource) run(ctx context.Context) error {
+	stop := s.readSpec.Bounds.Stop
+	tables, err := s.reader.ReadSketch(
+		ctx,
+		s.readSpec,
+		s.alloc,
+	)
+	if err != nil {
+		return err
+	}
+	return s.processTables(ctx, tables, stop)
+}
+
+func createReadSketchSource(s plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	span, ctx := tracing.StartSpanFromContext(a.Context())
+	defer span.Finish()
+
+	spec := s.(*ReadSketchPhysSpec)
+
+	bounds := a.StreamContext().Bounds()
+	if bounds == nil {
+		return nil, errors.New("nil bounds passed to from")
+	}
+
+	deps := GetStorageDependencies(a.Context()).FromDeps
+
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, errors.New("missing request on context")
+	}
+
+	orgID := req.OrganizationID
+	bucketID, err := spec.LookupBucketID(ctx, orgID, deps.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *semantic.FunctionExpression
+	if spec.FilterSet {
+		filter = spec.Filter
+	}
+	return ReadSketchSource(
 		id,
 		deps.Reader,
-		ReadGroupSpec{
+		ReadSketchSpec{
 			ReadFilterSpec: ReadFilterSpec{
 				OrganizationID: orgID,
 				BucketID:       bucketID,
 				Bounds:         *bounds,
 				Predicate:      filter,
 			},
-			GroupMode:       ToGroupMode(spec.GroupMode),
-			GroupKeys:       spec.GroupKeys,
-			AggregateMethod: spec.AggregateMethod,
+			Method:   spec.Method,
+			Quantile: spec.Quantile,
 		},
 		a,
 	), nil
@@ -40,6 +40,10 @@ func (mockReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec, al
 	return &mockTableIterator{}, nil
 }
 
+func (mockReader) ReadSketch(ctx context.Context, spec influxdb.ReadSketchSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &mockTableIterator{}, nil
+}
+
 func (mockReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	return &mockTableIterator{}, nil
 }
@@ -0,0 +1,158 @@
+package influxdb
+
+import (
+	"context"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb"
+)
+
+// The thresholds at which a group operation reads the series of a bucket
+// from storage and groups them as they stream in, rather than pushing the
+// group down to storage. Storage sorts the keys of all series matching the
+// predicate by the group key and reads the first block of each of them
+// before returning any data, which dominates the cost of the read when
+// there are many series with little data each.
+var (
+	// StreamingGroupMinSeries is the number of series matching the
+	// predicate above which a group may be streamed.
+	StreamingGroupMinSeries int64 = 10000
+
+	// StreamingGroupMaxBlocksPerSeries is the average number of TSM blocks
+	// of the series matching the predicate below which a group may be streamed.
+	StreamingGroupMaxBlocksPerSeries = 2.0
+)
+
+// useStreamingGroup reports whether the statistics of the bucket favor
+// streaming the group of the spec. It returns false if there are no
+// statistics for the bucket.
+func useStreamingGroup(ctx context.Context, lookup BucketStatisticsLookup, bucketID platform.ID, spec *ReadGroupPhysSpec) bool {
+	// Storage aggregates are always cheaper than reading every point.
+	if lookup == nil || spec.AggregateMethod != "" || spec.GroupMode != flux.GroupModeBy {
+		return false
+	}
+
+	stats, err := lookup.FindBucketStatistics(ctx, bucketID)
+	if err != nil || len(stats.Measurements) == 0 {
+		return false
+	}
+
+	var predicate *semantic.FunctionExpression
+	if spec.FilterSet {
+		predicate = spec.Filter
+	}
+	est := estimateStatistics(stats, predicate)
+	return est.Series >= StreamingGroupMinSeries && est.BlocksPerSeries() < StreamingGroupMaxBlocksPerSeries
+}
+
+// estimateStatistics returns the sum of the statistics of the measurements
+// the predicate may match, or of all measurements if the predicate does not
+// restrict the measurement.
+func estimateStatistics(stats *platform.BucketStatistics, predicate *semantic.FunctionExpression) platform.MeasurementStatistics {
+	var names []string
+	if predicate != nil && predicate.Block.Parameters != nil && len(predicate.Block.Parameters.List) == 1 {
+		if body, ok := predicate.Block.Body.(semantic.Expression); ok {
+			names, _ = predicateMeasurements(body, predicate.Block.Parameters.List[0].Key.Name)
+		}
+	}
+
+	var est platform.MeasurementStatistics
+	add := func(s platform.MeasurementStatistics) {
+		est.Series += s.Series
+		est.Blocks += s.Blocks
+	}
+	if names == nil {
+		for _, s := range stats.Measurements {
+			add(s)
+		}
+		return est
+	}
+	for _, name := range names {
+		add(stats.Measurements[name])
+	}
+	return est
+}
+
+// predicateMeasurements returns the measurements the predicate expression
+// may match, and false if it does not restrict the measurement.
+func predicateMeasurements(n semantic.Expression, objectName string) ([]string, bool) {
+	switch n := n.(type) {
+	case *semantic.LogicalExpression:
+		left, lok := predicateMeasurements(n.Left, objectName)
+		right, rok := predicateMeasurements(n.Right, objectName)
+		switch n.Operator {
+		case ast.AndOperator:
+			if lok && (!rok || len(left) <= len(right)) {
+				return left, true
+			}
+			return right, rok
+		case ast.OrOperator:
+			if lok && rok {
+				return append(left, right...), true
+			}
+		}
+	case *semantic.BinaryExpression:
+		if n.Operator != ast.EqualOperator {
+			break
+		}
+		if name, ok := measurementEquals(n.Left, n.Right, objectName); ok {
+			return []string{name}, true
+		}
+		if name, ok := measurementEquals(n.Right, n.Left, objectName); ok {
+			return []string{name}, true
+		}
+	}
+	return nil, false
+}
+
+func measurementEquals(ref, value semantic.Expression, objectName string) (string, bool) {
+	m, ok := ref.(*semantic.MemberExpression)
+	if !ok || m.Property != DefaultMeasurementColLabel {
+		return "", false
+	}
+	if ident, ok := m.Object.(*semantic.IdentifierExpression); !ok || ident.Name != objectName {
+		return "", false
+	}
+	lit, ok := value.(*semantic.StringLiteral)
+	if !ok {
+		return "", false
+	}
+	return lit.Value, true
+}
+
+// streamingGroupSource reads the series of a bucket with a read filter and
+// groups the tables in the query rather than in storage.
+type streamingGroupSource struct {
+	src execute.Source
+	d   execute.Dataset
+}
+
+func newStreamingGroupSource(id execute.DatasetID, r Reader, readSpec ReadGroupSpec, groupSpec *universe.GroupProcedureSpec, a execute.Administration) *streamingGroupSource {
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, execute.DiscardingMode, cache)
+	d.SetTriggerSpec(plan.DefaultTriggerSpec)
+
+	src := ReadFilterSource(id, r, readSpec.ReadFilterSpec, a)
+	src.AddTransformation(universe.NewGroupTransformation(d, cache, groupSpec))
+	return &streamingGroupSource{src: src, d: d}
+}
+
+func (s *streamingGroupSource) AddTransformation(t execute.Transformation) {
+	s.d.AddTransformation(t)
+}
+
+func (s *streamingGroupSource) Run(ctx context.Context) {
+	s.src.Run(ctx)
+}
+
+func (s *streamingGroupSource) Metadata() flux.Metadata {
+	if m, ok := s.src.(execute.MetadataNode); ok {
+		return m.Metadata()
+	}
+	return nil
+}
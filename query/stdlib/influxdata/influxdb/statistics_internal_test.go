@@ -0,0 +1,180 @@
+package influxdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/semantic"
+	platform "github.com/influxdata/influxdb"
+)
+
+type statisticsLookup map[platform.ID]*platform.BucketStatistics
+
+func (l statisticsLookup) FindBucketStatistics(_ context.Context, bucketID platform.ID) (*platform.BucketStatistics, error) {
+	stats, ok := l[bucketID]
+	if !ok {
+		return nil, &platform.Error{Code: platform.ENotFound}
+	}
+	return stats, nil
+}
+
+func measurementEqual(name string) semantic.Expression {
+	return &semantic.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &semantic.MemberExpression{
+			Object:   &semantic.IdentifierExpression{Name: "r"},
+			Property: "_measurement",
+		},
+		Right: &semantic.StringLiteral{Value: name},
+	}
+}
+
+func makePredicate(body semantic.Expression) *semantic.FunctionExpression {
+	return &semantic.FunctionExpression{
+		Block: &semantic.FunctionBlock{
+			Parameters: &semantic.FunctionParameters{
+				List: []*semantic.FunctionParameter{
+					{Key: &semantic.Identifier{Name: "r"}},
+				},
+			},
+			Body: body,
+		},
+	}
+}
+
+func TestUseStreamingGroup(t *testing.T) {
+	lookup := statisticsLookup{
+		1: {
+			BucketID: 1,
+			Measurements: map[string]platform.MeasurementStatistics{
+				// Many sparse series.
+				"sparse": {Series: 20000, Blocks: 20000},
+				// Many dense series.
+				"dense": {Series: 20000, Blocks: 200000},
+				// Few sparse series.
+				"small": {Series: 100, Blocks: 100},
+			},
+		},
+	}
+
+	hostEqual := &semantic.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &semantic.MemberExpression{
+			Object:   &semantic.IdentifierExpression{Name: "r"},
+			Property: "host",
+		},
+		Right: &semantic.StringLiteral{Value: "a"},
+	}
+
+	tests := []struct {
+		name      string
+		lookup    BucketStatisticsLookup
+		bucketID  platform.ID
+		predicate semantic.Expression
+		aggregate string
+		mode      flux.GroupMode
+		exp       bool
+	}{
+		{
+			name:      "sparse measurement",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("sparse"),
+			exp:       true,
+		},
+		{
+			name:      "dense measurement",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("dense"),
+		},
+		{
+			name:      "small measurement",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("small"),
+		},
+		{
+			name:      "unknown measurement",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("unknown"),
+		},
+		{
+			name:     "sparse measurement and tag",
+			lookup:   lookup,
+			bucketID: 1,
+			predicate: &semantic.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     hostEqual,
+				Right:    measurementEqual("sparse"),
+			},
+			exp: true,
+		},
+		{
+			name:     "sparse or small measurement",
+			lookup:   lookup,
+			bucketID: 1,
+			predicate: &semantic.LogicalExpression{
+				Operator: ast.OrOperator,
+				Left:     measurementEqual("sparse"),
+				Right:    measurementEqual("small"),
+			},
+			exp: true,
+		},
+		{
+			name:      "all measurements",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: hostEqual,
+		},
+		{
+			name:      "aggregate",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("sparse"),
+			aggregate: "count",
+		},
+		{
+			name:      "group except",
+			lookup:    lookup,
+			bucketID:  1,
+			predicate: measurementEqual("sparse"),
+			mode:      flux.GroupModeExcept,
+		},
+		{
+			name:      "no statistics",
+			lookup:    lookup,
+			bucketID:  2,
+			predicate: measurementEqual("sparse"),
+		},
+		{
+			name:      "no lookup",
+			bucketID:  1,
+			predicate: measurementEqual("sparse"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &ReadGroupPhysSpec{
+				GroupMode:       flux.GroupModeBy,
+				GroupKeys:       []string{"host"},
+				AggregateMethod: tt.aggregate,
+			}
+			if tt.mode != flux.GroupModeNone {
+				spec.GroupMode = tt.mode
+			}
+			if tt.predicate != nil {
+				spec.FilterSet = true
+				spec.Filter = makePredicate(tt.predicate)
+			}
+
+			if got := useStreamingGroup(context.Background(), tt.lookup, tt.bucketID, spec); got != tt.exp {
+				t.Fatalf("got %v, expected %v", got, tt.exp)
+			}
+		})
+	}
+}
@@ -30,11 +30,19 @@ type OrganizationLookup interface {
 	LookupName(ctx context.Context, id platform.ID) string
 }
 
+type BucketStatisticsLookup interface {
+	FindBucketStatistics(ctx context.Context, bucketID platform.ID) (*platform.BucketStatistics, error)
+}
+
 type FromDependencies struct {
 	Reader             Reader
 	BucketLookup       BucketLookup
 	OrganizationLookup OrganizationLookup
 	Metrics            *metrics
+
+	// Statistics is optional. Without it, group operations are always
+	// pushed down to storage.
+	Statistics BucketStatisticsLookup
 }
 
 func (d FromDependencies) Validate() error {
@@ -128,9 +136,26 @@ type ReadTagValuesSpec struct {
 	TagKey string
 }
 
+const (
+	// SketchMethodQuantile estimates a quantile of the values of each series.
+	SketchMethodQuantile = "quantile"
+	// SketchMethodDistinct estimates the number of distinct values of each series.
+	SketchMethodDistinct = "distinct"
+)
+
+// ReadSketchSpec reads a single estimate for each series from the sketches
+// that storage keeps of the series values.
+type ReadSketchSpec struct {
+	ReadFilterSpec
+
+	Method   string
+	Quantile float64
+}
+
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadSketch(ctx context.Context, spec ReadSketchSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
@@ -278,6 +278,9 @@ type ToTransformation struct {
 	implicitTagColumns bool
 	deps               ToDependencies
 	buf                *storage.BufferedPointsWriter
+	// tagConstraints are the tag constraints of the authorization of the
+	// query, which all written points must satisfy.
+	tagConstraints []platform.Tag
 }
 
 // RetractTable retracts the table for the transformation for the `to` flux function.
@@ -342,6 +345,15 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 			Msg:  "You must specify org and bucket",
 		}
 	}
+	// The written values that conflict with the types of existing fields are
+	// handled by the policy of the bucket, as they are on the write endpoints.
+	if l, ok := deps.BucketLookup.(FieldTypeConflictPolicyLookup); ok {
+		ctx = storage.WithFieldTypeConflictPolicy(ctx, l.LookupFieldTypeConflictPolicy(ctx, *orgID, *bucketID))
+	}
+	var tagConstraints []platform.Tag
+	if req := query.RequestFromContext(ctx); req != nil && req.Authorization != nil {
+		tagConstraints = req.Authorization.TagConstraints
+	}
 	return &ToTransformation{
 		Ctx:                ctx,
 		OrgID:              *orgID,
@@ -353,6 +365,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		implicitTagColumns: spec.TagColumns == nil,
 		deps:               deps,
 		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, deps.PointsWriter),
+		tagConstraints:     tagConstraints,
 	}, nil
 }
 
@@ -462,6 +475,12 @@ func (t *ToTransformation) Finish(id execute.DatasetID, err error) {
 	t.d.Finish(err)
 }
 
+// FieldTypeConflictPolicyLookup is implemented by the bucket lookups that
+// can look up the field type conflict policy of a bucket.
+type FieldTypeConflictPolicyLookup interface {
+	LookupFieldTypeConflictPolicy(ctx context.Context, orgID platform.ID, id platform.ID) platform.FieldTypeConflictPolicy
+}
+
 // ToDependencies contains the dependencies for executing the `to` function.
 type ToDependencies struct {
 	BucketLookup       BucketLookup
@@ -670,6 +689,9 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			}
 		}
 
+		if err := storage.CheckTagConstraints(t.tagConstraints, points); err != nil {
+			return err
+		}
 		return t.buf.WritePoints(ctx, points)
 	})
 }
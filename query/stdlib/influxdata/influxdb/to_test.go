@@ -21,6 +21,7 @@ import (
 	_ "github.com/influxdata/influxdb/query/builtin"
 	pquerytest "github.com/influxdata/influxdb/query/querytest"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
 )
 
@@ -827,6 +828,38 @@ m,tag1=c,tag2=ee _value=4 41`),
 	}
 }
 
+// policyBucketLookup looks up the buckets of mock.BucketLookup with a field
+// type conflict policy.
+type policyBucketLookup struct {
+	mock.BucketLookup
+	policy platform.FieldTypeConflictPolicy
+}
+
+func (l policyBucketLookup) LookupFieldTypeConflictPolicy(_ context.Context, orgID platform.ID, id platform.ID) platform.FieldTypeConflictPolicy {
+	return l.policy
+}
+
+func TestToTransformation_FieldTypeConflictPolicy(t *testing.T) {
+	deps := mockDependencies()
+	deps.BucketLookup = policyBucketLookup{policy: platform.FieldTypeConflictCoerce}
+
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+		},
+	}
+	tr, err := influxdb.NewToTransformation(context.Background(), nil, nil, spec, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := storage.FieldTypeConflictPolicyFromContext(tr.Ctx); got != platform.FieldTypeConflictCoerce {
+		t.Errorf("unexpected field type conflict policy: got %q want %q", got, platform.FieldTypeConflictCoerce)
+	}
+}
+
 func mockDependencies() influxdb.ToDependencies {
 	return influxdb.ToDependencies{
 		BucketLookup:       mock.BucketLookup{},
@@ -0,0 +1,15 @@
+package query
+
+import "time"
+
+// WindowStart returns the start of the window of the duration every that
+// contains t. Flux windows without an offset are aligned to the Unix epoch,
+// so the start is t rounded down to a multiple of every since the epoch.
+func WindowStart(t time.Time, every time.Duration) time.Time {
+	ns := t.UnixNano()
+	r := ns % int64(every)
+	if r < 0 {
+		r += int64(every)
+	}
+	return time.Unix(0, ns-r).UTC()
+}
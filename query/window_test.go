@@ -0,0 +1,23 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestWindowStart(t *testing.T) {
+	for _, tt := range []struct {
+		t, want time.Time
+		every   time.Duration
+	}{
+		{t: time.Date(2019, 12, 1, 10, 7, 30, 0, time.UTC), every: 5 * time.Minute, want: time.Date(2019, 12, 1, 10, 5, 0, 0, time.UTC)},
+		{t: time.Date(2019, 12, 1, 10, 7, 30, 0, time.UTC), every: 7 * 24 * time.Hour, want: time.Date(2019, 11, 28, 0, 0, 0, 0, time.UTC)},
+		{t: time.Unix(-90, 0), every: time.Minute, want: time.Unix(-120, 0)},
+	} {
+		if got := query.WindowStart(tt.t, tt.every); !got.Equal(tt.want) {
+			t.Errorf("WindowStart(%s, %s) = %s, want %s", tt.t, tt.every, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// ops for query template errors and op logs.
+const (
+	OpFindQueryTemplateByID = "FindQueryTemplateByID"
+	OpFindQueryTemplates    = "FindQueryTemplates"
+	OpCreateQueryTemplate   = "CreateQueryTemplate"
+	OpUpdateQueryTemplate   = "UpdateQueryTemplate"
+	OpDeleteQueryTemplate   = "DeleteQueryTemplate"
+	OpRenderQueryTemplate   = "RenderQueryTemplate"
+)
+
+// QueryTemplateService represents a service for managing the query templates
+// of organizations.
+type QueryTemplateService interface {
+	// FindQueryTemplateByID returns a single query template by ID.
+	FindQueryTemplateByID(ctx context.Context, id ID) (*QueryTemplate, error)
+
+	// FindQueryTemplates returns the query templates that match the filter.
+	FindQueryTemplates(ctx context.Context, filter QueryTemplateFilter) ([]*QueryTemplate, error)
+
+	// CreateQueryTemplate creates a new query template and sets t.ID with
+	// the new identifier. The names of the templates of an organization are
+	// unique.
+	CreateQueryTemplate(ctx context.Context, t *QueryTemplate) error
+
+	// UpdateQueryTemplate updates a single query template with the changeset.
+	UpdateQueryTemplate(ctx context.Context, id ID, upd QueryTemplateUpdate) (*QueryTemplate, error)
+
+	// DeleteQueryTemplate removes a query template by ID.
+	DeleteQueryTemplate(ctx context.Context, id ID) error
+}
+
+// QueryTemplate is a parameterized flux query. It is rendered to a flux query
+// by assigning the values of its parameters to variables of the same names
+// ahead of its flux, e.g. to use it in the queries of dashboards and checks.
+//
+// The built-in query templates have no ID nor organization.
+type QueryTemplate struct {
+	ID             ID                   `json:"id,omitempty"`
+	OrganizationID ID                   `json:"orgID,omitempty"`
+	Name           string               `json:"name"`
+	Description    string               `json:"description,omitempty"`
+	Params         []QueryTemplateParam `json:"params"`
+	Flux           string               `json:"flux"`
+
+	CRUDLog
+}
+
+// QueryTemplateParamType is the type of the value of a query template
+// parameter.
+type QueryTemplateParamType string
+
+// the types of query template parameters.
+const (
+	QueryTemplateParamString   QueryTemplateParamType = "string"
+	QueryTemplateParamDuration QueryTemplateParamType = "duration"
+	QueryTemplateParamInteger  QueryTemplateParamType = "integer"
+	QueryTemplateParamFloat    QueryTemplateParamType = "float"
+)
+
+// zero returns the zero value of the type.
+func (t QueryTemplateParamType) zero() string {
+	switch t {
+	case QueryTemplateParamDuration:
+		return "0s"
+	case QueryTemplateParamInteger, QueryTemplateParamFloat:
+		return "0"
+	default:
+		return ""
+	}
+}
+
+// QueryTemplateParam is a parameter of a query template.
+type QueryTemplateParam struct {
+	// Name is the name of the flux variable the value is assigned to.
+	Name        string                 `json:"name"`
+	Type        QueryTemplateParamType `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	// Default is the value of the parameter if none is given. Parameters
+	// without a default are required.
+	Default string `json:"default,omitempty"`
+}
+
+var (
+	queryTemplateParamNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	fluxDurationRegexp           = regexp.MustCompile(`^-?([0-9]+(ns|us|µs|ms|s|m|h|d|w|mo|y))+$`)
+	fluxDurationPartRegexp       = regexp.MustCompile(`([0-9]+)(ns|us|µs|ms|mo|s|m|h|d|w|y)`)
+)
+
+// Valid returns an error if the query template is invalid.
+func (t *QueryTemplate) Valid() error {
+	if t.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query template requires a name",
+		}
+	}
+	if strings.TrimSpace(t.Flux) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query template requires flux",
+		}
+	}
+
+	names := make(map[string]bool, len(t.Params))
+	args := make(map[string]string, len(t.Params))
+	for _, p := range t.Params {
+		if !queryTemplateParamNameRegexp.MatchString(p.Name) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("query template parameter name %q is not a valid identifier", p.Name),
+			}
+		}
+		if names[p.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("query template parameter %q is not unique", p.Name),
+			}
+		}
+		names[p.Name] = true
+
+		// Required parameters are rendered with the zero value of their type
+		// to check the flux of the template.
+		v := p.Default
+		if v == "" {
+			v = p.Type.zero()
+		}
+		if _, err := p.literal(v); err != nil {
+			return err
+		}
+		args[p.Name] = v
+	}
+
+	_, err := t.Render(args)
+	return err
+}
+
+// Render returns the flux query of the template with the values of the
+// parameters. Parameters without a value get their default.
+func (t *QueryTemplate) Render(params map[string]string) (string, error) {
+	declared := make(map[string]bool, len(t.Params))
+	for _, p := range t.Params {
+		declared[p.Name] = true
+	}
+	for name := range params {
+		if !declared[name] {
+			return "", &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("query template %s has no parameter %q", t.Name, name),
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, p := range t.Params {
+		v, ok := params[p.Name]
+		if !ok {
+			if p.Default == "" {
+				return "", &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("query template %s requires parameter %q", t.Name, p.Name),
+				}
+			}
+			v = p.Default
+		}
+
+		lit, err := p.literal(v)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s = %s\n", p.Name, lit)
+	}
+	b.WriteString(t.Flux)
+
+	flux := b.String()
+	if pkg := parser.ParseSource(flux); ast.Check(pkg) > 0 {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("query template %s does not render to a valid flux query", t.Name),
+			Err:  ast.GetError(pkg),
+		}
+	}
+	return flux, nil
+}
+
+// literal returns the flux literal of the value of the parameter.
+func (p QueryTemplateParam) literal(v string) (string, error) {
+	invalid := func() error {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid %s %q of query template parameter %q", p.Type, v, p.Name),
+		}
+	}
+
+	switch p.Type {
+	case QueryTemplateParamString:
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`)
+		return `"` + r.Replace(v) + `"`, nil
+	case QueryTemplateParamDuration:
+		if !fluxDurationRegexp.MatchString(v) {
+			return "", invalid()
+		}
+		return v, nil
+	case QueryTemplateParamInteger:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", invalid()
+		}
+		return strconv.FormatInt(i, 10), nil
+	case QueryTemplateParamFloat:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", invalid()
+		}
+		s := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return s, nil
+	default:
+		return "", &Error{
+			Code: EInvalid,
+			Msg: fmt.Sprintf("invalid type %q of query template parameter %q; must be %s, %s, %s or %s", p.Type, p.Name,
+				QueryTemplateParamString, QueryTemplateParamDuration, QueryTemplateParamInteger, QueryTemplateParamFloat),
+		}
+	}
+}
+
+// QueryTemplateFilter represents a set of filters that restrict the returned
+// query templates.
+type QueryTemplateFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Name           *string
+}
+
+// QueryTemplateUpdate represents updates to a query template. Only fields
+// which are set are updated.
+type QueryTemplateUpdate struct {
+	Name        *string               `json:"name,omitempty"`
+	Description *string               `json:"description,omitempty"`
+	Params      *[]QueryTemplateParam `json:"params,omitempty"`
+	Flux        *string               `json:"flux,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u QueryTemplateUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Params == nil && u.Flux == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "must update at least one attribute",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the query template.
+func (u QueryTemplateUpdate) Apply(t *QueryTemplate) {
+	if u.Name != nil {
+		t.Name = *u.Name
+	}
+	if u.Description != nil {
+		t.Description = *u.Description
+	}
+	if u.Params != nil {
+		t.Params = *u.Params
+	}
+	if u.Flux != nil {
+		t.Flux = *u.Flux
+	}
+}
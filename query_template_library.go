@@ -0,0 +1,144 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// builtinQueryTemplates are the query templates of common SRE patterns that
+// every organization can use, or seed its own templates with.
+var builtinQueryTemplates = []QueryTemplate{
+	{
+		Name:        "error-rate",
+		Description: "Ratio of the requests with a 5xx status tag to all requests, by window.",
+		Params: []QueryTemplateParam{
+			{Name: "bucket", Type: QueryTemplateParamString, Description: "Bucket of the requests."},
+			{Name: "measurement", Type: QueryTemplateParamString, Default: "http_requests", Description: "Measurement of the requests, tagged with their status."},
+			{Name: "field", Type: QueryTemplateParamString, Default: "count", Description: "Field of the number of requests."},
+			{Name: "start", Type: QueryTemplateParamDuration, Default: "-1h", Description: "Start of the range, relative to now."},
+			{Name: "every", Type: QueryTemplateParamDuration, Default: "1m", Description: "Duration of the windows."},
+		},
+		Flux: `requests = from(bucket: bucket)
+  |> range(start: start)
+  |> filter(fn: (r) => r._measurement == measurement and r._field == field)
+  |> group()
+  |> map(fn: (r) => ({_time: r._time, _value: float(v: r._value), errors: if r.status =~ /^5/ then float(v: r._value) else 0.0}))
+
+total = requests
+  |> aggregateWindow(every: every, fn: sum, createEmpty: false)
+errors = requests
+  |> aggregateWindow(every: every, fn: sum, column: "errors", createEmpty: false)
+
+join(tables: {total: total, errors: errors}, on: ["_time"])
+  |> map(fn: (r) => ({_time: r._time, _value: if r._value == 0.0 then 0.0 else r.errors / r._value}))
+`,
+	},
+	{
+		Name:        "burn-rate",
+		Description: "Rate at which the error budget of an availability SLO is spent, by window: 1 spends the budget exactly over the SLO period.",
+		Params: []QueryTemplateParam{
+			{Name: "bucket", Type: QueryTemplateParamString, Description: "Bucket of the requests."},
+			{Name: "measurement", Type: QueryTemplateParamString, Default: "http_requests", Description: "Measurement of the requests, tagged with their status."},
+			{Name: "field", Type: QueryTemplateParamString, Default: "count", Description: "Field of the number of requests."},
+			{Name: "objective", Type: QueryTemplateParamFloat, Default: "0.999", Description: "Objective of the SLO, the ratio of requests without a 5xx status."},
+			{Name: "start", Type: QueryTemplateParamDuration, Default: "-1h", Description: "Start of the range, relative to now."},
+			{Name: "every", Type: QueryTemplateParamDuration, Default: "5m", Description: "Duration of the windows."},
+		},
+		Flux: `requests = from(bucket: bucket)
+  |> range(start: start)
+  |> filter(fn: (r) => r._measurement == measurement and r._field == field)
+  |> group()
+  |> map(fn: (r) => ({_time: r._time, _value: float(v: r._value), errors: if r.status =~ /^5/ then float(v: r._value) else 0.0}))
+
+total = requests
+  |> aggregateWindow(every: every, fn: sum, createEmpty: false)
+errors = requests
+  |> aggregateWindow(every: every, fn: sum, column: "errors", createEmpty: false)
+
+join(tables: {total: total, errors: errors}, on: ["_time"])
+  |> map(fn: (r) => ({_time: r._time, _value: if r._value == 0.0 then 0.0 else r.errors / r._value / (1.0 - objective)}))
+`,
+	},
+	{
+		Name:        "top-hosts",
+		Description: "The k hosts with the highest mean of a field.",
+		Params: []QueryTemplateParam{
+			{Name: "bucket", Type: QueryTemplateParamString, Description: "Bucket of the hosts."},
+			{Name: "measurement", Type: QueryTemplateParamString, Default: "cpu", Description: "Measurement of the field."},
+			{Name: "field", Type: QueryTemplateParamString, Default: "usage_user", Description: "Field to rank the hosts by."},
+			{Name: "hostTag", Type: QueryTemplateParamString, Default: "host", Description: "Tag of the host."},
+			{Name: "k", Type: QueryTemplateParamInteger, Default: "5", Description: "Number of hosts."},
+			{Name: "start", Type: QueryTemplateParamDuration, Default: "-1h", Description: "Start of the range, relative to now."},
+		},
+		Flux: `from(bucket: bucket)
+  |> range(start: start)
+  |> filter(fn: (r) => r._measurement == measurement and r._field == field)
+  |> group(columns: [hostTag])
+  |> mean()
+  |> group()
+  |> top(n: k)
+`,
+	},
+}
+
+// BuiltinQueryTemplates returns the built-in query templates.
+func BuiltinQueryTemplates() []*QueryTemplate {
+	ts := make([]*QueryTemplate, 0, len(builtinQueryTemplates))
+	for _, t := range builtinQueryTemplates {
+		t := t
+		t.Params = append([]QueryTemplateParam(nil), t.Params...)
+		ts = append(ts, &t)
+	}
+	return ts
+}
+
+// FindQueryTemplate returns the query template of the organization with the
+// name, or else the built-in query template with the name. Templates of the
+// organization thus override the built-in templates.
+func FindQueryTemplate(ctx context.Context, s QueryTemplateService, orgID ID, name string) (*QueryTemplate, error) {
+	ts, err := s.FindQueryTemplates(ctx, QueryTemplateFilter{OrganizationID: &orgID, Name: &name})
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) > 0 {
+		return ts[0], nil
+	}
+
+	for _, t := range BuiltinQueryTemplates() {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, &Error{
+		Code: ENotFound,
+		Op:   OpRenderQueryTemplate,
+		Msg:  "query template not found",
+	}
+}
+
+// SeedQueryTemplates creates the built-in query templates in the
+// organization, so that it can adapt them to its data. Built-in templates
+// whose names the organization already has a template of are skipped. It
+// returns the created templates.
+func SeedQueryTemplates(ctx context.Context, s QueryTemplateService, orgID ID) ([]*QueryTemplate, error) {
+	existing, err := s.FindQueryTemplates(ctx, QueryTemplateFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		names[t.Name] = true
+	}
+
+	var created []*QueryTemplate
+	for _, t := range BuiltinQueryTemplates() {
+		if names[t.Name] {
+			continue
+		}
+		t.OrganizationID = orgID
+		if err := s.CreateQueryTemplate(ctx, t); err != nil {
+			return created, err
+		}
+		created = append(created, t)
+	}
+	return created, nil
+}
@@ -0,0 +1,119 @@
+package influxdb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestQueryTemplate_Render(t *testing.T) {
+	qt := &influxdb.QueryTemplate{
+		Name: "test",
+		Params: []influxdb.QueryTemplateParam{
+			{Name: "bucket", Type: influxdb.QueryTemplateParamString},
+			{Name: "start", Type: influxdb.QueryTemplateParamDuration, Default: "-1h"},
+			{Name: "k", Type: influxdb.QueryTemplateParamInteger, Default: "5"},
+			{Name: "objective", Type: influxdb.QueryTemplateParamFloat, Default: "1"},
+		},
+		Flux: `from(bucket: bucket) |> range(start: start) |> top(n: k)`,
+	}
+	if err := qt.Valid(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			params: map[string]string{"bucket": "telegraf"},
+			want:   "bucket = \"telegraf\"\nstart = -1h\nk = 5\nobjective = 1.0\n",
+		},
+		{
+			name:   "escaped string",
+			params: map[string]string{"bucket": `a" |> drop() ${x} \`, "start": "-2h30m", "k": "-3", "objective": "0.999"},
+			want:   "bucket = \"a\\\" |> drop() \\${x} \\\\\"\nstart = -2h30m\nk = -3\nobjective = 0.999\n",
+		},
+		{name: "missing required parameter", wantErr: true},
+		{name: "unknown parameter", params: map[string]string{"bucket": "b", "stop": "now()"}, wantErr: true},
+		{name: "invalid duration", params: map[string]string{"bucket": "b", "start": "-1h |> drop()"}, wantErr: true},
+		{name: "invalid integer", params: map[string]string{"bucket": "b", "k": "5.5"}, wantErr: true},
+		{name: "invalid float", params: map[string]string{"bucket": "b", "objective": "NaN"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := qt.Render(tt.params)
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EInvalid {
+					t.Fatalf("expected invalid error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.want + qt.Flux; got != want {
+				t.Errorf("Render() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestQueryTemplate_Valid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template influxdb.QueryTemplate
+		wantErr  string
+	}{
+		{name: "no name", template: influxdb.QueryTemplate{Flux: "1"}, wantErr: "requires a name"},
+		{name: "no flux", template: influxdb.QueryTemplate{Name: "t"}, wantErr: "requires flux"},
+		{name: "invalid flux", template: influxdb.QueryTemplate{Name: "t", Flux: "from(bucket: "}, wantErr: "valid flux"},
+		{
+			name: "invalid parameter name",
+			template: influxdb.QueryTemplate{Name: "t", Flux: "1", Params: []influxdb.QueryTemplateParam{
+				{Name: "a b", Type: influxdb.QueryTemplateParamString},
+			}},
+			wantErr: "valid identifier",
+		},
+		{
+			name: "duplicate parameter",
+			template: influxdb.QueryTemplate{Name: "t", Flux: "1", Params: []influxdb.QueryTemplateParam{
+				{Name: "a", Type: influxdb.QueryTemplateParamString},
+				{Name: "a", Type: influxdb.QueryTemplateParamInteger},
+			}},
+			wantErr: "not unique",
+		},
+		{
+			name: "invalid parameter type",
+			template: influxdb.QueryTemplate{Name: "t", Flux: "1", Params: []influxdb.QueryTemplateParam{
+				{Name: "a", Type: "time"},
+			}},
+			wantErr: "invalid type",
+		},
+		{
+			name: "invalid default",
+			template: influxdb.QueryTemplate{Name: "t", Flux: "1", Params: []influxdb.QueryTemplateParam{
+				{Name: "a", Type: influxdb.QueryTemplateParamDuration, Default: "1 hour"},
+			}},
+			wantErr: "invalid duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Valid()
+			if influxdb.ErrorCode(err) != influxdb.EInvalid || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Valid() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	for _, qt := range influxdb.BuiltinQueryTemplates() {
+		if err := qt.Valid(); err != nil {
+			t.Errorf("built-in template %s is invalid: %v", qt.Name, err)
+		}
+	}
+}
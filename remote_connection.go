@@ -0,0 +1,173 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/x509"
+	"net/url"
+)
+
+// ops for remote connection errors and op logs.
+const (
+	OpFindRemoteConnectionByID = "FindRemoteConnectionByID"
+	OpFindRemoteConnections    = "FindRemoteConnections"
+	OpCreateRemoteConnection   = "CreateRemoteConnection"
+	OpUpdateRemoteConnection   = "UpdateRemoteConnection"
+	OpDeleteRemoteConnection   = "DeleteRemoteConnection"
+)
+
+// RemoteConnectionService represents a service for managing remote connections.
+type RemoteConnectionService interface {
+	// FindRemoteConnectionByID returns a single remote connection by ID.
+	FindRemoteConnectionByID(ctx context.Context, id ID) (*RemoteConnection, error)
+
+	// FindRemoteConnections returns a list of remote connections that match
+	// the filter and the total count of matching connections.
+	FindRemoteConnections(ctx context.Context, filter RemoteConnectionFilter, opt ...FindOptions) ([]*RemoteConnection, int, error)
+
+	// CreateRemoteConnection creates a new remote connection and sets rc.ID
+	// with the new identifier.
+	CreateRemoteConnection(ctx context.Context, rc *RemoteConnection) error
+
+	// UpdateRemoteConnection updates a single remote connection with the changeset.
+	UpdateRemoteConnection(ctx context.Context, id ID, upd RemoteConnectionUpdate) (*RemoteConnection, error)
+
+	// DeleteRemoteConnection removes a remote connection by ID.
+	DeleteRemoteConnection(ctx context.Context, id ID) error
+}
+
+// RemoteConnection is a named connection of an organization to another
+// InfluxDB 2.x instance. The data of the remote organization is read and
+// written with a token kept in the secrets of the organization.
+type RemoteConnection struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	// URL is the address of the remote instance, e.g. https://edge-1:9999.
+	URL string `json:"url"`
+	// RemoteOrgID is the organization of the remote instance the local
+	// organization is mapped to.
+	RemoteOrgID ID `json:"remoteOrgID"`
+	// TokenSecretKey is the key of the secret of the organization holding
+	// the token of the remote instance.
+	TokenSecretKey string              `json:"tokenSecretKey"`
+	TLS            RemoteConnectionTLS `json:"tls"`
+	// Forward forwards the writes of the organization to the buckets of the
+	// same names in the remote organization when the instance runs in
+	// store-and-forward mode.
+	Forward bool `json:"forward,omitempty"`
+
+	CRUDLog
+}
+
+// RemoteConnectionTLS are the TLS options of a remote connection.
+type RemoteConnectionTLS struct {
+	// InsecureSkipVerify skips the verification of the certificate of the
+	// remote instance.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// CACert is a PEM bundle of the certificate authorities that verify the
+	// certificate of the remote instance instead of the system ones.
+	CACert string `json:"caCert,omitempty"`
+}
+
+// CertPool returns the pool of the certificate authorities of the options,
+// or nil if the system ones are used.
+func (t RemoteConnectionTLS) CertPool() (*x509.CertPool, error) {
+	if t.CACert == "" {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(t.CACert)) {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "remote connection ca certificate must be a PEM encoded certificate",
+		}
+	}
+	return pool, nil
+}
+
+// Valid returns an error if the remote connection is invalid.
+func (rc *RemoteConnection) Valid() error {
+	switch {
+	case !rc.OrganizationID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "remote connection requires an organization",
+		}
+	case rc.Name == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "remote connection requires a name",
+		}
+	case !rc.RemoteOrgID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "remote connection requires a remote organization",
+		}
+	case rc.TokenSecretKey == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "remote connection requires the secret key of its token",
+		}
+	}
+	u, err := url.Parse(rc.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "remote connection url must be an absolute http or https url",
+		}
+	}
+	_, err = rc.TLS.CertPool()
+	return err
+}
+
+// RemoteConnectionFilter represents a set of filters that restrict the
+// returned remote connections.
+type RemoteConnectionFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Name           *string
+}
+
+// RemoteConnectionUpdate represents updates to a remote connection. Only
+// fields which are set are updated.
+type RemoteConnectionUpdate struct {
+	Name           *string              `json:"name,omitempty"`
+	Description    *string              `json:"description,omitempty"`
+	URL            *string              `json:"url,omitempty"`
+	RemoteOrgID    *ID                  `json:"remoteOrgID,omitempty"`
+	TokenSecretKey *string              `json:"tokenSecretKey,omitempty"`
+	TLS            *RemoteConnectionTLS `json:"tls,omitempty"`
+	Forward        *bool                `json:"forward,omitempty"`
+}
+
+// Redirects returns true if the update changes where, with which token or
+// which data of the connection is sent.
+func (u RemoteConnectionUpdate) Redirects() bool {
+	return u.URL != nil || u.RemoteOrgID != nil || u.TokenSecretKey != nil || u.TLS != nil || u.Forward != nil
+}
+
+// Apply applies the update to the remote connection.
+func (u RemoteConnectionUpdate) Apply(rc *RemoteConnection) {
+	if u.Name != nil {
+		rc.Name = *u.Name
+	}
+	if u.Description != nil {
+		rc.Description = *u.Description
+	}
+	if u.URL != nil {
+		rc.URL = *u.URL
+	}
+	if u.RemoteOrgID != nil {
+		rc.RemoteOrgID = *u.RemoteOrgID
+	}
+	if u.TokenSecretKey != nil {
+		rc.TokenSecretKey = *u.TokenSecretKey
+	}
+	if u.TLS != nil {
+		rc.TLS = *u.TLS
+	}
+	if u.Forward != nil {
+		rc.Forward = *u.Forward
+	}
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	cellPadding  = 4
+	plotPadding  = 10
+	gridLines    = 4
+	scaleColorID = "scale"
+)
+
+var (
+	backgroundColor  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	panelColor       = color.RGBA{0xf4, 0xf5, 0xf8, 0xff}
+	placeholderColor = color.RGBA{0xdd, 0xdf, 0xe6, 0xff}
+	errorColor       = color.RGBA{0xf8, 0xd7, 0xda, 0xff}
+	gridColor        = color.RGBA{0xe1, 0xe3, 0xea, 0xff}
+	axisColor        = color.RGBA{0x9e, 0xa2, 0xb0, 0xff}
+
+	// defaultPalette colors the series of cells without scale colors.
+	defaultPalette = []color.RGBA{
+		{0x31, 0xc0, 0xf6, 0xff},
+		{0xa5, 0x00, 0xa5, 0xff},
+		{0xff, 0x7e, 0x27, 0xff},
+		{0x4e, 0xd8, 0xa0, 0xff},
+		{0xdc, 0x4e, 0x58, 0xff},
+		{0x7a, 0x65, 0xf2, 0xff},
+	}
+)
+
+// chartPalette returns the scale colors of a view, or the default palette if
+// it has none.
+func chartPalette(colors []influxdb.ViewColor) []color.RGBA {
+	var p []color.RGBA
+	for _, c := range colors {
+		if c.Type != scaleColorID {
+			continue
+		}
+		if rgba, ok := parseHexColor(c.Hex); ok {
+			p = append(p, rgba)
+		}
+	}
+	if len(p) == 0 {
+		return defaultPalette
+	}
+	return p
+}
+
+// parseHexColor parses a color of the form #rrggbb.
+func parseHexColor(s string) (color.RGBA, bool) {
+	if len(s) != 7 || !strings.HasPrefix(s, "#") {
+		return color.RGBA{}, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, true
+}
+
+// drawPanel draws the background of a cell in the color.
+func drawPanel(dst *image.RGBA, c color.RGBA) {
+	fillRect(dst, dst.Bounds().Inset(cellPadding), c)
+}
+
+// drawChart draws the series as lines over the time range, with the values
+// scaled to the minimum and maximum of the series.
+func drawChart(dst *image.RGBA, start, stop time.Time, ss []series, palette []color.RGBA) {
+	drawPanel(dst, panelColor)
+	plot := dst.Bounds().Inset(cellPadding + plotPadding)
+	if plot.Empty() {
+		return
+	}
+
+	for i := 1; i < gridLines; i++ {
+		y := plot.Max.Y - i*plot.Dy()/gridLines
+		drawLine(dst, plot.Min.X, y, plot.Max.X, y, gridColor)
+	}
+	drawLine(dst, plot.Min.X, plot.Min.Y, plot.Min.X, plot.Max.Y, axisColor)
+	drawLine(dst, plot.Min.X, plot.Max.Y, plot.Max.X, plot.Max.Y, axisColor)
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range ss {
+		for _, v := range s.values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	if lo > hi {
+		return
+	}
+	if lo == hi {
+		lo, hi = lo-1, hi+1
+	}
+
+	t0, t1 := float64(start.UnixNano()), float64(stop.UnixNano())
+	// Points outside of the time range, e.g. the stop of the last window,
+	// are drawn at its edges.
+	px := func(t int64) int {
+		f := math.Max(0, math.Min(1, (float64(t)-t0)/(t1-t0)))
+		return plot.Min.X + int(math.Round(f*float64(plot.Dx())))
+	}
+	py := func(v float64) int {
+		return plot.Max.Y - int(math.Round((v-lo)/(hi-lo)*float64(plot.Dy())))
+	}
+
+	for i, s := range ss {
+		c := palette[i%len(palette)]
+		x0, y0 := px(s.times[0]), py(s.values[0])
+		dst.SetRGBA(x0, y0, c)
+		for j := 1; j < len(s.times); j++ {
+			x1, y1 := px(s.times[j]), py(s.values[j])
+			drawLine(dst, x0, y0, x1, y1, c)
+			x0, y0 = x1, y1
+		}
+	}
+}
+
+func fillRect(dst *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(dst, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// drawLine draws the line between the points with Bresenham's algorithm.
+// Points outside of the image are not drawn.
+func drawLine(dst *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	e := dx + dy
+	for {
+		dst.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,350 @@
+// Package render renders dashboards to PNG images, so that notifications and
+// reports can embed their charts.
+//
+// Cells are laid out on the grid of the dashboard. The line charts of xy and
+// line plus single stat cells are drawn from the results of their queries;
+// the cells of other types are drawn as placeholders.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+const (
+	// DefaultWidth is the width of images when none is given.
+	DefaultWidth = 1200
+	// MinWidth and MaxWidth bound the width of images.
+	MinWidth = 240
+	MaxWidth = 4096
+
+	gridColumns = 12
+	rowHeight   = 80
+	// maxRows bounds the height of images; cells below it are cut off.
+	maxRows = 100
+
+	timeColumn  = "_time"
+	valueColumn = "_value"
+)
+
+// defaultTimeRange is the time range of cells without one.
+var defaultTimeRange = influxdb.DashboardTimeRange{Start: "-1h"}
+
+var _ influxdb.DashboardRenderService = (*Service)(nil)
+
+// Service implements influxdb.DashboardRenderService. Queries run with the
+// authorization of the caller.
+type Service struct {
+	ds  influxdb.DashboardService
+	qs  query.QueryService
+	os  influxdb.OrganizationSettingsService
+	now func() time.Time
+}
+
+// NewService creates a service rendering the dashboards of the dashboard
+// service with the results of the query service. The windows of whole days
+// of the queries are aligned to the timezone and the first day of the week of
+// the organization of the dashboard, if the organization settings service is
+// not nil.
+func NewService(ds influxdb.DashboardService, qs query.QueryService, os influxdb.OrganizationSettingsService) *Service {
+	return &Service{
+		ds:  ds,
+		qs:  qs,
+		os:  os,
+		now: time.Now,
+	}
+}
+
+// RenderDashboard renders the cells of the dashboard to a PNG image. A cell
+// whose query fails is drawn as an error, so that one broken cell does not
+// break the rendering of the dashboard.
+func (s *Service) RenderDashboard(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+	width := opts.Width
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if width < MinWidth || width > MaxWidth {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpRenderDashboard,
+			Msg:  fmt.Sprintf("width must be between %d and %d pixels", MinWidth, MaxWidth),
+		}
+	}
+	if opts.TimeRange != nil {
+		if err := opts.TimeRange.Valid(); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := s.ds.FindDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := query.AuthorizationFromContext(ctx, d.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := 1
+	for _, c := range d.Cells {
+		if r := int(c.Y + c.H); r > rows {
+			rows = r
+		}
+	}
+	if rows > maxRows {
+		rows = maxRows
+	}
+
+	cal, err := s.calendar(ctx, d.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, rows*rowHeight))
+	fillRect(img, img.Bounds(), backgroundColor)
+
+	now := s.now()
+	for _, c := range d.Cells {
+		r := cellRect(c, width).Intersect(img.Bounds())
+		if r.Empty() {
+			continue
+		}
+		dst := img.SubImage(r).(*image.RGBA)
+		if err := s.renderCell(ctx, dst, d, c, opts, auth, cal, now); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			drawPanel(dst, errorColor)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpRenderDashboard,
+			Err:  err,
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// cellRect returns the rectangle of the cell in an image of the width.
+func cellRect(c *influxdb.Cell, width int) image.Rectangle {
+	colWidth := float64(width) / gridColumns
+	return image.Rect(
+		int(float64(c.X)*colWidth), int(c.Y)*rowHeight,
+		int(float64(c.X+c.W)*colWidth), int(c.Y+c.H)*rowHeight,
+	)
+}
+
+// calendar returns the calendar the windows of the queries of the
+// organization are aligned to, or nil if the organization has no locale.
+func (s *Service) calendar(ctx context.Context, orgID influxdb.ID) (*query.Calendar, error) {
+	if s.os == nil {
+		return nil, nil
+	}
+	settings, err := s.os.FindOrganizationSettings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.HasLocale() {
+		return nil, nil
+	}
+	return &query.Calendar{
+		Location:  settings.Location(),
+		WeekStart: settings.FirstDayOfWeek(),
+	}, nil
+}
+
+func (s *Service) renderCell(ctx context.Context, dst *image.RGBA, d *influxdb.Dashboard, c *influxdb.Cell, opts influxdb.DashboardRenderOptions, auth *influxdb.Authorization, cal *query.Calendar, now time.Time) error {
+	v, err := s.ds.GetDashboardCellView(ctx, d.ID, c.ID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		queries          []influxdb.DashboardQuery
+		xColumn, yColumn string
+		colors           []influxdb.ViewColor
+	)
+	switch p := v.Properties.(type) {
+	case influxdb.XYViewProperties:
+		queries, xColumn, yColumn, colors = p.Queries, p.XColumn, p.YColumn, p.ViewColors
+	case influxdb.LinePlusSingleStatProperties:
+		queries, xColumn, yColumn, colors = p.Queries, p.XColumn, p.YColumn, p.ViewColors
+	default:
+		drawPanel(dst, placeholderColor)
+		return nil
+	}
+	if xColumn == "" {
+		xColumn = timeColumn
+	}
+	if yColumn == "" {
+		yColumn = valueColumn
+	}
+
+	tr := opts.TimeRange
+	if tr == nil {
+		tr = c.TimeRange
+	}
+	if tr == nil {
+		tr = d.TimeRange
+	}
+	if tr == nil {
+		tr = &defaultTimeRange
+	}
+	start, stop, err := tr.Bounds(now)
+	if err != nil {
+		return err
+	}
+
+	points := dst.Bounds().Dx()
+	var ss []series
+	for _, q := range queries {
+		if q.Text == "" {
+			continue
+		}
+		qs, err := s.querySeries(ctx, auth, d.OrganizationID, q.Text, start, stop, now, points, cal, xColumn, yColumn)
+		if err != nil {
+			return err
+		}
+		ss = append(ss, qs...)
+	}
+
+	drawChart(dst, start, stop, ss, chartPalette(colors))
+	return nil
+}
+
+// series are the points of a table of the results of a query.
+type series struct {
+	times  []int64
+	values []float64
+}
+
+// querySeries runs the query of a cell over the time range, with its results
+// downsampled to about one point per pixel and its windows of whole days
+// aligned to the calendar, if any, and returns a series per table of its
+// results.
+func (s *Service) querySeries(ctx context.Context, auth *influxdb.Authorization, orgID influxdb.ID, text string, start, stop, now time.Time, points int, cal *query.Calendar, xColumn, yColumn string) ([]series, error) {
+	pkg := parser.ParseSource(text)
+	if ast.Check(pkg) > 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpRenderDashboard,
+			Msg:  "cell query is not valid flux",
+			Err:  ast.GetError(pkg),
+		}
+	}
+
+	windowPeriod := stop.Sub(start) / time.Duration(points)
+	if windowPeriod < time.Millisecond {
+		windowPeriod = time.Millisecond
+	}
+	extern := parser.ParseSource(fmt.Sprintf(
+		"option v = {timeRangeStart: %s, timeRangeStop: %s, windowPeriod: %dms}",
+		start.UTC().Format(time.RFC3339Nano), stop.UTC().Format(time.RFC3339Nano), windowPeriod/time.Millisecond,
+	)).Files[0]
+	if cal != nil {
+		query.AlignWindows(pkg, *cal, now)
+	}
+	query.Downsample(pkg, extern, now, points)
+
+	c := lang.ASTCompiler{
+		AST: pkg,
+		Now: now,
+	}
+	c.PrependFile(extern)
+
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: orgID,
+		Compiler:       c,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	var ss []series
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			var sr series
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				return readPoints(&sr, cr, xColumn, yColumn)
+			}); err != nil {
+				return err
+			}
+			if len(sr.times) > 0 {
+				ss = append(ss, sr)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// readPoints appends the points of the columns to the series. Tables without
+// a time column or a numeric value column have no points.
+func readPoints(sr *series, cr flux.ColReader, xColumn, yColumn string) error {
+	x, y := -1, -1
+	for j, col := range cr.Cols() {
+		switch {
+		case col.Label == xColumn && col.Type == flux.TTime:
+			x = j
+		case col.Label == yColumn && (col.Type == flux.TFloat || col.Type == flux.TInt || col.Type == flux.TUInt):
+			y = j
+		}
+	}
+	if x < 0 || y < 0 {
+		return nil
+	}
+
+	times := cr.Times(x)
+	for i := 0; i < cr.Len(); i++ {
+		if times.IsNull(i) {
+			continue
+		}
+		var v float64
+		switch cr.Cols()[y].Type {
+		case flux.TFloat:
+			vs := cr.Floats(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = vs.Value(i)
+		case flux.TInt:
+			vs := cr.Ints(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = float64(vs.Value(i))
+		case flux.TUInt:
+			vs := cr.UInts(y)
+			if vs.IsNull(i) {
+				continue
+			}
+			v = float64(vs.Value(i))
+		}
+		sr.times = append(sr.times, times.Value(i))
+		sr.values = append(sr.values, v)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package render_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/render"
+)
+
+func TestService_RenderDashboard(t *testing.T) {
+	orgID, dashboardID := influxdb.ID(1), influxdb.ID(10)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+		return &influxdb.Dashboard{
+			ID:             id,
+			OrganizationID: orgID,
+			Cells: []*influxdb.Cell{
+				{ID: 100, CellProperty: influxdb.CellProperty{X: 0, Y: 0, W: 6, H: 3}},
+				{ID: 101, CellProperty: influxdb.CellProperty{X: 6, Y: 0, W: 6, H: 3}},
+				{ID: 102, CellProperty: influxdb.CellProperty{X: 0, Y: 3, W: 12, H: 2}},
+			},
+		}, nil
+	}
+	ds.GetDashboardCellViewF = func(ctx context.Context, dashboardID, cellID influxdb.ID) (*influxdb.View, error) {
+		switch cellID {
+		case 100:
+			return &influxdb.View{Properties: influxdb.XYViewProperties{
+				Queries:    []influxdb.DashboardQuery{{Text: `from(bucket: "b") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`}},
+				ViewColors: []influxdb.ViewColor{{Type: "scale", Hex: "#ff0000"}},
+			}}, nil
+		case 101:
+			return &influxdb.View{Properties: influxdb.SingleStatViewProperties{}}, nil
+		default:
+			return &influxdb.View{Properties: influxdb.XYViewProperties{
+				Queries: []influxdb.DashboardQuery{{Text: `from(bucket: `}},
+			}}, nil
+		}
+	}
+
+	auth := &influxdb.Authorization{ID: 1000, OrgID: orgID, Status: influxdb.Active}
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID || req.Authorization != auth {
+				t.Fatalf("expected query in org %s with the authorization of the caller, got %s %v", orgID, req.OrganizationID, req.Authorization)
+			}
+			c, ok := req.Compiler.(lang.ASTCompiler)
+			if !ok {
+				t.Fatalf("expected an AST compiler, got %T", req.Compiler)
+			}
+			var src []string
+			for _, f := range c.AST.Files {
+				src = append(src, ast.Format(f))
+			}
+			if s := strings.Join(src, "\n"); !strings.Contains(s, "timeRangeStart: 2020-01-01T00:00:00Z") || !strings.Contains(s, "aggregateWindow(") {
+				t.Fatalf("expected a downsampled query over the time range, got %s", s)
+			}
+
+			var data [][]interface{}
+			for i := 0; i <= 60; i++ {
+				data = append(data, []interface{}{values.ConvertTime(start.Add(time.Duration(i) * time.Minute)), float64(i % 10)})
+			}
+			r := executetest.NewResult([]*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: data,
+			}})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+
+	s := render.NewService(ds, qs, nil)
+	ctx := icontext.SetAuthorizer(context.Background(), auth)
+	b, err := s.RenderDashboard(ctx, dashboardID, influxdb.DashboardRenderOptions{
+		TimeRange: &influxdb.DashboardTimeRange{Start: "2020-01-01T00:00:00Z", Stop: "2020-01-01T01:00:00Z"},
+		Width:     600,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 600, 400); got != want {
+		t.Fatalf("expected image of %v, got %v", want, got)
+	}
+
+	count := func(r image.Rectangle, c color.RGBA) int {
+		n := 0
+		for x := r.Min.X; x < r.Max.X; x++ {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				if color.RGBAModel.Convert(img.At(x, y)) == c {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if n := count(image.Rect(0, 0, 300, 240), red); n < 300 {
+		t.Errorf("expected the chart to be drawn in the scale color, got %d pixels", n)
+	}
+	if n := count(image.Rect(300, 0, 600, 240), red); n != 0 {
+		t.Errorf("expected no chart in the single stat cell, got %d pixels", n)
+	}
+	if a, b := img.At(450, 120), img.At(300, 320); a == b {
+		t.Errorf("expected the placeholder and the error to differ, got %v", a)
+	}
+}
+
+func TestService_RenderDashboard_Calendar(t *testing.T) {
+	orgID := influxdb.ID(1)
+
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+		return &influxdb.Dashboard{
+			ID:             id,
+			OrganizationID: orgID,
+			Cells:          []*influxdb.Cell{{ID: 100, CellProperty: influxdb.CellProperty{X: 0, Y: 0, W: 12, H: 3}}},
+		}, nil
+	}
+	ds.GetDashboardCellViewF = func(ctx context.Context, dashboardID, cellID influxdb.ID) (*influxdb.View, error) {
+		return &influxdb.View{Properties: influxdb.XYViewProperties{
+			Queries: []influxdb.DashboardQuery{{Text: `from(bucket: "b") |> range(start: v.timeRangeStart) |> window(every: 1d)`}},
+		}}, nil
+	}
+
+	os := mock.NewOrganizationSettingsService()
+	os.FindOrganizationSettingsFn = func(ctx context.Context, id influxdb.ID) (*influxdb.OrganizationSettings, error) {
+		return &influxdb.OrganizationSettings{OrgID: id, Timezone: "Asia/Tokyo"}, nil
+	}
+
+	var src string
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			for _, f := range req.Compiler.(lang.ASTCompiler).AST.Files {
+				src += ast.Format(f)
+			}
+			return flux.NewSliceResultIterator(nil), nil
+		},
+	}
+
+	s := render.NewService(ds, qs, os)
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{ID: 1000, OrgID: orgID, Status: influxdb.Active})
+	if _, err := s.RenderDashboard(ctx, 10, influxdb.DashboardRenderOptions{
+		TimeRange: &influxdb.DashboardTimeRange{Start: "2020-01-01T00:00:00Z", Stop: "2020-01-08T00:00:00Z"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// midnight in Tokyo is 15:00 UTC.
+	if !strings.Contains(src, "window(every: 1d, offset: 54000000ms)") {
+		t.Errorf("expected the daily windows to be aligned to the timezone of the organization, got %s", src)
+	}
+}
+
+func TestService_RenderDashboard_Invalid(t *testing.T) {
+	s := render.NewService(mock.NewDashboardService(), &qmock.QueryService{}, nil)
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{})
+
+	for _, opts := range []influxdb.DashboardRenderOptions{
+		{Width: 10},
+		{Width: render.MaxWidth + 1},
+		{TimeRange: &influxdb.DashboardTimeRange{Start: "yesterday"}},
+	} {
+		if _, err := s.RenderDashboard(ctx, 1, opts); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("RenderDashboard(%+v) error = %v, want invalid", opts, err)
+		}
+	}
+}
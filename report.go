@@ -0,0 +1,266 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+)
+
+// ops for report errors and op logs.
+const (
+	OpFindReportByID = "FindReportByID"
+	OpFindReports    = "FindReports"
+	OpCreateReport   = "CreateReport"
+	OpUpdateReport   = "UpdateReport"
+	OpDeleteReport   = "DeleteReport"
+	OpFindReportRuns = "FindReportRuns"
+	OpAddReportRun   = "AddReportRun"
+	OpDeliverReport  = "DeliverReport"
+)
+
+// MaxReportRuns is the number of the most recent runs of a report that are
+// kept.
+const MaxReportRuns = 100
+
+// MinReportEvery is the shortest interval reports are delivered at.
+const MinReportEvery = time.Minute
+
+// ReportService represents a service for managing scheduled reports.
+type ReportService interface {
+	// FindReportByID returns a single report by ID.
+	FindReportByID(ctx context.Context, id ID) (*Report, error)
+
+	// FindReports returns a list of reports that match the filter and the
+	// total count of matching reports.
+	FindReports(ctx context.Context, filter ReportFilter, opt ...FindOptions) ([]*Report, int, error)
+
+	// CreateReport creates a new report and sets r.ID with the new identifier.
+	CreateReport(ctx context.Context, r *Report) error
+
+	// UpdateReport updates a single report with the changeset.
+	UpdateReport(ctx context.Context, id ID, upd ReportUpdate) (*Report, error)
+
+	// DeleteReport removes a report and its runs by ID.
+	DeleteReport(ctx context.Context, id ID) error
+
+	// FindReportRuns returns the kept runs of a report, the most recent first.
+	FindReportRuns(ctx context.Context, id ID) ([]*ReportRun, error)
+
+	// AddReportRun records a run of a report and sets run.ID with the new
+	// identifier. Only the MaxReportRuns most recent runs are kept.
+	AddReportRun(ctx context.Context, run *ReportRun) error
+}
+
+// ReportAuthorizationService finds the authorizations reports are rendered
+// with.
+type ReportAuthorizationService interface {
+	// FindReportAuthorization returns the authorization the report is
+	// rendered with, which has the permissions of the owner of the report.
+	FindReportAuthorization(ctx context.Context, r *Report) (*Authorization, error)
+}
+
+// ReportDeliveryService delivers reports.
+type ReportDeliveryService interface {
+	// DeliverReport renders and delivers the report immediately, outside of
+	// its schedule, and returns the recorded run.
+	DeliverReport(ctx context.Context, id ID) (*ReportRun, error)
+}
+
+// Report is the scheduled delivery of a rendering of a dashboard, so that
+// stakeholders without an account receive regular updates. A report is
+// delivered through a notification endpoint, Slack or HTTP, and emailed to
+// its recipients.
+//
+// A report is delivered every Every, at multiples of Every since the Unix
+// epoch shifted by Offset: a report every 24h with an offset of 9h is
+// delivered at 09:00 UTC. Deliveries missed while the server was down are
+// not caught up; the next due delivery is made once.
+type Report struct {
+	ID             ID       `json:"id,omitempty"`
+	OrganizationID ID       `json:"orgID"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	DashboardID    ID       `json:"dashboardID"`
+	Every          Duration `json:"every"`
+	Offset         Duration `json:"offset"`
+	// TimeRange is the time range of the cells of the dashboard, e.g. -24h
+	// for a daily report. The time range of the settings of the cells or of
+	// the dashboard is used if nil.
+	TimeRange *DashboardTimeRange `json:"timeRange,omitempty"`
+	// EndpointID is the notification endpoint the report is delivered
+	// through, if any.
+	EndpointID ID `json:"endpointID,omitempty"`
+	// Channel is the Slack channel the report is posted to. It is required
+	// by Slack endpoints with a token instead of a webhook URL.
+	Channel string `json:"channel,omitempty"`
+	// Emails are the addresses the report is emailed to.
+	Emails []string `json:"emails,omitempty"`
+	Status Status   `json:"status"`
+
+	// OwnerID is the user who created the report. The report is rendered
+	// with the permissions of its owner.
+	OwnerID ID `json:"ownerID,omitempty"`
+
+	// LatestScheduled is the time of the latest scheduled delivery.
+	LatestScheduled time.Time `json:"latestScheduled,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the report is invalid.
+func (r *Report) Valid() error {
+	switch {
+	case !r.OrganizationID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "report requires an organization",
+		}
+	case r.Name == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "report requires a name",
+		}
+	case !r.DashboardID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "report requires a dashboard",
+		}
+	case r.Every.Duration < MinReportEvery:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("report interval must be at least %s", MinReportEvery),
+		}
+	case r.Offset.Duration < 0 || r.Offset.Duration >= r.Every.Duration:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "report offset must not be negative and must be shorter than its interval",
+		}
+	case !r.EndpointID.Valid() && len(r.Emails) == 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "report requires a notification endpoint or emails to be delivered to",
+		}
+	}
+
+	for _, e := range r.Emails {
+		if _, err := mail.ParseAddress(e); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid report email %q", e),
+			}
+		}
+	}
+	if r.TimeRange != nil {
+		if err := r.TimeRange.Valid(); err != nil {
+			return err
+		}
+	}
+	return r.Status.Valid()
+}
+
+// LatestDue returns the time of the latest delivery of the schedule of the
+// report at or before now.
+func (r *Report) LatestDue(now time.Time) time.Time {
+	every, offset := int64(r.Every.Duration), int64(r.Offset.Duration)
+	t := now.UnixNano() - offset
+	t -= t % every
+	return time.Unix(0, t+offset).UTC()
+}
+
+// Due returns the time of the delivery of the report that is due at now, if
+// any. Deliveries scheduled before the report was created are not due.
+func (r *Report) Due(now time.Time) (time.Time, bool) {
+	if r.Status != Active {
+		return time.Time{}, false
+	}
+	t := r.LatestDue(now)
+	return t, t.After(r.LatestScheduled) && t.After(r.CreatedAt)
+}
+
+// ReportFilter represents a set of filters that restrict the returned reports.
+type ReportFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	DashboardID    *ID
+	Name           *string
+}
+
+// ReportUpdate represents updates to a report. Only fields which are set
+// are updated.
+type ReportUpdate struct {
+	Name        *string             `json:"name,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	DashboardID *ID                 `json:"dashboardID,omitempty"`
+	Every       *Duration           `json:"every,omitempty"`
+	Offset      *Duration           `json:"offset,omitempty"`
+	TimeRange   *DashboardTimeRange `json:"timeRange,omitempty"`
+	EndpointID  *ID                 `json:"endpointID,omitempty"`
+	Channel     *string             `json:"channel,omitempty"`
+	Emails      *[]string           `json:"emails,omitempty"`
+	Status      *Status             `json:"status,omitempty"`
+
+	// The schedule of the deliveries is not updated through the API.
+	LatestScheduled *time.Time `json:"-"`
+}
+
+// Apply applies the update to the report.
+func (u ReportUpdate) Apply(r *Report) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.DashboardID != nil {
+		r.DashboardID = *u.DashboardID
+	}
+	if u.Every != nil {
+		r.Every = *u.Every
+	}
+	if u.Offset != nil {
+		r.Offset = *u.Offset
+	}
+	if u.TimeRange != nil {
+		r.TimeRange = u.TimeRange
+	}
+	if u.EndpointID != nil {
+		r.EndpointID = *u.EndpointID
+	}
+	if u.Channel != nil {
+		r.Channel = *u.Channel
+	}
+	if u.Emails != nil {
+		r.Emails = *u.Emails
+	}
+	if u.Status != nil {
+		r.Status = *u.Status
+	}
+	if u.LatestScheduled != nil {
+		r.LatestScheduled = *u.LatestScheduled
+	}
+}
+
+// the statuses of report runs.
+const (
+	ReportRunSuccess = "success"
+	ReportRunFailed  = "failed"
+)
+
+// ReportRun is a delivery of a report.
+type ReportRun struct {
+	ID       ID `json:"id,omitempty"`
+	ReportID ID `json:"reportID"`
+	// ScheduledFor is the time of the delivery in the schedule of the
+	// report, or the time it was requested at if it was manual.
+	ScheduledFor time.Time `json:"scheduledFor"`
+	// Manual reports whether the delivery was requested outside of the
+	// schedule of the report.
+	Manual     bool      `json:"manual,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Status     string    `json:"status"`
+	// Error is the error of a failed run. A run fails if the report could
+	// not be rendered or any of its deliveries failed.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,200 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+)
+
+// SlackFilesURL is the URL of the Slack API uploading files, used by Slack
+// endpoints that have a token but no webhook URL.
+var SlackFilesURL = "https://slack.com/api/files.upload"
+
+// maxErrorResponseBody is the length of the excerpt of the body of a failed
+// response reported in the error of a run.
+const maxErrorResponseBody = 256
+
+// sendEndpoint sends the report through its notification endpoint.
+//
+// HTTP endpoints receive the report as JSON with the image encoded in
+// base64. Slack webhooks cannot upload files, so they receive the text of
+// the report; Slack endpoints with a token upload the image to the channel
+// of the report.
+func (s *Service) sendEndpoint(ctx context.Context, r *influxdb.Report, m *message) error {
+	edp, err := s.Endpoints.FindNotificationEndpointByID(ctx, r.EndpointID)
+	if err != nil {
+		return err
+	}
+	load := func(f influxdb.SecretField) (string, error) {
+		if f.Key == "" {
+			return "", nil
+		}
+		v, err := s.Secrets.LoadSecret(ctx, edp.GetOrgID(), f.Key)
+		if err != nil {
+			return "", fmt.Errorf("failed to load secret %q of the notification endpoint: %v", f.Key, err)
+		}
+		return v, nil
+	}
+
+	var req *http.Request
+	switch e := edp.(type) {
+	case *endpoint.HTTP:
+		req, err = newJSONRequest(e.Method, e.URL, map[string]interface{}{
+			"_message":                    m.text(),
+			"_time":                       m.time.Format(time.RFC3339Nano),
+			"_notification_endpoint_id":   e.ID.String(),
+			"_notification_endpoint_name": e.Name,
+			"_report_id":                  r.ID.String(),
+			"_report_name":                r.Name,
+			"_dashboard_id":               m.dashboard.ID.String(),
+			"_dashboard_name":             m.dashboard.Name,
+			"_start":                      formatTime(m.start),
+			"_stop":                       formatTime(m.stop),
+			"_image":                      base64.StdEncoding.EncodeToString(m.image),
+			"_image_content_type":         "image/png",
+		})
+		if err != nil {
+			return err
+		}
+		for k, v := range e.Headers {
+			req.Header.Set(k, v)
+		}
+		switch e.AuthMethod {
+		case "basic":
+			username, err := load(e.Username)
+			if err != nil {
+				return err
+			}
+			password, err := load(e.Password)
+			if err != nil {
+				return err
+			}
+			req.SetBasicAuth(username, password)
+		case "bearer":
+			token, err := load(e.Token)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+	case *endpoint.Slack:
+		token, err := load(e.Token)
+		if err != nil {
+			return err
+		}
+		if e.URL != "" {
+			req, err = newJSONRequest(http.MethodPost, e.URL, map[string]string{"text": m.text()})
+		} else {
+			req, err = newSlackUploadRequest(r.Channel, m)
+		}
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("reports cannot be delivered through notification endpoints of type %s", edp.Type()),
+		}
+	}
+
+	resp, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorResponseBody))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response %d: %s", resp.StatusCode, body)
+	}
+	if _, ok := edp.(*endpoint.Slack); ok {
+		// The Slack API reports errors in the body of successful responses.
+		var res struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &res) == nil && !res.OK && res.Error != "" {
+			return fmt.Errorf("slack error: %s", res.Error)
+		}
+	}
+	return nil
+}
+
+func newJSONRequest(method, url string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// newSlackUploadRequest returns the request uploading the image of the
+// report to the channel.
+func newSlackUploadRequest(channel string, m *message) (*http.Request, error) {
+	if channel == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "report requires a channel to be delivered through a slack endpoint without a webhook URL",
+		}
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range map[string]string{
+		"channels":        channel,
+		"title":           m.subject(),
+		"initial_comment": m.text(),
+		"filename":        m.filename(),
+	} {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, err
+		}
+	}
+	f, err := w.CreateFormFile("file", m.filename())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(m.image); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, SlackFilesURL, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
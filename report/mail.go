@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// base64LineLength is the length of the lines of base64 encoded attachments.
+const base64LineLength = 76
+
+// Mailer sends emails through an SMTP server.
+type Mailer struct {
+	// Addr is the host:port address of the SMTP server.
+	Addr string
+	// From is the address the emails are sent from.
+	From string
+	// Username and Password authenticate to the server with the PLAIN
+	// mechanism, if Username is set.
+	Username string
+	Password string
+
+	// sendMail is smtp.SendMail, replaced in tests.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Send sends an email with the text and the PNG image attached to the
+// recipients.
+func (m *Mailer) Send(to []string, subject, text, filename string, image []byte) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %v", m.From, err)
+	}
+	rcpts := make([]string, 0, len(to))
+	for _, t := range to {
+		a, err := mail.ParseAddress(t)
+		if err != nil {
+			return fmt.Errorf("invalid recipient address %q: %v", t, err)
+		}
+		rcpts = append(rcpts, a.Address)
+	}
+
+	msg, err := newMessage(from.String(), to, subject, text, filename, image, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP server address %q: %v", m.Addr, err)
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	send := m.sendMail
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(m.Addr, auth, from.Address, rcpts, msg)
+}
+
+// newMessage returns a MIME multipart message with the text and the image
+// attached.
+func newMessage(from string, to []string, subject, text, filename string, image []byte, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write([]byte(text + "\r\n")); err != nil {
+		return nil, err
+	}
+
+	part, err = w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType("image/png", map[string]string{"name": filename})},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filename})},
+	})
+	if err != nil {
+		return nil, err
+	}
+	enc := base64.StdEncoding.EncodeToString(image)
+	for len(enc) > 0 {
+		n := base64LineLength
+		if n > len(enc) {
+			n = len(enc)
+		}
+		if _, err := part.Write([]byte(enc[:n] + "\r\n")); err != nil {
+			return nil, err
+		}
+		enc = enc[n:]
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": w.Boundary()})},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
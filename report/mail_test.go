@@ -0,0 +1,68 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"testing"
+)
+
+func TestMailer_Send(t *testing.T) {
+	var (
+		addr, from string
+		to         []string
+		msg        []byte
+	)
+	m := &Mailer{
+		Addr: "smtp.example.com:587",
+		From: "InfluxDB <reports@example.com>",
+		sendMail: func(a string, auth smtp.Auth, f string, t []string, b []byte) error {
+			addr, from, to, msg = a, f, t, b
+			return nil
+		},
+	}
+	image := []byte("\x89PNG image")
+	if err := m.Send([]string{"Ops <ops@example.com>"}, "daily: overview", "Report of dashboard.", "overview.png", image); err != nil {
+		t.Fatal(err)
+	}
+	if addr != m.Addr || from != "reports@example.com" || len(to) != 1 || to[0] != "ops@example.com" {
+		t.Fatalf("unexpected envelope %s %s %v", addr, from, to)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Header.Get("Subject"); got != "daily: overview" {
+		t.Fatalf("unexpected subject %q", got)
+	}
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("expected a multipart message, got %q, %v", mediaType, err)
+	}
+
+	r := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := r.NextPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(p)
+		parts = append(parts, string(b))
+		if p.FileName() == "overview.png" && !bytes.Contains(b, []byte("iVBORyBpbWFnZQ==")) {
+			t.Fatalf("expected the image to be attached in base64, got %q", b)
+		}
+	}
+	if len(parts) != 2 || parts[0] != "Report of dashboard.\r\n" {
+		t.Fatalf("expected the text and the attachment, got %q", parts)
+	}
+
+	m.From = "reports"
+	if err := m.Send([]string{"ops@example.com"}, "", "", "overview.png", image); err == nil {
+		t.Fatal("expected an invalid sender address to be rejected")
+	}
+}
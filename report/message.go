@@ -0,0 +1,43 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// message is a rendered report.
+type message struct {
+	report    *influxdb.Report
+	dashboard *influxdb.Dashboard
+	image     []byte // PNG
+	time      time.Time
+
+	// start and stop are the time range of the rendering, if it is the same
+	// for all the cells of the dashboard.
+	start, stop time.Time
+}
+
+func (m *message) subject() string {
+	return fmt.Sprintf("%s: %s", m.report.Name, m.dashboard.Name)
+}
+
+func (m *message) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Report %q of dashboard %q", m.report.Name, m.dashboard.Name)
+	if !m.start.IsZero() {
+		fmt.Fprintf(&b, " from %s to %s", m.start.Format(time.RFC3339), m.stop.Format(time.RFC3339))
+	}
+	b.WriteString(".")
+	if m.report.Description != "" {
+		b.WriteString("\n\n")
+		b.WriteString(m.report.Description)
+	}
+	return b.String()
+}
+
+func (m *message) filename() string {
+	return fmt.Sprintf("%s-%s.png", m.dashboard.ID, m.time.Format("20060102T150405Z"))
+}
@@ -0,0 +1,248 @@
+// Package report delivers scheduled reports: renderings of dashboards sent
+// through notification endpoints and by email, so that stakeholders without
+// an account receive regular updates.
+package report
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"go.uber.org/zap"
+)
+
+// DeliveryTimeout is the timeout of the requests delivering reports.
+const DeliveryTimeout = 30 * time.Second
+
+var _ influxdb.ReportDeliveryService = (*Service)(nil)
+
+// Service renders and delivers reports, on their schedule or when requested.
+type Service struct {
+	Reports        influxdb.ReportService
+	Authorizations influxdb.ReportAuthorizationService
+	Dashboards     influxdb.DashboardService
+	Endpoints      influxdb.NotificationEndpointService
+	Secrets        influxdb.SecretService
+	Renderer       influxdb.DashboardRenderService
+
+	// Mailer sends the reports with emails. Reports are not emailed if nil.
+	Mailer *Mailer
+	// Client sends the reports through notification endpoints.
+	Client *http.Client
+
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewService returns a service delivering the reports of rs, rendered with
+// the authorizations of as. The services must not be authorized, as the
+// service acts on behalf of every organization.
+func NewService(rs influxdb.ReportService, as influxdb.ReportAuthorizationService, ds influxdb.DashboardService, es influxdb.NotificationEndpointService, ss influxdb.SecretService, renderer influxdb.DashboardRenderService) *Service {
+	return &Service{
+		Reports:        rs,
+		Authorizations: as,
+		Dashboards:     ds,
+		Endpoints:      es,
+		Secrets:        ss,
+		Renderer:       renderer,
+		Client:         &http.Client{Timeout: DeliveryTimeout},
+		logger:         zap.NewNop(),
+		now:            time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the service. It must be called before Run.
+func (s *Service) WithLogger(l *zap.Logger) {
+	s.logger = l.With(zap.String("component", "reports"))
+}
+
+// Run delivers the due reports immediately and then every interval until
+// ctx is canceled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Schedule(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Unable to deliver scheduled reports", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Schedule delivers the reports whose delivery is due. A delivery is marked
+// as scheduled before it is made, so that a failed delivery is recorded in
+// the runs of the report and not retried.
+func (s *Service) Schedule(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	log, logEnd := logger.NewOperation(ctx, s.logger, "Scheduled report delivery", "report_delivery")
+	defer logEnd()
+
+	reports, _, err := s.Reports.FindReports(ctx, influxdb.ReportFilter{})
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, r := range reports {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		due, ok := r.Due(now)
+		if !ok {
+			continue
+		}
+		if _, err := s.Reports.UpdateReport(ctx, r.ID, influxdb.ReportUpdate{LatestScheduled: &due}); err != nil {
+			log.Warn("Unable to schedule report",
+				zap.Stringer("org_id", r.OrganizationID),
+				zap.Stringer("report_id", r.ID),
+				zap.Error(err))
+			continue
+		}
+		run, err := s.deliver(ctx, r, due, false)
+		if err != nil {
+			log.Warn("Unable to record report run",
+				zap.Stringer("org_id", r.OrganizationID),
+				zap.Stringer("report_id", r.ID),
+				zap.Error(err))
+			continue
+		}
+		if run.Status == influxdb.ReportRunFailed {
+			log.Info("Report delivery failed",
+				zap.Stringer("org_id", r.OrganizationID),
+				zap.Stringer("report_id", r.ID),
+				zap.String("error", run.Error))
+		}
+	}
+	return nil
+}
+
+// DeliverReport renders and delivers the report immediately and returns the
+// recorded run. A failed delivery is recorded and is not an error.
+func (s *Service) DeliverReport(ctx context.Context, id influxdb.ID) (*influxdb.ReportRun, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.Reports.FindReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.deliver(ctx, r, s.now(), true)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpDeliverReport,
+			Err: err,
+		}
+	}
+	return run, nil
+}
+
+// deliver renders and delivers the report and records the run.
+func (s *Service) deliver(ctx context.Context, r *influxdb.Report, scheduledFor time.Time, manual bool) (*influxdb.ReportRun, error) {
+	run := &influxdb.ReportRun{
+		ReportID:     r.ID,
+		ScheduledFor: scheduledFor.UTC(),
+		Manual:       manual,
+		StartedAt:    s.now().UTC(),
+		Status:       influxdb.ReportRunSuccess,
+	}
+
+	var errs []string
+	for _, err := range s.send(ctx, r) {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		run.Status = influxdb.ReportRunFailed
+		run.Error = strings.Join(errs, "; ")
+	}
+	run.FinishedAt = s.now().UTC()
+
+	if err := s.Reports.AddReportRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// send renders the report and sends it to each of its destinations. It
+// returns the errors of the destinations it could not be sent to.
+func (s *Service) send(ctx context.Context, r *influxdb.Report) []error {
+	// The renderer is behind authorization, so the dashboard is rendered
+	// with the permissions of the owner of the report, so that a report
+	// does not show data its owner cannot read.
+	auth, err := s.Authorizations.FindReportAuthorization(ctx, r)
+	if err != nil {
+		return []error{fmt.Errorf("failed to find the authorization of the report: %v", err)}
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	d, err := s.Dashboards.FindDashboardByID(ctx, r.DashboardID)
+	if err != nil {
+		return []error{fmt.Errorf("failed to find dashboard: %v", err)}
+	}
+	img, err := s.Renderer.RenderDashboard(ctx, r.DashboardID, influxdb.DashboardRenderOptions{TimeRange: r.TimeRange})
+	if err != nil {
+		return []error{fmt.Errorf("failed to render dashboard: %v", err)}
+	}
+
+	m := &message{
+		report:    r,
+		dashboard: d,
+		image:     img,
+		time:      s.now().UTC(),
+	}
+	if tr := timeRange(r, d); tr != nil {
+		if m.start, m.stop, err = tr.Bounds(m.time); err != nil {
+			return []error{err}
+		}
+	}
+
+	var errs []error
+	if r.EndpointID.Valid() {
+		if err := s.sendEndpoint(ctx, r, m); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send report through notification endpoint: %v", err))
+		}
+	}
+	if len(r.Emails) > 0 {
+		if err := s.sendEmail(r, m); err != nil {
+			errs = append(errs, fmt.Errorf("failed to email report: %v", err))
+		}
+	}
+	return errs
+}
+
+// timeRange returns the time range the report is rendered over, if it is
+// the same for all the cells of the dashboard.
+func timeRange(r *influxdb.Report, d *influxdb.Dashboard) *influxdb.DashboardTimeRange {
+	if r.TimeRange != nil {
+		return r.TimeRange
+	}
+	for _, c := range d.Cells {
+		if c.TimeRange != nil {
+			return nil
+		}
+	}
+	return d.TimeRange
+}
+
+func (s *Service) sendEmail(r *influxdb.Report, m *message) error {
+	if s.Mailer == nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "email delivery is not configured",
+		}
+	}
+	return s.Mailer.Send(r.Emails, m.subject(), m.text(), m.filename(), m.image)
+}
@@ -0,0 +1,235 @@
+package report_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/report"
+)
+
+var image = []byte("\x89PNG image")
+
+// newService returns a report service delivering the report through the
+// endpoint, and the runs it records.
+func newService(t *testing.T, r *influxdb.Report, edp influxdb.NotificationEndpoint) (*report.Service, *[]*influxdb.ReportRun) {
+	rs := mock.NewReportService()
+	rs.FindReportByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Report, error) {
+		return r, nil
+	}
+	rs.FindReportsFn = func(ctx context.Context, filter influxdb.ReportFilter, opt ...influxdb.FindOptions) ([]*influxdb.Report, int, error) {
+		return []*influxdb.Report{r}, 1, nil
+	}
+	rs.UpdateReportFn = func(ctx context.Context, id influxdb.ID, upd influxdb.ReportUpdate) (*influxdb.Report, error) {
+		upd.Apply(r)
+		return r, nil
+	}
+	var runs []*influxdb.ReportRun
+	rs.AddReportRunFn = func(ctx context.Context, run *influxdb.ReportRun) error {
+		runs = append(runs, run)
+		return nil
+	}
+
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+		return &influxdb.Dashboard{ID: id, OrganizationID: r.OrganizationID, Name: "overview"}, nil
+	}
+
+	es := &mock.NotificationEndpointService{
+		FindNotificationEndpointByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+			return edp, nil
+		},
+	}
+	ss := &mock.SecretService{
+		LoadSecretFn: func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+			return "secret-" + k, nil
+		},
+	}
+
+	owner := &influxdb.Authorization{Status: influxdb.Active, ID: r.ID, OrgID: r.OrganizationID, UserID: r.OwnerID}
+	as := mock.NewReportAuthorizationService()
+	as.FindReportAuthorizationFn = func(ctx context.Context, rep *influxdb.Report) (*influxdb.Authorization, error) {
+		return owner, nil
+	}
+
+	renderer := mock.NewDashboardRenderService()
+	renderer.RenderDashboardFn = func(ctx context.Context, id influxdb.ID, opts influxdb.DashboardRenderOptions) ([]byte, error) {
+		a, err := icontext.GetAuthorizer(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != owner {
+			t.Fatalf("expected the dashboard to be rendered with the authorization of the owner of the report, got %v", a)
+		}
+		if opts.TimeRange != r.TimeRange {
+			t.Fatalf("expected the dashboard to be rendered over the time range of the report, got %v", opts.TimeRange)
+		}
+		return image, nil
+	}
+
+	return report.NewService(rs, as, ds, es, ss, renderer), &runs
+}
+
+func TestService_DeliverReport_HTTP(t *testing.T) {
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer secret-token"; got != want {
+			t.Errorf("expected authorization %q, got %q", want, got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	r := &influxdb.Report{
+		ID:             10,
+		OrganizationID: 1,
+		Name:           "daily",
+		DashboardID:    20,
+		EndpointID:     30,
+		TimeRange:      &influxdb.DashboardTimeRange{Start: "2020-01-01T00:00:00Z", Stop: "2020-01-02T00:00:00Z"},
+	}
+	edp := &endpoint.HTTP{
+		Base:       endpoint.Base{ID: 30, OrgID: 1, Name: "webhook"},
+		URL:        srv.URL,
+		Method:     http.MethodPost,
+		AuthMethod: "bearer",
+		Token:      influxdb.SecretField{Key: "token"},
+	}
+	s, runs := newService(t, r, edp)
+
+	run, err := s.DeliverReport(context.Background(), r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != influxdb.ReportRunSuccess || !run.Manual || len(*runs) != 1 {
+		t.Fatalf("expected a successful manual run to be recorded, got %+v", run)
+	}
+	if got, err := base64.StdEncoding.DecodeString(body["_image"]); err != nil || string(got) != string(image) {
+		t.Fatalf("expected the rendered image, got %q, %v", body["_image"], err)
+	}
+	if body["_report_name"] != "daily" || body["_dashboard_name"] != "overview" || body["_start"] != "2020-01-01T00:00:00Z" {
+		t.Fatalf("unexpected body %v", body)
+	}
+	if !strings.Contains(body["_message"], `"overview"`) {
+		t.Fatalf("expected the message to name the dashboard, got %q", body["_message"])
+	}
+}
+
+func TestService_DeliverReport_SlackUpload(t *testing.T) {
+	var channel, file string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		b, _ := ioutil.ReadAll(f)
+		channel, file = r.FormValue("channels"), string(b)
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer srv.Close()
+
+	defer func(u string) { report.SlackFilesURL = u }(report.SlackFilesURL)
+	report.SlackFilesURL = srv.URL
+
+	r := &influxdb.Report{ID: 10, OrganizationID: 1, Name: "daily", DashboardID: 20, EndpointID: 30, Channel: "#ops"}
+	edp := &endpoint.Slack{
+		Base:  endpoint.Base{ID: 30, OrgID: 1, Name: "slack"},
+		Token: influxdb.SecretField{Key: "token"},
+	}
+	s, _ := newService(t, r, edp)
+
+	run, err := s.DeliverReport(context.Background(), r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != influxdb.ReportRunSuccess {
+		t.Fatalf("expected a successful run, got %+v", run)
+	}
+	if channel != "#ops" || file != string(image) {
+		t.Fatalf("expected the image to be uploaded to the channel, got %q, %q", channel, file)
+	}
+
+	r.Channel = ""
+	if run, err := s.DeliverReport(context.Background(), r.ID); err != nil || run.Status != influxdb.ReportRunFailed {
+		t.Fatalf("expected the run without a channel to fail, got %+v, %v", run, err)
+	}
+}
+
+func TestService_DeliverReport_Failed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	r := &influxdb.Report{ID: 10, OrganizationID: 1, Name: "daily", DashboardID: 20, EndpointID: 30, Emails: []string{"ops@example.com"}}
+	edp := &endpoint.HTTP{
+		Base:       endpoint.Base{ID: 30, OrgID: 1, Name: "webhook"},
+		URL:        srv.URL,
+		Method:     http.MethodPost,
+		AuthMethod: "none",
+	}
+	s, runs := newService(t, r, edp)
+
+	run, err := s.DeliverReport(context.Background(), r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != influxdb.ReportRunFailed || len(*runs) != 1 {
+		t.Fatalf("expected a failed run to be recorded, got %+v", run)
+	}
+	if !strings.Contains(run.Error, "503") || !strings.Contains(run.Error, "email delivery is not configured") {
+		t.Fatalf("expected the errors of both destinations, got %q", run.Error)
+	}
+}
+
+func TestService_Schedule(t *testing.T) {
+	delivered := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+	defer srv.Close()
+
+	r := &influxdb.Report{
+		ID:             10,
+		OrganizationID: 1,
+		Name:           "hourly",
+		DashboardID:    20,
+		EndpointID:     30,
+		Every:          influxdb.Duration{Duration: time.Hour},
+		Status:         influxdb.Active,
+	}
+	r.CreatedAt = time.Now().Add(-2 * time.Hour)
+	edp := &endpoint.HTTP{
+		Base:       endpoint.Base{ID: 30, OrgID: 1, Name: "webhook"},
+		URL:        srv.URL,
+		Method:     http.MethodPost,
+		AuthMethod: "none",
+	}
+	s, runs := newService(t, r, edp)
+
+	for i := 0; i < 2; i++ {
+		if err := s.Schedule(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if delivered != 1 || len(*runs) != 1 {
+		t.Fatalf("expected the due delivery to be made once, got %d deliveries and %d runs", delivered, len(*runs))
+	}
+	run := (*runs)[0]
+	if run.Manual || !run.ScheduledFor.Equal(r.LatestScheduled) || run.ScheduledFor.Minute() != 0 {
+		t.Fatalf("expected a run scheduled on the hour, got %+v", run)
+	}
+}
@@ -0,0 +1,81 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestReport_Valid(t *testing.T) {
+	valid := func() *influxdb.Report {
+		return &influxdb.Report{
+			OrganizationID: 1,
+			Name:           "daily",
+			DashboardID:    2,
+			Every:          influxdb.Duration{Duration: 24 * time.Hour},
+			Emails:         []string{"ops@example.com"},
+			Status:         influxdb.Active,
+		}
+	}
+	if err := valid().Valid(); err != nil {
+		t.Fatalf("expected a valid report, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		update func(*influxdb.Report)
+	}{
+		{name: "no name", update: func(r *influxdb.Report) { r.Name = "" }},
+		{name: "no dashboard", update: func(r *influxdb.Report) { r.DashboardID = 0 }},
+		{name: "short interval", update: func(r *influxdb.Report) { r.Every.Duration = time.Second }},
+		{name: "offset of the interval", update: func(r *influxdb.Report) { r.Offset.Duration = r.Every.Duration }},
+		{name: "no destination", update: func(r *influxdb.Report) { r.Emails = nil }},
+		{name: "invalid email", update: func(r *influxdb.Report) { r.Emails = []string{"ops"} }},
+		{name: "invalid time range", update: func(r *influxdb.Report) { r.TimeRange = &influxdb.DashboardTimeRange{Start: "yesterday"} }},
+		{name: "invalid status", update: func(r *influxdb.Report) { r.Status = "paused" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.update(r)
+			if err := r.Valid(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected invalid error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReport_Due(t *testing.T) {
+	r := &influxdb.Report{
+		Every:  influxdb.Duration{Duration: 24 * time.Hour},
+		Offset: influxdb.Duration{Duration: 9 * time.Hour},
+		Status: influxdb.Active,
+	}
+	r.CreatedAt = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	now := time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC)
+	if got, want := r.LatestDue(now), time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("LatestDue() = %s, want %s", got, want)
+	}
+	if _, ok := r.Due(now); ok {
+		t.Fatal("expected the delivery scheduled before the report was created not to be due")
+	}
+
+	now = time.Date(2020, 1, 2, 9, 30, 0, 0, time.UTC)
+	due, ok := r.Due(now)
+	if want := time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC); !ok || !due.Equal(want) {
+		t.Fatalf("Due() = %s, %t, want %s, true", due, ok, want)
+	}
+
+	r.LatestScheduled = due
+	if _, ok := r.Due(now); ok {
+		t.Fatal("expected the scheduled delivery not to be due again")
+	}
+
+	r.LatestScheduled = time.Time{}
+	r.Status = influxdb.Inactive
+	if _, ok := r.Due(now); ok {
+		t.Fatal("expected the deliveries of an inactive report not to be due")
+	}
+}
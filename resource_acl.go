@@ -0,0 +1,157 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ops for resource acl error and op logs.
+const (
+	OpFindResourceACL   = "FindResourceACL"
+	OpPutResourceACL    = "PutResourceACL"
+	OpDeleteResourceACL = "DeleteResourceACL"
+)
+
+// ResourceAccess is the access a grant of a resource acl gives.
+type ResourceAccess string
+
+const (
+	// ResourceAccessView allows to read the resource.
+	ResourceAccessView ResourceAccess = "view"
+	// ResourceAccessEdit allows to read and write the resource.
+	ResourceAccessEdit ResourceAccess = "edit"
+)
+
+// Valid returns an error if the access is unknown.
+func (a ResourceAccess) Valid() error {
+	switch a {
+	case ResourceAccessView, ResourceAccessEdit:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid access %q; must be %s or %s", a, ResourceAccessView, ResourceAccessEdit),
+		}
+	}
+}
+
+// allows returns true if the access allows the action.
+func (a ResourceAccess) allows(action Action) bool {
+	return a == ResourceAccessEdit || (a == ResourceAccessView && action == ReadAction)
+}
+
+// ResourceACLService restricts single resources of an organization to the
+// users they are explicitly shared with, e.g. to limit an executive
+// dashboard to a few members of the organization.
+type ResourceACLService interface {
+	// FindResourceACL returns the acl of a resource. A resource that was
+	// never restricted has an acl without grants.
+	FindResourceACL(ctx context.Context, rt ResourceType, id ID) (*ResourceACL, error)
+
+	// PutResourceACL replaces the grants of the acl of a resource.
+	PutResourceACL(ctx context.Context, acl *ResourceACL) error
+
+	// DeleteResourceACL removes the acl of a resource, so that the resource
+	// is accessible with the permissions of the organization again.
+	DeleteResourceACL(ctx context.Context, rt ResourceType, id ID) error
+}
+
+// ResourceACL lists the users a resource is shared with. A resource with an
+// acl without grants is not restricted and accessible with the permissions
+// of the organization. A restricted resource is additionally only accessible
+// to the users of its grants.
+type ResourceACL struct {
+	ResourceType ResourceType    `json:"resourceType"`
+	ResourceID   ID              `json:"resourceID"`
+	Grants       []ResourceGrant `json:"grants"`
+	UpdatedAt    time.Time       `json:"updatedAt,omitempty"`
+}
+
+// ResourceGrant gives access to a resource to a user, or to every member of
+// the organization of the resource when UserID is not set.
+type ResourceGrant struct {
+	UserID *ID            `json:"userID,omitempty"`
+	Access ResourceAccess `json:"access"`
+}
+
+// ResourceACLResourceTypes are the types of resources that can be restricted with an acl.
+var ResourceACLResourceTypes = []ResourceType{
+	DashboardsResourceType,
+	NotebooksResourceType,
+}
+
+// Restricted returns true if the resource is only accessible to the users of
+// the grants.
+func (acl *ResourceACL) Restricted() bool {
+	return len(acl.Grants) > 0
+}
+
+// Allowed returns true if a grant of the acl allows the user the action on
+// the resource. Every user is allowed any action on a resource that is not
+// restricted.
+func (acl *ResourceACL) Allowed(userID ID, action Action) bool {
+	if !acl.Restricted() {
+		return true
+	}
+	for _, g := range acl.Grants {
+		if (g.UserID == nil || *g.UserID == userID) && g.Access.allows(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid returns an error if the resource type cannot be restricted or a
+// grant is invalid.
+func (acl *ResourceACL) Valid() error {
+	if err := ValidResourceACLResourceType(acl.ResourceType); err != nil {
+		return err
+	}
+	if !acl.ResourceID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "acl resource ID is invalid",
+		}
+	}
+
+	seen := make(map[ID]bool, len(acl.Grants))
+	for _, g := range acl.Grants {
+		if err := g.Access.Valid(); err != nil {
+			return err
+		}
+
+		var id ID
+		if g.UserID != nil {
+			if !g.UserID.Valid() {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "grant user ID is invalid",
+				}
+			}
+			id = *g.UserID
+		}
+		if seen[id] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "acl must not have more than one grant per user or for the organization",
+			}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ValidResourceACLResourceType returns an error if resources of the type
+// cannot be restricted with an acl.
+func ValidResourceACLResourceType(rt ResourceType) error {
+	for _, t := range ResourceACLResourceTypes {
+		if rt == t {
+			return nil
+		}
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("resources of type %q cannot be restricted", rt),
+	}
+}
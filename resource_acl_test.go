@@ -0,0 +1,114 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestResourceACL_Allowed(t *testing.T) {
+	viewer, editor, other := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	tests := []struct {
+		name   string
+		grants []influxdb.ResourceGrant
+		userID influxdb.ID
+		action influxdb.Action
+		want   bool
+	}{
+		{name: "not restricted", userID: other, action: influxdb.WriteAction, want: true},
+		{
+			name:   "view grant allows read",
+			grants: []influxdb.ResourceGrant{{UserID: &viewer, Access: influxdb.ResourceAccessView}},
+			userID: viewer,
+			action: influxdb.ReadAction,
+			want:   true,
+		},
+		{
+			name:   "view grant denies write",
+			grants: []influxdb.ResourceGrant{{UserID: &viewer, Access: influxdb.ResourceAccessView}},
+			userID: viewer,
+			action: influxdb.WriteAction,
+		},
+		{
+			name:   "edit grant allows write",
+			grants: []influxdb.ResourceGrant{{UserID: &editor, Access: influxdb.ResourceAccessEdit}},
+			userID: editor,
+			action: influxdb.WriteAction,
+			want:   true,
+		},
+		{
+			name:   "grant of another user",
+			grants: []influxdb.ResourceGrant{{UserID: &editor, Access: influxdb.ResourceAccessEdit}},
+			userID: other,
+			action: influxdb.ReadAction,
+		},
+		{
+			name: "organization grant",
+			grants: []influxdb.ResourceGrant{
+				{UserID: &editor, Access: influxdb.ResourceAccessEdit},
+				{Access: influxdb.ResourceAccessView},
+			},
+			userID: other,
+			action: influxdb.ReadAction,
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acl := &influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: 10, Grants: tt.grants}
+			if got := acl.Allowed(tt.userID, tt.action); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceACL_Valid(t *testing.T) {
+	userID := influxdb.ID(1)
+
+	tests := []struct {
+		name    string
+		acl     influxdb.ResourceACL
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			acl: influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: 10, Grants: []influxdb.ResourceGrant{
+				{UserID: &userID, Access: influxdb.ResourceAccessEdit},
+				{Access: influxdb.ResourceAccessView},
+			}},
+		},
+		{
+			name:    "unsupported resource type",
+			acl:     influxdb.ResourceACL{ResourceType: influxdb.BucketsResourceType, ResourceID: 10},
+			wantErr: true,
+		},
+		{
+			name: "invalid access",
+			acl: influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: 10, Grants: []influxdb.ResourceGrant{
+				{UserID: &userID, Access: "owner"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate user",
+			acl: influxdb.ResourceACL{ResourceType: influxdb.DashboardsResourceType, ResourceID: 10, Grants: []influxdb.ResourceGrant{
+				{UserID: &userID, Access: influxdb.ResourceAccessView},
+				{UserID: &userID, Access: influxdb.ResourceAccessEdit},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.acl.Valid()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("Valid() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EInvalid)
+			}
+		})
+	}
+}
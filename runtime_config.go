@@ -0,0 +1,136 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// RuntimeConfig is the subset of server settings that can be changed while
+// influxd is running.
+type RuntimeConfig struct {
+	// LogLevel is the minimum level of the server log, one of debug, info, warn or error.
+	LogLevel string `json:"logLevel"`
+	// QueryConcurrency is the number of queries allowed to execute concurrently.
+	// It may not exceed the concurrency the query controller was started with.
+	QueryConcurrency int `json:"queryConcurrency"`
+	// WriteMaxBodyBytes is the maximum size of a decompressed write request
+	// body. Zero means unlimited.
+	WriteMaxBodyBytes int64 `json:"writeMaxBodyBytes"`
+	// CompactionThroughput is the rate limit in bytes per second for TSM
+	// compactions. Zero removes the rate limit.
+	CompactionThroughput int64 `json:"compactionThroughput"`
+	// CompactionThroughputBurst is the burst size in bytes allowed by the
+	// compaction rate limit. Zero makes the burst equal to the throughput.
+	CompactionThroughputBurst int64 `json:"compactionThroughputBurst"`
+}
+
+// Valid returns an error if the runtime config is invalid.
+func (c RuntimeConfig) Valid() error {
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid log level %q; supported levels are debug, info, warn, and error", c.LogLevel),
+		}
+	}
+	if c.QueryConcurrency < 1 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query concurrency must be positive",
+		}
+	}
+	if c.WriteMaxBodyBytes < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "write max body bytes must not be negative",
+		}
+	}
+	if c.CompactionThroughput < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "compaction throughput must not be negative",
+		}
+	}
+	if c.CompactionThroughputBurst < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "compaction throughput burst must not be negative",
+		}
+	}
+	return nil
+}
+
+// RuntimeConfigUpdate is a partial update of the runtime config. Stored
+// updates are the overrides re-applied on top of the configured settings
+// when the server starts.
+type RuntimeConfigUpdate struct {
+	LogLevel                  *string `json:"logLevel,omitempty"`
+	QueryConcurrency          *int    `json:"queryConcurrency,omitempty"`
+	WriteMaxBodyBytes         *int64  `json:"writeMaxBodyBytes,omitempty"`
+	CompactionThroughput      *int64  `json:"compactionThroughput,omitempty"`
+	CompactionThroughputBurst *int64  `json:"compactionThroughputBurst,omitempty"`
+}
+
+// Apply sets the fields of the update on c.
+func (u RuntimeConfigUpdate) Apply(c *RuntimeConfig) {
+	if u.LogLevel != nil {
+		c.LogLevel = *u.LogLevel
+	}
+	if u.QueryConcurrency != nil {
+		c.QueryConcurrency = *u.QueryConcurrency
+	}
+	if u.WriteMaxBodyBytes != nil {
+		c.WriteMaxBodyBytes = *u.WriteMaxBodyBytes
+	}
+	if u.CompactionThroughput != nil {
+		c.CompactionThroughput = *u.CompactionThroughput
+	}
+	if u.CompactionThroughputBurst != nil {
+		c.CompactionThroughputBurst = *u.CompactionThroughputBurst
+	}
+}
+
+// Merge returns an update with the fields of other applied over u.
+func (u RuntimeConfigUpdate) Merge(other RuntimeConfigUpdate) RuntimeConfigUpdate {
+	if other.LogLevel != nil {
+		u.LogLevel = other.LogLevel
+	}
+	if other.QueryConcurrency != nil {
+		u.QueryConcurrency = other.QueryConcurrency
+	}
+	if other.WriteMaxBodyBytes != nil {
+		u.WriteMaxBodyBytes = other.WriteMaxBodyBytes
+	}
+	if other.CompactionThroughput != nil {
+		u.CompactionThroughput = other.CompactionThroughput
+	}
+	if other.CompactionThroughputBurst != nil {
+		u.CompactionThroughputBurst = other.CompactionThroughputBurst
+	}
+	return u
+}
+
+// RuntimeConfigService reads and changes the runtime configuration of the server.
+type RuntimeConfigService interface {
+	// FindRuntimeConfig returns the runtime config currently in effect.
+	FindRuntimeConfig(ctx context.Context) (*RuntimeConfig, error)
+
+	// UpdateRuntimeConfig applies the update to the running server and
+	// persists it so it is re-applied on restart.
+	UpdateRuntimeConfig(ctx context.Context, upd RuntimeConfigUpdate) (*RuntimeConfig, error)
+
+	// ResetRuntimeConfig removes the stored overrides and restores the
+	// configured settings.
+	ResetRuntimeConfig(ctx context.Context) (*RuntimeConfig, error)
+}
+
+// RuntimeConfigOverrideStore persists runtime config overrides.
+type RuntimeConfigOverrideStore interface {
+	// FindRuntimeConfigOverrides returns the stored overrides. An empty
+	// update is returned when none have been stored.
+	FindRuntimeConfigOverrides(ctx context.Context) (RuntimeConfigUpdate, error)
+
+	// PutRuntimeConfigOverrides replaces the stored overrides.
+	PutRuntimeConfigOverrides(ctx context.Context, upd RuntimeConfigUpdate) error
+}
@@ -0,0 +1,208 @@
+// Package sample samples the data of buckets, e.g. for the UI to preview the
+// shape of the data of a bucket during onboarding.
+package sample
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	fluxast "github.com/influxdata/influxdb/notification/flux"
+	"github.com/influxdata/influxdb/query"
+)
+
+var _ influxdb.BucketSampleService = (*Service)(nil)
+
+// Service implements influxdb.BucketSampleService with flux queries that run
+// with the authorization of the caller.
+type Service struct {
+	buckets influxdb.BucketService
+	qs      query.QueryService
+	now     func() time.Time
+}
+
+// NewService creates a service sampling the buckets of bs with the query
+// service.
+func NewService(bs influxdb.BucketService, qs query.QueryService) *Service {
+	return &Service{
+		buckets: bs,
+		qs:      qs,
+		now:     time.Now,
+	}
+}
+
+// SampleBucket returns the latest rows of the bucket, the latest first. The
+// rows are those of the last values of each series, with the values of the
+// fields of a series at the same time in the same row.
+func (s *Service) SampleBucket(ctx context.Context, bucketID influxdb.ID, opts influxdb.BucketSampleOptions) ([]*influxdb.BucketSampleRow, error) {
+	if err := opts.Valid(); err != nil {
+		return nil, err
+	}
+	n := opts.N
+	if n == 0 {
+		n = influxdb.DefaultBucketSampleSize
+	}
+
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := query.AuthorizationFromContext(ctx, b.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: b.OrgID,
+		Compiler: lang.ASTCompiler{
+			AST: sampleQuery(b.ID, opts.Measurement, n),
+			Now: s.now(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	var rows []*influxdb.BucketSampleRow
+	for it.More() {
+		if err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				rows = append(rows, readRows(cr)...)
+				// Only the latest rows are kept, so that sampling a bucket
+				// with many series does not hold the rows of all of them.
+				if len(rows) > 2*n {
+					rows = latest(rows, n)
+				}
+				return nil
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return latest(rows, n), nil
+}
+
+// latest returns the n latest rows, the latest first.
+func latest(rows []*influxdb.BucketSampleRow, n int) []*influxdb.BucketSampleRow {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Time.After(rows[j].Time)
+	})
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+// sampleQuery returns the query of the last n rows of each series of the
+// bucket, of the measurement if not nil, with the fields of the series as
+// columns.
+func sampleQuery(bucketID influxdb.ID, measurement *string, n int) *ast.Package {
+	calls := []*ast.CallExpression{
+		fluxast.Call(fluxast.Identifier("range"), fluxast.Object(
+			fluxast.Property("start", &ast.DateTimeLiteral{Value: time.Unix(0, models.MinNanoTime).UTC()}),
+		)),
+	}
+	if measurement != nil {
+		calls = append(calls, fluxast.Call(fluxast.Identifier("filter"), fluxast.Object(
+			fluxast.Property("fn", fluxast.Function(fluxast.FunctionParams("r"),
+				fluxast.Equal(fluxast.Member("r", "_measurement"), fluxast.String(*measurement)),
+			)),
+		)))
+	}
+	calls = append(calls,
+		fluxast.Call(fluxast.Identifier("tail"), fluxast.Object(fluxast.Property("n", fluxast.Integer(int64(n))))),
+		fluxast.Call(fluxast.Member("v1", "fieldsAsCols"), fluxast.Object()),
+	)
+
+	from := fluxast.Call(fluxast.Identifier("from"), fluxast.Object(fluxast.Property("bucketID", fluxast.String(bucketID.String()))))
+	f := fluxast.File("", fluxast.Imports("influxdata/influxdb/v1"), []ast.Statement{
+		fluxast.ExpressionStatement(fluxast.Pipe(from, calls...)),
+	})
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}
+}
+
+// readRows returns the rows of the series of the table, whose fields are
+// columns. The string columns of the group key are the tags of the series.
+func readRows(cr flux.ColReader) []*influxdb.BucketSampleRow {
+	key := cr.Key()
+	var measurement string
+	tags := make(map[string]string)
+	for j, c := range key.Cols() {
+		switch c.Label {
+		case execute.DefaultStartColLabel, execute.DefaultStopColLabel:
+		case "_measurement":
+			measurement = key.ValueString(j)
+		default:
+			if c.Type == flux.TString {
+				tags[c.Label] = key.ValueString(j)
+			}
+		}
+	}
+
+	t := execute.ColIdx(execute.DefaultTimeColLabel, cr.Cols())
+	if t < 0 {
+		return nil
+	}
+	times := cr.Times(t)
+
+	rows := make([]*influxdb.BucketSampleRow, 0, cr.Len())
+	for i := 0; i < cr.Len(); i++ {
+		if times.IsNull(i) {
+			continue
+		}
+		row := &influxdb.BucketSampleRow{
+			Time:        time.Unix(0, times.Value(i)).UTC(),
+			Measurement: measurement,
+			Tags:        tags,
+			Fields:      make(map[string]interface{}),
+		}
+		for j, c := range cr.Cols() {
+			if j == t || key.HasCol(c.Label) {
+				continue
+			}
+			if v, ok := value(cr, i, j); ok {
+				row.Fields[c.Label] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// value returns the value of the row i of the column j, and false if it is
+// null.
+func value(cr flux.ColReader, i, j int) (interface{}, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TString:
+		vs := cr.Strings(j)
+		return vs.ValueString(i), vs.IsValid(i)
+	case flux.TBool:
+		vs := cr.Bools(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TTime:
+		vs := cr.Times(j)
+		return time.Unix(0, vs.Value(i)).UTC(), vs.IsValid(i)
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,113 @@
+package sample_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/sample"
+)
+
+func TestService_SampleBucket(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(10)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	auth := &influxdb.Authorization{ID: 1000, OrgID: orgID, Status: influxdb.Active}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: orgID}, nil
+	}
+
+	cpu := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+		{Label: "_time", Type: flux.TTime},
+		{Label: "usage", Type: flux.TFloat},
+		{Label: "cores", Type: flux.TInt},
+	}
+	mem := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_time", Type: flux.TTime},
+		{Label: "used", Type: flux.TInt},
+	}
+	ts := func(m int) values.Time {
+		return values.ConvertTime(start.Add(time.Duration(m) * time.Minute))
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != orgID || req.Authorization != auth {
+				t.Fatalf("expected query in org %s with the authorization of the caller, got %s %v", orgID, req.OrganizationID, req.Authorization)
+			}
+			src := ast.Format(req.Compiler.(lang.ASTCompiler).AST.Files[0])
+			for _, want := range []string{`from(bucketID: "000000000000000a")`, `r._measurement == "cpu"`, `tail(n: 2)`, `v1.fieldsAsCols()`} {
+				if !strings.Contains(src, want) {
+					t.Fatalf("expected the query to contain %s, got %s", want, src)
+				}
+			}
+
+			r := executetest.NewResult([]*executetest.Table{
+				{KeyCols: []string{"_measurement", "host"}, ColMeta: cpu, Data: [][]interface{}{
+					{"cpu", "a", ts(1), 0.5, int64(4)},
+					{"cpu", "a", ts(3), 0.7, nil},
+				}},
+				{KeyCols: []string{"_measurement", "host"}, ColMeta: cpu, Data: [][]interface{}{
+					{"cpu", "b", ts(2), 0.1, int64(8)},
+				}},
+				{KeyCols: []string{"_measurement"}, ColMeta: mem, Data: [][]interface{}{
+					{"mem", ts(0), int64(1024)},
+				}},
+			})
+			return flux.NewSliceResultIterator([]flux.Result{r}), nil
+		},
+	}
+
+	s := sample.NewService(bs, qs)
+	ctx := icontext.SetAuthorizer(context.Background(), auth)
+	measurement := "cpu"
+	rows, err := s.SampleBucket(ctx, bucketID, influxdb.BucketSampleOptions{Measurement: &measurement, N: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*influxdb.BucketSampleRow{
+		{
+			Time:        start.Add(3 * time.Minute),
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "a"},
+			Fields:      map[string]interface{}{"usage": 0.7},
+		},
+		{
+			Time:        start.Add(2 * time.Minute),
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "b"},
+			Fields:      map[string]interface{}{"usage": 0.1, "cores": int64(8)},
+		},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expected the latest rows %+v, got %+v", want, rows)
+	}
+}
+
+func TestService_SampleBucket_Invalid(t *testing.T) {
+	s := sample.NewService(mock.NewBucketService(), &qmock.QueryService{})
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{})
+
+	for _, n := range []int{-1, influxdb.MaxBucketSampleSize + 1} {
+		if _, err := s.SampleBucket(ctx, 1, influxdb.BucketSampleOptions{N: n}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected a sample of %d rows to be invalid, got %v", n, err)
+		}
+	}
+}
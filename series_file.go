@@ -0,0 +1,68 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ops for series file errors.
+const (
+	OpFindSeriesFileStats = "FindSeriesFileStats"
+	OpCompactSeriesFile   = "CompactSeriesFile"
+	OpVerifySeriesFile    = "VerifySeriesFile"
+)
+
+// SeriesFileStats are the stats of the series file of the storage engine,
+// which maps the keys of all series to their IDs. Deleted series remain in
+// the index of a partition as tombstones until the index is compacted.
+type SeriesFileStats struct {
+	Series     uint64                     `json:"series"`
+	Tombstones uint64                     `json:"tombstones"`
+	DiskBytes  uint64                     `json:"diskBytes"`
+	Partitions []SeriesFilePartitionStats `json:"partitions"`
+}
+
+// SeriesFilePartitionStats are the stats of a partition of the series file.
+type SeriesFilePartitionStats struct {
+	ID             int     `json:"id"`
+	Series         uint64  `json:"series"`
+	Tombstones     uint64  `json:"tombstones"`
+	TombstoneRatio float64 `json:"tombstoneRatio"`
+	// DiskBytes is the size of the segments and the index of the partition.
+	DiskBytes uint64 `json:"diskBytes"`
+	Segments  int    `json:"segments"`
+	// Compacting is true while the index of the partition is compacted.
+	Compacting bool `json:"compacting"`
+}
+
+// SeriesFileVerification is the result of verifying the indexes of the
+// partitions of the series file against their segments.
+type SeriesFileVerification struct {
+	Valid      bool                              `json:"valid"`
+	Partitions []SeriesFilePartitionVerification `json:"partitions"`
+}
+
+// SeriesFilePartitionVerification is the result of verifying a partition.
+type SeriesFilePartitionVerification struct {
+	ID    int  `json:"id"`
+	Valid bool `json:"valid"`
+	// Entries is the number of segment entries verified.
+	Entries int `json:"entries"`
+	// Errors are the inconsistencies found, up to a limit.
+	Errors []string `json:"errors,omitempty"`
+	// Repaired is true if the index was rebuilt to repair the errors.
+	Repaired bool `json:"repaired"`
+}
+
+// SeriesFileService inspects and maintains the series file of the storage engine.
+type SeriesFileService interface {
+	// FindSeriesFileStats returns the stats of the series file.
+	FindSeriesFileStats(ctx context.Context) (*SeriesFileStats, error)
+
+	// CompactSeriesFile compacts the indexes of all partitions and waits for
+	// the compactions to finish.
+	CompactSeriesFile(ctx context.Context) error
+
+	// VerifySeriesFile verifies the partitions while the series file is in
+	// use, and rebuilds the indexes of inconsistent partitions if repair is true.
+	VerifySeriesFile(ctx context.Context, repair bool) (*SeriesFileVerification, error)
+}
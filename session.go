@@ -40,6 +40,9 @@ type Session struct {
 	ExpiresAt   time.Time    `json:"expiresAt"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions,omitempty"`
+	// Fingerprint identifies the client the session is bound to, if set.
+	// Requests of the session from other clients are rejected.
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // Expired returns an error if the session is expired.
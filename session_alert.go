@@ -0,0 +1,42 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for session alert errors.
+const (
+	OpCreateSessionAlert  = "CreateSessionAlert"
+	OpFindSessionAlerts   = "FindSessionAlerts"
+	OpDeleteSessionAlerts = "DeleteSessionAlerts"
+)
+
+// MaxSessionAlerts is the number of most recent session alerts kept per user.
+const MaxSessionAlerts = 20
+
+// SessionAlert reports a request with the key of a session of a user that was
+// rejected because it did not come from the client the session is bound to,
+// e.g. a stolen session cookie replayed from another network.
+type SessionAlert struct {
+	UserID    ID        `json:"userID"`
+	SessionID ID        `json:"sessionID"`
+	RemoteIP  string    `json:"remoteIP"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SessionAlertService stores the session alerts of users, so that users are
+// told when their sessions are used from other clients.
+type SessionAlertService interface {
+	// CreateSessionAlert records the alert for its user. Only the
+	// MaxSessionAlerts most recent alerts of a user are kept.
+	CreateSessionAlert(ctx context.Context, a *SessionAlert) error
+
+	// FindSessionAlerts returns the alerts of a user, the most recent first.
+	FindSessionAlerts(ctx context.Context, userID ID) ([]*SessionAlert, error)
+
+	// DeleteSessionAlerts removes the alerts of a user, e.g. once the user
+	// acknowledged them.
+	DeleteSessionAlerts(ctx context.Context, userID ID) error
+}
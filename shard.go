@@ -0,0 +1,74 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for shard errors.
+const (
+	OpFindShardGroupStats = "FindShardGroupStats"
+	OpFindCompactionPlan  = "FindCompactionPlan"
+)
+
+// ShardGroupStats are the stats of the data of a bucket with timestamps in
+// the time range of a shard group. The data of all buckets is stored in the
+// same files, so the stats are those of the blocks of the bucket.
+type ShardGroupStats struct {
+	BucketID  ID        `json:"bucketID"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	// DiskBytes is the size of the TSM blocks of the shard group on disk.
+	DiskBytes int64 `json:"diskBytes"`
+	// TSMFileCount is the number of TSM files holding blocks of the shard group.
+	TSMFileCount int `json:"tsmFileCount"`
+	// CompactionLevels is the number of TSM files holding blocks of the shard
+	// group by compaction level.
+	CompactionLevels map[int]int `json:"compactionLevels"`
+	// WALBytes is the size of the values of the shard group in the write
+	// ahead log that are not yet compacted into TSM files.
+	WALBytes int64 `json:"walBytes"`
+}
+
+// CompactionPlanGroup is a group of TSM files that would be compacted
+// together by the next compactions.
+type CompactionPlanGroup struct {
+	// Level is the level of the compaction: 1 to 3 for level compactions and
+	// 4 for full and optimize compactions.
+	Level    int  `json:"level"`
+	Optimize bool `json:"optimize"`
+	// Files are the names of the TSM files of the group.
+	Files []string `json:"files"`
+	// EstimatedBytes is the estimated size of the TSM files written by the
+	// compaction: the size of the files of the group, which is an upper
+	// bound since the values overwritten or deleted in the files are dropped.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+	// EstimatedFiles is the estimated number of TSM files written by the
+	// compaction.
+	EstimatedFiles int `json:"estimatedFiles"`
+}
+
+// ShardService reports the on-disk stats of the shard groups of buckets.
+type ShardService interface {
+	// FindShardGroupStats returns the stats of the shard groups of the bucket
+	// holding data, ordered by time.
+	FindShardGroupStats(ctx context.Context, bucketID ID) ([]*ShardGroupStats, error)
+
+	// FindCompactionPlan returns the compactions that would run next on the
+	// TSM files holding the data of the bucket, without running them. The
+	// files hold the data of all buckets of the organization.
+	FindCompactionPlan(ctx context.Context, bucketID ID) ([]*CompactionPlanGroup, error)
+}
+
+// DefaultShardGroupDuration returns the shard group duration of buckets with
+// the retention period that do not set a shard group duration.
+func DefaultShardGroupDuration(retention time.Duration) time.Duration {
+	switch {
+	case retention == InfiniteRetention, retention > 180*24*time.Hour:
+		return 7 * 24 * time.Hour
+	case retention >= 2*24*time.Hour:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// defaultOptimizationPollInterval is the default interval at which the
+// progress of the compactions of an optimization is checked.
+const defaultOptimizationPollInterval = 10 * time.Second
+
+// BucketOptimizer compacts the data of buckets.
+type BucketOptimizer interface {
+	BucketStatsReader
+	ScheduleBucketFullCompaction(ctx context.Context, orgID, bucketID influxdb.ID) error
+	OptimizeIndex(ctx context.Context) error
+}
+
+var _ influxdb.BucketOptimizationService = (*BucketOptimizationService)(nil)
+
+// BucketOptimizationServiceOption is a option you can use to modify the
+// BucketOptimizationService.
+type BucketOptimizationServiceOption func(*BucketOptimizationService)
+
+// WithOptimizationPollInterval sets the interval at which the progress of
+// the compactions of an optimization is checked.
+func WithOptimizationPollInterval(d time.Duration) BucketOptimizationServiceOption {
+	return func(s *BucketOptimizationService) {
+		if d > 0 {
+			s.pollInterval = d
+		}
+	}
+}
+
+// BucketOptimizationService optimizes buckets in maintenance windows. When
+// the window starts, a full compaction of the TSM files holding the bucket is
+// scheduled and the index is compacted. The optimization completes once the
+// blocks of every shard group of the bucket are in a single generation of
+// TSM files, and fails if the window ends first. The progress of
+// optimizations is kept in memory.
+type BucketOptimizationService struct {
+	logger  *zap.Logger
+	buckets influxdb.BucketService
+	engine  BucketOptimizer
+
+	pollInterval time.Duration
+
+	mu            sync.Mutex
+	optimizations map[influxdb.ID]*optimization
+}
+
+type optimization struct {
+	// progress is guarded by the mutex of the BucketOptimizationService.
+	progress influxdb.BucketOptimization
+	cancel   context.CancelFunc
+}
+
+// NewBucketOptimizationService returns a BucketOptimizationService
+// optimizing the buckets of bs in the engine.
+func NewBucketOptimizationService(logger *zap.Logger, bs influxdb.BucketService, engine BucketOptimizer, opts ...BucketOptimizationServiceOption) *BucketOptimizationService {
+	s := &BucketOptimizationService{
+		logger:        logger,
+		buckets:       bs,
+		engine:        engine,
+		pollInterval:  defaultOptimizationPollInterval,
+		optimizations: make(map[influxdb.ID]*optimization),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// OptimizeBucket schedules an optimization of the bucket in the maintenance
+// window [windowStart, windowStop). A zero windowStart starts it right away.
+func (s *BucketOptimizationService) OptimizeBucket(ctx context.Context, bucketID influxdb.ID, windowStart, windowStop time.Time) (*influxdb.BucketOptimization, error) {
+	now := time.Now().UTC()
+	if windowStart.IsZero() {
+		windowStart = now
+	}
+	if !windowStart.Before(windowStop) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpOptimizeBucket,
+			Msg:  "optimization window start must be before stop",
+		}
+	}
+	if !windowStop.After(now) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpOptimizeBucket,
+			Msg:  "optimization window must not be over",
+		}
+	}
+
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o, ok := s.optimizations[bucketID]; ok && !o.finished() {
+		return nil, influxdb.ErrBucketOptimizationInProgress
+	}
+
+	octx, cancel := context.WithCancel(context.Background())
+	o := &optimization{
+		progress: influxdb.BucketOptimization{
+			BucketID:    b.ID,
+			OrgID:       b.OrgID,
+			WindowStart: windowStart.UTC(),
+			WindowStop:  windowStop.UTC(),
+			Status:      influxdb.BucketOptimizationScheduled,
+			CreatedAt:   now,
+		},
+		cancel: cancel,
+	}
+	s.optimizations[bucketID] = o
+	go s.run(octx, o, shardGroupDuration(b))
+
+	progress := o.progress
+	return &progress, nil
+}
+
+// FindBucketOptimization returns the progress of the latest optimization of the bucket.
+func (s *BucketOptimizationService) FindBucketOptimization(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketOptimization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.optimizations[bucketID]
+	if !ok {
+		return nil, influxdb.ErrBucketOptimizationNotFound
+	}
+	progress := o.progress
+	return &progress, nil
+}
+
+// CancelBucketOptimization stops the optimization in progress of the bucket.
+func (s *BucketOptimizationService) CancelBucketOptimization(ctx context.Context, bucketID influxdb.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.optimizations[bucketID]
+	if !ok || o.finished() {
+		return influxdb.ErrBucketOptimizationNotFound
+	}
+	s.finish(o, influxdb.BucketOptimizationCanceled, "")
+	return nil
+}
+
+func shardGroupDuration(b *influxdb.Bucket) time.Duration {
+	if b.ShardGroupDuration > 0 {
+		return b.ShardGroupDuration
+	}
+	return influxdb.DefaultShardGroupDuration(b.RetentionPeriod)
+}
+
+// run waits for the window to start, starts the compactions and then tracks
+// them until every shard group of the bucket is compacted.
+func (s *BucketOptimizationService) run(ctx context.Context, o *optimization, window time.Duration) {
+	orgID, bucketID := o.progress.OrgID, o.progress.BucketID
+	logger := s.logger.With(zap.String("bucket_id", bucketID.String()))
+
+	start, stop := o.progress.WindowStart, o.progress.WindowStop
+	if d := time.Until(start); d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+	ctx, cancel := context.WithDeadline(ctx, stop)
+	defer cancel()
+
+	fail := func(err error) {
+		logger.Info("Failed to optimize bucket", zap.Error(err))
+		s.mu.Lock()
+		s.finish(o, influxdb.BucketOptimizationFailed, err.Error())
+		s.mu.Unlock()
+	}
+
+	// the shard groups written to after the window started are not waited for.
+	stats, err := s.engine.BucketWindowStats(ctx, orgID, bucketID, window)
+	if err != nil {
+		fail(err)
+		return
+	}
+	shardGroups := make(map[int64]struct{}, len(stats))
+	for _, w := range stats {
+		shardGroups[w.Min] = struct{}{}
+	}
+	s.update(o, func(p *influxdb.BucketOptimization) {
+		now := time.Now().UTC()
+		p.Status = influxdb.BucketOptimizationRunning
+		p.StartedAt = &now
+		p.ShardGroups = len(shardGroups)
+	})
+
+	if err := s.engine.ScheduleBucketFullCompaction(ctx, orgID, bucketID); err != nil {
+		fail(err)
+		return
+	}
+	if err := s.engine.OptimizeIndex(ctx); err != nil {
+		if ctx.Err() == nil {
+			fail(err)
+			return
+		}
+	} else {
+		s.update(o, func(p *influxdb.BucketOptimization) { p.IndexOptimized = true })
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		if ctx.Err() == context.DeadlineExceeded {
+			s.mu.Lock()
+			s.finish(o, influxdb.BucketOptimizationFailed, "optimization window ended before the bucket was optimized")
+			s.mu.Unlock()
+			return
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		stats, err := s.engine.BucketWindowStats(ctx, orgID, bucketID, window)
+		if err != nil && ctx.Err() == nil {
+			fail(err)
+			return
+		}
+		if err == nil {
+			optimized := len(shardGroups)
+			for _, w := range stats {
+				if _, ok := shardGroups[w.Min]; ok && w.Generations > 1 {
+					optimized--
+				}
+			}
+			s.mu.Lock()
+			o.progress.OptimizedShardGroups = optimized
+			if optimized == len(shardGroups) && o.progress.IndexOptimized {
+				s.finish(o, influxdb.BucketOptimizationCompleted, "")
+			}
+			s.mu.Unlock()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (s *BucketOptimizationService) update(o *optimization, fn func(*influxdb.BucketOptimization)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&o.progress)
+}
+
+func (o *optimization) finished() bool {
+	return o.progress.FinishedAt != nil
+}
+
+// finish must be called with the mutex of the BucketOptimizationService
+// held. Only the first status an optimization finishes with is kept.
+func (s *BucketOptimizationService) finish(o *optimization, status, msg string) {
+	if o.finished() {
+		return
+	}
+	now := time.Now().UTC()
+	o.progress.Status = status
+	o.progress.FinishedAt = &now
+	o.progress.Error = msg
+	o.cancel()
+}
@@ -0,0 +1,151 @@
+package storage_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap/zaptest"
+)
+
+// bucketOptimizer compacts the second shard group into a single generation
+// when a full compaction is scheduled, unless stuck is set.
+type bucketOptimizer struct {
+	mu        sync.Mutex
+	scheduled bool
+	stuck     bool
+}
+
+func (o *bucketOptimizer) BucketWindowStats(ctx context.Context, orgID, bucketID influxdb.ID, window time.Duration) ([]tsm1.WindowStats, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	gens := 3
+	if o.scheduled && !o.stuck {
+		gens = 1
+	}
+	return []tsm1.WindowStats{
+		{Min: 0, Max: int64(window), Files: 1, Generations: 1},
+		{Min: int64(window), Max: 2 * int64(window), Files: gens, Generations: gens},
+	}, nil
+}
+
+func (o *bucketOptimizer) ScheduleBucketFullCompaction(ctx context.Context, orgID, bucketID influxdb.ID) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.scheduled = true
+	return nil
+}
+
+func (o *bucketOptimizer) OptimizeIndex(ctx context.Context) error { return nil }
+
+func (o *bucketOptimizer) isScheduled() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.scheduled
+}
+
+func waitForOptimization(t *testing.T, s influxdb.BucketOptimizationService, bucketID influxdb.ID) *influxdb.BucketOptimization {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		o, err := s.FindBucketOptimization(context.Background(), bucketID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o.FinishedAt != nil {
+			return o
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("optimization did not finish: %+v", o)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBucketOptimizationService(t *testing.T) {
+	ctx := context.Background()
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: 1, ShardGroupDuration: time.Hour}, nil
+	}
+
+	t.Run("completes", func(t *testing.T) {
+		e := &bucketOptimizer{}
+		s := storage.NewBucketOptimizationService(zaptest.NewLogger(t), bs, e, storage.WithOptimizationPollInterval(time.Millisecond))
+
+		o, err := s.OptimizeBucket(ctx, 2, time.Time{}, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o.BucketID != 2 || o.OrgID != 1 {
+			t.Fatalf("unexpected optimization %+v", o)
+		}
+
+		o = waitForOptimization(t, s, 2)
+		if o.Status != influxdb.BucketOptimizationCompleted || o.ShardGroups != 2 || o.OptimizedShardGroups != 2 || !o.IndexOptimized || o.StartedAt == nil {
+			t.Fatalf("unexpected optimization %+v", o)
+		}
+	})
+
+	t.Run("window ends", func(t *testing.T) {
+		e := &bucketOptimizer{stuck: true}
+		s := storage.NewBucketOptimizationService(zaptest.NewLogger(t), bs, e, storage.WithOptimizationPollInterval(time.Millisecond))
+
+		if _, err := s.OptimizeBucket(ctx, 2, time.Time{}, time.Now().Add(100*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.OptimizeBucket(ctx, 2, time.Time{}, time.Now().Add(time.Minute)); influxdb.ErrorCode(err) != influxdb.EConflict {
+			t.Fatalf("expected optimization in progress to conflict, got %v", err)
+		}
+
+		o := waitForOptimization(t, s, 2)
+		if o.Status != influxdb.BucketOptimizationFailed || o.ShardGroups != 2 || o.OptimizedShardGroups != 1 || o.Error == "" {
+			t.Fatalf("unexpected optimization %+v", o)
+		}
+	})
+
+	t.Run("cancel scheduled", func(t *testing.T) {
+		e := &bucketOptimizer{}
+		s := storage.NewBucketOptimizationService(zaptest.NewLogger(t), bs, e)
+
+		start := time.Now().Add(time.Hour)
+		o, err := s.OptimizeBucket(ctx, 2, start, start.Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o.Status != influxdb.BucketOptimizationScheduled {
+			t.Fatalf("expected optimization to be scheduled, got %+v", o)
+		}
+
+		if err := s.CancelBucketOptimization(ctx, 2); err != nil {
+			t.Fatal(err)
+		}
+		o = waitForOptimization(t, s, 2)
+		if o.Status != influxdb.BucketOptimizationCanceled || e.isScheduled() {
+			t.Fatalf("unexpected optimization %+v", o)
+		}
+		if err := s.CancelBucketOptimization(ctx, 2); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Fatalf("expected no optimization in progress, got %v", err)
+		}
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		s := storage.NewBucketOptimizationService(zaptest.NewLogger(t), bs, &bucketOptimizer{})
+
+		now := time.Now()
+		if _, err := s.OptimizeBucket(ctx, 2, now, now.Add(-time.Hour)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("expected inverted window to be invalid, got %v", err)
+		}
+		if _, err := s.OptimizeBucket(ctx, 2, now.Add(-2*time.Hour), now.Add(-time.Hour)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("expected past window to be invalid, got %v", err)
+		}
+		if _, err := s.FindBucketOptimization(ctx, 2); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Fatalf("expected no optimization, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// DefaultSchemaCacheTTL is the default time the schema of a bucket is cached.
+const DefaultSchemaCacheTTL = time.Minute
+
+// SchemaReader reads the tag values of the series of buckets.
+type SchemaReader interface {
+	TagValues(ctx context.Context, orgID, bucketID influxdb.ID, tagKey string, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error)
+}
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaServiceOption is a option you can use to modify the
+// BucketSchemaService.
+type BucketSchemaServiceOption func(*BucketSchemaService)
+
+// WithSchemaCacheTTL sets the time the schema of a bucket is cached. The
+// schema is not cached if it is 0.
+func WithSchemaCacheTTL(d time.Duration) BucketSchemaServiceOption {
+	return func(s *BucketSchemaService) {
+		if d >= 0 {
+			s.ttl = d
+		}
+	}
+}
+
+// BucketSchemaService reports the measurements, fields and tag values of
+// buckets from the index of the engine. Scanning the series of a bucket is
+// expensive when its cardinality is high, so the results are cached in
+// memory for the TTL of the service: series written since may be missing
+// until they expire.
+type BucketSchemaService struct {
+	buckets influxdb.BucketService
+	engine  SchemaReader
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[schemaKey]*schemaEntry
+	sweepAt time.Time
+}
+
+// schemaKey identifies the values of a tag key of the series of a bucket,
+// of the measurement if byMeasurement is set.
+type schemaKey struct {
+	bucketID      influxdb.ID
+	tagKey        string
+	byMeasurement bool
+	measurement   string
+}
+
+type schemaEntry struct {
+	values  []string
+	expires time.Time
+}
+
+// NewBucketSchemaService returns a BucketSchemaService reporting the schema
+// of the buckets of bs from the engine.
+func NewBucketSchemaService(bs influxdb.BucketService, engine SchemaReader, opts ...BucketSchemaServiceOption) *BucketSchemaService {
+	s := &BucketSchemaService{
+		buckets: bs,
+		engine:  engine,
+		ttl:     DefaultSchemaCacheTTL,
+		now:     time.Now,
+		entries: make(map[schemaKey]*schemaEntry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// FindMeasurements returns the measurements of the bucket.
+func (s *BucketSchemaService) FindMeasurements(ctx context.Context, bucketID influxdb.ID) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.tagValues(ctx, influxdb.OpFindMeasurements, bucketID, models.MeasurementTagKey, nil)
+}
+
+// FindFields returns the field keys of the bucket, of a measurement if the
+// filter sets one.
+func (s *BucketSchemaService) FindFields(ctx context.Context, bucketID influxdb.ID, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.tagValues(ctx, influxdb.OpFindFields, bucketID, models.FieldKeyTagKey, filter.Measurement)
+}
+
+// FindTagValues returns the values of the tag key in the bucket, of a
+// measurement if the filter sets one. The _measurement and _field keys are
+// those of the measurements and field keys.
+func (s *BucketSchemaService) FindTagValues(ctx context.Context, bucketID influxdb.ID, key string, filter influxdb.BucketSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	switch key {
+	case "":
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpFindTagValues,
+			Msg:  "tag key is required",
+		}
+	case "_measurement":
+		key = models.MeasurementTagKey
+	case "_field":
+		key = models.FieldKeyTagKey
+	}
+	return s.tagValues(ctx, influxdb.OpFindTagValues, bucketID, key, filter.Measurement)
+}
+
+// tagValues returns the values of the tag key of the series of the bucket,
+// of the measurement if not nil, from the cache if they did not expire.
+func (s *BucketSchemaService) tagValues(ctx context.Context, op string, bucketID influxdb.ID, tagKey string, measurement *string) ([]string, error) {
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := schemaKey{bucketID: b.ID, tagKey: tagKey}
+	if measurement != nil {
+		key.byMeasurement, key.measurement = true, *measurement
+	}
+	if vs, ok := s.cached(key); ok {
+		return vs, nil
+	}
+
+	var predicate influxql.Expr
+	if measurement != nil {
+		predicate = &influxql.BinaryExpr{
+			Op:  influxql.EQ,
+			LHS: &influxql.VarRef{Val: models.MeasurementTagKey},
+			RHS: &influxql.StringLiteral{Val: *measurement},
+		}
+	}
+	it, err := s.engine.TagValues(ctx, b.OrgID, b.ID, tagKey, math.MinInt64, math.MaxInt64, predicate)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  "unable to read the schema of the bucket",
+			Err:  err,
+		}
+	}
+	vs := []string{}
+	for it.Next() {
+		vs = append(vs, it.Value())
+	}
+
+	s.store(key, vs)
+	return vs, nil
+}
+
+func (s *BucketSchemaService) cached(key schemaKey) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expires) {
+		return nil, false
+	}
+	return e.values, true
+}
+
+func (s *BucketSchemaService) store(key schemaKey, vs []string) {
+	if s.ttl == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	s.entries[key] = &schemaEntry{values: vs, expires: now.Add(s.ttl)}
+}
+
+// sweep removes expired entries at most once per TTL. It must be called
+// with the mutex held.
+func (s *BucketSchemaService) sweep(now time.Time) {
+	if now.Before(s.sweepAt) {
+		return
+	}
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.sweepAt = now.Add(s.ttl)
+}
@@ -0,0 +1,109 @@
+package storage_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+type schemaReader struct {
+	values map[string][]string
+	calls  []string
+}
+
+func (r *schemaReader) TagValues(ctx context.Context, orgID, bucketID influxdb.ID, tagKey string, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	call := tagKey
+	if predicate != nil {
+		call += " " + predicate.String()
+	}
+	r.calls = append(r.calls, call)
+	return cursors.NewStringSliceIterator(r.values[tagKey]), nil
+}
+
+func TestBucketSchemaService(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: 1}, nil
+	}
+	r := &schemaReader{values: map[string][]string{
+		models.MeasurementTagKey: {"cpu", "mem"},
+		models.FieldKeyTagKey:    {"usage_idle", "usage_user"},
+		"host":                   {"a", "b"},
+	}}
+	s := storage.NewBucketSchemaService(bs, r)
+	ctx := context.Background()
+	cpu := "cpu"
+
+	for i := 0; i < 2; i++ {
+		ms, err := s.FindMeasurements(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"cpu", "mem"}; !reflect.DeepEqual(ms, want) {
+			t.Fatalf("expected measurements %v, got %v", want, ms)
+		}
+		fs, err := s.FindFields(ctx, 2, influxdb.BucketSchemaFilter{Measurement: &cpu})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"usage_idle", "usage_user"}; !reflect.DeepEqual(fs, want) {
+			t.Fatalf("expected fields %v, got %v", want, fs)
+		}
+		vs, err := s.FindTagValues(ctx, 2, "host", influxdb.BucketSchemaFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"a", "b"}; !reflect.DeepEqual(vs, want) {
+			t.Fatalf("expected tag values %v, got %v", want, vs)
+		}
+	}
+	if _, err := s.FindTagValues(ctx, 2, "_measurement", influxdb.BucketSchemaFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FindTagValues(ctx, 3, "host", influxdb.BucketSchemaFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		models.MeasurementTagKey,
+		models.FieldKeyTagKey + " \"\x00\" = 'cpu'",
+		"host",
+		"host",
+	}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Fatalf("expected the schema to be read once per bucket and key, got %q", r.calls)
+	}
+
+	if _, err := s.FindTagValues(ctx, 2, "", influxdb.BucketSchemaFilter{}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected a missing tag key to be invalid, got %v", err)
+	}
+}
+
+func TestBucketSchemaService_NoCache(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: 1}, nil
+	}
+	r := &schemaReader{}
+	s := storage.NewBucketSchemaService(bs, r, storage.WithSchemaCacheTTL(0))
+
+	for i := 0; i < 2; i++ {
+		ms, err := s.FindMeasurements(context.Background(), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ms == nil || len(ms) != 0 {
+			t.Fatalf("expected no measurements, got %v", ms)
+		}
+	}
+	if len(r.calls) != 2 {
+		t.Fatalf("expected the schema to be read by every call without a cache, got %d reads", len(r.calls))
+	}
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// BucketBlockStatsReader reports the stats of the TSM blocks of a bucket by measurement.
+type BucketBlockStatsReader interface {
+	BucketBlockStats(ctx context.Context, orgID, bucketID influxdb.ID) (map[string]*tsm1.BlockStats, error)
+}
+
+// StatisticsCollector collects the statistics of the measurements of buckets
+// that the query planner consults, and persists them so that they are
+// available when the server restarts.
+type StatisticsCollector struct {
+	Engine        BucketBlockStatsReader
+	BucketService BucketFinder
+	Store         influxdb.BucketStatisticsStore
+
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewStatisticsCollector returns a collector of the statistics of the
+// buckets of bs read from the engine.
+func NewStatisticsCollector(engine BucketBlockStatsReader, bs BucketFinder, store influxdb.BucketStatisticsStore) *StatisticsCollector {
+	return &StatisticsCollector{
+		Engine:        engine,
+		BucketService: bs,
+		Store:         store,
+		logger:        zap.NewNop(),
+		now:           time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the collector. It must be called before Run.
+func (c *StatisticsCollector) WithLogger(l *zap.Logger) {
+	c.logger = l.With(zap.String("component", "statistics_collector"))
+}
+
+// Run collects the statistics of all buckets immediately and then every
+// interval until ctx is canceled.
+func (c *StatisticsCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Collect(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Error("Unable to collect bucket statistics", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect collects and stores the statistics of all buckets. The statistics
+// of a bucket are replaced only when they were collected successfully.
+func (c *StatisticsCollector) Collect(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	log, logEnd := logger.NewOperation(ctx, c.logger, "Bucket statistics collection", "bucket_statistics_collection")
+	defer logEnd()
+
+	bctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
+	buckets, _, err := c.BucketService.FindBuckets(bctx, influxdb.BucketFilter{})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, b := range buckets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.collectBucket(ctx, b); err != nil {
+			log.Warn("Unable to collect statistics of bucket",
+				zap.Stringer("org_id", b.OrgID),
+				zap.Stringer("bucket_id", b.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (c *StatisticsCollector) collectBucket(ctx context.Context, b *influxdb.Bucket) error {
+	blocks, err := c.Engine.BucketBlockStats(ctx, b.OrgID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	stats := &influxdb.BucketStatistics{
+		BucketID:     b.ID,
+		CollectedAt:  c.now().UTC(),
+		Measurements: make(map[string]influxdb.MeasurementStatistics, len(blocks)),
+	}
+	for name, s := range blocks {
+		stats.Measurements[name] = influxdb.MeasurementStatistics{
+			Series:  s.Series,
+			Blocks:  s.Blocks,
+			MinTime: s.MinTime,
+			MaxTime: s.MaxTime,
+		}
+	}
+	return c.Store.PutBucketStatistics(ctx, stats)
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+type testBlockStatsReader map[influxdb.ID]map[string]*tsm1.BlockStats
+
+func (r testBlockStatsReader) BucketBlockStats(_ context.Context, _, bucketID influxdb.ID) (map[string]*tsm1.BlockStats, error) {
+	stats, ok := r[bucketID]
+	if !ok {
+		return nil, errors.New("bucket failed")
+	}
+	return stats, nil
+}
+
+type testStatisticsStore map[influxdb.ID]*influxdb.BucketStatistics
+
+func (s testStatisticsStore) FindBucketStatistics(_ context.Context, bucketID influxdb.ID) (*influxdb.BucketStatistics, error) {
+	stats, ok := s[bucketID]
+	if !ok {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound}
+	}
+	return stats, nil
+}
+
+func (s testStatisticsStore) PutBucketStatistics(_ context.Context, stats *influxdb.BucketStatistics) error {
+	s[stats.BucketID] = stats
+	return nil
+}
+
+func TestStatisticsCollector_Collect(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	engine := testBlockStatsReader{
+		1: {
+			"cpu": {Series: 10, Blocks: 40, MinTime: 100, MaxTime: 200},
+			"mem": {Series: 2, Blocks: 1, MinTime: 150, MaxTime: 150},
+		},
+		2: {},
+	}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 20},
+		}, 3, nil
+	}
+	store := testStatisticsStore{
+		3: {BucketID: 3, CollectedAt: now.Add(-time.Hour)},
+	}
+
+	c := NewStatisticsCollector(engine, finder, store)
+	c.now = func() time.Time { return now }
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := testStatisticsStore{
+		1: {
+			BucketID:    1,
+			CollectedAt: now,
+			Measurements: map[string]influxdb.MeasurementStatistics{
+				"cpu": {Series: 10, Blocks: 40, MinTime: 100, MaxTime: 200},
+				"mem": {Series: 2, Blocks: 1, MinTime: 150, MaxTime: 150},
+			},
+		},
+		2: {BucketID: 2, CollectedAt: now, Measurements: map[string]influxdb.MeasurementStatistics{}},
+		// The statistics of a bucket that failed to collect are kept.
+		3: {BucketID: 3, CollectedAt: now.Add(-time.Hour)},
+	}
+	if !reflect.DeepEqual(store, exp) {
+		t.Fatalf("got %#v, expected %#v", store, exp)
+	}
+}
+
+func TestStatisticsCollector_Collect_FindBucketsError(t *testing.T) {
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return nil, 0, errors.New("find failed")
+	}
+
+	c := NewStatisticsCollector(testBlockStatsReader{}, finder, testStatisticsStore{})
+	if err := c.Collect(context.Background()); err == nil || err.Error() != "find failed" {
+		t.Fatalf("got error %v, expected find failed", err)
+	}
+}
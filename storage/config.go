@@ -38,6 +38,11 @@ type Config struct {
 	Engine     tsm1.Config `toml:"engine"`
 	EnginePath string      `toml:"engine-path"` // Overrides the default path.
 
+	// PartitionByOrganization stores the WAL and TSM files of every
+	// organization in separate directories, compacted separately. It cannot
+	// be changed once data has been written.
+	PartitionByOrganization bool `toml:"partition-by-organization"`
+
 	// Index config.
 	Index     tsi1.Config `toml:"index"`
 	IndexPath string      `toml:"index-path"` // Overrides the default path.
@@ -50,8 +50,14 @@ type Engine struct {
 	closing chan struct{} //closing returns the zero value when the engine is shutting down.
 	index   *tsi1.Index
 	sfile   *tsdb.SeriesFile
-	engine  *tsm1.Engine
-	wal     *wal.WAL
+
+	// partitions hold the WAL and TSM engine of the data of each
+	// organization, or of all data when the engine is not partitioned.
+	partitionsMu sync.RWMutex
+	partitions   map[platform.ID]*partition
+
+	tsmOptions           []func(*tsm1.Engine) // Applied to the TSM engine of every partition.
+	compactionThroughput *[2]int              // Applied to the TSM engine of every partition.
 
 	retentionEnforcer        runner
 	retentionEnforcerLimiter runnable
@@ -71,14 +77,14 @@ type Option func(*Engine)
 // how TSM files are named.
 func WithTSMFilenameFormatter(fn tsm1.FormatFileNameFunc) Option {
 	return func(e *Engine) {
-		e.engine.WithFormatFileNameFunc(fn)
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.WithFormatFileNameFunc(fn) })
 	}
 }
 
 // WithCurrentGenerationFunc sets a function for obtaining the current generation.
 func WithCurrentGenerationFunc(fn func() int) Option {
 	return func(e *Engine) {
-		e.engine.WithCurrentGenerationFunc(fn)
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.WithCurrentGenerationFunc(fn) })
 	}
 }
 
@@ -105,7 +111,7 @@ func WithNodeID(id int) Option {
 // metrics are labelled correctly.
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
-		e.retentionEnforcer = newRetentionEnforcer(e, e.engine, finder)
+		e.retentionEnforcer = newRetentionEnforcer(e, e, finder)
 	}
 }
 
@@ -122,14 +128,19 @@ func WithRetentionEnforcerLimiter(f runnable) Option {
 // WithFileStoreObserver makes the engine have the provided file store observer.
 func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
 	return func(e *Engine) {
-		e.engine.WithFileStoreObserver(obs)
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.WithFileStoreObserver(obs) })
 	}
 }
 
 // WithCompactionPlanner makes the engine have the provided compaction planner.
+// A planner plans the compactions of a single TSM engine, so it is ignored
+// when the engine is partitioned by organization.
 func WithCompactionPlanner(planner tsm1.CompactionPlanner) Option {
 	return func(e *Engine) {
-		e.engine.WithCompactionPlanner(planner)
+		if e.config.PartitionByOrganization {
+			return
+		}
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.WithCompactionPlanner(planner) })
 	}
 }
 
@@ -138,7 +149,7 @@ func WithCompactionPlanner(planner tsm1.CompactionPlanner) Option {
 // share the same limiter.
 func WithCompactionLimiter(limiter limiter.Fixed) Option {
 	return func(e *Engine) {
-		e.engine.WithCompactionLimiter(limiter)
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.WithCompactionLimiter(limiter) })
 	}
 }
 
@@ -146,7 +157,7 @@ func WithCompactionLimiter(limiter limiter.Fixed) Option {
 // across multiple storage engines.
 func WithCompactionSemaphore(s influxdb.Semaphore) Option {
 	return func(e *Engine) {
-		e.engine.SetSemaphore(s)
+		e.tsmOptions = append(e.tsmOptions, func(engine *tsm1.Engine) { engine.SetSemaphore(s) })
 	}
 }
 
@@ -156,6 +167,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e := &Engine{
 		config:              c,
 		path:                path,
+		partitions:          make(map[platform.ID]*partition),
 		defaultMetricLabels: prometheus.Labels{},
 		logger:              zap.NewNop(),
 	}
@@ -163,18 +175,22 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	// Initialize series file.
 	e.sfile = tsdb.NewSeriesFile(c.GetSeriesFilePath(path))
 	e.sfile.LargeWriteThreshold = c.TSDB.LargeSeriesWriteThreshold
+	e.sfile.CompactThreshold = c.TSDB.SeriesFileCompactThreshold
+	e.sfile.TombstoneCompactThreshold = c.TSDB.SeriesFileTombstoneCompactThreshold
+	e.sfile.MaxConcurrentCompactions = c.TSDB.SeriesFileMaxConcurrentCompactions
 
 	// Initialise index.
 	e.index = tsi1.NewIndex(e.sfile, c.Index,
 		tsi1.WithPath(c.GetIndexPath(path)))
 
-	// Initialize WAL
-	e.wal = wal.NewWAL(c.GetWALPath(path))
-	e.wal.WithFsyncDelay(time.Duration(c.WAL.FsyncDelay))
-	e.wal.SetEnabled(c.WAL.Enabled)
+	// The WAL and TSM engine of every partition are initialised when the
+	// engine is opened, or when the first data of an organization is written.
 
-	// Initialise Engine
-	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine, tsm1.WithSnapshotter(e))
+	// All partitions share the block cache, so that its size bounds the
+	// memory used to cache the blocks of the engine.
+	if c.Engine.BlockCacheMaxSize > 0 {
+		e.tsmOptions = append(e.tsmOptions, tsm1.WithBlockCache(tsm1.NewBlockCache(uint64(c.Engine.BlockCacheMaxSize))))
+	}
 
 	// Apply options.
 	for _, option := range options {
@@ -182,10 +198,8 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	}
 
 	// Set default metrics labels.
-	e.engine.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.sfile.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.index.SetDefaultMetricLabels(e.defaultMetricLabels)
-	e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
 		r.SetDefaultMetricLabels(e.defaultMetricLabels)
 	}
@@ -208,8 +222,6 @@ func (e *Engine) WithLogger(log *zap.Logger) {
 	e.logger = log.With(fields...)
 	e.sfile.WithLogger(e.logger)
 	e.index.WithLogger(e.logger)
-	e.engine.WithLogger(e.logger)
-	e.wal.WithLogger(e.logger)
 	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
 		r.WithLogger(e.logger)
 	}
@@ -244,13 +256,21 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	var oh openHelper
 	oh.Open(ctx, e.sfile)
 	oh.Open(ctx, e.index)
-	oh.Open(ctx, e.wal)
-	oh.Open(ctx, e.engine)
 	if err := oh.Done(); err != nil {
 		return err
 	}
 
-	if err := e.replayWAL(); err != nil {
+	// Initialise the metrics of the partitions, so that they can be
+	// registered before the first partition is opened.
+	labels := e.partitionMetricLabels(0)
+	tsm1.InitMetrics(labels)
+	wal.InitMetrics(labels)
+
+	if err := e.openPartitions(ctx); err != nil {
+		var ch closeHelper
+		ch.Close(e.index)
+		ch.Close(e.sfile)
+		ch.Done()
 		return err
 	}
 
@@ -266,14 +286,14 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	return nil
 }
 
-// replayWAL reads the WAL segment files and replays them.
-func (e *Engine) replayWAL() error {
+// replayWAL reads the WAL segment files of the partition and replays them.
+func (e *Engine) replayWAL(p *partition) error {
 	if !e.config.WAL.Enabled {
 		return nil
 	}
 	now := time.Now()
 
-	walPaths, err := wal.SegmentFileNames(e.wal.Path())
+	walPaths, err := wal.SegmentFileNames(p.wal.Path())
 	if err != nil {
 		return err
 	}
@@ -282,9 +302,9 @@ func (e *Engine) replayWAL() error {
 	// OOM situations when reloading huge WALs.
 
 	// Disable the max size during loading
-	limit := e.engine.Cache.MaxSize()
-	defer func() { e.engine.Cache.SetMaxSize(limit) }()
-	e.engine.Cache.SetMaxSize(0)
+	limit := p.engine.Cache.MaxSize()
+	defer func() { p.engine.Cache.SetMaxSize(limit) }()
+	p.engine.Cache.SetMaxSize(0)
 
 	// Execute all the entries in the WAL again
 	reader := wal.NewWALReader(walPaths)
@@ -293,7 +313,7 @@ func (e *Engine) replayWAL() error {
 		switch en := entry.(type) {
 		case *wal.WriteWALEntry:
 			points := tsm1.ValuesToPoints(en.Values)
-			err := e.writePointsLocked(context.Background(), tsdb.NewSeriesCollection(points), en.Values)
+			err := e.writePointsLocked(context.Background(), tsdb.NewSeriesCollection(points), p, en.Values)
 			if _, ok := err.(tsdb.PartialWriteError); ok {
 				err = nil
 			}
@@ -308,14 +328,14 @@ func (e *Engine) replayWAL() error {
 				}
 			}
 
-			return e.deleteBucketRangeLocked(context.Background(), en.OrgID, en.BucketID, en.Min, en.Max, pred)
+			return deleteBucketRangeLocked(context.Background(), p, en.OrgID, en.BucketID, en.Min, en.Max, pred)
 		}
 
 		return nil
 	})
 
 	e.logger.Info("Reloaded WAL",
-		zap.String("path", e.wal.Path()),
+		zap.String("path", p.wal.Path()),
 		zap.Duration("duration", time.Since(now)),
 		zap.Error(err))
 
@@ -410,8 +430,7 @@ func (e *Engine) Close() error {
 	e.closing = nil
 
 	var ch closeHelper
-	ch.Close(e.engine)
-	ch.Close(e.wal)
+	ch.Close(closerFunc(e.closePartitions))
 	ch.Close(e.index)
 	ch.Close(e.sfile)
 	return ch.Done()
@@ -435,7 +454,10 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 	if e.closing == nil {
 		return nil, ErrEngineClosed
 	}
-	return e.engine.CreateCursorIterator(ctx)
+	if !e.config.PartitionByOrganization {
+		return e.partitions[0].engine.CreateCursorIterator(ctx)
+	}
+	return &partitionedCursorIterator{e: e, ctx: ctx, itrs: make(map[platform.ID]tsdb.CursorIterator)}, nil
 }
 
 // WritePoints writes the provided points to the engine.
@@ -461,6 +483,11 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		collection.DroppedKeys = append(collection.DroppedKeys, key)
 	}
 
+	var resolver *fieldTypeResolver
+	if policy := FieldTypeConflictPolicyFromContext(ctx); policy != influxdb.FieldTypeConflictReject {
+		resolver = newFieldTypeResolver(e, policy)
+	}
+
 	for iter := collection.Iterator(); iter.Next(); {
 		tags := iter.Tags()
 
@@ -503,6 +530,16 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 			continue
 		}
 
+		// Coerce or drop values that conflict with the type of their field.
+		if resolver != nil {
+			p, typ, reason := resolver.resolve(iter.Key(), iter.Point(), iter.Type())
+			if reason != "" {
+				dropPoint(iter.Key(), reason)
+				continue
+			}
+			collection.Points[iter.Index()], collection.Types[iter.Index()] = p, typ
+		}
+
 		collection.Copy(j, iter.Index())
 		j++
 	}
@@ -521,16 +558,24 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return err
 	}
 
-	// Add the write to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.WriteMulti(ctx, values); err != nil {
+	parts, err := e.partitionValues(ctx, values)
+	if err != nil {
 		return err
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	// Add the write to the WAL to be replayed if there is a crash or shutdown.
+	for p, values := range parts {
+		if _, err := p.wal.WriteMulti(ctx, values); err != nil {
+			return err
+		}
+	}
+
+	return e.writePointsLocked(ctx, collection, nil, values)
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
-func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
+// The values are written to the partition p, or to the partitions holding them if p is nil.
+func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesCollection, p *partition, values map[string][]value.Value) error {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -554,45 +599,24 @@ func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesC
 	}
 
 	// Write the values to the engine.
-	if err := e.engine.WriteValues(values); err != nil {
-		return err
-	}
-
-	return collection.PartialWriteError()
-}
-
-// AcquireSegments closes the current WAL segment, gets the set of all the currently closed
-// segments, and calls the callback. It does all of this under the lock on the engine.
-func (e *Engine) AcquireSegments(ctx context.Context, fn func(segs []string) error) error {
-	span, _ := tracing.StartSpanFromContext(ctx)
-	defer span.Finish()
-
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	if err := e.wal.CloseSegment(); err != nil {
-		return err
+	if p != nil {
+		if err := p.engine.WriteValues(values); err != nil {
+			return err
+		}
+		return collection.PartialWriteError()
 	}
 
-	segments, err := e.wal.ClosedSegments()
+	parts, err := e.partitionValues(ctx, values)
 	if err != nil {
 		return err
 	}
-
-	return fn(segments)
-}
-
-// CommitSegments calls the callback and if that does not return an error, removes the segment
-// files from the WAL. It does all of this under the lock on the engine.
-func (e *Engine) CommitSegments(ctx context.Context, segs []string, fn func() error) error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	if err := fn(); err != nil {
-		return err
+	for p, values := range parts {
+		if err := p.engine.WriteValues(values); err != nil {
+			return err
+		}
 	}
 
-	return e.wal.Remove(ctx, segs)
+	return collection.PartialWriteError()
 }
 
 // DeleteBucket deletes an entire bucket from the storage engine.
@@ -613,12 +637,17 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID platform
 		return ErrEngineClosed
 	}
 
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return err // Nothing was written for the organization.
+	}
+
 	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.DeleteBucketRange(orgID, bucketID, min, max, nil); err != nil {
+	if _, err := p.wal.DeleteBucketRange(orgID, bucketID, min, max, nil); err != nil {
 		return err
 	}
 
-	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, nil)
+	return deleteBucketRangeLocked(ctx, p, orgID, bucketID, min, max, nil)
 }
 
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
@@ -633,8 +662,12 @@ func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID
 		return ErrEngineClosed
 	}
 
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return err // Nothing was written for the organization.
+	}
+
 	var predData []byte
-	var err error
 	if pred != nil {
 		// Marshal the predicate to add it to the WAL.
 		predData, err = pred.Marshal()
@@ -644,22 +677,168 @@ func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID
 	}
 
 	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.DeleteBucketRange(orgID, bucketID, min, max, predData); err != nil {
+	if _, err := p.wal.DeleteBucketRange(orgID, bucketID, min, max, predData); err != nil {
 		return err
 	}
 
-	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, pred)
+	return deleteBucketRangeLocked(ctx, p, orgID, bucketID, min, max, pred)
 }
 
-// deleteBucketRangeLocked does the work of deleting a bucket range and must be called under
-// some sort of lock.
-func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred tsm1.Predicate) error {
+// deleteBucketRangeLocked does the work of deleting a bucket range from the partition and
+// must be called under some sort of lock.
+func deleteBucketRangeLocked(ctx context.Context, p *partition, orgID, bucketID platform.ID, min, max int64, pred tsm1.Predicate) error {
 	// TODO(edd): we need to clean up how we're encoding the prefix so that we
 	// don't have to remember to get it right everywhere we need to touch TSM data.
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	name := models.EscapeMeasurement(encoded[:])
 
-	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
+	return p.engine.DeletePrefixRange(ctx, name, min, max, pred)
+}
+
+// BucketWindowStats returns the stats of the data of the bucket in
+// consecutive windows of time of the given duration.
+func (e *Engine) BucketWindowStats(ctx context.Context, orgID, bucketID platform.ID, window time.Duration) ([]tsm1.WindowStats, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid window %s; must be positive", window)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	return p.engine.PrefixWindowStats(name, int64(window))
+}
+
+// BucketBlockStats returns the stats of the TSM blocks of the bucket by measurement.
+func (e *Engine) BucketBlockStats(ctx context.Context, orgID, bucketID platform.ID) (map[string]*tsm1.BlockStats, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil {
+		return nil, err
+	} else if p == nil {
+		return map[string]*tsm1.BlockStats{}, nil
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	return p.engine.PrefixBlockStats(name)
+}
+
+// ScheduleBucketFullCompaction snapshots the cache of the partition holding
+// the bucket and schedules a full compaction of its TSM files. The data of
+// all buckets of the organization is compacted together.
+func (e *Engine) ScheduleBucketFullCompaction(ctx context.Context, orgID, bucketID platform.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return err
+	}
+	return p.engine.ScheduleFullCompaction(ctx)
+}
+
+// BucketCompactionPlan returns the compactions that would run next on the
+// TSM files of the partition holding the bucket, without running them. The
+// data of all buckets of the organization is compacted together.
+func (e *Engine) BucketCompactionPlan(ctx context.Context, orgID, bucketID platform.ID) ([]tsm1.CompactionPlanGroup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return p.engine.PlanCompactions(), nil
+}
+
+// OptimizeIndex compacts the partitions of the index, waits for the
+// compactions to finish and then compacts the series file.
+func (e *Engine) OptimizeIndex(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	e.index.Compact()
+	e.index.Wait()
+	return e.sfile.Compact(ctx)
+}
+
+// SeriesFileStats returns the stats of the partitions of the series file.
+func (e *Engine) SeriesFileStats(ctx context.Context) ([]tsdb.SeriesPartitionStats, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+	return e.sfile.Stats(), nil
+}
+
+// CompactSeriesFile rebuilds the indexes of the partitions of the series
+// file, removing deleted series from them.
+func (e *Engine) CompactSeriesFile(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+	return e.sfile.Compact(ctx)
+}
+
+// VerifySeriesFile checks the indexes of the partitions of the series file
+// against their segments, and rebuilds inconsistent indexes if repair is true.
+func (e *Engine) VerifySeriesFile(ctx context.Context, repair bool) ([]tsdb.SeriesPartitionVerification, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+	return e.sfile.Verify(ctx, repair)
 }
 
 // SeriesCardinality returns the number of series in the engine.
@@ -693,7 +872,37 @@ func (e *Engine) MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats
 	return e.index.MeasurementCardinalityStats()
 }
 
+// SetCompactionThroughput changes the rate limit applied to TSM compactions.
+// A bytesPerSec of zero removes the limit.
+func (e *Engine) SetCompactionThroughput(bytesPerSec, burst int) error {
+	e.partitionsMu.Lock()
+	defer e.partitionsMu.Unlock()
+	for _, p := range e.partitions {
+		if err := p.engine.SetCompactionThroughput(bytesPerSec, burst); err != nil {
+			return err
+		}
+	}
+	e.compactionThroughput = &[2]int{bytesPerSec, burst}
+	return nil
+}
+
 // MeasurementStats returns the current measurement stats for the engine.
 func (e *Engine) MeasurementStats() (tsm1.MeasurementStats, error) {
-	return e.engine.MeasurementStats()
+	stats := make(tsm1.MeasurementStats)
+	err := e.eachPartition(func(p *partition) error {
+		s, err := p.engine.MeasurementStats()
+		if err != nil {
+			return err
+		}
+		stats.Add(s)
+		return nil
+	})
+	return stats, err
+}
+
+// WriteSnapshot writes the caches of all partitions to TSM files.
+func (e *Engine) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
+	return e.eachPartition(func(p *partition) error {
+		return p.engine.WriteSnapshot(ctx, status)
+	})
 }
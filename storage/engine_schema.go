@@ -19,7 +19,11 @@ func (e *Engine) TagKeys(ctx context.Context, orgID, bucketID influxdb.ID, start
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.TagKeys(ctx, orgID, bucketID, start, end, predicate)
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return cursors.EmptyStringIterator, err
+	}
+	return p.engine.TagKeys(ctx, orgID, bucketID, start, end, predicate)
 }
 
 // TagValues returns an iterator which enumerates the values for the specific
@@ -34,5 +38,9 @@ func (e *Engine) TagValues(ctx context.Context, orgID, bucketID influxdb.ID, tag
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.TagValues(ctx, orgID, bucketID, tagKey, start, end, predicate)
+	p, err := e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return cursors.EmptyStringIterator, err
+	}
+	return p.engine.TagValues(ctx, orgID, bucketID, tagKey, start, end, predicate)
 }
@@ -7,6 +7,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -255,6 +256,102 @@ func TestEngine_DeleteBucket(t *testing.T) {
 	}
 }
 
+func TestEngine_BucketWindowStats(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	otherBucketID, _ := influxdb.IDFromString("8888888888888888")
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(tsdb.EncodeNameString(engine.org, engine.bucket), tags, map[string]interface{}{"value": 1.0}, time.Unix(10, 0)),
+		models.MustNewPoint(tsdb.EncodeNameString(engine.org, engine.bucket), tags, map[string]interface{}{"value": 1.0}, time.Unix(70, 0)),
+		models.MustNewPoint(tsdb.EncodeNameString(engine.org, *otherBucketID), tags, map[string]interface{}{"value": 1.0}, time.Unix(130, 0)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := engine.BucketWindowStats(context.Background(), engine.org, engine.bucket, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected stats of 2 windows, got %+v", stats)
+	}
+	if stats[0].Min != int64(0) || stats[1].Min != int64(time.Minute) || stats[0].CacheBytes == 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if _, err := engine.BucketWindowStats(context.Background(), engine.org, engine.bucket, 0); err == nil {
+		t.Error("expected invalid window to be rejected")
+	}
+}
+
+func TestEngine_PartitionByOrganization(t *testing.T) {
+	c := storage.NewConfig()
+	c.PartitionByOrganization = true
+	engine := NewEngine(c, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	otherOrgID, _ := influxdb.IDFromString("8888888888888888")
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(tsdb.EncodeNameString(engine.org, engine.bucket), tags, map[string]interface{}{"value": 1.0}, time.Unix(10, 0)),
+		models.MustNewPoint(tsdb.EncodeNameString(*otherOrgID, engine.bucket), tags, map[string]interface{}{"value": 1.0}, time.Unix(10, 0)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every organization has its own WAL and TSM directories.
+	for _, orgID := range []influxdb.ID{engine.org, *otherOrgID} {
+		for _, path := range []string{c.GetWALPath(engine.path), c.GetEnginePath(engine.path)} {
+			if _, err := os.Stat(filepath.Join(path, orgID.String())); err != nil {
+				t.Errorf("expected partition directory of org %s: %v", orgID, err)
+			}
+		}
+	}
+
+	// Deleting the bucket of one organization leaves the other untouched.
+	if err := engine.DeleteBucket(context.Background(), *otherOrgID, engine.bucket); err != nil {
+		t.Fatal(err)
+	}
+	bucketStats := func(orgID influxdb.ID) []tsm1.WindowStats {
+		t.Helper()
+		stats, err := engine.BucketWindowStats(context.Background(), orgID, engine.bucket, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+	if stats := bucketStats(engine.org); len(stats) != 1 {
+		t.Fatalf("expected stats of 1 window, got %+v", stats)
+	}
+	if stats := bucketStats(*otherOrgID); len(stats) != 0 {
+		t.Fatalf("expected no stats of the deleted bucket, got %+v", stats)
+	}
+
+	// The partitions are reopened, and their WALs replayed.
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	engine.MustOpen()
+	if stats := bucketStats(engine.org); len(stats) != 1 {
+		t.Fatalf("expected stats of 1 window after reopening, got %+v", stats)
+	}
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Partitioned data cannot be opened without partitioning.
+	engine.Engine = storage.NewEngine(engine.path, storage.NewConfig(), storage.WithEngineID(engine.engineID), storage.WithNodeID(engine.nodeID))
+	if err := engine.Engine.Open(context.Background()); err == nil {
+		t.Fatal("expected an error opening partitioned data without partitioning")
+	}
+}
+
 func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -384,6 +481,7 @@ func TestEngine_InitializeMetrics(t *testing.T) {
 	files := promtest.MustFindMetric(t, mfs, "storage_tsm_files_total", prometheus.Labels{
 		"node_id":   fmt.Sprint(engine.nodeID),
 		"engine_id": fmt.Sprint(engine.engineID),
+		"org_id":    "",
 		"level":     "1",
 	})
 	if m, got, exp := files, files.GetGauge().GetValue(), 0.0; got != exp {
@@ -393,6 +491,7 @@ func TestEngine_InitializeMetrics(t *testing.T) {
 	bytes := promtest.MustFindMetric(t, mfs, "storage_tsm_files_disk_bytes", prometheus.Labels{
 		"node_id":   fmt.Sprint(engine.nodeID),
 		"engine_id": fmt.Sprint(engine.engineID),
+		"org_id":    "",
 		"level":     "1",
 	})
 	if m, got, exp := bytes, bytes.GetGauge().GetValue(), 0.0; got != exp {
@@ -490,6 +589,45 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 	}
 }
 
+func TestEngine_WriteFieldTypeConflictPolicy(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(field string, v interface{}) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: field, models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{field: v},
+			time.Unix(1, 2),
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("value", 1.0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("value", 2)}); err == nil {
+		t.Fatal("expected conflicting write to be rejected")
+	}
+
+	ctx := storage.WithFieldTypeConflictPolicy(context.TODO(), influxdb.FieldTypeConflictCoerce)
+	if err := engine.Engine.WritePoints(ctx, []models.Point{point("value", 2)}); err != nil {
+		t.Fatalf("expected integer to be coerced into float field, got %v", err)
+	}
+
+	ctx = storage.WithFieldTypeConflictPolicy(context.TODO(), influxdb.FieldTypeConflictDrop)
+	err := engine.Engine.WritePoints(ctx, []models.Point{point("value", "a"), point("other", 3)})
+	if e, ok := err.(tsdb.PartialWriteError); !ok || e.Dropped != 1 {
+		t.Fatalf("expected conflicting value to be dropped, got %v", err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
 func BenchmarkDeleteBucket(b *testing.B) {
 	var engine *Engine
 	setup := func(card int) {
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+type fieldTypeConflictPolicyKey struct{}
+
+// WithFieldTypeConflictPolicy returns a context whose point writes handle
+// values that conflict with the type of their field according to the policy.
+func WithFieldTypeConflictPolicy(ctx context.Context, p influxdb.FieldTypeConflictPolicy) context.Context {
+	return context.WithValue(ctx, fieldTypeConflictPolicyKey{}, p)
+}
+
+// FieldTypeConflictPolicyFromContext returns the field type conflict policy
+// of the point writes with ctx. The default policy is to reject conflicts.
+func FieldTypeConflictPolicyFromContext(ctx context.Context) influxdb.FieldTypeConflictPolicy {
+	p, _ := ctx.Value(fieldTypeConflictPolicyKey{}).(influxdb.FieldTypeConflictPolicy)
+	if p == "" {
+		return influxdb.FieldTypeConflictReject
+	}
+	return p
+}
+
+// fieldTypeResolver resolves the conflicts between the types of written
+// points and the types of their fields.
+type fieldTypeResolver struct {
+	engine *Engine
+	coerce bool
+
+	// types are the types of the fields first written by the batch.
+	types map[string]models.FieldType
+}
+
+func newFieldTypeResolver(engine *Engine, p influxdb.FieldTypeConflictPolicy) *fieldTypeResolver {
+	return &fieldTypeResolver{
+		engine: engine,
+		coerce: p == influxdb.FieldTypeConflictCoerce,
+		types:  make(map[string]models.FieldType),
+	}
+}
+
+// resolve returns the point to write for the point with the series key.
+// Integer values written to float fields are converted to floats when the
+// resolver coerces values. The reason is set when the point conflicts with
+// the type of its field and must be dropped.
+func (r *fieldTypeResolver) resolve(key []byte, p models.Point, typ models.FieldType) (models.Point, models.FieldType, string) {
+	iter := p.FieldIterator()
+	if !iter.Next() {
+		return p, typ, ""
+	}
+	field := iter.FieldKey()
+
+	fkey := string(tsm1.AppendSeriesFieldKeyBytes(nil, key, field))
+	want, ok := r.types[fkey]
+	if !ok {
+		if want, ok = r.engine.fieldType([]byte(fkey)); !ok {
+			r.types[fkey] = typ
+			return p, typ, ""
+		}
+		r.types[fkey] = want
+	}
+	if typ == want {
+		return p, typ, ""
+	}
+
+	if r.coerce && want == models.Float {
+		var v float64
+		switch typ {
+		case models.Integer:
+			iv, err := iter.IntegerValue()
+			if err != nil {
+				return nil, typ, err.Error()
+			}
+			v = float64(iv)
+		case models.Unsigned:
+			uv, err := iter.UnsignedValue()
+			if err != nil {
+				return nil, typ, err.Error()
+			}
+			v = float64(uv)
+		}
+		if typ == models.Integer || typ == models.Unsigned {
+			pt, err := models.NewPoint(string(p.Name()), p.Tags(), models.Fields{string(field): v}, p.Time())
+			if err != nil {
+				return nil, typ, err.Error()
+			}
+			return pt, models.Float, ""
+		}
+	}
+
+	return nil, typ, fmt.Sprintf("field type conflict: input field %q is type %s, already exists as type %s", field, typ, want)
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// DefaultMeasurementLastWriteInterval is the interval at which the last
+// writes to measurements are recorded.
+const DefaultMeasurementLastWriteInterval = 10 * time.Second
+
+// MeasurementWriteTracker records the last writes to the measurements of
+// buckets. Writes are kept in memory and recorded in batches, so that the
+// writes of points only update a map of the measurements written to.
+type MeasurementWriteTracker struct {
+	Service influxdb.MeasurementLastWriteService
+
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[influxdb.ID]influxdb.MeasurementLastWrites
+}
+
+// NewMeasurementWriteTracker returns a tracker recording the last writes to
+// measurements in s.
+func NewMeasurementWriteTracker(s influxdb.MeasurementLastWriteService) *MeasurementWriteTracker {
+	return &MeasurementWriteTracker{
+		Service: s,
+		logger:  zap.NewNop(),
+		now:     time.Now,
+		pending: make(map[influxdb.ID]influxdb.MeasurementLastWrites),
+	}
+}
+
+// WithLogger sets the logger l on the tracker. It must be called before Run.
+func (t *MeasurementWriteTracker) WithLogger(l *zap.Logger) {
+	t.logger = l.With(zap.String("component", "measurement_last_write"))
+}
+
+// PointsWriter returns a PointsWriter writing points to w, and tracking the
+// writes to the measurements of the points that were written.
+func (t *MeasurementWriteTracker) PointsWriter(w PointsWriter) PointsWriter {
+	return &measurementWriteTrackingPointsWriter{w: w, t: t}
+}
+
+type measurementWriteTrackingPointsWriter struct {
+	w PointsWriter
+	t *MeasurementWriteTracker
+}
+
+func (w *measurementWriteTrackingPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	if err := w.w.WritePoints(ctx, points); err != nil {
+		return err
+	}
+	w.t.Track(points)
+	return nil
+}
+
+// Track records a write of the points now. The points must be exploded, i.e.
+// named after their organization and bucket with their measurement as their
+// first tag. Consecutive points of the same measurement, the common case of
+// a batch, are tracked once.
+func (t *MeasurementWriteTracker) Track(points []models.Point) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var prevName, prevMeasurement []byte
+	for _, p := range points {
+		name := p.Name()
+		if len(name) != len(tsdb.EncodeName(0, 0)) {
+			continue
+		}
+		m := firstTagValue(p, models.MeasurementTagKeyBytes)
+		if m == nil || bytes.Equal(name, prevName) && bytes.Equal(m, prevMeasurement) {
+			continue
+		}
+		prevName, prevMeasurement = name, m
+
+		_, bucketID := tsdb.DecodeNameSlice(name)
+		ws, ok := t.pending[bucketID]
+		if !ok {
+			ws = make(influxdb.MeasurementLastWrites)
+			t.pending[bucketID] = ws
+		}
+		ws[string(m)] = now
+	}
+}
+
+// firstTagValue returns the value of the first tag of the point if it has
+// the key, or nil.
+func firstTagValue(p models.Point, key []byte) []byte {
+	var v []byte
+	p.ForEachTag(func(k, tv []byte) bool {
+		if bytes.Equal(k, key) {
+			v = tv
+		}
+		return false
+	})
+	return v
+}
+
+// Flush records the writes tracked since the previous flush.
+func (t *MeasurementWriteTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[influxdb.ID]influxdb.MeasurementLastWrites)
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	return t.Service.RecordMeasurementLastWrites(ctx, pending)
+}
+
+// Run flushes the tracked writes every interval until ctx is canceled. The
+// writes tracked after the last flush are recorded by calling Flush.
+func (t *MeasurementWriteTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := t.Flush(ctx); err != nil && ctx.Err() == nil {
+			t.logger.Error("Unable to record the last writes to measurements", zap.Error(err))
+		}
+	}
+}
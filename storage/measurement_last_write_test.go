@@ -0,0 +1,91 @@
+package storage_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+type pointsWriterFunc func(ctx context.Context, points []models.Point) error
+
+func (f pointsWriterFunc) WritePoints(ctx context.Context, points []models.Point) error {
+	return f(ctx, points)
+}
+
+func TestMeasurementWriteTracker(t *testing.T) {
+	orgID, bucketA, bucketB := influxdb.ID(1), influxdb.ID(10), influxdb.ID(11)
+	parse := func(bucketID influxdb.ID, lp string) []models.Point {
+		t.Helper()
+		name := tsdb.EncodeName(orgID, bucketID)
+		ps, err := models.ParsePointsString(lp, string(models.EscapeMeasurement(name[:])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ps
+	}
+
+	var recorded map[influxdb.ID]influxdb.MeasurementLastWrites
+	svc := mock.NewMeasurementLastWriteService()
+	svc.RecordMeasurementLastWritesFn = func(ctx context.Context, writes map[influxdb.ID]influxdb.MeasurementLastWrites) error {
+		recorded = writes
+		return nil
+	}
+	tracker := storage.NewMeasurementWriteTracker(svc)
+
+	fail := false
+	w := tracker.PointsWriter(pointsWriterFunc(func(ctx context.Context, points []models.Point) error {
+		if fail {
+			return &influxdb.Error{Code: influxdb.EInternal}
+		}
+		return nil
+	}))
+
+	ctx := context.Background()
+	points := parse(bucketA, "cpu,host=a idle=1,user=2 1\nmem,host=a used=3i 1\ncpu,host=b idle=4 1")
+	points = append(points, parse(bucketB, "disk free=1 1")...)
+	if err := w.WritePoints(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+	fail = true
+	if err := w.WritePoints(ctx, parse(bucketB, "net rx=1 1")); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+
+	if err := tracker.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[influxdb.ID][]string)
+	for id, ws := range recorded {
+		for m, tm := range ws {
+			if tm.IsZero() {
+				t.Errorf("expected the time of the write to %s, got none", m)
+			}
+			got[id] = append(got[id], m)
+		}
+	}
+	for _, ms := range got {
+		sort.Strings(ms)
+	}
+	want := map[influxdb.ID][]string{
+		bucketA: {"cpu", "mem"},
+		bucketB: {"disk"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected measurements written to: %s", diff)
+	}
+
+	recorded = nil
+	if err := tracker.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if recorded != nil {
+		t.Errorf("expected nothing to be recorded without writes, got %v", recorded)
+	}
+}
@@ -60,3 +60,9 @@ func (c *closeHelper) Close(cl io.Closer) {
 func (c *closeHelper) Done() error {
 	return c.err
 }
+
+// closerFunc adapts a function to an io.Closer.
+type closerFunc func() error
+
+// Close calls f.
+func (f closerFunc) Close() error { return f() }
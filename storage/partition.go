@@ -0,0 +1,403 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxdb/tsdb/value"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// partition holds the WAL and TSM engine of a part of the data of an Engine.
+//
+// When the Engine is partitioned by organization, every organization with
+// data has its own partition, with its own directories, cache and compaction
+// queues, so that the compactions of one organization do not hold back the
+// others. Otherwise a single partition holds all the data. The series file
+// and index are always shared by all partitions.
+type partition struct {
+	orgID  platform.ID // Zero for the partition of an unpartitioned engine.
+	e      *Engine
+	wal    *wal.WAL
+	engine *tsm1.Engine
+}
+
+// newPartition initialises the partition of the organization.
+func (e *Engine) newPartition(orgID platform.ID) *partition {
+	walPath, enginePath := e.config.GetWALPath(e.path), e.config.GetEnginePath(e.path)
+	labels, log := e.partitionMetricLabels(orgID), e.logger
+	if e.config.PartitionByOrganization {
+		walPath = filepath.Join(walPath, orgID.String())
+		enginePath = filepath.Join(enginePath, orgID.String())
+		log = log.With(zap.String("org_id", orgID.String()))
+	}
+
+	p := &partition{orgID: orgID, e: e}
+
+	p.wal = wal.NewWAL(walPath)
+	p.wal.WithFsyncDelay(time.Duration(e.config.WAL.FsyncDelay))
+	p.wal.SetEnabled(e.config.WAL.Enabled)
+	p.wal.SetDefaultMetricLabels(labels)
+	p.wal.WithLogger(log)
+
+	p.engine = tsm1.NewEngine(enginePath, e.index, e.config.Engine, tsm1.WithSnapshotter(p))
+	for _, option := range e.tsmOptions {
+		option(p.engine)
+	}
+	p.engine.SetDefaultMetricLabels(labels)
+	p.engine.WithLogger(log)
+
+	return p
+}
+
+// partitionMetricLabels returns the labels of the metrics of the WAL and TSM
+// engine of the partition, which account the disk usage and IO of every
+// organization separately when the engine is partitioned by organization.
+// The org_id label is empty otherwise.
+func (e *Engine) partitionMetricLabels(orgID platform.ID) prometheus.Labels {
+	labels := make(prometheus.Labels, len(e.defaultMetricLabels)+1)
+	for k, v := range e.defaultMetricLabels {
+		labels[k] = v
+	}
+	labels["org_id"] = ""
+	if e.config.PartitionByOrganization {
+		labels["org_id"] = orgID.String()
+	}
+	return labels
+}
+
+// Open opens the WAL and TSM engine of the partition, and replays the WAL.
+func (p *partition) Open(ctx context.Context) error {
+	var oh openHelper
+	oh.Open(ctx, p.wal)
+	oh.Open(ctx, p.engine)
+	if err := oh.Done(); err != nil {
+		return err
+	}
+
+	if p.e.compactionThroughput != nil {
+		if err := p.engine.SetCompactionThroughput(p.e.compactionThroughput[0], p.e.compactionThroughput[1]); err != nil {
+			p.Close()
+			return err
+		}
+	}
+
+	if err := p.e.replayWAL(p); err != nil {
+		p.Close()
+		return err
+	}
+	return nil
+}
+
+// Close closes the TSM engine and WAL of the partition.
+func (p *partition) Close() error {
+	var ch closeHelper
+	ch.Close(p.engine)
+	ch.Close(p.wal)
+	return ch.Done()
+}
+
+// AcquireSegments closes the current WAL segment, gets the set of all the currently closed
+// segments, and calls the callback. It does all of this under the lock on the engine.
+func (p *partition) AcquireSegments(ctx context.Context, fn func(segs []string) error) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p.e.mu.Lock()
+	defer p.e.mu.Unlock()
+
+	if err := p.wal.CloseSegment(); err != nil {
+		return err
+	}
+
+	segments, err := p.wal.ClosedSegments()
+	if err != nil {
+		return err
+	}
+
+	return fn(segments)
+}
+
+// CommitSegments calls the callback and if that does not return an error, removes the segment
+// files from the WAL. It does all of this under the lock on the engine.
+func (p *partition) CommitSegments(ctx context.Context, segs []string, fn func() error) error {
+	p.e.mu.Lock()
+	defer p.e.mu.Unlock()
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return p.wal.Remove(ctx, segs)
+}
+
+// partitionID returns the key of the partition holding the data of the
+// organization.
+func (e *Engine) partitionID(orgID platform.ID) platform.ID {
+	if !e.config.PartitionByOrganization {
+		return 0
+	}
+	return orgID
+}
+
+// partition returns the partition holding the data of the organization. If
+// the partition does not exist, it is created when create is true and nil is
+// returned otherwise. It must be called under the lock on the engine.
+func (e *Engine) partition(ctx context.Context, orgID platform.ID, create bool) (*partition, error) {
+	id := e.partitionID(orgID)
+
+	e.partitionsMu.RLock()
+	p := e.partitions[id]
+	e.partitionsMu.RUnlock()
+	if p != nil || !create {
+		return p, nil
+	}
+
+	e.partitionsMu.Lock()
+	defer e.partitionsMu.Unlock()
+	if p = e.partitions[id]; p != nil {
+		return p, nil
+	}
+
+	p = e.newPartition(id)
+	if err := p.Open(ctx); err != nil {
+		return nil, err
+	}
+	e.partitions[id] = p
+	return p, nil
+}
+
+// eachPartition calls fn for every partition until fn returns an error. The
+// lock on the partitions is not held while fn is called, since snapshots of
+// the partitions take the lock on the engine.
+func (e *Engine) eachPartition(fn func(p *partition) error) error {
+	e.partitionsMu.RLock()
+	parts := make([]*partition, 0, len(e.partitions))
+	for _, p := range e.partitions {
+		parts = append(parts, p)
+	}
+	e.partitionsMu.RUnlock()
+
+	for _, p := range parts {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldType returns the type of the field of the series field key, if the
+// field has been written.
+func (e *Engine) fieldType(key []byte) (models.FieldType, bool) {
+	var orgID platform.ID
+	if e.config.PartitionByOrganization {
+		name := models.ParseName(key)
+		if len(name) < 8 {
+			return 0, false
+		}
+		orgID = platform.ID(binary.BigEndian.Uint64(name[:8]))
+	}
+
+	p, err := e.partition(context.Background(), orgID, false)
+	if err != nil || p == nil {
+		return 0, false
+	}
+	return p.engine.FieldType(key)
+}
+
+// partitionValues groups the values by the partitions holding them, and
+// creates the partitions that do not exist yet.
+func (e *Engine) partitionValues(ctx context.Context, values map[string][]value.Value) (map[*partition]map[string][]value.Value, error) {
+	if !e.config.PartitionByOrganization {
+		p, err := e.partition(ctx, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		return map[*partition]map[string][]value.Value{p: values}, nil
+	}
+
+	byOrg := make(map[platform.ID]map[string][]value.Value)
+	for key, vs := range values {
+		name := models.ParseName([]byte(key))
+		if len(name) != len(tsdb.EncodeName(0, 0)) {
+			return nil, fmt.Errorf("invalid measurement name %q of series key %q", name, key)
+		}
+		orgID, _ := tsdb.DecodeNameSlice(name)
+		if byOrg[orgID] == nil {
+			byOrg[orgID] = make(map[string][]value.Value)
+		}
+		byOrg[orgID][key] = vs
+	}
+
+	parts := make(map[*partition]map[string][]value.Value, len(byOrg))
+	for orgID, values := range byOrg {
+		p, err := e.partition(ctx, orgID, true)
+		if err != nil {
+			return nil, err
+		}
+		parts[p] = values
+	}
+	return parts, nil
+}
+
+// openPartitions opens the partitions of the data on disk. It returns an
+// error if the layout of the data does not match the configuration, since
+// partitioning can only be changed before any data has been written.
+func (e *Engine) openPartitions(ctx context.Context) error {
+	walPath, enginePath := e.config.GetWALPath(e.path), e.config.GetEnginePath(e.path)
+
+	if !e.config.PartitionByOrganization {
+		for _, path := range []string{walPath, enginePath} {
+			if ids, err := partitionDirs(path); err != nil {
+				return err
+			} else if len(ids) > 0 {
+				return fmt.Errorf("%s holds data partitioned by organization, but partitioning by organization is disabled", path)
+			}
+		}
+		p := e.newPartition(0)
+		if err := p.Open(ctx); err != nil {
+			return err
+		}
+		e.partitions[0] = p
+		return nil
+	}
+
+	for path, ext := range map[string]string{walPath: wal.WALFileExtension, enginePath: tsm1.TSMFileExtension} {
+		if files, err := filepath.Glob(filepath.Join(path, "*."+ext)); err != nil {
+			return err
+		} else if len(files) > 0 {
+			return fmt.Errorf("%s holds data that is not partitioned by organization, but partitioning by organization is enabled", path)
+		}
+	}
+
+	orgIDs := make(map[platform.ID]struct{})
+	for _, path := range []string{walPath, enginePath} {
+		ids, err := partitionDirs(path)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			orgIDs[id] = struct{}{}
+		}
+	}
+
+	for orgID := range orgIDs {
+		p := e.newPartition(orgID)
+		if err := p.Open(ctx); err != nil {
+			e.closePartitions()
+			return err
+		}
+		e.partitions[orgID] = p
+	}
+	return nil
+}
+
+// closePartitions closes and forgets all partitions.
+func (e *Engine) closePartitions() error {
+	e.partitionsMu.Lock()
+	defer e.partitionsMu.Unlock()
+
+	var ch closeHelper
+	for id, p := range e.partitions {
+		ch.Close(p)
+		delete(e.partitions, id)
+	}
+	return ch.Done()
+}
+
+// partitionDirs returns the organization IDs of the partition directories
+// in path.
+func partitionDirs(path string) ([]platform.ID, error) {
+	fis, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var ids []platform.ID
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		var id platform.ID
+		if err := id.DecodeFromString(fi.Name()); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// partitionedCursorIterator creates cursors from the partition of the
+// organization of each request.
+type partitionedCursorIterator struct {
+	e    *Engine
+	ctx  context.Context
+	itrs map[platform.ID]tsdb.CursorIterator
+}
+
+func (q *partitionedCursorIterator) iterator(ctx context.Context, name []byte) (tsdb.CursorIterator, error) {
+	if len(name) < 8 {
+		return nil, nil
+	}
+	orgID := platform.ID(binary.BigEndian.Uint64(name[:8]))
+	if itr, ok := q.itrs[orgID]; ok {
+		return itr, nil
+	}
+
+	q.e.mu.RLock()
+	defer q.e.mu.RUnlock()
+	if q.e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	p, err := q.e.partition(ctx, orgID, false)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	itr, err := p.engine.CreateCursorIterator(q.ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.itrs[orgID] = itr
+	return itr, nil
+}
+
+func (q *partitionedCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	itr, err := q.iterator(ctx, r.Name)
+	if err != nil || itr == nil {
+		return nil, err
+	}
+	return itr.Next(ctx, r)
+}
+
+// Sketch implements cursors.SketchIterator.
+func (q *partitionedCursorIterator) Sketch(ctx context.Context, r *cursors.CursorRequest) (*cursors.Sketch, error) {
+	itr, err := q.iterator(ctx, r.Name)
+	if err != nil || itr == nil {
+		return nil, err
+	}
+	return itr.(cursors.SketchIterator).Sketch(ctx, r)
+}
+
+func (q *partitionedCursorIterator) Stats() cursors.CursorStats {
+	var stats cursors.CursorStats
+	for _, itr := range q.itrs {
+		stats.Add(itr.Stats())
+	}
+	return stats
+}
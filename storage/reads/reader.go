@@ -51,6 +51,15 @@ func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec
 	}, nil
 }
 
+func (r *storeReader) ReadSketch(ctx context.Context, spec influxdb.ReadSketchSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &sketchIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if spec.Predicate != nil {
@@ -105,22 +114,40 @@ type filterIterator struct {
 func (fi *filterIterator) Statistics() cursors.CursorStats { return fi.stats }
 
 func (fi *filterIterator) Do(f func(flux.Table) error) error {
-	src := fi.s.GetSource(
-		uint64(fi.spec.OrganizationID),
-		uint64(fi.spec.BucketID),
+	req, err := newReadFilterRequest(fi.s, &fi.spec)
+	if err != nil {
+		return err
+	}
+
+	rs, err := fi.s.ReadFilter(fi.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+
+	return fi.handleRead(f, rs)
+}
+
+func newReadFilterRequest(s Store, spec *influxdb.ReadFilterSpec) (*datatypes.ReadFilterRequest, error) {
+	src := s.GetSource(
+		uint64(spec.OrganizationID),
+		uint64(spec.BucketID),
 	)
 
 	// Setup read request
 	any, err := types.MarshalAny(src)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var predicate *datatypes.Predicate
-	if fi.spec.Predicate != nil {
-		p, err := toStoragePredicate(fi.spec.Predicate)
+	if spec.Predicate != nil {
+		p, err := toStoragePredicate(spec.Predicate)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		predicate = p
 	}
@@ -128,19 +155,9 @@ func (fi *filterIterator) Do(f func(flux.Table) error) error {
 	var req datatypes.ReadFilterRequest
 	req.ReadSource = any
 	req.Predicate = predicate
-	req.Range.Start = int64(fi.spec.Bounds.Start)
-	req.Range.End = int64(fi.spec.Bounds.Stop)
-
-	rs, err := fi.s.ReadFilter(fi.ctx, &req)
-	if err != nil {
-		return err
-	}
-
-	if rs == nil {
-		return nil
-	}
-
-	return fi.handleRead(f, rs)
+	req.Range.Start = int64(spec.Bounds.Start)
+	req.Range.End = int64(spec.Bounds.Stop)
+	return &req, nil
 }
 
 func (fi *filterIterator) handleRead(f func(flux.Table) error, rs ResultSet) error {
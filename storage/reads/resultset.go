@@ -11,6 +11,7 @@ import (
 
 type multiShardCursors interface {
 	createCursor(row SeriesRow) cursors.Cursor
+	createSketch(row SeriesRow) (*cursors.Sketch, error)
 	newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor
 }
 
@@ -65,6 +66,10 @@ func (r *resultSet) Cursor() cursors.Cursor {
 	return cur
 }
 
+func (r *resultSet) Sketch() (*cursors.Sketch, error) {
+	return r.mb.createSketch(r.row)
+}
+
 func (r *resultSet) Tags() models.Tags {
 	return r.row.Tags
 }
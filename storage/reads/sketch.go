@@ -0,0 +1,178 @@
+package reads
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// createSketch returns the sketch of the values of row. Shards that keep
+// sketches are asked for them, unless the values are filtered by a
+// condition, in which case every value is read.
+func (m *multiShardArrayCursors) createSketch(row SeriesRow) (*cursors.Sketch, error) {
+	if row.ValueCond != nil {
+		return sketchOf(m.createCursor(row))
+	}
+
+	m.req.Name = row.Name
+	m.req.Tags = row.SeriesTags
+	m.req.Field = row.Field
+
+	var sketch *cursors.Sketch
+	for _, shard := range row.Query {
+		var s *cursors.Sketch
+		if si, ok := shard.(cursors.SketchIterator); ok {
+			var err error
+			if s, err = si.Sketch(m.ctx, &m.req); err != nil {
+				return nil, err
+			}
+		} else {
+			cur, err := shard.Next(m.ctx, &m.req)
+			if err != nil {
+				return nil, err
+			}
+			if s, err = sketchOf(cur); err != nil {
+				return nil, err
+			}
+		}
+
+		if s == nil {
+			continue
+		} else if sketch == nil {
+			sketch = s
+		} else if err := sketch.Merge(s); err != nil {
+			return nil, err
+		}
+	}
+	return sketch, nil
+}
+
+// sketchOf reads all values of cur into a new sketch and closes cur.
+func sketchOf(cur cursors.Cursor) (*cursors.Sketch, error) {
+	if cur == nil {
+		return nil, nil
+	}
+	defer cur.Close()
+
+	var numeric bool
+	switch cur.(type) {
+	case cursors.FloatArrayCursor, cursors.IntegerArrayCursor, cursors.UnsignedArrayCursor:
+		numeric = true
+	}
+	sketch := cursors.NewSketch(numeric)
+	if err := sketch.AddCursor(cur); err != nil {
+		return nil, err
+	}
+	return sketch, nil
+}
+
+type sketchIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadSketchSpec
+	stats cursors.CursorStats
+	alloc *memory.Allocator
+}
+
+func (si *sketchIterator) Statistics() cursors.CursorStats { return si.stats }
+
+func (si *sketchIterator) Do(f func(flux.Table) error) error {
+	req, err := newReadFilterRequest(si.s, &si.spec.ReadFilterSpec)
+	if err != nil {
+		return err
+	}
+
+	rs, err := si.s.ReadFilter(si.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+
+	return si.handleRead(f, rs)
+}
+
+func (si *sketchIterator) handleRead(f func(flux.Table) error, rs ResultSet) error {
+	defer rs.Close()
+
+	srs, _ := rs.(SketchResultSet)
+	for rs.Next() {
+		var sketch *cursors.Sketch
+		var err error
+		if srs != nil {
+			sketch, err = srs.Sketch()
+		} else {
+			sketch, err = sketchOf(rs.Cursor())
+		}
+		if err != nil {
+			return err
+		} else if sketch == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		tbl, err := si.table(rs, sketch)
+		if err != nil {
+			return err
+		} else if tbl == nil {
+			continue
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+
+	stats := rs.Stats()
+	si.stats.ScannedValues += stats.ScannedValues
+	si.stats.ScannedBytes += stats.ScannedBytes
+	return rs.Err()
+}
+
+// table returns a table with a single row that holds the estimate of the
+// sketch, or nil if the sketch is empty or cannot provide the estimate.
+func (si *sketchIterator) table(rs ResultSet, sketch *cursors.Sketch) (flux.Table, error) {
+	key := defaultGroupKeyForSeries(rs.Tags(), si.spec.Bounds)
+	builder := execute.NewColListTableBuilder(key, si.alloc)
+	defer builder.ClearData()
+	if err := execute.AddTableKeyCols(key, builder); err != nil {
+		return nil, err
+	}
+
+	switch si.spec.Method {
+	case influxdb.SketchMethodQuantile:
+		if sketch.Digest == nil || sketch.Digest.Count() == 0 {
+			return nil, nil
+		}
+		idx, err := builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TFloat})
+		if err != nil {
+			return nil, err
+		} else if err := builder.AppendFloat(idx, sketch.Digest.Quantile(si.spec.Quantile)); err != nil {
+			return nil, err
+		}
+	case influxdb.SketchMethodDistinct:
+		n := sketch.Distinct.Count()
+		if n == 0 {
+			return nil, nil
+		}
+		idx, err := builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TInt})
+		if err != nil {
+			return nil, err
+		} else if err := builder.AppendInt(idx, int64(n)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown sketch method: %q", si.spec.Method)
+	}
+
+	if err := execute.AppendKeyValues(key, builder); err != nil {
+		return nil, err
+	}
+	return builder.Table()
+}
@@ -29,6 +29,16 @@ type ResultSet interface {
 	Stats() cursors.CursorStats
 }
 
+// SketchResultSet is implemented by result sets that can summarize the
+// values of the most recent cursor without reading every value.
+type SketchResultSet interface {
+	ResultSet
+
+	// Sketch returns the sketch of the values of the most recent series after
+	// a call to Next, or nil if the series has no values.
+	Sketch() (*cursors.Sketch, error)
+}
+
 type GroupResultSet interface {
 	// Next advances the GroupResultSet and returns the next GroupCursor. It
 	// returns nil if there are no more groups.
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"math"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -22,6 +26,7 @@ const (
 // A Deleter implementation is capable of deleting data from a storage engine.
 type Deleter interface {
 	DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) error
+	DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error
 }
 
 // A Snapshotter implementation can take snapshots of the entire engine.
@@ -116,7 +121,9 @@ func (s *retentionEnforcer) run() {
 // expireData runs a delete operation on the storage engine.
 //
 // Any series data that (1) belongs to a bucket in the provided list and
-// (2) falls outside the bucket's indicated retention period will be deleted.
+// (2) falls outside the bucket's indicated retention period, or the period
+// of the first retention rule of the bucket matching the series, will be
+// deleted.
 func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.Bucket, now time.Time) {
 	logger, logEnd := logger.NewOperation(ctx, s.logger, "Data deletion", "data_deletion",
 		zap.Int("buckets", len(buckets)))
@@ -133,10 +140,16 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 			zap.String("org_id", b.OrgID.String()),
 			zap.String("bucket_id", b.ID.String()),
 			zap.Duration("retention_period", b.RetentionPeriod),
+			zap.Int("retention_rules", len(b.RetentionRules)),
 			zap.String("system_type", b.Type.String()),
 		}
 
-		if b.RetentionPeriod == 0 {
+		deletes, err := retentionDeletes(b)
+		if err != nil {
+			skipInvalid++
+			logger.Warn("Skipping bucket with invalid retention rules", append(bucketFields, zap.Error(err))...)
+			continue
+		} else if len(deletes) == 0 {
 			logger.Debug("Skipping bucket with infinite retention", bucketFields...)
 			skipInf++
 			continue
@@ -146,28 +159,36 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 			continue
 		}
 
-		min := int64(math.MinInt64)
-		max := now.Add(-b.RetentionPeriod).UnixNano()
-
-		span, ctx := tracing.StartSpanFromContext(ctx)
-		span.LogKV(
-			"bucket_id", b.ID,
-			"org_id", b.OrgID,
-			"system_type", b.Type,
-			"retention_period", b.RetentionPeriod,
-			"retention_policy", b.RetentionPolicyName,
-			"from", time.Unix(0, min).UTC(),
-			"to", time.Unix(0, max).UTC(),
-		)
-
-		err := s.Engine.DeleteBucketRange(ctx, b.OrgID, b.ID, min, max)
-		if err != nil {
-			logger.Info("Unable to delete bucket range",
-				append(bucketFields, zap.Time("min", time.Unix(0, min)), zap.Time("max", time.Unix(0, max)), zap.Error(err))...)
-			tracing.LogError(span, err)
+		for _, d := range deletes {
+			min := int64(math.MinInt64)
+			max := now.Add(-d.period).UnixNano()
+
+			span, ctx := tracing.StartSpanFromContext(ctx)
+			span.LogKV(
+				"bucket_id", b.ID,
+				"org_id", b.OrgID,
+				"system_type", b.Type,
+				"retention_period", d.period,
+				"retention_policy", b.RetentionPolicyName,
+				"predicate", d.pred != nil,
+				"from", time.Unix(0, min).UTC(),
+				"to", time.Unix(0, max).UTC(),
+			)
+
+			var err error
+			if d.pred == nil {
+				err = s.Engine.DeleteBucketRange(ctx, b.OrgID, b.ID, min, max)
+			} else {
+				err = s.Engine.DeleteBucketRangePredicate(ctx, b.OrgID, b.ID, min, max, d.pred)
+			}
+			if err != nil {
+				logger.Info("Unable to delete bucket range",
+					append(bucketFields, zap.Duration("period", d.period), zap.Time("min", time.Unix(0, min)), zap.Time("max", time.Unix(0, max)), zap.Error(err))...)
+				tracing.LogError(span, err)
+			}
+			s.tracker.IncChecks(err == nil)
+			span.Finish()
 		}
-		s.tracker.IncChecks(err == nil)
-		span.Finish()
 	}
 
 	if skipInf > 0 || skipInvalid > 0 {
@@ -175,6 +196,151 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 	}
 }
 
+// A retentionDelete deletes the series matching pred, or all the series of
+// the bucket if pred is nil, that are older than period.
+type retentionDelete struct {
+	period time.Duration
+	pred   influxdb.Predicate
+}
+
+// retentionDeletes returns the deletes enforcing the retention of the bucket.
+//
+// The series matched by a rule are deleted with a predicate that excludes the
+// series matched by earlier rules, and the remaining series are deleted with
+// a predicate that excludes the series matched by any rule. A rule keeping
+// its series no longer than the bucket does not need to be excluded from the
+// delete of the bucket, so buckets whose rules only shorten the retention of
+// some series delete the rest of their data without a predicate.
+func retentionDeletes(b *influxdb.Bucket) ([]retentionDelete, error) {
+	var deletes []retentionDelete
+	var earlier, excluded []*datatypes.Node
+	for _, r := range b.RetentionRules {
+		if err := r.Valid(); err != nil {
+			return nil, err
+		}
+		match := retentionRuleNode(r)
+		if r.RetentionPeriod > 0 {
+			pred, err := retentionPredicate(append(earlier, match))
+			if err != nil {
+				return nil, err
+			}
+			deletes = append(deletes, retentionDelete{period: r.RetentionPeriod, pred: pred})
+		}
+
+		notMatch := negatePredicateNode(match)
+		earlier = append(earlier, notMatch)
+		if r.RetentionPeriod == 0 || (b.RetentionPeriod > 0 && r.RetentionPeriod > b.RetentionPeriod) {
+			excluded = append(excluded, notMatch)
+		}
+	}
+
+	if b.RetentionPeriod > 0 {
+		var pred influxdb.Predicate
+		if len(excluded) > 0 {
+			var err error
+			if pred, err = retentionPredicate(excluded); err != nil {
+				return nil, err
+			}
+		}
+		deletes = append(deletes, retentionDelete{period: b.RetentionPeriod, pred: pred})
+	}
+	return deletes, nil
+}
+
+// retentionPredicate returns the predicate matching all the nodes.
+func retentionPredicate(nodes []*datatypes.Node) (influxdb.Predicate, error) {
+	root := nodes[0]
+	for _, n := range nodes[1:] {
+		root = logicalPredicateNode(datatypes.LogicalAnd, root, n)
+	}
+	return tsm1.NewProtobufPredicate(&datatypes.Predicate{Root: root})
+}
+
+// retentionRuleNode returns the predicate node matching the series of the rule.
+func retentionRuleNode(r influxdb.BucketRetentionRule) *datatypes.Node {
+	var comparisons []*datatypes.Node
+	if r.Measurement != "" {
+		comparisons = append(comparisons, patternPredicateNode(models.MeasurementTagKey, r.Measurement))
+	}
+	for _, t := range r.Tags {
+		key := t.Key
+		if key == "_field" {
+			key = models.FieldKeyTagKey
+		}
+		comparisons = append(comparisons, patternPredicateNode(key, t.Value))
+	}
+
+	root := comparisons[0]
+	for _, n := range comparisons[1:] {
+		root = logicalPredicateNode(datatypes.LogicalAnd, root, n)
+	}
+	return root
+}
+
+// patternPredicateNode returns the node comparing the tag with the pattern,
+// where * matches any characters.
+func patternPredicateNode(key, pattern string) *datatypes.Node {
+	var comparison datatypes.Node_Comparison
+	var value *datatypes.Node
+	if !strings.Contains(pattern, "*") {
+		comparison = datatypes.ComparisonEqual
+		value = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value:    &datatypes.Node_StringValue{StringValue: pattern},
+		}
+	} else {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		comparison = datatypes.ComparisonRegex
+		value = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value:    &datatypes.Node_RegexValue{RegexValue: "^" + strings.Join(parts, ".*") + "$"},
+		}
+	}
+
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: comparison},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			value,
+		},
+	}
+}
+
+// negatePredicateNode returns the node matching the series the node built by
+// retentionRuleNode does not match.
+func negatePredicateNode(n *datatypes.Node) *datatypes.Node {
+	if n.NodeType == datatypes.NodeTypeLogicalExpression {
+		// not (a and b) is (not a) or (not b).
+		return logicalPredicateNode(datatypes.LogicalOr,
+			negatePredicateNode(n.Children[0]), negatePredicateNode(n.Children[1]))
+	}
+
+	comparison := datatypes.ComparisonNotEqual
+	if n.GetComparison() == datatypes.ComparisonRegex {
+		comparison = datatypes.ComparisonNotRegex
+	}
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: comparison},
+		Children: n.Children,
+	}
+}
+
+func logicalPredicateNode(op datatypes.Node_Logical, left, right *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLogicalExpression,
+		Value:    &datatypes.Node_Logical_{Logical: op},
+		Children: []*datatypes.Node{left, right},
+	}
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation(ctx context.Context) ([]*influxdb.Bucket, error) {
 	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"os"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -240,6 +241,110 @@ func TestRetentionService(t *testing.T) {
 	})
 }
 
+func TestRetentionService_RetentionRules(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+	const day = 24 * time.Hour
+	key := func(measurement, tags string) []byte {
+		return []byte("bucketorg,\x00=" + measurement + tags + ",\xff=value#!~#value")
+	}
+
+	type expired struct {
+		period time.Duration
+		keys   []string // The keys matched by the delete, or nil for the whole bucket.
+	}
+	keys := map[string][]byte{
+		"debug":        key("debug_http", ",host=edge-1"),
+		"debug prefix": key("debugger", ",host=edge-1"),
+		"cpu edge":     key("cpu", ",host=edge-1"),
+		"cpu core":     key("cpu", ",host=core-1"),
+		"cpu":          key("cpu", ""),
+		"audit":        key("audit", ",host=edge-1"),
+	}
+
+	tests := []struct {
+		name   string
+		bucket influxdb.Bucket
+		want   []expired
+	}{
+		{
+			name: "shorter rule",
+			bucket: influxdb.Bucket{
+				RetentionPeriod: 90 * day,
+				RetentionRules: []influxdb.BucketRetentionRule{
+					{Measurement: "debug_*", RetentionPeriod: 3 * day},
+				},
+			},
+			want: []expired{
+				{period: 3 * day, keys: []string{"debug"}},
+				{period: 90 * day},
+			},
+		},
+		{
+			name: "longer rules",
+			bucket: influxdb.Bucket{
+				RetentionPeriod: 3 * day,
+				RetentionRules: []influxdb.BucketRetentionRule{
+					{Measurement: "audit"},
+					{Tags: []influxdb.Tag{{Key: "host", Value: "edge-*"}}, RetentionPeriod: 30 * day},
+				},
+			},
+			want: []expired{
+				{period: 30 * day, keys: []string{"cpu edge", "debug", "debug prefix"}},
+				{period: 3 * day, keys: []string{"cpu", "cpu core"}},
+			},
+		},
+		{
+			name: "infinite bucket",
+			bucket: influxdb.Bucket{
+				RetentionRules: []influxdb.BucketRetentionRule{
+					{Measurement: "cpu", Tags: []influxdb.Tag{{Key: "host", Value: "core-*"}}, RetentionPeriod: day},
+				},
+			},
+			want: []expired{
+				{period: day, keys: []string{"cpu core"}},
+			},
+		},
+		{
+			name: "infinite rule",
+			bucket: influxdb.Bucket{
+				RetentionRules: []influxdb.BucketRetentionRule{
+					{Measurement: "cpu"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []expired
+			engine := NewTestEngine()
+			engine.DeleteBucketRangeFn = func(ctx context.Context, orgID, bucketID influxdb.ID, from, to int64) error {
+				got = append(got, expired{period: now.Sub(time.Unix(0, to))})
+				return nil
+			}
+			engine.DeleteBucketRangePredicateFn = func(ctx context.Context, orgID, bucketID influxdb.ID, from, to int64, pred influxdb.Predicate) error {
+				e := expired{period: now.Sub(time.Unix(0, to)), keys: []string{}}
+				for name, key := range keys {
+					if pred.Matches(key) {
+						e.keys = append(e.keys, name)
+					}
+				}
+				sort.Strings(e.keys)
+				got = append(got, e)
+				return nil
+			}
+
+			b := tt.bucket
+			b.OrgID, b.ID = 1, 2
+			service := newRetentionEnforcer(engine, &TestSnapshotter{}, NewTestBucketFinder())
+			service.expireData(context.Background(), []*influxdb.Bucket{&b}, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got deletes\n%+v\nexpected\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMetrics_Retention(t *testing.T) {
 	t.Parallel()
 	// metrics to be shared by multiple file stores.
@@ -314,12 +419,16 @@ func (r *MockRunner) run() {
 }
 
 type TestEngine struct {
-	DeleteBucketRangeFn func(context.Context, influxdb.ID, influxdb.ID, int64, int64) error
+	DeleteBucketRangeFn          func(context.Context, influxdb.ID, influxdb.ID, int64, int64) error
+	DeleteBucketRangePredicateFn func(context.Context, influxdb.ID, influxdb.ID, int64, int64, influxdb.Predicate) error
 }
 
 func NewTestEngine() *TestEngine {
 	return &TestEngine{
 		DeleteBucketRangeFn: func(context.Context, influxdb.ID, influxdb.ID, int64, int64) error { return nil },
+		DeleteBucketRangePredicateFn: func(context.Context, influxdb.ID, influxdb.ID, int64, int64, influxdb.Predicate) error {
+			return nil
+		},
 	}
 }
 
@@ -327,6 +436,10 @@ func (e *TestEngine) DeleteBucketRange(ctx context.Context, orgID, bucketID infl
 	return e.DeleteBucketRangeFn(ctx, orgID, bucketID, min, max)
 }
 
+func (e *TestEngine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+	return e.DeleteBucketRangePredicateFn(ctx, orgID, bucketID, min, max, pred)
+}
+
 type TestSnapshotter struct{}
 
 func (s *TestSnapshotter) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
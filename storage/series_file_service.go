@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// SeriesFileMaintainer reports on and maintains the series file of an engine.
+type SeriesFileMaintainer interface {
+	SeriesFileStats(ctx context.Context) ([]tsdb.SeriesPartitionStats, error)
+	CompactSeriesFile(ctx context.Context) error
+	VerifySeriesFile(ctx context.Context, repair bool) ([]tsdb.SeriesPartitionVerification, error)
+}
+
+var _ influxdb.SeriesFileService = (*SeriesFileService)(nil)
+
+// SeriesFileService inspects and maintains the series file of an engine.
+type SeriesFileService struct {
+	engine SeriesFileMaintainer
+}
+
+// NewSeriesFileService returns a SeriesFileService for the series file of the engine.
+func NewSeriesFileService(engine SeriesFileMaintainer) *SeriesFileService {
+	return &SeriesFileService{engine: engine}
+}
+
+// FindSeriesFileStats returns the stats of the series file.
+func (s *SeriesFileService) FindSeriesFileStats(ctx context.Context) (*influxdb.SeriesFileStats, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	partitions, err := s.engine.SeriesFileStats(ctx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindSeriesFileStats,
+			Msg:  "unable to read series file stats",
+			Err:  err,
+		}
+	}
+
+	stats := &influxdb.SeriesFileStats{
+		Partitions: make([]influxdb.SeriesFilePartitionStats, 0, len(partitions)),
+	}
+	for _, p := range partitions {
+		stats.Series += p.Series
+		stats.Tombstones += p.Tombstones
+		stats.DiskBytes += p.DiskSize
+		stats.Partitions = append(stats.Partitions, influxdb.SeriesFilePartitionStats{
+			ID:             p.ID,
+			Series:         p.Series,
+			Tombstones:     p.Tombstones,
+			TombstoneRatio: p.TombstoneRatio,
+			DiskBytes:      p.DiskSize,
+			Segments:       p.Segments,
+			Compacting:     p.Compacting,
+		})
+	}
+	return stats, nil
+}
+
+// CompactSeriesFile compacts the indexes of all partitions of the series file.
+func (s *SeriesFileService) CompactSeriesFile(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.engine.CompactSeriesFile(ctx); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpCompactSeriesFile,
+			Msg:  "unable to compact series file",
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// VerifySeriesFile verifies the partitions of the series file, and rebuilds
+// the indexes of inconsistent partitions if repair is true.
+func (s *SeriesFileService) VerifySeriesFile(ctx context.Context, repair bool) (*influxdb.SeriesFileVerification, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	partitions, err := s.engine.VerifySeriesFile(ctx, repair)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpVerifySeriesFile,
+			Msg:  "unable to verify series file",
+			Err:  err,
+		}
+	}
+
+	v := &influxdb.SeriesFileVerification{
+		Valid:      true,
+		Partitions: make([]influxdb.SeriesFilePartitionVerification, 0, len(partitions)),
+	}
+	for _, p := range partitions {
+		v.Valid = v.Valid && p.Valid()
+		v.Partitions = append(v.Partitions, influxdb.SeriesFilePartitionVerification{
+			ID:       p.ID,
+			Valid:    p.Valid(),
+			Entries:  p.Entries,
+			Errors:   p.Errors,
+			Repaired: p.Repaired,
+		})
+	}
+	return v, nil
+}
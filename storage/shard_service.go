@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// BucketStatsReader reports the stats of the data of a bucket by window of time.
+type BucketStatsReader interface {
+	BucketWindowStats(ctx context.Context, orgID, bucketID influxdb.ID, window time.Duration) ([]tsm1.WindowStats, error)
+}
+
+// ShardReader reports the stats of the data of a bucket and the compactions
+// planned for the TSM files holding it.
+type ShardReader interface {
+	BucketStatsReader
+	BucketCompactionPlan(ctx context.Context, orgID, bucketID influxdb.ID) ([]tsm1.CompactionPlanGroup, error)
+}
+
+var _ influxdb.ShardService = (*ShardService)(nil)
+
+// ShardService reports the stats of the shard groups of buckets. Shard
+// groups are the windows of the shard group duration of a bucket.
+type ShardService struct {
+	buckets influxdb.BucketService
+	engine  ShardReader
+}
+
+// NewShardService returns a ShardService reading the stats of the buckets of
+// bs from the engine.
+func NewShardService(bs influxdb.BucketService, engine ShardReader) *ShardService {
+	return &ShardService{
+		buckets: bs,
+		engine:  engine,
+	}
+}
+
+// FindShardGroupStats returns the stats of the shard groups of the bucket
+// holding data, ordered by time.
+func (s *ShardService) FindShardGroupStats(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.ShardGroupStats, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := b.ShardGroupDuration
+	if d <= 0 {
+		d = influxdb.DefaultShardGroupDuration(b.RetentionPeriod)
+	}
+
+	windows, err := s.engine.BucketWindowStats(ctx, b.OrgID, b.ID, d)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindShardGroupStats,
+			Msg:  "unable to read storage stats",
+			Err:  err,
+		}
+	}
+
+	stats := make([]*influxdb.ShardGroupStats, 0, len(windows))
+	for _, w := range windows {
+		stats = append(stats, &influxdb.ShardGroupStats{
+			BucketID:         b.ID,
+			StartTime:        time.Unix(0, w.Min).UTC(),
+			EndTime:          time.Unix(0, w.Max).UTC(),
+			DiskBytes:        w.DiskBytes,
+			TSMFileCount:     w.Files,
+			CompactionLevels: w.FilesByLevel,
+			WALBytes:         w.CacheBytes,
+		})
+	}
+	return stats, nil
+}
+
+// FindCompactionPlan returns the compactions that would run next on the TSM
+// files holding the data of the bucket, without running them.
+func (s *ShardService) FindCompactionPlan(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.CompactionPlanGroup, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.engine.BucketCompactionPlan(ctx, b.OrgID, b.ID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindCompactionPlan,
+			Msg:  "unable to plan compactions",
+			Err:  err,
+		}
+	}
+
+	groups := make([]*influxdb.CompactionPlanGroup, 0, len(plan))
+	for _, g := range plan {
+		files := make([]string, 0, len(g.Files))
+		for _, f := range g.Files {
+			files = append(files, filepath.Base(f.Path))
+		}
+		groups = append(groups, &influxdb.CompactionPlanGroup{
+			Level:          g.Level,
+			Optimize:       g.Optimize,
+			Files:          files,
+			EstimatedBytes: int64(g.EstimatedSize),
+			EstimatedFiles: g.EstimatedFiles,
+		})
+	}
+	return groups, nil
+}
@@ -0,0 +1,129 @@
+package storage_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+type bucketStatsReader struct {
+	window time.Duration
+	stats  []tsm1.WindowStats
+	orgID  influxdb.ID
+	plan   []tsm1.CompactionPlanGroup
+}
+
+func (r *bucketStatsReader) BucketWindowStats(ctx context.Context, orgID, bucketID influxdb.ID, window time.Duration) ([]tsm1.WindowStats, error) {
+	r.window = window
+	return r.stats, nil
+}
+
+func (r *bucketStatsReader) BucketCompactionPlan(ctx context.Context, orgID, bucketID influxdb.ID) ([]tsm1.CompactionPlanGroup, error) {
+	r.orgID = orgID
+	return r.plan, nil
+}
+
+func TestShardService_FindShardGroupStats(t *testing.T) {
+	hour := int64(time.Hour)
+	tests := []struct {
+		name       string
+		bucket     influxdb.Bucket
+		wantWindow time.Duration
+	}{
+		{
+			name:       "shard group duration of bucket",
+			bucket:     influxdb.Bucket{ID: 2, OrgID: 1, ShardGroupDuration: time.Hour, RetentionPeriod: 30 * 24 * time.Hour},
+			wantWindow: time.Hour,
+		},
+		{
+			name:       "default of short retention",
+			bucket:     influxdb.Bucket{ID: 2, OrgID: 1, RetentionPeriod: 24 * time.Hour},
+			wantWindow: time.Hour,
+		},
+		{
+			name:       "default of infinite retention",
+			bucket:     influxdb.Bucket{ID: 2, OrgID: 1},
+			wantWindow: 7 * 24 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				b := tt.bucket
+				return &b, nil
+			}
+			r := &bucketStatsReader{
+				stats: []tsm1.WindowStats{
+					{Min: 0, Max: hour, DiskBytes: 100, Blocks: 2, Files: 1, FilesByLevel: map[int]int{1: 1}, CacheBytes: 10},
+				},
+			}
+
+			stats, err := storage.NewShardService(bs, r).FindShardGroupStats(context.Background(), 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.window != tt.wantWindow {
+				t.Errorf("expected window %s, got %s", tt.wantWindow, r.window)
+			}
+
+			want := []*influxdb.ShardGroupStats{
+				{
+					BucketID:         2,
+					StartTime:        time.Unix(0, 0).UTC(),
+					EndTime:          time.Unix(0, hour).UTC(),
+					DiskBytes:        100,
+					TSMFileCount:     1,
+					CompactionLevels: map[int]int{1: 1},
+					WALBytes:         10,
+				},
+			}
+			if !reflect.DeepEqual(stats, want) {
+				t.Errorf("unexpected stats %+v", stats[0])
+			}
+		})
+	}
+}
+
+func TestShardService_FindCompactionPlan(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: 1}, nil
+	}
+	r := &bucketStatsReader{
+		plan: []tsm1.CompactionPlanGroup{
+			{
+				Level:          2,
+				Files:          []tsm1.FileStat{{Path: "/data/000000001-000000002.tsm", Size: 10}, {Path: "/data/000000005-000000002.tsm", Size: 20}},
+				EstimatedSize:  30,
+				EstimatedFiles: 1,
+			},
+		},
+	}
+
+	plan, err := storage.NewShardService(bs, r).FindCompactionPlan(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.orgID != 1 {
+		t.Errorf("expected the plan of the organization of the bucket, got %s", r.orgID)
+	}
+
+	want := []*influxdb.CompactionPlanGroup{
+		{
+			Level:          2,
+			Files:          []string{"000000001-000000002.tsm", "000000005-000000002.tsm"},
+			EstimatedBytes: 30,
+			EstimatedFiles: 1,
+		},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("unexpected plan %+v", plan[0])
+	}
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// CheckTagConstraints returns a forbidden error if one of the points does not
+// have all of the tags of the constraints of an authorization. The check is
+// done before the points are written, so none of the points of a batch are
+// written when one of them violates the constraints.
+func CheckTagConstraints(constraints []influxdb.Tag, points []models.Point) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	keys := make([][]byte, len(constraints))
+	for i, c := range constraints {
+		keys[i] = []byte(c.Key)
+	}
+	for _, p := range points {
+		tags := p.Tags()
+		for i, c := range constraints {
+			if string(tags.Get(keys[i])) == c.Value {
+				continue
+			}
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Msg:  fmt.Sprintf("point of measurement %q does not have the tag %s=%s required by the token", tags.Get(models.MeasurementTagKeyBytes), c.Key, c.Value),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestCheckTagConstraints(t *testing.T) {
+	name := tsdb.EncodeName(1, 2)
+	constraints := []influxdb.Tag{
+		{Key: "host", Value: "web-01"},
+		{Key: "region", Value: "eu"},
+	}
+
+	tests := []struct {
+		name        string
+		constraints []influxdb.Tag
+		lines       string
+		wantErr     bool
+	}{
+		{
+			name:  "no constraints",
+			lines: "cpu usage=1 10",
+		},
+		{
+			name:        "all points have the tags",
+			constraints: constraints,
+			lines:       "cpu,host=web-01,region=eu usage=1,idle=2 10\nmem,core=1,host=web-01,region=eu used=3 10",
+		},
+		{
+			name:        "point with another tag value",
+			constraints: constraints,
+			lines:       "cpu,host=web-01,region=eu usage=1 10\ncpu,host=web-02,region=eu usage=1 10",
+			wantErr:     true,
+		},
+		{
+			name:        "point without a tag",
+			constraints: constraints,
+			lines:       "cpu,host=web-01 usage=1 10",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := models.ParsePointsWithPrecision([]byte(tt.lines), models.EscapeMeasurement(name[:]), time.Now(), "ns")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = storage.CheckTagConstraints(tt.constraints, points)
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EForbidden {
+					t.Fatalf("expected forbidden error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -28,6 +28,18 @@ func PrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
+// InitMetrics initialises the metrics shared by all WALs with the labels,
+// unless a WAL has done so already. WALs initialise the metrics when they
+// are opened; InitMetrics lets their collectors be registered before any
+// WAL is opened.
+func InitMetrics(labels prometheus.Labels) {
+	mmu.Lock()
+	defer mmu.Unlock()
+	if wms == nil {
+		wms = newWALMetrics(labels)
+	}
+}
+
 // namespace is the leading part of all published metrics for the Storage service.
 const namespace = "storage"
 
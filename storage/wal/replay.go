@@ -0,0 +1,284 @@
+package wal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// keyFieldSeparator separates the series key from the field in the keys of
+// write entries. It must match the separator of the tsm1 engine.
+const keyFieldSeparator = "#!~#"
+
+// ReplayPosition identifies a WAL entry by the sequence number of its
+// segment and its index in the segment.
+type ReplayPosition struct {
+	Segment int `json:"segment"`
+	Entry   int `json:"entry"`
+}
+
+// before returns true if p is before other.
+func (p ReplayPosition) before(other ReplayPosition) bool {
+	return p.Segment < other.Segment || p.Segment == other.Segment && p.Entry < other.Entry
+}
+
+// Replay writes the points of the write entries of WAL segments as line
+// protocol to a write service, such as another instance over the HTTP API.
+//
+// Segments are replayed in the order of their sequence numbers, and a
+// segment whose sequence number was already replayed from another file is
+// skipped. If a checkpoint file is set, the position of the last replayed
+// entry is saved to it after every write, and entries up to that position
+// are skipped when the replay is run again. Writing a point again overwrites
+// it with the same value, so an entry that was partially written before a
+// failure is safely written again.
+type Replay struct {
+	Logger *zap.Logger
+
+	// FileGlobs are the patterns of the segment files to replay.
+	FileGlobs []string
+
+	// Writer receives the points of every entry, one write per bucket.
+	Writer influxdb.WriteService
+
+	// OrgID and BucketID restrict the replay to the points of an
+	// organization or bucket when set.
+	OrgID, BucketID *influxdb.ID
+
+	// CheckpointPath is the file storing the position of the last replayed entry.
+	CheckpointPath string
+}
+
+// ReplayReport summarizes a replay.
+type ReplayReport struct {
+	// Segments is the number of segment files replayed.
+	Segments int
+	// Entries is the number of write entries replayed.
+	Entries int
+	// Points is the number of points written.
+	Points int
+	// SkippedEntries is the number of entries skipped because they were
+	// replayed before, or from another file of the same segment.
+	SkippedEntries int
+	// Deletes is the number of delete entries, which are not replayed.
+	Deletes int
+}
+
+// Run replays the segment files.
+func (r *Replay) Run(ctx context.Context) (*ReplayReport, error) {
+	logger := r.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	files, err := globAndDedupe(r.FileGlobs)
+	if err != nil {
+		return nil, err
+	}
+
+	// dedupe segments by sequence number, so that copies of a segment
+	// collected from several places are replayed once.
+	segments := make(map[int]string, len(files))
+	ids := make([]int, 0, len(files))
+	for _, file := range files {
+		if filepath.Ext(file) != "."+WALFileExtension {
+			return nil, fmt.Errorf("invalid wal filename: %s", file)
+		}
+		id, err := idFromFileName(file)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := segments[id]; ok {
+			logger.Warn("Skipping duplicate segment", zap.String("path", file), zap.String("replayed_path", prev))
+			continue
+		}
+		segments[id] = file
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	last, err := r.readCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{}
+	for _, id := range ids {
+		file := segments[id]
+		if last != nil && id < last.Segment {
+			logger.Info("Skipping replayed segment", zap.String("path", file))
+			continue
+		}
+		logger.Info("Replaying segment", zap.String("path", file))
+		if err := r.replaySegment(ctx, logger, id, file, last, report); err != nil {
+			return report, err
+		}
+		report.Segments++
+	}
+	return report, nil
+}
+
+func (r *Replay) replaySegment(ctx context.Context, logger *zap.Logger, id int, file string, last *ReplayPosition, report *ReplayReport) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	sr := NewWALSegmentReader(f)
+	defer sr.Close()
+
+	for pos := (ReplayPosition{Segment: id}); sr.Next(); pos.Entry++ {
+		entry, err := sr.Read()
+		if err != nil {
+			// the rest of a corrupt segment cannot be read, as with the
+			// WAL of a running engine.
+			logger.Warn("Segment corrupt", zap.String("path", file), zap.Int64("pos", sr.Count()), zap.Error(err))
+			break
+		}
+		if last != nil && !last.before(pos) {
+			report.SkippedEntries++
+			continue
+		}
+
+		switch entry := entry.(type) {
+		case *WriteWALEntry:
+			n, err := r.replayWrite(ctx, entry)
+			if err != nil {
+				return fmt.Errorf("%s: entry %d: %v", file, pos.Entry, err)
+			}
+			report.Entries++
+			report.Points += n
+		case *DeleteBucketRangeWALEntry:
+			logger.Warn("Delete entry not replayed",
+				zap.String("path", file),
+				zap.Int("entry", pos.Entry),
+				zap.Stringer("org_id", entry.OrgID),
+				zap.Stringer("bucket_id", entry.BucketID),
+				zap.Int64("min", entry.Min),
+				zap.Int64("max", entry.Max))
+			report.Deletes++
+		default:
+			return fmt.Errorf("%s: invalid wal entry: %#v", file, entry)
+		}
+
+		if err := r.writeCheckpoint(pos); err != nil {
+			return err
+		}
+	}
+	return sr.Close()
+}
+
+// replayWrite writes the points of the entry grouped by bucket and returns
+// the number of points written.
+func (r *Replay) replayWrite(ctx context.Context, entry *WriteWALEntry) (int, error) {
+	type orgBucket struct{ org, bucket influxdb.ID }
+	buckets := make(map[orgBucket]*bytes.Buffer)
+	var order []orgBucket
+
+	keys := make([]string, 0, len(entry.Values))
+	for k := range entry.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var (
+		tags models.Tags
+		n    int
+	)
+	for _, k := range keys {
+		key := []byte(k)
+		if len(key) < 16 {
+			return 0, fmt.Errorf("invalid key %q", key)
+		}
+		orgID, bucketID := tsdb.DecodeNameSlice(key[:16])
+		if r.OrgID != nil && *r.OrgID != orgID || r.BucketID != nil && *r.BucketID != bucketID {
+			continue
+		}
+
+		seriesKey, field := key, []byte(nil)
+		if i := bytes.Index(key, []byte(keyFieldSeparator)); i >= 0 {
+			seriesKey, field = key[:i], key[i+len(keyFieldSeparator):]
+		}
+		_, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+		measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+
+		// The measurement and field are stored as tags of the series and
+		// are written as the measurement and field of the points instead.
+		pointTags := make(models.Tags, 0, len(tags))
+		for _, t := range tags {
+			if string(t.Key) != models.MeasurementTagKey && string(t.Key) != models.FieldKeyTagKey {
+				pointTags = append(pointTags, t)
+			}
+		}
+
+		ob := orgBucket{org: orgID, bucket: bucketID}
+		buf, ok := buckets[ob]
+		if !ok {
+			buf = new(bytes.Buffer)
+			buckets[ob] = buf
+			order = append(order, ob)
+		}
+		for _, v := range entry.Values[k] {
+			pt, err := models.NewPoint(measurement, pointTags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+			if err != nil {
+				return 0, fmt.Errorf("invalid point for key %q: %v", key, err)
+			}
+			buf.WriteString(pt.String())
+			buf.WriteByte('\n')
+			n++
+		}
+	}
+
+	for _, ob := range order {
+		if err := r.Writer.Write(ctx, ob.org, ob.bucket, buckets[ob]); err != nil {
+			return 0, fmt.Errorf("failed to write to bucket %s of org %s: %v", ob.bucket, ob.org, err)
+		}
+	}
+	return n, nil
+}
+
+// readCheckpoint returns the position of the last replayed entry, or nil
+// if nothing was replayed.
+func (r *Replay) readCheckpoint() (*ReplayPosition, error) {
+	if r.CheckpointPath == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(r.CheckpointPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var pos ReplayPosition
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %v", r.CheckpointPath, err)
+	}
+	return &pos, nil
+}
+
+// writeCheckpoint saves the position of the last replayed entry. The file
+// is replaced atomically, so that a crash leaves the previous position.
+func (r *Replay) writeCheckpoint(pos ReplayPosition) error {
+	if r.CheckpointPath == "" {
+		return nil
+	}
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	tmp := r.CheckpointPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.CheckpointPath)
+}
@@ -0,0 +1,147 @@
+package wal
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/value"
+)
+
+type replayWrite struct {
+	Org, Bucket influxdb.ID
+	Lines       string
+}
+
+type replayWriter struct {
+	writes []replayWrite
+}
+
+func (w *replayWriter) Write(ctx context.Context, org, bucket influxdb.ID, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	w.writes = append(w.writes, replayWrite{Org: org, Bucket: bucket, Lines: string(data)})
+	return nil
+}
+
+func mustWriteSegment(t *testing.T, path string, entries ...WALEntry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWALSegmentWriter(f)
+	for _, entry := range entries {
+		if err := w.Write(mustMarshalEntry(entry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplay_Run(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	copyDir := filepath.Join(dir, "copy")
+	if err := os.Mkdir(copyDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	org, bucket1, bucket2 := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	name1, name2 := tsdb.EncodeName(org, bucket1), tsdb.EncodeName(org, bucket2)
+	key1 := string(name1[:]) + ",\x00=cpu,host=A,\xff=value#!~#value"
+	key2 := string(name2[:]) + ",\x00=mem,host=B,\xff=used#!~#used"
+
+	first := &WriteWALEntry{Values: map[string][]value.Value{
+		key1: {value.NewValue(1, 1.5), value.NewValue(2, 2.5)},
+		key2: {value.NewValue(1, int64(10))},
+	}}
+	second := &WriteWALEntry{Values: map[string][]value.Value{
+		key1: {value.NewValue(3, 3.5)},
+	}}
+	del := &DeleteBucketRangeWALEntry{OrgID: org, BucketID: bucket1, Min: 0, Max: 1}
+
+	mustWriteSegment(t, filepath.Join(dir, "_00001.wal"), first)
+	mustWriteSegment(t, filepath.Join(dir, "_00002.wal"), del, second)
+	// a copy of the first segment, e.g. collected from a backup.
+	mustWriteSegment(t, filepath.Join(copyDir, "_00001.wal"), first)
+
+	w := &replayWriter{}
+	replay := &Replay{
+		FileGlobs:      []string{filepath.Join(dir, "*.wal"), filepath.Join(copyDir, "*.wal")},
+		Writer:         w,
+		CheckpointPath: filepath.Join(dir, "checkpoint"),
+	}
+	report, err := replay.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantWrites := []replayWrite{
+		{Org: org, Bucket: bucket1, Lines: "cpu,host=A value=1.5 1\ncpu,host=A value=2.5 2\n"},
+		{Org: org, Bucket: bucket2, Lines: "mem,host=B used=10i 1\n"},
+		{Org: org, Bucket: bucket1, Lines: "cpu,host=A value=3.5 3\n"},
+	}
+	if diff := cmp.Diff(w.writes, wantWrites); diff != "" {
+		t.Fatalf("unexpected writes -got/+want\n%s", diff)
+	}
+	wantReport := &ReplayReport{Segments: 2, Entries: 2, Points: 4, Deletes: 1}
+	if diff := cmp.Diff(report, wantReport); diff != "" {
+		t.Fatalf("unexpected report -got/+want\n%s", diff)
+	}
+
+	// entries up to the checkpoint are not replayed again.
+	w.writes = nil
+	report, err = replay.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.writes) != 0 {
+		t.Fatalf("unexpected writes after checkpoint: %v", w.writes)
+	}
+	if want := (&ReplayReport{Segments: 1, SkippedEntries: 2}); !cmp.Equal(report, want) {
+		t.Fatalf("unexpected report -got/+want\n%s", cmp.Diff(report, want))
+	}
+}
+
+func TestReplay_Run_Bucket(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	org, bucket1, bucket2 := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	name1, name2 := tsdb.EncodeName(org, bucket1), tsdb.EncodeName(org, bucket2)
+	mustWriteSegment(t, filepath.Join(dir, "_00001.wal"), &WriteWALEntry{Values: map[string][]value.Value{
+		string(name1[:]) + ",\x00=cpu,\xff=value#!~#value": {value.NewValue(1, 1.5)},
+		string(name2[:]) + ",\x00=cpu,\xff=value#!~#value": {value.NewValue(1, 2.5)},
+	}})
+
+	w := &replayWriter{}
+	replay := &Replay{
+		FileGlobs: []string{filepath.Join(dir, "*.wal")},
+		Writer:    w,
+		BucketID:  &bucket2,
+	}
+	if _, err := replay.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wantWrites := []replayWrite{
+		{Org: org, Bucket: bucket2, Lines: "cpu value=2.5 1\n"},
+	}
+	if diff := cmp.Diff(w.writes, wantWrites); diff != "" {
+		t.Fatalf("unexpected writes -got/+want\n%s", diff)
+	}
+}
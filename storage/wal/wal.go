@@ -178,11 +178,7 @@ func (l *WAL) Open(ctx context.Context) error {
 		"path", l.path)
 
 	// Initialise metrics for trackers.
-	mmu.Lock()
-	if wms == nil {
-		wms = newWALMetrics(l.defaultMetricLabels)
-	}
-	mmu.Unlock()
+	InitMetrics(l.defaultMetricLabels)
 
 	// Set the shared metrics for the tracker
 	l.tracker = newWALTracker(wms, l.defaultMetricLabels)
@@ -45,7 +45,7 @@ type Task struct {
 	Type            string                 `json:"type,omitempty"`
 	OrganizationID  ID                     `json:"orgID"`
 	Organization    string                 `json:"org"`
-	AuthorizationID ID                     `json:"-"`
+	AuthorizationID ID                     `json:"scopedAuthorizationID,omitempty"`
 	Authorization   *Authorization         `json:"-"`
 	OwnerID         ID                     `json:"ownerID"`
 	Name            string                 `json:"name"`
@@ -0,0 +1,316 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultBackfillConcurrency is the default number of runs of a backfill that are in flight at once.
+	defaultBackfillConcurrency = 4
+
+	// defaultBackfillMaxRuns is the default maximum number of runs a single backfill may cover.
+	defaultBackfillMaxRuns = 10000
+
+	// defaultBackfillPollInterval is the default interval at which the status of the enqueued runs is checked.
+	defaultBackfillPollInterval = time.Second
+)
+
+var _ influxdb.TaskBackfillService = (*BackfillService)(nil)
+
+// BackfillServiceOption is a option you can use to modify the BackfillService.
+type BackfillServiceOption func(*BackfillService)
+
+// WithBackfillConcurrency sets the number of runs of a backfill that may be
+// enqueued but not yet finished at the same time.
+func WithBackfillConcurrency(n int) BackfillServiceOption {
+	return func(s *BackfillService) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
+
+// WithBackfillMaxRuns sets the maximum number of runs a single backfill may cover.
+func WithBackfillMaxRuns(n int) BackfillServiceOption {
+	return func(s *BackfillService) {
+		if n > 0 {
+			s.maxRuns = n
+		}
+	}
+}
+
+// WithBackfillPollInterval sets the interval at which the status of the enqueued runs is checked.
+func WithBackfillPollInterval(d time.Duration) BackfillServiceOption {
+	return func(s *BackfillService) {
+		if d > 0 {
+			s.pollInterval = d
+		}
+	}
+}
+
+// BackfillService enqueues manual runs of a task for every time the task was
+// scheduled in a historical range. At most concurrency runs of a backfill
+// are in flight at once; the next run is enqueued when one of them finished.
+// The progress of backfills is kept in memory.
+type BackfillService struct {
+	logger *zap.Logger
+	ts     influxdb.TaskService
+
+	concurrency  int
+	maxRuns      int
+	pollInterval time.Duration
+
+	mu        sync.Mutex
+	backfills map[influxdb.ID]*backfill
+}
+
+type backfill struct {
+	// progress is guarded by the mutex of the BackfillService.
+	progress influxdb.TaskBackfill
+	cancel   context.CancelFunc
+}
+
+// NewBackfillService returns a BackfillService that enqueues runs with the
+// ForceRun method of the task service.
+func NewBackfillService(logger *zap.Logger, ts influxdb.TaskService, opts ...BackfillServiceOption) *BackfillService {
+	s := &BackfillService{
+		logger:       logger,
+		ts:           ts,
+		concurrency:  defaultBackfillConcurrency,
+		maxRuns:      defaultBackfillMaxRuns,
+		pollInterval: defaultBackfillPollInterval,
+		backfills:    make(map[influxdb.ID]*backfill),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// BackfillTask starts enqueuing a run of the task for every time the task is
+// scheduled in the range [start, stop).
+func (s *BackfillService) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	if !start.Before(stop) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpBackfillTask,
+			Msg:  "backfill start must be before stop",
+		}
+	}
+
+	task, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := s.scheduledTimes(task, start, stop)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpBackfillTask,
+			Err:  err,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b, ok := s.backfills[taskID]; ok && b.progress.Status == influxdb.TaskBackfillRunning {
+		return nil, influxdb.ErrTaskBackfillInProgress
+	}
+
+	bctx, cancel := context.WithCancel(context.Background())
+	b := &backfill{
+		progress: influxdb.TaskBackfill{
+			TaskID:    taskID,
+			OrgID:     task.OrganizationID,
+			Start:     start.UTC(),
+			Stop:      stop.UTC(),
+			Status:    influxdb.TaskBackfillRunning,
+			Total:     len(times),
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	s.backfills[taskID] = b
+	go s.run(bctx, b, times)
+
+	progress := b.progress
+	return &progress, nil
+}
+
+// FindTaskBackfill returns the progress of the latest backfill of the task.
+func (s *BackfillService) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.backfills[taskID]
+	if !ok {
+		return nil, influxdb.ErrTaskBackfillNotFound
+	}
+	progress := b.progress
+	return &progress, nil
+}
+
+// CancelTaskBackfill stops enqueuing the runs of the backfill in progress of the task.
+func (s *BackfillService) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.backfills[taskID]
+	if !ok || b.progress.Status != influxdb.TaskBackfillRunning {
+		return influxdb.ErrTaskBackfillNotFound
+	}
+	s.finish(b, influxdb.TaskBackfillCanceled, "")
+	return nil
+}
+
+// scheduledTimes returns the times the task is scheduled in the range [start, stop).
+func (s *BackfillService) scheduledTimes(task *influxdb.Task, start, stop time.Time) ([]time.Time, error) {
+	effCron := task.EffectiveCron()
+	if effCron == "" {
+		return nil, fmt.Errorf("task %s has no schedule", task.ID)
+	}
+	sch, err := scheduler.NewSchedule(effCron)
+	if err != nil {
+		return nil, err
+	}
+
+	// the schedule is in seconds and Next is exclusive, so start one second early to include start.
+	from := start.UTC().Add(-time.Second)
+	if task.Cron == "" {
+		// every is relative to the time it is applied to, so run at the multiples of every since the epoch.
+		epoch := time.Unix(0, 0).UTC()
+		next, err := sch.Next(epoch)
+		if err != nil {
+			return nil, err
+		}
+		every := next.Sub(epoch)
+		first := start.UTC().Truncate(every)
+		if first.Before(start) {
+			first = first.Add(every)
+		}
+		from = first.Add(-every)
+	}
+
+	var times []time.Time
+	t, err := sch.Next(from)
+	for ; err == nil && t.Before(stop); t, err = sch.Next(t) {
+		if len(times) == s.maxRuns {
+			return nil, fmt.Errorf("backfill range covers more than %d runs", s.maxRuns)
+		}
+		times = append(times, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("task %s is not scheduled between %s and %s", task.ID, start.Format(time.RFC3339), stop.Format(time.RFC3339))
+	}
+	return times, nil
+}
+
+// run enqueues the runs of the backfill, waiting for a run to finish whenever
+// concurrency runs are in flight.
+func (s *BackfillService) run(ctx context.Context, b *backfill, times []time.Time) {
+	taskID := b.progress.TaskID
+	logger := s.logger.With(zap.String("task_id", taskID.String()))
+
+	// the runs in flight stop being waited for when the backfill is canceled or failed.
+	var wg sync.WaitGroup
+	inFlight := make(chan struct{}, s.concurrency)
+	for _, t := range times {
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		run, err := s.ts.ForceRun(ctx, taskID, t.Unix())
+		if err == influxdb.ErrTaskRunAlreadyQueued {
+			s.update(b, func(p *influxdb.TaskBackfill) { p.Skipped++ })
+			<-inFlight
+			continue
+		}
+		if err != nil {
+			logger.Info("Failed to enqueue backfill run", zap.Time("scheduled_for", t), zap.Error(err))
+			s.mu.Lock()
+			s.finish(b, influxdb.TaskBackfillFailed, err.Error())
+			s.mu.Unlock()
+			return
+		}
+		s.update(b, func(p *influxdb.TaskBackfill) { p.Enqueued++ })
+
+		wg.Add(1)
+		go func(runID influxdb.ID) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			status, ok := s.wait(ctx, taskID, runID)
+			if !ok {
+				return
+			}
+			s.update(b, func(p *influxdb.TaskBackfill) {
+				if status == RunSuccess.String() {
+					p.Succeeded++
+				} else {
+					p.Failed++
+				}
+			})
+		}(run.ID)
+	}
+
+	wg.Wait()
+	s.mu.Lock()
+	s.finish(b, influxdb.TaskBackfillCompleted, "")
+	s.mu.Unlock()
+}
+
+// wait polls the run until it finished and returns its final status. It
+// returns false when the context is done first.
+func (s *BackfillService) wait(ctx context.Context, taskID, runID influxdb.ID) (string, bool) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// runs that are still queued are not found.
+		if r, err := s.ts.FindRunByID(ctx, taskID, runID); err == nil {
+			switch r.Status {
+			case RunSuccess.String(), RunFail.String(), RunCanceled.String():
+				return r.Status, true
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+func (s *BackfillService) update(b *backfill, fn func(*influxdb.TaskBackfill)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&b.progress)
+}
+
+// finish must be called with the mutex of the BackfillService held. Only the
+// first status a backfill finishes with is kept.
+func (s *BackfillService) finish(b *backfill, status, msg string) {
+	if b.progress.Status != influxdb.TaskBackfillRunning {
+		return
+	}
+	now := time.Now().UTC()
+	b.progress.Status = status
+	b.progress.FinishedAt = &now
+	b.progress.Error = msg
+	b.cancel()
+}
@@ -0,0 +1,166 @@
+package backend_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend"
+	"go.uber.org/zap/zaptest"
+)
+
+// backfillTaskService is a task service that finishes every forced run on
+// the first lookup and tracks how many runs are in flight.
+type backfillTaskService struct {
+	mock.TaskService
+
+	mu          sync.Mutex
+	scheduled   []time.Time
+	inFlight    int
+	maxInFlight int
+	nextID      influxdb.ID
+}
+
+func newBackfillTaskService(task *influxdb.Task) *backfillTaskService {
+	s := &backfillTaskService{}
+	s.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		if id != task.ID {
+			return nil, influxdb.ErrTaskNotFound
+		}
+		return task, nil
+	}
+	s.ForceRunFn = func(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.nextID++
+		s.inFlight++
+		if s.inFlight > s.maxInFlight {
+			s.maxInFlight = s.inFlight
+		}
+		s.scheduled = append(s.scheduled, time.Unix(scheduledFor, 0).UTC())
+		return &influxdb.Run{ID: s.nextID, TaskID: taskID, Status: backend.RunScheduled.String()}, nil
+	}
+	s.FindRunByIDFn = func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.inFlight--
+		status := backend.RunSuccess
+		if runID%2 == 0 {
+			status = backend.RunFail
+		}
+		return &influxdb.Run{ID: runID, TaskID: taskID, Status: status.String()}, nil
+	}
+	return s
+}
+
+func waitForBackfill(t *testing.T, s *backend.BackfillService, taskID influxdb.ID) *influxdb.TaskBackfill {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		b, err := s.FindTaskBackfill(context.Background(), taskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Status != influxdb.TaskBackfillRunning {
+			return b
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("backfill did not finish in time")
+	return nil
+}
+
+func TestBackfillService_BackfillTask(t *testing.T) {
+	task := &influxdb.Task{ID: 1, OrganizationID: 2, Every: "1h"}
+	ts := newBackfillTaskService(task)
+	s := backend.NewBackfillService(zaptest.NewLogger(t), ts,
+		backend.WithBackfillConcurrency(2),
+		backend.WithBackfillPollInterval(time.Millisecond))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b, err := s.BackfillTask(context.Background(), task.ID, start, start.Add(6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Total != 6 || b.OrgID != task.OrganizationID {
+		t.Fatalf("unexpected backfill %+v", b)
+	}
+
+	b = waitForBackfill(t, s, task.ID)
+	if b.Status != influxdb.TaskBackfillCompleted {
+		t.Fatalf("expected backfill to complete, got %q: %s", b.Status, b.Error)
+	}
+	if b.Enqueued != 6 || b.Succeeded != 3 || b.Failed != 3 {
+		t.Fatalf("unexpected backfill progress %+v", b)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.maxInFlight > 2 {
+		t.Errorf("expected at most 2 runs in flight, got %d", ts.maxInFlight)
+	}
+	for i, got := range ts.scheduled {
+		if want := start.Add(time.Duration(i) * time.Hour); !got.Equal(want) {
+			t.Errorf("expected run %d scheduled for %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestBackfillService_BackfillTaskInvalid(t *testing.T) {
+	task := &influxdb.Task{ID: 1, OrganizationID: 2, Cron: "0 0 * * *"}
+	s := backend.NewBackfillService(zaptest.NewLogger(t), newBackfillTaskService(task), backend.WithBackfillMaxRuns(10))
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		start, stop time.Time
+	}{
+		{name: "stop before start", start: start, stop: start.Add(-time.Hour)},
+		{name: "no scheduled run", start: start, stop: start.Add(time.Hour)},
+		{name: "too many runs", start: start, stop: start.Add(30 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.BackfillTask(context.Background(), task.ID, tt.start, tt.stop)
+			if influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected invalid error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := s.FindTaskBackfill(context.Background(), task.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestBackfillService_CancelTaskBackfill(t *testing.T) {
+	task := &influxdb.Task{ID: 1, OrganizationID: 2, Every: "1m"}
+	ts := newBackfillTaskService(task)
+	// runs never finish.
+	ts.FindRunByIDFn = func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+		return nil, influxdb.ErrRunNotFound
+	}
+	s := backend.NewBackfillService(zaptest.NewLogger(t), ts, backend.WithBackfillPollInterval(time.Millisecond))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.BackfillTask(context.Background(), task.ID, start, start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.BackfillTask(context.Background(), task.ID, start, start.Add(time.Hour)); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	if err := s.CancelTaskBackfill(context.Background(), task.ID); err != nil {
+		t.Fatal(err)
+	}
+	b := waitForBackfill(t, s, task.ID)
+	if b.Status != influxdb.TaskBackfillCanceled || b.FinishedAt == nil {
+		t.Fatalf("expected backfill to be canceled, got %+v", b)
+	}
+	if err := s.CancelTaskBackfill(context.Background(), task.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
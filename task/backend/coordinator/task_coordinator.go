@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/influxdb/task/backend/executor"
 	"github.com/influxdata/influxdb/task/backend/middleware"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
@@ -39,7 +40,8 @@ type CoordinatorOption func(*TaskCoordinator)
 // SchedulableTask is a wrapper around the Task struct, giving it methods to make it compatible with the Scheduler
 type SchedulableTask struct {
 	*influxdb.Task
-	sch scheduler.Schedule
+	sch    scheduler.Schedule
+	policy scheduler.MissedRunPolicy
 }
 
 func (t SchedulableTask) ID() scheduler.ID {
@@ -68,6 +70,11 @@ func (t SchedulableTask) LastScheduled() time.Time {
 	return t.CreatedAt
 }
 
+// MissedRunPolicy returns the missed run policy from the Task's options
+func (t SchedulableTask) MissedRunPolicy() scheduler.MissedRunPolicy {
+	return t.policy
+}
+
 func WithLimitOpt(i int) CoordinatorOption {
 	return func(c *TaskCoordinator) {
 		c.limit = i
@@ -87,6 +94,10 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 	}
 
 	t := SchedulableTask{Task: task, sch: sch}
+	// the options of the task were validated when it was stored.
+	if opts, err := options.FromScript(task.Flux); err == nil {
+		t.policy = scheduler.MissedRunPolicy(opts.MissedRunPolicy)
+	}
 	return t, nil
 }
 
@@ -143,7 +154,7 @@ func (c *TaskCoordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Ta
 	return nil
 }
 
-//TaskDeleted asks the Scheduler to release the deleted task
+// TaskDeleted asks the Scheduler to release the deleted task
 func (c *TaskCoordinator) TaskDeleted(ctx context.Context, id influxdb.ID) error {
 	tid := scheduler.ID(id)
 	if err := c.sch.Release(tid); err != nil && err != influxdb.ErrTaskNotClaimed {
@@ -23,6 +23,7 @@ type runCollector struct {
 	totalRunsActive   *prometheus.Desc
 	workersBusy       *prometheus.Desc
 	promiseQueueUsage *prometheus.Desc
+	poolRunsActive    *prometheus.Desc
 	te                *TaskExecutor
 }
 
@@ -107,6 +108,12 @@ func NewRunCollector(te *TaskExecutor) prometheus.Collector {
 			nil,
 			prometheus.Labels{},
 		),
+		poolRunsActive: prometheus.NewDesc(
+			"task_executor_pool_runs_active",
+			"Number of workers currently running tasks by worker pool",
+			[]string{"pool"},
+			prometheus.Labels{},
+		),
 		te: te,
 	}
 }
@@ -166,6 +173,7 @@ func (r *runCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- r.workersBusy
 	ch <- r.promiseQueueUsage
 	ch <- r.totalRunsActive
+	ch <- r.poolRunsActive
 }
 
 // Collect returns the current state of all metrics of the run collector.
@@ -175,4 +183,8 @@ func (r *runCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(r.promiseQueueUsage, prometheus.GaugeValue, r.te.PromiseQueueUsage())
 
 	ch <- prometheus.MustNewConstMetric(r.totalRunsActive, prometheus.GaugeValue, float64(r.te.RunsActive()))
+
+	for pool, active := range r.te.PoolRunsActive() {
+		ch <- prometheus.MustNewConstMetric(r.poolRunsActive, prometheus.GaugeValue, float64(active), pool)
+	}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
@@ -42,8 +43,30 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 // LimitFunc is a function the executor will use to
 type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
+const (
+	// DefaultWorkerPool is the pool that runs the tasks which do not declare
+	// a pool in their options.
+	DefaultWorkerPool = "default"
+
+	defaultPoolWorkers = 100
+	poolQueueSize      = 1000 //TODO(lh): make this configurable
+)
+
+type executorOptFunc func(e *TaskExecutor)
+
+// WithWorkerPools adds named worker pools, each running at most its number
+// of workers runs at once. Tasks select a pool with the pool task option.
+// Including the DefaultWorkerPool changes the number of its workers.
+func WithWorkerPools(pools map[string]int) executorOptFunc {
+	return func(e *TaskExecutor) {
+		for name, workers := range pools {
+			e.pools[name] = newRunPool(name, workers)
+		}
+	}
+}
+
 // NewExecutor creates a new task executor
-func NewExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService) (*TaskExecutor, *ExecutorMetrics) {
+func NewExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService, opts ...executorOptFunc) (*TaskExecutor, *ExecutorMetrics) {
 	te := &TaskExecutor{
 		logger: logger,
 		ts:     ts,
@@ -52,9 +75,14 @@ func NewExecutor(logger *zap.Logger, qs query.QueryService, as influxdb.Authoriz
 		as:     as,
 
 		currentPromises: sync.Map{},
-		promiseQueue:    make(chan *promise, 1000),                                //TODO(lh): make this configurable
-		workerLimit:     make(chan struct{}, 100),                                 //TODO(lh): make this configurable
-		limitFunc:       func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
+		pools: map[string]*runPool{
+			DefaultWorkerPool: newRunPool(DefaultWorkerPool, defaultPoolWorkers),
+		},
+		limitFunc: func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
+	}
+
+	for _, opt := range opts {
+		opt(te)
 	}
 
 	te.metrics = NewExecutorMetrics(te)
@@ -81,14 +109,32 @@ type TaskExecutor struct {
 	// currentPromises are all the promises we are made that have not been fulfilled
 	currentPromises sync.Map
 
-	// keep a pool of promise's we have in queue
-	promiseQueue chan *promise
+	// pools are the named pools that queue and work the promises of the
+	// tasks which declare them.
+	pools map[string]*runPool
 
 	limitFunc LimitFunc
 
 	// keep a pool of execution workers.
-	workerPool  sync.Pool
-	workerLimit chan struct{}
+	workerPool sync.Pool
+}
+
+// runPool queues the promises of the tasks in a pool and limits the number
+// of workers that work them at once.
+type runPool struct {
+	name string
+
+	// keep a pool of promise's we have in queue
+	promiseQueue chan *promise
+	workerLimit  chan struct{}
+}
+
+func newRunPool(name string, workers int) *runPool {
+	return &runPool{
+		name:         name,
+		promiseQueue: make(chan *promise, poolQueueSize),
+		workerLimit:  make(chan struct{}, workers),
+	}
 }
 
 // SetLimitFunc sets the limit func for this task executor
@@ -115,7 +161,7 @@ func (e *TaskExecutor) PromisedExecute(ctx context.Context, id scheduler.ID, sch
 		return nil, err
 	}
 
-	e.startWorker()
+	e.startWorker(p.pool)
 	return p, nil
 }
 
@@ -126,8 +172,11 @@ func (e *TaskExecutor) ManualRun(ctx context.Context, id influxdb.ID, runID infl
 		return nil, err
 	}
 	p, err := e.createPromise(ctx, r)
+	if err != nil {
+		return nil, err
+	}
 
-	e.startWorker()
+	e.startWorker(p.pool)
 	e.metrics.manualRunsCounter.WithLabelValues(id.String()).Inc()
 	return p, err
 }
@@ -145,10 +194,13 @@ func (e *TaskExecutor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, run
 			}
 
 			p, err := e.createPromise(ctx, run)
+			if err != nil {
+				return nil, err
+			}
 
-			e.startWorker()
+			e.startWorker(p.pool)
 			e.metrics.resumeRunsCounter.WithLabelValues(id.String()).Inc()
-			return p, err
+			return p, nil
 		}
 	}
 	return nil, influxdb.ErrRunNotFound
@@ -163,10 +215,10 @@ func (e *TaskExecutor) createRun(ctx context.Context, id influxdb.ID, scheduledA
 	return e.createPromise(ctx, r)
 }
 
-func (e *TaskExecutor) startWorker() {
+func (e *TaskExecutor) startWorker(pool *runPool) {
 	// see if have available workers
 	select {
-	case e.workerLimit <- struct{}{}:
+	case pool.workerLimit <- struct{}{}:
 	default:
 		// we have reached our worker limit and we cannot start any more.
 		return
@@ -178,10 +230,10 @@ func (e *TaskExecutor) startWorker() {
 		go func() {
 			// don't forget to put the worker back when we are done
 			defer e.workerPool.Put(worker)
-			worker.work()
+			worker.work(pool)
 
 			// remove a struct from the worker limit to another worker to work
-			<-e.workerLimit
+			<-pool.workerLimit
 		}()
 	}
 }
@@ -216,6 +268,7 @@ func (e *TaskExecutor) createPromise(ctx context.Context, run *influxdb.Run) (*p
 		run:        run,
 		task:       t,
 		auth:       t.Authorization,
+		pool:       e.taskPool(ctx, t, run),
 		createdAt:  time.Now().UTC(),
 		done:       make(chan struct{}),
 		ctx:        ctx,
@@ -224,13 +277,30 @@ func (e *TaskExecutor) createPromise(ctx context.Context, run *influxdb.Run) (*p
 
 	// insert promise into queue to be worked
 	// when the queue gets full we will hand and apply back pressure to the scheduler
-	e.promiseQueue <- p
+	p.pool.promiseQueue <- p
 
 	// insert the promise into the registry
 	e.currentPromises.Store(run.ID, p)
 	return p, nil
 }
 
+// taskPool returns the pool declared by the options of the task. Tasks that
+// declare no pool or a pool the executor does not have run in the default pool.
+func (e *TaskExecutor) taskPool(ctx context.Context, t *influxdb.Task, run *influxdb.Run) *runPool {
+	o, err := options.FromScript(t.Flux)
+	if err != nil || o.Pool == "" {
+		// the run fails when the script is invalid.
+		return e.pools[DefaultWorkerPool]
+	}
+
+	pool, ok := e.pools[o.Pool]
+	if !ok {
+		e.tcs.AddRunLog(ctx, t.ID, run.ID, time.Now().UTC(), fmt.Sprintf("Unknown worker pool %q, running in the %s pool", o.Pool, DefaultWorkerPool))
+		return e.pools[DefaultWorkerPool]
+	}
+	return pool
+}
+
 type workerMaker struct {
 	te *TaskExecutor
 }
@@ -247,13 +317,13 @@ type worker struct {
 	exhaustResultIterators func(res flux.Result) error
 }
 
-func (w *worker) work() {
+func (w *worker) work(pool *runPool) {
 	// loop until we have no more work to do in the promise queue
 	for {
 		var prom *promise
 		// check to see if we can execute
 		select {
-		case p, ok := <-w.te.promiseQueue:
+		case p, ok := <-pool.promiseQueue:
 
 			if !ok {
 				// the promiseQueue has been closed
@@ -424,17 +494,40 @@ func (w *worker) executeQuery(p *promise) {
 // RunsActive returns the current number of workers, which is equivalent to
 // the number of runs actively running
 func (e *TaskExecutor) RunsActive() int {
-	return len(e.workerLimit)
+	var n int
+	for _, pool := range e.pools {
+		n += len(pool.workerLimit)
+	}
+	return n
 }
 
 // WorkersBusy returns the percent of total workers that are busy
 func (e *TaskExecutor) WorkersBusy() float64 {
-	return float64(len(e.workerLimit)) / float64(cap(e.workerLimit))
+	var busy, total int
+	for _, pool := range e.pools {
+		busy += len(pool.workerLimit)
+		total += cap(pool.workerLimit)
+	}
+	return float64(busy) / float64(total)
 }
 
 // PromiseQueueUsage returns the percent of the Promise Queue that is currently filled
 func (e *TaskExecutor) PromiseQueueUsage() float64 {
-	return float64(len(e.promiseQueue)) / float64(cap(e.promiseQueue))
+	var queued, total int
+	for _, pool := range e.pools {
+		queued += len(pool.promiseQueue)
+		total += cap(pool.promiseQueue)
+	}
+	return float64(queued) / float64(total)
+}
+
+// PoolRunsActive returns the number of runs actively running in each pool.
+func (e *TaskExecutor) PoolRunsActive() map[string]int {
+	active := make(map[string]int, len(e.pools))
+	for name, pool := range e.pools {
+		active[name] = len(pool.workerLimit)
+	}
+	return active
 }
 
 // promise represents a promise the executor makes to finish a run's execution asynchronously.
@@ -442,6 +535,7 @@ type promise struct {
 	run  *influxdb.Run
 	task *influxdb.Task
 	auth *influxdb.Authorization
+	pool *runPool
 
 	done chan struct{}
 	err  error
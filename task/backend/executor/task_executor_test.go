@@ -54,6 +54,7 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("ManualRun", testManualRun)
 	t.Run("ResumeRun", testResumingRun)
 	t.Run("WorkerLimit", testWorkerLimit)
+	t.Run("WorkerPools", testWorkerPools)
 	t.Run("LimitFunc", testLimitFunc)
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
@@ -244,7 +245,7 @@ func testWorkerLimit(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(tes.ex.workerLimit) != 1 {
+	if len(tes.ex.pools[DefaultWorkerPool].workerLimit) != 1 {
 		t.Fatal("expected a worker to be started")
 	}
 
@@ -258,6 +259,53 @@ func testWorkerLimit(t *testing.T) {
 	}
 }
 
+func testWorkerPools(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	WithWorkerPools(map[string]int{"critical": 1})(tes.ex)
+
+	const fmtPoolScript = `
+option task = {
+			name: %q,
+			every: 1m,
+			pool: %q,
+}
+
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	for _, tt := range []struct {
+		pool string
+		want string
+	}{
+		{pool: "critical", want: "critical"},
+		{pool: "unknown", want: DefaultWorkerPool},
+	} {
+		script := fmt.Sprintf(fmtPoolScript, t.Name()+tt.pool, tt.pool)
+		task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tes.svc.WaitForQueryLive(t, script)
+		if active := tes.ex.PoolRunsActive(); active[tt.want] != 1 {
+			t.Fatalf("expected a worker of the %s pool to be started, got %v", tt.want, active)
+		}
+
+		tes.svc.SucceedQuery(script)
+		<-promise.Done()
+
+		if got := promise.Error(); got != nil {
+			t.Fatal(got)
+		}
+	}
+}
+
 func testLimitFunc(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
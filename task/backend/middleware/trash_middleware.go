@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// CoordinatingTrashService acts as a TrashService decorator that schedules
+// restored tasks with the coordinator.
+type CoordinatingTrashService struct {
+	influxdb.TrashService
+	coordinator Coordinator
+}
+
+// NewTrashService constructs a new coordinating trash service
+func NewTrashService(ts influxdb.TrashService, coordinator Coordinator) *CoordinatingTrashService {
+	return &CoordinatingTrashService{
+		TrashService: ts,
+		coordinator:  coordinator,
+	}
+}
+
+// RestoreTask restores a task from the trash and publishes it so it can be scheduled again.
+func (s *CoordinatingTrashService) RestoreTask(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+	t, err := s.TrashService.RestoreTask(ctx, id)
+	if err != nil {
+		return t, err
+	}
+
+	if t.Status != string(backend.TaskActive) {
+		return t, nil
+	}
+
+	return t, s.coordinator.TaskCreated(ctx, t)
+}
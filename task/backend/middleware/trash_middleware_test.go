@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pmock "github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/coordinator"
+	"github.com/influxdata/influxdb/task/backend/middleware"
+	"github.com/influxdata/influxdb/task/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCoordinatingTrashService_RestoreTask(t *testing.T) {
+	var (
+		ts         = pmock.NewTrashService()
+		sched      = mock.NewScheduler()
+		coord      = coordinator.New(zaptest.NewLogger(t), sched)
+		middleware = middleware.NewTrashService(ts, coord)
+		createChan = sched.TaskCreateChan()
+	)
+
+	status := string(backend.TaskActive)
+	ts.RestoreTaskFn = func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+		return &platform.Task{ID: id, OrganizationID: 1, Flux: script, Status: status}, nil
+	}
+
+	task, err := middleware.RestoreTask(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoredTask, err := timeoutSelector(createChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != restoredTask.ID {
+		t.Fatal("task given to scheduler not the same as task restored")
+	}
+
+	status = string(backend.TaskInactive)
+	if _, err := middleware.RestoreTask(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case task := <-createChan:
+		t.Fatalf("inactive task %s given to scheduler", task.ID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/influxdata/cron"
@@ -40,6 +41,35 @@ type Schedulable interface {
 	// LastScheduled specifies last time this Schedulable was queued
 	// for execution.
 	LastScheduled() time.Time
+
+	// MissedRunPolicy defines how the runs this Schedulable missed, while
+	// the scheduler was down or its clock was adjusted, are executed.
+	MissedRunPolicy() MissedRunPolicy
+}
+
+// MissedRunPolicy defines how the scheduler executes the runs of a
+// Schedulable that are overdue by more than the missed run tolerance.
+type MissedRunPolicy string
+
+const (
+	// MissedRunBackfillAll executes every missed run in order. It is the
+	// policy of Schedulables that do not define one.
+	MissedRunBackfillAll MissedRunPolicy = "backfill-all"
+	// MissedRunOnce executes only the latest missed run.
+	MissedRunOnce MissedRunPolicy = "run-once"
+	// MissedRunSkip executes none of the missed runs.
+	MissedRunSkip MissedRunPolicy = "skip"
+)
+
+// Valid returns an error if the policy is unknown. The empty policy is valid
+// and means MissedRunBackfillAll.
+func (p MissedRunPolicy) Valid() error {
+	switch p {
+	case "", MissedRunBackfillAll, MissedRunOnce, MissedRunSkip:
+		return nil
+	default:
+		return fmt.Errorf("invalid missed run policy %q; must be %s, %s or %s", p, MissedRunSkip, MissedRunOnce, MissedRunBackfillAll)
+	}
 }
 
 // SchedulableService encapsulates the work necessary to schedule a job
@@ -12,6 +12,7 @@ type SchedulerMetrics struct {
 	scheduleCalls       prometheus.Counter
 	scheduleFails       prometheus.Counter
 	releaseCalls        prometheus.Counter
+	missedRunsCalls     *prometheus.CounterVec
 
 	executingTasks *executingTasks
 	scheduleDelay  prometheus.Summary
@@ -60,12 +61,18 @@ func NewSchedulerMetrics(te *TreeScheduler) *SchedulerMetrics {
 			Name:      "total_release_calls",
 			Help:      "Total number of release requests.",
 		}),
+		missedRunsCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "total_missed_runs",
+			Help:      "Total number of times the missed run policy was applied to overdue runs, by policy.",
+		}, []string{"policy"}),
 		executingTasks: newExecutingTasks(te),
 		scheduleDelay: prometheus.NewSummary(prometheus.SummaryOpts{
 			Namespace:  namespace,
 			Subsystem:  subsystem,
 			Name:       "schedule_delay",
-			Help:       "The duration between when a Item should be scheduled and when it is told to execute.",
+			Help:       "The duration in seconds between when a Item should be scheduled and when it is told to execute.",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 		}),
 
@@ -87,6 +94,7 @@ func (em *SchedulerMetrics) PrometheusCollectors() []prometheus.Collector {
 		em.scheduleCalls,
 		em.scheduleFails,
 		em.releaseCalls,
+		em.missedRunsCalls,
 		em.executingTasks,
 		em.scheduleDelay,
 		em.executeDelta,
@@ -105,6 +113,10 @@ func (em *SchedulerMetrics) release(taskID ID) {
 	em.releaseCalls.Inc()
 }
 
+func (em *SchedulerMetrics) missedRuns(policy MissedRunPolicy) {
+	em.missedRunsCalls.WithLabelValues(string(policy)).Inc()
+}
+
 func (em *SchedulerMetrics) reportScheduleDelay(d time.Duration) {
 	em.scheduleDelay.Observe(d.Seconds())
 }
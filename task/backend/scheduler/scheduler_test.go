@@ -20,6 +20,7 @@ type mockSchedulable struct {
 	schedule      Schedule
 	offset        time.Duration
 	lastScheduled time.Time
+	policy        MissedRunPolicy
 }
 
 func (s mockSchedulable) ID() ID {
@@ -35,6 +36,9 @@ func (s mockSchedulable) Offset() time.Duration {
 func (s mockSchedulable) LastScheduled() time.Time {
 	return s.lastScheduled
 }
+func (s mockSchedulable) MissedRunPolicy() MissedRunPolicy {
+	return s.policy
+}
 
 func (e *mockExecutor) Execute(ctx context.Context, id ID, scheduledAt time.Time) error {
 	done := make(chan struct{}, 1)
@@ -477,3 +481,126 @@ func TestTreeScheduler_Release(t *testing.T) {
 	case <-time.After(2 * time.Second):
 	}
 }
+
+func TestTreeScheduler_MissedRunPolicy(t *testing.T) {
+	now := time.Date(2020, 3, 4, 5, 6, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy MissedRunPolicy
+		want   time.Time
+	}{
+		{name: "backfill-all", policy: MissedRunBackfillAll, want: now.Add(-9 * time.Minute)},
+		{name: "default", want: now.Add(-9 * time.Minute)},
+		{name: "run-once", policy: MissedRunOnce, want: now},
+		{name: "skip", policy: MissedRunSkip, want: now.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := make(chan time.Time, 100)
+			exe := &mockExecutor{fn: func(l *sync.Mutex, ctx context.Context, id ID, scheduledAt time.Time) {
+				select {
+				case <-ctx.Done():
+					t.Log("ctx done")
+				case c <- scheduledAt:
+				}
+			}}
+			mockTime := clock.NewMock()
+			mockTime.Set(now)
+			sch, _, err := NewScheduler(
+				exe,
+				&mockSchedulableService{fn: func(ctx context.Context, id ID, t time.Time) error {
+					return nil
+				}},
+				WithTime(mockTime),
+				WithMaxConcurrentWorkers(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sch.Stop()
+			schedule, err := NewSchedule("0 * * * * * *")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the scheduler was down for the last ten minutes.
+			err = sch.Schedule(mockSchedulable{id: 1, schedule: schedule, lastScheduled: now.Add(-10 * time.Minute), policy: tt.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			go func() {
+				sch.mu.Lock()
+				mockTime.Set(now.Add(time.Minute))
+				sch.mu.Unlock()
+			}()
+
+			select {
+			case got := <-c:
+				if !got.Equal(tt.want) {
+					t.Fatalf("expected first run scheduled at %v, got %v", tt.want, got)
+				}
+			case <-time.After(6 * time.Second):
+				t.Fatal("test timed out, it should have fired but didn't")
+			}
+		})
+	}
+}
+
+func TestTreeScheduler_MissedRunTolerance(t *testing.T) {
+	now := time.Date(2020, 3, 4, 5, 6, 0, 0, time.UTC)
+	c := make(chan time.Time, 100)
+	exe := &mockExecutor{fn: func(l *sync.Mutex, ctx context.Context, id ID, scheduledAt time.Time) {
+		select {
+		case <-ctx.Done():
+			t.Log("ctx done")
+		case c <- scheduledAt:
+		}
+	}}
+	mockTime := clock.NewMock()
+	mockTime.Set(now)
+	sch, _, err := NewScheduler(
+		exe,
+		&mockSchedulableService{fn: func(ctx context.Context, id ID, t time.Time) error {
+			return nil
+		}},
+		WithTime(mockTime),
+		WithMissedRunTolerance(5*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sch.Stop()
+	schedule, err := NewSchedule("0 * * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// runs overdue by less than the tolerance are not missed.
+	err = sch.Schedule(mockSchedulable{id: 1, schedule: schedule, lastScheduled: now.Add(-3 * time.Minute), policy: MissedRunSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		sch.mu.Lock()
+		mockTime.Set(now.Add(time.Second))
+		sch.mu.Unlock()
+	}()
+
+	select {
+	case got := <-c:
+		if want := now.Add(-2 * time.Minute); !got.Equal(want) {
+			t.Fatalf("expected first run scheduled at %v, got %v", want, got)
+		}
+	case <-time.After(6 * time.Second):
+		t.Fatal("test timed out, it should have fired but didn't")
+	}
+}
+
+func TestMissedRunPolicy_Valid(t *testing.T) {
+	for _, p := range []MissedRunPolicy{"", MissedRunSkip, MissedRunOnce, MissedRunBackfillAll} {
+		if err := p.Valid(); err != nil {
+			t.Errorf("expected policy %q to be valid, got %v", p, err)
+		}
+	}
+	if err := MissedRunPolicy("sometimes").Valid(); err == nil {
+		t.Error("expected unknown policy to be invalid")
+	}
+}
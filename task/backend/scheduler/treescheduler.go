@@ -19,6 +19,9 @@ const (
 
 	// defaultMaxWorkers is a constant that sets the default number of maximum workers for a TreeScheduler
 	defaultMaxWorkers = 128
+
+	// defaultMissedRunTolerance is the default time a run may be overdue before it is considered missed.
+	defaultMissedRunTolerance = time.Minute
 )
 
 // TreeScheduler is a Scheduler based on a btree.
@@ -42,7 +45,7 @@ const (
 // Distribution is handled by hashing the TaskID (to ensure uniform distribution) and then distributing over those channels
 // evenly based on the hashed ID.  This is to ensure that all tasks of the same ID go to the same worker.
 //
-//The workers call ExecutorFunc handle any errors and update the LastScheduled time internally and also via the Checkpointer.
+// The workers call ExecutorFunc handle any errors and update the LastScheduled time internally and also via the Checkpointer.
 //
 // The main loop:
 //
@@ -74,6 +77,10 @@ type TreeScheduler struct {
 	wg           sync.WaitGroup
 	checkpointer SchedulableService
 
+	// missedRunTolerance is the time a run may be overdue, because of clock
+	// skew or busy workers, before the missed run policy of its Schedulable applies.
+	missedRunTolerance time.Duration
+
 	sm *SchedulerMetrics
 }
 
@@ -99,6 +106,18 @@ func WithMaxConcurrentWorkers(n int) treeSchedulerOptFunc {
 	}
 }
 
+// WithMissedRunTolerance is an option that sets how long a run may be overdue before the missed run policy of its
+// Schedulable applies to it.
+func WithMissedRunTolerance(d time.Duration) treeSchedulerOptFunc {
+	return func(t *TreeScheduler) error {
+		if d < 0 {
+			return errors.New("missed run tolerance must not be negative")
+		}
+		t.missedRunTolerance = d
+		return nil
+	}
+}
+
 // WithTime is an optiom for NewScheduler that allows you to inject a clock.Clock from ben johnson's github.com/benbjohnson/clock library, for testing purposes.
 func WithTime(t clock.Clock) treeSchedulerOptFunc {
 	return func(sch *TreeScheduler) error {
@@ -118,6 +137,8 @@ func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...tr
 		time:         clock.New(),
 		done:         make(chan struct{}, 1),
 		checkpointer: checkpointer,
+
+		missedRunTolerance: defaultMissedRunTolerance,
 	}
 
 	// apply options
@@ -242,6 +263,14 @@ func (s *TreeScheduler) iterator(ts time.Time) (btree.ItemIterator, *itemList) {
 		if time.Unix(it.next+it.Offset, 0).After(ts) {
 			return false
 		}
+		if missed := it; s.catchUp(&it, ts) {
+			itemsToPlace.toDelete = append(itemsToPlace.toDelete, missed)
+			if it.when().After(ts) {
+				// the missed runs were skipped.
+				itemsToPlace.toInsert = append(itemsToPlace.toInsert, it)
+				return true
+			}
+		}
 		// distribute to the right worker.
 		{
 			buf := [8]byte{}
@@ -270,6 +299,35 @@ func (s *TreeScheduler) iterator(ts time.Time) (btree.ItemIterator, *itemList) {
 	}, itemsToPlace
 }
 
+// catchUp applies the missed run policy of the item when its next run is
+// overdue by more than the missed run tolerance at ts. It reports whether
+// the next run of the item changed.
+func (s *TreeScheduler) catchUp(it *Item, ts time.Time) bool {
+	if it.policy == "" || it.policy == MissedRunBackfillAll || !it.when().Before(ts.Add(-s.missedRunTolerance)) {
+		return false
+	}
+
+	// the runs are due when their scheduled time is before the cutoff.
+	cutoff := ts.Add(-time.Duration(it.Offset) * time.Second)
+	next, err := it.cron.Next(cutoff)
+	if err != nil {
+		return false
+	}
+	if it.policy == MissedRunOnce {
+		if next, err = it.latestBefore(next, cutoff); err != nil {
+			return false
+		}
+	}
+	if next.Unix() == it.next {
+		return false
+	}
+
+	s.sm.missedRuns(it.policy)
+	it.next = next.UTC().Unix()
+	it.ordering.when = it.next + it.Offset
+	return true
+}
+
 // When gives us the next time the scheduler will run a task.
 func (s *TreeScheduler) When() time.Time {
 	s.mu.RLock()
@@ -315,7 +373,7 @@ func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 				}
 			}()
 			// report the difference between when the item was supposed to be scheduled and now
-			s.sm.reportScheduleDelay(time.Since(it.Next()))
+			s.sm.reportScheduleDelay(s.time.Now().Sub(it.when()))
 			preExec := time.Now()
 			// execute
 			err = s.executor.Execute(ctx, it.id, t)
@@ -340,6 +398,7 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 		cron:   sch.Schedule(),
 		id:     sch.ID(),
 		Offset: int64(sch.Offset().Seconds()),
+		policy: sch.MissedRunPolicy(),
 		//last:   sch.LastScheduled().Unix(),
 	}
 	nt, err := it.cron.Next(sch.LastScheduled())
@@ -354,7 +413,9 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	nt = nt.Add(sch.Offset())
+	// apply the missed run policy to the runs missed since the last scheduled run.
+	s.catchUp(&it, s.time.Now().UTC())
+	nt = it.when()
 	if s.when.IsZero() || s.when.After(nt) {
 		s.when = nt
 		s.timer.Stop()
@@ -397,6 +458,7 @@ type Item struct {
 	cron   Schedule
 	next   int64
 	Offset int64
+	policy MissedRunPolicy
 }
 
 func (it Item) Next() time.Time {
@@ -413,6 +475,36 @@ func (it Item) Less(bItem btree.Item) bool {
 	return it.ordering.when < it2.ordering.when || (it.ordering.when == it2.ordering.when && (it.nonce < it2.nonce || it.nonce == it2.nonce && it.id < it2.id))
 }
 
+// latestBefore returns the latest time of the schedule of the item that is
+// before next, the first time of the schedule after the cutoff.
+func (it Item) latestBefore(next, cutoff time.Time) (time.Time, error) {
+	// the next run of the item is a time of the schedule before next, so
+	// widening the window before the cutoff up to it finds one.
+	from := time.Unix(it.next, 0).UTC().Add(-time.Second)
+	t := next
+	for d := time.Second; !t.Before(next); d *= 2 {
+		start := cutoff.Add(-d)
+		if start.Before(from) {
+			start = from
+		}
+		var err error
+		if t, err = it.cron.Next(start); err != nil {
+			return time.Time{}, err
+		}
+	}
+	// walk to the last time of the schedule that is before next.
+	for {
+		n, err := it.cron.Next(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !n.Before(next) {
+			return t, nil
+		}
+		t = n
+	}
+}
+
 func (it *Item) updateNext() error {
 	newNext, err := it.cron.Next(time.Unix(it.next, 0))
 	if err != nil {
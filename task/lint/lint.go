@@ -0,0 +1,184 @@
+// Package lint checks the flux scripts of tasks for problems that would only
+// surface when the task runs on its schedule.
+package lint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+var _ influxdb.TaskLintService = (*Service)(nil)
+
+// Service checks that the script of a task parses, that its task option is
+// valid and that the buckets it reads and writes exist and are accessible
+// with the authorizer that owns the task.
+type Service struct {
+	buckets influxdb.BucketService
+}
+
+// NewService returns a Service that looks up the buckets referenced by task
+// scripts with the bucket service. The bucket service must not be authorized,
+// so that buckets that are readable but not writable can be told apart from
+// buckets that are not accessible; the Service must be wrapped by an
+// authorizer requiring read access to the organization.
+func NewService(bs influxdb.BucketService) *Service {
+	return &Service{
+		buckets: bs,
+	}
+}
+
+// LintTask checks the script as a task of the organization that is owned by
+// the authorizer on the context.
+func (s *Service) LintTask(ctx context.Context, orgID influxdb.ID, script string) (*influxdb.TaskLintResult, error) {
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &result{}
+	defer r.done()
+
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		ast.Visit(pkg, func(n ast.Node) {
+			for _, err := range n.Errs() {
+				r.errorAt(n, err.Msg)
+			}
+		})
+		return &r.TaskLintResult, nil
+	}
+
+	if _, err := options.FromScript(script); err != nil {
+		r.error(fmt.Sprintf("invalid task options: %v", err))
+	}
+	lintNow(r, pkg)
+
+	readBuckets, writeBuckets, err := query.BucketsAccessed(pkg, &orgID)
+	if err != nil {
+		r.error(fmt.Sprintf("failed to compile script: %v", err))
+		return &r.TaskLintResult, nil
+	}
+	for _, f := range readBuckets {
+		if err := s.lintBucket(ctx, r, auth, influxdb.ReadAction, f); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range writeBuckets {
+		if err := s.lintBucket(ctx, r, auth, influxdb.WriteAction, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return &r.TaskLintResult, nil
+}
+
+// lintBucket checks that the bucket matched by the filter exists and that
+// the authorizer may perform the action on it. Buckets that the authorizer
+// may not read are reported as missing, so that linting does not reveal the
+// buckets of other organizations. Only unexpected errors are returned.
+func (s *Service) lintBucket(ctx context.Context, r *result, auth influxdb.Authorizer, a influxdb.Action, f influxdb.BucketFilter) error {
+	name := bucketName(f)
+	b, err := s.buckets.FindBucket(ctx, f)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	if err == nil {
+		read, err := influxdb.NewPermissionAtID(b.ID, influxdb.ReadAction, influxdb.BucketsResourceType, b.OrgID)
+		if err != nil {
+			return err
+		}
+		if !auth.Allowed(*read) {
+			b = nil
+		}
+	}
+	if b == nil {
+		r.error(fmt.Sprintf("bucket %s not found", name))
+		return nil
+	}
+
+	p, err := influxdb.NewPermissionAtID(b.ID, a, influxdb.BucketsResourceType, b.OrgID)
+	if err != nil {
+		return err
+	}
+	if !auth.Allowed(*p) {
+		r.error(fmt.Sprintf("the token that owns the task has no %s permission on bucket %s", a, name))
+	}
+	return nil
+}
+
+func bucketName(f influxdb.BucketFilter) string {
+	if f.Name != nil {
+		return fmt.Sprintf("%q", *f.Name)
+	}
+	if f.ID != nil {
+		return f.ID.String()
+	}
+	return "<unknown>"
+}
+
+// lintNow warns about uses of the current time that are not pinned to the
+// time a run is scheduled for, so that runs are not reproducible.
+func lintNow(r *result, pkg *ast.Package) {
+	ast.Visit(pkg, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.OptionStatement:
+			if a, ok := n.Assignment.(*ast.VariableAssignment); ok && a.ID.Name == "now" {
+				r.warningAt(n, "option now overrides the time the run is scheduled for; runs and retries of the task will not query the same range")
+			}
+		case *ast.CallExpression:
+			if id, ok := n.Callee.(*ast.Identifier); ok && id.Name == "systemTime" {
+				r.warningAt(n, "systemTime() returns the time the run executes, not the time it is scheduled for; use now() instead")
+			}
+		}
+	})
+}
+
+// result collects the diagnostics of a script.
+type result struct {
+	influxdb.TaskLintResult
+}
+
+func (r *result) add(severity, msg string, loc *ast.SourceLocation) {
+	d := influxdb.TaskDiagnostic{
+		Severity: severity,
+		Message:  msg,
+	}
+	if loc != nil {
+		d.Line, d.Column = loc.Start.Line, loc.Start.Column
+	}
+	r.Diagnostics = append(r.Diagnostics, d)
+}
+
+func (r *result) error(msg string) {
+	r.add(influxdb.TaskDiagnosticError, msg, nil)
+}
+
+func (r *result) errorAt(n ast.Node, msg string) {
+	loc := n.Location()
+	r.add(influxdb.TaskDiagnosticError, msg, &loc)
+}
+
+func (r *result) warningAt(n ast.Node, msg string) {
+	loc := n.Location()
+	r.add(influxdb.TaskDiagnosticWarning, msg, &loc)
+}
+
+// done sets whether the script is valid from the collected diagnostics.
+func (r *result) done() {
+	r.Valid = true
+	for _, d := range r.Diagnostics {
+		if d.Severity == influxdb.TaskDiagnosticError {
+			r.Valid = false
+		}
+	}
+	if r.Diagnostics == nil {
+		r.Diagnostics = []influxdb.TaskDiagnostic{}
+	}
+}
@@ -0,0 +1,131 @@
+package lint_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/task/lint"
+)
+
+func TestService_LintTask(t *testing.T) {
+	orgID := influxdb.ID(1)
+	buckets := map[string]influxdb.ID{"in": 10, "out": 11, "private": 12, "other": 13}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		id, ok := buckets[*f.Name]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		if *f.Name == "other" {
+			return &influxdb.Bucket{ID: id, OrgID: 2, Name: *f.Name}, nil
+		}
+		return &influxdb.Bucket{ID: id, OrgID: orgID, Name: *f.Name}, nil
+	}
+
+	inID, outID := buckets["in"], buckets["out"]
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		Permissions: []influxdb.Permission{
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &inID}},
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &outID}},
+			{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &outID}},
+		},
+	}
+	ctx := icontext.SetAuthorizer(context.Background(), auth)
+
+	const header = `option task = {name: "downsample", every: 1h}
+`
+	tests := []struct {
+		name      string
+		script    string
+		wantValid bool
+		// wantMessages are substrings of the expected diagnostics, in order.
+		wantMessages []string
+		wantLine     int
+	}{
+		{
+			name:      "valid task",
+			script:    header + `from(bucket: "in") |> range(start: -1h) |> to(bucket: "out", org: "o")`,
+			wantValid: true,
+		},
+		{
+			name:         "parse error",
+			script:       header + `from(bucket: "in") |> range(start: -1h`,
+			wantMessages: []string{"expected RPAREN"},
+			wantLine:     2,
+		},
+		{
+			name:         "missing task option",
+			script:       `from(bucket: "in") |> range(start: -1h)`,
+			wantMessages: []string{"invalid task options"},
+		},
+		{
+			name:         "missing bucket",
+			script:       header + `from(bucket: "missing") |> range(start: -1h)`,
+			wantMessages: []string{`bucket "missing" not found`},
+		},
+		{
+			name:         "no read permission",
+			script:       header + `from(bucket: "private") |> range(start: -1h)`,
+			wantMessages: []string{`bucket "private" not found`},
+		},
+		{
+			name:         "no write permission",
+			script:       header + `from(bucket: "out") |> range(start: -1h) |> to(bucket: "in", org: "o")`,
+			wantMessages: []string{`no write permission on bucket "in"`},
+		},
+		{
+			name:         "no write permission on an unreadable bucket",
+			script:       header + `from(bucket: "in") |> range(start: -1h) |> to(bucket: "private", org: "o")`,
+			wantMessages: []string{`bucket "private" not found`},
+		},
+		{
+			name:         "bucket of another organization",
+			script:       header + `from(bucket: "in") |> range(start: -1h) |> to(bucket: "other", org: "other")`,
+			wantMessages: []string{`bucket "other" not found`},
+		},
+		{
+			name:         "unpinned now",
+			script:       header + `option now = () => 2020-01-01T00:00:00Z` + "\n" + `from(bucket: "in") |> range(start: -1h)`,
+			wantValid:    true,
+			wantMessages: []string{"option now overrides"},
+			wantLine:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := lint.NewService(bs)
+			r, err := s.LintTask(ctx, orgID, tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Valid != tt.wantValid {
+				t.Errorf("expected valid %v, got %v: %+v", tt.wantValid, r.Valid, r.Diagnostics)
+			}
+			if len(r.Diagnostics) != len(tt.wantMessages) {
+				t.Fatalf("expected %d diagnostics, got %+v", len(tt.wantMessages), r.Diagnostics)
+			}
+			for i, msg := range tt.wantMessages {
+				if !strings.Contains(r.Diagnostics[i].Message, msg) {
+					t.Errorf("expected diagnostic %d to contain %q, got %q", i, msg, r.Diagnostics[i].Message)
+				}
+			}
+			if tt.wantLine != 0 && r.Diagnostics[0].Line != tt.wantLine {
+				t.Errorf("expected diagnostic at line %d, got %d", tt.wantLine, r.Diagnostics[0].Line)
+			}
+		})
+	}
+}
+
+func TestService_LintTaskUnauthorized(t *testing.T) {
+	s := lint.NewService(mock.NewBucketService())
+	if _, err := s.LintTask(context.Background(), 1, `option task = {name: "a", every: 1h}`); err == nil {
+		t.Fatal("expected an error without an authorizer")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/pkg/pointer"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
 	cron "gopkg.in/robfig/cron.v2"
 )
 
@@ -38,6 +39,15 @@ type Options struct {
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Pool is the name of the executor worker pool that runs the task.
+	// Tasks without a pool run in the default pool.
+	Pool string `json:"pool,omitempty"`
+
+	// MissedRunPolicy is how the runs the task missed while the scheduler was
+	// down are executed: "skip", "run-once" or "backfill-all".
+	// Tasks without a policy backfill all missed runs.
+	MissedRunPolicy string `json:"missedRunPolicy,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -134,6 +144,8 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Pool = ""
+	o.MissedRunPolicy = ""
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -143,7 +155,9 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Pool == "" &&
+		o.MissedRunPolicy == ""
 }
 
 // All the task option names we accept.
@@ -154,6 +168,9 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optPool        = "pool"
+
+	optMissedRunPolicy = "missedRunPolicy"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -326,6 +343,20 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if poolVal, ok := optObject.Get(optPool); ok {
+		if err := checkNature(poolVal.PolyType().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Pool = poolVal.Str()
+	}
+
+	if policyVal, ok := optObject.Get(optMissedRunPolicy); ok {
+		if err := checkNature(policyVal.PolyType().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.MissedRunPolicy = policyVal.Str()
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -386,6 +417,12 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	if strings.ContainsAny(o.Pool, " \t\n") {
+		errs = append(errs, "pool must not contain whitespace")
+	}
+	if err := scheduler.MissedRunPolicy(o.MissedRunPolicy).Valid(); err != nil {
+		errs = append(errs, err.Error())
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -427,7 +464,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optPool, optMissedRunPolicy:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -436,7 +473,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optPool, optMissedRunPolicy}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
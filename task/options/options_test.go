@@ -33,6 +33,12 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.Pool != "" {
+		taskData = fmt.Sprintf("%s  pool: %q,\n", taskData, opt.Pool)
+	}
+	if opt.MissedRunPolicy != "" {
+		taskData = fmt.Sprintf("%s  missedRunPolicy: %q,\n", taskData, opt.MissedRunPolicy)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -77,6 +83,11 @@ func TestFromScript(t *testing.T) {
 		{script: scriptGenerator(options.Options{Name: "name7", Retry: pointer.Int64(20), Every: *(options.MustParseDuration("1h"))}, ""), shouldErr: true},
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name10", Every: *(options.MustParseDuration("5s")), Pool: "critical"}, ""), exp: options.Options{Name: "name10", Every: *(options.MustParseDuration("5s")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Pool: "critical"}},
+		{script: scriptGenerator(options.Options{Name: "name11", Every: *(options.MustParseDuration("5s")), Pool: "bad pool"}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name12\",\n  pool: 1,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name13", Every: *(options.MustParseDuration("5s")), MissedRunPolicy: "run-once"}, ""), exp: options.Options{Name: "name13", Every: *(options.MustParseDuration("5s")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), MissedRunPolicy: "run-once"}},
+		{script: scriptGenerator(options.Options{Name: "name14", Every: *(options.MustParseDuration("5s")), MissedRunPolicy: "sometimes"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
 		{script: `option task = {
 			name: "test",
@@ -143,7 +154,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry", "pool", "missedRunPolicy"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
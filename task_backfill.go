@@ -0,0 +1,76 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for task backfill errors.
+const (
+	OpBackfillTask       = "BackfillTask"
+	OpFindTaskBackfill   = "FindTaskBackfill"
+	OpCancelTaskBackfill = "CancelTaskBackfill"
+)
+
+var (
+	// ErrTaskBackfillNotFound is used when the task has no backfill.
+	ErrTaskBackfillNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "task backfill not found",
+	}
+
+	// ErrTaskBackfillInProgress is used when a backfill is started for a
+	// task that has a backfill in progress.
+	ErrTaskBackfillInProgress = &Error{
+		Code: EConflict,
+		Msg:  "task has a backfill in progress",
+	}
+)
+
+// Task backfill statuses.
+const (
+	TaskBackfillRunning   = "running"
+	TaskBackfillCompleted = "completed"
+	TaskBackfillCanceled  = "canceled"
+	TaskBackfillFailed    = "failed"
+)
+
+// TaskBackfillService runs a task for every time it was scheduled in a
+// historical range, e.g. to re-compute a downsample after a bug fix.
+type TaskBackfillService interface {
+	// BackfillTask starts enqueuing a run of the task for every time the
+	// task is scheduled in the range [start, stop). A task has at most one
+	// backfill in progress.
+	BackfillTask(ctx context.Context, taskID ID, start, stop time.Time) (*TaskBackfill, error)
+
+	// FindTaskBackfill returns the progress of the latest backfill of the task.
+	FindTaskBackfill(ctx context.Context, taskID ID) (*TaskBackfill, error)
+
+	// CancelTaskBackfill stops enqueuing the runs of the backfill in
+	// progress of the task. Runs that are already enqueued are not waited for.
+	CancelTaskBackfill(ctx context.Context, taskID ID) error
+}
+
+// TaskBackfill is the progress of the runs enqueued to cover a historical
+// range of a task.
+type TaskBackfill struct {
+	TaskID ID        `json:"taskID"`
+	OrgID  ID        `json:"orgID"`
+	Start  time.Time `json:"start"`
+	Stop   time.Time `json:"stop"`
+	Status string    `json:"status"`
+
+	// Total is the number of runs that cover the range.
+	Total int `json:"total"`
+	// Enqueued is the number of runs enqueued so far.
+	Enqueued int `json:"enqueued"`
+	// Skipped is the number of runs that were not enqueued because a run
+	// for the same time was already queued.
+	Skipped   int `json:"skipped"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
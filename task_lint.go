@@ -0,0 +1,35 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// Severities of task diagnostics.
+const (
+	TaskDiagnosticError   = "error"
+	TaskDiagnosticWarning = "warning"
+)
+
+// TaskLintService checks the flux script of a task for problems that would
+// make its runs fail, before the task is created or updated.
+type TaskLintService interface {
+	// LintTask checks the script as a task of the organization that is
+	// owned by the authorizer on the context.
+	LintTask(ctx context.Context, orgID ID, script string) (*TaskLintResult, error)
+}
+
+// TaskLintResult is the outcome of checking the script of a task.
+type TaskLintResult struct {
+	// Valid is false when any of the diagnostics is an error.
+	Valid       bool             `json:"valid"`
+	Diagnostics []TaskDiagnostic `json:"diagnostics"`
+}
+
+// TaskDiagnostic is a problem found in the script of a task. Line and
+// Column are only set when the problem has a position in the script.
+type TaskDiagnostic struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
@@ -1151,6 +1151,7 @@ func UpdateBucket(
 		name        string
 		id          influxdb.ID
 		retention   int
+		shardGroup  int
 		description *string
 	}
 	type wants struct {
@@ -1305,6 +1306,43 @@ func UpdateBucket(
 				},
 			},
 		},
+		{
+			name: "update shard group duration",
+			fields: BucketFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*influxdb.Bucket{
+					{
+						ID:                 MustIDBase16(bucketOneID),
+						OrgID:              MustIDBase16(orgOneID),
+						Name:               "bucket1",
+						RetentionPeriod:    100 * time.Minute,
+						ShardGroupDuration: 60 * time.Minute,
+					},
+				},
+			},
+			args: args{
+				id:         MustIDBase16(bucketOneID),
+				shardGroup: 10,
+			},
+			wants: wants{
+				bucket: &influxdb.Bucket{
+					ID:                 MustIDBase16(bucketOneID),
+					OrgID:              MustIDBase16(orgOneID),
+					Name:               "bucket1",
+					RetentionPeriod:    100 * time.Minute,
+					ShardGroupDuration: 10 * time.Minute,
+					CRUDLog: influxdb.CRUDLog{
+						UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					},
+				},
+			},
+		},
 		{
 			name: "update description",
 			fields: BucketFields{
@@ -1440,6 +1478,10 @@ func UpdateBucket(
 				d := time.Duration(tt.args.retention) * time.Minute
 				upd.RetentionPeriod = &d
 			}
+			if tt.args.shardGroup != 0 {
+				d := time.Duration(tt.args.shardGroup) * time.Minute
+				upd.ShardGroupDuration = &d
+			}
 
 			upd.Description = tt.args.description
 
@@ -0,0 +1,511 @@
+package testing
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	dbrpOneID = "020f755c3c082000"
+	dbrpTwoID = "020f755c3c082001"
+	dbrpNewID = "020f755c3c082002"
+)
+
+var dbrpMappingV2CmpOptions = cmp.Options{
+	cmp.Transformer("Sort", func(in []*influxdb.DBRPMappingV2) []*influxdb.DBRPMappingV2 {
+		out := append([]*influxdb.DBRPMappingV2(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID < out[j].ID
+		})
+		return out
+	}),
+}
+
+// DBRPMappingFieldsV2 will include the buckets and dbrp mappings.
+type DBRPMappingFieldsV2 struct {
+	IDGenerator  influxdb.IDGenerator
+	Buckets      []*influxdb.Bucket
+	DBRPMappings []*influxdb.DBRPMappingV2
+}
+
+func dbrpBuckets() []*influxdb.Bucket {
+	return []*influxdb.Bucket{
+		{ID: MustIDBase16(dbrpBucket1ID), OrgID: MustIDBase16(dbrpOrg1ID), Name: "db/autogen"},
+		{ID: MustIDBase16(dbrpBucket2ID), OrgID: MustIDBase16(dbrpOrg1ID), Name: "db/week"},
+		{ID: MustIDBase16(dbrpBucketAID), OrgID: MustIDBase16(dbrpOrg2ID), Name: "db/autogen"},
+	}
+}
+
+func dbrpMappingOne() *influxdb.DBRPMappingV2 {
+	return &influxdb.DBRPMappingV2{
+		ID:              MustIDBase16(dbrpOneID),
+		OrganizationID:  MustIDBase16(dbrpOrg1ID),
+		BucketID:        MustIDBase16(dbrpBucket1ID),
+		Database:        "db",
+		RetentionPolicy: "autogen",
+		Default:         true,
+	}
+}
+
+func dbrpMappingTwo() *influxdb.DBRPMappingV2 {
+	return &influxdb.DBRPMappingV2{
+		ID:              MustIDBase16(dbrpTwoID),
+		OrganizationID:  MustIDBase16(dbrpOrg2ID),
+		BucketID:        MustIDBase16(dbrpBucketAID),
+		Database:        "db",
+		RetentionPolicy: "autogen",
+		Default:         true,
+	}
+}
+
+// DBRPMappingServiceV2 tests all the service functions.
+func DBRPMappingServiceV2(
+	init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateDBRPMappingV2",
+			fn:   CreateDBRPMappingV2,
+		},
+		{
+			name: "FindDBRPMappingsV2",
+			fn:   FindDBRPMappingsV2,
+		},
+		{
+			name: "UpdateDBRPMappingV2",
+			fn:   UpdateDBRPMappingV2,
+		},
+		{
+			name: "DeleteDBRPMappingV2",
+			fn:   DeleteDBRPMappingV2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// CreateDBRPMappingV2 testing
+func CreateDBRPMappingV2(
+	init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()),
+	t *testing.T,
+) {
+	type args struct {
+		mapping *influxdb.DBRPMappingV2
+	}
+	type wants struct {
+		err      error
+		mappings []*influxdb.DBRPMappingV2
+	}
+
+	tests := []struct {
+		name   string
+		fields DBRPMappingFieldsV2
+		args   args
+		wants  wants
+	}{
+		{
+			name: "create mapping for another retention policy",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator:  mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+			args: args{
+				mapping: &influxdb.DBRPMappingV2{
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+					Database:        "db",
+					RetentionPolicy: "week",
+				},
+			},
+			wants: wants{
+				mappings: []*influxdb.DBRPMappingV2{
+					dbrpMappingOne(),
+					{
+						ID:              MustIDBase16(dbrpNewID),
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket2ID),
+						Database:        "db",
+						RetentionPolicy: "week",
+					},
+				},
+			},
+		},
+		{
+			name: "databases of organizations are independent",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator:  mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+			args: args{
+				mapping: dbrpMappingTwo(),
+			},
+			wants: wants{
+				mappings: []*influxdb.DBRPMappingV2{
+					dbrpMappingOne(),
+					{
+						ID:              MustIDBase16(dbrpNewID),
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucketAID),
+						Database:        "db",
+						RetentionPolicy: "autogen",
+						Default:         true,
+					},
+				},
+			},
+		},
+		{
+			name: "retention policy is already mapped",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator:  mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+			args: args{
+				mapping: &influxdb.DBRPMappingV2{
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+					Database:        "db",
+					RetentionPolicy: "autogen",
+				},
+			},
+			wants: wants{
+				err:      influxdb.ErrDBRPAlreadyExists,
+				mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+		},
+		{
+			name: "database already has a default mapping",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator:  mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+			args: args{
+				mapping: &influxdb.DBRPMappingV2{
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+					Database:        "db",
+					RetentionPolicy: "week",
+					Default:         true,
+				},
+			},
+			wants: wants{
+				err:      influxdb.ErrDBRPDefaultAlreadyExists,
+				mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+			},
+		},
+		{
+			name: "bucket of another organization",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator: mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:     dbrpBuckets(),
+			},
+			args: args{
+				mapping: &influxdb.DBRPMappingV2{
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucketAID),
+					Database:        "db",
+					RetentionPolicy: "autogen",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "bucket does not belong to the organization",
+				},
+				mappings: []*influxdb.DBRPMappingV2{},
+			},
+		},
+		{
+			name: "invalid database name",
+			fields: DBRPMappingFieldsV2{
+				IDGenerator: mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:     dbrpBuckets(),
+			},
+			args: args{
+				mapping: &influxdb.DBRPMappingV2{
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket1ID),
+					Database:        "db/x",
+					RetentionPolicy: "autogen",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "database must contain at least one character and only be letters, numbers, '_', '-', and '.'",
+				},
+				mappings: []*influxdb.DBRPMappingV2{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateDBRPMapping(ctx, tt.args.mapping)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			mappings, _, err := s.FindDBRPMappings(ctx, influxdb.DBRPMappingFilterV2{})
+			if err != nil {
+				t.Fatalf("failed to retrieve dbrp mappings: %v", err)
+			}
+			if diff := cmp.Diff(mappings, tt.wants.mappings, dbrpMappingV2CmpOptions...); diff != "" {
+				t.Errorf("dbrp mappings are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindDBRPMappingsV2 testing
+func FindDBRPMappingsV2(
+	init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()),
+	t *testing.T,
+) {
+	fields := DBRPMappingFieldsV2{
+		Buckets:      dbrpBuckets(),
+		DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne(), dbrpMappingTwo()},
+	}
+
+	tests := []struct {
+		name     string
+		filter   influxdb.DBRPMappingFilterV2
+		mappings []*influxdb.DBRPMappingV2
+	}{
+		{
+			name:     "find all mappings",
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne(), dbrpMappingTwo()},
+		},
+		{
+			name:     "find mapping by id",
+			filter:   influxdb.DBRPMappingFilterV2{ID: idPtr(MustIDBase16(dbrpTwoID))},
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingTwo()},
+		},
+		{
+			name:     "find missing mapping by id",
+			filter:   influxdb.DBRPMappingFilterV2{ID: idPtr(MustIDBase16(dbrpNewID))},
+			mappings: []*influxdb.DBRPMappingV2{},
+		},
+		{
+			name: "find mappings of database of organization",
+			filter: influxdb.DBRPMappingFilterV2{
+				OrgID:    idPtr(MustIDBase16(dbrpOrg1ID)),
+				Database: strPtr("db"),
+			},
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+		},
+		{
+			name: "find default mapping of database of organization",
+			filter: influxdb.DBRPMappingFilterV2{
+				OrgID:    idPtr(MustIDBase16(dbrpOrg2ID)),
+				Database: strPtr("db"),
+				Default:  boolPtr(true),
+			},
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingTwo()},
+		},
+		{
+			name:     "find mappings of bucket",
+			filter:   influxdb.DBRPMappingFilterV2{BucketID: idPtr(MustIDBase16(dbrpBucket1ID))},
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne()},
+		},
+		{
+			name:     "find mappings of retention policy",
+			filter:   influxdb.DBRPMappingFilterV2{RetentionPolicy: strPtr("week")},
+			mappings: []*influxdb.DBRPMappingV2{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(fields, t)
+			defer done()
+			ctx := context.Background()
+
+			mappings, n, err := s.FindDBRPMappings(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve dbrp mappings: %v", err)
+			}
+			if n != len(tt.mappings) {
+				t.Errorf("expected %d dbrp mappings, got %d", len(tt.mappings), n)
+			}
+			if diff := cmp.Diff(mappings, tt.mappings, dbrpMappingV2CmpOptions...); diff != "" {
+				t.Errorf("dbrp mappings are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateDBRPMappingV2 testing
+func UpdateDBRPMappingV2(
+	init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()),
+	t *testing.T,
+) {
+	week := &influxdb.DBRPMappingV2{
+		ID:              MustIDBase16(dbrpNewID),
+		OrganizationID:  MustIDBase16(dbrpOrg1ID),
+		BucketID:        MustIDBase16(dbrpBucket2ID),
+		Database:        "db",
+		RetentionPolicy: "week",
+	}
+
+	type wants struct {
+		err     error
+		mapping *influxdb.DBRPMappingV2
+	}
+
+	tests := []struct {
+		name  string
+		id    influxdb.ID
+		upd   influxdb.DBRPMappingUpdate
+		wants wants
+	}{
+		{
+			name: "unset default mapping",
+			id:   MustIDBase16(dbrpOneID),
+			upd:  influxdb.DBRPMappingUpdate{Default: boolPtr(false)},
+			wants: wants{
+				mapping: &influxdb.DBRPMappingV2{
+					ID:              MustIDBase16(dbrpOneID),
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket1ID),
+					Database:        "db",
+					RetentionPolicy: "autogen",
+				},
+			},
+		},
+		{
+			name: "rename retention policy",
+			id:   MustIDBase16(dbrpNewID),
+			upd:  influxdb.DBRPMappingUpdate{RetentionPolicy: strPtr("7d")},
+			wants: wants{
+				mapping: &influxdb.DBRPMappingV2{
+					ID:              MustIDBase16(dbrpNewID),
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+					Database:        "db",
+					RetentionPolicy: "7d",
+				},
+			},
+		},
+		{
+			name: "second default mapping",
+			id:   MustIDBase16(dbrpNewID),
+			upd:  influxdb.DBRPMappingUpdate{Default: boolPtr(true)},
+			wants: wants{
+				err: influxdb.ErrDBRPDefaultAlreadyExists,
+			},
+		},
+		{
+			name: "retention policy is already mapped",
+			id:   MustIDBase16(dbrpNewID),
+			upd:  influxdb.DBRPMappingUpdate{RetentionPolicy: strPtr("autogen")},
+			wants: wants{
+				err: influxdb.ErrDBRPAlreadyExists,
+			},
+		},
+		{
+			name: "mapping not found",
+			id:   MustIDBase16(dbrpTwoID),
+			upd:  influxdb.DBRPMappingUpdate{Default: boolPtr(false)},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrDBRPNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(DBRPMappingFieldsV2{
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne(), week},
+			}, t)
+			defer done()
+			ctx := context.Background()
+
+			m, err := s.UpdateDBRPMapping(ctx, tt.id, tt.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+			if diff := cmp.Diff(m, tt.wants.mapping); diff != "" {
+				t.Errorf("dbrp mapping is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteDBRPMappingV2 testing
+func DeleteDBRPMappingV2(
+	init func(DBRPMappingFieldsV2, *testing.T) (influxdb.DBRPMappingServiceV2, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name     string
+		id       influxdb.ID
+		err      error
+		mappings []*influxdb.DBRPMappingV2
+	}{
+		{
+			name:     "delete mapping",
+			id:       MustIDBase16(dbrpOneID),
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingTwo()},
+		},
+		{
+			name: "mapping not found",
+			id:   MustIDBase16(dbrpNewID),
+			err: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  influxdb.ErrDBRPNotFound,
+			},
+			mappings: []*influxdb.DBRPMappingV2{dbrpMappingOne(), dbrpMappingTwo()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(DBRPMappingFieldsV2{
+				IDGenerator:  mock.NewIDGenerator(dbrpNewID, t),
+				Buckets:      dbrpBuckets(),
+				DBRPMappings: []*influxdb.DBRPMappingV2{dbrpMappingOne(), dbrpMappingTwo()},
+			}, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteDBRPMapping(ctx, tt.id)
+			ErrorsEqual(t, err, tt.err)
+
+			mappings, _, err := s.FindDBRPMappings(ctx, influxdb.DBRPMappingFilterV2{})
+			if err != nil {
+				t.Fatalf("failed to retrieve dbrp mappings: %v", err)
+			}
+			if diff := cmp.Diff(mappings, tt.mappings, dbrpMappingV2CmpOptions...); diff != "" {
+				t.Errorf("dbrp mappings are different -got/+want\ndiff %s", diff)
+			}
+
+			// A deleted mapping does not block creating the mapping again.
+			if tt.err == nil {
+				m := dbrpMappingOne()
+				if err := s.CreateDBRPMapping(ctx, m); err != nil {
+					t.Errorf("failed to create deleted dbrp mapping again: %v", err)
+				}
+			}
+		})
+	}
+}
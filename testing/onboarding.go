@@ -25,7 +25,9 @@ func Generate(
 	t *testing.T,
 ) {
 	type args struct {
-		request *platform.OnboardingRequest
+		// previous is a request completing the onboarding before request is made.
+		previous *platform.OnboardingRequest
+		request  *platform.OnboardingRequest
 	}
 	type wants struct {
 		errCode  string
@@ -189,12 +191,124 @@ func Generate(
 				},
 			},
 		},
+		{
+			name: "setup profile should create additional buckets, users, and scoped tokens",
+			fields: OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID, fiveID, sixID, sevenID},
+				},
+				TimeGenerator:  mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			},
+			args: args{
+				request: profileOnboardingRequest(""),
+			},
+			wants: wants{
+				password: "password1",
+				results:  profileOnboardingResults(),
+			},
+		},
+		{
+			name: "setup profile token for a bucket outside the setup is invalid",
+			fields: OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID},
+				},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			},
+			args: args{
+				request: &platform.OnboardingRequest{
+					User:     "admin",
+					Password: "password1",
+					Org:      "org1",
+					Bucket:   "bucket1",
+					Tokens: []platform.OnboardingToken{
+						{
+							Permissions: []platform.OnboardingPermission{
+								onboardingPermission(platform.WriteAction, platform.BucketsResourceType, "bucket2"),
+							},
+						},
+					},
+				},
+			},
+			wants: wants{
+				errCode: platform.EInvalid,
+			},
+		},
+		{
+			name: "setup profile user with an unknown role is invalid",
+			fields: OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID},
+				},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			},
+			args: args{
+				request: &platform.OnboardingRequest{
+					User:     "admin",
+					Password: "password1",
+					Org:      "org1",
+					Bucket:   "bucket1",
+					Users: []platform.OnboardingUser{
+						{Name: "user2", Role: "admin"},
+					},
+				},
+			},
+			wants: wants{
+				errCode: platform.EInvalid,
+			},
+		},
+		{
+			name: "repeating a confirmed onboarding returns the original results",
+			fields: OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID, fiveID, sixID, sevenID},
+				},
+				TimeGenerator:  mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			},
+			args: args{
+				previous: profileOnboardingRequest("confirm1"),
+				request:  profileOnboardingRequest("confirm1"),
+			},
+			wants: wants{
+				password: "password1",
+				results:  profileOnboardingResults(),
+			},
+		},
+		{
+			name: "repeating a confirmed onboarding with another confirmation token is denied",
+			fields: OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID, fiveID, sixID, sevenID},
+				},
+				TimeGenerator:  mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			},
+			args: args{
+				previous: profileOnboardingRequest("confirm1"),
+				request:  profileOnboardingRequest("confirm2"),
+			},
+			wants: wants{
+				errCode: platform.EConflict,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, done := init(tt.fields, t)
 			defer done()
 			ctx := context.Background()
+			if tt.args.previous != nil {
+				if _, err := s.Generate(ctx, tt.args.previous); err != nil {
+					t.Fatalf("failed to complete previous onboarding: %v", err)
+				}
+			}
 			results, err := s.Generate(ctx, tt.args.request)
 			if (err != nil) != (tt.wants.errCode != "") {
 				t.Logf("Error: %v", err)
@@ -226,6 +340,7 @@ const (
 	fourID   = "020f755c3c082003"
 	fiveID   = "020f755c3c082004"
 	sixID    = "020f755c3c082005"
+	sevenID  = "020f755c3c082006"
 	oneToken = "020f755c3c082008"
 )
 
@@ -242,3 +357,111 @@ func (g *loopIDGenerator) ID() platform.ID {
 	g.p++
 	return id
 }
+
+func onboardingPermission(action platform.Action, typ platform.ResourceType, name string) platform.OnboardingPermission {
+	var p platform.OnboardingPermission
+	p.Action = action
+	p.Resource.Type = typ
+	p.Resource.Name = name
+	return p
+}
+
+// profileOnboardingRequest returns an onboarding request with a setup profile.
+func profileOnboardingRequest(confirmationToken string) *platform.OnboardingRequest {
+	return &platform.OnboardingRequest{
+		User:     "admin",
+		Password: "password1",
+		Org:      "org1",
+		Bucket:   "bucket1",
+		Buckets: []platform.OnboardingBucket{
+			{Name: "bucket2", Description: "telegraf metrics", RetentionPeriod: 24},
+		},
+		Users: []platform.OnboardingUser{
+			{Name: "user2", Password: "password2"},
+		},
+		Tokens: []platform.OnboardingToken{
+			{
+				Description: "telegraf",
+				Token:       "telegraf-token",
+				User:        "user2",
+				Permissions: []platform.OnboardingPermission{
+					onboardingPermission(platform.WriteAction, platform.BucketsResourceType, "bucket2"),
+				},
+			},
+		},
+		ConfirmationToken: confirmationToken,
+	}
+}
+
+// profileOnboardingResults returns the results of profileOnboardingRequest.
+func profileOnboardingResults() *platform.OnboardingResults {
+	now := time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+	orgID := MustIDBase16(twoID)
+	bucketID := MustIDBase16(fiveID)
+	return &platform.OnboardingResults{
+		User: &platform.User{
+			ID:     MustIDBase16(oneID),
+			Name:   "admin",
+			Status: platform.Active,
+		},
+		Org: &platform.Organization{
+			ID:      orgID,
+			Name:    "org1",
+			CRUDLog: platform.CRUDLog{CreatedAt: now, UpdatedAt: now},
+		},
+		Bucket: &platform.Bucket{
+			ID:      MustIDBase16(threeID),
+			Name:    "bucket1",
+			OrgID:   orgID,
+			CRUDLog: platform.CRUDLog{CreatedAt: now, UpdatedAt: now},
+		},
+		Auth: &platform.Authorization{
+			ID:          MustIDBase16(fourID),
+			Token:       oneToken,
+			Status:      platform.Active,
+			UserID:      MustIDBase16(oneID),
+			Description: "admin's Token",
+			OrgID:       orgID,
+			Permissions: platform.OperPermissions(),
+			CRUDLog:     platform.CRUDLog{CreatedAt: now, UpdatedAt: now},
+		},
+		Buckets: []*platform.Bucket{
+			{
+				ID:              bucketID,
+				Name:            "bucket2",
+				Description:     "telegraf metrics",
+				OrgID:           orgID,
+				RetentionPeriod: 24 * time.Hour,
+				CRUDLog:         platform.CRUDLog{CreatedAt: now, UpdatedAt: now},
+			},
+		},
+		Users: []*platform.User{
+			{
+				ID:     MustIDBase16(sixID),
+				Name:   "user2",
+				Status: platform.Active,
+			},
+		},
+		Auths: []*platform.Authorization{
+			{
+				ID:          MustIDBase16(sevenID),
+				Token:       "telegraf-token",
+				Status:      platform.Active,
+				UserID:      MustIDBase16(sixID),
+				Description: "telegraf",
+				OrgID:       orgID,
+				Permissions: []platform.Permission{
+					{
+						Action: platform.WriteAction,
+						Resource: platform.Resource{
+							Type:  platform.BucketsResourceType,
+							OrgID: &orgID,
+							ID:    &bucketID,
+						},
+					},
+				},
+				CRUDLog: platform.CRUDLog{CreatedAt: now, UpdatedAt: now},
+			},
+		},
+	}
+}
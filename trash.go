@@ -0,0 +1,61 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrTrashedResourceNotFound is used when the trashed resource is not found.
+var ErrTrashedResourceNotFound = &Error{
+	Msg:  "trashed resource not found",
+	Code: ENotFound,
+}
+
+// ops for trash error and trash op logs.
+const (
+	OpFindTrashedResourceByID = "FindTrashedResourceByID"
+	OpFindTrashedResources    = "FindTrashedResources"
+	OpRestoreDashboard        = "RestoreDashboard"
+	OpRestoreTask             = "RestoreTask"
+)
+
+// TrashService represents a service for soft deleted resources. When soft
+// deletion is enabled, deleted dashboards and tasks are moved to the trash of
+// their organization, from where they can be restored until their retention
+// expires.
+type TrashService interface {
+	// FindTrashedResourceByID returns a single trashed resource by the ID it had before deletion.
+	FindTrashedResourceByID(ctx context.Context, id ID) (*TrashedResource, error)
+
+	// FindTrashedResources returns a list of trashed resources that match the filter
+	// and the total count of matching resources.
+	FindTrashedResources(ctx context.Context, filter TrashFilter, opts ...FindOptions) ([]*TrashedResource, int, error)
+
+	// RestoreDashboard moves a dashboard out of the trash.
+	RestoreDashboard(ctx context.Context, id ID) (*Dashboard, error)
+
+	// RestoreTask moves a task out of the trash. The run history of the task
+	// is not restored.
+	RestoreTask(ctx context.Context, id ID) (*Task, error)
+}
+
+// TrashedResource is a soft deleted resource.
+type TrashedResource struct {
+	ID        ID           `json:"id"`
+	OrgID     ID           `json:"orgID"`
+	Type      ResourceType `json:"type"`
+	Name      string       `json:"name"`
+	DeletedAt time.Time    `json:"deletedAt"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+}
+
+// Expired returns true if the retention of the trashed resource has passed.
+func (r *TrashedResource) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// TrashFilter represents a set of filters that restrict the returned trashed resources.
+type TrashFilter struct {
+	OrgID *ID
+	Type  *ResourceType
+}
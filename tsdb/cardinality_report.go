@@ -0,0 +1,162 @@
+package tsdb
+
+import (
+	"sort"
+
+	"github.com/cespare/xxhash"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// CardinalityReport counts series by organization, bucket, measurement and
+// tag key. Series are identified by the hash of their key, so the same
+// series added from several files is counted once.
+type CardinalityReport struct {
+	// OrgID and BucketID restrict the report to series of an organization
+	// or bucket when set.
+	OrgID, BucketID *influxdb.ID
+
+	measurements map[measurementKey]*measurementCardinality
+	buf          []byte
+}
+
+type measurementKey struct {
+	name        [16]byte
+	measurement string
+}
+
+type measurementCardinality struct {
+	series  map[uint64]struct{}
+	tagKeys map[string]*tagKeyCardinality
+}
+
+type tagKeyCardinality struct {
+	series int
+	values map[string]struct{}
+}
+
+// NewCardinalityReport returns an empty report.
+func NewCardinalityReport() *CardinalityReport {
+	return &CardinalityReport{
+		measurements: make(map[measurementKey]*measurementCardinality),
+	}
+}
+
+// Add counts the series with the encoded organization and bucket name and
+// tags. The measurement is read from the measurement tag, and the field tag
+// is counted as the _field tag key.
+func (r *CardinalityReport) Add(name []byte, tags models.Tags) {
+	if len(name) != 16 {
+		return
+	}
+	var k measurementKey
+	copy(k.name[:], name)
+	org, bucket := DecodeName(k.name)
+	if r.OrgID != nil && *r.OrgID != org || r.BucketID != nil && *r.BucketID != bucket {
+		return
+	}
+	k.measurement = string(tags.Get(models.MeasurementTagKeyBytes))
+
+	m := r.measurements[k]
+	if m == nil {
+		m = &measurementCardinality{
+			series:  make(map[uint64]struct{}),
+			tagKeys: make(map[string]*tagKeyCardinality),
+		}
+		r.measurements[k] = m
+	}
+
+	r.buf = models.AppendMakeKey(r.buf[:0], name, tags)
+	h := xxhash.Sum64(r.buf)
+	if _, ok := m.series[h]; ok {
+		return
+	}
+	m.series[h] = struct{}{}
+
+	for _, t := range tags {
+		key := string(t.Key)
+		switch key {
+		case models.MeasurementTagKey:
+			continue
+		case models.FieldKeyTagKey:
+			key = "_field"
+		}
+		tk := m.tagKeys[key]
+		if tk == nil {
+			tk = &tagKeyCardinality{values: make(map[string]struct{})}
+			m.tagKeys[key] = tk
+		}
+		tk.series++
+		tk.values[string(t.Value)] = struct{}{}
+	}
+}
+
+// CardinalitySummary is the result of a cardinality report.
+type CardinalitySummary struct {
+	Series       int                      `json:"series"`
+	Measurements []MeasurementCardinality `json:"measurements"`
+}
+
+// MeasurementCardinality is the number of series of a measurement.
+type MeasurementCardinality struct {
+	OrgID       influxdb.ID         `json:"orgID"`
+	BucketID    influxdb.ID         `json:"bucketID"`
+	Measurement string              `json:"measurement"`
+	Series      int                 `json:"series"`
+	TagKeys     []TagKeyCardinality `json:"tagKeys"`
+}
+
+// TagKeyCardinality is the number of series with a tag key and the number
+// of distinct values of the tag key within a measurement.
+type TagKeyCardinality struct {
+	Key    string `json:"key"`
+	Series int    `json:"series"`
+	Values int    `json:"values"`
+}
+
+// Summary returns the counted series. Measurements are ordered by
+// descending series count and tag keys by descending value count.
+func (r *CardinalityReport) Summary() *CardinalitySummary {
+	s := &CardinalitySummary{
+		Measurements: make([]MeasurementCardinality, 0, len(r.measurements)),
+	}
+	for k, m := range r.measurements {
+		org, bucket := DecodeName(k.name)
+		mc := MeasurementCardinality{
+			OrgID:       org,
+			BucketID:    bucket,
+			Measurement: k.measurement,
+			Series:      len(m.series),
+			TagKeys:     make([]TagKeyCardinality, 0, len(m.tagKeys)),
+		}
+		for key, tk := range m.tagKeys {
+			mc.TagKeys = append(mc.TagKeys, TagKeyCardinality{
+				Key:    key,
+				Series: tk.series,
+				Values: len(tk.values),
+			})
+		}
+		sort.Slice(mc.TagKeys, func(i, j int) bool {
+			a, b := mc.TagKeys[i], mc.TagKeys[j]
+			if a.Values != b.Values {
+				return a.Values > b.Values
+			}
+			return a.Key < b.Key
+		})
+		s.Series += mc.Series
+		s.Measurements = append(s.Measurements, mc)
+	}
+	sort.Slice(s.Measurements, func(i, j int) bool {
+		a, b := s.Measurements[i], s.Measurements[j]
+		switch {
+		case a.Series != b.Series:
+			return a.Series > b.Series
+		case a.OrgID != b.OrgID:
+			return a.OrgID < b.OrgID
+		case a.BucketID != b.BucketID:
+			return a.BucketID < b.BucketID
+		}
+		return a.Measurement < b.Measurement
+	})
+	return s
+}
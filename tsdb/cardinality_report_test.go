@@ -0,0 +1,73 @@
+package tsdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestCardinalityReport(t *testing.T) {
+	org, bucket, other := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	name := tsdb.EncodeName(org, bucket)
+	otherName := tsdb.EncodeName(org, other)
+
+	series := func(kv ...string) models.Tags {
+		m := make(map[string]string)
+		for i := 0; i < len(kv); i += 2 {
+			m[kv[i]] = kv[i+1]
+		}
+		return models.NewTags(m)
+	}
+
+	newReport := func() *tsdb.CardinalityReport {
+		r := tsdb.NewCardinalityReport()
+		r.Add(name[:], series("\x00", "cpu", "\xff", "usage", "host", "a"))
+		r.Add(name[:], series("\x00", "cpu", "\xff", "usage", "host", "b"))
+		r.Add(name[:], series("\x00", "cpu", "\xff", "idle", "host", "a", "region", "east"))
+		// Duplicate series are counted once.
+		r.Add(name[:], series("\x00", "cpu", "\xff", "usage", "host", "a"))
+		r.Add(otherName[:], series("\x00", "mem", "\xff", "free"))
+		return r
+	}
+
+	t.Run("all", func(t *testing.T) {
+		got := newReport().Summary()
+		want := &tsdb.CardinalitySummary{
+			Series: 4,
+			Measurements: []tsdb.MeasurementCardinality{
+				{
+					OrgID: org, BucketID: bucket, Measurement: "cpu", Series: 3,
+					TagKeys: []tsdb.TagKeyCardinality{
+						{Key: "_field", Series: 3, Values: 2},
+						{Key: "host", Series: 3, Values: 2},
+						{Key: "region", Series: 1, Values: 1},
+					},
+				},
+				{
+					OrgID: org, BucketID: other, Measurement: "mem", Series: 1,
+					TagKeys: []tsdb.TagKeyCardinality{
+						{Key: "_field", Series: 1, Values: 1},
+					},
+				},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected summary -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("bucket filter", func(t *testing.T) {
+		r := tsdb.NewCardinalityReport()
+		r.OrgID, r.BucketID = &org, &other
+		r.Add(name[:], series("\x00", "cpu", "\xff", "usage", "host", "a"))
+		r.Add(otherName[:], series("\x00", "mem", "\xff", "free"))
+
+		got := r.Summary()
+		if got.Series != 1 || len(got.Measurements) != 1 || got.Measurements[0].Measurement != "mem" {
+			t.Fatalf("unexpected summary %+v", got)
+		}
+	})
+}
@@ -18,11 +18,27 @@ type Config struct {
 	// LargeSeriesWriteThreshold is the threshold before a write requires
 	// preallocation to improve throughput. Currently used in the series file.
 	LargeSeriesWriteThreshold int `toml:"large-series-write-threshold"`
+
+	// SeriesFileCompactThreshold is the number of series created in a
+	// partition of the series file since its index was last compacted that
+	// triggers a compaction of the index; 0 disables the trigger.
+	SeriesFileCompactThreshold int `toml:"series-file-compact-threshold"`
+
+	// SeriesFileTombstoneCompactThreshold is the number of series deleted in
+	// a partition of the series file since its index was last compacted
+	// that triggers a compaction of the index; 0 disables the trigger.
+	SeriesFileTombstoneCompactThreshold int `toml:"series-file-tombstone-compact-threshold"`
+
+	// SeriesFileMaxConcurrentCompactions is the maximum number of partitions
+	// of the series file compacting at once; 0 means no limit.
+	SeriesFileMaxConcurrentCompactions int `toml:"series-file-max-concurrent-compactions"`
 }
 
 // NewConfig return a new instance of config with default settings.
 func NewConfig() Config {
 	return Config{
-		LargeSeriesWriteThreshold: DefaultLargeSeriesWriteThreshold,
+		LargeSeriesWriteThreshold:           DefaultLargeSeriesWriteThreshold,
+		SeriesFileCompactThreshold:          DefaultSeriesPartitionCompactThreshold,
+		SeriesFileTombstoneCompactThreshold: DefaultSeriesPartitionTombstoneCompactThreshold,
 	}
 }
@@ -53,6 +53,13 @@ type CursorIterator interface {
 	Stats() CursorStats
 }
 
+// SketchIterator is implemented by cursor iterators that can summarize a
+// series without reading every value. Sketch returns nil if the series
+// does not exist.
+type SketchIterator interface {
+	Sketch(ctx context.Context, r *CursorRequest) (*Sketch, error)
+}
+
 type CursorIterators []CursorIterator
 
 // Stats returns the aggregate stats of all cursor iterators.
@@ -0,0 +1,127 @@
+package cursors
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/pkg/estimator/tdigest"
+)
+
+// SketchPrecision is the precision of the HyperLogLog sketches that estimate
+// the number of distinct values. Sketches can only be merged with sketches
+// of the same precision. The sketches of blocks remain sparse, so a high
+// precision only costs memory while the sketches of a series are merged.
+const SketchPrecision = hll.DefaultPrecision
+
+// Sketch summarizes the values of a series, so that quantiles and the number
+// of distinct values can be estimated without reading every value.
+type Sketch struct {
+	// Digest estimates the quantiles of numeric values. It is nil for
+	// string and boolean series.
+	Digest *tdigest.TDigest
+
+	// Distinct estimates the number of distinct values.
+	Distinct *hll.Plus
+
+	buf [8]byte
+}
+
+// NewSketch returns an empty sketch. Digest is only set when numeric is true.
+func NewSketch(numeric bool) *Sketch {
+	s := &Sketch{Distinct: newDistinctSketch()}
+	if numeric {
+		s.Digest = tdigest.New()
+	}
+	return s
+}
+
+func newDistinctSketch() *hll.Plus {
+	h, err := hll.NewPlus(SketchPrecision)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func (s *Sketch) AddFloat(v float64) {
+	s.Digest.Add(v)
+	binary.BigEndian.PutUint64(s.buf[:], math.Float64bits(v))
+	s.Distinct.Add(s.buf[:])
+}
+
+func (s *Sketch) AddInteger(v int64) {
+	s.Digest.Add(float64(v))
+	binary.BigEndian.PutUint64(s.buf[:], uint64(v))
+	s.Distinct.Add(s.buf[:])
+}
+
+func (s *Sketch) AddUnsigned(v uint64) {
+	s.Digest.Add(float64(v))
+	binary.BigEndian.PutUint64(s.buf[:], v)
+	s.Distinct.Add(s.buf[:])
+}
+
+func (s *Sketch) AddString(v string) {
+	s.Distinct.Add([]byte(v))
+}
+
+func (s *Sketch) AddBoolean(v bool) {
+	s.buf[0] = 0
+	if v {
+		s.buf[0] = 1
+	}
+	s.Distinct.Add(s.buf[:1])
+}
+
+// AddCursor adds all values of cur to s. The cursor must be one of the
+// array cursors; it is not closed.
+func (s *Sketch) AddCursor(cur Cursor) error {
+	switch c := cur.(type) {
+	case FloatArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for _, v := range a.Values {
+				s.AddFloat(v)
+			}
+		}
+	case IntegerArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for _, v := range a.Values {
+				s.AddInteger(v)
+			}
+		}
+	case UnsignedArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for _, v := range a.Values {
+				s.AddUnsigned(v)
+			}
+		}
+	case StringArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for _, v := range a.Values {
+				s.AddString(v)
+			}
+		}
+	case BooleanArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for _, v := range a.Values {
+				s.AddBoolean(v)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported cursor type %T", cur)
+	}
+	return cur.Err()
+}
+
+// Merge adds the values summarized by other to s.
+func (s *Sketch) Merge(other *Sketch) error {
+	if other.Digest != nil {
+		if s.Digest == nil {
+			s.Digest = tdigest.New()
+		}
+		s.Digest.Merge(other.Digest)
+	}
+	return s.Distinct.Merge(other.Distinct)
+}
@@ -44,6 +44,9 @@ type seriesFileMetrics struct {
 	DiskSize      *prometheus.GaugeVec   // Size occupied on disk.
 	Segments      *prometheus.GaugeVec   // Number of segment files.
 
+	Tombstones     *prometheus.GaugeVec // Number of deleted series not yet removed from the index.
+	TombstoneRatio *prometheus.GaugeVec // Ratio of deleted series to series in the index.
+
 	CompactionsActive  *prometheus.GaugeVec     // Number of active compactions.
 	CompactionDuration *prometheus.HistogramVec // Duration of compactions.
 	// The following metrics include a ``"status" = {ok, error}` label
@@ -89,6 +92,18 @@ func newSeriesFileMetrics(labels prometheus.Labels) *seriesFileMetrics {
 			Name:      "segments_total",
 			Help:      "Number of segment files in Series File.",
 		}, names),
+		Tombstones: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: seriesFileSubsystem,
+			Name:      "tombstones_total",
+			Help:      "Number of deleted series in Series File not yet removed by an index compaction.",
+		}, names),
+		TombstoneRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: seriesFileSubsystem,
+			Name:      "tombstone_ratio",
+			Help:      "Ratio of deleted series to all series in the index of Series File.",
+		}, names),
 		CompactionsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: seriesFileSubsystem,
@@ -119,6 +134,8 @@ func (m *seriesFileMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.Series,
 		m.DiskSize,
 		m.Segments,
+		m.Tombstones,
+		m.TombstoneRatio,
 		m.CompactionsActive,
 		m.CompactionDuration,
 		m.Compactions,
@@ -31,6 +31,8 @@ func TestMetrics_SeriesPartition(t *testing.T) {
 		base + "disk_bytes",
 		base + "segments_total",
 		base + "index_compactions_active",
+		base + "tombstones_total",
+		base + "tombstone_ratio",
 	}
 
 	counters := []string{
@@ -51,6 +53,7 @@ func TestMetrics_SeriesPartition(t *testing.T) {
 		labels := tracker.Labels()
 		labels["component"] = "index"
 		tracker.metrics.CompactionsActive.With(labels).Add(float64(i + len(gauges[3])))
+		tracker.SetTombstones(uint64(i+len(gauges[4])), float64(i+len(gauges[5])))
 
 		tracker.AddSeriesCreated(uint64(i + len(counters[0])))
 		labels = tracker.Labels()
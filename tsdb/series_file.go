@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/binaryutil"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
+	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/rhh"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/multierr"
@@ -51,6 +52,15 @@ type SeriesFile struct {
 
 	LargeWriteThreshold int
 
+	// CompactThreshold and TombstoneCompactThreshold are the numbers of
+	// series and deleted series in the in-memory index of a partition that
+	// trigger a compaction of its index; 0 disables the trigger.
+	CompactThreshold          int
+	TombstoneCompactThreshold int
+	// MaxConcurrentCompactions limits the number of partitions compacting at
+	// once; 0 means no limit.
+	MaxConcurrentCompactions int
+
 	Logger *zap.Logger
 }
 
@@ -61,7 +71,9 @@ func NewSeriesFile(path string) *SeriesFile {
 		metricsEnabled: true,
 		Logger:         zap.NewNop(),
 
-		LargeWriteThreshold: DefaultLargeSeriesWriteThreshold,
+		LargeWriteThreshold:       DefaultLargeSeriesWriteThreshold,
+		CompactThreshold:          DefaultSeriesPartitionCompactThreshold,
+		TombstoneCompactThreshold: DefaultSeriesPartitionTombstoneCompactThreshold,
 	}
 }
 
@@ -121,12 +133,21 @@ func (f *SeriesFile) Open(ctx context.Context) error {
 	}
 	mmu.Unlock()
 
+	// All partitions share the limit of concurrent compactions.
+	var compactionLimiter limiter.Fixed
+	if f.MaxConcurrentCompactions > 0 {
+		compactionLimiter = limiter.NewFixed(f.MaxConcurrentCompactions)
+	}
+
 	// Open partitions.
 	f.partitions = make([]*SeriesPartition, 0, SeriesFilePartitionN)
 	for i := 0; i < SeriesFilePartitionN; i++ {
 		// TODO(edd): These partition initialisation should be moved up to NewSeriesFile.
 		p := NewSeriesPartition(i, f.SeriesPartitionPath(i))
 		p.LargeWriteThreshold = f.LargeWriteThreshold
+		p.CompactThreshold = f.CompactThreshold
+		p.TombstoneCompactThreshold = f.TombstoneCompactThreshold
+		p.compactionLimiter = compactionLimiter
 		p.Logger = f.Logger.With(zap.Int("partition", p.ID()))
 
 		// For each series file index, rhh trackers are used to track the RHH Hashmap.
@@ -212,6 +233,52 @@ func (f *SeriesFile) DisableCompactions() {
 	}
 }
 
+// Stats returns the stats of the partitions.
+func (f *SeriesFile) Stats() []SeriesPartitionStats {
+	stats := make([]SeriesPartitionStats, 0, len(f.partitions))
+	for _, p := range f.partitions {
+		stats = append(stats, p.Stats())
+	}
+	return stats
+}
+
+// Compact rebuilds the indexes of the partitions one at a time and waits
+// for the compactions to finish.
+func (f *SeriesFile) Compact(ctx context.Context) error {
+	ref, err := f.Acquire()
+	if err != nil {
+		return err
+	}
+	defer ref.Release()
+
+	for _, p := range f.partitions {
+		if err := p.Compact(ctx); err != nil {
+			return fmt.Errorf("series partition %d: %v", p.ID(), err)
+		}
+	}
+	return nil
+}
+
+// Verify checks the indexes of the partitions against their segments while
+// the series file is in use, and rebuilds inconsistent indexes if repair is true.
+func (f *SeriesFile) Verify(ctx context.Context, repair bool) ([]SeriesPartitionVerification, error) {
+	ref, err := f.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ref.Release()
+
+	vs := make([]SeriesPartitionVerification, 0, len(f.partitions))
+	for _, p := range f.partitions {
+		v, err := p.Verify(ctx, repair)
+		if err != nil {
+			return nil, fmt.Errorf("series partition %d: %v", p.ID(), err)
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
 // CreateSeriesListIfNotExists creates a list of series in bulk if they don't exist. It overwrites
 // the collection's Keys and SeriesIDs fields. The collection's SeriesIDs slice will have IDs for
 // every name+tags, creating new series IDs as needed. If any SeriesID is zero, then a type
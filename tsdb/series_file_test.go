@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
@@ -271,6 +272,186 @@ func TestSeriesFile_DeleteSeriesID(t *testing.T) {
 	}
 }
 
+// Ensure deleted series trigger a compaction once a partition crosses the tombstone threshold.
+func TestSeriesFile_TombstoneCompactThreshold(t *testing.T) {
+	sfile := NewSeriesFile()
+	sfile.CompactThreshold = 0
+	sfile.TombstoneCompactThreshold = 10
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	collection := mustCreateSeries(t, sfile, 1000)
+	for _, id := range collection.SeriesIDs[:500] {
+		if err := sfile.DeleteSeriesID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Deleting 500 series crosses the threshold of every partition at least
+	// once, and leaves fewer than 10 tombstones per partition in memory.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var tombstones uint64
+		var compacting bool
+		for _, s := range sfile.Stats() {
+			tombstones += s.Tombstones
+			compacting = compacting || s.Compacting
+		}
+		if !compacting && tombstones < 10*tsdb.SeriesFilePartitionN {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("tombstones were not compacted: %d remaining", tombstones)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got, exp := sfile.SeriesCount(), uint64(500); got != exp {
+		t.Fatalf("SeriesCount()=%d, expected %d", got, exp)
+	}
+	for _, id := range collection.SeriesIDs[:500] {
+		if !sfile.IsDeleted(id) {
+			t.Fatalf("expected series %d to be deleted", id.RawID())
+		}
+	}
+}
+
+// Ensure an online compaction removes tombstones from the index.
+func TestSeriesFile_Compact(t *testing.T) {
+	sfile := NewSeriesFile()
+	sfile.CompactThreshold = 0
+	sfile.TombstoneCompactThreshold = 0
+	sfile.MaxConcurrentCompactions = 1
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+
+	collection := mustCreateSeries(t, sfile, 100)
+	for _, id := range collection.SeriesIDs[:10] {
+		if err := sfile.DeleteSeriesID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tombstones uint64
+	for _, s := range sfile.Stats() {
+		tombstones += s.Tombstones
+	}
+	if tombstones != 10 {
+		t.Fatalf("unexpected tombstones before compaction: %d", tombstones)
+	}
+
+	if err := sfile.Compact(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range sfile.Stats() {
+		if s.Tombstones != 0 || s.TombstoneRatio != 0 {
+			t.Fatalf("unexpected tombstones in partition %d after compaction: %d", s.ID, s.Tombstones)
+		}
+	}
+	if got, exp := sfile.SeriesCount(), uint64(90); got != exp {
+		t.Fatalf("SeriesCount()=%d, expected %d", got, exp)
+	}
+}
+
+// Ensure an inconsistent index is detected and rebuilt by an online verification.
+func TestSeriesFile_Verify(t *testing.T) {
+	src := MustOpenSeriesFile()
+	defer src.Close()
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	// Create the same series in a different order, so that their offsets
+	// and IDs differ between the series files.
+	mustCreateSeries(t, src, 100)
+	collection := new(tsdb.SeriesCollection)
+	for i := 99; i >= 0; i-- {
+		collection.Names = append(collection.Names, []byte(fmt.Sprintf("m%d", i)))
+		collection.Tags = append(collection.Tags, models.Tags{})
+		collection.Types = append(collection.Types, models.Integer)
+	}
+	if err := sfile.CreateSeriesListIfNotExists(collection); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.ForceCompact(); err != nil {
+		t.Fatal(err)
+	} else if err := sfile.ForceCompact(); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := sfile.Verify(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vs {
+		if !v.Valid() {
+			t.Fatalf("unexpected errors in partition %d: %v", v.ID, v.Errors)
+		}
+	}
+
+	// Replace the indexes with the indexes of the other series file.
+	if err := sfile.SeriesFile.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range src.Partitions() {
+		data, err := ioutil.ReadFile(p.IndexPath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path.Join(sfile.SeriesPartitionPath(p.ID()), "index"), data, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sfile.SeriesFile = tsdb.NewSeriesFile(sfile.Path())
+	if err := sfile.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err = sfile.Verify(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var invalid bool
+	for _, v := range vs {
+		invalid = invalid || !v.Valid()
+	}
+	if !invalid {
+		t.Fatal("expected the replaced indexes to be inconsistent")
+	}
+
+	vs, err = sfile.Verify(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vs {
+		if !v.Valid() {
+			t.Fatalf("unexpected errors in partition %d after repair: %v", v.ID, v.Errors)
+		}
+	}
+	for iter := collection.Iterator(); iter.Next(); {
+		if id := sfile.SeriesID(iter.Name(), iter.Tags(), nil); id.IsZero() {
+			t.Fatalf("series does not exist after repair: %s", iter.Name())
+		}
+	}
+}
+
+// mustCreateSeries creates n series in the series file.
+func mustCreateSeries(t *testing.T, sfile *SeriesFile, n int) *tsdb.SeriesCollection {
+	t.Helper()
+	collection := new(tsdb.SeriesCollection)
+	for i := 0; i < n; i++ {
+		collection.Names = append(collection.Names, []byte(fmt.Sprintf("m%d", i)))
+		collection.Tags = append(collection.Tags, models.Tags{})
+		collection.Types = append(collection.Types, models.Integer)
+	}
+	if err := sfile.CreateSeriesListIfNotExists(collection); err != nil {
+		t.Fatal(err)
+	}
+	return collection
+}
+
 // Series represents name/tagset pairs that are used in testing.
 type Series struct {
 	Name    []byte
@@ -172,6 +172,9 @@ func (idx *SeriesIndex) Count() uint64 {
 	return uint64(n)
 }
 
+// TombstoneCount returns the number of deleted series in the index.
+func (idx *SeriesIndex) TombstoneCount() uint64 { return uint64(len(idx.tombstones)) }
+
 // OnDiskCount returns the number of series in the on-disk index.
 func (idx *SeriesIndex) OnDiskCount() uint64 { return idx.count }
 
@@ -238,6 +241,11 @@ func (idx *SeriesIndex) FindIDBySeriesKey(segments []*SeriesSegment, key []byte)
 			return id
 		}
 	}
+	return idx.findOnDiskIDBySeriesKey(segments, key)
+}
+
+// findOnDiskIDBySeriesKey looks up the series key in the on-disk index only.
+func (idx *SeriesIndex) findOnDiskIDBySeriesKey(segments []*SeriesSegment, key []byte) SeriesIDTyped {
 	if len(idx.data) == 0 {
 		return SeriesIDTyped{}
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/rhh"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -23,12 +24,21 @@ import (
 var (
 	ErrSeriesPartitionClosed              = errors.New("tsdb: series partition closed")
 	ErrSeriesPartitionCompactionCancelled = errors.New("tsdb: series partition compaction cancelled")
+	ErrSeriesPartitionCompactionsDisabled = errors.New("tsdb: series partition compactions disabled")
 )
 
 // DefaultSeriesPartitionCompactThreshold is the number of series IDs to hold in the in-memory
 // series map before compacting and rebuilding the on-disk representation.
 const DefaultSeriesPartitionCompactThreshold = 1 << 17 // 128K
 
+// DefaultSeriesPartitionTombstoneCompactThreshold is the number of deleted series IDs to hold
+// in the in-memory series map before compacting and rebuilding the on-disk representation.
+const DefaultSeriesPartitionTombstoneCompactThreshold = 1 << 14 // 16K
+
+// maxSeriesPartitionVerifyErrors is the maximum number of errors reported by
+// a verification of a partition.
+const maxSeriesPartitionVerifyErrors = 100
+
 // SeriesPartition represents a subset of series file data.
 type SeriesPartition struct {
 	mu   sync.RWMutex
@@ -45,10 +55,13 @@ type SeriesPartition struct {
 	seq      uint64 // series id sequence
 
 	compacting          bool
+	compaction          *seriesPartitionCompaction // the running compaction, if compacting
 	compactionsDisabled int
+	compactionLimiter   limiter.Fixed // limits concurrent compactions of partitions, if set
 
-	CompactThreshold    int
-	LargeWriteThreshold int
+	CompactThreshold          int
+	TombstoneCompactThreshold int
+	LargeWriteThreshold       int
 
 	tracker *seriesPartitionTracker
 	Logger  *zap.Logger
@@ -60,11 +73,12 @@ func NewSeriesPartition(id int, path string) *SeriesPartition {
 		id:                  id,
 		path:                path,
 		closing:             make(chan struct{}),
-		CompactThreshold:    DefaultSeriesPartitionCompactThreshold,
-		LargeWriteThreshold: DefaultLargeSeriesWriteThreshold,
-		tracker:             newSeriesPartitionTracker(newSeriesFileMetrics(nil), nil),
-		Logger:              zap.NewNop(),
-		seq:                 uint64(id) + 1,
+		CompactThreshold:          DefaultSeriesPartitionCompactThreshold,
+		TombstoneCompactThreshold: DefaultSeriesPartitionTombstoneCompactThreshold,
+		LargeWriteThreshold:       DefaultLargeSeriesWriteThreshold,
+		tracker:                   newSeriesPartitionTracker(newSeriesFileMetrics(nil), nil),
+		Logger:                    zap.NewNop(),
+		seq:                       uint64(id) + 1,
 	}
 	p.index = NewSeriesIndex(p.IndexPath())
 	return p
@@ -104,6 +118,7 @@ func (p *SeriesPartition) Open() error {
 
 	p.tracker.SetSeries(p.index.Count()) // Set series count metric.
 	p.tracker.SetDiskSize(p.DiskSize())  // Set on-disk size metric.
+	p.trackTombstones()                  // Set tombstone metrics.
 	return nil
 }
 
@@ -307,39 +322,128 @@ func (p *SeriesPartition) CreateSeriesListIfNotExists(collection *SeriesCollecti
 	p.tracker.AddSeries(uint64(len(newKeyRanges)))
 
 	// Check if we've crossed the compaction threshold.
-	if p.compactionsEnabled() && !p.compacting && p.CompactThreshold != 0 && p.index.InMemCount() >= uint64(p.CompactThreshold) {
-		p.compacting = true
-		log, logEnd := logger.NewOperation(ctx, p.Logger, "Series partition compaction", "series_partition_compaction", zap.String("path", p.path))
+	if p.needsCompaction() {
+		p.compact(ctx)
+	}
 
-		p.wg.Add(1)
-		p.tracker.IncCompactionsActive()
-		go func() {
-			defer p.wg.Done()
+	return nil
+}
+
+// seriesPartitionCompaction is a running compaction of a partition.
+type seriesPartitionCompaction struct {
+	done chan struct{} // closed when the compaction finished
+	err  error         // set before done is closed
+}
 
+// needsCompaction returns true if a compaction should be started because
+// the in-memory index crossed one of the compaction thresholds. It must be
+// called with the lock held.
+func (p *SeriesPartition) needsCompaction() bool {
+	if !p.compactionsEnabled() || p.compacting {
+		return false
+	}
+	if p.CompactThreshold != 0 && p.index.InMemCount() >= uint64(p.CompactThreshold) {
+		return true
+	}
+	return p.TombstoneCompactThreshold != 0 && p.index.TombstoneCount() >= uint64(p.TombstoneCompactThreshold)
+}
+
+// compact starts a compaction of the index in the background. It must be
+// called with the write lock held while no compaction is running.
+func (p *SeriesPartition) compact(ctx context.Context) *seriesPartitionCompaction {
+	c := &seriesPartitionCompaction{done: make(chan struct{})}
+	p.compacting, p.compaction = true, c
+	log, logEnd := logger.NewOperation(ctx, p.Logger, "Series partition compaction", "series_partition_compaction", zap.String("path", p.path))
+
+	p.wg.Add(1)
+	p.tracker.IncCompactionsActive()
+	go func() {
+		defer p.wg.Done()
+
+		var (
+			duration time.Duration
+			err      error
+		)
+		if p.compactionLimiter != nil {
+			// Wait for other partitions to finish compacting.
+			select {
+			case p.compactionLimiter <- struct{}{}:
+			case <-p.closing:
+				err = ErrSeriesPartitionCompactionCancelled
+			}
+		}
+		if err == nil {
 			compactor := NewSeriesPartitionCompactor()
 			compactor.cancel = p.closing
-			duration, err := compactor.Compact(p)
-			if err != nil {
-				p.tracker.IncCompactionErr()
-				log.Error("series partition compaction failed", zap.Error(err))
-			} else {
-				p.tracker.IncCompactionOK(duration)
+			duration, err = compactor.Compact(p)
+			if p.compactionLimiter != nil {
+				p.compactionLimiter.Release()
 			}
+		}
+		if err != nil {
+			p.tracker.IncCompactionErr()
+			log.Error("series partition compaction failed", zap.Error(err))
+		} else {
+			p.tracker.IncCompactionOK(duration)
+		}
 
-			logEnd()
+		logEnd()
+
+		// Clear compaction flag.
+		p.mu.Lock()
+		p.compacting, p.compaction = false, nil
+		p.trackTombstones()
+		c.err = err
+		close(c.done)
+		p.mu.Unlock()
+		p.tracker.DecCompactionsActive()
 
-			// Clear compaction flag.
-			p.mu.Lock()
-			p.compacting = false
+		// Disk size may have changed due to compaction.
+		p.tracker.SetDiskSize(p.DiskSize())
+	}()
+	return c
+}
+
+// Compact rebuilds the index of the partition and waits for the compaction
+// to finish. A compaction that is already running is waited for first, so
+// that the index excludes all series deleted before the call.
+func (p *SeriesPartition) Compact(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		if p.closed {
 			p.mu.Unlock()
-			p.tracker.DecCompactionsActive()
+			return ErrSeriesPartitionClosed
+		} else if !p.compactionsEnabled() {
+			p.mu.Unlock()
+			return ErrSeriesPartitionCompactionsDisabled
+		}
+
+		running := p.compacting
+		c := p.compaction
+		if !running {
+			c = p.compact(ctx)
+		}
+		p.mu.Unlock()
 
-			// Disk size may have changed due to compaction.
-			p.tracker.SetDiskSize(p.DiskSize())
-		}()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !running {
+			return c.err
+		}
 	}
+}
 
-	return nil
+// trackTombstones sets the tombstone metrics. It must be called with the lock held.
+func (p *SeriesPartition) trackTombstones() {
+	n := p.index.TombstoneCount()
+	var ratio float64
+	if total := p.index.OnDiskCount() + p.index.InMemCount(); total > 0 {
+		ratio = float64(n) / float64(total)
+	}
+	p.tracker.SetTombstones(n, ratio)
 }
 
 // Compacting returns if the SeriesPartition is currently compacting.
@@ -380,6 +484,12 @@ func (p *SeriesPartition) DeleteSeriesID(id SeriesID) error {
 	// Mark tombstone in memory.
 	p.index.Delete(id)
 	p.tracker.SubSeries(1)
+	p.trackTombstones()
+
+	// Check if we've crossed the tombstone compaction threshold.
+	if p.needsCompaction() {
+		p.compact(context.Background())
+	}
 	return nil
 }
 
@@ -483,6 +593,152 @@ func (p *SeriesPartition) compactionsEnabled() bool {
 	return p.compactionsDisabled == 0
 }
 
+// SeriesPartitionStats are the stats of a series partition.
+type SeriesPartitionStats struct {
+	ID int
+	// Series is the number of series, excluding deleted series.
+	Series uint64
+	// Tombstones is the number of deleted series not yet removed from the
+	// index by a compaction.
+	Tombstones uint64
+	// TombstoneRatio is the ratio of Tombstones to all series in the index.
+	TombstoneRatio float64
+	// DiskSize is the number of bytes of the segments and the index.
+	DiskSize   uint64
+	Segments   int
+	Compacting bool
+}
+
+// Stats returns the stats of the partition.
+func (p *SeriesPartition) Stats() SeriesPartitionStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := SeriesPartitionStats{ID: p.id}
+	if p.closed {
+		return stats
+	}
+	stats.Series = p.index.Count()
+	stats.Tombstones = p.index.TombstoneCount()
+	if total := p.index.OnDiskCount() + p.index.InMemCount(); total > 0 {
+		stats.TombstoneRatio = float64(stats.Tombstones) / float64(total)
+	}
+	stats.DiskSize = p.diskSize()
+	stats.Segments = len(p.segments)
+	stats.Compacting = p.compacting
+	return stats
+}
+
+// SeriesPartitionVerification is the result of the verification of a series partition.
+type SeriesPartitionVerification struct {
+	ID int
+	// Entries is the number of segment entries verified.
+	Entries int
+	// Errors are the inconsistencies found, up to a limit.
+	Errors []string
+	// Repaired is true if the index was rebuilt to repair the errors.
+	Repaired bool
+}
+
+// Valid returns true if no inconsistencies were found.
+func (v SeriesPartitionVerification) Valid() bool { return len(v.Errors) == 0 }
+
+// Verify checks that the index of the partition agrees with the series in
+// its segments while the partition is in use. Series created after the
+// verification started are not checked. If repair is true and the index
+// is inconsistent, the index is rebuilt from the segments and verified
+// again.
+func (p *SeriesPartition) Verify(ctx context.Context, repair bool) (SeriesPartitionVerification, error) {
+	v, err := p.verify(ctx)
+	if err != nil || v.Valid() || !repair {
+		return v, err
+	}
+
+	p.Logger.Warn("Rebuilding inconsistent series partition index", zap.String("path", p.path), zap.Strings("errors", v.Errors))
+	if err := p.Compact(ctx); err != nil {
+		return v, err
+	}
+	if v, err = p.verify(ctx); err != nil {
+		return v, err
+	}
+	v.Repaired = true
+	return v, nil
+}
+
+func (p *SeriesPartition) verify(ctx context.Context) (SeriesPartitionVerification, error) {
+	v := SeriesPartitionVerification{ID: p.id}
+
+	// Snapshot the segments and index, as a compaction does.
+	p.mu.Lock()
+	select {
+	case <-p.closing:
+		p.mu.Unlock()
+		return v, ErrSeriesPartitionClosed
+	default:
+	}
+	segments := CloneSeriesSegments(p.segments)
+	index := p.index.Clone()
+	p.wg.Add(1)
+	p.mu.Unlock()
+	defer p.wg.Done()
+
+	addError := func(format string, args ...interface{}) {
+		if len(v.Errors) < maxSeriesPartitionVerifyErrors {
+			v.Errors = append(v.Errors, fmt.Sprintf(format, args...))
+		}
+	}
+
+	errDone := errors.New("done")
+	for _, segment := range segments {
+		if err := segment.ForEachEntry(func(flag uint8, id SeriesIDTyped, offset int64, key []byte) error {
+			// Entries after the snapshot may not be indexed yet.
+			if offset > index.maxOffset {
+				return errDone
+			}
+
+			// Check for cancellation periodically.
+			if v.Entries++; v.Entries%1000 == 0 {
+				select {
+				case <-p.closing:
+					return ErrSeriesPartitionClosed
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+			}
+
+			if flag != SeriesEntryInsertFlag {
+				return nil
+			}
+
+			untypedID := id.SeriesID()
+			if partitionID := int((untypedID.RawID() - 1) % SeriesFilePartitionN); partitionID != p.id {
+				addError("series %d at offset %d belongs to partition %d", untypedID.RawID(), offset, partitionID)
+			}
+			if index.IsDeleted(untypedID) {
+				return nil
+			}
+			if indexOffset := index.FindOffsetByID(untypedID); indexOffset != offset {
+				addError("series %d is at offset %d but indexed at offset %d", untypedID.RawID(), offset, indexOffset)
+			}
+			// The in-memory key map is not part of the snapshot, so only keys
+			// of series in the on-disk index are looked up.
+			if _, ok := index.idOffsetMap[untypedID]; ok {
+				return nil
+			}
+			if indexID := index.findOnDiskIDBySeriesKey(segments, key); indexID.SeriesID() != untypedID {
+				addError("series key %q of series %d is indexed as series %d", key, untypedID.RawID(), indexID.SeriesID().RawID())
+			}
+			return nil
+		}); err == errDone {
+			break
+		} else if err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
 // AppendSeriesIDs returns a list of all series ids.
 func (p *SeriesPartition) AppendSeriesIDs(a []SeriesID) []SeriesID {
 	for _, segment := range p.segments {
@@ -645,6 +901,18 @@ func (t *seriesPartitionTracker) SetDiskSize(sz uint64) {
 	t.metrics.DiskSize.With(labels).Set(float64(sz))
 }
 
+// SetTombstones sets the number of deleted series in the index of the
+// partition and their ratio to all series in the index.
+func (t *seriesPartitionTracker) SetTombstones(n uint64, ratio float64) {
+	if !t.enabled {
+		return
+	}
+
+	labels := t.Labels()
+	t.metrics.Tombstones.With(labels).Set(float64(n))
+	t.metrics.TombstoneRatio.With(labels).Set(ratio)
+}
+
 // SetSegments sets the number of segments files for the partition.
 func (t *seriesPartitionTracker) SetSegments(n uint64) {
 	if !t.enabled {
@@ -21,6 +21,11 @@ type Config struct {
 	// be compacted less frequently, store more series in-memory, and provide higher write throughput.
 	MaxIndexLogFileSize toml.Size `toml:"max-index-log-file-size"`
 
+	// MaxConcurrentCompactions is the maximum number of log file and level
+	// compactions running at once across all partitions of the index. Zero
+	// does not limit compactions.
+	MaxConcurrentCompactions int `toml:"max-concurrent-compactions"`
+
 	// SeriesIDSetCacheSize determines the size taken up by the cache of series ID
 	// sets in the index. Since a series id set is a compressed bitmap of all series ids
 	// matching a tag key/value pair, setting this size does not necessarily limit the
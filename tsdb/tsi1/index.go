@@ -21,6 +21,7 @@ import (
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
+	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/slices"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/tsdb"
@@ -243,6 +244,12 @@ func (i *Index) Open(ctx context.Context) error {
 	i.tagValueCache.tracker = newCacheTracker(cms, i.defaultLabels)
 	i.tagValueCache.tracker.enabled = i.metricsEnabled
 
+	// All partitions share the limit of concurrent compactions.
+	var compactionLimiter limiter.Fixed
+	if i.config.MaxConcurrentCompactions > 0 {
+		compactionLimiter = limiter.NewFixed(i.config.MaxConcurrentCompactions)
+	}
+
 	// Initialize index partitions.
 	i.partitions = make([]*Partition, i.PartitionN)
 	for j := 0; j < len(i.partitions); j++ {
@@ -251,6 +258,7 @@ func (i *Index) Open(ctx context.Context) error {
 		p.StatsTTL = i.StatsTTL
 		p.nosync = i.disableFsync
 		p.logbufferSize = i.logfileBufferSize
+		p.compactionLimiter = compactionLimiter
 		p.logger = i.logger.With(zap.String("tsi1_partition", fmt.Sprint(j+1)))
 
 		// Each of the trackers needs to be given slightly different default
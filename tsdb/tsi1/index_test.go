@@ -331,6 +331,48 @@ func TestIndex_DiskSizeBytes(t *testing.T) {
 	})
 }
 
+// Ensure log files are compacted when compactions are limited to one at a time.
+func TestIndex_MaxConcurrentCompactions(t *testing.T) {
+	c := tsi1.NewConfig()
+	c.MaxIndexLogFileSize = 1 // compact the log file after every write.
+	c.MaxConcurrentCompactions = 1
+	idx := MustOpenIndex(2, c)
+	defer idx.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := idx.CreateSeriesSliceIfNotExists([]Series{
+			{Name: []byte(fmt.Sprintf("m%d", i)), Tags: models.NewTags(map[string]string{"region": "east"})},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx.DisableCompactions()
+	idx.Wait()
+	defer idx.EnableCompactions()
+
+	var indexFiles int
+	for i := 0; i < 2; i++ {
+		fs, err := idx.PartitionAt(i).FileSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		indexFiles += len(fs.IndexFiles())
+		fs.Release()
+	}
+	if indexFiles == 0 {
+		t.Fatal("expected log files to be compacted into index files")
+	}
+
+	for i := 0; i < 10; i++ {
+		if v, err := idx.MeasurementExists([]byte(fmt.Sprintf("m%d", i))); err != nil {
+			t.Fatal(err)
+		} else if !v {
+			t.Fatalf("expected measurement m%d", i)
+		}
+	}
+}
+
 // Ensure index can returns measurement cardinality stats.
 func TestIndex_MeasurementCardinalityStats(t *testing.T) {
 	t.Parallel()
@@ -114,8 +114,13 @@ type partitionMetrics struct {
 	// This metrics has a "type" = {index, log}
 	FilesTotal *prometheus.GaugeVec // files on disk.
 
-	// This metric has a "level" metric.
+	LogFileSize *prometheus.GaugeVec // Size of the active log file.
+
+	// These metrics have a "level" metric.
 	CompactionsActive *prometheus.GaugeVec // Number of active compactions.
+	LevelSize         *prometheus.GaugeVec // Size of the files of each level.
+
+	CompactionsQueued *prometheus.GaugeVec // Number of compactions waiting to run.
 
 	// These metrics have a "level" metric.
 	// The following metrics include a "status" = {ok, error}` label
@@ -188,12 +193,30 @@ func newPartitionMetrics(labels prometheus.Labels) *partitionMetrics {
 			Name:      "disk_bytes",
 			Help:      "Number of bytes TSI partition is using on disk.",
 		}, names),
+		LogFileSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: partitionSubsystem,
+			Name:      "log_file_bytes",
+			Help:      "Number of bytes of the active log file of the partition.",
+		}, names),
 		CompactionsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: partitionSubsystem,
 			Name:      "compactions_active",
 			Help:      "Number of active partition compactions.",
 		}, compactionNames),
+		LevelSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: partitionSubsystem,
+			Name:      "level_bytes",
+			Help:      "Number of bytes of the files of a compaction level of the partition. Level 0 are log files.",
+		}, compactionNames),
+		CompactionsQueued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: partitionSubsystem,
+			Name:      "compactions_queued",
+			Help:      "Number of partition compactions waiting for the compaction limit.",
+		}, names),
 		CompactionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: partitionSubsystem,
@@ -221,7 +244,10 @@ func (m *partitionMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.Measurements,
 		m.FilesTotal,
 		m.DiskSize,
+		m.LogFileSize,
 		m.CompactionsActive,
+		m.LevelSize,
+		m.CompactionsQueued,
 		m.CompactionDuration,
 		m.Compactions,
 	}
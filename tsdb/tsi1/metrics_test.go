@@ -107,6 +107,9 @@ func TestMetrics_Partition(t *testing.T) {
 		base + "files_total",
 		base + "disk_bytes",
 		base + "compactions_active",
+		base + "log_file_bytes",
+		base + "level_bytes",
+		base + "compactions_queued",
 	}
 
 	counters := []string{
@@ -131,6 +134,9 @@ func TestMetrics_Partition(t *testing.T) {
 		labels = tracker.Labels()
 		labels["level"] = "2"
 		tracker.metrics.CompactionsActive.With(labels).Add(float64(i + len(gauges[4])))
+		tracker.SetLogFileSize(uint64(i + len(gauges[5])))
+		tracker.SetLevelSize(2, uint64(i+len(gauges[6])))
+		tracker.metrics.CompactionsQueued.With(tracker.Labels()).Add(float64(i + len(gauges[7])))
 
 		tracker.metrics.SeriesCreated.With(tracker.Labels()).Add(float64(i + len(counters[0])))
 		tracker.AddSeriesDropped(uint64(i + len(counters[1])))
@@ -170,7 +176,7 @@ func TestMetrics_Partition(t *testing.T) {
 				}
 				l["type"] = "index"
 				metric = promtest.MustFindMetric(t, mfs, name, l)
-			} else if i == 4 {
+			} else if i == 4 || i == 6 {
 				l := make(prometheus.Labels, len(labels))
 				for k, v := range labels {
 					l[k] = v
@@ -20,6 +20,7 @@ import (
 	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
+	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
 	"github.com/prometheus/client_golang/prometheus"
@@ -76,6 +77,10 @@ type Partition struct {
 	compactionsDisabled int               // counter of disables
 	currentCompactionN  int               // counter of in-progress compactions
 
+	// compactionLimiter limits the compactions running at once across the
+	// partitions of an index. A nil limiter does not limit compactions.
+	compactionLimiter limiter.Fixed
+
 	// Directory of the Partition's index files.
 	path string
 	id   string // id portion of path.
@@ -275,9 +280,7 @@ func (p *Partition) Open() (err error) {
 		return err
 	}
 	p.tracker.SetSeries(p.seriesIDSet.Cardinality())
-	p.tracker.SetFiles(uint64(len(p.fileSet.IndexFiles())), "index")
-	p.tracker.SetFiles(uint64(len(p.fileSet.LogFiles())), "log")
-	p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+	p.trackFiles()
 
 	// Mark opened.
 	p.res.Open()
@@ -506,9 +509,7 @@ func (p *Partition) prependActiveLogFile() error {
 	p.manifestSize = manifestSize
 
 	// Set the file metrics again.
-	p.tracker.SetFiles(uint64(len(p.fileSet.IndexFiles())), "index")
-	p.tracker.SetFiles(uint64(len(p.fileSet.LogFiles())), "log")
-	p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+	p.trackFiles()
 	return nil
 }
 
@@ -744,6 +745,7 @@ func (p *Partition) createSeriesListIfNotExists(collection *tsdb.SeriesCollectio
 		p.tracker.AddSeries(totalNew)
 		p.mu.RLock()
 		p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+		p.tracker.SetLogFileSize(uint64(p.activeLogFile.Size()))
 		p.mu.RUnlock()
 	}
 	return ids, nil
@@ -985,8 +987,11 @@ func (p *Partition) compact() {
 		// Start compacting in a separate goroutine.
 		p.currentCompactionN++
 		go func(level int) {
-			// Compact to a new level.
-			p.compactToLevel(files, frefs, level+1, ref.Closing())
+			// Compact to a new level once the compaction limit allows it.
+			if p.acquireCompaction(ref.Closing()) {
+				p.compactToLevel(files, frefs, level+1, ref.Closing())
+				p.releaseCompaction()
+			}
 
 			// Ensure references are released.
 			frefs.Release()
@@ -1020,9 +1025,7 @@ func (p *Partition) compactToLevel(files []*IndexFile, frefs lifecycle.Reference
 	defer func() {
 		p.mu.RLock()
 		defer p.mu.RUnlock()
-		p.tracker.SetFiles(uint64(len(p.fileSet.IndexFiles())), "index")
-		p.tracker.SetFiles(uint64(len(p.fileSet.LogFiles())), "log")
-		p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+		p.trackFiles()
 		p.tracker.DecActiveCompaction(level)
 
 		success := err == nil
@@ -1137,6 +1140,53 @@ func (p *Partition) compactToLevel(files []*IndexFile, frefs lifecycle.Reference
 	}
 }
 
+// acquireCompaction blocks until the compaction limiter allows another
+// compaction to run. It returns false if interrupt closes first.
+func (p *Partition) acquireCompaction(interrupt <-chan struct{}) bool {
+	if p.compactionLimiter == nil {
+		return true
+	}
+
+	p.tracker.IncQueuedCompaction()
+	defer p.tracker.DecQueuedCompaction()
+
+	select {
+	case p.compactionLimiter <- struct{}{}:
+		return true
+	case <-interrupt:
+		return false
+	}
+}
+
+// releaseCompaction releases the token taken by acquireCompaction.
+func (p *Partition) releaseCompaction() {
+	if p.compactionLimiter != nil {
+		p.compactionLimiter.Release()
+	}
+}
+
+// trackFiles sets the metrics of the files of the partition. The caller must
+// hold a lock on the partition.
+func (p *Partition) trackFiles() {
+	p.tracker.SetFiles(uint64(len(p.fileSet.IndexFiles())), "index")
+	p.tracker.SetFiles(uint64(len(p.fileSet.LogFiles())), "log")
+	p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+
+	// Set every level, so that levels emptied by a compaction are reset.
+	sizes := make([]uint64, len(p.levels))
+	for _, f := range p.fileSet.Files() {
+		if level := f.Level(); level < len(sizes) {
+			sizes[level] += uint64(f.Size())
+		}
+	}
+	for level, n := range sizes {
+		p.tracker.SetLevelSize(level, n)
+	}
+	if p.activeLogFile != nil {
+		p.tracker.SetLogFileSize(uint64(p.activeLogFile.Size()))
+	}
+}
+
 func (p *Partition) CheckLogFile() error {
 	// Check log file size under read lock.
 	p.mu.RLock()
@@ -1184,7 +1234,10 @@ func (p *Partition) checkLogFile() error {
 	// Begin compacting in a background goroutine.
 	p.currentCompactionN++
 	go func() {
-		p.compactLogFile(ctx, logFile, ref.Closing())
+		if p.acquireCompaction(ref.Closing()) {
+			p.compactLogFile(ctx, logFile, ref.Closing())
+			p.releaseCompaction()
+		}
 		ref.Release() // release our reference
 
 		p.mu.Lock()
@@ -1201,16 +1254,19 @@ func (p *Partition) checkLogFile() error {
 // same identifier but will have a ".tsi" extension. Once the log file is
 // compacted then the manifest is updated and the log file is discarded.
 func (p *Partition) compactLogFile(ctx context.Context, logFile *LogFile, interrupt <-chan struct{}) {
+	start := time.Now()
+	var success bool
+
+	// Log files are compacted from level 0.
+	p.tracker.IncActiveCompaction(0)
 	defer func() {
 		p.mu.RLock()
 		defer p.mu.RUnlock()
-		p.tracker.SetFiles(uint64(len(p.fileSet.IndexFiles())), "index")
-		p.tracker.SetFiles(uint64(len(p.fileSet.LogFiles())), "log")
-		p.tracker.SetDiskSize(uint64(p.fileSet.Size()))
+		p.trackFiles()
+		p.tracker.DecActiveCompaction(0)
+		p.tracker.CompactionAttempted(0, success, time.Since(start))
 	}()
 
-	start := time.Now()
-
 	// Retrieve identifier from current path.
 	id := logFile.ID()
 	assert(id != 0, "cannot parse log file id: %s", logFile.Path())
@@ -1278,6 +1334,7 @@ func (p *Partition) compactLogFile(ctx context.Context, logFile *LogFile, interr
 		log.Error("Cannot update manifest or stats", zap.Error(err))
 		return
 	}
+	success = true
 
 	elapsed := time.Since(start)
 	log.Info("Log file compacted",
@@ -1498,6 +1555,47 @@ func (t *partitionTracker) SetDiskSize(n uint64) {
 	t.metrics.DiskSize.With(labels).Set(float64(n))
 }
 
+// SetLogFileSize sets the size of the active log file of the partition.
+func (t *partitionTracker) SetLogFileSize(n uint64) {
+	if !t.enabled {
+		return
+	}
+
+	labels := t.Labels()
+	t.metrics.LogFileSize.With(labels).Set(float64(n))
+}
+
+// SetLevelSize sets the size of the files of the provided level.
+func (t *partitionTracker) SetLevelSize(level int, n uint64) {
+	if !t.enabled {
+		return
+	}
+
+	labels := t.Labels()
+	labels["level"] = fmt.Sprint(level)
+	t.metrics.LevelSize.With(labels).Set(float64(n))
+}
+
+// IncQueuedCompaction increments the number of compactions waiting to run.
+func (t *partitionTracker) IncQueuedCompaction() {
+	if !t.enabled {
+		return
+	}
+
+	labels := t.Labels()
+	t.metrics.CompactionsQueued.With(labels).Inc()
+}
+
+// DecQueuedCompaction decrements the number of compactions waiting to run.
+func (t *partitionTracker) DecQueuedCompaction() {
+	if !t.enabled {
+		return
+	}
+
+	labels := t.Labels()
+	t.metrics.CompactionsQueued.With(labels).Dec()
+}
+
 // IncActiveCompaction increments the number of active compactions for the provided level.
 func (t *partitionTracker) IncActiveCompaction(level int) {
 	if !t.enabled {
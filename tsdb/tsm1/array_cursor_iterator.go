@@ -3,6 +3,8 @@ package tsm1
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/metrics"
@@ -67,6 +69,93 @@ func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (
 	}
 }
 
+// Sketch summarizes the values of a series field from r.StartTime up to, but
+// not including, r.EndTime. Blocks of compacted files are summarized by their sketches, and
+// the values of all other blocks and of the cache are read.
+func (q *arrayCursorIterator) Sketch(ctx context.Context, r *tsdb.CursorRequest) (*cursors.Sketch, error) {
+	q.key = tsdb.AppendSeriesKey(q.key[:0], r.Name, r.Tags)
+	id := q.e.sfile.SeriesIDTypedBySeriesKey(q.key)
+	if id.IsZero() {
+		return nil, nil
+	}
+	typ := id.Type()
+	sketch := cursors.NewSketch(typ == models.Float || typ == models.Integer || typ == models.Unsigned)
+
+	key := append([]byte(nil), q.seriesFieldKeyBytes(r.Name, r.Tags, r.Field)...)
+	start, end := r.StartTime, r.EndTime-1
+	clean, dirty := q.e.FileStore.BlockSketches(key, start, end)
+
+	// Blocks that overlap the cache may have values that were overwritten.
+	var cached *TimeRange
+	if values := q.e.Cache.Values(key); len(values) > 0 {
+		cached = &TimeRange{Min: values[0].UnixNano(), Max: values[len(values)-1].UnixNano()}
+		dirty = append(dirty, *cached)
+	}
+	for _, b := range clean {
+		if cached != nil && cached.Overlaps(b.MinTime, b.MaxTime) {
+			dirty = append(dirty, TimeRange{Min: b.MinTime, Max: b.MaxTime})
+			continue
+		}
+		s, err := b.Sketch()
+		if err != nil {
+			return nil, err
+		} else if err := sketch.Merge(s); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read the values of the dirty ranges, which never overlap the blocks
+	// whose sketches were used.
+	req := *r
+	req.Ascending = true
+	for _, tr := range mergeTimeRanges(dirty) {
+		if tr.Min < start {
+			tr.Min = start
+		}
+		if tr.Max > end {
+			tr.Max = end
+		}
+		if tr.Min > tr.Max {
+			continue
+		}
+
+		req.StartTime, req.EndTime = tr.Min, tr.Max+1
+		cur, err := q.Next(ctx, &req)
+		if err != nil {
+			return nil, err
+		} else if cur == nil {
+			continue
+		}
+		err = sketch.AddCursor(cur)
+		cur.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sketch, nil
+}
+
+// mergeTimeRanges returns the union of a as sorted, non-overlapping ranges.
+func mergeTimeRanges(a []TimeRange) []TimeRange {
+	if len(a) == 0 {
+		return nil
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Min < a[j].Min })
+
+	merged := []TimeRange{a[0]}
+	for _, tr := range a[1:] {
+		last := &merged[len(merged)-1]
+		if tr.Min <= last.Max || (last.Max < math.MaxInt64 && tr.Min == last.Max+1) {
+			if tr.Max > last.Max {
+				last.Max = tr.Max
+			}
+			continue
+		}
+		merged = append(merged, tr)
+	}
+	return merged
+}
+
 func (q *arrayCursorIterator) seriesFieldKeyBytes(name []byte, tags models.Tags, field string) []byte {
 	q.key = models.AppendMakeKey(q.key[:0], name, tags)
 	q.key = append(q.key, KeyFieldSeparatorBytes...)
@@ -0,0 +1,120 @@
+package tsm1
+
+import (
+	"container/list"
+	"sync"
+)
+
+// BlockCache is a least recently used cache of the blocks of TSM files,
+// bounded by the total size of the blocks. It is safe for concurrent use and
+// may be shared by the file stores of several engines.
+//
+// Blocks read from the cache are copies of the blocks on the heap, so the
+// memory used by hot blocks is bounded by the size of the cache instead of
+// depending on how the OS manages the page cache of the mmap'd files.
+type BlockCache struct {
+	maxSize uint64
+
+	mu    sync.Mutex
+	size  uint64
+	lru   *list.List // of *blockCacheEntry, most recently used first.
+	files map[*mmapAccessor]map[int64]*list.Element
+}
+
+// blockCacheEntry is a block of a TSM file in the block cache.
+type blockCacheEntry struct {
+	file   *mmapAccessor
+	offset int64
+	b      []byte // The block with its checksum, as in the file.
+
+	// tracker accounts the block in the metrics of the engine that read it.
+	tracker *readTracker
+}
+
+// NewBlockCache returns a block cache holding at most maxSize bytes of blocks.
+func NewBlockCache(maxSize uint64) *BlockCache {
+	return &BlockCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		files:   make(map[*mmapAccessor]map[int64]*list.Element),
+	}
+}
+
+// Size returns the total size of the blocks in the cache.
+func (c *BlockCache) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// get returns the block of the file at offset, or nil if it is not cached.
+func (c *BlockCache) get(file *mmapAccessor, offset int64) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.files[file][offset]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*blockCacheEntry).b
+}
+
+// put adds the block of the file at offset to the cache, evicting the least
+// recently used blocks to make room for it. Blocks larger than the cache are
+// not cached.
+func (c *BlockCache) put(file *mmapAccessor, offset int64, b []byte, tracker *readTracker) {
+	size := uint64(len(b))
+	if size > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	blocks := c.files[file]
+	if blocks == nil {
+		blocks = make(map[int64]*list.Element)
+		c.files[file] = blocks
+	} else if _, ok := blocks[offset]; ok {
+		return
+	}
+
+	for c.size+size > c.maxSize {
+		e := c.remove(c.lru.Back())
+		e.tracker.AddBlockCacheEvictions(1)
+	}
+
+	blocks[offset] = c.lru.PushFront(&blockCacheEntry{
+		file:    file,
+		offset:  offset,
+		b:       b,
+		tracker: tracker,
+	})
+	c.size += size
+	tracker.AddBlockCacheBytes(int64(size))
+}
+
+// removeFile removes the blocks of the file from the cache.
+func (c *BlockCache) removeFile(file *mmapAccessor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, el := range c.files[file] {
+		c.remove(el)
+	}
+}
+
+// remove removes the element from the cache and returns its entry. The lock
+// must be held.
+func (c *BlockCache) remove(el *list.Element) *blockCacheEntry {
+	e := c.lru.Remove(el).(*blockCacheEntry)
+	blocks := c.files[e.file]
+	delete(blocks, e.offset)
+	if len(blocks) == 0 {
+		delete(c.files, e.file)
+	}
+	c.size -= uint64(len(e.b))
+	e.tracker.AddBlockCacheBytes(-int64(len(e.b)))
+	return e
+}
@@ -0,0 +1,178 @@
+package tsm1
+
+import (
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// mustWriteBlockCacheTSM writes a TSM file with a block of a single float
+// value for every key and returns it opened for reading.
+func mustWriteBlockCacheTSM(t *testing.T, dir string, keys ...string) *os.File {
+	t.Helper()
+
+	f := mustTempFile(dir)
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, k := range keys {
+		if err := w.Write([]byte(k), []Value{NewValue(1, float64(i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = os.Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestBlockCache(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	keys := []string{"cpu,host=a#!~#value", "cpu,host=b#!~#value", "cpu,host=c#!~#value"}
+	metrics := newReadMetrics(prometheus.Labels{"engine_id": ""})
+	t1 := newReadTracker(metrics, prometheus.Labels{"engine_id": "1"})
+	t2 := newReadTracker(metrics, prometheus.Labels{"engine_id": "2"})
+
+	// Find the size of the blocks by reading them without a cache.
+	r, err := NewTSMReader(mustWriteBlockCacheTSM(t, dir, keys...), withBlockCache(nil, t1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if _, err := r.Read([]byte(k), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blockSize := t1.mmapBytes / uint64(len(keys))
+	if t1.mmapBytes == 0 || t1.blockCacheMisses != 0 {
+		t.Fatalf("expected the blocks to be read from the file only, got %+v", t1)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache holds two blocks, shared by two readers.
+	cache := NewBlockCache(2 * blockSize)
+	r1, err := NewTSMReader(mustWriteBlockCacheTSM(t, dir, keys...), withBlockCache(cache, t1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r1.Close()
+	r2, err := NewTSMReader(mustWriteBlockCacheTSM(t, dir, keys...), withBlockCache(cache, t2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r2.Close()
+
+	read := func(r *TSMReader, key string, want float64) {
+		t.Helper()
+		values, err := r.Read([]byte(key), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 1 || values[0].Value() != want {
+			t.Fatalf("read %s = %v, want %v", key, values, want)
+		}
+	}
+
+	read(r1, keys[0], 0)
+	read(r1, keys[0], 0)
+	read(r1, keys[1], 1)
+	if t1.blockCacheHits != 1 || t1.blockCacheMisses != 2 || cache.Size() != 2*blockSize {
+		t.Fatalf("expected 1 hit and 2 misses filling the cache, got %d hits, %d misses, %d bytes", t1.blockCacheHits, t1.blockCacheMisses, cache.Size())
+	}
+
+	// Reading the block of another file evicts the least recently used block.
+	read(r2, keys[2], 2)
+	if t2.blockCacheMisses != 1 || t1.blockCacheEvictions != 1 || cache.Size() != 2*blockSize {
+		t.Fatalf("expected the block of r1 to be evicted, got %d misses, %d evictions, %d bytes", t2.blockCacheMisses, t1.blockCacheEvictions, cache.Size())
+	}
+	if t1.blockCacheBytes != int64(blockSize) || t2.blockCacheBytes != int64(blockSize) {
+		t.Fatalf("expected every reader to account its cached block, got %d and %d bytes", t1.blockCacheBytes, t2.blockCacheBytes)
+	}
+	read(r1, keys[1], 1)
+	read(r1, keys[0], 0)
+	if t1.blockCacheHits != 2 || t1.blockCacheMisses != 3 {
+		t.Fatalf("expected the evicted block to be read again, got %d hits, %d misses", t1.blockCacheHits, t1.blockCacheMisses)
+	}
+
+	// The raw reads share the cached blocks.
+	entries, err := r1.ReadEntries([]byte(keys[0]), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r1.ReadBytes(&entries[0], nil); err != nil {
+		t.Fatal(err)
+	}
+	if t1.blockCacheHits != 3 {
+		t.Fatalf("expected the raw read to hit the cache, got %d hits", t1.blockCacheHits)
+	}
+
+	// Closing a reader removes its blocks from the cache.
+	if err := r1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Size() != 0 || t1.blockCacheBytes != 0 {
+		t.Fatalf("expected the blocks of the closed reader to be removed, got %d bytes", cache.Size())
+	}
+}
+
+func TestBlockCache_LargeBlock(t *testing.T) {
+	cache := NewBlockCache(4)
+	tracker := newReadTracker(newReadMetrics(nil), nil)
+	m := &mmapAccessor{}
+
+	cache.put(m, 0, []byte("too large"), tracker)
+	if cache.Size() != 0 || cache.get(m, 0) != nil {
+		t.Fatalf("expected a block larger than the cache not to be cached")
+	}
+
+	cache.put(m, 8, []byte("abcd"), tracker)
+	cache.put(m, 8, []byte("abcd"), tracker)
+	if cache.Size() != 4 || string(cache.get(m, 8)) != "abcd" {
+		t.Fatalf("expected the block to be cached once, got %d bytes", cache.Size())
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, strategy := range []string{"", MADVStrategyNormal, MADVStrategyWillNeed, MADVStrategyRandom, MADVStrategySequential} {
+		c := NewConfig()
+		c.MADVStrategy = strategy
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate() of strategy %q: unexpected error %v", strategy, err)
+		}
+	}
+
+	c := NewConfig()
+	c.MADVStrategy = "dontneed"
+	if err := c.Validate(); err == nil {
+		t.Error("expected an unknown strategy to be invalid")
+	}
+
+	for _, tt := range []struct {
+		strategy string
+		willNeed bool
+		want     string
+	}{
+		{want: MADVStrategyNormal},
+		{willNeed: true, want: MADVStrategyWillNeed},
+		{strategy: MADVStrategyRandom, willNeed: true, want: MADVStrategyRandom},
+	} {
+		c := Config{MADVStrategy: tt.strategy, MADVWillNeed: tt.willNeed}
+		if got := c.madviseStrategy(); got != tt.want {
+			t.Errorf("madviseStrategy() of %+v = %s, want %s", c, got, tt.want)
+		}
+	}
+}
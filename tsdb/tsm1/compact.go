@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/tsdb"
 )
@@ -42,6 +43,9 @@ const (
 
 	// TSSFileExtension is the extension used for TSM stats files.
 	TSSFileExtension = "tss"
+
+	// TSKFileExtension is the extension used for TSM block sketch files.
+	TSKFileExtension = "tsk"
 )
 
 var (
@@ -698,6 +702,10 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// Sketches enables writing the sketches of the blocks of compacted files,
+	// which are used to estimate quantiles and distinct values at query time.
+	Sketches bool
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -1037,9 +1045,11 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 		// New TSM files are written to a temp file and renamed when fully completed.
 		fileName := filepath.Join(c.Dir, c.formatFileName(generation, sequence)+"."+TSMFileExtension+"."+TmpTSMFileExtension)
 		statsFileName := StatsFilename(fileName)
+		sketchFileName := SketchFilename(fileName)
 
-		// Write as much as possible to this file
-		err := c.write(fileName, iter, throttle)
+		// Write as much as possible to this file. Block sketches are only
+		// computed by compactions, as snapshots are compacted soon after.
+		err := c.write(fileName, iter, throttle, c.Sketches && src != nil)
 
 		// We've hit the max file limit and there is more to write.  Create a new file
 		// and continue.
@@ -1053,6 +1063,8 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 				return nil, err
 			} else if err := os.RemoveAll(statsFileName); err != nil && !os.IsNotExist(err) {
 				return nil, err
+			} else if err := os.RemoveAll(sketchFileName); err != nil && !os.IsNotExist(err) {
+				return nil, err
 			}
 			break
 		} else if _, ok := err.(errCompactionInProgress); ok {
@@ -1066,6 +1078,8 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 					return nil, err
 				} else if err := os.RemoveAll(StatsFilename(f)); err != nil && !os.IsNotExist(err) {
 					return nil, err
+				} else if err := os.RemoveAll(SketchFilename(f)); err != nil && !os.IsNotExist(err) {
+					return nil, err
 				}
 			}
 			// We hit an error and didn't finish the compaction.  Remove the temp file and abort.
@@ -1073,6 +1087,8 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 				return nil, err
 			} else if err := os.RemoveAll(statsFileName); err != nil && !os.IsNotExist(err) {
 				return nil, err
+			} else if err := os.RemoveAll(sketchFileName); err != nil && !os.IsNotExist(err) {
+				return nil, err
 			}
 			return nil, err
 		}
@@ -1084,7 +1100,7 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 	return files, nil
 }
 
-func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err error) {
+func (c *Compactor) write(path string, iter KeyIterator, throttle, sketches bool) (err error) {
 	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
 	if err != nil {
 		return errCompactionInProgress{err: err}
@@ -1122,12 +1138,31 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 		}
 	}
 
+	// Summarize the blocks in a sketch file next to the TSM file.
+	var (
+		sf *os.File
+		sw *SketchWriter
+	)
+	if sketches {
+		if sf, err = fs.CreateFileWithReplacement(SketchFilename(path)); err != nil {
+			w.Remove()
+			return err
+		}
+		sw = NewSketchWriter(sf)
+	}
+
 	defer func() {
 		closeErr := w.Close()
 		if err == nil {
 			err = closeErr
 		}
 
+		if sf != nil {
+			if closeErr := closeSketchFile(sw, sf); closeErr != nil && (err == nil || err == ErrMaxBlocksExceeded || err == errMaxFileExceeded) {
+				err = closeErr
+			}
+		}
+
 		// Check for errors where we should not remove the file
 		_, inProgress := err.(errCompactionInProgress)
 		maxBlocks := err == ErrMaxBlocksExceeded
@@ -1161,6 +1196,12 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return fmt.Errorf("invalid index entry for block. min=%d, max=%d", minTime, maxTime)
 		}
 
+		if sw != nil {
+			if err := sw.Add(key, minTime, maxTime, block); err != nil {
+				return err
+			}
+		}
+
 		// Write the key and value
 		if err := w.WriteBlock(key, minTime, maxTime, block); err == ErrMaxBlocksExceeded {
 			if err := w.WriteIndex(); err != nil {
@@ -1194,6 +1235,17 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 	return nil
 }
 
+// closeSketchFile writes the remaining block sketches, and syncs and closes f.
+func closeSketchFile(sw *SketchWriter, f *os.File) error {
+	defer f.Close()
+	if err := sw.Close(); err != nil {
+		return err
+	} else if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func (c *Compactor) add(files []string) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -1,6 +1,7 @@
 package tsm1
 
 import (
+	"fmt"
 	"runtime"
 	"time"
 
@@ -12,6 +13,11 @@ var DefaultMaxConcurrentOpens = runtime.GOMAXPROCS(0)
 const (
 	DefaultMADVWillNeed = false
 
+	// DefaultBlockCacheMaxSize is the default size of the block cache. The
+	// block cache is disabled by default, leaving the caching of TSM files to
+	// the page cache of the OS.
+	DefaultBlockCacheMaxSize = toml.Size(0)
+
 	// DefaultLargeSeriesWriteThreshold is the number of series per write
 	// that requires the series index be pregrown before insert.
 	DefaultLargeSeriesWriteThreshold = 10000
@@ -29,6 +35,20 @@ type Config struct {
 	// slow disks.
 	MADVWillNeed bool `toml:"use-madv-willneed"`
 
+	// MADVStrategy is the advice given to the kernel about the accesses to the
+	// mmap'd TSM files: normal, willneed, random or sequential. Random stops
+	// the kernel from reading ahead, which helps workloads reading small
+	// ranges of large datasets. If empty, willneed is used if MADVWillNeed is
+	// set and normal otherwise.
+	MADVStrategy string `toml:"madvise-strategy"`
+
+	// BlockCacheMaxSize is the maximum size of the blocks of TSM files kept in
+	// the block cache, shared by all the partitions of the storage engine. The
+	// block cache keeps the most recently read blocks on the heap, so the
+	// memory used to cache them is bounded instead of depending on the page
+	// cache of the OS. Zero disables the block cache.
+	BlockCacheMaxSize toml.Size `toml:"block-cache-max-size"`
+
 	// LargeSeriesWriteThreshold is the threshold before a write requires
 	// preallocation to improve throughput. Currently used in the series file.
 	LargeSeriesWriteThreshold int `toml:"large-series-write-threshold"`
@@ -42,6 +62,7 @@ func NewConfig() Config {
 	return Config{
 		MaxConcurrentOpens:        DefaultMaxConcurrentOpens,
 		MADVWillNeed:              DefaultMADVWillNeed,
+		BlockCacheMaxSize:         DefaultBlockCacheMaxSize,
 		LargeSeriesWriteThreshold: DefaultLargeSeriesWriteThreshold,
 
 		Cache: NewCacheConfig(),
@@ -50,15 +71,48 @@ func NewConfig() Config {
 			Throughput:            toml.Size(DefaultCompactThroughput),
 			ThroughputBurst:       toml.Size(DefaultCompactThroughputBurst),
 			MaxConcurrent:         DefaultCompactMaxConcurrent,
+			Sketches:              DefaultCompactSketches,
 		},
 	}
 }
 
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	switch c.MADVStrategy {
+	case "", MADVStrategyNormal, MADVStrategyWillNeed, MADVStrategyRandom, MADVStrategySequential:
+	default:
+		return fmt.Errorf("invalid madvise strategy %q, expected %q, %q, %q or %q",
+			c.MADVStrategy, MADVStrategyNormal, MADVStrategyWillNeed, MADVStrategyRandom, MADVStrategySequential)
+	}
+	return nil
+}
+
+// madviseStrategy returns the madvise strategy in effect.
+func (c Config) madviseStrategy() string {
+	if c.MADVStrategy == "" && c.MADVWillNeed {
+		return MADVStrategyWillNeed
+	}
+	if c.MADVStrategy == "" {
+		return MADVStrategyNormal
+	}
+	return c.MADVStrategy
+}
+
+// The strategies of the advice given to the kernel about the accesses to
+// mmap'd TSM files.
+const (
+	MADVStrategyNormal     = "normal"
+	MADVStrategyWillNeed   = "willneed"
+	MADVStrategyRandom     = "random"
+	MADVStrategySequential = "sequential"
+)
+
 const (
 	DefaultCompactFullWriteColdDuration = time.Duration(4 * time.Hour)
 	DefaultCompactThroughput            = 48 * 1024 * 1024
 	DefaultCompactThroughputBurst       = 48 * 1024 * 1024
 	DefaultCompactMaxConcurrent         = 0
+	DefaultCompactSketches              = false
 )
 
 // CompactionConfing holds all of the configuration for compactions. Eventually we want
@@ -82,6 +136,12 @@ type CompactionConfig struct {
 	// MaxConcurrent is the maximum number of concurrent full and level compactions that can
 	// run at one time.  A value of 0 results in 50% of runtime.GOMAXPROCS(0) used at runtime.
 	MaxConcurrent int `toml:"max-concurrent"`
+
+	// Sketches controls whether compactions write t-digest and HyperLogLog
+	// sketches of every block, which allow approximate quantiles and distinct
+	// counts to be computed without reading the blocks. It is disabled by
+	// default, as the sketches add to the size of the files compacted.
+	Sketches bool `toml:"sketches"`
 }
 
 // Default Cache configuration values.
@@ -95,6 +95,14 @@ type noSnapshotter struct{}
 func (noSnapshotter) AcquireSegments(_ context.Context, fn func([]string) error) error    { return fn(nil) }
 func (noSnapshotter) CommitSegments(_ context.Context, _ []string, fn func() error) error { return fn() }
 
+// WithBlockCache sets the block cache of the engine, e.g. to share a block
+// cache between engines. A nil cache disables the block cache.
+func WithBlockCache(cache *BlockCache) EngineOption {
+	return func(e *Engine) {
+		e.FileStore.blockCache = cache
+	}
+}
+
 // WithSnapshotter sets the callbacks for the engine to use when creating snapshots.
 func WithSnapshotter(snapshotter Snapshotter) EngineOption {
 	return func(e *Engine) {
@@ -174,7 +182,10 @@ type Engine struct {
 func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOption) *Engine {
 	fs := NewFileStore(path)
 	fs.openLimiter = limiter.NewFixed(config.MaxConcurrentOpens)
-	fs.tsmMMAPWillNeed = config.MADVWillNeed
+	fs.tsmMadviseStrategy = config.madviseStrategy()
+	if config.BlockCacheMaxSize > 0 {
+		fs.blockCache = NewBlockCache(uint64(config.BlockCacheMaxSize))
+	}
 
 	cache := NewCache(uint64(config.Cache.MaxMemorySize))
 
@@ -184,6 +195,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	c.RateLimit = limiter.NewRate(
 		int(config.Compaction.Throughput),
 		int(config.Compaction.ThroughputBurst))
+	c.Sketches = config.Compaction.Sketches
 
 	// determine max concurrent compactions informed by the system
 	maxCompactions := config.Compaction.MaxConcurrent
@@ -249,6 +261,12 @@ func (e *Engine) WithCompactionLimiter(limiter limiter.Fixed) {
 	e.compactionLimiter = limiter
 }
 
+// SetCompactionThroughput changes the rate limit applied to compactions
+// writing TSM files. A bytesPerSec of zero removes the limit.
+func (e *Engine) SetCompactionThroughput(bytesPerSec, burst int) error {
+	return limiter.UpdateRate(e.Compactor.RateLimit, bytesPerSec, burst)
+}
+
 func (e *Engine) WithFormatFileNameFunc(formatFileNameFunc FormatFileNameFunc) {
 	e.Compactor.WithFormatFileNameFunc(formatFileNameFunc)
 	e.formatFileName = formatFileNameFunc
@@ -501,19 +519,18 @@ func (e *Engine) MeasurementStats() (MeasurementStats, error) {
 }
 
 func (e *Engine) initTrackers() {
+	// Initialise metrics if an engine has not done so already.
+	InitMetrics(e.defaultMetricLabels)
+
 	mmu.Lock()
 	defer mmu.Unlock()
 
-	if bms == nil {
-		// Initialise metrics if an engine has not done so already.
-		bms = newBlockMetrics(e.defaultMetricLabels)
-	}
-
 	// Propagate prometheus metrics down into trackers.
 	e.compactionTracker = newCompactionTracker(bms.compactionMetrics, e.defaultMetricLabels)
 	e.FileStore.tracker = newFileTracker(bms.fileMetrics, e.defaultMetricLabels)
 	e.Cache.tracker = newCacheTracker(bms.cacheMetrics, e.defaultMetricLabels)
 	e.readTracker = newReadTracker(bms.readMetrics, e.defaultMetricLabels)
+	e.FileStore.readTracker = e.readTracker
 
 	e.scheduler.setCompactionTracker(e.compactionTracker)
 }
@@ -774,6 +791,14 @@ func (t *compactionTracker) Attempted(level compactionLevel, success bool, reaso
 	t.metrics.Compactions.With(labels).Inc()
 }
 
+// Compacted adds the sizes of the TSM files read and written by a successful
+// compaction of the provided level.
+func (t *compactionTracker) Compacted(level compactionLevel, read, written int64) {
+	labels := t.Labels(level)
+	t.metrics.ReadBytes.With(labels).Add(float64(read))
+	t.metrics.WrittenBytes.With(labels).Add(float64(written))
+}
+
 // SnapshotAttempted updates the number of snapshots attempted.
 func (t *compactionTracker) SnapshotAttempted(success bool, reason CacheStatus, duration time.Duration) {
 	t.Attempted(0, success, reason.String(), duration)
@@ -932,6 +957,7 @@ const (
 	CacheStatusColdNoWrites                      // The cache has not been written to for long enough that it should be snapshotted.
 	CacheStatusRetention                         // The cache was snapshotted before running retention.
 	CacheStatusFullCompaction                    // The cache was snapshotted as part of a full compaction.
+	CacheStatusShutdown                          // The cache was snapshotted before the engine was shut down.
 )
 
 // ShouldCompactCache returns a status indicating if the Cache should be
@@ -1255,6 +1281,9 @@ func (s *compactionStrategy) compactGroup(ctx context.Context) {
 		return
 	}
 
+	// The sizes must be read before the files are replaced.
+	read, written := filesSize(group), filesSize(files)
+
 	if err := s.fileStore.ReplaceWithCallback(group, files, nil); err != nil {
 		tracing.LogError(span, err)
 		log.Info("Error replacing new TSM files", zap.Error(err))
@@ -1277,6 +1306,19 @@ func (s *compactionStrategy) compactGroup(ctx context.Context) {
 	}
 	log.Info("Finished compacting files", zap.Int("tsm1_files_n", len(files)))
 	s.tracker.Attempted(s.level, true, "", time.Since(now))
+	s.tracker.Compacted(s.level, read, written)
+}
+
+// filesSize returns the total size of the files. Files that cannot be read
+// are ignored.
+func filesSize(paths []string) int64 {
+	var n int64
+	for _, path := range paths {
+		if fi, err := os.Stat(path); err == nil {
+			n += fi.Size()
+		}
+	}
+	return n
 }
 
 // levelCompactionStrategy returns a compactionStrategy for the given level.
@@ -1428,6 +1470,34 @@ func (e *Engine) seriesCost(seriesKey, field string, tmin, tmax int64) query.Ite
 	return c
 }
 
+// FieldType returns the type of the values stored for the series field key,
+// looking in the cache before the TSM files. It returns false if no values
+// are stored for the key.
+func (e *Engine) FieldType(key []byte) (models.FieldType, bool) {
+	if typ, err := e.Cache.Type(key); err == nil {
+		return typ, true
+	}
+
+	typ, err := e.FileStore.Type(key)
+	if err != nil {
+		return models.Empty, false
+	}
+
+	switch BlockTypeToInfluxQLDataType(typ) {
+	case influxql.Float:
+		return models.Float, true
+	case influxql.Integer:
+		return models.Integer, true
+	case influxql.Unsigned:
+		return models.Unsigned, true
+	case influxql.Boolean:
+		return models.Boolean, true
+	case influxql.String:
+		return models.String, true
+	}
+	return models.Empty, false
+}
+
 // SeriesFieldKey combine a series key and field name for a unique string to be hashed to a numeric ID.
 func SeriesFieldKey(seriesKey, field string) string {
 	return seriesKey + keyFieldSeparator + field
@@ -1481,12 +1551,23 @@ type readTracker struct {
 	labels  prometheus.Labels
 	cursors uint64
 	seeks   uint64
+
+	mmapBytes           uint64
+	blockCacheHits      uint64
+	blockCacheMisses    uint64
+	blockCacheEvictions uint64
+	blockCacheBytes     int64
 }
 
 func newReadTracker(metrics *readMetrics, defaultLabels prometheus.Labels) *readTracker {
 	t := &readTracker{metrics: metrics, labels: defaultLabels}
 	t.AddCursors(0)
 	t.AddSeeks(0)
+	t.AddMMapBytes(0)
+	t.AddBlockCacheHits(0)
+	t.AddBlockCacheMisses(0)
+	t.AddBlockCacheEvictions(0)
+	t.AddBlockCacheBytes(0)
 	return t
 }
 
@@ -1511,3 +1592,34 @@ func (t *readTracker) AddSeeks(n uint64) {
 	atomic.AddUint64(&t.seeks, n)
 	t.metrics.Seeks.With(t.labels).Add(float64(n))
 }
+
+// AddMMapBytes increases the number of bytes of blocks read from mmap'd files.
+func (t *readTracker) AddMMapBytes(n uint64) {
+	atomic.AddUint64(&t.mmapBytes, n)
+	t.metrics.MMapBytes.With(t.labels).Add(float64(n))
+}
+
+// AddBlockCacheHits increases the number of blocks read from the block cache.
+func (t *readTracker) AddBlockCacheHits(n uint64) {
+	atomic.AddUint64(&t.blockCacheHits, n)
+	t.metrics.BlockCacheHits.With(t.labels).Add(float64(n))
+}
+
+// AddBlockCacheMisses increases the number of blocks not found in the block cache.
+func (t *readTracker) AddBlockCacheMisses(n uint64) {
+	atomic.AddUint64(&t.blockCacheMisses, n)
+	t.metrics.BlockCacheMisses.With(t.labels).Add(float64(n))
+}
+
+// AddBlockCacheEvictions increases the number of blocks evicted from the block cache.
+func (t *readTracker) AddBlockCacheEvictions(n uint64) {
+	atomic.AddUint64(&t.blockCacheEvictions, n)
+	t.metrics.BlockCacheEvictions.With(t.labels).Add(float64(n))
+}
+
+// AddBlockCacheBytes changes the number of bytes of blocks in the block cache
+// by delta.
+func (t *readTracker) AddBlockCacheBytes(delta int64) {
+	atomic.AddInt64(&t.blockCacheBytes, delta)
+	t.metrics.BlockCacheSize.With(t.labels).Add(float64(delta))
+}
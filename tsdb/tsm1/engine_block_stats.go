@@ -0,0 +1,98 @@
+package tsm1
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// BlockStats are the stats of the TSM blocks of the series of a measurement.
+// Values in the cache are not included.
+type BlockStats struct {
+	// Series is the number of series with blocks.
+	Series int64
+	// Blocks is the number of blocks.
+	Blocks int64
+	// MinTime and MaxTime are the bounds of the blocks in nanoseconds.
+	MinTime, MaxTime int64
+}
+
+func (s *BlockStats) addBlock(entry IndexEntry) {
+	if s.Blocks == 0 || entry.MinTime < s.MinTime {
+		s.MinTime = entry.MinTime
+	}
+	if s.Blocks == 0 || entry.MaxTime > s.MaxTime {
+		s.MaxTime = entry.MaxTime
+	}
+	s.Blocks++
+}
+
+// errStopWalk stops walking the keys of the file store.
+var errStopWalk = errors.New("stop walk")
+
+// PrefixBlockStats returns the stats of the TSM blocks of the keys with the
+// prefix by measurement.
+func (e *Engine) PrefixBlockStats(prefix []byte) (map[string]*BlockStats, error) {
+	stats := make(map[string]*BlockStats)
+	var tags models.Tags
+	measurement := func(key []byte) *BlockStats {
+		seriesKey, _ := SeriesAndFieldFromCompositeKey(key)
+		_, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+		name := string(tags.Get(models.MeasurementTagKeyBytes))
+		s, ok := stats[name]
+		if !ok {
+			s = &BlockStats{}
+			stats[name] = s
+		}
+		return s
+	}
+
+	// Keys are counted once across files to count series.
+	err := e.FileStore.WalkKeys(prefix, func(key []byte, typ byte) error {
+		if !bytes.HasPrefix(key, prefix) {
+			return errStopWalk
+		}
+		measurement(key).Series++
+		return nil
+	})
+	if err != nil && err != errStopWalk {
+		return nil, err
+	}
+
+	err = e.FileStore.prefixBlocks(prefix, func(key []byte, entries []IndexEntry) {
+		s := measurement(key)
+		for _, entry := range entries {
+			s.addBlock(entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// prefixBlocks calls fn with the index entries of the keys with the prefix of
+// every file.
+func (f *FileStore) prefixBlocks(prefix []byte, fn func(key []byte, entries []IndexEntry)) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, file := range f.files {
+		if !file.OverlapsKeyPrefixRange(prefix, prefix) {
+			continue
+		}
+
+		iter := file.Iterator(prefix)
+		for iter.Next() {
+			if !bytes.HasPrefix(iter.Key(), prefix) {
+				break
+			}
+			fn(iter.Key(), iter.Entries())
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package tsm1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestEngine_PrefixBlockStats(t *testing.T) {
+	e, err := NewEngine(tsm1.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Two snapshots write the blocks of host=A to two files.
+	for _, points := range [][]string{
+		{"cpu,host=A value=1.1 10", "cpu,host=B value=1.2 20", "mem,host=A value=1.3 30"},
+		{"cpu,host=A value=1.4 40"},
+	} {
+		for _, p := range points {
+			if err := e.writePoints(MustParsePointString(p, "mm0")); err != nil {
+				t.Fatalf("failed to write points: %v", err)
+			}
+		}
+		if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+			t.Fatalf("failed to snapshot: %v", err)
+		}
+	}
+	if err := e.writePoints(MustParsePointString("cpu,host=C value=1.5 50", "mm1")); err != nil {
+		t.Fatalf("failed to write points: %v", err)
+	}
+
+	stats, err := e.PrefixBlockStats([]byte("mm0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected stats of 2 measurements, got %+v", stats)
+	}
+	if s := stats["cpu"]; s == nil || *s != (tsm1.BlockStats{Series: 2, Blocks: 3, MinTime: 10, MaxTime: 40}) {
+		t.Errorf("unexpected stats of cpu: %+v", s)
+	}
+	if s := stats["mem"]; s == nil || *s != (tsm1.BlockStats{Series: 1, Blocks: 1, MinTime: 30, MaxTime: 30}) {
+		t.Errorf("unexpected stats of mem: %+v", s)
+	}
+}
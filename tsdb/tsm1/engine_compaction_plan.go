@@ -0,0 +1,93 @@
+package tsm1
+
+import (
+	"time"
+)
+
+// CompactionPlanGroup is a group of TSM files that the compaction planner
+// would compact together.
+type CompactionPlanGroup struct {
+	// Level is the level of the compaction: 1 to 3 for level compactions and
+	// 4 for full and optimize compactions.
+	Level int
+	// Optimize is true if the files would be rewritten by an optimize
+	// compaction, which is only planned when no full compaction is.
+	Optimize bool
+	Files    []FileStat
+
+	// EstimatedSize is the estimated size of the files written by the
+	// compaction. Compactions merge the blocks of the files, so it is the
+	// size of the files, which overestimates it when the files hold
+	// overwritten or deleted values.
+	EstimatedSize uint64
+	// EstimatedFiles is the estimated number of files written by the
+	// compaction, which splits its output into files of at most 2GB.
+	EstimatedFiles int
+}
+
+// PlanCompactions returns the compactions that the default planner would
+// plan next for the TSM files of the file store, in the order of their
+// levels, without running them. lastWrite is the time of the last write
+// to the files, which decides whether a full compaction is due.
+func PlanCompactions(fs *FileStore, fullWriteColdDuration time.Duration, lastWrite time.Time) []CompactionPlanGroup {
+	return planCompactions(fs, NewDefaultPlanner(fs, fullWriteColdDuration), lastWrite)
+}
+
+// PlanCompactions returns the compactions that the engine would plan next
+// for its TSM files, without running them.
+//
+// The plan is made by a planner of its own, so it includes the files of the
+// compactions that are running.
+func (e *Engine) PlanCompactions() []CompactionPlanGroup {
+	var coldDuration time.Duration
+	var forceFull bool
+	if p, ok := e.CompactionPlan.(*DefaultPlanner); ok {
+		p.mu.RLock()
+		coldDuration, forceFull = p.compactFullWriteColdDuration, p.forceFull
+		p.mu.RUnlock()
+	}
+
+	planner := NewDefaultPlanner(e.FileStore, coldDuration)
+	if forceFull {
+		planner.ForceFull()
+	}
+	return planCompactions(e.FileStore, planner, e.lastModified())
+}
+
+// planCompactions plans the compactions of the files of the file store with
+// the planner the way the compaction loop of the engine does.
+func planCompactions(fs *FileStore, planner *DefaultPlanner, lastWrite time.Time) []CompactionPlanGroup {
+	stats := make(map[string]FileStat)
+	for _, s := range fs.Stats() {
+		stats[s.Path] = s
+	}
+
+	var plan []CompactionPlanGroup
+	add := func(level int, optimize bool, groups []CompactionGroup) {
+		for _, group := range groups {
+			g := CompactionPlanGroup{Level: level, Optimize: optimize}
+			for _, path := range group {
+				s := stats[path]
+				g.Files = append(g.Files, s)
+				g.EstimatedSize += uint64(s.Size)
+			}
+			g.EstimatedFiles = int((g.EstimatedSize + uint64(maxTSMFileSize) - 1) / uint64(maxTSMFileSize))
+			if g.EstimatedFiles == 0 {
+				g.EstimatedFiles = 1
+			}
+			plan = append(plan, g)
+		}
+	}
+
+	// The files of a group are acquired by the planner, so that like in the
+	// engine, they are not planned again by the plans of the next levels.
+	for level := 1; level <= 3; level++ {
+		add(level, false, planner.PlanLevel(level))
+	}
+	if full := planner.Plan(lastWrite); len(full) > 0 {
+		add(4, false, full)
+	} else {
+		add(4, true, planner.PlanOptimize())
+	}
+	return plan
+}
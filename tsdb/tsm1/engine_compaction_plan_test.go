@@ -0,0 +1,68 @@
+package tsm1_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestEngine_PlanCompactions(t *testing.T) {
+	e, err := NewEngine(tsm1.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	snapshot := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if err := e.writePoints(MustParsePointString(fmt.Sprintf("cpu,host=A value=%d %d", i, i), "mm0")); err != nil {
+				t.Fatalf("failed to write points: %v", err)
+			}
+			if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+				t.Fatalf("failed to snapshot: %v", err)
+			}
+		}
+	}
+
+	// Level 1 files are only compacted by generations of 8, and a full
+	// compaction is not due since the files were just written.
+	snapshot(3)
+	if plan := e.PlanCompactions(); len(plan) != 0 {
+		t.Fatalf("expected no compactions, got %+v", plan)
+	}
+
+	// A cold full compaction compacts all the files.
+	plan := tsm1.PlanCompactions(e.FileStore, time.Nanosecond, time.Now().Add(-time.Hour))
+	if len(plan) != 1 || plan[0].Level != 4 || plan[0].Optimize || len(plan[0].Files) != 3 {
+		t.Fatalf("expected a full compaction of 3 files, got %+v", plan)
+	}
+
+	snapshot(5)
+	plan = e.PlanCompactions()
+	if len(plan) != 1 {
+		t.Fatalf("expected a single compaction, got %+v", plan)
+	}
+	g := plan[0]
+	if g.Level != 1 || len(g.Files) != 8 || g.EstimatedFiles != 1 {
+		t.Fatalf("expected a level 1 compaction of 8 files into 1, got %+v", g)
+	}
+	var size uint64
+	for _, f := range g.Files {
+		size += uint64(f.Size)
+	}
+	if size == 0 || g.EstimatedSize != size {
+		t.Fatalf("expected the estimated size to be the size of the files %d, got %d", size, g.EstimatedSize)
+	}
+
+	// Planning does not acquire the files of the engine.
+	if plan := e.PlanCompactions(); len(plan) != 1 {
+		t.Fatalf("expected the compaction to be planned again, got %+v", plan)
+	}
+}
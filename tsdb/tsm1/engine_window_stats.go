@@ -0,0 +1,146 @@
+package tsm1
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// maxCompactionLevel is the highest compaction level of TSM files; files
+// compacted further than level 3 are all level 4.
+const maxCompactionLevel = 4
+
+// WindowStats are the stats of the data of a key prefix with timestamps in a
+// window of time. Blocks are attributed to the window of their first value.
+type WindowStats struct {
+	// Min and Max are the bounds of the window [Min, Max) in nanoseconds.
+	Min, Max int64
+
+	// DiskBytes is the size of the TSM blocks in the window.
+	DiskBytes int64
+	// Blocks is the number of TSM blocks in the window.
+	Blocks int
+	// Files is the number of TSM files holding blocks of the window.
+	Files int
+	// FilesByLevel is the number of TSM files holding blocks of the window
+	// by compaction level.
+	FilesByLevel map[int]int
+	// Generations is the number of generations of TSM files holding blocks of
+	// the window. The blocks of a fully compacted window are in one generation.
+	Generations int
+
+	// CacheBytes is the size of the values in the window that are held in
+	// the cache and the write ahead log but not yet written to TSM files.
+	CacheBytes int64
+}
+
+// windowStart returns the start of the window of size w containing t.
+func windowStart(t, w int64) int64 {
+	m := t % w
+	if m < 0 {
+		m += w
+	}
+	return t - m
+}
+
+// PrefixWindowStats returns the stats of the data of the keys with the
+// prefix in consecutive windows of time of the given size in nanoseconds,
+// ordered by time. Windows without data are omitted.
+func (e *Engine) PrefixWindowStats(prefix []byte, window int64) ([]WindowStats, error) {
+	stats, err := e.FileStore.prefixWindowStats(prefix, window)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPrefix := string(prefix)
+	err = e.Cache.ApplyEntryFn(func(key string, entry *entry) error {
+		if !strings.HasPrefix(key, keyPrefix) {
+			return nil
+		}
+		entry.mu.RLock()
+		defer entry.mu.RUnlock()
+		for _, v := range entry.values {
+			s := stats.window(windowStart(v.UnixNano(), window), window)
+			s.CacheBytes += int64(v.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.sorted(), nil
+}
+
+// windowStatsSet are window stats by the start of the window.
+type windowStatsSet map[int64]*WindowStats
+
+func (set windowStatsSet) window(min, size int64) *WindowStats {
+	s, ok := set[min]
+	if !ok {
+		s = &WindowStats{Min: min, Max: min + size, FilesByLevel: make(map[int]int)}
+		set[min] = s
+	}
+	return s
+}
+
+func (set windowStatsSet) sorted() []WindowStats {
+	a := make([]WindowStats, 0, len(set))
+	for _, s := range set {
+		a = append(a, *s)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Min < a[j].Min })
+	return a
+}
+
+// prefixWindowStats returns the stats of the TSM blocks of the keys with the prefix.
+func (f *FileStore) prefixWindowStats(prefix []byte, window int64) (windowStatsSet, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	stats := make(windowStatsSet)
+	generations := make(map[int64]map[int]struct{})
+	for _, file := range f.files {
+		if !file.OverlapsKeyPrefixRange(prefix, prefix) {
+			continue
+		}
+
+		level, gen := maxCompactionLevel, 0
+		if g, seq, err := f.parseFileName(file.Path()); err == nil {
+			gen = g
+			if seq < maxCompactionLevel {
+				level = seq
+			}
+		}
+
+		windows := make(map[int64]struct{})
+		iter := file.Iterator(prefix)
+		for iter.Next() {
+			if !bytes.HasPrefix(iter.Key(), prefix) {
+				break
+			}
+			for _, entry := range iter.Entries() {
+				min := windowStart(entry.MinTime, window)
+				s := stats.window(min, window)
+				s.DiskBytes += int64(entry.Size)
+				s.Blocks++
+				windows[min] = struct{}{}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+
+		for min := range windows {
+			s := stats[min]
+			s.Files++
+			s.FilesByLevel[level]++
+			if generations[min] == nil {
+				generations[min] = make(map[int]struct{})
+			}
+			generations[min][gen] = struct{}{}
+			s.Generations = len(generations[min])
+		}
+	}
+	return stats, nil
+}
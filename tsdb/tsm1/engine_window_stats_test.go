@@ -0,0 +1,55 @@
+package tsm1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestEngine_PrefixWindowStats(t *testing.T) {
+	e, err := NewEngine(tsm1.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=1.1 10", "mm0"),
+		MustParsePointString("cpu,host=A value=1.2 20", "mm0"),
+		MustParsePointString("cpu,host=B value=1.3 110", "mm0"),
+		MustParsePointString("cpu,host=B value=1.3 10", "mm1"),
+	); err != nil {
+		t.Fatalf("failed to write points: %v", err)
+	}
+	if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+		t.Fatalf("failed to snapshot: %v", err)
+	}
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=1.4 30", "mm0"),
+		MustParsePointString("cpu,host=A value=1.5 -10", "mm0"),
+	); err != nil {
+		t.Fatalf("failed to write points: %v", err)
+	}
+
+	stats, err := e.PrefixWindowStats([]byte("mm0"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 windows, got %+v", stats)
+	}
+
+	if s := stats[0]; s.Min != -100 || s.Max != 0 || s.DiskBytes != 0 || s.Files != 0 || s.CacheBytes == 0 {
+		t.Errorf("unexpected stats of cached window: %+v", s)
+	}
+	if s := stats[1]; s.Min != 0 || s.Max != 100 || s.Blocks != 1 || s.DiskBytes == 0 || s.Files != 1 || s.FilesByLevel[1] != 1 || s.Generations != 1 || s.CacheBytes == 0 {
+		t.Errorf("unexpected stats of first window: %+v", s)
+	}
+	if s := stats[2]; s.Min != 100 || s.Blocks != 1 || s.Files != 1 || s.CacheBytes != 0 {
+		t.Errorf("unexpected stats of second window: %+v", s)
+	}
+}
@@ -166,6 +166,10 @@ type TSMFile interface {
 
 	// Stats returns the statistics for the file.
 	MeasurementStats() (MeasurementStats, error)
+
+	// BlockSketches returns the sketches of the blocks of key, if the file
+	// has them.
+	BlockSketches(key []byte) ([]BlockSketch, error)
 }
 
 // FileStoreObserver is passed notifications before the file store adds or deletes files. In this way, it can
@@ -203,9 +207,12 @@ type FileStore struct {
 	currentGenerationFunc func() int // external generation
 	dir                   string
 
-	files           []TSMFile
-	tsmMMAPWillNeed bool          // If true then the kernel will be advised MMAP_WILLNEED for TSM files.
-	openLimiter     limiter.Fixed // limit the number of concurrent opening TSM files.
+	files              []TSMFile
+	tsmMadviseStrategy string        // The madvise strategy of the mmap'd TSM files.
+	openLimiter        limiter.Fixed // limit the number of concurrent opening TSM files.
+
+	blockCache  *BlockCache  // If set, the blocks read from TSM files are cached.
+	readTracker *readTracker // Accounts the blocks read from TSM files.
 
 	logger *zap.Logger // Logger to be used for important messages
 
@@ -259,6 +266,7 @@ func NewFileStore(dir string) *FileStore {
 		obs:           noFileStoreObserver{},
 		parseFileName: DefaultParseFileName,
 		tracker:       newFileTracker(newFileMetrics(nil), nil),
+		readTracker:   newReadTracker(newReadMetrics(nil), nil),
 	}
 	fs.purger.fileStore = fs
 	return fs
@@ -660,7 +668,8 @@ func (f *FileStore) Open(ctx context.Context) error {
 
 			start := time.Now()
 			df, err := NewTSMReader(file,
-				WithMadviseWillNeed(f.tsmMMAPWillNeed),
+				WithMadviseStrategy(f.tsmMadviseStrategy),
+				withBlockCache(f.blockCache, f.readTracker),
 				WithTSMReaderLogger(f.logger))
 			f.logger.Info("Opened file",
 				zap.String("path", file.Name()),
@@ -779,6 +788,87 @@ func (f *FileStore) Read(key []byte, t int64) ([]Value, error) {
 	return nil, nil
 }
 
+// BlockSketches returns the sketches of the blocks of key that lie within
+// [min, max] and overlap neither other blocks nor deleted ranges, so that
+// they summarize exactly the values of key in their time ranges. The time
+// ranges of all other blocks that overlap [min, max] are returned as dirty;
+// their values must be read instead.
+func (f *FileStore) BlockSketches(key []byte, min, max int64) (clean []BlockSketch, dirty []TimeRange) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	type block struct {
+		r       TSMFile
+		entry   IndexEntry
+		deleted bool
+	}
+
+	var (
+		blocks  []block
+		entries []IndexEntry
+		trbuf   []TimeRange
+		err     error
+	)
+	for _, fd := range f.files {
+		if !fd.OverlapsTimeRange(min, max) {
+			continue
+		}
+
+		entries, err = fd.ReadEntries(key, entries)
+		if err != nil {
+			continue
+		}
+		trbuf = fd.TombstoneRange(key, trbuf[:0])
+
+		for _, ie := range entries {
+			if !ie.OverlapsTimeRange(min, max) {
+				continue
+			}
+			b := block{r: fd, entry: ie}
+			for _, t := range trbuf {
+				if t.Overlaps(ie.MinTime, ie.MaxTime) {
+					b.deleted = true
+					break
+				}
+			}
+			blocks = append(blocks, b)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].entry.MinTime < blocks[j].entry.MinTime })
+
+	var prevMax int64
+	for i, b := range blocks {
+		ie := b.entry
+		overlaps := (i > 0 && prevMax >= ie.MinTime) || (i+1 < len(blocks) && blocks[i+1].entry.MinTime <= ie.MaxTime)
+		if i == 0 || ie.MaxTime > prevMax {
+			prevMax = ie.MaxTime
+		}
+
+		if !overlaps && !b.deleted && ie.MinTime >= min && ie.MaxTime <= max {
+			if sketch, ok := blockSketch(b.r, key, ie); ok {
+				clean = append(clean, sketch)
+				continue
+			}
+		}
+		dirty = append(dirty, TimeRange{Min: ie.MinTime, Max: ie.MaxTime})
+	}
+	return clean, dirty
+}
+
+// blockSketch returns the sketch of the block of key identified by ie. Files
+// whose sketches cannot be read are treated as if they had none.
+func blockSketch(r TSMFile, key []byte, ie IndexEntry) (BlockSketch, bool) {
+	sketches, err := r.BlockSketches(key)
+	if err != nil {
+		return BlockSketch{}, false
+	}
+	i := sort.Search(len(sketches), func(i int) bool { return sketches[i].MinTime >= ie.MinTime })
+	if i < len(sketches) && sketches[i].MinTime == ie.MinTime && sketches[i].MaxTime == ie.MaxTime {
+		return sketches[i], true
+	}
+	return BlockSketch{}, false
+}
+
 func (f *FileStore) Cost(key []byte, min, max int64) query.IteratorCost {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
@@ -871,11 +961,12 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 			return err
 		}
 
-		// Observe the associated statistics file, if available.
-		statsFile := StatsFilename(file)
-		if _, err := os.Stat(statsFile); err == nil {
-			if err := f.obs.FileFinishing(statsFile); err != nil {
-				return err
+		// Observe the associated statistics and sketch files, if available.
+		for _, sidecar := range []string{StatsFilename(file), SketchFilename(file)} {
+			if _, err := os.Stat(sidecar); err == nil {
+				if err := f.obs.FileFinishing(sidecar); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -901,7 +992,8 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 		}
 
 		tsm, err := NewTSMReader(fd,
-			WithMadviseWillNeed(f.tsmMMAPWillNeed),
+			WithMadviseStrategy(f.tsmMadviseStrategy),
+			withBlockCache(f.blockCache, f.readTracker),
 			WithTSMReaderLogger(f.logger))
 		if err != nil {
 			return err
@@ -938,11 +1030,12 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 					return err
 				}
 
-				// Remove associated stats file.
-				statsFile := StatsFilename(file.Path())
-				if _, err := os.Stat(statsFile); err == nil {
-					if err := f.obs.FileUnlinking(statsFile); err != nil {
-						return err
+				// Remove associated stats and sketch files.
+				for _, sidecar := range []string{StatsFilename(file.Path()), SketchFilename(file.Path())} {
+					if _, err := os.Stat(sidecar); err == nil {
+						if err := f.obs.FileUnlinking(sidecar); err != nil {
+							return err
+						}
 					}
 				}
 
@@ -0,0 +1,144 @@
+package tsm1
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// Ensure type implements interface.
+var _ BlockExporter = (*LineProtocolExporter)(nil)
+
+// LineProtocolExporter writes the points of TSM files as line protocol.
+//
+// Points are written in the order of the files and keys, and deleted points
+// are excluded. A point overwritten in a later file is written once for
+// every file it appears in, so the last line of a point holds its value.
+type LineProtocolExporter struct {
+	w *bufio.Writer
+
+	// OrgID and BucketID restrict the export to the points of an
+	// organization or bucket when set.
+	OrgID, BucketID *influxdb.ID
+
+	// Measurements restricts the export to the points of the measurements
+	// when not empty.
+	Measurements map[string]bool
+
+	// MinTime and MaxTime restrict the export to points within the time
+	// range, inclusive.
+	MinTime, MaxTime int64
+}
+
+// NewLineProtocolExporter returns a new instance of LineProtocolExporter
+// exporting all points.
+func NewLineProtocolExporter(w io.Writer) *LineProtocolExporter {
+	return &LineProtocolExporter{
+		w:       bufio.NewWriter(w),
+		MinTime: math.MinInt64,
+		MaxTime: math.MaxInt64,
+	}
+}
+
+// Close flushes the exported points.
+func (e *LineProtocolExporter) Close() error {
+	return e.w.Flush()
+}
+
+// ExportFile writes the points of the TSM file.
+func (e *LineProtocolExporter) ExportFile(filename string) error {
+	f, err := os.OpenFile(filename, os.O_RDONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := NewTSMReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if !r.OverlapsTimeRange(e.MinTime, e.MaxTime) {
+		return nil
+	}
+
+	itr := r.Iterator(nil)
+	if itr == nil {
+		return errors.New("invalid TSM file, no index iterator")
+	}
+
+	var (
+		tags       models.Tags
+		tombstones []TimeRange
+		values     []Value
+		buf        []byte
+	)
+	for itr.Next() {
+		key := itr.Key()
+		if len(key) < 16 {
+			continue
+		}
+		orgID, bucketID := tsdb.DecodeNameSlice(key[:16])
+		if e.OrgID != nil && *e.OrgID != orgID || e.BucketID != nil && *e.BucketID != bucketID {
+			continue
+		}
+
+		seriesKey, field := SeriesAndFieldFromCompositeKey(key)
+		_, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+		measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+		if len(e.Measurements) > 0 && !e.Measurements[measurement] {
+			continue
+		}
+
+		// The measurement and field are stored as tags of the series and
+		// are written as the measurement and field of the points instead.
+		pointTags := make(models.Tags, 0, len(tags))
+		for _, t := range tags {
+			if string(t.Key) != models.MeasurementTagKey && string(t.Key) != models.FieldKeyTagKey {
+				pointTags = append(pointTags, t)
+			}
+		}
+
+		tombstones = r.TombstoneRange(key, tombstones[:0])
+		entries := itr.Entries()
+		for i := range entries {
+			if !entries[i].OverlapsTimeRange(e.MinTime, e.MaxTime) {
+				continue
+			}
+			if values, err = r.ReadAt(&entries[i], values[:0]); err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+			for _, t := range tombstones {
+				values = Values(values).Exclude(t.Min, t.Max)
+			}
+
+			for _, v := range values {
+				ts := v.UnixNano()
+				if ts < e.MinTime || ts > e.MaxTime {
+					continue
+				}
+				pt, err := models.NewPoint(measurement, pointTags, models.Fields{string(field): v.Value()}, time.Unix(0, ts))
+				if err != nil {
+					return fmt.Errorf("%s: invalid point for key %q: %v", filename, key, err)
+				}
+				buf = append(pt.AppendString(buf[:0]), '\n')
+				if _, err := e.w.Write(buf); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return r.Close()
+}
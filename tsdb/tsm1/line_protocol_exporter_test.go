@@ -0,0 +1,97 @@
+package tsm1
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestLineProtocolExporter_Export(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	f := mustTempFile(dir)
+
+	org, bucket, other := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	key := func(b influxdb.ID, m, field string, kv ...string) []byte {
+		name := tsdb.EncodeName(org, b)
+		tags := models.NewTags(map[string]string{models.MeasurementTagKey: m, models.FieldKeyTagKey: field})
+		for i := 0; i < len(kv); i += 2 {
+			tags.Set([]byte(kv[i]), []byte(kv[i+1]))
+		}
+		return SeriesFieldKeyBytes(string(models.MakeKey(name[:], tags)), field)
+	}
+
+	// Keys must be written in order.
+	cpu := key(bucket, "cpu", "usage", "host", "a")
+	mem := key(bucket, "mem", "free")
+	disk := key(other, "disk", "used")
+	keys := [][]byte{cpu, mem, disk}
+	values := map[string][]Value{
+		string(cpu):  {NewValue(10, 1.5), NewValue(20, 2.5), NewValue(30, 3.5)},
+		string(mem):  {NewValue(10, int64(7))},
+		string(disk): {NewValue(10, "x")},
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if err := w.Write(k, values[string(k)]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Delete a point of cpu.
+	if f, err := os.Open(f.Name()); err != nil {
+		t.Fatal(err)
+	} else if r, err := NewTSMReader(f); err != nil {
+		t.Fatal(err)
+	} else if err := r.DeleteRange([][]byte{cpu}, 20, 20); err != nil {
+		t.Fatal(err)
+	} else if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	export := func(fn func(e *LineProtocolExporter)) string {
+		var buf bytes.Buffer
+		e := NewLineProtocolExporter(&buf)
+		fn(e)
+		if err := e.ExportFile(f.Name()); err != nil {
+			t.Fatal(err)
+		} else if err := e.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	t.Run("bucket", func(t *testing.T) {
+		got := export(func(e *LineProtocolExporter) { e.BucketID = &bucket })
+		want := "cpu,host=a usage=1.5 10\ncpu,host=a usage=3.5 30\nmem free=7i 10\n"
+		if got != want {
+			t.Fatalf("unexpected output:\ngot=%s\nwant=%s", got, want)
+		}
+	})
+
+	t.Run("measurement and time range", func(t *testing.T) {
+		got := export(func(e *LineProtocolExporter) {
+			e.Measurements = map[string]bool{"cpu": true, "disk": true}
+			e.MinTime, e.MaxTime = 15, 40
+		})
+		want := "cpu,host=a usage=3.5 30\n"
+		if got != want {
+			t.Fatalf("unexpected output:\ngot=%s\nwant=%s", got, want)
+		}
+	})
+}
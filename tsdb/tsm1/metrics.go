@@ -30,6 +30,18 @@ func PrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
+// InitMetrics initialises the metrics shared by all engines with the labels,
+// unless an engine has done so already. Engines initialise the metrics when
+// they are opened; InitMetrics lets their collectors be registered before any
+// engine is opened.
+func InitMetrics(labels prometheus.Labels) {
+	mmu.Lock()
+	defer mmu.Unlock()
+	if bms == nil {
+		bms = newBlockMetrics(labels)
+	}
+}
+
 // namespace is the leading part of all published metrics for the Storage service.
 const namespace = "storage"
 
@@ -73,6 +85,8 @@ type compactionMetrics struct {
 	CompactionsActive  *prometheus.GaugeVec
 	CompactionDuration *prometheus.HistogramVec
 	CompactionQueue    *prometheus.GaugeVec
+	ReadBytes          *prometheus.CounterVec
+	WrittenBytes       *prometheus.CounterVec
 
 	// The following metrics include a ``"status" = {ok, error}` label
 	Compactions *prometheus.CounterVec
@@ -116,6 +130,18 @@ func newCompactionMetrics(labels prometheus.Labels) *compactionMetrics {
 			Name:      "queued",
 			Help:      "Number of queued compactions.",
 		}, names),
+		ReadBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: compactionSubsystem,
+			Name:      "read_bytes",
+			Help:      "Number of bytes of TSM files read by successful compactions.",
+		}, names),
+		WrittenBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: compactionSubsystem,
+			Name:      "written_bytes",
+			Help:      "Number of bytes of TSM files written by successful compactions.",
+		}, names),
 	}
 }
 
@@ -126,6 +152,8 @@ func (m *compactionMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.CompactionsActive,
 		m.CompactionDuration,
 		m.CompactionQueue,
+		m.ReadBytes,
+		m.WrittenBytes,
 	}
 }
 
@@ -255,6 +283,12 @@ func (m *cacheMetrics) PrometheusCollectors() []prometheus.Collector {
 type readMetrics struct {
 	Cursors *prometheus.CounterVec
 	Seeks   *prometheus.CounterVec
+
+	MMapBytes           *prometheus.CounterVec
+	BlockCacheHits      *prometheus.CounterVec
+	BlockCacheMisses    *prometheus.CounterVec
+	BlockCacheEvictions *prometheus.CounterVec
+	BlockCacheSize      *prometheus.GaugeVec
 }
 
 // newReadMetrics initialises the prometheus metrics for tracking reads.
@@ -278,6 +312,36 @@ func newReadMetrics(labels prometheus.Labels) *readMetrics {
 			Name:      "seeks",
 			Help:      "Number of tsm locations seeked.",
 		}, names),
+		MMapBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: readSubsystem,
+			Name:      "mmap_bytes",
+			Help:      "Number of bytes of blocks read from mmap'd TSM files, i.e. from the page cache or the disk.",
+		}, names),
+		BlockCacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: readSubsystem,
+			Name:      "block_cache_hits",
+			Help:      "Number of blocks read from the block cache.",
+		}, names),
+		BlockCacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: readSubsystem,
+			Name:      "block_cache_misses",
+			Help:      "Number of blocks not found in the block cache and read from mmap'd TSM files.",
+		}, names),
+		BlockCacheEvictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: readSubsystem,
+			Name:      "block_cache_evictions",
+			Help:      "Number of blocks evicted from the block cache to make room for other blocks.",
+		}, names),
+		BlockCacheSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: readSubsystem,
+			Name:      "block_cache_inuse_bytes",
+			Help:      "Number of bytes of blocks in the block cache.",
+		}, names),
 	}
 }
 
@@ -286,5 +350,10 @@ func (m *readMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		m.Cursors,
 		m.Seeks,
+		m.MMapBytes,
+		m.BlockCacheHits,
+		m.BlockCacheMisses,
+		m.BlockCacheEvictions,
+		m.BlockCacheSize,
 	}
 }
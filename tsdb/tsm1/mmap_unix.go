@@ -33,6 +33,20 @@ func madviseWillNeed(b []byte) error {
 	return madvise(b, syscall.MADV_WILLNEED)
 }
 
+// madviseStrategy gives the kernel the mmap madvise value of the strategy.
+func madviseStrategy(b []byte, strategy string) error {
+	switch strategy {
+	case MADVStrategyWillNeed:
+		return madviseWillNeed(b)
+	case MADVStrategyRandom:
+		return madvise(b, syscall.MADV_RANDOM)
+	case MADVStrategySequential:
+		return madvise(b, syscall.MADV_SEQUENTIAL)
+	default:
+		return nil
+	}
+}
+
 func madviseDontNeed(b []byte) error {
 	return madvise(b, syscall.MADV_DONTNEED)
 }
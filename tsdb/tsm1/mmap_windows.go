@@ -124,6 +124,9 @@ func munmap(b []byte) (err error) {
 // madviseWillNeed is unsupported on Windows.
 func madviseWillNeed(b []byte) error { return nil }
 
+// madviseStrategy is unsupported on Windows.
+func madviseStrategy(b []byte, strategy string) error { return nil }
+
 // madviseDontNeed is unsupported on Windows.
 func madviseDontNeed(b []byte) error { return nil }
 
@@ -139,9 +139,15 @@ func (p *predicateMatcher) Matches(key []byte) bool {
 		}
 	}
 
-	// If it always needed more then it didn't match. For example, consider if
-	// the predicate matches `tag1=val1` but tag1 is not present in the key.
-	return false
+	// Tags that are not present in the key compare as empty values, as they
+	// do in queries. For example, `tag1=val1` does not match a key without
+	// tag1 while `tag1!=val1` does.
+	for i, value := range p.state.values {
+		if value == nil {
+			p.state.values[i] = []byte{}
+		}
+	}
+	return p.root.Update() == predicateResponse_true
 }
 
 // Marshal returns a buffer representing the protobuf predicate.
@@ -151,6 +151,22 @@ func TestPredicate_Matches(t *testing.T) {
 			Matches: false,
 		},
 
+		{
+			Name: "No Tag Not Equal",
+			Predicate: predicate(
+				comparisonNode(datatypes.ComparisonNotEqual, tagNode("tag4"), stringNode("val4"))),
+			Key:     "bucketorg,tag3=val3",
+			Matches: true,
+		},
+
+		{
+			Name: "No Tag Not Regex",
+			Predicate: predicate(
+				comparisonNode(datatypes.ComparisonNotRegex, tagNode("tag4"), regexNode("^val"))),
+			Key:     "bucketorg,tag3=val3",
+			Matches: true,
+		},
+
 		{
 			Name: "Not Equal",
 			Predicate: predicate(
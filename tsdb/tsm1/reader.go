@@ -20,9 +20,14 @@ type TSMReader struct {
 	refsWG sync.WaitGroup
 
 	logger          *zap.Logger
-	madviseWillNeed bool // Hint to the kernel with MADV_WILLNEED.
+	madviseStrategy string // The advice given to the kernel about accesses to the file.
 	mu              sync.RWMutex
 
+	// blockCache caches the blocks read from the file if set, and
+	// readTracker accounts them.
+	blockCache  *BlockCache
+	readTracker *readTracker
+
 	// accessor provides access and decoding of blocks for the reader.
 	accessor blockAccessor
 
@@ -40,6 +45,12 @@ type TSMReader struct {
 
 	// deleteMu limits concurrent deletes
 	deleteMu sync.Mutex
+
+	// sketches reads the block sketches of the file on demand. It is opened
+	// on first use, and closed along with the file.
+	sketchesMu  sync.Mutex
+	sketches    *SketchReader
+	sketchesErr error
 }
 
 type tsmReaderOption func(*TSMReader)
@@ -47,7 +58,26 @@ type tsmReaderOption func(*TSMReader)
 // WithMadviseWillNeed is an option for specifying whether to provide a MADV_WILL need hint to the kernel.
 var WithMadviseWillNeed = func(willNeed bool) tsmReaderOption {
 	return func(r *TSMReader) {
-		r.madviseWillNeed = willNeed
+		if willNeed {
+			r.madviseStrategy = MADVStrategyWillNeed
+		}
+	}
+}
+
+// WithMadviseStrategy is an option for specifying the madvise strategy of the file.
+var WithMadviseStrategy = func(strategy string) tsmReaderOption {
+	return func(r *TSMReader) {
+		r.madviseStrategy = strategy
+	}
+}
+
+// withBlockCache is an option for caching the blocks read from the file in
+// the block cache, and accounting them with the tracker. A nil cache disables
+// the caching.
+func withBlockCache(cache *BlockCache, tracker *readTracker) tsmReaderOption {
+	return func(r *TSMReader) {
+		r.blockCache = cache
+		r.readTracker = tracker
 	}
 }
 
@@ -60,7 +90,8 @@ var WithTSMReaderLogger = func(logger *zap.Logger) tsmReaderOption {
 // NewTSMReader returns a new TSMReader from the given file.
 func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	t := &TSMReader{
-		logger: zap.NewNop(),
+		logger:      zap.NewNop(),
+		readTracker: newReadTracker(newReadMetrics(nil), nil),
 	}
 	for _, option := range options {
 		option(t)
@@ -73,9 +104,11 @@ func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	t.size = stat.Size()
 	t.lastModified = stat.ModTime().UnixNano()
 	t.accessor = &mmapAccessor{
-		logger:       t.logger,
-		f:            f,
-		mmapWillNeed: t.madviseWillNeed,
+		logger:          t.logger,
+		f:               f,
+		madviseStrategy: t.madviseStrategy,
+		cache:           t.blockCache,
+		tracker:         t.readTracker,
 	}
 
 	index, err := t.accessor.init()
@@ -217,6 +250,37 @@ func (t *TSMReader) MeasurementStats() (MeasurementStats, error) {
 	return stats, err
 }
 
+// BlockSketches returns the sketches of the blocks of key, in the order of
+// the blocks in the file. Blocks that were written without sketches, such as
+// the blocks of snapshots, have none.
+func (t *TSMReader) BlockSketches(key []byte) ([]BlockSketch, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	t.sketchesMu.Lock()
+	if t.sketches == nil && t.sketchesErr == nil {
+		t.sketches, t.sketchesErr = OpenSketchReader(SketchFilename(t.accessor.path()))
+	}
+	r, err := t.sketches, t.sketchesErr
+	t.sketchesMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return r.BlockSketches(key)
+}
+
+// closeSketches closes the sketch file, so that it is opened again on next
+// use. It must be called with the write lock held.
+func (t *TSMReader) closeSketches() error {
+	r := t.sketches
+	t.sketches, t.sketchesErr = nil, nil
+	if r == nil {
+		return nil
+	}
+	return r.Close()
+}
+
 // Close closes the TSMReader.
 func (t *TSMReader) Close() error {
 	t.refsWG.Wait()
@@ -224,7 +288,9 @@ func (t *TSMReader) Close() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if err := t.accessor.close(); err != nil {
+	if err := t.closeSketches(); err != nil {
+		return err
+	} else if err := t.accessor.close(); err != nil {
 		return err
 	}
 
@@ -264,6 +330,9 @@ func (t *TSMReader) Remove() error {
 func (t *TSMReader) Rename(path string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	if err := t.closeSketches(); err != nil {
+		return err
+	}
 	return t.accessor.rename(path)
 }
 
@@ -274,12 +343,17 @@ func (t *TSMReader) remove() error {
 	if t.InUse() {
 		return ErrFileInUse
 	}
+	if err := t.closeSketches(); err != nil {
+		return err
+	}
 
 	if path != "" {
 		if err := os.RemoveAll(path); err != nil {
 			return err
 		} else if err := os.RemoveAll(StatsFilename(path)); err != nil && !os.IsNotExist(err) {
 			return err
+		} else if err := os.RemoveAll(SketchFilename(path)); err != nil && !os.IsNotExist(err) {
+			return err
 		}
 	}
 
@@ -17,8 +17,11 @@ type mmapAccessor struct {
 	accessCount uint64 // Counter incremented everytime the mmapAccessor is accessed
 	freeCount   uint64 // Counter to determine whether the accessor can free its resources
 
-	logger       *zap.Logger
-	mmapWillNeed bool // If true then mmap advise value MADV_WILLNEED will be provided the kernel for b.
+	logger          *zap.Logger
+	madviseStrategy string // The mmap advise value provided to the kernel for b.
+
+	cache   *BlockCache  // If set, the blocks read are cached.
+	tracker *readTracker // Accounts the blocks read.
 
 	mu    sync.RWMutex
 	b     []byte
@@ -58,13 +61,11 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 		return nil, fmt.Errorf("mmapAccessor: byte slice too small for indirectIndex")
 	}
 
-	// Hint to the kernel that we will be reading the file.  It would be better to hint
-	// that we will be reading the index section, but that's not been
-	// implemented as yet.
-	if m.mmapWillNeed {
-		if err := madviseWillNeed(m.b); err != nil {
-			return nil, err
-		}
+	// Hint to the kernel how we will be reading the file.  With willneed, it
+	// would be better to hint that we will be reading the index section, but
+	// that's not been implemented as yet.
+	if err := madviseStrategy(m.b, m.madviseStrategy); err != nil {
+		return nil, err
 	}
 
 	indexOfsPos := len(m.b) - 8
@@ -145,12 +146,12 @@ func (m *mmapAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, er
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if int64(len(m.b)) < entry.Offset+int64(entry.Size) {
-		return nil, ErrTSMClosed
+	b, err := m.block(entry)
+	if err != nil {
+		return nil, err
 	}
 	//TODO: Validate checksum
-	var err error
-	values, err = DecodeBlock(m.b[entry.Offset+4:entry.Offset+int64(entry.Size)], values)
+	values, err = DecodeBlock(b[4:], values)
 	if err != nil {
 		return nil, err
 	}
@@ -162,13 +163,14 @@ func (m *mmapAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, e
 	m.incAccess()
 
 	m.mu.RLock()
-	if int64(len(m.b)) < entry.Offset+int64(entry.Size) {
+	b, err := m.block(entry)
+	if err != nil {
 		m.mu.RUnlock()
-		return 0, nil, ErrTSMClosed
+		return 0, nil, err
 	}
 
 	// return the bytes after the 4 byte checksum
-	crc, block := binary.BigEndian.Uint32(m.b[entry.Offset:entry.Offset+4]), m.b[entry.Offset+4:entry.Offset+int64(entry.Size)]
+	crc, block := binary.BigEndian.Uint32(b[:4]), b[4:]
 	m.mu.RUnlock()
 
 	return crc, block, nil
@@ -203,10 +205,14 @@ func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 		if skip {
 			continue
 		}
+		b, err := m.block(&block)
+		if err != nil {
+			return nil, err
+		}
 		//TODO: Validate checksum
 		temp = temp[:0]
-		// The +4 is the 4 byte checksum length
-		temp, err = DecodeBlock(m.b[block.Offset+4:block.Offset+int64(block.Size)], temp)
+		// The 4 byte checksum is skipped
+		temp, err = DecodeBlock(b[4:], temp)
 		if err != nil {
 			return nil, err
 		}
@@ -222,6 +228,33 @@ func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 	return values, nil
 }
 
+// block returns the block of the entry with its checksum, from the block
+// cache if the block cache is enabled. The read lock must be held.
+func (m *mmapAccessor) block(entry *IndexEntry) ([]byte, error) {
+	end := entry.Offset + int64(entry.Size)
+	if int64(len(m.b)) < end {
+		return nil, ErrTSMClosed
+	}
+
+	if m.cache == nil {
+		m.tracker.AddMMapBytes(uint64(entry.Size))
+		return m.b[entry.Offset:end], nil
+	}
+
+	if b := m.cache.get(m, entry.Offset); b != nil {
+		m.tracker.AddBlockCacheHits(1)
+		return b, nil
+	}
+	m.tracker.AddBlockCacheMisses(1)
+	m.tracker.AddMMapBytes(uint64(entry.Size))
+
+	// Copy the block so that it outlives the mapping of the file.
+	b := make([]byte, entry.Size)
+	copy(b, m.b[entry.Offset:end])
+	m.cache.put(m, entry.Offset, b, m.tracker)
+	return b, nil
+}
+
 func (m *mmapAccessor) path() string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -236,6 +269,10 @@ func (m *mmapAccessor) close() error {
 		return nil
 	}
 
+	if m.cache != nil {
+		m.cache.removeFile(m)
+	}
+
 	err := munmap(m.b)
 	if err != nil {
 		return err
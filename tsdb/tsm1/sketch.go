@@ -0,0 +1,473 @@
+package tsm1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/pkg/estimator/tdigest"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	// SketchMagicNumber is written as the first 4 bytes of a sketch file to
+	// identify the file as a tsm1 sketch file.
+	SketchMagicNumber string = "TSK1"
+
+	// SketchVersion indicates the version of the TSK1 file format.
+	SketchVersion byte = 2
+
+	// sketchFooterSize is the size of the footer of a sketch file, which holds
+	// the offset of the index and its checksum.
+	sketchFooterSize = 8 + crc32.Size
+
+	// blockSketchCompression is the compression of the t-digests of single
+	// blocks. Blocks hold at most 1000 values, so a lower compression than
+	// the default keeps sketch files small without losing much accuracy.
+	blockSketchCompression = 50
+)
+
+// BlockSketch holds the sketch of the values of a single TSM block.
+type BlockSketch struct {
+	MinTime, MaxTime int64
+
+	digest   []byte // nil for string and boolean blocks
+	distinct []byte
+}
+
+// Sketch decodes the sketch of the block.
+func (b *BlockSketch) Sketch() (*cursors.Sketch, error) {
+	s := &cursors.Sketch{Distinct: new(hll.Plus)}
+	if err := s.Distinct.UnmarshalBinary(b.distinct); err != nil {
+		return nil, err
+	}
+	if b.digest != nil {
+		s.Digest = new(tdigest.TDigest)
+		if err := s.Digest.UnmarshalBinary(b.digest); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// SketchReader reads the block sketches of the keys of a TSM file from its
+// sketch file on demand. Only the index of the keys is held in memory.
+type SketchReader struct {
+	f *os.File
+
+	// index holds the entries of the index, which are sorted by key, and
+	// offsets the position of every entry in index.
+	index   []byte
+	offsets []int
+}
+
+// OpenSketchReader opens the sketch file at path and reads its index. The
+// reader has no sketches if the file does not exist.
+func OpenSketchReader(path string) (*SketchReader, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &SketchReader{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	r := &SketchReader{f: f}
+	if err := r.readIndex(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("tsm1.OpenSketchReader: %v", err)
+	}
+	return r, nil
+}
+
+// readIndex verifies the header and footer of the file and reads its index.
+func (r *SketchReader) readIndex() error {
+	fi, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr := len(SketchMagicNumber) + 1
+	if fi.Size() < int64(hdr+sketchFooterSize) {
+		return errors.New("sketch file too short")
+	}
+	var b [sketchFooterSize]byte
+	if _, err := r.f.ReadAt(b[:hdr], 0); err != nil {
+		return err
+	} else if string(b[:len(SketchMagicNumber)]) != SketchMagicNumber {
+		return errors.New("invalid tsm1 sketch file")
+	} else if v := b[len(SketchMagicNumber)]; v != SketchVersion {
+		return fmt.Errorf("incompatible tsm1 sketch version: %d", v)
+	}
+
+	footer := fi.Size() - sketchFooterSize
+	if _, err := r.f.ReadAt(b[:], footer); err != nil {
+		return err
+	}
+	indexOffset := int64(binary.BigEndian.Uint64(b[:8]))
+	if indexOffset < int64(hdr) || indexOffset > footer {
+		return errors.New("invalid index offset")
+	}
+	r.index = make([]byte, footer-indexOffset)
+	if _, err := r.f.ReadAt(r.index, indexOffset); err != nil {
+		return err
+	} else if crc32.ChecksumIEEE(r.index) != binary.BigEndian.Uint32(b[8:]) {
+		return errors.New("index checksum mismatch")
+	}
+
+	for buf := r.index; len(buf) > 0; {
+		r.offsets = append(r.offsets, len(r.index)-len(buf))
+		e, err := readSketchIndexEntry(&buf)
+		if err != nil {
+			return err
+		} else if e.offset < int64(hdr) || e.size > indexOffset-e.offset {
+			return fmt.Errorf("invalid location of the sketches of key %q", e.key)
+		}
+	}
+	return nil
+}
+
+// BlockSketches reads the sketches of the blocks of key, in the order of the
+// blocks in the TSM file. It returns no sketches if the key has none.
+func (r *SketchReader) BlockSketches(key []byte) ([]BlockSketch, error) {
+	i := sort.Search(len(r.offsets), func(i int) bool {
+		buf := r.index[r.offsets[i]:]
+		k, _ := readSketchBytes(&buf)
+		return bytes.Compare(k, key) >= 0
+	})
+	if i == len(r.offsets) {
+		return nil, nil
+	}
+	buf := r.index[r.offsets[i]:]
+	e, err := readSketchIndexEntry(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("tsm1.SketchReader: %v", err)
+	} else if !bytes.Equal(e.key, key) {
+		return nil, nil
+	}
+
+	data := make([]byte, e.size)
+	if _, err := r.f.ReadAt(data, e.offset); err != nil {
+		return nil, err
+	} else if crc32.ChecksumIEEE(data) != e.checksum {
+		return nil, errors.New("tsm1.SketchReader: checksum mismatch")
+	}
+
+	blockN, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errors.New("tsm1.SketchReader: cannot read block count")
+	}
+	data = data[n:]
+	blocks := make([]BlockSketch, 0, blockN)
+	for j := uint64(0); j < blockN; j++ {
+		b, err := readBlockSketch(&data)
+		if err != nil {
+			return nil, fmt.Errorf("tsm1.SketchReader: %v", err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// Close closes the sketch file.
+func (r *SketchReader) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Close()
+}
+
+// sketchIndexEntry locates the block sketches of a key in the sketch file.
+type sketchIndexEntry struct {
+	key          []byte
+	offset, size int64
+	checksum     uint32
+}
+
+func readSketchIndexEntry(buf *[]byte) (sketchIndexEntry, error) {
+	var e sketchIndexEntry
+	var err error
+	if e.key, err = readSketchBytes(buf); err != nil {
+		return e, err
+	}
+	offset, n := binary.Uvarint(*buf)
+	if n <= 0 {
+		return e, errors.New("cannot read offset")
+	}
+	*buf = (*buf)[n:]
+	size, n := binary.Uvarint(*buf)
+	if n <= 0 {
+		return e, errors.New("cannot read size")
+	}
+	*buf = (*buf)[n:]
+	if len(*buf) < crc32.Size {
+		return e, errors.New("cannot read checksum")
+	}
+	e.checksum = binary.BigEndian.Uint32(*buf)
+	*buf = (*buf)[crc32.Size:]
+	e.offset, e.size = int64(offset), int64(size)
+	return e, nil
+}
+
+func readBlockSketch(buf *[]byte) (BlockSketch, error) {
+	var b BlockSketch
+	var n int
+	if b.MinTime, n = binary.Varint(*buf); n <= 0 {
+		return b, errors.New("cannot read block min time")
+	}
+	*buf = (*buf)[n:]
+	if b.MaxTime, n = binary.Varint(*buf); n <= 0 {
+		return b, errors.New("cannot read block max time")
+	}
+	*buf = (*buf)[n:]
+
+	var err error
+	if b.digest, err = readSketchBytes(buf); err != nil {
+		return b, err
+	} else if len(b.digest) == 0 {
+		b.digest = nil
+	}
+	if b.distinct, err = readSketchBytes(buf); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+func readSketchBytes(buf *[]byte) ([]byte, error) {
+	sz, n := binary.Uvarint(*buf)
+	if n <= 0 || sz > uint64(len(*buf)-n) {
+		return nil, errors.New("invalid length")
+	}
+	b := (*buf)[n : n+int(sz)]
+	*buf = (*buf)[n+int(sz):]
+	return b, nil
+}
+
+// SketchWriter computes the sketches of the blocks written to a TSM file and
+// writes them to a sketch file. The file holds the magic number and version,
+// followed by the sketches of every key and their checksum, the index of the
+// keys locating their sketches, and a footer with the offset of the index and
+// its checksum.
+type SketchWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+
+	// index holds the entries of the index of the keys written.
+	index bytes.Buffer
+
+	// The block sketches of the current key are buffered until the next key,
+	// so that the number of blocks can be written before them.
+	key    []byte
+	blocks bytes.Buffer
+	blockN int
+
+	floats   tsdb.FloatArray
+	integers tsdb.IntegerArray
+	unsigned tsdb.UnsignedArray
+	strings  tsdb.StringArray
+	booleans tsdb.BooleanArray
+	buf      [binary.MaxVarintLen64]byte
+}
+
+// NewSketchWriter returns a SketchWriter that writes to w.
+func NewSketchWriter(w io.Writer) *SketchWriter {
+	sw := &SketchWriter{w: bufio.NewWriter(w)}
+	if _, err := sw.w.WriteString(SketchMagicNumber); err != nil {
+		sw.err = err
+	} else if err := sw.w.WriteByte(SketchVersion); err != nil {
+		sw.err = err
+	}
+	sw.n = int64(len(SketchMagicNumber) + 1)
+	return sw
+}
+
+// Add computes the sketch of a block of key. Blocks must be added in the
+// order in which they are written to the TSM file, so keys are ascending.
+func (w *SketchWriter) Add(key []byte, minTime, maxTime int64, block []byte) error {
+	if w.err != nil {
+		return w.err
+	} else if len(block) == 0 {
+		return nil
+	}
+
+	sketch, err := w.sketch(block)
+	if err != nil {
+		return err
+	}
+
+	if cmp := bytes.Compare(key, w.key); cmp < 0 && w.blockN > 0 {
+		return fmt.Errorf("tsm1: sketch of key %q added after key %q", key, w.key)
+	} else if cmp != 0 {
+		if err := w.flushKey(); err != nil {
+			return err
+		}
+		w.key = append(w.key[:0], key...)
+	}
+
+	w.putVarint(minTime)
+	w.putVarint(maxTime)
+	if sketch.Digest != nil {
+		data, err := sketch.Digest.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		w.putBytes(data)
+	} else {
+		w.putBytes(nil)
+	}
+	data, err := sketch.Distinct.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	w.putBytes(data)
+	w.blockN++
+	return nil
+}
+
+// sketch decodes the block and returns the sketch of its values.
+func (w *SketchWriter) sketch(block []byte) (*cursors.Sketch, error) {
+	typ, err := BlockType(block)
+	if err != nil {
+		return nil, err
+	}
+
+	distinct, err := hll.NewPlus(cursors.SketchPrecision)
+	if err != nil {
+		return nil, err
+	}
+	s := &cursors.Sketch{Distinct: distinct}
+	if typ == BlockFloat64 || typ == BlockInteger || typ == BlockUnsigned {
+		s.Digest = tdigest.NewWithCompression(blockSketchCompression)
+	}
+
+	switch typ {
+	case BlockFloat64:
+		if err := DecodeFloatArrayBlock(block, &w.floats); err != nil {
+			return nil, err
+		}
+		for _, v := range w.floats.Values {
+			s.AddFloat(v)
+		}
+	case BlockInteger:
+		if err := DecodeIntegerArrayBlock(block, &w.integers); err != nil {
+			return nil, err
+		}
+		for _, v := range w.integers.Values {
+			s.AddInteger(v)
+		}
+	case BlockUnsigned:
+		if err := DecodeUnsignedArrayBlock(block, &w.unsigned); err != nil {
+			return nil, err
+		}
+		for _, v := range w.unsigned.Values {
+			s.AddUnsigned(v)
+		}
+	case BlockString:
+		if err := DecodeStringArrayBlock(block, &w.strings); err != nil {
+			return nil, err
+		}
+		for _, v := range w.strings.Values {
+			s.AddString(v)
+		}
+	case BlockBoolean:
+		if err := DecodeBooleanArrayBlock(block, &w.booleans); err != nil {
+			return nil, err
+		}
+		for _, v := range w.booleans.Values {
+			s.AddBoolean(v)
+		}
+	default:
+		return nil, fmt.Errorf("unknown block type: %d", typ)
+	}
+
+	// Counting merges the temporary set of the sparse sketch, which makes
+	// its encoding smaller.
+	s.Distinct.Count()
+	return s, nil
+}
+
+func (w *SketchWriter) putVarint(v int64) {
+	w.blocks.Write(w.buf[:binary.PutVarint(w.buf[:], v)])
+}
+
+func (w *SketchWriter) putBytes(b []byte) {
+	w.blocks.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(len(b)))])
+	w.blocks.Write(b)
+}
+
+// flushKey writes the buffered block sketches of the current key, and adds
+// the key to the index.
+func (w *SketchWriter) flushKey() error {
+	if w.blockN == 0 {
+		return nil
+	}
+
+	var hdr [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(hdr[:], uint64(w.blockN))
+	checksum := crc32.Update(crc32.ChecksumIEEE(hdr[:n]), crc32.IEEETable, w.blocks.Bytes())
+	size := int64(n + w.blocks.Len())
+
+	if _, err := w.w.Write(hdr[:n]); err != nil {
+		w.err = err
+		return err
+	} else if _, err := w.w.Write(w.blocks.Bytes()); err != nil {
+		w.err = err
+		return err
+	}
+
+	w.index.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(len(w.key)))])
+	w.index.Write(w.key)
+	w.index.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(w.n))])
+	w.index.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(size))])
+	binary.BigEndian.PutUint32(w.buf[:crc32.Size], checksum)
+	w.index.Write(w.buf[:crc32.Size])
+
+	w.n += size
+	w.blocks.Reset()
+	w.blockN = 0
+	return nil
+}
+
+// Close writes the remaining block sketches, the index and the footer, and
+// flushes the underlying writer.
+func (w *SketchWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.flushKey(); err != nil {
+		return err
+	}
+
+	var footer [sketchFooterSize]byte
+	binary.BigEndian.PutUint64(footer[:8], uint64(w.n))
+	binary.BigEndian.PutUint32(footer[8:], crc32.ChecksumIEEE(w.index.Bytes()))
+	if _, err := w.w.Write(w.index.Bytes()); err != nil {
+		return err
+	} else if _, err := w.w.Write(footer[:]); err != nil {
+		return err
+	} else if err := w.w.Flush(); err != nil {
+		return err
+	}
+	w.err = errors.New("tsm1: sketch writer closed")
+	return nil
+}
+
+// SketchFilename returns the path to the sketch file for a given TSM file path.
+func SketchFilename(tsmPath string) string {
+	if strings.HasSuffix(tsmPath, "."+TmpTSMFileExtension) {
+		tsmPath = strings.TrimSuffix(tsmPath, "."+TmpTSMFileExtension)
+	}
+	if strings.HasSuffix(tsmPath, "."+TSMFileExtension) {
+		tsmPath = strings.TrimSuffix(tsmPath, "."+TSMFileExtension)
+	}
+	return tsmPath + "." + TSKFileExtension
+}
@@ -0,0 +1,261 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestCompactor_Sketches(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	var cpu, mem []tsm1.Value
+	for i := 0; i < 2500; i++ {
+		cpu = append(cpu, tsm1.NewValue(int64(i), float64(i%100)))
+		mem = append(mem, tsm1.NewValue(int64(i), fmt.Sprintf("v%d", i%10)))
+	}
+	f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{"cpu#!~#value": cpu[:2000]})
+	f2 := MustWriteTSM(dir, 2, map[string][]tsm1.Value{"cpu#!~#value": cpu[2000:], "mem#!~#value": mem})
+
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.Sketches = true
+	compactor.Open()
+
+	files, err := compactor.CompactFull([]string{f1, f2})
+	if err != nil {
+		t.Fatalf("unexpected error compacting: %v", err)
+	} else if len(files) != 1 {
+		t.Fatalf("got %d files, want 1", len(files))
+	}
+
+	r, err := tsm1.OpenSketchReader(tsm1.SketchFilename(files[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	blocks, err := r.BlockSketches([]byte("cpu#!~#value"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(blocks) == 0 {
+		t.Fatal("expected sketches of the cpu blocks")
+	} else if blocks[0].MinTime != 0 || blocks[len(blocks)-1].MaxTime != 2499 {
+		t.Errorf("unexpected time range of the blocks: [%d, %d]", blocks[0].MinTime, blocks[len(blocks)-1].MaxTime)
+	}
+	sketch := cursors.NewSketch(true)
+	for _, b := range blocks {
+		s, err := b.Sketch()
+		if err != nil {
+			t.Fatal(err)
+		} else if err := sketch.Merge(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := sketch.Digest.Count(); got != 2500 {
+		t.Errorf("got digest count %d, want 2500", got)
+	}
+	if got := sketch.Digest.Quantile(0.5); math.Abs(got-49.5) > 2 {
+		t.Errorf("got median %v, want about 49.5", got)
+	}
+	if got := sketch.Distinct.Count(); got < 95 || got > 105 {
+		t.Errorf("got distinct count %d, want about 100", got)
+	}
+
+	blocks, err = r.BlockSketches([]byte("mem#!~#value"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(blocks) == 0 {
+		t.Fatal("expected sketches of the mem blocks")
+	}
+	s, err := blocks[0].Sketch()
+	if err != nil {
+		t.Fatal(err)
+	} else if s.Digest != nil {
+		t.Errorf("unexpected digest of string values")
+	} else if got := s.Distinct.Count(); got != 10 {
+		t.Errorf("got distinct count %d, want 10", got)
+	}
+
+	// Keys before, between and after the keys of the file have no sketches.
+	for _, key := range []string{"a#!~#value", "disk#!~#value", "zz#!~#value"} {
+		if blocks, err := r.BlockSketches([]byte(key)); err != nil || blocks != nil {
+			t.Errorf("got sketches %v and error %v for key %q, want neither", blocks, err, key)
+		}
+	}
+}
+
+func TestSketchWriter_KeyOrder(t *testing.T) {
+	block, err := tsm1.Values{tsm1.NewValue(0, 1.0)}.Encode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := tsm1.NewSketchWriter(&buf)
+	if err := w.Add([]byte("mem"), 0, 0, block); err != nil {
+		t.Fatal(err)
+	} else if err := w.Add([]byte("cpu"), 0, 0, block); err == nil {
+		t.Fatal("expected an error adding a key before the previous one")
+	}
+}
+
+func TestOpenSketchReader_Corrupt(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// A missing file has no sketches.
+	path := filepath.Join(dir, "000000001-000000001.tsk")
+	r, err := tsm1.OpenSketchReader(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if blocks, err := r.BlockSketches([]byte("cpu")); err != nil || blocks != nil {
+		t.Fatalf("got sketches %v and error %v of a missing file, want neither", blocks, err)
+	}
+
+	block, err := tsm1.Values{tsm1.NewValue(0, 1.0)}.Encode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := tsm1.NewSketchWriter(&buf)
+	if err := w.Add([]byte("cpu"), 0, 0, block); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupted sketches of a key are detected when they are read.
+	data := buf.Bytes()
+	data[len(tsm1.SketchMagicNumber)+3] ^= 0xff
+	if err := ioutil.WriteFile(path, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if r, err = tsm1.OpenSketchReader(path); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := r.BlockSketches([]byte("cpu")); err == nil {
+		t.Fatal("expected an error reading corrupted sketches")
+	}
+
+	// A corrupted index is detected when the file is opened.
+	data[len(data)-1] ^= 0xff
+	if err := ioutil.WriteFile(path, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tsm1.OpenSketchReader(path); err == nil {
+		t.Fatal("expected an error opening a file with a corrupted index")
+	}
+}
+
+func TestEngine_CursorIterator_Sketch(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+	e.Compactor.Sketches = true
+
+	tags := models.Tags{{Key: []byte("host"), Value: []byte("a")}}
+	writePoints := func(ts []int64, value func(int64) float64) {
+		t.Helper()
+		var points []models.Point
+		for _, ts := range ts {
+			points = append(points, models.MustNewPoint("cpu", tags, models.Fields{"value": value(ts)}, time.Unix(0, ts)))
+		}
+		if err := e.index.CreateSeriesListIfNotExists(tsdb.NewSeriesCollection(points)); err != nil {
+			t.Fatal(err)
+		} else if err := e.WritePoints(points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Three blocks of 100 distinct values, compacted with sketches.
+	var ts []int64
+	for i := int64(0); i < 3000; i++ {
+		ts = append(ts, i)
+	}
+	writePoints(ts, func(ts int64) float64 { return float64(ts % 100) })
+	e.MustWriteSnapshot()
+
+	var snapshots []string
+	for _, f := range e.FileStore.Files() {
+		snapshots = append(snapshots, f.Path())
+	}
+	files, err := e.Compactor.CompactFull(snapshots)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := e.FileStore.Replace(snapshots, files); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite a value of the second block in a new file, and add a value
+	// to the cache. Both must be read instead of the sketches.
+	writePoints([]int64{1500}, func(int64) float64 { return 7 })
+	e.MustWriteSnapshot()
+	writePoints([]int64{3500}, func(int64) float64 { return 1000 })
+
+	ctx := context.Background()
+	itr, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sketch, err := itr.(cursors.SketchIterator).Sketch(ctx, &tsdb.CursorRequest{
+		Name:      []byte("cpu"),
+		Tags:      tags,
+		Field:     "value",
+		StartTime: 0,
+		EndTime:   4000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sketch.Digest.Count(); got != 3001 {
+		t.Errorf("got digest count %d, want 3001", got)
+	}
+	if got := sketch.Digest.Quantile(1); got != 1000 {
+		t.Errorf("got maximum %v, want 1000", got)
+	}
+	if got := sketch.Distinct.Count(); got < 96 || got > 106 {
+		t.Errorf("got distinct count %d, want about 101", got)
+	}
+	if stats := itr.Stats(); stats.ScannedValues >= 3000 {
+		t.Errorf("scanned %d values, expected the sketches of clean blocks to be used", stats.ScannedValues)
+	}
+
+	// A range that only partially covers blocks reads their values.
+	sketch, err = itr.(cursors.SketchIterator).Sketch(ctx, &tsdb.CursorRequest{
+		Name:      []byte("cpu"),
+		Tags:      tags,
+		Field:     "value",
+		StartTime: 500,
+		EndTime:   1500,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if got := sketch.Digest.Count(); got != 1000 {
+		t.Errorf("got digest count %d, want 1000", got)
+	}
+
+	// Series that do not exist have no sketch.
+	sketch, err = itr.(cursors.SketchIterator).Sketch(ctx, &tsdb.CursorRequest{
+		Name:  []byte("mem"),
+		Field: "value",
+	})
+	if err != nil || sketch != nil {
+		t.Errorf("got sketch %v and error %v for a missing series, want neither", sketch, err)
+	}
+}
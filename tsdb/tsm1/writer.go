@@ -883,6 +883,8 @@ func (t *tsmWriter) Remove() error {
 			return err
 		} else if err := os.Remove(StatsFilename(f.Name())); err != nil && !os.IsNotExist(err) {
 			return err
+		} else if err := os.Remove(SketchFilename(f.Name())); err != nil && !os.IsNotExist(err) {
+			return err
 		}
 	}
 	return nil
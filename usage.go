@@ -23,6 +23,11 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+	// UsageQueryDuration is the name of the metrics for tracking the total time spent executing queries in seconds.
+	UsageQueryDuration UsageMetric = "usage_query_duration_seconds"
+
+	// UsageStorageBytes is the name of the metrics for tracking the number of bytes stored.
+	UsageStorageBytes UsageMetric = "usage_storage_bytes"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.
@@ -50,3 +55,34 @@ type Timespan struct {
 	Start time.Time `json:"start"`
 	Stop  time.Time `json:"stop"`
 }
+
+// UsageWindow is the request usage of an organization within the hour
+// starting at Start.
+type UsageWindow struct {
+	OrgID         ID            `json:"orgID"`
+	Start         time.Time     `json:"start"`
+	WriteRequests int64         `json:"writeRequests"`
+	WriteBytes    int64         `json:"writeBytes"`
+	QueryRequests int64         `json:"queryRequests"`
+	QueryBytes    int64         `json:"queryBytes"`
+	QueryDuration time.Duration `json:"queryDuration"`
+}
+
+// ops for usage window errors.
+const (
+	OpFindUsageWindows   = "FindUsageWindows"
+	OpPutUsageWindows    = "PutUsageWindows"
+	OpDeleteUsageWindows = "DeleteUsageWindows"
+)
+
+// UsageWindowService stores the request usage windows of organizations, so
+// that request usage outlives restarts.
+type UsageWindowService interface {
+	// FindUsageWindows returns the windows starting at or after since.
+	FindUsageWindows(ctx context.Context, since time.Time) ([]*UsageWindow, error)
+	// PutUsageWindows replaces the stored windows of the same organizations
+	// and starts with windows.
+	PutUsageWindows(ctx context.Context, windows []*UsageWindow) error
+	// DeleteUsageWindows removes the windows starting before before.
+	DeleteUsageWindows(ctx context.Context, before time.Time) error
+}
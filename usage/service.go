@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// StorageStats reports the size of the data held by the storage engine by
+// measurement. Measurement names are the encoded organization and bucket IDs.
+type StorageStats interface {
+	MeasurementStats() (tsm1.MeasurementStats, error)
+	MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats, error)
+}
+
+var _ influxdb.UsageService = (*Service)(nil)
+
+// Service reports the usage of an organization. Request usage comes from the
+// tracker and is limited to the requested range. Storage usage is the current
+// size of the organization's data regardless of the range.
+type Service struct {
+	tracker *Tracker
+	storage StorageStats
+}
+
+// NewService returns a usage service combining the request usage of t with
+// the storage usage of s. Storage usage is omitted when s is nil.
+func NewService(t *Tracker, s StorageStats) *Service {
+	return &Service{
+		tracker: t,
+		storage: s,
+	}
+}
+
+// GetUsage returns the usage of the organization in the filter.
+func (s *Service) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.OrgID == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "usage requires an organization",
+		}
+	}
+	if filter.BucketID != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "usage by bucket is not supported",
+		}
+	}
+	if filter.Range == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "usage requires a time range",
+		}
+	}
+
+	orgID := *filter.OrgID
+	c := s.tracker.usage(orgID, filter.Range.Start, filter.Range.Stop)
+
+	u := make(map[influxdb.UsageMetric]*influxdb.Usage)
+	set := func(m influxdb.UsageMetric, v float64) {
+		u[m] = &influxdb.Usage{
+			OrganizationID: &orgID,
+			Type:           m,
+			Value:          v,
+		}
+	}
+	set(influxdb.UsageWriteRequestCount, float64(c.writeRequests))
+	set(influxdb.UsageWriteRequestBytes, float64(c.writeBytes))
+	set(influxdb.UsageQueryRequestCount, float64(c.queryRequests))
+	set(influxdb.UsageQueryRequestBytes, float64(c.queryBytes))
+	set(influxdb.UsageQueryDuration, c.queryDuration.Seconds())
+
+	if s.storage == nil {
+		return u, nil
+	}
+
+	bytes, err := s.storage.MeasurementStats()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to read storage stats",
+			Err:  err,
+		}
+	}
+	series, err := s.storage.MeasurementCardinalityStats()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to read series cardinality stats",
+			Err:  err,
+		}
+	}
+	set(influxdb.UsageStorageBytes, float64(sumByOrg(bytes, orgID)))
+	set(influxdb.UsageSeries, float64(sumByOrg(series, orgID)))
+
+	return u, nil
+}
+
+// sumByOrg sums the values of the measurements belonging to orgID.
+func sumByOrg(stats map[string]int, orgID influxdb.ID) int {
+	var n int
+	for name, v := range stats {
+		// Measurement names are the 8 byte organization ID followed by the
+		// 8 byte bucket ID.
+		if len(name) != 16 {
+			continue
+		}
+		if org, _ := tsdb.DecodeNameSlice([]byte(name)); org == orgID {
+			n += v
+		}
+	}
+	return n
+}
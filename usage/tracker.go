@@ -0,0 +1,288 @@
+// Package usage aggregates the usage of the server by organization.
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRetention is how long request usage is kept when a tracker is
+	// created without an explicit retention.
+	DefaultRetention = 90 * 24 * time.Hour
+
+	// DefaultFlushInterval is the interval at which the windows changed since
+	// the previous flush are written when the tracker has a service.
+	DefaultFlushInterval = time.Minute
+
+	// window is the granularity at which request usage is aggregated.
+	window = time.Hour
+)
+
+// counts is the request usage of an organization within a single window.
+type counts struct {
+	writeRequests int64
+	writeBytes    int64
+	queryRequests int64
+	queryBytes    int64
+	queryDuration time.Duration
+}
+
+func (c *counts) add(o *counts) {
+	c.writeRequests += o.writeRequests
+	c.writeBytes += o.writeBytes
+	c.queryRequests += o.queryRequests
+	c.queryBytes += o.queryBytes
+	c.queryDuration += o.queryDuration
+}
+
+// windowKey identifies the window of an organization.
+type windowKey struct {
+	orgID influxdb.ID
+	start time.Time
+}
+
+// Tracker aggregates successful write and query requests by organization in
+// hourly windows. Windows older than the retention are discarded. When the
+// tracker has a service, the windows are written to it by Flush and read back
+// by Load, so that request usage outlives restarts.
+type Tracker struct {
+	Retention time.Duration
+	Service   influxdb.UsageWindowService
+
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	windows map[influxdb.ID]map[time.Time]*counts
+	changed map[windowKey]struct{}
+
+	now func() time.Time
+}
+
+// NewTracker returns a tracker keeping request usage for retention. A
+// retention of zero uses DefaultRetention.
+func NewTracker(retention time.Duration) *Tracker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Tracker{
+		Retention: retention,
+		logger:    zap.NewNop(),
+		windows:   make(map[influxdb.ID]map[time.Time]*counts),
+		changed:   make(map[windowKey]struct{}),
+		now:       time.Now,
+	}
+}
+
+// WithLogger sets the logger l on the tracker. It must be called before Run.
+func (t *Tracker) WithLogger(l *zap.Logger) {
+	t.logger = l.With(zap.String("component", "usage"))
+}
+
+// Load adds the windows of the service within the retention to the tracker.
+// It is called once on startup, before requests are recorded.
+func (t *Tracker) Load(ctx context.Context) error {
+	if t.Service == nil {
+		return nil
+	}
+
+	ws, err := t.Service.FindUsageWindows(ctx, t.now().Add(-t.Retention).Truncate(window))
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, w := range ws {
+		t.addLocked(w.OrgID, w.Start, &counts{
+			writeRequests: w.WriteRequests,
+			writeBytes:    w.WriteBytes,
+			queryRequests: w.QueryRequests,
+			queryBytes:    w.QueryBytes,
+			queryDuration: w.QueryDuration,
+		})
+	}
+	return nil
+}
+
+// Flush writes the windows changed since the previous flush to the service,
+// and removes the windows older than the retention from it. The window in
+// progress is written as well, so that it is not lost on shutdown.
+func (t *Tracker) Flush(ctx context.Context) error {
+	if t.Service == nil {
+		return nil
+	}
+
+	now := t.now()
+	t.mu.Lock()
+	ws := make([]*influxdb.UsageWindow, 0, len(t.changed))
+	for k := range t.changed {
+		c, ok := t.windows[k.orgID][k.start]
+		if !ok {
+			continue
+		}
+		ws = append(ws, &influxdb.UsageWindow{
+			OrgID:         k.orgID,
+			Start:         k.start,
+			WriteRequests: c.writeRequests,
+			WriteBytes:    c.writeBytes,
+			QueryRequests: c.queryRequests,
+			QueryBytes:    c.queryBytes,
+			QueryDuration: c.queryDuration,
+		})
+	}
+	changed := t.changed
+	t.changed = make(map[windowKey]struct{})
+	t.mu.Unlock()
+
+	if len(ws) > 0 {
+		if err := t.Service.PutUsageWindows(ctx, ws); err != nil {
+			// the windows are written again by the next flush.
+			t.mu.Lock()
+			for k := range changed {
+				t.changed[k] = struct{}{}
+			}
+			t.mu.Unlock()
+			return err
+		}
+	}
+	return t.Service.DeleteUsageWindows(ctx, now.Add(-t.Retention).Truncate(window))
+}
+
+// Run flushes the windows every interval until ctx is canceled. The windows
+// changed after the last flush are written by calling Flush.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := t.Flush(ctx); err != nil && ctx.Err() == nil {
+			t.logger.Error("Unable to write request usage", zap.Error(err))
+		}
+	}
+}
+
+// WriteRecorder returns an event recorder that tracks write requests and
+// forwards every event to next.
+func (t *Tracker) WriteRecorder(next metric.EventRecorder) metric.EventRecorder {
+	return &recorder{next: next, record: t.recordWrite}
+}
+
+// QueryRecorder returns an event recorder that tracks query requests and
+// forwards every event to next.
+func (t *Tracker) QueryRecorder(next metric.EventRecorder) metric.EventRecorder {
+	return &recorder{next: next, record: t.recordQuery}
+}
+
+func (t *Tracker) recordWrite(e metric.Event) {
+	t.add(e.OrgID, &counts{
+		writeRequests: 1,
+		writeBytes:    int64(e.RequestBytes),
+	})
+}
+
+func (t *Tracker) recordQuery(e metric.Event) {
+	t.add(e.OrgID, &counts{
+		queryRequests: 1,
+		queryBytes:    int64(e.RequestBytes),
+		queryDuration: e.Duration,
+	})
+}
+
+func (t *Tracker) add(orgID influxdb.ID, c *counts) {
+	now := t.now()
+
+	w := now.Truncate(window)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.changed[windowKey{orgID: orgID, start: w}] = struct{}{}
+	if t.addLocked(orgID, w, c) {
+		// Prune when a new window is opened so the cost is paid at most once
+		// an hour for every organization.
+		t.prune(orgID, now)
+	}
+}
+
+// addLocked adds c to the window of orgID starting at w and returns true if
+// the window is new. It must be called with the lock held.
+func (t *Tracker) addLocked(orgID influxdb.ID, w time.Time, c *counts) bool {
+	ws, ok := t.windows[orgID]
+	if !ok {
+		ws = make(map[time.Time]*counts)
+		t.windows[orgID] = ws
+	}
+	if cur, ok := ws[w]; ok {
+		cur.add(c)
+		return false
+	}
+	ws[w] = c
+	return true
+}
+
+// prune removes the windows of orgID that ended before the retention.
+func (t *Tracker) prune(orgID influxdb.ID, now time.Time) {
+	min := now.Add(-t.Retention).Truncate(window)
+	for w := range t.windows[orgID] {
+		if w.Before(min) {
+			delete(t.windows[orgID], w)
+			delete(t.changed, windowKey{orgID: orgID, start: w})
+		}
+	}
+}
+
+// usage returns the request usage of orgID between start and stop. A window
+// is included when it overlaps the range.
+func (t *Tracker) usage(orgID influxdb.ID, start, stop time.Time) counts {
+	start = start.Truncate(window)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total counts
+	for w, c := range t.windows[orgID] {
+		if !w.Before(start) && w.Before(stop) {
+			total.add(c)
+		}
+	}
+	return total
+}
+
+// recorder records successful events with the tracker before handing them to
+// the next recorder.
+type recorder struct {
+	next   metric.EventRecorder
+	record func(metric.Event)
+}
+
+func (r *recorder) Record(ctx context.Context, e metric.Event) {
+	if e.Status >= 200 && e.Status < 300 && e.OrgID.Valid() {
+		r.record(e)
+	}
+	if r.next != nil {
+		r.next.Record(ctx, e)
+	}
+}
+
+// PrometheusCollectors returns the collectors of the wrapped recorder so
+// wrapping does not hide its metrics.
+func (r *recorder) PrometheusCollectors() []prometheus.Collector {
+	if pc, ok := r.next.(prom.PrometheusCollector); ok {
+		return pc.PrometheusCollectors()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+type countingRecorder struct {
+	n int
+}
+
+func (r *countingRecorder) Record(ctx context.Context, e metric.Event) { r.n++ }
+
+func TestTracker(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	now := time.Date(2019, 10, 1, 12, 30, 0, 0, time.UTC)
+
+	tr := NewTracker(48 * time.Hour)
+	tr.now = func() time.Time { return now }
+
+	next := &countingRecorder{}
+	writes, queries := tr.WriteRecorder(next), tr.QueryRecorder(next)
+	ctx := context.Background()
+
+	writes.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 100, Status: http.StatusNoContent})
+	writes.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 50, Status: http.StatusBadRequest})
+	writes.Record(ctx, metric.Event{OrgID: otherOrgID, RequestBytes: 10, Status: http.StatusNoContent})
+	queries.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 20, Status: http.StatusOK, Duration: 2 * time.Second})
+
+	now = now.Add(time.Hour)
+	queries.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 30, Status: http.StatusOK, Duration: time.Second})
+
+	if next.n != 5 {
+		t.Errorf("next recorder received %d events, want 5", next.n)
+	}
+
+	got := tr.usage(orgID, now.Add(-2*time.Hour), now.Truncate(time.Hour))
+	want := counts{writeRequests: 1, writeBytes: 100, queryRequests: 1, queryBytes: 20, queryDuration: 2 * time.Second}
+	if got != want {
+		t.Errorf("usage() = %+v, want %+v", got, want)
+	}
+
+	// The range start is rounded down to the window it falls in.
+	got = tr.usage(orgID, now.Add(-time.Minute), now.Add(time.Hour))
+	want = counts{queryRequests: 1, queryBytes: 30, queryDuration: time.Second}
+	if got != want {
+		t.Errorf("usage() = %+v, want %+v", got, want)
+	}
+
+	// Opening a window beyond the retention discards the old windows.
+	now = now.Add(72 * time.Hour)
+	writes.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 1, Status: http.StatusNoContent})
+	got = tr.usage(orgID, time.Time{}, now.Add(time.Hour))
+	want = counts{writeRequests: 1, writeBytes: 1}
+	if got != want {
+		t.Errorf("usage() after retention = %+v, want %+v", got, want)
+	}
+}
+
+// windowService is an in-memory usage window service.
+type windowService struct {
+	windows map[windowKey]influxdb.UsageWindow
+}
+
+func (s *windowService) FindUsageWindows(ctx context.Context, since time.Time) ([]*influxdb.UsageWindow, error) {
+	var ws []*influxdb.UsageWindow
+	for _, w := range s.windows {
+		if !w.Start.Before(since) {
+			w := w
+			ws = append(ws, &w)
+		}
+	}
+	return ws, nil
+}
+
+func (s *windowService) PutUsageWindows(ctx context.Context, ws []*influxdb.UsageWindow) error {
+	for _, w := range ws {
+		s.windows[windowKey{orgID: w.OrgID, start: w.Start}] = *w
+	}
+	return nil
+}
+
+func (s *windowService) DeleteUsageWindows(ctx context.Context, before time.Time) error {
+	for k := range s.windows {
+		if k.start.Before(before) {
+			delete(s.windows, k)
+		}
+	}
+	return nil
+}
+
+func TestTracker_FlushLoad(t *testing.T) {
+	orgID := influxdb.ID(1)
+	now := time.Date(2019, 10, 1, 12, 30, 0, 0, time.UTC)
+	svc := &windowService{windows: make(map[windowKey]influxdb.UsageWindow)}
+	ctx := context.Background()
+
+	// an expired window is removed by the first flush.
+	old := now.Add(-72 * time.Hour).Truncate(time.Hour)
+	svc.windows[windowKey{orgID: orgID, start: old}] = influxdb.UsageWindow{OrgID: orgID, Start: old, WriteRequests: 9}
+
+	tr := NewTracker(48 * time.Hour)
+	tr.Service = svc
+	tr.now = func() time.Time { return now }
+	writes := tr.WriteRecorder(nil)
+	writes.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 100, Status: http.StatusNoContent})
+	if err := tr.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the window in progress is written again with the requests recorded
+	// after the previous flush.
+	now = now.Add(10 * time.Minute)
+	writes.Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 50, Status: http.StatusNoContent})
+	if err := tr.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.windows) != 1 {
+		t.Fatalf("service has %d windows, want 1", len(svc.windows))
+	}
+
+	// a new tracker, as after a restart, reads the windows back.
+	restarted := NewTracker(48 * time.Hour)
+	restarted.Service = svc
+	restarted.now = func() time.Time { return now }
+	if err := restarted.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	restarted.WriteRecorder(nil).Record(ctx, metric.Event{OrgID: orgID, RequestBytes: 1, Status: http.StatusNoContent})
+
+	got := restarted.usage(orgID, now.Add(-time.Hour), now.Add(time.Hour))
+	want := counts{writeRequests: 3, writeBytes: 151}
+	if got != want {
+		t.Errorf("usage() after restart = %+v, want %+v", got, want)
+	}
+}
+
+type storageStats struct {
+	bytes  tsm1.MeasurementStats
+	series tsi1.MeasurementCardinalityStats
+}
+
+func (s *storageStats) MeasurementStats() (tsm1.MeasurementStats, error) { return s.bytes, nil }
+
+func (s *storageStats) MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats, error) {
+	return s.series, nil
+}
+
+func TestService_GetUsage(t *testing.T) {
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	now := time.Date(2019, 10, 1, 12, 30, 0, 0, time.UTC)
+
+	tr := NewTracker(0)
+	tr.now = func() time.Time { return now }
+	tr.WriteRecorder(nil).Record(context.Background(), metric.Event{OrgID: orgID, RequestBytes: 100, Status: http.StatusNoContent})
+	tr.QueryRecorder(nil).Record(context.Background(), metric.Event{OrgID: orgID, RequestBytes: 20, Status: http.StatusOK, Duration: 1500 * time.Millisecond})
+
+	name := func(org, bucket influxdb.ID) string {
+		n := tsdb.EncodeName(org, bucket)
+		return string(n[:])
+	}
+	s := NewService(tr, &storageStats{
+		bytes: tsm1.MeasurementStats{
+			name(orgID, 10):      1000,
+			name(orgID, 11):      500,
+			name(otherOrgID, 12): 7,
+		},
+		series: tsi1.MeasurementCardinalityStats{
+			name(orgID, 10):      3,
+			name(otherOrgID, 12): 4,
+		},
+	})
+
+	u, err := s.GetUsage(context.Background(), influxdb.UsageFilter{
+		OrgID: &orgID,
+		Range: &influxdb.Timespan{Start: now.Add(-time.Hour), Stop: now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for m, want := range map[influxdb.UsageMetric]float64{
+		influxdb.UsageWriteRequestCount: 1,
+		influxdb.UsageWriteRequestBytes: 100,
+		influxdb.UsageQueryRequestCount: 1,
+		influxdb.UsageQueryRequestBytes: 20,
+		influxdb.UsageQueryDuration:     1.5,
+		influxdb.UsageStorageBytes:      1500,
+		influxdb.UsageSeries:            3,
+	} {
+		if u[m] == nil {
+			t.Errorf("missing %s", m)
+			continue
+		}
+		if got := u[m].Value; got != want {
+			t.Errorf("%s = %v, want %v", m, got, want)
+		}
+		if id := u[m].OrganizationID; id == nil || *id != orgID {
+			t.Errorf("%s organization = %v, want %s", m, id, orgID)
+		}
+	}
+
+	if _, err := s.GetUsage(context.Background(), influxdb.UsageFilter{}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("GetUsage() without org error = %v, want %s", err, influxdb.EInvalid)
+	}
+}
@@ -0,0 +1,111 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ops for user settings error and op logs.
+const (
+	OpFindUserSettings  = "FindUserSettings"
+	OpPutUserSetting    = "PutUserSetting"
+	OpDeleteUserSetting = "DeleteUserSetting"
+)
+
+const (
+	// MaxUserSettingSize is the maximum size in bytes of the value of a
+	// single namespace of the settings of a user.
+	MaxUserSettingSize = 16 << 10
+
+	// MaxUserSettingsSize is the maximum size in bytes of the values of all
+	// namespaces of the settings of a user.
+	MaxUserSettingsSize = 128 << 10
+)
+
+// ErrUserSettingNotFound is used when the user has no setting in a namespace.
+var ErrUserSettingNotFound = &Error{
+	Code: ENotFound,
+	Msg:  "user setting not found",
+}
+
+var userSettingNamespaceRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,63}$`)
+
+// UserSettingsService represents a service for managing the settings of
+// users, e.g. the preferences of the UI, so that they follow a user across
+// browsers.
+type UserSettingsService interface {
+	// FindUserSettings returns the settings of a user. A user that never
+	// stored a setting has empty settings.
+	FindUserSettings(ctx context.Context, userID ID) (*UserSettings, error)
+
+	// PutUserSetting replaces the value of the namespace of the settings of
+	// a user and returns the new settings.
+	PutUserSetting(ctx context.Context, userID ID, namespace string, value json.RawMessage) (*UserSettings, error)
+
+	// DeleteUserSetting removes the namespace from the settings of a user.
+	DeleteUserSetting(ctx context.Context, userID ID, namespace string) error
+}
+
+// UserSettings are the settings of a user. Settings are arbitrary JSON
+// values keyed by a namespace such as "theme" or "savedSearches"; the
+// server does not interpret them.
+type UserSettings struct {
+	UserID    ID                         `json:"userID"`
+	Settings  map[string]json.RawMessage `json:"settings"`
+	UpdatedAt time.Time                  `json:"updatedAt,omitempty"`
+}
+
+// Valid returns an error if a namespace of the settings is invalid or the
+// settings exceed the size limits.
+func (s *UserSettings) Valid() error {
+	var size int
+	for ns, v := range s.Settings {
+		if err := ValidUserSetting(ns, v); err != nil {
+			return err
+		}
+		size += len(v)
+	}
+	if size > MaxUserSettingsSize {
+		return &Error{
+			Code: ERequestTooLarge,
+			Msg:  fmt.Sprintf("user settings must not be larger than %d bytes in total", MaxUserSettingsSize),
+		}
+	}
+	return nil
+}
+
+// ValidUserSetting returns an error if the namespace is not a valid name or
+// the value is not JSON of at most MaxUserSettingSize bytes.
+func ValidUserSetting(namespace string, value json.RawMessage) error {
+	if err := ValidUserSettingNamespace(namespace); err != nil {
+		return err
+	}
+	if len(value) > MaxUserSettingSize {
+		return &Error{
+			Code: ERequestTooLarge,
+			Msg:  fmt.Sprintf("user setting %q must not be larger than %d bytes", namespace, MaxUserSettingSize),
+		}
+	}
+	if !json.Valid(value) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("user setting %q must be valid JSON", namespace),
+		}
+	}
+	return nil
+}
+
+// ValidUserSettingNamespace returns an error if the namespace is not 1 to 64
+// letters, digits, '_', '.' or '-' starting with a letter or digit.
+func ValidUserSettingNamespace(namespace string) error {
+	if !userSettingNamespaceRegexp.MatchString(namespace) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid user setting namespace %q; must be 1 to 64 letters, digits, '_', '.' or '-'", namespace),
+		}
+	}
+	return nil
+}
@@ -63,6 +63,12 @@ func (b *Batcher) Write(ctx context.Context, org, bucket platform.ID, r io.Reade
 // it is possible for an io.Reader to block forever; Write's context can be
 // used to cancel, but, it's possible there will be dangling read go routines.
 func (b *Batcher) read(ctx context.Context, r io.Reader, lines chan<- []byte, errC chan<- error) {
+	readLines(ctx, r, lines, errC)
+}
+
+// readLines sends the lines read from r on lines and then closes it. The
+// error reading r is sent on errC.
+func readLines(ctx context.Context, r io.Reader, lines chan<- []byte, errC chan<- error) {
 	defer close(lines)
 	scanner := bufio.NewScanner(r)
 	scanner.Split(ScanLines)
@@ -0,0 +1,275 @@
+package write
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed batch is retried.
+	DefaultMaxRetries = 3
+	// DefaultRetryInterval is the wait before the first retry of a batch. It
+	// doubles with every further retry.
+	DefaultRetryInterval = time.Second
+)
+
+// BulkWriter writes line protocol in batches that are sent concurrently.
+// Failed batches are retried when the failure is transient.
+type BulkWriter struct {
+	Service platform.WriteService // Service receives the batches.
+
+	MaxFlushBytes    int           // MaxFlushBytes is the maximum number of bytes in a batch
+	MaxFlushInterval time.Duration // MaxFlushInterval is the maximum amount of time to wait before flushing
+	Concurrency      int           // Concurrency is the number of batches written at the same time
+	MaxRetries       int           // MaxRetries is the number of retries of a failed batch; negative disables retries
+	RetryInterval    time.Duration // RetryInterval is the wait before the first retry
+
+	// Limiter limits the rate of bytes written when set. Its burst must be
+	// at least MaxFlushBytes.
+	Limiter *rate.Limiter
+
+	// Checkpoint is called with the offset below which all lines of the
+	// input have been written whenever it advances. The write is aborted if
+	// it returns an error.
+	Checkpoint func(offset int64) error
+}
+
+// bulkBatch is a batch of lines. End is the offset in the input following
+// the last line of the batch.
+type bulkBatch struct {
+	seq  int
+	end  int64
+	data []byte
+}
+
+type bulkResult struct {
+	seq int
+	end int64
+	err error
+}
+
+// Write reads lines from r and writes them in batches. It returns the offset
+// in r below which all lines have been written, so a failed write can be
+// resumed by skipping that many bytes of the same input.
+func (w *BulkWriter) Write(ctx context.Context, org, bucket platform.ID, r io.Reader) (int64, error) {
+	if w.Service == nil {
+		return 0, fmt.Errorf("destination write service required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := w.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	lines := make(chan []byte)
+	batches := make(chan bulkBatch, concurrency)
+	results := make(chan bulkResult, concurrency)
+	errC := make(chan error, 2)
+
+	go readLines(ctx, r, lines, errC)
+	go w.batch(ctx, lines, batches, errC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx, org, bucket, batches, results)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Batches finish out of order; the written offset only advances over
+	// batches whose predecessors have all been written.
+	var (
+		written  int64
+		next     int
+		ends     = make(map[int]int64)
+		writeErr error
+	)
+	for res := range results {
+		if res.err != nil {
+			if writeErr == nil {
+				writeErr = res.err
+				cancel()
+			}
+			continue
+		}
+		ends[res.seq] = res.end
+		for end, ok := ends[next]; ok; end, ok = ends[next] {
+			delete(ends, next)
+			next++
+			written = end
+			if w.Checkpoint != nil && writeErr == nil {
+				if err := w.Checkpoint(written); err != nil {
+					writeErr = err
+					cancel()
+				}
+			}
+		}
+	}
+	if writeErr != nil {
+		return written, writeErr
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errC:
+			if err != nil {
+				return written, err
+			}
+		case <-ctx.Done():
+			return written, ctx.Err()
+		}
+	}
+	return written, nil
+}
+
+// batch groups lines into batches. Batches are flushed when they reach the
+// maximum size, when the flush interval passes, or when the input ends.
+func (w *BulkWriter) batch(ctx context.Context, lines <-chan []byte, batches chan<- bulkBatch, errC chan<- error) {
+	defer close(batches)
+
+	flushInterval := w.MaxFlushInterval
+	if flushInterval == 0 {
+		flushInterval = DefaultInterval
+	}
+
+	maxBytes := w.MaxFlushBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBytes
+	}
+
+	timer := time.NewTimer(flushInterval)
+	defer func() { _ = timer.Stop() }()
+
+	var (
+		b      = bulkBatch{data: make([]byte, 0, maxBytes)}
+		offset int64
+	)
+	flush := func() bool {
+		timer.Reset(flushInterval)
+		if len(b.data) == 0 {
+			return true
+		}
+		b.end = offset
+		select {
+		case batches <- b:
+		case <-ctx.Done():
+			return false
+		}
+		b = bulkBatch{seq: b.seq + 1, data: make([]byte, 0, maxBytes)}
+		return true
+	}
+
+	for {
+		select {
+		case line, more := <-lines:
+			if !more {
+				if !flush() {
+					errC <- ctx.Err()
+					return
+				}
+				errC <- nil
+				return
+			}
+			b.data = append(b.data, line...)
+			offset += int64(len(line))
+			if len(b.data) >= maxBytes && !flush() {
+				errC <- ctx.Err()
+				return
+			}
+		case <-timer.C:
+			if !flush() {
+				errC <- ctx.Err()
+				return
+			}
+		case <-ctx.Done():
+			errC <- ctx.Err()
+			return
+		}
+	}
+}
+
+// work writes batches until there are no more batches or a batch fails.
+func (w *BulkWriter) work(ctx context.Context, org, bucket platform.ID, batches <-chan bulkBatch, results chan<- bulkResult) {
+	for b := range batches {
+		err := ctx.Err()
+		if err == nil {
+			err = w.writeBatch(ctx, org, bucket, b.data)
+		}
+		results <- bulkResult{seq: b.seq, end: b.end, err: err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeBatch writes data, retrying transient failures.
+func (w *BulkWriter) writeBatch(ctx context.Context, org, bucket platform.ID, data []byte) error {
+	if w.Limiter != nil {
+		// A batch may exceed the burst of the limiter by its last line, so
+		// wait for the batch in parts no larger than the burst.
+		for n := len(data); n > 0; {
+			m := n
+			if b := w.Limiter.Burst(); b > 0 && m > b {
+				m = b
+			}
+			if err := w.Limiter.WaitN(ctx, m); err != nil {
+				return err
+			}
+			n -= m
+		}
+	}
+
+	interval := w.RetryInterval
+	if interval == 0 {
+		interval = DefaultRetryInterval
+	}
+	retries := w.MaxRetries
+	if retries == 0 {
+		retries = DefaultMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := w.Service.Write(ctx, org, bucket, bytes.NewReader(data))
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		interval *= 2
+	}
+}
+
+// retryable reports whether a write that failed with err may succeed when
+// tried again.
+func retryable(err error) bool {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	switch platform.ErrorCode(err) {
+	case platform.EInternal, platform.EUnavailable, platform.ETooManyRequests:
+		return true
+	}
+	return false
+}
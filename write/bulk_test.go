@@ -0,0 +1,144 @@
+package write
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBulkWriter_Write(t *testing.T) {
+	input := "m1 f=1\nm2 f=2\nm3 f=3\nm4 f=4\nm5 f=5\n"
+
+	var (
+		mu      sync.Mutex
+		batches []string
+	)
+	w := &BulkWriter{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				b, err := ioutil.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				batches = append(batches, string(b))
+				mu.Unlock()
+				return nil
+			},
+		},
+		MaxFlushBytes: 14,
+		Concurrency:   3,
+	}
+
+	n, err := w.Write(context.Background(), 1, 2, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(input)) {
+		t.Errorf("Write() = %d, want %d", n, len(input))
+	}
+
+	sort.Strings(batches)
+	if got, want := strings.Join(batches, ""), input; got != want {
+		t.Errorf("written lines = %q, want %q", got, want)
+	}
+	if len(batches) != 3 {
+		t.Errorf("wrote %d batches, want 3", len(batches))
+	}
+}
+
+func TestBulkWriter_WriteRetries(t *testing.T) {
+	var calls int
+	w := &BulkWriter{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				calls++
+				if calls < 3 {
+					return &platform.Error{Code: platform.EUnavailable, Msg: "try again"}
+				}
+				return nil
+			},
+		},
+		RetryInterval: time.Millisecond,
+	}
+
+	if _, err := w.Write(context.Background(), 1, 2, strings.NewReader("m f=1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("wrote the batch %d times, want 3", calls)
+	}
+}
+
+func TestBulkWriter_WritePartialFailure(t *testing.T) {
+	input := "m1 f=1\nm2 f=2\nm3 f=3\n"
+
+	var (
+		calls       int
+		checkpoints []int64
+	)
+	w := &BulkWriter{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				calls++
+				if calls == 2 {
+					return &platform.Error{Code: platform.EInvalid, Msg: "bad line"}
+				}
+				return nil
+			},
+		},
+		MaxFlushBytes: 7,
+		Checkpoint: func(offset int64) error {
+			checkpoints = append(checkpoints, offset)
+			return nil
+		},
+	}
+
+	n, err := w.Write(context.Background(), 1, 2, strings.NewReader(input))
+	if platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("Write() error = %v, want %s", err, platform.EInvalid)
+	}
+	if n != 7 {
+		t.Errorf("Write() = %d, want 7", n)
+	}
+	if calls != 2 {
+		t.Errorf("wrote %d batches, want 2; invalid batches must not be retried", calls)
+	}
+	if len(checkpoints) != 1 || checkpoints[0] != 7 {
+		t.Errorf("checkpoints = %v, want [7]", checkpoints)
+	}
+}
+
+func TestBulkWriter_WriteCheckpointFailure(t *testing.T) {
+	input := "m1 f=1\nm2 f=2\nm3 f=3\nm4 f=4\nm5 f=5\n"
+
+	var checkpoints int
+	cpErr := &platform.Error{Code: platform.EInternal, Msg: "disk full"}
+	w := &BulkWriter{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				return nil
+			},
+		},
+		MaxFlushBytes: 7,
+		Checkpoint: func(offset int64) error {
+			checkpoints++
+			return cpErr
+		},
+	}
+
+	if _, err := w.Write(context.Background(), 1, 2, strings.NewReader(input)); err != cpErr {
+		t.Fatalf("Write() error = %v, want the checkpoint error", err)
+	}
+	if checkpoints != 1 {
+		t.Errorf("saved %d checkpoints, want the write to abort after the failed one", checkpoints)
+	}
+}
@@ -0,0 +1,21 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// OpUseWriteLink is the op of the errors of using write links.
+const OpUseWriteLink = "UseWriteLink"
+
+// WriteLinkService records the one-time write links that were used, so that a
+// link cannot be used twice, also across restarts and by servers sharing the
+// store.
+type WriteLinkService interface {
+	// UseWriteLink finds the authorization signing a write link, verifies the
+	// link with it and marks the link, identified by its signature, used
+	// until it expires. All of this happens in a single transaction, so that
+	// concurrent requests of a link cannot both use it. An error with code
+	// EConflict is returned if the link was already used.
+	UseWriteLink(ctx context.Context, authID ID, signature []byte, expiresAt time.Time, verify func(*Authorization) error) (*Authorization, error)
+}